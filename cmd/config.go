@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+)
+
+// newConfigCmd creates the Cobra command grouping the configuration helpers.
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the serve configuration",
+	}
+	cmd.AddCommand(newConfigValidateCmd())
+	return cmd
+}
+
+// newConfigValidateCmd creates the command that checks a serve configuration
+// without starting the server. It accepts the same flags, configuration file
+// and environment variables as serve.
+func newConfigValidateCmd() *cobra.Command {
+	cmd := newServeCommand(func(cmd *cobra.Command, config ServeConfig) error {
+		if err := validateServeConfig(config); err != nil {
+			return err
+		}
+		if config.Transport != transportStdio {
+			if _, err := httpStackConfig(config, nil); err != nil {
+				return err
+			}
+		}
+		if config.Policy.File != "" {
+			if _, err := security.NewEngineFromFile(config.Policy.File); err != nil {
+				return fmt.Errorf("invalid policy file: %w", err)
+			}
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "configuration is valid")
+		return nil
+	})
+	cmd.Use = "validate"
+	cmd.Short = "Validate the serve configuration without starting the server"
+	cmd.Long = `Validate the serve flags, the --config file and the environment variables,
+and report the first problem found. The server is not started and no
+cluster is contacted.`
+	return cmd
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// doctorCheckTimeout bounds each readiness check of the doctor command.
+const doctorCheckTimeout = 15 * time.Second
+
+// googleIssuerURL is the OpenID Connect issuer of the Google provider.
+const googleIssuerURL = "https://accounts.google.com"
+
+// doctorCheck is a readiness check of the doctor command.
+type doctorCheck struct {
+	// name identifies the check in the report
+	name string
+
+	// check runs the check
+	check server.HealthCheckFunc
+
+	// hint tells the operator how to fix a failure
+	hint string
+}
+
+// newDoctorCmd creates the command that checks the dependencies of a serve
+// configuration and prints a readiness report, without starting the server.
+func newDoctorCmd() *cobra.Command {
+	cmd := newServeCommand(func(cmd *cobra.Command, config ServeConfig) error {
+		out := cmd.OutOrStdout()
+		if err := validateServeConfig(config); err != nil {
+			printDoctorResult(out, doctorCheck{
+				name: "configuration",
+				hint: "fix the setting above; mcp-kubernetes config validate repeats this check",
+			}, err)
+			return fmt.Errorf("configuration is invalid")
+		}
+		printDoctorResult(out, doctorCheck{name: "configuration"}, nil)
+
+		checks, closeChecks := doctorChecks(config)
+		defer closeChecks()
+		failed := runDoctorChecks(cmd.Context(), out, checks)
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks)+1)
+		}
+		_, _ = fmt.Fprintln(out, "ready")
+		return nil
+	})
+	cmd.Use = "doctor"
+	cmd.Short = "Check the dependencies of the serve configuration"
+	cmd.Long = `Check the serve configuration and the services it depends on, and print
+a readiness report with a hint for each failed check. The server is not
+started.
+
+Checks:
+  - configuration: the flags, the --config file and the environment variables
+  - kubernetes: the Kubernetes API server is reachable with the kubeconfig or
+    in-cluster credentials
+  - oauth-issuer: the OAuth issuer serves its OpenID Connect discovery
+    document (with --enable-oauth)
+  - valkey: Valkey answers a ping (when a Valkey backend is selected)
+  - cluster-api: the Cluster API CRDs are installed (when federation is
+    enabled)`
+	return cmd
+}
+
+// doctorChecks returns the readiness checks that apply to config. The
+// returned function releases the connections the checks opened.
+func doctorChecks(config ServeConfig) ([]doctorCheck, func()) {
+	var (
+		checks  []doctorCheck
+		closers []func()
+	)
+
+	// The client is created by the first check that needs it, so that a
+	// missing kubeconfig is reported as a failed check.
+	clientset := sync.OnceValues(func() (kubernetes.Interface, error) {
+		client, err := k8s.NewClient(&k8s.ClientConfig{
+			InCluster:       config.InCluster,
+			AllowedContexts: config.KubeconfigContexts,
+			Timeout:         config.Timeouts.Request,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return client.Clientset()
+	})
+
+	// Fixture replay never talks to a cluster.
+	if !config.Fixtures.Replay() {
+		hint := "check KUBECONFIG and its current context, e.g. with kubectl cluster-info"
+		if config.InCluster {
+			hint = "check that the pod's service account token is mounted and the API server is reachable from the pod"
+		}
+		checks = append(checks, doctorCheck{
+			name:  server.HealthCheckKubernetes,
+			check: server.KubernetesAPICheck(clientset),
+			hint:  hint,
+		})
+	}
+
+	if config.OAuth.Enabled && config.Transport != transportStdio {
+		issuer, caFile := googleIssuerURL, ""
+		hint := "check that " + googleIssuerURL + " is reachable from the server"
+		if config.OAuth.Provider == OAuthProviderDex {
+			issuer, caFile = config.OAuth.DexIssuerURL, config.OAuth.DexCAFile
+			hint = "check --dex-issuer-url (DEX_ISSUER_URL) and, for a private CA, --dex-ca-file (DEX_CA_FILE)"
+		}
+		checks = append(checks, doctorCheck{
+			name:  "oauth-issuer",
+			check: server.OAuthIssuerCheck(issuer, caFile),
+			hint:  hint,
+		})
+	}
+
+	if usesValkey(config) {
+		check, closeValkey := server.ValkeyCheck(config.OAuth.Storage.Valkey)
+		closers = append(closers, closeValkey)
+		checks = append(checks, doctorCheck{
+			name:  server.HealthCheckValkey,
+			check: check,
+			hint:  "check --valkey-url, --valkey-password, --valkey-tls and --valkey-db (VALKEY_* environment variables)",
+		})
+	}
+
+	if config.CAPIMode.Enabled {
+		checks = append(checks, doctorCheck{
+			name:  "cluster-api",
+			check: server.CAPICRDCheck(clientset),
+			hint:  "install the Cluster API CRDs on the management cluster, or disable federation (CAPI_MODE_ENABLED=false)",
+		})
+	}
+
+	return checks, func() {
+		for _, closeFn := range closers {
+			closeFn()
+		}
+	}
+}
+
+// usesValkey reports whether a backend selected by config stores its state
+// in Valkey.
+func usesValkey(config ServeConfig) bool {
+	return (config.OAuth.Enabled && config.OAuth.Storage.Type == server.OAuthStorageTypeValkey) ||
+		(config.RateLimit.Rate > 0 && config.RateLimit.Backend == ratelimit.BackendValkey) ||
+		config.Sessions.Backend == sessionBackendValkey ||
+		config.UndoJournal.Backend == undoJournalValkey ||
+		config.Preferences.Backend == preferencesValkey
+}
+
+// runDoctorChecks runs checks in order, prints their results to out and
+// returns the number of failed checks.
+func runDoctorChecks(ctx context.Context, out io.Writer, checks []doctorCheck) int {
+	failed := 0
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+		err := c.check(checkCtx)
+		cancel()
+		if err != nil {
+			failed++
+		}
+		printDoctorResult(out, c, err)
+	}
+	return failed
+}
+
+// printDoctorResult prints a line of the readiness report, and the hint of a
+// failed check.
+func printDoctorResult(out io.Writer, c doctorCheck, err error) {
+	if err == nil {
+		_, _ = fmt.Fprintf(out, "ok    %s\n", c.name)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "FAIL  %s: %v\n", c.name, err)
+	if c.hint != "" {
+		_, _ = fmt.Fprintf(out, "      hint: %s\n", c.hint)
+	}
+}
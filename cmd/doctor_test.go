@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+func TestRunDoctorChecks(t *testing.T) {
+	var out bytes.Buffer
+	failed := runDoctorChecks(context.Background(), &out, []doctorCheck{
+		{name: "good", check: func(context.Context) error { return nil }, hint: "unused"},
+		{name: "bad", check: func(context.Context) error { return errors.New("connection refused") }, hint: "start it"},
+	})
+
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, "ok    good\nFAIL  bad: connection refused\n      hint: start it\n", out.String())
+}
+
+func TestDoctorChecks(t *testing.T) {
+	checkNames := func(config ServeConfig) []string {
+		checks, closeChecks := doctorChecks(config)
+		defer closeChecks()
+		names := make([]string, len(checks))
+		for i, c := range checks {
+			names[i] = c.name
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{server.HealthCheckKubernetes}, checkNames(ServeConfig{Transport: transportStdio}))
+
+	assert.Empty(t, checkNames(ServeConfig{Transport: transportStdio, Fixtures: FixtureServeConfig{Dir: "fixtures"}}))
+
+	assert.Equal(t, []string{server.HealthCheckKubernetes, "oauth-issuer", server.HealthCheckValkey, "cluster-api"}, checkNames(ServeConfig{
+		Transport: transportStreamableHTTP,
+		OAuth: OAuthServeConfig{
+			Enabled:      true,
+			Provider:     OAuthProviderDex,
+			DexIssuerURL: "https://dex.example.com",
+			Storage:      server.OAuthStorageConfig{Type: server.OAuthStorageTypeMemory},
+		},
+		RateLimit: RateLimitServeConfig{Rate: 5, Backend: ratelimit.BackendValkey},
+		CAPIMode:  CAPIModeConfig{Enabled: true},
+	}))
+}
+
+func TestUsesValkey(t *testing.T) {
+	assert.False(t, usesValkey(ServeConfig{RateLimit: RateLimitServeConfig{Backend: ratelimit.BackendValkey}}))
+	assert.True(t, usesValkey(ServeConfig{UndoJournal: UndoJournalServeConfig{Backend: undoJournalValkey}}))
+	assert.True(t, usesValkey(ServeConfig{Preferences: PreferencesServeConfig{Backend: preferencesValkey}}))
+	assert.True(t, usesValkey(ServeConfig{Sessions: SessionServeConfig{Backend: sessionBackendValkey}}))
+}
+
+func TestDoctorCmd_InvalidConfiguration(t *testing.T) {
+	cmd := newDoctorCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--profile", "everything"})
+
+	assert.EqualError(t, cmd.Execute(), "configuration is invalid")
+	assert.Contains(t, out.String(), "FAIL  configuration:")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s/fixture"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+)
+
+// validateFixtureConfig rejects settings that bypass the server's Kubernetes
+// client, since their calls could be neither recorded nor replayed.
+func validateFixtureConfig(config ServeConfig) error {
+	if config.Fixtures.Record && config.Fixtures.Dir == "" {
+		return fmt.Errorf("--record-fixtures requires --fixture-dir")
+	}
+	if config.CAPIMode.Enabled {
+		return fmt.Errorf("--fixture-dir cannot be combined with CAPI mode (CAPI_MODE_ENABLED)")
+	}
+	if config.DownstreamOAuth {
+		return fmt.Errorf("--fixture-dir cannot be combined with --downstream-oauth")
+	}
+	if config.Fixtures.Replay() && config.HTTPAuth.Mode == middleware.AuthModeTokenReview {
+		return fmt.Errorf("--auth-mode=%s needs a cluster and cannot be used when replaying --fixture-dir", middleware.AuthModeTokenReview)
+	}
+	return nil
+}
+
+// newFixtureClient returns the client tools use with --fixture-dir: a
+// recorder around live when recording, otherwise a replay client that never
+// contacts a cluster.
+func newFixtureClient(config ServeConfig, live k8s.Client) (k8s.Client, error) {
+	if err := validateFixtureConfig(config); err != nil {
+		return nil, err
+	}
+
+	if config.Fixtures.Record {
+		recorder, err := fixture.NewRecorder(live, config.Fixtures.Dir, slog.Default())
+		if err != nil {
+			return nil, err
+		}
+		slog.Warn("recording Kubernetes calls to fixtures; recorded responses may contain secrets",
+			"dir", config.Fixtures.Dir)
+		return recorder, nil
+	}
+
+	replay, err := fixture.NewReplayClient(config.Fixtures.Dir)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("serving tools from recorded fixtures instead of a cluster",
+		"dir", config.Fixtures.Dir,
+		"fixtures", replay.Len())
+	return replay, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s/fixture"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+)
+
+func TestValidateFixtureConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ServeConfig
+		wantErr string
+	}{
+		{name: "replay", config: ServeConfig{Fixtures: FixtureServeConfig{Dir: "fixtures"}}},
+		{name: "record", config: ServeConfig{Fixtures: FixtureServeConfig{Dir: "fixtures", Record: true}}},
+		{
+			name:   "record with tokenreview",
+			config: ServeConfig{Fixtures: FixtureServeConfig{Dir: "fixtures", Record: true}, HTTPAuth: HTTPAuthServeConfig{Mode: middleware.AuthModeTokenReview}},
+		},
+		{
+			name:    "record without dir",
+			config:  ServeConfig{Fixtures: FixtureServeConfig{Record: true}},
+			wantErr: "requires --fixture-dir",
+		},
+		{
+			name:    "capi mode",
+			config:  ServeConfig{Fixtures: FixtureServeConfig{Dir: "fixtures"}, CAPIMode: CAPIModeConfig{Enabled: true}},
+			wantErr: "CAPI mode",
+		},
+		{
+			name:    "downstream oauth",
+			config:  ServeConfig{Fixtures: FixtureServeConfig{Dir: "fixtures"}, DownstreamOAuth: true},
+			wantErr: "--downstream-oauth",
+		},
+		{
+			name:    "replay with tokenreview",
+			config:  ServeConfig{Fixtures: FixtureServeConfig{Dir: "fixtures"}, HTTPAuth: HTTPAuthServeConfig{Mode: middleware.AuthModeTokenReview}},
+			wantErr: "needs a cluster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFixtureConfig(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewFixtureClient(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "contexts.json"),
+		[]byte(`{"method": "ListContexts", "response": [{"name": "demo"}]}`), 0o600))
+
+	client, err := newFixtureClient(ServeConfig{Fixtures: FixtureServeConfig{Dir: dir}}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &fixture.ReplayClient{}, client)
+
+	recordDir := filepath.Join(dir, "recorded")
+	client, err = newFixtureClient(ServeConfig{Fixtures: FixtureServeConfig{Dir: recordDir, Record: true}}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &fixture.Recorder{}, client)
+	assert.DirExists(t, recordDir)
+
+	_, err = newFixtureClient(ServeConfig{Fixtures: FixtureServeConfig{Dir: filepath.Join(dir, "missing")}}, nil)
+	assert.Error(t, err)
+}
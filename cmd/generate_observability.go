@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+)
+
+// newGenerateObservabilityCmd creates the Cobra command that emits a
+// Prometheus Operator monitor and a Grafana dashboard for the server's metrics.
+func newGenerateObservabilityCmd() *cobra.Command {
+	var (
+		opts      instrumentation.ObservabilityOptions
+		kind      string
+		outputDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate-observability",
+		Short: "Generate a ServiceMonitor/PodMonitor and Grafana dashboard",
+		Long: `Generates a Prometheus Operator ServiceMonitor (or PodMonitor) that scrapes the
+mcp-kubernetes metrics endpoint, plus an opinionated Grafana dashboard covering
+the server's metric families (HTTP, Kubernetes operations, authentication and
+federation).
+
+By default both are written to stdout as YAML manifests, with the dashboard
+wrapped in a ConfigMap labelled for the Grafana dashboard sidecar, so the
+output can be piped straight into kubectl:
+
+  mcp-kubernetes generate-observability --namespace mcp | kubectl apply -f -
+
+With --output-dir the monitor manifest and the raw dashboard JSON are written
+to separate files instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch strings.ToLower(kind) {
+			case "servicemonitor":
+				opts.Kind = instrumentation.MonitorKindServiceMonitor
+			case "podmonitor":
+				opts.Kind = instrumentation.MonitorKindPodMonitor
+			default:
+				return fmt.Errorf("invalid --kind %q: must be servicemonitor or podmonitor", kind)
+			}
+			return runGenerateObservability(cmd.OutOrStdout(), opts, outputDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Name, "name", "mcp-kubernetes", "Name of the generated monitor and dashboard ConfigMap (for ServiceMonitor, also the Service name used as scrape job)")
+	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "default", "Namespace mcp-kubernetes runs in; the monitor is created there and dashboard queries are scoped to it")
+	cmd.Flags().StringToStringVar(&opts.Selector, "selector", map[string]string{"app.kubernetes.io/name": "mcp-kubernetes"}, "Labels selecting the mcp-kubernetes Service or Pods (key=value,...)")
+	cmd.Flags().StringToStringVar(&opts.MonitorLabels, "monitor-labels", nil, "Extra labels for the monitor, e.g. the release label your Prometheus selects (key=value,...)")
+	cmd.Flags().StringToStringVar(&opts.DashboardLabels, "dashboard-labels", map[string]string{"grafana_dashboard": "1"}, "Labels for the dashboard ConfigMap (key=value,...)")
+	cmd.Flags().StringVar(&kind, "kind", "servicemonitor", "Monitor kind: servicemonitor or podmonitor")
+	cmd.Flags().StringVar(&opts.Port, "port", "metrics", "Name of the metrics port on the Service or Pod")
+	cmd.Flags().StringVar(&opts.Path, "path", "/metrics", "Metrics endpoint path")
+	cmd.Flags().StringVar(&opts.Interval, "interval", "30s", "Scrape interval")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write <name>-monitor.yaml and <name>-dashboard.json to this directory instead of stdout")
+
+	return cmd
+}
+
+// runGenerateObservability renders the monitor and dashboard and writes them
+// to w, or to files in outputDir when set.
+func runGenerateObservability(w io.Writer, opts instrumentation.ObservabilityOptions, outputDir string) error {
+	monitor, err := instrumentation.GenerateMonitor(opts)
+	if err != nil {
+		return err
+	}
+	dashboard, err := instrumentation.GenerateDashboard(opts)
+	if err != nil {
+		return err
+	}
+
+	monitorYAML, err := yaml.Marshal(monitor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal monitor: %w", err)
+	}
+	dashboardJSON, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dashboard: %w", err)
+	}
+
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		monitorPath := filepath.Join(outputDir, opts.Name+"-monitor.yaml")
+		if err := os.WriteFile(monitorPath, monitorYAML, 0o644); err != nil {
+			return fmt.Errorf("failed to write monitor: %w", err)
+		}
+		dashboardPath := filepath.Join(outputDir, opts.Name+"-dashboard.json")
+		if err := os.WriteFile(dashboardPath, append(dashboardJSON, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write dashboard: %w", err)
+		}
+		_, _ = fmt.Fprintf(w, "Wrote %s\nWrote %s\n", monitorPath, dashboardPath)
+		return nil
+	}
+
+	configMapYAML, err := yaml.Marshal(instrumentation.GenerateDashboardConfigMap(opts, dashboardJSON))
+	if err != nil {
+		return fmt.Errorf("failed to marshal dashboard ConfigMap: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "---\n%s---\n%s", monitorYAML, configMapYAML)
+	return err
+}
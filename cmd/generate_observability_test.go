@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateObservabilityCmd_Stdout(t *testing.T) {
+	cmd := newGenerateObservabilityCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--namespace", "mcp", "--selector", "app=mcp", "--monitor-labels", "release=prom"})
+	require.NoError(t, cmd.Execute())
+
+	docs := strings.Split(strings.TrimPrefix(out.String(), "---\n"), "---\n")
+	require.Len(t, docs, 2)
+
+	var monitor map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(docs[0]), &monitor))
+	assert.Equal(t, "ServiceMonitor", monitor["kind"])
+
+	var configMap struct {
+		Kind string            `json:"kind"`
+		Data map[string]string `json:"data"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &configMap))
+	assert.Equal(t, "ConfigMap", configMap.Kind)
+
+	var dashboard map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data["mcp-kubernetes.json"]), &dashboard))
+	assert.NotEmpty(t, dashboard["panels"])
+}
+
+func TestGenerateObservabilityCmd_OutputDir(t *testing.T) {
+	dir := t.TempDir()
+	cmd := newGenerateObservabilityCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--kind", "podmonitor", "--output-dir", dir})
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(filepath.Join(dir, "mcp-kubernetes-monitor.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "kind: PodMonitor")
+
+	data, err = os.ReadFile(filepath.Join(dir, "mcp-kubernetes-dashboard.json"))
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+}
+
+func TestGenerateObservabilityCmd_InvalidKind(t *testing.T) {
+	cmd := newGenerateObservabilityCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--kind", "probe"})
+	assert.Error(t, cmd.Execute())
+}
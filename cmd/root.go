@@ -61,6 +61,11 @@ func init() {
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newSelfUpdateCmd())
 	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newGenerateObservabilityCmd())
+	rootCmd.AddCommand(newRotateEncryptionKeyCmd())
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newToolsCmd())
 
 	// Example of how to define persistent flags (global for the application):
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/mcp-kubernetes/config.yaml)")
@@ -39,6 +39,7 @@ func TestRootCommandHasSubcommands(t *testing.T) {
 	assert.Contains(t, foundCommands, "version")
 	assert.Contains(t, foundCommands, "self-update")
 	assert.Contains(t, foundCommands, "serve")
+	assert.Contains(t, foundCommands, "rotate-encryption-key")
 
 	// Ensure we have at least the minimum expected commands
 	assert.GreaterOrEqual(t, len(foundCommands), 3)
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/keyrotation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// newRotateEncryptionKeyCmd creates the Cobra command that re-encrypts OAuth
+// tokens stored in Valkey from an old encryption key to a new one.
+func newRotateEncryptionKeyCmd() *cobra.Command {
+	var (
+		oldKey  string
+		newKey  string
+		storage server.OAuthStorageConfig
+		dryRun  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rotate-encryption-key",
+		Short: "Re-encrypt stored OAuth tokens with a new encryption key",
+		Long: `Re-encrypts the OAuth tokens stored in Valkey from the previous encryption key
+to a new one, so rotating OAUTH_ENCRYPTION_KEY does not invalidate every user
+session.
+
+Tokens already encrypted with the new key are left alone, so the command can be
+run repeatedly and concurrently with running servers. A row changed by a server
+while it is being rotated is skipped and reported as a conflict; run the
+command again to pick it up.
+
+  mcp-kubernetes rotate-encryption-key \
+    --old-key "$OLD_KEY" --new-key "$NEW_KEY" --valkey-url valkey:6379
+
+For an online rotation without a separate step, start the servers with
+--oauth-encryption-key set to the new key and --oauth-encryption-key-previous
+set to the old one instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("old-key") {
+				loadEnvIfEmpty(&oldKey, "OAUTH_ENCRYPTION_KEY_PREVIOUS")
+			}
+			if !cmd.Flags().Changed("new-key") {
+				loadEnvIfEmpty(&newKey, "OAUTH_ENCRYPTION_KEY")
+			}
+			loadOAuthStorageEnvVars(cmd, &storage)
+
+			if storage.Valkey.URL == "" {
+				return fmt.Errorf("--valkey-url is required (or set VALKEY_URL)")
+			}
+			oldDecoded, newDecoded, err := decodeRotationKeys(oldKey, newKey)
+			if err != nil {
+				return err
+			}
+
+			client, err := server.NewValkeyClient(storage.Valkey)
+			if err != nil {
+				return fmt.Errorf("failed to connect to Valkey: %w", err)
+			}
+			store := keyrotation.NewValkeyStore(client)
+			defer func() { _ = store.Close() }()
+
+			return runRotateEncryptionKey(cmd.Context(), cmd.OutOrStdout(), store, storage.Valkey.KeyPrefix, oldDecoded, newDecoded, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&oldKey, "old-key", "", "Base64 AES-256 key the tokens are currently encrypted with (can also be set via OAUTH_ENCRYPTION_KEY_PREVIOUS env var)")
+	cmd.Flags().StringVar(&newKey, "new-key", "", "Base64 AES-256 key to re-encrypt the tokens with (can also be set via OAUTH_ENCRYPTION_KEY env var)")
+	cmd.Flags().StringVar(&storage.Valkey.URL, "valkey-url", "", "Valkey server address (e.g., valkey.namespace.svc:6379, can also be set via VALKEY_URL env var)")
+	cmd.Flags().StringVar(&storage.Valkey.Password, "valkey-password", "", "Valkey authentication password (can also be set via VALKEY_PASSWORD env var)")
+	cmd.Flags().BoolVar(&storage.Valkey.TLSEnabled, "valkey-tls", false, "Enable TLS for Valkey connections (can also be set via VALKEY_TLS_ENABLED env var)")
+	cmd.Flags().StringVar(&storage.Valkey.KeyPrefix, "valkey-key-prefix", "mcp:", "Prefix for all Valkey keys (can also be set via VALKEY_KEY_PREFIX env var)")
+	cmd.Flags().IntVar(&storage.Valkey.DB, "valkey-db", 0, "Valkey database number (can also be set via VALKEY_DB env var)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report how many tokens would be re-encrypted without writing anything")
+
+	return cmd
+}
+
+// decodeRotationKeys decodes and validates the old and new encryption keys.
+func decodeRotationKeys(oldKey, newKey string) ([]byte, []byte, error) {
+	if oldKey == "" {
+		return nil, nil, fmt.Errorf("--old-key is required (or set OAUTH_ENCRYPTION_KEY_PREVIOUS)")
+	}
+	if newKey == "" {
+		return nil, nil, fmt.Errorf("--new-key is required (or set OAUTH_ENCRYPTION_KEY)")
+	}
+	oldDecoded, err := decodeEncryptionKey(oldKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("old encryption key: %w", err)
+	}
+	newDecoded, err := decodeEncryptionKey(newKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new encryption key: %w", err)
+	}
+	return oldDecoded, newDecoded, nil
+}
+
+// runRotateEncryptionKey runs a single rotation pass over store and writes a
+// summary to w. It fails when any token could not be re-encrypted so scripts
+// notice an incomplete rotation.
+func runRotateEncryptionKey(ctx context.Context, w io.Writer, store keyrotation.Store, keyPrefix string, oldKey, newKey []byte, dryRun bool) error {
+	rotator, err := keyrotation.NewRotator(store, keyPrefix, oldKey, newKey, slog.Default())
+	if err != nil {
+		return err
+	}
+	result, err := rotator.Run(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Re-encrypted"
+	if dryRun {
+		verb = "Would re-encrypt"
+	}
+	_, _ = fmt.Fprintf(w, "Scanned %d stored tokens\n", result.Scanned)
+	_, _ = fmt.Fprintf(w, "%s: %d\n", verb, result.Rotated)
+	_, _ = fmt.Fprintf(w, "Already using the new key: %d\n", result.Current)
+	_, _ = fmt.Fprintf(w, "Changed during rotation (run again): %d\n", result.Conflicts)
+	_, _ = fmt.Fprintf(w, "Failed: %d\n", result.Failed)
+
+	if result.Failed > 0 {
+		return fmt.Errorf("%d stored tokens could not be re-encrypted; see the log for details", result.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/mcp-oauth/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotationStore is a minimal keyrotation.Store returning all keys in one scan.
+type rotationStore struct {
+	data map[string]string
+}
+
+func (s *rotationStore) Scan(_ context.Context, _ uint64, pattern string, _ int64) ([]string, uint64, error) {
+	var keys []string
+	for k := range s.data {
+		if strings.HasPrefix(k, strings.TrimSuffix(pattern, "*")) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, 0, nil
+}
+
+func (s *rotationStore) Get(_ context.Context, key string) (string, bool, error) {
+	v, ok := s.data[key]
+	return v, ok, nil
+}
+
+func (s *rotationStore) CompareAndSet(_ context.Context, key, oldValue, newValue string) (bool, error) {
+	if s.data[key] != oldValue {
+		return false, nil
+	}
+	s.data[key] = newValue
+	return true, nil
+}
+
+func encryptedTokenRow(t *testing.T, key []byte, access string) string {
+	t.Helper()
+	enc, err := security.NewEncryptor(key)
+	require.NoError(t, err)
+	encrypted, err := enc.Encrypt(access)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]string{"access_token": encrypted, "token_type": "Bearer"})
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestDecodeEncryptionKey(t *testing.T) {
+	key, err := security.GenerateKey()
+	require.NoError(t, err)
+
+	decoded, err := decodeEncryptionKey(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, decoded)
+
+	_, err = decodeEncryptionKey("not base64!")
+	assert.ErrorContains(t, err, "base64")
+
+	_, err = decodeEncryptionKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "validation failed")
+}
+
+func TestDecodeRotationKeys(t *testing.T) {
+	oldKey, err := security.GenerateKey()
+	require.NoError(t, err)
+	newKey, err := security.GenerateKey()
+	require.NoError(t, err)
+	oldB64 := base64.StdEncoding.EncodeToString(oldKey)
+	newB64 := base64.StdEncoding.EncodeToString(newKey)
+
+	gotOld, gotNew, err := decodeRotationKeys(oldB64, newB64)
+	require.NoError(t, err)
+	assert.Equal(t, oldKey, gotOld)
+	assert.Equal(t, newKey, gotNew)
+
+	_, _, err = decodeRotationKeys("", newB64)
+	assert.ErrorContains(t, err, "--old-key")
+	_, _, err = decodeRotationKeys(oldB64, "")
+	assert.ErrorContains(t, err, "--new-key")
+	_, _, err = decodeRotationKeys(oldB64, "invalid")
+	assert.ErrorContains(t, err, "new encryption key")
+}
+
+func TestRunRotateEncryptionKey(t *testing.T) {
+	oldKey, err := security.GenerateKey()
+	require.NoError(t, err)
+	newKey, err := security.GenerateKey()
+	require.NoError(t, err)
+
+	original := encryptedTokenRow(t, oldKey, "access-1")
+	store := &rotationStore{data: map[string]string{
+		"mcp:token:alice": original,
+		"mcp:token:bob":   encryptedTokenRow(t, newKey, "access-2"),
+		"mcp:client:x":    "unrelated",
+	}}
+
+	t.Run("dry run leaves rows untouched", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runRotateEncryptionKey(context.Background(), &out, store, "mcp:", oldKey, newKey, true))
+		assert.Contains(t, out.String(), "Scanned 2 stored tokens")
+		assert.Contains(t, out.String(), "Would re-encrypt: 1")
+		assert.Equal(t, original, store.data["mcp:token:alice"])
+	})
+
+	t.Run("rotates rows encrypted with the old key", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runRotateEncryptionKey(context.Background(), &out, store, "mcp:", oldKey, newKey, false))
+		assert.Contains(t, out.String(), "Re-encrypted: 1")
+		assert.Contains(t, out.String(), "Already using the new key: 1")
+		assert.NotEqual(t, original, store.data["mcp:token:alice"])
+		assert.Equal(t, "unrelated", store.data["mcp:client:x"])
+	})
+
+	t.Run("fails when rows cannot be decrypted", func(t *testing.T) {
+		otherKey, err := security.GenerateKey()
+		require.NoError(t, err)
+		store.data["mcp:token:carol"] = encryptedTokenRow(t, otherKey, "access-3")
+
+		var out bytes.Buffer
+		err = runRotateEncryptionKey(context.Background(), &out, store, "mcp:", oldKey, newKey, false)
+		assert.ErrorContains(t, err, "1 stored tokens could not be re-encrypted")
+		assert.Contains(t, out.String(), "Failed: 1")
+	})
+}
+
+func TestRotateEncryptionKeyCmd_RequiresValkeyURL(t *testing.T) {
+	t.Setenv("VALKEY_URL", "")
+	cmd := newRotateEncryptionKeyCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--old-key", "a", "--new-key", "b"})
+	assert.ErrorContains(t, cmd.Execute(), "--valkey-url is required")
+}
+
+func TestStartEncryptionKeyRotation(t *testing.T) {
+	oldKey, err := security.GenerateKey()
+	require.NoError(t, err)
+	newKey, err := security.GenerateKey()
+	require.NoError(t, err)
+	cfg := OAuthServeConfig{PreviousEncryptionKey: base64.StdEncoding.EncodeToString(oldKey)}
+
+	_, err = startEncryptionKeyRotation(context.Background(), cfg, nil)
+	assert.ErrorContains(t, err, "requires --oauth-encryption-key")
+
+	// In-memory storage has nothing to rotate or read with the previous key.
+	previousKey, err := startEncryptionKeyRotation(context.Background(), cfg, newKey)
+	assert.NoError(t, err)
+	assert.Nil(t, previousKey)
+
+	cfg.PreviousEncryptionKey = "invalid"
+	_, err = startEncryptionKeyRotation(context.Background(), cfg, newKey)
+	assert.ErrorContains(t, err, "previous OAuth encryption key")
+}
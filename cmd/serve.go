@@ -17,20 +17,33 @@ import (
 
 	tklogging "github.com/giantswarm/mcp-toolkit/logging"
 	"github.com/giantswarm/mcp-toolkit/middleware/responsecap"
-	"github.com/giantswarm/mcp-toolkit/middleware/timeout"
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/valkey-io/valkey-go"
+	"k8s.io/client-go/kubernetes"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+	"github.com/giantswarm/mcp-kubernetes/internal/confirmation"
 	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/keyrotation"
 	"github.com/giantswarm/mcp-kubernetes/internal/logging"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
+	"github.com/giantswarm/mcp-kubernetes/internal/plan"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/access"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/capi"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/cluster"
 	contexttools "github.com/giantswarm/mcp-kubernetes/internal/tools/context"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/pod"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource"
 )
@@ -111,6 +124,144 @@ func parseFloat32Env(value, envName string) (float32, bool) {
 	return float32(f), ok
 }
 
+// Session backends accepted by --session-backend.
+const (
+	sessionBackendMemory = "memory"
+	sessionBackendValkey = "valkey"
+)
+
+// Undo journal backends accepted by --undo-journal.
+const (
+	undoJournalMemory = "memory"
+	undoJournalValkey = "valkey"
+)
+
+// Preferences backends accepted by --preferences.
+const (
+	preferencesMemory = "memory"
+	preferencesValkey = "valkey"
+)
+
+// newPreferencesStore creates the store of per-user preferences, or nil
+// when preferences are disabled.
+func newPreferencesStore(cfg PreferencesServeConfig, valkeyCfg server.ValkeyStorageConfig) (*preferences.Store, error) {
+	var backend preferences.Backend
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case preferencesMemory:
+		backend = preferences.NewMemoryBackend()
+	case preferencesValkey:
+		client, err := server.NewValkeyClient(valkeyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create preferences store: %w", err)
+		}
+		backend = preferences.NewValkeyBackend(client)
+	default:
+		return nil, fmt.Errorf("unsupported preferences backend: %s (supported: %s, %s)", cfg.Backend, preferencesMemory, preferencesValkey)
+	}
+	slog.Info("user preferences enabled", "backend", cfg.Backend)
+	return preferences.New(backend, valkeyCfg.KeyPrefix), nil
+}
+
+// newUndoJournal creates the journal of changes for the undo tool, or nil
+// when it is disabled.
+func newUndoJournal(cfg UndoJournalServeConfig, valkeyCfg server.ValkeyStorageConfig) (*journal.Journal, error) {
+	var backend journal.Backend
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case undoJournalMemory:
+		backend = journal.NewMemoryBackend()
+	case undoJournalValkey:
+		client, err := server.NewValkeyClient(valkeyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create undo journal: %w", err)
+		}
+		backend = journal.NewValkeyBackend(client)
+	default:
+		return nil, fmt.Errorf("unsupported undo journal backend: %s (supported: %s, %s)", cfg.Backend, undoJournalMemory, undoJournalValkey)
+	}
+	if cfg.Size <= 0 {
+		_ = backend.Close()
+		return nil, fmt.Errorf("--undo-journal-size must be positive, got %d", cfg.Size)
+	}
+	if cfg.TTL <= 0 {
+		_ = backend.Close()
+		return nil, fmt.Errorf("--undo-journal-ttl must be positive, got %s", cfg.TTL)
+	}
+	slog.Info("undo journal enabled", "backend", cfg.Backend, "size", cfg.Size, "ttl", cfg.TTL)
+	return journal.New(backend, valkeyCfg.KeyPrefix, cfg.Size, cfg.TTL), nil
+}
+
+// sessionServerOptions returns the server options setting the replica ID
+// and, with the valkey backend, the registry sharing session ownership
+// with other replicas.
+func sessionServerOptions(cfg SessionServeConfig, valkeyCfg server.ValkeyStorageConfig) ([]server.Option, error) {
+	replicaID := cfg.ReplicaID
+	if replicaID == "" {
+		replicaID = sessions.DefaultReplicaID()
+	}
+	opts := []server.Option{server.WithReplicaID(replicaID)}
+
+	switch cfg.Backend {
+	case sessionBackendMemory, "":
+		return opts, nil
+	case sessionBackendValkey:
+	default:
+		return nil, fmt.Errorf("unsupported session backend: %s (supported: %s, %s)", cfg.Backend, sessionBackendMemory, sessionBackendValkey)
+	}
+
+	client, err := server.NewValkeyClient(valkeyCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session registry: %w", err)
+	}
+	store := sessions.NewValkeyStore(client)
+	registry, err := sessions.NewRegistry(store, valkeyCfg.KeyPrefix, replicaID, slog.Default())
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("failed to create session registry: %w", err)
+	}
+	slog.Info("shared session registry enabled", "replica_id", replicaID)
+	return append(opts, server.WithSessionRegistry(registry)), nil
+}
+
+// parseToolTimeouts parses the --tool-timeouts overrides into durations.
+// Returns nil if there are none.
+func parseToolTimeouts(values map[string]string) (map[string]time.Duration, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	timeouts := make(map[string]time.Duration, len(values))
+	for name, value := range values {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --tool-timeouts value for %q: %w", name, err)
+		}
+		timeouts[name] = d
+	}
+	return timeouts, nil
+}
+
+// impersonationExtrasConfig validates the per-request impersonation extras
+// of config. The extra keys of --impersonation-extras-meta must be valid, so
+// that a typo is reported at startup instead of silently dropping the extra.
+func impersonationExtrasConfig(config ImpersonationExtrasServeConfig) (*server.ImpersonationExtrasConfig, error) {
+	for field, key := range config.RequestMeta {
+		if field == "" {
+			return nil, fmt.Errorf("invalid --impersonation-extras-meta: empty _meta field for key %q", key)
+		}
+		if err := k8s.ValidateImpersonationExtraKey(key); err != nil {
+			return nil, fmt.Errorf("invalid --impersonation-extras-meta: %w", err)
+		}
+	}
+	return &server.ImpersonationExtrasConfig{
+		ClientInfo:  config.ClientInfo,
+		SessionID:   config.SessionID,
+		RequestMeta: config.RequestMeta,
+	}, nil
+}
+
 // splitAndTrimAudiences splits a comma-separated string into a slice of trimmed audiences.
 // Empty entries are filtered out. Returns nil if the result is empty.
 // This is used to parse OAUTH_TRUSTED_AUDIENCES env var.
@@ -134,13 +285,141 @@ func splitAndTrimAudiences(value string) []string {
 
 // newServeCmd creates the Cobra command for starting the MCP server.
 func newServeCmd() *cobra.Command {
+	return newServeCommand(func(_ *cobra.Command, config ServeConfig) error {
+		return runServe(config)
+	})
+}
+
+// newServeCommand creates a command with the serve flags, the --config file
+// and the environment variables, which calls run with the resulting
+// configuration.
+func newServeCommand(run func(cmd *cobra.Command, config ServeConfig) error) *cobra.Command {
 	var (
+		// Configuration file
+		configFile string
+
 		nonDestructiveMode bool
 		dryRun             bool
 		qpsLimit           float32
 		burstLimit         int
+		userQPSLimit       float32
+		userBurstLimit     int
+		protobuf           bool
 		debugMode          bool
 		inCluster          bool
+		kubeconfigContexts []string
+
+		// Admin impersonation override
+		allowImpersonateAs bool
+
+		// Images allowed for ephemeral debug containers
+		debugImages  []string
+		copyPaths    []string
+		copyMaxBytes int64
+
+		// Tool authorization policy
+		readOnlyGroups       []string
+		policyFile           string
+		opaURL               string
+		opaTimeout           time.Duration
+		opaFailOpen          bool
+		policyReloadInterval time.Duration
+
+		restrictedNamespaceSelector string
+		restrictedNamespaceCacheTTL time.Duration
+		restrictedNamespaceFailOpen bool
+
+		readRestrictions         []string
+		writeNamespaces          []string
+		allowClusterScopedWrites bool
+
+		// Tool output processing
+		scrubPII           bool
+		piiPatterns        []string
+		secretRedaction    string
+		scrubCredentials   bool
+		credentialPatterns []string
+		secretWrites       string
+
+		tokenWarningThreshold int
+
+		// Journal of changes for the undo tool
+		undoJournalBackend string
+		undoJournalSize    int
+		undoJournalTTL     time.Duration
+
+		// Per-user preferences
+		preferencesBackend string
+
+		// Confirmation of destructive tool calls
+		confirmDestructive bool
+		confirmationTTL    time.Duration
+
+		// Change-sets built with the plan tools
+		enablePlans bool
+		planTTL     time.Duration
+
+		// Tool exposure profile and the admin endpoint changing it
+		toolProfile    string
+		adminAddr      string
+		adminTokenFile string
+
+		// Tool rate limiting
+		toolRateLimit        float64
+		toolRateLimitBurst   int
+		toolRateLimitPerTool bool
+		toolRateLimitBackend string
+
+		// Session ownership across replicas
+		replicaID      string
+		sessionBackend string
+
+		// Non-OAuth HTTP authentication
+		authMode           string
+		authTokenFile      string
+		authTokenAudiences []string
+
+		// Recorded fixtures
+		fixtureDir     string
+		recordFixtures bool
+
+		// Server logs
+		logFile           string
+		logFormat         string
+		logMaxBytes       int64
+		logRotateInterval time.Duration
+		logMaxBackups     int
+
+		// Debug capture of tool calls
+		debugCaptureSize     int
+		debugCaptureMaxBytes int
+
+		// Kubernetes API timeouts
+		requestTimeout       time.Duration
+		discoveryTimeout     time.Duration
+		discoveryMinInterval time.Duration
+		toolTimeout          time.Duration
+		toolTimeouts         map[string]string
+
+		// Informer-backed list cache
+		listCacheResources []string
+		listCacheResync    time.Duration
+
+		// Retries of Kubernetes API reads
+		apiRetries         int
+		apiRetryBackoff    time.Duration
+		apiRetryMaxBackoff time.Duration
+
+		// Exec credential plugin hardening
+		execPluginAllowlist []string
+		execPluginTimeout   time.Duration
+		execPluginMaxOutput int64
+
+		// Impersonation extras for audit logs
+		impersonationExtras          map[string]string
+		impersonationExtrasMeta      map[string]string
+		impersonationExtrasClient    bool
+		impersonationExtrasSessionID bool
 
 		// Transport options
 		transport       string
@@ -153,6 +432,10 @@ func newServeCmd() *cobra.Command {
 		metricsEnabled bool
 		metricsAddr    string
 
+		// Response compression options
+		compressionEnabled bool
+		compressionMinSize int
+
 		// OAuth options
 		enableOAuth                        bool
 		oauthBaseURL                       string
@@ -172,6 +455,7 @@ func newServeCmd() *cobra.Command {
 		allowPrivateOAuthURLs              bool
 		maxClientsPerIP                    int
 		oauthEncryptionKey                 string
+		oauthEncryptionKeyPrevious         string
 		downstreamOAuth                    bool
 		tlsCertFile                        string
 		tlsKeyFile                         string
@@ -228,6 +512,17 @@ Downstream OAuth (--downstream-oauth):
   account token. This ensures users only have their configured RBAC permissions.
   Requires the Kubernetes cluster to be configured for OIDC authentication.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// The configuration file sets the flags that were not passed and
+			// whose environment variables are not set.
+			var configFileEnv map[string]string
+			if configFile != "" {
+				var err error
+				configFileEnv, err = applyConfigFile(cmd.Flags(), configFile)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Load TLS paths from environment if not provided via flags
 			loadEnvIfEmpty(&tlsCertFile, "TLS_CERT_FILE")
 			loadEnvIfEmpty(&tlsKeyFile, "TLS_KEY_FILE")
@@ -246,6 +541,21 @@ Downstream OAuth (--downstream-oauth):
 			// Load env vars only for flags not explicitly set by user
 			loadOAuthStorageEnvVars(cmd, &storageConfig)
 
+			rateLimitConfig := RateLimitServeConfig{
+				Rate:    toolRateLimit,
+				Burst:   toolRateLimitBurst,
+				PerTool: toolRateLimitPerTool,
+				Backend: toolRateLimitBackend,
+			}
+			loadRateLimitEnvVars(cmd, &rateLimitConfig)
+
+			httpAuthConfig := HTTPAuthServeConfig{
+				Mode:      authMode,
+				TokenFile: authTokenFile,
+				Audiences: authTokenAudiences,
+			}
+			loadHTTPAuthEnvVars(cmd, &httpAuthConfig)
+
 			// CIMD env var - only apply if flag was not explicitly set
 			if !cmd.Flags().Changed("enable-cimd") {
 				if envVal := os.Getenv("ENABLE_CIMD"); envVal != "" {
@@ -295,6 +605,11 @@ Downstream OAuth (--downstream-oauth):
 					"recommendation", "use the VALKEY_PASSWORD environment variable instead")
 			}
 
+			toolTimeoutOverrides, err := parseToolTimeouts(toolTimeouts)
+			if err != nil {
+				return err
+			}
+
 			config := ServeConfig{
 				Transport:          transport,
 				HTTPAddr:           httpAddr,
@@ -305,8 +620,109 @@ Downstream OAuth (--downstream-oauth):
 				DryRun:             dryRun,
 				QPSLimit:           qpsLimit,
 				BurstLimit:         burstLimit,
+				UserQPSLimit:       userQPSLimit,
+				UserBurstLimit:     userBurstLimit,
+				Protobuf:           protobuf,
 				DebugMode:          debugMode,
 				InCluster:          inCluster,
+				KubeconfigContexts: kubeconfigContexts,
+				AllowImpersonateAs: allowImpersonateAs,
+				DebugImages:        debugImages,
+				CopyPaths:          copyPaths,
+				CopyMaxBytes:       copyMaxBytes,
+				Policy: PolicyServeConfig{
+					ReadOnlyGroups: readOnlyGroups,
+					File:           policyFile,
+					ReloadInterval: policyReloadInterval,
+					OPAURL:         opaURL,
+					OPATimeout:     opaTimeout,
+					OPAFailOpen:    opaFailOpen,
+
+					RestrictedNamespaceSelector: restrictedNamespaceSelector,
+					RestrictedNamespaceCacheTTL: restrictedNamespaceCacheTTL,
+					RestrictedNamespaceFailOpen: restrictedNamespaceFailOpen,
+
+					ReadRestrictions: readRestrictions,
+					WriteNamespaces:  writeNamespaces,
+
+					AllowClusterScopedWrites: allowClusterScopedWrites,
+				},
+				Output: OutputServeConfig{
+					ScrubPII:        scrubPII,
+					PIIPatterns:     piiPatterns,
+					SecretRedaction: secretRedaction,
+
+					ScrubCredentials:   scrubCredentials,
+					CredentialPatterns: credentialPatterns,
+
+					TokenWarningThreshold: tokenWarningThreshold,
+				},
+				SecretWrites:       secretWrites,
+				ConfirmDestructive: confirmDestructive,
+				ConfirmationTTL:    confirmationTTL,
+				EnablePlans:        enablePlans,
+				PlanTTL:            planTTL,
+				ToolProfile:        toolProfile,
+				RateLimit:          rateLimitConfig,
+				HTTPAuth:           httpAuthConfig,
+				Admin: AdminServeConfig{
+					Addr:      adminAddr,
+					TokenFile: adminTokenFile,
+				},
+				Sessions: SessionServeConfig{
+					ReplicaID: replicaID,
+					Backend:   sessionBackend,
+				},
+				UndoJournal: UndoJournalServeConfig{
+					Backend: undoJournalBackend,
+					Size:    undoJournalSize,
+					TTL:     undoJournalTTL,
+				},
+				Preferences: PreferencesServeConfig{
+					Backend: preferencesBackend,
+				},
+				Fixtures: FixtureServeConfig{
+					Dir:    fixtureDir,
+					Record: recordFixtures,
+				},
+				Logging: LoggingServeConfig{
+					File:           logFile,
+					Format:         logFormat,
+					MaxBytes:       logMaxBytes,
+					RotateInterval: logRotateInterval,
+					MaxBackups:     logMaxBackups,
+				},
+				DebugCapture: DebugCaptureServeConfig{
+					Size:     debugCaptureSize,
+					MaxBytes: debugCaptureMaxBytes,
+				},
+				Timeouts: TimeoutServeConfig{
+					Request:              requestTimeout,
+					Discovery:            discoveryTimeout,
+					DiscoveryMinInterval: discoveryMinInterval,
+					Tool:                 toolTimeout,
+					Tools:                toolTimeoutOverrides,
+				},
+				ListCache: ListCacheServeConfig{
+					Resources: listCacheResources,
+					Resync:    listCacheResync,
+				},
+				Retry: RetryServeConfig{
+					MaxRetries:     apiRetries,
+					InitialBackoff: apiRetryBackoff,
+					MaxBackoff:     apiRetryMaxBackoff,
+				},
+				ExecPlugins: ExecPluginServeConfig{
+					Allowlist:      execPluginAllowlist,
+					Timeout:        execPluginTimeout,
+					MaxOutputBytes: execPluginMaxOutput,
+				},
+				ImpersonationExtras: ImpersonationExtrasServeConfig{
+					Static:      impersonationExtras,
+					ClientInfo:  impersonationExtrasClient,
+					SessionID:   impersonationExtrasSessionID,
+					RequestMeta: impersonationExtrasMeta,
+				},
 				OAuth: OAuthServeConfig{
 					Enabled:                            enableOAuth,
 					BaseURL:                            oauthBaseURL,
@@ -326,6 +742,7 @@ Downstream OAuth (--downstream-oauth):
 					AllowPrivateURLs:                   allowPrivateOAuthURLs,
 					MaxClientsPerIP:                    maxClientsPerIP,
 					EncryptionKey:                      oauthEncryptionKey,
+					PreviousEncryptionKey:              oauthEncryptionKeyPrevious,
 					TLSCertFile:                        tlsCertFile,
 					TLSKeyFile:                         tlsKeyFile,
 					Storage:                            storageConfig,
@@ -350,18 +767,100 @@ Downstream OAuth (--downstream-oauth):
 					Enabled: metricsEnabled,
 					Addr:    metricsAddr,
 				},
+				Compression: CompressionServeConfig{
+					Enabled: compressionEnabled,
+					MinSize: compressionMinSize,
+				},
+				ConfigFileEnv: configFileEnv,
+			}
+			loadOAuthEnvVars(&config.OAuth)
+
+			// Load CAPI mode configuration from environment variables
+			if err := loadCAPIModeConfig(&config.CAPIMode, config.getenv); err != nil {
+				return fmt.Errorf("failed to load CAPI mode configuration: %w", err)
 			}
-			return runServe(config)
+			return run(cmd, config)
 		},
 	}
 
 	// Add flags for configuring the server
+	cmd.Flags().StringVar(&configFile, "config", "", "YAML configuration file; flags and environment variables take precedence over its settings")
 	cmd.Flags().BoolVar(&nonDestructiveMode, "non-destructive", true, "Enable non-destructive mode (default: true)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Enable dry run mode (default: false)")
 	cmd.Flags().Float32Var(&qpsLimit, "qps-limit", 20.0, "QPS limit for Kubernetes API calls (default: 20.0)")
 	cmd.Flags().IntVar(&burstLimit, "burst-limit", 30, "Burst limit for Kubernetes API calls (default: 30)")
+	cmd.Flags().Float32Var(&userQPSLimit, "user-qps-limit", 0, "Share of --qps-limit each user may take; their Kubernetes API calls wait for their own budget first (0 disables)")
+	cmd.Flags().IntVar(&userBurstLimit, "user-burst-limit", 10, "Kubernetes API calls a user may make at once before --user-qps-limit applies")
+	cmd.Flags().BoolVar(&protobuf, "protobuf", true, "Read pods, nodes and events from the Kubernetes API as protobuf instead of JSON (default: true)")
 	cmd.Flags().BoolVar(&debugMode, "debug", false, "Enable debug logging (default: false)")
+	cmd.Flags().StringVar(&logFile, "log-file", "", "Write server logs to this file, rotated by size and age, instead of stderr (recommended with the stdio transport)")
+	cmd.Flags().StringVar(&logFormat, "log-format", logFormatAuto, "Log format: "+logFormatAuto+" (JSON in a pod, text otherwise), "+logFormatJSON+" or "+logFormatText)
+	cmd.Flags().Int64Var(&logMaxBytes, "log-max-bytes", defaultLogMaxBytes, "Rotate --log-file before it grows beyond this many bytes (0 disables)")
+	cmd.Flags().DurationVar(&logRotateInterval, "log-rotate-interval", 24*time.Hour, "Rotate --log-file after it has been written to for this long (0 disables)")
+	cmd.Flags().IntVar(&logMaxBackups, "log-max-backups", 5, "Rotated --log-file files kept next to it (0 keeps all)")
+	cmd.Flags().IntVar(&debugCaptureSize, "debug-capture", 0, "Keep the last N tool calls, with secrets masked, for debugging; read them at "+adminCapturesPath+" or log them with SIGUSR1 (0 disables)")
+	cmd.Flags().IntVar(&debugCaptureMaxBytes, "debug-capture-max-bytes", capture.DefaultMaxBytes, "Cap the captured arguments and result of each tool call at this many bytes")
+	cmd.Flags().BoolVar(&allowImpersonateAs, "allow-impersonate-as", false, "Expose the impersonateUser/impersonateGroups tool parameters so callers holding impersonate RBAC can run tools as another user (requires CAPI federation mode)")
+	cmd.Flags().StringToStringVar(&impersonationExtras, "impersonation-extras", nil, "Extras added to the Impersonate-Extra-* headers of every impersonated Kubernetes API request, for the audit log (key=value,..., keys lowercase)")
+	cmd.Flags().BoolVar(&impersonationExtrasClient, "impersonation-extras-client-info", false, "Add the MCP client's name and version as the "+tools.ImpersonationExtraClient+" impersonation extra")
+	cmd.Flags().BoolVar(&impersonationExtrasSessionID, "impersonation-extras-session-id", false, "Add the MCP session ID as the "+tools.ImpersonationExtraSessionID+" impersonation extra")
+	cmd.Flags().StringToStringVar(&impersonationExtrasMeta, "impersonation-extras-meta", nil, "Tool call _meta fields added as impersonation extras (field=key,..., e.g. ticketId=ticket-id)")
+	cmd.Flags().StringSliceVar(&debugImages, "debug-images", nil, "Images debug_pod may start as ephemeral containers; an entry ending in * matches by prefix and the first entry is the default (comma-separated, default: "+server.DefaultDebugImage+")")
+	cmd.Flags().StringSliceVar(&copyPaths, "copy-paths", nil, "Container directories cp_from_pod and cp_to_pod may read and write (comma-separated, default: "+strings.Join(server.DefaultCopyPaths, ",")+")")
+	cmd.Flags().Int64Var(&copyMaxBytes, "copy-max-bytes", server.DefaultCopyMaxBytes, "Largest file cp_from_pod and cp_to_pod may copy, in bytes")
+	cmd.Flags().StringSliceVar(&readOnlyGroups, "read-only-groups", nil, "OAuth groups whose members are only offered and allowed read-only tools (comma-separated)")
+	cmd.Flags().StringVar(&policyFile, "policy-file", "", "Path to a YAML tool authorization policy with per-cluster, per-namespace, per-resource and per-verb allow/deny rules")
+	cmd.Flags().DurationVar(&policyReloadInterval, "policy-reload-interval", 30*time.Second, "How often to check the policy file for changes (0 disables hot reload)")
+	cmd.Flags().StringVar(&opaURL, "opa-url", "", "OPA decision URL consulted for every tool call, e.g. http://localhost:8181/v1/data/mcp/authz/allow")
+	cmd.Flags().DurationVar(&opaTimeout, "opa-timeout", security.DefaultOPATimeout, "Timeout for a single OPA decision request")
+	cmd.Flags().BoolVar(&opaFailOpen, "opa-fail-open", false, "Allow tool calls when OPA is unreachable or returns an invalid response (default: deny)")
+	cmd.Flags().StringVar(&restrictedNamespaceSelector, "restricted-namespace-selector", "", "Label selector for namespaces tool calls may not access, e.g. policy.giantswarm.io/mcp-access=deny")
+	cmd.Flags().DurationVar(&restrictedNamespaceCacheTTL, "restricted-namespace-cache-ttl", security.DefaultNamespaceLabelCacheTTL, "How long namespace labels are cached for --restricted-namespace-selector")
+	cmd.Flags().BoolVar(&restrictedNamespaceFailOpen, "restricted-namespace-fail-open", false, "Allow tool calls when namespace labels cannot be read (default: deny)")
+	cmd.Flags().StringSliceVar(&readRestrictions, "read-restrictions", nil, "Namespaces, or namespace/resource pairs, tool calls may not read, as glob patterns (comma-separated), e.g. kube-system/secrets")
+	cmd.Flags().StringSliceVar(&writeNamespaces, "write-namespaces", nil, "Namespaces mutating tools may write to, as glob patterns (comma-separated), e.g. team-* (default: all)")
+	cmd.Flags().BoolVar(&allowClusterScopedWrites, "allow-cluster-scoped-writes", false, "Let mutating tools write cluster-scoped objects, such as ClusterRoleBindings and CRDs, while --write-namespaces limits writes to some namespaces (default: deny)")
+	cmd.Flags().BoolVar(&scrubPII, "scrub-pii", false, "Mask emails, IP addresses, bearer tokens and AWS keys in annotations, ConfigMap data and container env values before returning them")
+	cmd.Flags().BoolVar(&scrubCredentials, "scrub-credentials", true, "Redact likely credentials (private keys, JWTs, bearer tokens, AWS keys) from pod log and exec output before returning it")
+	cmd.Flags().StringSliceVar(&credentialPatterns, "credential-patterns", nil, "Credential patterns to redact when --scrub-credentials is set (comma-separated: "+strings.Join(output.CredentialPatternSet.Names(), ", ")+"; default: all)")
+	cmd.Flags().IntVar(&tokenWarningThreshold, "token-warning-threshold", output.DefaultTokenWarningThreshold, "Estimated token count of a tool response above which a warning suggesting summary mode or filters is added to it (0 disables the warning)")
+	cmd.Flags().StringVar(&secretRedaction, "secret-redaction", "", "How Secret data is shown: "+output.SecretRedactionFull+" (drop keys and values), "+output.SecretRedactionKeys+" (key names only) or "+output.SecretRedactionHash+" (key names with a short SHA-256 of each value) (default: "+output.SecretRedactionKeys+")")
+	cmd.Flags().StringVar(&secretWrites, "secret-writes", server.SecretWritesAllow, "Whether create, apply and patch may write Secrets: "+server.SecretWritesAllow+", "+server.SecretWritesDeny+", or "+server.SecretWritesOperation+" (only where the "+server.SecretWriteOperation+" operation is allowed by policy)")
+	cmd.Flags().BoolVar(&confirmDestructive, "confirm-destructive", false, "Preview delete, delete_namespace, drain and scale to 0 replicas as a dry-run and only run them when confirmed with the confirm tool")
+	cmd.Flags().DurationVar(&confirmationTTL, "confirmation-ttl", confirmation.DefaultTTL, "How long a destructive call previewed with --confirm-destructive can be confirmed")
+	cmd.Flags().BoolVar(&enablePlans, "enable-plans", false, "Enable the plan tools, which build change-sets of create, patch and scale operations that are reviewed with dry-run diffs and applied together with rollback on failure")
+	cmd.Flags().DurationVar(&planTTL, "plan-ttl", plan.DefaultTTL, "How long a plan is kept after its last change")
+	cmd.Flags().StringVar(&toolProfile, "profile", tools.ProfileAdmin, "Tools to expose: "+tools.ProfileReadOnly+" (read-only tools only), "+tools.ProfileOperator+" (adds workload changes such as apply, patch, scale and delete) or "+tools.ProfileAdmin+" (every tool)")
+	cmd.Flags().StringVar(&adminAddr, "admin-addr", "", "Address of the admin server, whose "+adminToolsPath+" endpoint switches the profile and enables or disables tools at runtime (default: disabled)")
+	cmd.Flags().StringVar(&adminTokenFile, "admin-token-file", "", "File of bearer tokens accepted by the admin server, one per line (required with --admin-addr)")
+	cmd.Flags().StringSliceVar(&piiPatterns, "pii-patterns", nil, "PII patterns to scrub when --scrub-pii is set (comma-separated: "+strings.Join(output.PIIPatternSet.Names(), ", ")+"; default: all)")
+	cmd.Flags().Float64Var(&toolRateLimit, "tool-rate-limit", 0, "Sustained tool calls per second allowed per user (or client IP when anonymous); 0 disables (can also be set via TOOL_RATE_LIMIT env var)")
+	cmd.Flags().IntVar(&toolRateLimitBurst, "tool-rate-limit-burst", 20, "Tool calls a caller may make at once before --tool-rate-limit applies (can also be set via TOOL_RATE_LIMIT_BURST env var)")
+	cmd.Flags().BoolVar(&toolRateLimitPerTool, "tool-rate-limit-per-tool", false, "Give each tool its own rate limit budget per caller (can also be set via TOOL_RATE_LIMIT_PER_TOOL env var)")
+	cmd.Flags().StringVar(&toolRateLimitBackend, "tool-rate-limit-backend", ratelimit.BackendMemory, "Rate limit state backend: memory (per replica) or valkey (shared, uses the --valkey-* settings; can also be set via TOOL_RATE_LIMIT_BACKEND env var)")
+	cmd.Flags().StringVar(&replicaID, "replica-id", "", "ID of this replica, reported in tool results and session ownership (default: the K8S_POD_NAME env var, else the host name)")
+	cmd.Flags().StringVar(&sessionBackend, "session-backend", sessionBackendMemory, "Where port-forward session ownership is recorded: memory (this replica only) or valkey (shared, so other replicas can report which replica holds a session; uses the --valkey-* settings)")
+	cmd.Flags().StringVar(&undoJournalBackend, "undo-journal", "", "Record the prior state of objects changed by tool calls so the undo tool can restore it: memory (this replica only) or valkey (shared, uses the --valkey-* settings) (default: disabled)")
+	cmd.Flags().IntVar(&undoJournalSize, "undo-journal-size", journal.DefaultSize, "Changes the undo journal keeps per session")
+	cmd.Flags().DurationVar(&undoJournalTTL, "undo-journal-ttl", journal.DefaultTTL, "How long the undo journal of a session is kept after its last change")
+	cmd.Flags().StringVar(&preferencesBackend, "preferences", "", "Keep per-user preferences (default output format, cluster, limit and masked fields) applied to tool calls: memory (this replica, lost on restart) or valkey (shared and persistent, uses the --valkey-* settings) (default: disabled)")
+	cmd.Flags().DurationVar(&requestTimeout, "request-timeout", k8s.DefaultTimeout*time.Second, "Timeout for data-plane Kubernetes API calls (get, list, apply, ...)")
+	cmd.Flags().DurationVar(&discoveryTimeout, "discovery-timeout", k8s.DiscoveryTimeoutSeconds*time.Second, "Timeout for Kubernetes API discovery (resource type resolution), independent of --request-timeout")
+	cmd.Flags().DurationVar(&toolTimeout, "tool-timeout", server.DefaultToolTimeout, "Timeout for a whole tool call; the call's Kubernetes requests are cancelled when it passes (0 disables)")
+	cmd.Flags().StringToStringVar(&toolTimeouts, "tool-timeouts", nil, "Per-tool overrides of --tool-timeout (tool=duration,..., e.g. connectivity_test=5m; 0 disables)")
+	cmd.Flags().IntVar(&apiRetries, "api-retries", k8s.DefaultRetries, "Retries of Kubernetes API reads (get, list, describe, discovery) after a 429, 5xx or connection reset (0 disables)")
+	cmd.Flags().DurationVar(&apiRetryBackoff, "api-retry-backoff", k8s.DefaultRetryInitialBackoff, "Wait before the first retry of a Kubernetes API read; doubles with each retry")
+	cmd.Flags().DurationVar(&apiRetryMaxBackoff, "api-retry-max-backoff", k8s.DefaultRetryMaxBackoff, "Longest wait before a retry of a Kubernetes API read, including Retry-After delays")
+	cmd.Flags().StringSliceVar(&execPluginAllowlist, "exec-plugin-allowlist", nil, "Kubeconfig exec credential plugins that may run, by name in PATH or absolute path, e.g. aws-iam-authenticator,gke-gcloud-auth-plugin (default: all)")
+	cmd.Flags().DurationVar(&execPluginTimeout, "exec-plugin-timeout", k8s.DefaultExecPluginTimeout, "Longest run of a kubeconfig exec credential plugin before the tool call fails")
+	cmd.Flags().Int64Var(&execPluginMaxOutput, "exec-plugin-max-output", k8s.DefaultExecPluginMaxOutputBytes, "Largest output, in bytes, accepted from a kubeconfig exec credential plugin")
+	cmd.Flags().StringSliceVar(&listCacheResources, "list-cache-resources", nil, "Resource types whose lists are served from an informer cache of the server's own client, e.g. pods,deployments.apps,nodes (not used for per-user clients; default: none)")
+	cmd.Flags().DurationVar(&listCacheResync, "list-cache-resync", k8s.DefaultListCacheResync, "Resync period of the --list-cache-resources informers")
+	cmd.Flags().DurationVar(&discoveryMinInterval, "discovery-min-interval", k8s.DefaultDiscoveryMinInterval, "Minimum interval between API discovery requests per cluster; results are reused within this window")
 	cmd.Flags().BoolVar(&inCluster, "in-cluster", false, "Use in-cluster authentication (service account token) instead of kubeconfig (default: false)")
+	cmd.Flags().StringSliceVar(&kubeconfigContexts, "kubeconfig-contexts", nil, "Kubeconfig contexts the kubeContext tool parameter may select, for multi-cluster access from one kubeconfig without federation (default: all contexts)")
+	cmd.Flags().StringVar(&fixtureDir, "fixture-dir", "", "Serve tools from the recorded Kubernetes fixtures in this directory instead of a cluster (for demos and tests)")
+	cmd.Flags().BoolVar(&recordFixtures, "record-fixtures", false, "Talk to the cluster as usual and record every Kubernetes call to --fixture-dir")
 
 	// Transport flags
 	cmd.Flags().StringVar(&transport, "transport", transportStdio, "Transport type: stdio, sse, or streamable-http")
@@ -369,11 +868,18 @@ Downstream OAuth (--downstream-oauth):
 	cmd.Flags().StringVar(&sseEndpoint, "sse-endpoint", "/sse", "SSE endpoint path (for sse transport)")
 	cmd.Flags().StringVar(&messageEndpoint, "message-endpoint", "/message", "Message endpoint path (for sse transport)")
 	cmd.Flags().StringVar(&httpEndpoint, "http-endpoint", "/mcp", "HTTP endpoint path (for streamable-http transport)")
+	cmd.Flags().StringVar(&authMode, "auth-mode", middleware.AuthModeNone, "Authentication for sse and streamable-http without OAuth: none, static-token (pre-shared bearer tokens from --auth-token-file) or tokenreview (Kubernetes TokenReview of presented tokens) (can also be set via MCP_AUTH_MODE env var)")
+	cmd.Flags().StringVar(&authTokenFile, "auth-token-file", "", "File with accepted bearer tokens for --auth-mode=static-token, one per line (can also be set via MCP_AUTH_TOKEN_FILE env var)")
+	cmd.Flags().StringSliceVar(&authTokenAudiences, "auth-token-audiences", nil, "Audiences a token must be valid for with --auth-mode=tokenreview (comma-separated; default: the API server's audiences, can also be set via MCP_AUTH_TOKEN_AUDIENCES env var)")
 
 	// Metrics server flags
 	cmd.Flags().BoolVar(&metricsEnabled, "metrics-enabled", true, "Enable dedicated metrics server (default: true)")
 	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":9090", "Metrics server address (default: :9090)")
 
+	// Response compression flags
+	cmd.Flags().BoolVar(&compressionEnabled, "compression", true, "Compress responses of the sse and streamable-http transports with gzip or deflate when the client accepts it (default: true)")
+	cmd.Flags().IntVar(&compressionMinSize, "compression-min-size", middleware.DefaultCompressionMinSize, "Smallest response body, in bytes, that is compressed; event streams are always compressed")
+
 	// OAuth flags
 	cmd.Flags().BoolVar(&enableOAuth, "enable-oauth", false, "Enable OAuth 2.1 authentication (for HTTP transports)")
 	cmd.Flags().StringVar(&oauthBaseURL, "oauth-base-url", "", "OAuth base URL (e.g., https://mcp.example.com)")
@@ -393,6 +899,7 @@ Downstream OAuth (--downstream-oauth):
 	cmd.Flags().BoolVar(&allowPrivateOAuthURLs, "allow-private-oauth-urls", false, "Allow OAuth URLs that resolve to private/internal IP addresses (for internal deployments)")
 	cmd.Flags().IntVar(&maxClientsPerIP, "max-clients-per-ip", 10, "Maximum number of OAuth clients that can be registered per IP address")
 	cmd.Flags().StringVar(&oauthEncryptionKey, "oauth-encryption-key", "", "AES-256 encryption key for token encryption (32 bytes, can also be set via OAUTH_ENCRYPTION_KEY env var)")
+	cmd.Flags().StringVar(&oauthEncryptionKeyPrevious, "oauth-encryption-key-previous", "", "Previous AES-256 token encryption key during a key rotation; stored tokens are re-encrypted with --oauth-encryption-key (Valkey storage only, can also be set via OAUTH_ENCRYPTION_KEY_PREVIOUS env var)")
 	cmd.Flags().BoolVar(&downstreamOAuth, "downstream-oauth", false, "Use OAuth access tokens for downstream Kubernetes API authentication (requires --enable-oauth and --in-cluster)")
 
 	// TLS flags for HTTPS support
@@ -489,8 +996,166 @@ func validateEncryptionKey(key []byte) error {
 	return nil
 }
 
+// validateHTTPAuthConfig checks the non-OAuth HTTP authentication settings
+// against the selected transport and OAuth configuration.
+func validateHTTPAuthConfig(cfg HTTPAuthServeConfig, transport string, oauthEnabled bool) error {
+	switch cfg.Mode {
+	case "", middleware.AuthModeNone:
+		return nil
+	case middleware.AuthModeStaticToken:
+		if cfg.TokenFile == "" {
+			return fmt.Errorf("--auth-mode=%s requires --auth-token-file", middleware.AuthModeStaticToken)
+		}
+	case middleware.AuthModeTokenReview:
+	default:
+		return fmt.Errorf("unsupported auth mode %q (supported: %s, %s, %s)", cfg.Mode,
+			middleware.AuthModeNone, middleware.AuthModeStaticToken, middleware.AuthModeTokenReview)
+	}
+	if oauthEnabled {
+		return fmt.Errorf("--auth-mode=%s cannot be combined with --enable-oauth", cfg.Mode)
+	}
+	if transport == transportStdio {
+		slog.Warn("--auth-mode has no effect with the stdio transport", "auth_mode", cfg.Mode)
+	}
+	return nil
+}
+
+// newHTTPAuthenticator builds the bearer token authenticator for cfg. It
+// returns nil when authentication is disabled. clientset is only called in
+// tokenreview mode.
+func newHTTPAuthenticator(cfg HTTPAuthServeConfig, clientset func() (kubernetes.Interface, error)) (middleware.TokenAuthenticator, error) {
+	switch cfg.Mode {
+	case middleware.AuthModeStaticToken:
+		tokens, err := middleware.LoadStaticTokens(cfg.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		return middleware.NewStaticTokenAuthenticator(tokens)
+	case middleware.AuthModeTokenReview:
+		cs, err := clientset()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create clientset for token review: %w", err)
+		}
+		return middleware.NewTokenReviewAuthenticator(cs, cfg.Audiences, middleware.DefaultTokenReviewCacheTTL), nil
+	default:
+		return nil, nil
+	}
+}
+
+// decodeEncryptionKey decodes a base64 AES-256 key and validates it for
+// security weaknesses.
+func decodeEncryptionKey(value string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("must be base64 encoded (use: openssl rand -base64 32): %w", err)
+	}
+	if err := validateEncryptionKey(decoded); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return decoded, nil
+}
+
+// startEncryptionKeyRotation re-encrypts tokens stored with the previous
+// OAuth encryption key. With Valkey storage it runs one pass before the
+// server starts and then keeps converting rows written by replicas still
+// running with the previous key, and returns the previous key for the token
+// store to read the rows not converted yet. In-memory storage starts empty,
+// so there is nothing to rotate.
+func startEncryptionKeyRotation(ctx context.Context, cfg OAuthServeConfig, currentKey []byte) ([]byte, error) {
+	if currentKey == nil {
+		return nil, fmt.Errorf("--oauth-encryption-key-previous requires --oauth-encryption-key")
+	}
+	previousKey, err := decodeEncryptionKey(cfg.PreviousEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("previous OAuth encryption key: %w", err)
+	}
+	if cfg.Storage.Type != server.OAuthStorageTypeValkey {
+		slog.Warn("--oauth-encryption-key-previous has no effect with in-memory OAuth storage")
+		return nil, nil
+	}
+
+	valkeyCfg := cfg.Storage.Valkey
+	client, err := server.NewValkeyClient(valkeyCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Valkey for encryption key rotation: %w", err)
+	}
+	store := keyrotation.NewValkeyStore(client)
+	rotator, err := keyrotation.NewRotator(store, valkeyCfg.KeyPrefix, previousKey, currentKey, slog.Default())
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("failed to set up encryption key rotation: %w", err)
+	}
+
+	result, err := rotator.Run(ctx, false)
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("encryption key rotation failed: %w", err)
+	}
+	slog.Info("re-encrypted stored OAuth tokens with the current encryption key",
+		"scanned", result.Scanned,
+		"rotated", result.Rotated,
+		"conflicts", result.Conflicts,
+		"failed", result.Failed)
+
+	go func() {
+		defer func() { _ = store.Close() }()
+		rotator.Watch(ctx, keyrotation.DefaultWatchInterval)
+	}()
+	return previousKey, nil
+}
+
+// validatePIIPatterns rejects PII pattern names the output scrubber does not know.
+func validatePIIPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if !output.PIIPatternSet.IsValid(p) {
+			return fmt.Errorf("unknown PII pattern %q (valid: %s)", p, strings.Join(output.PIIPatternSet.Names(), ", "))
+		}
+	}
+	return nil
+}
+
+// validateCredentialPatterns rejects unknown --credential-patterns entries.
+func validateCredentialPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if !output.CredentialPatternSet.IsValid(p) {
+			return fmt.Errorf("unknown credential pattern %q (valid: %s)", p, strings.Join(output.CredentialPatternSet.Names(), ", "))
+		}
+	}
+	return nil
+}
+
+// loggingOptions returns the logger options of cfg, and a function that
+// closes the log file. With OTLP logs configured, records are exported
+// instead of written to the file.
+func loggingOptions(cfg LoggingServeConfig) ([]tklogging.Option, func() error, error) {
+	var opts []tklogging.Option
+	switch cfg.Format {
+	case logFormatJSON:
+		opts = append(opts, tklogging.WithFormat(tklogging.FormatJSON))
+	case logFormatText:
+		opts = append(opts, tklogging.WithFormat(tklogging.FormatText))
+	}
+	if cfg.File == "" {
+		return opts, func() error { return nil }, nil
+	}
+	file, err := logging.NewRotatingFile(logging.RotatingFileConfig{
+		Path:       cfg.File,
+		MaxBytes:   cfg.MaxBytes,
+		Interval:   cfg.RotateInterval,
+		MaxBackups: cfg.MaxBackups,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(opts, tklogging.WithOutput(file)), file.Close, nil
+}
+
 // runServe contains the main server logic with support for multiple transports
 func runServe(config ServeConfig) error {
+	if err := validateServeConfig(config); err != nil {
+		return err
+	}
+
 	// logging.Init selects JSON (KUBERNETES_SERVICE_HOST set) or text automatically;
 	// switches to OTel log bridge when OTEL_EXPORTER_OTLP_LOGS_ENDPOINT is set.
 	// internal/logging redaction helpers are explicit call-site wrappers, not
@@ -499,7 +1164,12 @@ func runServe(config ServeConfig) error {
 	if config.DebugMode {
 		logLevel = slog.LevelDebug
 	}
-	logger, logShutdown, err := tklogging.Init(context.Background(), tklogging.WithLevel(logLevel))
+	logOptions, closeLogFile, err := loggingOptions(config.Logging)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = closeLogFile() }()
+	logger, logShutdown, err := tklogging.Init(context.Background(), append(logOptions, tklogging.WithLevel(logLevel))...)
 	if err != nil {
 		return fmt.Errorf("initialising logger: %w", err)
 	}
@@ -520,25 +1190,96 @@ func runServe(config ServeConfig) error {
 		DryRun:             config.DryRun,
 		QPSLimit:           config.QPSLimit,
 		BurstLimit:         config.BurstLimit,
-		Timeout:            30 * time.Second,
-		DebugMode:          config.DebugMode,
-		InCluster:          config.InCluster,
-		Logger:             k8sLogger,
+		Timeout:            config.Timeouts.Request,
+		Retry: k8s.RetryConfig{
+			MaxRetries:     config.Retry.MaxRetries,
+			InitialBackoff: config.Retry.InitialBackoff,
+			MaxBackoff:     config.Retry.MaxBackoff,
+		},
+		ExecPlugins: k8s.ExecPluginConfig{
+			Allowlist:      config.ExecPlugins.Allowlist,
+			Timeout:        config.ExecPlugins.Timeout,
+			MaxOutputBytes: config.ExecPlugins.MaxOutputBytes,
+		},
+		AllowedContexts: config.KubeconfigContexts,
+		Protobuf:        config.Protobuf,
+		DebugMode:       config.DebugMode,
+		InCluster:       config.InCluster,
+		Logger:          k8sLogger,
+	}
+	if len(config.KubeconfigContexts) > 0 {
+		slog.Info("kubeconfig contexts restricted", "contexts", config.KubeconfigContexts)
+	}
+
+	// A user's Kubernetes API calls wait for their own budget before the
+	// shared --qps-limit budget, across all clients and clusters.
+	if config.UserQPSLimit > 0 {
+		k8sConfig.UserRateLimiter = k8s.NewUserRateLimiter(config.UserQPSLimit, config.UserBurstLimit)
+		slog.Info("per-user Kubernetes API rate limiting enabled",
+			"qps", config.UserQPSLimit,
+			"burst", config.UserBurstLimit)
+	}
+
+	// Configured and per-request extras are added to the impersonation
+	// headers of every impersonating client, for the clusters' audit logs.
+	serverImpersonationExtras, err := impersonationExtrasConfig(config.ImpersonationExtras)
+	if err != nil {
+		return err
+	}
+	if len(config.ImpersonationExtras.Static) > 0 || serverImpersonationExtras.Enabled() {
+		k8sConfig.ImpersonationExtras, err = k8s.NewImpersonationExtras(config.ImpersonationExtras.Static)
+		if err != nil {
+			return fmt.Errorf("invalid --impersonation-extras: %w", err)
+		}
+		slog.Info("impersonation extras enabled",
+			"static", len(config.ImpersonationExtras.Static),
+			"client_info", config.ImpersonationExtras.ClientInfo,
+			"session_id", config.ImpersonationExtras.SessionID,
+			"request_meta", len(config.ImpersonationExtras.RequestMeta))
+	}
+
+	// Lists of hot resource types are served from informers. Only the
+	// client created here uses the cache; per-user clients always ask the
+	// API server so that their RBAC applies.
+	if len(config.ListCache.Resources) > 0 {
+		k8sConfig.ListCache = k8s.NewListCache(k8s.ListCacheConfig{
+			Resources: config.ListCache.Resources,
+			Resync:    config.ListCache.Resync,
+		})
+		defer k8sConfig.ListCache.Close()
+		slog.Info("list cache enabled",
+			"resources", config.ListCache.Resources,
+			"resync", config.ListCache.Resync)
 	}
 
+	// Discovery has its own budget: it is bounded separately from data-plane
+	// calls and capped in frequency per cluster.
+	k8s.SetDiscoveryBudget(k8s.DiscoveryBudget{
+		Timeout:     config.Timeouts.Discovery,
+		MinInterval: config.Timeouts.DiscoveryMinInterval,
+	})
+
 	// Create Kubernetes client
 	k8sClient, err := k8s.NewClient(k8sConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create Kubernetes client: %w", err)
 	}
 
-	// Validate downstream OAuth configuration
-	if config.DownstreamOAuth {
-		if !config.OAuth.Enabled {
-			return fmt.Errorf("--downstream-oauth requires --enable-oauth to be set")
+	var serverK8sClient k8s.Client = k8sClient
+	if config.Fixtures.Dir != "" || config.Fixtures.Record {
+		serverK8sClient, err = newFixtureClient(config, k8sClient)
+		if err != nil {
+			return err
 		}
-		if !config.InCluster {
-			return fmt.Errorf("--downstream-oauth requires --in-cluster mode (must be running inside a Kubernetes cluster)")
+	}
+	var httpAuth middleware.TokenAuthenticator
+	if config.Transport != transportStdio {
+		httpAuth, err = newHTTPAuthenticator(config.HTTPAuth, k8sClient.Clientset)
+		if err != nil {
+			return fmt.Errorf("failed to configure HTTP authentication: %w", err)
+		}
+		if httpAuth != nil {
+			slog.Info("HTTP bearer token authentication enabled", "auth_mode", config.HTTPAuth.Mode)
 		}
 	}
 
@@ -566,14 +1307,168 @@ func runServe(config ServeConfig) error {
 		slog.Info("opentelemetry instrumentation enabled",
 			"metrics_exporter", instrumentationConfig.MetricsExporter,
 			"tracing_exporter", instrumentationConfig.TracingExporter)
+
+		// The client reads its retry settings from k8sConfig on each call,
+		// so retries are counted although it was created before the
+		// provider. The client factories below copy them when created.
+		k8sConfig.Retry.Metrics = instrumentationProvider.Metrics()
 	}
 
 	// Create server context with kubernetes client and shutdown context
 	var serverContextOptions []server.Option
-	serverContextOptions = append(serverContextOptions, server.WithK8sClient(k8sClient))
+	serverContextOptions = append(serverContextOptions, server.WithK8sClient(serverK8sClient))
 	serverContextOptions = append(serverContextOptions, server.WithInstrumentationProvider(instrumentationProvider))
 	serverContextOptions = append(serverContextOptions, server.WithNonDestructiveMode(config.NonDestructiveMode))
 	serverContextOptions = append(serverContextOptions, server.WithDryRun(config.DryRun))
+	serverContextOptions = append(serverContextOptions, server.WithAllowImpersonateAs(config.AllowImpersonateAs))
+	if len(config.KubeconfigContexts) > 0 {
+		serverContextOptions = append(serverContextOptions, server.WithKubeconfigContexts(config.KubeconfigContexts))
+	}
+	if serverImpersonationExtras.Enabled() {
+		serverContextOptions = append(serverContextOptions, server.WithImpersonationExtras(serverImpersonationExtras))
+	}
+	if config.AllowImpersonateAs && !config.CAPIMode.Enabled {
+		slog.Warn("--allow-impersonate-as has no effect without CAPI federation mode")
+	}
+
+	clusterOverrides, clusterTypeOverrides, err := outputClusterOverrides(config)
+	if err != nil {
+		return err
+	}
+	kindProfiles, err := outputKindProfiles(config)
+	if err != nil {
+		return err
+	}
+	if config.Output.ScrubPII || config.Output.SecretRedaction != "" || !config.Output.ScrubCredentials || len(config.Output.CredentialPatterns) > 0 ||
+		config.Output.TokenWarningThreshold != output.DefaultTokenWarningThreshold ||
+		len(clusterOverrides) > 0 || len(clusterTypeOverrides) > 0 || len(kindProfiles) > 0 {
+		outputConfig := server.NewDefaultOutputConfig()
+		outputConfig.ScrubPII = config.Output.ScrubPII
+		outputConfig.PIIPatterns = config.Output.PIIPatterns
+		outputConfig.SecretRedaction = config.Output.SecretRedaction
+		outputConfig.ScrubCredentials = config.Output.ScrubCredentials
+		outputConfig.CredentialPatterns = config.Output.CredentialPatterns
+		outputConfig.TokenWarningThreshold = config.Output.TokenWarningThreshold
+		outputConfig.KindProfiles = kindProfiles
+		outputConfig.ClusterOverrides = clusterOverrides
+		outputConfig.ClusterTypeOverrides = clusterTypeOverrides
+		serverContextOptions = append(serverContextOptions, server.WithOutputConfig(outputConfig))
+	}
+	if config.SecretWrites != "" {
+		serverContextOptions = append(serverContextOptions, server.WithSecretWrites(config.SecretWrites))
+	}
+	if config.ConfirmDestructive {
+		serverContextOptions = append(serverContextOptions, server.WithConfirmations(confirmation.NewStore(config.ConfirmationTTL)))
+	}
+	if config.EnablePlans {
+		serverContextOptions = append(serverContextOptions, server.WithPlans(plan.NewStore(config.PlanTTL)))
+	}
+
+	if len(config.DebugImages) > 0 {
+		serverContextOptions = append(serverContextOptions, server.WithDebugImages(config.DebugImages))
+	}
+	if len(config.CopyPaths) > 0 || config.CopyMaxBytes != server.DefaultCopyMaxBytes {
+		serverContextOptions = append(serverContextOptions, server.WithCopyLimits(config.CopyPaths, config.CopyMaxBytes))
+	}
+	if config.Timeouts.Tool != server.DefaultToolTimeout || len(config.Timeouts.Tools) > 0 {
+		serverContextOptions = append(serverContextOptions, server.WithToolTimeouts(config.Timeouts.Tool, config.Timeouts.Tools))
+	}
+
+	if len(config.Policy.ReadOnlyGroups) > 0 {
+		serverContextOptions = append(serverContextOptions, server.WithReadOnlyGroups(config.Policy.ReadOnlyGroups))
+	}
+
+	// Load the tool authorization policy. A policy file supersedes the flat
+	// allowed-operations / restricted-namespaces settings.
+	if config.Policy.File != "" {
+		policyEngine, err := security.NewEngineFromFile(config.Policy.File)
+		if err != nil {
+			return fmt.Errorf("failed to load policy file: %w", err)
+		}
+		serverContextOptions = append(serverContextOptions, server.WithPolicyEngine(policyEngine))
+		go policyEngine.Watch(shutdownCtx, config.Policy.ReloadInterval, slog.Default())
+		slog.Info("tool authorization policy loaded",
+			"path", config.Policy.File,
+			"rules", len(policyEngine.Policy().Rules),
+			"reload_interval", config.Policy.ReloadInterval)
+	}
+
+	if config.Policy.OPAURL != "" {
+		serverContextOptions = append(serverContextOptions, server.WithAuthorizer(
+			security.NewOPAAuthorizer(config.Policy.OPAURL, config.Policy.OPATimeout, config.Policy.OPAFailOpen)))
+		slog.Info("external OPA authorization enabled",
+			"url", config.Policy.OPAURL,
+			"fail_open", config.Policy.OPAFailOpen)
+	}
+
+	if config.Policy.RestrictedNamespaceSelector != "" {
+		guard, err := security.NewNamespaceLabelGuard(
+			config.Policy.RestrictedNamespaceSelector,
+			config.Policy.RestrictedNamespaceCacheTTL,
+			config.Policy.RestrictedNamespaceFailOpen)
+		if err != nil {
+			return fmt.Errorf("failed to configure namespace restrictions: %w", err)
+		}
+		serverContextOptions = append(serverContextOptions, server.WithNamespaceLabelGuard(guard))
+		slog.Info("label-based namespace restrictions enabled",
+			"selector", guard.Selector(),
+			"cache_ttl", config.Policy.RestrictedNamespaceCacheTTL,
+			"fail_open", config.Policy.RestrictedNamespaceFailOpen)
+	}
+
+	if len(config.Policy.ReadRestrictions) > 0 || len(config.Policy.WriteNamespaces) > 0 {
+		serverContextOptions = append(serverContextOptions, server.WithNamespaceAccess(config.Policy.ReadRestrictions, config.Policy.WriteNamespaces, config.Policy.AllowClusterScopedWrites))
+		slog.Info("namespace access restrictions enabled",
+			"read_restrictions", config.Policy.ReadRestrictions,
+			"write_namespaces", config.Policy.WriteNamespaces,
+			"allow_cluster_scoped_writes", config.Policy.AllowClusterScopedWrites)
+	}
+
+	if config.RateLimit.Rate > 0 {
+		valkeyCfg := config.OAuth.Storage.Valkey
+		var client valkey.Client
+		if config.RateLimit.Backend == ratelimit.BackendValkey {
+			if client, err = server.NewValkeyClient(valkeyCfg); err != nil {
+				return fmt.Errorf("failed to create tool rate limiter: %w", err)
+			}
+		}
+		backend, err := ratelimit.NewBackend(config.RateLimit.Backend, client, valkeyCfg.KeyPrefix, config.RateLimit.Rate, config.RateLimit.Burst)
+		if err != nil {
+			if client != nil {
+				client.Close()
+			}
+			return fmt.Errorf("failed to create tool rate limiter: %w", err)
+		}
+		serverContextOptions = append(serverContextOptions, server.WithToolRateLimiter(
+			ratelimit.NewLimiter(backend, config.RateLimit.PerTool, slog.Default())))
+		slog.Info("tool rate limiting enabled",
+			"rate", config.RateLimit.Rate,
+			"burst", config.RateLimit.Burst,
+			"per_tool", config.RateLimit.PerTool,
+			"backend", config.RateLimit.Backend)
+	}
+
+	sessionOptions, err := sessionServerOptions(config.Sessions, config.OAuth.Storage.Valkey)
+	if err != nil {
+		return err
+	}
+	serverContextOptions = append(serverContextOptions, sessionOptions...)
+
+	undoJournal, err := newUndoJournal(config.UndoJournal, config.OAuth.Storage.Valkey)
+	if err != nil {
+		return err
+	}
+	if undoJournal != nil {
+		serverContextOptions = append(serverContextOptions, server.WithUndoJournal(undoJournal))
+	}
+
+	preferencesStore, err := newPreferencesStore(config.Preferences, config.OAuth.Storage.Valkey)
+	if err != nil {
+		return err
+	}
+	if preferencesStore != nil {
+		serverContextOptions = append(serverContextOptions, server.WithPreferences(preferencesStore))
+	}
 
 	// Set in-cluster mode flag
 	if config.InCluster {
@@ -599,7 +1494,7 @@ func runServe(config ServeConfig) error {
 	}
 
 	if len(config.OAuth.TrustedIssuers) == 0 {
-		if envVal := os.Getenv("OAUTH_TRUSTED_ISSUERS"); envVal != "" {
+		if envVal := config.getenv("OAUTH_TRUSTED_ISSUERS"); envVal != "" {
 			if err := json.Unmarshal([]byte(envVal), &config.OAuth.TrustedIssuers); err != nil {
 				return fmt.Errorf("OAUTH_TRUSTED_ISSUERS: invalid JSON: %w", err)
 			}
@@ -626,23 +1521,10 @@ func runServe(config ServeConfig) error {
 			"issuer_count", len(config.OAuth.TrustedIssuers))
 	}
 
-	// Load CAPI mode configuration from environment variables
-	if err := loadCAPIModeConfig(&config.CAPIMode); err != nil {
-		return fmt.Errorf("failed to load CAPI mode configuration: %w", err)
-	}
-
 	// Create federation manager if CAPI mode is enabled
 	var fedManager federation.ClusterClientManager
 	var hybridProvider *federation.HybridOAuthClientProvider // Track for cleanup
 	if config.CAPIMode.Enabled {
-		// CAPI mode requires downstream OAuth and in-cluster mode
-		if !config.DownstreamOAuth {
-			return fmt.Errorf("CAPI mode requires downstream OAuth to be enabled (--downstream-oauth)")
-		}
-		if !config.InCluster {
-			return fmt.Errorf("CAPI mode requires in-cluster mode (--in-cluster)")
-		}
-
 		// Create OAuth client provider (used for user-scoped operations in all modes)
 		oauthProvider, err := federation.NewOAuthClientProviderFromInCluster()
 		if err != nil {
@@ -739,6 +1621,22 @@ func runServe(config ServeConfig) error {
 				ssoConfig.CAConfigMapSuffix = config.CAPIMode.WorkloadClusterAuth.CAConfigMapSuffix
 			}
 
+			// Token exchange for clusters expecting a cluster-specific audience
+			if tx := config.CAPIMode.WorkloadClusterAuth.TokenExchange; tx.URL != "" {
+				ssoConfig.TokenExchange = &federation.TokenExchangeConfig{
+					TokenURL:     tx.URL,
+					ClientID:     tx.ClientID,
+					ClientSecret: tx.ClientSecret,
+					ConnectorID:  tx.ConnectorID,
+				}
+				if err := ssoConfig.TokenExchange.Validate(); err != nil {
+					return fmt.Errorf("invalid workload cluster token exchange configuration: %w", err)
+				}
+				slog.Info("Workload cluster token exchange enabled", //nolint:gosec // G706: URL from operator, not end-user input
+					"token_url", tx.URL,
+					"annotation", federation.AnnotationTokenExchangeAudience)
+			}
+
 			managerOpts = append(managerOpts, federation.WithSSOPassthroughConfig(ssoConfig))
 
 			// Log configuration including security-relevant options
@@ -842,6 +1740,14 @@ func runServe(config ServeConfig) error {
 			managerOpts = append(managerOpts, federation.WithConnectivityConfig(connectivityConfig))
 		}
 
+		if k8sConfig.UserRateLimiter != nil {
+			managerOpts = append(managerOpts, federation.WithUserRateLimiter(k8sConfig.UserRateLimiter))
+		}
+		if k8sConfig.ImpersonationExtras != nil {
+			managerOpts = append(managerOpts, federation.WithImpersonationExtras(k8sConfig.ImpersonationExtras))
+		}
+		managerOpts = append(managerOpts, federation.WithExecPlugins(k8sConfig.ExecPlugins))
+
 		// Add instrumentation metrics if enabled
 		if instrumentationProvider.Enabled() {
 			managerOpts = append(managerOpts, federation.WithManagerCacheMetrics(instrumentationProvider.Metrics()))
@@ -860,16 +1766,44 @@ func runServe(config ServeConfig) error {
 			}
 		}
 
-		// Configure group mapping for impersonation mode
-		if len(config.CAPIMode.WorkloadClusterAuth.GroupMappings) > 0 {
-			groupMapper, err := federation.NewGroupMapper(
-				config.CAPIMode.WorkloadClusterAuth.GroupMappings, slog.Default())
+		// Configure group filtering and mapping for impersonation mode
+		wcAuth := config.CAPIMode.WorkloadClusterAuth
+		if len(wcAuth.GroupMappings) > 0 || len(wcAuth.GroupStripPrefixes) > 0 || len(wcAuth.GroupAllowPatterns) > 0 {
+			groupMapper, err := federation.NewGroupMapper(wcAuth.GroupMappings, slog.Default(),
+				federation.WithGroupStripPrefixes(wcAuth.GroupStripPrefixes...),
+				federation.WithGroupAllowPatterns(wcAuth.GroupAllowPatterns...))
 			if err != nil {
 				return fmt.Errorf("failed to create group mapper: %w", err)
 			}
 			managerOpts = append(managerOpts, federation.WithGroupMapper(groupMapper))
 			slog.Info("Group mapping enabled for workload cluster impersonation",
-				"mapping_count", groupMapper.MappingCount())
+				"mapping_count", groupMapper.MappingCount(),
+				"strip_prefix_count", len(wcAuth.GroupStripPrefixes),
+				"allow_pattern_count", len(wcAuth.GroupAllowPatterns))
+		}
+
+		// Configure cluster sources beyond CAPI
+		sourceOpts, err := clusterSourceOptions(config.CAPIMode.ClusterSources, k8sConfig.ExecPlugins)
+		if err != nil {
+			return err
+		}
+		managerOpts = append(managerOpts, sourceOpts...)
+
+		// Limit how many workload clusters are contacted at the same time
+		managerOpts = append(managerOpts, federation.WithConcurrencyLimit(concurrencyConfig(config.CAPIMode.Concurrency)))
+		if instrumentationProvider.Enabled() {
+			managerOpts = append(managerOpts, federation.WithConcurrencyMetrics(instrumentationProvider.Metrics()))
+		}
+
+		// Configure the CAPI cluster discovery cache
+		if config.CAPIMode.DiscoveryCache.Enabled {
+			managerOpts = append(managerOpts, federation.WithDiscoveryCache(federation.DiscoveryCacheConfig{
+				ResyncPeriod: config.CAPIMode.DiscoveryCache.ResyncPeriod,
+				AccessTTL:    config.CAPIMode.DiscoveryCache.AccessTTL,
+			}))
+			if instrumentationProvider.Enabled() {
+				managerOpts = append(managerOpts, federation.WithDiscoveryCacheMetrics(instrumentationProvider.Metrics()))
+			}
 		}
 
 		// Create federation manager
@@ -883,6 +1817,12 @@ func runServe(config ServeConfig) error {
 		slog.Info("CAPI federation mode enabled: multi-cluster operations available")
 	}
 
+	debugCapture := newDebugCapture(config.DebugCapture)
+	if debugCapture != nil {
+		serverContextOptions = append(serverContextOptions, server.WithDebugCapture(debugCapture))
+		dumpDebugCaptureOnSignal(shutdownCtx, debugCapture)
+	}
+
 	serverContext, err := server.NewServerContext(shutdownCtx, serverContextOptions...)
 	if err != nil {
 		return fmt.Errorf("failed to create server context: %w", err)
@@ -917,6 +1857,11 @@ func runServe(config ServeConfig) error {
 			"protocol_version", msg.Params.ProtocolVersion,
 		)
 	})
+	// Session defaults and list versions end with their session.
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session mcpserver.ClientSession) {
+		serverContext.SessionDefaults().Delete(session.SessionID())
+		serverContext.ListVersions().Delete(session.SessionID())
+	})
 
 	mcpSrv := mcpserver.NewMCPServer(serviceName, rootCmd.Version,
 		mcpserver.WithToolCapabilities(true),
@@ -924,30 +1869,34 @@ func runServe(config ServeConfig) error {
 		mcpserver.WithInputSchemaValidation(),
 		mcpserver.WithStrictInputSchemaDefault(),
 		mcpserver.WithToolFilter(tools.HideDeprecatedAliasesFilter),
-		mcpserver.WithToolHandlerMiddleware(timeout.New(30*time.Second)),
+		mcpserver.WithToolFilter(tools.NewAccessAwareToolFilter(serverContext).ToolFilterFunc()),
 		mcpserver.WithToolHandlerMiddleware(responsecap.New(responsecap.Options{})),
 	)
 
-	// Register all tool categories
-	if err := resource.RegisterResourceTools(mcpSrv, serverContext); err != nil {
-		return fmt.Errorf("failed to register resource tools: %w", err)
+	if err := registerTools(mcpSrv, serverContext); err != nil {
+		return err
 	}
 
-	if err := pod.RegisterPodTools(mcpSrv, serverContext); err != nil {
-		return fmt.Errorf("failed to register pod tools: %w", err)
+	// Expose the tools of the profile, once every tool is registered
+	toolExposure, err := tools.NewToolExposure(mcpSrv, config.ToolProfile)
+	if err != nil {
+		return err
 	}
-
-	if err := contexttools.RegisterContextTools(mcpSrv, serverContext); err != nil {
-		return fmt.Errorf("failed to register context tools: %w", err)
+	if hidden := toolExposure.Status().Hidden; len(hidden) > 0 {
+		slog.Info("tool profile applied", "profile", config.ToolProfile, "hidden_tools", len(hidden))
 	}
-
-	if err := cluster.RegisterClusterTools(mcpSrv, serverContext); err != nil {
-		return fmt.Errorf("failed to register cluster tools: %w", err)
+	if config.Admin.Addr != "" {
+		if err := startAdminServer(shutdownCtx, config.Admin, toolExposure, debugCapture); err != nil {
+			return err
+		}
 	}
 
-	// Register CAPI discovery tools (only registers when federation is enabled)
-	if err := capi.RegisterCAPITools(mcpSrv, serverContext); err != nil {
-		return fmt.Errorf("failed to register CAPI tools: %w", err)
+	// Health endpoints are served by every HTTP transport
+	var healthChecker *server.HealthChecker
+	if config.Transport != transportStdio {
+		var closeHealthChecks func()
+		healthChecker, closeHealthChecks = newHealthChecker(config, serverContext, k8sClient.Clientset)
+		defer closeHealthChecks()
 	}
 
 	// Start the appropriate server based on transport type
@@ -955,85 +1904,17 @@ func runServe(config ServeConfig) error {
 	case transportStdio:
 		// Don't print startup message for stdio mode as it interferes with MCP communication
 		return runStdioServer(mcpSrv)
-	case transportSSE:
-		slog.Info("starting MCP Kubernetes server", "transport", config.Transport)
-		return runSSEServer(mcpSrv, config.HTTPAddr, config.SSEEndpoint, config.MessageEndpoint, shutdownCtx, config.DebugMode, instrumentationProvider, config.Metrics)
-	case transportStreamableHTTP:
+	case transportSSE, transportStreamableHTTP:
+		// Both HTTP transports share the same middleware and, when enabled,
+		// the same OAuth protection.
 		slog.Info("starting MCP Kubernetes server", "transport", config.Transport)
 		if config.OAuth.Enabled {
-			// Get OAuth credentials from env vars if not provided via flags
-			loadEnvIfEmpty(&config.OAuth.GoogleClientID, "GOOGLE_CLIENT_ID")
-			loadEnvIfEmpty(&config.OAuth.GoogleClientSecret, "GOOGLE_CLIENT_SECRET")
-			loadEnvIfEmpty(&config.OAuth.DexIssuerURL, "DEX_ISSUER_URL")
-			loadEnvIfEmpty(&config.OAuth.DexClientID, "DEX_CLIENT_ID")
-			loadEnvIfEmpty(&config.OAuth.DexClientSecret, "DEX_CLIENT_SECRET")
-			loadEnvIfEmpty(&config.OAuth.DexConnectorID, "DEX_CONNECTOR_ID")
-			loadEnvIfEmpty(&config.OAuth.DexCAFile, "DEX_CA_FILE")
-			loadEnvIfEmpty(&config.OAuth.DexKubernetesAuthenticatorClientID, "DEX_K8S_AUTHENTICATOR_CLIENT_ID")
-			loadEnvIfEmpty(&config.OAuth.EncryptionKey, "OAUTH_ENCRYPTION_KEY")
-			// Note: Valkey storage and CIMD env vars are loaded in RunE closure where cmd is available
-
-			// Load trusted audiences from environment variable if not set via flag
-			if len(config.OAuth.TrustedAudiences) == 0 {
-				if envVal := os.Getenv("OAUTH_TRUSTED_AUDIENCES"); envVal != "" {
-					config.OAuth.TrustedAudiences = splitAndTrimAudiences(envVal)
-				}
-			}
-
-			// Validate OAuth configuration
-			if config.OAuth.BaseURL == "" {
-				return fmt.Errorf("--oauth-base-url is required when --enable-oauth is set")
-			}
-			// Validate OAuth base URL (allows localhost for development, but requires HTTPS for production)
-			if err := validateOAuthBaseURL(config.OAuth.BaseURL); err != nil {
-				return err
-			}
-
-			// Validate TLS configuration - both cert and key must be provided together
-			if (config.OAuth.TLSCertFile != "" && config.OAuth.TLSKeyFile == "") ||
-				(config.OAuth.TLSCertFile == "" && config.OAuth.TLSKeyFile != "") {
-				return fmt.Errorf("both --tls-cert-file and --tls-key-file must be provided together for HTTPS")
-			}
-
-			// Provider-specific validation
-			switch config.OAuth.Provider {
-			case OAuthProviderDex:
-				if config.OAuth.DexIssuerURL == "" {
-					return fmt.Errorf("dex issuer URL is required when using Dex provider (--dex-issuer-url or DEX_ISSUER_URL)")
-				}
-				// Validate Dex issuer URL is HTTPS and not vulnerable to SSRF
-				if err := validateSecureURL(config.OAuth.DexIssuerURL, "Dex issuer URL", config.OAuth.AllowPrivateURLs); err != nil {
-					return err
-				}
-				if config.OAuth.DexClientID == "" {
-					return fmt.Errorf("dex client ID is required when using Dex provider (--dex-client-id or DEX_CLIENT_ID)")
-				}
-				if config.OAuth.DexClientSecret == "" {
-					return fmt.Errorf("dex client secret is required when using Dex provider (--dex-client-secret or DEX_CLIENT_SECRET)")
-				}
-				// Validate Kubernetes authenticator client ID format (if provided)
-				if err := validateOAuthClientID(config.OAuth.DexKubernetesAuthenticatorClientID, "Dex Kubernetes authenticator client ID"); err != nil {
-					return err
-				}
-				// Warn if Kubernetes authenticator client ID is set but downstream OAuth is not enabled
-				if config.OAuth.DexKubernetesAuthenticatorClientID != "" && !config.DownstreamOAuth {
-					slog.Warn("Dex Kubernetes authenticator client ID is configured but downstream OAuth is disabled; cross-client audience tokens will be requested but not used for Kubernetes API authentication",
-						"hint", "enable --downstream-oauth to use OAuth tokens for Kubernetes API authentication")
-				}
-			case OAuthProviderGoogle:
-				if config.OAuth.GoogleClientID == "" {
-					return fmt.Errorf("google client ID is required when using Google provider (--google-client-id or GOOGLE_CLIENT_ID)")
-				}
-				if config.OAuth.GoogleClientSecret == "" {
-					return fmt.Errorf("google client secret is required when using Google provider (--google-client-secret or GOOGLE_CLIENT_SECRET)")
-				}
-			default:
-				return fmt.Errorf("unsupported OAuth provider: %s (supported: %s, %s)", config.OAuth.Provider, OAuthProviderDex, OAuthProviderGoogle)
-			}
-
-			// Validate trusted schemes if configured (RFC 3986 compliance)
-			if err := validateTrustedSchemes(config.OAuth.TrustedPublicRegistrationSchemes); err != nil {
-				return fmt.Errorf("invalid trusted public registration scheme: %w", err)
+			// The OAuth configuration was checked by validateServeConfig.
+			// Warn if the Kubernetes authenticator client ID is set but
+			// downstream OAuth is not enabled.
+			if config.OAuth.Provider == OAuthProviderDex && config.OAuth.DexKubernetesAuthenticatorClientID != "" && !config.DownstreamOAuth {
+				slog.Warn("Dex Kubernetes authenticator client ID is configured but downstream OAuth is disabled; cross-client audience tokens will be requested but not used for Kubernetes API authentication",
+					"hint", "enable --downstream-oauth to use OAuth tokens for Kubernetes API authentication")
 			}
 
 			// Log security warning when SSO token forwarding is enabled
@@ -1051,39 +1932,30 @@ func runServe(config ServeConfig) error {
 				}
 			}
 
-			// Registration token is required unless:
-			// 1. Public registration is enabled (anyone can register), OR
-			// 2. Trusted schemes are configured (Cursor/VSCode can register without token), OR
-			// 3. CIMD is enabled (clients use HTTPS URLs as client IDs)
-			hasTrustedSchemes := len(config.OAuth.TrustedPublicRegistrationSchemes) > 0
-			cimdEnabled := config.OAuth.EnableCIMD
-			if !config.OAuth.AllowPublicRegistration && config.OAuth.RegistrationToken == "" && !hasTrustedSchemes && !cimdEnabled {
-				return fmt.Errorf("--registration-token is required when public registration is disabled, " +
-					"no trusted schemes are configured, and CIMD is disabled. " +
-					"Either set --registration-token, enable --allow-public-registration, " +
-					"configure --trusted-public-registration-schemes, or enable --enable-cimd")
-			}
-
 			// Prepare encryption key if provided (must be base64 encoded)
 			var encryptionKey []byte
 			if config.OAuth.EncryptionKey != "" {
-				// Decode from base64
-				decoded, err := base64.StdEncoding.DecodeString(config.OAuth.EncryptionKey)
+				decoded, err := decodeEncryptionKey(config.OAuth.EncryptionKey)
 				if err != nil {
-					return fmt.Errorf("OAuth encryption key must be base64 encoded (use: openssl rand -base64 32): %w", err)
+					return fmt.Errorf("OAuth encryption key: %w", err)
 				}
-
-				// Validate key for security weaknesses
-				if err := validateEncryptionKey(decoded); err != nil {
-					return fmt.Errorf("OAuth encryption key validation failed: %w", err)
-				}
-
 				encryptionKey = decoded
 				slog.Info("OAuth token encryption at rest enabled", "algorithm", "AES-256-GCM")
 			} else {
 				slog.Warn("OAuth encryption key not set - tokens will be stored unencrypted")
 			}
 
+			// With the previous key set, stored tokens are re-encrypted
+			// from it to the current key, and read with it until they are,
+			// so rotating the key does not log every user out.
+			var previousEncryptionKey []byte
+			if config.OAuth.PreviousEncryptionKey != "" {
+				previousEncryptionKey, err = startEncryptionKeyRotation(shutdownCtx, config.OAuth, encryptionKey)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Warn about insecure configuration options
 			if config.OAuth.AllowPublicRegistration {
 				slog.Warn("public client registration is enabled - this allows unlimited client registration and may lead to DoS",
@@ -1106,7 +1978,14 @@ func runServe(config ServeConfig) error {
 					"note", "this reduces SSRF protection, only use for internal/air-gapped deployments")
 			}
 
-			return runOAuthHTTPServer(mcpSrv, config.HTTPAddr, shutdownCtx, server.OAuthConfig{
+			// Drop a user's cached workload cluster clients when their token is
+			// refreshed, so they are rebuilt with the new token.
+			var onTokenRefresh func(context.Context, string)
+			if manager, ok := fedManager.(*federation.Manager); ok {
+				onTokenRefresh = manager.InvalidateUser
+			}
+
+			return runOAuthHTTPServer(mcpSrv, config.Transport, config.HTTPAddr, shutdownCtx, server.OAuthConfig{
 				ServiceVersion:                     rootCmd.Version,
 				BaseURL:                            config.OAuth.BaseURL,
 				Provider:                           config.OAuth.Provider,
@@ -1119,12 +1998,15 @@ func runServe(config ServeConfig) error {
 				DexCAFile:                          config.OAuth.DexCAFile,
 				DexKubernetesAuthenticatorClientID: config.OAuth.DexKubernetesAuthenticatorClientID,
 				DisableStreaming:                   config.OAuth.DisableStreaming,
+				SSEEndpoint:                        config.SSEEndpoint,
+				MessageEndpoint:                    config.MessageEndpoint,
 				DebugMode:                          config.DebugMode,
 				AllowPublicClientRegistration:      config.OAuth.AllowPublicRegistration,
 				RegistrationAccessToken:            config.OAuth.RegistrationToken,
 				AllowInsecureAuthWithoutState:      config.OAuth.AllowInsecureAuthWithoutState,
 				MaxClientsPerIP:                    config.OAuth.MaxClientsPerIP,
 				EncryptionKey:                      encryptionKey,
+				PreviousEncryptionKey:              previousEncryptionKey,
 				EnableHSTS:                         os.Getenv("ENABLE_HSTS") == envValueTrue,
 				AllowedOrigins:                     os.Getenv("ALLOWED_ORIGINS"),
 				TLSCertFile:                        config.OAuth.TLSCertFile,
@@ -1142,9 +2024,21 @@ func runServe(config ServeConfig) error {
 				TrustedAudiences:   config.OAuth.TrustedAudiences,
 				SSOAllowPrivateIPs: config.OAuth.SSOAllowPrivateIPs,
 				TrustedIssuers:     config.OAuth.TrustedIssuers,
-			}, serverContext, config.Metrics)
+				Compression:        config.Compression.Enabled,
+				CompressionMinSize: config.Compression.MinSize,
+				// Refresh ID tokens used for downstream Kubernetes authentication
+				RefreshIDTokens: config.DownstreamOAuth,
+				OnTokenRefresh:  onTokenRefresh,
+			}, healthChecker, config.Metrics)
+		}
+		stack, err := httpStackConfig(config, instrumentationProvider)
+		if err != nil {
+			return err
+		}
+		if config.Transport == transportSSE {
+			return runSSEServer(mcpSrv, config.HTTPAddr, config.SSEEndpoint, config.MessageEndpoint, shutdownCtx, config.DebugMode, stack, healthChecker, config.Metrics, httpAuth)
 		}
-		return runStreamableHTTPServer(mcpSrv, config.HTTPAddr, config.HTTPEndpoint, shutdownCtx, config.DebugMode, instrumentationProvider, serverContext, config.Metrics)
+		return runStreamableHTTPServer(mcpSrv, config.HTTPAddr, config.HTTPEndpoint, shutdownCtx, config.DebugMode, stack, healthChecker, config.Metrics, httpAuth)
 	default:
 		return fmt.Errorf("unsupported transport type: %s (supported: stdio, sse, streamable-http)", config.Transport)
 	}
@@ -1193,32 +2087,147 @@ func loadOAuthStorageEnvVars(cmd *cobra.Command, config *server.OAuthStorageConf
 	}
 }
 
-// loadCAPIModeConfig loads CAPI mode configuration from environment variables.
-// This matches the environment variables set by the Helm chart deployment.yaml.
+// loadRateLimitEnvVars loads tool rate limiting settings from environment
+// variables. Environment variables only override flag values when the flag
+// was not explicitly set.
+func loadRateLimitEnvVars(cmd *cobra.Command, config *RateLimitServeConfig) {
+	if !cmd.Flags().Changed("tool-rate-limit") {
+		if rate, ok := parseFloat64Env(os.Getenv("TOOL_RATE_LIMIT"), "TOOL_RATE_LIMIT"); ok {
+			config.Rate = rate
+		}
+	}
+
+	if !cmd.Flags().Changed("tool-rate-limit-burst") {
+		if burst, ok := parseIntEnv(os.Getenv("TOOL_RATE_LIMIT_BURST"), "TOOL_RATE_LIMIT_BURST"); ok {
+			config.Burst = burst
+		}
+	}
+
+	if !cmd.Flags().Changed("tool-rate-limit-per-tool") {
+		if os.Getenv("TOOL_RATE_LIMIT_PER_TOOL") == envValueTrue {
+			config.PerTool = true
+		}
+	}
+
+	if !cmd.Flags().Changed("tool-rate-limit-backend") {
+		if backend := os.Getenv("TOOL_RATE_LIMIT_BACKEND"); backend != "" {
+			config.Backend = backend
+		}
+	}
+}
+
+// loadHTTPAuthEnvVars loads the non-OAuth HTTP authentication settings from
+// environment variables. Environment variables only override flag values when
+// the flag was not explicitly set.
+func loadHTTPAuthEnvVars(cmd *cobra.Command, config *HTTPAuthServeConfig) {
+	if !cmd.Flags().Changed("auth-mode") {
+		if mode := os.Getenv("MCP_AUTH_MODE"); mode != "" {
+			config.Mode = mode
+		}
+	}
+
+	if !cmd.Flags().Changed("auth-token-file") {
+		loadEnvIfEmpty(&config.TokenFile, "MCP_AUTH_TOKEN_FILE")
+	}
+
+	if !cmd.Flags().Changed("auth-token-audiences") {
+		if audiences := os.Getenv("MCP_AUTH_TOKEN_AUDIENCES"); audiences != "" {
+			config.Audiences = splitAndTrimAudiences(audiences)
+		}
+	}
+}
+
+// concurrencyConfig applies the configured overrides to the default
+// concurrency limits.
+func concurrencyConfig(config ConcurrencyLimitConfig) federation.ConcurrencyConfig {
+	limits := federation.DefaultConcurrencyConfig()
+	if config.MaxConcurrent != nil {
+		limits.MaxConcurrent = *config.MaxConcurrent
+	}
+	if config.MaxQueued != nil {
+		limits.MaxQueued = *config.MaxQueued
+	}
+	if config.QueueTimeout > 0 {
+		limits.QueueTimeout = config.QueueTimeout
+	}
+	return limits
+}
+
+// clusterSourceOptions returns the federation manager options for the
+// configured cluster sources. The kubeconfig source is loaded here so that a
+// broken kubeconfig, or one running an exec credential plugin execPlugins
+// does not allow, fails startup.
+func clusterSourceOptions(config ClusterSourcesConfig, execPlugins k8s.ExecPluginConfig) ([]federation.ManagerOption, error) {
+	var opts []federation.ManagerOption
+
+	if config.CAPIDisabled {
+		opts = append(opts, federation.WithCAPIClusterSource(false))
+		slog.Info("CAPI cluster source disabled")
+	}
+
+	if config.KubeconfigPath != "" {
+		source, err := federation.NewKubeconfigClusterSource(config.KubeconfigPath, execPlugins)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLUSTER_SOURCE_KUBECONFIG: %w", err)
+		}
+		opts = append(opts, federation.WithClusterSources(source))
+		slog.Info("Kubeconfig cluster source enabled", //nolint:gosec // G706: path from operator, not end-user input
+			"path", config.KubeconfigPath)
+	}
+
+	if config.SecretLabelSelector != "" {
+		opts = append(opts, federation.WithSecretClusterSource(federation.SecretClusterSourceConfig{
+			LabelSelector:    config.SecretLabelSelector,
+			Namespace:        config.SecretNamespace,
+			ClusterNameLabel: config.SecretClusterNameLabel,
+		}))
+		slog.Info("Secret cluster source enabled", //nolint:gosec // G706: selector from operator, not end-user input
+			"label_selector", config.SecretLabelSelector,
+			"namespace", config.SecretNamespace)
+	}
+
+	return opts, nil
+}
+
+// loadCAPIModeConfig loads CAPI mode configuration from environment variables,
+// read with getenv. This matches the environment variables set by the Helm
+// chart deployment.yaml.
 //
 // Returns an error for security-critical configuration that must not be silently
 // ignored (e.g., malformed WC_GROUP_MAPPINGS). Non-critical values are logged
 // as warnings and use defaults.
-func loadCAPIModeConfig(config *CAPIModeConfig) error {
+func loadCAPIModeConfig(config *CAPIModeConfig, getenv func(string) string) error {
 	// Check if CAPI mode is enabled
-	if os.Getenv("CAPI_MODE_ENABLED") == envValueTrue {
+	if getenv("CAPI_MODE_ENABLED") == envValueTrue {
 		config.Enabled = true
 	}
 
 	// Workload cluster authentication mode
-	if mode := os.Getenv("WC_AUTH_MODE"); mode != "" {
+	if mode := getenv("WC_AUTH_MODE"); mode != "" {
 		config.WorkloadClusterAuth.Mode = mode
 	}
-	if suffix := os.Getenv("WC_CA_CONFIGMAP_SUFFIX"); suffix != "" {
+	if suffix := getenv("WC_CA_CONFIGMAP_SUFFIX"); suffix != "" {
 		config.WorkloadClusterAuth.CAConfigMapSuffix = suffix
 	}
-	if os.Getenv("WC_DISABLE_CACHING") == envValueTrue {
+	if getenv("WC_DISABLE_CACHING") == envValueTrue {
 		config.WorkloadClusterAuth.DisableCaching = true
 	}
+	if tokenURL := getenv("WC_TOKEN_EXCHANGE_URL"); tokenURL != "" {
+		config.WorkloadClusterAuth.TokenExchange.URL = tokenURL
+	}
+	if clientID := getenv("WC_TOKEN_EXCHANGE_CLIENT_ID"); clientID != "" {
+		config.WorkloadClusterAuth.TokenExchange.ClientID = clientID
+	}
+	if secret := getenv("WC_TOKEN_EXCHANGE_CLIENT_SECRET"); secret != "" {
+		config.WorkloadClusterAuth.TokenExchange.ClientSecret = secret
+	}
+	if connectorID := getenv("WC_TOKEN_EXCHANGE_CONNECTOR_ID"); connectorID != "" {
+		config.WorkloadClusterAuth.TokenExchange.ConnectorID = connectorID
+	}
 	// Group mappings for impersonation mode (JSON format).
 	// This is a security-critical setting: if an operator sets it, malformed JSON
 	// must fail startup rather than silently starting without mappings (fail-closed).
-	if mappingsJSON := os.Getenv("WC_GROUP_MAPPINGS"); mappingsJSON != "" {
+	if mappingsJSON := getenv("WC_GROUP_MAPPINGS"); mappingsJSON != "" {
 		mappings, err := federation.ParseGroupMappingsJSON(mappingsJSON)
 		if err != nil {
 			return fmt.Errorf("invalid WC_GROUP_MAPPINGS: %w (the server refuses to start "+
@@ -1231,70 +2240,166 @@ func loadCAPIModeConfig(config *CAPIModeConfig) error {
 				"summary", federation.FormatGroupMappingsForLog(mappings))
 		}
 	}
+	// Group filters are security-relevant in the same way: a malformed
+	// allowlist must not silently fall back to sending every group.
+	if prefixesJSON := getenv("WC_GROUP_STRIP_PREFIXES"); prefixesJSON != "" {
+		prefixes, err := federation.ParseGroupListJSON(prefixesJSON)
+		if err != nil {
+			return fmt.Errorf("invalid WC_GROUP_STRIP_PREFIXES: %w", err)
+		}
+		config.WorkloadClusterAuth.GroupStripPrefixes = prefixes
+	}
+	if patternsJSON := getenv("WC_GROUP_ALLOW_PATTERNS"); patternsJSON != "" {
+		patterns, err := federation.ParseGroupListJSON(patternsJSON)
+		if err != nil {
+			return fmt.Errorf("invalid WC_GROUP_ALLOW_PATTERNS: %w", err)
+		}
+		config.WorkloadClusterAuth.GroupAllowPatterns = patterns
+	}
 
 	// Privileged access configuration (split-credential model)
-	if v := os.Getenv("PRIVILEGED_ACCESS_ENABLED"); v != "" {
+	if v := getenv("PRIVILEGED_ACCESS_ENABLED"); v != "" {
 		val := v == envValueTrue
 		config.PrivilegedAccess.Enabled = &val
 	}
 	// PRIVILEGED_ACCESS_STRICT (new) with PRIVILEGED_SECRET_ACCESS_STRICT (deprecated) fallback
-	if os.Getenv("PRIVILEGED_ACCESS_STRICT") == envValueTrue || os.Getenv("PRIVILEGED_SECRET_ACCESS_STRICT") == envValueTrue {
+	if getenv("PRIVILEGED_ACCESS_STRICT") == envValueTrue || getenv("PRIVILEGED_SECRET_ACCESS_STRICT") == envValueTrue {
 		config.PrivilegedAccess.Strict = true
 	}
 	// Privileged CAPI discovery (default: true)
-	if v := os.Getenv("PRIVILEGED_CAPI_DISCOVERY"); v != "" {
+	if v := getenv("PRIVILEGED_CAPI_DISCOVERY"); v != "" {
 		val := v == envValueTrue
 		config.PrivilegedAccess.PrivilegedCAPIDiscovery = &val
 	}
 	// PRIVILEGED_ACCESS_RATE_PER_SECOND (new) with PRIVILEGED_SECRET_ACCESS_RATE_PER_SECOND (deprecated) fallback
-	if f, ok := parseFloat64Env(os.Getenv("PRIVILEGED_ACCESS_RATE_PER_SECOND"), "PRIVILEGED_ACCESS_RATE_PER_SECOND"); ok {
+	if f, ok := parseFloat64Env(getenv("PRIVILEGED_ACCESS_RATE_PER_SECOND"), "PRIVILEGED_ACCESS_RATE_PER_SECOND"); ok {
 		config.PrivilegedAccess.RateLimitPerSecond = f
-	} else if f, ok := parseFloat64Env(os.Getenv("PRIVILEGED_SECRET_ACCESS_RATE_PER_SECOND"), "PRIVILEGED_SECRET_ACCESS_RATE_PER_SECOND"); ok {
+	} else if f, ok := parseFloat64Env(getenv("PRIVILEGED_SECRET_ACCESS_RATE_PER_SECOND"), "PRIVILEGED_SECRET_ACCESS_RATE_PER_SECOND"); ok {
 		config.PrivilegedAccess.RateLimitPerSecond = f
 	}
 	// PRIVILEGED_ACCESS_RATE_BURST (new) with PRIVILEGED_SECRET_ACCESS_RATE_BURST (deprecated) fallback
-	if n, ok := parseIntEnv(os.Getenv("PRIVILEGED_ACCESS_RATE_BURST"), "PRIVILEGED_ACCESS_RATE_BURST"); ok {
+	if n, ok := parseIntEnv(getenv("PRIVILEGED_ACCESS_RATE_BURST"), "PRIVILEGED_ACCESS_RATE_BURST"); ok {
 		config.PrivilegedAccess.RateLimitBurst = n
-	} else if n, ok := parseIntEnv(os.Getenv("PRIVILEGED_SECRET_ACCESS_RATE_BURST"), "PRIVILEGED_SECRET_ACCESS_RATE_BURST"); ok {
+	} else if n, ok := parseIntEnv(getenv("PRIVILEGED_SECRET_ACCESS_RATE_BURST"), "PRIVILEGED_SECRET_ACCESS_RATE_BURST"); ok {
 		config.PrivilegedAccess.RateLimitBurst = n
 	}
 
+	// Cluster sources (CAPI is enabled by default)
+	if getenv("CLUSTER_SOURCE_CAPI_ENABLED") == "false" {
+		config.ClusterSources.CAPIDisabled = true
+	}
+	if path := getenv("CLUSTER_SOURCE_KUBECONFIG"); path != "" {
+		config.ClusterSources.KubeconfigPath = path
+	}
+	if selector := getenv("CLUSTER_SOURCE_SECRET_SELECTOR"); selector != "" {
+		config.ClusterSources.SecretLabelSelector = selector
+	}
+	if namespace := getenv("CLUSTER_SOURCE_SECRET_NAMESPACE"); namespace != "" {
+		config.ClusterSources.SecretNamespace = namespace
+	}
+	if label := getenv("CLUSTER_SOURCE_SECRET_CLUSTER_NAME_LABEL"); label != "" {
+		config.ClusterSources.SecretClusterNameLabel = label
+	}
+
+	// Concurrency limit for contacting workload clusters
+	if n, ok := parseIntEnv(getenv("FEDERATION_MAX_CONCURRENT_CLUSTERS"), "FEDERATION_MAX_CONCURRENT_CLUSTERS"); ok {
+		config.Concurrency.MaxConcurrent = &n
+	}
+	if n, ok := parseIntEnv(getenv("FEDERATION_MAX_QUEUED_CLUSTER_OPERATIONS"), "FEDERATION_MAX_QUEUED_CLUSTER_OPERATIONS"); ok {
+		config.Concurrency.MaxQueued = &n
+	}
+	if d, ok := parseDurationEnv(getenv("FEDERATION_CLUSTER_QUEUE_TIMEOUT"), "FEDERATION_CLUSTER_QUEUE_TIMEOUT"); ok {
+		config.Concurrency.QueueTimeout = d
+	}
+
+	// CAPI cluster discovery cache
+	if getenv("DISCOVERY_CACHE_ENABLED") == envValueTrue {
+		config.DiscoveryCache.Enabled = true
+	}
+	if d, ok := parseDurationEnv(getenv("DISCOVERY_CACHE_RESYNC_PERIOD"), "DISCOVERY_CACHE_RESYNC_PERIOD"); ok {
+		config.DiscoveryCache.ResyncPeriod = d
+	}
+	if d, ok := parseDurationEnv(getenv("DISCOVERY_CACHE_ACCESS_TTL"), "DISCOVERY_CACHE_ACCESS_TTL"); ok {
+		config.DiscoveryCache.AccessTTL = d
+	}
+
 	// Cache configuration - store as strings for later validation
-	if ttl := os.Getenv("CLIENT_CACHE_TTL"); ttl != "" {
+	if ttl := getenv("CLIENT_CACHE_TTL"); ttl != "" {
 		config.CacheTTL = ttl
 	}
-	if n, ok := parseIntEnv(os.Getenv("CLIENT_CACHE_MAX_ENTRIES"), "CLIENT_CACHE_MAX_ENTRIES"); ok {
+	if n, ok := parseIntEnv(getenv("CLIENT_CACHE_MAX_ENTRIES"), "CLIENT_CACHE_MAX_ENTRIES"); ok {
 		config.CacheMaxEntries = n
 	}
-	if interval := os.Getenv("CLIENT_CACHE_CLEANUP_INTERVAL"); interval != "" {
+	if interval := getenv("CLIENT_CACHE_CLEANUP_INTERVAL"); interval != "" {
 		config.CacheCleanupInterval = interval
 	}
 
 	// OAuth token lifetime for cache TTL validation
 	// This helps operators avoid cache TTLs that exceed their token lifetime
-	if lifetime := os.Getenv("OAUTH_TOKEN_LIFETIME"); lifetime != "" {
+	if lifetime := getenv("OAUTH_TOKEN_LIFETIME"); lifetime != "" {
 		config.OAuthTokenLifetime = lifetime
 	}
 
 	// Connectivity configuration - store as strings for later validation
-	if timeout := os.Getenv("CONNECTIVITY_TIMEOUT"); timeout != "" {
+	if timeout := getenv("CONNECTIVITY_TIMEOUT"); timeout != "" {
 		config.ConnectivityTimeout = timeout
 	}
-	if n, ok := parseIntEnv(os.Getenv("CONNECTIVITY_RETRY_ATTEMPTS"), "CONNECTIVITY_RETRY_ATTEMPTS"); ok {
+	if n, ok := parseIntEnv(getenv("CONNECTIVITY_RETRY_ATTEMPTS"), "CONNECTIVITY_RETRY_ATTEMPTS"); ok {
 		config.ConnectivityRetryAttempts = n
 	}
-	if backoff := os.Getenv("CONNECTIVITY_RETRY_BACKOFF"); backoff != "" {
+	if backoff := getenv("CONNECTIVITY_RETRY_BACKOFF"); backoff != "" {
 		config.ConnectivityRetryBackoff = backoff
 	}
-	if reqTimeout := os.Getenv("CONNECTIVITY_REQUEST_TIMEOUT"); reqTimeout != "" {
+	if reqTimeout := getenv("CONNECTIVITY_REQUEST_TIMEOUT"); reqTimeout != "" {
 		config.ConnectivityRequestTimeout = reqTimeout
 	}
-	if f, ok := parseFloat32Env(os.Getenv("CONNECTIVITY_QPS"), "CONNECTIVITY_QPS"); ok {
+	if f, ok := parseFloat32Env(getenv("CONNECTIVITY_QPS"), "CONNECTIVITY_QPS"); ok {
 		config.ConnectivityQPS = f
 	}
-	if n, ok := parseIntEnv(os.Getenv("CONNECTIVITY_BURST"), "CONNECTIVITY_BURST"); ok {
+	if n, ok := parseIntEnv(getenv("CONNECTIVITY_BURST"), "CONNECTIVITY_BURST"); ok {
 		config.ConnectivityBurst = n
 	}
 
 	return nil
 }
+
+// registerTools registers every tool category on mcpSrv. Categories
+// register only the tools that serverContext enables.
+func registerTools(mcpSrv *mcpserver.MCPServer, serverContext *server.ServerContext) error {
+	if err := resource.RegisterResourceTools(mcpSrv, serverContext); err != nil {
+		return fmt.Errorf("failed to register resource tools: %w", err)
+	}
+
+	if err := pod.RegisterPodTools(mcpSrv, serverContext); err != nil {
+		return fmt.Errorf("failed to register pod tools: %w", err)
+	}
+
+	if err := contexttools.RegisterContextTools(mcpSrv, serverContext); err != nil {
+		return fmt.Errorf("failed to register context tools: %w", err)
+	}
+
+	if err := cluster.RegisterClusterTools(mcpSrv, serverContext); err != nil {
+		return fmt.Errorf("failed to register cluster tools: %w", err)
+	}
+
+	access.RegisterTools(mcpSrv, serverContext)
+
+	// Register the confirm tool (only registers when confirmations are enabled)
+	tools.RegisterConfirmTool(mcpSrv, serverContext)
+
+	// Register the undo tool (only registers when the undo journal is enabled)
+	tools.RegisterUndoTool(mcpSrv, serverContext)
+
+	// Register the session context tools
+	tools.RegisterSessionTools(mcpSrv, serverContext)
+
+	// Register the preferences tools (only registers when preferences are enabled)
+	tools.RegisterPreferencesTools(mcpSrv, serverContext)
+
+	// Register CAPI discovery tools (only registers when federation is enabled)
+	if err := capi.RegisterCAPITools(mcpSrv, serverContext); err != nil {
+		return fmt.Errorf("failed to register CAPI tools: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+)
+
+// adminToolsPath is the admin endpoint reporting and changing the exposed
+// tools.
+const adminToolsPath = "/admin/tools"
+
+// maxAdminRequestBytes caps the body of an admin request.
+const maxAdminRequestBytes = 64 << 10
+
+// adminToolsRequest changes the exposed tools. The profile is switched
+// first, then tools are enabled, then disabled.
+type adminToolsRequest struct {
+	Profile string   `json:"profile,omitempty"`
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+}
+
+// validateAdminConfig checks that the admin endpoint, when enabled, is
+// protected by a token file.
+func validateAdminConfig(cfg AdminServeConfig) error {
+	if cfg.Addr != "" && cfg.TokenFile == "" {
+		return fmt.Errorf("--admin-addr requires --admin-token-file")
+	}
+	return nil
+}
+
+// newAdminHandler serves the admin endpoints for exposure and, when not nil,
+// the debug capture recorder. Every request must carry one of the tokens
+// auth accepts.
+func newAdminHandler(exposure *tools.ToolExposure, recorder *capture.Recorder, auth middleware.TokenAuthenticator) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(adminToolsPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req adminToolsRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminRequestBytes)).Decode(&req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := applyAdminToolsRequest(exposure, req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			slog.Info("exposed tools changed through the admin endpoint",
+				"subject", middleware.AuthenticatedSubjectFromContext(r.Context()),
+				"profile", req.Profile,
+				"enabled", req.Enable,
+				"disabled", req.Disable)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(exposure.Status()); err != nil {
+			slog.Warn("failed to write admin response", "error", err)
+		}
+	})
+	if recorder != nil {
+		handleCaptures(mux, recorder)
+	}
+	return middleware.BearerAuth(auth, slog.Default())(mux)
+}
+
+// applyAdminToolsRequest validates the whole request before changing
+// anything, so that a bad tool name does not leave a half-applied change.
+func applyAdminToolsRequest(exposure *tools.ToolExposure, req adminToolsRequest) error {
+	if req.Profile != "" {
+		if err := tools.ValidateProfile(req.Profile); err != nil {
+			return err
+		}
+	}
+	if err := exposure.CheckToolNames(append(append([]string{}, req.Enable...), req.Disable...)...); err != nil {
+		return err
+	}
+
+	if req.Profile != "" {
+		if err := exposure.SetProfile(req.Profile); err != nil {
+			return err
+		}
+	}
+	if len(req.Enable) > 0 {
+		if err := exposure.EnableTools(req.Enable...); err != nil {
+			return err
+		}
+	}
+	if len(req.Disable) > 0 {
+		if err := exposure.DisableTools(req.Disable...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startAdminServer serves the admin endpoints on cfg.Addr until ctx is
+// done. The admin endpoint has its own listener and tokens, so it is never
+// reachable through the MCP endpoint.
+func startAdminServer(ctx context.Context, cfg AdminServeConfig, exposure *tools.ToolExposure, recorder *capture.Recorder) error {
+	tokens, err := middleware.LoadStaticTokens(cfg.TokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to load admin tokens: %w", err)
+	}
+	auth, err := middleware.NewStaticTokenAuthenticator(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to load admin tokens: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           newAdminHandler(exposure, recorder, auth),
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	endpoints := []string{adminToolsPath}
+	if recorder != nil {
+		endpoints = append(endpoints, adminCapturesPath)
+	}
+	go func() {
+		slog.Info("starting admin server", "addr", cfg.Addr, "endpoints", endpoints)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("admin server stopped with error", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), server.DefaultShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("error shutting down admin server", "error", err)
+		}
+	}()
+	return nil
+}
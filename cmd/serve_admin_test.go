@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+)
+
+func newAdminTestHandler(t *testing.T) (http.Handler, *mcpserver.MCPServer) {
+	t.Helper()
+	s := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithToolCapabilities(true))
+	handler := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	s.AddTool(mcp.NewTool("get", mcp.WithReadOnlyHintAnnotation(true)), handler)
+	s.AddTool(mcp.NewTool("exec", mcp.WithReadOnlyHintAnnotation(false)), handler)
+	s.AddTool(mcp.NewTool("scale", mcp.WithReadOnlyHintAnnotation(false)), handler)
+
+	exposure, err := tools.NewToolExposure(s, tools.ProfileAdmin)
+	require.NoError(t, err)
+	auth, err := middleware.NewStaticTokenAuthenticator([]string{"admin-secret"})
+	require.NoError(t, err)
+	return newAdminHandler(exposure, nil, auth), s
+}
+
+func adminRequest(t *testing.T, h http.Handler, method, token, body string) (*httptest.ResponseRecorder, tools.ToolExposureStatus) {
+	t.Helper()
+	req := httptest.NewRequest(method, adminToolsPath, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var status tools.ToolExposureStatus
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	}
+	return rec, status
+}
+
+func TestAdminHandler(t *testing.T) {
+	h, s := newAdminTestHandler(t)
+
+	rec, _ := adminRequest(t, h, http.MethodGet, "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec, _ = adminRequest(t, h, http.MethodGet, "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, status := adminRequest(t, h, http.MethodGet, "admin-secret", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, tools.ProfileAdmin, status.Profile)
+	assert.Equal(t, []string{"exec", "get", "scale"}, status.Exposed)
+
+	rec, status = adminRequest(t, h, http.MethodPost, "admin-secret", `{"disable":["exec"]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"exec"}, status.Disabled)
+	assert.Nil(t, s.GetTool("exec"), "a disabled tool is unregistered")
+
+	rec, status = adminRequest(t, h, http.MethodPost, "admin-secret", `{"profile":"read-only","enable":["exec"]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"get"}, status.Exposed)
+	assert.Empty(t, status.Disabled)
+
+	rec, _ = adminRequest(t, h, http.MethodPost, "admin-secret", `{"profile":"admin","disable":["rm"]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `unknown tool "rm"`)
+	_, status = adminRequest(t, h, http.MethodGet, "admin-secret", "")
+	assert.Equal(t, tools.ProfileReadOnly, status.Profile, "a rejected request changes nothing")
+
+	rec, _ = adminRequest(t, h, http.MethodDelete, "admin-secret", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestValidateAdminConfig(t *testing.T) {
+	assert.NoError(t, validateAdminConfig(AdminServeConfig{}))
+	assert.ErrorContains(t, validateAdminConfig(AdminServeConfig{Addr: ":9091"}), "--admin-token-file")
+	assert.NoError(t, validateAdminConfig(AdminServeConfig{Addr: ":9091", TokenFile: "/etc/admin/tokens"}))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+)
+
+// adminCapturesPath is the admin endpoint reporting and clearing the
+// captured tool calls.
+const adminCapturesPath = "/admin/captures"
+
+// validateDebugCaptureConfig checks the debug capture limits.
+func validateDebugCaptureConfig(cfg DebugCaptureServeConfig) error {
+	if cfg.Size < 0 {
+		return fmt.Errorf("--debug-capture must not be negative, got %d", cfg.Size)
+	}
+	if cfg.Size > 0 && cfg.MaxBytes <= 0 {
+		return fmt.Errorf("--debug-capture-max-bytes must be positive, got %d", cfg.MaxBytes)
+	}
+	return nil
+}
+
+// newDebugCapture returns the recorder of cfg, or nil when debug capture is
+// disabled.
+func newDebugCapture(cfg DebugCaptureServeConfig) *capture.Recorder {
+	if cfg.Size == 0 {
+		return nil
+	}
+	slog.Info("debug capture enabled", "size", cfg.Size, "max_bytes", cfg.MaxBytes)
+	return capture.NewRecorder(cfg.Size, cfg.MaxBytes)
+}
+
+// handleCaptures serves the captured tool calls of recorder on mux. GET
+// returns them oldest first, DELETE clears them.
+func handleCaptures(mux *http.ServeMux, recorder *capture.Recorder) {
+	mux.HandleFunc(adminCapturesPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodDelete:
+			recorder.Clear()
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(recorder.Entries()); err != nil {
+			slog.Warn("failed to write admin response", "error", err)
+		}
+	})
+}
+
+// dumpDebugCapture logs the captured tool calls of recorder, oldest first.
+func dumpDebugCapture(logger *slog.Logger, recorder *capture.Recorder) {
+	entries := recorder.Entries()
+	logger.Info("dumping debug capture", "calls", len(entries))
+	for _, e := range entries {
+		logger.Info("captured tool call",
+			"time", e.Time,
+			"tool", e.Tool,
+			"session", e.Session,
+			"user", e.User,
+			"trace_id", e.TraceID,
+			"duration", e.Duration,
+			"is_error", e.IsError,
+			"error", e.Error,
+			"arguments", e.Arguments,
+			"result", e.Result)
+	}
+}
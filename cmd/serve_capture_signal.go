@@ -0,0 +1,31 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+)
+
+// dumpDebugCaptureOnSignal logs the captured tool calls of recorder every
+// time the process receives SIGUSR1, until ctx is done.
+func dumpDebugCaptureOnSignal(ctx context.Context, recorder *capture.Recorder) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				dumpDebugCapture(slog.Default(), recorder)
+			}
+		}
+	}()
+}
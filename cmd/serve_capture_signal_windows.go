@@ -0,0 +1,13 @@
+//go:build windows
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+)
+
+// dumpDebugCaptureOnSignal does nothing on Windows, which has no SIGUSR1;
+// the captured tool calls are read through the admin endpoint.
+func dumpDebugCaptureOnSignal(context.Context, *capture.Recorder) {}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+)
+
+func TestValidateDebugCaptureConfig(t *testing.T) {
+	assert.NoError(t, validateDebugCaptureConfig(DebugCaptureServeConfig{}))
+	assert.NoError(t, validateDebugCaptureConfig(DebugCaptureServeConfig{Size: 100, MaxBytes: 1024}))
+	assert.ErrorContains(t, validateDebugCaptureConfig(DebugCaptureServeConfig{Size: -1}), "--debug-capture")
+	assert.ErrorContains(t, validateDebugCaptureConfig(DebugCaptureServeConfig{Size: 100}), "--debug-capture-max-bytes")
+}
+
+func TestAdminHandler_Captures(t *testing.T) {
+	s := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithToolCapabilities(true))
+	exposure, err := tools.NewToolExposure(s, tools.ProfileAdmin)
+	require.NoError(t, err)
+	auth, err := middleware.NewStaticTokenAuthenticator([]string{"admin-secret"})
+	require.NoError(t, err)
+
+	captures := func(h http.Handler, method, token string) (int, []capture.Entry) {
+		req := httptest.NewRequest(method, adminCapturesPath, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var entries []capture.Entry
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		}
+		return rec.Code, entries
+	}
+
+	code, _ := captures(newAdminHandler(exposure, nil, auth), http.MethodGet, "admin-secret")
+	assert.Equal(t, http.StatusNotFound, code, "the endpoint is only served with debug capture enabled")
+
+	recorder := capture.NewRecorder(5, 0)
+	recorder.Record(capture.Entry{Tool: "get", Arguments: `{"namespace":"prod"}`})
+	h := newAdminHandler(exposure, recorder, auth)
+
+	code, _ = captures(h, http.MethodGet, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, entries := captures(h, http.MethodGet, "admin-secret")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "get", entries[0].Tool)
+
+	code, entries = captures(h, http.MethodDelete, "admin-secret")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, entries)
+	assert.Empty(t, recorder.Entries())
+
+	code, _ = captures(h, http.MethodPost, "admin-secret")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestDumpDebugCapture(t *testing.T) {
+	recorder := capture.NewRecorder(5, 0)
+	recorder.Record(capture.Entry{Tool: "get", Arguments: `{"namespace":"prod"}`, Result: "pod/web"})
+
+	var buf bytes.Buffer
+	dumpDebugCapture(slog.New(slog.NewTextHandler(&buf, nil)), recorder)
+	assert.Contains(t, buf.String(), "calls=1")
+	assert.Contains(t, buf.String(), "tool=get")
+	assert.Contains(t, buf.String(), "result=pod/web")
+}
@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/url"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
 )
 
 // OAuth provider constants - use server package constants for consistency
@@ -40,9 +46,87 @@ type ServeConfig struct {
 	DryRun             bool
 	QPSLimit           float32
 	BurstLimit         int
+	UserQPSLimit       float32
+	UserBurstLimit     int
+	Protobuf           bool
 	DebugMode          bool
 	InCluster          bool
 
+	// KubeconfigContexts restricts the kubeContext tool parameter to these
+	// kubeconfig contexts
+	KubeconfigContexts []string
+
+	// AllowImpersonateAs enables the per-call impersonation override
+	AllowImpersonateAs bool
+
+	// DebugImages are the images debug_pod may start as ephemeral containers
+	DebugImages []string
+
+	// CopyPaths are the container directories cp_from_pod and cp_to_pod may use
+	CopyPaths []string
+
+	// CopyMaxBytes caps the size of a copied file
+	CopyMaxBytes int64
+
+	// SecretWrites controls whether create, apply and patch may write Secrets
+	SecretWrites string
+
+	// ConfirmDestructive holds destructive tool calls until they are
+	// confirmed with the confirm tool
+	ConfirmDestructive bool
+
+	// ConfirmationTTL is how long a destructive call can be confirmed
+	ConfirmationTTL time.Duration
+
+	// EnablePlans enables the plan tools
+	EnablePlans bool
+
+	// PlanTTL is how long a plan is kept after its last change
+	PlanTTL time.Duration
+
+	// ToolProfile selects the tools exposed: read-only, operator or admin
+	ToolProfile string
+
+	// Admin endpoint changing the exposed tools at runtime
+	Admin AdminServeConfig
+
+	// Tool authorization policy
+	Policy PolicyServeConfig
+
+	// Kubernetes API timeouts
+	Timeouts TimeoutServeConfig
+
+	// Retries of Kubernetes API reads
+	Retry RetryServeConfig
+
+	// Limits of kubeconfig exec credential plugins
+	ExecPlugins ExecPluginServeConfig
+
+	// Impersonation extras sent to the Kubernetes API for audit logging
+	ImpersonationExtras ImpersonationExtrasServeConfig
+
+	// Informer-backed list cache
+	ListCache ListCacheServeConfig
+
+	// Tool output processing
+	Output OutputServeConfig
+
+	// Per-caller tool rate limiting
+	RateLimit RateLimitServeConfig
+
+	// Session ownership across replicas
+	Sessions SessionServeConfig
+
+	// Journal of changes for the undo tool
+	UndoJournal UndoJournalServeConfig
+
+	// Per-user preferences
+	Preferences PreferencesServeConfig
+
+	// HTTPAuth configures bearer token authentication for the HTTP
+	// transports when OAuth is not enabled.
+	HTTPAuth HTTPAuthServeConfig
+
 	// OAuth configuration
 	OAuth           OAuthServeConfig
 	DownstreamOAuth bool
@@ -52,6 +136,285 @@ type ServeConfig struct {
 
 	// Metrics server configuration
 	Metrics MetricsServeConfig
+
+	// Response compression of the HTTP transports
+	Compression CompressionServeConfig
+
+	// Fixtures serves or records Kubernetes calls from a fixture directory
+	Fixtures FixtureServeConfig
+
+	// Logging selects the format and destination of the server logs
+	Logging LoggingServeConfig
+
+	// DebugCapture records sanitized tool calls for debugging
+	DebugCapture DebugCaptureServeConfig
+
+	// ConfigFileEnv holds the settings of the --config file that have no
+	// flag, by the environment variable they stand in for
+	ConfigFileEnv map[string]string
+}
+
+// getenv returns the environment variable key or, when it is not set, the
+// value the --config file gives it.
+func (c ServeConfig) getenv(key string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return c.ConfigFileEnv[key]
+}
+
+// PolicyServeConfig holds the tool authorization policy configuration.
+type PolicyServeConfig struct {
+	// ReadOnlyGroups are OAuth groups restricted to read-only tools.
+	ReadOnlyGroups []string
+
+	// File is the path to the YAML policy file. Empty disables the policy engine.
+	File string
+
+	// ReloadInterval is how often the policy file is checked for changes.
+	ReloadInterval time.Duration
+
+	// OPAURL is the OPA decision URL. Empty disables external authorization.
+	OPAURL string
+
+	// OPATimeout bounds a single OPA decision request.
+	OPATimeout time.Duration
+
+	// OPAFailOpen allows calls when OPA cannot be reached.
+	OPAFailOpen bool
+
+	// RestrictedNamespaceSelector restricts namespaces whose labels match
+	// it. Empty disables label-based restrictions.
+	RestrictedNamespaceSelector string
+
+	// RestrictedNamespaceCacheTTL is how long namespace labels are cached.
+	RestrictedNamespaceCacheTTL time.Duration
+
+	// RestrictedNamespaceFailOpen allows calls when namespace labels cannot
+	// be read.
+	RestrictedNamespaceFailOpen bool
+
+	// ReadRestrictions lists namespaces, or namespace/resource pairs, tool
+	// calls may not read.
+	ReadRestrictions []string
+
+	// WriteNamespaces lists the namespaces mutating tools may write to.
+	// Empty allows every namespace.
+	WriteNamespaces []string
+
+	// AllowClusterScopedWrites lets mutating tools write cluster-scoped
+	// objects while WriteNamespaces limits them to some namespaces.
+	AllowClusterScopedWrites bool
+}
+
+// OutputServeConfig holds the tool output processing settings exposed as flags.
+type OutputServeConfig struct {
+	// ScrubPII masks PII patterns in returned object data.
+	ScrubPII bool
+
+	// PIIPatterns limits scrubbing to the named patterns. Empty means all.
+	PIIPatterns []string
+
+	// SecretRedaction is how Secret data is shown: full, keys or hash.
+	SecretRedaction string
+
+	// ScrubCredentials redacts likely credentials from pod log and exec output.
+	ScrubCredentials bool
+
+	// CredentialPatterns limits credential scrubbing to the named patterns.
+	// Empty means all.
+	CredentialPatterns []string
+
+	// TokenWarningThreshold is the estimated token count of a tool response
+	// above which a warning is added to it. 0 disables the warning.
+	TokenWarningThreshold int
+}
+
+// FixtureServeConfig holds the recorded-fixture settings.
+type FixtureServeConfig struct {
+	// Dir is the fixture directory. Empty talks to the cluster as usual.
+	Dir string
+
+	// Record forwards calls to the cluster and records them to Dir instead
+	// of replaying Dir.
+	Record bool
+}
+
+// Replay reports whether tools are served from fixtures without a cluster.
+func (c FixtureServeConfig) Replay() bool {
+	return c.Dir != "" && !c.Record
+}
+
+// Log formats accepted by --log-format.
+const (
+	logFormatAuto = "auto"
+	logFormatJSON = "json"
+	logFormatText = "text"
+)
+
+// defaultLogMaxBytes is the default size at which --log-file is rotated.
+const defaultLogMaxBytes = 100 << 20
+
+// LoggingServeConfig holds the server log settings.
+type LoggingServeConfig struct {
+	// File is the log file. Empty logs to stderr.
+	File string
+
+	// Format is auto, json or text. Auto selects JSON inside a pod.
+	Format string
+
+	// MaxBytes rotates File before it grows beyond this size; 0 disables
+	MaxBytes int64
+
+	// RotateInterval rotates File after it has been written to for this
+	// long; 0 disables
+	RotateInterval time.Duration
+
+	// MaxBackups is the number of rotated files kept; 0 keeps all
+	MaxBackups int
+}
+
+// RateLimitServeConfig holds the tool invocation rate limiting settings.
+type RateLimitServeConfig struct {
+	// Rate is the sustained tool calls per second allowed per caller.
+	// Zero disables rate limiting.
+	Rate float64
+
+	// Burst is the number of calls a caller may make at once.
+	Burst int
+
+	// PerTool gives every tool its own budget per caller.
+	PerTool bool
+
+	// Backend is "memory" or "valkey". Valkey shares budgets across
+	// replicas and uses the --valkey-* connection settings.
+	Backend string
+}
+
+// SessionServeConfig holds the replica identity and where session
+// ownership is recorded.
+type SessionServeConfig struct {
+	// ReplicaID identifies this replica. Defaults to the pod name or host
+	// name.
+	ReplicaID string
+
+	// Backend is "memory" or "valkey". Valkey shares session ownership
+	// across replicas and uses the --valkey-* connection settings.
+	Backend string
+}
+
+// UndoJournalServeConfig holds the settings of the undo journal.
+type UndoJournalServeConfig struct {
+	// Backend is "memory" or "valkey"; empty disables the journal. Valkey
+	// shares journals across replicas and uses the --valkey-* connection
+	// settings.
+	Backend string
+
+	// Size is the number of changes kept per session
+	Size int
+
+	// TTL is how long a journal is kept after its session's last change
+	TTL time.Duration
+}
+
+// PreferencesServeConfig holds the settings of per-user preferences.
+type PreferencesServeConfig struct {
+	// Backend is "memory" or "valkey"; empty disables preferences. Valkey
+	// keeps preferences across replicas and restarts and uses the
+	// --valkey-* connection settings.
+	Backend string
+}
+
+// ListCacheServeConfig holds the settings of the informer-backed list cache.
+type ListCacheServeConfig struct {
+	// Resources are the resource types served from the cache. Empty
+	// disables the cache.
+	Resources []string
+
+	// Resync is the informers' resync period.
+	Resync time.Duration
+}
+
+// RetryServeConfig holds the retry settings of Kubernetes API reads.
+type RetryServeConfig struct {
+	// MaxRetries is the number of retries after a transient error.
+	MaxRetries int
+
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait before a retry.
+	MaxBackoff time.Duration
+}
+
+// ExecPluginServeConfig holds the limits of exec credential plugins, such as
+// aws-iam-authenticator, run for kubeconfig users.
+type ExecPluginServeConfig struct {
+	// Allowlist lists the plugins that may run. Empty allows all.
+	Allowlist []string
+
+	// Timeout bounds a plugin run.
+	Timeout time.Duration
+
+	// MaxOutputBytes bounds a plugin's output.
+	MaxOutputBytes int64
+}
+
+// ImpersonationExtrasServeConfig holds the extras added to the Impersonate-Extra-*
+// headers of impersonated Kubernetes API requests, next to the built-in agent
+// and trace-id extras.
+type ImpersonationExtrasServeConfig struct {
+	// Static extras are sent with every impersonated request.
+	Static map[string]string
+
+	// ClientInfo adds the MCP client's name and version.
+	ClientInfo bool
+
+	// SessionID adds the MCP session ID.
+	SessionID bool
+
+	// RequestMeta maps tool call _meta fields to extra keys.
+	RequestMeta map[string]string
+}
+
+// TimeoutServeConfig holds the Kubernetes API timeout settings.
+// Discovery is budgeted separately from data-plane calls because a single
+// unhealthy aggregated API can make discovery far slower than a get or list.
+type TimeoutServeConfig struct {
+	// Request bounds data-plane Kubernetes API calls.
+	Request time.Duration
+
+	// Discovery bounds a single API discovery request.
+	Discovery time.Duration
+
+	// DiscoveryMinInterval caps discovery frequency per cluster.
+	DiscoveryMinInterval time.Duration
+
+	// Tool bounds a whole tool call, which may make many API calls.
+	Tool time.Duration
+
+	// Tools overrides Tool for the named tools.
+	Tools map[string]time.Duration
+}
+
+// AdminServeConfig holds the settings of the admin endpoint.
+type AdminServeConfig struct {
+	// Addr is the address of the admin server (e.g., "127.0.0.1:9091").
+	// Empty disables it.
+	Addr string
+
+	// TokenFile lists the bearer tokens accepted by the admin server, one
+	// per line.
+	TokenFile string
+}
+
+// DebugCaptureServeConfig holds the settings of debug capture.
+type DebugCaptureServeConfig struct {
+	// Size is the number of tool calls kept. 0 disables debug capture.
+	Size int
+
+	// MaxBytes caps the captured arguments and result of each call.
+	MaxBytes int
 }
 
 // MetricsServeConfig holds configuration for the metrics server.
@@ -63,6 +426,17 @@ type MetricsServeConfig struct {
 	Addr string
 }
 
+// CompressionServeConfig holds the response compression settings of the
+// sse and streamable-http transports.
+type CompressionServeConfig struct {
+	// Enabled compresses responses with gzip or deflate when the client
+	// accepts it (default: true)
+	Enabled bool
+
+	// MinSize is the smallest response body, in bytes, that is compressed
+	MinSize int
+}
+
 // CAPIModeConfig holds CAPI federation mode configuration.
 type CAPIModeConfig struct {
 	// Enabled enables CAPI federation mode for multi-cluster operations
@@ -92,6 +466,67 @@ type CAPIModeConfig struct {
 
 	// Privileged access configuration (split-credential model)
 	PrivilegedAccess PrivilegedAccessConfig
+
+	// Sources of workload clusters and their kubeconfigs
+	ClusterSources ClusterSourcesConfig
+
+	// Informer-backed cache for CAPI cluster discovery
+	DiscoveryCache DiscoveryCacheConfig
+
+	// Limit on workload clusters contacted at the same time
+	Concurrency ConcurrencyLimitConfig
+}
+
+// ConcurrencyLimitConfig limits how many workload cluster clients the
+// federation manager creates at the same time. Unset fields use the defaults
+// of federation.DefaultConcurrencyConfig.
+type ConcurrencyLimitConfig struct {
+	// MaxConcurrent is how many workload cluster clients may be created at
+	// the same time. Zero disables the limit.
+	MaxConcurrent *int
+
+	// MaxQueued is how many operations may wait for a slot.
+	MaxQueued *int
+
+	// QueueTimeout is how long an operation waits for a slot.
+	QueueTimeout time.Duration
+}
+
+// DiscoveryCacheConfig configures the CAPI cluster discovery cache, which
+// watches Cluster resources instead of listing them on every request.
+// It requires privileged CAPI discovery.
+type DiscoveryCacheConfig struct {
+	// Enabled enables the discovery cache.
+	Enabled bool
+
+	// ResyncPeriod is how often the informer re-delivers every cached Cluster.
+	ResyncPeriod time.Duration
+
+	// AccessTTL is how long per-user access decisions are cached.
+	AccessTTL time.Duration
+}
+
+// ClusterSourcesConfig configures where the federation manager discovers
+// workload clusters and their kubeconfigs. Sources are asked in field order.
+type ClusterSourcesConfig struct {
+	// CAPIDisabled disables discovery of CAPI Cluster resources and their
+	// kubeconfig secrets, for fleets without Cluster API.
+	CAPIDisabled bool
+
+	// KubeconfigPath is a kubeconfig file or a directory of kubeconfig files.
+	// Every context becomes a cluster named after the context.
+	KubeconfigPath string
+
+	// SecretLabelSelector selects Secrets on the Management Cluster holding
+	// workload cluster kubeconfigs. Empty disables the secret source.
+	SecretLabelSelector string
+
+	// SecretNamespace limits the secret source to one namespace.
+	SecretNamespace string
+
+	// SecretClusterNameLabel is the Secret label whose value names the cluster.
+	// Default: the Secret name without a "-kubeconfig" suffix.
+	SecretClusterNameLabel string
 }
 
 // WorkloadClusterAuthConfig configures how mcp-kubernetes authenticates to workload clusters.
@@ -143,6 +578,38 @@ type WorkloadClusterAuthConfig struct {
 	// Configured via Helm values (native YAML map) or the WC_GROUP_MAPPINGS
 	// environment variable (JSON-serialized by the Helm template).
 	GroupMappings map[string]string
+
+	// GroupStripPrefixes are removed from the start of OIDC groups (first
+	// match wins) before they are filtered and mapped, e.g. "oidc:".
+	// Configured via the WC_GROUP_STRIP_PREFIXES environment variable (JSON array).
+	GroupStripPrefixes []string
+
+	// GroupAllowPatterns is a regex allowlist applied to OIDC groups after
+	// prefix stripping. Groups that do not fully match any pattern are not
+	// sent as Impersonate-Group headers, which keeps headers within size
+	// limits and excludes IdP-internal groups. Empty means all groups are kept.
+	// Configured via the WC_GROUP_ALLOW_PATTERNS environment variable (JSON array).
+	GroupAllowPatterns []string
+
+	// TokenExchange configures RFC 8693 token exchange for workload clusters
+	// whose API servers expect a cluster-specific token audience. Clusters opt
+	// in with the mcp.giantswarm.io/token-exchange-audience annotation on their
+	// CAPI Cluster resource. Only used in "sso-passthrough" mode.
+	TokenExchange TokenExchangeServeConfig
+}
+
+// TokenExchangeServeConfig holds the IdP token endpoint and client used to
+// exchange user tokens for cluster-specific audiences.
+type TokenExchangeServeConfig struct {
+	// URL is the IdP's token endpoint. Token exchange is disabled when empty.
+	URL string
+
+	// ClientID and ClientSecret authenticate to the token endpoint.
+	ClientID     string
+	ClientSecret string
+
+	// ConnectorID is the Dex connector that validates the exchanged token.
+	ConnectorID string
 }
 
 // PrivilegedAccessConfig configures the split-credential model for privileged access.
@@ -228,6 +695,7 @@ type OAuthServeConfig struct {
 	AllowPrivateURLs                   bool // skip private IP validation for internal deployments
 	MaxClientsPerIP                    int
 	EncryptionKey                      string
+	PreviousEncryptionKey              string // set during a key rotation; see rotate-encryption-key
 	TLSCertFile                        string
 	TLSKeyFile                         string
 
@@ -548,3 +1016,286 @@ func validateOAuthBaseURL(baseURL string) error {
 	// HTTPS is always allowed (including localhost with HTTPS)
 	return nil
 }
+
+// HTTPAuthServeConfig holds the non-OAuth authentication settings for the
+// streamable-http and SSE transports.
+type HTTPAuthServeConfig struct {
+	// Mode is "none", "static-token" or "tokenreview".
+	Mode string
+
+	// TokenFile lists the accepted tokens for static-token mode, one per line.
+	TokenFile string
+
+	// Audiences, when set, restricts tokenreview mode to tokens issued for
+	// one of these audiences.
+	Audiences []string
+}
+
+// loadOAuthEnvVars loads the OAuth credentials and trusted audiences from
+// environment variables when they were not set with flags (or the --config
+// file).
+func loadOAuthEnvVars(config *OAuthServeConfig) {
+	loadEnvIfEmpty(&config.GoogleClientID, "GOOGLE_CLIENT_ID")
+	loadEnvIfEmpty(&config.GoogleClientSecret, "GOOGLE_CLIENT_SECRET")
+	loadEnvIfEmpty(&config.DexIssuerURL, "DEX_ISSUER_URL")
+	loadEnvIfEmpty(&config.DexClientID, "DEX_CLIENT_ID")
+	loadEnvIfEmpty(&config.DexClientSecret, "DEX_CLIENT_SECRET")
+	loadEnvIfEmpty(&config.DexConnectorID, "DEX_CONNECTOR_ID")
+	loadEnvIfEmpty(&config.DexCAFile, "DEX_CA_FILE")
+	loadEnvIfEmpty(&config.DexKubernetesAuthenticatorClientID, "DEX_K8S_AUTHENTICATOR_CLIENT_ID")
+	loadEnvIfEmpty(&config.EncryptionKey, "OAUTH_ENCRYPTION_KEY")
+	loadEnvIfEmpty(&config.PreviousEncryptionKey, "OAUTH_ENCRYPTION_KEY_PREVIOUS")
+
+	// Load trusted audiences from environment variable if not set via flag
+	if len(config.TrustedAudiences) == 0 {
+		if envVal := os.Getenv("OAUTH_TRUSTED_AUDIENCES"); envVal != "" {
+			config.TrustedAudiences = splitAndTrimAudiences(envVal)
+		}
+	}
+}
+
+// validateServeConfig checks config for settings the server would refuse at
+// startup, without connecting to anything. serve runs it before starting and
+// "config validate" runs it on its own.
+func validateServeConfig(config ServeConfig) error {
+	switch config.Transport {
+	case transportStdio, transportSSE, transportStreamableHTTP:
+	default:
+		return fmt.Errorf("unsupported transport type: %s (supported: stdio, sse, streamable-http)", config.Transport)
+	}
+	if err := tools.ValidateProfile(config.ToolProfile); err != nil {
+		return err
+	}
+	if err := validateAdminConfig(config.Admin); err != nil {
+		return err
+	}
+	if err := validateLoggingConfig(config.Logging); err != nil {
+		return err
+	}
+	if err := validateDebugCaptureConfig(config.DebugCapture); err != nil {
+		return err
+	}
+	if _, _, err := outputClusterOverrides(config); err != nil {
+		return err
+	}
+	if _, err := outputKindProfiles(config); err != nil {
+		return err
+	}
+	if len(config.KubeconfigContexts) > 0 && config.InCluster {
+		return fmt.Errorf("--kubeconfig-contexts cannot be used with --in-cluster")
+	}
+
+	// Validate downstream OAuth configuration
+	if config.DownstreamOAuth {
+		if !config.OAuth.Enabled {
+			return fmt.Errorf("--downstream-oauth requires --enable-oauth to be set")
+		}
+		if !config.InCluster {
+			return fmt.Errorf("--downstream-oauth requires --in-cluster mode (must be running inside a Kubernetes cluster)")
+		}
+	}
+	if err := validateHTTPAuthConfig(config.HTTPAuth, config.Transport, config.OAuth.Enabled); err != nil {
+		return err
+	}
+	if config.OAuth.Enabled && config.Transport != transportStdio {
+		if err := validateOAuthConfig(config.OAuth); err != nil {
+			return err
+		}
+		if config.OAuth.EncryptionKey != "" {
+			if _, err := decodeEncryptionKey(config.OAuth.EncryptionKey); err != nil {
+				return fmt.Errorf("OAuth encryption key: %w", err)
+			}
+		}
+	}
+
+	if err := validatePIIPatterns(config.Output.PIIPatterns); err != nil {
+		return err
+	}
+	if err := validateCredentialPatterns(config.Output.CredentialPatterns); err != nil {
+		return err
+	}
+	if err := output.ValidateSecretRedaction(config.Output.SecretRedaction); err != nil {
+		return err
+	}
+	if config.Output.TokenWarningThreshold < 0 {
+		return fmt.Errorf("--token-warning-threshold must not be negative, got %d", config.Output.TokenWarningThreshold)
+	}
+	if config.ConfirmDestructive && config.ConfirmationTTL <= 0 {
+		return fmt.Errorf("--confirmation-ttl must be positive, got %s", config.ConfirmationTTL)
+	}
+	if config.EnablePlans && config.PlanTTL <= 0 {
+		return fmt.Errorf("--plan-ttl must be positive, got %s", config.PlanTTL)
+	}
+
+	// CAPI mode requires downstream OAuth and in-cluster mode
+	if config.CAPIMode.Enabled {
+		if !config.DownstreamOAuth {
+			return fmt.Errorf("CAPI mode requires downstream OAuth to be enabled (--downstream-oauth)")
+		}
+		if !config.InCluster {
+			return fmt.Errorf("CAPI mode requires in-cluster mode (--in-cluster)")
+		}
+	}
+	return nil
+}
+
+// validateLoggingConfig checks the server log settings.
+func validateLoggingConfig(cfg LoggingServeConfig) error {
+	switch cfg.Format {
+	case "", logFormatAuto, logFormatJSON, logFormatText:
+	default:
+		return fmt.Errorf("unsupported log format: %s (supported: %s, %s, %s)", cfg.Format, logFormatAuto, logFormatJSON, logFormatText)
+	}
+	if cfg.MaxBytes < 0 {
+		return fmt.Errorf("--log-max-bytes must not be negative, got %d", cfg.MaxBytes)
+	}
+	if cfg.RotateInterval < 0 {
+		return fmt.Errorf("--log-rotate-interval must not be negative, got %s", cfg.RotateInterval)
+	}
+	if cfg.MaxBackups < 0 {
+		return fmt.Errorf("--log-max-backups must not be negative, got %d", cfg.MaxBackups)
+	}
+	return nil
+}
+
+// outputClusterOverrides reads the per-cluster output overrides from the
+// OUTPUT_CLUSTER_OVERRIDES and OUTPUT_CLUSTER_TYPE_OVERRIDES environment
+// variables, JSON objects keyed by cluster name and by cluster type.
+func outputClusterOverrides(config ServeConfig) (byCluster, byType map[string]server.OutputOverride, err error) {
+	for _, v := range []struct {
+		env       string
+		overrides *map[string]server.OutputOverride
+	}{
+		{"OUTPUT_CLUSTER_OVERRIDES", &byCluster},
+		{"OUTPUT_CLUSTER_TYPE_OVERRIDES", &byType},
+	} {
+		value := config.getenv(v.env)
+		if value == "" {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(v.overrides); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", v.env, err)
+		}
+		for name, override := range *v.overrides {
+			if err := validateOutputOverride(override); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %s: %w", v.env, name, err)
+			}
+		}
+	}
+	for clusterType := range byType {
+		if !slices.Contains(outputClusterTypes, clusterType) {
+			return nil, nil, fmt.Errorf("invalid OUTPUT_CLUSTER_TYPE_OVERRIDES: unknown cluster type %q (supported: %s)",
+				clusterType, strings.Join(outputClusterTypes, ", "))
+		}
+	}
+	return byCluster, byType, nil
+}
+
+// outputKindProfiles reads the per-kind slimming profiles from the
+// OUTPUT_KIND_PROFILES environment variable, a JSON object mapping "Kind" or
+// "Kind.group" to the field paths kept in list responses.
+func outputKindProfiles(config ServeConfig) (map[string][]string, error) {
+	value := config.getenv("OUTPUT_KIND_PROFILES")
+	if value == "" {
+		return nil, nil
+	}
+	var profiles map[string][]string
+	if err := json.Unmarshal([]byte(value), &profiles); err != nil {
+		return nil, fmt.Errorf("invalid OUTPUT_KIND_PROFILES: %w", err)
+	}
+	if err := output.ValidateKindProfiles(profiles); err != nil {
+		return nil, fmt.Errorf("invalid OUTPUT_KIND_PROFILES: %w", err)
+	}
+	return profiles, nil
+}
+
+// outputClusterTypes are the cluster types output overrides may target.
+var outputClusterTypes = []string{
+	string(instrumentation.ClusterTypeProduction),
+	string(instrumentation.ClusterTypeStaging),
+	string(instrumentation.ClusterTypeDevelopment),
+	string(instrumentation.ClusterTypeCICD),
+	string(instrumentation.ClusterTypeOperations),
+	string(instrumentation.ClusterTypeManagement),
+	string(instrumentation.ClusterTypeOther),
+}
+
+// validateOutputOverride checks the limits of an output override.
+func validateOutputOverride(o server.OutputOverride) error {
+	if o.MaxItems != nil && (*o.MaxItems < 1 || *o.MaxItems > output.AbsoluteMaxItems) {
+		return fmt.Errorf("maxItems must be between 1 and %d, got %d", output.AbsoluteMaxItems, *o.MaxItems)
+	}
+	if o.SummaryThreshold != nil && *o.SummaryThreshold < 1 {
+		return fmt.Errorf("summaryThreshold must be positive, got %d", *o.SummaryThreshold)
+	}
+	return nil
+}
+
+// validateOAuthConfig checks the OAuth settings of an HTTP transport.
+func validateOAuthConfig(config OAuthServeConfig) error {
+	if config.BaseURL == "" {
+		return fmt.Errorf("--oauth-base-url is required when --enable-oauth is set")
+	}
+	// Validate OAuth base URL (allows localhost for development, but requires HTTPS for production)
+	if err := validateOAuthBaseURL(config.BaseURL); err != nil {
+		return err
+	}
+
+	// Validate TLS configuration - both cert and key must be provided together
+	if (config.TLSCertFile != "" && config.TLSKeyFile == "") ||
+		(config.TLSCertFile == "" && config.TLSKeyFile != "") {
+		return fmt.Errorf("both --tls-cert-file and --tls-key-file must be provided together for HTTPS")
+	}
+
+	// Provider-specific validation
+	switch config.Provider {
+	case OAuthProviderDex:
+		if config.DexIssuerURL == "" {
+			return fmt.Errorf("dex issuer URL is required when using Dex provider (--dex-issuer-url or DEX_ISSUER_URL)")
+		}
+		// Validate Dex issuer URL is HTTPS and not vulnerable to SSRF
+		if err := validateSecureURL(config.DexIssuerURL, "Dex issuer URL", config.AllowPrivateURLs); err != nil {
+			return err
+		}
+		if config.DexClientID == "" {
+			return fmt.Errorf("dex client ID is required when using Dex provider (--dex-client-id or DEX_CLIENT_ID)")
+		}
+		if config.DexClientSecret == "" {
+			return fmt.Errorf("dex client secret is required when using Dex provider (--dex-client-secret or DEX_CLIENT_SECRET)")
+		}
+		// Validate Kubernetes authenticator client ID format (if provided)
+		if err := validateOAuthClientID(config.DexKubernetesAuthenticatorClientID, "Dex Kubernetes authenticator client ID"); err != nil {
+			return err
+		}
+	case OAuthProviderGoogle:
+		if config.GoogleClientID == "" {
+			return fmt.Errorf("google client ID is required when using Google provider (--google-client-id or GOOGLE_CLIENT_ID)")
+		}
+		if config.GoogleClientSecret == "" {
+			return fmt.Errorf("google client secret is required when using Google provider (--google-client-secret or GOOGLE_CLIENT_SECRET)")
+		}
+	default:
+		return fmt.Errorf("unsupported OAuth provider: %s (supported: %s, %s)", config.Provider, OAuthProviderDex, OAuthProviderGoogle)
+	}
+
+	// Validate trusted schemes if configured (RFC 3986 compliance)
+	if err := validateTrustedSchemes(config.TrustedPublicRegistrationSchemes); err != nil {
+		return fmt.Errorf("invalid trusted public registration scheme: %w", err)
+	}
+
+	// Registration token is required unless:
+	// 1. Public registration is enabled (anyone can register), OR
+	// 2. Trusted schemes are configured (Cursor/VSCode can register without token), OR
+	// 3. CIMD is enabled (clients use HTTPS URLs as client IDs)
+	hasTrustedSchemes := len(config.TrustedPublicRegistrationSchemes) > 0
+	if !config.AllowPublicRegistration && config.RegistrationToken == "" && !hasTrustedSchemes && !config.EnableCIMD {
+		return fmt.Errorf("--registration-token is required when public registration is disabled, " +
+			"no trusted schemes are configured, and CIMD is disabled. " +
+			"Either set --registration-token, enable --allow-public-registration, " +
+			"configure --trusted-public-registration-schemes, or enable --enable-cimd")
+	}
+
+	return nil
+}
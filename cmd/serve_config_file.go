@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// configFileKey is a setting of the serve configuration file (--config).
+// A setting either sets a serve flag or, for settings only available as
+// environment variables, stands in for one. Precedence is flags, then
+// environment variables, then the file, then defaults: a setting is ignored
+// when its flag was passed or its environment variable is set.
+type configFileKey struct {
+	// flag is the serve flag the setting sets
+	flag string
+
+	// env is the environment variable that takes precedence over the
+	// setting; for settings without a flag it is the variable the setting
+	// stands in for
+	env string
+
+	// json marks settings whose environment variable holds JSON
+	json bool
+}
+
+// configFileKeys are the settings of the serve configuration file, by their
+// dotted path in the file.
+var configFileKeys = map[string]configFileKey{
+	"debug": {flag: "debug"},
+
+	// Server logs
+	"logging.file":           {flag: "log-file"},
+	"logging.format":         {flag: "log-format"},
+	"logging.maxBytes":       {flag: "log-max-bytes"},
+	"logging.rotateInterval": {flag: "log-rotate-interval"},
+	"logging.maxBackups":     {flag: "log-max-backups"},
+
+	// Debug capture of tool calls
+	"debugCapture.size":     {flag: "debug-capture"},
+	"debugCapture.maxBytes": {flag: "debug-capture-max-bytes"},
+
+	// Transport
+	"transport.type":               {flag: "transport"},
+	"transport.httpAddr":           {flag: "http-addr"},
+	"transport.sseEndpoint":        {flag: "sse-endpoint"},
+	"transport.messageEndpoint":    {flag: "message-endpoint"},
+	"transport.httpEndpoint":       {flag: "http-endpoint"},
+	"transport.compression":        {flag: "compression"},
+	"transport.compressionMinSize": {flag: "compression-min-size"},
+	"transport.allowedOrigins":     {env: "ALLOWED_ORIGINS"},
+	"transport.enableHSTS":         {env: "ENABLE_HSTS"},
+	"transport.tls.certFile":       {flag: "tls-cert-file", env: "TLS_CERT_FILE"},
+	"transport.tls.keyFile":        {flag: "tls-key-file", env: "TLS_KEY_FILE"},
+	"transport.auth.mode":          {flag: "auth-mode", env: "MCP_AUTH_MODE"},
+	"transport.auth.tokenFile":     {flag: "auth-token-file", env: "MCP_AUTH_TOKEN_FILE"},
+	"transport.auth.audiences":     {flag: "auth-token-audiences", env: "MCP_AUTH_TOKEN_AUDIENCES"},
+
+	// Kubernetes client
+	"kubernetes.inCluster":                   {flag: "in-cluster"},
+	"kubernetes.kubeconfigContexts":          {flag: "kubeconfig-contexts"},
+	"kubernetes.nonDestructive":              {flag: "non-destructive"},
+	"kubernetes.dryRun":                      {flag: "dry-run"},
+	"kubernetes.protobuf":                    {flag: "protobuf"},
+	"kubernetes.qpsLimit":                    {flag: "qps-limit"},
+	"kubernetes.burstLimit":                  {flag: "burst-limit"},
+	"kubernetes.userQPSLimit":                {flag: "user-qps-limit"},
+	"kubernetes.userBurstLimit":              {flag: "user-burst-limit"},
+	"kubernetes.requestTimeout":              {flag: "request-timeout"},
+	"kubernetes.discoveryTimeout":            {flag: "discovery-timeout"},
+	"kubernetes.discoveryMinInterval":        {flag: "discovery-min-interval"},
+	"kubernetes.retries":                     {flag: "api-retries"},
+	"kubernetes.retryBackoff":                {flag: "api-retry-backoff"},
+	"kubernetes.retryMaxBackoff":             {flag: "api-retry-max-backoff"},
+	"kubernetes.listCache.resources":         {flag: "list-cache-resources"},
+	"kubernetes.listCache.resync":            {flag: "list-cache-resync"},
+	"kubernetes.execPlugins.allowlist":       {flag: "exec-plugin-allowlist"},
+	"kubernetes.execPlugins.timeout":         {flag: "exec-plugin-timeout"},
+	"kubernetes.execPlugins.maxOutput":       {flag: "exec-plugin-max-output"},
+	"kubernetes.impersonationExtras.static":  {flag: "impersonation-extras"},
+	"kubernetes.impersonationExtras.meta":    {flag: "impersonation-extras-meta"},
+	"kubernetes.impersonationExtras.client":  {flag: "impersonation-extras-client-info"},
+	"kubernetes.impersonationExtras.session": {flag: "impersonation-extras-session-id"},
+
+	// OAuth
+	"oauth.enabled":                             {flag: "enable-oauth"},
+	"oauth.baseURL":                             {flag: "oauth-base-url"},
+	"oauth.provider":                            {flag: "oauth-provider"},
+	"oauth.downstream":                          {flag: "downstream-oauth"},
+	"oauth.google.clientID":                     {flag: "google-client-id", env: "GOOGLE_CLIENT_ID"},
+	"oauth.google.clientSecret":                 {flag: "google-client-secret", env: "GOOGLE_CLIENT_SECRET"},
+	"oauth.dex.issuerURL":                       {flag: "dex-issuer-url", env: "DEX_ISSUER_URL"},
+	"oauth.dex.clientID":                        {flag: "dex-client-id", env: "DEX_CLIENT_ID"},
+	"oauth.dex.clientSecret":                    {flag: "dex-client-secret", env: "DEX_CLIENT_SECRET"},
+	"oauth.dex.connectorID":                     {flag: "dex-connector-id", env: "DEX_CONNECTOR_ID"},
+	"oauth.dex.caFile":                          {flag: "dex-ca-file", env: "DEX_CA_FILE"},
+	"oauth.dex.kubernetesAuthenticatorClientID": {flag: "dex-k8s-authenticator-client-id", env: "DEX_K8S_AUTHENTICATOR_CLIENT_ID"},
+	"oauth.disableStreaming":                    {flag: "disable-streaming"},
+	"oauth.registrationToken":                   {flag: "registration-token"},
+	"oauth.allowPublicRegistration":             {flag: "allow-public-registration"},
+	"oauth.allowInsecureAuthWithoutState":       {flag: "allow-insecure-auth-without-state"},
+	"oauth.allowPrivateURLs":                    {flag: "allow-private-oauth-urls"},
+	"oauth.maxClientsPerIP":                     {flag: "max-clients-per-ip"},
+	"oauth.encryptionKey":                       {flag: "oauth-encryption-key", env: "OAUTH_ENCRYPTION_KEY"},
+	"oauth.previousEncryptionKey":               {flag: "oauth-encryption-key-previous", env: "OAUTH_ENCRYPTION_KEY_PREVIOUS"},
+	"oauth.storage":                             {flag: "oauth-storage-type", env: "OAUTH_STORAGE_TYPE"},
+	"oauth.trustedPublicRegistrationSchemes":    {flag: "trusted-public-registration-schemes"},
+	"oauth.disableStrictSchemeMatching":         {flag: "disable-strict-scheme-matching"},
+	"oauth.cimd.enabled":                        {flag: "enable-cimd", env: "ENABLE_CIMD"},
+	"oauth.cimd.allowPrivateIPs":                {flag: "cimd-allow-private-ips", env: "CIMD_ALLOW_PRIVATE_IPS"},
+	"oauth.sso.trustedAudiences":                {flag: "oauth-trusted-audiences", env: "OAUTH_TRUSTED_AUDIENCES"},
+	"oauth.sso.allowPrivateIPs":                 {flag: "sso-allow-private-ips", env: "SSO_ALLOW_PRIVATE_IPS"},
+	"oauth.trustedIssuers":                      {env: "OAUTH_TRUSTED_ISSUERS", json: true},
+
+	"oauth.redirectURISecurity.disableProductionMode":              {flag: "disable-production-mode"},
+	"oauth.redirectURISecurity.allowLocalhost":                     {flag: "allow-localhost-redirect-uris"},
+	"oauth.redirectURISecurity.allowPrivateIP":                     {flag: "allow-private-ip-redirect-uris"},
+	"oauth.redirectURISecurity.allowLinkLocal":                     {flag: "allow-link-local-redirect-uris"},
+	"oauth.redirectURISecurity.disableDNSValidation":               {flag: "disable-dns-validation"},
+	"oauth.redirectURISecurity.disableDNSValidationStrict":         {flag: "disable-dns-validation-strict"},
+	"oauth.redirectURISecurity.disableAuthorizationTimeValidation": {flag: "disable-authorization-time-validation"},
+
+	// Valkey, shared by OAuth storage, rate limiting, sessions, the undo
+	// journal and preferences
+	"valkey.url":       {flag: "valkey-url", env: "VALKEY_URL"},
+	"valkey.password":  {flag: "valkey-password", env: "VALKEY_PASSWORD"},
+	"valkey.tls":       {flag: "valkey-tls", env: "VALKEY_TLS_ENABLED"},
+	"valkey.keyPrefix": {flag: "valkey-key-prefix", env: "VALKEY_KEY_PREFIX"},
+	"valkey.db":        {flag: "valkey-db", env: "VALKEY_DB"},
+
+	// Federation (CAPI mode)
+	"federation.enabled":                                        {env: "CAPI_MODE_ENABLED"},
+	"federation.workloadClusterAuth.mode":                       {env: "WC_AUTH_MODE"},
+	"federation.workloadClusterAuth.caConfigMapSuffix":          {env: "WC_CA_CONFIGMAP_SUFFIX"},
+	"federation.workloadClusterAuth.disableCaching":             {env: "WC_DISABLE_CACHING"},
+	"federation.workloadClusterAuth.tokenExchange.url":          {env: "WC_TOKEN_EXCHANGE_URL"},
+	"federation.workloadClusterAuth.tokenExchange.clientID":     {env: "WC_TOKEN_EXCHANGE_CLIENT_ID"},
+	"federation.workloadClusterAuth.tokenExchange.clientSecret": {env: "WC_TOKEN_EXCHANGE_CLIENT_SECRET"},
+	"federation.workloadClusterAuth.tokenExchange.connectorID":  {env: "WC_TOKEN_EXCHANGE_CONNECTOR_ID"},
+	"federation.workloadClusterAuth.groupMappings":              {env: "WC_GROUP_MAPPINGS", json: true},
+	"federation.workloadClusterAuth.groupStripPrefixes":         {env: "WC_GROUP_STRIP_PREFIXES", json: true},
+	"federation.workloadClusterAuth.groupAllowPatterns":         {env: "WC_GROUP_ALLOW_PATTERNS", json: true},
+	"federation.privilegedAccess.enabled":                       {env: "PRIVILEGED_ACCESS_ENABLED"},
+	"federation.privilegedAccess.strict":                        {env: "PRIVILEGED_ACCESS_STRICT"},
+	"federation.privilegedAccess.capiDiscovery":                 {env: "PRIVILEGED_CAPI_DISCOVERY"},
+	"federation.privilegedAccess.ratePerSecond":                 {env: "PRIVILEGED_ACCESS_RATE_PER_SECOND"},
+	"federation.privilegedAccess.rateBurst":                     {env: "PRIVILEGED_ACCESS_RATE_BURST"},
+	"federation.clusterSources.capi":                            {env: "CLUSTER_SOURCE_CAPI_ENABLED"},
+	"federation.clusterSources.kubeconfig":                      {env: "CLUSTER_SOURCE_KUBECONFIG"},
+	"federation.clusterSources.secretSelector":                  {env: "CLUSTER_SOURCE_SECRET_SELECTOR"},
+	"federation.clusterSources.secretNamespace":                 {env: "CLUSTER_SOURCE_SECRET_NAMESPACE"},
+	"federation.clusterSources.secretClusterNameLabel":          {env: "CLUSTER_SOURCE_SECRET_CLUSTER_NAME_LABEL"},
+	"federation.concurrency.maxConcurrentClusters":              {env: "FEDERATION_MAX_CONCURRENT_CLUSTERS"},
+	"federation.concurrency.maxQueuedOperations":                {env: "FEDERATION_MAX_QUEUED_CLUSTER_OPERATIONS"},
+	"federation.concurrency.queueTimeout":                       {env: "FEDERATION_CLUSTER_QUEUE_TIMEOUT"},
+	"federation.discoveryCache.enabled":                         {env: "DISCOVERY_CACHE_ENABLED"},
+	"federation.discoveryCache.resyncPeriod":                    {env: "DISCOVERY_CACHE_RESYNC_PERIOD"},
+	"federation.discoveryCache.accessTTL":                       {env: "DISCOVERY_CACHE_ACCESS_TTL"},
+	"federation.clientCache.ttl":                                {env: "CLIENT_CACHE_TTL"},
+	"federation.clientCache.maxEntries":                         {env: "CLIENT_CACHE_MAX_ENTRIES"},
+	"federation.clientCache.cleanupInterval":                    {env: "CLIENT_CACHE_CLEANUP_INTERVAL"},
+	"federation.oauthTokenLifetime":                             {env: "OAUTH_TOKEN_LIFETIME"},
+	"federation.connectivity.timeout":                           {env: "CONNECTIVITY_TIMEOUT"},
+	"federation.connectivity.retryAttempts":                     {env: "CONNECTIVITY_RETRY_ATTEMPTS"},
+	"federation.connectivity.retryBackoff":                      {env: "CONNECTIVITY_RETRY_BACKOFF"},
+	"federation.connectivity.requestTimeout":                    {env: "CONNECTIVITY_REQUEST_TIMEOUT"},
+	"federation.connectivity.qps":                               {env: "CONNECTIVITY_QPS"},
+	"federation.connectivity.burst":                             {env: "CONNECTIVITY_BURST"},
+
+	// Tool output
+	"output.scrubPII":              {flag: "scrub-pii"},
+	"output.piiPatterns":           {flag: "pii-patterns"},
+	"output.scrubCredentials":      {flag: "scrub-credentials"},
+	"output.credentialPatterns":    {flag: "credential-patterns"},
+	"output.secretRedaction":       {flag: "secret-redaction"},
+	"output.tokenWarningThreshold": {flag: "token-warning-threshold"},
+	"output.kindProfiles":          {env: "OUTPUT_KIND_PROFILES", json: true},
+	"output.clusterOverrides":      {env: "OUTPUT_CLUSTER_OVERRIDES", json: true},
+	"output.clusterTypeOverrides":  {env: "OUTPUT_CLUSTER_TYPE_OVERRIDES", json: true},
+	"output.copyPaths":             {flag: "copy-paths"},
+	"output.copyMaxBytes":          {flag: "copy-max-bytes"},
+
+	// Security policy
+	"security.profile":                       {flag: "profile"},
+	"security.readOnlyGroups":                {flag: "read-only-groups"},
+	"security.policy.file":                   {flag: "policy-file"},
+	"security.policy.reloadInterval":         {flag: "policy-reload-interval"},
+	"security.opa.url":                       {flag: "opa-url"},
+	"security.opa.timeout":                   {flag: "opa-timeout"},
+	"security.opa.failOpen":                  {flag: "opa-fail-open"},
+	"security.restrictedNamespaces.selector": {flag: "restricted-namespace-selector"},
+	"security.restrictedNamespaces.cacheTTL": {flag: "restricted-namespace-cache-ttl"},
+	"security.restrictedNamespaces.failOpen": {flag: "restricted-namespace-fail-open"},
+	"security.readRestrictions":              {flag: "read-restrictions"},
+	"security.writeNamespaces":               {flag: "write-namespaces"},
+	"security.allowClusterScopedWrites":      {flag: "allow-cluster-scoped-writes"},
+	"security.secretWrites":                  {flag: "secret-writes"},
+	"security.confirmDestructive":            {flag: "confirm-destructive"},
+	"security.confirmationTTL":               {flag: "confirmation-ttl"},
+	"security.allowImpersonateAs":            {flag: "allow-impersonate-as"},
+	"security.debugImages":                   {flag: "debug-images"},
+
+	// Tools
+	"tools.timeout":             {flag: "tool-timeout"},
+	"tools.timeouts":            {flag: "tool-timeouts"},
+	"tools.rateLimit.rate":      {flag: "tool-rate-limit", env: "TOOL_RATE_LIMIT"},
+	"tools.rateLimit.burst":     {flag: "tool-rate-limit-burst", env: "TOOL_RATE_LIMIT_BURST"},
+	"tools.rateLimit.perTool":   {flag: "tool-rate-limit-per-tool", env: "TOOL_RATE_LIMIT_PER_TOOL"},
+	"tools.rateLimit.backend":   {flag: "tool-rate-limit-backend", env: "TOOL_RATE_LIMIT_BACKEND"},
+	"tools.plans.enabled":       {flag: "enable-plans"},
+	"tools.plans.ttl":           {flag: "plan-ttl"},
+	"tools.undoJournal.backend": {flag: "undo-journal"},
+	"tools.undoJournal.size":    {flag: "undo-journal-size"},
+	"tools.undoJournal.ttl":     {flag: "undo-journal-ttl"},
+	"tools.preferences":         {flag: "preferences"},
+	"tools.sessions.replicaID":  {flag: "replica-id"},
+	"tools.sessions.backend":    {flag: "session-backend"},
+
+	// Admin and metrics servers
+	"admin.addr":      {flag: "admin-addr"},
+	"admin.tokenFile": {flag: "admin-token-file"},
+	"metrics.enabled": {flag: "metrics-enabled"},
+	"metrics.addr":    {flag: "metrics-addr"},
+
+	// Recorded fixtures
+	"fixtures.dir":    {flag: "fixture-dir"},
+	"fixtures.record": {flag: "record-fixtures"},
+}
+
+// applyConfigFile applies the serve configuration file at path to flags. It
+// returns the values of the settings without a flag, by the environment
+// variable they stand in for; see ServeConfig.getenv.
+func applyConfigFile(flags *pflag.FlagSet, path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the operator's --config flag
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	settings := make(map[string]any)
+	if err := flattenConfigFile("", doc, settings); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	env := make(map[string]string)
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		setting, value := configFileKeys[key], settings[key]
+		if setting.env != "" {
+			if _, ok := os.LookupEnv(setting.env); ok {
+				continue
+			}
+		}
+		if setting.flag == "" {
+			s, err := configEnvValue(value, setting.json)
+			if err != nil {
+				return nil, fmt.Errorf("invalid config file %s: %s: %w", path, key, err)
+			}
+			env[setting.env] = s
+			continue
+		}
+		if flags.Changed(setting.flag) {
+			continue
+		}
+		if err := setConfigFlag(flags.Lookup(setting.flag), value); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %s: %w", path, key, err)
+		}
+	}
+	return env, nil
+}
+
+// flattenConfigFile collects the settings of doc into settings by their
+// dotted path. Unknown keys are rejected, so that typos do not silently
+// leave a setting at its default.
+func flattenConfigFile(prefix string, doc map[string]any, settings map[string]any) error {
+	for name, value := range doc {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if _, ok := configFileKeys[key]; ok {
+			if value != nil {
+				settings[key] = value
+			}
+			continue
+		}
+		section, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+		if err := flattenConfigFile(key, section, settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setConfigFlag sets flag to a value of the configuration file, without
+// marking it as changed on the command line.
+func setConfigFlag(flag *pflag.Flag, value any) error {
+	switch v := value.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := configScalar(item)
+			if err != nil {
+				return err
+			}
+			items[i] = s
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			return slice.Replace(items)
+		}
+		return flag.Value.Set(strings.Join(items, ","))
+	case map[string]any:
+		pairs := make([]string, 0, len(v))
+		for k, item := range v {
+			s, err := configScalar(item)
+			if err != nil {
+				return err
+			}
+			pairs = append(pairs, k+"="+s)
+		}
+		slices.Sort(pairs)
+		return flag.Value.Set(strings.Join(pairs, ","))
+	default:
+		s, err := configScalar(v)
+		if err != nil {
+			return err
+		}
+		return flag.Value.Set(s)
+	}
+}
+
+// configEnvValue formats a value of the configuration file as the
+// environment variable it stands in for holds it: JSON, a comma-separated
+// list or a plain value.
+func configEnvValue(value any, asJSON bool) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	if asJSON {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	if list, ok := value.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			s, err := configScalar(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	}
+	return configScalar(value)
+}
+
+// configScalar formats a scalar value of the configuration file.
+func configScalar(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("expected a string, number or boolean, got %T", value)
+	}
+}
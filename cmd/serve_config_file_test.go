@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a serve configuration file and returns its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// runServeCommand executes the serve flags and environment handling with
+// args and returns the resulting configuration.
+func runServeCommand(t *testing.T, args ...string) (ServeConfig, error) {
+	t.Helper()
+	var got ServeConfig
+	cmd := newServeCommand(func(_ *cobra.Command, config ServeConfig) error {
+		got = config
+		return nil
+	})
+	cmd.SetArgs(args)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+	return got, err
+}
+
+func TestConfigFile_SetsFlags(t *testing.T) {
+	path := writeConfigFile(t, `
+transport:
+  type: streamable-http
+kubernetes:
+  qpsLimit: 50
+  kubeconfigContexts: [dev, prod]
+security:
+  profile: read-only
+tools:
+  timeouts:
+    kubernetes_logs: 5m
+`)
+
+	config, err := runServeCommand(t, "--config", path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "streamable-http", config.Transport)
+	assert.Equal(t, float32(50), config.QPSLimit)
+	assert.Equal(t, []string{"dev", "prod"}, config.KubeconfigContexts)
+	assert.Equal(t, "read-only", config.ToolProfile)
+	assert.Equal(t, 5*time.Minute, config.Timeouts.Tools["kubernetes_logs"])
+}
+
+func TestConfigFile_Precedence(t *testing.T) {
+	path := writeConfigFile(t, `
+kubernetes:
+  qpsLimit: 50
+tools:
+  rateLimit:
+    rate: 3
+    burst: 7
+`)
+	t.Setenv("TOOL_RATE_LIMIT", "5")
+
+	config, err := runServeCommand(t, "--config", path, "--qps-limit", "10")
+	require.NoError(t, err)
+
+	assert.Equal(t, float32(10), config.QPSLimit, "flag wins over the file")
+	assert.Equal(t, float64(5), config.RateLimit.Rate, "environment wins over the file")
+	assert.Equal(t, 7, config.RateLimit.Burst, "file wins over the default")
+}
+
+func TestConfigFile_EnvOnlySettings(t *testing.T) {
+	path := writeConfigFile(t, `
+transport:
+  allowedOrigins: [https://a.example.com, https://b.example.com]
+  enableHSTS: true
+federation:
+  workloadClusterAuth:
+    groupMappings:
+      platform-admins: system:masters
+`)
+	t.Setenv("ENABLE_HSTS", "false")
+
+	config, err := runServeCommand(t, "--config", path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://a.example.com,https://b.example.com", config.getenv("ALLOWED_ORIGINS"))
+	assert.Equal(t, "false", config.getenv("ENABLE_HSTS"), "environment wins over the file")
+	assert.JSONEq(t, `{"platform-admins":"system:masters"}`, config.getenv("WC_GROUP_MAPPINGS"))
+}
+
+func TestConfigFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unknown setting",
+			content: "kubernetes:\n  qpsLimt: 5\n",
+			wantErr: `unknown setting "kubernetes.qpsLimt"`,
+		},
+		{
+			name:    "invalid value",
+			content: "kubernetes:\n  qpsLimit: fast\n",
+			wantErr: "kubernetes.qpsLimit",
+		},
+		{
+			name:    "nested value for a scalar setting",
+			content: "debug:\n  enabled: true\n",
+			wantErr: "debug",
+		},
+		{
+			name:    "invalid YAML",
+			content: "kubernetes: [",
+			wantErr: "invalid config file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runServeCommand(t, "--config", writeConfigFile(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := runServeCommand(t, "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file")
+}
+
+func TestConfigFileKeys(t *testing.T) {
+	flags := newServeCmd().Flags()
+	for key, setting := range configFileKeys {
+		if setting.flag != "" {
+			assert.NotNil(t, flags.Lookup(setting.flag), "%s: unknown flag %q", key, setting.flag)
+		} else {
+			assert.NotEmpty(t, setting.env, "%s: needs a flag or an environment variable", key)
+		}
+		for other := range configFileKeys {
+			assert.False(t, strings.HasPrefix(other, key+"."), "%s is both a setting and a section of %s", key, other)
+		}
+	}
+}
+
+func TestConfigValidateCmd(t *testing.T) {
+	valid := writeConfigFile(t, "security:\n  profile: operator\n")
+	invalid := writeConfigFile(t, "security:\n  profile: everything\n")
+
+	cmd := newConfigValidateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config", valid})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "configuration is valid\n", out.String())
+
+	cmd = newConfigValidateCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--config", invalid})
+	assert.Error(t, cmd.Execute())
+}
@@ -5,17 +5,18 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
 
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
 	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
 )
 
 // runStreamableHTTPServer runs the server with Streamable HTTP transport
-func runStreamableHTTPServer(mcpSrv *mcpserver.MCPServer, addr, endpoint string, ctx context.Context, debugMode bool, provider *instrumentation.Provider, sc *server.ServerContext, metricsConfig MetricsServeConfig) error {
+func runStreamableHTTPServer(mcpSrv *mcpserver.MCPServer, addr, endpoint string, ctx context.Context, debugMode bool, stack server.HTTPStackConfig, healthChecker *server.HealthChecker, metricsConfig MetricsServeConfig, auth middleware.TokenAuthenticator) error {
 	// Create a custom HTTP server (metrics are now on a separate server)
 	mux := http.NewServeMux()
 
@@ -24,28 +25,30 @@ func runStreamableHTTPServer(mcpSrv *mcpserver.MCPServer, addr, endpoint string,
 		mcpserver.WithEndpointPath(endpoint),
 	)
 
-	// Add MCP endpoint
-	mux.Handle(endpoint, mcpHandler)
+	// Add MCP endpoint; ClientIP identifies anonymous callers for rate limiting
+	mux.Handle(endpoint, middleware.ClientIP(withBearerAuth(mcpHandler, auth)))
 
 	// Note: Metrics are served on a separate metrics server for security
 	// See startMetricsServer() for the dedicated /metrics endpoint
 
 	// Add health check endpoints
-	healthChecker := server.NewHealthChecker(sc)
 	healthChecker.RegisterHealthEndpoints(mux)
 
 	slog.Info("streamable HTTP server starting",
 		"addr", addr,
 		"endpoint", endpoint,
-		"health_endpoints", []string{"/healthz", "/readyz"})
+		"health_endpoints", []string{"/healthz", "/readyz"},
+		"bearer_auth", auth != nil,
+		"compression", stack.Compression,
+		"allowed_origins", stack.AllowedOrigins)
 
-	// Apply HTTP metrics middleware to record request metrics
-	var handler http.Handler = mux
-	handler = middleware.HTTPMetrics(provider)(handler)
+	// Apply the security, CORS, compression and metrics middleware shared
+	// by every HTTP transport
+	handler := server.WrapHTTPHandler(mux, stack)
 
 	// Start metrics server if enabled
 	var metricsServer *server.MetricsServer
-	if metricsConfig.Enabled && provider != nil && provider.Enabled() {
+	if provider := stack.InstrumentationProvider; metricsConfig.Enabled && provider != nil && provider.Enabled() {
 		var err error
 		metricsServer, err = startMetricsServer(metricsConfig, provider)
 		if err != nil {
@@ -54,13 +57,7 @@ func runStreamableHTTPServer(mcpSrv *mcpserver.MCPServer, addr, endpoint string,
 	}
 
 	// Create HTTP server with security timeouts
-	httpServer := &http.Server{
-		Addr:              addr,
-		Handler:           handler,
-		ReadHeaderTimeout: 10 * time.Second,
-		WriteTimeout:      120 * time.Second,
-		IdleTimeout:       120 * time.Second,
-	}
+	httpServer := server.NewHTTPServer(addr, handler)
 
 	// Start server in goroutine
 	serverDone := make(chan error, 1)
@@ -99,22 +96,81 @@ func runStreamableHTTPServer(mcpSrv *mcpserver.MCPServer, addr, endpoint string,
 	return nil
 }
 
+// newHealthChecker creates the health checker behind /healthz and /readyz for
+// all HTTP transports. Besides the server's own state it checks Kubernetes
+// API reachability, the federation manager (registered by
+// server.NewHealthChecker when federation is enabled) and, when OAuth storage
+// or the tool rate limiter uses it, Valkey. The returned function releases
+// the Valkey connection.
+func newHealthChecker(config ServeConfig, sc *server.ServerContext, clientset func() (kubernetes.Interface, error)) (*server.HealthChecker, func()) {
+	healthChecker := server.NewHealthChecker(sc)
+	// Fixture replay never talks to a cluster.
+	if !config.Fixtures.Replay() {
+		healthChecker.AddCheck(server.HealthCheckKubernetes, server.KubernetesAPICheck(clientset))
+	}
+
+	usesValkey := (config.OAuth.Enabled && config.OAuth.Storage.Type == server.OAuthStorageTypeValkey) ||
+		(config.RateLimit.Rate > 0 && config.RateLimit.Backend == ratelimit.BackendValkey)
+	if !usesValkey {
+		return healthChecker, func() {}
+	}
+	check, closeValkey := server.ValkeyCheck(config.OAuth.Storage.Valkey)
+	healthChecker.AddCheck(server.HealthCheckValkey, check)
+	return healthChecker, closeValkey
+}
+
+// withBearerAuth protects h with bearer token authentication when auth is
+// set. Health endpoints are registered separately and stay unauthenticated so
+// probes keep working.
+func withBearerAuth(h http.Handler, auth middleware.TokenAuthenticator) http.Handler {
+	if auth == nil {
+		return h
+	}
+	return middleware.BearerAuth(auth, slog.Default())(h)
+}
+
+// httpStackConfig returns the middleware stack every HTTP transport shares,
+// with or without OAuth. ENABLE_HSTS and ALLOWED_ORIGINS apply to all of
+// them.
+func httpStackConfig(config ServeConfig, provider *instrumentation.Provider) (server.HTTPStackConfig, error) {
+	allowedOrigins, err := middleware.ValidateAllowedOrigins(config.getenv("ALLOWED_ORIGINS"))
+	if err != nil {
+		return server.HTTPStackConfig{}, fmt.Errorf("invalid ALLOWED_ORIGINS: %w", err)
+	}
+	return server.HTTPStackConfig{
+		EnableHSTS:              config.getenv("ENABLE_HSTS") == envValueTrue,
+		AllowedOrigins:          allowedOrigins,
+		Compression:             config.Compression.Enabled,
+		CompressionMinSize:      config.Compression.MinSize,
+		InstrumentationProvider: provider,
+	}, nil
+}
+
+// mcpEndpoints returns the MCP endpoints of the HTTP transport, for logging.
+func mcpEndpoints(transport, sseEndpoint, messageEndpoint string) []string {
+	if transport == transportSSE {
+		return []string{sseEndpoint, messageEndpoint}
+	}
+	return []string{"/mcp"}
+}
+
 // runOAuthHTTPServer runs the server with OAuth 2.1 authentication
-func runOAuthHTTPServer(mcpSrv *mcpserver.MCPServer, addr string, ctx context.Context, config server.OAuthConfig, sc *server.ServerContext, metricsConfig MetricsServeConfig) error {
+// for the given transport, "sse" or "streamable-http".
+func runOAuthHTTPServer(mcpSrv *mcpserver.MCPServer, transport, addr string, ctx context.Context, config server.OAuthConfig, healthChecker *server.HealthChecker, metricsConfig MetricsServeConfig) error {
 	// Create OAuth HTTP server
-	oauthServer, err := server.NewOAuthHTTPServer(mcpSrv, "streamable-http", config)
+	oauthServer, err := server.NewOAuthHTTPServer(mcpSrv, transport, config)
 	if err != nil {
 		return fmt.Errorf("failed to create OAuth HTTP server: %w", err)
 	}
 
 	// Set up health checker
-	healthChecker := server.NewHealthChecker(sc)
 	oauthServer.SetHealthChecker(healthChecker)
 
 	slog.Info("OAuth-enabled HTTP server starting",
 		"addr", addr,
+		"transport", transport,
 		"base_url", config.BaseURL,
-		"mcp_endpoint", "/mcp",
+		"mcp_endpoints", mcpEndpoints(transport, config.SSEEndpoint, config.MessageEndpoint),
 		"health_endpoints", []string{"/healthz", "/readyz"},
 		"oauth_endpoints", []string{
 			"/.well-known/oauth-authorization-server",
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+func TestNewHealthChecker(t *testing.T) {
+	unreachable := func() (kubernetes.Interface, error) { return nil, errors.New("no cluster") }
+
+	tests := []struct {
+		name       string
+		config     ServeConfig
+		wantValkey bool
+	}{
+		{
+			name: "memory storage",
+		},
+		{
+			name: "oauth valkey storage",
+			config: ServeConfig{OAuth: OAuthServeConfig{
+				Enabled: true,
+				Storage: server.OAuthStorageConfig{Type: server.OAuthStorageTypeValkey, Valkey: server.ValkeyStorageConfig{URL: "127.0.0.1:1"}},
+			}},
+			wantValkey: true,
+		},
+		{
+			name: "valkey rate limit backend",
+			config: ServeConfig{
+				RateLimit: RateLimitServeConfig{Rate: 5, Backend: ratelimit.BackendValkey},
+				OAuth:     OAuthServeConfig{Storage: server.OAuthStorageConfig{Valkey: server.ValkeyStorageConfig{URL: "127.0.0.1:1"}}},
+			},
+			wantValkey: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthChecker, closeChecks := newHealthChecker(tt.config, nil, unreachable)
+			defer closeChecks()
+
+			rec := httptest.NewRecorder()
+			healthChecker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+			var response server.HealthResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "failed", response.Checks[server.HealthCheckKubernetes])
+			_, hasValkey := response.Checks[server.HealthCheckValkey]
+			assert.Equal(t, tt.wantValkey, hasValkey)
+		})
+	}
+}
+
+func TestHTTPStackConfig(t *testing.T) {
+	t.Setenv("ALLOWED_ORIGINS", "https://app.example.com/")
+	t.Setenv("ENABLE_HSTS", "true")
+
+	stack, err := httpStackConfig(ServeConfig{Compression: CompressionServeConfig{Enabled: true, MinSize: 512}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, server.HTTPStackConfig{
+		EnableHSTS:         true,
+		AllowedOrigins:     []string{"https://app.example.com"},
+		Compression:        true,
+		CompressionMinSize: 512,
+	}, stack)
+
+	t.Setenv("ALLOWED_ORIGINS", "ftp://files.example.com")
+	_, err = httpStackConfig(ServeConfig{}, nil)
+	assert.ErrorContains(t, err, "invalid ALLOWED_ORIGINS")
+}
@@ -5,17 +5,15 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
 
 	mcpserver "github.com/mark3labs/mcp-go/server"
 
-	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
 	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
 )
 
 // runSSEServer runs the server with SSE transport
-func runSSEServer(mcpSrv *mcpserver.MCPServer, addr, sseEndpoint, messageEndpoint string, ctx context.Context, debugMode bool, provider *instrumentation.Provider, metricsConfig MetricsServeConfig) error {
+func runSSEServer(mcpSrv *mcpserver.MCPServer, addr, sseEndpoint, messageEndpoint string, ctx context.Context, debugMode bool, stack server.HTTPStackConfig, healthChecker *server.HealthChecker, metricsConfig MetricsServeConfig, auth middleware.TokenAuthenticator) error {
 	if debugMode {
 		slog.Debug("initializing SSE server",
 			"address", addr,
@@ -32,13 +30,17 @@ func runSSEServer(mcpSrv *mcpserver.MCPServer, addr, sseEndpoint, messageEndpoin
 		mcpserver.WithMessageEndpoint(messageEndpoint),
 	)
 
-	// Add SSE and message endpoints
-	mux.Handle(sseEndpoint, sseHandler)
-	mux.Handle(messageEndpoint, sseHandler)
+	// Add SSE and message endpoints; ClientIP identifies anonymous callers
+	// for rate limiting
+	mux.Handle(sseEndpoint, middleware.ClientIP(withBearerAuth(sseHandler, auth)))
+	mux.Handle(messageEndpoint, middleware.ClientIP(withBearerAuth(sseHandler, auth)))
 
 	// Note: Metrics are served on a separate metrics server for security
 	// See startMetricsServer() for the dedicated /metrics endpoint
 
+	// Add health check endpoints
+	healthChecker.RegisterHealthEndpoints(mux)
+
 	if debugMode {
 		slog.Debug("sse server instance created successfully")
 	}
@@ -46,15 +48,19 @@ func runSSEServer(mcpSrv *mcpserver.MCPServer, addr, sseEndpoint, messageEndpoin
 	slog.Info("SSE server starting",
 		"addr", addr,
 		"sse_endpoint", sseEndpoint,
-		"message_endpoint", messageEndpoint)
+		"message_endpoint", messageEndpoint,
+		"health_endpoints", []string{"/healthz", "/readyz"},
+		"bearer_auth", auth != nil,
+		"compression", stack.Compression,
+		"allowed_origins", stack.AllowedOrigins)
 
-	// Apply HTTP metrics middleware to record request metrics
-	var handler http.Handler = mux
-	handler = middleware.HTTPMetrics(provider)(handler)
+	// Apply the security, CORS, compression and metrics middleware shared
+	// by every HTTP transport
+	handler := server.WrapHTTPHandler(mux, stack)
 
 	// Start metrics server if enabled
 	var metricsServer *server.MetricsServer
-	if metricsConfig.Enabled && provider != nil && provider.Enabled() {
+	if provider := stack.InstrumentationProvider; metricsConfig.Enabled && provider != nil && provider.Enabled() {
 		var err error
 		metricsServer, err = startMetricsServer(metricsConfig, provider)
 		if err != nil {
@@ -63,13 +69,7 @@ func runSSEServer(mcpSrv *mcpserver.MCPServer, addr, sseEndpoint, messageEndpoin
 	}
 
 	// Create HTTP server with security timeouts
-	httpServer := &http.Server{
-		Addr:              addr,
-		Handler:           handler,
-		ReadHeaderTimeout: 10 * time.Second,
-		WriteTimeout:      120 * time.Second,
-		IdleTimeout:       120 * time.Second,
-	}
+	httpServer := server.NewHTTPServer(addr, handler)
 
 	// Start server in goroutine
 	serverDone := make(chan error, 1)
@@ -2,13 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	tklogging "github.com/giantswarm/mcp-toolkit/logging"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 )
 
 func TestNewServeCmd(t *testing.T) {
@@ -726,3 +733,95 @@ func TestParseIntEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadRateLimitEnvVars(t *testing.T) {
+	t.Run("env vars apply when flags are unset", func(t *testing.T) {
+		t.Setenv("TOOL_RATE_LIMIT", "2.5")
+		t.Setenv("TOOL_RATE_LIMIT_BURST", "5")
+		t.Setenv("TOOL_RATE_LIMIT_PER_TOOL", "true")
+		t.Setenv("TOOL_RATE_LIMIT_BACKEND", "valkey")
+
+		cmd := newServeCmd()
+		config := RateLimitServeConfig{Burst: 20, Backend: "memory"}
+		loadRateLimitEnvVars(cmd, &config)
+
+		assert.Equal(t, RateLimitServeConfig{Rate: 2.5, Burst: 5, PerTool: true, Backend: "valkey"}, config)
+	})
+
+	t.Run("explicit flags win over env vars", func(t *testing.T) {
+		t.Setenv("TOOL_RATE_LIMIT", "2.5")
+		t.Setenv("TOOL_RATE_LIMIT_BACKEND", "valkey")
+
+		cmd := newServeCmd()
+		require.NoError(t, cmd.Flags().Set("tool-rate-limit", "10"))
+		require.NoError(t, cmd.Flags().Set("tool-rate-limit-backend", "memory"))
+		config := RateLimitServeConfig{Rate: 10, Burst: 20, Backend: "memory"}
+		loadRateLimitEnvVars(cmd, &config)
+
+		assert.Equal(t, RateLimitServeConfig{Rate: 10, Burst: 20, Backend: "memory"}, config)
+	})
+}
+
+func TestLoadHTTPAuthEnvVars(t *testing.T) {
+	t.Run("env vars apply when flags are unset", func(t *testing.T) {
+		t.Setenv("MCP_AUTH_MODE", "tokenreview")
+		t.Setenv("MCP_AUTH_TOKEN_FILE", "/etc/mcp/tokens")
+		t.Setenv("MCP_AUTH_TOKEN_AUDIENCES", "mcp-kubernetes, https://kubernetes.default.svc")
+
+		cmd := newServeCmd()
+		config := HTTPAuthServeConfig{Mode: "none"}
+		loadHTTPAuthEnvVars(cmd, &config)
+
+		assert.Equal(t, HTTPAuthServeConfig{
+			Mode:      "tokenreview",
+			TokenFile: "/etc/mcp/tokens",
+			Audiences: []string{"mcp-kubernetes", "https://kubernetes.default.svc"},
+		}, config)
+	})
+
+	t.Run("explicit flags win over env vars", func(t *testing.T) {
+		t.Setenv("MCP_AUTH_MODE", "tokenreview")
+
+		cmd := newServeCmd()
+		require.NoError(t, cmd.Flags().Set("auth-mode", "static-token"))
+		config := HTTPAuthServeConfig{Mode: "static-token"}
+		loadHTTPAuthEnvVars(cmd, &config)
+
+		assert.Equal(t, "static-token", config.Mode)
+	})
+}
+
+func TestConcurrencyConfig(t *testing.T) {
+	assert.Equal(t, federation.DefaultConcurrencyConfig(), concurrencyConfig(ConcurrencyLimitConfig{}))
+
+	disabled, queued := 0, 50
+	limits := concurrencyConfig(ConcurrencyLimitConfig{
+		MaxConcurrent: &disabled,
+		MaxQueued:     &queued,
+		QueueTimeout:  5 * time.Second,
+	})
+	assert.Equal(t, federation.ConcurrencyConfig{MaxConcurrent: 0, MaxQueued: 50, QueueTimeout: 5 * time.Second}, limits)
+}
+
+func TestLoggingOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.log")
+	opts, closeLogFile, err := loggingOptions(LoggingServeConfig{File: path, Format: logFormatJSON, MaxBytes: 1 << 20})
+	require.NoError(t, err)
+
+	logger, _, err := tklogging.Init(context.Background(), opts...)
+	require.NoError(t, err)
+	logger.Info("server started", "transport", transportStdio)
+	require.NoError(t, closeLogFile())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(content, &record))
+	assert.Equal(t, "server started", record["msg"])
+	assert.Equal(t, transportStdio, record["transport"])
+
+	opts, closeLogFile, err = loggingOptions(LoggingServeConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+	assert.NoError(t, closeLogFile())
+}
@@ -1,10 +1,16 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 // TestOAuthProviderValidation tests validation of OAuth provider configuration
@@ -335,77 +341,6 @@ func TestOAuthProviderValidation(t *testing.T) {
 	}
 }
 
-// validateOAuthConfig extracts and tests the OAuth validation logic from runServe
-func validateOAuthConfig(config OAuthServeConfig) error {
-	if !config.Enabled {
-		return nil
-	}
-
-	// Validate OAuth configuration
-	if config.BaseURL == "" {
-		return fmt.Errorf("--oauth-base-url is required when --enable-oauth is set")
-	}
-	// Validate OAuth base URL (allows localhost for development, but requires HTTPS for production)
-	if err := validateOAuthBaseURL(config.BaseURL); err != nil {
-		return err
-	}
-
-	// Validate TLS configuration - both cert and key must be provided together
-	if (config.TLSCertFile != "" && config.TLSKeyFile == "") ||
-		(config.TLSCertFile == "" && config.TLSKeyFile != "") {
-		return fmt.Errorf("both --tls-cert-file and --tls-key-file must be provided together for HTTPS")
-	}
-
-	// Provider-specific validation
-	switch config.Provider {
-	case OAuthProviderDex:
-		if config.DexIssuerURL == "" {
-			return fmt.Errorf("dex issuer URL is required when using Dex provider (--dex-issuer-url or DEX_ISSUER_URL)")
-		}
-		// Validate Dex issuer URL is HTTPS and not vulnerable to SSRF
-		if err := validateSecureURL(config.DexIssuerURL, "Dex issuer URL", config.AllowPrivateURLs); err != nil {
-			return err
-		}
-		if config.DexClientID == "" {
-			return fmt.Errorf("dex client ID is required when using Dex provider (--dex-client-id or DEX_CLIENT_ID)")
-		}
-		if config.DexClientSecret == "" {
-			return fmt.Errorf("dex client secret is required when using Dex provider (--dex-client-secret or DEX_CLIENT_SECRET)")
-		}
-		// Validate Kubernetes authenticator client ID format (if provided)
-		if err := validateOAuthClientID(config.DexKubernetesAuthenticatorClientID, "Dex Kubernetes authenticator client ID"); err != nil {
-			return err
-		}
-	case OAuthProviderGoogle:
-		if config.GoogleClientID == "" {
-			return fmt.Errorf("google client ID is required when using Google provider (--google-client-id or GOOGLE_CLIENT_ID)")
-		}
-		if config.GoogleClientSecret == "" {
-			return fmt.Errorf("google client secret is required when using Google provider (--google-client-secret or GOOGLE_CLIENT_SECRET)")
-		}
-	default:
-		return fmt.Errorf("unsupported OAuth provider: %s (supported: %s, %s)", config.Provider, OAuthProviderDex, OAuthProviderGoogle)
-	}
-
-	// Validate trusted schemes if configured (RFC 3986 compliance)
-	if err := validateTrustedSchemes(config.TrustedPublicRegistrationSchemes); err != nil {
-		return fmt.Errorf("invalid trusted public registration scheme: %w", err)
-	}
-
-	// Registration token is required unless:
-	// 1. Public registration is enabled (anyone can register), OR
-	// 2. Trusted schemes are configured (Cursor/VSCode can register without token), OR
-	// 3. CIMD is enabled (clients use HTTPS URLs as client IDs)
-	hasTrustedSchemes := len(config.TrustedPublicRegistrationSchemes) > 0
-	cimdEnabled := config.EnableCIMD
-	if !config.AllowPublicRegistration && config.RegistrationToken == "" && !hasTrustedSchemes && !cimdEnabled {
-		return fmt.Errorf("--registration-token is required when public registration is disabled, " +
-			"no trusted schemes are configured, and CIMD is disabled")
-	}
-
-	return nil
-}
-
 // TestValidateTrustedSchemes tests RFC 3986 scheme validation
 func TestValidateTrustedSchemes(t *testing.T) {
 	tests := []struct {
@@ -703,3 +638,137 @@ func TestTLSConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestValidatePIIPatterns tests validation of --pii-patterns values
+func TestValidatePIIPatterns(t *testing.T) {
+	assert.NoError(t, validatePIIPatterns(nil))
+	assert.NoError(t, validatePIIPatterns([]string{"email", "ip", "bearer_token", "aws_key"}))
+
+	err := validatePIIPatterns([]string{"email", "ssn"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown PII pattern "ssn"`)
+}
+
+func TestValidateLoggingConfig(t *testing.T) {
+	assert.NoError(t, validateLoggingConfig(LoggingServeConfig{}))
+	assert.NoError(t, validateLoggingConfig(LoggingServeConfig{Format: logFormatJSON, File: "/var/log/mcp.log", MaxBytes: 1024, MaxBackups: 3}))
+
+	assert.ErrorContains(t, validateLoggingConfig(LoggingServeConfig{Format: "logfmt"}), "unsupported log format: logfmt")
+	assert.ErrorContains(t, validateLoggingConfig(LoggingServeConfig{MaxBytes: -1}), "--log-max-bytes")
+	assert.ErrorContains(t, validateLoggingConfig(LoggingServeConfig{RotateInterval: -time.Second}), "--log-rotate-interval")
+	assert.ErrorContains(t, validateLoggingConfig(LoggingServeConfig{MaxBackups: -1}), "--log-max-backups")
+}
+
+func TestOutputClusterOverrides(t *testing.T) {
+	byCluster, byType, err := outputClusterOverrides(ServeConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, byCluster)
+	assert.Nil(t, byType)
+
+	byCluster, byType, err = outputClusterOverrides(ServeConfig{ConfigFileEnv: map[string]string{
+		"OUTPUT_CLUSTER_OVERRIDES":      `{"sandbox-01": {"maskSecrets": false}}`,
+		"OUTPUT_CLUSTER_TYPE_OVERRIDES": `{"production": {"maxItems": 50, "slimOutput": true, "summaryThreshold": 200}}`,
+	}})
+	require.NoError(t, err)
+	require.Contains(t, byCluster, "sandbox-01")
+	require.NotNil(t, byCluster["sandbox-01"].MaskSecrets)
+	assert.False(t, *byCluster["sandbox-01"].MaskSecrets)
+	assert.Nil(t, byCluster["sandbox-01"].MaxItems)
+	require.Contains(t, byType, "production")
+	assert.Equal(t, 50, *byType["production"].MaxItems)
+	assert.Equal(t, 200, *byType["production"].SummaryThreshold)
+
+	tests := []struct {
+		name    string
+		env     string
+		value   string
+		wantErr string
+	}{
+		{"invalid JSON", "OUTPUT_CLUSTER_OVERRIDES", `{"a":`, "invalid OUTPUT_CLUSTER_OVERRIDES"},
+		{"unknown setting", "OUTPUT_CLUSTER_OVERRIDES", `{"a": {"maxClusters": 5}}`, `unknown field "maxClusters"`},
+		{"too many items", "OUTPUT_CLUSTER_OVERRIDES", `{"a": {"maxItems": 5000}}`, "a: maxItems must be between 1 and 1000"},
+		{"zero threshold", "OUTPUT_CLUSTER_TYPE_OVERRIDES", `{"staging": {"summaryThreshold": 0}}`, "summaryThreshold must be positive"},
+		{"unknown cluster type", "OUTPUT_CLUSTER_TYPE_OVERRIDES", `{"prod": {"maxItems": 5}}`, `unknown cluster type "prod"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := outputClusterOverrides(ServeConfig{ConfigFileEnv: map[string]string{tt.env: tt.value}})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOutputKindProfiles(t *testing.T) {
+	profiles, err := outputKindProfiles(ServeConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, profiles)
+
+	profiles, err = outputKindProfiles(ServeConfig{ConfigFileEnv: map[string]string{
+		"OUTPUT_KIND_PROFILES": `{"Pod": ["status.phase", "status.containerStatuses[*].restartCount"], "Node": ["status.conditions", "status.allocatable"]}`,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status.conditions", "status.allocatable"}, profiles["Node"])
+
+	_, err = outputKindProfiles(ServeConfig{ConfigFileEnv: map[string]string{"OUTPUT_KIND_PROFILES": `["Pod"]`}})
+	assert.ErrorContains(t, err, "invalid OUTPUT_KIND_PROFILES")
+	_, err = outputKindProfiles(ServeConfig{ConfigFileEnv: map[string]string{"OUTPUT_KIND_PROFILES": `{"Pod": []}`}})
+	assert.ErrorContains(t, err, "no fields to keep")
+}
+
+func TestValidateHTTPAuthConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    HTTPAuthServeConfig
+		transport string
+		oauth     bool
+		wantErr   string
+	}{
+		{name: "disabled", config: HTTPAuthServeConfig{Mode: "none"}, transport: transportStreamableHTTP},
+		{name: "disabled with oauth", config: HTTPAuthServeConfig{Mode: "none"}, transport: transportStreamableHTTP, oauth: true},
+		{name: "static token", config: HTTPAuthServeConfig{Mode: "static-token", TokenFile: "/tokens"}, transport: transportSSE},
+		{name: "static token without file", config: HTTPAuthServeConfig{Mode: "static-token"}, transport: transportSSE, wantErr: "requires --auth-token-file"},
+		{name: "tokenreview", config: HTTPAuthServeConfig{Mode: "tokenreview"}, transport: transportStreamableHTTP},
+		{name: "combined with oauth", config: HTTPAuthServeConfig{Mode: "tokenreview"}, transport: transportStreamableHTTP, oauth: true, wantErr: "cannot be combined with --enable-oauth"},
+		{name: "unknown mode", config: HTTPAuthServeConfig{Mode: "basic"}, transport: transportStreamableHTTP, wantErr: `unsupported auth mode "basic"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHTTPAuthConfig(tt.config, tt.transport, tt.oauth)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNewHTTPAuthenticator(t *testing.T) {
+	noClientset := func() (kubernetes.Interface, error) {
+		t.Fatal("clientset must only be requested in tokenreview mode")
+		return nil, nil
+	}
+
+	auth, err := newHTTPAuthenticator(HTTPAuthServeConfig{Mode: "none"}, noClientset)
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+
+	path := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(path, []byte("secret-token\n"), 0o600))
+	auth, err = newHTTPAuthenticator(HTTPAuthServeConfig{Mode: "static-token", TokenFile: path}, noClientset)
+	require.NoError(t, err)
+	subject, err := auth.Authenticate(context.Background(), "secret-token")
+	require.NoError(t, err)
+	assert.Equal(t, "static-token-1", subject)
+
+	_, err = newHTTPAuthenticator(HTTPAuthServeConfig{Mode: "static-token", TokenFile: filepath.Join(t.TempDir(), "missing")}, noClientset)
+	assert.Error(t, err)
+
+	auth, err = newHTTPAuthenticator(HTTPAuthServeConfig{Mode: "tokenreview"}, func() (kubernetes.Interface, error) {
+		return fake.NewSimpleClientset(), nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, auth)
+}
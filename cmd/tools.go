@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/confirmation"
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/plan"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+)
+
+// newToolsCmd creates the Cobra command grouping the tool helpers.
+func newToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Inspect the MCP tools",
+	}
+	cmd.AddCommand(newToolsListCmd())
+	return cmd
+}
+
+// newToolsListCmd creates the command that prints the tools a serve
+// configuration exposes.
+func newToolsListCmd() *cobra.Command {
+	var asJSON bool
+	cmd := newServeCommand(func(cmd *cobra.Command, config ServeConfig) error {
+		if err := validateServeConfig(config); err != nil {
+			return err
+		}
+		list, err := listTools(config)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(list)
+		}
+		return printTools(cmd.OutOrStdout(), list)
+	})
+	cmd.Use = "list"
+	cmd.Short = "List the tools the serve configuration exposes"
+	cmd.Long = `List the tools a server started with the same flags, --config file and
+environment variables exposes, with their input parameters. Settings that
+add or remove tools apply, such as --profile, --non-destructive,
+--confirm-destructive, --enable-plans, --undo-journal, --preferences,
+--in-cluster and federation. No cluster is contacted.
+
+With --json the tool definitions are printed as the MCP tools/list
+response carries them, including their JSON input schemas.
+
+Per-user filtering (--read-only-groups, the policy file and RBAC-aware
+tool visibility) happens when a client lists the tools and is not applied.`
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the tool definitions, including their input schemas, as JSON")
+	return cmd
+}
+
+// offlineK8sClient stands in for the Kubernetes client when tools are only
+// registered to be listed. Registration never calls the client.
+type offlineK8sClient struct {
+	k8s.Client
+}
+
+// offlineFederationManager stands in for the federation manager when tools
+// are only registered to be listed. Registration only checks that a manager
+// is set.
+type offlineFederationManager struct {
+	federation.ClusterClientManager
+}
+
+// listTools registers the tools of config without connecting to a cluster or
+// Valkey, applies the tool profile and returns the tools clients are offered,
+// sorted by name.
+func listTools(config ServeConfig) ([]mcp.Tool, error) {
+	opts := []server.Option{
+		server.WithK8sClient(offlineK8sClient{}),
+		server.WithNonDestructiveMode(config.NonDestructiveMode),
+		server.WithDryRun(config.DryRun),
+	}
+	if config.InCluster {
+		opts = append(opts, server.WithInCluster(true))
+	}
+	if config.ConfirmDestructive {
+		opts = append(opts, server.WithConfirmations(confirmation.NewStore(config.ConfirmationTTL)))
+	}
+	if config.EnablePlans {
+		opts = append(opts, server.WithPlans(plan.NewStore(config.PlanTTL)))
+	}
+	// Backends only matter to handlers, so memory stands in for Valkey.
+	if config.UndoJournal.Backend != "" {
+		opts = append(opts, server.WithUndoJournal(journal.New(journal.NewMemoryBackend(), "", config.UndoJournal.Size, config.UndoJournal.TTL)))
+	}
+	if config.Preferences.Backend != "" {
+		opts = append(opts, server.WithPreferences(preferences.New(preferences.NewMemoryBackend(), "")))
+	}
+	if config.CAPIMode.Enabled {
+		opts = append(opts, server.WithFederationManager(offlineFederationManager{}))
+	}
+
+	// The server context holds no connections or sessions, so it is not shut
+	// down.
+	serverContext, err := server.NewServerContext(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server context: %w", err)
+	}
+
+	mcpSrv := mcpserver.NewMCPServer(serviceName, rootCmd.Version, mcpserver.WithToolCapabilities(true))
+	if err := registerTools(mcpSrv, serverContext); err != nil {
+		return nil, err
+	}
+	if _, err := tools.NewToolExposure(mcpSrv, config.ToolProfile); err != nil {
+		return nil, err
+	}
+
+	list := make([]mcp.Tool, 0, len(mcpSrv.ListTools()))
+	for _, t := range mcpSrv.ListTools() {
+		list = append(list, t.Tool)
+	}
+	list = tools.HideDeprecatedAliasesFilter(context.Background(), list)
+	slices.SortFunc(list, func(a, b mcp.Tool) int { return strings.Compare(a.Name, b.Name) })
+	return list, nil
+}
+
+// toolParameter is an input parameter of a tool, as printed by tools list.
+type toolParameter struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// toolParameters returns the input parameters of t, sorted by name.
+func toolParameters(t mcp.Tool) ([]toolParameter, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var def struct {
+		InputSchema struct {
+			Properties map[string]struct {
+				Type        any    `json:"type"`
+				Description string `json:"description"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, err
+	}
+	params := make([]toolParameter, 0, len(def.InputSchema.Properties))
+	for name, prop := range def.InputSchema.Properties {
+		typ := "any"
+		if prop.Type != nil {
+			typ = fmt.Sprint(prop.Type)
+		}
+		params = append(params, toolParameter{
+			Name:        name,
+			Type:        typ,
+			Required:    slices.Contains(def.InputSchema.Required, name),
+			Description: prop.Description,
+		})
+	}
+	slices.SortFunc(params, func(a, b toolParameter) int { return strings.Compare(a.Name, b.Name) })
+	return params, nil
+}
+
+// printTools prints list as text: every tool with the first line of its
+// description and its parameters.
+func printTools(out io.Writer, list []mcp.Tool) error {
+	for i, t := range list {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintf(out, "%s\n    %s\n", t.Name, firstLine(t.Description))
+		params, err := toolParameters(t)
+		if err != nil {
+			return fmt.Errorf("failed to read the input schema of %s: %w", t.Name, err)
+		}
+		for _, p := range params {
+			required := ""
+			if p.Required {
+				required = ", required"
+			}
+			_, _ = fmt.Fprintf(out, "    - %s (%s%s): %s\n", p.Name, p.Type, required, firstLine(p.Description))
+		}
+	}
+	return nil
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
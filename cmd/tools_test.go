@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+)
+
+func toolNames(t *testing.T, config ServeConfig) []string {
+	t.Helper()
+	list, err := listTools(config)
+	require.NoError(t, err)
+	names := make([]string, len(list))
+	for i, tool := range list {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+func TestListTools(t *testing.T) {
+	t.Run("non-destructive mode registers no mutating tools", func(t *testing.T) {
+		names := toolNames(t, ServeConfig{NonDestructiveMode: true})
+		assert.Contains(t, names, "get")
+		assert.NotContains(t, names, "delete")
+		assert.True(t, slices.IsSorted(names), "tools are sorted by name")
+		for _, name := range names {
+			assert.False(t, tools.IsDeprecatedAlias(name), "deprecated alias %s listed", name)
+		}
+	})
+
+	t.Run("profile hides tools", func(t *testing.T) {
+		assert.Contains(t, toolNames(t, ServeConfig{ToolProfile: tools.ProfileAdmin}), "delete")
+		assert.NotContains(t, toolNames(t, ServeConfig{ToolProfile: tools.ProfileReadOnly}), "delete")
+	})
+
+	t.Run("optional tools", func(t *testing.T) {
+		names := toolNames(t, ServeConfig{
+			ConfirmDestructive: true,
+			EnablePlans:        true,
+			UndoJournal:        UndoJournalServeConfig{Backend: undoJournalValkey, Size: 20},
+			Preferences:        PreferencesServeConfig{Backend: preferencesValkey},
+		})
+		assert.Contains(t, names, "confirm")
+		assert.Contains(t, names, "add_to_plan")
+		assert.Contains(t, names, "undo")
+		assert.Contains(t, names, "set_preferences")
+	})
+
+	t.Run("federation", func(t *testing.T) {
+		assert.NotContains(t, toolNames(t, ServeConfig{}), "capi_list_clusters")
+		assert.Contains(t, toolNames(t, ServeConfig{CAPIMode: CAPIModeConfig{Enabled: true}}), "capi_list_clusters")
+	})
+}
+
+func TestPrintTools(t *testing.T) {
+	list := []mcp.Tool{
+		mcp.NewTool("get",
+			mcp.WithDescription("Get a resource.\nMore details."),
+			mcp.WithString("resourceType", mcp.Required(), mcp.Description("Type of the resource")),
+			mcp.WithBoolean("full", mcp.Description("Return the full manifest")),
+		),
+		mcp.NewTool("list_namespaces", mcp.WithDescription("List namespaces")),
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printTools(&out, list))
+	assert.Equal(t, `get
+    Get a resource.
+    - full (boolean): Return the full manifest
+    - resourceType (string, required): Type of the resource
+
+list_namespaces
+    List namespaces
+`, out.String())
+}
+
+func TestToolsListCmd_JSON(t *testing.T) {
+	cmd := newToolsListCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--json", "--profile", tools.ProfileReadOnly})
+	require.NoError(t, cmd.Execute())
+
+	var list []struct {
+		Name        string         `json:"name"`
+		InputSchema map[string]any `json:"inputSchema"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &list))
+	require.NotEmpty(t, list)
+	for _, tool := range list {
+		assert.NotEmpty(t, tool.Name)
+		assert.Equal(t, "object", tool.InputSchema["type"], tool.Name)
+	}
+}
@@ -0,0 +1,176 @@
+package capture
+
+import (
+	"encoding/json"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// DefaultMaxBytes caps the captured arguments and result of a call.
+const DefaultMaxBytes = 16 << 10
+
+// redacted replaces masked values.
+const redacted = "***REDACTED***"
+
+// truncatedSuffix marks captured text cut at the size limit.
+const truncatedSuffix = "...[truncated]"
+
+// sensitiveKey matches argument names whose values are masked: besides
+// credentials, the content cp_to_pod writes and the stdin session_input
+// types into a shell, which may hold passwords.
+var sensitiveKey = regexp.MustCompile(`(?i)password|passwd|secret|token|credential|api_?key|private_?key|^stringdata$|^content$|^input$`)
+
+// secretManifest matches YAML or JSON text declaring a Secret.
+var secretManifest = regexp.MustCompile(`(?m)^\s*kind:\s*["']?Secret["']?\s*$|"kind"\s*:\s*"Secret"`)
+
+// Entry is a captured tool call.
+type Entry struct {
+	// Time is when the call started.
+	Time time.Time `json:"time"`
+
+	// Tool is the name of the tool called.
+	Tool string `json:"tool"`
+
+	// Session is the MCP session ID, if any.
+	Session string `json:"session,omitempty"`
+
+	// User is the anonymized caller, if authenticated.
+	User string `json:"user,omitempty"`
+
+	// TraceID links the call to its trace, if tracing is enabled.
+	TraceID string `json:"traceId,omitempty"`
+
+	// Duration is how long the call took.
+	Duration time.Duration `json:"durationNs"`
+
+	// Arguments are the sanitized arguments as JSON.
+	Arguments string `json:"arguments"`
+
+	// Result is the sanitized text of the result.
+	Result string `json:"result"`
+
+	// IsError reports an error result or a failed call.
+	IsError bool `json:"isError,omitempty"`
+
+	// Error is the error of a failed call.
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder keeps the last calls in a ring buffer. It is safe for concurrent
+// use.
+type Recorder struct {
+	maxBytes int
+
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRecorder creates a Recorder keeping the last size calls, with their
+// arguments and result each cut to maxBytes (DefaultMaxBytes if not
+// positive).
+func NewRecorder(size, maxBytes int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Recorder{maxBytes: maxBytes, entries: make([]Entry, size)}
+}
+
+// Record adds a call, replacing the oldest once the buffer is full. The
+// arguments and result of e must already be sanitized; see
+// SanitizeArguments and SanitizeText.
+func (r *Recorder) Record(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Entries returns the captured calls, oldest first.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	out := make([]Entry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// Clear drops all captured calls.
+func (r *Recorder) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.entries)
+	r.next = 0
+	r.full = false
+}
+
+// SanitizeArguments returns args as JSON with sensitive values masked,
+// credentials scrubbed and cut to the Recorder's size limit.
+func (r *Recorder) SanitizeArguments(args map[string]any) string {
+	data, err := json.Marshal(maskValue("", args))
+	if err != nil {
+		return r.SanitizeText("<unencodable arguments: " + err.Error() + ">")
+	}
+	return r.SanitizeText(string(data))
+}
+
+// SanitizeText returns text with credentials scrubbed, cut to the
+// Recorder's size limit.
+func (r *Recorder) SanitizeText(text string) string {
+	text, _ = output.ScrubCredentials(text, nil)
+	if len(text) <= r.maxBytes {
+		return text
+	}
+	// Cut at a rune boundary, so that the text stays valid UTF-8.
+	n := r.maxBytes
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n] + truncatedSuffix
+}
+
+// maskValue masks the values of sensitive keys, Secret objects and
+// manifests declaring a Secret in v.
+func maskValue(key string, v any) any {
+	if key != "" && sensitiveKey.MatchString(key) {
+		return redacted
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		if kind, _ := val["kind"].(string); kind == "Secret" {
+			val = output.MaskSecrets(val)
+		}
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = maskValue(k, item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = maskValue("", item)
+		}
+		return out
+	case string:
+		if secretManifest.MatchString(val) {
+			return "***REDACTED:secret_manifest***"
+		}
+		return val
+	default:
+		return v
+	}
+}
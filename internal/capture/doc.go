@@ -0,0 +1,10 @@
+// Package capture keeps the most recent tool calls, sanitized, for
+// debugging.
+//
+// With debug capture enabled, every tool call's arguments and result are
+// recorded in a fixed-size ring buffer. Before they are stored, credentials
+// are scrubbed, the values of sensitive arguments and Secret objects are
+// masked, and both sides are cut to a size limit. Operators read the buffer
+// to see what an agent actually asked and got back when it reports a
+// strange answer, without enabling debug logging in production.
+package capture
@@ -0,0 +1,14 @@
+// Package confirmation holds destructive tool calls that wait for a second,
+// confirming call before they run.
+//
+// With confirmations enabled, a destructive call such as delete, drain or a
+// scale to zero is not run right away. It is previewed as a server-side
+// dry-run and parked in a Store under a random token; the caller runs it by
+// presenting the token to the confirm tool before the token expires. This
+// gives human-in-the-loop MCP clients a natural checkpoint between the
+// proposed change and the change itself.
+//
+// Tokens are single-use and bound to the caller that was given them. They
+// live in the memory of the replica that issued them, so behind a load
+// balancer the confirming call must reach the same replica.
+package confirmation
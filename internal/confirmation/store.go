@@ -0,0 +1,139 @@
+package confirmation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultTTL is how long a proposed operation can be confirmed.
+const DefaultTTL = 5 * time.Minute
+
+// DefaultMaxPending caps the number of operations a Store holds.
+const DefaultMaxPending = 1000
+
+var (
+	// ErrNotFound is returned for tokens that were never issued, have
+	// expired or were already used.
+	ErrNotFound = errors.New("confirmation token is unknown, expired or already used")
+
+	// ErrCallerMismatch is returned when a token is presented by a caller
+	// other than the one it was issued to.
+	ErrCallerMismatch = errors.New("confirmation token was issued to another caller")
+
+	// ErrTooManyPending is returned when the Store is full.
+	ErrTooManyPending = errors.New("too many operations awaiting confirmation")
+)
+
+// Operation is a destructive tool call awaiting confirmation.
+type Operation struct {
+	// Token confirms the operation. Set by Store.Propose.
+	Token string
+
+	// Tool is the name of the tool that was called.
+	Tool string
+
+	// Caller identifies who proposed the operation; only they can confirm
+	// it.
+	Caller string
+
+	// ExpiresAt is when the token stops being accepted. Set by
+	// Store.Propose.
+	ExpiresAt time.Time
+
+	// Run executes the operation with the confirming call's context.
+	Run func(ctx context.Context) (*mcp.CallToolResult, error)
+}
+
+// Store holds proposed operations until they are confirmed or expire.
+type Store struct {
+	mu         sync.Mutex
+	pending    map[string]*Operation
+	ttl        time.Duration
+	maxPending int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// NewStore creates a store whose tokens expire after ttl. A ttl of zero or
+// less uses DefaultTTL.
+func NewStore(ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{
+		pending:    make(map[string]*Operation),
+		ttl:        ttl,
+		maxPending: DefaultMaxPending,
+		now:        time.Now,
+	}
+}
+
+// TTL returns how long a proposed operation can be confirmed.
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Propose parks op under a new token and sets its Token and ExpiresAt.
+func (s *Store) Propose(op *Operation) error {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("generating confirmation token: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+	if len(s.pending) >= s.maxPending {
+		return ErrTooManyPending
+	}
+	op.Token = "confirm-" + hex.EncodeToString(b)
+	op.ExpiresAt = s.now().Add(s.ttl)
+	s.pending[op.Token] = op
+	return nil
+}
+
+// Take removes and returns the operation of token. A token presented by
+// another caller is left in place, so that it cannot be burnt by someone
+// who guessed or saw it.
+func (s *Store) Take(token, caller string) (*Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.pending[token]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !s.now().Before(op.ExpiresAt) {
+		delete(s.pending, token)
+		return nil, ErrNotFound
+	}
+	if op.Caller != caller {
+		return nil, ErrCallerMismatch
+	}
+	delete(s.pending, token)
+	return op, nil
+}
+
+// Len returns the number of operations awaiting confirmation, including
+// expired ones not yet evicted.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+func (s *Store) evictExpiredLocked() {
+	now := s.now()
+	for token, op := range s.pending {
+		if !now.Before(op.ExpiresAt) {
+			delete(s.pending, token)
+		}
+	}
+}
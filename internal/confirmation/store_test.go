@@ -0,0 +1,64 @@
+package confirmation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_ProposeAndTake(t *testing.T) {
+	s := NewStore(time.Minute)
+	op := &Operation{Tool: "delete", Caller: "user:jane@example.com"}
+	require.NoError(t, s.Propose(op))
+	assert.NotEmpty(t, op.Token)
+	assert.False(t, op.ExpiresAt.IsZero())
+
+	_, err := s.Take(op.Token, "user:mallory@example.com")
+	assert.ErrorIs(t, err, ErrCallerMismatch)
+
+	taken, err := s.Take(op.Token, "user:jane@example.com")
+	require.NoError(t, err)
+	assert.Same(t, op, taken)
+
+	_, err = s.Take(op.Token, "user:jane@example.com")
+	assert.ErrorIs(t, err, ErrNotFound, "tokens are single-use")
+
+	_, err = s.Take("confirm-unknown", "user:jane@example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_Expiry(t *testing.T) {
+	now := time.Now()
+	s := NewStore(time.Minute)
+	s.now = func() time.Time { return now }
+
+	op := &Operation{Tool: "drain", Caller: "anonymous"}
+	require.NoError(t, s.Propose(op))
+
+	now = now.Add(time.Minute)
+	_, err := s.Take(op.Token, "anonymous")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Zero(t, s.Len())
+}
+
+func TestStore_MaxPending(t *testing.T) {
+	now := time.Now()
+	s := NewStore(time.Minute)
+	s.now = func() time.Time { return now }
+	s.maxPending = 2
+
+	require.NoError(t, s.Propose(&Operation{}))
+	require.NoError(t, s.Propose(&Operation{}))
+	assert.ErrorIs(t, s.Propose(&Operation{}), ErrTooManyPending)
+
+	// Expired operations make room.
+	now = now.Add(time.Minute)
+	assert.NoError(t, s.Propose(&Operation{}))
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestNewStore_DefaultTTL(t *testing.T) {
+	assert.Equal(t, DefaultTTL, NewStore(0).TTL())
+}
@@ -442,6 +442,34 @@ func (c *ClientCache) DeleteByCluster(ctx context.Context, clusterName string) {
 	}
 }
 
+// DeleteByUser removes all cached clients for the given user.
+// This is used when the user's token is refreshed, as clients built from
+// the old token stop working once it expires.
+func (c *ClientCache) DeleteByUser(ctx context.Context, userEmail string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	deleted := 0
+	for key, client := range c.clients {
+		if client.userEmail == userEmail {
+			delete(c.clients, key)
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		c.metrics.RecordCacheEviction(ctx, "manual")
+		c.metrics.SetCacheSize(ctx, len(c.clients))
+		c.logger.Debug("Deleted cached clients for user",
+			UserHashAttr(userEmail),
+			"count", deleted)
+	}
+}
+
 // Size returns the current number of entries in the cache.
 func (c *ClientCache) Size() int {
 	c.mu.RLock()
@@ -327,6 +327,32 @@ func TestClientCache_DeleteByCluster(t *testing.T) {
 	assert.NotNil(t, cache.Get(ctx, "cluster-b", "user1@example.com"))
 }
 
+func TestClientCache_DeleteByUser(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	cache := NewClientCache(
+		WithCacheLogger(logger),
+	)
+	t.Cleanup(func() { _ = cache.Close() })
+
+	ctx := context.Background()
+	fakeClient := fake.NewClientset()
+	scheme := runtime.NewScheme()
+	fakeDynamic := dynamicfake.NewSimpleDynamicClient(scheme)
+
+	cache.Set(ctx, "cluster-a", "user1@example.com", fakeClient, fakeDynamic, nil)
+	cache.Set(ctx, "cluster-b", "user1@example.com", fakeClient, fakeDynamic, nil)
+	cache.Set(ctx, "cluster-a", "user2@example.com", fakeClient, fakeDynamic, nil)
+
+	// Delete all entries for user1, e.g. after their token was refreshed
+	cache.DeleteByUser(ctx, "user1@example.com")
+	assert.Equal(t, 1, cache.Size())
+
+	assert.Nil(t, cache.Get(ctx, "cluster-a", "user1@example.com"))
+	assert.Nil(t, cache.Get(ctx, "cluster-b", "user1@example.com"))
+	assert.NotNil(t, cache.Get(ctx, "cluster-a", "user2@example.com"))
+}
+
 func TestClientCache_LRUEviction(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	metrics := newMockMetricsRecorder()
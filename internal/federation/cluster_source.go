@@ -0,0 +1,605 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Names of the built-in cluster sources, as reported in ClusterSummary.Source.
+const (
+	// ClusterSourceCAPI is the name of the source that discovers CAPI Cluster
+	// resources and reads their ${CLUSTER_NAME}-kubeconfig secrets.
+	ClusterSourceCAPI = "capi"
+
+	// ClusterSourceKubeconfig is the name of the source that serves clusters
+	// from static kubeconfig files.
+	ClusterSourceKubeconfig = "kubeconfig"
+
+	// ClusterSourceSecret is the name of the source that reads kubeconfigs
+	// from Secrets selected by a label selector.
+	ClusterSourceSecret = "secret"
+)
+
+// ClusterSource discovers workload clusters and provides the admin kubeconfig
+// used to reach them. The Manager asks its sources in order and applies user
+// impersonation (or SSO passthrough) on top of the returned configuration, so
+// sources never see or enforce workload cluster RBAC.
+//
+// Implementations must be safe for concurrent use.
+type ClusterSource interface {
+	// Name identifies the source in logs and in ClusterSummary.Source.
+	Name() string
+
+	// ListClusters returns the clusters known to the source.
+	ListClusters(ctx context.Context, user *UserInfo) ([]ClusterSummary, error)
+
+	// GetClusterSummary returns the cluster with the given name.
+	// Returns a ClusterNotFoundError if the source does not know the cluster.
+	GetClusterSummary(ctx context.Context, clusterName string, user *UserInfo) (*ClusterSummary, error)
+
+	// GetKubeconfig returns the REST configuration for the cluster. The caller
+	// may modify it, so sources that cache configurations must return a copy.
+	// Returns a ClusterNotFoundError if the source does not know the cluster.
+	GetKubeconfig(ctx context.Context, clusterName string, user *UserInfo) (*rest.Config, error)
+}
+
+// buildClusterSources assembles the cluster sources from the Manager options.
+func (m *Manager) buildClusterSources() error {
+	var sources []ClusterSource
+	if !m.disableCAPISource {
+		sources = append(sources, &capiClusterSource{m: m})
+	}
+	for _, source := range m.extraClusterSources {
+		if source == nil {
+			return fmt.Errorf("WithClusterSources: sources must not be nil")
+		}
+		sources = append(sources, source)
+	}
+	if m.secretSourceConfig != nil {
+		source, err := NewSecretClusterSource(*m.secretSourceConfig, m.getSecretAccessClient)
+		if err != nil {
+			return fmt.Errorf("WithSecretClusterSource: %w", err)
+		}
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 {
+		return fmt.Errorf("no cluster source configured: the CAPI source is disabled and no other source was added")
+	}
+
+	m.clusterSources = sources
+	return nil
+}
+
+// clusterSourceNames returns the names of the sources for logging.
+func clusterSourceNames(sources []ClusterSource) []string {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.Name())
+	}
+	return names
+}
+
+// listClustersFromSources merges the clusters of all sources. With a single
+// source its result, including errors, is returned unchanged. With several,
+// a failing source is logged and skipped so that, for example, a missing CAPI
+// CRD does not hide statically configured clusters; an error is only
+// returned if every source fails.
+func (m *Manager) listClustersFromSources(ctx context.Context, user *UserInfo) ([]ClusterSummary, error) {
+	if len(m.clusterSources) == 1 {
+		return m.clusterSources[0].ListClusters(ctx, user)
+	}
+
+	var (
+		clusters []ClusterSummary
+		firstErr error
+		failed   int
+	)
+	seen := make(map[string]string)
+	for _, source := range m.clusterSources {
+		list, err := source.ListClusters(ctx, user)
+		if err != nil {
+			m.logger.Warn("Cluster source failed to list clusters",
+				"source", source.Name(),
+				UserHashAttr(user.Email),
+				"error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+
+		for _, cluster := range list {
+			if owner, ok := seen[cluster.Name]; ok {
+				m.logger.Warn("Ignoring cluster shadowed by an earlier cluster source",
+					"cluster", cluster.Name,
+					"source", source.Name(),
+					"shadowed_by", owner)
+				continue
+			}
+			seen[cluster.Name] = source.Name()
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	if failed == len(m.clusterSources) {
+		return nil, firstErr
+	}
+	return clusters, nil
+}
+
+// getClusterSummaryFromSources returns the cluster from the first source that
+// knows it. Errors other than ClusterNotFoundError are returned only if no
+// later source knows the cluster either.
+func (m *Manager) getClusterSummaryFromSources(ctx context.Context, clusterName string, user *UserInfo) (*ClusterSummary, error) {
+	var firstErr error
+	for _, source := range m.clusterSources {
+		summary, err := source.GetClusterSummary(ctx, clusterName, user)
+		if err == nil {
+			return summary, nil
+		}
+		if len(m.clusterSources) == 1 {
+			return nil, err
+		}
+		if !errors.Is(err, ErrClusterNotFound) && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, &ClusterNotFoundError{
+		ClusterName: clusterName,
+		Reason:      "no cluster source knows a cluster with this name",
+	}
+}
+
+// getKubeconfigFromSources returns the kubeconfig from the first source that
+// knows the cluster. Unlike getClusterSummaryFromSources, any error other than
+// ClusterNotFoundError is returned immediately: falling through to a later
+// source after, for example, a strict-mode rejection could connect to a
+// different cluster of the same name.
+func (m *Manager) getKubeconfigFromSources(ctx context.Context, clusterName string, user *UserInfo) (*rest.Config, error) {
+	var notFound error
+	for _, source := range m.clusterSources {
+		config, err := source.GetKubeconfig(ctx, clusterName, user)
+		if err == nil {
+			return config, nil
+		}
+		if !errors.Is(err, ErrClusterNotFound) {
+			return nil, err
+		}
+		if notFound == nil {
+			notFound = err
+		}
+	}
+	return nil, notFound
+}
+
+// capiClusterSource is the built-in source for CAPI-managed fleets. It uses
+// the Manager's credential mode for both discovery and secret access.
+type capiClusterSource struct {
+	m *Manager
+}
+
+func (s *capiClusterSource) Name() string {
+	return ClusterSourceCAPI
+}
+
+func (s *capiClusterSource) ListClusters(ctx context.Context, user *UserInfo) ([]ClusterSummary, error) {
+	return s.m.listClustersWithOptions(ctx, user, nil)
+}
+
+func (s *capiClusterSource) GetClusterSummary(ctx context.Context, clusterName string, user *UserInfo) (*ClusterSummary, error) {
+	return s.m.getCAPIClusterSummary(ctx, clusterName, user)
+}
+
+func (s *capiClusterSource) GetKubeconfig(ctx context.Context, clusterName string, user *UserInfo) (*rest.Config, error) {
+	return s.m.getCAPIKubeconfig(ctx, clusterName, user)
+}
+
+// staticCluster is a cluster loaded from a kubeconfig file.
+type staticCluster struct {
+	summary ClusterSummary
+	config  *rest.Config
+}
+
+// KubeconfigClusterSource serves workload clusters from static kubeconfig
+// files. Every context of every file becomes a cluster named after the
+// context. The files are read once, at construction; restart the server to
+// pick up changes.
+//
+// Every authenticated user can see the clusters of this source. Access to
+// the clusters themselves is still governed by workload cluster RBAC through
+// impersonation.
+type KubeconfigClusterSource struct {
+	clusters    map[string]staticCluster
+	names       []string
+	execPlugins ExecPluginApplier
+}
+
+// NewKubeconfigClusterSource loads the kubeconfig file at path or, if path is
+// a directory, every regular file in it except hidden files. Context names
+// must be valid cluster names and unique across all files. execPlugins, if
+// not nil, is applied to the configuration of every context, so a context
+// whose exec credential plugin it does not allow fails the load.
+func NewKubeconfigClusterSource(path string, execPlugins ExecPluginApplier) (*KubeconfigClusterSource, error) {
+	files, err := kubeconfigFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &KubeconfigClusterSource{clusters: make(map[string]staticCluster), execPlugins: execPlugins}
+	for _, file := range files {
+		if err := source.load(file); err != nil {
+			return nil, err
+		}
+	}
+	if len(source.clusters) == 0 {
+		return nil, fmt.Errorf("no kubeconfig contexts found in %s", path)
+	}
+
+	sort.Strings(source.names)
+	return source, nil
+}
+
+// kubeconfigFiles returns path if it is a file, or the non-hidden regular
+// files in it if it is a directory.
+func kubeconfigFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig path: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		// Skip hidden files, which includes the ..data directories of mounted
+		// ConfigMaps and Secrets. Symlinks to regular files are followed.
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(path, entry.Name())
+		if info, err := os.Stat(file); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// load adds the contexts of one kubeconfig file.
+func (s *KubeconfigClusterSource) load(file string) error {
+	kubeconfig, err := clientcmd.LoadFromFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig %s: %w", file, err)
+	}
+	// Certificate and token files are relative to the kubeconfig file.
+	if err := clientcmd.ResolveLocalPaths(kubeconfig); err != nil {
+		return fmt.Errorf("failed to load kubeconfig %s: %w", file, err)
+	}
+
+	for contextName := range kubeconfig.Contexts {
+		if err := ValidateClusterName(contextName); err != nil {
+			return fmt.Errorf("kubeconfig %s: context %q is not a valid cluster name: %w", file, contextName, err)
+		}
+		if _, exists := s.clusters[contextName]; exists {
+			return fmt.Errorf("kubeconfig %s: context %q is defined more than once", file, contextName)
+		}
+
+		config, err := clientcmd.NewNonInteractiveClientConfig(*kubeconfig, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+		if err != nil {
+			return fmt.Errorf("kubeconfig %s: invalid context %q: %w", file, contextName, err)
+		}
+		if s.execPlugins != nil {
+			if err := s.execPlugins.ApplyTo(config); err != nil {
+				return fmt.Errorf("kubeconfig %s: context %q: %w", file, contextName, err)
+			}
+		}
+
+		s.clusters[contextName] = staticCluster{
+			summary: ClusterSummary{
+				Name:   contextName,
+				Status: string(ClusterPhaseUnknown),
+				Source: ClusterSourceKubeconfig,
+			},
+			config: config,
+		}
+		s.names = append(s.names, contextName)
+	}
+	return nil
+}
+
+// Name implements ClusterSource.
+func (s *KubeconfigClusterSource) Name() string {
+	return ClusterSourceKubeconfig
+}
+
+// ListClusters implements ClusterSource.
+func (s *KubeconfigClusterSource) ListClusters(_ context.Context, _ *UserInfo) ([]ClusterSummary, error) {
+	clusters := make([]ClusterSummary, 0, len(s.names))
+	for _, name := range s.names {
+		clusters = append(clusters, s.clusters[name].summary)
+	}
+	return clusters, nil
+}
+
+// GetClusterSummary implements ClusterSource.
+func (s *KubeconfigClusterSource) GetClusterSummary(_ context.Context, clusterName string, _ *UserInfo) (*ClusterSummary, error) {
+	cluster, ok := s.clusters[clusterName]
+	if !ok {
+		return nil, &ClusterNotFoundError{
+			ClusterName: clusterName,
+			Reason:      "no kubeconfig context with this name",
+		}
+	}
+	summary := cluster.summary
+	return &summary, nil
+}
+
+// GetKubeconfig implements ClusterSource.
+func (s *KubeconfigClusterSource) GetKubeconfig(_ context.Context, clusterName string, _ *UserInfo) (*rest.Config, error) {
+	cluster, ok := s.clusters[clusterName]
+	if !ok {
+		return nil, &ClusterNotFoundError{
+			ClusterName: clusterName,
+			Reason:      "no kubeconfig context with this name",
+		}
+	}
+	return rest.CopyConfig(cluster.config), nil
+}
+
+// SecretClusterSourceConfig configures a SecretClusterSource.
+type SecretClusterSourceConfig struct {
+	// LabelSelector selects the Secrets that hold workload cluster kubeconfigs.
+	// Required.
+	LabelSelector string
+
+	// Namespace limits the search to one namespace. If empty, all namespaces
+	// are searched.
+	Namespace string
+
+	// ClusterNameLabel is the label whose value names the cluster. If empty or
+	// missing on a Secret, the Secret name without a "-kubeconfig" suffix is
+	// used.
+	ClusterNameLabel string
+}
+
+// SecretClientFunc returns the client used to read kubeconfig Secrets on the
+// Management Cluster for an operation on clusterName.
+type SecretClientFunc func(ctx context.Context, clusterName string, user *UserInfo) (kubernetes.Interface, error)
+
+// SecretClusterSource reads workload cluster kubeconfigs from Secrets on the
+// Management Cluster selected by a label selector, such as the kubeconfig
+// Secrets of Rancher or Gardener. Like CAPI kubeconfig secrets, the kubeconfig
+// is read from the "value" or "kubeconfig" key.
+//
+// # Security
+//
+// Anyone who can create a matching Secret can add a cluster, so restrict the
+// selector to a Namespace with tight RBAC. Kubeconfigs that run exec or auth
+// provider plugins or reference local files are rejected, as they would run
+// commands or read files on the server.
+type SecretClusterSource struct {
+	config    SecretClusterSourceConfig
+	selector  string
+	getClient SecretClientFunc
+}
+
+// NewSecretClusterSource creates a SecretClusterSource that reads Secrets with
+// the clients returned by getClient.
+func NewSecretClusterSource(config SecretClusterSourceConfig, getClient SecretClientFunc) (*SecretClusterSource, error) {
+	if config.LabelSelector == "" {
+		return nil, fmt.Errorf("label selector is required")
+	}
+	selector, err := labels.Parse(config.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", config.LabelSelector, err)
+	}
+	if getClient == nil {
+		return nil, fmt.Errorf("secret client function is required")
+	}
+
+	return &SecretClusterSource{
+		config:    config,
+		selector:  selector.String(),
+		getClient: getClient,
+	}, nil
+}
+
+// Name implements ClusterSource.
+func (s *SecretClusterSource) Name() string {
+	return ClusterSourceSecret
+}
+
+// ListClusters implements ClusterSource.
+func (s *SecretClusterSource) ListClusters(ctx context.Context, user *UserInfo) ([]ClusterSummary, error) {
+	secrets, err := s.listSecrets(ctx, "", user)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]ClusterSummary, 0, len(secrets))
+	for i := range secrets {
+		clusters = append(clusters, s.summary(&secrets[i]))
+	}
+	return clusters, nil
+}
+
+// GetClusterSummary implements ClusterSource.
+func (s *SecretClusterSource) GetClusterSummary(ctx context.Context, clusterName string, user *UserInfo) (*ClusterSummary, error) {
+	secret, err := s.findSecret(ctx, clusterName, user)
+	if err != nil {
+		return nil, err
+	}
+	summary := s.summary(secret)
+	return &summary, nil
+}
+
+// GetKubeconfig implements ClusterSource.
+func (s *SecretClusterSource) GetKubeconfig(ctx context.Context, clusterName string, user *UserInfo) (*rest.Config, error) {
+	secret, err := s.findSecret(ctx, clusterName, user)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfigError := func(reason string, err error) error {
+		return &KubeconfigError{
+			ClusterName:  clusterName,
+			ResourceName: secret.Name,
+			Namespace:    secret.Namespace,
+			Reason:       reason,
+			Err:          err,
+		}
+	}
+
+	data := secret.Data[CAPISecretKey]
+	if len(data) == 0 {
+		data = secret.Data[CAPISecretKeyAlternate]
+	}
+	if len(data) == 0 {
+		return nil, kubeconfigError(fmt.Sprintf("secret missing '%s' or '%s' key", CAPISecretKey, CAPISecretKeyAlternate), nil)
+	}
+
+	config, err := restConfigFromSecretKubeconfig(data)
+	if err != nil {
+		return nil, kubeconfigError("invalid kubeconfig data", err)
+	}
+	return config, nil
+}
+
+// findSecret returns the Secret of the named cluster.
+func (s *SecretClusterSource) findSecret(ctx context.Context, clusterName string, user *UserInfo) (*corev1.Secret, error) {
+	secrets, err := s.listSecrets(ctx, clusterName, user)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if s.clusterName(&secrets[i]) == clusterName {
+			return &secrets[i], nil
+		}
+	}
+	return nil, &ClusterNotFoundError{
+		ClusterName: clusterName,
+		Reason:      "no kubeconfig secret found for this cluster",
+	}
+}
+
+// listSecrets lists the selected Secrets whose cluster name is valid. The
+// first Secret of a name wins; later duplicates are dropped.
+func (s *SecretClusterSource) listSecrets(ctx context.Context, clusterName string, user *UserInfo) ([]corev1.Secret, error) {
+	client, err := s.getClient(ctx, clusterName, user)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.CoreV1().Secrets(s.config.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.selector})
+	if err != nil {
+		return nil, &ClusterDiscoveryError{
+			Reason: "failed to list kubeconfig secrets",
+			Err:    err,
+		}
+	}
+
+	secrets := make([]corev1.Secret, 0, len(list.Items))
+	seen := make(map[string]bool, len(list.Items))
+	for _, secret := range list.Items {
+		name := s.clusterName(&secret)
+		if seen[name] || ValidateClusterName(name) != nil {
+			continue
+		}
+		seen[name] = true
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// clusterName derives the cluster name of a Secret.
+func (s *SecretClusterSource) clusterName(secret *corev1.Secret) string {
+	if s.config.ClusterNameLabel != "" {
+		if name := secret.Labels[s.config.ClusterNameLabel]; name != "" {
+			return name
+		}
+	}
+	return strings.TrimSuffix(secret.Name, CAPISecretSuffix)
+}
+
+// summary builds the cluster summary of a Secret. Without a cluster resource,
+// the status of the cluster is unknown.
+func (s *SecretClusterSource) summary(secret *corev1.Secret) ClusterSummary {
+	return ClusterSummary{
+		Name:        s.clusterName(secret),
+		Namespace:   secret.Namespace,
+		Status:      string(ClusterPhaseUnknown),
+		CreatedAt:   secret.CreationTimestamp.Time,
+		Labels:      secret.Labels,
+		Annotations: secret.Annotations,
+		Source:      ClusterSourceSecret,
+	}
+}
+
+// restConfigFromSecretKubeconfig parses a kubeconfig read from a Secret using
+// its current context or, if none is set, its only context. Kubeconfigs that
+// would run plugins or read files on the server are rejected.
+func restConfigFromSecretKubeconfig(data []byte) (*rest.Config, error) {
+	kubeconfig, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	contextName := kubeconfig.CurrentContext
+	if contextName == "" && len(kubeconfig.Contexts) == 1 {
+		for name := range kubeconfig.Contexts {
+			contextName = name
+		}
+	}
+	if kubeContext, ok := kubeconfig.Contexts[contextName]; ok {
+		if err := checkSecretKubeconfig(kubeconfig.Clusters[kubeContext.Cluster], kubeconfig.AuthInfos[kubeContext.AuthInfo]); err != nil {
+			return nil, err
+		}
+	}
+
+	return clientcmd.NewNonInteractiveClientConfig(*kubeconfig, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+}
+
+// checkSecretKubeconfig rejects exec and auth provider plugins, which would
+// run on the server, and file references, which would read server files.
+func checkSecretKubeconfig(cluster *clientcmdapi.Cluster, authInfo *clientcmdapi.AuthInfo) error {
+	if cluster != nil && cluster.CertificateAuthority != "" {
+		return fmt.Errorf("kubeconfig references local files, which is not allowed")
+	}
+	if authInfo == nil {
+		return nil
+	}
+	switch {
+	case authInfo.Exec != nil:
+		return fmt.Errorf("kubeconfig uses an exec credential plugin, which is not allowed")
+	case authInfo.AuthProvider != nil:
+		return fmt.Errorf("kubeconfig uses an auth provider plugin, which is not allowed")
+	case authInfo.ClientCertificate != "" || authInfo.ClientKey != "" || authInfo.TokenFile != "":
+		return fmt.Errorf("kubeconfig references local files, which is not allowed")
+	default:
+		return nil
+	}
+}
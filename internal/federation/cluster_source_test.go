@@ -0,0 +1,362 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testKubeconfigFor returns a kubeconfig with one context per name, each
+// pointing at https://<name>.example.com.
+func testKubeconfigFor(names ...string) string {
+	kubeconfig := "apiVersion: v1\nkind: Config\nclusters:\n"
+	for _, name := range names {
+		kubeconfig += fmt.Sprintf("- name: %[1]s\n  cluster:\n    server: https://%[1]s.example.com\n", name)
+	}
+	kubeconfig += "users:\n- name: admin\n  user:\n    token: test-token\ncontexts:\n"
+	for _, name := range names {
+		kubeconfig += fmt.Sprintf("- name: %[1]s\n  context:\n    cluster: %[1]s\n    user: admin\n", name)
+	}
+	return kubeconfig
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestKubeconfigClusterSource(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeTestFile(t, dir, "fleet.yaml", testKubeconfigFor("prod-b", "prod-a"))
+	writeTestFile(t, dir, "dev.yaml", testKubeconfigFor("dev-a"))
+	writeTestFile(t, dir, ".hidden", "not a kubeconfig")
+
+	source, err := NewKubeconfigClusterSource(dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ClusterSourceKubeconfig, source.Name())
+
+	clusters, err := source.ListClusters(ctx, testUser())
+	require.NoError(t, err)
+	var names []string
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+		assert.Equal(t, ClusterSourceKubeconfig, cluster.Source)
+		assert.Equal(t, string(ClusterPhaseUnknown), cluster.Status)
+	}
+	assert.Equal(t, []string{"dev-a", "prod-a", "prod-b"}, names)
+
+	summary, err := source.GetClusterSummary(ctx, "prod-a", testUser())
+	require.NoError(t, err)
+	assert.Equal(t, "prod-a", summary.Name)
+
+	config, err := source.GetKubeconfig(ctx, "prod-b", testUser())
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod-b.example.com", config.Host)
+
+	// Callers may modify the returned config.
+	config.Host = "https://modified.example.com"
+	config, err = source.GetKubeconfig(ctx, "prod-b", testUser())
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod-b.example.com", config.Host)
+
+	_, err = source.GetKubeconfig(ctx, "unknown", testUser())
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+	_, err = source.GetClusterSummary(ctx, "unknown", testUser())
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+}
+
+func TestNewKubeconfigClusterSource_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		path  string
+		error string
+	}{
+		{
+			name:  "missing path",
+			path:  filepath.Join(dir, "missing"),
+			error: "failed to read kubeconfig path",
+		},
+		{
+			name:  "invalid kubeconfig",
+			path:  writeTestFile(t, dir, "invalid.yaml", testInvalidKubeconfig),
+			error: "failed to load kubeconfig",
+		},
+		{
+			name:  "invalid cluster name",
+			path:  writeTestFile(t, dir, "invalid-name.yaml", testKubeconfigFor("Prod_A")),
+			error: "not a valid cluster name",
+		},
+		{
+			name:  "no contexts",
+			path:  writeTestFile(t, dir, "empty.yaml", "apiVersion: v1\nkind: Config\n"),
+			error: "no kubeconfig contexts found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKubeconfigClusterSource(tt.path, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.error)
+		})
+	}
+
+	t.Run("duplicate context", func(t *testing.T) {
+		dupDir := t.TempDir()
+		writeTestFile(t, dupDir, "a.yaml", testKubeconfigFor("prod-a"))
+		writeTestFile(t, dupDir, "b.yaml", testKubeconfigFor("prod-a"))
+
+		_, err := NewKubeconfigClusterSource(dupDir, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "defined more than once")
+	})
+}
+
+// testExecKubeconfig is a kubeconfig whose user runs the exec credential
+// plugin sh.
+const testExecKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: eks-a
+  cluster:
+    server: https://eks-a.example.com
+users:
+- name: eks
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: sh
+      args: ["-c", "exit 1"]
+      interactiveMode: Never
+contexts:
+- name: eks-a
+  context:
+    cluster: eks-a
+    user: eks
+`
+
+func TestKubeconfigClusterSource_ExecPlugins(t *testing.T) {
+	path := writeTestFile(t, t.TempDir(), "eks.yaml", testExecKubeconfig)
+
+	_, err := NewKubeconfigClusterSource(path, k8s.ExecPluginConfig{Allowlist: []string{"aws-iam-authenticator"}})
+	require.ErrorIs(t, err, k8s.ErrExecPluginNotAllowed)
+	assert.Contains(t, err.Error(), `context "eks-a"`)
+
+	source, err := NewKubeconfigClusterSource(path, k8s.ExecPluginConfig{Allowlist: []string{"sh"}})
+	require.NoError(t, err)
+	config, err := source.GetKubeconfig(context.Background(), "eks-a", testUser())
+	require.NoError(t, err)
+	assert.Nil(t, config.ExecProvider, "the plugin runs through the bounded exec plugin instead of client-go")
+	assert.NotNil(t, config.WrapTransport)
+}
+
+// createTestSourceSecret creates a kubeconfig Secret selected by "fleet=rancher".
+func createTestSourceSecret(name, namespace, kubeconfig string, labels map[string]string) *corev1.Secret {
+	secretLabels := map[string]string{"fleet": "rancher"}
+	for k, v := range labels {
+		secretLabels[k] = v
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: secretLabels},
+		Data:       map[string][]byte{CAPISecretKeyAlternate: []byte(kubeconfig)},
+	}
+}
+
+func newTestSecretClusterSource(t *testing.T, config SecretClusterSourceConfig, objects ...runtime.Object) *SecretClusterSource {
+	t.Helper()
+	client := fake.NewClientset(objects...)
+	source, err := NewSecretClusterSource(config, func(context.Context, string, *UserInfo) (kubernetes.Interface, error) {
+		return client, nil
+	})
+	require.NoError(t, err)
+	return source
+}
+
+func TestSecretClusterSource(t *testing.T) {
+	ctx := context.Background()
+	execKubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: exec
+  cluster:
+    server: https://exec.example.com
+users:
+- name: admin
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: /bin/sh
+contexts:
+- name: exec
+  context:
+    cluster: exec
+    user: admin
+`
+	source := newTestSecretClusterSource(t,
+		SecretClusterSourceConfig{LabelSelector: "fleet=rancher", ClusterNameLabel: "cluster-name"},
+		createTestSourceSecret("prod-a-kubeconfig", "fleet", testKubeconfigFor("prod-a"), nil),
+		createTestSourceSecret("c-m-abc123", "fleet", testKubeconfigFor("prod-b"), map[string]string{"cluster-name": "prod-b"}),
+		createTestSourceSecret("exec-kubeconfig", "fleet", execKubeconfig, nil),
+		createTestSourceSecret("Invalid_Name", "fleet", testKubeconfigFor("invalid"), nil),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unlabelled-kubeconfig", Namespace: "fleet"}},
+	)
+	assert.Equal(t, ClusterSourceSecret, source.Name())
+
+	clusters, err := source.ListClusters(ctx, testUser())
+	require.NoError(t, err)
+	names := make(map[string]string)
+	for _, cluster := range clusters {
+		names[cluster.Name] = cluster.Namespace
+		assert.Equal(t, ClusterSourceSecret, cluster.Source)
+	}
+	assert.Equal(t, map[string]string{"prod-a": "fleet", "prod-b": "fleet", "exec": "fleet"}, names)
+
+	config, err := source.GetKubeconfig(ctx, "prod-b", testUser())
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod-b.example.com", config.Host)
+
+	_, err = source.GetKubeconfig(ctx, "exec", testUser())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exec credential plugin")
+
+	_, err = source.GetKubeconfig(ctx, "unlabelled", testUser())
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+}
+
+func TestNewSecretClusterSource_Validation(t *testing.T) {
+	getClient := func(context.Context, string, *UserInfo) (kubernetes.Interface, error) {
+		return fake.NewClientset(), nil
+	}
+
+	_, err := NewSecretClusterSource(SecretClusterSourceConfig{}, getClient)
+	assert.ErrorContains(t, err, "label selector is required")
+
+	_, err = NewSecretClusterSource(SecretClusterSourceConfig{LabelSelector: "a in (b"}, getClient)
+	assert.ErrorContains(t, err, "invalid label selector")
+
+	_, err = NewSecretClusterSource(SecretClusterSourceConfig{LabelSelector: "a=b"}, nil)
+	assert.ErrorContains(t, err, "secret client function is required")
+}
+
+func TestManager_ClusterSources(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "fleet.yaml", testKubeconfigFor("capi-a", "static-b"))
+	kubeconfigSource, err := NewKubeconfigClusterSource(path, nil)
+	require.NoError(t, err)
+
+	fakeClient := fake.NewClientset(
+		createTestKubeconfigSecret("capi-a", "org-acme", CAPISecretKey, testValidKubeconfig),
+		createTestSourceSecret("secret-c-kubeconfig", "fleet", testKubeconfigFor("secret-c"), nil),
+	)
+	provider := &StaticClientProvider{
+		Clientset:     fakeClient,
+		DynamicClient: createTestFakeDynamicClient(runtime.NewScheme(), createTestCAPICluster("capi-a", "org-acme")),
+	}
+
+	manager, err := NewManager(provider,
+		WithManagerLogger(newTestLogger()),
+		WithClusterSources(kubeconfigSource),
+		WithSecretClusterSource(SecretClusterSourceConfig{LabelSelector: "fleet=rancher"}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	clusters, err := manager.ListClusters(ctx, user)
+	require.NoError(t, err)
+	sources := make(map[string]string)
+	for _, cluster := range clusters {
+		sources[cluster.Name] = cluster.Source
+	}
+	assert.Equal(t, map[string]string{
+		"capi-a":   ClusterSourceCAPI,
+		"static-b": ClusterSourceKubeconfig,
+		"secret-c": ClusterSourceSecret,
+	}, sources, "the CAPI cluster shadows the kubeconfig context of the same name")
+
+	config, err := manager.GetKubeconfigForCluster(ctx, "capi-a", user)
+	require.NoError(t, err)
+	assert.Equal(t, "https://test-cluster.example.com:6443", config.Host)
+
+	config, err = manager.GetKubeconfigForCluster(ctx, "static-b", user)
+	require.NoError(t, err)
+	assert.Equal(t, "https://static-b.example.com", config.Host)
+
+	config, err = manager.GetKubeconfigForCluster(ctx, "secret-c", user)
+	require.NoError(t, err)
+	assert.Equal(t, "https://secret-c.example.com", config.Host)
+
+	summary, err := manager.GetClusterSummary(ctx, "secret-c", user)
+	require.NoError(t, err)
+	assert.Equal(t, "fleet", summary.Namespace)
+
+	_, err = manager.GetKubeconfigForCluster(ctx, "unknown", user)
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+	_, err = manager.GetClusterSummary(ctx, "unknown", user)
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+}
+
+func TestManager_ClusterSourcesWithoutCAPI(t *testing.T) {
+	ctx := context.Background()
+
+	path := writeTestFile(t, t.TempDir(), "fleet.yaml", testKubeconfigFor("static-a"))
+	kubeconfigSource, err := NewKubeconfigClusterSource(path, nil)
+	require.NoError(t, err)
+
+	// The dynamic client does not serve CAPI clusters: with the CAPI source
+	// still enabled, its failure must not hide the static clusters.
+	provider := &StaticClientProvider{
+		Clientset:     fake.NewClientset(),
+		DynamicClient: createTestFakeDynamicClient(runtime.NewScheme()),
+	}
+	for _, capiEnabled := range []bool{true, false} {
+		manager, err := NewManager(provider,
+			WithManagerLogger(newTestLogger()),
+			WithCAPIClusterSource(capiEnabled),
+			WithClusterSources(kubeconfigSource),
+		)
+		require.NoError(t, err)
+
+		clusters, err := manager.ListClusters(ctx, testUser())
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		assert.Equal(t, "static-a", clusters[0].Name)
+		require.NoError(t, manager.Close())
+	}
+}
+
+func TestNewManager_ClusterSourceValidation(t *testing.T) {
+	provider := &StaticClientProvider{Clientset: fake.NewClientset()}
+
+	_, err := NewManager(provider, WithCAPIClusterSource(false))
+	assert.ErrorContains(t, err, "no cluster source configured")
+
+	_, err = NewManager(provider, WithClusterSources(nil))
+	assert.ErrorContains(t, err, "sources must not be nil")
+
+	_, err = NewManager(provider, WithSecretClusterSource(SecretClusterSourceConfig{}))
+	assert.ErrorContains(t, err, "WithSecretClusterSource")
+}
+
+func TestClusterSummaryFromUnstructured_Source(t *testing.T) {
+	summary := clusterSummaryFromUnstructured(&unstructured.Unstructured{Object: createTestCAPICluster("a", "b").Object})
+	assert.Equal(t, ClusterSourceCAPI, summary.Source)
+}
@@ -0,0 +1,207 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default concurrency limits for workload cluster operations.
+const (
+	// DefaultMaxConcurrentClusterOperations is how many workload cluster
+	// clients may be created at the same time.
+	DefaultMaxConcurrentClusterOperations = 20
+
+	// DefaultMaxQueuedClusterOperations is how many operations may wait for a
+	// slot before further ones are rejected.
+	DefaultMaxQueuedClusterOperations = 200
+
+	// DefaultConcurrencyQueueTimeout is how long an operation waits for a slot.
+	DefaultConcurrencyQueueTimeout = 30 * time.Second
+)
+
+// Slot acquisition results, as recorded by ConcurrencyMetricsRecorder.
+const (
+	// ConcurrencyResultAcquired means the operation got a slot.
+	ConcurrencyResultAcquired = "acquired"
+
+	// ConcurrencyResultQueueFull means the operation was rejected because the
+	// queue was full.
+	ConcurrencyResultQueueFull = "queue_full"
+
+	// ConcurrencyResultTimeout means the operation gave up waiting for a slot.
+	ConcurrencyResultTimeout = "timeout"
+
+	// ConcurrencyResultCanceled means the request was canceled while waiting.
+	ConcurrencyResultCanceled = "canceled"
+)
+
+// ConcurrencyConfig limits how many workload clusters the Manager contacts at
+// the same time. Each cache miss for a workload cluster client reads the
+// cluster's kubeconfig from the Management Cluster and may validate
+// connectivity; the limit keeps a fleet-wide fan-out from a single agent
+// prompt from overwhelming the Management Cluster or the network path.
+type ConcurrencyConfig struct {
+	// MaxConcurrent is how many workload cluster clients may be created at the
+	// same time. Zero or negative disables the limit.
+	MaxConcurrent int
+
+	// MaxQueued is how many operations may wait for a slot; further operations
+	// fail with ErrConcurrencyLimitExceeded. Zero or negative means no limit.
+	MaxQueued int
+
+	// QueueTimeout is how long an operation waits for a slot before failing
+	// with ErrConcurrencyLimitExceeded. Zero or negative waits until the
+	// request is canceled.
+	QueueTimeout time.Duration
+}
+
+// DefaultConcurrencyConfig returns the default concurrency limits.
+func DefaultConcurrencyConfig() ConcurrencyConfig {
+	return ConcurrencyConfig{
+		MaxConcurrent: DefaultMaxConcurrentClusterOperations,
+		MaxQueued:     DefaultMaxQueuedClusterOperations,
+		QueueTimeout:  DefaultConcurrencyQueueTimeout,
+	}
+}
+
+// ConcurrencyMetricsRecorder records concurrency limiter metrics.
+type ConcurrencyMetricsRecorder interface {
+	// RecordClusterSlotAcquisition records an attempt to get a slot and how
+	// long it waited.
+	// result: "acquired", "queue_full", "timeout" or "canceled"
+	RecordClusterSlotAcquisition(ctx context.Context, result string, wait time.Duration)
+
+	// SetClusterOperationUsage sets the number of operations holding a slot
+	// and waiting for one.
+	SetClusterOperationUsage(ctx context.Context, inFlight, queued int)
+}
+
+// noopConcurrencyMetrics is the default ConcurrencyMetricsRecorder.
+type noopConcurrencyMetrics struct{}
+
+func (noopConcurrencyMetrics) RecordClusterSlotAcquisition(context.Context, string, time.Duration) {}
+func (noopConcurrencyMetrics) SetClusterOperationUsage(context.Context, int, int)                  {}
+
+// concurrencyLimiter is a semaphore with a bounded, timed queue.
+type concurrencyLimiter struct {
+	config  ConcurrencyConfig
+	metrics ConcurrencyMetricsRecorder
+	slots   chan struct{}
+
+	mu     sync.Mutex
+	queued int
+}
+
+// newConcurrencyLimiter creates a limiter, or returns nil if config has no limit.
+func newConcurrencyLimiter(config ConcurrencyConfig, metrics ConcurrencyMetricsRecorder) *concurrencyLimiter {
+	if config.MaxConcurrent <= 0 {
+		return nil
+	}
+	if metrics == nil {
+		metrics = noopConcurrencyMetrics{}
+	}
+	return &concurrencyLimiter{
+		config:  config,
+		metrics: metrics,
+		slots:   make(chan struct{}, config.MaxConcurrent),
+	}
+}
+
+// acquire waits for a slot. The returned function releases it.
+func (l *concurrencyLimiter) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.acquired(ctx, start)
+		return l.release, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.config.MaxQueued > 0 && l.queued >= l.config.MaxQueued {
+		l.mu.Unlock()
+		l.metrics.RecordClusterSlotAcquisition(ctx, ConcurrencyResultQueueFull, 0)
+		return nil, fmt.Errorf("%w: %d operations are already waiting", ErrConcurrencyLimitExceeded, l.config.MaxQueued)
+	}
+	l.queued++
+	l.mu.Unlock()
+	l.recordUsage(ctx)
+
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+		l.recordUsage(ctx)
+	}()
+
+	var timeout <-chan time.Time
+	if l.config.QueueTimeout > 0 {
+		timer := time.NewTimer(l.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.acquired(ctx, start)
+		return l.release, nil
+	case <-timeout:
+		l.metrics.RecordClusterSlotAcquisition(ctx, ConcurrencyResultTimeout, time.Since(start))
+		return nil, fmt.Errorf("%w: no slot became free within %s", ErrConcurrencyLimitExceeded, l.config.QueueTimeout)
+	case <-ctx.Done():
+		l.metrics.RecordClusterSlotAcquisition(ctx, ConcurrencyResultCanceled, time.Since(start))
+		return nil, fmt.Errorf("canceled while waiting for a cluster operation slot: %w", ctx.Err())
+	}
+}
+
+func (l *concurrencyLimiter) acquired(ctx context.Context, start time.Time) {
+	l.metrics.RecordClusterSlotAcquisition(ctx, ConcurrencyResultAcquired, time.Since(start))
+	l.recordUsage(ctx)
+}
+
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+	l.recordUsage(context.Background())
+}
+
+func (l *concurrencyLimiter) recordUsage(ctx context.Context) {
+	inFlight, queued := l.usage()
+	l.metrics.SetClusterOperationUsage(ctx, inFlight, queued)
+}
+
+// usage returns the number of operations holding a slot and waiting for one.
+func (l *concurrencyLimiter) usage() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.slots), l.queued
+}
+
+// acquireClusterSlot waits for a slot to contact a workload cluster. The
+// returned function releases it.
+func (m *Manager) acquireClusterSlot(ctx context.Context, clusterName string, user *UserInfo) (func(), error) {
+	if m.concurrencyLimiter == nil {
+		return func() {}, nil
+	}
+
+	release, err := m.concurrencyLimiter.acquire(ctx)
+	if errors.Is(err, ErrConcurrencyLimitExceeded) {
+		m.logger.Warn("Cluster operation rejected by the concurrency limit",
+			"cluster", clusterName,
+			UserHashAttr(user.Email),
+			"max_concurrent", m.concurrencyLimiter.config.MaxConcurrent,
+			"error", err)
+	}
+	return release, err
+}
+
+// maxConcurrentClusters returns the concurrency limit, or 0 if there is none.
+func (m *Manager) maxConcurrentClusters() int {
+	if m.concurrencyLimiter == nil {
+		return 0
+	}
+	return m.concurrencyLimiter.config.MaxConcurrent
+}
@@ -0,0 +1,211 @@
+package federation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// mockConcurrencyMetrics records concurrency limiter metrics for assertions.
+type mockConcurrencyMetrics struct {
+	mu       sync.Mutex
+	results  map[string]int
+	inFlight int
+	queued   int
+}
+
+func newMockConcurrencyMetrics() *mockConcurrencyMetrics {
+	return &mockConcurrencyMetrics{results: map[string]int{}}
+}
+
+func (m *mockConcurrencyMetrics) RecordClusterSlotAcquisition(_ context.Context, result string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[result]++
+}
+
+func (m *mockConcurrencyMetrics) SetClusterOperationUsage(_ context.Context, inFlight, queued int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight = inFlight
+	m.queued = queued
+}
+
+func (m *mockConcurrencyMetrics) resultCount(result string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.results[result]
+}
+
+func (m *mockConcurrencyMetrics) usage() (int, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inFlight, m.queued
+}
+
+func TestNewConcurrencyLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, newConcurrencyLimiter(ConcurrencyConfig{}, nil))
+	assert.Nil(t, newConcurrencyLimiter(ConcurrencyConfig{MaxConcurrent: -1}, nil))
+}
+
+func TestConcurrencyLimiter_Acquire(t *testing.T) {
+	ctx := context.Background()
+	metrics := newMockConcurrencyMetrics()
+	limiter := newConcurrencyLimiter(ConcurrencyConfig{MaxConcurrent: 2}, metrics)
+
+	release1, err := limiter.acquire(ctx)
+	require.NoError(t, err)
+	release2, err := limiter.acquire(ctx)
+	require.NoError(t, err)
+
+	inFlight, queued := limiter.usage()
+	assert.Equal(t, 2, inFlight)
+	assert.Equal(t, 0, queued)
+
+	// A third operation waits until a slot is released.
+	acquired := make(chan func())
+	go func() {
+		release, err := limiter.acquire(ctx)
+		assert.NoError(t, err)
+		acquired <- release
+	}()
+	require.Eventually(t, func() bool {
+		_, queued := limiter.usage()
+		return queued == 1
+	}, 5*time.Second, time.Millisecond)
+
+	release1()
+	release3 := <-acquired
+	release2()
+	release3()
+
+	inFlight, queued = limiter.usage()
+	assert.Equal(t, 0, inFlight)
+	assert.Equal(t, 0, queued)
+	assert.Equal(t, 3, metrics.resultCount(ConcurrencyResultAcquired))
+
+	inFlight, queued = metrics.usage()
+	assert.Equal(t, 0, inFlight)
+	assert.Equal(t, 0, queued)
+}
+
+func TestConcurrencyLimiter_Rejections(t *testing.T) {
+	t.Run("queue full", func(t *testing.T) {
+		metrics := newMockConcurrencyMetrics()
+		limiter := newConcurrencyLimiter(ConcurrencyConfig{MaxConcurrent: 1, MaxQueued: 1}, metrics)
+
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		waitCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = limiter.acquire(waitCtx)
+		}()
+		require.Eventually(t, func() bool {
+			_, queued := limiter.usage()
+			return queued == 1
+		}, 5*time.Second, time.Millisecond)
+
+		_, err = limiter.acquire(context.Background())
+		assert.ErrorIs(t, err, ErrConcurrencyLimitExceeded)
+		assert.Equal(t, 1, metrics.resultCount(ConcurrencyResultQueueFull))
+
+		cancel()
+		<-done
+		assert.Equal(t, 1, metrics.resultCount(ConcurrencyResultCanceled))
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		metrics := newMockConcurrencyMetrics()
+		limiter := newConcurrencyLimiter(ConcurrencyConfig{MaxConcurrent: 1, QueueTimeout: 10 * time.Millisecond}, metrics)
+
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		_, err = limiter.acquire(context.Background())
+		assert.ErrorIs(t, err, ErrConcurrencyLimitExceeded)
+		assert.Equal(t, 1, metrics.resultCount(ConcurrencyResultTimeout))
+
+		_, queued := limiter.usage()
+		assert.Equal(t, 0, queued)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		limiter := newConcurrencyLimiter(ConcurrencyConfig{MaxConcurrent: 1}, nil)
+
+		release, err := limiter.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = limiter.acquire(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrConcurrencyLimitExceeded)
+	})
+}
+
+func TestManager_ConcurrencyLimit(t *testing.T) {
+	ctx := context.Background()
+	metrics := newMockConcurrencyMetrics()
+
+	provider := &StaticClientProvider{
+		Clientset:     fake.NewClientset(),
+		DynamicClient: createTestFakeDynamicClient(runtime.NewScheme()),
+	}
+	manager, err := NewManager(provider,
+		WithManagerLogger(newTestLogger()),
+		WithConcurrencyLimit(ConcurrencyConfig{MaxConcurrent: 1, QueueTimeout: 10 * time.Millisecond}),
+		WithConcurrencyMetrics(metrics),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	// Occupy the only slot.
+	release, err := manager.concurrencyLimiter.acquire(ctx)
+	require.NoError(t, err)
+
+	stats := manager.Stats()
+	assert.Equal(t, 1, stats.ClusterOperationsInFlight)
+
+	_, err = manager.GetClient(ctx, "workload-cluster", testUser())
+	assert.ErrorIs(t, err, ErrConcurrencyLimitExceeded)
+	assert.Equal(t, 1, metrics.resultCount(ConcurrencyResultTimeout))
+
+	// The local cluster does not need a slot.
+	_, err = manager.GetClient(ctx, "", testUser())
+	assert.NoError(t, err)
+
+	release()
+
+	// With a free slot, the request gets as far as cluster lookup.
+	_, err = manager.GetClient(ctx, "workload-cluster", testUser())
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+	assert.Equal(t, 0, manager.Stats().ClusterOperationsInFlight, "the slot is released after client creation")
+}
+
+func TestManager_NoConcurrencyLimitByDefault(t *testing.T) {
+	manager, err := NewManager(&StaticClientProvider{Clientset: fake.NewClientset()},
+		WithManagerLogger(newTestLogger()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	assert.Nil(t, manager.concurrencyLimiter)
+	assert.Equal(t, 0, manager.maxConcurrentClusters())
+
+	release, err := manager.acquireClusterSlot(context.Background(), "workload-cluster", testUser())
+	require.NoError(t, err)
+	release()
+}
@@ -150,6 +150,7 @@ func clusterSummaryFromUnstructured(cluster *unstructured.Unstructured) ClusterS
 		Labels:      cluster.GetLabels(),
 		Annotations: cluster.GetAnnotations(),
 		CreatedAt:   cluster.GetCreationTimestamp().Time,
+		Source:      ClusterSourceCAPI,
 	}
 
 	// Extract provider from infrastructure reference
@@ -511,6 +512,10 @@ func (m *Manager) listClustersWithOptions(ctx context.Context, user *UserInfo, o
 		return nil, err
 	}
 
+	if clusters, ok := m.listClustersFromDiscoveryCache(ctx, user, opts); ok {
+		return clusters, nil
+	}
+
 	// Get dynamic client for CAPI discovery (privileged or user credentials)
 	dynamicClient, err := m.getDynamicClientForCAPIDiscovery(ctx, user)
 	if err != nil {
@@ -0,0 +1,504 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Default discovery cache settings.
+const (
+	// DefaultDiscoveryCacheResyncPeriod is how often the informer re-delivers
+	// every cached Cluster to its handlers.
+	DefaultDiscoveryCacheResyncPeriod = 10 * time.Minute
+
+	// DefaultDiscoveryCacheAccessTTL is how long per-user access decisions
+	// are cached.
+	DefaultDiscoveryCacheAccessTTL = time.Minute
+
+	// DefaultDiscoveryCacheSyncTimeout bounds how long a request waits for the
+	// initial sync before falling back to a live list.
+	DefaultDiscoveryCacheSyncTimeout = 10 * time.Second
+)
+
+// Discovery cache request results, as recorded by DiscoveryCacheMetricsRecorder.
+const (
+	// DiscoveryCacheResultHit means the request was served from the cache.
+	DiscoveryCacheResultHit = "hit"
+
+	// DiscoveryCacheResultFallback means the cache could not serve the request
+	// (not started, not synced or an access check failed) and the clusters
+	// were listed from the API server.
+	DiscoveryCacheResultFallback = "fallback"
+)
+
+// DiscoveryCacheConfig configures the CAPI cluster discovery cache.
+//
+// The cache watches CAPI Cluster resources with a single informer and keeps an
+// in-memory index of cluster summaries, so that ListClusters and
+// GetClusterSummary no longer list Cluster resources on every call. The index
+// is updated from watch events; the resync period only re-delivers cached
+// objects to the handlers.
+type DiscoveryCacheConfig struct {
+	// ResyncPeriod is how often the informer re-delivers every cached Cluster.
+	// Default: DefaultDiscoveryCacheResyncPeriod.
+	ResyncPeriod time.Duration
+
+	// AccessTTL is how long a user's access decisions are cached when
+	// discovery runs with user credentials (see Per-User Filtering on
+	// WithDiscoveryCache). Default: DefaultDiscoveryCacheAccessTTL.
+	AccessTTL time.Duration
+
+	// SyncTimeout bounds how long a request waits for the initial sync.
+	// Default: DefaultDiscoveryCacheSyncTimeout.
+	SyncTimeout time.Duration
+
+	// Client is the dynamic client the informer watches with. It must be able
+	// to list and watch Cluster resources in all namespaces. If nil, the
+	// privileged discovery client is used, which requires
+	// CredentialModeFullPrivileged.
+	Client dynamic.Interface
+}
+
+// DiscoveryCacheMetricsRecorder records discovery cache metrics.
+type DiscoveryCacheMetricsRecorder interface {
+	// RecordDiscoveryCacheRequest records a cluster discovery request.
+	// result: "hit" or "fallback"
+	RecordDiscoveryCacheRequest(ctx context.Context, result string)
+
+	// RecordDiscoveryCacheEvent records a watch event applied to the index.
+	// event: "add", "update" or "delete"
+	RecordDiscoveryCacheEvent(ctx context.Context, event string)
+
+	// SetDiscoveryCacheClusters sets the number of clusters in the index.
+	SetDiscoveryCacheClusters(ctx context.Context, count int)
+}
+
+// noopDiscoveryCacheMetrics is the default DiscoveryCacheMetricsRecorder.
+type noopDiscoveryCacheMetrics struct{}
+
+func (noopDiscoveryCacheMetrics) RecordDiscoveryCacheRequest(context.Context, string) {}
+func (noopDiscoveryCacheMetrics) RecordDiscoveryCacheEvent(context.Context, string)   {}
+func (noopDiscoveryCacheMetrics) SetDiscoveryCacheClusters(context.Context, int)      {}
+
+// discoveryCache holds the informer-backed index of CAPI clusters.
+type discoveryCache struct {
+	config  DiscoveryCacheConfig
+	metrics DiscoveryCacheMetricsRecorder
+	logger  *slog.Logger
+
+	// startMu guards starting the informer, which happens on first use so
+	// that the privileged client can be created with a requesting user for
+	// audit purposes. A failed start is retried on the next request.
+	startMu sync.Mutex
+	started bool
+	stopCh  chan struct{}
+	synced  chan struct{}
+
+	mu       sync.RWMutex
+	clusters map[string]ClusterSummary // keyed by namespace/name
+
+	accessMu sync.Mutex
+	access   map[string]*userClusterAccess // keyed by user email
+}
+
+// userClusterAccess caches which namespaces a user may list clusters in.
+type userClusterAccess struct {
+	expires    time.Time
+	all        bool
+	namespaces map[string]bool
+}
+
+// newDiscoveryCache creates a discovery cache with defaults applied.
+func newDiscoveryCache(config DiscoveryCacheConfig, metrics DiscoveryCacheMetricsRecorder, logger *slog.Logger) *discoveryCache {
+	if config.ResyncPeriod <= 0 {
+		config.ResyncPeriod = DefaultDiscoveryCacheResyncPeriod
+	}
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = DefaultDiscoveryCacheAccessTTL
+	}
+	if config.SyncTimeout <= 0 {
+		config.SyncTimeout = DefaultDiscoveryCacheSyncTimeout
+	}
+	if metrics == nil {
+		metrics = noopDiscoveryCacheMetrics{}
+	}
+
+	return &discoveryCache{
+		config:   config,
+		metrics:  metrics,
+		logger:   logger,
+		stopCh:   make(chan struct{}),
+		synced:   make(chan struct{}),
+		clusters: make(map[string]ClusterSummary),
+		access:   make(map[string]*userClusterAccess),
+	}
+}
+
+// start runs the informer with client unless it is already running.
+func (c *discoveryCache) start(client dynamic.Interface) error {
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+
+	if c.started {
+		return nil
+	}
+	select {
+	case <-c.stopCh:
+		return ErrManagerClosed
+	default:
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, c.config.ResyncPeriod)
+	informer := factory.ForResource(CAPIClusterGVR).Informer()
+
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { c.upsert(obj, "add") },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Resyncs re-deliver unchanged objects; skip them.
+			oldCluster, oldOK := oldObj.(*unstructured.Unstructured)
+			newCluster, newOK := newObj.(*unstructured.Unstructured)
+			if oldOK && newOK && oldCluster.GetResourceVersion() == newCluster.GetResourceVersion() {
+				return
+			}
+			c.upsert(newObj, "update")
+		},
+		DeleteFunc: c.remove,
+	}); err != nil {
+		return fmt.Errorf("failed to register discovery cache handler: %w", err)
+	}
+	if err := informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		c.logger.Warn("CAPI cluster watch failed, the discovery cache may be stale until it recovers",
+			"error", err)
+	}); err != nil {
+		return fmt.Errorf("failed to register discovery cache watch error handler: %w", err)
+	}
+
+	go informer.Run(c.stopCh)
+	go func() {
+		if cache.WaitForCacheSync(c.stopCh, informer.HasSynced) {
+			close(c.synced)
+			c.logger.Info("CAPI cluster discovery cache synced",
+				"clusters", c.size())
+		}
+	}()
+
+	c.started = true
+	c.logger.Info("CAPI cluster discovery cache started",
+		"resync_period", c.config.ResyncPeriod)
+	return nil
+}
+
+// stop stops the informer. It is safe to call more than once.
+func (c *discoveryCache) stop() {
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+
+	select {
+	case <-c.stopCh:
+	default:
+		close(c.stopCh)
+	}
+}
+
+// waitForSync waits for the initial sync, up to the sync timeout.
+func (c *discoveryCache) waitForSync(ctx context.Context) bool {
+	select {
+	case <-c.synced:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(c.config.SyncTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-c.synced:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	case <-c.stopCh:
+	}
+	return false
+}
+
+// isSynced reports whether the initial sync has completed.
+func (c *discoveryCache) isSynced() bool {
+	select {
+	case <-c.synced:
+		return true
+	default:
+		return false
+	}
+}
+
+// upsert adds or updates a cluster in the index.
+func (c *discoveryCache) upsert(obj interface{}, event string) {
+	cluster, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	c.clusters[cluster.GetNamespace()+"/"+cluster.GetName()] = clusterSummaryFromUnstructured(cluster)
+	size := len(c.clusters)
+	c.mu.Unlock()
+
+	ctx := context.Background()
+	c.metrics.RecordDiscoveryCacheEvent(ctx, event)
+	c.metrics.SetDiscoveryCacheClusters(ctx, size)
+}
+
+// remove deletes a cluster from the index.
+func (c *discoveryCache) remove(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cluster, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	delete(c.clusters, cluster.GetNamespace()+"/"+cluster.GetName())
+	size := len(c.clusters)
+	c.mu.Unlock()
+
+	ctx := context.Background()
+	c.metrics.RecordDiscoveryCacheEvent(ctx, "delete")
+	c.metrics.SetDiscoveryCacheClusters(ctx, size)
+}
+
+// size returns the number of clusters in the index.
+func (c *discoveryCache) size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.clusters)
+}
+
+// snapshot returns the cached clusters sorted by namespace and name.
+func (c *discoveryCache) snapshot() []ClusterSummary {
+	c.mu.RLock()
+	clusters := make([]ClusterSummary, 0, len(c.clusters))
+	for _, cluster := range c.clusters {
+		clusters = append(clusters, cluster)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Namespace != clusters[j].Namespace {
+			return clusters[i].Namespace < clusters[j].Namespace
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters
+}
+
+// cachedAccess returns a copy of the unexpired access decisions of a user.
+func (c *discoveryCache) cachedAccess(email string) *userClusterAccess {
+	c.accessMu.Lock()
+	defer c.accessMu.Unlock()
+
+	access, ok := c.access[email]
+	if !ok || time.Now().After(access.expires) {
+		return nil
+	}
+
+	copied := &userClusterAccess{
+		expires:    access.expires,
+		all:        access.all,
+		namespaces: make(map[string]bool, len(access.namespaces)),
+	}
+	for namespace, allowed := range access.namespaces {
+		copied.namespaces[namespace] = allowed
+	}
+	return copied
+}
+
+// storeAccess caches the access decisions of a user and drops expired
+// entries. Decisions added to a cached entry keep its expiry, so that no
+// decision outlives the access TTL.
+func (c *discoveryCache) storeAccess(email string, access *userClusterAccess) {
+	c.accessMu.Lock()
+	defer c.accessMu.Unlock()
+
+	now := time.Now()
+	for key, entry := range c.access {
+		if now.After(entry.expires) {
+			delete(c.access, key)
+		}
+	}
+	if access.expires.IsZero() {
+		access.expires = now.Add(c.config.AccessTTL)
+	}
+	c.access[email] = access
+}
+
+// listClustersFromDiscoveryCache returns the cached clusters visible to the
+// user, filtered by opts. It returns false if the cache cannot serve the
+// request and the caller should list the clusters from the API server.
+func (m *Manager) listClustersFromDiscoveryCache(ctx context.Context, user *UserInfo, opts *ClusterListOptions) ([]ClusterSummary, bool) {
+	c := m.discoveryCache
+	if c == nil {
+		return nil, false
+	}
+
+	if err := m.startDiscoveryCache(ctx, user); err != nil {
+		m.logger.Debug("Discovery cache unavailable, listing clusters from the API server",
+			UserHashAttr(user.Email),
+			"error", err)
+		c.metrics.RecordDiscoveryCacheRequest(ctx, DiscoveryCacheResultFallback)
+		return nil, false
+	}
+	if !c.waitForSync(ctx) {
+		m.logger.Debug("Discovery cache not synced, listing clusters from the API server",
+			UserHashAttr(user.Email))
+		c.metrics.RecordDiscoveryCacheRequest(ctx, DiscoveryCacheResultFallback)
+		return nil, false
+	}
+
+	var selector labels.Selector
+	if opts != nil && opts.LabelSelector != "" {
+		parsed, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			// Let the API server report the invalid selector.
+			c.metrics.RecordDiscoveryCacheRequest(ctx, DiscoveryCacheResultFallback)
+			return nil, false
+		}
+		selector = parsed
+	}
+
+	cached := c.snapshot()
+	access, err := m.userClusterAccess(ctx, user, cached)
+	if err != nil {
+		m.logger.Debug("Discovery cache access check failed, listing clusters from the API server",
+			UserHashAttr(user.Email),
+			"error", err)
+		c.metrics.RecordDiscoveryCacheRequest(ctx, DiscoveryCacheResultFallback)
+		return nil, false
+	}
+
+	clusters := make([]ClusterSummary, 0, len(cached))
+	for _, cluster := range cached {
+		if !access.all && !access.namespaces[cluster.Namespace] {
+			continue
+		}
+		if opts != nil {
+			if opts.Namespace != "" && cluster.Namespace != opts.Namespace {
+				continue
+			}
+			if selector != nil && !selector.Matches(labels.Set(cluster.Labels)) {
+				continue
+			}
+			if opts.Provider != "" && cluster.Provider != opts.Provider {
+				continue
+			}
+			if opts.Status != "" && cluster.Status != string(opts.Status) {
+				continue
+			}
+			if opts.ReadyOnly && !cluster.Ready {
+				continue
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	c.metrics.RecordDiscoveryCacheRequest(ctx, DiscoveryCacheResultHit)
+	return clusters, true
+}
+
+// startDiscoveryCache starts the informer on first use.
+func (m *Manager) startDiscoveryCache(ctx context.Context, user *UserInfo) error {
+	c := m.discoveryCache
+
+	c.startMu.Lock()
+	started := c.started
+	c.startMu.Unlock()
+	if started {
+		return nil
+	}
+
+	client := c.config.Client
+	if client == nil {
+		if m.credentialMode != CredentialModeFullPrivileged {
+			return fmt.Errorf("discovery cache requires privileged CAPI discovery or an explicit client")
+		}
+		privileged, err := m.privilegedProvider.GetPrivilegedDynamicClient(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to get privileged client for discovery cache: %w", err)
+		}
+		client = privileged
+	}
+
+	return c.start(client)
+}
+
+// userClusterAccess returns the namespaces the user may list clusters in.
+//
+// With CredentialModeFullPrivileged, live discovery uses ServiceAccount
+// credentials, so every user sees every cluster and so does the cache. In the
+// other modes live discovery uses the user's credentials; the cache mirrors
+// that with SelfSubjectAccessReviews for listing Cluster resources, first
+// cluster-wide and then per namespace of the cached clusters.
+func (m *Manager) userClusterAccess(ctx context.Context, user *UserInfo, clusters []ClusterSummary) (*userClusterAccess, error) {
+	if m.credentialMode == CredentialModeFullPrivileged {
+		return &userClusterAccess{all: true}, nil
+	}
+
+	c := m.discoveryCache
+	access := c.cachedAccess(user.Email)
+	changed := false
+	if access == nil {
+		allowed, err := m.canListClusters(ctx, user, "")
+		if err != nil {
+			return nil, err
+		}
+		access = &userClusterAccess{all: allowed, namespaces: make(map[string]bool)}
+		changed = true
+	}
+
+	// Check namespaces not decided yet, e.g. those of clusters created since
+	// the decisions were cached.
+	if !access.all {
+		for _, cluster := range clusters {
+			if _, decided := access.namespaces[cluster.Namespace]; decided {
+				continue
+			}
+			allowed, err := m.canListClusters(ctx, user, cluster.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			access.namespaces[cluster.Namespace] = allowed
+			changed = true
+		}
+	}
+
+	if changed {
+		c.storeAccess(user.Email, access)
+	}
+	return access, nil
+}
+
+// canListClusters checks whether the user may list CAPI Cluster resources in
+// namespace, or in all namespaces if namespace is empty.
+func (m *Manager) canListClusters(ctx context.Context, user *UserInfo, namespace string) (bool, error) {
+	result, err := m.CheckAccess(ctx, "", user, &AccessCheck{
+		Verb:      "list",
+		APIGroup:  CAPIClusterGVR.Group,
+		Resource:  CAPIClusterGVR.Resource,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.Allowed, nil
+}
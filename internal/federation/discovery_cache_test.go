@@ -0,0 +1,232 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// mockDiscoveryCacheMetrics records discovery cache metrics for assertions.
+type mockDiscoveryCacheMetrics struct {
+	mu       sync.Mutex
+	requests map[string]int
+	events   map[string]int
+	clusters int
+}
+
+func newMockDiscoveryCacheMetrics() *mockDiscoveryCacheMetrics {
+	return &mockDiscoveryCacheMetrics{requests: map[string]int{}, events: map[string]int{}}
+}
+
+func (m *mockDiscoveryCacheMetrics) RecordDiscoveryCacheRequest(_ context.Context, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[result]++
+}
+
+func (m *mockDiscoveryCacheMetrics) RecordDiscoveryCacheEvent(_ context.Context, event string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[event]++
+}
+
+func (m *mockDiscoveryCacheMetrics) SetDiscoveryCacheClusters(_ context.Context, count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clusters = count
+}
+
+func (m *mockDiscoveryCacheMetrics) requestCount(result string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests[result]
+}
+
+func (m *mockDiscoveryCacheMetrics) eventCounts() (map[string]int, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := make(map[string]int, len(m.events))
+	for event, count := range m.events {
+		events[event] = count
+	}
+	return events, m.clusters
+}
+
+func clusterNames(clusters []ClusterSummary) []string {
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
+
+func TestDiscoveryCache_WatchUpdates(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+
+	dynamicClient := createTestFakeDynamicClient(runtime.NewScheme(), createTestCAPICluster("prod-a", "org-acme"))
+	provider := &mockPrivilegedStaticProvider{
+		userClientset:           fake.NewClientset(),
+		privilegedDynamicClient: dynamicClient,
+		privilegedCAPIDiscovery: true,
+	}
+	metrics := newMockDiscoveryCacheMetrics()
+
+	manager, err := NewManager(provider,
+		WithManagerLogger(newTestLogger()),
+		WithPrivilegedAccess(provider),
+		WithDiscoveryCache(DiscoveryCacheConfig{}),
+		WithDiscoveryCacheMetrics(metrics),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	clusters, err := manager.ListClusters(ctx, user)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod-a"}, clusterNames(clusters))
+	assert.Equal(t, 1, metrics.requestCount(DiscoveryCacheResultHit))
+
+	clusterResource := dynamicClient.Resource(CAPIClusterGVR).Namespace("org-acme")
+
+	// Added clusters appear without a new list.
+	_, err = clusterResource.Create(ctx,
+		createTestCAPIClusterWithDetails("prod-b", "org-acme", withLabels(map[string]string{"env": "prod"})),
+		metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		summary, err := manager.GetClusterSummary(ctx, "prod-b", user)
+		return err == nil && summary.Labels["env"] == "prod"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Updates are applied to the index.
+	updated := createTestCAPIClusterWithDetails("prod-b", "org-acme", withLabels(map[string]string{"env": "staging"}))
+	updated.SetResourceVersion("2")
+	_, err = clusterResource.Update(ctx, updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		clusters, err := manager.listClustersWithOptions(ctx, user, &ClusterListOptions{LabelSelector: "env=staging"})
+		return err == nil && len(clusters) == 1 && clusters[0].Name == "prod-b"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Deleted clusters disappear.
+	require.NoError(t, clusterResource.Delete(ctx, "prod-a", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		_, err := manager.GetClusterSummary(ctx, "prod-a", user)
+		return errors.Is(err, ErrClusterNotFound)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stats := manager.Stats()
+	assert.True(t, stats.DiscoveryCacheSynced)
+	assert.Equal(t, 1, stats.DiscoveryCacheClusters)
+	assert.Equal(t, 0, metrics.requestCount(DiscoveryCacheResultFallback))
+	events, size := metrics.eventCounts()
+	assert.Equal(t, map[string]int{"add": 2, "update": 1, "delete": 1}, events)
+	assert.Equal(t, 1, size)
+	assert.Equal(t, 1, provider.privilegedDynamicCalls, "the informer is started once")
+}
+
+func TestDiscoveryCache_PerUserFiltering(t *testing.T) {
+	ctx := context.Background()
+
+	var reviews atomic.Int32
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews.Add(1)
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		allowed := attrs.Verb == "list" && attrs.Resource == "clusters" && attrs.Namespace == "org-acme"
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed},
+		}, nil
+	})
+
+	watchClient := createTestFakeDynamicClient(runtime.NewScheme(),
+		createTestCAPICluster("acme-a", "org-acme"),
+		createTestCAPICluster("acme-b", "org-acme"),
+		createTestCAPICluster("other-a", "org-other"),
+	)
+	manager, err := NewManager(&StaticClientProvider{Clientset: clientset},
+		WithManagerLogger(newTestLogger()),
+		WithDiscoveryCache(DiscoveryCacheConfig{Client: watchClient}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	clusters, err := manager.ListClusters(ctx, testUser())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme-a", "acme-b"}, clusterNames(clusters))
+	// One cluster-wide review and one per namespace.
+	assert.Equal(t, int32(3), reviews.Load())
+
+	_, err = manager.GetClusterSummary(ctx, "other-a", testUser())
+	assert.ErrorIs(t, err, ErrClusterNotFound)
+
+	// Decisions are cached.
+	_, err = manager.ListClusters(ctx, testUser())
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), reviews.Load())
+}
+
+func TestDiscoveryCache_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no watch client outside full privileged mode", func(t *testing.T) {
+		metrics := newMockDiscoveryCacheMetrics()
+		provider := &StaticClientProvider{
+			Clientset:     fake.NewClientset(),
+			DynamicClient: createTestFakeDynamicClient(runtime.NewScheme(), createTestCAPICluster("prod-a", "org-acme")),
+		}
+		manager, err := NewManager(provider,
+			WithManagerLogger(newTestLogger()),
+			WithDiscoveryCache(DiscoveryCacheConfig{}),
+			WithDiscoveryCacheMetrics(metrics),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = manager.Close() })
+
+		clusters, err := manager.ListClusters(ctx, testUser())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-a"}, clusterNames(clusters))
+		assert.Equal(t, 1, metrics.requestCount(DiscoveryCacheResultFallback))
+		assert.False(t, manager.Stats().DiscoveryCacheSynced)
+	})
+
+	t.Run("invalid label selector", func(t *testing.T) {
+		metrics := newMockDiscoveryCacheMetrics()
+		dynamicClient := createTestFakeDynamicClient(runtime.NewScheme(), createTestCAPICluster("prod-a", "org-acme"))
+		manager, err := NewManager(&StaticClientProvider{Clientset: fake.NewClientset(), DynamicClient: dynamicClient},
+			WithManagerLogger(newTestLogger()),
+			WithDiscoveryCache(DiscoveryCacheConfig{Client: dynamicClient}),
+			WithDiscoveryCacheMetrics(metrics),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = manager.Close() })
+
+		_, ok := manager.listClustersFromDiscoveryCache(ctx, testUser(), &ClusterListOptions{LabelSelector: "a in (b"})
+		assert.False(t, ok)
+		assert.Equal(t, 1, metrics.requestCount(DiscoveryCacheResultFallback))
+	})
+}
+
+func TestDiscoveryCache_NotStartedAfterClose(t *testing.T) {
+	dynamicClient := createTestFakeDynamicClient(runtime.NewScheme())
+	manager, err := NewManager(&StaticClientProvider{Clientset: fake.NewClientset(), DynamicClient: dynamicClient},
+		WithManagerLogger(newTestLogger()),
+		WithDiscoveryCache(DiscoveryCacheConfig{Client: dynamicClient}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, manager.Close())
+
+	assert.ErrorIs(t, manager.discoveryCache.start(dynamicClient), ErrManagerClosed)
+}
@@ -38,6 +38,33 @@
 //	// List all available clusters
 //	clusters, err := manager.ListClusters(ctx, userInfo)
 //
+// # Cluster Sources
+//
+// Workload clusters and their admin kubeconfigs come from ClusterSource
+// implementations, asked in order. By default the Manager uses only the CAPI
+// source, which discovers CAPI Cluster resources and reads their
+// ${CLUSTER_NAME}-kubeconfig secrets. Fleets that are not managed by Cluster
+// API can add other sources:
+//
+//   - KubeconfigClusterSource: static kubeconfig files, one cluster per context
+//   - SecretClusterSource: Secrets selected by a label selector, such as the
+//     kubeconfig Secrets of Rancher or Gardener (see WithSecretClusterSource)
+//
+// Use WithClusterSources to add sources and WithCAPIClusterSource(false) to
+// drop the CAPI source. Impersonation is applied to every source alike; SSO
+// passthrough needs the CAPI source, as it reads the cluster endpoint from the
+// CAPI Cluster resource.
+//
+// # Discovery Cache
+//
+// WithDiscoveryCache serves CAPI cluster discovery from an in-memory index
+// kept up to date by a single informer on Cluster resources, instead of
+// listing them on every ListClusters and GetClusterSummary call. The informer
+// watches with ServiceAccount credentials; in credential modes where discovery
+// uses the user's credentials, the index is filtered per user with
+// SelfSubjectAccessReviews. Requests fall back to live listing until the
+// cache has synced.
+//
 // # Security Model
 //
 // The federation package enforces security through defense in depth:
@@ -135,6 +162,12 @@
 //   - Each translation is logged at Info level for operational visibility
 //   - Mapping to dangerous system groups (e.g., system:masters) is rejected at startup
 //
+// Large group lists can exceed API server and proxy header limits, and IdPs often
+// include internal groups that mean nothing to workload clusters. The GroupMapper
+// can also strip prefixes (WC_GROUP_STRIP_PREFIXES) and keep only groups matching
+// a regex allowlist (WC_GROUP_ALLOW_PATTERNS) before mapping. The agent extra is
+// added after filtering and cannot be removed by it.
+//
 // Group mapping is only applied in impersonation mode. In SSO passthrough mode,
 // the workload cluster's own OIDC configuration handles group resolution.
 //
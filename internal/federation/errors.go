@@ -60,6 +60,11 @@ var (
 	//   - The token extractor is not properly configured
 	ErrSSOTokenMissing = errors.New("SSO token not available in context for passthrough authentication")
 
+	// ErrTokenExchangeFailed indicates that the IdP refused or failed to exchange
+	// the user's token for a token with the workload cluster's audience
+	// (see AnnotationTokenExchangeAudience).
+	ErrTokenExchangeFailed = errors.New("token exchange failed")
+
 	// ErrTLSHandshakeFailed indicates that the TLS handshake with the cluster failed.
 	// Common causes include:
 	//   - Certificate signed by unknown authority
@@ -73,6 +78,11 @@ var (
 	//   - Network latency is too high
 	//   - The cluster is not running
 	ErrConnectionTimeout = errors.New("connection timeout")
+
+	// ErrConcurrencyLimitExceeded indicates that too many workload clusters
+	// are being contacted at the same time and the operation could not get a
+	// slot (see WithConcurrencyLimit).
+	ErrConcurrencyLimitExceeded = errors.New("too many concurrent cluster operations")
 )
 
 // userFacingClusterError is the standardized message returned to users for all
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"regexp"
 	"sort"
 	"strings"
 )
@@ -14,6 +15,10 @@ const (
 	// configurations that would slow down startup validation and produce verbose
 	// warning logs. 100 mappings is generous for any real-world deployment.
 	MaxMappingCount = 100
+
+	// MaxGroupFilterCount is the maximum number of allow patterns and of strip
+	// prefixes each. Every group of every request is checked against them.
+	MaxGroupFilterCount = 50
 )
 
 // GroupMapper translates OIDC group identifiers to the identifiers expected by
@@ -62,32 +67,86 @@ const (
 // GroupMapper is immutable after construction and safe for concurrent use
 // from multiple goroutines. The mapping table is never modified after creation.
 //
+// # Filtering
+//
+// Users in large organizations can carry hundreds of groups, most of them
+// irrelevant to Kubernetes RBAC. Sent as Impersonate-Group headers they can
+// exceed API server and proxy header limits. Two optional filters (see
+// WithGroupStripPrefixes and WithGroupAllowPatterns) reduce the list before
+// it is mapped. Each group is processed in this order:
+//
+//  1. Prefix stripping: the first matching prefix is removed
+//     (e.g. "oidc:" turns "oidc:platform" into "platform").
+//  2. Allowlist: the group is dropped unless it fully matches one of the
+//     allow patterns. Without patterns, all groups are kept.
+//  3. Mapping: the (stripped) group is translated via the mapping table.
+//
+// Groups that end up identical are sent once. A stripped group that is not
+// mapped and lands on a denied target group is dropped, so prefix stripping
+// cannot be used to reach system:masters.
+//
 // # Behavior
 //
 //   - Mapped groups: translated to their target identifiers
 //   - Unmapped groups: passed through unchanged (backward compatible)
-//   - Empty mapping: all groups pass through unchanged (no-op)
+//   - Empty mapping and no filters: all groups pass through unchanged (no-op)
 //   - Nil/empty groups: returned as-is
 type GroupMapper struct {
 	// mappings is the source-group -> target-group map.
 	// This map is never modified after construction (immutable).
 	mappings map[string]string
 
+	// stripPrefixes are removed from the start of groups, first match wins.
+	stripPrefixes []string
+
+	// allowPatterns are the configured allowlist expressions; allow holds
+	// them compiled and anchored. Empty means all groups are allowed.
+	allowPatterns []string
+	allow         []*regexp.Regexp
+
 	// logger for logging group translations.
 	logger *slog.Logger
 }
 
-// NewGroupMapper creates a new GroupMapper with the given mappings.
-// Returns nil if the mappings are empty (no-op optimization).
+// GroupMapperOption configures optional group filtering on a GroupMapper.
+type GroupMapperOption func(*GroupMapper)
+
+// WithGroupStripPrefixes removes the first matching prefix from each group
+// before it is filtered and mapped, e.g. the "oidc:" prefix added by
+// --oidc-groups-prefix style IdP configurations.
+func WithGroupStripPrefixes(prefixes ...string) GroupMapperOption {
+	return func(gm *GroupMapper) {
+		gm.stripPrefixes = append([]string(nil), prefixes...)
+	}
+}
+
+// WithGroupAllowPatterns keeps only groups that fully match at least one of
+// the given regular expressions (RE2 syntax, implicitly anchored). Use it to
+// exclude IdP-internal groups and to keep the Impersonate-Group headers small.
+func WithGroupAllowPatterns(patterns ...string) GroupMapperOption {
+	return func(gm *GroupMapper) {
+		gm.allowPatterns = append([]string(nil), patterns...)
+	}
+}
+
+// NewGroupMapper creates a new GroupMapper with the given mappings and
+// filter options. Returns nil if there are neither mappings nor filters
+// (no-op optimization).
 //
 // The mappings are defensively copied before validation to prevent external
 // mutation. After construction, the GroupMapper is immutable and safe for
 // concurrent use.
 //
 // Returns an error if the mappings are invalid (e.g., empty keys or values,
-// multiple source groups mapping to the same target, dangerous target groups).
-func NewGroupMapper(mappings map[string]string, logger *slog.Logger) (*GroupMapper, error) {
-	if len(mappings) == 0 {
+// multiple source groups mapping to the same target, dangerous target groups)
+// or a filter is invalid (empty prefix, pattern that does not compile).
+func NewGroupMapper(mappings map[string]string, logger *slog.Logger, opts ...GroupMapperOption) (*GroupMapper, error) {
+	gm := &GroupMapper{}
+	for _, opt := range opts {
+		opt(gm)
+	}
+
+	if len(mappings) == 0 && len(gm.stripPrefixes) == 0 && len(gm.allowPatterns) == 0 {
 		return nil, nil
 	}
 
@@ -95,6 +154,11 @@ func NewGroupMapper(mappings map[string]string, logger *slog.Logger) (*GroupMapp
 		logger = slog.Default()
 	}
 
+	allow, err := compileGroupFilters(gm.stripPrefixes, gm.allowPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group filters: %w", err)
+	}
+
 	// Defensive copy first, then validate the copy (not the original).
 	// This ensures we validate exactly what we store.
 	copied := make(map[string]string, len(mappings))
@@ -117,71 +181,192 @@ func NewGroupMapper(mappings map[string]string, logger *slog.Logger) (*GroupMapp
 		}
 	}
 
-	return &GroupMapper{
-		mappings: copied,
-		logger:   logger,
-	}, nil
+	gm.mappings = copied
+	gm.allow = allow
+	gm.logger = logger
+	return gm, nil
+}
+
+// compileGroupFilters validates the strip prefixes and compiles the allow
+// patterns, anchored so that a pattern has to match the whole group.
+func compileGroupFilters(prefixes, patterns []string) ([]*regexp.Regexp, error) {
+	if len(prefixes) > MaxGroupFilterCount {
+		return nil, fmt.Errorf("too many strip prefixes (%d): maximum is %d", len(prefixes), MaxGroupFilterCount)
+	}
+	if len(patterns) > MaxGroupFilterCount {
+		return nil, fmt.Errorf("too many allow patterns (%d): maximum is %d", len(patterns), MaxGroupFilterCount)
+	}
+
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			return nil, fmt.Errorf("strip prefix must not be empty")
+		}
+		if containsControlCharacters(prefix) {
+			return nil, fmt.Errorf("strip prefix %q contains control characters", prefix)
+		}
+	}
+
+	allow := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("allow pattern must not be empty")
+		}
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("allow pattern %q: %w", pattern, err)
+		}
+		allow = append(allow, re)
+	}
+	return allow, nil
 }
 
-// MapGroups translates a slice of OIDC group identifiers using the configured mappings.
-// Groups that have a mapping are translated; groups without a mapping pass through unchanged.
+// MapGroups applies prefix stripping, the allowlist and the mappings to a
+// slice of OIDC group identifiers. Without filters, groups that have a mapping
+// are translated and all others pass through unchanged.
 //
-// The original slice is never modified. A new slice is returned when any mapping is applied.
+// The original slice is never modified. A new slice is returned when any group
+// is translated, stripped or dropped.
 //
-// Returns the mapped groups and a boolean indicating whether any group was actually
-// translated. When mapped is true, the caller should record the original groups in the
-// impersonation Extra headers (via OriginalGroupsExtraKey) so the Kubernetes audit log
-// on the workload cluster contains a complete audit trail.
+// Returns the resulting groups and a boolean indicating whether they differ from
+// the input. Callers building impersonation configs should use ImpersonationUser,
+// which also records the original groups in the impersonation Extra headers (via
+// OriginalGroupsExtraKey) so the Kubernetes audit log on the workload cluster
+// contains a complete audit trail.
 //
 // A summary of all translations is logged at Info level for operational visibility.
 // The user email is hashed in logs (via UserHashAttr) for privacy.
 //
 // Returns (nil, false) if groups is nil, or (empty, false) if groups is empty.
 func (gm *GroupMapper) MapGroups(groups []string, userEmail string) ([]string, bool) {
-	if gm == nil || len(gm.mappings) == 0 {
-		return groups, false
+	result, _, _, changed := gm.apply(groups, userEmail)
+	return result, changed
+}
+
+// ImpersonationUser returns the user to impersonate on workload clusters:
+// user with its groups filtered and mapped. When any group was renamed by
+// stripping or mapping, the original identifiers of the kept groups are
+// recorded under OriginalGroupsExtraKey. Groups dropped by the allowlist are
+// left out of the extras too, so they do not reappear in the headers.
+//
+// user itself is never modified; it is returned as is when nothing changes
+// or the mapper is nil.
+func (gm *GroupMapper) ImpersonationUser(user *UserInfo) *UserInfo {
+	if gm == nil || user == nil {
+		return user
 	}
 
-	if groups == nil {
-		return nil, false
+	groups, originals, renamed, changed := gm.apply(user.Groups, user.Email)
+	if !changed {
+		return user
 	}
 
-	if len(groups) == 0 {
-		return groups, false
+	// Deep copy slice values to prevent any downstream mutation from
+	// affecting the original UserInfo (defense-in-depth for security path).
+	extra := make(map[string][]string, len(user.Extra)+1)
+	for k, v := range user.Extra {
+		copied := make([]string, len(v))
+		copy(copied, v)
+		extra[k] = copied
+	}
+	if renamed {
+		extra[OriginalGroupsExtraKey] = originals
 	}
 
-	// Check if any mapping applies before allocating a new slice
-	anyMapped := false
-	for _, g := range groups {
-		if _, ok := gm.mappings[g]; ok {
-			anyMapped = true
-			break
-		}
+	return &UserInfo{
+		Email:  user.Email,
+		Groups: groups,
+		Extra:  extra,
 	}
+}
 
-	if !anyMapped {
-		return groups, false
+// apply runs the strip, allow and map steps over groups. It returns the
+// resulting groups, the original identifiers of the groups that were kept,
+// whether any kept group was renamed, and whether the result differs from
+// groups at all.
+func (gm *GroupMapper) apply(groups []string, userEmail string) (result, originals []string, renamed, changed bool) {
+	if gm == nil || len(groups) == 0 {
+		return groups, groups, false, false
 	}
 
-	// At least one group needs mapping: create a new slice
-	mapped := make([]string, len(groups))
-	var translations []string
-	for i, g := range groups {
-		if target, ok := gm.mappings[g]; ok {
-			mapped[i] = target
-			translations = append(translations, fmt.Sprintf("%s->%s", g, target))
-		} else {
-			mapped[i] = g
+	result = make([]string, 0, len(groups))
+	originals = make([]string, 0, len(groups))
+	seen := make(map[string]struct{}, len(groups))
+	var translations, dropped []string
+
+	for _, original := range groups {
+		group, stripped := gm.stripPrefix(original)
+		if group == "" || !gm.allowed(group) {
+			dropped = append(dropped, original)
+			continue
+		}
+
+		if target, ok := gm.mappings[group]; ok {
+			translations = append(translations, fmt.Sprintf("%s->%s", original, target))
+			group = target
+		} else if _, denied := deniedTargetGroups[group]; denied && stripped {
+			// Mapping targets are checked at startup; stripping is not, and
+			// must not turn an IdP group into a privileged system group.
+			gm.logger.Warn("Dropped group that becomes a denied system group after prefix stripping",
+				"group", original,
+				UserHashAttr(userEmail))
+			dropped = append(dropped, original)
+			continue
+		}
+
+		if group != original {
+			renamed = true
+		}
+		if _, dup := seen[group]; dup {
+			changed = true
+			continue
 		}
+		seen[group] = struct{}{}
+		result = append(result, group)
+		originals = append(originals, original)
+	}
+
+	if !renamed && !changed && len(dropped) == 0 {
+		return groups, groups, false, false
 	}
 
-	gm.logger.Info("Groups mapped for impersonation",
-		"mapped_count", len(translations),
-		"total_groups", len(groups),
-		"translations", strings.Join(translations, ", "),
-		UserHashAttr(userEmail))
+	if len(translations) > 0 {
+		gm.logger.Info("Groups mapped for impersonation",
+			"mapped_count", len(translations),
+			"total_groups", len(groups),
+			"translations", strings.Join(translations, ", "),
+			UserHashAttr(userEmail))
+	}
+	if len(dropped) > 0 {
+		gm.logger.Debug("Groups filtered for impersonation",
+			"dropped_count", len(dropped),
+			"kept_count", len(result),
+			UserHashAttr(userEmail))
+	}
+
+	return result, originals, renamed, true
+}
+
+// stripPrefix removes the first configured prefix that group starts with.
+func (gm *GroupMapper) stripPrefix(group string) (string, bool) {
+	for _, prefix := range gm.stripPrefixes {
+		if strings.HasPrefix(group, prefix) {
+			return strings.TrimPrefix(group, prefix), true
+		}
+	}
+	return group, false
+}
 
-	return mapped, true
+// allowed reports whether group passes the allowlist.
+func (gm *GroupMapper) allowed(group string) bool {
+	if len(gm.allow) == 0 {
+		return true
+	}
+	for _, re := range gm.allow {
+		if re.MatchString(group) {
+			return true
+		}
+	}
+	return false
 }
 
 // MappingCount returns the number of configured group mappings.
@@ -198,7 +383,11 @@ func (gm *GroupMapper) String() string {
 	if gm == nil {
 		return "GroupMapper{disabled}"
 	}
-	return fmt.Sprintf("GroupMapper{mappings=%d}", len(gm.mappings))
+	if len(gm.stripPrefixes) == 0 && len(gm.allow) == 0 {
+		return fmt.Sprintf("GroupMapper{mappings=%d}", len(gm.mappings))
+	}
+	return fmt.Sprintf("GroupMapper{mappings=%d, stripPrefixes=%d, allowPatterns=%d}",
+		len(gm.mappings), len(gm.stripPrefixes), len(gm.allow))
 }
 
 // deniedTargetGroups contains Kubernetes groups that must never be used as mapping
@@ -311,6 +500,22 @@ func ParseGroupMappingsJSON(jsonStr string) (map[string]string, error) {
 	return mappings, nil
 }
 
+// ParseGroupListJSON parses a JSON array of strings, the format of the
+// WC_GROUP_STRIP_PREFIXES and WC_GROUP_ALLOW_PATTERNS environment variables.
+// JSON is used because regular expressions may contain commas.
+func ParseGroupListJSON(jsonStr string) ([]string, error) {
+	if jsonStr == "" {
+		return nil, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal([]byte(jsonStr), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse group list JSON: %w", err)
+	}
+
+	return list, nil
+}
+
 // FormatGroupMappingsForLog returns a human-readable representation of group mappings
 // for operator logs. It includes the mapping count and source group names (sorted
 // alphabetically) to help operators verify the configuration. Note that source
@@ -505,6 +505,111 @@ func TestParseGroupMappingsJSON(t *testing.T) {
 	})
 }
 
+func TestParseGroupListJSON(t *testing.T) {
+	result, err := ParseGroupListJSON("")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = ParseGroupListJSON(`["team-.*", "ops-(sre|oncall)", "a,b"]`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-.*", "ops-(sre|oncall)", "a,b"}, result)
+
+	_, err = ParseGroupListJSON(`"team-.*"`)
+	assert.ErrorContains(t, err, "failed to parse group list JSON")
+}
+
+func TestGroupMapper_Filters(t *testing.T) {
+	t.Run("filters alone create a mapper", func(t *testing.T) {
+		mapper, err := NewGroupMapper(nil, slog.Default(), WithGroupAllowPatterns("team-.*"))
+		require.NoError(t, err)
+		require.NotNil(t, mapper)
+		assert.Equal(t, "GroupMapper{mappings=0, stripPrefixes=0, allowPatterns=1}", mapper.String())
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		_, err := NewGroupMapper(nil, slog.Default(), WithGroupAllowPatterns("team-("))
+		assert.ErrorContains(t, err, "allow pattern")
+		_, err = NewGroupMapper(nil, slog.Default(), WithGroupAllowPatterns(" "))
+		assert.ErrorContains(t, err, "allow pattern must not be empty")
+		_, err = NewGroupMapper(nil, slog.Default(), WithGroupStripPrefixes(""))
+		assert.ErrorContains(t, err, "strip prefix must not be empty")
+		_, err = NewGroupMapper(nil, slog.Default(), WithGroupStripPrefixes("oidc:\n"))
+		assert.ErrorContains(t, err, "control characters")
+	})
+
+	t.Run("allowlist patterns match whole groups", func(t *testing.T) {
+		mapper, err := NewGroupMapper(nil, slog.Default(), WithGroupAllowPatterns("team-[a-z]+", "admins"))
+		require.NoError(t, err)
+
+		result, changed := mapper.MapGroups([]string{"team-sre", "team-sre-internal", "admins", "sysadmins", "idp:internal"}, "user@example.com")
+		assert.True(t, changed)
+		assert.Equal(t, []string{"team-sre", "admins"}, result)
+
+		result, changed = mapper.MapGroups([]string{"team-sre", "admins"}, "user@example.com")
+		assert.False(t, changed)
+		assert.Equal(t, []string{"team-sre", "admins"}, result)
+	})
+
+	t.Run("prefixes are stripped before filtering and mapping", func(t *testing.T) {
+		mapper, err := NewGroupMapper(map[string]string{"platform": "guid-platform"}, slog.Default(),
+			WithGroupStripPrefixes("oidc:", "dex:"),
+			WithGroupAllowPatterns("platform|team-.*"))
+		require.NoError(t, err)
+
+		result, changed := mapper.MapGroups([]string{"oidc:platform", "dex:team-a", "team-b", "oidc:other", "oidc:team-a"}, "user@example.com")
+		assert.True(t, changed)
+		assert.Equal(t, []string{"guid-platform", "team-a", "team-b"}, result, "duplicates after stripping are sent once")
+	})
+
+	t.Run("stripping cannot produce a denied group", func(t *testing.T) {
+		mapper, err := NewGroupMapper(nil, slog.Default(), WithGroupStripPrefixes("oidc:"))
+		require.NoError(t, err)
+
+		result, _ := mapper.MapGroups([]string{"oidc:system:masters", "oidc:dev", "system:authenticated"}, "user@example.com")
+		assert.Equal(t, []string{"dev", "system:authenticated"}, result)
+	})
+}
+
+func TestGroupMapper_ImpersonationUser(t *testing.T) {
+	user := &UserInfo{
+		Email:  "user@example.com",
+		Groups: []string{"oidc:platform", "oidc:idp-internal", "team-a"},
+		Extra:  map[string][]string{"tenant": {"acme"}},
+	}
+
+	t.Run("nil mapper returns the user", func(t *testing.T) {
+		var mapper *GroupMapper
+		assert.Same(t, user, mapper.ImpersonationUser(user))
+	})
+
+	t.Run("renamed groups record the kept originals", func(t *testing.T) {
+		mapper, err := NewGroupMapper(nil, slog.Default(),
+			WithGroupStripPrefixes("oidc:"),
+			WithGroupAllowPatterns("platform|team-.*"))
+		require.NoError(t, err)
+
+		got := mapper.ImpersonationUser(user)
+		assert.Equal(t, []string{"platform", "team-a"}, got.Groups)
+		assert.Equal(t, []string{"oidc:platform", "team-a"}, got.Extra[OriginalGroupsExtraKey],
+			"filtered groups are not reintroduced via the extras")
+		assert.Equal(t, []string{"acme"}, got.Extra["tenant"])
+		assert.Equal(t, []string{"oidc:platform", "oidc:idp-internal", "team-a"}, user.Groups, "user is not modified")
+
+		// The agent extra is added after filtering and stays immutable.
+		config := ConfigWithImpersonation(&rest.Config{Host: "https://test.example.com"}, got)
+		assert.Equal(t, []string{ImpersonationAgentName}, config.Impersonate.Extra[ImpersonationAgentExtraKey])
+	})
+
+	t.Run("filtering alone records no originals", func(t *testing.T) {
+		mapper, err := NewGroupMapper(nil, slog.Default(), WithGroupAllowPatterns("team-.*"))
+		require.NoError(t, err)
+
+		got := mapper.ImpersonationUser(user)
+		assert.Equal(t, []string{"team-a"}, got.Groups)
+		assert.NotContains(t, got.Extra, OriginalGroupsExtraKey)
+	})
+}
+
 func TestFormatGroupMappingsForLog(t *testing.T) {
 	t.Run("empty mappings", func(t *testing.T) {
 		assert.Equal(t, "none", FormatGroupMappingsForLog(nil))
@@ -36,11 +36,21 @@ type ClusterInfo struct {
 	// Populated from spec.controlPlaneEndpoint when available. Empty if the cluster
 	// resource does not have a control plane endpoint set.
 	Endpoint string
+
+	// TokenAudience is the audience the cluster's API server expects in user
+	// tokens, from the AnnotationTokenExchangeAudience annotation. Empty if
+	// the cluster accepts the user's token as is.
+	TokenAudience string
 }
 
-// GetKubeconfigForCluster retrieves the kubeconfig secret for a CAPI cluster
+// GetKubeconfigForCluster retrieves the admin kubeconfig for a workload cluster
 // and returns a rest.Config suitable for creating clients.
 //
+// The configured cluster sources are asked in order (see WithClusterSources);
+// the first source that knows the cluster provides the kubeconfig. The
+// security model below describes the default CAPI source, which reads the
+// cluster's kubeconfig secret.
+//
 // # Security Model
 //
 // This method implements a split-credential model for enhanced security:
@@ -86,6 +96,14 @@ func (m *Manager) GetKubeconfigForCluster(ctx context.Context, clusterName strin
 		}
 	}
 
+	return m.getKubeconfigFromSources(ctx, clusterName, user)
+}
+
+// getCAPIKubeconfig retrieves the admin kubeconfig of a CAPI cluster from its
+// ${CLUSTER_NAME}-kubeconfig secret. It implements GetKubeconfig of the
+// built-in CAPI cluster source; see GetKubeconfigForCluster for the security
+// model.
+func (m *Manager) getCAPIKubeconfig(ctx context.Context, clusterName string, user *UserInfo) (*rest.Config, error) {
 	// Get a dynamic client for CAPI cluster discovery.
 	// This uses the same split-credential strategy as the CAPI tools:
 	// 1. Try ServiceAccount credentials (privileged) - no cluster-scoped RBAC needed for user
@@ -266,9 +284,10 @@ func (m *Manager) findClusterInfo(ctx context.Context, clusterName string, dynam
 				"namespace", namespace,
 				UserHashAttr(user.Email))
 			return &ClusterInfo{
-				Name:      clusterName,
-				Namespace: namespace,
-				Endpoint:  endpoint,
+				Name:          clusterName,
+				Namespace:     namespace,
+				Endpoint:      endpoint,
+				TokenAudience: strings.TrimSpace(cluster.GetAnnotations()[AnnotationTokenExchangeAudience]),
 			}, nil
 		}
 	}
@@ -422,6 +441,18 @@ func (m *Manager) getKubeconfigFromSecret(ctx context.Context, info *ClusterInfo
 		}
 	}
 
+	if m.execPlugins != nil {
+		if err := m.execPlugins.ApplyTo(config); err != nil {
+			return nil, &KubeconfigError{
+				ClusterName:  info.Name,
+				ResourceName: secretName,
+				Namespace:    info.Namespace,
+				Reason:       "exec credential plugin rejected",
+				Err:          err,
+			}
+		}
+	}
+
 	// Log success without exposing sensitive data
 	m.logger.Debug("Successfully parsed kubeconfig",
 		"cluster", info.Name,
@@ -1207,3 +1207,31 @@ func TestConnectionValidationTimeoutConfigurable(t *testing.T) {
 		assert.Less(t, elapsed, 2*time.Second, "validation should fail quickly with short timeout")
 	})
 }
+
+// rejectingExecPlugins rejects every rest.Config it is applied to.
+type rejectingExecPlugins struct{ applied int }
+
+func (p *rejectingExecPlugins) ApplyTo(*rest.Config) error {
+	p.applied++
+	return errors.New("exec credential plugin is not allowed: aws-iam-authenticator")
+}
+
+func TestGetKubeconfigForCluster_ExecPlugins(t *testing.T) {
+	const (
+		clusterName = "wc-cluster"
+		namespace   = "org-acme"
+	)
+	clientProvider := &StaticClientProvider{
+		Clientset:     fake.NewClientset(createTestKubeconfigSecret(clusterName, namespace, CAPISecretKey, testValidKubeconfig)),
+		DynamicClient: createTestFakeDynamicClient(runtime.NewScheme(), createTestCAPICluster(clusterName, namespace)),
+	}
+	plugins := &rejectingExecPlugins{}
+	manager, err := NewManager(clientProvider, WithManagerLogger(newTestLogger()), WithExecPlugins(plugins))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	_, err = manager.GetKubeconfigForCluster(context.Background(), clusterName, testUser())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exec credential plugin rejected")
+	assert.Equal(t, 1, plugins.applied)
+}
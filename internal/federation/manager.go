@@ -82,7 +82,8 @@ type ClusterClientManager interface {
 	// The list is filtered based on the user's RBAC permissions - only clusters
 	// the user has access to view will be returned.
 	//
-	// This method queries CAPI Cluster resources on the Management Cluster.
+	// This method queries the configured cluster sources; by default, CAPI
+	// Cluster resources on the Management Cluster.
 	ListClusters(ctx context.Context, user *UserInfo) ([]ClusterSummary, error)
 
 	// GetClusterSummary returns detailed information about a specific cluster.
@@ -178,6 +179,18 @@ type Manager struct {
 	// When enabled, CheckConnectivityWithRetry is called before caching a new client.
 	validateConnectivity bool
 
+	// userRateLimiter partitions the QPS budget of workload cluster clients
+	// per user. Optional.
+	userRateLimiter RESTConfigLimiter
+
+	// impersonationExtras adds configured and per-request extras to the
+	// impersonation headers of workload cluster clients.
+	impersonationExtras ImpersonationExtrasApplier
+
+	// execPlugins bounds the exec credential plugins of CAPI kubeconfigs.
+	// Optional.
+	execPlugins ExecPluginApplier
+
 	// workloadClusterAuthMode determines how to authenticate to workload clusters.
 	// Default is "impersonation" (existing behavior).
 	// "sso-passthrough" forwards user's SSO token directly to WC API servers.
@@ -187,11 +200,39 @@ type Manager struct {
 	// Only used when workloadClusterAuthMode is WorkloadClusterAuthModeSSOPassthrough.
 	ssoPassthroughConfig *SSOPassthroughConfig
 
-	// groupMapper translates OIDC group identifiers to the identifiers expected
-	// by workload cluster RoleBindings. Only used in impersonation mode.
+	// groupMapper filters OIDC groups and translates them to the identifiers
+	// expected by workload cluster RoleBindings. Only used in impersonation mode.
 	// Nil when no group mapping is configured (groups pass through unchanged).
 	groupMapper *GroupMapper
 
+	// clusterSources discover workload clusters and provide their admin
+	// kubeconfigs, asked in order. Assembled in NewManager from the built-in
+	// CAPI source (unless disabled via WithCAPIClusterSource), the sources
+	// added via WithClusterSources and the secret source configured via
+	// WithSecretClusterSource.
+	clusterSources []ClusterSource
+
+	// Cluster source configuration (set via options, applied during NewManager)
+	disableCAPISource   bool
+	extraClusterSources []ClusterSource
+	secretSourceConfig  *SecretClusterSourceConfig
+
+	// discoveryCache serves CAPI cluster discovery from an informer-backed
+	// index. Nil when the discovery cache is disabled.
+	discoveryCache *discoveryCache
+
+	// Discovery cache configuration (set via options, applied during NewManager)
+	discoveryCacheConfig  *DiscoveryCacheConfig
+	discoveryCacheMetrics DiscoveryCacheMetricsRecorder
+
+	// concurrencyLimiter bounds how many workload cluster clients are created
+	// at the same time. Nil when no limit is configured.
+	concurrencyLimiter *concurrencyLimiter
+
+	// Concurrency configuration (set via options, applied during NewManager)
+	concurrencyConfig  *ConcurrencyConfig
+	concurrencyMetrics ConcurrencyMetricsRecorder
+
 	// Logger for operational messages
 	logger *slog.Logger
 
@@ -288,6 +329,57 @@ func WithConnectivityValidation(enabled bool) ManagerOption {
 	}
 }
 
+// RESTConfigLimiter installs a client-side rate limiter on a rest.Config
+// before clients are created from it. It is implemented by
+// k8s.UserRateLimiter.
+type RESTConfigLimiter interface {
+	ApplyTo(config *rest.Config)
+}
+
+// WithUserRateLimiter gives each user their own share of the QPS budget of
+// workload cluster clients, so one user's requests cannot starve another's.
+// The limiter is applied after the connectivity configuration sets the
+// client's QPS and burst.
+func WithUserRateLimiter(limiter RESTConfigLimiter) ManagerOption {
+	return func(m *Manager) {
+		m.userRateLimiter = limiter
+	}
+}
+
+// ImpersonationExtrasApplier adds Impersonate-Extra-* headers to the requests
+// of clients created from a rest.Config. It is implemented by
+// k8s.ImpersonationExtras.
+type ImpersonationExtrasApplier interface {
+	ApplyTo(config *rest.Config)
+}
+
+// WithImpersonationExtras adds operator-configured and per-request extras to
+// the impersonation headers of workload cluster clients, next to the agent
+// and original-groups extras, so they show up in the workload cluster's
+// audit log. It has no effect in SSO passthrough mode, which does not
+// impersonate.
+func WithImpersonationExtras(extras ImpersonationExtrasApplier) ManagerOption {
+	return func(m *Manager) {
+		m.impersonationExtras = extras
+	}
+}
+
+// ExecPluginApplier replaces the exec credential plugin of a rest.Config
+// with one that enforces an allowlist, a timeout and an output limit, failing
+// if the plugin is not allowed. It is implemented by k8s.ExecPluginConfig.
+type ExecPluginApplier interface {
+	ApplyTo(config *rest.Config) error
+}
+
+// WithExecPlugins bounds the exec credential plugins of the kubeconfigs read
+// from CAPI kubeconfig secrets, as for the local kubeconfig. Pass the same
+// applier to NewKubeconfigClusterSource for static kubeconfig files.
+func WithExecPlugins(plugins ExecPluginApplier) ManagerOption {
+	return func(m *Manager) {
+		m.execPlugins = plugins
+	}
+}
+
 // WithWorkloadClusterAuthMode sets the authentication mode for workload clusters.
 //
 // # Supported Modes
@@ -339,12 +431,14 @@ func WithSSOPassthroughConfig(config *SSOPassthroughConfig) ManagerOption {
 	}
 }
 
-// WithGroupMapper sets the group mapper for translating OIDC group identifiers
-// before setting Impersonate-Group headers on workload cluster requests.
+// WithGroupMapper sets the group mapper for filtering and translating OIDC group
+// identifiers before setting Impersonate-Group headers on workload cluster requests.
 //
 // This is useful when the OIDC provider returns group identifiers in a different
 // format than what the workload cluster RoleBindings expect. For example, when
-// Dex returns Azure AD group display names but workload clusters use GUIDs.
+// Dex returns Azure AD group display names but workload clusters use GUIDs. It
+// also keeps large group lists from exceeding header limits (see
+// WithGroupAllowPatterns).
 //
 // The group mapper is only applied in impersonation mode. In SSO passthrough mode,
 // the workload cluster's own OIDC configuration handles group resolution.
@@ -395,6 +489,99 @@ func WithPrivilegedAccessMetrics(metrics PrivilegedAccessMetricsRecorder) Manage
 	}
 }
 
+// WithClusterSources adds cluster sources that are asked after the built-in
+// CAPI source. This lets fleets that are not managed by Cluster API, such as
+// plain kubeconfigs, Rancher or Gardener, use the multi-cluster tooling.
+//
+// Sources are asked in the order given; when two sources know a cluster of
+// the same name, the first one wins. Cluster names of all sources share one
+// namespace, so make sure they do not collide.
+//
+// Example:
+//
+//	source, err := federation.NewKubeconfigClusterSource("/etc/mcp-kubernetes/clusters")
+//	if err != nil {
+//	    return err
+//	}
+//	manager, err := federation.NewManager(provider,
+//	    federation.WithClusterSources(source),
+//	)
+func WithClusterSources(sources ...ClusterSource) ManagerOption {
+	return func(m *Manager) {
+		m.extraClusterSources = append(m.extraClusterSources, sources...)
+	}
+}
+
+// WithSecretClusterSource adds a cluster source that reads workload cluster
+// kubeconfigs from Secrets on the Management Cluster selected by a label
+// selector. The Secrets are read with the same credentials as CAPI kubeconfig
+// secrets (see WithPrivilegedAccess). The source is asked after the sources
+// added with WithClusterSources.
+func WithSecretClusterSource(config SecretClusterSourceConfig) ManagerOption {
+	return func(m *Manager) {
+		m.secretSourceConfig = &config
+	}
+}
+
+// WithCAPIClusterSource enables or disables the built-in CAPI cluster source,
+// which discovers CAPI Cluster resources and reads their kubeconfig secrets.
+// Disable it for fleets without Cluster API; at least one other source must
+// then be configured.
+//
+// Default: true (enabled)
+func WithCAPIClusterSource(enabled bool) ManagerOption {
+	return func(m *Manager) {
+		m.disableCAPISource = !enabled
+	}
+}
+
+// WithDiscoveryCache enables the CAPI cluster discovery cache. Instead of
+// listing Cluster resources on every ListClusters and GetClusterSummary call,
+// the Manager watches them with a single informer and serves discovery from
+// an in-memory index, which reduces Management Cluster API load under heavy
+// agent activity. Requests fall back to listing from the API server while
+// the cache is not synced.
+//
+// # Per-User Filtering
+//
+// The informer watches with ServiceAccount credentials: config.Client, or the
+// privileged client with CredentialModeFullPrivileged. In that mode every user
+// sees every cluster, as with live discovery. In the other credential modes
+// the cached clusters are filtered per user with SelfSubjectAccessReviews for
+// listing Cluster resources, cached for config.AccessTTL.
+func WithDiscoveryCache(config DiscoveryCacheConfig) ManagerOption {
+	return func(m *Manager) {
+		m.discoveryCacheConfig = &config
+	}
+}
+
+// WithDiscoveryCacheMetrics sets the metrics recorder for the discovery cache.
+func WithDiscoveryCacheMetrics(metrics DiscoveryCacheMetricsRecorder) ManagerOption {
+	return func(m *Manager) {
+		m.discoveryCacheMetrics = metrics
+	}
+}
+
+// WithConcurrencyLimit limits how many workload clusters the Manager contacts
+// at the same time. Creating a workload cluster client (on a client cache
+// miss) needs a slot; operations beyond config.MaxConcurrent wait in a
+// bounded queue and fail with ErrConcurrencyLimitExceeded when the queue is
+// full or config.QueueTimeout passes. Cached clients need no slot.
+//
+// Default: no limit. See DefaultConcurrencyConfig for recommended values.
+func WithConcurrencyLimit(config ConcurrencyConfig) ManagerOption {
+	return func(m *Manager) {
+		m.concurrencyConfig = &config
+	}
+}
+
+// WithConcurrencyMetrics sets the metrics recorder for the concurrency limit.
+func WithConcurrencyMetrics(metrics ConcurrencyMetricsRecorder) ManagerOption {
+	return func(m *Manager) {
+		m.concurrencyMetrics = metrics
+	}
+}
+
 // NewManager creates a new ClusterClientManager with the provided ClientProvider.
 //
 // # Security Model
@@ -461,9 +648,28 @@ func NewManager(clientProvider ClientProvider, opts ...ManagerOption) (*Manager,
 	}
 	m.cache = NewClientCache(cacheOpts...)
 
+	if err := m.buildClusterSources(); err != nil {
+		return nil, err
+	}
+
+	if m.concurrencyConfig != nil {
+		m.concurrencyLimiter = newConcurrencyLimiter(*m.concurrencyConfig, m.concurrencyMetrics)
+	}
+
+	if m.discoveryCacheConfig != nil && !m.disableCAPISource {
+		m.discoveryCache = newDiscoveryCache(*m.discoveryCacheConfig, m.discoveryCacheMetrics, m.logger)
+		if m.discoveryCacheConfig.Client == nil && m.credentialMode != CredentialModeFullPrivileged {
+			m.logger.Warn("Discovery cache requires privileged CAPI discovery; clusters are listed from the API server",
+				"credential_mode", m.credentialMode.String())
+		}
+	}
+
 	m.logger.Info("Federation manager initialized",
 		"credential_mode", m.credentialMode.String(),
+		"cluster_sources", clusterSourceNames(m.clusterSources),
 		"cache_enabled", m.cache != nil,
+		"discovery_cache_enabled", m.discoveryCache != nil,
+		"max_concurrent_clusters", m.maxConcurrentClusters(),
 		"group_mapper", m.groupMapper.String())
 
 	return m, nil
@@ -585,9 +791,21 @@ func (m *Manager) GetRestConfig(ctx context.Context, clusterName string, user *U
 //   - Kubernetes version
 //   - Cluster status and readiness
 //
+// Clusters of additional sources configured with WithClusterSources are
+// included; when two sources know a cluster of the same name, the first
+// source wins.
+//
 // Returns ClusterDiscoveryError if CAPI CRDs are not installed.
 func (m *Manager) ListClusters(ctx context.Context, user *UserInfo) ([]ClusterSummary, error) {
-	return m.listClustersWithOptions(ctx, user, nil)
+	if err := m.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateUserInfo(user); err != nil {
+		return nil, err
+	}
+
+	return m.listClustersFromSources(ctx, user)
 }
 
 // GetClusterSummary returns information about a specific cluster.
@@ -614,16 +832,27 @@ func (m *Manager) GetClusterSummary(ctx context.Context, clusterName string, use
 		return nil, err
 	}
 
-	// Get dynamic client for CAPI discovery (privileged or user credentials)
-	dynamicClient, err := m.getDynamicClientForCAPIDiscovery(ctx, user)
-	if err != nil {
-		return nil, fmt.Errorf("cluster summary failed: %w", err)
-	}
+	return m.getClusterSummaryFromSources(ctx, clusterName, user)
+}
 
-	// Query cluster by name using field selector for efficiency
-	summary, err := m.getClusterByName(ctx, dynamicClient, clusterName, user)
-	if err != nil {
-		return nil, err
+// getCAPIClusterSummary returns the summary of a CAPI cluster. It implements
+// GetClusterSummary of the built-in CAPI cluster source.
+func (m *Manager) getCAPIClusterSummary(ctx context.Context, clusterName string, user *UserInfo) (*ClusterSummary, error) {
+	var summary *ClusterSummary
+	if cached, ok := m.listClustersFromDiscoveryCache(ctx, user, nil); ok {
+		summary = findClusterByName(cached, clusterName)
+	} else {
+		// Get dynamic client for CAPI discovery (privileged or user credentials)
+		dynamicClient, err := m.getDynamicClientForCAPIDiscovery(ctx, user)
+		if err != nil {
+			return nil, fmt.Errorf("cluster summary failed: %w", err)
+		}
+
+		// Query cluster by name using field selector for efficiency
+		summary, err = m.getClusterByName(ctx, dynamicClient, clusterName, user)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if summary == nil {
@@ -643,6 +872,16 @@ func (m *Manager) GetClusterSummary(ctx context.Context, clusterName string, use
 	return summary, nil
 }
 
+// InvalidateUser drops the cached clients of a user, so the next request
+// builds them with the user's current credentials. It is called when the
+// user's OAuth token is refreshed.
+func (m *Manager) InvalidateUser(ctx context.Context, userEmail string) {
+	if userEmail == "" || m.cache == nil || m.checkClosed() != nil {
+		return
+	}
+	m.cache.DeleteByUser(ctx, userEmail)
+}
+
 // Close releases all cached clients and resources.
 func (m *Manager) Close() error {
 	m.mu.Lock()
@@ -655,6 +894,10 @@ func (m *Manager) Close() error {
 	m.logger.Info("Closing federation manager")
 	m.closed = true
 
+	if m.discoveryCache != nil {
+		m.discoveryCache.stop()
+	}
+
 	// Close the client cache
 	if m.cache != nil {
 		if err := m.cache.Close(); err != nil {
@@ -794,6 +1037,12 @@ func (m *Manager) createRemoteClusterClient(ctx context.Context, clusterName str
 		UserHashAttr(user.Email),
 		"group_count", len(user.Groups))
 
+	release, err := m.acquireClusterSlot(ctx, clusterName, user)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer release()
+
 	// Use SSO passthrough if configured
 	if m.workloadClusterAuthMode == WorkloadClusterAuthModeSSOPassthrough {
 		return m.createSSOPassthroughClient(ctx, clusterName, user)
@@ -829,41 +1078,24 @@ func (m *Manager) createImpersonationClient(ctx context.Context, clusterName str
 		}
 	}
 
-	// Apply group mapping if configured.
-	// This translates OIDC group identifiers to the format expected by the
-	// workload cluster's RoleBindings (e.g., display names -> GUIDs).
-	// The original user.Groups slice is never modified; MapGroups returns a
-	// new slice only when at least one group is translated.
-	impersonationUser := user
-	if m.groupMapper != nil {
-		mappedGroups, didMap := m.groupMapper.MapGroups(user.Groups, user.Email)
-		if didMap {
-			// Include original groups in impersonation extras for audit trail.
-			// This ensures the K8s audit log on the workload cluster contains both
-			// the mapped groups (in Impersonate-Group) and the originals (in Extra),
-			// providing a complete audit trail in a single log source.
-			//
-			// Deep copy slice values to prevent any downstream mutation from
-			// affecting the original UserInfo (defense-in-depth for security path).
-			extra := make(map[string][]string, len(user.Extra)+1)
-			for k, v := range user.Extra {
-				copied := make([]string, len(v))
-				copy(copied, v)
-				extra[k] = copied
-			}
-			extra[OriginalGroupsExtraKey] = user.Groups
-
-			impersonationUser = &UserInfo{
-				Email:  user.Email,
-				Groups: mappedGroups,
-				Extra:  extra,
-			}
-		}
-	}
+	// Apply group filtering and mapping if configured.
+	// This drops groups outside the allowlist, strips configured prefixes and
+	// translates OIDC group identifiers to the format expected by the workload
+	// cluster's RoleBindings (e.g., display names -> GUIDs). The original
+	// UserInfo is never modified; when a group is renamed, the originals are
+	// recorded in the impersonation extras so the K8s audit log on the
+	// workload cluster contains a complete audit trail.
+	impersonationUser := m.groupMapper.ImpersonationUser(user)
 
 	// Configure impersonation for WC operations
 	// The kubeconfig contains admin credentials; we impersonate the user
 	impersonatedConfig := ConfigWithImpersonation(baseConfig, impersonationUser)
+	if m.userRateLimiter != nil {
+		m.userRateLimiter.ApplyTo(impersonatedConfig)
+	}
+	if m.impersonationExtras != nil {
+		m.impersonationExtras.ApplyTo(impersonatedConfig)
+	}
 
 	// Record impersonation metric - this tracks successful impersonation configuration
 	m.authMetrics.RecordImpersonation(ctx, user.Email, clusterName, "success")
@@ -916,8 +1148,11 @@ func (m *Manager) createSSOPassthroughClient(ctx context.Context, clusterName st
 	if err != nil {
 		// Determine error type for metrics using sentinel error
 		result := "error"
-		if errors.Is(err, ErrSSOTokenMissing) {
+		switch {
+		case errors.Is(err, ErrSSOTokenMissing):
 			result = "token_missing"
+		case errors.Is(err, ErrTokenExchangeFailed):
+			result = "token_exchange_failed"
 		}
 		m.authMetrics.RecordWorkloadClusterAuth(ctx, string(WorkloadClusterAuthModeSSOPassthrough), clusterName, result)
 		m.authMetrics.RecordFederationClientCreation(ctx, clusterName, "error")
@@ -956,6 +1191,20 @@ type ManagerStats struct {
 	// CacheTTL is the configured time-to-live for cache entries.
 	CacheTTL time.Duration
 
+	// ClusterOperationsInFlight is the number of workload cluster clients
+	// being created. Always zero when no concurrency limit is configured.
+	ClusterOperationsInFlight int
+
+	// ClusterOperationsQueued is the number of operations waiting for a slot.
+	ClusterOperationsQueued int
+
+	// DiscoveryCacheClusters is the number of clusters in the discovery cache.
+	DiscoveryCacheClusters int
+
+	// DiscoveryCacheSynced indicates whether the discovery cache has completed
+	// its initial sync. Always false when the discovery cache is disabled.
+	DiscoveryCacheSynced bool
+
 	// Closed indicates whether the manager has been closed.
 	Closed bool
 }
@@ -978,6 +1227,15 @@ func (m *Manager) Stats() ManagerStats {
 		stats.CacheTTL = cacheStats.TTL
 	}
 
+	if m.concurrencyLimiter != nil {
+		stats.ClusterOperationsInFlight, stats.ClusterOperationsQueued = m.concurrencyLimiter.usage()
+	}
+
+	if m.discoveryCache != nil {
+		stats.DiscoveryCacheClusters = m.discoveryCache.size()
+		stats.DiscoveryCacheSynced = m.discoveryCache.isSynced()
+	}
+
 	return stats
 }
 
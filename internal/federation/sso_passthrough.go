@@ -23,6 +23,12 @@ type SSOPassthroughConfig struct {
 	// TokenExtractor extracts the user's SSO token from context.
 	// This is typically oauth.GetIDTokenFromContext.
 	TokenExtractor TokenExtractor
+
+	// TokenExchange, if set, exchanges the user's token for one with the
+	// cluster's audience for clusters annotated with
+	// AnnotationTokenExchangeAudience. Clusters carrying the annotation
+	// cannot be reached when it is nil.
+	TokenExchange *TokenExchangeConfig
 }
 
 // DefaultSSOPassthroughConfig returns the default configuration for SSO passthrough.
@@ -59,17 +65,28 @@ func DefaultSSOPassthroughConfig() *SSOPassthroughConfig {
 // These ConfigMaps should be created by an operator that extracts the CA certificate
 // (tls.crt) from the CAPI-generated ${CLUSTER_NAME}-ca secret.
 func (m *Manager) GetCAForCluster(ctx context.Context, clusterName string, user *UserInfo) ([]byte, string, error) {
+	caData, clusterInfo, err := m.getSSOPassthroughTarget(ctx, clusterName, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return caData, clusterInfo.Endpoint, nil
+}
+
+// getSSOPassthroughTarget retrieves the CA certificate and the cluster info,
+// including endpoint and token audience, for a workload cluster. See
+// GetCAForCluster for the security model.
+func (m *Manager) getSSOPassthroughTarget(ctx context.Context, clusterName string, user *UserInfo) ([]byte, *ClusterInfo, error) {
 	// Validate inputs
 	if err := ValidateUserInfo(user); err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 	if err := ValidateClusterName(clusterName); err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Fail fast if context is already cancelled
 	if err := ctx.Err(); err != nil {
-		return nil, "", &ClusterNotFoundError{
+		return nil, nil, &ClusterNotFoundError{
 			ClusterName: clusterName,
 			Reason:      "context cancelled or expired",
 		}
@@ -89,10 +106,10 @@ func (m *Manager) GetCAForCluster(ctx context.Context, clusterName string, user
 		// Preserve strict-mode sentinel so callers can distinguish policy
 		// rejections from transient failures.
 		if errors.Is(err, ErrStrictPrivilegedAccessRequired) {
-			return nil, "", err
+			return nil, nil, err
 		}
 
-		return nil, "", &ClusterNotFoundError{
+		return nil, nil, &ClusterNotFoundError{
 			ClusterName: clusterName,
 			Reason:      "failed to create client for CAPI discovery",
 		}
@@ -103,12 +120,12 @@ func (m *Manager) GetCAForCluster(ctx context.Context, clusterName string, user
 	// so we don't need a separate API call for the endpoint.
 	clusterInfo, err := m.findClusterInfo(ctx, clusterName, dynamicClient, user)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Verify we have an endpoint (required for SSO passthrough TLS connection)
 	if clusterInfo.Endpoint == "" {
-		return nil, "", &ClusterNotFoundError{
+		return nil, nil, &ClusterNotFoundError{
 			ClusterName: clusterName,
 			Reason:      "could not determine cluster API endpoint from CAPI Cluster resource",
 		}
@@ -123,17 +140,17 @@ func (m *Manager) GetCAForCluster(ctx context.Context, clusterName string, user
 			"cluster", clusterName,
 			UserHashAttr(user.Email),
 			"error", err)
-		return nil, "", fmt.Errorf("failed to create user client for ConfigMap access (cluster %s): %w", clusterName, err)
+		return nil, nil, fmt.Errorf("failed to create user client for ConfigMap access (cluster %s): %w", clusterName, err)
 	}
 
 	// Retrieve CA certificate from the CA ConfigMap
 	// Note: ConfigMaps are used instead of Secrets because the CA certificate is public information
 	caData, err := m.getCAFromConfigMap(ctx, clusterInfo, clientset, user)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	return caData, clusterInfo.Endpoint, nil
+	return caData, clusterInfo, nil
 }
 
 // getCAFromConfigMap retrieves the CA certificate from a CA ConfigMap.
@@ -237,11 +254,29 @@ func (m *Manager) CreateSSOPassthroughClient(ctx context.Context, clusterName st
 		return nil, nil, nil, ErrSSOTokenMissing
 	}
 
-	// Get CA certificate and endpoint for the cluster
-	caData, endpoint, err := m.GetCAForCluster(ctx, clusterName, user)
+	// Get CA certificate, endpoint and token audience for the cluster
+	caData, clusterInfo, err := m.getSSOPassthroughTarget(ctx, clusterName, user)
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	endpoint := clusterInfo.Endpoint
+
+	// Clusters that expect a cluster-specific audience get an exchanged token
+	if clusterInfo.TokenAudience != "" {
+		if m.ssoPassthroughConfig.TokenExchange == nil {
+			return nil, nil, nil, fmt.Errorf("%w: cluster %s requires audience %q but token exchange is not configured",
+				ErrTokenExchangeFailed, clusterName, clusterInfo.TokenAudience)
+		}
+		ssoToken, err = m.ssoPassthroughConfig.TokenExchange.ExchangeToken(ctx, ssoToken, clusterInfo.TokenAudience)
+		if err != nil {
+			m.logger.Debug("Failed to exchange SSO token for cluster audience",
+				"cluster", clusterName,
+				"audience", clusterInfo.TokenAudience,
+				UserHashAttr(user.Email),
+				"error", err)
+			return nil, nil, nil, fmt.Errorf("cluster %s: %w", clusterName, err)
+		}
+	}
 
 	// Create REST config with SSO token (no impersonation)
 	restConfig := &rest.Config{
@@ -259,6 +294,9 @@ func (m *Manager) CreateSSOPassthroughClient(ctx context.Context, clusterName st
 	if m.connectivityConfig != nil {
 		ApplyConnectivityConfig(restConfig, *m.connectivityConfig)
 	}
+	if m.userRateLimiter != nil {
+		m.userRateLimiter.ApplyTo(restConfig)
+	}
 
 	// Create clientset
 	clientset, err := kubernetes.NewForConfig(restConfig)
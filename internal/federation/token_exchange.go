@@ -0,0 +1,171 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// AnnotationTokenExchangeAudience is the CAPI Cluster annotation naming the
+	// audience the cluster's API server expects in tokens. When set, the user's
+	// token is exchanged for one with this audience before it is forwarded in
+	// sso-passthrough mode.
+	AnnotationTokenExchangeAudience = "mcp.giantswarm.io/token-exchange-audience"
+
+	// GrantTypeTokenExchange is the RFC 8693 token exchange grant type.
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// TokenTypeIDToken is the RFC 8693 token type of an OIDC ID token.
+	TokenTypeIDToken = "urn:ietf:params:oauth:token-type:id_token"
+
+	// TokenTypeAccessToken is the RFC 8693 token type of an OAuth access token.
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// DefaultTokenExchangeTimeout bounds a single token exchange request.
+	DefaultTokenExchangeTimeout = 10 * time.Second
+
+	// maxTokenExchangeResponseBytes bounds the token endpoint response read.
+	maxTokenExchangeResponseBytes = 1 << 20
+)
+
+// TokenExchangeConfig configures RFC 8693 token exchange against the IdP.
+type TokenExchangeConfig struct {
+	// TokenURL is the IdP's token endpoint (e.g. "https://dex.example.com/token").
+	TokenURL string
+
+	// ClientID and ClientSecret authenticate mcp-kubernetes to the token
+	// endpoint. The client must be allowed to issue tokens for the audiences
+	// used by the clusters.
+	ClientID     string
+	ClientSecret string
+
+	// ConnectorID is sent as connector_id, which Dex requires to know which
+	// connector validates the subject token. Leave empty for other IdPs.
+	ConnectorID string
+
+	// SubjectTokenType is the type of the token being exchanged.
+	// Default: TokenTypeIDToken.
+	SubjectTokenType string
+
+	// RequestedTokenType is the type of token requested.
+	// Default: TokenTypeIDToken, as Kubernetes OIDC authentication validates ID tokens.
+	RequestedTokenType string
+
+	// Timeout bounds each exchange request. Default: DefaultTokenExchangeTimeout.
+	Timeout time.Duration
+
+	// HTTPClient is used for requests to the token endpoint.
+	// Default: http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Validate checks that the configuration can be used for token exchange.
+func (c *TokenExchangeConfig) Validate() error {
+	if c.TokenURL == "" {
+		return fmt.Errorf("token exchange URL is required")
+	}
+	u, err := url.Parse(c.TokenURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid token exchange URL %q", c.TokenURL)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("token exchange URL must use https: %q", c.TokenURL)
+	}
+	if c.ClientID == "" {
+		return fmt.Errorf("token exchange client ID is required")
+	}
+	return nil
+}
+
+// tokenExchangeResponse is the part of the RFC 8693 section 2.2.1 success
+// response that is used: the issued token is returned in access_token
+// whatever its type.
+type tokenExchangeResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// tokenExchangeError is the RFC 6749 section 5.2 error response.
+type tokenExchangeError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// ExchangeToken exchanges subjectToken for a token issued for audience using
+// the RFC 8693 token exchange grant. The issued token is returned as is.
+func (c *TokenExchangeConfig) ExchangeToken(ctx context.Context, subjectToken, audience string) (string, error) {
+	subjectTokenType := c.SubjectTokenType
+	if subjectTokenType == "" {
+		subjectTokenType = TokenTypeIDToken
+	}
+	requestedTokenType := c.RequestedTokenType
+	if requestedTokenType == "" {
+		requestedTokenType = TokenTypeIDToken
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTokenExchangeTimeout
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	form := url.Values{
+		"grant_type":           {GrantTypeTokenExchange},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {subjectTokenType},
+		"requested_token_type": {requestedTokenType},
+		"audience":             {audience},
+		"scope":                {"openid"},
+	}
+	if c.ConnectorID != "" {
+		form.Set("connector_id", c.ConnectorID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTokenExchangeFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTokenExchangeFailed, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenExchangeResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read response: %v", ErrTokenExchangeFailed, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr tokenExchangeError
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			if oauthErr.ErrorDescription != "" {
+				return "", fmt.Errorf("%w: %s: %s", ErrTokenExchangeFailed, oauthErr.Error, oauthErr.ErrorDescription)
+			}
+			return "", fmt.Errorf("%w: %s", ErrTokenExchangeFailed, oauthErr.Error)
+		}
+		return "", fmt.Errorf("%w: token endpoint returned HTTP %d", ErrTokenExchangeFailed, resp.StatusCode)
+	}
+
+	var result tokenExchangeResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("%w: invalid response: %v", ErrTokenExchangeFailed, err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("%w: response contains no token", ErrTokenExchangeFailed)
+	}
+	return result.AccessToken, nil
+}
@@ -0,0 +1,199 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestTokenEndpoint starts a TLS token endpoint that checks the exchange
+// request and answers with status and body.
+func newTestTokenEndpoint(t *testing.T, status int, body interface{}) *TokenExchangeConfig {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		clientID, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "mcp-kubernetes", clientID)
+		assert.Equal(t, "s3cr%3Et", secret, "credentials are form-encoded per RFC 6749")
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, GrantTypeTokenExchange, r.PostForm.Get("grant_type"))
+		assert.Equal(t, "user-token", r.PostForm.Get("subject_token"))
+		assert.Equal(t, TokenTypeIDToken, r.PostForm.Get("subject_token_type"))
+		assert.Equal(t, TokenTypeIDToken, r.PostForm.Get("requested_token_type"))
+		assert.Equal(t, "wc-audience", r.PostForm.Get("audience"))
+		assert.Equal(t, "ldap", r.PostForm.Get("connector_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if s, ok := body.(string); ok {
+			_, _ = w.Write([]byte(s))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &TokenExchangeConfig{
+		TokenURL:     srv.URL + "/token",
+		ClientID:     "mcp-kubernetes",
+		ClientSecret: "s3cr>t",
+		ConnectorID:  "ldap",
+		HTTPClient:   srv.Client(),
+	}
+}
+
+func TestTokenExchangeConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TokenExchangeConfig
+		wantErr string
+	}{
+		{name: "valid", config: TokenExchangeConfig{TokenURL: "https://dex.example.com/token", ClientID: "mcp"}},
+		{name: "missing URL", config: TokenExchangeConfig{ClientID: "mcp"}, wantErr: "URL is required"},
+		{name: "URL without host", config: TokenExchangeConfig{TokenURL: "https:///token", ClientID: "mcp"}, wantErr: "invalid token exchange URL"},
+		{name: "plain http", config: TokenExchangeConfig{TokenURL: "http://dex.example.com/token", ClientID: "mcp"}, wantErr: "must use https"},
+		{name: "missing client ID", config: TokenExchangeConfig{TokenURL: "https://dex.example.com/token"}, wantErr: "client ID is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTokenExchangeConfig_ExchangeToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      interface{}
+		wantToken string
+		wantErr   string
+	}{
+		{
+			name:      "issued token",
+			status:    http.StatusOK,
+			body:      map[string]string{"access_token": "exchanged-token", "issued_token_type": TokenTypeIDToken},
+			wantToken: "exchanged-token",
+		},
+		{
+			name:    "OAuth error with description",
+			status:  http.StatusBadRequest,
+			body:    map[string]string{"error": "invalid_target", "error_description": "audience not allowed"},
+			wantErr: "invalid_target: audience not allowed",
+		},
+		{
+			name:    "HTTP error without OAuth error",
+			status:  http.StatusBadGateway,
+			body:    "bad gateway",
+			wantErr: "HTTP 502",
+		},
+		{
+			name:    "response without token",
+			status:  http.StatusOK,
+			body:    map[string]string{"token_type": "Bearer"},
+			wantErr: "no token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestTokenEndpoint(t, tt.status, tt.body)
+
+			token, err := config.ExchangeToken(context.Background(), "user-token", "wc-audience")
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, ErrTokenExchangeFailed)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+		})
+	}
+}
+
+func TestManager_CreateSSOPassthroughClient_TokenExchange(t *testing.T) {
+	const (
+		clusterName = "wc-cluster"
+		namespace   = "org-acme"
+	)
+
+	// Building the clientset needs a CA that parses; any certificate will do.
+	caServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(caServer.Close)
+	caConfigMap := createTestCAConfigMap(clusterName, namespace, DefaultCAConfigMapSuffix)
+	caConfigMap.Data[CAConfigMapKey] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caServer.Certificate().Raw}))
+
+	newManager := func(t *testing.T, audience string, exchange *TokenExchangeConfig) *Manager {
+		t.Helper()
+		cluster := createTestCAPIClusterWithEndpoint(clusterName, namespace, "api.wc-cluster.example.com", 6443)
+		if audience != "" {
+			cluster.SetAnnotations(map[string]string{AnnotationTokenExchangeAudience: audience})
+		}
+		manager, err := NewManager(
+			&StaticClientProvider{
+				Clientset:     fake.NewClientset(caConfigMap.DeepCopy()),
+				DynamicClient: createTestFakeDynamicClient(runtime.NewScheme(), cluster),
+			},
+			WithManagerLogger(newTestLogger()),
+			WithSSOPassthroughConfig(&SSOPassthroughConfig{
+				CAConfigMapSuffix: DefaultCAConfigMapSuffix,
+				TokenExtractor: func(context.Context) (string, bool) {
+					return "user-token", true
+				},
+				TokenExchange: exchange,
+			}),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = manager.Close() })
+		return manager
+	}
+
+	t.Run("cluster without audience forwards the user token", func(t *testing.T) {
+		manager := newManager(t, "", nil)
+
+		_, _, restConfig, err := manager.CreateSSOPassthroughClient(context.Background(), clusterName, testUser())
+		require.NoError(t, err)
+		assert.Equal(t, "user-token", restConfig.BearerToken)
+	})
+
+	t.Run("cluster with audience gets an exchanged token", func(t *testing.T) {
+		exchange := newTestTokenEndpoint(t, http.StatusOK, map[string]string{"access_token": "exchanged-token"})
+		manager := newManager(t, " wc-audience ", exchange)
+
+		_, _, restConfig, err := manager.CreateSSOPassthroughClient(context.Background(), clusterName, testUser())
+		require.NoError(t, err)
+		assert.Equal(t, "exchanged-token", restConfig.BearerToken)
+	})
+
+	t.Run("cluster with audience fails without token exchange", func(t *testing.T) {
+		manager := newManager(t, "wc-audience", nil)
+
+		_, _, _, err := manager.CreateSSOPassthroughClient(context.Background(), clusterName, testUser())
+		require.ErrorIs(t, err, ErrTokenExchangeFailed)
+		assert.ErrorContains(t, err, "not configured")
+	})
+
+	t.Run("rejected exchange fails the client", func(t *testing.T) {
+		exchange := newTestTokenEndpoint(t, http.StatusBadRequest, map[string]string{"error": "invalid_target"})
+		manager := newManager(t, "wc-audience", exchange)
+
+		_, _, _, err := manager.CreateSSOPassthroughClient(context.Background(), clusterName, testUser())
+		require.ErrorIs(t, err, ErrTokenExchangeFailed)
+		assert.ErrorContains(t, err, clusterName)
+	})
+}
@@ -135,6 +135,10 @@ type ClusterSummary struct {
 	// Annotations contains the Kubernetes annotations on the Cluster resource.
 	// May include operational metadata or external references.
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Source is the name of the cluster source that discovered the cluster,
+	// e.g. "capi", "kubeconfig" or "secret".
+	Source string `json:"source,omitempty"`
 }
 
 // ClusterPhase represents the lifecycle phase of a CAPI cluster.
@@ -169,6 +173,43 @@ var (
 		Version:  "v1beta2",
 		Resource: "clusters",
 	}
+
+	// CAPIMachineGVR is the GroupVersionResource for CAPI Machine objects.
+	CAPIMachineGVR = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Version:  "v1beta2",
+		Resource: "machines",
+	}
+
+	// CAPIMachineDeploymentGVR is the GroupVersionResource for CAPI
+	// MachineDeployment objects.
+	CAPIMachineDeploymentGVR = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Version:  "v1beta2",
+		Resource: "machinedeployments",
+	}
+
+	// CAPIMachinePoolGVR is the GroupVersionResource for CAPI MachinePool objects.
+	CAPIMachinePoolGVR = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Version:  "v1beta2",
+		Resource: "machinepools",
+	}
+)
+
+// CAPI labels set on the objects belonging to a workload cluster.
+const (
+	// LabelCAPIClusterName names the Cluster an object belongs to.
+	LabelCAPIClusterName = "cluster.x-k8s.io/cluster-name"
+
+	// LabelCAPIDeploymentName names the MachineDeployment a Machine belongs to.
+	LabelCAPIDeploymentName = "cluster.x-k8s.io/deployment-name"
+
+	// LabelCAPIPoolName names the MachinePool a Machine belongs to.
+	LabelCAPIPoolName = "cluster.x-k8s.io/pool-name"
+
+	// LabelCAPIControlPlane marks control plane Machines.
+	LabelCAPIControlPlane = "cluster.x-k8s.io/control-plane"
 )
 
 // CAPISecretSuffix is the suffix used by CAPI for kubeconfig secrets.
@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -25,6 +27,11 @@ type ToolInvocation struct {
 	UserEmail string
 	Groups    []string
 
+	// Impersonation override requested by the caller (impersonateUser /
+	// impersonateGroups). Empty when the tool ran as the caller.
+	ImpersonatedUser   string
+	ImpersonatedGroups []string
+
 	// Target information
 	ClusterName  string
 	Namespace    string
@@ -78,6 +85,9 @@ func (ti *ToolInvocation) LogAttrs() []slog.Attr {
 	}
 
 	// Add optional fields only if present
+	if ti.ImpersonatedUser != "" {
+		attrs = append(attrs, slog.Bool("impersonated", true))
+	}
 	if ti.Namespace != "" {
 		attrs = append(attrs, slog.String("namespace", ti.Namespace))
 	}
@@ -115,6 +125,11 @@ func (ti *ToolInvocation) LogAuditAttrs() []slog.Attr {
 	}
 
 	// Add all optional fields
+	if ti.ImpersonatedUser != "" {
+		attrs = append(attrs,
+			slog.String("impersonated_user", ti.ImpersonatedUser),
+			slog.Any("impersonated_groups", ti.ImpersonatedGroups))
+	}
 	if ti.Namespace != "" {
 		attrs = append(attrs, slog.String("namespace", ti.Namespace))
 	}
@@ -153,6 +168,14 @@ func (ti *ToolInvocation) WithUser(email string, groups []string) *ToolInvocatio
 	return ti
 }
 
+// WithImpersonation records the identity the tool was executed as when the
+// caller used the impersonation override.
+func (ti *ToolInvocation) WithImpersonation(user string, groups []string) *ToolInvocation {
+	ti.ImpersonatedUser = user
+	ti.ImpersonatedGroups = groups
+	return ti
+}
+
 // WithCluster sets the target cluster name.
 func (ti *ToolInvocation) WithCluster(clusterName string) *ToolInvocation {
 	ti.ClusterName = clusterName
@@ -198,10 +221,16 @@ func (ti *ToolInvocation) CompleteSuccess() *ToolInvocation {
 	return ti.Complete(true, nil)
 }
 
+// AuditLoggerName is the instrumentation scope of exported audit records.
+const AuditLoggerName = "github.com/giantswarm/mcp-kubernetes/audit"
+
 // AuditLogger provides structured audit logging for tool invocations.
 // It wraps slog.Logger with convenience methods for logging tool operations.
+// Records can additionally be exported as OpenTelemetry logs; see
+// WithLoggerProvider.
 type AuditLogger struct {
-	logger *slog.Logger
+	logger   *slog.Logger
+	exporter *slog.Logger
 }
 
 // NewAuditLogger creates a new AuditLogger with the given slog.Logger.
@@ -212,6 +241,21 @@ func NewAuditLogger(logger *slog.Logger) *AuditLogger {
 	return &AuditLogger{logger: logger}
 }
 
+// WithLoggerProvider additionally exports every audit record as an
+// OpenTelemetry log through provider, for example to an OTLP collector.
+func (al *AuditLogger) WithLoggerProvider(provider log.LoggerProvider) *AuditLogger {
+	al.exporter = otelslog.NewLogger(AuditLoggerName, otelslog.WithLoggerProvider(provider))
+	return al
+}
+
+// log writes a record to the log and, when configured, the exporter.
+func (al *AuditLogger) log(level slog.Level, msg string, args []any) {
+	al.logger.Log(context.Background(), level, msg, args...)
+	if al.exporter != nil {
+		al.exporter.Log(context.Background(), level, msg, args...)
+	}
+}
+
 // LogToolInvocation logs a tool invocation using the standard log attributes.
 // This is suitable for general operational logging with cardinality controls.
 func (al *AuditLogger) LogToolInvocation(ti *ToolInvocation) {
@@ -222,9 +266,9 @@ func (al *AuditLogger) LogToolInvocation(ti *ToolInvocation) {
 	}
 
 	if ti.Success {
-		al.logger.Info("tool_executed", args...)
+		al.log(slog.LevelInfo, "tool_executed", args)
 	} else {
-		al.logger.Warn("tool_failed", args...)
+		al.log(slog.LevelWarn, "tool_failed", args)
 	}
 }
 
@@ -237,7 +281,7 @@ func (al *AuditLogger) LogToolAudit(ti *ToolInvocation) {
 		args[i] = attr
 	}
 
-	al.logger.Info("tool_audit", args...)
+	al.log(slog.LevelInfo, "tool_audit", args)
 }
 
 // TraceIDFromContext extracts the trace ID from the current span in context.
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"log/slog"
 	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 )
 
 // Test constants to reduce string repetition and satisfy goconst
@@ -74,6 +77,39 @@ func TestToolInvocation_WithUser(t *testing.T) {
 	}
 }
 
+func TestToolInvocation_WithImpersonation(t *testing.T) {
+	ti := NewToolInvocation(testToolGet)
+	ti.WithUser(testEmail, []string{"admins"}).
+		WithImpersonation("jane@example.com", []string{"team-b"}).
+		CompleteSuccess()
+
+	if ti.ImpersonatedUser != "jane@example.com" {
+		t.Errorf("ImpersonatedUser = %q, want %q", ti.ImpersonatedUser, "jane@example.com")
+	}
+
+	attrMap := make(map[string]slog.Attr)
+	for _, attr := range ti.LogAttrs() {
+		attrMap[attr.Key] = attr
+	}
+	if _, ok := attrMap["impersonated_user"]; ok {
+		t.Error("impersonated_user must not appear in cardinality-controlled attrs")
+	}
+	if !attrMap["impersonated"].Value.Bool() {
+		t.Error("impersonated should be true")
+	}
+
+	auditMap := make(map[string]slog.Attr)
+	for _, attr := range ti.LogAuditAttrs() {
+		auditMap[attr.Key] = attr
+	}
+	if user := auditMap["impersonated_user"].Value.String(); user != "jane@example.com" {
+		t.Errorf("impersonated_user = %q, want %q", user, "jane@example.com")
+	}
+	if user := auditMap["user"].Value.String(); user != testEmail {
+		t.Errorf("user = %q, want caller %q", user, testEmail)
+	}
+}
+
 func TestToolInvocation_WithCluster(t *testing.T) {
 	ti := NewToolInvocation(testToolGet)
 	ti.WithCluster(testCluster)
@@ -371,6 +407,59 @@ func TestAuditLogger_LogToolAudit(t *testing.T) {
 	al.LogToolAudit(ti)
 }
 
+// captureExporter keeps the exported log records.
+type captureExporter struct {
+	records []sdklog.Record
+}
+
+func (e *captureExporter) Export(_ context.Context, records []sdklog.Record) error {
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *captureExporter) Shutdown(context.Context) error   { return nil }
+func (e *captureExporter) ForceFlush(context.Context) error { return nil }
+
+func TestAuditLogger_WithLoggerProvider(t *testing.T) {
+	exporter := &captureExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	al := NewAuditLogger(slog.Default()).WithLoggerProvider(lp)
+
+	al.LogToolInvocation(NewToolInvocation(testToolGet).CompleteSuccess())
+	al.LogToolInvocation(NewToolInvocation(testToolDelete).CompleteWithError(errors.New("test error")))
+	al.LogToolAudit(NewToolInvocation(testToolDelete).
+		WithUser(testEmail, []string{"group"}).
+		WithCluster(testCluster).
+		CompleteSuccess())
+
+	if len(exporter.records) != 3 {
+		t.Fatalf("expected 3 exported records, got %d", len(exporter.records))
+	}
+	wantBodies := []string{"tool_executed", "tool_failed", "tool_audit"}
+	for i, r := range exporter.records {
+		if got := r.Body().AsString(); got != wantBodies[i] {
+			t.Errorf("record %d: expected body %q, got %q", i, wantBodies[i], got)
+		}
+		if r.InstrumentationScope().Name != AuditLoggerName {
+			t.Errorf("record %d: expected scope %q, got %q", i, AuditLoggerName, r.InstrumentationScope().Name)
+		}
+	}
+	if got := exporter.records[1].Severity(); got != log.SeverityWarn {
+		t.Errorf("expected failed call to be exported as a warning, got %v", got)
+	}
+
+	attrs := map[string]string{}
+	exporter.records[2].WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.String()
+		return true
+	})
+	if attrs["user"] != testEmail || attrs["cluster"] != testCluster {
+		t.Errorf("expected audit record to carry user and cluster, got %v", attrs)
+	}
+}
+
 func TestTraceIDFromContext_NoSpan(t *testing.T) {
 	ctx := context.Background()
 	traceID := TraceIDFromContext(ctx)
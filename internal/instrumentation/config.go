@@ -37,6 +37,11 @@ type Config struct {
 	// Options: "otlp", "stdout", "none" (default: "none")
 	TracingExporter string
 
+	// AuditLogExporter specifies where audit records (tool invocations) are
+	// exported as OpenTelemetry logs, in addition to the process log.
+	// Options: "otlp", "stdout", "none" (default: "none")
+	AuditLogExporter string
+
 	// OTLPEndpoint is the OTLP collector endpoint
 	// Example: "localhost:4318" (without protocol prefix)
 	OTLPEndpoint string
@@ -73,6 +78,7 @@ func DefaultConfig() Config {
 		Enabled:            getEnvBoolOrDefault("INSTRUMENTATION_ENABLED", true),
 		MetricsExporter:    getEnvOrDefault("METRICS_EXPORTER", ExporterPrometheus),
 		TracingExporter:    getEnvOrDefault("TRACING_EXPORTER", ExporterNone),
+		AuditLogExporter:   getEnvOrDefault("AUDIT_LOG_EXPORTER", ExporterNone),
 		OTLPEndpoint:       getEnvOrDefault("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
 		OTLPInsecure:       getEnvBoolOrDefault("OTEL_EXPORTER_OTLP_INSECURE", false),
 		TraceSamplingRate:  getEnvFloatOrDefault("OTEL_TRACES_SAMPLER_ARG", 0.1),
@@ -102,6 +108,12 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid tracing exporter %q, must be one of: otlp, stdout, none", c.TracingExporter)
 	}
 
+	// Validate audit log exporter
+	validAuditLogExporters := map[string]bool{ExporterOTLP: true, ExporterStdout: true, ExporterNone: true}
+	if c.AuditLogExporter != "" && !validAuditLogExporters[c.AuditLogExporter] {
+		return fmt.Errorf("invalid audit log exporter %q, must be one of: otlp, stdout, none", c.AuditLogExporter)
+	}
+
 	// OTLP endpoint required when using OTLP exporters
 	if c.TracingExporter == ExporterOTLP && c.OTLPEndpoint == "" {
 		return fmt.Errorf("OTLP endpoint is required when using OTLP tracing exporter")
@@ -109,6 +121,9 @@ func (c *Config) Validate() error {
 	if c.MetricsExporter == ExporterOTLP && c.OTLPEndpoint == "" {
 		return fmt.Errorf("OTLP endpoint is required when using OTLP metrics exporter")
 	}
+	if c.AuditLogExporter == ExporterOTLP && c.OTLPEndpoint == "" {
+		return fmt.Errorf("OTLP endpoint is required when using OTLP audit log exporter")
+	}
 
 	return nil
 }
@@ -159,16 +174,17 @@ const (
 	OAuthResultDenied   = "denied" // Used when strict mode blocks fallback
 
 	// Operation types
-	OperationGet    = "get"
-	OperationList   = "list"
-	OperationCreate = "create"
-	OperationApply  = "apply"
-	OperationDelete = "delete"
-	OperationPatch  = "patch"
-	OperationScale  = "scale"
-	OperationLogs   = "logs"
-	OperationExec   = "exec"
-	OperationWatch  = "watch"
+	OperationGet      = "get"
+	OperationList     = "list"
+	OperationCreate   = "create"
+	OperationApply    = "apply"
+	OperationDelete   = "delete"
+	OperationPatch    = "patch"
+	OperationScale    = "scale"
+	OperationLogs     = "logs"
+	OperationExec     = "exec"
+	OperationWatch    = "watch"
+	OperationValidate = "validate"
 
 	// Exporter types
 	ExporterPrometheus = "prometheus"
@@ -138,6 +138,26 @@ func TestConfigValidate(t *testing.T) {
 	if err := config.Validate(); err != nil {
 		t.Errorf("expected no error for valid OTLP metrics config, got %v", err)
 	}
+
+	// Test invalid audit log exporter
+	config.MetricsExporter = "prometheus"
+	config.AuditLogExporter = "invalid"
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for invalid audit log exporter")
+	}
+
+	// Test OTLP audit logs without endpoint
+	config.AuditLogExporter = "otlp"
+	config.OTLPEndpoint = ""
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for OTLP audit logs without endpoint")
+	}
+
+	// Test OTLP audit logs with endpoint (valid)
+	config.OTLPEndpoint = "localhost:4318"
+	if err := config.Validate(); err != nil {
+		t.Errorf("expected no error for valid OTLP audit log config, got %v", err)
+	}
 }
 
 func TestGetEnvOrDefault(t *testing.T) {
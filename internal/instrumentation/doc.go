@@ -46,6 +46,10 @@
 //   - mcp_kubernetes_client_cache_misses_total: Counter of client cache misses
 //   - mcp_kubernetes_client_cache_evictions_total: Counter of client cache evictions
 //   - mcp_kubernetes_client_cache_entries: Gauge of current cache entries
+//   - mcp_kubernetes_federation_slot_acquisitions_total: Counter of concurrency limit slot acquisitions (by result)
+//   - mcp_kubernetes_federation_slot_wait_duration_seconds: Histogram of time spent waiting for a slot
+//   - mcp_kubernetes_federation_operations_in_flight: Gauge of workload cluster operations holding a slot
+//   - mcp_kubernetes_federation_operations_queued: Gauge of workload cluster operations waiting for a slot
 //
 // # Cardinality Management
 //
@@ -115,7 +119,8 @@
 //   - METRICS_EXPORTER: Metrics exporter type (prometheus, otlp, stdout, default: prometheus)
 //   - METRICS_DETAILED_LABELS: Include high-cardinality labels (default: false)
 //   - TRACING_EXPORTER: Tracing exporter type (otlp, stdout, none, default: none)
-//   - OTEL_EXPORTER_OTLP_ENDPOINT: OTLP endpoint for traces/metrics
+//   - AUDIT_LOG_EXPORTER: Audit log exporter type (otlp, stdout, none, default: none)
+//   - OTEL_EXPORTER_OTLP_ENDPOINT: OTLP endpoint for traces/metrics/audit logs
 //   - OTEL_TRACES_SAMPLER_ARG: Sampling rate (0.0 to 1.0, default: 0.1)
 //   - OTEL_SERVICE_NAME: Service name (default: mcp-kubernetes)
 //
@@ -0,0 +1,53 @@
+package instrumentation
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+// exemplarInstruments are the latency histograms that carry trace-ID
+// exemplars, so that a latency spike in Grafana links to a trace that caused
+// it. Exemplars are only taken from measurements made within a sampled span,
+// so none are recorded unless tracing is enabled.
+var exemplarInstruments = map[string]bool{
+	"mcp_kubernetes_operation_duration_seconds": true,
+	"mcp_tool_duration_seconds":                 true,
+}
+
+// exemplarView leaves every instrument as it is, but drops the exemplars of
+// instruments outside exemplarInstruments.
+func exemplarView(i metric.Instrument) (metric.Stream, bool) {
+	stream := metric.Stream{
+		Name:        i.Name,
+		Description: i.Description,
+		Unit:        i.Unit,
+	}
+	if !exemplarInstruments[i.Name] {
+		stream.ExemplarReservoirProviderSelector = func(metric.Aggregation) exemplar.ReservoirProvider {
+			return func(attribute.Set) exemplar.Reservoir { return dropReservoir{} }
+		}
+	}
+	return stream, true
+}
+
+// exemplarOptions configures a meter provider to record trace-ID exemplars
+// on the exemplarInstruments only.
+func exemplarOptions() []metric.Option {
+	return []metric.Option{
+		metric.WithExemplarFilter(exemplar.TraceBasedFilter),
+		metric.WithView(exemplarView),
+	}
+}
+
+// dropReservoir is an exemplar reservoir that keeps nothing.
+type dropReservoir struct{}
+
+func (dropReservoir) Offer(context.Context, time.Time, exemplar.Value, []attribute.KeyValue) {}
+
+func (dropReservoir) Collect(dest *[]exemplar.Exemplar) {
+	*dest = (*dest)[:0]
+}
@@ -0,0 +1,87 @@
+package instrumentation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exemplarCounts collects the metrics of reader and returns the number of
+// exemplars per histogram.
+func exemplarCounts(t *testing.T, reader metric.Reader) map[string]int {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+	counts := map[string]int{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if h, ok := m.Data.(metricdata.Histogram[float64]); ok {
+				for _, dp := range h.DataPoints {
+					counts[m.Name] += len(dp.Exemplars)
+				}
+			}
+		}
+	}
+	return counts
+}
+
+func newExemplarTestMetrics(t *testing.T) (*Metrics, metric.Reader) {
+	t.Helper()
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(append([]metric.Option{metric.WithReader(reader)}, exemplarOptions()...)...)
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	m, err := NewMetrics(mp.Meter("test"), false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+	return m, reader
+}
+
+func recordLatencies(ctx context.Context, m *Metrics) {
+	m.RecordK8sOperation(ctx, "", OperationList, "pods", "default", StatusSuccess, 2*time.Second)
+	m.RecordClusterOperation(ctx, "prod-wc-01", OperationGet, StatusSuccess, time.Second)
+	m.RecordToolInvocation(ctx, "list", StatusSuccess, 3*time.Second, 100)
+	m.RecordHTTPRequest(ctx, "POST", "/mcp", 200, time.Second)
+}
+
+func TestExemplars_SampledSpan(t *testing.T) {
+	m, reader := newExemplarTestMetrics(t)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	recordLatencies(ctx, m)
+
+	counts := exemplarCounts(t, reader)
+	if counts["mcp_kubernetes_operation_duration_seconds"] == 0 {
+		t.Error("expected exemplars on mcp_kubernetes_operation_duration_seconds")
+	}
+	if counts["mcp_tool_duration_seconds"] == 0 {
+		t.Error("expected exemplars on mcp_tool_duration_seconds")
+	}
+	if counts["http_request_duration_seconds"] != 0 {
+		t.Errorf("expected no exemplars on http_request_duration_seconds, got %d", counts["http_request_duration_seconds"])
+	}
+}
+
+func TestExemplars_NoSampledSpan(t *testing.T) {
+	m, reader := newExemplarTestMetrics(t)
+
+	recordLatencies(context.Background(), m)
+
+	for name, n := range exemplarCounts(t, reader) {
+		if n != 0 {
+			t.Errorf("expected no exemplars without a sampled span, got %d on %s", n, name)
+		}
+	}
+}
@@ -27,12 +27,30 @@ const (
 	attrUserDomain  = "user_domain"
 	attrClusterType = "cluster_type"
 	attrAuthMode    = "auth_mode"
+	attrEvent       = "event"
 
 	// Kubernetes operation scope labels
 	attrClusterScope  = "cluster_scope"
 	attrDiscoveryMode = "discovery_mode"
+
+	// Output processing attributes
+	attrPattern = "pattern"
+	attrSource  = "source"
+
+	// Tool call attributes
+	attrTool = "tool"
 )
 
+// BytesPerToken is the rough number of bytes per LLM token used to estimate
+// the context a tool response takes up. JSON and YAML tokenize at about four
+// bytes per token.
+const BytesPerToken = 4
+
+// EstimateTokens estimates the LLM tokens of a response of n bytes.
+func EstimateTokens(n int) int {
+	return (n + BytesPerToken - 1) / BytesPerToken
+}
+
 const (
 	clusterScopeManagement = "management"
 	clusterScopeWorkload   = "workload"
@@ -64,6 +82,17 @@ type Metrics struct {
 	clientCacheEvictionsTotal metric.Int64Counter
 	clientCacheSize           metric.Int64Gauge
 
+	// Federation concurrency limit metrics
+	clusterSlotAcquisitionsTotal metric.Int64Counter
+	clusterSlotWaitDuration      metric.Float64Histogram
+	clusterOperationsInFlight    metric.Int64Gauge
+	clusterOperationsQueued      metric.Int64Gauge
+
+	// CAPI cluster discovery cache metrics
+	discoveryCacheRequestsTotal metric.Int64Counter
+	discoveryCacheEventsTotal   metric.Int64Counter
+	discoveryCacheClusters      metric.Int64Gauge
+
 	// CAPI/Federation metrics
 	impersonationTotal        metric.Int64Counter
 	federationClientCreations metric.Int64Counter
@@ -74,6 +103,21 @@ type Metrics struct {
 	// Workload cluster authentication metrics
 	wcAuthTotal metric.Int64Counter
 
+	// Output PII scrubbing metrics
+	piiScrubbedTotal metric.Int64Counter
+
+	// Log and exec output credential redaction metrics
+	credentialsRedactedTotal metric.Int64Counter
+
+	// Kubernetes API read retry metrics
+	apiRetriesTotal metric.Int64Counter
+
+	// Tool call metrics
+	toolInvocationsTotal metric.Int64Counter
+	toolDuration         metric.Float64Histogram
+	toolResponseBytes    metric.Int64Histogram
+	toolResponseTokens   metric.Int64Histogram
+
 	// Configuration
 	// detailedLabels controls whether high-cardinality labels (namespace, resource_type)
 	// are included in Kubernetes operation metrics
@@ -218,6 +262,72 @@ func NewMetrics(meter metric.Meter, detailedLabels bool) (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create mcp_kubernetes_client_cache_entries gauge: %w", err)
 	}
 
+	// Federation Concurrency Limit Metrics
+	m.clusterSlotAcquisitionsTotal, err = meter.Int64Counter(
+		"mcp_kubernetes_federation_slot_acquisitions_total",
+		metric.WithDescription("Total number of attempts to get a slot for contacting a workload cluster. Label: result (acquired, queue_full, timeout, canceled)"),
+		metric.WithUnit("{attempt}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_federation_slot_acquisitions_total counter: %w", err)
+	}
+
+	m.clusterSlotWaitDuration, err = meter.Float64Histogram(
+		"mcp_kubernetes_federation_slot_wait_duration_seconds",
+		metric.WithDescription("Time spent waiting for a slot to contact a workload cluster. Label: result"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.01, 0.1, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_federation_slot_wait_duration_seconds histogram: %w", err)
+	}
+
+	m.clusterOperationsInFlight, err = meter.Int64Gauge(
+		"mcp_kubernetes_federation_operations_in_flight",
+		metric.WithDescription("Current number of workload cluster clients being created under the concurrency limit"),
+		metric.WithUnit("{operation}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_federation_operations_in_flight gauge: %w", err)
+	}
+
+	m.clusterOperationsQueued, err = meter.Int64Gauge(
+		"mcp_kubernetes_federation_operations_queued",
+		metric.WithDescription("Current number of workload cluster operations waiting for a slot"),
+		metric.WithUnit("{operation}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_federation_operations_queued gauge: %w", err)
+	}
+
+	// CAPI Cluster Discovery Cache Metrics
+	m.discoveryCacheRequestsTotal, err = meter.Int64Counter(
+		"mcp_kubernetes_discovery_cache_requests_total",
+		metric.WithDescription("Total number of cluster discovery requests handled by the discovery cache. Label: result (hit, fallback)"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_discovery_cache_requests_total counter: %w", err)
+	}
+
+	m.discoveryCacheEventsTotal, err = meter.Int64Counter(
+		"mcp_kubernetes_discovery_cache_events_total",
+		metric.WithDescription("Total number of CAPI Cluster watch events applied to the discovery cache. Label: event (add, update, delete)"),
+		metric.WithUnit("{event}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_discovery_cache_events_total counter: %w", err)
+	}
+
+	m.discoveryCacheClusters, err = meter.Int64Gauge(
+		"mcp_kubernetes_discovery_cache_clusters",
+		metric.WithDescription("Current number of CAPI clusters in the discovery cache"),
+		metric.WithUnit("{cluster}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_discovery_cache_clusters gauge: %w", err)
+	}
+
 	// CAPI/Federation Metrics
 	//
 	// Note on cardinality: These metrics use cardinality controls:
@@ -268,6 +378,85 @@ func NewMetrics(meter metric.Meter, detailedLabels bool) (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create mcp_kubernetes_wc_auth_total counter: %w", err)
 	}
 
+	// Output PII Scrubbing Metrics
+	//
+	// Note on cardinality: pattern is one of a fixed set of detector names.
+	m.piiScrubbedTotal, err = meter.Int64Counter(
+		"mcp_kubernetes_pii_scrubbed_total",
+		metric.WithDescription("Total PII matches masked in tool output. Labels: pattern (email, ip, bearer_token, aws_key)"),
+		metric.WithUnit("{match}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_pii_scrubbed_total counter: %w", err)
+	}
+
+	// Log and Exec Output Credential Redaction Metrics
+	//
+	// Note on cardinality: source and pattern are both fixed sets.
+	m.credentialsRedactedTotal, err = meter.Int64Counter(
+		"mcp_kubernetes_credentials_redacted_total",
+		metric.WithDescription("Total likely credentials redacted from pod log and exec output. Labels: source (logs, exec), pattern (private_key, jwt, bearer_token, aws_key)"),
+		metric.WithUnit("{match}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_credentials_redacted_total counter: %w", err)
+	}
+
+	// Kubernetes API read retry metrics
+	//
+	// Note on cardinality: operation and reason are both fixed sets.
+	m.apiRetriesTotal, err = meter.Int64Counter(
+		"mcp_kubernetes_api_retries_total",
+		metric.WithDescription("Total retries of Kubernetes API reads after a transient error. Labels: operation (get, list, describe, api-resources), reason (too_many_requests, server_error, connection)"),
+		metric.WithUnit("{retry}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_kubernetes_api_retries_total counter: %w", err)
+	}
+
+	// Tool Call Metrics
+	//
+	// Note on cardinality: tool is one of the registered tools and status is
+	// success or error.
+	m.toolInvocationsTotal, err = meter.Int64Counter(
+		"mcp_tool_invocations_total",
+		metric.WithDescription("Total MCP tool calls. Labels: tool, status (success, error)"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_tool_invocations_total counter: %w", err)
+	}
+
+	m.toolDuration, err = meter.Float64Histogram(
+		"mcp_tool_duration_seconds",
+		metric.WithDescription("MCP tool call duration in seconds. Labels: tool, status"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.05, 0.1, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0, 60.0, 120.0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_tool_duration_seconds histogram: %w", err)
+	}
+
+	m.toolResponseBytes, err = meter.Int64Histogram(
+		"mcp_tool_response_bytes",
+		metric.WithDescription("Size of MCP tool call results in bytes. Labels: tool, status"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_tool_response_bytes histogram: %w", err)
+	}
+
+	m.toolResponseTokens, err = meter.Int64Histogram(
+		"mcp_tool_response_tokens",
+		metric.WithDescription("Estimated LLM tokens of MCP tool call results, at four bytes per token. Labels: tool, status"),
+		metric.WithUnit("{token}"),
+		metric.WithExplicitBucketBoundaries(64, 256, 1024, 4096, 16384, 65536, 262144, 1048576),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mcp_tool_response_tokens histogram: %w", err)
+	}
+
 	return m, nil
 }
 
@@ -364,6 +553,7 @@ func (m *Metrics) RecordOAuthDownstreamAuth(ctx context.Context, result string)
 //   - "no_user": No authenticated user in context (passthrough)
 //   - "no_store": Token store not available (passthrough)
 //   - "lookup_failed": Token lookup failed (passthrough)
+//   - "token_expired": ID token expired and could not be refreshed (passthrough)
 func (m *Metrics) RecordSSOTokenInjection(ctx context.Context, result string) {
 	if m.oauthSSOTokenInjectionTotal == nil {
 		return // Instrumentation not initialized
@@ -453,6 +643,64 @@ func (m *Metrics) SetCacheSize(ctx context.Context, size int) {
 	m.clientCacheSize.Record(ctx, int64(size))
 }
 
+// RecordClusterSlotAcquisition records an attempt to get a slot for contacting
+// a workload cluster and how long it waited.
+// Results: "acquired", "queue_full", "timeout", "canceled"
+func (m *Metrics) RecordClusterSlotAcquisition(ctx context.Context, result string, wait time.Duration) {
+	if m.clusterSlotAcquisitionsTotal == nil || m.clusterSlotWaitDuration == nil {
+		return // Instrumentation not initialized
+	}
+
+	attrs := metric.WithAttributes(attribute.String(attrResult, result))
+	m.clusterSlotAcquisitionsTotal.Add(ctx, 1, attrs)
+	m.clusterSlotWaitDuration.Record(ctx, wait.Seconds(), attrs)
+}
+
+// SetClusterOperationUsage sets the number of workload cluster operations
+// holding a slot and waiting for one.
+func (m *Metrics) SetClusterOperationUsage(ctx context.Context, inFlight, queued int) {
+	if m.clusterOperationsInFlight == nil || m.clusterOperationsQueued == nil {
+		return // Instrumentation not initialized
+	}
+
+	m.clusterOperationsInFlight.Record(ctx, int64(inFlight))
+	m.clusterOperationsQueued.Record(ctx, int64(queued))
+}
+
+// RecordDiscoveryCacheRequest records a cluster discovery request handled by
+// the discovery cache.
+// Results: "hit", "fallback"
+func (m *Metrics) RecordDiscoveryCacheRequest(ctx context.Context, result string) {
+	if m.discoveryCacheRequestsTotal == nil {
+		return // Instrumentation not initialized
+	}
+
+	m.discoveryCacheRequestsTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(attrResult, result),
+	))
+}
+
+// RecordDiscoveryCacheEvent records a watch event applied to the discovery cache.
+// Events: "add", "update", "delete"
+func (m *Metrics) RecordDiscoveryCacheEvent(ctx context.Context, event string) {
+	if m.discoveryCacheEventsTotal == nil {
+		return // Instrumentation not initialized
+	}
+
+	m.discoveryCacheEventsTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(attrEvent, event),
+	))
+}
+
+// SetDiscoveryCacheClusters sets the number of clusters in the discovery cache.
+func (m *Metrics) SetDiscoveryCacheClusters(ctx context.Context, count int) {
+	if m.discoveryCacheClusters == nil {
+		return // Instrumentation not initialized
+	}
+
+	m.discoveryCacheClusters.Record(ctx, int64(count))
+}
+
 // RecordClusterOperation records a workload cluster operation using the unified
 // mcp_kubernetes_* metrics with cluster_scope=workload and discovery_mode=capi.
 //
@@ -574,7 +822,7 @@ func (m *Metrics) RecordPrivilegedAccess(ctx context.Context, userDomain, operat
 // Parameters:
 //   - authMode: Authentication mode ("impersonation" or "sso-passthrough")
 //   - clusterName: Target cluster (will be classified for cardinality control)
-//   - result: One of "success", "error", "token_missing"
+//   - result: One of "success", "error", "token_missing", "token_exchange_failed"
 func (m *Metrics) RecordWorkloadClusterAuth(ctx context.Context, authMode, clusterName, result string) {
 	if m.wcAuthTotal == nil {
 		return // Instrumentation not initialized
@@ -588,3 +836,64 @@ func (m *Metrics) RecordWorkloadClusterAuth(ctx context.Context, authMode, clust
 
 	m.wcAuthTotal.Add(ctx, 1, metric.WithAttributes(attrs...))
 }
+
+// RecordPIIScrubbed records count PII matches masked in tool output for the
+// given pattern (email, ip, bearer_token, aws_key).
+func (m *Metrics) RecordPIIScrubbed(ctx context.Context, pattern string, count int) {
+	if m.piiScrubbedTotal == nil || count <= 0 {
+		return // Instrumentation not initialized
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String(attrPattern, pattern),
+	}
+
+	m.piiScrubbedTotal.Add(ctx, int64(count), metric.WithAttributes(attrs...))
+}
+
+// RecordCredentialsRedacted records count likely credentials redacted from
+// pod log or exec output (source) for the given pattern.
+func (m *Metrics) RecordCredentialsRedacted(ctx context.Context, source, pattern string, count int) {
+	if m.credentialsRedactedTotal == nil || count <= 0 {
+		return // Instrumentation not initialized
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String(attrSource, source),
+		attribute.String(attrPattern, pattern),
+	}
+
+	m.credentialsRedactedTotal.Add(ctx, int64(count), metric.WithAttributes(attrs...))
+}
+
+// RecordAPIRetry records a retry of a Kubernetes API read operation after a
+// transient error of the given reason.
+func (m *Metrics) RecordAPIRetry(ctx context.Context, operation, reason string) {
+	if m.apiRetriesTotal == nil {
+		return // Instrumentation not initialized
+	}
+
+	m.apiRetriesTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(attrOperation, operation),
+		attribute.String(attrReason, reason),
+	))
+}
+
+// RecordToolInvocation records a tool call with its status (success or
+// error), duration and the size of its result in bytes, from which the
+// LLM tokens it takes up are estimated.
+func (m *Metrics) RecordToolInvocation(ctx context.Context, tool, status string, duration time.Duration, responseBytes int) {
+	if m.toolInvocationsTotal == nil || m.toolDuration == nil || m.toolResponseBytes == nil || m.toolResponseTokens == nil {
+		return // Instrumentation not initialized
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String(attrTool, tool),
+		attribute.String(attrStatus, status),
+	)
+
+	m.toolInvocationsTotal.Add(ctx, 1, attrs)
+	m.toolDuration.Record(ctx, duration.Seconds(), attrs)
+	m.toolResponseBytes.Record(ctx, int64(responseBytes), attrs)
+	m.toolResponseTokens.Record(ctx, int64(EstimateTokens(responseBytes)), attrs)
+}
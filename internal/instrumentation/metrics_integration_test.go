@@ -114,6 +114,18 @@ func TestAllMetricsExposedViaPrometheus(t *testing.T) {
 
 		// Workload cluster auth metrics
 		{"mcp_kubernetes_wc_auth_total", "Workload cluster auth attempts", false},
+
+		// Output PII scrubbing metrics
+		{"mcp_kubernetes_pii_scrubbed_total", "PII matches scrubbed", false},
+
+		// Log and exec credential redaction metrics
+		{"mcp_kubernetes_credentials_redacted_total", "Credentials redacted", false},
+
+		// Tool call metrics
+		{"mcp_tool_invocations_total", "Tool calls", false},
+		{"mcp_tool_duration_seconds", "Tool call duration", true},
+		{"mcp_tool_response_bytes", "Tool result size", true},
+		{"mcp_tool_response_tokens", "Tool result estimated tokens", true},
 	}
 
 	// Check each metric
@@ -241,6 +253,17 @@ func recordAllMetrics(ctx context.Context, m *Metrics) {
 	m.RecordWorkloadClusterAuth(ctx, "sso-passthrough", "staging-cluster", "success")
 	m.RecordWorkloadClusterAuth(ctx, "impersonation", "dev-cluster", "error")
 	m.RecordWorkloadClusterAuth(ctx, "sso-passthrough", "new-cluster", "token_missing")
+
+	// Output PII scrubbing metrics
+	m.RecordPIIScrubbed(ctx, "email", 2)
+	m.RecordPIIScrubbed(ctx, "bearer_token", 1)
+
+	// Log and exec credential redaction metrics
+	m.RecordCredentialsRedacted(ctx, "logs", "jwt", 1)
+
+	// Tool call metrics
+	m.RecordToolInvocation(ctx, "list", StatusSuccess, 120*time.Millisecond, 18000)
+	m.RecordToolInvocation(ctx, "delete", StatusError, 30*time.Millisecond, 200)
 }
 
 // containsMetric checks if the metrics output contains a metric line
@@ -617,6 +617,57 @@ func TestMetrics_CacheMetrics_NilMetrics(t *testing.T) {
 	metrics.SetCacheSize(ctx, 42)
 }
 
+func TestMetrics_ConcurrencyMetrics(t *testing.T) {
+	meter := mockMeterProvider()
+	metrics, err := NewMetrics(meter, false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+
+	ctx := context.Background()
+
+	metrics.RecordClusterSlotAcquisition(ctx, "acquired", 0)
+	metrics.RecordClusterSlotAcquisition(ctx, "timeout", 30*time.Second)
+	metrics.RecordClusterSlotAcquisition(ctx, "queue_full", 0)
+	metrics.SetClusterOperationUsage(ctx, 20, 7)
+}
+
+func TestMetrics_ConcurrencyMetrics_NilMetrics(t *testing.T) {
+	metrics := &Metrics{}
+	ctx := context.Background()
+
+	// Should not panic with nil metrics
+	metrics.RecordClusterSlotAcquisition(ctx, "acquired", time.Second)
+	metrics.SetClusterOperationUsage(ctx, 1, 0)
+}
+
+func TestMetrics_DiscoveryCacheMetrics(t *testing.T) {
+	meter := mockMeterProvider()
+	metrics, err := NewMetrics(meter, false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+
+	ctx := context.Background()
+
+	metrics.RecordDiscoveryCacheRequest(ctx, "hit")
+	metrics.RecordDiscoveryCacheRequest(ctx, "fallback")
+	metrics.RecordDiscoveryCacheEvent(ctx, "add")
+	metrics.RecordDiscoveryCacheEvent(ctx, "update")
+	metrics.RecordDiscoveryCacheEvent(ctx, "delete")
+	metrics.SetDiscoveryCacheClusters(ctx, 12)
+}
+
+func TestMetrics_DiscoveryCacheMetrics_NilMetrics(t *testing.T) {
+	metrics := &Metrics{}
+	ctx := context.Background()
+
+	// Should not panic with nil metrics
+	metrics.RecordDiscoveryCacheRequest(ctx, "hit")
+	metrics.RecordDiscoveryCacheEvent(ctx, "add")
+	metrics.SetDiscoveryCacheClusters(ctx, 12)
+}
+
 // SSO Token Injection metrics tests
 
 func TestMetrics_RecordSSOTokenInjection(t *testing.T) {
@@ -831,6 +882,12 @@ func TestNewMetrics_AllMetricsInitialized(t *testing.T) {
 
 		// Workload cluster auth metrics
 		{"wcAuthTotal", metrics.wcAuthTotal},
+
+		// Output PII scrubbing metrics
+		{"piiScrubbedTotal", metrics.piiScrubbedTotal},
+
+		// Log and exec credential redaction metrics
+		{"credentialsRedactedTotal", metrics.credentialsRedactedTotal},
 	}
 
 	for _, check := range checks {
@@ -839,3 +896,91 @@ func TestNewMetrics_AllMetricsInitialized(t *testing.T) {
 		}
 	}
 }
+
+// PII scrubbing metrics tests
+
+func TestMetrics_RecordPIIScrubbed(t *testing.T) {
+	meter := mockMeterProvider()
+	metrics, err := NewMetrics(meter, false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+
+	ctx := context.Background()
+	metrics.RecordPIIScrubbed(ctx, "email", 3)
+	metrics.RecordPIIScrubbed(ctx, "ip", 1)
+	metrics.RecordPIIScrubbed(ctx, "aws_key", 0)
+}
+
+func TestMetrics_RecordPIIScrubbed_NilMetrics(t *testing.T) {
+	metrics := &Metrics{}
+
+	// Should not panic with nil metrics
+	metrics.RecordPIIScrubbed(context.Background(), "email", 1)
+}
+
+func TestMetrics_RecordCredentialsRedacted(t *testing.T) {
+	meter := mockMeterProvider()
+	metrics, err := NewMetrics(meter, false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+
+	ctx := context.Background()
+	metrics.RecordCredentialsRedacted(ctx, "logs", "jwt", 2)
+	metrics.RecordCredentialsRedacted(ctx, "exec", "private_key", 1)
+	metrics.RecordCredentialsRedacted(ctx, "logs", "aws_key", 0)
+}
+
+func TestMetrics_RecordCredentialsRedacted_NilMetrics(t *testing.T) {
+	metrics := &Metrics{}
+
+	// Should not panic with nil metrics
+	metrics.RecordCredentialsRedacted(context.Background(), "logs", "jwt", 1)
+}
+
+func TestMetrics_RecordAPIRetry(t *testing.T) {
+	meter := mockMeterProvider()
+	metrics, err := NewMetrics(meter, false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+
+	ctx := context.Background()
+	metrics.RecordAPIRetry(ctx, "get", "too_many_requests")
+	metrics.RecordAPIRetry(ctx, "list", "connection")
+}
+
+func TestMetrics_RecordAPIRetry_NilMetrics(t *testing.T) {
+	metrics := &Metrics{}
+
+	// Should not panic with nil metrics
+	metrics.RecordAPIRetry(context.Background(), "get", "server_error")
+}
+
+func TestMetrics_RecordToolInvocation(t *testing.T) {
+	meter := mockMeterProvider()
+	metrics, err := NewMetrics(meter, false)
+	if err != nil {
+		t.Fatalf("expected no error creating metrics, got %v", err)
+	}
+
+	ctx := context.Background()
+	metrics.RecordToolInvocation(ctx, "list", StatusSuccess, 250*time.Millisecond, 40000)
+	metrics.RecordToolInvocation(ctx, "get", StatusError, time.Millisecond, 0)
+}
+
+func TestMetrics_RecordToolInvocation_NilMetrics(t *testing.T) {
+	metrics := &Metrics{}
+
+	// Should not panic with nil metrics
+	metrics.RecordToolInvocation(context.Background(), "list", StatusSuccess, time.Second, 100)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	for n, want := range map[int]int{0: 0, 1: 1, 4: 1, 5: 2, 4000: 1000} {
+		if got := EstimateTokens(n); got != want {
+			t.Errorf("EstimateTokens(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package instrumentation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Monitor kinds supported by GenerateMonitor.
+const (
+	MonitorKindServiceMonitor = "ServiceMonitor"
+	MonitorKindPodMonitor     = "PodMonitor"
+)
+
+// ObservabilityOptions parameterizes the generated Prometheus Operator
+// monitor and Grafana dashboard.
+type ObservabilityOptions struct {
+	// Name is the name of the generated monitor and dashboard ConfigMap.
+	Name string
+
+	// Namespace is the namespace mcp-kubernetes runs in. The monitor is
+	// created there and the dashboard queries are scoped to it.
+	Namespace string
+
+	// Selector holds the Kubernetes labels that select the mcp-kubernetes
+	// Service (ServiceMonitor) or Pods (PodMonitor).
+	Selector map[string]string
+
+	// MonitorLabels are extra labels added to the monitor, e.g. the
+	// release label a Prometheus instance selects monitors by.
+	MonitorLabels map[string]string
+
+	// Kind is MonitorKindServiceMonitor (default) or MonitorKindPodMonitor.
+	Kind string
+
+	// Port is the name of the metrics port (default "metrics").
+	Port string
+
+	// Path is the metrics path (default "/metrics").
+	Path string
+
+	// Interval is the scrape interval (default "30s").
+	Interval string
+
+	// DashboardLabels are labels added to the dashboard ConfigMap so the
+	// Grafana sidecar picks it up (default grafana_dashboard: "1").
+	DashboardLabels map[string]string
+}
+
+// withDefaults returns a copy of o with unset fields filled in.
+func (o ObservabilityOptions) withDefaults() ObservabilityOptions {
+	if o.Name == "" {
+		o.Name = "mcp-kubernetes"
+	}
+	if o.Namespace == "" {
+		o.Namespace = "default"
+	}
+	if len(o.Selector) == 0 {
+		o.Selector = map[string]string{"app.kubernetes.io/name": "mcp-kubernetes"}
+	}
+	if o.Kind == "" {
+		o.Kind = MonitorKindServiceMonitor
+	}
+	if o.Port == "" {
+		o.Port = "metrics"
+	}
+	if o.Path == "" {
+		o.Path = "/metrics"
+	}
+	if o.Interval == "" {
+		o.Interval = "30s"
+	}
+	if len(o.DashboardLabels) == 0 {
+		o.DashboardLabels = map[string]string{"grafana_dashboard": "1"}
+	}
+	return o
+}
+
+// Validate checks that the options describe a valid monitor.
+func (o ObservabilityOptions) Validate() error {
+	o = o.withDefaults()
+	if o.Kind != MonitorKindServiceMonitor && o.Kind != MonitorKindPodMonitor {
+		return fmt.Errorf("invalid monitor kind %q: must be %s or %s", o.Kind, MonitorKindServiceMonitor, MonitorKindPodMonitor)
+	}
+	return nil
+}
+
+// ScrapeJob returns the Prometheus job label the Prometheus Operator assigns
+// to targets discovered by the generated monitor. Dashboard queries filter on it.
+func (o ObservabilityOptions) ScrapeJob() string {
+	o = o.withDefaults()
+	if o.Kind == MonitorKindPodMonitor {
+		return o.Namespace + "/" + o.Name
+	}
+	// ServiceMonitor targets get the Service name as job. The Service is
+	// assumed to share the monitor's name.
+	return o.Name
+}
+
+// GenerateMonitor returns a ServiceMonitor or PodMonitor manifest as a
+// generic object, ready to be marshalled to YAML or JSON.
+func GenerateMonitor(opts ObservabilityOptions) (map[string]interface{}, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	labels := copyStringMap(opts.Selector)
+	for k, v := range opts.MonitorLabels {
+		labels[k] = v
+	}
+
+	endpoint := map[string]interface{}{
+		"port":     opts.Port,
+		"path":     opts.Path,
+		"interval": opts.Interval,
+	}
+	endpointsKey := "endpoints"
+	if opts.Kind == MonitorKindPodMonitor {
+		endpointsKey = "podMetricsEndpoints"
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       opts.Kind,
+		"metadata": map[string]interface{}{
+			"name":      opts.Name,
+			"namespace": opts.Namespace,
+			"labels":    labels,
+		},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": copyStringMap(opts.Selector),
+			},
+			"namespaceSelector": map[string]interface{}{
+				"matchNames": []string{opts.Namespace},
+			},
+			endpointsKey: []interface{}{endpoint},
+		},
+	}, nil
+}
+
+// GenerateDashboardConfigMap wraps the dashboard JSON in a ConfigMap labelled
+// for the Grafana dashboard sidecar.
+func GenerateDashboardConfigMap(opts ObservabilityOptions, dashboardJSON []byte) map[string]interface{} {
+	opts = opts.withDefaults()
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      opts.Name + "-dashboard",
+			"namespace": opts.Namespace,
+			"labels":    copyStringMap(opts.DashboardLabels),
+		},
+		"data": map[string]string{
+			opts.Name + ".json": string(dashboardJSON),
+		},
+	}
+}
+
+// dashboardPanel describes one time series panel of the generated dashboard.
+type dashboardPanel struct {
+	title       string
+	description string
+	unit        string
+	exprs       []string
+	legends     []string
+}
+
+// dashboardRow groups panels under a collapsible row header.
+type dashboardRow struct {
+	title  string
+	panels []dashboardPanel
+}
+
+// dashboardRows returns the dashboard layout for the server's metric
+// families. sel is the PromQL label matcher scoping every query.
+func dashboardRows(sel string) []dashboardRow {
+	q := func(format string) string { return strings.ReplaceAll(format, "SEL", sel) }
+
+	return []dashboardRow{
+		{
+			title: "HTTP",
+			panels: []dashboardPanel{
+				{
+					title: "Request rate by status", unit: "reqps",
+					exprs:   []string{q(`sum by (status) (rate(http_requests_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{status}}"},
+				},
+				{
+					title: "Request latency", unit: "s",
+					exprs: []string{
+						q(`histogram_quantile(0.50, sum by (le) (rate(http_request_duration_seconds_bucket{SEL}[$__rate_interval])))`),
+						q(`histogram_quantile(0.95, sum by (le) (rate(http_request_duration_seconds_bucket{SEL}[$__rate_interval])))`),
+						q(`histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket{SEL}[$__rate_interval])))`),
+					},
+					legends: []string{"p50", "p95", "p99"},
+				},
+			},
+		},
+		{
+			title: "Kubernetes operations",
+			panels: []dashboardPanel{
+				{
+					title: "Operations by type", unit: "ops",
+					exprs:   []string{q(`sum by (operation, status) (rate(mcp_kubernetes_operations_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{operation}} {{status}}"},
+				},
+				{
+					title: "Operation latency (p95)", unit: "s",
+					exprs:   []string{q(`histogram_quantile(0.95, sum by (le, operation) (rate(mcp_kubernetes_operation_duration_seconds_bucket{SEL}[$__rate_interval])))`)},
+					legends: []string{"{{operation}}"},
+				},
+				{
+					title: "Pod operations", unit: "ops",
+					exprs:   []string{q(`sum by (operation, status) (rate(kubernetes_pod_operations_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{operation}} {{status}}"},
+				},
+				{
+					title: "Active port-forward sessions", unit: "short",
+					exprs:   []string{q(`sum(active_port_forward_sessions{SEL})`)},
+					legends: []string{"sessions"},
+				},
+			},
+		},
+		{
+			title: "Authentication",
+			panels: []dashboardPanel{
+				{
+					title: "Downstream OAuth by result", unit: "ops",
+					exprs:   []string{q(`sum by (result) (rate(oauth_downstream_auth_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{result}}"},
+				},
+				{
+					title: "SSO token injection by result", unit: "ops",
+					exprs:   []string{q(`sum by (result) (rate(oauth_sso_token_injection_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{result}}"},
+				},
+				{
+					title: "Privileged access by result", unit: "ops",
+					exprs:   []string{q(`sum by (operation, result) (rate(mcp_kubernetes_privileged_access_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{operation}} {{result}}"},
+				},
+			},
+		},
+		{
+			title: "Federation",
+			panels: []dashboardPanel{
+				{
+					title: "Client cache hit ratio", unit: "percentunit",
+					description: "Share of workload cluster client lookups served from cache",
+					exprs:       []string{q(`sum(rate(mcp_kubernetes_client_cache_hits_total{SEL}[$__rate_interval])) / (sum(rate(mcp_kubernetes_client_cache_hits_total{SEL}[$__rate_interval])) + sum(rate(mcp_kubernetes_client_cache_misses_total{SEL}[$__rate_interval])))`)},
+					legends:     []string{"hit ratio"},
+				},
+				{
+					title: "Client cache entries and evictions", unit: "short",
+					exprs: []string{
+						q(`sum(mcp_kubernetes_client_cache_entries{SEL})`),
+						q(`sum by (reason) (rate(mcp_kubernetes_client_cache_evictions_total{SEL}[$__rate_interval]))`),
+					},
+					legends: []string{"entries", "evictions {{reason}}"},
+				},
+				{
+					title: "Impersonation by result", unit: "ops",
+					exprs:   []string{q(`sum by (cluster_type, result) (rate(mcp_kubernetes_impersonation_total{SEL}[$__rate_interval]))`)},
+					legends: []string{"{{cluster_type}} {{result}}"},
+				},
+				{
+					title: "Workload cluster auth by mode", unit: "ops",
+					exprs: []string{
+						q(`sum by (auth_mode, result) (rate(mcp_kubernetes_wc_auth_total{SEL}[$__rate_interval]))`),
+						q(`sum by (result) (rate(mcp_kubernetes_federation_client_creations_total{SEL}[$__rate_interval]))`),
+					},
+					legends: []string{"{{auth_mode}} {{result}}", "client creation {{result}}"},
+				},
+			},
+		},
+	}
+}
+
+// GenerateDashboard returns an opinionated Grafana dashboard covering the
+// server's metric families. All queries are scoped to the monitor's
+// namespace and scrape job.
+func GenerateDashboard(opts ObservabilityOptions) (map[string]interface{}, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	sel := fmt.Sprintf(`namespace=%q, job=%q`, opts.Namespace, opts.ScrapeJob())
+	datasource := map[string]interface{}{"type": "prometheus", "uid": "${datasource}"}
+
+	var panels []interface{}
+	id, y := 1, 0
+	for _, row := range dashboardRows(sel) {
+		panels = append(panels, map[string]interface{}{
+			"id":        id,
+			"type":      "row",
+			"title":     row.title,
+			"collapsed": false,
+			"gridPos":   map[string]int{"h": 1, "w": 24, "x": 0, "y": y},
+			"panels":    []interface{}{},
+		})
+		id++
+		y++
+
+		for i, p := range row.panels {
+			targets := make([]interface{}, 0, len(p.exprs))
+			for j, expr := range p.exprs {
+				targets = append(targets, map[string]interface{}{
+					"datasource":   datasource,
+					"expr":         expr,
+					"legendFormat": p.legends[j],
+					"refId":        string(rune('A' + j)),
+				})
+			}
+			panels = append(panels, map[string]interface{}{
+				"id":          id,
+				"type":        "timeseries",
+				"title":       p.title,
+				"description": p.description,
+				"datasource":  datasource,
+				"gridPos":     map[string]int{"h": 8, "w": 12, "x": (i % 2) * 12, "y": y + (i/2)*8},
+				"fieldConfig": map[string]interface{}{
+					"defaults":  map[string]interface{}{"unit": p.unit},
+					"overrides": []interface{}{},
+				},
+				"options": map[string]interface{}{
+					"legend":  map[string]interface{}{"displayMode": "list", "placement": "bottom", "showLegend": true},
+					"tooltip": map[string]interface{}{"mode": "multi", "sort": "desc"},
+				},
+				"targets": targets,
+			})
+			id++
+		}
+		y += ((len(row.panels) + 1) / 2) * 8
+	}
+
+	return map[string]interface{}{
+		"uid":           opts.Name + "-overview",
+		"title":         "MCP Kubernetes - " + opts.Namespace,
+		"tags":          []string{"mcp-kubernetes"},
+		"schemaVersion": 38,
+		"editable":      true,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":  "datasource",
+					"label": "Datasource",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+			},
+		},
+		"panels": panels,
+	}, nil
+}
+
+// copyStringMap returns a shallow copy of m. A nil map yields an empty map.
+func copyStringMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package instrumentation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMonitor(t *testing.T) {
+	t.Run("service monitor defaults", func(t *testing.T) {
+		m, err := GenerateMonitor(ObservabilityOptions{Namespace: "mcp"})
+		require.NoError(t, err)
+
+		assert.Equal(t, MonitorKindServiceMonitor, m["kind"])
+		meta := m["metadata"].(map[string]interface{})
+		assert.Equal(t, "mcp-kubernetes", meta["name"])
+		assert.Equal(t, "mcp", meta["namespace"])
+
+		spec := m["spec"].(map[string]interface{})
+		selector := spec["selector"].(map[string]interface{})
+		assert.Equal(t, map[string]string{"app.kubernetes.io/name": "mcp-kubernetes"}, selector["matchLabels"])
+		endpoints := spec["endpoints"].([]interface{})
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, "metrics", endpoints[0].(map[string]interface{})["port"])
+	})
+
+	t.Run("pod monitor with custom labels", func(t *testing.T) {
+		m, err := GenerateMonitor(ObservabilityOptions{
+			Namespace:     "mcp",
+			Kind:          MonitorKindPodMonitor,
+			Selector:      map[string]string{"app": "mcp"},
+			MonitorLabels: map[string]string{"release": "prometheus"},
+			Interval:      "15s",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, MonitorKindPodMonitor, m["kind"])
+		labels := m["metadata"].(map[string]interface{})["labels"].(map[string]string)
+		assert.Equal(t, map[string]string{"app": "mcp", "release": "prometheus"}, labels)
+
+		spec := m["spec"].(map[string]interface{})
+		assert.NotContains(t, spec, "endpoints")
+		endpoints := spec["podMetricsEndpoints"].([]interface{})
+		assert.Equal(t, "15s", endpoints[0].(map[string]interface{})["interval"])
+		// Monitor labels must not leak into the target selector.
+		assert.Equal(t, map[string]string{"app": "mcp"}, spec["selector"].(map[string]interface{})["matchLabels"])
+	})
+
+	t.Run("invalid kind", func(t *testing.T) {
+		_, err := GenerateMonitor(ObservabilityOptions{Kind: "Probe"})
+		assert.Error(t, err)
+	})
+}
+
+func TestObservabilityOptions_ScrapeJob(t *testing.T) {
+	assert.Equal(t, "mcp-kubernetes", ObservabilityOptions{Namespace: "mcp"}.ScrapeJob())
+	assert.Equal(t, "mcp/mcp-kubernetes", ObservabilityOptions{Namespace: "mcp", Kind: MonitorKindPodMonitor}.ScrapeJob())
+}
+
+func TestGenerateDashboard(t *testing.T) {
+	d, err := GenerateDashboard(ObservabilityOptions{Namespace: "mcp", Kind: MonitorKindPodMonitor})
+	require.NoError(t, err)
+
+	assert.Equal(t, "mcp-kubernetes-overview", d["uid"])
+
+	var exprs []string
+	ids := map[int]bool{}
+	for _, p := range d["panels"].([]interface{}) {
+		panel := p.(map[string]interface{})
+		id := panel["id"].(int)
+		assert.False(t, ids[id], "panel ids must be unique")
+		ids[id] = true
+
+		targets, _ := panel["targets"].([]interface{})
+		for _, target := range targets {
+			exprs = append(exprs, target.(map[string]interface{})["expr"].(string))
+		}
+	}
+	require.NotEmpty(t, exprs)
+
+	joined := strings.Join(exprs, "\n")
+	for _, family := range []string{
+		"http_requests_total",
+		"http_request_duration_seconds_bucket",
+		"mcp_kubernetes_operations_total",
+		"kubernetes_pod_operations_total",
+		"oauth_downstream_auth_total",
+		"mcp_kubernetes_client_cache_hits_total",
+		"mcp_kubernetes_impersonation_total",
+		"mcp_kubernetes_wc_auth_total",
+	} {
+		assert.Contains(t, joined, family)
+	}
+	for _, expr := range exprs {
+		assert.Contains(t, expr, `namespace="mcp", job="mcp/mcp-kubernetes"`, "every query must be scoped to the monitor")
+	}
+}
+
+func TestGenerateDashboardConfigMap(t *testing.T) {
+	cm := GenerateDashboardConfigMap(ObservabilityOptions{Namespace: "mcp"}, []byte(`{}`))
+	meta := cm["metadata"].(map[string]interface{})
+	assert.Equal(t, "mcp-kubernetes-dashboard", meta["name"])
+	assert.Equal(t, map[string]string{"grafana_dashboard": "1"}, meta["labels"])
+	assert.Equal(t, map[string]string{"mcp-kubernetes.json": "{}"}, cm["data"])
+}
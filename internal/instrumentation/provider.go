@@ -9,11 +9,14 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -22,11 +25,13 @@ import (
 	"go.opentelemetry.io/otel/trace/noop"
 )
 
-// Provider encapsulates OpenTelemetry meter and tracer providers.
+// Provider encapsulates OpenTelemetry meter, tracer and audit logger
+// providers.
 type Provider struct {
 	config             Config
 	meterProvider      *metric.MeterProvider
 	tracerProvider     *sdktrace.TracerProvider
+	loggerProvider     *sdklog.LoggerProvider
 	metrics            *Metrics
 	prometheusExporter *prometheus.Exporter
 	auditLogger        *AuditLogger
@@ -91,6 +96,12 @@ func NewProvider(ctx context.Context, config Config) (*Provider, error) {
 		return nil, fmt.Errorf("failed to initialize tracer provider: %w", err)
 	}
 
+	// Initialize logger provider for audit log export
+	if err := provider.initLoggerProvider(ctx, res); err != nil {
+		_ = provider.Shutdown(ctx)
+		return nil, fmt.Errorf("failed to initialize audit logger provider: %w", err)
+	}
+
 	// Set global providers
 	otel.SetMeterProvider(provider.meterProvider)
 	otel.SetTracerProvider(provider.tracerProvider)
@@ -106,6 +117,9 @@ func NewProvider(ctx context.Context, config Config) (*Provider, error) {
 
 	// Create audit logger
 	provider.auditLogger = NewAuditLogger(slog.Default())
+	if provider.loggerProvider != nil {
+		provider.auditLogger.WithLoggerProvider(provider.loggerProvider)
+	}
 
 	return provider, nil
 }
@@ -162,10 +176,11 @@ func (p *Provider) initMeterProvider(ctx context.Context, res *resource.Resource
 	}
 
 	// Create meter provider
-	p.meterProvider = metric.NewMeterProvider(
+	opts := append([]metric.Option{
 		metric.WithResource(res),
 		metric.WithReader(reader),
-	)
+	}, exemplarOptions()...)
+	p.meterProvider = metric.NewMeterProvider(opts...)
 
 	return nil
 }
@@ -240,6 +255,64 @@ func (p *Provider) initTracerProvider(ctx context.Context, res *resource.Resourc
 	return nil
 }
 
+// initLoggerProvider initializes the OpenTelemetry logger provider that audit
+// records are exported through. It is left nil when audit log export is off.
+// The provider is not set globally: only audit records are exported.
+func (p *Provider) initLoggerProvider(ctx context.Context, res *resource.Resource) error {
+	var exporter sdklog.Exporter
+	var err error
+
+	switch p.config.AuditLogExporter {
+	case "", ExporterNone:
+		return nil
+
+	case ExporterOTLP:
+		if p.config.OTLPEndpoint == "" {
+			return fmt.Errorf("OTLP endpoint is required for OTLP audit log exporter")
+		}
+
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(p.config.OTLPEndpoint),
+		}
+
+		if p.config.OTLPInsecure {
+			// SECURITY WARNING: Audit records contain user identities
+			slog.Warn("OTLP insecure transport enabled - audit records contain user identities, use only for development",
+				"component", "instrumentation",
+				"exporter", ExporterOTLP,
+				"endpoint", p.config.OTLPEndpoint,
+			)
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+
+		exporter, err = otlploghttp.New(ctx, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create OTLP audit log exporter: %w", err)
+		}
+
+	case ExporterStdout:
+		// DEVELOPMENT ONLY WARNING
+		slog.Warn("stdout audit log exporter enabled - for development/debugging only, not for production",
+			"component", "instrumentation",
+			"exporter", ExporterStdout,
+		)
+		exporter, err = stdoutlog.New()
+		if err != nil {
+			return fmt.Errorf("failed to create stdout audit log exporter: %w", err)
+		}
+
+	default:
+		return fmt.Errorf("unsupported audit log exporter: %s", p.config.AuditLogExporter)
+	}
+
+	p.loggerProvider = sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+
+	return nil
+}
+
 // Metrics returns the metrics recorder for recording observability metrics.
 func (p *Provider) Metrics() *Metrics {
 	return p.metrics
@@ -289,6 +362,13 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Shutdown logger provider, flushing pending audit records
+	if p.loggerProvider != nil {
+		if err := p.loggerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown logger provider: %w", err))
+		}
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -100,6 +100,38 @@ func TestNewProvider_StdoutExporter(t *testing.T) {
 	}
 }
 
+func TestNewProvider_AuditLogExporter(t *testing.T) {
+	config := Config{
+		ServiceName:      "test-service",
+		ServiceVersion:   "1.0.0",
+		Enabled:          true,
+		MetricsExporter:  "prometheus",
+		TracingExporter:  "none",
+		AuditLogExporter: "stdout",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	provider, err := NewProvider(ctx, config)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer func() { _ = provider.Shutdown(ctx) }()
+
+	if provider.loggerProvider == nil {
+		t.Error("expected logger provider for stdout audit log exporter")
+	}
+	if provider.AuditLogger().exporter == nil {
+		t.Error("expected audit logger to export records")
+	}
+
+	config.AuditLogExporter = "invalid"
+	if _, err := NewProvider(ctx, config); err == nil {
+		t.Error("expected error for invalid audit log exporter")
+	}
+}
+
 func TestNewProvider_InvalidMetricsExporter(t *testing.T) {
 	config := Config{
 		ServiceName:     "test-service",
@@ -52,6 +52,19 @@ const (
 
 	// SpanAttrFederated indicates whether federation was used.
 	SpanAttrFederated = "mcp.federated"
+
+	// SpanAttrItemCount is the number of items an output stage returned.
+	SpanAttrItemCount = "mcp.output.item_count"
+
+	// SpanAttrOriginalItemCount is the number of items an output stage was
+	// given.
+	SpanAttrOriginalItemCount = "mcp.output.original_item_count"
+
+	// SpanAttrTruncated indicates whether output processing dropped items.
+	SpanAttrTruncated = "mcp.output.truncated"
+
+	// SpanAttrResponseBytes is the size of a tool response in bytes.
+	SpanAttrResponseBytes = "mcp.response.bytes"
 )
 
 // SpanAttributeBuilder helps construct OpenTelemetry span attributes
@@ -212,6 +225,17 @@ func StartK8sSpan(ctx context.Context, operation, resourceType, namespace string
 	)
 }
 
+// StartOutputSpan starts a span for a stage of a tool's output pipeline,
+// such as processing (slim output, secret masking, truncation) or marshaling,
+// so that time spent there can be told apart from Kubernetes API calls.
+func StartOutputSpan(ctx context.Context, stage string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := otel.GetTracerProvider().Tracer(TracerName)
+	return tracer.Start(ctx, "output."+stage,
+		trace.WithAttributes(attrs...),
+		trace.WithSpanKind(trace.SpanKindInternal),
+	)
+}
+
 // SetSpanError records an error on the span and sets the status to error.
 func SetSpanError(span trace.Span, err error) {
 	if err != nil {
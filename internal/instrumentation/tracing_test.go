@@ -399,6 +399,19 @@ func TestStartK8sSpan_EmptyOptionalFields(t *testing.T) {
 	}
 }
 
+func TestStartOutputSpan(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := StartOutputSpan(ctx, "marshal")
+	defer span.End()
+
+	if spanCtx == nil {
+		t.Error("Context should not be nil")
+	}
+	if span == nil {
+		t.Error("Span should not be nil")
+	}
+}
+
 func TestSetSpanError(t *testing.T) {
 	ctx, span, _ := createTestSpanContext()
 	defer span.End()
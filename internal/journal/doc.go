@@ -0,0 +1,19 @@
+// Package journal records the prior state of objects changed through the
+// MCP server, so that an agent's recent changes can be undone.
+//
+// Before a tool changes an object, the state it replaces is read and, once
+// the change succeeded, recorded as an Entry in the caller's journal. Each
+// journal keeps the most recent entries of one caller's MCP session, up to
+// a size limit, and expires when the session has made no changes for the
+// journal TTL. Undoing an entry restores the recorded state: an object that
+// did not exist is deleted, any other is put back as it was.
+//
+// Journals are held by a Backend:
+//
+//   - MemoryBackend keeps journals in process; a change can only be undone
+//     on the replica that made it.
+//   - ValkeyBackend keeps journals in Valkey and shares them across
+//     replicas.
+//
+// Entries hold whole objects, so Secrets are never recorded.
+package journal
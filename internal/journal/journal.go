@@ -0,0 +1,169 @@
+package journal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Journal defaults, applied when New is given zero values.
+const (
+	DefaultSize = 20
+	DefaultTTL  = 24 * time.Hour
+)
+
+// keySegment namespaces journals under the key prefix.
+const keySegment = "journal:"
+
+// Backend stores journals as lists of encoded entries, newest first.
+type Backend interface {
+	// Push adds value to the front of the list under key, keeps its first
+	// size values and expires the list after ttl.
+	Push(ctx context.Context, key, value string, size int, ttl time.Duration) error
+
+	// Range returns the first n values of the list under key.
+	Range(ctx context.Context, key string, n int) ([]string, error)
+
+	// Remove removes value from the list under key. Removing a missing
+	// value is not an error.
+	Remove(ctx context.Context, key, value string) error
+
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// Entry is a change to one object and the state it replaced.
+type Entry struct {
+	// ID identifies the entry within its journal.
+	ID string `json:"id"`
+
+	// Tool is the tool that made the change.
+	Tool string `json:"tool"`
+
+	// Cluster is the federated cluster of the object; empty for the local
+	// cluster.
+	Cluster string `json:"cluster,omitempty"`
+
+	// KubeContext is the kubeconfig context of the object.
+	KubeContext string `json:"kubeContext,omitempty"`
+
+	// Namespace, ResourceType, APIGroup and Name identify the object.
+	Namespace    string `json:"namespace,omitempty"`
+	ResourceType string `json:"resourceType"`
+	APIGroup     string `json:"apiGroup,omitempty"`
+	Name         string `json:"name"`
+
+	// Before is the object before the change; nil when the change created
+	// it.
+	Before map[string]interface{} `json:"before,omitempty"`
+
+	// RecordedAt is when the change was made.
+	RecordedAt time.Time `json:"recordedAt"`
+
+	// raw is the encoded entry as stored, used to remove it.
+	raw string
+}
+
+// Object describes the object of e, as resourceType/name in namespace.
+func (e Entry) Object() string {
+	object := e.ResourceType + "/" + e.Name
+	if e.Namespace != "" {
+		object += " in namespace " + e.Namespace
+	}
+	return object
+}
+
+// Journal records changes per session.
+type Journal struct {
+	backend   Backend
+	keyPrefix string
+	size      int
+	ttl       time.Duration
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// New creates a journal keeping the last size changes of a session for ttl
+// after its last change. Keys are prefixed with keyPrefix.
+func New(backend Backend, keyPrefix string, size int, ttl time.Duration) *Journal {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Journal{
+		backend:   backend,
+		keyPrefix: keyPrefix,
+		size:      size,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+// Size returns how many changes a session's journal keeps.
+func (j *Journal) Size() int {
+	return j.size
+}
+
+// Record adds entry to the journal of session, setting its ID and
+// RecordedAt.
+func (j *Journal) Record(ctx context.Context, session string, entry Entry) error {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("generating journal entry ID: %w", err)
+	}
+	entry.ID = hex.EncodeToString(b)
+	entry.RecordedAt = j.now().UTC()
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding journal entry: %w", err)
+	}
+	if err := j.backend.Push(ctx, j.key(session), string(value), j.size, j.ttl); err != nil {
+		return fmt.Errorf("recording journal entry: %w", err)
+	}
+	return nil
+}
+
+// Recent returns the last n changes of session, newest first.
+func (j *Journal) Recent(ctx context.Context, session string, n int) ([]Entry, error) {
+	values, err := j.backend.Range(ctx, j.key(session), n)
+	if err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		var entry Entry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			return nil, fmt.Errorf("decoding journal entry: %w", err)
+		}
+		entry.raw = value
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Remove removes an entry returned by Recent from the journal of session.
+func (j *Journal) Remove(ctx context.Context, session string, entry Entry) error {
+	if entry.raw == "" {
+		return fmt.Errorf("journal entry %s was not read from the journal", entry.ID)
+	}
+	if err := j.backend.Remove(ctx, j.key(session), entry.raw); err != nil {
+		return fmt.Errorf("removing journal entry: %w", err)
+	}
+	return nil
+}
+
+// Close releases the backend.
+func (j *Journal) Close() error {
+	return j.backend.Close()
+}
+
+func (j *Journal) key(session string) string {
+	return j.keyPrefix + keySegment + session
+}
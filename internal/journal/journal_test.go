@@ -0,0 +1,83 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal_RecordRecentRemove(t *testing.T) {
+	ctx := context.Background()
+	j := New(NewMemoryBackend(), "mcp:", 2, time.Hour)
+
+	before := map[string]interface{}{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": "app"}}
+	require.NoError(t, j.Record(ctx, "user:jane", Entry{Tool: "patch", ResourceType: "configmaps", Namespace: "prod", Name: "app", Before: before}))
+	require.NoError(t, j.Record(ctx, "user:jane", Entry{Tool: "create", ResourceType: "Deployment", APIGroup: "apps", Namespace: "prod", Name: "web"}))
+	require.NoError(t, j.Record(ctx, "user:jane", Entry{Tool: "delete", ResourceType: "services", Namespace: "prod", Name: "web", Before: before}))
+	require.NoError(t, j.Record(ctx, "user:john", Entry{Tool: "create", ResourceType: "pods", Name: "other"}))
+
+	entries, err := j.Recent(ctx, "user:jane", 5)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "journals keep the last size changes")
+	assert.Equal(t, "delete", entries[0].Tool, "newest first")
+	assert.Equal(t, "create", entries[1].Tool)
+	assert.Nil(t, entries[1].Before)
+	assert.Equal(t, before, entries[0].Before)
+	assert.NotEmpty(t, entries[0].ID)
+	assert.False(t, entries[0].RecordedAt.IsZero())
+	assert.Equal(t, "services/web in namespace prod", entries[0].Object())
+
+	require.NoError(t, j.Remove(ctx, "user:jane", entries[0]))
+	entries, err = j.Recent(ctx, "user:jane", 5)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "create", entries[0].Tool)
+
+	assert.Error(t, j.Remove(ctx, "user:jane", Entry{ID: "x"}), "only entries read from the journal can be removed")
+}
+
+func TestMemoryBackend_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	b := NewMemoryBackend()
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.Push(ctx, "a", "1", 10, time.Minute))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, b.Push(ctx, "a", "2", 10, time.Minute))
+
+	// Every change extends the journal's TTL.
+	now = now.Add(45 * time.Second)
+	values, err := b.Range(ctx, "a", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "1"}, values)
+
+	now = now.Add(time.Minute)
+	values, err = b.Range(ctx, "a", 10)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestMemoryBackend_MaxJournals(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	b := NewMemoryBackend()
+	b.now = func() time.Time { return now }
+	b.maxJournals = 2
+
+	require.NoError(t, b.Push(ctx, "a", "1", 10, time.Minute))
+	now = now.Add(time.Second)
+	require.NoError(t, b.Push(ctx, "b", "1", 10, time.Minute))
+	now = now.Add(time.Second)
+	require.NoError(t, b.Push(ctx, "c", "1", 10, time.Minute))
+
+	values, err := b.Range(ctx, "a", 10)
+	require.NoError(t, err)
+	assert.Empty(t, values, "the journal closest to expiry is dropped")
+	values, err = b.Range(ctx, "c", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, values)
+}
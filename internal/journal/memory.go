@@ -0,0 +1,121 @@
+package journal
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultMaxJournals caps the number of journals a MemoryBackend keeps.
+const DefaultMaxJournals = 10000
+
+// memoryList is a journal and when it expires.
+type memoryList struct {
+	values  []string
+	expires time.Time
+}
+
+// MemoryBackend keeps journals in process memory.
+type MemoryBackend struct {
+	mu          sync.Mutex
+	lists       map[string]*memoryList
+	maxJournals int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// NewMemoryBackend creates an in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		lists:       make(map[string]*memoryList),
+		maxJournals: DefaultMaxJournals,
+		now:         time.Now,
+	}
+}
+
+// Push implements Backend. When the backend is full, the journal closest
+// to expiry is dropped to make room.
+func (b *MemoryBackend) Push(_ context.Context, key, value string, size int, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	list := b.liveLocked(key, now)
+	if list == nil {
+		b.evictLocked(now)
+		list = &memoryList{}
+		b.lists[key] = list
+	}
+	list.values = append([]string{value}, list.values...)
+	if len(list.values) > size {
+		list.values = list.values[:size]
+	}
+	list.expires = now.Add(ttl)
+	return nil
+}
+
+// Range implements Backend.
+func (b *MemoryBackend) Range(_ context.Context, key string, n int) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list := b.liveLocked(key, b.now())
+	if list == nil || n <= 0 {
+		return nil, nil
+	}
+	return slices.Clone(list.values[:min(n, len(list.values))]), nil
+}
+
+// Remove implements Backend.
+func (b *MemoryBackend) Remove(_ context.Context, key, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if list := b.liveLocked(key, b.now()); list != nil {
+		list.values = slices.DeleteFunc(list.values, func(v string) bool { return v == value })
+	}
+	return nil
+}
+
+// Close implements Backend.
+func (b *MemoryBackend) Close() error {
+	return nil
+}
+
+// liveLocked returns the unexpired journal under key, dropping it if it has
+// expired.
+func (b *MemoryBackend) liveLocked(key string, now time.Time) *memoryList {
+	list, ok := b.lists[key]
+	if !ok {
+		return nil
+	}
+	if !now.Before(list.expires) {
+		delete(b.lists, key)
+		return nil
+	}
+	return list
+}
+
+// evictLocked drops expired journals and, if the backend is still full,
+// the one closest to expiry.
+func (b *MemoryBackend) evictLocked(now time.Time) {
+	if len(b.lists) < b.maxJournals {
+		return
+	}
+	var oldestKey string
+	var oldest time.Time
+	for key, list := range b.lists {
+		if !now.Before(list.expires) {
+			delete(b.lists, key)
+			continue
+		}
+		if oldestKey == "" || list.expires.Before(oldest) {
+			oldestKey, oldest = key, list.expires
+		}
+	}
+	if len(b.lists) >= b.maxJournals {
+		delete(b.lists, oldestKey)
+	}
+}
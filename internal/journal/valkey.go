@@ -0,0 +1,60 @@
+package journal
+
+import (
+	"context"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// ValkeyBackend keeps journals in Valkey lists, so a change can be undone
+// on any replica.
+type ValkeyBackend struct {
+	client valkey.Client
+}
+
+// NewValkeyBackend creates a backend on client.
+func NewValkeyBackend(client valkey.Client) *ValkeyBackend {
+	return &ValkeyBackend{client: client}
+}
+
+// Push implements Backend. The list is pushed, trimmed and given its TTL in
+// one transaction.
+func (b *ValkeyBackend) Push(ctx context.Context, key, value string, size int, ttl time.Duration) error {
+	cmds := valkey.Commands{
+		b.client.B().Multi().Build(),
+		b.client.B().Lpush().Key(key).Element(value).Build(),
+		b.client.B().Ltrim().Key(key).Start(0).Stop(int64(size - 1)).Build(),
+		b.client.B().Pexpire().Key(key).Milliseconds(ttl.Milliseconds()).Build(),
+		b.client.B().Exec().Build(),
+	}
+	for _, resp := range b.client.DoMulti(ctx, cmds...) {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Range implements Backend.
+func (b *ValkeyBackend) Range(ctx context.Context, key string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	values, err := b.client.Do(ctx, b.client.B().Lrange().Key(key).Start(0).Stop(int64(n-1)).Build()).AsStrSlice()
+	if valkey.IsValkeyNil(err) {
+		return nil, nil
+	}
+	return values, err
+}
+
+// Remove implements Backend.
+func (b *ValkeyBackend) Remove(ctx context.Context, key, value string) error {
+	return b.client.Do(ctx, b.client.B().Lrem().Key(key).Count(0).Element(value).Build()).Error()
+}
+
+// Close closes the Valkey connection.
+func (b *ValkeyBackend) Close() error {
+	b.client.Close()
+	return nil
+}
@@ -7,12 +7,17 @@ import (
 	"os"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+
+	"golang.org/x/oauth2"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/logging"
 )
@@ -24,6 +29,14 @@ type bearerTokenClient struct {
 	// Bearer token for authentication
 	bearerToken string
 
+	// tokenSource, when set, replaces bearerToken: it is asked for the
+	// token before each request, so the client survives token refreshes.
+	tokenSource oauth2.TokenSource
+
+	// discoveryKey keys the client's discovery results: the key of the
+	// token source, which names the user, or the bearer token.
+	discoveryKey string
+
 	// In-cluster configuration (host, CA cert)
 	clusterHost string
 	caCertFile  string
@@ -43,6 +56,9 @@ type bearerTokenClient struct {
 	qpsLimit             float32
 	burstLimit           int
 	timeout              time.Duration
+	retry                RetryConfig
+	userRateLimiter      *UserRateLimiter
+	protobuf             bool
 
 	// Debug settings
 	debugMode bool
@@ -64,6 +80,9 @@ type BearerTokenClientFactory struct {
 	qpsLimit             float32
 	burstLimit           int
 	timeout              time.Duration
+	retry                RetryConfig
+	userRateLimiter      *UserRateLimiter
+	protobuf             bool
 	debugMode            bool
 	logger               Logger
 
@@ -115,6 +134,9 @@ func NewBearerTokenClientFactory(config *ClientConfig) (*BearerTokenClientFactor
 		qpsLimit:             qpsLimit,
 		burstLimit:           burstLimit,
 		timeout:              timeout,
+		retry:                config.Retry,
+		userRateLimiter:      config.UserRateLimiter,
+		protobuf:             config.Protobuf,
 		debugMode:            config.DebugMode,
 		logger:               config.Logger,
 		cache:                newClientCacheWithConfig(cacheConfig),
@@ -147,9 +169,61 @@ func (f *BearerTokenClientFactory) CreateBearerTokenClient(bearerToken string) (
 		}
 	}
 
-	// Create new client
-	client := &bearerTokenClient{
-		bearerToken:          bearerToken,
+	client := f.newClient()
+	client.bearerToken = bearerToken
+	client.discoveryKey = tokenDiscoveryKey("host:"+client.clusterHost, bearerToken)
+
+	// Cache the new client
+	if f.cache != nil {
+		f.cache.Set(bearerToken, client)
+		if f.logger != nil {
+			f.logger.Debug("created and cached new bearer token client")
+		}
+	}
+
+	return client, nil
+}
+
+// CreateTokenSourceClient creates a Kubernetes client that takes its bearer
+// token from source before each request, checking the token's lifetime each
+// time, so a client held across a token refresh keeps working. Clients are
+// cached by key, which identifies the token the source yields.
+func (f *BearerTokenClientFactory) CreateTokenSourceClient(key string, source oauth2.TokenSource) (Client, error) {
+	if key == "" || source == nil {
+		return nil, fmt.Errorf("token source and key are required")
+	}
+
+	cacheKey := tokenSourceCachePrefix + key
+	if f.cache != nil {
+		if cachedClient := f.cache.Get(cacheKey); cachedClient != nil {
+			if f.logger != nil {
+				f.logger.Debug("using cached token source client")
+			}
+			return cachedClient, nil
+		}
+	}
+
+	client := f.newClient()
+	client.tokenSource = source
+	client.discoveryKey = userDiscoveryKey("host:"+client.clusterHost, key, nil)
+
+	if f.cache != nil {
+		f.cache.Set(cacheKey, client)
+		if f.logger != nil {
+			f.logger.Debug("created and cached new token source client")
+		}
+	}
+
+	return client, nil
+}
+
+// tokenSourceCachePrefix keeps token source clients apart from bearer token
+// clients in the shared cache.
+const tokenSourceCachePrefix = "source:"
+
+// newClient creates a client with the factory's settings and no credentials.
+func (f *BearerTokenClientFactory) newClient() *bearerTokenClient {
+	return &bearerTokenClient{
 		clusterHost:          f.clusterHost,
 		caCertFile:           f.caCertFile,
 		nonDestructiveMode:   f.nonDestructiveMode,
@@ -159,26 +233,19 @@ func (f *BearerTokenClientFactory) CreateBearerTokenClient(bearerToken string) (
 		qpsLimit:             f.qpsLimit,
 		burstLimit:           f.burstLimit,
 		timeout:              f.timeout,
+		retry:                f.retry,
+		userRateLimiter:      f.userRateLimiter,
+		protobuf:             f.protobuf,
 		debugMode:            f.debugMode,
 		logger:               f.logger,
 	}
-
-	// Cache the new client
-	if f.cache != nil {
-		f.cache.Set(bearerToken, client)
-		if f.logger != nil {
-			f.logger.Debug("created and cached new bearer token client")
-		}
-	}
-
-	return client, nil
 }
 
 // getRestConfig returns the REST config with bearer token authentication.
 // Uses lazyValue for thread-safe lazy initialization with double-check locking.
 func (c *bearerTokenClient) getRestConfig() (*rest.Config, error) {
 	return c.restConfigLazy.Get(func() (*rest.Config, error) {
-		return &rest.Config{
+		config := &rest.Config{
 			Host:        c.clusterHost,
 			BearerToken: c.bearerToken,
 			TLSClientConfig: rest.TLSClientConfig{
@@ -187,7 +254,12 @@ func (c *bearerTokenClient) getRestConfig() (*rest.Config, error) {
 			QPS:     c.qpsLimit,
 			Burst:   c.burstLimit,
 			Timeout: c.timeout,
-		}, nil
+		}
+		if c.tokenSource != nil {
+			config.WrapTransport = transport.TokenSourceWrapTransport(c.tokenSource)
+		}
+		c.userRateLimiter.ApplyTo(config)
+		return config, nil
 	})
 }
 
@@ -219,6 +291,13 @@ func (c *bearerTokenClient) getDynamicClient() (dynamic.Interface, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 		}
+		if c.protobuf {
+			clientset, err := newProtobufClientset(config)
+			if err != nil {
+				return nil, err
+			}
+			return withProtobuf(dynamicClient, clientset), nil
+		}
 		return dynamicClient, nil
 	})
 }
@@ -235,7 +314,7 @@ func (c *bearerTokenClient) getDiscoveryClient() (discovery.DiscoveryInterface,
 		if err != nil {
 			return nil, fmt.Errorf("failed to create discovery client: %w", err)
 		}
-		return discoveryClient, nil
+		return newThrottledDiscoveryClient(c.discoveryKey, discoveryClient), nil
 	})
 }
 
@@ -255,7 +334,7 @@ func (c *bearerTokenClient) isOperationAllowed(operation string) error {
 	}
 
 	if c.nonDestructiveMode {
-		destructiveOps := []string{"delete", "patch", "scale", "create", "apply"}
+		destructiveOps := []string{"delete", "patch", "scale", "create", "apply", "evict"}
 		for _, destructiveOp := range destructiveOps {
 			if destructiveOp == operation {
 				if !c.dryRun {
@@ -370,7 +449,9 @@ func (c *bearerTokenClient) Get(ctx context.Context, kubeContext, namespace, res
 		return nil, err
 	}
 
-	return getResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name)
+	return retryRead(ctx, c.retry, "get", func() (*GetResponse, error) {
+		return getResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name)
+	})
 }
 
 // List retrieves resources with pagination support.
@@ -398,7 +479,9 @@ func (c *bearerTokenClient) List(ctx context.Context, kubeContext, namespace, re
 	}
 	c.debugLog("acquired discovery client", "elapsed", time.Since(listStart))
 
-	result, err := listResources(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, opts)
+	result, err := retryRead(ctx, c.retry, "list", func() (*PaginatedListResponse, error) {
+		return listResources(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, opts)
+	})
 	if err != nil {
 		c.debugLog("list operation failed", "elapsed", time.Since(listStart), "error", logging.SanitizeHost(err.Error()))
 		return nil, err
@@ -430,7 +513,9 @@ func (c *bearerTokenClient) Describe(ctx context.Context, kubeContext, namespace
 		return nil, err
 	}
 
-	return describeResource(ctx, dynamicClient, discoveryClient, clientset, namespace, resourceType, apiGroup, name)
+	return retryRead(ctx, c.retry, "describe", func() (*ResourceDescription, error) {
+		return describeResource(ctx, dynamicClient, discoveryClient, clientset, namespace, resourceType, apiGroup, name)
+	})
 }
 
 // Create creates a new resource.
@@ -455,7 +540,7 @@ func (c *bearerTokenClient) Create(ctx context.Context, kubeContext, namespace s
 		return nil, err
 	}
 
-	return createResource(ctx, dynamicClient, discoveryClient, namespace, obj, c.dryRun)
+	return createResource(ctx, dynamicClient, discoveryClient, namespace, obj, isDryRun(ctx, c.dryRun))
 }
 
 // Apply applies a resource configuration.
@@ -480,7 +565,7 @@ func (c *bearerTokenClient) Apply(ctx context.Context, kubeContext, namespace st
 		return nil, err
 	}
 
-	return applyResource(ctx, dynamicClient, discoveryClient, namespace, obj, c.dryRun)
+	return applyResource(ctx, dynamicClient, discoveryClient, namespace, obj, isDryRun(ctx, c.dryRun))
 }
 
 // Delete removes a resource.
@@ -507,7 +592,7 @@ func (c *bearerTokenClient) Delete(ctx context.Context, kubeContext, namespace,
 		return nil, err
 	}
 
-	return deleteResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, c.dryRun)
+	return deleteResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, isDryRun(ctx, c.dryRun))
 }
 
 // Patch updates specific fields of a resource.
@@ -534,7 +619,7 @@ func (c *bearerTokenClient) Patch(ctx context.Context, kubeContext, namespace, r
 		return nil, err
 	}
 
-	return patchResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, patchType, data, c.dryRun)
+	return patchResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, patchType, data, isDryRun(ctx, c.dryRun))
 }
 
 // Scale changes the number of replicas.
@@ -561,7 +646,51 @@ func (c *bearerTokenClient) Scale(ctx context.Context, kubeContext, namespace, r
 		return nil, err
 	}
 
-	return scaleResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, replicas, c.dryRun)
+	return scaleResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, replicas, isDryRun(ctx, c.dryRun))
+}
+
+// Watch streams changes to resources.
+func (c *bearerTokenClient) Watch(ctx context.Context, kubeContext, namespace, resourceType, apiGroup string, opts WatchOptions) (watch.Interface, error) {
+	c.logOperation("watch", kubeContext, namespace, resourceType, "")
+
+	if namespace != "" {
+		if err := c.isNamespaceRestricted(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	dynamicClient, err := c.getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := c.getDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return watchResources(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, opts)
+}
+
+// Validate performs a server-side dry-run create or update.
+func (c *bearerTokenClient) Validate(ctx context.Context, kubeContext, namespace string, obj runtime.Object, opts ValidateOptions) (*ValidateResponse, error) {
+	c.logOperation("validate", kubeContext, namespace, "", "")
+
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := c.getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := c.getDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return validateResource(ctx, dynamicClient, discoveryClient, namespace, obj, opts)
 }
 
 // ========== PodManager Implementation ==========
@@ -645,6 +774,46 @@ func (c *bearerTokenClient) PortForwardToService(ctx context.Context, kubeContex
 	return portForwardToService(ctx, clientset, config, namespace, serviceName, ports, opts)
 }
 
+// EvictPod evicts a pod through the Eviction API.
+func (c *bearerTokenClient) EvictPod(ctx context.Context, kubeContext, namespace, podName string, opts EvictOptions) error {
+	c.logOperation("evict", kubeContext, namespace, "pod", podName)
+
+	if err := c.isOperationAllowed("evict"); err != nil {
+		return err
+	}
+
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return err
+	}
+
+	clientset, err := c.getClientset()
+	if err != nil {
+		return err
+	}
+
+	return evictPod(ctx, clientset, namespace, podName, opts, isDryRun(ctx, c.dryRun))
+}
+
+// AddEphemeralContainer adds an ephemeral container to a running pod.
+func (c *bearerTokenClient) AddEphemeralContainer(ctx context.Context, kubeContext, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	c.logOperation("debug", kubeContext, namespace, "pod", podName)
+
+	if err := c.isOperationAllowed("debug"); err != nil {
+		return nil, err
+	}
+
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+
+	clientset, err := c.getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return addEphemeralContainer(ctx, clientset, namespace, podName, container, isDryRun(ctx, c.dryRun))
+}
+
 // ========== ClusterManager Implementation ==========
 
 // GetAPIResources returns available API resources.
@@ -656,7 +825,28 @@ func (c *bearerTokenClient) GetAPIResources(ctx context.Context, kubeContext str
 		return nil, err
 	}
 
-	return getAPIResources(ctx, discoveryClient, limit, offset, apiGroup, namespacedOnly, verbs)
+	return retryRead(ctx, c.retry, "api-resources", func() (*PaginatedAPIResourceResponse, error) {
+		return getAPIResources(ctx, discoveryClient, limit, offset, apiGroup, namespacedOnly, verbs)
+	})
+}
+
+// ProxyGetService sends a GET request to a service through the API server's
+// service proxy.
+func (c *bearerTokenClient) ProxyGetService(ctx context.Context, kubeContext, namespace string, req ServiceProxyRequest) (*ServiceProxyResponse, error) {
+	if err := c.isOperationAllowed("proxy"); err != nil {
+		return nil, err
+	}
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+
+	c.logOperation("proxy-get", kubeContext, namespace, "service", req.Service)
+
+	clientset, err := c.getClientset()
+	if err != nil {
+		return nil, err
+	}
+	return proxyGetService(ctx, clientset, namespace, req)
 }
 
 // GetClusterHealth returns the health status of the cluster.
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 )
 
 // TestBearerTokenClientFactory tests the creation of the bearer token client factory.
@@ -54,6 +55,44 @@ func TestBearerTokenClientFactory_CreateBearerTokenClient(t *testing.T) {
 	})
 }
 
+// TestBearerTokenClientFactory_CreateTokenSourceClient tests clients that take
+// their bearer token from a token source before each request.
+func TestBearerTokenClientFactory_CreateTokenSourceClient(t *testing.T) {
+	factory := &BearerTokenClientFactory{
+		clusterHost: "https://kubernetes.default.svc",
+		caCertFile:  "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+		qpsLimit:    20.0,
+		burstLimit:  30,
+		timeout:     30 * time.Second,
+		cache:       newClientCache(time.Minute),
+	}
+	t.Cleanup(factory.Close)
+
+	_, err := factory.CreateTokenSourceClient("", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "t"}))
+	assert.ErrorContains(t, err, "token source and key are required")
+
+	source := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+	client, err := factory.CreateTokenSourceClient("session", source)
+	require.NoError(t, err)
+	bearerClient, ok := client.(*bearerTokenClient)
+	require.True(t, ok)
+	assert.Empty(t, bearerClient.bearerToken)
+
+	config, err := bearerClient.getRestConfig()
+	require.NoError(t, err)
+	assert.Empty(t, config.BearerToken)
+	assert.NotNil(t, config.WrapTransport, "the token is injected per request")
+
+	again, err := factory.CreateTokenSourceClient("session", source)
+	require.NoError(t, err)
+	assert.Same(t, client, again)
+
+	// A bearer token equal to the key does not share the cached client.
+	other, err := factory.CreateBearerTokenClient("session")
+	require.NoError(t, err)
+	assert.NotSame(t, client, other)
+}
+
 // TestBearerTokenClient_ContextMethods tests the context management methods.
 func TestBearerTokenClient_ContextMethods(t *testing.T) {
 	client := &bearerTokenClient{
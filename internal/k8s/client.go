@@ -5,9 +5,11 @@ import (
 	"io"
 	"time"
 
+	"golang.org/x/oauth2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/portforward"
 )
 
@@ -38,6 +40,17 @@ type ClientFactory interface {
 	CreateBearerTokenClient(bearerToken string) (Client, error)
 }
 
+// TokenSourceClientFactory is implemented by client factories that can build
+// clients whose bearer token is fetched from a token source before each
+// request. Such clients keep working when the user's token is refreshed
+// while they are in use, e.g. by a long-running exec or watch.
+type TokenSourceClientFactory interface {
+	// CreateTokenSourceClient creates a client authenticating with tokens
+	// from source. key identifies the token the source yields and is used
+	// to reuse clients across requests.
+	CreateTokenSourceClient(key string, source oauth2.TokenSource) (Client, error)
+}
+
 // ContextManager handles Kubernetes context operations.
 type ContextManager interface {
 	// ListContexts returns all available Kubernetes contexts.
@@ -75,6 +88,16 @@ type ResourceManager interface {
 
 	// Scale changes the number of replicas for scalable resources.
 	Scale(ctx context.Context, kubeContext, namespace, resourceType, apiGroup, name string, replicas int32) (*ScaleResponse, error)
+
+	// Watch streams changes to resources, starting after opts.ResourceVersion.
+	// The caller must stop the returned watch.
+	Watch(ctx context.Context, kubeContext, namespace, resourceType, apiGroup string, opts WatchOptions) (watch.Interface, error)
+
+	// Validate sends obj to the API server as a dry-run create, or update if
+	// it exists, so admission and schema validation run without persisting
+	// anything. The response is returned together with any error so the
+	// warnings received for a rejected object are not lost.
+	Validate(ctx context.Context, kubeContext, namespace string, obj runtime.Object, opts ValidateOptions) (*ValidateResponse, error)
 }
 
 // PodManager handles pod-specific operations.
@@ -90,6 +113,16 @@ type PodManager interface {
 
 	// PortForwardToService sets up port forwarding to the first available pod behind a service.
 	PortForwardToService(ctx context.Context, kubeContext, namespace, serviceName string, ports []string, opts PortForwardOptions) (*PortForwardSession, error)
+
+	// EvictPod evicts a pod through the Eviction API, which honours
+	// PodDisruptionBudgets. An eviction refused by a budget fails with a
+	// TooManyRequests API error.
+	EvictPod(ctx context.Context, kubeContext, namespace, podName string, opts EvictOptions) error
+
+	// AddEphemeralContainer adds an ephemeral container to a running pod
+	// through the ephemeralcontainers subresource and returns the updated
+	// pod.
+	AddEphemeralContainer(ctx context.Context, kubeContext, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error)
 }
 
 // ClusterManager handles cluster-level operations.
@@ -99,6 +132,11 @@ type ClusterManager interface {
 
 	// GetClusterHealth returns the health status of the cluster.
 	GetClusterHealth(ctx context.Context, kubeContext string) (*ClusterHealth, error)
+
+	// ProxyGetService sends a GET request to a service through the API
+	// server's service proxy. Non-2xx responses from the service or the
+	// proxy are returned as a response, not an error.
+	ProxyGetService(ctx context.Context, kubeContext, namespace string, req ServiceProxyRequest) (*ServiceProxyResponse, error)
 }
 
 // ContextInfo represents information about a Kubernetes context.
@@ -121,6 +159,41 @@ type ListOptions struct {
 	Continue string `json:"continue,omitempty"` // Continue token from previous request
 }
 
+// WatchOptions configures watch operations.
+type WatchOptions struct {
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// ResourceVersion is typically the resource version of a preceding list.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// Field validation levels for ValidateOptions, as accepted by the API server.
+const (
+	FieldValidationStrict = "Strict"
+	FieldValidationWarn   = "Warn"
+	FieldValidationIgnore = "Ignore"
+)
+
+// ValidateOptions configures validate operations.
+type ValidateOptions struct {
+	// FieldValidation decides how unknown and duplicate fields are reported:
+	// Strict rejects the object, Warn returns warnings and Ignore drops them.
+	// Empty means Strict.
+	FieldValidation string `json:"fieldValidation,omitempty"`
+}
+
+// ValidateResponse contains the result of a dry-run validation.
+type ValidateResponse struct {
+	// Operation is "create" or "update", depending on whether the object exists.
+	Operation string `json:"operation"`
+	// Resource is the object as the API server would have stored it.
+	Resource runtime.Object `json:"resource,omitempty"`
+	// Warnings are the API warnings received, such as deprecation notices
+	// and admission webhook warnings.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 // PaginatedListResponse contains a paginated list of resources with metadata
 type PaginatedListResponse struct {
 	Items           []runtime.Object `json:"items"`
@@ -140,6 +213,11 @@ type ResponseMeta struct {
 	RequestedNamespace string `json:"requestedNamespace,omitempty"` // Namespace provided in request
 	EffectiveNamespace string `json:"effectiveNamespace,omitempty"` // Namespace actually used (empty for cluster-scoped)
 	Hint               string `json:"hint,omitempty"`               // Helpful message for agents
+	DegradedDiscovery  bool   `json:"degradedDiscovery,omitempty"`  // Resource type resolved from the built-in table because API discovery was unavailable
+	DryRun             bool   `json:"dryRun,omitempty"`             // The change was a server-side dry-run and was not persisted
+
+	// ListCache is set when a list was served from the list cache.
+	ListCache *ListCacheMeta `json:"listCache,omitempty"`
 }
 
 // BuildResponseMeta creates metadata for resource operations to provide transparency
@@ -211,6 +289,10 @@ type ExecOptions struct {
 	Stdout io.Writer `json:"-"`
 	Stderr io.Writer `json:"-"`
 	TTY    bool      `json:"tty,omitempty"`
+
+	// Attach connects the streams to the container's running process, like
+	// kubectl attach, instead of starting the command, which must be empty.
+	Attach bool `json:"attach,omitempty"`
 }
 
 // ExecResult contains the result of command execution.
@@ -220,6 +302,12 @@ type ExecResult struct {
 	Stderr   string `json:"stderr,omitempty"`
 }
 
+// EvictOptions configures pod eviction.
+type EvictOptions struct {
+	// GracePeriodSeconds overrides the pod's termination grace period.
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+}
+
 // PortForwardOptions configures port forwarding.
 type PortForwardOptions struct {
 	Stdout io.Writer `json:"-"`
@@ -257,7 +345,10 @@ type PaginatedAPIResourceResponse struct {
 
 // ClusterHealth represents the health status of a Kubernetes cluster.
 type ClusterHealth struct {
-	Status     string            `json:"status"`
+	Status string `json:"status"`
+	// Version is the API server version, e.g. v1.29.3. It is empty when the
+	// API server cannot be reached.
+	Version    string            `json:"version,omitempty"`
 	Components []ComponentHealth `json:"components"`
 	Nodes      []NodeHealth      `json:"nodes"`
 }
@@ -269,6 +360,31 @@ type ComponentHealth struct {
 	Message string `json:"message,omitempty"`
 }
 
+// ServiceProxyRequest identifies the service endpoint for ProxyGetService.
+type ServiceProxyRequest struct {
+	// Scheme is "http" or "https"; empty means http.
+	Scheme string
+
+	// Service is the service name.
+	Service string
+
+	// Port is the service port name or number; empty uses the first port.
+	Port string
+
+	// Path is the request path on the service.
+	Path string
+
+	// MaxBytes caps the returned body; zero means no limit.
+	MaxBytes int
+}
+
+// ServiceProxyResponse is the result of a service proxy request.
+type ServiceProxyResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
 // NodeHealth represents the health of a cluster node.
 type NodeHealth struct {
 	Name       string                 `json:"name"`
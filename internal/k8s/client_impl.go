@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -35,6 +36,10 @@ type kubernetesClient struct {
 	kubeconfigData *clientcmdapi.Config
 	currentContext string
 
+	// allowedContexts restricts the usable kubeconfig contexts. Nil allows
+	// every context in the kubeconfig.
+	allowedContexts map[string]struct{}
+
 	// Resource scope cache - caches whether resources are namespaced or cluster-scoped
 	// Key format: "context:group/resource" or "context:resource" for core resources
 	resourceScopeCache map[string]bool // true = namespaced, false = cluster-scoped
@@ -57,6 +62,11 @@ type ClientConfig struct {
 	KubeconfigPath string
 	Context        string
 
+	// AllowedContexts restricts the client to these kubeconfig contexts,
+	// giving multi-cluster access from a single kubeconfig. Each must exist
+	// in the kubeconfig. Empty allows every context. Ignored in-cluster.
+	AllowedContexts []string
+
 	// Authentication mode
 	InCluster bool // Use in-cluster service account authentication instead of kubeconfig
 
@@ -76,6 +86,28 @@ type ClientConfig struct {
 	CacheMaxEntries int                  // Max entries before LRU eviction. Defaults to 100.
 	CacheMetrics    CacheMetricsCallback // Optional metrics callback for cache observability.
 
+	// Retry configures retries of reads that fail with a transient error.
+	Retry RetryConfig
+
+	// ExecPlugins bounds the exec credential plugins of kubeconfig users.
+	ExecPlugins ExecPluginConfig
+
+	// UserRateLimiter gives each user their own share of the QPS budget.
+	// Optional.
+	UserRateLimiter *UserRateLimiter
+
+	// ImpersonationExtras adds configured and per-request Impersonate-Extra-*
+	// headers to impersonating clients. Optional.
+	ImpersonationExtras *ImpersonationExtras
+
+	// ListCache serves lists of hot resource types from informers.
+	// Optional; only used by the client created by NewClient.
+	ListCache *ListCache
+
+	// Protobuf reads pods, nodes and events with the typed clientset over
+	// protobuf instead of JSON.
+	Protobuf bool
+
 	// Debug settings
 	DebugMode bool
 
@@ -146,6 +178,10 @@ func NewClient(config *ClientConfig) (*kubernetesClient, error) {
 			client.currentContext = client.kubeconfigData.CurrentContext
 		}
 
+		if err := client.setAllowedContexts(config.AllowedContexts, config.Context != ""); err != nil {
+			return nil, err
+		}
+
 		// Validate current context exists
 		if _, exists := client.kubeconfigData.Contexts[client.currentContext]; !exists && client.currentContext != "" {
 			return nil, fmt.Errorf("context %q does not exist in kubeconfig", client.currentContext)
@@ -159,6 +195,48 @@ func NewClient(config *ClientConfig) (*kubernetesClient, error) {
 	return client, nil
 }
 
+// ErrContextNotAllowed is returned for kubeconfig contexts outside
+// ClientConfig.AllowedContexts.
+var ErrContextNotAllowed = errors.New("kubeconfig context is not allowed")
+
+// setAllowedContexts restricts the client to contexts. When the kubeconfig's
+// current context is not among them, the first allowed context becomes the
+// current one; an explicitly configured context must be allowed.
+func (c *kubernetesClient) setAllowedContexts(contexts []string, explicitContext bool) error {
+	if len(contexts) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]struct{}, len(contexts))
+	for _, name := range contexts {
+		if _, exists := c.kubeconfigData.Contexts[name]; !exists {
+			return fmt.Errorf("allowed context %q does not exist in kubeconfig", name)
+		}
+		allowed[name] = struct{}{}
+	}
+	c.allowedContexts = allowed
+
+	if _, ok := allowed[c.currentContext]; !ok {
+		if explicitContext {
+			return fmt.Errorf("context %q: %w", c.currentContext, ErrContextNotAllowed)
+		}
+		c.currentContext = contexts[0]
+	}
+	return nil
+}
+
+// checkContextAllowed returns ErrContextNotAllowed if contextName is outside
+// the allowed contexts.
+func (c *kubernetesClient) checkContextAllowed(contextName string) error {
+	if c.allowedContexts == nil {
+		return nil
+	}
+	if _, ok := c.allowedContexts[contextName]; !ok {
+		return fmt.Errorf("context %q: %w", contextName, ErrContextNotAllowed)
+	}
+	return nil
+}
+
 // validateInClusterEnvironment checks if the required in-cluster authentication files are present.
 func (c *kubernetesClient) validateInClusterEnvironment() error {
 	// Check if service account token file exists
@@ -226,6 +304,10 @@ func (c *kubernetesClient) getRestConfig(contextName string) (*rest.Config, erro
 		c.config.Logger.Debug("getRestConfig: starting", "contextName", contextName)
 	}
 
+	if err := c.checkContextAllowed(contextName); err != nil {
+		return nil, err
+	}
+
 	c.mu.RLock()
 	if restConfig, exists := c.restConfigs[contextName]; exists {
 		c.mu.RUnlock()
@@ -305,6 +387,9 @@ func (c *kubernetesClient) getRestConfig(contextName string) (*rest.Config, erro
 			}
 			return nil, fmt.Errorf("failed to create rest config for context %q: %w", contextName, err)
 		}
+		if err := c.config.ExecPlugins.ApplyTo(restConfig); err != nil {
+			return nil, fmt.Errorf("context %q: %w", contextName, err)
+		}
 
 		if c.config.DebugMode && c.config.Logger != nil {
 			c.config.Logger.Debug("getRestConfig: got REST config", "host", restConfig.Host, "serverName", restConfig.ServerName)
@@ -319,6 +404,8 @@ func (c *kubernetesClient) getRestConfig(contextName string) (*rest.Config, erro
 	restConfig.QPS = c.qpsLimit
 	restConfig.Burst = c.burstLimit
 	restConfig.Timeout = c.timeout
+	c.config.UserRateLimiter.ApplyTo(restConfig)
+	c.config.ImpersonationExtras.ApplyTo(restConfig)
 
 	if c.config.DebugMode && c.config.Logger != nil {
 		c.config.Logger.Debug("getRestConfig: caching config", "contextName", contextName)
@@ -342,6 +429,10 @@ func (c *kubernetesClient) getRestConfigLocked(contextName string) (*rest.Config
 		contextName = c.currentContext
 	}
 
+	if err := c.checkContextAllowed(contextName); err != nil {
+		return nil, err
+	}
+
 	// Check cache first (caller must hold write lock)
 	if restConfig, exists := c.restConfigs[contextName]; exists {
 		return restConfig, nil
@@ -374,12 +465,17 @@ func (c *kubernetesClient) getRestConfigLocked(contextName string) (*rest.Config
 		if err != nil {
 			return nil, fmt.Errorf("failed to create rest config for context %q: %w", contextName, err)
 		}
+		if err := c.config.ExecPlugins.ApplyTo(restConfig); err != nil {
+			return nil, fmt.Errorf("context %q: %w", contextName, err)
+		}
 	}
 
 	// Apply performance settings
 	restConfig.QPS = c.qpsLimit
 	restConfig.Burst = c.burstLimit
 	restConfig.Timeout = c.timeout
+	c.config.UserRateLimiter.ApplyTo(restConfig)
+	c.config.ImpersonationExtras.ApplyTo(restConfig)
 
 	// Cache the config (caller must hold write lock)
 	c.restConfigs[contextName] = restConfig
@@ -387,6 +483,13 @@ func (c *kubernetesClient) getRestConfigLocked(contextName string) (*rest.Config
 	return restConfig, nil
 }
 
+// Clientset returns the typed clientset for the current context. It serves
+// components outside the tool surface, such as HTTP bearer token
+// authentication via TokenReview.
+func (c *kubernetesClient) Clientset() (kubernetes.Interface, error) {
+	return c.getClientset("")
+}
+
 // getClientset returns a Kubernetes clientset for the specified context.
 func (c *kubernetesClient) getClientset(contextName string) (kubernetes.Interface, error) {
 	// Use current context if none specified
@@ -515,13 +618,21 @@ func (c *kubernetesClient) getDynamicClient(contextName string) (dynamic.Interfa
 		c.config.Logger.Debug("getDynamicClient: creating dynamic client from REST config")
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	var dynamicClient dynamic.Interface
+	dynamicClient, err = dynamic.NewForConfig(restConfig)
 	if err != nil {
 		if c.config.DebugMode && c.config.Logger != nil {
 			c.config.Logger.Error("getDynamicClient: failed to create dynamic client", "error", err)
 		}
 		return nil, fmt.Errorf("failed to create dynamic client for context %q: %w", contextName, err)
 	}
+	if c.config.Protobuf {
+		clientset, err := newProtobufClientset(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dynamic client for context %q: %w", contextName, err)
+		}
+		dynamicClient = withProtobuf(dynamicClient, clientset)
+	}
 
 	if c.config.DebugMode && c.config.Logger != nil {
 		c.config.Logger.Debug("getDynamicClient: caching dynamic client", "contextName", contextName)
@@ -590,13 +701,14 @@ func (c *kubernetesClient) getDiscoveryClient(contextName string) (discovery.Dis
 		c.config.Logger.Debug("getDiscoveryClient: creating discovery client from REST config")
 	}
 
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
 		if c.config.DebugMode && c.config.Logger != nil {
 			c.config.Logger.Error("getDiscoveryClient: failed to create discovery client", "error", err)
 		}
 		return nil, fmt.Errorf("failed to create discovery client for context %q: %w", contextName, err)
 	}
+	discoveryClient := newThrottledDiscoveryClient("context:"+contextName, dc)
 
 	if c.config.DebugMode && c.config.Logger != nil {
 		c.config.Logger.Debug("getDiscoveryClient: caching discovery client", "contextName", contextName)
@@ -630,7 +742,7 @@ func (c *kubernetesClient) isOperationAllowed(operation string) error {
 
 	// Check if operation is destructive and non-destructive mode is enabled
 	if c.nonDestructiveMode {
-		destructiveOps := []string{"delete", "patch", "scale", "create", "apply"}
+		destructiveOps := []string{"delete", "patch", "scale", "create", "apply", "evict"}
 		for _, destructiveOp := range destructiveOps {
 			if destructiveOp == operation {
 				if !c.dryRun {
@@ -690,6 +802,9 @@ func (c *kubernetesClient) ListContexts(ctx context.Context) ([]ContextInfo, err
 	var contexts []ContextInfo
 
 	for contextName, contextInfo := range c.kubeconfigData.Contexts {
+		if c.checkContextAllowed(contextName) != nil {
+			continue
+		}
 		contexts = append(contexts, ContextInfo{
 			Name:      contextName,
 			Cluster:   contextInfo.Cluster,
@@ -749,6 +864,9 @@ func (c *kubernetesClient) SwitchContext(ctx context.Context, contextName string
 	if _, exists := c.kubeconfigData.Contexts[contextName]; !exists {
 		return fmt.Errorf("context %q does not exist in kubeconfig", contextName)
 	}
+	if err := c.checkContextAllowed(contextName); err != nil {
+		return err
+	}
 
 	// Update current context
 	c.mu.Lock()
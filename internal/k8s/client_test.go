@@ -209,6 +209,90 @@ func TestClientContextManagement(t *testing.T) {
 	})
 }
 
+func TestNewClient_AllowedContexts(t *testing.T) {
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	kubeconfig := `
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://dev:6443
+  name: dev
+- cluster:
+    server: https://staging:6443
+  name: staging
+- cluster:
+    server: https://prod:6443
+  name: prod
+contexts:
+- context: {cluster: dev, user: test-user}
+  name: dev
+- context: {cluster: staging, user: test-user}
+  name: staging
+- context: {cluster: prod, user: test-user}
+  name: prod
+current-context: prod
+users:
+- name: test-user
+  user:
+    token: test-token
+`
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte(kubeconfig), 0600)) // #nosec G306 - test file
+
+	newClient := func(t *testing.T, context string, allowed ...string) (*kubernetesClient, error) {
+		t.Helper()
+		return NewClient(&ClientConfig{KubeconfigPath: kubeconfigPath, Context: context, AllowedContexts: allowed})
+	}
+
+	t.Run("allowed contexts get their own clients", func(t *testing.T) {
+		client, err := newClient(t, "", "dev", "staging")
+		require.NoError(t, err)
+		assert.Equal(t, "dev", client.currentContext, "the first allowed context replaces a disallowed current context")
+
+		devConfig, err := client.getRestConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "https://dev:6443", devConfig.Host)
+		stagingConfig, err := client.getRestConfig("staging")
+		require.NoError(t, err)
+		assert.Equal(t, "https://staging:6443", stagingConfig.Host)
+		cached, err := client.getRestConfig("staging")
+		require.NoError(t, err)
+		assert.Same(t, stagingConfig, cached)
+
+		_, err = client.getRestConfig("prod")
+		assert.ErrorIs(t, err, ErrContextNotAllowed)
+		_, err = client.getDynamicClient("prod")
+		assert.ErrorIs(t, err, ErrContextNotAllowed)
+	})
+
+	t.Run("contexts are listed and switched within the allowlist", func(t *testing.T) {
+		client, err := newClient(t, "", "dev", "staging")
+		require.NoError(t, err)
+		ctx := context.Background()
+
+		contexts, err := client.ListContexts(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, c := range contexts {
+			names = append(names, c.Name)
+		}
+		assert.ElementsMatch(t, []string{"dev", "staging"}, names)
+
+		require.NoError(t, client.SwitchContext(ctx, "staging"))
+		assert.ErrorIs(t, client.SwitchContext(ctx, "prod"), ErrContextNotAllowed)
+	})
+
+	t.Run("configured context must be allowed", func(t *testing.T) {
+		_, err := newClient(t, "prod", "dev")
+		assert.ErrorIs(t, err, ErrContextNotAllowed)
+	})
+
+	t.Run("allowed contexts must exist", func(t *testing.T) {
+		_, err := newClient(t, "", "dev", "missing")
+		assert.ErrorContains(t, err, `allowed context "missing" does not exist`)
+	})
+}
+
 func TestClientContextManagementInCluster(t *testing.T) {
 	// Test with a client in in-cluster mode
 	client := &kubernetesClient{
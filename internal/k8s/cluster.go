@@ -81,7 +81,9 @@ func (c *kubernetesClient) getAllAPIResources(ctx context.Context, kubeContext s
 // GetAPIResources returns available API resources with pagination support.
 func (c *kubernetesClient) GetAPIResources(ctx context.Context, kubeContext string, limit, offset int, apiGroup string, namespacedOnly bool, verbs []string) (*PaginatedAPIResourceResponse, error) {
 	// First get all API resources using the helper method
-	allResources, err := c.getAllAPIResources(ctx, kubeContext)
+	allResources, err := retryRead(ctx, c.config.Retry, "api-resources", func() ([]APIResourceInfo, error) {
+		return c.getAllAPIResources(ctx, kubeContext)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +155,25 @@ func (c *kubernetesClient) GetAPIResources(ctx context.Context, kubeContext stri
 	}, nil
 }
 
+// ProxyGetService sends a GET request to a service through the API server's
+// service proxy.
+func (c *kubernetesClient) ProxyGetService(ctx context.Context, kubeContext, namespace string, req ServiceProxyRequest) (*ServiceProxyResponse, error) {
+	if err := c.isOperationAllowed("proxy"); err != nil {
+		return nil, err
+	}
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+
+	c.logOperation("proxy-get", kubeContext, namespace, "service", req.Service)
+
+	clientset, err := c.getClientset(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	return proxyGetService(ctx, clientset, namespace, req)
+}
+
 // GetClusterHealth returns the health status of the cluster.
 func (c *kubernetesClient) GetClusterHealth(ctx context.Context, kubeContext string) (*ClusterHealth, error) {
 	// Validate operation
@@ -186,6 +207,8 @@ func (c *kubernetesClient) GetClusterHealth(ctx context.Context, kubeContext str
 		return health, nil
 	}
 
+	health.Version = version.GitVersion
+
 	// API Server is healthy if we can get version
 	health.Components = append(health.Components, ComponentHealth{
 		Name:    "API Server",
@@ -1,11 +1,17 @@
 package k8s
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func TestAPIResourceInfo_Structure(t *testing.T) {
@@ -319,3 +325,49 @@ func TestKubernetesClient_LogClusterOperations(t *testing.T) {
 	// Verify that logging occurred
 	assert.NotEmpty(t, testLog.messages)
 }
+
+func TestProxyGetService(t *testing.T) {
+	var gotPath string
+	status := http.StatusOK
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("hello from the service"))
+	}))
+	defer apiServer.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: apiServer.URL})
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		resp, err := proxyGetService(context.Background(), clientset, "shop", ServiceProxyRequest{
+			Scheme: "https", Service: "checkout", Port: "web", Path: "/healthz",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v1/namespaces/shop/services/https:checkout:web/proxy/healthz", gotPath)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "hello from the service", resp.Body)
+		assert.False(t, resp.Truncated)
+	})
+
+	t.Run("truncates body", func(t *testing.T) {
+		resp, err := proxyGetService(context.Background(), clientset, "shop", ServiceProxyRequest{Service: "checkout", MaxBytes: 5})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", resp.Body)
+		assert.True(t, resp.Truncated)
+	})
+
+	t.Run("error status is a response", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		resp, err := proxyGetService(context.Background(), clientset, "shop", ServiceProxyRequest{Service: "checkout"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("unreachable API server", func(t *testing.T) {
+		offline, err := kubernetes.NewForConfig(&rest.Config{Host: "http://127.0.0.1:1"})
+		require.NoError(t, err)
+		_, err = proxyGetService(context.Background(), offline, "shop", ServiceProxyRequest{Service: "checkout"})
+		assert.Error(t, err)
+	})
+}
@@ -1,5 +1,7 @@
 package k8s
 
+import "time"
+
 const (
 	// Service account paths - default Kubernetes in-cluster locations
 	DefaultServiceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
@@ -15,6 +17,10 @@ const (
 	// Discovery timeout
 	DiscoveryTimeoutSeconds = 30
 
+	// DefaultDiscoveryMinInterval is the default minimum time between two
+	// API discovery requests against the same cluster.
+	DefaultDiscoveryMinInterval = 30 * time.Second
+
 	// In-cluster context name
 	InClusterContext = "in-cluster"
 
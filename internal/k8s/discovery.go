@@ -0,0 +1,356 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// DiscoveryBudget bounds API discovery traffic (ServerPreferredResources)
+// independently of the timeout applied to data-plane calls. Discovery walks
+// every aggregated API group, so a single slow APIService can stall it far
+// longer than a typical get/list, and hammering it on every tool call is
+// the quickest way to get throttled by a busy API server.
+type DiscoveryBudget struct {
+	// Timeout bounds a single ServerPreferredResources call.
+	// Defaults to DiscoveryTimeoutSeconds.
+	Timeout time.Duration
+
+	// MinInterval is the hard cap on discovery frequency per cluster: within
+	// this window the previous successful result is reused instead of
+	// issuing another discovery request. Failures are not cached, so the
+	// next call retries. Defaults to DefaultDiscoveryMinInterval.
+	MinInterval time.Duration
+}
+
+// withDefaults returns a copy of the budget with zero values replaced by defaults.
+func (b DiscoveryBudget) withDefaults() DiscoveryBudget {
+	if b.Timeout <= 0 {
+		b.Timeout = DiscoveryTimeoutSeconds * time.Second
+	}
+	if b.MinInterval <= 0 {
+		b.MinInterval = DefaultDiscoveryMinInterval
+	}
+	return b
+}
+
+// errDiscoveryTimeout is returned when discovery does not complete within the budget.
+var errDiscoveryTimeout = errors.New("API discovery timed out")
+
+const (
+	// discoveryEntryTTL is how long a discovery result is kept after it
+	// was last asked for. Per-user keys come and go with the users of the
+	// server, so unused entries are dropped rather than kept forever.
+	discoveryEntryTTL = 30 * time.Minute
+
+	// maxDiscoveryEntries caps the number of keys the throttle holds; the
+	// least recently used entry is evicted beyond it.
+	maxDiscoveryEntries = 1024
+)
+
+// discoveryThrottle enforces a DiscoveryBudget per cluster key. Concurrent
+// callers for the same key are serialized so that at most one discovery
+// request per cluster is in flight at any time. Clients acting for a user
+// include the user's identity in the key (see userDiscoveryKey). Entries
+// unused for discoveryEntryTTL are evicted, and at most maxDiscoveryEntries
+// are kept.
+type discoveryThrottle struct {
+	mu         sync.Mutex
+	budget     DiscoveryBudget
+	entries    map[string]*discoveryEntry
+	maxEntries int
+	sweptAt    time.Time
+	now        func() time.Time
+}
+
+// discoveryEntry holds the last successful discovery result for a single key.
+// err is the partial-discovery error returned alongside resources, if any.
+// usedAt is guarded by the throttle's mutex, the other fields by mu.
+type discoveryEntry struct {
+	mu        sync.Mutex
+	resources []*metav1.APIResourceList
+	err       error
+	fetchedAt time.Time
+	usedAt    time.Time
+}
+
+// newDiscoveryThrottle creates a throttle with the given budget.
+func newDiscoveryThrottle(budget DiscoveryBudget) *discoveryThrottle {
+	return &discoveryThrottle{
+		budget:     budget.withDefaults(),
+		entries:    make(map[string]*discoveryEntry),
+		maxEntries: maxDiscoveryEntries,
+		now:        time.Now,
+	}
+}
+
+// sharedDiscoveryThrottle is used by every client implementation so that the
+// per-cluster frequency cap holds across kubeconfig, bearer token,
+// impersonation and federated clients alike.
+var sharedDiscoveryThrottle = newDiscoveryThrottle(DiscoveryBudget{})
+
+// SetDiscoveryBudget replaces the discovery budget used by all clients and
+// drops any cached discovery results. It is intended to be called once at
+// startup, before any client is created.
+func SetDiscoveryBudget(budget DiscoveryBudget) {
+	sharedDiscoveryThrottle.setBudget(budget)
+}
+
+// CurrentDiscoveryBudget returns the discovery budget currently in effect.
+func CurrentDiscoveryBudget() DiscoveryBudget {
+	return sharedDiscoveryThrottle.currentBudget()
+}
+
+func (t *discoveryThrottle) setBudget(budget DiscoveryBudget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budget = budget.withDefaults()
+	t.entries = make(map[string]*discoveryEntry)
+}
+
+func (t *discoveryThrottle) currentBudget() DiscoveryBudget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.budget
+}
+
+func (t *discoveryThrottle) entry(key string) (*discoveryEntry, DiscoveryBudget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	e, ok := t.entries[key]
+	if !ok {
+		t.evict(now)
+		e = &discoveryEntry{}
+		t.entries[key] = e
+	}
+	e.usedAt = now
+	return e, t.budget
+}
+
+// evict drops the entries unused for discoveryEntryTTL, at most once per
+// TTL unless the throttle is full, and, if it is still full, the least
+// recently used one, making room for a new entry. A caller still holding an
+// evicted entry finishes with it; the next call for its key starts afresh.
+// Must be called with t.mu held.
+func (t *discoveryThrottle) evict(now time.Time) {
+	if len(t.entries) < t.maxEntries && now.Sub(t.sweptAt) < discoveryEntryTTL {
+		return
+	}
+	t.sweptAt = now
+	var oldestKey string
+	var oldest *discoveryEntry
+	for key, e := range t.entries {
+		switch {
+		case now.Sub(e.usedAt) >= discoveryEntryTTL:
+			delete(t.entries, key)
+		case oldest == nil || e.usedAt.Before(oldest.usedAt):
+			oldestKey, oldest = key, e
+		}
+	}
+	if len(t.entries) >= t.maxEntries {
+		delete(t.entries, oldestKey)
+	}
+}
+
+// serverPreferredResources returns the preferred resources for the cluster
+// identified by key, issuing a discovery request only if the last successful
+// one is older than the budget's MinInterval. Failures are never cached: a
+// failed refresh returns the previous successful result, when one exists,
+// together with the error so callers can tell the data may be stale.
+func (t *discoveryThrottle) serverPreferredResources(key string, dc discovery.DiscoveryInterface) ([]*metav1.APIResourceList, error) {
+	e, budget := t.entry(key)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.fetchedAt.IsZero() && t.now().Sub(e.fetchedAt) < budget.MinInterval {
+		return e.resources, e.err
+	}
+
+	resources, err := fetchPreferredResources(dc, budget.Timeout)
+	if len(resources) == 0 && err != nil {
+		return e.resources, err
+	}
+	e.resources = resources
+	e.err = err
+	e.fetchedAt = t.now()
+	return resources, err
+}
+
+// fetchPreferredResources calls ServerPreferredResources with a timeout.
+// ServerPreferredResources does not accept a context, so the call runs in a
+// goroutine and is abandoned (not cancelled) when the timeout fires.
+func fetchPreferredResources(dc discovery.DiscoveryInterface, timeout time.Duration) ([]*metav1.APIResourceList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type discoveryResult struct {
+		resourceLists []*metav1.APIResourceList
+		err           error
+	}
+
+	resultChan := make(chan discoveryResult, 1)
+	go func() {
+		resourceLists, err := dc.ServerPreferredResources()
+		resultChan <- discoveryResult{resourceLists: resourceLists, err: err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		// ServerPreferredResources may return partial results with an error
+		return result.resourceLists, result.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w after %s", errDiscoveryTimeout, timeout)
+	}
+}
+
+// throttledDiscoveryClient wraps a discovery client so that
+// ServerPreferredResources honours the shared discovery budget for its cluster.
+// All other discovery methods pass straight through.
+type throttledDiscoveryClient struct {
+	discovery.DiscoveryInterface
+	key      string
+	throttle *discoveryThrottle
+}
+
+// newThrottledDiscoveryClient wraps dc with the shared discovery throttle.
+// key must uniquely identify the target cluster.
+func newThrottledDiscoveryClient(key string, dc discovery.DiscoveryInterface) discovery.DiscoveryInterface {
+	if dc == nil {
+		return nil
+	}
+	if _, ok := dc.(*throttledDiscoveryClient); ok {
+		return dc
+	}
+	return &throttledDiscoveryClient{
+		DiscoveryInterface: dc,
+		key:                key,
+		throttle:           sharedDiscoveryThrottle,
+	}
+}
+
+// userDiscoveryKey returns the throttle key for a client that acts for user
+// with groups on cluster. Discovery results depend on the caller's
+// permissions, which RBAC grants to users and groups, so they are shared
+// only between clients with the same identity.
+func userDiscoveryKey(cluster, user string, groups []string) string {
+	key := cluster + "|user:" + user
+	if len(groups) > 0 {
+		groups = slices.Clone(groups)
+		slices.Sort(groups)
+		key += "|groups:" + strings.Join(groups, ",")
+	}
+	return key
+}
+
+// tokenDiscoveryKey returns the throttle key for a client that only knows
+// its caller by bearer token. The token is hashed so that credentials are
+// never held as map keys.
+func tokenDiscoveryKey(cluster, token string) string {
+	return cluster + "|token:" + hashToken(token)
+}
+
+// ServerPreferredResources returns the (possibly cached) preferred resources.
+func (c *throttledDiscoveryClient) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return c.throttle.serverPreferredResources(c.key, c.DiscoveryInterface)
+}
+
+// preferredResources returns discovery results for dc, applying the shared
+// discovery timeout when dc has not been wrapped by the throttle.
+func preferredResources(dc discovery.DiscoveryInterface) ([]*metav1.APIResourceList, error) {
+	if _, ok := dc.(*throttledDiscoveryClient); ok {
+		return dc.ServerPreferredResources()
+	}
+	return fetchPreferredResources(dc, CurrentDiscoveryBudget().Timeout)
+}
+
+// builtinResource describes a core Kubernetes resource in the fallback table.
+type builtinResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+	aliases    []string
+}
+
+// builtinResources is the fallback alias table used only when API discovery
+// is unavailable. It covers the stable built-in APIs; CRDs and aggregated
+// APIs always require discovery.
+var builtinResources = []builtinResource{
+	{schema.GroupVersionResource{Version: "v1", Resource: "pods"}, true, []string{"pod", "po"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "services"}, true, []string{"service", "svc"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, true, []string{"configmap", "cm"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, true, []string{"secret"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, true, []string{"serviceaccount", "sa"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}, true, []string{"endpoint", "ep"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "events"}, true, []string{"event", "ev"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, true, []string{"persistentvolumeclaim", "pvc"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}, true, []string{"resourcequota", "quota"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}, true, []string{"limitrange", "limits"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "nodes"}, false, []string{"node", "no"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, false, []string{"namespace", "ns"}},
+	{schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumes"}, false, []string{"persistentvolume", "pv"}},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, true, []string{"deployment", "deploy"}},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, true, []string{"statefulset", "sts"}},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, true, []string{"daemonset", "ds"}},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, true, []string{"replicaset", "rs"}},
+	{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, true, []string{"job"}},
+	{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, true, []string{"cronjob", "cj"}},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, true, []string{"ingress", "ing"}},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}, true, []string{"networkpolicy", "netpol"}},
+	{schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}, true, []string{"poddisruptionbudget", "pdb"}},
+	{schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}, true, []string{"horizontalpodautoscaler", "hpa"}},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, true, []string{"role"}},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, true, []string{"rolebinding"}},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, false, []string{"clusterrole"}},
+	{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, false, []string{"clusterrolebinding"}},
+	{schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}, false, []string{"storageclass", "sc"}},
+	{schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, false, []string{"customresourcedefinition", "crd", "crds"}},
+}
+
+// lookupBuiltinResource resolves resourceType against the fallback table,
+// honouring the requested API group when one is given.
+func lookupBuiltinResource(resourceType, requestedGroup string) (schema.GroupVersionResource, bool, bool) {
+	resourceType = strings.ToLower(resourceType)
+	for _, r := range builtinResources {
+		if requestedGroup != "" && !groupsMatch(requestedGroup, r.gvr.Group) {
+			continue
+		}
+		if r.gvr.Resource == resourceType {
+			return r.gvr, r.namespaced, true
+		}
+		for _, alias := range r.aliases {
+			if alias == resourceType {
+				return r.gvr, r.namespaced, true
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, false, false
+}
+
+// BuiltinNamespaced reports whether resourceType, a plural, singular, kind
+// or short name of a built-in resource in apiGroup (any group when empty),
+// is namespaced. ok is false for other resources, whose scope only
+// discovery knows.
+func BuiltinNamespaced(resourceType, apiGroup string) (namespaced, ok bool) {
+	group, _ := parseAPIGroup(apiGroup)
+	_, namespaced, ok = lookupBuiltinResource(resourceType, group)
+	return namespaced, ok
+}
+
+// CanonicalResource returns the plural name of resourceType when it is a
+// plural, singular, kind or short name of a built-in resource in apiGroup
+// (any group when empty). ok is false for other resources, which need
+// discovery to resolve.
+func CanonicalResource(resourceType, apiGroup string) (resource string, ok bool) {
+	group, _ := parseAPIGroup(apiGroup)
+	gvr, _, ok := lookupBuiltinResource(resourceType, group)
+	return gvr.Resource, ok
+}
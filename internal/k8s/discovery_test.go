@@ -0,0 +1,244 @@
+package k8s
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// countingDiscovery wraps a discovery client and counts ServerPreferredResources calls.
+type countingDiscovery struct {
+	discovery.DiscoveryInterface
+	calls     atomic.Int32
+	delay     time.Duration
+	err       error
+	resources []*metav1.APIResourceList
+}
+
+func (d *countingDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	d.calls.Add(1)
+	if d.delay > 0 {
+		time.Sleep(d.delay)
+	}
+	return d.resources, d.err
+}
+
+func newCountingDiscovery() *countingDiscovery {
+	return &countingDiscovery{
+		DiscoveryInterface: &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		resources: []*metav1.APIResourceList{
+			{
+				GroupVersion: "example.com/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "widgets", SingularName: "widget", Kind: "Widget", Namespaced: true},
+				},
+			},
+		},
+	}
+}
+
+func TestDiscoveryThrottleCapsFrequency(t *testing.T) {
+	dc := newCountingDiscovery()
+	throttle := newDiscoveryThrottle(DiscoveryBudget{Timeout: time.Second, MinInterval: time.Minute})
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		res, err := throttle.serverPreferredResources("cluster-a", dc)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+	}
+	assert.Equal(t, int32(1), dc.calls.Load(), "discovery should run once within MinInterval")
+
+	// A different cluster has its own budget.
+	_, err := throttle.serverPreferredResources("cluster-b", dc)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), dc.calls.Load())
+
+	// Once the interval has elapsed discovery runs again.
+	now = now.Add(2 * time.Minute)
+	_, err = throttle.serverPreferredResources("cluster-a", dc)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), dc.calls.Load())
+}
+
+func TestDiscoveryThrottleDoesNotCacheFailures(t *testing.T) {
+	dc := newCountingDiscovery()
+	resources := dc.resources
+	dc.resources = nil
+	dc.err = errors.New("Unauthorized")
+	throttle := newDiscoveryThrottle(DiscoveryBudget{Timeout: time.Second, MinInterval: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		_, err := throttle.serverPreferredResources("cluster-a", dc)
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(3), dc.calls.Load(), "failed discovery must be retried")
+
+	// The next success is cached as usual.
+	dc.resources, dc.err = resources, nil
+	for i := 0; i < 3; i++ {
+		res, err := throttle.serverPreferredResources("cluster-a", dc)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+	}
+	assert.Equal(t, int32(4), dc.calls.Load())
+}
+
+func TestUserDiscoveryKey(t *testing.T) {
+	alice := userDiscoveryKey("host:https://api", "alice", []string{"dev", "ops"})
+	assert.Equal(t, alice, userDiscoveryKey("host:https://api", "alice", []string{"ops", "dev"}), "group order does not matter")
+	assert.NotEqual(t, alice, userDiscoveryKey("host:https://api", "alice", []string{"dev"}))
+	assert.NotEqual(t, alice, userDiscoveryKey("host:https://api", "bob", []string{"dev", "ops"}))
+	assert.NotEqual(t, alice, userDiscoveryKey("host:https://other", "alice", []string{"dev", "ops"}))
+
+	token := tokenDiscoveryKey("host:https://api", "alice-token")
+	assert.NotEqual(t, token, tokenDiscoveryKey("host:https://api", "bob-token"))
+	assert.NotContains(t, token, "alice-token")
+}
+
+func TestFederatedDiscoveryKey(t *testing.T) {
+	impersonating := &FederatedClientConfig{ClusterName: "prod", User: "alice", RestConfig: &rest.Config{
+		Impersonate: rest.ImpersonationConfig{UserName: "alice", Groups: []string{"dev"}},
+	}}
+	assert.Equal(t, userDiscoveryKey("cluster:prod", "alice", []string{"dev"}), federatedDiscoveryKey(impersonating))
+
+	passthrough := &FederatedClientConfig{ClusterName: "prod", User: "alice", Groups: []string{"ops"}, RestConfig: &rest.Config{BearerToken: "alice-token"}}
+	assert.Equal(t, userDiscoveryKey("cluster:prod", "alice", []string{"ops"}), federatedDiscoveryKey(passthrough))
+
+	passthrough.User = ""
+	assert.Equal(t, tokenDiscoveryKey("cluster:prod", "alice-token"), federatedDiscoveryKey(passthrough))
+}
+
+func TestDiscoveryThrottleEvictsEntries(t *testing.T) {
+	dc := newCountingDiscovery()
+	throttle := newDiscoveryThrottle(DiscoveryBudget{Timeout: time.Second, MinInterval: time.Minute})
+	throttle.maxEntries = 2
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+
+	_, _ = throttle.serverPreferredResources("alice", dc)
+	now = now.Add(time.Second)
+	_, _ = throttle.serverPreferredResources("bob", dc)
+	now = now.Add(time.Second)
+	_, _ = throttle.serverPreferredResources("alice", dc)
+	now = now.Add(time.Second)
+	_, _ = throttle.serverPreferredResources("carol", dc)
+	assert.ElementsMatch(t, []string{"alice", "carol"}, keys(throttle.entries), "the least recently used entry is evicted")
+
+	now = now.Add(discoveryEntryTTL)
+	_, _ = throttle.serverPreferredResources("dave", dc)
+	assert.ElementsMatch(t, []string{"dave"}, keys(throttle.entries), "entries unused for the TTL are evicted")
+}
+
+func keys(entries map[string]*discoveryEntry) []string {
+	var out []string
+	for key := range entries {
+		out = append(out, key)
+	}
+	return out
+}
+
+func TestDiscoveryThrottleTimeoutKeepsLastGoodResult(t *testing.T) {
+	dc := newCountingDiscovery()
+	throttle := newDiscoveryThrottle(DiscoveryBudget{Timeout: 50 * time.Millisecond, MinInterval: time.Minute})
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+
+	_, err := throttle.serverPreferredResources("cluster-a", dc)
+	require.NoError(t, err)
+
+	dc.delay = 500 * time.Millisecond
+	now = now.Add(2 * time.Minute)
+	res, err := throttle.serverPreferredResources("cluster-a", dc)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errDiscoveryTimeout))
+	assert.Len(t, res, 1, "stale result should be served when refresh times out")
+}
+
+func TestDiscoveryBudgetDefaults(t *testing.T) {
+	b := DiscoveryBudget{}.withDefaults()
+	assert.Equal(t, DiscoveryTimeoutSeconds*time.Second, b.Timeout)
+	assert.Equal(t, DefaultDiscoveryMinInterval, b.MinInterval)
+
+	b = DiscoveryBudget{Timeout: 5 * time.Second, MinInterval: time.Second}.withDefaults()
+	assert.Equal(t, 5*time.Second, b.Timeout)
+	assert.Equal(t, time.Second, b.MinInterval)
+}
+
+func TestResolveResourceTypeDetailed(t *testing.T) {
+	t.Run("discovery hit is not degraded", func(t *testing.T) {
+		dc := newCountingDiscovery()
+		res, err := resolveResourceTypeDetailed("widget", "", dc)
+		require.NoError(t, err)
+		assert.Equal(t, "widgets", res.GVR.Resource)
+		assert.True(t, res.Namespaced)
+		assert.False(t, res.Degraded)
+	})
+
+	t.Run("discovery failure falls back to built-in table", func(t *testing.T) {
+		dc := newCountingDiscovery()
+		dc.resources = nil
+		dc.err = errors.New("aggregated API unavailable")
+
+		res, err := resolveResourceTypeDetailed("deploy", "", dc)
+		require.NoError(t, err)
+		assert.Equal(t, "apps", res.GVR.Group)
+		assert.Equal(t, "deployments", res.GVR.Resource)
+		assert.True(t, res.Namespaced)
+		assert.True(t, res.Degraded)
+
+		res, err = resolveResourceTypeDetailed("nodes", "", dc)
+		require.NoError(t, err)
+		assert.False(t, res.Namespaced)
+		assert.True(t, res.Degraded)
+	})
+
+	t.Run("unknown types still fail when discovery is down", func(t *testing.T) {
+		dc := newCountingDiscovery()
+		dc.resources = nil
+		dc.err = errors.New("aggregated API unavailable")
+
+		_, err := resolveResourceTypeDetailed("widgets", "", dc)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown resource type")
+	})
+
+	t.Run("requested group is honoured by fallback", func(t *testing.T) {
+		dc := newCountingDiscovery()
+		dc.resources = nil
+		dc.err = errors.New("aggregated API unavailable")
+
+		_, err := resolveResourceTypeDetailed("deployments", "batch", dc)
+		require.Error(t, err)
+	})
+}
+
+func TestMarkDegradedDiscovery(t *testing.T) {
+	meta := BuildResponseMeta(false, "default", "", "nodes", false)
+	markDegradedDiscovery(meta, resourceResolution{Degraded: true})
+	assert.True(t, meta.DegradedDiscovery)
+	assert.Contains(t, meta.Hint, "cluster-scoped")
+	assert.Contains(t, meta.Hint, "built-in table")
+
+	meta = BuildResponseMeta(true, "default", "default", "pods", false)
+	markDegradedDiscovery(meta, resourceResolution{})
+	assert.False(t, meta.DegradedDiscovery)
+	assert.Empty(t, meta.Hint)
+}
+
+func TestNewThrottledDiscoveryClientIsIdempotent(t *testing.T) {
+	dc := newCountingDiscovery()
+	wrapped := newThrottledDiscoveryClient("cluster-a", dc)
+	assert.Same(t, wrapped, newThrottledDiscoveryClient("cluster-a", wrapped))
+	assert.Nil(t, newThrottledDiscoveryClient("cluster-a", nil))
+}
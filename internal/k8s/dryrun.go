@@ -0,0 +1,36 @@
+package k8s
+
+import "context"
+
+// dryRunKey is the context key for a per-call dry-run request.
+type dryRunKey struct{}
+
+// ContextWithDryRun returns a context whose mutating Kubernetes API requests
+// are sent as server-side dry-runs, whatever the client's DryRun setting.
+// A per-call request can only turn dry-run on, never off.
+func ContextWithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// DryRunFromContext reports whether ctx was returned by ContextWithDryRun.
+func DryRunFromContext(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// isDryRun reports whether a mutation made with ctx by a client configured
+// with dryRun must be a dry-run.
+func isDryRun(ctx context.Context, dryRun bool) bool {
+	return dryRun || DryRunFromContext(ctx)
+}
+
+// markDryRun flags meta and message of a mutation made as a dry-run.
+func markDryRun(meta *ResponseMeta, message string, dryRun bool) (*ResponseMeta, string) {
+	if !dryRun {
+		return meta, message
+	}
+	if meta != nil {
+		meta.DryRun = true
+	}
+	return meta, message + " (dry run)"
+}
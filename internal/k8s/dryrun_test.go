@@ -0,0 +1,26 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDryRun(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, isDryRun(ctx, false))
+	assert.True(t, isDryRun(ctx, true))
+	assert.True(t, isDryRun(ContextWithDryRun(ctx), false))
+	assert.True(t, isDryRun(ContextWithDryRun(ctx), true))
+}
+
+func TestMarkDryRun(t *testing.T) {
+	meta, message := markDryRun(&ResponseMeta{ResourceScope: "namespaced"}, "Resource pods/web deleted successfully", true)
+	assert.True(t, meta.DryRun)
+	assert.Equal(t, "Resource pods/web deleted successfully (dry run)", message)
+
+	meta, message = markDryRun(&ResponseMeta{ResourceScope: "namespaced"}, "Resource pods/web deleted successfully", false)
+	assert.False(t, meta.DryRun)
+	assert.Equal(t, "Resource pods/web deleted successfully", message)
+}
@@ -0,0 +1,315 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/pkg/apis/clientauthentication"
+	"k8s.io/client-go/pkg/apis/clientauthentication/install"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Default exec credential plugin limits, applied when ExecPluginConfig
+// leaves them unset.
+const (
+	DefaultExecPluginTimeout        = 30 * time.Second
+	DefaultExecPluginMaxOutputBytes = 1 << 20
+)
+
+// maxExecPluginStderr bounds the plugin error output kept for error messages.
+const maxExecPluginStderr = 1024
+
+// execInfoEnv is the environment variable that passes the ExecCredential
+// request to the plugin.
+const execInfoEnv = "KUBERNETES_EXEC_INFO"
+
+var (
+	// ErrExecPluginNotAllowed is returned for exec credential plugins that
+	// are not in ExecPluginConfig.Allowlist.
+	ErrExecPluginNotAllowed = errors.New("exec credential plugin is not allowed")
+
+	// ErrExecPluginTimeout is returned when an exec credential plugin does
+	// not finish within ExecPluginConfig.Timeout.
+	ErrExecPluginTimeout = errors.New("exec credential plugin timed out")
+
+	// ErrExecPluginFailed is returned when an exec credential plugin fails
+	// or returns output that is not a usable credential.
+	ErrExecPluginFailed = errors.New("exec credential plugin failed")
+)
+
+var (
+	execCredentialScheme = runtime.NewScheme()
+	execCredentialCodecs = serializer.NewCodecFactory(execCredentialScheme)
+)
+
+func init() {
+	install.Install(execCredentialScheme)
+}
+
+// ExecPluginConfig hardens the exec credential plugins of kubeconfig users,
+// such as aws-iam-authenticator or gke-gcloud-auth-plugin. Plugins run with a
+// timeout and an output limit instead of client-go's unbounded execution, so
+// a hanging plugin fails the tool call instead of blocking it.
+//
+// Plugins must return a bearer token; client certificates are not supported.
+type ExecPluginConfig struct {
+	// Allowlist lists the plugins that may run, by name (looked up in PATH)
+	// or absolute path. Empty allows every plugin.
+	Allowlist []string
+
+	// Timeout bounds a plugin run. Defaults to DefaultExecPluginTimeout.
+	Timeout time.Duration
+
+	// MaxOutputBytes bounds the plugin's output. Defaults to
+	// DefaultExecPluginMaxOutputBytes.
+	MaxOutputBytes int64
+}
+
+// ApplyTo replaces the exec credential plugin of config, if any, with one
+// that enforces c. It fails if the plugin is not allowed. It must be called
+// before clients are created from config.
+func (c ExecPluginConfig) ApplyTo(config *rest.Config) error {
+	if config == nil || config.ExecProvider == nil {
+		return nil
+	}
+	execConfig := config.ExecProvider
+
+	path, err := c.resolve(execConfig.Command)
+	if err != nil {
+		return err
+	}
+	if execConfig.InteractiveMode == clientcmdapi.AlwaysExecInteractiveMode {
+		return fmt.Errorf("%w: %s requires an interactive terminal", ErrExecPluginFailed, execConfig.Command)
+	}
+	group, err := schema.ParseGroupVersion(execConfig.APIVersion)
+	if err != nil {
+		return fmt.Errorf("%w: invalid apiVersion %q: %v", ErrExecPluginFailed, execConfig.APIVersion, err)
+	}
+	if !execCredentialScheme.IsVersionRegistered(group) {
+		return fmt.Errorf("%w: unsupported apiVersion %q", ErrExecPluginFailed, execConfig.APIVersion)
+	}
+
+	var cluster *clientauthentication.Cluster
+	if execConfig.ProvideClusterInfo {
+		if cluster, err = rest.ConfigToExecCluster(config); err != nil {
+			return fmt.Errorf("%w: %v", ErrExecPluginFailed, err)
+		}
+	}
+
+	plugin := &execPlugin{
+		name:      execConfig.Command,
+		path:      path,
+		args:      execConfig.Args,
+		group:     group,
+		cluster:   cluster,
+		timeout:   c.Timeout,
+		maxOutput: c.MaxOutputBytes,
+	}
+	if plugin.timeout <= 0 {
+		plugin.timeout = DefaultExecPluginTimeout
+	}
+	if plugin.maxOutput <= 0 {
+		plugin.maxOutput = DefaultExecPluginMaxOutputBytes
+	}
+	for _, env := range execConfig.Env {
+		plugin.env = append(plugin.env, env.Name+"="+env.Value)
+	}
+
+	config.ExecProvider = nil
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &execPluginRoundTripper{plugin: plugin, delegate: rt}
+	})
+	return nil
+}
+
+// resolve returns the path of command, checking it against the allowlist.
+// Names in the allowlist match the plugin found in PATH under that name.
+func (c ExecPluginConfig) resolve(command string) (string, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s not found: %v", ErrExecPluginFailed, command, err)
+	}
+	if len(c.Allowlist) == 0 {
+		return path, nil
+	}
+	for _, entry := range c.Allowlist {
+		allowed := filepath.Clean(entry)
+		if !strings.ContainsRune(entry, filepath.Separator) {
+			if allowed, err = exec.LookPath(entry); err != nil {
+				continue
+			}
+		}
+		if allowed == path {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrExecPluginNotAllowed, command)
+}
+
+// execPlugin runs an exec credential plugin and caches its token until it
+// expires.
+type execPlugin struct {
+	name      string
+	path      string
+	args      []string
+	env       []string
+	group     schema.GroupVersion
+	cluster   *clientauthentication.Cluster
+	timeout   time.Duration
+	maxOutput int64
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// getToken returns the cached token or runs the plugin for a new one.
+func (p *execPlugin) getToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && (p.expiry.IsZero() || time.Now().Before(p.expiry)) {
+		return p.token, nil
+	}
+	token, expiry, err := p.run(ctx)
+	if err != nil {
+		return "", err
+	}
+	p.token, p.expiry = token, expiry
+	return token, nil
+}
+
+// invalidate drops token from the cache, after the API server rejected it.
+func (p *execPlugin) invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+// run executes the plugin and decodes the credential it writes to stdout.
+func (p *execPlugin) run(ctx context.Context) (string, time.Time, error) {
+	request := &clientauthentication.ExecCredential{
+		Spec: clientauthentication.ExecCredentialSpec{Cluster: p.cluster},
+	}
+	info, err := runtime.Encode(execCredentialCodecs.LegacyCodec(p.group), request)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: encode ExecCredential: %v", ErrExecPluginFailed, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: p.maxOutput, onOverflow: cancel}
+	stderr := &limitedBuffer{limit: maxExecPluginStderr}
+	cmd := exec.CommandContext(ctx, p.path, p.args...) // #nosec G204 -- plugin from the operator's kubeconfig, checked against the allowlist
+	cmd.Env = append(append(os.Environ(), p.env...), execInfoEnv+"="+string(info))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Output pipes held open by the plugin's children must not outlive the
+	// timeout.
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	switch {
+	case stdout.overflow:
+		return "", time.Time{}, fmt.Errorf("%w: %s wrote more than %d bytes", ErrExecPluginFailed, p.name, p.maxOutput)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "", time.Time{}, fmt.Errorf("%w: %s did not finish within %s", ErrExecPluginTimeout, p.name, p.timeout)
+	case ctx.Err() != nil:
+		return "", time.Time{}, ctx.Err()
+	case err != nil:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", time.Time{}, fmt.Errorf("%w: %s: %v: %s", ErrExecPluginFailed, p.name, err, msg)
+		}
+		return "", time.Time{}, fmt.Errorf("%w: %s: %v", ErrExecPluginFailed, p.name, err)
+	}
+
+	credential := &clientauthentication.ExecCredential{}
+	_, gvk, err := execCredentialCodecs.UniversalDecoder(p.group).Decode(stdout.Bytes(), nil, credential)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: %s: decoding output: %v", ErrExecPluginFailed, p.name, err)
+	}
+	if gvk.GroupVersion() != p.group {
+		return "", time.Time{}, fmt.Errorf("%w: %s returned %s, expected %s", ErrExecPluginFailed, p.name, gvk.GroupVersion(), p.group)
+	}
+	if credential.Status == nil || credential.Status.Token == "" {
+		if credential.Status != nil && credential.Status.ClientCertificateData != "" {
+			return "", time.Time{}, fmt.Errorf("%w: %s returned a client certificate, which is not supported", ErrExecPluginFailed, p.name)
+		}
+		return "", time.Time{}, fmt.Errorf("%w: %s returned no token", ErrExecPluginFailed, p.name)
+	}
+
+	var expiry time.Time
+	if credential.Status.ExpirationTimestamp != nil {
+		expiry = credential.Status.ExpirationTimestamp.Time
+	}
+	return credential.Status.Token, expiry, nil
+}
+
+// execPluginRoundTripper authenticates requests with the plugin's token.
+type execPluginRoundTripper struct {
+	plugin   *execPlugin
+	delegate http.RoundTripper
+}
+
+func (rt *execPluginRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Explicit credentials, such as a bearer token, take precedence as
+	// they do with client-go's plugins.
+	if req.Header.Get("Authorization") != "" {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	token, err := rt.plugin.getToken(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("getting credentials: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := rt.delegate.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		rt.plugin.invalidate(token)
+	}
+	return resp, err
+}
+
+// limitedBuffer keeps at most limit bytes written to it and reports whether
+// more were written. It does not embed bytes.Buffer, whose ReadFrom would
+// let io.Copy bypass the limit.
+type limitedBuffer struct {
+	buf        bytes.Buffer
+	limit      int64
+	overflow   bool
+	onOverflow func()
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - int64(b.buf.Len()); int64(len(p)) > room {
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		if !b.overflow && b.onOverflow != nil {
+			b.onOverflow()
+		}
+		b.overflow = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte  { return b.buf.Bytes() }
+func (b *limitedBuffer) String() string { return b.buf.String() }
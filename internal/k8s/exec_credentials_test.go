@@ -0,0 +1,182 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// writeExecPlugin writes a shell script plugin to a temporary directory and
+// returns its path.
+func writeExecPlugin(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test-auth-plugin")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700)) // #nosec G306 - test plugin must be executable
+	return path
+}
+
+// execPluginRequest sends a request with a client built from config and
+// returns the Authorization header the API server received.
+func execPluginRequest(t *testing.T, config *rest.Config) (string, error) {
+	t.Helper()
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("Authorization")
+	}))
+	t.Cleanup(srv.Close)
+
+	config.Host = srv.URL
+	client, err := rest.HTTPClientFor(config)
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL + "/api")
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+	return <-received, nil
+}
+
+func execPluginConfig(command string) *rest.Config {
+	return &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{
+		Command:         command,
+		APIVersion:      "client.authentication.k8s.io/v1",
+		Env:             []clientcmdapi.ExecEnvVar{{Name: "PLUGIN_TOKEN", Value: "plugin-token"}},
+		InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+	}}
+}
+
+const execPluginTokenScript = `echo run >> "$(dirname "$0")/runs"
+echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"'"$PLUGIN_TOKEN"'"}}'
+`
+
+func TestExecPluginConfig_Token(t *testing.T) {
+	plugin := writeExecPlugin(t, execPluginTokenScript)
+	config := execPluginConfig(plugin)
+	require.NoError(t, ExecPluginConfig{}.ApplyTo(config))
+	assert.Nil(t, config.ExecProvider)
+
+	for i := 0; i < 2; i++ {
+		auth, err := execPluginRequest(t, config)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer plugin-token", auth)
+	}
+
+	runs, err := os.ReadFile(filepath.Join(filepath.Dir(plugin), "runs"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(runs), "run"), "the token is cached")
+}
+
+func TestExecPluginConfig_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		config  ExecPluginConfig
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "hanging plugin times out",
+			script:  "sleep 10\n",
+			config:  ExecPluginConfig{Timeout: 200 * time.Millisecond},
+			wantErr: ErrExecPluginTimeout,
+			wantMsg: "did not finish within 200ms",
+		},
+		{
+			name:    "output over the limit",
+			script:  "head -c 4096 /dev/zero\n",
+			config:  ExecPluginConfig{MaxOutputBytes: 1024},
+			wantErr: ErrExecPluginFailed,
+			wantMsg: "more than 1024 bytes",
+		},
+		{
+			name:    "failing plugin reports its error output",
+			script:  "echo 'token expired, run login' >&2\nexit 1\n",
+			wantErr: ErrExecPluginFailed,
+			wantMsg: "token expired, run login",
+		},
+		{
+			name:    "client certificates are not supported",
+			script:  `echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"clientCertificateData":"x","clientKeyData":"y"}}'` + "\n",
+			wantErr: ErrExecPluginFailed,
+			wantMsg: "client certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := execPluginConfig(writeExecPlugin(t, tt.script))
+			require.NoError(t, tt.config.ApplyTo(config))
+
+			start := time.Now()
+			_, err := execPluginRequest(t, config)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorContains(t, err, tt.wantMsg)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func TestExecPluginConfig_Allowlist(t *testing.T) {
+	plugin := writeExecPlugin(t, execPluginTokenScript)
+	t.Setenv("PATH", filepath.Dir(plugin)+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tests := []struct {
+		name      string
+		command   string
+		allowlist []string
+		wantErr   error
+	}{
+		{name: "name in allowlist", command: "test-auth-plugin", allowlist: []string{"aws-iam-authenticator", "test-auth-plugin"}},
+		{name: "path in allowlist", command: plugin, allowlist: []string{plugin}},
+		{name: "path matches a name in allowlist", command: plugin, allowlist: []string{"test-auth-plugin"}},
+		{name: "not in allowlist", command: plugin, allowlist: []string{"aws-iam-authenticator"}, wantErr: ErrExecPluginNotAllowed},
+		{name: "missing plugin", command: "no-such-auth-plugin", wantErr: ErrExecPluginFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ExecPluginConfig{Allowlist: tt.allowlist}.ApplyTo(execPluginConfig(tt.command))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExecPluginConfig_InvalidatesRejectedToken(t *testing.T) {
+	plugin := writeExecPlugin(t, execPluginTokenScript)
+	config := execPluginConfig(plugin)
+	require.NoError(t, ExecPluginConfig{}.ApplyTo(config))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+	config.Host = srv.URL
+	client, err := rest.HTTPClientFor(config)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/api", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	runs, err := os.ReadFile(filepath.Join(filepath.Dir(plugin), "runs"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(runs), "run"), "a rejected token is not reused")
+}
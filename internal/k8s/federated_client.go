@@ -7,8 +7,10 @@ import (
 	"io"
 	"log/slog"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -44,7 +46,8 @@ type FederatedClient struct {
 	// restConfig is the REST configuration for the target cluster
 	restConfig *rest.Config
 
-	// discoveryClient is derived from clientset for resource type resolution
+	// discoveryClient is derived from clientset for resource type resolution.
+	// It is wrapped with the shared discovery throttle.
 	discoveryClient discovery.DiscoveryInterface
 }
 
@@ -61,6 +64,11 @@ type FederatedClientConfig struct {
 
 	// RestConfig is the REST configuration from the federation manager
 	RestConfig *rest.Config
+
+	// User and Groups identify the user the clients act for, if any. They
+	// key the client's discovery results.
+	User   string
+	Groups []string
 }
 
 // NewFederatedClient creates a new FederatedClient from federation manager clients.
@@ -84,10 +92,29 @@ func NewFederatedClient(config *FederatedClientConfig) (*FederatedClient, error)
 		clientset:       config.Clientset,
 		dynamicClient:   config.DynamicClient,
 		restConfig:      config.RestConfig,
-		discoveryClient: config.Clientset.Discovery(),
+		discoveryClient: newThrottledDiscoveryClient(federatedDiscoveryKey(config), config.Clientset.Discovery()),
 	}, nil
 }
 
+// federatedDiscoveryKey keys discovery results by cluster and by the user the
+// federation manager built the clients for: the impersonated user and
+// groups, the caller's identity when the manager passes the user's own
+// credentials through, or the bearer token when that is all there is.
+func federatedDiscoveryKey(config *FederatedClientConfig) string {
+	key := "cluster:" + config.ClusterName
+	impersonate := config.RestConfig.Impersonate
+	switch {
+	case impersonate.UserName != "":
+		return userDiscoveryKey(key, impersonate.UserName, impersonate.Groups)
+	case config.User != "":
+		return userDiscoveryKey(key, config.User, config.Groups)
+	case config.RestConfig.BearerToken != "":
+		return tokenDiscoveryKey(key, config.RestConfig.BearerToken)
+	default:
+		return key
+	}
+}
+
 // ClusterName returns the name of the target cluster.
 func (c *FederatedClient) ClusterName() string {
 	return c.clusterName
@@ -181,6 +208,20 @@ func (c *FederatedClient) Scale(ctx context.Context, _, namespace, resourceType,
 	return scaleResource(ctx, c.dynamicClient, c.discoveryClient, namespace, resourceType, apiGroup, name, replicas, false)
 }
 
+// Watch streams changes to resources.
+// The kubeContext parameter is ignored (federated clients operate on a single cluster).
+func (c *FederatedClient) Watch(ctx context.Context, _, namespace, resourceType, apiGroup string, opts WatchOptions) (watch.Interface, error) {
+	c.logOperation("watch", namespace, resourceType, "")
+	return watchResources(ctx, c.dynamicClient, c.discoveryClient, namespace, resourceType, apiGroup, opts)
+}
+
+// Validate performs a server-side dry-run create or update.
+// The kubeContext parameter is ignored (federated clients operate on a single cluster).
+func (c *FederatedClient) Validate(ctx context.Context, _, namespace string, obj runtime.Object, opts ValidateOptions) (*ValidateResponse, error) {
+	c.logOperation("validate", namespace, "resource", "")
+	return validateResource(ctx, c.dynamicClient, c.discoveryClient, namespace, obj, opts)
+}
+
 // PodManager implementation
 
 // GetLogs retrieves logs from a pod container.
@@ -211,6 +252,20 @@ func (c *FederatedClient) PortForwardToService(ctx context.Context, _, namespace
 	return portForwardToService(ctx, c.clientset, c.restConfig, namespace, serviceName, ports, opts)
 }
 
+// EvictPod evicts a pod through the Eviction API.
+// The kubeContext parameter is ignored (federated clients operate on a single cluster).
+func (c *FederatedClient) EvictPod(ctx context.Context, _, namespace, podName string, opts EvictOptions) error {
+	c.logOperation("evict", namespace, "pod", podName)
+	return evictPod(ctx, c.clientset, namespace, podName, opts, false)
+}
+
+// AddEphemeralContainer adds an ephemeral container to a running pod.
+// The kubeContext parameter is ignored (federated clients operate on a single cluster).
+func (c *FederatedClient) AddEphemeralContainer(ctx context.Context, _, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	c.logOperation("debug", namespace, "pod", podName)
+	return addEphemeralContainer(ctx, c.clientset, namespace, podName, container, false)
+}
+
 // ClusterManager implementation
 
 // GetAPIResources returns available API resources with pagination support.
@@ -227,6 +282,14 @@ func (c *FederatedClient) GetClusterHealth(ctx context.Context, _ string) (*Clus
 	return getClusterHealth(ctx, c.clientset, c.discoveryClient)
 }
 
+// ProxyGetService sends a GET request to a service through the API server's
+// service proxy.
+// The kubeContext parameter is ignored (federated clients operate on a single cluster).
+func (c *FederatedClient) ProxyGetService(ctx context.Context, _ string, namespace string, req ServiceProxyRequest) (*ServiceProxyResponse, error) {
+	c.logOperation("proxy-get", namespace, "service", req.Service)
+	return proxyGetService(ctx, c.clientset, namespace, req)
+}
+
 // logOperation logs a kubernetes operation for debugging.
 func (c *FederatedClient) logOperation(operation, namespace, resource, name string) {
 	slog.Debug("kubernetes operation (federated)",
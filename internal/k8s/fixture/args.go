@@ -0,0 +1,131 @@
+package fixture
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+// The argument maps below name the arguments of each k8s.Client method as
+// they appear in fixture files. Recorder and ReplayClient share them so a
+// recorded call and its replay build the same key.
+
+func contextArgs(kubeContext string) map[string]interface{} {
+	return map[string]interface{}{"kubeContext": kubeContext}
+}
+
+func switchContextArgs(contextName string) map[string]interface{} {
+	return map[string]interface{}{"contextName": contextName}
+}
+
+func resourceArgs(kubeContext, namespace, resourceType, apiGroup, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext":  kubeContext,
+		"namespace":    namespace,
+		"resourceType": resourceType,
+		"apiGroup":     apiGroup,
+		"name":         name,
+	}
+}
+
+func listArgs(kubeContext, namespace, resourceType, apiGroup string, opts k8s.ListOptions) map[string]interface{} {
+	args := resourceArgs(kubeContext, namespace, resourceType, apiGroup, "")
+	args["opts"] = opts
+	return args
+}
+
+func objectArgs(kubeContext, namespace string, obj runtime.Object) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext": kubeContext,
+		"namespace":   namespace,
+		"object":      objectArg(obj),
+	}
+}
+
+func patchArgs(kubeContext, namespace, resourceType, apiGroup, name string, patchType types.PatchType, data []byte) map[string]interface{} {
+	args := resourceArgs(kubeContext, namespace, resourceType, apiGroup, name)
+	args["patchType"] = string(patchType)
+	args["patch"] = string(data)
+	return args
+}
+
+func validateArgs(kubeContext, namespace string, obj runtime.Object, opts k8s.ValidateOptions) map[string]interface{} {
+	args := objectArgs(kubeContext, namespace, obj)
+	args["opts"] = opts
+	return args
+}
+
+func scaleArgs(kubeContext, namespace, resourceType, apiGroup, name string, replicas int32) map[string]interface{} {
+	args := resourceArgs(kubeContext, namespace, resourceType, apiGroup, name)
+	args["replicas"] = replicas
+	return args
+}
+
+func logsArgs(kubeContext, namespace, podName, containerName string, opts k8s.LogOptions) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext": kubeContext,
+		"namespace":   namespace,
+		"pod":         podName,
+		"container":   containerName,
+		"opts":        opts,
+	}
+}
+
+func execArgs(kubeContext, namespace, podName, containerName string, command []string, opts k8s.ExecOptions) map[string]interface{} {
+	args := map[string]interface{}{
+		"kubeContext": kubeContext,
+		"namespace":   namespace,
+		"pod":         podName,
+		"container":   containerName,
+		"command":     command,
+		"tty":         opts.TTY,
+	}
+	// Only recorded when set, so fixtures recorded before attach still match.
+	if opts.Attach {
+		args["attach"] = true
+	}
+	return args
+}
+
+func evictArgs(kubeContext, namespace, podName string, opts k8s.EvictOptions) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext": kubeContext,
+		"namespace":   namespace,
+		"pod":         podName,
+		"opts":        opts,
+	}
+}
+
+func ephemeralContainerArgs(kubeContext, namespace, podName string, container corev1.EphemeralContainer) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext": kubeContext,
+		"namespace":   namespace,
+		"pod":         podName,
+		"container":   container,
+	}
+}
+
+func apiResourcesArgs(kubeContext string, limit, offset int, apiGroup string, namespacedOnly bool, verbs []string) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext":    kubeContext,
+		"limit":          limit,
+		"offset":         offset,
+		"apiGroup":       apiGroup,
+		"namespacedOnly": namespacedOnly,
+		"verbs":          verbs,
+	}
+}
+
+func proxyArgs(kubeContext, namespace string, req k8s.ServiceProxyRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"kubeContext": kubeContext,
+		"namespace":   namespace,
+		"scheme":      req.Scheme,
+		"service":     req.Service,
+		"port":        req.Port,
+		"path":        req.Path,
+		"maxBytes":    req.MaxBytes,
+	}
+}
@@ -0,0 +1,37 @@
+// Package fixture records and replays Kubernetes client calls as JSON
+// fixtures, so the MCP server can run without a cluster.
+//
+// A Recorder wraps a live k8s.Client and writes every call it serves, with
+// its response or error, to a fixture directory. A ReplayClient implements
+// k8s.Client from such a directory: a call returns the recorded response for
+// the same method and arguments, or an error naming the missing fixture.
+// Replay is deterministic, which makes fixtures suitable for demos, MCP client
+// development and end-to-end tests.
+//
+// # Fixture Files
+//
+// A fixture file holds one entry or a JSON array of entries. Recorded files
+// are named after the method and a hash of the arguments, but any *.json file
+// in the directory tree is loaded, so fixtures can also be written by hand:
+//
+//	[
+//	  {
+//	    "method": "List",
+//	    "args": {"namespace": "default", "resourceType": "pods"},
+//	    "response": {"items": [{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web-0"}}], "totalItems": 1}
+//	  },
+//	  {
+//	    "method": "Get",
+//	    "args": {"namespace": "default", "resourceType": "pods", "name": "missing"},
+//	    "status": {"status": "Failure", "reason": "NotFound", "code": 404, "message": "pods \"missing\" not found"}
+//	  }
+//	]
+//
+// Arguments with zero values (empty strings, false, 0, empty lists) are
+// omitted from the match key, so they can be left out of hand-written
+// fixtures. API errors are stored as a metav1.Status and replayed as
+// *apierrors.StatusError, so callers that check for NotFound or Forbidden
+// behave as they would against a cluster.
+//
+// Port forwarding cannot be recorded and is not available during replay.
+package fixture
@@ -0,0 +1,254 @@
+package fixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Entry is one recorded client call.
+type Entry struct {
+	// Method is the k8s.Client method name, e.g. "List".
+	Method string `json:"method"`
+
+	// Args are the call arguments by name. Zero values are omitted.
+	Args map[string]interface{} `json:"args,omitempty"`
+
+	// Response is the JSON-encoded return value of a successful call.
+	Response json.RawMessage `json:"response,omitempty"`
+
+	// Status is set when the call failed with a Kubernetes API error.
+	Status *metav1.Status `json:"status,omitempty"`
+
+	// Error is set when the call failed with any other error.
+	Error string `json:"error,omitempty"`
+}
+
+// err returns the error recorded in the entry, if any.
+func (e *Entry) err() error {
+	if e.Status != nil {
+		return &apierrors.StatusError{ErrStatus: *e.Status}
+	}
+	if e.Error != "" {
+		return errors.New(e.Error)
+	}
+	return nil
+}
+
+// setErr records err in the entry.
+func (e *Entry) setErr(err error) {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		s := status.Status()
+		e.Status = &s
+		return
+	}
+	e.Error = err.Error()
+}
+
+// normalizeArgs converts args to their JSON form and drops zero values, so
+// arguments built from Go values and arguments read from a fixture file
+// compare equal.
+func normalizeArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode arguments: %w", err)
+	}
+	pruned, _ := prune(generic).(map[string]interface{})
+	return pruned, nil
+}
+
+// prune removes zero values from decoded JSON, recursively. It returns nil
+// when nothing is left.
+func prune(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if p := prune(item); p == nil {
+				delete(val, k)
+			} else {
+				val[k] = p
+			}
+		}
+		if len(val) == 0 {
+			return nil
+		}
+		return val
+	case []interface{}:
+		if len(val) == 0 {
+			return nil
+		}
+		// Keep list elements in place, even zero ones, so positions match.
+		return val
+	case string:
+		if val == "" {
+			return nil
+		}
+	case bool:
+		if !val {
+			return nil
+		}
+	case float64:
+		if val == 0 {
+			return nil
+		}
+	case nil:
+		return nil
+	}
+	return v
+}
+
+// callKey identifies a call by method and normalized arguments.
+func callKey(method string, args map[string]interface{}) (string, error) {
+	normalized, err := normalizeArgs(args)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	return method + " " + string(data), nil
+}
+
+// fileName returns the name a recorded entry is stored under.
+func fileName(method, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return strings.ToLower(method) + "-" + hex.EncodeToString(sum[:6]) + ".json"
+}
+
+// loadDir reads every *.json fixture file below dir, keyed by call. When two
+// entries share a key, the one in the file sorted last wins.
+func loadDir(dir string) (map[string]*Entry, error) {
+	entries := map[string]*Entry{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		loaded, err := readFile(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range loaded {
+			key, err := callKey(e.Method, e.Args)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			entries[key] = e
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures from %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+// readFile reads a fixture file holding one entry or an array of entries.
+func readFile(path string) ([]*Entry, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- fixture paths come from the operator
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var entries []*Entry
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &entries)
+	} else {
+		var e Entry
+		err = json.Unmarshal(data, &e)
+		entries = []*Entry{&e}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid fixture: %w", path, err)
+	}
+	for i, e := range entries {
+		if e.Method == "" {
+			return nil, fmt.Errorf("%s: entry %d has no method", path, i)
+		}
+	}
+	return entries, nil
+}
+
+// writeEntry stores e in dir under its call key, replacing an earlier
+// recording of the same call.
+func writeEntry(dir string, e *Entry) error {
+	key, err := callKey(e.Method, e.Args)
+	if err != nil {
+		return err
+	}
+	normalized, err := normalizeArgs(e.Args)
+	if err != nil {
+		return err
+	}
+	stored := *e
+	stored.Args = normalized
+
+	data, err := json.MarshalIndent([]*Entry{&stored}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".fixture-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, fileName(e.Method, key)))
+}
+
+// objectArg converts an object argument to its unstructured form, so typed
+// and unstructured objects record the same way.
+func objectArg(obj runtime.Object) interface{} {
+	if obj == nil {
+		return nil
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return obj
+	}
+	return content
+}
+
+// decodeObject decodes a recorded object as unstructured. It returns nil for
+// a JSON null.
+func decodeObject(raw json.RawMessage) (runtime.Object, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
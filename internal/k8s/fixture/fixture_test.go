@@ -0,0 +1,195 @@
+package fixture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+// liveClient stands in for a cluster. Methods that are not overridden panic.
+type liveClient struct {
+	k8s.Client
+	calls int
+}
+
+func (c *liveClient) Get(_ context.Context, _, namespace, resourceType, _, name string) (*k8s.GetResponse, error) {
+	c.calls++
+	if name == "missing" {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace(namespace)
+	pod.SetName(name)
+	return &k8s.GetResponse{Resource: pod, Meta: &k8s.ResponseMeta{ResourceScope: "namespaced"}}, nil
+}
+
+func (c *liveClient) List(_ context.Context, _, namespace, _, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	c.calls++
+	items := []runtime.Object{&corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: namespace, Labels: map[string]string{"app": opts.LabelSelector}},
+	}}
+	return &k8s.PaginatedListResponse{Items: items, TotalItems: 1, Continue: "next"}, nil
+}
+
+func (c *liveClient) Create(_ context.Context, _, _ string, obj runtime.Object) (runtime.Object, error) {
+	c.calls++
+	return obj, nil
+}
+
+func (c *liveClient) GetLogs(context.Context, string, string, string, string, k8s.LogOptions) (io.ReadCloser, error) {
+	c.calls++
+	return io.NopCloser(bytes.NewBufferString("line 1\nline 2\n")), nil
+}
+
+func (c *liveClient) Exec(context.Context, string, string, string, string, []string, k8s.ExecOptions) (*k8s.ExecResult, error) {
+	c.calls++
+	return nil, errors.New("container not running")
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	live := &liveClient{}
+	rec, err := NewRecorder(live, dir, nil)
+	require.NoError(t, err)
+
+	// Record.
+	_, err = rec.Get(ctx, "", "default", "pods", "", "web-0")
+	require.NoError(t, err)
+	_, err = rec.Get(ctx, "", "default", "pods", "", "missing")
+	require.Error(t, err)
+	_, err = rec.List(ctx, "", "default", "pods", "", k8s.ListOptions{LabelSelector: "web"})
+	require.NoError(t, err)
+	newPod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "default"},
+	}
+	_, err = rec.Create(ctx, "", "default", newPod)
+	require.NoError(t, err)
+	logs, err := rec.GetLogs(ctx, "", "default", "web-0", "app", k8s.LogOptions{})
+	require.NoError(t, err)
+	data, _ := io.ReadAll(logs)
+	assert.Equal(t, "line 1\nline 2\n", string(data), "recording must not consume the stream")
+	_, err = rec.Exec(ctx, "", "default", "web-0", "app", []string{"ls"}, k8s.ExecOptions{})
+	require.Error(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, files, 6)
+
+	// Replay without the live client.
+	replay, err := NewReplayClient(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 6, replay.Len())
+	callsBefore := live.calls
+
+	got, err := replay.Get(ctx, "", "default", "pods", "", "web-0")
+	require.NoError(t, err)
+	obj := got.Resource.(*unstructured.Unstructured)
+	assert.Equal(t, "web-0", obj.GetName())
+	assert.Equal(t, "namespaced", got.Meta.ResourceScope)
+
+	_, err = replay.Get(ctx, "", "default", "pods", "", "missing")
+	assert.True(t, apierrors.IsNotFound(err), "API errors must replay as status errors, got %v", err)
+
+	list, err := replay.List(ctx, "", "default", "pods", "", k8s.ListOptions{LabelSelector: "web"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "next", list.Continue)
+	assert.Equal(t, "web", list.Items[0].(*unstructured.Unstructured).GetLabels()["app"])
+
+	// A typed and an unstructured object with the same content match.
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newPod)
+	require.NoError(t, err)
+	created, err := replay.Create(ctx, "", "default", &unstructured.Unstructured{Object: content})
+	require.NoError(t, err)
+	assert.Equal(t, "debug", created.(*unstructured.Unstructured).GetName())
+
+	logs, err = replay.GetLogs(ctx, "", "default", "web-0", "app", k8s.LogOptions{})
+	require.NoError(t, err)
+	data, _ = io.ReadAll(logs)
+	assert.Equal(t, "line 1\nline 2\n", string(data))
+
+	_, err = replay.Exec(ctx, "", "default", "web-0", "app", []string{"ls"}, k8s.ExecOptions{})
+	assert.EqualError(t, err, "container not running")
+
+	assert.Equal(t, callsBefore, live.calls, "replay must not call the live client")
+}
+
+func TestReplayClient_Misses(t *testing.T) {
+	replay, err := NewReplayClient(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = replay.List(context.Background(), "", "default", "pods", "", k8s.ListOptions{})
+	assert.EqualError(t, err, `no fixture recorded for List {"namespace":"default","resourceType":"pods"}`)
+
+	_, err = replay.ListContexts(context.Background())
+	assert.EqualError(t, err, "no fixture recorded for ListContexts")
+
+	_, err = replay.PortForward(context.Background(), "", "default", "web-0", []string{"8080"}, k8s.PortForwardOptions{})
+	assert.ErrorIs(t, err, ErrPortForwardUnavailable)
+}
+
+func TestReplayClient_HandWrittenFixtures(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "pods.json"), []byte(`[
+  {
+    "method": "List",
+    "args": {"namespace": "default", "resourceType": "pods", "opts": {"limit": 0}},
+    "response": {"items": [{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web-0"}}], "totalItems": 1}
+  },
+  {
+    "method": "GetCurrentContext",
+    "response": {"name": "demo", "current": true}
+  }
+]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "health.json"), []byte(`{
+  "method": "GetClusterHealth",
+  "response": {"status": "Healthy"}
+}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o600))
+
+	replay, err := NewReplayClient(dir)
+	require.NoError(t, err)
+
+	list, err := replay.List(context.Background(), "", "default", "pods", "", k8s.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.TotalItems)
+
+	current, err := replay.GetCurrentContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "demo", current.Name)
+
+	health, err := replay.GetClusterHealth(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "Healthy", health.Status)
+}
+
+func TestNewReplayClient_InvalidFixture(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"args": {}}`), 0o600))
+	_, err := NewReplayClient(dir)
+	assert.ErrorContains(t, err, "has no method")
+
+	_, err = NewReplayClient(filepath.Join(dir, "does-not-exist"))
+	assert.Error(t, err)
+}
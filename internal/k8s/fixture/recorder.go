@@ -0,0 +1,222 @@
+package fixture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+// Recorder is a k8s.Client that forwards every call to a live client and
+// records it, with its result, as a fixture file. Recording failures are
+// logged and never fail the call.
+type Recorder struct {
+	client k8s.Client
+	dir    string
+	logger *slog.Logger
+}
+
+var _ k8s.Client = (*Recorder)(nil)
+
+// NewRecorder returns a Recorder writing fixtures for client to dir, which is
+// created if needed.
+func NewRecorder(client k8s.Client, dir string, logger *slog.Logger) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Recorder{client: client, dir: dir, logger: logger}, nil
+}
+
+// record writes a fixture for one call.
+func (r *Recorder) record(method string, args map[string]interface{}, response interface{}, callErr error) {
+	e := &Entry{Method: method, Args: args}
+	if callErr != nil {
+		e.setErr(callErr)
+	} else if response != nil {
+		data, err := json.Marshal(response)
+		if err != nil {
+			r.logger.Warn("failed to encode fixture response", "method", method, "error", err)
+			return
+		}
+		e.Response = data
+	}
+	if err := writeEntry(r.dir, e); err != nil {
+		r.logger.Warn("failed to write fixture", "method", method, "error", err)
+	}
+}
+
+// ListContexts implements k8s.Client.
+func (r *Recorder) ListContexts(ctx context.Context) ([]k8s.ContextInfo, error) {
+	resp, err := r.client.ListContexts(ctx)
+	r.record("ListContexts", nil, resp, err)
+	return resp, err
+}
+
+// GetCurrentContext implements k8s.Client.
+func (r *Recorder) GetCurrentContext(ctx context.Context) (*k8s.ContextInfo, error) {
+	resp, err := r.client.GetCurrentContext(ctx)
+	r.record("GetCurrentContext", nil, resp, err)
+	return resp, err
+}
+
+// SwitchContext implements k8s.Client.
+func (r *Recorder) SwitchContext(ctx context.Context, contextName string) error {
+	err := r.client.SwitchContext(ctx, contextName)
+	r.record("SwitchContext", switchContextArgs(contextName), nil, err)
+	return err
+}
+
+// Get implements k8s.Client.
+func (r *Recorder) Get(ctx context.Context, kubeContext, namespace, resourceType, apiGroup, name string) (*k8s.GetResponse, error) {
+	resp, err := r.client.Get(ctx, kubeContext, namespace, resourceType, apiGroup, name)
+	r.record("Get", resourceArgs(kubeContext, namespace, resourceType, apiGroup, name), resp, err)
+	return resp, err
+}
+
+// List implements k8s.Client.
+func (r *Recorder) List(ctx context.Context, kubeContext, namespace, resourceType, apiGroup string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	resp, err := r.client.List(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+	r.record("List", listArgs(kubeContext, namespace, resourceType, apiGroup, opts), resp, err)
+	return resp, err
+}
+
+// Describe implements k8s.Client.
+func (r *Recorder) Describe(ctx context.Context, kubeContext, namespace, resourceType, apiGroup, name string) (*k8s.ResourceDescription, error) {
+	resp, err := r.client.Describe(ctx, kubeContext, namespace, resourceType, apiGroup, name)
+	r.record("Describe", resourceArgs(kubeContext, namespace, resourceType, apiGroup, name), resp, err)
+	return resp, err
+}
+
+// Create implements k8s.Client.
+func (r *Recorder) Create(ctx context.Context, kubeContext, namespace string, obj runtime.Object) (runtime.Object, error) {
+	resp, err := r.client.Create(ctx, kubeContext, namespace, obj)
+	r.record("Create", objectArgs(kubeContext, namespace, obj), resp, err)
+	return resp, err
+}
+
+// Apply implements k8s.Client.
+func (r *Recorder) Apply(ctx context.Context, kubeContext, namespace string, obj runtime.Object) (runtime.Object, error) {
+	resp, err := r.client.Apply(ctx, kubeContext, namespace, obj)
+	r.record("Apply", objectArgs(kubeContext, namespace, obj), resp, err)
+	return resp, err
+}
+
+// Delete implements k8s.Client.
+func (r *Recorder) Delete(ctx context.Context, kubeContext, namespace, resourceType, apiGroup, name string) (*k8s.DeleteResponse, error) {
+	resp, err := r.client.Delete(ctx, kubeContext, namespace, resourceType, apiGroup, name)
+	r.record("Delete", resourceArgs(kubeContext, namespace, resourceType, apiGroup, name), resp, err)
+	return resp, err
+}
+
+// Patch implements k8s.Client.
+func (r *Recorder) Patch(ctx context.Context, kubeContext, namespace, resourceType, apiGroup, name string, patchType types.PatchType, data []byte) (*k8s.PatchResponse, error) {
+	resp, err := r.client.Patch(ctx, kubeContext, namespace, resourceType, apiGroup, name, patchType, data)
+	r.record("Patch", patchArgs(kubeContext, namespace, resourceType, apiGroup, name, patchType, data), resp, err)
+	return resp, err
+}
+
+// Scale implements k8s.Client.
+func (r *Recorder) Scale(ctx context.Context, kubeContext, namespace, resourceType, apiGroup, name string, replicas int32) (*k8s.ScaleResponse, error) {
+	resp, err := r.client.Scale(ctx, kubeContext, namespace, resourceType, apiGroup, name, replicas)
+	r.record("Scale", scaleArgs(kubeContext, namespace, resourceType, apiGroup, name, replicas), resp, err)
+	return resp, err
+}
+
+// Watch implements k8s.Client. Watches are passed through unrecorded.
+func (r *Recorder) Watch(ctx context.Context, kubeContext, namespace, resourceType, apiGroup string, opts k8s.WatchOptions) (watch.Interface, error) {
+	return r.client.Watch(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+}
+
+// Validate implements k8s.Client.
+func (r *Recorder) Validate(ctx context.Context, kubeContext, namespace string, obj runtime.Object, opts k8s.ValidateOptions) (*k8s.ValidateResponse, error) {
+	resp, err := r.client.Validate(ctx, kubeContext, namespace, obj, opts)
+	r.record("Validate", validateArgs(kubeContext, namespace, obj, opts), resp, err)
+	return resp, err
+}
+
+// GetLogs implements k8s.Client. The log stream is read to the end so it can
+// be recorded; followed streams never end and are passed through unrecorded.
+func (r *Recorder) GetLogs(ctx context.Context, kubeContext, namespace, podName, containerName string, opts k8s.LogOptions) (io.ReadCloser, error) {
+	stream, err := r.client.GetLogs(ctx, kubeContext, namespace, podName, containerName, opts)
+	if opts.Follow {
+		return stream, err
+	}
+	args := logsArgs(kubeContext, namespace, podName, containerName, opts)
+	if err != nil {
+		r.record("GetLogs", args, nil, err)
+		return nil, err
+	}
+	defer func() { _ = stream.Close() }()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	r.record("GetLogs", args, string(data), nil)
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Exec implements k8s.Client.
+func (r *Recorder) Exec(ctx context.Context, kubeContext, namespace, podName, containerName string, command []string, opts k8s.ExecOptions) (*k8s.ExecResult, error) {
+	resp, err := r.client.Exec(ctx, kubeContext, namespace, podName, containerName, command, opts)
+	r.record("Exec", execArgs(kubeContext, namespace, podName, containerName, command, opts), resp, err)
+	return resp, err
+}
+
+// PortForward implements k8s.Client. Sessions are passed through unrecorded.
+func (r *Recorder) PortForward(ctx context.Context, kubeContext, namespace, podName string, ports []string, opts k8s.PortForwardOptions) (*k8s.PortForwardSession, error) {
+	return r.client.PortForward(ctx, kubeContext, namespace, podName, ports, opts)
+}
+
+// PortForwardToService implements k8s.Client. Sessions are passed through
+// unrecorded.
+func (r *Recorder) PortForwardToService(ctx context.Context, kubeContext, namespace, serviceName string, ports []string, opts k8s.PortForwardOptions) (*k8s.PortForwardSession, error) {
+	return r.client.PortForwardToService(ctx, kubeContext, namespace, serviceName, ports, opts)
+}
+
+// EvictPod implements k8s.Client.
+func (r *Recorder) EvictPod(ctx context.Context, kubeContext, namespace, podName string, opts k8s.EvictOptions) error {
+	err := r.client.EvictPod(ctx, kubeContext, namespace, podName, opts)
+	r.record("EvictPod", evictArgs(kubeContext, namespace, podName, opts), nil, err)
+	return err
+}
+
+// AddEphemeralContainer implements k8s.Client.
+func (r *Recorder) AddEphemeralContainer(ctx context.Context, kubeContext, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	resp, err := r.client.AddEphemeralContainer(ctx, kubeContext, namespace, podName, container)
+	r.record("AddEphemeralContainer", ephemeralContainerArgs(kubeContext, namespace, podName, container), resp, err)
+	return resp, err
+}
+
+// GetAPIResources implements k8s.Client.
+func (r *Recorder) GetAPIResources(ctx context.Context, kubeContext string, limit, offset int, apiGroup string, namespacedOnly bool, verbs []string) (*k8s.PaginatedAPIResourceResponse, error) {
+	resp, err := r.client.GetAPIResources(ctx, kubeContext, limit, offset, apiGroup, namespacedOnly, verbs)
+	r.record("GetAPIResources", apiResourcesArgs(kubeContext, limit, offset, apiGroup, namespacedOnly, verbs), resp, err)
+	return resp, err
+}
+
+// GetClusterHealth implements k8s.Client.
+func (r *Recorder) GetClusterHealth(ctx context.Context, kubeContext string) (*k8s.ClusterHealth, error) {
+	resp, err := r.client.GetClusterHealth(ctx, kubeContext)
+	r.record("GetClusterHealth", contextArgs(kubeContext), resp, err)
+	return resp, err
+}
+
+// ProxyGetService implements k8s.Client.
+func (r *Recorder) ProxyGetService(ctx context.Context, kubeContext, namespace string, req k8s.ServiceProxyRequest) (*k8s.ServiceProxyResponse, error) {
+	resp, err := r.client.ProxyGetService(ctx, kubeContext, namespace, req)
+	r.record("ProxyGetService", proxyArgs(kubeContext, namespace, req), resp, err)
+	return resp, err
+}
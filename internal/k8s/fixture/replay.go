@@ -0,0 +1,326 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+// ErrPortForwardUnavailable is returned by ReplayClient for port forwarding,
+// which cannot be replayed.
+var ErrPortForwardUnavailable = errors.New("port forwarding is not available when serving from fixtures")
+
+// ErrWatchUnavailable is returned by ReplayClient for watches, which cannot
+// be replayed.
+var ErrWatchUnavailable = errors.New("watching is not available when serving from fixtures")
+
+// ReplayClient is a k8s.Client that answers every call from recorded
+// fixtures. It never contacts a cluster.
+type ReplayClient struct {
+	entries map[string]*Entry
+}
+
+var _ k8s.Client = (*ReplayClient)(nil)
+
+// NewReplayClient loads the fixtures below dir.
+func NewReplayClient(dir string) (*ReplayClient, error) {
+	entries, err := loadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayClient{entries: entries}, nil
+}
+
+// Len returns the number of recorded calls.
+func (c *ReplayClient) Len() int {
+	return len(c.entries)
+}
+
+// lookup returns the entry recorded for a call.
+func (c *ReplayClient) lookup(method string, args map[string]interface{}) (*Entry, error) {
+	key, err := callKey(method, args)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("no fixture recorded for %s", strings.TrimSuffix(key, " null"))
+	}
+	return e, nil
+}
+
+// replay decodes the response recorded for a call into out, or returns the
+// recorded error.
+func (c *ReplayClient) replay(method string, args map[string]interface{}, out interface{}) error {
+	e, err := c.lookup(method, args)
+	if err != nil {
+		return err
+	}
+	if err := e.err(); err != nil {
+		return err
+	}
+	if out == nil || len(e.Response) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(e.Response, out); err != nil {
+		return fmt.Errorf("invalid %s fixture response: %w", method, err)
+	}
+	return nil
+}
+
+// validateResponse is the recorded form of a k8s.ValidateResponse.
+type validateResponse struct {
+	Operation string          `json:"operation"`
+	Resource  json.RawMessage `json:"resource,omitempty"`
+	Warnings  []string        `json:"warnings,omitempty"`
+}
+
+// objectResponse is the recorded form of a response holding a runtime.Object.
+type objectResponse struct {
+	Resource json.RawMessage   `json:"resource"`
+	Events   json.RawMessage   `json:"events,omitempty"`
+	Metadata json.RawMessage   `json:"metadata,omitempty"`
+	Meta     *k8s.ResponseMeta `json:"_meta,omitempty"`
+}
+
+// ListContexts implements k8s.Client.
+func (c *ReplayClient) ListContexts(_ context.Context) ([]k8s.ContextInfo, error) {
+	var resp []k8s.ContextInfo
+	if err := c.replay("ListContexts", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetCurrentContext implements k8s.Client.
+func (c *ReplayClient) GetCurrentContext(_ context.Context) (*k8s.ContextInfo, error) {
+	resp := &k8s.ContextInfo{}
+	if err := c.replay("GetCurrentContext", nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SwitchContext implements k8s.Client.
+func (c *ReplayClient) SwitchContext(_ context.Context, contextName string) error {
+	return c.replay("SwitchContext", switchContextArgs(contextName), nil)
+}
+
+// Get implements k8s.Client.
+func (c *ReplayClient) Get(_ context.Context, kubeContext, namespace, resourceType, apiGroup, name string) (*k8s.GetResponse, error) {
+	var recorded objectResponse
+	if err := c.replay("Get", resourceArgs(kubeContext, namespace, resourceType, apiGroup, name), &recorded); err != nil {
+		return nil, err
+	}
+	obj, err := decodeObject(recorded.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Get fixture response: %w", err)
+	}
+	return &k8s.GetResponse{Resource: obj, Meta: recorded.Meta}, nil
+}
+
+// List implements k8s.Client.
+func (c *ReplayClient) List(_ context.Context, kubeContext, namespace, resourceType, apiGroup string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	var recorded struct {
+		k8s.PaginatedListResponse
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := c.replay("List", listArgs(kubeContext, namespace, resourceType, apiGroup, opts), &recorded); err != nil {
+		return nil, err
+	}
+	resp := recorded.PaginatedListResponse
+	resp.Items = make([]runtime.Object, 0, len(recorded.Items))
+	for _, raw := range recorded.Items {
+		obj, err := decodeObject(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid List fixture response: %w", err)
+		}
+		resp.Items = append(resp.Items, obj)
+	}
+	return &resp, nil
+}
+
+// Describe implements k8s.Client.
+func (c *ReplayClient) Describe(_ context.Context, kubeContext, namespace, resourceType, apiGroup, name string) (*k8s.ResourceDescription, error) {
+	var recorded objectResponse
+	if err := c.replay("Describe", resourceArgs(kubeContext, namespace, resourceType, apiGroup, name), &recorded); err != nil {
+		return nil, err
+	}
+	obj, err := decodeObject(recorded.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Describe fixture response: %w", err)
+	}
+	resp := &k8s.ResourceDescription{Resource: obj, Meta: recorded.Meta}
+	if len(recorded.Events) > 0 {
+		if err := json.Unmarshal(recorded.Events, &resp.Events); err != nil {
+			return nil, fmt.Errorf("invalid Describe fixture events: %w", err)
+		}
+	}
+	if len(recorded.Metadata) > 0 {
+		if err := json.Unmarshal(recorded.Metadata, &resp.Metadata); err != nil {
+			return nil, fmt.Errorf("invalid Describe fixture metadata: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// Create implements k8s.Client.
+func (c *ReplayClient) Create(_ context.Context, kubeContext, namespace string, obj runtime.Object) (runtime.Object, error) {
+	return c.replayObject("Create", objectArgs(kubeContext, namespace, obj))
+}
+
+// Apply implements k8s.Client.
+func (c *ReplayClient) Apply(_ context.Context, kubeContext, namespace string, obj runtime.Object) (runtime.Object, error) {
+	return c.replayObject("Apply", objectArgs(kubeContext, namespace, obj))
+}
+
+func (c *ReplayClient) replayObject(method string, args map[string]interface{}) (runtime.Object, error) {
+	var raw json.RawMessage
+	if err := c.replay(method, args, &raw); err != nil {
+		return nil, err
+	}
+	obj, err := decodeObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s fixture response: %w", method, err)
+	}
+	return obj, nil
+}
+
+// Delete implements k8s.Client.
+func (c *ReplayClient) Delete(_ context.Context, kubeContext, namespace, resourceType, apiGroup, name string) (*k8s.DeleteResponse, error) {
+	resp := &k8s.DeleteResponse{}
+	if err := c.replay("Delete", resourceArgs(kubeContext, namespace, resourceType, apiGroup, name), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Patch implements k8s.Client.
+func (c *ReplayClient) Patch(_ context.Context, kubeContext, namespace, resourceType, apiGroup, name string, patchType types.PatchType, data []byte) (*k8s.PatchResponse, error) {
+	var recorded objectResponse
+	if err := c.replay("Patch", patchArgs(kubeContext, namespace, resourceType, apiGroup, name, patchType, data), &recorded); err != nil {
+		return nil, err
+	}
+	obj, err := decodeObject(recorded.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Patch fixture response: %w", err)
+	}
+	return &k8s.PatchResponse{Resource: obj, Meta: recorded.Meta}, nil
+}
+
+// Scale implements k8s.Client.
+func (c *ReplayClient) Scale(_ context.Context, kubeContext, namespace, resourceType, apiGroup, name string, replicas int32) (*k8s.ScaleResponse, error) {
+	resp := &k8s.ScaleResponse{}
+	if err := c.replay("Scale", scaleArgs(kubeContext, namespace, resourceType, apiGroup, name, replicas), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Watch implements k8s.Client. It always fails.
+func (c *ReplayClient) Watch(context.Context, string, string, string, string, k8s.WatchOptions) (watch.Interface, error) {
+	return nil, ErrWatchUnavailable
+}
+
+// Validate implements k8s.Client.
+func (c *ReplayClient) Validate(_ context.Context, kubeContext, namespace string, obj runtime.Object, opts k8s.ValidateOptions) (*k8s.ValidateResponse, error) {
+	var recorded validateResponse
+	if err := c.replay("Validate", validateArgs(kubeContext, namespace, obj, opts), &recorded); err != nil {
+		return nil, err
+	}
+	resp := &k8s.ValidateResponse{Operation: recorded.Operation, Warnings: recorded.Warnings}
+	if len(recorded.Resource) > 0 {
+		resource, err := decodeObject(recorded.Resource)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Validate fixture response: %w", err)
+		}
+		resp.Resource = resource
+	}
+	return resp, nil
+}
+
+// GetLogs implements k8s.Client.
+func (c *ReplayClient) GetLogs(_ context.Context, kubeContext, namespace, podName, containerName string, opts k8s.LogOptions) (io.ReadCloser, error) {
+	var logs string
+	if err := c.replay("GetLogs", logsArgs(kubeContext, namespace, podName, containerName, opts), &logs); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+// Exec implements k8s.Client. The recorded output is also written to the
+// Stdout and Stderr writers, if set.
+func (c *ReplayClient) Exec(_ context.Context, kubeContext, namespace, podName, containerName string, command []string, opts k8s.ExecOptions) (*k8s.ExecResult, error) {
+	resp := &k8s.ExecResult{}
+	if err := c.replay("Exec", execArgs(kubeContext, namespace, podName, containerName, command, opts), resp); err != nil {
+		return nil, err
+	}
+	if opts.Stdout != nil {
+		_, _ = io.WriteString(opts.Stdout, resp.Stdout)
+	}
+	if opts.Stderr != nil {
+		_, _ = io.WriteString(opts.Stderr, resp.Stderr)
+	}
+	return resp, nil
+}
+
+// PortForward implements k8s.Client. It always fails.
+func (c *ReplayClient) PortForward(context.Context, string, string, string, []string, k8s.PortForwardOptions) (*k8s.PortForwardSession, error) {
+	return nil, ErrPortForwardUnavailable
+}
+
+// PortForwardToService implements k8s.Client. It always fails.
+func (c *ReplayClient) PortForwardToService(context.Context, string, string, string, []string, k8s.PortForwardOptions) (*k8s.PortForwardSession, error) {
+	return nil, ErrPortForwardUnavailable
+}
+
+// EvictPod implements k8s.Client.
+func (c *ReplayClient) EvictPod(_ context.Context, kubeContext, namespace, podName string, opts k8s.EvictOptions) error {
+	return c.replay("EvictPod", evictArgs(kubeContext, namespace, podName, opts), nil)
+}
+
+// AddEphemeralContainer implements k8s.Client.
+func (c *ReplayClient) AddEphemeralContainer(_ context.Context, kubeContext, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	resp := &corev1.Pod{}
+	if err := c.replay("AddEphemeralContainer", ephemeralContainerArgs(kubeContext, namespace, podName, container), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAPIResources implements k8s.Client.
+func (c *ReplayClient) GetAPIResources(_ context.Context, kubeContext string, limit, offset int, apiGroup string, namespacedOnly bool, verbs []string) (*k8s.PaginatedAPIResourceResponse, error) {
+	resp := &k8s.PaginatedAPIResourceResponse{}
+	if err := c.replay("GetAPIResources", apiResourcesArgs(kubeContext, limit, offset, apiGroup, namespacedOnly, verbs), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetClusterHealth implements k8s.Client.
+func (c *ReplayClient) GetClusterHealth(_ context.Context, kubeContext string) (*k8s.ClusterHealth, error) {
+	resp := &k8s.ClusterHealth{}
+	if err := c.replay("GetClusterHealth", contextArgs(kubeContext), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ProxyGetService implements k8s.Client.
+func (c *ReplayClient) ProxyGetService(_ context.Context, kubeContext, namespace string, req k8s.ServiceProxyRequest) (*k8s.ServiceProxyResponse, error) {
+	resp := &k8s.ServiceProxyResponse{}
+	if err := c.replay("ProxyGetService", proxyArgs(kubeContext, namespace, req), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
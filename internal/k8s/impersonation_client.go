@@ -9,8 +9,10 @@ import (
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -27,6 +29,10 @@ type InClusterImpersonationFactory struct {
 	qpsLimit             float32
 	burstLimit           int
 	timeout              time.Duration
+	retry                RetryConfig
+	userRateLimiter      *UserRateLimiter
+	impersonationExtras  *ImpersonationExtras
+	protobuf             bool
 	nonDestructiveMode   bool
 	dryRun               bool
 	allowedOperations    []string
@@ -73,6 +79,10 @@ func NewInClusterImpersonationFactory(config *ClientConfig) (*InClusterImpersona
 		qpsLimit:             qpsLimit,
 		burstLimit:           burstLimit,
 		timeout:              timeout,
+		retry:                config.Retry,
+		userRateLimiter:      config.UserRateLimiter,
+		impersonationExtras:  config.ImpersonationExtras,
+		protobuf:             config.Protobuf,
 		nonDestructiveMode:   config.NonDestructiveMode,
 		dryRun:               config.DryRun,
 		allowedOperations:    config.AllowedOperations,
@@ -109,10 +119,14 @@ func (f *InClusterImpersonationFactory) CreateImpersonationClient(identity Imper
 			Groups:   identity.Groups,
 		},
 	}
+	f.userRateLimiter.ApplyTo(cfg)
+	f.impersonationExtras.ApplyTo(cfg)
 
 	client := &impersonationClient{
 		restConfig:           cfg,
 		namespace:            f.namespace,
+		retry:                f.retry,
+		protobuf:             f.protobuf,
 		nonDestructiveMode:   f.nonDestructiveMode,
 		dryRun:               f.dryRun,
 		allowedOperations:    f.allowedOperations,
@@ -128,6 +142,8 @@ func (f *InClusterImpersonationFactory) CreateImpersonationClient(identity Imper
 type impersonationClient struct {
 	restConfig *rest.Config
 	namespace  string
+	retry      RetryConfig
+	protobuf   bool
 
 	clientsetLazy       lazyValue[kubernetes.Interface]
 	dynamicClientLazy   lazyValue[dynamic.Interface]
@@ -156,6 +172,13 @@ func (c *impersonationClient) getDynamicClient() (dynamic.Interface, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 		}
+		if c.protobuf {
+			cs, err := newProtobufClientset(c.restConfig)
+			if err != nil {
+				return nil, err
+			}
+			return withProtobuf(dc, cs), nil
+		}
 		return dc, nil
 	})
 }
@@ -166,7 +189,7 @@ func (c *impersonationClient) getDiscoveryClient() (discovery.DiscoveryInterface
 		if err != nil {
 			return nil, fmt.Errorf("failed to create discovery client: %w", err)
 		}
-		return dc, nil
+		return newThrottledDiscoveryClient(userDiscoveryKey("host:"+c.restConfig.Host, c.restConfig.Impersonate.UserName, c.restConfig.Impersonate.Groups), dc), nil
 	})
 }
 
@@ -178,7 +201,7 @@ func (c *impersonationClient) isOperationAllowed(operation string) error {
 		return nil
 	}
 	if c.nonDestructiveMode {
-		if slices.Contains([]string{"delete", "patch", "scale", "create", "apply"}, operation) && !c.dryRun {
+		if slices.Contains([]string{"delete", "patch", "scale", "create", "apply", "evict"}, operation) && !c.dryRun {
 			return fmt.Errorf("destructive operation %q is not allowed in non-destructive mode", operation)
 		}
 	}
@@ -239,7 +262,9 @@ func (c *impersonationClient) Get(ctx context.Context, _, namespace, resourceTyp
 	if err != nil {
 		return nil, err
 	}
-	return getResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name)
+	return retryRead(ctx, c.retry, "get", func() (*GetResponse, error) {
+		return getResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name)
+	})
 }
 
 func (c *impersonationClient) List(ctx context.Context, _, namespace, resourceType, apiGroup string, opts ListOptions) (*PaginatedListResponse, error) {
@@ -256,7 +281,9 @@ func (c *impersonationClient) List(ctx context.Context, _, namespace, resourceTy
 	if err != nil {
 		return nil, err
 	}
-	return listResources(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, opts)
+	return retryRead(ctx, c.retry, "list", func() (*PaginatedListResponse, error) {
+		return listResources(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, opts)
+	})
 }
 
 func (c *impersonationClient) Describe(ctx context.Context, _, namespace, resourceType, apiGroup, name string) (*ResourceDescription, error) {
@@ -275,7 +302,9 @@ func (c *impersonationClient) Describe(ctx context.Context, _, namespace, resour
 	if err != nil {
 		return nil, err
 	}
-	return describeResource(ctx, dynamicClient, discoveryClient, clientset, namespace, resourceType, apiGroup, name)
+	return retryRead(ctx, c.retry, "describe", func() (*ResourceDescription, error) {
+		return describeResource(ctx, dynamicClient, discoveryClient, clientset, namespace, resourceType, apiGroup, name)
+	})
 }
 
 func (c *impersonationClient) Create(ctx context.Context, _, namespace string, obj runtime.Object) (runtime.Object, error) {
@@ -293,7 +322,7 @@ func (c *impersonationClient) Create(ctx context.Context, _, namespace string, o
 	if err != nil {
 		return nil, err
 	}
-	return createResource(ctx, dynamicClient, discoveryClient, namespace, obj, c.dryRun)
+	return createResource(ctx, dynamicClient, discoveryClient, namespace, obj, isDryRun(ctx, c.dryRun))
 }
 
 func (c *impersonationClient) Apply(ctx context.Context, _, namespace string, obj runtime.Object) (runtime.Object, error) {
@@ -311,7 +340,7 @@ func (c *impersonationClient) Apply(ctx context.Context, _, namespace string, ob
 	if err != nil {
 		return nil, err
 	}
-	return applyResource(ctx, dynamicClient, discoveryClient, namespace, obj, c.dryRun)
+	return applyResource(ctx, dynamicClient, discoveryClient, namespace, obj, isDryRun(ctx, c.dryRun))
 }
 
 func (c *impersonationClient) Delete(ctx context.Context, _, namespace, resourceType, apiGroup, name string) (*DeleteResponse, error) {
@@ -329,7 +358,7 @@ func (c *impersonationClient) Delete(ctx context.Context, _, namespace, resource
 	if err != nil {
 		return nil, err
 	}
-	return deleteResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, c.dryRun)
+	return deleteResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, isDryRun(ctx, c.dryRun))
 }
 
 func (c *impersonationClient) Patch(ctx context.Context, _, namespace, resourceType, apiGroup, name string, patchType types.PatchType, data []byte) (*PatchResponse, error) {
@@ -347,7 +376,7 @@ func (c *impersonationClient) Patch(ctx context.Context, _, namespace, resourceT
 	if err != nil {
 		return nil, err
 	}
-	return patchResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, patchType, data, c.dryRun)
+	return patchResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, patchType, data, isDryRun(ctx, c.dryRun))
 }
 
 func (c *impersonationClient) Scale(ctx context.Context, _, namespace, resourceType, apiGroup, name string, replicas int32) (*ScaleResponse, error) {
@@ -365,7 +394,39 @@ func (c *impersonationClient) Scale(ctx context.Context, _, namespace, resourceT
 	if err != nil {
 		return nil, err
 	}
-	return scaleResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, replicas, c.dryRun)
+	return scaleResource(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, name, replicas, isDryRun(ctx, c.dryRun))
+}
+
+func (c *impersonationClient) Watch(ctx context.Context, _, namespace, resourceType, apiGroup string, opts WatchOptions) (watch.Interface, error) {
+	if namespace != "" {
+		if err := c.isNamespaceRestricted(namespace); err != nil {
+			return nil, err
+		}
+	}
+	dynamicClient, err := c.getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := c.getDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return watchResources(ctx, dynamicClient, discoveryClient, namespace, resourceType, apiGroup, opts)
+}
+
+func (c *impersonationClient) Validate(ctx context.Context, _, namespace string, obj runtime.Object, opts ValidateOptions) (*ValidateResponse, error) {
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+	dynamicClient, err := c.getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := c.getDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return validateResource(ctx, dynamicClient, discoveryClient, namespace, obj, opts)
 }
 
 // ========== PodManager ==========
@@ -414,6 +475,34 @@ func (c *impersonationClient) PortForwardToService(ctx context.Context, _, names
 	return portForwardToService(ctx, clientset, c.restConfig, namespace, serviceName, ports, opts)
 }
 
+func (c *impersonationClient) EvictPod(ctx context.Context, _, namespace, podName string, opts EvictOptions) error {
+	if err := c.isOperationAllowed("evict"); err != nil {
+		return err
+	}
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return err
+	}
+	clientset, err := c.getClientset()
+	if err != nil {
+		return err
+	}
+	return evictPod(ctx, clientset, namespace, podName, opts, isDryRun(ctx, c.dryRun))
+}
+
+func (c *impersonationClient) AddEphemeralContainer(ctx context.Context, _, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	if err := c.isOperationAllowed("debug"); err != nil {
+		return nil, err
+	}
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+	clientset, err := c.getClientset()
+	if err != nil {
+		return nil, err
+	}
+	return addEphemeralContainer(ctx, clientset, namespace, podName, container, isDryRun(ctx, c.dryRun))
+}
+
 // ========== ClusterManager ==========
 
 func (c *impersonationClient) GetAPIResources(ctx context.Context, _ string, limit, offset int, apiGroup string, namespacedOnly bool, verbs []string) (*PaginatedAPIResourceResponse, error) {
@@ -421,7 +510,17 @@ func (c *impersonationClient) GetAPIResources(ctx context.Context, _ string, lim
 	if err != nil {
 		return nil, err
 	}
-	return getAPIResources(ctx, discoveryClient, limit, offset, apiGroup, namespacedOnly, verbs)
+	return retryRead(ctx, c.retry, "api-resources", func() (*PaginatedAPIResourceResponse, error) {
+		return getAPIResources(ctx, discoveryClient, limit, offset, apiGroup, namespacedOnly, verbs)
+	})
+}
+
+func (c *impersonationClient) ProxyGetService(ctx context.Context, _ string, namespace string, req ServiceProxyRequest) (*ServiceProxyResponse, error) {
+	clientset, err := c.getClientset()
+	if err != nil {
+		return nil, err
+	}
+	return proxyGetService(ctx, clientset, namespace, req)
 }
 
 func (c *impersonationClient) GetClusterHealth(ctx context.Context, _ string) (*ClusterHealth, error) {
@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// MaxImpersonationExtraValueLength bounds each extra value sent in an
+// Impersonate-Extra-* header. Longer values are truncated.
+const MaxImpersonationExtraValueLength = 256
+
+// reservedImpersonationExtraKeys are extras set by mcp-kubernetes itself.
+// They cannot be configured, so that the audit trail they provide cannot be
+// forged.
+var reservedImpersonationExtraKeys = map[string]struct{}{
+	"agent":                             {},
+	"trace-id":                          {},
+	"mcp.giantswarm.io/original-groups": {},
+}
+
+// impersonationExtrasKey is the context key for per-request impersonation extras.
+type impersonationExtrasKey struct{}
+
+// ContextWithImpersonationExtras returns a context whose Kubernetes API
+// requests carry the given extras as Impersonate-Extra-* headers, on clients
+// that impersonate and have ImpersonationExtras applied. Extras already in
+// ctx are kept unless extras sets the same key. Invalid and reserved keys are
+// dropped.
+func ContextWithImpersonationExtras(ctx context.Context, extras map[string][]string) context.Context {
+	merged := make(map[string][]string)
+	for k, v := range ImpersonationExtrasFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range extras {
+		if ValidateImpersonationExtraKey(k) != nil {
+			continue
+		}
+		merged[k] = sanitizeImpersonationExtraValues(v)
+	}
+	if len(merged) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, impersonationExtrasKey{}, merged)
+}
+
+// ImpersonationExtrasFromContext returns the extras set by
+// ContextWithImpersonationExtras, or nil if there are none.
+func ImpersonationExtrasFromContext(ctx context.Context) map[string][]string {
+	extras, _ := ctx.Value(impersonationExtrasKey{}).(map[string][]string)
+	return extras
+}
+
+// ValidateImpersonationExtraKey checks that key can be used as an
+// impersonation extra. Keys must be lowercase, as the API server lowercases
+// header names, and must not be one of the extras mcp-kubernetes sets itself.
+func ValidateImpersonationExtraKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("impersonation extra key must not be empty")
+	}
+	if key != strings.ToLower(key) {
+		return fmt.Errorf("impersonation extra key %q must be lowercase", key)
+	}
+	for _, r := range key {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return fmt.Errorf("impersonation extra key %q contains whitespace or control characters", key)
+		}
+	}
+	if _, reserved := reservedImpersonationExtraKeys[key]; reserved {
+		return fmt.Errorf("impersonation extra key %q is reserved", key)
+	}
+	return nil
+}
+
+// sanitizeImpersonationExtraValues drops control characters, which are not
+// allowed in header values, and truncates long values.
+func sanitizeImpersonationExtraValues(values []string) []string {
+	sanitized := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, v)
+		if len(v) > MaxImpersonationExtraValueLength {
+			v = strings.ToValidUTF8(v[:MaxImpersonationExtraValueLength], "")
+		}
+		if v != "" {
+			sanitized = append(sanitized, v)
+		}
+	}
+	return sanitized
+}
+
+// ImpersonationExtras adds operator-configured extras, and the per-request
+// extras of ContextWithImpersonationExtras, to the impersonation headers of
+// Kubernetes clients. They complement the extras the client is configured
+// with, such as the agent and trace ID, and never replace them: a key that a
+// request already carries is left as is.
+//
+// Requests that do not impersonate are not changed.
+type ImpersonationExtras struct {
+	static map[string][]string
+}
+
+// NewImpersonationExtras validates the static extras sent with every
+// impersonated request.
+func NewImpersonationExtras(static map[string]string) (*ImpersonationExtras, error) {
+	extras := &ImpersonationExtras{static: make(map[string][]string, len(static))}
+	for k, v := range static {
+		if err := ValidateImpersonationExtraKey(k); err != nil {
+			return nil, err
+		}
+		values := sanitizeImpersonationExtraValues([]string{v})
+		if len(values) == 0 {
+			return nil, fmt.Errorf("impersonation extra %q has an empty value", k)
+		}
+		extras.static[k] = values
+	}
+	return extras, nil
+}
+
+// ApplyTo makes config's clients send the extras. It must be called before
+// clients are created from config.
+func (e *ImpersonationExtras) ApplyTo(config *rest.Config) {
+	if e == nil || config == nil {
+		return
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonationExtrasRoundTripper{static: e.static, delegate: rt}
+	})
+}
+
+// impersonationExtrasRoundTripper runs inside client-go's impersonating
+// round tripper, so the configured Impersonate-* headers are already set.
+type impersonationExtrasRoundTripper struct {
+	static   map[string][]string
+	delegate http.RoundTripper
+}
+
+func (rt *impersonationExtrasRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(transport.ImpersonateUserHeader) == "" {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	perRequest := ImpersonationExtrasFromContext(req.Context())
+	if len(rt.static) == 0 && len(perRequest) == 0 {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	// Static extras go first so a request cannot replace them.
+	for _, extras := range []map[string][]string{rt.static, perRequest} {
+		for k, values := range extras {
+			header := transport.ImpersonateUserExtraHeaderPrefix + impersonationExtraHeaderKey(k)
+			if len(req.Header.Values(header)) > 0 {
+				continue
+			}
+			for _, v := range values {
+				req.Header.Add(header, v)
+			}
+		}
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+// impersonationExtraHeaderKey percent-encodes the bytes of key that are not
+// allowed in header names, as client-go does for configured extras.
+func impersonationExtraHeaderKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '%' || !isHeaderTokenByte(c) {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// isHeaderTokenByte reports whether c is an RFC 7230 token character.
+func isHeaderTokenByte(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&'*+-.^_`|~", c) >= 0
+}
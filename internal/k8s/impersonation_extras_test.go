@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+// doImpersonatedRequest sends a request through a client built from config
+// and returns the headers the API server received.
+func doImpersonatedRequest(t *testing.T, ctx context.Context, impersonate rest.ImpersonationConfig, extras *ImpersonationExtras) http.Header {
+	t.Helper()
+	received := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	t.Cleanup(srv.Close)
+
+	config := &rest.Config{Host: srv.URL, Impersonate: impersonate}
+	extras.ApplyTo(config)
+	client, err := rest.HTTPClientFor(config)
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return <-received
+}
+
+func TestImpersonationExtras(t *testing.T) {
+	extras, err := NewImpersonationExtras(map[string]string{"team": "platform", "example.com/env": "prod"})
+	require.NoError(t, err)
+
+	impersonate := rest.ImpersonationConfig{
+		UserName: "jane@example.com",
+		Extra:    map[string][]string{"agent": {"mcp-kubernetes"}},
+	}
+
+	t.Run("static and per-request extras are added", func(t *testing.T) {
+		ctx := ContextWithImpersonationExtras(context.Background(), map[string][]string{
+			"ticket-id": {"OPS-42"},
+			"team":      {"spoofed"},
+		})
+		header := doImpersonatedRequest(t, ctx, impersonate, extras)
+
+		assert.Equal(t, "jane@example.com", header.Get("Impersonate-User"))
+		assert.Equal(t, []string{"mcp-kubernetes"}, header.Values("Impersonate-Extra-Agent"))
+		assert.Equal(t, []string{"platform"}, header.Values("Impersonate-Extra-Team"), "requests cannot replace static extras")
+		assert.Equal(t, []string{"prod"}, header.Values("Impersonate-Extra-Example.com%2Fenv"))
+		assert.Equal(t, []string{"OPS-42"}, header.Values("Impersonate-Extra-Ticket-Id"))
+	})
+
+	t.Run("reserved keys from the request are dropped", func(t *testing.T) {
+		ctx := ContextWithImpersonationExtras(context.Background(), map[string][]string{"agent": {"other"}})
+		assert.Nil(t, ImpersonationExtrasFromContext(ctx))
+
+		noAgent := rest.ImpersonationConfig{UserName: "jane@example.com"}
+		header := doImpersonatedRequest(t, ctx, noAgent, extras)
+		assert.Empty(t, header.Values("Impersonate-Extra-Agent"))
+	})
+
+	t.Run("requests that do not impersonate are unchanged", func(t *testing.T) {
+		ctx := ContextWithImpersonationExtras(context.Background(), map[string][]string{"ticket-id": {"OPS-42"}})
+		header := doImpersonatedRequest(t, ctx, rest.ImpersonationConfig{}, extras)
+
+		for name := range header {
+			assert.False(t, strings.HasPrefix(name, "Impersonate-"), "unexpected header %s", name)
+		}
+	})
+}
+
+func TestNewImpersonationExtras_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		static  map[string]string
+		wantErr string
+	}{
+		{name: "reserved key", static: map[string]string{"trace-id": "x"}, wantErr: "reserved"},
+		{name: "uppercase key", static: map[string]string{"Team": "x"}, wantErr: "lowercase"},
+		{name: "key with space", static: map[string]string{"my team": "x"}, wantErr: "whitespace"},
+		{name: "empty value", static: map[string]string{"team": "\n"}, wantErr: "empty value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewImpersonationExtras(tt.static)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestContextWithImpersonationExtras(t *testing.T) {
+	ctx := ContextWithImpersonationExtras(context.Background(), map[string][]string{
+		"a": {"1"},
+		"b": {"line\nbreak", strings.Repeat("x", MaxImpersonationExtraValueLength+10)},
+	})
+	ctx = ContextWithImpersonationExtras(ctx, map[string][]string{"a": {"2"}})
+
+	extras := ImpersonationExtrasFromContext(ctx)
+	assert.Equal(t, []string{"2"}, extras["a"])
+	require.Len(t, extras["b"], 2)
+	assert.Equal(t, "linebreak", extras["b"][0])
+	assert.Len(t, extras["b"][1], MaxImpersonationExtraValueLength)
+
+	assert.Equal(t, context.Background(), ContextWithImpersonationExtras(context.Background(), nil))
+}
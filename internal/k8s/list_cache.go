@@ -0,0 +1,313 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Default list cache settings, used when ListCacheConfig leaves them unset.
+const (
+	DefaultListCacheResync      = 10 * time.Minute
+	DefaultListCacheSyncTimeout = 10 * time.Second
+)
+
+// listCacheContinuePrefix marks continue tokens issued by the list cache,
+// so they are never sent to the API server.
+const listCacheContinuePrefix = "mcp-cache:"
+
+// ListCacheConfig configures a ListCache.
+type ListCacheConfig struct {
+	// Resources are the resource types served from the cache, by plural
+	// name ("pods") or plural name and group ("deployments.apps").
+	Resources []string
+
+	// Resync is the informers' resync period. Defaults to
+	// DefaultListCacheResync.
+	Resync time.Duration
+
+	// SyncTimeout bounds how long the first list of a resource type waits
+	// for its informer to sync before it falls back to the API server.
+	// Defaults to DefaultListCacheSyncTimeout.
+	SyncTimeout time.Duration
+}
+
+// ListCache serves list calls for selected resource types from informers,
+// one per cluster and resource type, started on the first list. Lists the
+// cache cannot answer, such as those with a field selector or an API
+// server continue token, go to the API server as usual.
+//
+// Informers watch with the credentials of the client that started them, so
+// a ListCache is only used by the server's own client and never by per-user
+// clients, whose RBAC it would bypass.
+type ListCache struct {
+	resources   map[string]bool
+	resync      time.Duration
+	syncTimeout time.Duration
+
+	mu        sync.Mutex
+	informers map[string]*cachedInformer
+	stop      chan struct{}
+	closed    bool
+}
+
+// cachedInformer is the informer of one resource type in one cluster.
+type cachedInformer struct {
+	informer cache.SharedIndexInformer
+
+	mu       sync.Mutex
+	updated  time.Time
+	watchErr error
+}
+
+// NewListCache creates a list cache for cfg.Resources. It returns nil, which
+// disables caching, when no resources are given.
+func NewListCache(cfg ListCacheConfig) *ListCache {
+	if len(cfg.Resources) == 0 {
+		return nil
+	}
+	resources := make(map[string]bool, len(cfg.Resources))
+	for _, r := range cfg.Resources {
+		if r = strings.ToLower(strings.TrimSpace(r)); r != "" {
+			resources[r] = true
+		}
+	}
+	resync := cfg.Resync
+	if resync <= 0 {
+		resync = DefaultListCacheResync
+	}
+	syncTimeout := cfg.SyncTimeout
+	if syncTimeout <= 0 {
+		syncTimeout = DefaultListCacheSyncTimeout
+	}
+	return &ListCache{
+		resources:   resources,
+		resync:      resync,
+		syncTimeout: syncTimeout,
+		informers:   make(map[string]*cachedInformer),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Close stops all informers.
+func (lc *ListCache) Close() {
+	if lc == nil {
+		return
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if !lc.closed {
+		close(lc.stop)
+		lc.closed = true
+	}
+}
+
+// caches reports whether lists of gvr are served from the cache.
+func (lc *ListCache) caches(gvr schema.GroupVersionResource) bool {
+	if lc == nil {
+		return false
+	}
+	if lc.resources[gvr.Resource] {
+		return true
+	}
+	return gvr.Group != "" && lc.resources[gvr.Resource+"."+gvr.Group]
+}
+
+// list answers a list call from the cache. It reports false when the call
+// has to go to the API server instead. namespace is the effective
+// namespace, empty for all namespaces and cluster-scoped resources.
+func (lc *ListCache) list(ctx context.Context, kubeContext string, client dynamic.Interface,
+	gvr schema.GroupVersionResource, namespace string, opts ListOptions) (*PaginatedListResponse, bool, error) {
+
+	fromCache := strings.HasPrefix(opts.Continue, listCacheContinuePrefix)
+	if !lc.caches(gvr) || opts.FieldSelector != "" || (opts.Continue != "" && !fromCache) {
+		if fromCache {
+			return nil, false, apierrors.NewResourceExpired("the continue token is no longer valid; list again without it")
+		}
+		return nil, false, nil
+	}
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		// Let the API server report the invalid selector.
+		return nil, false, nil
+	}
+
+	ci := lc.informer(kubeContext, client, gvr)
+	if ci == nil || !lc.waitForSync(ctx, ci) {
+		if fromCache {
+			return nil, false, apierrors.NewResourceExpired("the continue token is no longer valid; list again without it")
+		}
+		return nil, false, nil
+	}
+
+	var after string
+	if fromCache {
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(opts.Continue, listCacheContinuePrefix))
+		if err != nil {
+			return nil, false, apierrors.NewBadRequest("invalid continue token")
+		}
+		after = string(decoded)
+	}
+
+	var objs []interface{}
+	if namespace != "" {
+		objs, err = ci.informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		if err != nil {
+			return nil, false, nil
+		}
+	} else {
+		objs = ci.informer.GetStore().List()
+	}
+
+	type keyed struct {
+		key string
+		obj *unstructured.Unstructured
+	}
+	matches := make([]keyed, 0, len(objs))
+	for _, o := range objs {
+		u, ok := o.(*unstructured.Unstructured)
+		if !ok || !selector.Matches(labels.Set(u.GetLabels())) {
+			continue
+		}
+		key := u.GetNamespace() + "/" + u.GetName()
+		if after != "" && key <= after {
+			continue
+		}
+		matches = append(matches, keyed{key, u})
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].key < matches[j].key })
+
+	page := matches
+	if opts.Limit > 0 && int64(len(matches)) > opts.Limit {
+		page = matches[:opts.Limit]
+	}
+	items := make([]runtime.Object, 0, len(page))
+	for _, m := range page {
+		// Callers shape and redact the items; the cached objects are shared.
+		items = append(items, m.obj.DeepCopy())
+	}
+
+	rv := ci.informer.LastSyncResourceVersion()
+	response := &PaginatedListResponse{
+		Items:           items,
+		ResourceVersion: rv,
+		TotalItems:      len(items),
+	}
+	if remaining := int64(len(matches) - len(page)); remaining > 0 {
+		response.Continue = listCacheContinuePrefix + base64.RawURLEncoding.EncodeToString([]byte(page[len(page)-1].key))
+		response.RemainingItems = &remaining
+	}
+	response.Meta = &ResponseMeta{ListCache: &ListCacheMeta{
+		ResourceVersion: rv,
+		UpdatedAt:       ci.lastUpdate(),
+	}}
+	return response, true, nil
+}
+
+// informer returns the informer of gvr in kubeContext, starting it on first
+// use. Returns nil once the cache is closed.
+func (lc *ListCache) informer(kubeContext string, client dynamic.Interface, gvr schema.GroupVersionResource) *cachedInformer {
+	key := kubeContext + "|" + gvr.String()
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.closed {
+		return nil
+	}
+	if ci, ok := lc.informers[key]; ok {
+		return ci
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, gvr, metav1.NamespaceAll, lc.resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil).Informer()
+	ci := &cachedInformer{informer: informer}
+	_ = informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		ci.setWatchErr(err)
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+	})
+	_, _ = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { ci.touch() },
+		UpdateFunc: func(any, any) { ci.touch() },
+		DeleteFunc: func(any) { ci.touch() },
+	})
+	lc.informers[key] = ci
+	go informer.Run(lc.stop)
+	return ci
+}
+
+// waitForSync waits until ci has synced, reporting false if ctx is done or
+// the sync timeout passes first, or if the informer's last list or watch
+// failed: a cache that has lost its watch is not served.
+func (lc *ListCache) waitForSync(ctx context.Context, ci *cachedInformer) bool {
+	timeout := time.NewTimer(lc.syncTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if ci.failing() {
+			return false
+		}
+		if ci.informer.HasSynced() {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timeout.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ci *cachedInformer) touch() {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	ci.updated = time.Now()
+	ci.watchErr = nil
+}
+
+func (ci *cachedInformer) setWatchErr(err error) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	ci.watchErr = err
+}
+
+// failing reports whether the informer's last list or watch failed and it
+// has not heard from the API server since.
+func (ci *cachedInformer) failing() bool {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	return ci.watchErr != nil
+}
+
+// lastUpdate returns when the cache last heard from the API server.
+func (ci *cachedInformer) lastUpdate() time.Time {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	return ci.updated
+}
+
+// ListCacheMeta tells that a list was served from the list cache and how
+// fresh the cache is.
+type ListCacheMeta struct {
+	// ResourceVersion is the latest resourceVersion the cache has seen.
+	ResourceVersion string `json:"resourceVersion"`
+
+	// UpdatedAt is when the cache last heard from the API server: the last
+	// change to the resource type, or the last resync.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
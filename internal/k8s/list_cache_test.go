@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+func testPod(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace(namespace)
+	pod.SetName(name)
+	pod.SetLabels(labels)
+	return pod
+}
+
+func newListCacheTestClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList"}, objects...)
+}
+
+func itemNames(items []runtime.Object) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.(*unstructured.Unstructured).GetName())
+	}
+	return names
+}
+
+func TestListCache(t *testing.T) {
+	client := newListCacheTestClient(
+		testPod("default", "web-1", map[string]string{"app": "web"}),
+		testPod("default", "web-0", map[string]string{"app": "web"}),
+		testPod("default", "db-0", map[string]string{"app": "db"}),
+		testPod("kube-system", "dns-0", nil),
+	)
+	lc := NewListCache(ListCacheConfig{Resources: []string{"pods"}})
+	defer lc.Close()
+	ctx := context.Background()
+
+	t.Run("namespace and label selector", func(t *testing.T) {
+		resp, ok, err := lc.list(ctx, "", client, podsGVR, "default", ListOptions{LabelSelector: "app=web"})
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{"web-0", "web-1"}, itemNames(resp.Items))
+		require.NotNil(t, resp.Meta.ListCache)
+		assert.Empty(t, resp.Continue)
+	})
+
+	t.Run("pages with cache continue tokens", func(t *testing.T) {
+		resp, ok, err := lc.list(ctx, "", client, podsGVR, "", ListOptions{Limit: 3})
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{"db-0", "web-0", "web-1"}, itemNames(resp.Items))
+		require.NotNil(t, resp.RemainingItems)
+		assert.Equal(t, int64(1), *resp.RemainingItems)
+		assert.Contains(t, resp.Continue, listCacheContinuePrefix)
+
+		resp, ok, err = lc.list(ctx, "", client, podsGVR, "", ListOptions{Limit: 3, Continue: resp.Continue})
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{"dns-0"}, itemNames(resp.Items))
+		assert.Empty(t, resp.Continue)
+	})
+
+	t.Run("returns copies", func(t *testing.T) {
+		resp, _, err := lc.list(ctx, "", client, podsGVR, "kube-system", ListOptions{})
+		require.NoError(t, err)
+		resp.Items[0].(*unstructured.Unstructured).SetName("changed")
+
+		resp, _, err = lc.list(ctx, "", client, podsGVR, "kube-system", ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dns-0"}, itemNames(resp.Items))
+	})
+
+	t.Run("sees changes", func(t *testing.T) {
+		_, err := client.Resource(podsGVR).Namespace("default").Create(ctx,
+			testPod("default", "web-2", map[string]string{"app": "web"}), metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			resp, _, err := lc.list(ctx, "", client, podsGVR, "default", ListOptions{LabelSelector: "app=web"})
+			return err == nil && len(resp.Items) == 3
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("leaves other lists to the API server", func(t *testing.T) {
+		for name, opts := range map[string]ListOptions{
+			"field selector":         {FieldSelector: "spec.nodeName=node-1"},
+			"API server continue":    {Continue: "eyJ2IjoibWV0YS5rOHMuaW8vdjEifQ"},
+			"invalid label selector": {LabelSelector: "app in (web"},
+		} {
+			_, ok, err := lc.list(ctx, "", client, podsGVR, "default", opts)
+			assert.NoError(t, err, name)
+			assert.False(t, ok, name)
+		}
+
+		deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+		_, ok, err := lc.list(ctx, "", client, deployments, "default", ListOptions{})
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("expires cache continue tokens it cannot serve", func(t *testing.T) {
+		var disabled *ListCache
+		_, ok, err := disabled.list(ctx, "", client, podsGVR, "default", ListOptions{Continue: listCacheContinuePrefix + "eA"})
+		assert.False(t, ok)
+		assert.True(t, apierrors.IsResourceExpired(err))
+	})
+}
+
+func TestListCacheCaches(t *testing.T) {
+	lc := NewListCache(ListCacheConfig{Resources: []string{"Pods", " deployments.apps ", "nodes"}})
+	defer lc.Close()
+
+	assert.True(t, lc.caches(podsGVR))
+	assert.True(t, lc.caches(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}))
+	assert.False(t, lc.caches(schema.GroupVersionResource{Group: "other.io", Version: "v1", Resource: "deployments"}))
+	assert.False(t, lc.caches(schema.GroupVersionResource{Version: "v1", Resource: "services"}))
+
+	assert.Nil(t, NewListCache(ListCacheConfig{}))
+}
@@ -10,9 +10,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/portforward"
-	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/client-go/transport/spdy"
 )
 
@@ -91,47 +89,7 @@ func (c *kubernetesClient) Exec(ctx context.Context, kubeContext, namespace, pod
 		return nil, err
 	}
 
-	// Build exec request
-	execReq := clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(podName).
-		Namespace(namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: containerName,
-			Command:   command,
-			Stdin:     opts.Stdin != nil,
-			Stdout:    opts.Stdout != nil,
-			Stderr:    opts.Stderr != nil,
-			TTY:       opts.TTY,
-		}, scheme.ParameterCodec)
-
-	// Create SPDY executor
-	exec, err := remotecommand.NewSPDYExecutor(restConfig, http.MethodPost, execReq.URL())
-	if err != nil {
-		return nil, fmt.Errorf("failed to create executor: %w", err)
-	}
-
-	// Create streams
-	streamOpts := remotecommand.StreamOptions{
-		Stdin:  opts.Stdin,
-		Stdout: opts.Stdout,
-		Stderr: opts.Stderr,
-		Tty:    opts.TTY,
-	}
-
-	// Execute the command
-	err = exec.StreamWithContext(ctx, streamOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute command in pod %s/%s: %w", namespace, podName, err)
-	}
-
-	// Create result (note: exit code extraction would require more complex implementation)
-	result := &ExecResult{
-		ExitCode: 0, // TODO: Extract actual exit code
-	}
-
-	return result, nil
+	return execInPod(ctx, clientset, restConfig, namespace, podName, containerName, command, opts)
 }
 
 // PortForward sets up port forwarding to a pod.
@@ -355,6 +313,52 @@ func (c *kubernetesClient) PortForwardToService(ctx context.Context, kubeContext
 	return c.PortForward(ctx, kubeContext, namespace, targetPod, ports, opts)
 }
 
+// EvictPod evicts a pod through the Eviction API.
+func (c *kubernetesClient) EvictPod(ctx context.Context, kubeContext, namespace, podName string, opts EvictOptions) error {
+	// Validate operation
+	if err := c.isOperationAllowed("evict"); err != nil {
+		return err
+	}
+
+	// Validate namespace access
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return err
+	}
+
+	c.logOperation("evict", kubeContext, namespace, "pod", podName)
+
+	// Get clientset for the context
+	clientset, err := c.getClientset(kubeContext)
+	if err != nil {
+		return err
+	}
+
+	return evictPod(ctx, clientset, namespace, podName, opts, isDryRun(ctx, c.dryRun))
+}
+
+// AddEphemeralContainer adds an ephemeral container to a running pod.
+func (c *kubernetesClient) AddEphemeralContainer(ctx context.Context, kubeContext, namespace, podName string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	// Validate operation
+	if err := c.isOperationAllowed("debug"); err != nil {
+		return nil, err
+	}
+
+	// Validate namespace access
+	if err := c.isNamespaceRestricted(namespace); err != nil {
+		return nil, err
+	}
+
+	c.logOperation("debug", kubeContext, namespace, "pod", podName)
+
+	// Get clientset for the context
+	clientset, err := c.getClientset(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return addEphemeralContainer(ctx, clientset, namespace, podName, container, isDryRun(ctx, c.dryRun))
+}
+
 // validatePodRunning checks if a pod is running in the specified namespace.
 func (c *kubernetesClient) validatePodRunning(ctx context.Context, kubeContext, namespace, podName string) error {
 	clientset, err := c.getClientset(kubeContext)
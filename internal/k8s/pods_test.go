@@ -1,11 +1,20 @@
 package k8s
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestKubernetesClient_PodOperationsValidation(t *testing.T) {
@@ -200,3 +209,68 @@ func TestKubernetesClient_LogPodOperations(t *testing.T) {
 	// Verify that logging occurred
 	assert.NotEmpty(t, testLog.messages)
 }
+
+func TestEvictPod(t *testing.T) {
+	var got *policyv1.Eviction
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create := action.(k8stesting.CreateAction)
+		if create.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		got = create.GetObject().(*policyv1.Eviction)
+		if got.Name == "db-0" {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+		}
+		return true, nil, nil
+	})
+
+	grace := int64(30)
+	require.NoError(t, evictPod(context.Background(), clientset, "shop", "web-1", EvictOptions{GracePeriodSeconds: &grace}, true))
+	require.NotNil(t, got)
+	assert.Equal(t, "shop", got.Namespace)
+	assert.Equal(t, "web-1", got.Name)
+	assert.Equal(t, &grace, got.DeleteOptions.GracePeriodSeconds)
+	assert.Equal(t, []string{metav1.DryRunAll}, got.DeleteOptions.DryRun)
+
+	err := evictPod(context.Background(), clientset, "shop", "db-0", EvictOptions{}, false)
+	require.Error(t, err)
+	assert.True(t, apierrors.IsTooManyRequests(err), "budget refusals must stay detectable, got %v", err)
+	assert.Empty(t, got.DeleteOptions.DryRun)
+}
+
+func TestAddEphemeralContainer(t *testing.T) {
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "example.com/app:1"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "shop"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	clientset := fake.NewClientset(running, pending)
+
+	var opts []string
+	clientset.PrependReactor("update", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateAction)
+		if update.GetSubresource() != "ephemeralcontainers" {
+			return false, nil, nil
+		}
+		opts = append(opts, update.(k8stesting.UpdateActionImpl).UpdateOptions.DryRun...)
+		return true, update.GetObject(), nil
+	})
+
+	debugger := corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger-abcde", Image: "busybox:1.36"}}
+	pod, err := addEphemeralContainer(context.Background(), clientset, "shop", "web-1", debugger, true)
+	require.NoError(t, err)
+	require.Len(t, pod.Spec.EphemeralContainers, 1)
+	assert.Equal(t, "debugger-abcde", pod.Spec.EphemeralContainers[0].Name)
+	assert.Equal(t, []string{metav1.DryRunAll}, opts)
+
+	_, err = addEphemeralContainer(context.Background(), clientset, "shop", "web-2", debugger, false)
+	assert.ErrorContains(t, err, "is not running")
+
+	_, err = addEphemeralContainer(context.Background(), clientset, "shop", "missing", debugger, false)
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestProtobufConfig(t *testing.T) {
+	config := &rest.Config{Host: "https://example.com", QPS: 5}
+	pb := protobufConfig(config)
+
+	assert.Equal(t, "application/vnd.kubernetes.protobuf", pb.ContentType)
+	assert.Equal(t, "application/vnd.kubernetes.protobuf,application/json", pb.AcceptContentTypes)
+	assert.Equal(t, float32(5), pb.QPS)
+	assert.Empty(t, config.ContentType, "the original config is left alone")
+}
+
+func TestProtobufDynamicClient(t *testing.T) {
+	clientset := kubefake.NewClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-0", Labels: map[string]string{"app": "web"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dns-0"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	)
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	dynamicClient := newListCacheTestClient()
+	client := withProtobuf(dynamicClient, clientset)
+	ctx := context.Background()
+
+	t.Run("lists pods in a namespace", func(t *testing.T) {
+		list, err := client.Resource(podsGVR).Namespace("default").List(ctx, metav1.ListOptions{LabelSelector: "app=web"})
+		require.NoError(t, err)
+		assert.Equal(t, "PodList", list.GetKind())
+		require.Len(t, list.Items, 1)
+		pod := list.Items[0]
+		assert.Equal(t, "v1", pod.GetAPIVersion())
+		assert.Equal(t, "Pod", pod.GetKind())
+		assert.Equal(t, "web-0", pod.GetName())
+		assert.Equal(t, map[string]string{"app": "web"}, pod.GetLabels())
+	})
+
+	t.Run("lists pods across namespaces", func(t *testing.T) {
+		list, err := client.Resource(podsGVR).List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 2)
+	})
+
+	t.Run("gets cluster-scoped nodes", func(t *testing.T) {
+		node, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "nodes"}).Get(ctx, "node-1", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "Node", node.GetKind())
+		assert.Equal(t, "node-1", node.GetName())
+	})
+
+	t.Run("keeps API errors", func(t *testing.T) {
+		_, err := client.Resource(podsGVR).Namespace("default").Get(ctx, "missing", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("leaves other resources to the dynamic client", func(t *testing.T) {
+		assert.Equal(t, dynamicClient.Resource(deployments), client.Resource(deployments))
+	})
+
+	t.Run("leaves subresources to the dynamic client", func(t *testing.T) {
+		_, err := client.Resource(podsGVR).Namespace("default").Get(ctx, "web-0", metav1.GetOptions{}, "status")
+		// The empty fake dynamic client does not know the pod.
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestToUnstructuredList(t *testing.T) {
+	remaining := int64(7)
+	list, err := toUnstructuredList(
+		metav1.ListMeta{ResourceVersion: "42", Continue: "next", RemainingItemCount: &remaining},
+		[]corev1.Event{{ObjectMeta: metav1.ObjectMeta{Name: "e1"}, Reason: "Started"}},
+		"Event")
+	require.NoError(t, err)
+
+	assert.Equal(t, "42", list.GetResourceVersion())
+	assert.Equal(t, "next", list.GetContinue())
+	assert.Equal(t, &remaining, list.GetRemainingItemCount())
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "Event", list.Items[0].GetKind())
+	assert.Equal(t, "Started", list.Items[0].Object["reason"])
+}
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,11 +12,15 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -38,10 +43,11 @@ func getResource(ctx context.Context, dynamicClient dynamic.Interface, discovery
 	// Store requested namespace for metadata
 	requestedNamespace := namespace
 
-	gvr, namespaced, err := resolveResourceTypeShared(resourceType, apiGroup, discoveryClient)
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Determine effective namespace based on resource scope
 	effectiveNamespace := ""
@@ -55,7 +61,7 @@ func getResource(ctx context.Context, dynamicClient dynamic.Interface, discovery
 	}
 
 	// Build response with metadata
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false)
+	meta := markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false), res)
 
 	return &GetResponse{
 		Resource: obj,
@@ -89,7 +95,7 @@ func listResources(ctx context.Context, dynamicClient dynamic.Interface, discove
 
 	listStart := time.Now()
 
-	gvr, namespaced, err := resolveResourceTypeShared(resourceType, apiGroup, discoveryClient)
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
 	if err != nil {
 		slog.Debug("resource type resolution failed",
 			slog.String("resourceType", resourceType),
@@ -98,12 +104,18 @@ func listResources(ctx context.Context, dynamicClient dynamic.Interface, discove
 			logging.SanitizedErr(err))
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 	slog.Debug("resolved resource type",
 		slog.String("gvr", gvr.String()),
 		slog.Bool("namespaced", namespaced),
 		slog.Duration("elapsed", time.Since(listStart)))
 
-	return listResourcesWithGVR(ctx, dynamicClient, gvr, namespaced, namespace, resourceType, opts)
+	response, err := listResourcesWithGVR(ctx, dynamicClient, gvr, namespaced, namespace, resourceType, opts)
+	if err != nil {
+		return nil, err
+	}
+	markDegradedDiscovery(response.Meta, res)
+	return response, nil
 }
 
 // listResourcesWithGVR retrieves resources using a pre-resolved GVR.
@@ -304,10 +316,11 @@ func deleteResource(ctx context.Context, dynamicClient dynamic.Interface, discov
 	// Store requested namespace for metadata
 	requestedNamespace := namespace
 
-	gvr, namespaced, err := resolveResourceTypeShared(resourceType, apiGroup, discoveryClient)
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Determine effective namespace based on resource scope
 	effectiveNamespace := ""
@@ -321,10 +334,11 @@ func deleteResource(ctx context.Context, dynamicClient dynamic.Interface, discov
 	}
 
 	// Build response with metadata
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false)
+	meta, message := markDryRun(markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false), res),
+		fmt.Sprintf("Resource %s/%s deleted successfully", resourceType, name), dryRun)
 
 	return &DeleteResponse{
-		Message: fmt.Sprintf("Resource %s/%s deleted successfully", resourceType, name),
+		Message: message,
 		Meta:    meta,
 	}, nil
 }
@@ -362,10 +376,11 @@ func patchResource(ctx context.Context, dynamicClient dynamic.Interface, discove
 	// Store requested namespace for metadata
 	requestedNamespace := namespace
 
-	gvr, namespaced, err := resolveResourceTypeShared(resourceType, apiGroup, discoveryClient)
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Determine effective namespace based on resource scope
 	effectiveNamespace := ""
@@ -379,7 +394,7 @@ func patchResource(ctx context.Context, dynamicClient dynamic.Interface, discove
 	}
 
 	// Build response with metadata
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false)
+	meta, _ := markDryRun(markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false), res), "", dryRun)
 
 	return &PatchResponse{
 		Resource: obj,
@@ -420,10 +435,11 @@ func scaleResource(ctx context.Context, dynamicClient dynamic.Interface, discove
 	// Store requested namespace for metadata (scalable resources are always namespaced)
 	requestedNamespace := namespace
 
-	gvr, namespaced, err := resolveResourceTypeShared(resourceType, apiGroup, discoveryClient)
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	err = scaleResourceWithGVR(ctx, dynamicClient, gvr, namespaced, namespace, resourceType, name, replicas, dryRun)
 	if err != nil {
@@ -432,10 +448,11 @@ func scaleResource(ctx context.Context, dynamicClient dynamic.Interface, discove
 
 	// Build response with metadata
 	// Note: all scalable resources (deployments, replicasets, statefulsets) are namespaced
-	meta := BuildResponseMeta(true, requestedNamespace, namespace, resourceType, false)
+	meta, message := markDryRun(markDegradedDiscovery(BuildResponseMeta(true, requestedNamespace, namespace, resourceType, false), res),
+		fmt.Sprintf("Resource %s/%s scaled to %d replicas successfully", resourceType, name, replicas), dryRun)
 
 	return &ScaleResponse{
-		Message:  fmt.Sprintf("Resource %s/%s scaled to %d replicas successfully", resourceType, name, replicas),
+		Message:  message,
 		Replicas: replicas,
 		Meta:     meta,
 	}, nil
@@ -478,6 +495,96 @@ func scaleResourceWithGVR(ctx context.Context, dynamicClient dynamic.Interface,
 	return nil
 }
 
+// watchResources streams changes to resources.
+func watchResources(ctx context.Context, dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface,
+	namespace, resourceType, apiGroup string, opts WatchOptions) (watch.Interface, error) {
+
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+	return watchResourcesWithGVR(ctx, dynamicClient, res.GVR, res.Namespaced, namespace, opts)
+}
+
+// watchResourcesWithGVR streams changes to resources using a pre-resolved GVR.
+func watchResourcesWithGVR(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource,
+	namespaced bool, namespace string, opts WatchOptions) (watch.Interface, error) {
+
+	var resourceInterface dynamic.ResourceInterface
+	if namespaced && namespace != "" {
+		resourceInterface = dynamicClient.Resource(gvr).Namespace(namespace)
+	} else {
+		resourceInterface = dynamicClient.Resource(gvr)
+	}
+
+	w, err := resourceInterface.Watch(ctx, metav1.ListOptions{
+		LabelSelector:   opts.LabelSelector,
+		FieldSelector:   opts.FieldSelector,
+		ResourceVersion: opts.ResourceVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", gvr.Resource, err)
+	}
+	return w, nil
+}
+
+// validateResource performs a server-side dry-run create or update of obj and
+// collects the API warnings received.
+func validateResource(ctx context.Context, dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface,
+	namespace string, obj runtime.Object, opts ValidateOptions) (*ValidateResponse, error) {
+
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object to unstructured: %w", err)
+	}
+	unstruct := &unstructured.Unstructured{Object: unstructuredObj}
+
+	gvr, namespaced, err := resolveGVRFromObjectShared(unstruct, discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceInterface dynamic.ResourceInterface
+	if namespaced && namespace != "" {
+		unstruct.SetNamespace(namespace)
+		resourceInterface = dynamicClient.Resource(gvr).Namespace(namespace)
+	} else {
+		resourceInterface = dynamicClient.Resource(gvr)
+	}
+
+	fieldValidation := opts.FieldValidation
+	if fieldValidation == "" {
+		fieldValidation = FieldValidationStrict
+	}
+
+	ctx, collector := withWarningCollector(ctx)
+	resp := &ValidateResponse{Operation: "create"}
+
+	var result *unstructured.Unstructured
+	existing, err := resourceInterface.Get(ctx, unstruct.GetName(), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		resp.Operation = "update"
+		unstruct.SetResourceVersion(existing.GetResourceVersion())
+		result, err = resourceInterface.Update(ctx, unstruct, metav1.UpdateOptions{
+			DryRun:          []string{metav1.DryRunAll},
+			FieldValidation: fieldValidation,
+		})
+	case apierrors.IsNotFound(err) || unstruct.GetName() == "":
+		result, err = resourceInterface.Create(ctx, unstruct, metav1.CreateOptions{
+			DryRun:          []string{metav1.DryRunAll},
+			FieldValidation: fieldValidation,
+		})
+	}
+
+	resp.Warnings = collector.list()
+	if err != nil {
+		return resp, fmt.Errorf("failed to validate %s: %w", unstruct.GetKind(), err)
+	}
+	resp.Resource = result
+	return resp, nil
+}
+
 // getLogs retrieves logs from a pod container.
 func getLogs(ctx context.Context, clientset kubernetes.Interface, namespace, podName, containerName string, opts LogOptions) (io.ReadCloser, error) {
 	logOpts := &corev1.PodLogOptions{
@@ -504,23 +611,38 @@ func getLogs(ctx context.Context, clientset kubernetes.Interface, namespace, pod
 	return logs, nil
 }
 
-// execInPod executes a command inside a pod container.
+// execInPod executes a command inside a pod container, or attaches to its
+// running process with opts.Attach.
 func execInPod(ctx context.Context, clientset kubernetes.Interface, restConfig *rest.Config,
 	namespace, podName, containerName string, command []string, opts ExecOptions) (*ExecResult, error) {
 
 	execReq := clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(podName).
-		Namespace(namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: containerName,
-			Command:   command,
-			Stdin:     opts.Stdin != nil,
-			Stdout:    opts.Stdout != nil,
-			Stderr:    opts.Stderr != nil,
-			TTY:       opts.TTY,
-		}, scheme.ParameterCodec)
+		Namespace(namespace)
+	if opts.Attach {
+		if len(command) > 0 {
+			return nil, fmt.Errorf("a command cannot be given when attaching to pod %s/%s", namespace, podName)
+		}
+		execReq = execReq.SubResource("attach").
+			VersionedParams(&corev1.PodAttachOptions{
+				Container: containerName,
+				Stdin:     opts.Stdin != nil,
+				Stdout:    opts.Stdout != nil,
+				Stderr:    opts.Stderr != nil,
+				TTY:       opts.TTY,
+			}, scheme.ParameterCodec)
+	} else {
+		execReq = execReq.SubResource("exec").
+			VersionedParams(&corev1.PodExecOptions{
+				Container: containerName,
+				Command:   command,
+				Stdin:     opts.Stdin != nil,
+				Stdout:    opts.Stdout != nil,
+				Stderr:    opts.Stderr != nil,
+				TTY:       opts.TTY,
+			}, scheme.ParameterCodec)
+	}
 
 	exec, err := remotecommand.NewSPDYExecutor(restConfig, http.MethodPost, execReq.URL())
 	if err != nil {
@@ -542,6 +664,49 @@ func execInPod(ctx context.Context, clientset kubernetes.Interface, restConfig *
 	return &ExecResult{ExitCode: 0}, nil
 }
 
+// evictPod evicts a pod through the policy/v1 Eviction API.
+func evictPod(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, opts EvictOptions, dryRun bool) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: namespace},
+		DeleteOptions: &metav1.DeleteOptions{
+			GracePeriodSeconds: opts.GracePeriodSeconds,
+		},
+	}
+	if dryRun {
+		eviction.DeleteOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	if err := clientset.PolicyV1().Evictions(namespace).Evict(ctx, eviction); err != nil {
+		return fmt.Errorf("failed to evict pod %s/%s: %w", namespace, podName, err)
+	}
+	return nil
+}
+
+// addEphemeralContainer appends container to the ephemeral containers of a
+// running pod.
+func addEphemeralContainer(ctx context.Context, clientset kubernetes.Interface, namespace, podName string, container corev1.EphemeralContainer, dryRun bool) (*corev1.Pod, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("pod %s/%s not found: %w", namespace, podName, err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, fmt.Errorf("pod %s/%s is not running", namespace, podName)
+	}
+
+	updated := pod.DeepCopy()
+	updated.Spec.EphemeralContainers = append(updated.Spec.EphemeralContainers, container)
+	opts := metav1.UpdateOptions{}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	result, err := clientset.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, podName, updated, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add ephemeral container to pod %s/%s: %w", namespace, podName, err)
+	}
+	return result, nil
+}
+
 // portForwardToPod sets up port forwarding to a pod.
 func portForwardToPod(ctx context.Context, clientset kubernetes.Interface, restConfig *rest.Config,
 	namespace, podName string, ports []string, opts PortForwardOptions) (*PortForwardSession, error) {
@@ -790,6 +955,48 @@ func getAPIResources(ctx context.Context, discoveryClient discovery.DiscoveryInt
 	}, nil
 }
 
+// proxyGetService sends a GET request to a service through the API server's
+// service proxy. The status code is taken from the response even when the
+// request failed, so a 503 from a service without endpoints is reported as
+// such rather than as an error.
+func proxyGetService(ctx context.Context, clientset kubernetes.Interface, namespace string, req ServiceProxyRequest) (*ServiceProxyResponse, error) {
+	// Services().ProxyGet only exposes DoRaw, which drops the status code,
+	// so build the same request on the REST client.
+	result := clientset.CoreV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("services").
+		SubResource("proxy").
+		Name(utilnet.JoinSchemeNamePort(req.Scheme, req.Service, req.Port)).
+		Suffix(req.Path).
+		Do(ctx)
+
+	var statusCode int
+	result.StatusCode(&statusCode)
+	body, err := result.Raw()
+	// Error responses carry their status on the error instead.
+	var apiStatus apierrors.APIStatus
+	if statusCode == 0 && errors.As(err, &apiStatus) {
+		statusCode = int(apiStatus.Status().Code)
+		if len(body) == 0 {
+			body = []byte(apiStatus.Status().Message)
+		}
+	}
+	if statusCode == 0 {
+		if err == nil {
+			err = fmt.Errorf("no response from service proxy")
+		}
+		return nil, err
+	}
+
+	response := &ServiceProxyResponse{StatusCode: statusCode}
+	if req.MaxBytes > 0 && len(body) > req.MaxBytes {
+		body = body[:req.MaxBytes]
+		response.Truncated = true
+	}
+	response.Body = string(body)
+	return response, nil
+}
+
 // getClusterHealth returns the health status of the cluster.
 func getClusterHealth(ctx context.Context, clientset kubernetes.Interface, discoveryClient discovery.DiscoveryInterface) (*ClusterHealth, error) {
 	health := &ClusterHealth{
@@ -809,6 +1016,8 @@ func getClusterHealth(ctx context.Context, clientset kubernetes.Interface, disco
 		return health, nil
 	}
 
+	health.Version = version.GitVersion
+
 	health.Components = append(health.Components, ComponentHealth{
 		Name:    "API Server",
 		Status:  clusterHealthHealthy,
@@ -897,40 +1106,42 @@ func parseAPIGroup(apiGroup string) (group, preferredVersion string) {
 	return group, preferredVersion
 }
 
+// resourceResolution is the outcome of resolving a resource type to a GVR.
+type resourceResolution struct {
+	GVR        schema.GroupVersionResource
+	Namespaced bool
+
+	// Degraded is true when discovery was unavailable and the resource was
+	// resolved from the built-in fallback table instead.
+	Degraded bool
+}
+
 // resolveResourceTypeShared determines the GroupVersionResource for a given resource type.
 // It uses the Kubernetes API discovery to resolve resources and determine their scope.
 // Discovery results are cached by the discovery client.
 func resolveResourceTypeShared(resourceType, apiGroup string,
 	discoveryClient discovery.DiscoveryInterface) (schema.GroupVersionResource, bool, error) {
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
+	return res.GVR, res.Namespaced, err
+}
 
-	resourceType = strings.ToLower(resourceType)
-	requestedGroup, preferredVersion := parseAPIGroup(apiGroup)
-
-	// Always use discovery for resource resolution and scope determination.
-	// The discovery client caches results, so this is efficient.
-	// Set up timeout for discovery API call
-	ctx, cancel := context.WithTimeout(context.Background(), DiscoveryTimeoutSeconds*time.Second)
-	defer cancel()
+// ErrUnknownResourceType is returned for resource types the cluster does not
+// serve.
+var ErrUnknownResourceType = errors.New("unknown resource type")
 
-	type discoveryResult struct {
-		resourceLists []*metav1.APIResourceList
-		err           error
-	}
+// resolveResourceTypeDetailed is resolveResourceTypeShared, additionally
+// reporting whether the result came from the built-in fallback table.
+// Discovery is bounded by the shared DiscoveryBudget; when it times out or
+// fails without yielding a match, well-known core resources still resolve
+// (flagged as degraded) so that basic reads keep working.
+func resolveResourceTypeDetailed(resourceType, apiGroup string,
+	discoveryClient discovery.DiscoveryInterface) (resourceResolution, error) {
 
-	resultChan := make(chan discoveryResult, 1)
-	go func() {
-		resourceLists, err := discoveryClient.ServerPreferredResources()
-		resultChan <- discoveryResult{resourceLists: resourceLists, err: err}
-	}()
+	resourceType = strings.ToLower(resourceType)
+	requestedGroup, preferredVersion := parseAPIGroup(apiGroup)
 
-	var resourceLists []*metav1.APIResourceList
-	select {
-	case result := <-resultChan:
-		resourceLists = result.resourceLists
-		// Continue with partial results even on error
-	case <-ctx.Done():
-		return schema.GroupVersionResource{}, false, fmt.Errorf("API discovery timed out after 30 seconds")
-	}
+	// Continue with partial results even on error
+	resourceLists, discoveryErr := preferredResources(discoveryClient)
 
 	// Helper to search API resources with optional group/version preference
 	searchResources := func(preferVersion string) (schema.GroupVersionResource, bool, bool) {
@@ -984,16 +1195,29 @@ func resolveResourceTypeShared(resourceType, apiGroup string,
 	// First, if a preferred version was specified (via apiGroup like "apps/v1"), search with that preference
 	if preferredVersion != "" {
 		if gvr, namespaced, found := searchResources(preferredVersion); found {
-			return gvr, namespaced, nil
+			return resourceResolution{GVR: gvr, Namespaced: namespaced}, nil
 		}
 	}
 
 	// Fallback: search without version preference (still honoring requested group if provided)
 	if gvr, namespaced, found := searchResources(""); found {
-		return gvr, namespaced, nil
+		return resourceResolution{GVR: gvr, Namespaced: namespaced}, nil
+	}
+
+	if discoveryErr != nil {
+		if gvr, namespaced, found := lookupBuiltinResource(resourceType, requestedGroup); found {
+			slog.Debug("resolved resource type from built-in table; API discovery degraded",
+				slog.String("resourceType", resourceType),
+				slog.String("resource", gvr.Resource),
+				logging.SanitizedErr(discoveryErr))
+			return resourceResolution{GVR: gvr, Namespaced: namespaced, Degraded: true}, nil
+		}
+		if errors.Is(discoveryErr, errDiscoveryTimeout) {
+			return resourceResolution{}, discoveryErr
+		}
 	}
 
-	return schema.GroupVersionResource{}, false, fmt.Errorf("unknown resource type: %s", resourceType)
+	return resourceResolution{}, fmt.Errorf("%w: %s", ErrUnknownResourceType, resourceType)
 }
 
 // resolveGVRFromObjectShared resolves GroupVersionResource from an unstructured object.
@@ -1006,6 +1230,20 @@ func resolveGVRFromObjectShared(obj *unstructured.Unstructured, discoveryClient
 	return resolveResourceTypeShared(obj.GetKind(), "", discoveryClient)
 }
 
+// markDegradedDiscovery flags meta when resolution fell back to the built-in table.
+func markDegradedDiscovery(meta *ResponseMeta, res resourceResolution) *ResponseMeta {
+	if meta == nil || !res.Degraded {
+		return meta
+	}
+	meta.DegradedDiscovery = true
+	hint := "API discovery unavailable; resource type resolved from the built-in table"
+	if meta.Hint != "" {
+		hint = meta.Hint + "; " + hint
+	}
+	meta.Hint = hint
+	return meta
+}
+
 // getResourceEventsShared retrieves events related to a specific resource.
 func getResourceEventsShared(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]corev1.Event, error) {
 	eventList, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -42,10 +43,13 @@ func (c *kubernetesClient) Get(ctx context.Context, kubeContext, namespace, reso
 	}
 
 	// Resolve resource type to GVR
-	gvr, namespaced, err := c.resolveResourceType(resourceType, apiGroup, kubeContext)
+	res, err := retryRead(ctx, c.config.Retry, "get", func() (resourceResolution, error) {
+		return c.resolveResource(resourceType, apiGroup, kubeContext)
+	})
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Determine effective namespace based on resource scope
 	effectiveNamespace := ""
@@ -58,13 +62,15 @@ func (c *kubernetesClient) Get(ctx context.Context, kubeContext, namespace, reso
 	}
 
 	// Get the resource
-	obj, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+	obj, err := retryRead(ctx, c.config.Retry, "get", func() (*unstructured.Unstructured, error) {
+		return resourceInterface.Get(ctx, name, metav1.GetOptions{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get %s %q: %w", resourceType, name, err)
 	}
 
 	// Build response with metadata
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false)
+	meta := markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false), res)
 
 	return &GetResponse{
 		Resource: obj,
@@ -98,10 +104,13 @@ func (c *kubernetesClient) List(ctx context.Context, kubeContext, namespace, res
 	}
 
 	// Resolve resource type to GVR
-	gvr, namespaced, err := c.resolveResourceType(resourceType, apiGroup, kubeContext)
+	res, err := retryRead(ctx, c.config.Retry, "list", func() (resourceResolution, error) {
+		return c.resolveResource(resourceType, apiGroup, kubeContext)
+	})
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Prepare list options with pagination
 	listOpts := metav1.ListOptions{
@@ -127,8 +136,29 @@ func (c *kubernetesClient) List(ctx context.Context, kubeContext, namespace, res
 		resourceInterface = dynamicClient.Resource(gvr)
 	}
 
+	// Serve hot resource types from the list cache when configured
+	cached, ok, err := c.config.ListCache.list(ctx, kubeContext, dynamicClient, gvr, effectiveNamespace, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", resourceType, err)
+	}
+	if ok {
+		meta := markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, opts.AllNamespaces), res)
+		meta.ListCache = cached.Meta.ListCache
+		cached.Meta = meta
+		if c.config.DebugMode && c.config.Logger != nil {
+			c.config.Logger.Debug("listed resources from cache",
+				"resourceType", resourceType,
+				"namespace", namespace,
+				"count", cached.TotalItems,
+				"resourceVersion", cached.ResourceVersion)
+		}
+		return cached, nil
+	}
+
 	// List the resources
-	list, err := resourceInterface.List(ctx, listOpts)
+	list, err := retryRead(ctx, c.config.Retry, "list", func() (*unstructured.UnstructuredList, error) {
+		return resourceInterface.List(ctx, listOpts)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list %s: %w", resourceType, err)
 	}
@@ -140,7 +170,7 @@ func (c *kubernetesClient) List(ctx context.Context, kubeContext, namespace, res
 	}
 
 	// Build response metadata for transparency
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, opts.AllNamespaces)
+	meta := markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, opts.AllNamespaces), res)
 
 	// Build paginated response
 	response := &PaginatedListResponse{
@@ -268,7 +298,7 @@ func (c *kubernetesClient) Create(ctx context.Context, kubeContext, namespace st
 
 	// Prepare create options
 	createOpts := metav1.CreateOptions{}
-	if c.dryRun {
+	if isDryRun(ctx, c.dryRun) {
 		createOpts.DryRun = []string{metav1.DryRunAll}
 	}
 
@@ -345,7 +375,7 @@ func (c *kubernetesClient) Apply(ctx context.Context, kubeContext, namespace str
 
 	// Prepare update options
 	updateOpts := metav1.UpdateOptions{}
-	if c.dryRun {
+	if isDryRun(ctx, c.dryRun) {
 		updateOpts.DryRun = []string{metav1.DryRunAll}
 	}
 
@@ -392,14 +422,15 @@ func (c *kubernetesClient) Delete(ctx context.Context, kubeContext, namespace, r
 	}
 
 	// Resolve resource type to GVR
-	gvr, namespaced, err := c.resolveResourceType(resourceType, apiGroup, kubeContext)
+	res, err := c.resolveResource(resourceType, apiGroup, kubeContext)
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Prepare delete options
 	deleteOpts := metav1.DeleteOptions{}
-	if c.dryRun {
+	if isDryRun(ctx, c.dryRun) {
 		deleteOpts.DryRun = []string{metav1.DryRunAll}
 	}
 
@@ -420,10 +451,11 @@ func (c *kubernetesClient) Delete(ctx context.Context, kubeContext, namespace, r
 	}
 
 	// Build response with metadata
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false)
+	meta, message := markDryRun(markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false), res),
+		fmt.Sprintf("Resource %s/%s deleted successfully", resourceType, name), isDryRun(ctx, c.dryRun))
 
 	return &DeleteResponse{
-		Message: fmt.Sprintf("Resource %s/%s deleted successfully", resourceType, name),
+		Message: message,
 		Meta:    meta,
 	}, nil
 }
@@ -454,14 +486,15 @@ func (c *kubernetesClient) Patch(ctx context.Context, kubeContext, namespace, re
 	}
 
 	// Resolve resource type to GVR
-	gvr, namespaced, err := c.resolveResourceType(resourceType, apiGroup, kubeContext)
+	res, err := c.resolveResource(resourceType, apiGroup, kubeContext)
 	if err != nil {
 		return nil, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Prepare patch options
 	patchOpts := metav1.PatchOptions{}
-	if c.dryRun {
+	if isDryRun(ctx, c.dryRun) {
 		patchOpts.DryRun = []string{metav1.DryRunAll}
 	}
 
@@ -482,7 +515,7 @@ func (c *kubernetesClient) Patch(ctx context.Context, kubeContext, namespace, re
 	}
 
 	// Build response with metadata
-	meta := BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false)
+	meta, _ := markDryRun(markDegradedDiscovery(BuildResponseMeta(namespaced, requestedNamespace, effectiveNamespace, resourceType, false), res), "", isDryRun(ctx, c.dryRun))
 
 	return &PatchResponse{
 		Resource: result,
@@ -517,7 +550,7 @@ func (c *kubernetesClient) Scale(ctx context.Context, kubeContext, namespace, re
 
 	// Prepare scale options
 	scaleOpts := metav1.UpdateOptions{}
-	if c.dryRun {
+	if isDryRun(ctx, c.dryRun) {
 		scaleOpts.DryRun = []string{metav1.DryRunAll}
 	}
 
@@ -562,15 +595,71 @@ func (c *kubernetesClient) Scale(ctx context.Context, kubeContext, namespace, re
 
 	// Build response with metadata
 	// Note: all scalable resources (deployments, replicasets, statefulsets) are namespaced
-	meta := BuildResponseMeta(true, requestedNamespace, namespace, resourceType, false)
+	meta, message := markDryRun(BuildResponseMeta(true, requestedNamespace, namespace, resourceType, false),
+		fmt.Sprintf("Resource %s/%s scaled to %d replicas successfully", resourceType, name, replicas), isDryRun(ctx, c.dryRun))
 
 	return &ScaleResponse{
-		Message:  fmt.Sprintf("Resource %s/%s scaled to %d replicas successfully", resourceType, name, replicas),
+		Message:  message,
 		Replicas: replicas,
 		Meta:     meta,
 	}, nil
 }
 
+// Watch streams changes to resources.
+func (c *kubernetesClient) Watch(ctx context.Context, kubeContext, namespace, resourceType, apiGroup string, opts WatchOptions) (watch.Interface, error) {
+	// Validate operation
+	if err := c.isOperationAllowed("watch"); err != nil {
+		return nil, err
+	}
+
+	// Validate namespace access
+	if namespace != "" {
+		if err := c.isNamespaceRestricted(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	c.logOperation("watch", kubeContext, namespace, resourceType, "")
+
+	// Get dynamic client for the context
+	dynamicClient, err := c.getDynamicClient(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve resource type to GVR
+	res, err := c.resolveResource(resourceType, apiGroup, kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return watchResourcesWithGVR(ctx, dynamicClient, res.GVR, res.Namespaced, namespace, opts)
+}
+
+// Validate performs a server-side dry-run create or update.
+func (c *kubernetesClient) Validate(ctx context.Context, kubeContext, namespace string, obj runtime.Object, opts ValidateOptions) (*ValidateResponse, error) {
+	// Validate namespace access
+	if namespace != "" {
+		if err := c.isNamespaceRestricted(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	c.logOperation("validate", kubeContext, namespace, obj.GetObjectKind().GroupVersionKind().Kind, "")
+
+	dynamicClient, err := c.getDynamicClient(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := c.getDiscoveryClient(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return validateResource(ctx, dynamicClient, discoveryClient, namespace, obj, opts)
+}
+
 // Helper methods
 
 // buildScopeCacheKey creates a cache key for resource scope lookups.
@@ -583,8 +672,15 @@ func buildScopeCacheKey(contextName, resourceType, apiGroup string) string {
 }
 
 // resolveResourceType determines the GroupVersionResource for a given resource type.
-// This method wraps resolveResourceTypeShared with debug logging support and scope caching.
 func (c *kubernetesClient) resolveResourceType(resourceType, apiGroup, contextName string) (schema.GroupVersionResource, bool, error) {
+	res, err := c.resolveResource(resourceType, apiGroup, contextName)
+	return res.GVR, res.Namespaced, err
+}
+
+// resolveResource resolves a resource type and reports whether the result
+// came from the built-in fallback table.
+// This method wraps resolveResourceTypeDetailed with debug logging support and scope caching.
+func (c *kubernetesClient) resolveResource(resourceType, apiGroup, contextName string) (resourceResolution, error) {
 	if c.config.DebugMode && c.config.Logger != nil {
 		c.config.Logger.Debug("resolveResourceType: starting", "resourceType", resourceType, "apiGroup", apiGroup, "contextName", contextName)
 	}
@@ -595,17 +691,18 @@ func (c *kubernetesClient) resolveResourceType(resourceType, apiGroup, contextNa
 		if c.config.DebugMode && c.config.Logger != nil {
 			c.config.Logger.Error("resolveResourceType: failed to get discovery client", "error", err)
 		}
-		return schema.GroupVersionResource{}, false, fmt.Errorf("failed to get discovery client: %w", err)
+		return resourceResolution{}, fmt.Errorf("failed to get discovery client: %w", err)
 	}
 
 	// Use the shared implementation
-	gvr, namespaced, err := resolveResourceTypeShared(resourceType, apiGroup, discoveryClient)
+	res, err := resolveResourceTypeDetailed(resourceType, apiGroup, discoveryClient)
 	if err != nil {
 		if c.config.DebugMode && c.config.Logger != nil {
 			c.config.Logger.Error("resolveResourceType: resolution failed", "resourceType", resourceType, "error", err)
 		}
-		return gvr, namespaced, err
+		return res, err
 	}
+	gvr, namespaced := res.GVR, res.Namespaced
 
 	// Cache the resource scope for future lookups
 	cacheKey := buildScopeCacheKey(contextName, gvr.Resource, gvr.Group)
@@ -620,10 +717,11 @@ func (c *kubernetesClient) resolveResourceType(resourceType, apiGroup, contextNa
 			"version", gvr.Version,
 			"resource", gvr.Resource,
 			"namespaced", namespaced,
+			"degradedDiscovery", res.Degraded,
 			"cached", true)
 	}
 
-	return gvr, namespaced, nil
+	return res, nil
 }
 
 // resolveGVRFromObject resolves GroupVersionResource from an unstructured object.
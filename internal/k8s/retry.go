@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// Default retry settings. DefaultRetries is the serve command's default;
+// the others apply when RetryConfig leaves them unset.
+const (
+	DefaultRetries             = 3
+	DefaultRetryInitialBackoff = 200 * time.Millisecond
+	DefaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// Retry reasons, as passed to RetryMetricsRecorder.
+const (
+	RetryReasonTooManyRequests = "too_many_requests"
+	RetryReasonServerError     = "server_error"
+	RetryReasonConnection      = "connection"
+)
+
+// RetryMetricsRecorder records retried Kubernetes API reads. It is
+// implemented by instrumentation.Metrics.
+type RetryMetricsRecorder interface {
+	// RecordAPIRetry is called before each retry of a read operation
+	// ("get", "list", "describe", "api-resources") with the reason of the
+	// failure being retried.
+	RecordAPIRetry(ctx context.Context, operation, reason string)
+}
+
+// RetryConfig configures retries of idempotent reads (get, list, describe
+// and discovery) that fail with a transient error: 429, a 5xx other than
+// 501, or a reset or refused connection. Writes are never retried.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt.
+	// Zero disables retries.
+	MaxRetries int
+
+	// InitialBackoff is the wait before the first retry. It doubles with
+	// each further retry. Defaults to DefaultRetryInitialBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait before a retry, including a wait asked for
+	// by a Retry-After header. Defaults to DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+
+	// Metrics is told about each retry. Optional.
+	Metrics RetryMetricsRecorder
+}
+
+// retryReason returns why err is worth retrying, or "" if it is not.
+func retryReason(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		code := int(status.Status().Code)
+		switch {
+		case code == http.StatusTooManyRequests:
+			return RetryReasonTooManyRequests
+		case code >= http.StatusInternalServerError && code != http.StatusNotImplemented:
+			return RetryReasonServerError
+		}
+		return ""
+	}
+	if utilnet.IsConnectionReset(err) || utilnet.IsConnectionRefused(err) || utilnet.IsProbableEOF(err) {
+		return RetryReasonConnection
+	}
+	return ""
+}
+
+// backoff returns the wait before the given retry (1 for the first), taking
+// a Retry-After delay suggested by err into account.
+func (rc RetryConfig) backoff(retry int, err error) time.Duration {
+	initial := rc.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultRetryInitialBackoff
+	}
+	maxBackoff := rc.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+
+	wait := initial
+	for i := 1; i < retry && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		if suggested := time.Duration(seconds) * time.Second; suggested > wait {
+			wait = suggested
+		}
+	}
+	return min(wait, maxBackoff)
+}
+
+// retryRead runs a read operation, retrying it as configured by rc while it
+// fails with a transient error. It gives up early with the last error when
+// ctx is done.
+func retryRead[T any](ctx context.Context, rc RetryConfig, operation string, fn func() (T, error)) (T, error) {
+	result, err := fn()
+	for retry := 1; retry <= rc.MaxRetries; retry++ {
+		reason := retryReason(err)
+		if reason == "" {
+			return result, err
+		}
+
+		timer := time.NewTimer(rc.backoff(retry, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+
+		if rc.Metrics != nil {
+			rc.Metrics.RecordAPIRetry(ctx, operation, reason)
+		}
+		result, err = fn()
+	}
+	return result, err
+}
@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type recordedRetry struct {
+	operation, reason string
+}
+
+type fakeRetryMetrics struct {
+	retries []recordedRetry
+}
+
+func (f *fakeRetryMetrics) RecordAPIRetry(_ context.Context, operation, reason string) {
+	f.retries = append(f.retries, recordedRetry{operation, reason})
+}
+
+func TestRetryReason(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"too many requests", apierrors.NewTooManyRequests("slow down", 1), RetryReasonTooManyRequests},
+		{"internal error", apierrors.NewInternalError(errors.New("boom")), RetryReasonServerError},
+		{"service unavailable", apierrors.NewServiceUnavailable("etcd"), RetryReasonServerError},
+		{"wrapped server error", fmt.Errorf("failed to get pods: %w", apierrors.NewServiceUnavailable("etcd")), RetryReasonServerError},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), RetryReasonConnection},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), RetryReasonConnection},
+		{"not found", apierrors.NewNotFound(pods, "web"), ""},
+		{"forbidden", apierrors.NewForbidden(pods, "web", errors.New("rbac")), ""},
+		{"not implemented", &apierrors.StatusError{ErrStatus: metav1.Status{Code: http.StatusNotImplemented}}, ""},
+		{"bad gateway", &apierrors.StatusError{ErrStatus: metav1.Status{Code: http.StatusBadGateway}}, RetryReasonServerError},
+		{"deadline exceeded", context.DeadlineExceeded, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryReason(tt.err))
+		})
+	}
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	rc := RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, rc.backoff(1, nil))
+	assert.Equal(t, 200*time.Millisecond, rc.backoff(2, nil))
+	assert.Equal(t, 400*time.Millisecond, rc.backoff(3, nil))
+	assert.Equal(t, time.Second, rc.backoff(10, nil), "capped at MaxBackoff")
+
+	retryAfter := apierrors.NewTooManyRequests("slow down", 1)
+	assert.Equal(t, time.Second, rc.backoff(1, retryAfter), "Retry-After wins over a shorter backoff")
+
+	retryAfter = apierrors.NewTooManyRequests("slow down", 30)
+	assert.Equal(t, time.Second, rc.backoff(1, retryAfter), "Retry-After is capped at MaxBackoff")
+
+	assert.Equal(t, DefaultRetryInitialBackoff, RetryConfig{}.backoff(1, nil))
+}
+
+func TestRetryRead(t *testing.T) {
+	fast := RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		metrics := &fakeRetryMetrics{}
+		rc := fast
+		rc.Metrics = metrics
+
+		calls := 0
+		result, err := retryRead(context.Background(), rc, "list", func() (string, error) {
+			calls++
+			if calls < 3 {
+				return "", apierrors.NewServiceUnavailable("etcd")
+			}
+			return "ok", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []recordedRetry{
+			{"list", RetryReasonServerError},
+			{"list", RetryReasonServerError},
+		}, metrics.retries)
+	})
+
+	t.Run("gives up after MaxRetries", func(t *testing.T) {
+		calls := 0
+		_, err := retryRead(context.Background(), fast, "get", func() (string, error) {
+			calls++
+			return "", apierrors.NewTooManyRequests("slow down", 0)
+		})
+		assert.True(t, apierrors.IsTooManyRequests(err))
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		_, err := retryRead(context.Background(), fast, "get", func() (string, error) {
+			calls++
+			return "", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")
+		})
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		calls := 0
+		_, err := retryRead(context.Background(), RetryConfig{}, "get", func() (string, error) {
+			calls++
+			return "", apierrors.NewServiceUnavailable("etcd")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		rc := RetryConfig{MaxRetries: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+		_, err := retryRead(ctx, rc, "get", func() (string, error) {
+			calls++
+			return "", apierrors.NewServiceUnavailable("etcd")
+		})
+		assert.True(t, apierrors.IsServiceUnavailable(err))
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// DefaultUserRateLimiterMaxUsers caps the number of users a UserRateLimiter
+// tracks at once.
+const DefaultUserRateLimiterMaxUsers = 10000
+
+// rateLimitUserKey is the context key for the user whose budget a Kubernetes
+// API request is drawn from.
+type rateLimitUserKey struct{}
+
+// ContextWithRateLimitUser returns a context whose Kubernetes API requests
+// are drawn from user's budget in a UserRateLimiter.
+func ContextWithRateLimitUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, rateLimitUserKey{}, user)
+}
+
+// RateLimitUserFromContext returns the user set by ContextWithRateLimitUser,
+// or "" if there is none.
+func RateLimitUserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(rateLimitUserKey{}).(string)
+	return user
+}
+
+// userBucket is a user's token bucket and the last time it was used.
+type userBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// UserRateLimiter partitions the client-side QPS budget of Kubernetes
+// clients per user. A client it is applied to first waits for the
+// requesting user's own bucket and then for the client's shared bucket, so a
+// single noisy user cannot use up the shared budget of the replica. Requests
+// whose context carries no user (see ContextWithRateLimitUser) only wait for
+// the shared bucket.
+//
+// One UserRateLimiter is shared by all clients, so a user's budget covers
+// their requests to every cluster.
+type UserRateLimiter struct {
+	mu       sync.Mutex
+	users    map[string]*userBucket
+	qps      rate.Limit
+	burst    int
+	maxUsers int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// NewUserRateLimiter creates a limiter allowing each user qps requests per
+// second with bursts of up to burst requests. A burst below 1 is raised to 1
+// so that requests can pass at all.
+func NewUserRateLimiter(qps float32, burst int) *UserRateLimiter {
+	burst = max(burst, 1)
+	return &UserRateLimiter{
+		users:    make(map[string]*userBucket),
+		qps:      rate.Limit(qps),
+		burst:    burst,
+		maxUsers: DefaultUserRateLimiterMaxUsers,
+		now:      time.Now,
+	}
+}
+
+// ApplyTo makes config's clients wait for the user's budget before the
+// shared budget given by config.QPS and config.Burst. It must be called after
+// those are set and before clients are created from config.
+func (l *UserRateLimiter) ApplyTo(config *rest.Config) {
+	if l == nil || config == nil {
+		return
+	}
+	qps, burst := config.QPS, config.Burst
+	if qps == 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst == 0 {
+		burst = rest.DefaultBurst
+	}
+	config.RateLimiter = &userPartitionedRateLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		users:       l,
+	}
+}
+
+// Wait blocks until the user of ctx may make a request, or ctx is done.
+func (l *UserRateLimiter) Wait(ctx context.Context) error {
+	user := RateLimitUserFromContext(ctx)
+	if user == "" {
+		return nil
+	}
+	return l.bucket(user).Wait(ctx)
+}
+
+// bucket returns the user's token bucket, creating it if needed.
+func (l *UserRateLimiter) bucket(user string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.users[user]
+	if !ok {
+		if len(l.users) >= l.maxUsers {
+			l.evictIdle(now)
+		}
+		b = &userBucket{limiter: rate.NewLimiter(l.qps, l.burst)}
+		l.users[user] = b
+	}
+	b.lastUsed = now
+	return b.limiter
+}
+
+// evictIdle drops buckets that have refilled completely, since they are
+// indistinguishable from new ones. If none have, the least recently used
+// bucket is dropped. Must be called with mu held.
+func (l *UserRateLimiter) evictIdle(now time.Time) {
+	var oldestUser string
+	var oldest time.Time
+	for user, b := range l.users {
+		if b.limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.users, user)
+			continue
+		}
+		if oldestUser == "" || b.lastUsed.Before(oldest) {
+			oldestUser, oldest = user, b.lastUsed
+		}
+	}
+	if len(l.users) >= l.maxUsers && oldestUser != "" {
+		delete(l.users, oldestUser)
+	}
+}
+
+// userPartitionedRateLimiter is the flowcontrol.RateLimiter installed by
+// UserRateLimiter.ApplyTo. client-go only calls Wait for requests; the other
+// methods use the shared bucket alone.
+type userPartitionedRateLimiter struct {
+	flowcontrol.RateLimiter
+	users *UserRateLimiter
+}
+
+func (r *userPartitionedRateLimiter) Wait(ctx context.Context) error {
+	if err := r.users.Wait(ctx); err != nil {
+		return err
+	}
+	return r.RateLimiter.Wait(ctx)
+}
@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestUserRateLimiterPartitionsUsers(t *testing.T) {
+	l := NewUserRateLimiter(0.001, 1)
+
+	alice := ContextWithRateLimitUser(context.Background(), "alice")
+	require.NoError(t, l.Wait(alice))
+
+	// Alice used up her burst; her next request has to wait far longer
+	// than the context allows.
+	ctx, cancel := context.WithTimeout(alice, 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Wait(ctx))
+
+	// Bob has his own budget.
+	bob := ContextWithRateLimitUser(context.Background(), "bob")
+	assert.NoError(t, l.Wait(bob))
+
+	// Requests without a user only wait for the shared budget.
+	assert.NoError(t, l.Wait(context.Background()))
+}
+
+func TestUserRateLimiterEvictsIdleUsers(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewUserRateLimiter(1, 1)
+	l.maxUsers = 2
+	l.now = func() time.Time { return now }
+
+	l.bucket("alice").AllowN(now, 1)
+	now = now.Add(time.Millisecond)
+	l.bucket("bob").AllowN(now, 1)
+
+	// Neither bucket has refilled, so the least recently used one goes.
+	now = now.Add(time.Millisecond)
+	l.bucket("carol")
+	assert.NotContains(t, l.users, "alice")
+	assert.Contains(t, l.users, "bob")
+
+	// Once refilled, buckets are dropped as they equal new ones.
+	now = now.Add(time.Minute)
+	l.bucket("dave")
+	assert.Len(t, l.users, 1)
+	assert.Contains(t, l.users, "dave")
+}
+
+func TestUserRateLimiterApplyTo(t *testing.T) {
+	config := &rest.Config{QPS: 5, Burst: 10}
+	NewUserRateLimiter(1, 1).ApplyTo(config)
+
+	limiter, ok := config.RateLimiter.(*userPartitionedRateLimiter)
+	require.True(t, ok)
+	assert.Equal(t, float32(5), limiter.QPS())
+
+	// A nil limiter leaves the config alone.
+	config = &rest.Config{}
+	var nilLimiter *UserRateLimiter
+	nilLimiter.ApplyTo(config)
+	assert.Nil(t, config.RateLimiter)
+}
@@ -0,0 +1,62 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// warningCollectorKey is the context key for a warningCollector.
+type warningCollectorKey struct{}
+
+// warningCollector gathers the API warnings returned during one call, such
+// as deprecation notices and admission webhook warnings.
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (w *warningCollector) add(text string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, existing := range w.warnings {
+		if existing == text {
+			return
+		}
+	}
+	w.warnings = append(w.warnings, text)
+}
+
+func (w *warningCollector) list() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.warnings...)
+}
+
+// withWarningCollector returns a context whose API requests record their
+// warnings in the returned collector.
+func withWarningCollector(ctx context.Context) (context.Context, *warningCollector) {
+	collector := &warningCollector{}
+	return context.WithValue(ctx, warningCollectorKey{}, collector), collector
+}
+
+// contextWarningHandler hands warnings to the collector in the request
+// context, if any, and logs them otherwise like client-go does by default.
+type contextWarningHandler struct{}
+
+func (contextWarningHandler) HandleWarningHeaderWithContext(ctx context.Context, code int, agent, text string) {
+	if collector, ok := ctx.Value(warningCollectorKey{}).(*warningCollector); ok {
+		if code == 299 && text != "" {
+			collector.add(text)
+		}
+		return
+	}
+	rest.WarningLogger{}.HandleWarningHeaderWithContext(ctx, code, agent, text)
+}
+
+// None of the clients set a warning handler on their REST config, so the
+// default handler sees the warnings of every request.
+func init() {
+	rest.SetDefaultWarningHandlerWithContext(contextWarningHandler{})
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// validateServer fakes the widgets endpoint of an API server. Existing
+// widgets are answered on GET; every write must be a dry run and gets a
+// deprecation warning. Writes are rejected when reject is set.
+func validateServer(existing, reject bool, queries *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			if !existing {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusFailure, Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound})
+				return
+			}
+			_, _ = io.WriteString(w, `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"w1","namespace":"shop","resourceVersion":"7"}}`)
+			return
+		}
+
+		*queries = append(*queries, r.Method+" "+r.URL.RawQuery)
+		w.Header().Add("Warning", `299 - "example.com/v1 Widget is deprecated; use example.com/v2 Widget"`)
+		w.Header().Add("Warning", `299 - "unknown field \"spec.colour\""`)
+		if reject {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusFailure, Reason: metav1.StatusReasonInvalid, Code: http.StatusUnprocessableEntity, Message: "spec.size: Invalid value"})
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+}
+
+func validateWidget(t *testing.T, srv *httptest.Server, opts ValidateOptions) (*ValidateResponse, error) {
+	t.Helper()
+	dynamicClient, err := dynamic.NewForConfig(&rest.Config{Host: srv.URL})
+	require.NoError(t, err)
+
+	widget := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "w1"},
+		"spec":       map[string]interface{}{"colour": "red"},
+	}}
+	return validateResource(context.Background(), dynamicClient, newCountingDiscovery(), "shop", widget, opts)
+}
+
+func TestValidateResource_CreateCollectsWarnings(t *testing.T) {
+	var queries []string
+	srv := validateServer(false, false, &queries)
+	defer srv.Close()
+
+	resp, err := validateWidget(t, srv, ValidateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "create", resp.Operation)
+	assert.Equal(t, []string{
+		"example.com/v1 Widget is deprecated; use example.com/v2 Widget",
+		`unknown field "spec.colour"`,
+	}, resp.Warnings)
+	assert.Equal(t, []string{"POST dryRun=All&fieldValidation=Strict"}, queries)
+}
+
+func TestValidateResource_UpdateKeepsWarningsOnRejection(t *testing.T) {
+	var queries []string
+	srv := validateServer(true, true, &queries)
+	defer srv.Close()
+
+	resp, err := validateWidget(t, srv, ValidateOptions{FieldValidation: FieldValidationWarn})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.size: Invalid value")
+	require.NotNil(t, resp)
+	assert.Equal(t, "update", resp.Operation)
+	assert.Len(t, resp.Warnings, 2)
+	assert.Equal(t, []string{"PUT dryRun=All&fieldValidation=Warn"}, queries)
+}
+
+func TestContextWarningHandler_CollectsOnlyWithCollector(t *testing.T) {
+	ctx, collector := withWarningCollector(context.Background())
+	contextWarningHandler{}.HandleWarningHeaderWithContext(ctx, 299, "-", "deprecated")
+	contextWarningHandler{}.HandleWarningHeaderWithContext(ctx, 299, "-", "deprecated")
+	contextWarningHandler{}.HandleWarningHeaderWithContext(ctx, 199, "-", "misc")
+	contextWarningHandler{}.HandleWarningHeaderWithContext(context.Background(), 299, "-", "elsewhere")
+
+	assert.Equal(t, []string{"deprecated"}, collector.list())
+}
@@ -0,0 +1,18 @@
+// Package keyrotation re-encrypts OAuth provider tokens stored in Valkey
+// from an old AES-256 encryption key to a new one.
+//
+// mcp-oauth encrypts the access token, refresh token and ID token of every
+// stored provider token with a single key. Changing --oauth-encryption-key
+// therefore makes every stored token unreadable and logs all users out. A
+// Rotator walks the token keys, decrypts each sensitive field with whichever
+// of the two keys opens it, and writes the row back encrypted with the new
+// key. Rows already encrypted with the new key are left alone, so a rotation
+// can be repeated safely until no old-key rows remain.
+//
+// Writes are compare-and-set: a row changed by a running server between the
+// read and the write is skipped and picked up by the next pass.
+//
+// A server holding both keys serves tokens through a FallbackStore, which
+// reads a row the Rotator has not reached yet with the old key and
+// re-encrypts it with the new one.
+package keyrotation
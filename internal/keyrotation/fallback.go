@@ -0,0 +1,74 @@
+package keyrotation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/giantswarm/mcp-oauth/storage"
+	oauthvalkey "github.com/giantswarm/mcp-oauth/storage/valkey"
+	"golang.org/x/oauth2"
+)
+
+// tokenStore is the part of the mcp-oauth token store a FallbackStore reads
+// and writes provider tokens through.
+type tokenStore interface {
+	GetToken(ctx context.Context, userID string) (*oauth2.Token, error)
+	SaveToken(ctx context.Context, userID string, token *oauth2.Token) error
+	GetRefreshTokenInfo(ctx context.Context, refreshToken string) (string, error)
+	AtomicGetAndDeleteRefreshToken(ctx context.Context, refreshToken string) (string, string, *oauth2.Token, error)
+}
+
+// FallbackStore is the Valkey token store of a server holding both the
+// current and the previous encryption key. A provider token the current key
+// cannot decrypt is read with the previous key and written back encrypted
+// with the current one, so users whose rows the Rotator has not reached yet
+// stay logged in. Every other method is the current-key store's.
+type FallbackStore struct {
+	*oauthvalkey.Store
+
+	current  tokenStore
+	previous tokenStore
+	logger   *slog.Logger
+}
+
+// NewFallbackStore returns a store reading tokens with current and falling
+// back to previous, two stores on the same Valkey encrypting with the
+// current and the previous key.
+func NewFallbackStore(current, previous *oauthvalkey.Store, logger *slog.Logger) *FallbackStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FallbackStore{Store: current, current: current, previous: previous, logger: logger}
+}
+
+// GetToken returns the provider token of userID, re-encrypting it with the
+// current key when only the previous key decrypts it.
+func (s *FallbackStore) GetToken(ctx context.Context, userID string) (*oauth2.Token, error) {
+	token, err := s.current.GetToken(ctx, userID)
+	if err == nil || errors.Is(err, storage.ErrTokenNotFound) || errors.Is(err, storage.ErrTokenExpired) {
+		return token, err
+	}
+
+	previousToken, previousErr := s.previous.GetToken(ctx, userID)
+	if previousErr != nil {
+		return nil, err
+	}
+	if saveErr := s.current.SaveToken(ctx, userID, previousToken); saveErr != nil {
+		// The token is still readable with the previous key; the Rotator
+		// or the next read retries the write.
+		s.logger.Warn("failed to re-encrypt token with the current encryption key", "error", saveErr)
+	}
+	return previousToken, nil
+}
+
+// AtomicGetAndDeleteRefreshToken consumes refreshToken. The provider token
+// it refers to is decrypted after the refresh token is deleted, so it is
+// re-encrypted with the current key first; otherwise a refresh would fail
+// on a previous-key row and the refresh token would be lost.
+func (s *FallbackStore) AtomicGetAndDeleteRefreshToken(ctx context.Context, refreshToken string) (string, string, *oauth2.Token, error) {
+	if userID, err := s.current.GetRefreshTokenInfo(ctx, refreshToken); err == nil {
+		_, _ = s.GetToken(ctx, userID)
+	}
+	return s.current.AtomicGetAndDeleteRefreshToken(ctx, refreshToken)
+}
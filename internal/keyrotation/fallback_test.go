@@ -0,0 +1,117 @@
+package keyrotation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/giantswarm/mcp-oauth/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// keyedRow is a stored token and the key it is encrypted with.
+type keyedRow struct {
+	key   string
+	token *oauth2.Token
+}
+
+// keyedStore is a token store over rows shared between stores. It reads
+// only rows encrypted with its own key and writes rows with it.
+type keyedStore struct {
+	key     string
+	rows    map[string]keyedRow
+	refresh map[string]string
+	saveErr error
+}
+
+func (s *keyedStore) GetToken(_ context.Context, userID string) (*oauth2.Token, error) {
+	row, ok := s.rows[userID]
+	switch {
+	case !ok:
+		return nil, storage.ErrTokenNotFound
+	case row.key != s.key:
+		return nil, errors.New("failed to decrypt token: failed to decrypt access token")
+	}
+	return row.token, nil
+}
+
+func (s *keyedStore) SaveToken(_ context.Context, userID string, token *oauth2.Token) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.rows[userID] = keyedRow{key: s.key, token: token}
+	return nil
+}
+
+func (s *keyedStore) GetRefreshTokenInfo(_ context.Context, refreshToken string) (string, error) {
+	userID, ok := s.refresh[refreshToken]
+	if !ok {
+		return "", storage.ErrTokenNotFound
+	}
+	return userID, nil
+}
+
+func (s *keyedStore) AtomicGetAndDeleteRefreshToken(ctx context.Context, refreshToken string) (string, string, *oauth2.Token, error) {
+	userID, err := s.GetRefreshTokenInfo(ctx, refreshToken)
+	if err != nil {
+		return "", "", nil, err
+	}
+	delete(s.refresh, refreshToken)
+	token, err := s.GetToken(ctx, userID)
+	return userID, "client", token, err
+}
+
+// newKeyedStores returns a current-key and a previous-key store sharing
+// alice's token, encrypted with the previous key.
+func newKeyedStores() (*keyedStore, *keyedStore) {
+	rows := map[string]keyedRow{"alice": {key: "old", token: &oauth2.Token{AccessToken: "alice-token"}}}
+	refresh := map[string]string{"refresh-alice": "alice"}
+	return &keyedStore{key: "new", rows: rows, refresh: refresh}, &keyedStore{key: "old", rows: rows, refresh: refresh}
+}
+
+func TestFallbackStore_GetToken(t *testing.T) {
+	current, previous := newKeyedStores()
+	s := &FallbackStore{current: current, previous: previous, logger: slog.Default()}
+
+	token, err := s.GetToken(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice-token", token.AccessToken)
+	assert.Equal(t, "new", current.rows["alice"].key, "the token is re-encrypted with the current key")
+
+	_, err = s.GetToken(context.Background(), "bob")
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func TestFallbackStore_GetTokenUndecryptable(t *testing.T) {
+	current, previous := newKeyedStores()
+	current.rows["alice"] = keyedRow{key: "unknown", token: &oauth2.Token{AccessToken: "alice-token"}}
+	s := &FallbackStore{current: current, previous: previous, logger: slog.Default()}
+
+	_, err := s.GetToken(context.Background(), "alice")
+	assert.ErrorContains(t, err, "failed to decrypt token", "the current key's error is returned")
+}
+
+func TestFallbackStore_GetTokenSaveFails(t *testing.T) {
+	current, previous := newKeyedStores()
+	current.saveErr = errors.New("valkey unavailable")
+	s := &FallbackStore{current: current, previous: previous, logger: slog.Default()}
+
+	token, err := s.GetToken(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice-token", token.AccessToken)
+	assert.Equal(t, "old", current.rows["alice"].key)
+}
+
+func TestFallbackStore_AtomicGetAndDeleteRefreshToken(t *testing.T) {
+	current, previous := newKeyedStores()
+	s := &FallbackStore{current: current, previous: previous, logger: slog.Default()}
+
+	userID, _, token, err := s.AtomicGetAndDeleteRefreshToken(context.Background(), "refresh-alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", userID)
+	assert.Equal(t, "alice-token", token.AccessToken)
+	assert.NotContains(t, current.refresh, "refresh-alice")
+}
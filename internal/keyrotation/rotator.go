@@ -0,0 +1,258 @@
+package keyrotation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/giantswarm/mcp-oauth/security"
+	"github.com/giantswarm/mcp-oauth/storage"
+	oauthvalkey "github.com/giantswarm/mcp-oauth/storage/valkey"
+)
+
+// tokenKeySegment is the key namespace mcp-oauth stores provider tokens under.
+const tokenKeySegment = "token:"
+
+// DefaultWatchInterval is how often a server holding both keys re-runs the
+// rotation.
+const DefaultWatchInterval = time.Minute
+
+// scanBatchSize is the SCAN COUNT hint used while walking token keys.
+const scanBatchSize = 200
+
+// errUndecryptable is returned for a field neither key can decrypt.
+var errUndecryptable = errors.New("field cannot be decrypted with the old or the new key")
+
+// Store is the key-value access a Rotator needs.
+type Store interface {
+	// Scan returns keys matching pattern starting at cursor, and the cursor
+	// for the next call (0 when done).
+	Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error)
+
+	// Get returns the value of key and whether it exists.
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// CompareAndSet replaces the value of key with newValue, keeping its
+	// TTL, only if the current value is oldValue. Reports whether it did.
+	CompareAndSet(ctx context.Context, key, oldValue, newValue string) (bool, error)
+}
+
+// Result summarizes a rotation pass.
+type Result struct {
+	// Scanned is the number of token rows examined.
+	Scanned int `json:"scanned"`
+
+	// Rotated is the number of rows re-encrypted with the new key (or that
+	// would be, in a dry run).
+	Rotated int `json:"rotated"`
+
+	// Current is the number of rows already encrypted with the new key.
+	Current int `json:"current"`
+
+	// Conflicts is the number of rows changed concurrently and skipped.
+	Conflicts int `json:"conflicts"`
+
+	// Failed is the number of rows neither key could decrypt.
+	Failed int `json:"failed"`
+}
+
+// Rotator re-encrypts stored provider tokens from an old key to a new one.
+type Rotator struct {
+	store  Store
+	prefix string
+	oldKey *security.Encryptor
+	newKey *security.Encryptor
+	logger *slog.Logger
+}
+
+// NewRotator creates a rotator for token rows under keyPrefix, which
+// defaults to the mcp-oauth prefix when empty. Both keys must be valid,
+// distinct AES-256 keys.
+func NewRotator(store Store, keyPrefix string, oldKey, newKey []byte, logger *slog.Logger) (*Rotator, error) {
+	if len(oldKey) == 0 || len(newKey) == 0 {
+		return nil, fmt.Errorf("both the old and the new encryption key are required")
+	}
+	if bytes.Equal(oldKey, newKey) {
+		return nil, fmt.Errorf("the old and the new encryption key are identical")
+	}
+	oldEnc, err := security.NewEncryptor(oldKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid old encryption key: %w", err)
+	}
+	newEnc, err := security.NewEncryptor(newKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid new encryption key: %w", err)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if keyPrefix == "" {
+		keyPrefix = oauthvalkey.DefaultKeyPrefix
+	}
+	return &Rotator{store: store, prefix: keyPrefix, oldKey: oldEnc, newKey: newEnc, logger: logger}, nil
+}
+
+// Run makes one pass over all token rows. With dryRun set, rows are
+// inspected and counted but not written.
+func (r *Rotator) Run(ctx context.Context, dryRun bool) (Result, error) {
+	var result Result
+	pattern := r.prefix + tokenKeySegment + "*"
+	var cursor uint64
+
+	for {
+		keys, next, err := r.store.Scan(ctx, cursor, pattern, scanBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to scan token keys: %w", err)
+		}
+		for _, key := range keys {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			r.rotateKey(ctx, key, dryRun, &result)
+		}
+		if next == 0 {
+			return result, nil
+		}
+		cursor = next
+	}
+}
+
+// rotateKey rotates a single row and records the outcome in result.
+func (r *Rotator) rotateKey(ctx context.Context, key string, dryRun bool, result *Result) {
+	value, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		r.logger.Warn("failed to read token row", "key", key, "error", err)
+		result.Failed++
+		return
+	}
+	if !ok {
+		// Expired or deleted since the scan.
+		return
+	}
+	result.Scanned++
+
+	rotated, changed, err := r.rotateValue(value)
+	if err != nil {
+		r.logger.Warn("failed to re-encrypt token row", "key", key, "error", err)
+		result.Failed++
+		return
+	}
+	if !changed {
+		result.Current++
+		return
+	}
+	if dryRun {
+		result.Rotated++
+		return
+	}
+
+	swapped, err := r.store.CompareAndSet(ctx, key, value, rotated)
+	switch {
+	case err != nil:
+		r.logger.Warn("failed to write re-encrypted token row", "key", key, "error", err)
+		result.Failed++
+	case !swapped:
+		result.Conflicts++
+	default:
+		result.Rotated++
+	}
+}
+
+// rotateValue re-encrypts the sensitive fields of a serialized token. It
+// reports whether any field was encrypted with the old key.
+func (r *Rotator) rotateValue(value string) (string, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(value)))
+	dec.UseNumber()
+	var row map[string]interface{}
+	if err := dec.Decode(&row); err != nil {
+		return "", false, fmt.Errorf("failed to parse token row: %w", err)
+	}
+
+	changed := false
+	rotateField := func(m map[string]interface{}, field string) error {
+		s, ok := m[field].(string)
+		if !ok || s == "" {
+			return nil
+		}
+		out, rotated, err := r.rotateString(s)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if rotated {
+			m[field] = out
+			changed = true
+		}
+		return nil
+	}
+
+	for _, field := range []string{"access_token", "refresh_token"} {
+		if err := rotateField(row, field); err != nil {
+			return "", false, err
+		}
+	}
+	if extra, ok := row["extra"].(map[string]interface{}); ok {
+		for _, field := range storage.SensitiveExtraFields {
+			if err := rotateField(extra, field); err != nil {
+				return "", false, err
+			}
+		}
+	}
+
+	if !changed {
+		return value, false, nil
+	}
+	out, err := json.Marshal(row)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to serialize token row: %w", err)
+	}
+	return string(out), true, nil
+}
+
+// rotateString returns s re-encrypted with the new key, or reports that it
+// already is.
+func (r *Rotator) rotateString(s string) (string, bool, error) {
+	if _, err := r.newKey.Decrypt(s); err == nil {
+		return s, false, nil
+	}
+	plaintext, err := r.oldKey.Decrypt(s)
+	if err != nil {
+		return "", false, errUndecryptable
+	}
+	out, err := r.newKey.Encrypt(plaintext)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to encrypt with the new key: %w", err)
+	}
+	return out, true, nil
+}
+
+// Watch runs a pass every interval until ctx is done. Servers run it while
+// both keys are configured, so rows still written with the old key by
+// replicas that have not been restarted yet are converted.
+func (r *Rotator) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := r.Run(ctx, false)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			r.logger.Warn("encryption key rotation pass failed", "error", err)
+		case result.Rotated > 0 || result.Failed > 0:
+			r.logger.Info("encryption key rotation pass completed",
+				"scanned", result.Scanned,
+				"rotated", result.Rotated,
+				"conflicts", result.Conflicts,
+				"failed", result.Failed)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package keyrotation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/mcp-oauth/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store. Scan returns two keys per call so
+// tests exercise cursor handling.
+type memoryStore struct {
+	data map[string]string
+
+	// beforeCAS, when set, runs before each CompareAndSet to simulate a
+	// concurrent writer.
+	beforeCAS func(key string)
+}
+
+func (s *memoryStore) Scan(_ context.Context, cursor uint64, pattern string, _ int64) ([]string, uint64, error) {
+	var keys []string
+	prefix := strings.TrimSuffix(pattern, "*")
+	for k := range s.data {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	start := int(cursor)
+	end := min(start+2, len(keys))
+	next := uint64(end)
+	if end == len(keys) {
+		next = 0
+	}
+	return keys[start:end], next, nil
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (string, bool, error) {
+	v, ok := s.data[key]
+	return v, ok, nil
+}
+
+func (s *memoryStore) CompareAndSet(_ context.Context, key, oldValue, newValue string) (bool, error) {
+	if s.beforeCAS != nil {
+		s.beforeCAS(key)
+	}
+	if s.data[key] != oldValue {
+		return false, nil
+	}
+	s.data[key] = newValue
+	return true, nil
+}
+
+func testKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := security.GenerateKey()
+	require.NoError(t, err)
+	return key
+}
+
+// tokenRow serializes a provider token the way mcp-oauth's Valkey store
+// does, encrypting the sensitive fields with key.
+func tokenRow(t *testing.T, key []byte, access, refresh, idToken string) string {
+	t.Helper()
+	enc, err := security.NewEncryptor(key)
+	require.NoError(t, err)
+	encrypt := func(s string) string {
+		out, err := enc.Encrypt(s)
+		require.NoError(t, err)
+		return out
+	}
+	row := map[string]interface{}{
+		"access_token":  encrypt(access),
+		"token_type":    "Bearer",
+		"refresh_token": encrypt(refresh),
+		"expiry":        "2026-10-16T12:00:00Z",
+		"extra": map[string]interface{}{
+			"id_token":   encrypt(idToken),
+			"scope":      "openid email",
+			"expires_in": 3600,
+		},
+	}
+	data, err := json.Marshal(row)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// decryptRow decrypts the sensitive fields of row with key.
+func decryptRow(t *testing.T, key []byte, row string) (access, refresh, idToken string, extra map[string]interface{}) {
+	t.Helper()
+	enc, err := security.NewEncryptor(key)
+	require.NoError(t, err)
+	var parsed struct {
+		AccessToken  string                 `json:"access_token"`
+		RefreshToken string                 `json:"refresh_token"`
+		Extra        map[string]interface{} `json:"extra"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(row), &parsed))
+	access, err = enc.Decrypt(parsed.AccessToken)
+	require.NoError(t, err)
+	refresh, err = enc.Decrypt(parsed.RefreshToken)
+	require.NoError(t, err)
+	idToken, err = enc.Decrypt(parsed.Extra["id_token"].(string))
+	require.NoError(t, err)
+	return access, refresh, idToken, parsed.Extra
+}
+
+func TestNewRotator_DefaultPrefix(t *testing.T) {
+	r, err := NewRotator(&memoryStore{}, "", testKey(t), testKey(t), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mcp:", r.prefix)
+}
+
+func TestNewRotator_Validation(t *testing.T) {
+	key := testKey(t)
+
+	_, err := NewRotator(&memoryStore{}, "mcp:", nil, key, nil)
+	assert.Error(t, err)
+
+	_, err = NewRotator(&memoryStore{}, "mcp:", key, key, nil)
+	assert.ErrorContains(t, err, "identical")
+
+	_, err = NewRotator(&memoryStore{}, "mcp:", []byte("short"), key, nil)
+	assert.ErrorContains(t, err, "invalid old encryption key")
+}
+
+func TestRotator_Run(t *testing.T) {
+	oldKey, newKey, otherKey := testKey(t), testKey(t), testKey(t)
+	store := &memoryStore{data: map[string]string{
+		"mcp:token:alice":   tokenRow(t, oldKey, "a-access", "a-refresh", "a-id"),
+		"mcp:token:bob":     tokenRow(t, oldKey, "b-access", "b-refresh", "b-id"),
+		"mcp:token:carol":   tokenRow(t, newKey, "c-access", "c-refresh", "c-id"),
+		"mcp:token:mallory": tokenRow(t, otherKey, "m-access", "m-refresh", "m-id"),
+		"mcp:client:abc":    `{"client_id":"abc"}`,
+		"other:token:dave":  tokenRow(t, oldKey, "d-access", "d-refresh", "d-id"),
+	}}
+	carol := store.data["mcp:token:carol"]
+
+	r, err := NewRotator(store, "mcp:", oldKey, newKey, nil)
+	require.NoError(t, err)
+
+	result, err := r.Run(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Scanned: 4, Rotated: 2, Current: 1, Failed: 1}, result)
+
+	access, refresh, idToken, extra := decryptRow(t, newKey, store.data["mcp:token:alice"])
+	assert.Equal(t, []string{"a-access", "a-refresh", "a-id"}, []string{access, refresh, idToken})
+	assert.Equal(t, "openid email", extra["scope"], "non-sensitive fields are preserved")
+	assert.Equal(t, float64(3600), extra["expires_in"])
+
+	assert.Equal(t, carol, store.data["mcp:token:carol"], "rows already on the new key are untouched")
+	assert.Equal(t, `{"client_id":"abc"}`, store.data["mcp:client:abc"], "only token rows are rotated")
+
+	// A second pass finds nothing left to do.
+	result, err = r.Run(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Scanned: 4, Current: 3, Failed: 1}, result)
+}
+
+func TestRotator_DryRun(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+	row := tokenRow(t, oldKey, "access", "refresh", "id")
+	store := &memoryStore{data: map[string]string{"mcp:token:alice": row}}
+
+	r, err := NewRotator(store, "mcp:", oldKey, newKey, nil)
+	require.NoError(t, err)
+
+	result, err := r.Run(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Scanned: 1, Rotated: 1}, result)
+	assert.Equal(t, row, store.data["mcp:token:alice"], "dry runs do not write")
+}
+
+func TestRotator_Conflict(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+	store := &memoryStore{data: map[string]string{"mcp:token:alice": tokenRow(t, oldKey, "access", "refresh", "id")}}
+	fresh := tokenRow(t, newKey, "new-access", "new-refresh", "new-id")
+	store.beforeCAS = func(key string) { store.data[key] = fresh }
+
+	r, err := NewRotator(store, "mcp:", oldKey, newKey, nil)
+	require.NoError(t, err)
+
+	result, err := r.Run(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Scanned: 1, Conflicts: 1}, result)
+	assert.Equal(t, fresh, store.data["mcp:token:alice"], "concurrent writes win")
+}
+
+// failingScanStore fails every Scan.
+type failingScanStore struct{ memoryStore }
+
+func (s *failingScanStore) Scan(context.Context, uint64, string, int64) ([]string, uint64, error) {
+	return nil, 0, errors.New("connection refused")
+}
+
+func TestRotator_ScanError(t *testing.T) {
+	r, err := NewRotator(&failingScanStore{}, "mcp:", testKey(t), testKey(t), nil)
+	require.NoError(t, err)
+
+	_, err = r.Run(context.Background(), false)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestRotator_Watch(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+	store := &memoryStore{data: map[string]string{"mcp:token:alice": tokenRow(t, oldKey, "access", "refresh", "id")}}
+
+	r, err := NewRotator(store, "mcp:", oldKey, newKey, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store.beforeCAS = func(string) { cancel() }
+	done := make(chan struct{})
+	go func() {
+		r.Watch(ctx, time.Millisecond)
+		close(done)
+	}()
+	<-done
+
+	access, _, _, _ := decryptRow(t, newKey, store.data["mcp:token:alice"])
+	assert.Equal(t, "access", access, "rows are rotated on each tick")
+}
@@ -0,0 +1,64 @@
+package keyrotation
+
+import (
+	"context"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// compareAndSetScript replaces a value only if it is unchanged, keeping its
+// TTL. KEYS[1] key; ARGV[1] expected value; ARGV[2] new value.
+const compareAndSetScript = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'KEEPTTL')
+return 1
+`
+
+// ValkeyStore implements Store on a Valkey connection.
+type ValkeyStore struct {
+	client valkey.Client
+	cas    *valkey.Lua
+}
+
+// NewValkeyStore creates a store on client.
+func NewValkeyStore(client valkey.Client) *ValkeyStore {
+	return &ValkeyStore{client: client, cas: valkey.NewLuaScript(compareAndSetScript)}
+}
+
+// Scan implements Store.
+func (s *ValkeyStore) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	entry, err := s.client.Do(ctx, s.client.B().Scan().Cursor(cursor).Match(pattern).Count(count).Build()).AsScanEntry()
+	if err != nil {
+		return nil, 0, err
+	}
+	return entry.Elements, entry.Cursor, nil
+}
+
+// Get implements Store.
+func (s *ValkeyStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := s.client.Do(ctx, s.client.B().Get().Key(key).Build()).ToString()
+	if valkey.IsValkeyNil(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// CompareAndSet implements Store.
+func (s *ValkeyStore) CompareAndSet(ctx context.Context, key, oldValue, newValue string) (bool, error) {
+	n, err := s.cas.Exec(ctx, s.client, []string{key}, []string{oldValue, newValue}).AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Close closes the Valkey connection.
+func (s *ValkeyStore) Close() error {
+	s.client.Close()
+	return nil
+}
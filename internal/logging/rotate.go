@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated log files after the time they were rotated.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotatingFileConfig configures a RotatingFile.
+type RotatingFileConfig struct {
+	// Path is the log file. Rotated files are kept next to it as
+	// <path>.<timestamp>.
+	Path string
+
+	// MaxBytes rotates the file before a write would make it larger. 0
+	// disables size-based rotation.
+	MaxBytes int64
+
+	// Interval rotates the file once it has been written to for this long.
+	// 0 disables time-based rotation.
+	Interval time.Duration
+
+	// MaxBackups is the number of rotated files kept. 0 keeps all of them.
+	MaxBackups int
+}
+
+// RotatingFile is an io.WriteCloser that appends to a log file and rotates
+// it by size and age. It is safe for concurrent use.
+type RotatingFile struct {
+	config RotatingFileConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotatingFile opens the log file of config, creating it and its
+// directory if needed.
+func NewRotatingFile(config RotatingFileConfig) (*RotatingFile, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+	if config.MaxBytes < 0 || config.Interval < 0 || config.MaxBackups < 0 {
+		return nil, fmt.Errorf("log rotation limits must not be negative")
+	}
+	f := &RotatingFile{config: config, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the log file, rotating it first when p would exceed
+// MaxBytes or the file is older than Interval.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	// A failed rotation is reported, but p is still written as long as a
+	// file is open, so that no log line is lost.
+	var rotateErr error
+	if f.needsRotation(int64(len(p))) {
+		rotateErr = f.rotate()
+		if f.file == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+// Close closes the log file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// needsRotation reports whether the file must be rotated before n more
+// bytes are written. An empty file is never rotated, so a single write
+// larger than MaxBytes still lands in a file.
+func (f *RotatingFile) needsRotation(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.config.MaxBytes > 0 && f.size+n > f.config.MaxBytes {
+		return true
+	}
+	return f.config.Interval > 0 && f.now().Sub(f.openedAt) >= f.config.Interval
+}
+
+// open opens the log file for appending.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.config.Path), 0o750); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// rotate renames the log file to a backup, opens a new one and removes the
+// oldest backups beyond MaxBackups. When the file cannot be renamed, the
+// current file is reopened.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+	backup := f.config.Path + "." + f.now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(f.config.Path, backup); err != nil {
+		// Keep logging to the current file.
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.removeOldBackups()
+}
+
+// removeOldBackups removes the oldest rotated files beyond MaxBackups.
+// Backup names sort in rotation order.
+func (f *RotatingFile) removeOldBackups() error {
+	if f.config.MaxBackups == 0 {
+		return nil
+	}
+	dir, base := filepath.Split(f.config.Path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list log backups: %w", err)
+	}
+	var backups []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), base+".") {
+			backups = append(backups, e.Name())
+		}
+	}
+	slices.Sort(backups)
+	for len(backups) > f.config.MaxBackups {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return fmt.Errorf("failed to remove log backup: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logFiles returns the log file and its backups in dir, sorted by name.
+func logFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestRotatingFile_SizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, MaxBytes: 10, MaxBackups: 2})
+	require.NoError(t, err)
+	f.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+	defer func() { _ = f.Close() }()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	names := logFiles(t, dir)
+	require.Len(t, names, 3, "the log file and two backups: %v", names)
+	assert.Equal(t, "server.log", names[0])
+	for _, name := range names[1:] {
+		assert.True(t, strings.HasPrefix(name, "server.log.2026"), name)
+	}
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(current))
+	newest, err := os.ReadFile(filepath.Join(dir, names[2]))
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(newest))
+}
+
+func TestRotatingFile_IntervalRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, Interval: time.Hour})
+	require.NoError(t, err)
+	f.now = func() time.Time { return clock }
+	f.openedAt = clock
+	defer func() { _ = f.Close() }()
+
+	_, err = f.Write([]byte("one\n"))
+	require.NoError(t, err)
+	clock = clock.Add(30 * time.Minute)
+	_, err = f.Write([]byte("two\n"))
+	require.NoError(t, err)
+	assert.Len(t, logFiles(t, dir), 1)
+
+	clock = clock.Add(30 * time.Minute)
+	_, err = f.Write([]byte("three\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server.log", "server.log.20260102T040405.000"}, logFiles(t, dir))
+
+	backup, err := os.ReadFile(filepath.Join(dir, "server.log.20260102T040405.000"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(backup))
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "server.log")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path})
+	require.NoError(t, err)
+	_, err = f.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old\nnew\n", string(content))
+
+	_, err = f.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestNewRotatingFile_Invalid(t *testing.T) {
+	_, err := NewRotatingFile(RotatingFileConfig{})
+	assert.Error(t, err)
+
+	_, err = NewRotatingFile(RotatingFileConfig{Path: filepath.Join(t.TempDir(), "server.log"), MaxBytes: -1})
+	assert.Error(t, err)
+}
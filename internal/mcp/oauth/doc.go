@@ -61,6 +61,17 @@
 //
 // See: https://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
 //
+// # ID Token Refresh
+//
+// Downstream Kubernetes authentication uses the provider's ID token, which
+// expires long before a user's MCP session does. [IDTokenRefresher] refreshes
+// the stored ID token with the refresh token shortly before it expires:
+//
+//   - [IDTokenRefresher.IDToken]: Returns a valid ID token, refreshing it if needed
+//   - [IDTokenSource]: An oauth2.TokenSource checking the token's lifetime on each call
+//   - [ContextWithIDTokenSource]: Stores the source for Kubernetes client creation
+//   - [IDTokenExpiry]: Reads the expiry of an ID token
+//
 // # Dependency Security Note
 //
 // This package depends on github.com/giantswarm/mcp-oauth for OAuth 2.1 implementation.
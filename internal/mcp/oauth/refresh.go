@@ -0,0 +1,246 @@
+package oauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultRefreshSkew is how long before its expiry an ID token is
+	// refreshed, so a request never starts with a token about to lapse.
+	DefaultRefreshSkew = 2 * time.Minute
+
+	// refreshTimeout bounds a refresh started by a token source, which has
+	// no request context of its own.
+	refreshTimeout = 30 * time.Second
+
+	// idTokenSourceKey is the context key for the user's ID token source.
+	//nolint:gosec // G101 false positive - this is a context key name, not a credential
+	idTokenSourceKey contextKey = "oauth_id_token_source"
+)
+
+// ErrIDTokenExpired is returned when an ID token has expired and cannot be
+// refreshed, either because no refresh token is stored or the refresh failed.
+var ErrIDTokenExpired = errors.New("ID token expired and could not be refreshed")
+
+// TokenStore is the part of the mcp-oauth token store that ID token refresh
+// reads and writes. storage.TokenStore satisfies it.
+type TokenStore interface {
+	GetToken(ctx context.Context, key string) (*oauth2.Token, error)
+	SaveToken(ctx context.Context, key string, token *oauth2.Token) error
+}
+
+// TokenRefresher mints new provider tokens from a refresh token.
+// providers.Provider satisfies it.
+type TokenRefresher interface {
+	RefreshToken(ctx context.Context, refreshToken string) (*oauth2.Token, error)
+}
+
+// IDTokenRefresher hands out provider ID tokens for downstream Kubernetes
+// authentication, refreshing them with the stored refresh token shortly
+// before they expire. Refreshed tokens are saved back to the token store
+// under the same key, and OnRefresh is called so that clients built from
+// the previous token can be dropped.
+type IDTokenRefresher struct {
+	store     TokenStore
+	refresher TokenRefresher
+
+	// Skew is how long before expiry a token is refreshed.
+	Skew time.Duration
+
+	// OnRefresh, if set, is called with the user's email after a refresh.
+	OnRefresh func(ctx context.Context, email string)
+
+	now   func() time.Time
+	group singleflight.Group
+}
+
+// NewIDTokenRefresher creates a refresher over the given store and provider.
+func NewIDTokenRefresher(store TokenStore, refresher TokenRefresher) *IDTokenRefresher {
+	return &IDTokenRefresher{
+		store:     store,
+		refresher: refresher,
+		Skew:      DefaultRefreshSkew,
+		now:       time.Now,
+	}
+}
+
+// IDToken returns the ID token stored under key, refreshing it first when
+// it expires within Skew. If the refresh fails while the current token is
+// still valid, the current token is returned; once it has expired,
+// ErrIDTokenExpired is returned.
+func (r *IDTokenRefresher) IDToken(ctx context.Context, key, email string) (string, error) {
+	token, err := r.store.GetToken(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token: %w", err)
+	}
+	idToken := GetIDToken(token)
+	if idToken == "" {
+		return "", fmt.Errorf("no ID token in stored token")
+	}
+
+	expiry, ok := IDTokenExpiry(idToken)
+	if !ok || expiry.Sub(r.now()) > r.Skew {
+		return idToken, nil
+	}
+	if token.RefreshToken == "" {
+		if r.now().Before(expiry) {
+			return idToken, nil
+		}
+		return "", ErrIDTokenExpired
+	}
+
+	// Concurrent requests for the same key share one refresh: a second use
+	// of a rotated refresh token would be rejected by the provider.
+	refreshed, err, _ := r.group.Do(key, func() (interface{}, error) {
+		return r.refresh(context.WithoutCancel(ctx), key, email, token)
+	})
+	if err != nil {
+		if r.now().Before(expiry) {
+			return idToken, nil
+		}
+		return "", fmt.Errorf("%w: %v", ErrIDTokenExpired, err)
+	}
+	return refreshed.(string), nil
+}
+
+// refresh mints a new token from the stored refresh token and saves it.
+func (r *IDTokenRefresher) refresh(ctx context.Context, key, email string, token *oauth2.Token) (string, error) {
+	newToken, err := r.refresher.RefreshToken(ctx, token.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
+	}
+	idToken := GetIDToken(newToken)
+	if idToken == "" {
+		return "", fmt.Errorf("refreshed token has no ID token")
+	}
+	// Providers that do not rotate refresh tokens omit them from the
+	// response; keep the old one so the next refresh can still happen.
+	if newToken.RefreshToken == "" {
+		newToken.RefreshToken = token.RefreshToken
+	}
+	if err := r.store.SaveToken(ctx, key, newToken); err != nil {
+		return "", fmt.Errorf("failed to save refreshed token: %w", err)
+	}
+	if r.OnRefresh != nil {
+		r.OnRefresh(ctx, email)
+	}
+	return idToken, nil
+}
+
+// TokenSource returns a token source for the ID token stored under key.
+// Its lifetime is checked on every call, so a client built on it keeps
+// working across refreshes instead of failing once the token it was
+// created with expires.
+func (r *IDTokenRefresher) TokenSource(key, email string) *IDTokenSource {
+	return &IDTokenSource{refresher: r, key: key, email: email}
+}
+
+// IDTokenSource is an oauth2.TokenSource yielding a user's current ID token.
+type IDTokenSource struct {
+	refresher  *IDTokenRefresher
+	key, email string
+
+	mu     sync.Mutex
+	last   string
+	expiry time.Time
+}
+
+// Key identifies the stored token the source reads. Sources with the same
+// key yield the same tokens.
+func (s *IDTokenSource) Key() string {
+	return s.key
+}
+
+// Token returns the current ID token as a bearer token, going back to the
+// refresher only when the last one is within the refresh skew of expiry.
+// If that fails, the last token is used for as long as it is still valid.
+// Tokens without an exp claim are treated as not expiring.
+func (s *IDTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.refresher.now()
+	if s.last != "" && (s.expiry.IsZero() || s.expiry.Sub(now) > s.refresher.Skew) {
+		return s.token(), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+	idToken, err := s.refresher.IDToken(ctx, s.key, s.email)
+	if err != nil {
+		if s.last != "" && now.Before(s.expiry) {
+			return s.token(), nil
+		}
+		return nil, err
+	}
+
+	s.last = idToken
+	s.expiry, _ = IDTokenExpiry(idToken)
+	return s.token(), nil
+}
+
+func (s *IDTokenSource) token() *oauth2.Token {
+	return &oauth2.Token{AccessToken: s.last, TokenType: "Bearer", Expiry: s.expiry}
+}
+
+// ContextWithIDTokenSource creates a context carrying the user's ID token
+// source, for clients that outlive the request's ID token.
+func ContextWithIDTokenSource(ctx context.Context, source *IDTokenSource) context.Context {
+	return context.WithValue(ctx, idTokenSourceKey, source)
+}
+
+// GetIDTokenSourceFromContext retrieves the ID token source from the context.
+func GetIDTokenSourceFromContext(ctx context.Context) (*IDTokenSource, bool) {
+	source, ok := ctx.Value(idTokenSourceKey).(*IDTokenSource)
+	return source, ok && source != nil
+}
+
+// idTokenClaims are the claims read from an ID token without verifying it.
+type idTokenClaims struct {
+	Expiry int64  `json:"exp"`
+	Email  string `json:"email"`
+}
+
+// parseIDTokenClaims decodes the payload of a JWT. The signature is not
+// checked: the token came from the provider and is verified by Kubernetes.
+func parseIDTokenClaims(idToken string) (idTokenClaims, bool) {
+	var claims idTokenClaims
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return claims, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, false
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, false
+	}
+	return claims, true
+}
+
+// IDTokenExpiry returns the expiry of an ID token from its exp claim, or
+// false if the token is not a JWT or has no expiry.
+func IDTokenExpiry(idToken string) (time.Time, bool) {
+	claims, ok := parseIDTokenClaims(idToken)
+	if !ok || claims.Expiry == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Expiry, 0), true
+}
+
+// IDTokenEmail returns the email claim of an ID token, or "" if absent.
+func IDTokenEmail(idToken string) string {
+	claims, _ := parseIDTokenClaims(idToken)
+	return claims.Email
+}
@@ -0,0 +1,198 @@
+package oauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// testIDToken builds an unsigned JWT with the given expiry and email.
+func testIDToken(t *testing.T, expiry time.Time, email string) string {
+	t.Helper()
+	payload, err := json.Marshal(map[string]interface{}{"exp": expiry.Unix(), "email": email})
+	require.NoError(t, err)
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func testProviderToken(idToken, refreshToken string) *oauth2.Token {
+	return (&oauth2.Token{AccessToken: "access", RefreshToken: refreshToken}).WithExtra(map[string]interface{}{"id_token": idToken})
+}
+
+type memoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*oauth2.Token
+}
+
+func (s *memoryTokenStore) GetToken(_ context.Context, key string) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, errors.New("token not found")
+	}
+	return token, nil
+}
+
+func (s *memoryTokenStore) SaveToken(_ context.Context, key string, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[key] = token
+	return nil
+}
+
+type fakeRefresher struct {
+	token *oauth2.Token
+	err   error
+	calls []string
+}
+
+func (f *fakeRefresher) RefreshToken(_ context.Context, refreshToken string) (*oauth2.Token, error) {
+	f.calls = append(f.calls, refreshToken)
+	return f.token, f.err
+}
+
+func newTestRefresher(t *testing.T, stored *oauth2.Token, provider *fakeRefresher, now time.Time) (*IDTokenRefresher, *memoryTokenStore, *[]string) {
+	t.Helper()
+	store := &memoryTokenStore{tokens: map[string]*oauth2.Token{"session": stored}}
+	r := NewIDTokenRefresher(store, provider)
+	r.now = func() time.Time { return now }
+	var refreshed []string
+	r.OnRefresh = func(_ context.Context, email string) { refreshed = append(refreshed, email) }
+	return r, store, &refreshed
+}
+
+func TestIDTokenExpiry(t *testing.T) {
+	expiry := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	got, ok := IDTokenExpiry(testIDToken(t, expiry, "alice@example.com"))
+	require.True(t, ok)
+	assert.True(t, expiry.Equal(got))
+	assert.Equal(t, "alice@example.com", IDTokenEmail(testIDToken(t, expiry, "alice@example.com")))
+
+	_, ok = IDTokenExpiry("opaque-token")
+	assert.False(t, ok)
+	_, ok = IDTokenExpiry("a.!!!.c")
+	assert.False(t, ok)
+	assert.Empty(t, IDTokenEmail("opaque-token"))
+}
+
+func TestIDTokenRefresher_IDToken(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("valid token is returned as is", func(t *testing.T) {
+		current := testIDToken(t, now.Add(time.Hour), "alice@example.com")
+		provider := &fakeRefresher{}
+		r, _, refreshed := newTestRefresher(t, testProviderToken(current, "refresh-1"), provider, now)
+
+		idToken, err := r.IDToken(ctx, "session", "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, current, idToken)
+		assert.Empty(t, provider.calls)
+		assert.Empty(t, *refreshed)
+	})
+
+	t.Run("token near expiry is refreshed and saved", func(t *testing.T) {
+		current := testIDToken(t, now.Add(time.Minute), "alice@example.com")
+		fresh := testIDToken(t, now.Add(time.Hour), "alice@example.com")
+		provider := &fakeRefresher{token: testProviderToken(fresh, "")}
+		r, store, refreshed := newTestRefresher(t, testProviderToken(current, "refresh-1"), provider, now)
+
+		idToken, err := r.IDToken(ctx, "session", "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, fresh, idToken)
+		assert.Equal(t, []string{"refresh-1"}, provider.calls)
+		assert.Equal(t, []string{"alice@example.com"}, *refreshed)
+
+		saved := store.tokens["session"]
+		assert.Equal(t, fresh, GetIDToken(saved))
+		assert.Equal(t, "refresh-1", saved.RefreshToken, "refresh token is kept when the provider omits it")
+	})
+
+	t.Run("failed refresh keeps a still valid token", func(t *testing.T) {
+		current := testIDToken(t, now.Add(time.Minute), "alice@example.com")
+		provider := &fakeRefresher{err: errors.New("invalid_grant")}
+		r, _, refreshed := newTestRefresher(t, testProviderToken(current, "refresh-1"), provider, now)
+
+		idToken, err := r.IDToken(ctx, "session", "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, current, idToken)
+		assert.Empty(t, *refreshed)
+	})
+
+	t.Run("expired token without refresh token", func(t *testing.T) {
+		current := testIDToken(t, now.Add(-time.Minute), "alice@example.com")
+		r, _, _ := newTestRefresher(t, testProviderToken(current, ""), &fakeRefresher{}, now)
+
+		_, err := r.IDToken(ctx, "session", "alice@example.com")
+		assert.ErrorIs(t, err, ErrIDTokenExpired)
+	})
+
+	t.Run("expired token with failing refresh", func(t *testing.T) {
+		current := testIDToken(t, now.Add(-time.Minute), "alice@example.com")
+		provider := &fakeRefresher{err: errors.New("invalid_grant")}
+		r, _, _ := newTestRefresher(t, testProviderToken(current, "refresh-1"), provider, now)
+
+		_, err := r.IDToken(ctx, "session", "alice@example.com")
+		assert.ErrorIs(t, err, ErrIDTokenExpired)
+		assert.ErrorContains(t, err, "invalid_grant")
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		r, _, _ := newTestRefresher(t, nil, &fakeRefresher{}, now)
+		_, err := r.IDToken(ctx, "other", "alice@example.com")
+		assert.ErrorContains(t, err, "failed to get token")
+	})
+}
+
+func TestIDTokenSource(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := testIDToken(t, now.Add(10*time.Minute), "alice@example.com")
+	second := testIDToken(t, now.Add(time.Hour), "alice@example.com")
+	provider := &fakeRefresher{token: testProviderToken(second, "refresh-2")}
+	r, store, refreshed := newTestRefresher(t, testProviderToken(first, "refresh-1"), provider, now)
+	r.now = func() time.Time { return now }
+
+	source := r.TokenSource("session", "alice@example.com")
+	assert.Equal(t, "session", source.Key())
+
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, first, token.AccessToken)
+	assert.Equal(t, "Bearer", token.TokenType)
+
+	// Within the skew of expiry, the next call refreshes.
+	now = now.Add(9 * time.Minute)
+	token, err = source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, second, token.AccessToken)
+	assert.Equal(t, []string{"alice@example.com"}, *refreshed)
+
+	// Once the stored token is gone, the last token is used while valid.
+	delete(store.tokens, "session")
+	now = now.Add(50 * time.Minute)
+	token, err = source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, second, token.AccessToken)
+
+	now = now.Add(2 * time.Minute)
+	_, err = source.Token()
+	assert.ErrorContains(t, err, "failed to get token")
+}
+
+func TestContextWithIDTokenSource(t *testing.T) {
+	_, ok := GetIDTokenSourceFromContext(context.Background())
+	assert.False(t, ok)
+
+	source := NewIDTokenRefresher(&memoryTokenStore{}, &fakeRefresher{}).TokenSource("session", "alice@example.com")
+	got, ok := GetIDTokenSourceFromContext(ContextWithIDTokenSource(context.Background(), source))
+	require.True(t, ok)
+	assert.Same(t, source, got)
+}
@@ -0,0 +1,11 @@
+// Package plan holds change-sets: named lists of proposed operations that a
+// caller builds up one tool call at a time, reviews as a whole, and then
+// applies in a single call.
+//
+// A Plan only records the tool and arguments of each operation; validating
+// them with a server-side dry-run, rendering their diffs and running them
+// is left to the tools that use the Store. Plans are private to the caller
+// that built them and expire when they are left untouched for the Store's
+// TTL. They live in the memory of the replica that holds them, so behind a
+// load balancer all calls for a plan must reach the same replica.
+package plan
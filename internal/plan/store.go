@@ -0,0 +1,203 @@
+package plan
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a plan is kept after its last change.
+const DefaultTTL = time.Hour
+
+// DefaultMaxOperations caps the number of operations in a plan.
+const DefaultMaxOperations = 50
+
+// DefaultMaxPlans caps the number of plans a Store holds.
+const DefaultMaxPlans = 1000
+
+var (
+	// ErrNotFound is returned for plans that were never created, have
+	// expired, were discarded or belong to another caller.
+	ErrNotFound = errors.New("plan is unknown, expired or already applied")
+
+	// ErrTooManyOperations is returned when an operation is added to a
+	// full plan.
+	ErrTooManyOperations = errors.New("plan has too many operations")
+
+	// ErrTooManyPlans is returned when the Store is full.
+	ErrTooManyPlans = errors.New("too many plans")
+)
+
+// Operation is a proposed tool call in a plan.
+type Operation struct {
+	// Tool is the name of the tool to call.
+	Tool string
+
+	// Arguments are the arguments of the tool call.
+	Arguments map[string]interface{}
+}
+
+// Plan is a named list of operations built by one caller.
+type Plan struct {
+	// Name identifies the plan among the caller's plans.
+	Name string
+
+	// Caller identifies who built the plan; only they can see, apply or
+	// discard it.
+	Caller string
+
+	// Operations are run in order when the plan is applied.
+	Operations []Operation
+
+	// ExpiresAt is when the plan is dropped unless it changes. Set by the
+	// Store.
+	ExpiresAt time.Time
+}
+
+// Store holds plans until they are applied, discarded or expire.
+type Store struct {
+	mu            sync.Mutex
+	plans         map[planKey]*Plan
+	ttl           time.Duration
+	maxOperations int
+	maxPlans      int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+type planKey struct {
+	caller, name string
+}
+
+// NewStore creates a store whose plans expire ttl after their last change.
+// A ttl of zero or less uses DefaultTTL.
+func NewStore(ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{
+		plans:         make(map[planKey]*Plan),
+		ttl:           ttl,
+		maxOperations: DefaultMaxOperations,
+		maxPlans:      DefaultMaxPlans,
+		now:           time.Now,
+	}
+}
+
+// TTL returns how long a plan is kept after its last change.
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// MaxOperations returns the number of operations a plan can hold.
+func (s *Store) MaxOperations() int {
+	return s.maxOperations
+}
+
+// Add appends op to the caller's plan name, creating the plan if needed,
+// and returns a copy of the plan.
+func (s *Store) Add(caller, name string, op Operation) (*Plan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+
+	key := planKey{caller, name}
+	p, ok := s.plans[key]
+	if !ok {
+		if len(s.plans) >= s.maxPlans {
+			return nil, ErrTooManyPlans
+		}
+		p = &Plan{Name: name, Caller: caller}
+		s.plans[key] = p
+	}
+	if len(p.Operations) >= s.maxOperations {
+		return nil, ErrTooManyOperations
+	}
+	p.Operations = append(p.Operations, op)
+	p.ExpiresAt = s.now().Add(s.ttl)
+	return p.clone(), nil
+}
+
+// Get returns a copy of the caller's plan name.
+func (s *Store) Get(caller, name string) (*Plan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := s.getLocked(caller, name)
+	if err != nil {
+		return nil, err
+	}
+	return p.clone(), nil
+}
+
+// Take removes and returns the caller's plan name, so that it is applied
+// at most once at a time. Put returns it to the Store.
+func (s *Store) Take(caller, name string) (*Plan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := s.getLocked(caller, name)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.plans, planKey{caller, name})
+	return p, nil
+}
+
+// Put returns a plan removed by Take, unless the caller built a new plan
+// of the same name in the meantime. Its expiry is renewed.
+func (s *Store) Put(p *Plan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := planKey{p.Caller, p.Name}
+	if _, ok := s.plans[key]; ok {
+		return
+	}
+	p.ExpiresAt = s.now().Add(s.ttl)
+	s.plans[key] = p
+}
+
+// Discard removes the caller's plan name.
+func (s *Store) Discard(caller, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getLocked(caller, name); err != nil {
+		return err
+	}
+	delete(s.plans, planKey{caller, name})
+	return nil
+}
+
+// Len returns the number of plans, including expired ones not yet evicted.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.plans)
+}
+
+func (s *Store) getLocked(caller, name string) (*Plan, error) {
+	key := planKey{caller, name}
+	p, ok := s.plans[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !s.now().Before(p.ExpiresAt) {
+		delete(s.plans, key)
+		return nil, ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *Store) evictExpiredLocked() {
+	now := s.now()
+	for key, p := range s.plans {
+		if !now.Before(p.ExpiresAt) {
+			delete(s.plans, key)
+		}
+	}
+}
+
+func (p *Plan) clone() *Plan {
+	c := *p
+	c.Operations = append([]Operation(nil), p.Operations...)
+	return &c
+}
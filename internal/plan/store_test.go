@@ -0,0 +1,66 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_AddAndGet(t *testing.T) {
+	s := NewStore(time.Minute)
+	_, err := s.Add("user:jane@example.com", "rollout", Operation{Tool: "create"})
+	require.NoError(t, err)
+	p, err := s.Add("user:jane@example.com", "rollout", Operation{Tool: "scale"})
+	require.NoError(t, err)
+	assert.Equal(t, []Operation{{Tool: "create"}, {Tool: "scale"}}, p.Operations)
+
+	got, err := s.Get("user:jane@example.com", "rollout")
+	require.NoError(t, err)
+	assert.Len(t, got.Operations, 2)
+
+	_, err = s.Get("user:mallory@example.com", "rollout")
+	assert.ErrorIs(t, err, ErrNotFound, "plans are private to their caller")
+
+	require.NoError(t, s.Discard("user:jane@example.com", "rollout"))
+	_, err = s.Get("user:jane@example.com", "rollout")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_TakeAndPut(t *testing.T) {
+	s := NewStore(time.Minute)
+	_, err := s.Add("anonymous", "p", Operation{Tool: "patch"})
+	require.NoError(t, err)
+
+	p, err := s.Take("anonymous", "p")
+	require.NoError(t, err)
+	_, err = s.Take("anonymous", "p")
+	assert.ErrorIs(t, err, ErrNotFound, "a plan is applied at most once at a time")
+
+	s.Put(p)
+	got, err := s.Get("anonymous", "p")
+	require.NoError(t, err)
+	assert.Len(t, got.Operations, 1)
+}
+
+func TestStore_Limits(t *testing.T) {
+	now := time.Now()
+	s := NewStore(time.Minute)
+	s.now = func() time.Time { return now }
+	s.maxOperations = 1
+	s.maxPlans = 1
+
+	_, err := s.Add("anonymous", "a", Operation{})
+	require.NoError(t, err)
+	_, err = s.Add("anonymous", "a", Operation{})
+	assert.ErrorIs(t, err, ErrTooManyOperations)
+	_, err = s.Add("anonymous", "b", Operation{})
+	assert.ErrorIs(t, err, ErrTooManyPlans)
+
+	// Expired plans make room.
+	now = now.Add(time.Minute)
+	_, err = s.Add("anonymous", "b", Operation{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s.Len())
+}
@@ -0,0 +1,16 @@
+// Package preferences stores per-user preferences that shape tool calls
+// across MCP sessions: the default output format, default cluster,
+// preferred result limit and fields to mask in read results.
+//
+// Preferences are keyed by the caller's email and held by a Backend:
+//
+//   - MemoryBackend keeps preferences in process; they are lost when the
+//     replica restarts and are not shared between replicas.
+//   - ValkeyBackend keeps preferences in Valkey, shared across replicas and
+//     restarts.
+//
+// Preferences only fill in what a call leaves out. An argument passed to a
+// tool, or a default set for the MCP session, takes precedence, and masked
+// fields are hidden on top of the server's own secret masking, which
+// preferences cannot turn off.
+package preferences
@@ -0,0 +1,62 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultMaxUsers caps the number of users a MemoryBackend keeps
+// preferences for.
+const DefaultMaxUsers = 10000
+
+// ErrTooManyUsers is returned when a full MemoryBackend is asked to store
+// the preferences of another user.
+var ErrTooManyUsers = errors.New("too many users with preferences")
+
+// MemoryBackend keeps preferences in process memory.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	values   map[string]string
+	maxUsers int
+}
+
+// NewMemoryBackend creates an in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		values:   make(map[string]string),
+		maxUsers: DefaultMaxUsers,
+	}
+}
+
+// Get implements Backend.
+func (b *MemoryBackend) Get(_ context.Context, key string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.values[key], nil
+}
+
+// Set implements Backend. Preferences do not expire, so a full backend
+// refuses new users rather than dropping the preferences of others.
+func (b *MemoryBackend) Set(_ context.Context, key, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.values[key]; !ok && len(b.values) >= b.maxUsers {
+		return ErrTooManyUsers
+	}
+	b.values[key] = value
+	return nil
+}
+
+// Delete implements Backend.
+func (b *MemoryBackend) Delete(_ context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.values, key)
+	return nil
+}
+
+// Close implements Backend.
+func (b *MemoryBackend) Close() error {
+	return nil
+}
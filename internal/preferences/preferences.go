@@ -0,0 +1,172 @@
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Limits on the preferences a user can store.
+const (
+	// MaxLimit is the largest preferred limit, matching the largest page
+	// the read tools return.
+	MaxLimit = 1000
+
+	// MaxMaskedFields caps the number of masked fields.
+	MaxMaskedFields = 50
+
+	// maxFieldPathLength caps the length of a masked field path.
+	maxFieldPathLength = 256
+)
+
+// OutputFormats are the output formats a user can prefer. Tools whose
+// output argument does not accept the preferred format keep their own
+// default.
+var OutputFormats = []string{"slim", "normal", "wide", "full", "table"}
+
+// keySegment namespaces preferences under the key prefix.
+const keySegment = "preferences:"
+
+// ErrNoUser is returned when preferences are read or stored without a user.
+var ErrNoUser = errors.New("preferences need a user")
+
+// Backend stores encoded preferences by key.
+type Backend interface {
+	// Get returns the value under key, or "" if there is none.
+	Get(ctx context.Context, key string) (string, error)
+
+	// Set stores value under key.
+	Set(ctx context.Context, key, value string) error
+
+	// Delete removes the value under key. Deleting a missing key is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// Preferences are a user's defaults for tool calls. Zero fields have no
+// preference.
+type Preferences struct {
+	// Output is the output format of read tools that take one.
+	Output string `json:"output,omitempty"`
+
+	// Cluster is the workload cluster of tools that take one.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Limit is the number of items tools that take a limit return.
+	Limit int `json:"limit,omitempty"`
+
+	// MaskedFields are field paths, in the dot notation of the output
+	// processor's excluded fields, whose values are masked in read
+	// results.
+	MaskedFields []string `json:"maskedFields,omitempty"`
+}
+
+// IsZero reports whether no preference is set.
+func (p Preferences) IsZero() bool {
+	return p.Output == "" && p.Cluster == "" && p.Limit == 0 && len(p.MaskedFields) == 0
+}
+
+// Validate returns an error describing the first invalid preference.
+func (p Preferences) Validate() error {
+	if p.Output != "" && !slices.Contains(OutputFormats, p.Output) {
+		return fmt.Errorf("output must be one of %s, got %q", strings.Join(OutputFormats, ", "), p.Output)
+	}
+	if p.Limit < 0 || p.Limit > MaxLimit {
+		return fmt.Errorf("limit must be between 1 and %d, got %d", MaxLimit, p.Limit)
+	}
+	if len(p.MaskedFields) > MaxMaskedFields {
+		return fmt.Errorf("at most %d masked fields can be set, got %d", MaxMaskedFields, len(p.MaskedFields))
+	}
+	for _, field := range p.MaskedFields {
+		if field == "" || len(field) > maxFieldPathLength || strings.ContainsAny(field, " \t\n") {
+			return fmt.Errorf("invalid masked field path %q", field)
+		}
+	}
+	return nil
+}
+
+// Store holds the preferences of users.
+type Store struct {
+	backend   Backend
+	keyPrefix string
+}
+
+// New creates a store keeping preferences in backend, under keys prefixed
+// with keyPrefix.
+func New(backend Backend, keyPrefix string) *Store {
+	return &Store{backend: backend, keyPrefix: keyPrefix}
+}
+
+// Get returns the preferences of user, which are zero when none are set.
+func (s *Store) Get(ctx context.Context, user string) (Preferences, error) {
+	if user == "" {
+		return Preferences{}, ErrNoUser
+	}
+	value, err := s.backend.Get(ctx, s.key(user))
+	if err != nil {
+		return Preferences{}, fmt.Errorf("reading preferences: %w", err)
+	}
+	if value == "" {
+		return Preferences{}, nil
+	}
+	var p Preferences
+	if err := json.Unmarshal([]byte(value), &p); err != nil {
+		return Preferences{}, fmt.Errorf("decoding preferences: %w", err)
+	}
+	return p, nil
+}
+
+// Set validates and stores the preferences of user. Storing zero
+// preferences removes them.
+func (s *Store) Set(ctx context.Context, user string, p Preferences) error {
+	if user == "" {
+		return ErrNoUser
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.IsZero() {
+		if err := s.backend.Delete(ctx, s.key(user)); err != nil {
+			return fmt.Errorf("removing preferences: %w", err)
+		}
+		return nil
+	}
+	value, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encoding preferences: %w", err)
+	}
+	if err := s.backend.Set(ctx, s.key(user), string(value)); err != nil {
+		return fmt.Errorf("storing preferences: %w", err)
+	}
+	return nil
+}
+
+// Close releases the backend.
+func (s *Store) Close() error {
+	return s.backend.Close()
+}
+
+func (s *Store) key(user string) string {
+	return s.keyPrefix + keySegment + user
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying p, so that the output processor
+// can apply the caller's masked fields.
+func NewContext(ctx context.Context, p Preferences) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the preferences carried by ctx, which are zero when
+// there are none.
+func FromContext(ctx context.Context) Preferences {
+	p, _ := ctx.Value(contextKey{}).(Preferences)
+	return p
+}
@@ -0,0 +1,81 @@
+package preferences
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_GetSet(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	s := New(backend, "mcp:")
+
+	p, err := s.Get(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.True(t, p.IsZero())
+
+	want := Preferences{Output: "table", Cluster: "prod-wc-01", Limit: 50, MaskedFields: []string{"metadata.labels"}}
+	require.NoError(t, s.Set(ctx, "jane@example.com", want))
+	p, err = s.Get(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, want, p)
+	assert.Contains(t, backend.values, "mcp:preferences:jane@example.com")
+
+	p, err = s.Get(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.True(t, p.IsZero(), "preferences are per user")
+
+	require.NoError(t, s.Set(ctx, "jane@example.com", Preferences{}))
+	assert.Empty(t, backend.values, "zero preferences are removed")
+
+	_, err = s.Get(ctx, "")
+	assert.ErrorIs(t, err, ErrNoUser)
+	assert.ErrorIs(t, s.Set(ctx, "", want), ErrNoUser)
+}
+
+func TestPreferences_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Preferences
+		wantErr string
+	}{
+		{name: "zero", p: Preferences{}},
+		{name: "valid", p: Preferences{Output: "wide", Limit: MaxLimit, MaskedFields: []string{"status.podIPs"}}},
+		{name: "unknown output", p: Preferences{Output: "yaml"}, wantErr: "output must be one of"},
+		{name: "negative limit", p: Preferences{Limit: -1}, wantErr: "limit must be between"},
+		{name: "limit too large", p: Preferences{Limit: MaxLimit + 1}, wantErr: "limit must be between"},
+		{name: "empty field", p: Preferences{MaskedFields: []string{""}}, wantErr: "invalid masked field path"},
+		{name: "field with spaces", p: Preferences{MaskedFields: []string{"metadata. labels"}}, wantErr: "invalid masked field path"},
+		{name: "too many fields", p: Preferences{MaskedFields: strings.Split(strings.Repeat("a,", MaxMaskedFields)+"a", ",")}, wantErr: "at most"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemoryBackend_MaxUsers(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBackend()
+	b.maxUsers = 1
+
+	require.NoError(t, b.Set(ctx, "a", "1"))
+	assert.ErrorIs(t, b.Set(ctx, "b", "1"), ErrTooManyUsers)
+	assert.NoError(t, b.Set(ctx, "a", "2"), "existing users can still change their preferences")
+}
+
+func TestContext(t *testing.T) {
+	assert.True(t, FromContext(context.Background()).IsZero())
+	p := Preferences{MaskedFields: []string{"metadata.labels"}}
+	assert.Equal(t, p, FromContext(NewContext(context.Background(), p)))
+}
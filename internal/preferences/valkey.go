@@ -0,0 +1,43 @@
+package preferences
+
+import (
+	"context"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// ValkeyBackend keeps preferences in Valkey strings, shared across replicas
+// and restarts.
+type ValkeyBackend struct {
+	client valkey.Client
+}
+
+// NewValkeyBackend creates a backend on client.
+func NewValkeyBackend(client valkey.Client) *ValkeyBackend {
+	return &ValkeyBackend{client: client}
+}
+
+// Get implements Backend.
+func (b *ValkeyBackend) Get(ctx context.Context, key string) (string, error) {
+	value, err := b.client.Do(ctx, b.client.B().Get().Key(key).Build()).ToString()
+	if valkey.IsValkeyNil(err) {
+		return "", nil
+	}
+	return value, err
+}
+
+// Set implements Backend.
+func (b *ValkeyBackend) Set(ctx context.Context, key, value string) error {
+	return b.client.Do(ctx, b.client.B().Set().Key(key).Value(value).Build()).Error()
+}
+
+// Delete implements Backend.
+func (b *ValkeyBackend) Delete(ctx context.Context, key string) error {
+	return b.client.Do(ctx, b.client.B().Del().Key(key).Build()).Error()
+}
+
+// Close closes the Valkey connection.
+func (b *ValkeyBackend) Close() error {
+	b.client.Close()
+	return nil
+}
@@ -0,0 +1,16 @@
+// Package ratelimit limits tool invocations per caller.
+//
+// A Limiter keys every call by the caller's identity: the authenticated
+// user's email, or the client IP for anonymous callers. With per-tool
+// limiting enabled the tool name is added to the key, so each tool has its
+// own budget per caller.
+//
+// Budgets are token buckets held by a Backend:
+//
+//   - MemoryBackend keeps buckets in process. Each replica enforces its own
+//     budget, so N replicas allow up to N times the configured rate.
+//   - ValkeyBackend keeps buckets in Valkey and shares them across replicas.
+//
+// A backend error never blocks a call: rate limiting protects the cluster
+// from runaway agents, it is not an authorization control.
+package ratelimit
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultMaxEntries caps the number of buckets a MemoryBackend tracks.
+const DefaultMaxEntries = 10000
+
+// memoryBucket is a token bucket and the last time it was used.
+type memoryBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// MemoryBackend keeps token buckets in process memory.
+type MemoryBackend struct {
+	mu         sync.Mutex
+	buckets    map[string]*memoryBucket
+	rate       rate.Limit
+	burst      int
+	maxEntries int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// NewMemoryBackend creates an in-memory backend refilling at rps tokens per
+// second up to burst tokens.
+func NewMemoryBackend(rps float64, burst int) *MemoryBackend {
+	return &MemoryBackend{
+		buckets:    make(map[string]*memoryBucket),
+		rate:       rate.Limit(rps),
+		burst:      burst,
+		maxEntries: DefaultMaxEntries,
+		now:        time.Now,
+	}
+}
+
+// Allow takes a token from key's bucket.
+func (b *MemoryBackend) Allow(_ context.Context, key string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	bucket, ok := b.buckets[key]
+	if !ok {
+		if len(b.buckets) >= b.maxEntries {
+			b.evictIdle(now)
+		}
+		bucket = &memoryBucket{limiter: rate.NewLimiter(b.rate, b.burst)}
+		b.buckets[key] = bucket
+	}
+	bucket.lastUsed = now
+	return bucket.limiter.AllowN(now, 1), nil
+}
+
+// evictIdle drops buckets that have refilled completely, since they are
+// indistinguishable from new ones. If none have, the least recently used
+// bucket is dropped. Must be called with mu held.
+func (b *MemoryBackend) evictIdle(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, bucket := range b.buckets {
+		if bucket.limiter.TokensAt(now) >= float64(b.burst) {
+			delete(b.buckets, key)
+			continue
+		}
+		if oldestKey == "" || bucket.lastUsed.Before(oldest) {
+			oldestKey, oldest = key, bucket.lastUsed
+		}
+	}
+	if len(b.buckets) >= b.maxEntries && oldestKey != "" {
+		delete(b.buckets, oldestKey)
+	}
+}
+
+// Close is a no-op for the in-memory backend.
+func (b *MemoryBackend) Close() error {
+	return nil
+}
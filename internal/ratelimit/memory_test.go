@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBackend_TokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := NewMemoryBackend(1, 2)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	allow := func(key string) bool {
+		ok, err := b.Allow(ctx, key)
+		assert.NoError(t, err)
+		return ok
+	}
+
+	assert.True(t, allow("a"))
+	assert.True(t, allow("a"))
+	assert.False(t, allow("a"), "burst exhausted")
+	assert.True(t, allow("b"), "buckets are independent")
+
+	now = now.Add(time.Second)
+	assert.True(t, allow("a"), "one token refilled after a second")
+	assert.False(t, allow("a"))
+}
+
+func TestMemoryBackend_Eviction(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := NewMemoryBackend(1, 1)
+	b.maxEntries = 3
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, _ = b.Allow(ctx, fmt.Sprintf("k%d", i))
+		now = now.Add(100 * time.Millisecond)
+	}
+
+	// No bucket has refilled yet, so the least recently used one goes.
+	_, _ = b.Allow(ctx, "k3")
+	assert.Len(t, b.buckets, 3)
+	assert.NotContains(t, b.buckets, "k0")
+
+	// Once buckets have refilled they are all dropped.
+	now = now.Add(time.Minute)
+	_, _ = b.Allow(ctx, "k4")
+	assert.Len(t, b.buckets, 1)
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// Backend names accepted by NewBackend.
+const (
+	BackendMemory = "memory"
+	BackendValkey = "valkey"
+)
+
+// anonymousIdentity keys callers with neither a user nor a client IP,
+// e.g. the single client of the stdio transport.
+const anonymousIdentity = "anonymous"
+
+// Backend stores token buckets and consumes one token per call.
+type Backend interface {
+	// Allow takes a token from the bucket identified by key and reports
+	// whether one was available.
+	Allow(ctx context.Context, key string) (bool, error)
+
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// Caller identifies who is making a tool call.
+type Caller struct {
+	// Email is the authenticated user's email. Takes precedence over IP.
+	Email string
+
+	// Subject is the identity authenticated by a non-OAuth HTTP auth mode
+	// (static token or TokenReview). Used when Email is empty.
+	Subject string
+
+	// IP is the client address, used for anonymous callers.
+	IP string
+}
+
+// Identity returns the bucket identity for the caller.
+func (c Caller) Identity() string {
+	switch {
+	case c.Email != "":
+		return "user:" + strings.ToLower(c.Email)
+	case c.Subject != "":
+		return "subject:" + c.Subject
+	case c.IP != "":
+		return "ip:" + c.IP
+	default:
+		return anonymousIdentity
+	}
+}
+
+// Limiter decides whether a caller may invoke a tool.
+type Limiter struct {
+	backend Backend
+	perTool bool
+	logger  *slog.Logger
+}
+
+// NewLimiter creates a limiter over backend. When perTool is true every tool
+// has its own bucket per caller; otherwise all tools share one.
+func NewLimiter(backend Backend, perTool bool, logger *slog.Logger) *Limiter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Limiter{backend: backend, perTool: perTool, logger: logger}
+}
+
+// Key returns the bucket key for a caller and tool.
+func (l *Limiter) Key(caller Caller, tool string) string {
+	if l.perTool && tool != "" {
+		return caller.Identity() + "|tool:" + tool
+	}
+	return caller.Identity()
+}
+
+// Allow reports whether caller may invoke tool now. Backend errors are
+// logged and the call is allowed.
+func (l *Limiter) Allow(ctx context.Context, caller Caller, tool string) bool {
+	key := l.Key(caller, tool)
+	allowed, err := l.backend.Allow(ctx, key)
+	if err != nil {
+		l.logger.Warn("rate limit backend error, allowing call",
+			slog.String("tool", tool),
+			slog.Any("error", err))
+		return true
+	}
+	return allowed
+}
+
+// Close releases the backend.
+func (l *Limiter) Close() error {
+	return l.backend.Close()
+}
+
+// ExceededMessage is the error returned to a caller whose budget is spent.
+func ExceededMessage(tool string) string {
+	return fmt.Sprintf("rate limit exceeded for tool %q: too many requests, retry later", tool)
+}
+
+// NewBackend creates the named backend refilling at rps tokens per second up
+// to burst tokens. client and keyPrefix are only used by the valkey backend.
+func NewBackend(name string, client valkey.Client, keyPrefix string, rps float64, burst int) (Backend, error) {
+	if rps <= 0 {
+		return nil, fmt.Errorf("rate limit must be positive, got %v", rps)
+	}
+	if burst < 1 {
+		return nil, fmt.Errorf("rate limit burst must be at least 1, got %d", burst)
+	}
+
+	switch name {
+	case BackendMemory, "":
+		return NewMemoryBackend(rps, burst), nil
+	case BackendValkey:
+		if client == nil {
+			return nil, fmt.Errorf("a valkey client is required for the valkey rate limit backend")
+		}
+		return NewValkeyBackend(client, keyPrefix, rps, burst), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit backend: %s (supported: %s, %s)", name, BackendMemory, BackendValkey)
+	}
+}
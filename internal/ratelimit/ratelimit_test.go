@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend records keys and returns a fixed answer.
+type fakeBackend struct {
+	allowed bool
+	err     error
+	keys    []string
+}
+
+func (f *fakeBackend) Allow(_ context.Context, key string) (bool, error) {
+	f.keys = append(f.keys, key)
+	return f.allowed, f.err
+}
+
+func (f *fakeBackend) Close() error { return nil }
+
+func TestLimiter_Key(t *testing.T) {
+	shared := NewLimiter(&fakeBackend{}, false, nil)
+	perTool := NewLimiter(&fakeBackend{}, true, nil)
+
+	tests := []struct {
+		name    string
+		limiter *Limiter
+		caller  Caller
+		tool    string
+		want    string
+	}{
+		{name: "user", limiter: shared, caller: Caller{Email: "Jane@Example.com", IP: "10.0.0.1"}, tool: "list", want: "user:jane@example.com"},
+		{name: "subject", limiter: shared, caller: Caller{Subject: "system:serviceaccount:ops:agent", IP: "10.0.0.1"}, tool: "list", want: "subject:system:serviceaccount:ops:agent"},
+		{name: "ip fallback", limiter: shared, caller: Caller{IP: "10.0.0.1"}, tool: "list", want: "ip:10.0.0.1"},
+		{name: "anonymous", limiter: shared, tool: "list", want: "anonymous"},
+		{name: "per tool", limiter: perTool, caller: Caller{Email: "jane@example.com"}, tool: "delete", want: "user:jane@example.com|tool:delete"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.limiter.Key(tt.caller, tt.tool))
+		})
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	backend := &fakeBackend{allowed: false}
+	l := NewLimiter(backend, true, nil)
+
+	assert.False(t, l.Allow(context.Background(), Caller{Email: "jane@example.com"}, "get"))
+	assert.Equal(t, []string{"user:jane@example.com|tool:get"}, backend.keys)
+
+	backend.err = errors.New("connection refused")
+	assert.True(t, l.Allow(context.Background(), Caller{}, "get"), "backend errors must not block calls")
+}
+
+func TestNewBackend(t *testing.T) {
+	b, err := NewBackend(BackendMemory, nil, "", 1, 1)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryBackend{}, b)
+
+	_, err = NewBackend(BackendValkey, nil, "", 1, 1)
+	assert.ErrorContains(t, err, "a valkey client is required")
+
+	_, err = NewBackend("etcd", nil, "", 1, 1)
+	assert.ErrorContains(t, err, "unsupported rate limit backend")
+
+	_, err = NewBackend(BackendMemory, nil, "", 0, 1)
+	assert.Error(t, err)
+
+	_, err = NewBackend(BackendMemory, nil, "", 1, 0)
+	assert.Error(t, err)
+}
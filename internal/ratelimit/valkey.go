@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// valkeyKeySegment namespaces rate limit buckets under the key prefix.
+const valkeyKeySegment = "ratelimit:"
+
+// tokenBucketScript atomically refills and consumes a token bucket stored as
+// a hash. It uses the server clock so replicas with skewed clocks share one
+// consistent bucket.
+//
+// KEYS[1] bucket key; ARGV[1] tokens per second; ARGV[2] burst;
+// ARGV[3] idle expiry in milliseconds. Returns 1 when a token was taken.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return allowed
+`
+
+// ValkeyBackend keeps token buckets in Valkey so every replica draws from
+// the same budget.
+type ValkeyBackend struct {
+	client    valkey.Client
+	keyPrefix string
+	rate      float64
+	burst     int
+	ttl       time.Duration
+
+	// eval runs the token bucket script; replaced in tests.
+	eval func(ctx context.Context, keys, args []string) (int64, error)
+}
+
+// NewValkeyBackend creates a backend on client refilling at rps tokens per
+// second up to burst tokens. keyPrefix is prepended to every bucket key.
+func NewValkeyBackend(client valkey.Client, keyPrefix string, rps float64, burst int) *ValkeyBackend {
+	script := valkey.NewLuaScript(tokenBucketScript)
+	b := newValkeyBackend(keyPrefix, rps, burst)
+	b.client = client
+	b.eval = func(ctx context.Context, keys, args []string) (int64, error) {
+		return script.Exec(ctx, client, keys, args).AsInt64()
+	}
+	return b
+}
+
+// newValkeyBackend builds a backend without a connection.
+func newValkeyBackend(keyPrefix string, rps float64, burst int) *ValkeyBackend {
+	return &ValkeyBackend{
+		keyPrefix: keyPrefix,
+		rate:      rps,
+		burst:     burst,
+		ttl:       bucketTTL(rps, burst),
+	}
+}
+
+// bucketTTL is how long an idle bucket takes to refill, after which it can
+// be dropped. At least one second.
+func bucketTTL(rps float64, burst int) time.Duration {
+	if rps <= 0 {
+		return time.Second
+	}
+	ttl := time.Duration(math.Ceil(float64(burst)/rps)) * time.Second
+	if ttl < time.Second {
+		return time.Second
+	}
+	return ttl
+}
+
+// Allow takes a token from key's bucket.
+func (b *ValkeyBackend) Allow(ctx context.Context, key string) (bool, error) {
+	allowed, err := b.eval(ctx,
+		[]string{b.keyPrefix + valkeyKeySegment + key},
+		[]string{
+			strconv.FormatFloat(b.rate, 'f', -1, 64),
+			strconv.Itoa(b.burst),
+			strconv.FormatInt(b.ttl.Milliseconds(), 10),
+		})
+	if err != nil {
+		return false, fmt.Errorf("valkey rate limit check failed: %w", err)
+	}
+	return allowed == 1, nil
+}
+
+// Close closes the Valkey connection.
+func (b *ValkeyBackend) Close() error {
+	if b.client != nil {
+		b.client.Close()
+	}
+	return nil
+}
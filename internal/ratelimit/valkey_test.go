@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValkeyBackend_Allow(t *testing.T) {
+	var gotKeys, gotArgs []string
+	b := newValkeyBackend("mcp:", 0.5, 10)
+	b.eval = func(_ context.Context, keys, args []string) (int64, error) {
+		gotKeys, gotArgs = keys, args
+		return 1, nil
+	}
+
+	allowed, err := b.Allow(context.Background(), "user:jane@example.com")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, []string{"mcp:ratelimit:user:jane@example.com"}, gotKeys)
+	assert.Equal(t, []string{"0.5", "10", "20000"}, gotArgs)
+
+	b.eval = func(context.Context, []string, []string) (int64, error) { return 0, nil }
+	allowed, err = b.Allow(context.Background(), "k")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	b.eval = func(context.Context, []string, []string) (int64, error) { return 0, errors.New("timeout") }
+	_, err = b.Allow(context.Background(), "k")
+	assert.ErrorContains(t, err, "valkey rate limit check failed")
+}
+
+func TestBucketTTL(t *testing.T) {
+	assert.Equal(t, 20*time.Second, bucketTTL(0.5, 10))
+	assert.Equal(t, time.Second, bucketTTL(100, 10))
+	assert.Equal(t, time.Second, bucketTTL(0, 10))
+}
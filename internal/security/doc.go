@@ -0,0 +1,37 @@
+// Package security provides the tool authorization policy engine.
+//
+// A Policy is an ordered set of allow/deny rules matched on four dimensions:
+// cluster, namespace, resource type and verb. Each dimension accepts glob
+// patterns (path.Match syntax) and an empty list matches anything.
+//
+// # Precedence
+//
+// Deny rules always win over allow rules. When no rule matches, the policy's
+// DefaultEffect applies. This keeps policies easy to reason about: an allow
+// rule can never punch a hole through a deny.
+//
+// # Policy Files
+//
+// Policies are loaded from YAML:
+//
+//	defaultEffect: allow
+//	rules:
+//	  - effect: deny
+//	    namespaces: ["kube-system", "kube-public"]
+//	  - effect: deny
+//	    clusters: ["prod-*"]
+//	    verbs: ["delete", "patch", "scale", "exec"]
+//	  - effect: allow
+//	    clusters: ["prod-*"]
+//	    namespaces: ["sandbox-*"]
+//	    verbs: ["exec"]
+//
+// An Engine holds the active policy and can reload it from disk whenever the
+// file changes, so rules can be updated without restarting the server.
+//
+// # Relation to the Flat Settings
+//
+// The policy engine supersedes the flat AllowedOperations and
+// RestrictedNamespaces settings: when a policy file is configured, it is the
+// single source of truth for which tool calls are permitted.
+package security
@@ -0,0 +1,137 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Engine evaluates requests against the active policy. The policy can be
+// replaced at any time, either directly via SetPolicy or by reloading the
+// backing file, and evaluation is safe for concurrent use.
+type Engine struct {
+	policy atomic.Pointer[Policy]
+
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// NewEngine returns an engine enforcing p. A nil policy allows everything.
+func NewEngine(p *Policy) *Engine {
+	e := &Engine{}
+	e.SetPolicy(p)
+	return e
+}
+
+// NewEngineFromFile loads the policy at path and returns an engine bound to
+// that file, so later calls to Reload and Watch pick up changes.
+func NewEngineFromFile(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if _, err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetPolicy replaces the active policy.
+func (e *Engine) SetPolicy(p *Policy) {
+	if p == nil {
+		p = &Policy{DefaultEffect: EffectAllow}
+	}
+	e.policy.Store(p)
+}
+
+// Policy returns the active policy.
+func (e *Engine) Policy() *Policy {
+	return e.policy.Load()
+}
+
+// Path returns the policy file backing this engine, or "" if none.
+func (e *Engine) Path() string {
+	return e.path
+}
+
+// Evaluate decides whether req is permitted by the active policy.
+func (e *Engine) Evaluate(req Request) Decision {
+	return e.Policy().Evaluate(req)
+}
+
+// VerbDenied reports whether the active policy denies verb everywhere.
+func (e *Engine) VerbDenied(verb string) bool {
+	return e.Policy().VerbDenied(verb)
+}
+
+// Reload re-reads the policy file if it changed since the last load.
+// It reports whether a new policy was installed. On error the previously
+// active policy stays in effect.
+func (e *Engine) Reload() (bool, error) {
+	if e.path == "" {
+		return false, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat policy file: %w", err)
+	}
+	if e.Policy() != nil && info.ModTime().Equal(e.modTime) && info.Size() == e.size {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	p, err := ParsePolicy(data)
+	if err != nil {
+		return false, fmt.Errorf("policy file %s: %w", e.path, err)
+	}
+
+	e.policy.Store(p)
+	e.modTime = info.ModTime()
+	e.size = info.Size()
+	return true, nil
+}
+
+// Watch polls the policy file every interval and reloads it when it changes,
+// until ctx is cancelled. Invalid files are logged and ignored so that a bad
+// edit never drops the server back to an unrestricted state.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if e.path == "" || interval <= 0 {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := e.Reload()
+			if err != nil {
+				logger.Warn("Failed to reload security policy, keeping previous policy",
+					slog.String("path", e.path),
+					slog.Any("error", err))
+				continue
+			}
+			if changed {
+				logger.Info("Reloaded security policy",
+					slog.String("path", e.path),
+					slog.Int("rules", len(e.Policy().Rules)))
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicyFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestNewEngine_NilPolicyAllows(t *testing.T) {
+	e := NewEngine(nil)
+	assert.True(t, e.Evaluate(Request{Verb: "delete", Namespace: "kube-system"}).Allowed)
+	assert.Empty(t, e.Path())
+
+	changed, err := e.Reload()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestNewEngineFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicyFile(t, path, "rules:\n  - effect: deny\n    verbs: [delete]\n", time.Now())
+
+	e, err := NewEngineFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, e.Path())
+	assert.False(t, e.Evaluate(Request{Verb: "delete"}).Allowed)
+	assert.True(t, e.Evaluate(Request{Verb: "get"}).Allowed)
+
+	_, err = NewEngineFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestEngineReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	base := time.Now().Add(-time.Hour)
+	writePolicyFile(t, path, "rules:\n  - effect: deny\n    verbs: [delete]\n", base)
+
+	e, err := NewEngineFromFile(path)
+	require.NoError(t, err)
+
+	changed, err := e.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed, "unchanged file must not be reloaded")
+
+	writePolicyFile(t, path, "rules:\n  - effect: deny\n    verbs: [get]\n", base.Add(time.Minute))
+	changed, err = e.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, e.Evaluate(Request{Verb: "delete"}).Allowed)
+	assert.False(t, e.Evaluate(Request{Verb: "get"}).Allowed)
+
+	// An invalid edit keeps the previous policy.
+	writePolicyFile(t, path, "rules:\n  - effect: nope\n", base.Add(2*time.Minute))
+	changed, err = e.Reload()
+	require.Error(t, err)
+	assert.False(t, changed)
+	assert.False(t, e.Evaluate(Request{Verb: "get"}).Allowed)
+}
+
+func TestEngineWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	base := time.Now().Add(-time.Hour)
+	writePolicyFile(t, path, "defaultEffect: allow\n", base)
+
+	e, err := NewEngineFromFile(path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Watch(ctx, 10*time.Millisecond, nil)
+		close(done)
+	}()
+
+	writePolicyFile(t, path, "defaultEffect: deny\n", base.Add(time.Minute))
+	assert.Eventually(t, func() bool {
+		return !e.Evaluate(Request{Verb: "get"}).Allowed
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,181 @@
+package security
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Access is whether a tool call reads or writes.
+type Access string
+
+// Supported access kinds.
+const (
+	AccessRead  Access = "read"
+	AccessWrite Access = "write"
+)
+
+// NamespaceAccess restricts reads and writes by namespace separately, for the
+// asymmetric policies most platform teams want: reads everywhere except
+// kube-system secrets, writes only in team-* namespaces.
+//
+// Namespaces and resources are glob patterns (path.Match syntax). A request
+// with an empty namespace reads or writes across all namespaces when it sets
+// AllNamespaces, and writes a cluster-scoped object otherwise.
+type NamespaceAccess struct {
+	readRestrictions    []readRestriction
+	writeNamespaces     []string
+	clusterScopedWrites bool
+}
+
+// readRestriction denies reading resources (any, when empty) in the
+// namespaces matching namespace.
+type readRestriction struct {
+	namespace string
+	resource  string
+}
+
+// NewNamespaceAccess creates the namespace access policy. Each read
+// restriction is a namespace, denying reads of anything in it, or
+// namespace/resource, e.g. "kube-system/secrets". writeNamespaces lists the
+// namespaces writes are allowed in; empty allows writes anywhere. While
+// writes are limited to some namespaces, cluster-scoped objects may only be
+// written when clusterScopedWrites is set.
+func NewNamespaceAccess(readRestrictions, writeNamespaces []string, clusterScopedWrites bool) (*NamespaceAccess, error) {
+	a := &NamespaceAccess{clusterScopedWrites: clusterScopedWrites}
+	for _, entry := range readRestrictions {
+		namespace, resource, _ := strings.Cut(entry, "/")
+		if namespace == "" || strings.Contains(resource, "/") {
+			return nil, fmt.Errorf("invalid read restriction %q: want namespace or namespace/resource", entry)
+		}
+		r := readRestriction{namespace: namespace, resource: strings.ToLower(resource)}
+		if err := validPatterns(entry, r.namespace, r.resource); err != nil {
+			return nil, err
+		}
+		a.readRestrictions = append(a.readRestrictions, r)
+	}
+	for _, namespace := range writeNamespaces {
+		if namespace == "" {
+			return nil, fmt.Errorf("write namespaces must not be empty")
+		}
+		if err := validPatterns(namespace, namespace); err != nil {
+			return nil, err
+		}
+		a.writeNamespaces = append(a.writeNamespaces, namespace)
+	}
+	return a, nil
+}
+
+func validPatterns(entry string, patterns ...string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", entry, err)
+		}
+	}
+	return nil
+}
+
+// ReadRestrictions returns the read restrictions as configured.
+func (a *NamespaceAccess) ReadRestrictions() []string {
+	out := make([]string, 0, len(a.readRestrictions))
+	for _, r := range a.readRestrictions {
+		if r.resource == "" {
+			out = append(out, r.namespace)
+		} else {
+			out = append(out, r.namespace+"/"+r.resource)
+		}
+	}
+	return out
+}
+
+// WriteNamespaces returns the namespaces writes are allowed in; empty when
+// writes are allowed anywhere.
+func (a *NamespaceAccess) WriteNamespaces() []string {
+	return append([]string(nil), a.writeNamespaces...)
+}
+
+// ClusterScopedWrites reports whether cluster-scoped objects may be written
+// while writes are limited to some namespaces.
+func (a *NamespaceAccess) ClusterScopedWrites() bool {
+	return a.clusterScopedWrites
+}
+
+// Evaluate decides whether req may read or write, as given by access, in its
+// namespace.
+//
+// A read across all namespaces is denied when a read restriction covers its
+// resource, as it would include the restricted namespace. A read whose
+// resource is unknown is denied by every restriction of its namespace. A
+// write across all namespaces is denied when writes are limited to some
+// namespaces, and so is a write of a cluster-scoped object unless
+// cluster-scoped writes are allowed.
+func (a *NamespaceAccess) Evaluate(req Request, access Access) Decision {
+	if access == AccessWrite {
+		return a.evaluateWrite(req)
+	}
+	return a.evaluateRead(req)
+}
+
+func (a *NamespaceAccess) evaluateRead(req Request) Decision {
+	for _, r := range a.readRestrictions {
+		if r.resource != "" && !req.ResourceUnknown() && !matchResource(r.resource, req.Resource) {
+			continue
+		}
+		if req.Namespace != "" {
+			if ok, _ := path.Match(r.namespace, req.Namespace); !ok {
+				continue
+			}
+		}
+		subject := "reading"
+		switch {
+		case req.ResourceUnknown():
+			subject += " unknown resources"
+		case r.resource != "":
+			subject += " " + req.Resource
+		}
+		if req.Namespace == "" {
+			return Decision{
+				Allowed: false,
+				Reason:  fmt.Sprintf("%s across all namespaces is not allowed: reads are restricted in namespaces matching %q", subject, r.namespace),
+			}
+		}
+		return Decision{
+			Allowed: false,
+			Reason:  fmt.Sprintf("%s in namespace %s is restricted", subject, req.Namespace),
+		}
+	}
+	return Decision{Allowed: true}
+}
+
+func (a *NamespaceAccess) evaluateWrite(req Request) Decision {
+	if len(a.writeNamespaces) == 0 {
+		return Decision{Allowed: true}
+	}
+	allowed := strings.Join(a.writeNamespaces, ", ")
+	if req.Namespace == "" && !req.AllNamespaces {
+		if a.clusterScopedWrites {
+			return Decision{Allowed: true}
+		}
+		subject := "cluster-scoped objects"
+		if req.Resource != "" {
+			subject = "cluster-scoped " + req.Resource
+		}
+		return Decision{
+			Allowed: false,
+			Reason:  fmt.Sprintf("%s of %s is not allowed: writes are limited to namespaces %s", req.Verb, subject, allowed),
+		}
+	}
+	if req.Namespace == "" {
+		return Decision{
+			Allowed: false,
+			Reason:  fmt.Sprintf("%s across all namespaces is not allowed: writes are limited to namespaces %s", req.Verb, allowed),
+		}
+	}
+	if !matchAny(a.writeNamespaces, req.Namespace) {
+		return Decision{
+			Allowed: false,
+			Reason:  fmt.Sprintf("%s in namespace %s is not allowed: writes are limited to namespaces %s", req.Verb, req.Namespace, allowed),
+		}
+	}
+	return Decision{Allowed: true}
+}
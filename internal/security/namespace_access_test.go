@@ -0,0 +1,77 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNamespaceAccess_Validation(t *testing.T) {
+	for _, entry := range []string{"", "/secrets", "kube-system/secrets/x", "kube-[system"} {
+		_, err := NewNamespaceAccess([]string{entry}, nil, false)
+		assert.Error(t, err, entry)
+	}
+	_, err := NewNamespaceAccess(nil, []string{""}, false)
+	assert.ErrorContains(t, err, "must not be empty")
+	_, err = NewNamespaceAccess(nil, []string{"team-["}, false)
+	assert.ErrorContains(t, err, "invalid pattern")
+
+	a, err := NewNamespaceAccess([]string{"kube-system/Secrets", "vault"}, []string{"team-*"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kube-system/secrets", "vault"}, a.ReadRestrictions())
+	assert.Equal(t, []string{"team-*"}, a.WriteNamespaces())
+	assert.False(t, a.ClusterScopedWrites())
+}
+
+func TestNamespaceAccess_ClusterScopedWrites(t *testing.T) {
+	a, err := NewNamespaceAccess(nil, []string{"team-*"}, true)
+	require.NoError(t, err)
+	assert.True(t, a.ClusterScopedWrites())
+	assert.True(t, a.Evaluate(Request{Resource: "clusterrolebindings", Verb: "apply"}, AccessWrite).Allowed)
+	assert.False(t, a.Evaluate(Request{Resource: "pods", Verb: "label", AllNamespaces: true}, AccessWrite).Allowed)
+	assert.False(t, a.Evaluate(Request{Namespace: "default", Resource: "pods", Verb: "delete"}, AccessWrite).Allowed)
+}
+
+func TestNamespaceAccess_Evaluate(t *testing.T) {
+	a, err := NewNamespaceAccess([]string{"kube-system/secrets", "vault-*"}, []string{"team-*"}, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		req    Request
+		access Access
+		want   bool
+		reason string
+	}{
+		{name: "read allowed", req: Request{Namespace: "kube-system", Resource: "pods", Verb: "get"}, access: AccessRead, want: true},
+		{name: "read restricted resource", req: Request{Namespace: "kube-system", Resource: "secrets", Verb: "get"}, access: AccessRead, reason: "reading secrets in namespace kube-system is restricted"},
+		{name: "singular resource", req: Request{Namespace: "kube-system", Resource: "Secret", Verb: "get"}, access: AccessRead, reason: "restricted"},
+		{name: "restricted namespace", req: Request{Namespace: "vault-prod", Resource: "configmaps", Verb: "list"}, access: AccessRead, reason: "reading in namespace vault-prod is restricted"},
+		{name: "unknown resource", req: Request{Namespace: "kube-system", Verb: "namespace_overview"}, access: AccessRead, reason: "reading unknown resources in namespace kube-system is restricted"},
+		{name: "no objects", req: Request{Namespace: "kube-system", Verb: "context_list", NoObjects: true}, access: AccessRead, want: true},
+		{name: "secrets elsewhere", req: Request{Namespace: "team-a", Resource: "secrets", Verb: "get"}, access: AccessRead, want: true},
+		{name: "all namespaces read of restricted resource", req: Request{Resource: "secrets", Verb: "list"}, access: AccessRead, reason: "across all namespaces"},
+		{name: "write allowed", req: Request{Namespace: "team-a", Resource: "deployments", Verb: "scale"}, access: AccessWrite, want: true},
+		{name: "write elsewhere", req: Request{Namespace: "default", Resource: "deployments", Verb: "scale"}, access: AccessWrite, reason: "scale in namespace default is not allowed: writes are limited to namespaces team-*"},
+		{name: "write across all namespaces", req: Request{Resource: "pods", Verb: "label", AllNamespaces: true}, access: AccessWrite, reason: "label across all namespaces is not allowed"},
+		{name: "cluster-scoped write", req: Request{Resource: "clusterrolebindings", Verb: "apply"}, access: AccessWrite, reason: "apply of cluster-scoped clusterrolebindings is not allowed: writes are limited to namespaces team-*"},
+		{name: "write to readable-only namespace", req: Request{Namespace: "kube-system", Resource: "pods", Verb: "delete"}, access: AccessWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := a.Evaluate(tt.req, tt.access)
+			assert.Equal(t, tt.want, decision.Allowed)
+			if tt.reason != "" {
+				assert.Contains(t, decision.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestNamespaceAccess_Unrestricted(t *testing.T) {
+	a, err := NewNamespaceAccess(nil, nil, false)
+	require.NoError(t, err)
+	assert.True(t, a.Evaluate(Request{Resource: "secrets", Verb: "list"}, AccessRead).Allowed)
+	assert.True(t, a.Evaluate(Request{Verb: "label"}, AccessWrite).Allowed)
+}
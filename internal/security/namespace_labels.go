@@ -0,0 +1,189 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DefaultNamespaceLabelCacheTTL is how long the labels of a namespace are
+// reused before they are read from the cluster again.
+const DefaultNamespaceLabelCacheTTL = 30 * time.Second
+
+// maxNamespaceLabelCacheEntries bounds the namespace label cache. Expired
+// entries are dropped once it is reached.
+const maxNamespaceLabelCacheEntries = 10000
+
+// NamespaceLabelLookup reads the labels of a namespace in a cluster. found is
+// false when the namespace does not exist.
+type NamespaceLabelLookup func(ctx context.Context, cluster, namespace string) (labels map[string]string, found bool, err error)
+
+// NamespaceSelectorLookup lists the names of the namespaces in a cluster
+// whose labels match selector.
+type NamespaceSelectorLookup func(ctx context.Context, cluster, selector string) (namespaces []string, err error)
+
+// NamespaceLabelGuard restricts access to namespaces whose labels match a
+// selector, such as policy.giantswarm.io/mcp-access=deny. This keeps the
+// access policy on the namespaces themselves instead of in a static list.
+//
+// Labels are read when a tool call targets a namespace and cached for a short
+// TTL, so label changes take effect without a restart.
+type NamespaceLabelGuard struct {
+	selector labels.Selector
+	ttl      time.Duration
+
+	// FailOpen allows calls when the namespace labels cannot be read. The
+	// default is to deny.
+	FailOpen bool
+
+	mu    sync.Mutex
+	cache map[string]namespaceLabelEntry
+	now   func() time.Time
+}
+
+type namespaceLabelEntry struct {
+	restricted bool
+	expires    time.Time
+
+	// namespaces are the restricted namespaces of a cluster, for
+	// CheckAllNamespaces.
+	namespaces []string
+}
+
+// NewNamespaceLabelGuard creates a guard restricting namespaces that match
+// selector, in kubectl label selector syntax. A ttl <= 0 uses
+// DefaultNamespaceLabelCacheTTL.
+func NewNamespaceLabelGuard(selector string, ttl time.Duration, failOpen bool) (*NamespaceLabelGuard, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace label selector %q: %w", selector, err)
+	}
+	if parsed.Empty() {
+		return nil, fmt.Errorf("namespace label selector must not be empty")
+	}
+	if ttl <= 0 {
+		ttl = DefaultNamespaceLabelCacheTTL
+	}
+	return &NamespaceLabelGuard{
+		selector: parsed,
+		ttl:      ttl,
+		FailOpen: failOpen,
+		cache:    make(map[string]namespaceLabelEntry),
+		now:      time.Now,
+	}, nil
+}
+
+// Selector returns the label selector restricted namespaces match.
+func (g *NamespaceLabelGuard) Selector() string {
+	return g.selector.String()
+}
+
+// Check decides whether a tool call may access namespace in cluster, reading
+// the namespace labels through lookup unless they are cached. A namespace that
+// does not exist is not restricted. When lookup fails, the decision follows
+// FailOpen and the error is returned alongside it for logging.
+func (g *NamespaceLabelGuard) Check(ctx context.Context, cluster, namespace string, lookup NamespaceLabelLookup) (Decision, error) {
+	if namespace == "" {
+		return Decision{Allowed: true}, nil
+	}
+
+	key := cluster + "/" + namespace
+	now := g.now()
+
+	g.mu.Lock()
+	entry, ok := g.cache[key]
+	g.mu.Unlock()
+
+	if !ok || now.After(entry.expires) {
+		nsLabels, found, err := lookup(ctx, cluster, namespace)
+		if err != nil {
+			if g.FailOpen {
+				return Decision{Allowed: true}, err
+			}
+			return Decision{
+				Allowed: false,
+				Reason:  fmt.Sprintf("cannot verify access to namespace %q: failed to read its labels", namespace),
+			}, err
+		}
+		entry = namespaceLabelEntry{
+			restricted: found && g.selector.Matches(labels.Set(nsLabels)),
+			expires:    now.Add(g.ttl),
+		}
+		g.store(key, entry, now)
+	}
+
+	if entry.restricted {
+		return Decision{
+			Allowed: false,
+			Reason:  fmt.Sprintf("access to namespace %q is restricted by its labels (%s)", namespace, g.selector.String()),
+		}, nil
+	}
+	return Decision{Allowed: true}, nil
+}
+
+// CheckAllNamespaces decides whether a tool call may read across all
+// namespaces of cluster, which it may not while any namespace is
+// restricted. The restricted namespaces are listed through lookup unless
+// they are cached. When lookup fails, the decision follows FailOpen and the
+// error is returned alongside it for logging.
+func (g *NamespaceLabelGuard) CheckAllNamespaces(ctx context.Context, cluster string, lookup NamespaceSelectorLookup) (Decision, error) {
+	// "*" is not a valid namespace name, so the key is the cluster's own.
+	key := cluster + "/*"
+	now := g.now()
+
+	g.mu.Lock()
+	entry, ok := g.cache[key]
+	g.mu.Unlock()
+
+	if !ok || now.After(entry.expires) {
+		namespaces, err := lookup(ctx, cluster, g.selector.String())
+		if err != nil {
+			if g.FailOpen {
+				return Decision{Allowed: true}, err
+			}
+			return Decision{
+				Allowed: false,
+				Reason:  "cannot verify access across all namespaces: failed to list restricted namespaces",
+			}, err
+		}
+		sort.Strings(namespaces)
+		entry = namespaceLabelEntry{
+			restricted: len(namespaces) > 0,
+			expires:    now.Add(g.ttl),
+			namespaces: namespaces,
+		}
+		g.store(key, entry, now)
+	}
+
+	if entry.restricted {
+		return Decision{
+			Allowed: false,
+			Reason: fmt.Sprintf("access across all namespaces is not allowed: namespaces %s are restricted by their labels (%s)",
+				strings.Join(entry.namespaces, ", "), g.selector.String()),
+		}, nil
+	}
+	return Decision{Allowed: true}, nil
+}
+
+// store caches entry under key, dropping expired entries when the cache is full.
+func (g *NamespaceLabelGuard) store(key string, entry namespaceLabelEntry, now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.cache) >= maxNamespaceLabelCacheEntries {
+		for k, e := range g.cache {
+			if now.After(e.expires) {
+				delete(g.cache, k)
+			}
+		}
+		if len(g.cache) >= maxNamespaceLabelCacheEntries {
+			return
+		}
+	}
+	g.cache[key] = entry
+}
@@ -0,0 +1,166 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const denySelector = "policy.giantswarm.io/mcp-access=deny"
+
+// fakeNamespaces serves namespace labels per cluster and counts lookups.
+type fakeNamespaces struct {
+	labels map[string]map[string]string
+	err    error
+	calls  int
+}
+
+func (f *fakeNamespaces) lookup(_ context.Context, cluster, namespace string) (map[string]string, bool, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, false, f.err
+	}
+	l, ok := f.labels[cluster+"/"+namespace]
+	return l, ok, nil
+}
+
+func (f *fakeNamespaces) list(_ context.Context, cluster, _ string) ([]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	var names []string
+	for key, l := range f.labels {
+		c, namespace, _ := strings.Cut(key, "/")
+		if c == cluster && l["policy.giantswarm.io/mcp-access"] == "deny" {
+			names = append(names, namespace)
+		}
+	}
+	return names, nil
+}
+
+func TestNewNamespaceLabelGuard_Validation(t *testing.T) {
+	_, err := NewNamespaceLabelGuard("", 0, false)
+	assert.ErrorContains(t, err, "must not be empty")
+
+	_, err = NewNamespaceLabelGuard("a in (", 0, false)
+	assert.ErrorContains(t, err, "invalid namespace label selector")
+
+	g, err := NewNamespaceLabelGuard(denySelector, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, denySelector, g.Selector())
+	assert.Equal(t, DefaultNamespaceLabelCacheTTL, g.ttl)
+}
+
+func TestNamespaceLabelGuard_Check(t *testing.T) {
+	ns := &fakeNamespaces{labels: map[string]map[string]string{
+		"/secrets":     {"policy.giantswarm.io/mcp-access": "deny"},
+		"/apps":        {"team": "a"},
+		"prod/secrets": {},
+	}}
+	g, err := NewNamespaceLabelGuard(denySelector, time.Minute, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		cluster   string
+		namespace string
+		allowed   bool
+	}{
+		{name: "labelled namespace is restricted", namespace: "secrets", allowed: false},
+		{name: "other labels are allowed", namespace: "apps", allowed: true},
+		{name: "missing namespace is allowed", namespace: "missing", allowed: true},
+		{name: "labels are per cluster", cluster: "prod", namespace: "secrets", allowed: true},
+		{name: "no namespace is allowed", namespace: "", allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := g.Check(context.Background(), tt.cluster, tt.namespace, ns.lookup)
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, decision.Allowed)
+			if !tt.allowed {
+				assert.Contains(t, decision.Reason, "restricted by its labels")
+			}
+		})
+	}
+}
+
+func TestNamespaceLabelGuard_Cache(t *testing.T) {
+	ns := &fakeNamespaces{labels: map[string]map[string]string{
+		"/apps": {},
+	}}
+	g, err := NewNamespaceLabelGuard(denySelector, time.Minute, false)
+	require.NoError(t, err)
+	now := time.Now()
+	g.now = func() time.Time { return now }
+
+	decision, err := g.Check(context.Background(), "", "apps", ns.lookup)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	// The namespace is labelled, but the cached labels are still used.
+	ns.labels["/apps"] = map[string]string{"policy.giantswarm.io/mcp-access": "deny"}
+	decision, err = g.Check(context.Background(), "", "apps", ns.lookup)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 1, ns.calls)
+
+	// Once the entry expires the new labels apply.
+	now = now.Add(2 * time.Minute)
+	decision, err = g.Check(context.Background(), "", "apps", ns.lookup)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 2, ns.calls)
+}
+
+func TestNamespaceLabelGuard_LookupFailure(t *testing.T) {
+	ns := &fakeNamespaces{err: errors.New("forbidden")}
+
+	g, err := NewNamespaceLabelGuard(denySelector, time.Minute, false)
+	require.NoError(t, err)
+	decision, err := g.Check(context.Background(), "", "apps", ns.lookup)
+	assert.Error(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "cannot verify access")
+
+	g.FailOpen = true
+	decision, err = g.Check(context.Background(), "", "apps", ns.lookup)
+	assert.Error(t, err)
+	assert.True(t, decision.Allowed)
+
+	// Failures are not cached.
+	assert.Equal(t, 2, ns.calls)
+}
+
+func TestNamespaceLabelGuard_CheckAllNamespaces(t *testing.T) {
+	ns := &fakeNamespaces{labels: map[string]map[string]string{
+		"/secrets":  {"policy.giantswarm.io/mcp-access": "deny"},
+		"/payments": {"policy.giantswarm.io/mcp-access": "deny"},
+		"prod/apps": {"team": "a"},
+	}}
+	g, err := NewNamespaceLabelGuard(denySelector, time.Minute, false)
+	require.NoError(t, err)
+
+	decision, err := g.CheckAllNamespaces(context.Background(), "", ns.list)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "namespaces payments, secrets are restricted by their labels")
+
+	decision, err = g.CheckAllNamespaces(context.Background(), "prod", ns.list)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed, "labels are per cluster")
+
+	_, _ = g.CheckAllNamespaces(context.Background(), "", ns.list)
+	assert.Equal(t, 2, ns.calls, "the restricted namespaces are cached per cluster")
+
+	ns.err = errors.New("forbidden")
+	decision, err = g.CheckAllNamespaces(context.Background(), "staging", ns.list)
+	assert.Error(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "cannot verify access across all namespaces")
+}
@@ -0,0 +1,199 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultOPATimeout bounds a single OPA decision request.
+const DefaultOPATimeout = 2 * time.Second
+
+// maxOPAResponseBytes caps the decision response body read from OPA.
+const maxOPAResponseBytes = 1 << 20
+
+// AuthorizationInput is the document sent to an external authorizer for every
+// tool call. It is the OPA "input".
+type AuthorizationInput struct {
+	Tool      string   `json:"tool"`
+	Verb      string   `json:"verb"`
+	Cluster   string   `json:"cluster"`
+	Namespace string   `json:"namespace,omitempty"`
+	Resource  string   `json:"resource,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	User      UserInfo `json:"user"`
+
+	// Access is whether the call reads or writes, so that policies can
+	// restrict the two differently.
+	Access Access `json:"access"`
+
+	// AllNamespaces is set for calls across all namespaces, which have no
+	// Namespace.
+	AllNamespaces bool `json:"allNamespaces"`
+
+	// ResourceUnknown is set for calls that may touch objects of resources
+	// not named in Resource, so that policies can fail closed on them.
+	ResourceUnknown bool `json:"resourceUnknown"`
+
+	// ImpersonatedUser is set when the call runs under an impersonate-as override.
+	ImpersonatedUser *UserInfo `json:"impersonatedUser,omitempty"`
+}
+
+// UserInfo identifies the caller in an AuthorizationInput.
+type UserInfo struct {
+	Email  string   `json:"email,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Authorizer makes an allow/deny decision for a tool call.
+type Authorizer interface {
+	Authorize(ctx context.Context, input AuthorizationInput) (Decision, error)
+}
+
+// OPAAuthorizer queries an Open Policy Agent server (typically a sidecar)
+// through its Data API: POST <URL> with {"input": ...}.
+//
+// The policy result may be a boolean, or an object with an "allow" boolean
+// and an optional "reason" string:
+//
+//	package mcp.authz
+//	default allow := false
+//	allow if input.verb in {"get", "list", "describe"}
+//
+// A missing (undefined) result is treated as a denial.
+type OPAAuthorizer struct {
+	// URL is the full decision URL, e.g.
+	// http://localhost:8181/v1/data/mcp/authz/allow
+	URL string
+
+	// Client is the HTTP client used for requests. Defaults to a client
+	// with Timeout.
+	Client *http.Client
+
+	// Timeout bounds each decision request. Defaults to DefaultOPATimeout.
+	Timeout time.Duration
+
+	// FailOpen allows calls when OPA cannot be reached or returns an
+	// invalid response. The default is to deny.
+	FailOpen bool
+}
+
+// NewOPAAuthorizer creates an authorizer for the given decision URL.
+func NewOPAAuthorizer(url string, timeout time.Duration, failOpen bool) *OPAAuthorizer {
+	if timeout <= 0 {
+		timeout = DefaultOPATimeout
+	}
+	return &OPAAuthorizer{
+		URL:      url,
+		Client:   &http.Client{Timeout: timeout},
+		Timeout:  timeout,
+		FailOpen: failOpen,
+	}
+}
+
+// opaResponse is the Data API response envelope.
+type opaResponse struct {
+	Result json.RawMessage `json:"result"`
+}
+
+// opaObjectResult is the object form of a policy result.
+type opaObjectResult struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+// Authorize asks OPA for a decision. When OPA fails, the decision follows
+// FailOpen and the error is returned alongside it for logging.
+func (a *OPAAuthorizer) Authorize(ctx context.Context, input AuthorizationInput) (Decision, error) {
+	decision, err := a.query(ctx, input)
+	if err != nil {
+		if a.FailOpen {
+			return Decision{Allowed: true}, err
+		}
+		return Decision{Allowed: false, Reason: "authorization service unavailable"}, err
+	}
+	return decision, nil
+}
+
+func (a *OPAAuthorizer) query(ctx context.Context, input AuthorizationInput) (Decision, error) {
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultOPATimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to encode OPA input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to create OPA request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("OPA request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOPAResponseBytes))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to read OPA response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Decision{}, fmt.Errorf("OPA returned status %d", resp.StatusCode)
+	}
+
+	var envelope opaResponse
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode OPA response: %w", err)
+	}
+	return parseOPAResult(envelope.Result, input)
+}
+
+// parseOPAResult interprets a boolean or {allow, reason} policy result.
+func parseOPAResult(raw json.RawMessage, input AuthorizationInput) (Decision, error) {
+	denied := fmt.Sprintf("%s is denied by external policy", describeRequest(Request{
+		Cluster:   input.Cluster,
+		Namespace: input.Namespace,
+		Resource:  input.Resource,
+		Verb:      input.Verb,
+	}))
+
+	// Undefined result: the policy did not produce a decision.
+	if len(raw) == 0 || string(raw) == "null" {
+		return Decision{Allowed: false, Reason: denied}, nil
+	}
+
+	var allowed bool
+	if err := json.Unmarshal(raw, &allowed); err == nil {
+		if allowed {
+			return Decision{Allowed: true}, nil
+		}
+		return Decision{Allowed: false, Reason: denied}, nil
+	}
+
+	var obj opaObjectResult
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return Decision{}, fmt.Errorf("unexpected OPA result %s: must be a boolean or an object with an allow field", string(raw))
+	}
+	if obj.Allow {
+		return Decision{Allowed: true}, nil
+	}
+	if obj.Reason != "" {
+		denied += ": " + obj.Reason
+	}
+	return Decision{Allowed: false, Reason: denied}, nil
+}
@@ -0,0 +1,104 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOPAServer(t *testing.T, status int, response string, received *map[string]AuthorizationInput) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if received != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOPAAuthorizer_Decisions(t *testing.T) {
+	input := AuthorizationInput{
+		Tool:      "kubernetes_delete",
+		Verb:      "delete",
+		Cluster:   "prod-eu",
+		Namespace: "app",
+		Resource:  "pods",
+		User:      UserInfo{Email: "jane@example.com", Groups: []string{"devs"}},
+	}
+
+	tests := []struct {
+		name       string
+		response   string
+		allowed    bool
+		wantReason string
+	}{
+		{name: "boolean allow", response: `{"result": true}`, allowed: true},
+		{name: "boolean deny", response: `{"result": false}`, wantReason: "delete pods in namespace app on cluster prod-eu is denied by external policy"},
+		{name: "object allow", response: `{"result": {"allow": true}}`, allowed: true},
+		{name: "object deny with reason", response: `{"result": {"allow": false, "reason": "change freeze"}}`, wantReason: "change freeze"},
+		{name: "undefined result", response: `{}`, wantReason: "denied by external policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received map[string]AuthorizationInput
+			srv := newOPAServer(t, http.StatusOK, tt.response, &received)
+
+			d, err := NewOPAAuthorizer(srv.URL, time.Second, false).Authorize(context.Background(), input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, d.Allowed)
+			assert.Contains(t, d.Reason, tt.wantReason)
+			assert.Equal(t, input, received["input"])
+		})
+	}
+}
+
+func TestOPAAuthorizer_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+	}{
+		{name: "server error", status: http.StatusInternalServerError, response: `{}`},
+		{name: "malformed body", status: http.StatusOK, response: `not json`},
+		{name: "unexpected result type", status: http.StatusOK, response: `{"result": "yes"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOPAServer(t, tt.status, tt.response, nil)
+
+			d, err := NewOPAAuthorizer(srv.URL, time.Second, false).Authorize(context.Background(), AuthorizationInput{Verb: "get"})
+			assert.Error(t, err)
+			assert.False(t, d.Allowed, "fail closed by default")
+
+			d, err = NewOPAAuthorizer(srv.URL, time.Second, true).Authorize(context.Background(), AuthorizationInput{Verb: "get"})
+			assert.Error(t, err)
+			assert.True(t, d.Allowed, "fail open when configured")
+		})
+	}
+}
+
+func TestOPAAuthorizer_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	d, err := NewOPAAuthorizer(srv.URL, 20*time.Millisecond, false).Authorize(context.Background(), AuthorizationInput{Verb: "get"})
+	assert.Error(t, err)
+	assert.False(t, d.Allowed)
+}
@@ -0,0 +1,283 @@
+package security
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Effect is the outcome of a policy rule.
+type Effect string
+
+// Supported rule effects.
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Rule is a single allow or deny statement. Every non-empty dimension must
+// match for the rule to apply; an empty dimension matches anything.
+type Rule struct {
+	// Effect is either "allow" or "deny".
+	Effect Effect `json:"effect"`
+
+	// Description is a human-readable note surfaced in denial reasons.
+	Description string `json:"description,omitempty"`
+
+	// Clusters matches the target cluster name. The local (management)
+	// cluster is matched as LocalCluster.
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Namespaces matches the target namespace. Cluster-scoped requests have
+	// an empty namespace and only match rules without namespace patterns.
+	// Requests across all namespaces match every deny rule with namespace
+	// patterns, as they include the namespaces it names, and no allow rule
+	// with them.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Resources matches the plural resource name (e.g. "pods",
+	// "deployments", "secrets"). Callers resolve singular names, kinds and
+	// short names to it before evaluation. A request without a resource that
+	// may still touch objects matches every deny rule with resource
+	// patterns, as its resource is unknown.
+	Resources []string `json:"resources,omitempty"`
+
+	// Verbs matches the operation, e.g. get, list, describe, create, apply,
+	// delete, patch, scale, logs, exec, port_forward.
+	Verbs []string `json:"verbs,omitempty"`
+}
+
+// Policy is a set of rules plus the effect applied when none matches.
+type Policy struct {
+	// DefaultEffect applies when no rule matches. Defaults to allow.
+	DefaultEffect Effect `json:"defaultEffect,omitempty"`
+
+	// Rules are evaluated as a whole: any matching deny wins, otherwise any
+	// matching allow, otherwise DefaultEffect.
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// LocalCluster is the cluster name used for requests against the local
+// (management) cluster, i.e. when no cluster parameter was given.
+const LocalCluster = "local"
+
+// Request describes an operation to authorize.
+type Request struct {
+	Cluster   string
+	Namespace string
+	Resource  string
+	Verb      string
+
+	// AllNamespaces is set for requests across all namespaces, which have
+	// an empty Namespace.
+	AllNamespaces bool
+
+	// NoObjects is set for requests that touch no objects of their own, such
+	// as listing contexts, or that check each object they touch separately.
+	// Without it, a request with an empty Resource has an unknown resource.
+	NoObjects bool
+}
+
+// ResourceUnknown reports whether req may touch objects whose resource is
+// not known.
+func (req Request) ResourceUnknown() bool {
+	return req.Resource == "" && !req.NoObjects
+}
+
+// Decision is the result of evaluating a Request against a Policy.
+type Decision struct {
+	Allowed bool
+
+	// Rule is the rule that decided the outcome, or nil when the default applied.
+	Rule *Rule
+
+	// Reason is a short explanation suitable for tool error messages.
+	Reason string
+}
+
+// ParsePolicy parses and validates a YAML (or JSON) policy document.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that effects are known and all patterns are well-formed.
+func (p *Policy) Validate() error {
+	switch p.DefaultEffect {
+	case "", EffectAllow, EffectDeny:
+	default:
+		return fmt.Errorf("invalid defaultEffect %q: must be %q or %q", p.DefaultEffect, EffectAllow, EffectDeny)
+	}
+
+	for i, r := range p.Rules {
+		if r.Effect != EffectAllow && r.Effect != EffectDeny {
+			return fmt.Errorf("rule %d: invalid effect %q: must be %q or %q", i, r.Effect, EffectAllow, EffectDeny)
+		}
+		for _, patterns := range [][]string{r.Clusters, r.Namespaces, r.Resources, r.Verbs} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("rule %d: invalid pattern %q: %w", i, pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate decides whether req is permitted.
+func (p *Policy) Evaluate(req Request) Decision {
+	if req.Cluster == "" {
+		req.Cluster = LocalCluster
+	}
+
+	var allow *Rule
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if !r.matches(req) {
+			continue
+		}
+		if r.Effect == EffectDeny {
+			return Decision{Allowed: false, Rule: r, Reason: denialReason(r, req)}
+		}
+		if allow == nil {
+			allow = r
+		}
+	}
+
+	if allow != nil {
+		return Decision{Allowed: true, Rule: allow}
+	}
+	if p.DefaultEffect == EffectDeny {
+		return Decision{Allowed: false, Reason: fmt.Sprintf("%s is not permitted by policy", describeRequest(req))}
+	}
+	return Decision{Allowed: true}
+}
+
+// VerbDenied reports whether verb is denied regardless of cluster, namespace
+// and resource: either an unconditional deny rule names it, or the default is
+// deny and no allow rule could ever match it. Use it to hide tools that can
+// never succeed; per-call decisions still go through Evaluate.
+func (p *Policy) VerbDenied(verb string) bool {
+	allowable := p.DefaultEffect != EffectDeny
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if !matchAny(r.Verbs, verb) {
+			continue
+		}
+		if r.Effect == EffectDeny && len(r.Clusters) == 0 && len(r.Namespaces) == 0 && len(r.Resources) == 0 {
+			return true
+		}
+		if r.Effect == EffectAllow {
+			allowable = true
+		}
+	}
+	return !allowable
+}
+
+func (r *Rule) matches(req Request) bool {
+	return matchAny(r.Clusters, req.Cluster) &&
+		r.matchesNamespace(req) &&
+		r.matchesResource(req) &&
+		matchAny(r.Verbs, req.Verb)
+}
+
+// matchesResource reports whether the resource of req matches r. A request
+// whose resource is unknown fails closed: it may touch the resources a deny
+// rule names.
+func (r *Rule) matchesResource(req Request) bool {
+	if req.ResourceUnknown() && len(r.Resources) > 0 && r.Effect == EffectDeny {
+		return true
+	}
+	return matchAnyResource(r.Resources, req.Resource)
+}
+
+// matchesNamespace reports whether the namespace of req matches r. A request
+// across all namespaces includes the namespaces a deny rule names, but is
+// not limited to those an allow rule names.
+func (r *Rule) matchesNamespace(req Request) bool {
+	if req.AllNamespaces && len(r.Namespaces) > 0 {
+		return r.Effect == EffectDeny
+	}
+	return matchAny(r.Namespaces, req.Namespace)
+}
+
+// matchAny reports whether value matches any of the glob patterns.
+// An empty pattern list matches everything.
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyResource reports whether resource matches any of the patterns, as
+// matchResource does. An empty pattern list matches everything.
+func matchAnyResource(patterns []string, resource string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matchResource(pattern, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchResource reports whether resource matches the glob pattern. Both are
+// compared in lower case, and a resource that was not resolved to its plural
+// also matches in its plural form, so that "secret" and "Secret" are covered
+// by "secrets". Policy and NamespaceAccess share it.
+func matchResource(pattern, resource string) bool {
+	pattern, resource = strings.ToLower(pattern), strings.ToLower(resource)
+	candidates := []string{resource}
+	if resource != "" {
+		candidates = append(candidates, resource+"s", resource+"es")
+	}
+	for _, candidate := range candidates {
+		if ok, _ := path.Match(pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func denialReason(r *Rule, req Request) string {
+	reason := fmt.Sprintf("%s is denied by policy", describeRequest(req))
+	if r.Description != "" {
+		reason += ": " + r.Description
+	}
+	return reason
+}
+
+func describeRequest(req Request) string {
+	var b strings.Builder
+	b.WriteString(req.Verb)
+	if req.Resource != "" {
+		b.WriteString(" " + req.Resource)
+	} else if req.ResourceUnknown() {
+		b.WriteString(" of unknown resources")
+	}
+	if req.Namespace != "" {
+		b.WriteString(" in namespace " + req.Namespace)
+	} else if req.AllNamespaces {
+		b.WriteString(" across all namespaces")
+	}
+	if req.Cluster != "" && req.Cluster != LocalCluster {
+		b.WriteString(" on cluster " + req.Cluster)
+	}
+	return b.String()
+}
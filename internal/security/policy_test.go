@@ -0,0 +1,167 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const examplePolicy = `
+defaultEffect: allow
+rules:
+  - effect: deny
+    description: system namespaces are off limits
+    namespaces: ["kube-system", "kube-public"]
+  - effect: deny
+    clusters: ["prod-*"]
+    verbs: ["delete", "patch", "scale", "exec"]
+  - effect: allow
+    clusters: ["prod-*"]
+    namespaces: ["sandbox-*"]
+    verbs: ["exec"]
+  - effect: deny
+    resources: ["secrets"]
+    verbs: ["get", "list", "describe"]
+`
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy([]byte(examplePolicy))
+	require.NoError(t, err)
+	assert.Equal(t, EffectAllow, p.DefaultEffect)
+	require.Len(t, p.Rules, 4)
+	assert.Equal(t, []string{"kube-system", "kube-public"}, p.Rules[0].Namespaces)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "unknown field", data: "rules:\n  - effect: deny\n    namespace: [x]\n", wantErr: "failed to parse policy"},
+		{name: "bad effect", data: "rules:\n  - effect: maybe\n", wantErr: "invalid effect"},
+		{name: "bad default", data: "defaultEffect: perhaps\n", wantErr: "invalid defaultEffect"},
+		{name: "bad pattern", data: "rules:\n  - effect: deny\n    clusters: [\"[\"]\n", wantErr: "invalid pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	p, err := ParsePolicy([]byte(examplePolicy))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		req     Request
+		allowed bool
+	}{
+		{name: "default allow", req: Request{Verb: "get", Resource: "pods", Namespace: "default"}, allowed: true},
+		{name: "restricted namespace", req: Request{Verb: "list", Resource: "pods", Namespace: "kube-system"}, allowed: false},
+		{name: "prod delete denied", req: Request{Cluster: "prod-eu", Verb: "delete", Resource: "pods", Namespace: "app"}, allowed: false},
+		{name: "prod read allowed", req: Request{Cluster: "prod-eu", Verb: "get", Resource: "pods", Namespace: "app"}, allowed: true},
+		{name: "deny beats allow", req: Request{Cluster: "prod-eu", Verb: "exec", Resource: "pods", Namespace: "sandbox-1"}, allowed: false},
+		{name: "secrets denied", req: Request{Verb: "get", Resource: "Secrets", Namespace: "app"}, allowed: false},
+		{name: "singular secret denied", req: Request{Verb: "get", Resource: "secret", Namespace: "app"}, allowed: false},
+		{name: "non-prod delete allowed", req: Request{Cluster: "dev", Verb: "delete", Resource: "pods", Namespace: "app"}, allowed: true},
+		{name: "all namespaces include restricted namespace", req: Request{Verb: "list", Resource: "pods", AllNamespaces: true}, allowed: false},
+		{name: "cluster-scoped request", req: Request{Verb: "list", Resource: "nodes"}, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := p.Evaluate(tt.req)
+			assert.Equal(t, tt.allowed, d.Allowed)
+			if !tt.allowed {
+				assert.NotEmpty(t, d.Reason)
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluate_DefaultDeny(t *testing.T) {
+	p := &Policy{
+		DefaultEffect: EffectDeny,
+		Rules: []Rule{
+			{Effect: EffectAllow, Clusters: []string{LocalCluster}, Verbs: []string{"get", "list"}},
+		},
+	}
+
+	assert.True(t, p.Evaluate(Request{Verb: "list", Resource: "pods"}).Allowed)
+
+	d := p.Evaluate(Request{Cluster: "wc-1", Verb: "list", Resource: "pods"})
+	assert.False(t, d.Allowed)
+	assert.Nil(t, d.Rule)
+	assert.Contains(t, d.Reason, "on cluster wc-1")
+}
+
+func TestPolicyEvaluate_DenialReasonIncludesDescription(t *testing.T) {
+	p, err := ParsePolicy([]byte(examplePolicy))
+	require.NoError(t, err)
+
+	d := p.Evaluate(Request{Verb: "delete", Resource: "pods", Namespace: "kube-system"})
+	require.False(t, d.Allowed)
+	require.NotNil(t, d.Rule)
+	assert.Equal(t, "delete pods in namespace kube-system is denied by policy: system namespaces are off limits", d.Reason)
+}
+
+func TestPolicyVerbDenied(t *testing.T) {
+	p, err := ParsePolicy([]byte(examplePolicy))
+	require.NoError(t, err)
+	assert.False(t, p.VerbDenied("delete"), "delete is only denied on prod clusters")
+	assert.False(t, p.VerbDenied("get"))
+
+	p = &Policy{Rules: []Rule{{Effect: EffectDeny, Verbs: []string{"exec", "port_forward"}}}}
+	assert.True(t, p.VerbDenied("exec"))
+	assert.False(t, p.VerbDenied("logs"))
+
+	p = &Policy{
+		DefaultEffect: EffectDeny,
+		Rules:         []Rule{{Effect: EffectAllow, Namespaces: []string{"dev-*"}, Verbs: []string{"get", "list"}}},
+	}
+	assert.False(t, p.VerbDenied("get"), "allowed in some namespaces")
+	assert.True(t, p.VerbDenied("delete"), "no allow rule can match")
+}
+
+func TestPolicyEvaluate_AllNamespaces(t *testing.T) {
+	p := &Policy{
+		DefaultEffect: EffectDeny,
+		Rules: []Rule{
+			{Effect: EffectDeny, Namespaces: []string{"kube-system"}, Resources: []string{"secrets"}},
+			{Effect: EffectAllow, Namespaces: []string{"dev-*"}},
+			{Effect: EffectAllow, Verbs: []string{"list"}},
+		},
+	}
+
+	d := p.Evaluate(Request{Verb: "list", Resource: "secrets", AllNamespaces: true})
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "list secrets across all namespaces is denied by policy", d.Reason)
+	assert.True(t, p.Evaluate(Request{Verb: "list", Resource: "pods", AllNamespaces: true}).Allowed)
+	assert.False(t, p.Evaluate(Request{Verb: "get", Resource: "pods", AllNamespaces: true}).Allowed,
+		"an allow rule limited to some namespaces does not cover all of them")
+	assert.True(t, p.Evaluate(Request{Verb: "get", Resource: "pods", Namespace: "dev-1"}).Allowed)
+}
+
+func TestPolicyEvaluate_UnknownResource(t *testing.T) {
+	p := &Policy{
+		Rules: []Rule{
+			{Effect: EffectDeny, Resources: []string{"secrets"}},
+			{Effect: EffectDeny, Resources: []string{"pods"}, Verbs: []string{"logs"}},
+		},
+	}
+
+	d := p.Evaluate(Request{Verb: "namespace_overview", Namespace: "app"})
+	assert.False(t, d.Allowed, "a deny rule with resources fails closed")
+	assert.Equal(t, "namespace_overview of unknown resources in namespace app is denied by policy", d.Reason)
+	assert.True(t, p.Evaluate(Request{Verb: "context_list", NoObjects: true}).Allowed, "a call without objects matches no resource")
+	assert.True(t, p.Evaluate(Request{Verb: "get", Resource: "pods", Namespace: "app"}).Allowed)
+
+	p = &Policy{
+		DefaultEffect: EffectDeny,
+		Rules:         []Rule{{Effect: EffectAllow, Resources: []string{"pods"}}},
+	}
+	assert.False(t, p.Evaluate(Request{Verb: "diagnose_pod", Namespace: "app"}).Allowed, "an allow rule with resources does not cover an unknown resource")
+}
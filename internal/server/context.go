@@ -6,10 +6,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+	"github.com/giantswarm/mcp-kubernetes/internal/confirmation"
 	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
+	"github.com/giantswarm/mcp-kubernetes/internal/plan"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
 )
 
 // ServerContext encapsulates all dependencies needed by the MCP server
@@ -41,6 +49,60 @@ type ServerContext struct {
 	// When set, enables operations across multiple Kubernetes clusters via CAPI.
 	federationManager federation.ClusterClientManager
 
+	// Tool authorization policy. When set, every tool call is evaluated
+	// against it before the handler runs.
+	policyEngine *security.Engine
+
+	// External authorizer (e.g. OPA), consulted after the policy engine.
+	authorizer security.Authorizer
+
+	// Restricts namespaces by their labels. Nil disables label-based
+	// restrictions.
+	namespaceLabelGuard *security.NamespaceLabelGuard
+
+	// Read restrictions and write allowlist by namespace. Nil allows reads
+	// and writes in every namespace.
+	namespaceAccess *security.NamespaceAccess
+
+	// Per-caller tool rate limiter. Nil disables rate limiting.
+	toolRateLimiter *ratelimit.Limiter
+
+	// Destructive tool calls awaiting confirmation. Nil runs them right
+	// away.
+	confirmations *confirmation.Store
+
+	// Records the prior state of changed objects for undo. Nil disables
+	// the undo journal.
+	undoJournal *journal.Journal
+
+	// Change-sets built with the plan tools. Nil disables plans.
+	plans *plan.Store
+
+	// Default cluster and namespace of MCP sessions.
+	sessionDefaults *sessions.DefaultsStore
+
+	// Resource versions last returned to MCP sessions by changedSince
+	// lists.
+	listVersions *sessions.ListVersionStore
+
+	// Per-user preferences applied to tool calls. Nil disables
+	// preferences.
+	preferences *preferences.Store
+
+	// ID of this replica, reported in tool results. Empty when unset.
+	replicaID string
+
+	// Records sanitized tool calls for debugging. Nil disables debug
+	// capture.
+	debugCapture *capture.Recorder
+
+	// Per-request impersonation extras. Nil when none are configured.
+	impersonationExtras *ImpersonationExtrasConfig
+
+	// Shares session ownership with other replicas. Nil when sessions are
+	// only known to this replica.
+	sessionRegistry *sessions.Registry
+
 	// OpenTelemetry instrumentation provider
 	instrumentationProvider *instrumentation.Provider
 
@@ -70,6 +132,9 @@ func NewServerContext(ctx context.Context, opts ...Option) (*ServerContext, erro
 		config:         NewDefaultConfig(),
 		logger:         NewDefaultLogger(),
 		activeSessions: make(map[string]*k8s.PortForwardSession),
+
+		sessionDefaults: sessions.NewDefaultsStore(0),
+		listVersions:    sessions.NewListVersionStore(0),
 	}
 
 	// Apply functional options
@@ -80,6 +145,15 @@ func NewServerContext(ctx context.Context, opts ...Option) (*ServerContext, erro
 		}
 	}
 
+	// Read restrictions and write namespaces given through WithConfig take
+	// effect as if set with WithNamespaceAccess.
+	if sc.namespaceAccess == nil && (len(sc.config.ReadRestrictions) > 0 || len(sc.config.WriteNamespaces) > 0) {
+		if err := WithNamespaceAccess(sc.config.ReadRestrictions, sc.config.WriteNamespaces, sc.config.AllowClusterScopedWrites)(sc); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
 	// Validate required dependencies
 	if err := sc.validate(); err != nil {
 		cancel()
@@ -167,8 +241,17 @@ func (sc *ServerContext) K8sClientForContext(ctx context.Context) (k8s.Client, e
 		return sc.k8sClient, nil
 	}
 
-	// Create a per-user client with the bearer token
-	client, err := sc.clientFactory.CreateBearerTokenClient(idToken)
+	// Create a per-user client with the bearer token. When the token can be
+	// refreshed, the client fetches it before each request instead, so it
+	// keeps working if held past the token's expiry.
+	var client k8s.Client
+	var err error
+	source, hasSource := oauth.GetIDTokenSourceFromContext(ctx)
+	if sourceFactory, ok := sc.clientFactory.(k8s.TokenSourceClientFactory); ok && hasSource {
+		client, err = sourceFactory.CreateTokenSourceClient(source.Key(), source)
+	} else {
+		client, err = sc.clientFactory.CreateBearerTokenClient(idToken)
+	}
 	if err != nil {
 		if sc.downstreamOAuthStrict {
 			// Strict mode: fail closed - do not fall back to service account
@@ -239,6 +322,124 @@ func (sc *ServerContext) FederationManager() federation.ClusterClientManager {
 	return sc.federationManager
 }
 
+// PolicyEngine returns the tool authorization policy engine.
+// Returns nil if no policy is configured.
+func (sc *ServerContext) PolicyEngine() *security.Engine {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.policyEngine
+}
+
+// Authorizer returns the external tool authorizer.
+// Returns nil if none is configured.
+func (sc *ServerContext) Authorizer() security.Authorizer {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.authorizer
+}
+
+// NamespaceLabelGuard returns the label-based namespace restriction.
+// Returns nil if none is configured.
+func (sc *ServerContext) NamespaceLabelGuard() *security.NamespaceLabelGuard {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.namespaceLabelGuard
+}
+
+// NamespaceAccess returns the read restrictions and write allowlist by
+// namespace. Returns nil if none is configured.
+func (sc *ServerContext) NamespaceAccess() *security.NamespaceAccess {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.namespaceAccess
+}
+
+// ToolRateLimiter returns the per-caller tool rate limiter.
+// Returns nil if rate limiting is disabled.
+func (sc *ServerContext) ToolRateLimiter() *ratelimit.Limiter {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.toolRateLimiter
+}
+
+// Confirmations returns the store of destructive tool calls awaiting
+// confirmation. Returns nil if confirmations are disabled.
+func (sc *ServerContext) Confirmations() *confirmation.Store {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.confirmations
+}
+
+// UndoJournal returns the journal of changes that can be undone.
+// Returns nil if the undo journal is disabled.
+func (sc *ServerContext) UndoJournal() *journal.Journal {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.undoJournal
+}
+
+// Preferences returns the store of per-user preferences.
+// Returns nil if preferences are disabled.
+func (sc *ServerContext) Preferences() *preferences.Store {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.preferences
+}
+
+// Plans returns the store of change-sets built with the plan tools.
+// Returns nil if plans are disabled.
+func (sc *ServerContext) Plans() *plan.Store {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.plans
+}
+
+// SessionDefaults returns the default cluster and namespace of MCP sessions.
+func (sc *ServerContext) SessionDefaults() *sessions.DefaultsStore {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.sessionDefaults
+}
+
+// ListVersions returns the resource versions that changedSince lists
+// compare against.
+func (sc *ServerContext) ListVersions() *sessions.ListVersionStore {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.listVersions
+}
+
+// ReplicaID returns the ID of this replica, or "" if none is configured.
+func (sc *ServerContext) ReplicaID() string {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.replicaID
+}
+
+// DebugCapture returns the recorder of sanitized tool calls, or nil if
+// debug capture is disabled.
+func (sc *ServerContext) DebugCapture() *capture.Recorder {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.debugCapture
+}
+
+// ImpersonationExtras returns the per-request impersonation extras
+// configuration, or nil if none is configured.
+func (sc *ServerContext) ImpersonationExtras() *ImpersonationExtrasConfig {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.impersonationExtras
+}
+
+// SessionRegistry returns the registry sharing session ownership with
+// other replicas. Returns nil if sessions are only tracked locally.
+func (sc *ServerContext) SessionRegistry() *sessions.Registry {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.sessionRegistry
+}
+
 // FederationEnabled returns true if multi-cluster federation is enabled.
 func (sc *ServerContext) FederationEnabled() bool {
 	sc.mu.RLock()
@@ -272,6 +473,12 @@ func (sc *ServerContext) OutputConfig() *OutputConfig {
 	return NewDefaultOutputConfig()
 }
 
+// OutputConfigFor returns the output processing configuration for the named
+// cluster ("" for the local cluster), with its overrides applied.
+func (sc *ServerContext) OutputConfigFor(clusterName string) *OutputConfig {
+	return sc.OutputConfig().ForCluster(clusterName)
+}
+
 // RecordK8sOperation records a Kubernetes operation metric if instrumentation is enabled.
 // This is a convenience method that handles nil checks internally.
 func (sc *ServerContext) RecordK8sOperation(ctx context.Context, clusterName, operation, resourceType, namespace, status string, duration time.Duration) {
@@ -296,6 +503,30 @@ func (sc *ServerContext) RecordPodOperation(ctx context.Context, operation, name
 	}
 }
 
+// RecordPIIScrubbed records PII matches masked in tool output if
+// instrumentation is enabled.
+func (sc *ServerContext) RecordPIIScrubbed(ctx context.Context, pattern string, count int) {
+	sc.mu.RLock()
+	provider := sc.instrumentationProvider
+	sc.mu.RUnlock()
+
+	if provider != nil && provider.Enabled() {
+		provider.Metrics().RecordPIIScrubbed(ctx, pattern, count)
+	}
+}
+
+// RecordCredentialsRedacted records likely credentials redacted from pod log
+// or exec output if instrumentation is enabled.
+func (sc *ServerContext) RecordCredentialsRedacted(ctx context.Context, source, pattern string, count int) {
+	sc.mu.RLock()
+	provider := sc.instrumentationProvider
+	sc.mu.RUnlock()
+
+	if provider != nil && provider.Enabled() {
+		provider.Metrics().RecordCredentialsRedacted(ctx, source, pattern, count)
+	}
+}
+
 // IncrementActiveSessions increments the active port-forward sessions metric.
 func (sc *ServerContext) IncrementActiveSessions(ctx context.Context) {
 	sc.mu.RLock()
@@ -452,6 +683,34 @@ func (sc *ServerContext) Shutdown() error {
 		}
 	}
 
+	// Close the rate limiter backend
+	if sc.toolRateLimiter != nil {
+		if err := sc.toolRateLimiter.Close(); err != nil {
+			sc.logger.Error("Failed to close tool rate limiter", "error", err)
+		}
+	}
+
+	// Close the undo journal backend
+	if sc.undoJournal != nil {
+		if err := sc.undoJournal.Close(); err != nil {
+			sc.logger.Error("Failed to close undo journal", "error", err)
+		}
+	}
+
+	// Close the preferences backend
+	if sc.preferences != nil {
+		if err := sc.preferences.Close(); err != nil {
+			sc.logger.Error("Failed to close preferences store", "error", err)
+		}
+	}
+
+	// Remove this replica's sessions from the shared registry
+	if sc.sessionRegistry != nil {
+		if err := sc.sessionRegistry.Close(); err != nil {
+			sc.logger.Error("Failed to close session registry", "error", err)
+		}
+	}
+
 	// Shutdown instrumentation provider
 	if sc.instrumentationProvider != nil {
 		shutdownCtx := context.Background()
@@ -562,6 +821,59 @@ type Config struct {
 	AllowedOperations    []string `json:"allowedOperations"`
 	RestrictedNamespaces []string `json:"restrictedNamespaces"`
 
+	// ReadRestrictions lists what tool calls may not read, each a namespace
+	// or namespace/resource glob pattern, e.g. "kube-system/secrets".
+	ReadRestrictions []string `json:"readRestrictions,omitempty"`
+
+	// WriteNamespaces lists glob patterns of the namespaces mutating tools
+	// may write to. Empty allows writes in every namespace.
+	WriteNamespaces []string `json:"writeNamespaces,omitempty"`
+
+	// AllowClusterScopedWrites lets mutating tools write cluster-scoped
+	// objects while WriteNamespaces limits them to some namespaces.
+	AllowClusterScopedWrites bool `json:"allowClusterScopedWrites,omitempty"`
+
+	// AllowImpersonateAs exposes the impersonateUser/impersonateGroups tool
+	// parameters, letting callers who hold impersonate RBAC run a tool as
+	// another user. Disabled by default.
+	AllowImpersonateAs bool `json:"allowImpersonateAs"`
+
+	// KubeconfigContexts lists the kubeconfig contexts the kubeContext tool
+	// parameter may select. Empty allows every context in the kubeconfig.
+	KubeconfigContexts []string `json:"kubeconfigContexts,omitempty"`
+
+	// ReadOnlyGroups lists OAuth groups whose members are only offered and
+	// allowed read-only tools, whatever the server-wide safety mode.
+	ReadOnlyGroups []string `json:"readOnlyGroups,omitempty"`
+
+	// DebugImages lists the images debug_pod may start as an ephemeral
+	// container. An entry ending in "*" matches any image with that prefix.
+	// The first entry is the default image. Empty allows only
+	// DefaultDebugImage.
+	DebugImages []string `json:"debugImages,omitempty"`
+
+	// CopyPaths lists the directories in a container that cp_from_pod and
+	// cp_to_pod may read and write. Empty allows only DefaultCopyPaths.
+	CopyPaths []string `json:"copyPaths,omitempty"`
+
+	// CopyMaxBytes caps the size of a file copied to or from a pod. Zero
+	// means DefaultCopyMaxBytes.
+	CopyMaxBytes int64 `json:"copyMaxBytes,omitempty"`
+
+	// SecretWrites controls create, apply and patch of Secrets:
+	// SecretWritesAllow (the default when empty), SecretWritesDeny, or
+	// SecretWritesOperation, which requires SecretWriteOperation to be
+	// allowed explicitly.
+	SecretWrites string `json:"secretWrites,omitempty"`
+
+	// ToolTimeout bounds a single tool call; the handler's context is
+	// cancelled when it passes. Zero disables the timeout.
+	ToolTimeout time.Duration `json:"toolTimeout,omitempty"`
+
+	// ToolTimeouts overrides ToolTimeout for the named tools. A zero
+	// duration disables the timeout for that tool.
+	ToolTimeouts map[string]time.Duration `json:"toolTimeouts,omitempty"`
+
 	// Output processing settings for fleet-scale operations
 	Output *OutputConfig `json:"output,omitempty"`
 }
@@ -589,11 +901,133 @@ type OutputConfig struct {
 	// Default: true (security critical - should rarely be disabled)
 	MaskSecrets bool `json:"maskSecrets" yaml:"maskSecrets"`
 
+	// SecretRedaction is how masked secret data is shown: "full", "keys" or
+	// "hash". Default: keys
+	SecretRedaction string `json:"secretRedaction,omitempty" yaml:"secretRedaction,omitempty"`
+
+	// ScrubPII masks emails, IP addresses, bearer tokens and AWS keys in
+	// annotations, ConfigMap data and container env values.
+	// Default: false
+	ScrubPII bool `json:"scrubPII" yaml:"scrubPII"`
+
+	// PIIPatterns limits PII scrubbing to the named patterns. Empty means all.
+	PIIPatterns []string `json:"piiPatterns,omitempty" yaml:"piiPatterns,omitempty"`
+
+	// ScrubCredentials redacts likely credentials (private keys, JWTs,
+	// bearer tokens, AWS keys) from pod log and exec output.
+	// Default: true
+	ScrubCredentials bool `json:"scrubCredentials" yaml:"scrubCredentials"`
+
+	// CredentialPatterns limits credential scrubbing to the named patterns.
+	// Empty means all.
+	CredentialPatterns []string `json:"credentialPatterns,omitempty" yaml:"credentialPatterns,omitempty"`
+
 	// SummaryThreshold is the item count above which summary mode is suggested.
 	// Default: 500
 	SummaryThreshold int `json:"summaryThreshold" yaml:"summaryThreshold"`
+
+	// TokenWarningThreshold is the estimated token count of a tool response
+	// above which a warning suggesting summary mode or filters is added to
+	// it. 0 disables the warning.
+	// Default: 20000
+	TokenWarningThreshold int `json:"tokenWarningThreshold" yaml:"tokenWarningThreshold"`
+
+	// KindProfiles slim list items of the given kinds down to the listed
+	// JSON paths. Keys are "Kind" or "Kind.group"; see
+	// output.ApplyKindProfile.
+	KindProfiles map[string][]string `json:"kindProfiles,omitempty" yaml:"kindProfiles,omitempty"`
+
+	// ClusterOverrides change settings for the named clusters, on top of
+	// ClusterTypeOverrides.
+	ClusterOverrides map[string]OutputOverride `json:"clusterOverrides,omitempty" yaml:"clusterOverrides,omitempty"`
+
+	// ClusterTypeOverrides change settings for the clusters of a type, as
+	// classified from the cluster name: production, staging, development,
+	// cicd, operations, management (the local cluster) or other.
+	ClusterTypeOverrides map[string]OutputOverride `json:"clusterTypeOverrides,omitempty" yaml:"clusterTypeOverrides,omitempty"`
+}
+
+// OutputOverride changes output settings for some clusters. Unset fields
+// keep the setting of the OutputConfig.
+type OutputOverride struct {
+	MaxItems         *int  `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	SlimOutput       *bool `json:"slimOutput,omitempty" yaml:"slimOutput,omitempty"`
+	MaskSecrets      *bool `json:"maskSecrets,omitempty" yaml:"maskSecrets,omitempty"`
+	SummaryThreshold *int  `json:"summaryThreshold,omitempty" yaml:"summaryThreshold,omitempty"`
 }
 
+// ForCluster returns the settings for the named cluster ("" for the local
+// cluster): the override of its cluster type applied first, then the
+// override of its name. c is returned as is when no override applies.
+func (c *OutputConfig) ForCluster(clusterName string) *OutputConfig {
+	byType, hasType := c.ClusterTypeOverrides[instrumentation.ClassifyClusterName(clusterName)]
+	byName, hasName := c.ClusterOverrides[clusterName]
+	if !hasType && !hasName {
+		return c
+	}
+	cfg := *c
+	if hasType {
+		byType.apply(&cfg)
+	}
+	if hasName {
+		byName.apply(&cfg)
+	}
+	return &cfg
+}
+
+// apply sets the fields of o on cfg.
+func (o OutputOverride) apply(cfg *OutputConfig) {
+	if o.MaxItems != nil {
+		cfg.MaxItems = *o.MaxItems
+	}
+	if o.SlimOutput != nil {
+		cfg.SlimOutput = *o.SlimOutput
+	}
+	if o.MaskSecrets != nil {
+		cfg.MaskSecrets = *o.MaskSecrets
+	}
+	if o.SummaryThreshold != nil {
+		cfg.SummaryThreshold = *o.SummaryThreshold
+	}
+}
+
+// DefaultDebugImage is the only image debug_pod may start when no
+// DebugImages are configured.
+const DefaultDebugImage = "busybox:1.36"
+
+// DefaultCopyPaths are the container directories cp_from_pod and cp_to_pod
+// may use when no CopyPaths are configured.
+var DefaultCopyPaths = []string{"/tmp"}
+
+// DefaultCopyMaxBytes caps file copies when CopyMaxBytes is not set.
+const DefaultCopyMaxBytes = 1024 * 1024
+
+// DefaultToolTimeout bounds a tool call when ToolTimeout is not changed.
+const DefaultToolTimeout = 30 * time.Second
+
+// DefaultToolTimeouts are the timeouts of tools whose own arguments allow
+// them to run longer than DefaultToolTimeout. They apply when they exceed
+// ToolTimeout and the tool has no entry in ToolTimeouts.
+var DefaultToolTimeouts = map[string]time.Duration{
+	// Pod mode waits up to twice the largest probe timeout plus the pod
+	// startup allowance, then deletes the test pod.
+	"connectivity_test": 4 * time.Minute,
+	// debug_pod waits up to 120 seconds for the container to start.
+	"debug_pod": 3 * time.Minute,
+}
+
+// Secret write modes for Config.SecretWrites.
+const (
+	SecretWritesAllow     = "allow"
+	SecretWritesDeny      = "deny"
+	SecretWritesOperation = "operation"
+)
+
+// SecretWriteOperation is the operation that must be allowed, in
+// AllowedOperations or by a policy rule, to write Secrets when SecretWrites
+// is SecretWritesOperation.
+const SecretWriteOperation = "secret-write"
+
 // NewDefaultConfig creates a configuration with sensible defaults.
 func NewDefaultConfig() *Config {
 	return &Config{
@@ -607,10 +1041,26 @@ func NewDefaultConfig() *Config {
 		EnableAuth:           false,
 		AllowedOperations:    []string{"get", "list", "describe"},
 		RestrictedNamespaces: []string{"kube-system", "kube-public"},
+		ToolTimeout:          DefaultToolTimeout,
 		Output:               NewDefaultOutputConfig(),
 	}
 }
 
+// ToolTimeoutFor returns the timeout of a call to the named tool, or zero if
+// its calls are not bounded.
+func (c *Config) ToolTimeoutFor(toolName string) time.Duration {
+	if timeout, ok := c.ToolTimeouts[toolName]; ok {
+		return timeout
+	}
+	if c.ToolTimeout <= 0 {
+		return 0
+	}
+	if timeout := DefaultToolTimeouts[toolName]; timeout > c.ToolTimeout {
+		return timeout
+	}
+	return c.ToolTimeout
+}
+
 // NewDefaultOutputConfig creates default output processing configuration.
 func NewDefaultOutputConfig() *OutputConfig {
 	return &OutputConfig{
@@ -619,7 +1069,10 @@ func NewDefaultOutputConfig() *OutputConfig {
 		MaxResponseBytes: 512 * 1024, // 512KB
 		SlimOutput:       true,
 		MaskSecrets:      true,
+		ScrubCredentials: true,
 		SummaryThreshold: 500,
+
+		TokenWarningThreshold: 20000,
 	}
 }
 
@@ -642,9 +1095,49 @@ func (c *Config) Clone() *Config {
 		copy(clone.RestrictedNamespaces, c.RestrictedNamespaces)
 	}
 
+	if c.ReadRestrictions != nil {
+		clone.ReadRestrictions = make([]string, len(c.ReadRestrictions))
+		copy(clone.ReadRestrictions, c.ReadRestrictions)
+	}
+
+	if c.WriteNamespaces != nil {
+		clone.WriteNamespaces = make([]string, len(c.WriteNamespaces))
+		copy(clone.WriteNamespaces, c.WriteNamespaces)
+	}
+
+	if c.ReadOnlyGroups != nil {
+		clone.ReadOnlyGroups = make([]string, len(c.ReadOnlyGroups))
+		copy(clone.ReadOnlyGroups, c.ReadOnlyGroups)
+	}
+
+	if c.DebugImages != nil {
+		clone.DebugImages = make([]string, len(c.DebugImages))
+		copy(clone.DebugImages, c.DebugImages)
+	}
+
+	if c.CopyPaths != nil {
+		clone.CopyPaths = make([]string, len(c.CopyPaths))
+		copy(clone.CopyPaths, c.CopyPaths)
+	}
+
+	if c.ToolTimeouts != nil {
+		clone.ToolTimeouts = make(map[string]time.Duration, len(c.ToolTimeouts))
+		for name, timeout := range c.ToolTimeouts {
+			clone.ToolTimeouts[name] = timeout
+		}
+	}
+
 	// Deep copy output config
 	if c.Output != nil {
 		outputCopy := *c.Output
+		if c.Output.PIIPatterns != nil {
+			outputCopy.PIIPatterns = make([]string, len(c.Output.PIIPatterns))
+			copy(outputCopy.PIIPatterns, c.Output.PIIPatterns)
+		}
+		if c.Output.CredentialPatterns != nil {
+			outputCopy.CredentialPatterns = make([]string, len(c.Output.CredentialPatterns))
+			copy(outputCopy.CredentialPatterns, c.Output.CredentialPatterns)
+		}
 		clone.Output = &outputCopy
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
@@ -158,6 +159,48 @@ func TestK8sClientForContext_ValidToken_Success(t *testing.T) {
 	assert.NotSame(t, sharedClient, client)
 }
 
+// mockTokenSourceClientFactory also builds clients from token sources.
+type mockTokenSourceClientFactory struct {
+	mockClientFactory
+	sourceClient k8s.Client
+	sourceKey    string
+}
+
+func (f *mockTokenSourceClientFactory) CreateTokenSourceClient(key string, _ oauth2.TokenSource) (k8s.Client, error) {
+	f.sourceKey = key
+	return f.sourceClient, nil
+}
+
+func TestK8sClientForContext_TokenSource(t *testing.T) {
+	// When the request carries an ID token source, the client is built on it
+	// so it survives token refreshes
+	bearerClient := &mockK8sClient{}
+	sourceClient := &mockK8sClient{}
+	factory := &mockTokenSourceClientFactory{
+		mockClientFactory: mockClientFactory{client: bearerClient},
+		sourceClient:      sourceClient,
+	}
+
+	sc := &ServerContext{
+		k8sClient:             &mockK8sClient{},
+		downstreamOAuth:       true,
+		downstreamOAuthStrict: true,
+		clientFactory:         factory,
+		logger:                NewDefaultLogger(),
+	}
+
+	ctx := oauth.ContextWithIDToken(context.Background(), "valid-token")
+	client, err := sc.K8sClientForContext(ctx)
+	require.NoError(t, err)
+	assert.Same(t, bearerClient, client, "without a source the bearer token is used")
+
+	source := oauth.NewIDTokenRefresher(nil, nil).TokenSource("session-key", "user@example.com")
+	client, err = sc.K8sClientForContext(oauth.ContextWithIDTokenSource(ctx, source))
+	require.NoError(t, err)
+	assert.Same(t, sourceClient, client)
+	assert.Equal(t, "session-key", factory.sourceKey)
+}
+
 func TestDownstreamOAuthStrictEnabled(t *testing.T) {
 	// Note: In production, strict mode is always enabled.
 	// This test verifies the getter function works correctly.
@@ -187,3 +230,30 @@ func TestOAuthErrors(t *testing.T) {
 	assert.Contains(t, ErrOAuthTokenMissing.Error(), "authentication")
 	assert.Contains(t, ErrOAuthClientFailed.Error(), "authentication")
 }
+
+func TestOutputConfig_ForCluster(t *testing.T) {
+	strictItems, devItems, slim, mask := 20, 500, false, false
+	cfg := NewDefaultOutputConfig()
+	cfg.ClusterTypeOverrides = map[string]OutputOverride{
+		"production":  {MaxItems: &strictItems},
+		"development": {MaxItems: &devItems, MaskSecrets: &mask},
+	}
+	cfg.ClusterOverrides = map[string]OutputOverride{
+		"prod-wc-01": {SlimOutput: &slim},
+		"dev-wc-01":  {MaxItems: &strictItems},
+	}
+
+	assert.Same(t, cfg, cfg.ForCluster("my-cluster"), "no override applies")
+
+	prod := cfg.ForCluster("prod-wc-01")
+	assert.Equal(t, 20, prod.MaxItems, "the cluster type override applies")
+	assert.False(t, prod.SlimOutput, "the cluster override applies on top")
+	assert.True(t, prod.MaskSecrets)
+
+	dev := cfg.ForCluster("dev-wc-01")
+	assert.Equal(t, 20, dev.MaxItems, "the cluster override wins over its type")
+	assert.False(t, dev.MaskSecrets)
+
+	assert.Equal(t, 100, cfg.MaxItems, "the base configuration is not changed")
+	assert.True(t, cfg.SlimOutput)
+}
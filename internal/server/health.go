@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -12,6 +13,16 @@ const (
 	healthStatusOK           = "ok"
 	healthStatusNotReady     = "not ready"
 	healthStatusShuttingDown = "shutting down"
+	healthStatusFailed       = "failed"
+)
+
+// Defaults for component checks.
+const (
+	// DefaultHealthCheckTimeout bounds each component check.
+	DefaultHealthCheckTimeout = 5 * time.Second
+
+	// defaultCheckCacheTTL is how long a component check result is reused.
+	defaultCheckCacheTTL = 5 * time.Second
 )
 
 // HealthChecker provides health check endpoints for Kubernetes probes.
@@ -22,6 +33,14 @@ type HealthChecker struct {
 	serverContext *ServerContext
 	// startTime tracks when the server started
 	startTime time.Time
+
+	// checks are the component checks run by /readyz, keyed by name
+	checksMu      sync.Mutex
+	checks        map[string]HealthCheckFunc
+	results       map[string]componentResult
+	checkTimeout  time.Duration
+	checkCacheTTL time.Duration
+	now           func() time.Time
 }
 
 // NewHealthChecker creates a new HealthChecker.
@@ -29,12 +48,30 @@ func NewHealthChecker(sc *ServerContext) *HealthChecker {
 	h := &HealthChecker{
 		serverContext: sc,
 		startTime:     time.Now(),
+		checks:        make(map[string]HealthCheckFunc),
+		results:       make(map[string]componentResult),
+		checkTimeout:  DefaultHealthCheckTimeout,
+		checkCacheTTL: defaultCheckCacheTTL,
+		now:           time.Now,
 	}
 	// Server starts as ready by default
 	h.ready.Store(true)
+	if sc != nil && sc.FederationEnabled() {
+		h.AddCheck(HealthCheckFederation, federationCheck(sc))
+	}
 	return h
 }
 
+// AddCheck registers a component check under name. Failing checks make
+// /readyz report the server as not ready. Registering a name again replaces
+// the previous check.
+func (h *HealthChecker) AddCheck(name string, check HealthCheckFunc) {
+	h.checksMu.Lock()
+	defer h.checksMu.Unlock()
+	h.checks[name] = check
+	delete(h.results, name)
+}
+
 // SetReady sets the readiness state of the server.
 func (h *HealthChecker) SetReady(ready bool) {
 	h.ready.Store(ready)
@@ -59,6 +96,13 @@ type DetailedHealthResponse struct {
 	ManagementCluster *ManagementClusterStatus    `json:"management_cluster,omitempty"`
 	Federation        *FederationHealthStatus     `json:"federation,omitempty"`
 	Instrumentation   *InstrumentationHealthCheck `json:"instrumentation,omitempty"`
+	Components        map[string]ComponentStatus  `json:"components,omitempty"`
+}
+
+// ComponentStatus is the outcome of a component check.
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 // ManagementClusterStatus provides health information about the management cluster connection.
@@ -121,6 +165,17 @@ func (h *HealthChecker) ReadinessHandler() http.Handler {
 			checks["shutdown"] = healthStatusOK
 		}
 
+		// Component checks report only ok/failed here; error details are
+		// on /healthz/detailed
+		for name, err := range h.runChecks(r.Context()) {
+			if err != nil {
+				checks[name] = healthStatusFailed
+				allOk = false
+			} else {
+				checks[name] = healthStatusOK
+			}
+		}
+
 		// Check instrumentation provider if enabled
 		if h.serverContext != nil {
 			provider := h.serverContext.InstrumentationProvider()
@@ -175,8 +230,26 @@ func (h *HealthChecker) DetailedHealthHandler() http.Handler {
 			response.Instrumentation = h.getInstrumentationStatus()
 		}
 
+		componentsOk := true
+		if results := h.runChecks(r.Context()); len(results) > 0 {
+			response.Components = make(map[string]ComponentStatus, len(results))
+			for name, err := range results {
+				status := ComponentStatus{Status: healthStatusOK}
+				if err != nil {
+					status = ComponentStatus{Status: healthStatusFailed, Error: err.Error()}
+					componentsOk = false
+				}
+				response.Components[name] = status
+			}
+			if response.ManagementCluster != nil {
+				if err, ok := results[HealthCheckKubernetes]; ok {
+					response.ManagementCluster.Connected = err == nil
+				}
+			}
+		}
+
 		// Determine overall status
-		if !h.ready.Load() {
+		if !h.ready.Load() || !componentsOk {
 			response.Status = healthStatusNotReady
 			w.WriteHeader(http.StatusServiceUnavailable)
 		} else if h.serverContext != nil && h.serverContext.IsShutdown() {
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+)
+
+// HealthCheckFunc checks a dependency of the server. It returns nil when the
+// dependency is healthy.
+type HealthCheckFunc func(ctx context.Context) error
+
+// Component check names reported by /readyz and /healthz/detailed.
+const (
+	HealthCheckKubernetes = "kubernetes"
+	HealthCheckFederation = "federation"
+	HealthCheckValkey     = "valkey"
+)
+
+// KubernetesAPICheck returns a check that calls the API server's /readyz
+// endpoint. The endpoint is readable by every authenticated identity via the
+// system:public-info-viewer role, so the check needs no extra RBAC.
+func KubernetesAPICheck(clientset func() (kubernetes.Interface, error)) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		cs, err := clientset()
+		if err != nil {
+			return fmt.Errorf("failed to get Kubernetes client: %w", err)
+		}
+		if err := cs.Discovery().RESTClient().Get().AbsPath("/readyz").Do(ctx).Error(); err != nil {
+			return fmt.Errorf("kubernetes API not reachable: %w", err)
+		}
+		return nil
+	}
+}
+
+// ValkeyCheck pings Valkey. The connection is opened on first use and
+// retried on later checks, so an unreachable Valkey makes the server not
+// ready instead of failing startup. Call the returned close function on
+// shutdown.
+func ValkeyCheck(cfg ValkeyStorageConfig) (HealthCheckFunc, func()) {
+	var (
+		mu     sync.Mutex
+		client valkey.Client
+	)
+	connect := func() (valkey.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if client != nil {
+			return client, nil
+		}
+		c, err := NewValkeyClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		client = c
+		return client, nil
+	}
+
+	check := func(ctx context.Context) error {
+		c, err := connect()
+		if err != nil {
+			return fmt.Errorf("failed to connect to valkey: %w", err)
+		}
+		if err := c.Do(ctx, c.B().Ping().Build()).Error(); err != nil {
+			return fmt.Errorf("valkey ping failed: %w", err)
+		}
+		return nil
+	}
+	closeFn := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if client != nil {
+			client.Close()
+			client = nil
+		}
+	}
+	return check, closeFn
+}
+
+// OAuthIssuerCheck fetches the OpenID Connect discovery document of
+// issuerURL and checks that it names the same issuer. caFile, when set, is
+// trusted in addition to the system CAs, as for the Dex provider.
+func OAuthIssuerCheck(issuerURL, caFile string) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		client := &http.Client{Timeout: 30 * time.Second}
+		if caFile != "" {
+			var err error
+			client, err = createHTTPClientWithCA(caFile)
+			if err != nil {
+				return err
+			}
+		}
+		issuer := strings.TrimSuffix(issuerURL, "/")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+		if err != nil {
+			return fmt.Errorf("invalid issuer URL: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("OAuth issuer not reachable: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("OAuth issuer discovery returned HTTP %d", resp.StatusCode)
+		}
+		var discovery struct {
+			Issuer string `json:"issuer"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+			return fmt.Errorf("invalid OAuth issuer discovery document: %w", err)
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+			return fmt.Errorf("OAuth issuer discovery names issuer %q, expected %q", discovery.Issuer, issuerURL)
+		}
+		return nil
+	}
+}
+
+// CAPICRDCheck checks that the API server serves the Cluster API Cluster
+// resource that federation discovers workload clusters from.
+func CAPICRDCheck(clientset func() (kubernetes.Interface, error)) HealthCheckFunc {
+	return func(context.Context) error {
+		cs, err := clientset()
+		if err != nil {
+			return fmt.Errorf("failed to get Kubernetes client: %w", err)
+		}
+		gv := federation.CAPIClusterGVR.GroupVersion().String()
+		resources, err := cs.Discovery().ServerResourcesForGroupVersion(gv)
+		if err != nil {
+			return fmt.Errorf("cluster API %s not served: %w", gv, err)
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == federation.CAPIClusterGVR.Resource {
+				return nil
+			}
+		}
+		return fmt.Errorf("cluster API %s does not serve %s", gv, federation.CAPIClusterGVR.Resource)
+	}
+}
+
+// federationCheck fails once the federation manager has been closed.
+func federationCheck(sc *ServerContext) HealthCheckFunc {
+	return func(context.Context) error {
+		stats := sc.FederationStats()
+		if stats != nil && stats.Closed {
+			return fmt.Errorf("federation manager is closed")
+		}
+		return nil
+	}
+}
+
+// componentResult is the cached outcome of a component check.
+type componentResult struct {
+	err     error
+	checked time.Time
+}
+
+// runChecks runs the registered component checks concurrently, reusing
+// results younger than checkCacheTTL so frequent probes (or unauthenticated
+// callers) cannot flood the Kubernetes API or Valkey.
+func (h *HealthChecker) runChecks(ctx context.Context) map[string]error {
+	h.checksMu.Lock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	h.checksMu.Unlock()
+
+	results := make(map[string]error, len(names))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err := h.runCheck(ctx, name)
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return results
+}
+
+// runCheck runs a single check, or returns its cached result.
+func (h *HealthChecker) runCheck(ctx context.Context, name string) error {
+	h.checksMu.Lock()
+	check := h.checks[name]
+	cached, ok := h.results[name]
+	h.checksMu.Unlock()
+
+	now := h.now()
+	if ok && now.Sub(cached.checked) < h.checkCacheTTL {
+		return cached.err
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+	err := check(checkCtx)
+
+	h.checksMu.Lock()
+	h.results[name] = componentResult{err: err, checked: now}
+	h.checksMu.Unlock()
+	return err
+}
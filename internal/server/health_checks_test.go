@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+)
+
+func readyz(t *testing.T, h *HealthChecker) (int, HealthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var response HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response
+}
+
+func TestReadinessHandler_ComponentChecks(t *testing.T) {
+	h := NewHealthChecker(&ServerContext{config: NewDefaultConfig()})
+	h.AddCheck("good", func(context.Context) error { return nil })
+	h.AddCheck("bad", func(context.Context) error { return errors.New("connection refused") })
+
+	code, response := readyz(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not ready", response.Status)
+	assert.Equal(t, "ok", response.Checks["good"])
+	assert.Equal(t, "failed", response.Checks["bad"])
+}
+
+func TestReadinessHandler_CachesCheckResults(t *testing.T) {
+	h := NewHealthChecker(&ServerContext{config: NewDefaultConfig()})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	calls := 0
+	h.AddCheck("counted", func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	readyz(t, h)
+	readyz(t, h)
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(defaultCheckCacheTTL)
+	readyz(t, h)
+	assert.Equal(t, 2, calls)
+}
+
+func TestReadinessHandler_CheckTimeout(t *testing.T) {
+	h := NewHealthChecker(&ServerContext{config: NewDefaultConfig()})
+	h.checkTimeout = 10 * time.Millisecond
+	h.AddCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	code, response := readyz(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "failed", response.Checks["slow"])
+}
+
+func TestDetailedHealthHandler_Components(t *testing.T) {
+	sc := &ServerContext{
+		config:            NewDefaultConfig(),
+		federationManager: &mockFederationManager{},
+	}
+	h := NewHealthChecker(sc)
+	h.AddCheck(HealthCheckKubernetes, func(context.Context) error { return errors.New("dial tcp: i/o timeout") })
+
+	rec := httptest.NewRecorder()
+	h.DetailedHealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/detailed", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var response DetailedHealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "not ready", response.Status)
+	assert.Equal(t, ComponentStatus{Status: "ok"}, response.Components[HealthCheckFederation])
+	assert.Equal(t, ComponentStatus{Status: "failed", Error: "dial tcp: i/o timeout"}, response.Components[HealthCheckKubernetes])
+	require.NotNil(t, response.ManagementCluster)
+	assert.False(t, response.ManagementCluster.Connected)
+}
+
+func TestFederationCheck(t *testing.T) {
+	manager := &mockFederationManager{}
+	sc := &ServerContext{config: NewDefaultConfig(), federationManager: manager}
+
+	assert.NoError(t, federationCheck(sc)(context.Background()))
+
+	manager.stats = federation.ManagerStats{Closed: true}
+	assert.ErrorContains(t, federationCheck(sc)(context.Background()), "closed")
+}
+
+func TestKubernetesAPICheck(t *testing.T) {
+	status := http.StatusOK
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/readyz", r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer apiServer.Close()
+
+	clientset := func() (kubernetes.Interface, error) {
+		return kubernetes.NewForConfig(&rest.Config{Host: apiServer.URL})
+	}
+	check := KubernetesAPICheck(clientset)
+
+	assert.NoError(t, check(context.Background()))
+
+	status = http.StatusInternalServerError
+	assert.ErrorContains(t, check(context.Background()), "kubernetes API not reachable")
+
+	failing := KubernetesAPICheck(func() (kubernetes.Interface, error) { return nil, errors.New("no config") })
+	assert.ErrorContains(t, failing(context.Background()), "failed to get Kubernetes client")
+}
+
+func TestValkeyCheck_Unreachable(t *testing.T) {
+	check, closeFn := ValkeyCheck(ValkeyStorageConfig{URL: "127.0.0.1:1"})
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.ErrorContains(t, check(ctx), "valkey")
+}
+
+func TestOAuthIssuerCheck(t *testing.T) {
+	issuer := ""
+	status := http.StatusOK
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/dex/.well-known/openid-configuration", r.URL.Path)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer})
+	}))
+	defer idp.Close()
+
+	issuer = idp.URL + "/dex"
+	check := OAuthIssuerCheck(idp.URL+"/dex/", "")
+	assert.NoError(t, check(context.Background()))
+
+	issuer = "https://other.example.com"
+	assert.ErrorContains(t, check(context.Background()), "expected")
+
+	status = http.StatusNotFound
+	assert.ErrorContains(t, check(context.Background()), "HTTP 404")
+
+	missingCA := OAuthIssuerCheck(idp.URL+"/dex", "/nonexistent/ca.pem")
+	assert.ErrorContains(t, missingCA(context.Background()), "failed to read CA file")
+}
+
+func TestCAPICRDCheck(t *testing.T) {
+	served := true
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !served {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "/apis/"+federation.CAPIClusterGVR.GroupVersion().String(), r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"kind":         "APIResourceList",
+			"apiVersion":   "v1",
+			"groupVersion": federation.CAPIClusterGVR.GroupVersion().String(),
+			"resources":    []map[string]any{{"name": "clusters", "namespaced": true, "kind": "Cluster", "verbs": []string{"get", "list"}}},
+		})
+	}))
+	defer apiServer.Close()
+
+	check := CAPICRDCheck(func() (kubernetes.Interface, error) {
+		return kubernetes.NewForConfig(&rest.Config{Host: apiServer.URL})
+	})
+	assert.NoError(t, check(context.Background()))
+
+	served = false
+	assert.ErrorContains(t, check(context.Background()), "not served")
+}
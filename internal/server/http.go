@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+)
+
+// HTTPStackConfig configures the middleware every HTTP transport puts in
+// front of its routes, with or without OAuth, so that the transports share
+// one security posture.
+type HTTPStackConfig struct {
+	// EnableHSTS sends the HSTS header on plain HTTP too (for reverse proxy
+	// scenarios)
+	EnableHSTS bool
+
+	// AllowedOrigins are the CORS origins, as validated by
+	// middleware.ValidateAllowedOrigins
+	AllowedOrigins []string
+
+	// Compression compresses responses with gzip or deflate when the client
+	// accepts it
+	Compression bool
+
+	// CompressionMinSize is the smallest response body, in bytes, that is
+	// compressed
+	CompressionMinSize int
+
+	// InstrumentationProvider records HTTP request metrics; nil disables them
+	InstrumentationProvider *instrumentation.Provider
+}
+
+// WrapHTTPHandler puts the shared middleware stack in front of h.
+// Order: Metrics (outermost) -> Compression -> Security Headers -> CORS -> Handler
+// Metrics middleware wraps everything to capture all request metrics.
+func WrapHTTPHandler(h http.Handler, config HTTPStackConfig) http.Handler {
+	handler := middleware.SecurityHeaders(config.EnableHSTS)(
+		middleware.CORS(config.AllowedOrigins)(h),
+	)
+	if config.Compression {
+		handler = middleware.Compress(config.CompressionMinSize)(handler)
+	}
+	return middleware.HTTPMetrics(config.InstrumentationProvider)(handler)
+}
+
+// NewHTTPServer creates the HTTP server of a transport with the default
+// security timeouts.
+func NewHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		WriteTimeout:      DefaultWriteTimeout,
+		IdleTimeout:       DefaultIdleTimeout,
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapHTTPHandler(t *testing.T) {
+	called := false
+	h := WrapHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), HTTPStackConfig{EnableHSTS: true, AllowedOrigins: []string{"https://app.example.com"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.True(t, called)
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.NotEmpty(t, rec.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Preflight requests are answered by the CORS middleware.
+	called = false
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/mcp", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	srv := NewHTTPServer(":8080", http.NotFoundHandler())
+	assert.Equal(t, ":8080", srv.Addr)
+	assert.Equal(t, DefaultReadHeaderTimeout, srv.ReadHeaderTimeout)
+	assert.Equal(t, DefaultWriteTimeout, srv.WriteTimeout)
+	assert.Equal(t, DefaultIdleTimeout, srv.IdleTimeout)
+}
@@ -19,3 +19,25 @@ func ImpersonationIdentityFromContext(ctx context.Context) (k8s.ImpersonationIde
 	v, ok := ctx.Value(impersonationContextKey{}).(k8s.ImpersonationIdentity)
 	return v, ok
 }
+
+// ImpersonationExtrasConfig selects details of each tool call that are added
+// as impersonation extras to the Kubernetes API requests it makes, so that
+// they appear in the audit logs of the clusters. Operator-configured static
+// extras are set on the clients instead; see k8s.ImpersonationExtras.
+type ImpersonationExtrasConfig struct {
+	// ClientInfo adds the name and version the MCP client reported when it
+	// initialized the session.
+	ClientInfo bool
+
+	// SessionID adds the MCP session ID.
+	SessionID bool
+
+	// RequestMeta maps fields of the tool call's _meta object to extra keys,
+	// e.g. {"ticketId": "ticket-id"}. Only string fields are used.
+	RequestMeta map[string]string
+}
+
+// Enabled reports whether any per-request extra is configured.
+func (c *ImpersonationExtrasConfig) Enabled() bool {
+	return c != nil && (c.ClientInfo || c.SessionID || len(c.RequestMeta) > 0)
+}
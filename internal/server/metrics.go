@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
@@ -74,10 +75,15 @@ func NewMetricsServer(config MetricsServerConfig) (*MetricsServer, error) {
 func (s *MetricsServer) Start() error {
 	mux := http.NewServeMux()
 
-	// Register /metrics endpoint using promhttp.Handler()
+	// Register /metrics endpoint
 	// The OpenTelemetry prometheus exporter registers metrics to the global
-	// Prometheus registry, which promhttp.Handler() exposes.
-	mux.Handle("/metrics", promhttp.Handler())
+	// Prometheus registry, which is exposed here. OpenMetrics is offered so
+	// that scrapers asking for it get the trace-ID exemplars of the latency
+	// histograms.
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	))
 
 	// Add a basic health check for the metrics server itself
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
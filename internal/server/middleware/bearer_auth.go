@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/mcp-oauth/security"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Authentication modes for the HTTP transports when OAuth is not enabled.
+const (
+	// AuthModeNone accepts every request. Only safe on trusted networks.
+	AuthModeNone = "none"
+
+	// AuthModeStaticToken accepts requests carrying one of a set of
+	// pre-shared bearer tokens.
+	AuthModeStaticToken = "static-token"
+
+	// AuthModeTokenReview validates presented ServiceAccount (or any other
+	// Kubernetes) tokens with the TokenReview API.
+	AuthModeTokenReview = "tokenreview"
+)
+
+const (
+	// DefaultTokenReviewCacheTTL is how long a successful TokenReview is reused.
+	DefaultTokenReviewCacheTTL = time.Minute
+
+	// tokenReviewCacheMaxEntries bounds the TokenReview cache.
+	tokenReviewCacheMaxEntries = 1000
+)
+
+// ErrInvalidToken is returned when a bearer token is not accepted.
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// TokenAuthenticator validates a bearer token.
+type TokenAuthenticator interface {
+	// Authenticate returns the name of the authenticated subject, or an
+	// error wrapping ErrInvalidToken when the token is rejected.
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// subjectKey is the context key for the authenticated subject.
+type subjectKey struct{}
+
+// ContextWithAuthenticatedSubject returns a copy of ctx carrying the subject
+// authenticated by BearerAuth.
+func ContextWithAuthenticatedSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// AuthenticatedSubjectFromContext returns the subject authenticated by
+// BearerAuth, or an empty string if none is set.
+func AuthenticatedSubjectFromContext(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
+
+// BearerAuth rejects requests without a bearer token accepted by auth and
+// stores the authenticated subject in the request context. It protects the
+// MCP endpoints of the streamable-http and SSE transports when OAuth is not
+// enabled.
+func BearerAuth(auth TokenAuthenticator, logger *slog.Logger) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := extractBearerToken(r)
+			if token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "bearer token required", http.StatusUnauthorized)
+				return
+			}
+
+			subject, err := auth.Authenticate(r.Context(), token)
+			if err != nil {
+				logger.WarnContext(r.Context(), "rejecting request: bearer token authentication failed",
+					slog.String("client_ip", security.GetClientIP(r, false, 0)),
+					slog.Any("error", err))
+
+				if !errors.Is(err, ErrInvalidToken) {
+					http.Error(w, "authentication unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithAuthenticatedSubject(r.Context(), subject)))
+		})
+	}
+}
+
+// StaticTokenAuthenticator accepts a fixed set of pre-shared tokens. Only
+// SHA-256 digests are kept in memory and comparisons are constant time.
+type StaticTokenAuthenticator struct {
+	digests [][sha256.Size]byte
+}
+
+// NewStaticTokenAuthenticator creates an authenticator for tokens. Empty
+// tokens are rejected.
+func NewStaticTokenAuthenticator(tokens []string) (*StaticTokenAuthenticator, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("at least one static token is required")
+	}
+	a := &StaticTokenAuthenticator{}
+	for i, t := range tokens {
+		if strings.TrimSpace(t) == "" {
+			return nil, fmt.Errorf("static token %d is empty", i+1)
+		}
+		a.digests = append(a.digests, sha256.Sum256([]byte(t)))
+	}
+	return a, nil
+}
+
+// LoadStaticTokens reads tokens from a file, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadStaticTokens(path string) ([]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	var tokens []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("token file %s contains no tokens", path)
+	}
+	return tokens, nil
+}
+
+// Authenticate checks token against every configured token. The subject is
+// "static-token-<n>" for the n-th token, so audit logs can tell clients apart
+// without recording the token.
+func (a *StaticTokenAuthenticator) Authenticate(_ context.Context, token string) (string, error) {
+	digest := sha256.Sum256([]byte(token))
+	match := 0
+	for i := range a.digests {
+		if subtle.ConstantTimeCompare(digest[:], a.digests[i][:]) == 1 {
+			match = i + 1
+		}
+	}
+	if match == 0 {
+		return "", ErrInvalidToken
+	}
+	return fmt.Sprintf("static-token-%d", match), nil
+}
+
+// tokenReviewEntry is a cached successful TokenReview.
+type tokenReviewEntry struct {
+	subject string
+	expires time.Time
+}
+
+// TokenReviewAuthenticator validates tokens with the Kubernetes TokenReview
+// API. Successful reviews are cached for the configured TTL.
+type TokenReviewAuthenticator struct {
+	client    kubernetes.Interface
+	audiences []string
+	cacheTTL  time.Duration
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]tokenReviewEntry
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// NewTokenReviewAuthenticator creates an authenticator using client. When
+// audiences is non-empty the token must be valid for at least one of them.
+// A cacheTTL of zero uses DefaultTokenReviewCacheTTL.
+func NewTokenReviewAuthenticator(client kubernetes.Interface, audiences []string, cacheTTL time.Duration) *TokenReviewAuthenticator {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultTokenReviewCacheTTL
+	}
+	return &TokenReviewAuthenticator{
+		client:    client,
+		audiences: audiences,
+		cacheTTL:  cacheTTL,
+		cache:     make(map[[sha256.Size]byte]tokenReviewEntry),
+		now:       time.Now,
+	}
+}
+
+// Authenticate reviews token and returns the Kubernetes username.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	key := sha256.Sum256([]byte(token))
+	now := a.now()
+
+	a.mu.Lock()
+	if entry, ok := a.cache[key]; ok && now.Before(entry.expires) {
+		a.mu.Unlock()
+		return entry.subject, nil
+	}
+	a.mu.Unlock()
+
+	review, err := a.client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: a.audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("token review failed: %w", err)
+	}
+
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return "", fmt.Errorf("%w: %s", ErrInvalidToken, review.Status.Error)
+		}
+		return "", ErrInvalidToken
+	}
+	// The API server returns the audiences the token is valid for; an empty
+	// list means it could not confirm any of the requested ones.
+	if len(a.audiences) > 0 && len(review.Status.Audiences) == 0 {
+		return "", fmt.Errorf("%w: token is not valid for the required audiences", ErrInvalidToken)
+	}
+
+	subject := review.Status.User.Username
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.cache) >= tokenReviewCacheMaxEntries {
+		for k, entry := range a.cache {
+			if !now.Before(entry.expires) {
+				delete(a.cache, k)
+			}
+		}
+		if len(a.cache) >= tokenReviewCacheMaxEntries {
+			a.cache = make(map[[sha256.Size]byte]tokenReviewEntry)
+		}
+	}
+	a.cache[key] = tokenReviewEntry{subject: subject, expires: now.Add(a.cacheTTL)}
+
+	return subject, nil
+}
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestStaticTokenAuthenticator(t *testing.T) {
+	auth, err := NewStaticTokenAuthenticator([]string{"first-token", "second-token"})
+	require.NoError(t, err)
+
+	subject, err := auth.Authenticate(context.Background(), "second-token")
+	require.NoError(t, err)
+	assert.Equal(t, "static-token-2", subject)
+
+	_, err = auth.Authenticate(context.Background(), "wrong-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = auth.Authenticate(context.Background(), "")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewStaticTokenAuthenticator_Invalid(t *testing.T) {
+	_, err := NewStaticTokenAuthenticator(nil)
+	assert.Error(t, err)
+
+	_, err = NewStaticTokenAuthenticator([]string{"ok", "  "})
+	assert.Error(t, err)
+}
+
+func TestLoadStaticTokens(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "tokens")
+	require.NoError(t, os.WriteFile(path, []byte("# CI runner\ntoken-a\n\n  token-b  \n"), 0o600))
+	tokens, err := LoadStaticTokens(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"token-a", "token-b"}, tokens)
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("# nothing here\n"), 0o600))
+	_, err = LoadStaticTokens(empty)
+	assert.Error(t, err)
+
+	_, err = LoadStaticTokens(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+// newTokenReviewClient returns a fake clientset that authenticates "good"
+// tokens and counts the TokenReviews it receives.
+func newTokenReviewClient(calls *int, audiences []string) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		*calls++
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "good" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:ops:agent"},
+				Audiences:     audiences,
+			}
+		} else {
+			review.Status = authenticationv1.TokenReviewStatus{Error: "token expired"}
+		}
+		return true, review, nil
+	})
+	return client
+}
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	calls := 0
+	auth := NewTokenReviewAuthenticator(newTokenReviewClient(&calls, nil), nil, time.Minute)
+
+	subject, err := auth.Authenticate(context.Background(), "good")
+	require.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:ops:agent", subject)
+
+	_, err = auth.Authenticate(context.Background(), "bad")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Contains(t, err.Error(), "token expired")
+}
+
+func TestTokenReviewAuthenticator_Caches(t *testing.T) {
+	calls := 0
+	auth := NewTokenReviewAuthenticator(newTokenReviewClient(&calls, nil), nil, time.Minute)
+	now := time.Now()
+	auth.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, err := auth.Authenticate(context.Background(), "good")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls, "successful reviews are cached")
+
+	now = now.Add(2 * time.Minute)
+	_, err := auth.Authenticate(context.Background(), "good")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "expired entries are reviewed again")
+
+	_, _ = auth.Authenticate(context.Background(), "bad")
+	_, _ = auth.Authenticate(context.Background(), "bad")
+	assert.Equal(t, 4, calls, "failed reviews are not cached")
+}
+
+func TestTokenReviewAuthenticator_Audiences(t *testing.T) {
+	calls := 0
+	auth := NewTokenReviewAuthenticator(newTokenReviewClient(&calls, nil), []string{"mcp-kubernetes"}, 0)
+	_, err := auth.Authenticate(context.Background(), "good")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	auth = NewTokenReviewAuthenticator(newTokenReviewClient(&calls, []string{"mcp-kubernetes"}), []string{"mcp-kubernetes"}, 0)
+	_, err = auth.Authenticate(context.Background(), "good")
+	assert.NoError(t, err)
+}
+
+// authenticatorFunc adapts a function to TokenAuthenticator.
+type authenticatorFunc func(ctx context.Context, token string) (string, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, token string) (string, error) {
+	return f(ctx, token)
+}
+
+func TestBearerAuth(t *testing.T) {
+	auth := authenticatorFunc(func(_ context.Context, token string) (string, error) {
+		switch token {
+		case "good":
+			return "ci-runner", nil
+		case "unavailable":
+			return "", errors.New("connection refused")
+		default:
+			return "", ErrInvalidToken
+		}
+	})
+
+	var subject string
+	handler := BearerAuth(auth, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = AuthenticatedSubjectFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name          string
+		header        string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "valid token", header: "Bearer good", wantStatus: http.StatusOK},
+		{name: "missing token", wantStatus: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "wrong scheme", header: "Basic Zm9vOmJhcg==", wantStatus: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "invalid token", header: "Bearer nope", wantStatus: http.StatusUnauthorized, wantChallenge: `Bearer error="invalid_token"`},
+		{name: "authenticator unavailable", header: "Bearer unavailable", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject = ""
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantChallenge, rec.Header().Get("WWW-Authenticate"))
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "ci-runner", subject)
+			} else {
+				assert.Empty(t, subject, "rejected requests must not reach the handler")
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/giantswarm/mcp-oauth/security"
+)
+
+// clientIPKey is the context key for the client IP address.
+type clientIPKey struct{}
+
+// ClientIP stores the request's client IP address in the request context so
+// tool handlers can identify anonymous callers (e.g. for rate limiting).
+// Only the connection's remote address is used; forwarding headers are
+// ignored because they can be set by the client.
+func ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := security.GetClientIP(r, false, 0)
+		next.ServeHTTP(w, r.WithContext(ContextWithClientIP(r.Context(), ip)))
+	})
+}
+
+// ContextWithClientIP returns a copy of ctx carrying the client IP address.
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext returns the client IP address stored by ClientIP, or
+// an empty string if none is set.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	var got string
+	handler := ClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientIPFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.RemoteAddr = "192.0.2.10:54321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "192.0.2.10", got, "forwarding headers must be ignored")
+}
+
+func TestClientIPFromContext_Empty(t *testing.T) {
+	assert.Empty(t, ClientIPFromContext(context.Background()))
+}
@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressionMinSize is the smallest response body, in bytes, that
+// Compress compresses by default. Smaller bodies rarely get smaller.
+const DefaultCompressionMinSize = 1024
+
+// Supported content codings, in order of preference.
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// compressor is the common interface of gzip.Writer and zlib.Writer.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var (
+	gzipPool = sync.Pool{New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}}
+	// The deflate content coding is the zlib format (RFC 9110).
+	deflatePool = sync.Pool{New: func() any {
+		return zlib.NewWriter(io.Discard)
+	}}
+)
+
+// Compress compresses response bodies of at least minSize bytes with gzip
+// or deflate, whichever the client prefers in Accept-Encoding. List results
+// are large, repetitive JSON and typically shrink tenfold.
+//
+// A body is buffered until it reaches minSize, so small responses are sent
+// as they are. Event streams (SSE) are compressed from the first flush on,
+// since their size is not known up front; each flush also flushes the
+// compressor so events reach the client without delay. Responses that
+// already carry a Content-Encoding are left alone.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	if minSize < 0 {
+		minSize = 0
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the content coding for an Accept-Encoding header:
+// the supported coding with the highest quality, preferring gzip on ties.
+// It returns "" if the client accepts neither.
+func negotiateEncoding(acceptEncoding string) string {
+	quality := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch name {
+		case encodingGzip, encodingDeflate:
+			quality[name] = q
+		case "*":
+			for _, enc := range []string{encodingGzip, encodingDeflate} {
+				if _, set := quality[enc]; !set {
+					quality[enc] = q
+				}
+			}
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range []string{encodingGzip, encodingDeflate} {
+		if q := quality[enc]; q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a response until it knows whether to
+// compress it.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     compressor
+}
+
+// WriteHeader holds the status back until the body decides on compression.
+// Statuses without a body are written at once.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if cw.status != 0 {
+		return
+	}
+	cw.status = code
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		_ = cw.start(false)
+	}
+}
+
+// Write buffers p until the body reaches the minimum size.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		if cw.Header().Get("Content-Encoding") != "" {
+			_ = cw.start(false)
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) >= cw.minSize {
+				if err := cw.start(true); err != nil {
+					return 0, err
+				}
+			}
+			return len(p), nil
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends what has been written so far. Event streams are compressed
+// from here on; other responses flushed before reaching the minimum size
+// are sent uncompressed.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		_ = cw.start(isEventStream(cw.Header().Get("Content-Type")) && cw.Header().Get("Content-Encoding") == "")
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// start writes the held-back status and body, compressed or not.
+func (cw *compressWriter) start(compress bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if compress {
+		// net/http would sniff the type from the compressed bytes.
+		if h.Get("Content-Type") == "" {
+			h.Set("Content-Type", http.DetectContentType(cw.buf))
+		}
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+		if cw.encoding == encodingGzip {
+			cw.enc = gzipPool.Get().(*gzip.Writer)
+		} else {
+			cw.enc = deflatePool.Get().(*zlib.Writer)
+		}
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close sends a body that stayed below the minimum size, or finishes the
+// compressed stream.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		_ = cw.start(false)
+		return
+	}
+	if cw.enc == nil {
+		return
+	}
+	_ = cw.enc.Close()
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		enc.Reset(io.Discard)
+		gzipPool.Put(enc)
+	case *zlib.Writer:
+		enc.Reset(io.Discard)
+		deflatePool.Put(enc)
+	}
+	cw.enc = nil
+}
+
+// isEventStream reports whether contentType is text/event-stream.
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
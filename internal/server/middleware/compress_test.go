@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"kind":"Pod","metadata":{"name":"web"}},`, 100)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		body           string
+		wantEncoding   string
+	}{
+		{name: "gzip", acceptEncoding: "gzip, deflate", body: large, wantEncoding: "gzip"},
+		{name: "deflate", acceptEncoding: "deflate", body: large, wantEncoding: "deflate"},
+		{name: "preferred by quality", acceptEncoding: "gzip;q=0.5, deflate", body: large, wantEncoding: "deflate"},
+		{name: "small body", acceptEncoding: "gzip", body: `{"ok":true}`, wantEncoding: ""},
+		{name: "not accepted", acceptEncoding: "br", body: large, wantEncoding: ""},
+		{name: "no header", body: large, wantEncoding: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Compress(DefaultCompressionMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", "1")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			assert.Equal(t, tt.wantEncoding, rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.body, decodeBody(t, tt.wantEncoding, rec.Body))
+			if tt.wantEncoding != "" {
+				assert.Empty(t, rec.Header().Get("Content-Length"))
+				assert.Less(t, rec.Body.Len(), len(tt.body)/5)
+			}
+		})
+	}
+}
+
+func TestCompress_EventStream(t *testing.T) {
+	handler := Compress(DefaultCompressionMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: endpoint\ndata: /message\n\n")
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, "event: message\ndata: {}\n\n")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "event: endpoint\ndata: /message\n\nevent: message\ndata: {}\n\n", decodeBody(t, "gzip", rec.Body))
+}
+
+func TestCompress_LeavesResponsesAlone(t *testing.T) {
+	t.Run("flushed before the minimum size", func(t *testing.T) {
+		handler := Compress(DefaultCompressionMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"partial":`)
+			w.(http.Flusher).Flush()
+			_, _ = io.WriteString(w, strings.Repeat("x", 2*DefaultCompressionMinSize))
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, `{"partial":`+strings.Repeat("x", 2*DefaultCompressionMinSize), rec.Body.String())
+	})
+
+	t.Run("already encoded", func(t *testing.T) {
+		body := strings.Repeat("x", 2*DefaultCompressionMinSize)
+		handler := Compress(DefaultCompressionMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = io.WriteString(w, body)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		handler := Compress(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Zero(t, rec.Body.Len())
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                      "",
+		"gzip":                  "gzip",
+		"GZIP, deflate":         "gzip",
+		"deflate, gzip;q=0.9":   "deflate",
+		"gzip;q=0, deflate":     "deflate",
+		"gzip;q=0, deflate;q=0": "",
+		"*":                     "gzip",
+		"gzip;q=0, *":           "deflate",
+		"identity":              "",
+		"gzip;q=abc, deflate":   "deflate",
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, negotiateEncoding(header), header)
+	}
+}
+
+func decodeBody(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(body)
+		require.NoError(t, err)
+		r = gz
+	case "deflate":
+		zr, err := zlib.NewReader(body)
+		require.NoError(t, err)
+		r = zr
+	default:
+		r = body
+	}
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
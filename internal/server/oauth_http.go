@@ -23,9 +23,11 @@ import (
 	"github.com/giantswarm/mcp-oauth/storage/memory"
 	"github.com/giantswarm/mcp-oauth/storage/valkey"
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"golang.org/x/oauth2"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/keyrotation"
 	"github.com/giantswarm/mcp-kubernetes/internal/logging"
 	mcpoauth "github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
 	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
@@ -230,6 +232,11 @@ type OAuthConfig struct {
 	// DisableStreaming disables streaming for streamable-http transport
 	DisableStreaming bool
 
+	// SSEEndpoint and MessageEndpoint are the endpoints of the sse
+	// transport; they default to /sse and /message
+	SSEEndpoint     string
+	MessageEndpoint string
+
 	// DebugMode enables debug logging
 	DebugMode bool
 
@@ -237,6 +244,11 @@ type OAuthConfig struct {
 	// If empty, tokens are stored unencrypted in memory
 	EncryptionKey []byte
 
+	// PreviousEncryptionKey is the encryption key being rotated away from.
+	// With Valkey storage, tokens the current key cannot decrypt are read
+	// with it and re-encrypted with EncryptionKey.
+	PreviousEncryptionKey []byte
+
 	// RegistrationAccessToken is the token required for client registration
 	// Required if AllowPublicClientRegistration is false
 	RegistrationAccessToken string
@@ -255,6 +267,14 @@ type OAuthConfig struct {
 	// EnableHSTS enables HSTS header (for reverse proxy scenarios)
 	EnableHSTS bool
 
+	// Compression compresses responses with gzip or deflate when the client
+	// accepts it
+	Compression bool
+
+	// CompressionMinSize is the smallest response body, in bytes, that is
+	// compressed
+	CompressionMinSize int
+
 	// AllowedOrigins is a comma-separated list of allowed CORS origins
 	AllowedOrigins string
 
@@ -321,6 +341,15 @@ type OAuthConfig struct {
 	// Default: false (blocked for security)
 	SSOAllowPrivateIPs bool
 
+	// RefreshIDTokens refreshes a user's provider ID token with their refresh
+	// token shortly before it expires, so downstream Kubernetes clients built
+	// from it do not fail mid-session. Enabled with downstream OAuth.
+	RefreshIDTokens bool
+
+	// OnTokenRefresh, if set, is called with the user's email whenever their
+	// provider token is refreshed, to drop clients built from the old token.
+	OnTokenRefresh func(ctx context.Context, email string)
+
 	// TrustedIssuers lists external JWT issuers whose tokens are accepted at /mcp.
 	// Each entry's JWKS is used to verify the Bearer JWT's signature when its `iss`
 	// matches. AllowedAudiences restricts the accepted `aud` values; when empty,
@@ -397,17 +426,29 @@ type OAuthHTTPServer struct {
 	oauthHandler            *handler.Handler
 	tokenStore              storage.TokenStore
 	httpServer              *http.Server
-	serverType              string // "streamable-http"
+	serverType              string // "streamable-http" or "sse"
 	disableStreaming        bool
+	sseEndpoint             string
+	messageEndpoint         string
 	instrumentationProvider *instrumentation.Provider
 	healthChecker           *HealthChecker
 	// trustedIssuersByIssuer maps issuer URL to its configured entry (one per
 	// issuer URL, enforced by startup validation).
 	trustedIssuersByIssuer map[string]TrustedIssuerConfig
+	// idTokenRefresher refreshes provider ID tokens before they expire; nil
+	// when RefreshIDTokens is off.
+	idTokenRefresher *mcpoauth.IDTokenRefresher
 }
 
 // createOAuthServer creates an OAuth server using mcp-oauth library directly
 func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, error) {
+	server, tokenStore, _, err := buildOAuthServer(config)
+	return server, tokenStore, err
+}
+
+// buildOAuthServer creates the OAuth server and also returns its upstream
+// provider, which refreshes ID tokens for downstream authentication.
+func buildOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, providers.Provider, error) {
 	// Create logger with appropriate level
 	var logger *slog.Logger
 	if config.DebugMode {
@@ -452,7 +493,7 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		if config.DexCAFile != "" {
 			httpClient, err := createHTTPClientWithCA(config.DexCAFile)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to create HTTP client with CA: %w", err)
+				return nil, nil, nil, fmt.Errorf("failed to create HTTP client with CA: %w", err)
 			}
 			dexConfig.HTTPClient = httpClient
 			logger.Info("Using custom CA for Dex TLS verification", "caFile", config.DexCAFile)
@@ -465,13 +506,13 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 			// without this, forwarded ID tokens from an internal-CA Dex fail with
 			// "x509: certificate signed by unknown authority".
 			if err := installDexCAOnDefaultTransport(config.DexCAFile); err != nil {
-				return nil, nil, fmt.Errorf("failed to install Dex CA on default transport: %w", err)
+				return nil, nil, nil, fmt.Errorf("failed to install Dex CA on default transport: %w", err)
 			}
 			logger.Info("Installed custom Dex CA on http.DefaultTransport for SSO JWKS validation", "caFile", config.DexCAFile)
 		}
 		provider, err = dex.NewProvider(dexConfig)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create Dex provider: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to create Dex provider: %w", err)
 		}
 		logger.Info("Using Dex OIDC provider", "issuer", config.DexIssuerURL)
 
@@ -483,12 +524,12 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 			Scopes:       googleOAuthScopes,
 		})
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create Google provider: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to create Google provider: %w", err)
 		}
 		logger.Info("Using Google OAuth provider")
 
 	default:
-		return nil, nil, fmt.Errorf("unsupported OAuth provider: %s (supported: %s, %s)", config.Provider, OAuthProviderDex, OAuthProviderGoogle)
+		return nil, nil, nil, fmt.Errorf("unsupported OAuth provider: %s (supported: %s, %s)", config.Provider, OAuthProviderDex, OAuthProviderGoogle)
 	}
 
 	// Create storage backend based on configuration
@@ -500,7 +541,7 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 	switch config.Storage.Type {
 	case OAuthStorageTypeValkey:
 		if config.Storage.Valkey.URL == "" {
-			return nil, nil, fmt.Errorf("valkey URL is required when using valkey storage (--valkey-url or VALKEY_URL)")
+			return nil, nil, nil, fmt.Errorf("valkey URL is required when using valkey storage (--valkey-url or VALKEY_URL)")
 		}
 
 		// Configure Valkey storage
@@ -528,14 +569,14 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		if len(config.EncryptionKey) > 0 {
 			encryptor, err := security.NewEncryptor(config.EncryptionKey)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to create encryptor for Valkey storage: %w", err)
+				return nil, nil, nil, fmt.Errorf("failed to create encryptor for Valkey storage: %w", err)
 			}
 			valkeyOpts = append(valkeyOpts, valkey.WithEncryptor(encryptor))
 		}
 
 		valkeyStore, err := valkey.New(valkeyConfig, valkeyOpts...)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create Valkey storage: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to create Valkey storage: %w", err)
 		}
 		if len(config.EncryptionKey) > 0 {
 			logger.Info("Token encryption at rest enabled for Valkey storage (AES-256-GCM)")
@@ -543,6 +584,18 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 
 		// Valkey store implements all required interfaces
 		tokenStore = valkeyStore
+		if len(config.EncryptionKey) > 0 && len(config.PreviousEncryptionKey) > 0 {
+			previousEncryptor, err := security.NewEncryptor(config.PreviousEncryptionKey)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to create encryptor for the previous encryption key: %w", err)
+			}
+			previousStore, err := valkey.New(valkeyConfig, valkey.WithEncryptor(previousEncryptor))
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to create Valkey storage for the previous encryption key: %w", err)
+			}
+			tokenStore = keyrotation.NewFallbackStore(valkeyStore, previousStore, logger)
+			logger.Info("Reading tokens encrypted with the previous encryption key during key rotation")
+		}
 		clientStore = valkeyStore
 		flowStore = valkeyStore
 		logger.Info("Using Valkey storage backend", "address", config.Storage.Valkey.URL, "tls", config.Storage.Valkey.TLSEnabled)
@@ -552,7 +605,7 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		if len(config.EncryptionKey) > 0 {
 			encryptor, err := security.NewEncryptor(config.EncryptionKey)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to create encryptor: %w", err)
+				return nil, nil, nil, fmt.Errorf("failed to create encryptor: %w", err)
 			}
 			memOpts = append(memOpts, memory.WithEncryptor(encryptor))
 		}
@@ -567,7 +620,7 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		}
 
 	default:
-		return nil, nil, fmt.Errorf("unsupported OAuth storage type: %s (supported: memory, valkey)", config.Storage.Type)
+		return nil, nil, nil, fmt.Errorf("unsupported OAuth storage type: %s (supported: memory, valkey)", config.Storage.Type)
 	}
 
 	// Set defaults
@@ -648,7 +701,7 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		MetricsExporter: "prometheus",
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create instrumentation: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create instrumentation: %w", err)
 	}
 	opts = append(opts, oauth.WithInstrumentation(inst))
 
@@ -680,6 +733,17 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		"maxClientsPerIP", maxClientsPerIP,
 		"window", security.DefaultRegistrationWindow)
 
+	// mcp-oauth refreshes provider tokens itself when validating requests;
+	// report those refreshes as well as the ones made by IDTokenRefresher.
+	if config.OnTokenRefresh != nil {
+		onRefresh := config.OnTokenRefresh
+		opts = append(opts, oauth.WithTokenRefreshHandler(func(ctx context.Context, _, _ string, newToken *oauth2.Token) {
+			if email := mcpoauth.IDTokenEmail(mcpoauth.GetIDToken(newToken)); email != "" {
+				onRefresh(ctx, email)
+			}
+		}))
+	}
+
 	if len(config.TrustedAudiences) > 0 {
 		opts = append(opts, oauthserver.WithTrustedAudiences(config.TrustedAudiences))
 	}
@@ -715,19 +779,25 @@ func createOAuthServer(config OAuthConfig) (*oauth.Server, storage.TokenStore, e
 		opts...,
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create OAuth server: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create OAuth server: %w", err)
 	}
 
-	return server, tokenStore, nil
+	return server, tokenStore, provider, nil
 }
 
 // NewOAuthHTTPServer creates a new OAuth-enabled HTTP server
 func NewOAuthHTTPServer(mcpServer *mcpserver.MCPServer, serverType string, config OAuthConfig) (*OAuthHTTPServer, error) {
-	oauthServer, tokenStore, err := createOAuthServer(config)
+	oauthServer, tokenStore, provider, err := buildOAuthServer(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OAuth server: %w", err)
 	}
 
+	var idTokenRefresher *mcpoauth.IDTokenRefresher
+	if config.RefreshIDTokens {
+		idTokenRefresher = mcpoauth.NewIDTokenRefresher(tokenStore, provider)
+		idTokenRefresher.OnRefresh = config.OnTokenRefresh
+	}
+
 	// Build issuer→config lookup for the access-token injector middleware. One
 	// entry per issuer URL (enforced by startup validation); mcp-oauth has already
 	// validated the token (including allowedClaims) by the time the injector runs.
@@ -746,8 +816,11 @@ func NewOAuthHTTPServer(mcpServer *mcpserver.MCPServer, serverType string, confi
 		tokenStore:              tokenStore,
 		serverType:              serverType,
 		disableStreaming:        config.DisableStreaming,
+		sseEndpoint:             config.SSEEndpoint,
+		messageEndpoint:         config.MessageEndpoint,
 		instrumentationProvider: config.InstrumentationProvider,
 		trustedIssuersByIssuer:  issuerMap,
+		idTokenRefresher:        idTokenRefresher,
 	}, nil
 }
 
@@ -801,14 +874,13 @@ func (s *OAuthHTTPServer) setupOAuthRoutes(mux *http.ServeMux) {
 
 // setupMCPRoutes registers MCP endpoints on the mux
 func (s *OAuthHTTPServer) setupMCPRoutes(mux *http.ServeMux) error {
+	// The HTTPContextFunc propagates the access token to mcp-go's tool
+	// execution context. The token is set in r.Context() by our middleware
+	// chain, and we copy it to mcp-go's internal context here.
+	httpContextFunc := s.createHTTPContextFunc()
 
 	switch s.serverType {
 	case "streamable-http":
-		// Create Streamable HTTP server with HTTPContextFunc to propagate access token
-		// to mcp-go's tool execution context. The token is set in r.Context() by our
-		// middleware chain, and we copy it to mcp-go's internal context here.
-		httpContextFunc := s.createHTTPContextFunc()
-
 		var httpServer http.Handler
 		if s.disableStreaming {
 			httpServer = mcpserver.NewStreamableHTTPServer(s.mcpServer,
@@ -823,16 +895,30 @@ func (s *OAuthHTTPServer) setupMCPRoutes(mux *http.ServeMux) error {
 			)
 		}
 
-		// Create middleware to inject access token into request context for downstream K8s auth
-		accessTokenInjector := s.createAccessTokenInjectorMiddleware(httpServer)
+		mux.Handle("/mcp", s.protectMCPHandler(httpServer))
 
-		// Fail fast when the validated UserInfo carries no email claim; without
-		// an email there is no Impersonate-User to send to the Kubernetes API.
-		requireIdentity := middleware.RequireIdentity(s.oauthServer.Auditor, s.oauthServer.Logger)
+		return nil
+	case "sse":
+		sseEndpoint := s.sseEndpoint
+		if sseEndpoint == "" {
+			sseEndpoint = "/sse"
+		}
+		messageEndpoint := s.messageEndpoint
+		if messageEndpoint == "" {
+			messageEndpoint = "/message"
+		}
 
-		// Wrap MCP endpoint with OAuth middleware (ValidateToken validates and adds user info)
-		// Then enforce that UserInfo has an email before the injector / tool dispatch.
-		mux.Handle("/mcp", s.oauthHandler.ValidateToken(requireIdentity(accessTokenInjector)))
+		sseServer := mcpserver.NewSSEServer(s.mcpServer,
+			mcpserver.WithSSEEndpoint(sseEndpoint),
+			mcpserver.WithMessageEndpoint(messageEndpoint),
+			mcpserver.WithSSEContextFunc(mcpserver.SSEContextFunc(httpContextFunc)),
+		)
+
+		// Both endpoints need a valid token: the stream carries tool results
+		// and the message endpoint dispatches tool calls.
+		protected := s.protectMCPHandler(sseServer)
+		mux.Handle(sseEndpoint, protected)
+		mux.Handle(messageEndpoint, protected)
 
 		return nil
 	default:
@@ -840,6 +926,22 @@ func (s *OAuthHTTPServer) setupMCPRoutes(mux *http.ServeMux) error {
 	}
 }
 
+// protectMCPHandler puts the OAuth middleware chain in front of an MCP
+// endpoint, the same for every transport.
+func (s *OAuthHTTPServer) protectMCPHandler(next http.Handler) http.Handler {
+	// Create middleware to inject access token into request context for downstream K8s auth
+	accessTokenInjector := s.createAccessTokenInjectorMiddleware(next)
+
+	// Fail fast when the validated UserInfo carries no email claim; without
+	// an email there is no Impersonate-User to send to the Kubernetes API.
+	requireIdentity := middleware.RequireIdentity(s.oauthServer.Auditor, s.oauthServer.Logger)
+
+	// Wrap MCP endpoint with OAuth middleware (ValidateToken validates and adds user info)
+	// Then enforce that UserInfo has an email before the injector / tool dispatch.
+	// ClientIP records the caller's address for rate limiting.
+	return middleware.ClientIP(s.oauthHandler.ValidateToken(requireIdentity(accessTokenInjector)))
+}
+
 // validateStartConfig validates the configuration before starting the server
 func (s *OAuthHTTPServer) validateStartConfig(config OAuthConfig) ([]string, error) {
 	// Validate HTTPS requirement for OAuth 2.1
@@ -885,22 +987,16 @@ func (s *OAuthHTTPServer) Start(addr string, config OAuthConfig) error {
 		s.healthChecker.RegisterHealthEndpoints(mux)
 	}
 
-	// Create HTTP server with security, CORS, and metrics middleware
-	// Order: Metrics (outermost) -> Security Headers -> CORS -> Handler
-	// Metrics middleware wraps everything to capture all request metrics
-	handler := middleware.HTTPMetrics(s.instrumentationProvider)(
-		middleware.SecurityHeaders(config.EnableHSTS)(
-			middleware.CORS(allowedOrigins)(mux),
-		),
-	)
-
-	s.httpServer = &http.Server{
-		Addr:              addr,
-		Handler:           handler,
-		ReadHeaderTimeout: DefaultReadHeaderTimeout,
-		WriteTimeout:      DefaultWriteTimeout,
-		IdleTimeout:       DefaultIdleTimeout,
-	}
+	// Create HTTP server with the security, CORS, and metrics middleware
+	// shared with the transports that run without OAuth
+	handler := WrapHTTPHandler(mux, HTTPStackConfig{
+		EnableHSTS:              config.EnableHSTS,
+		AllowedOrigins:          allowedOrigins,
+		Compression:             config.Compression,
+		CompressionMinSize:      config.CompressionMinSize,
+		InstrumentationProvider: s.instrumentationProvider,
+	})
+	s.httpServer = NewHTTPServer(addr, handler)
 
 	// Start server with TLS if certificates are provided
 	if config.TLSCertFile != "" && config.TLSKeyFile != "" {
@@ -1093,12 +1189,14 @@ func (s *OAuthHTTPServer) createAccessTokenInjectorMiddleware(next http.Handler)
 
 		// Retrieve the provider token using the MCP access token (Bearer token) as the key
 		// This is the same key used by mcp-oauth during token exchange and proactive refresh
-		token, err := s.tokenStore.GetToken(ctx, bearerToken)
+		storeKey := bearerToken
+		token, err := s.tokenStore.GetToken(ctx, storeKey)
 		if err != nil {
 			slog.Debug("AccessTokenInjector: failed to get token from store by access token", //nolint:gosec // G706: email is hashed, err is internal
 				logging.UserHash(userInfo.Email), logging.Err(err))
 			// Fallback to email-based lookup for backwards compatibility
-			token, err = s.tokenStore.GetToken(ctx, userInfo.Email)
+			storeKey = userInfo.Email
+			token, err = s.tokenStore.GetToken(ctx, storeKey)
 			if err != nil {
 				slog.Debug("AccessTokenInjector: fallback email lookup also failed",
 					logging.UserHash(userInfo.Email), logging.Err(err))
@@ -1132,6 +1230,20 @@ func (s *OAuthHTTPServer) createAccessTokenInjectorMiddleware(next http.Handler)
 			return
 		}
 
+		// Refresh the ID token if it is about to expire, and hand tool handlers
+		// a source for it so clients outliving this request stay authenticated.
+		if s.idTokenRefresher != nil {
+			idToken, err = s.idTokenRefresher.IDToken(ctx, storeKey, userInfo.Email)
+			if err != nil {
+				slog.Debug("AccessTokenInjector: ID token expired and refresh failed", //nolint:gosec // G706: email is hashed, err is internal
+					logging.UserHash(userInfo.Email), logging.Err(err))
+				recordMetric(ctx, "token_expired")
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx = mcpoauth.ContextWithIDTokenSource(ctx, s.idTokenRefresher.TokenSource(storeKey, userInfo.Email))
+		}
+
 		slog.Debug("AccessTokenInjector: successfully injected ID token",
 			logging.UserHash(userInfo.Email))
 		ctx = mcpoauth.ContextWithIDToken(ctx, idToken)
@@ -1155,7 +1267,11 @@ func (s *OAuthHTTPServer) createHTTPContextFunc() mcpserver.HTTPContextFunc {
 		idToken, ok := mcpoauth.GetIDTokenFromContext(r.Context())
 		if ok && idToken != "" {
 			slog.Debug("HTTPContextFunc: propagating ID token to mcp-go context")
-			return mcpoauth.ContextWithIDToken(ctx, idToken)
+			ctx = mcpoauth.ContextWithIDToken(ctx, idToken)
+			if source, ok := mcpoauth.GetIDTokenSourceFromContext(r.Context()); ok {
+				ctx = mcpoauth.ContextWithIDTokenSource(ctx, source)
+			}
+			return ctx
 		}
 		slog.Debug("HTTPContextFunc: no ID token in request context to propagate")
 		return ctx
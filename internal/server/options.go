@@ -5,10 +5,19 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+	"github.com/giantswarm/mcp-kubernetes/internal/confirmation"
 	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/plan"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
 )
 
 // Option is a functional option for configuring ServerContext.
@@ -91,6 +100,29 @@ func WithDryRun(enabled bool) Option {
 	}
 }
 
+// WithAllowImpersonateAs enables or disables the per-call impersonation override.
+func WithAllowImpersonateAs(enabled bool) Option {
+	return func(sc *ServerContext) error {
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		sc.config.AllowImpersonateAs = enabled
+		return nil
+	}
+}
+
+// WithKubeconfigContexts restricts the kubeContext tool parameter to the
+// given kubeconfig contexts.
+func WithKubeconfigContexts(contexts []string) Option {
+	return func(sc *ServerContext) error {
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		sc.config.KubeconfigContexts = contexts
+		return nil
+	}
+}
+
 // WithLogLevel sets the logging level.
 func WithLogLevel(level string) Option {
 	return func(sc *ServerContext) error {
@@ -131,6 +163,97 @@ func WithRestrictedNamespaces(namespaces []string) Option {
 	}
 }
 
+// WithReadOnlyGroups sets the OAuth groups restricted to read-only tools.
+func WithReadOnlyGroups(groups []string) Option {
+	return func(sc *ServerContext) error {
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		if groups != nil {
+			sc.config.ReadOnlyGroups = make([]string, len(groups))
+			copy(sc.config.ReadOnlyGroups, groups)
+		}
+		return nil
+	}
+}
+
+// WithDebugImages sets the images debug_pod may start as ephemeral containers.
+func WithDebugImages(images []string) Option {
+	return func(sc *ServerContext) error {
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		if images != nil {
+			sc.config.DebugImages = make([]string, len(images))
+			copy(sc.config.DebugImages, images)
+		}
+		return nil
+	}
+}
+
+// WithSecretWrites sets whether Secrets may be created, applied and patched:
+// SecretWritesAllow, SecretWritesDeny or SecretWritesOperation.
+func WithSecretWrites(mode string) Option {
+	return func(sc *ServerContext) error {
+		switch mode {
+		case "", SecretWritesAllow, SecretWritesDeny, SecretWritesOperation:
+		default:
+			return fmt.Errorf("invalid secret writes mode %q (valid: %s, %s, %s)", mode, SecretWritesAllow, SecretWritesDeny, SecretWritesOperation)
+		}
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		sc.config.SecretWrites = mode
+		return nil
+	}
+}
+
+// WithCopyLimits sets the container directories file copies may use and the
+// largest file they may copy. A zero maxBytes keeps the default.
+func WithCopyLimits(paths []string, maxBytes int64) Option {
+	return func(sc *ServerContext) error {
+		if maxBytes < 0 {
+			return fmt.Errorf("copy max bytes must not be negative, got %d", maxBytes)
+		}
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		if paths != nil {
+			sc.config.CopyPaths = make([]string, len(paths))
+			copy(sc.config.CopyPaths, paths)
+		}
+		sc.config.CopyMaxBytes = maxBytes
+		return nil
+	}
+}
+
+// WithToolTimeouts sets the timeout of a tool call and its per-tool
+// overrides. A zero timeout disables it.
+func WithToolTimeouts(timeout time.Duration, overrides map[string]time.Duration) Option {
+	return func(sc *ServerContext) error {
+		if timeout < 0 {
+			return fmt.Errorf("tool timeout must not be negative, got %s", timeout)
+		}
+		for name, d := range overrides {
+			if d < 0 {
+				return fmt.Errorf("timeout of tool %q must not be negative, got %s", name, d)
+			}
+		}
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		sc.config.ToolTimeout = timeout
+		sc.config.ToolTimeouts = nil
+		if overrides != nil {
+			sc.config.ToolTimeouts = make(map[string]time.Duration, len(overrides))
+			for name, d := range overrides {
+				sc.config.ToolTimeouts[name] = d
+			}
+		}
+		return nil
+	}
+}
+
 // WithClientFactory sets the client factory for creating per-user Kubernetes clients.
 // This is used for OAuth downstream authentication where each user's OAuth token
 // is used to authenticate with Kubernetes.
@@ -215,6 +338,155 @@ func WithFederationManager(manager federation.ClusterClientManager) Option {
 	}
 }
 
+// WithPolicyEngine sets the tool authorization policy engine.
+// When set, it supersedes the flat AllowedOperations and RestrictedNamespaces
+// settings as the source of truth for which tool calls are permitted.
+func WithPolicyEngine(engine *security.Engine) Option {
+	return func(sc *ServerContext) error {
+		sc.policyEngine = engine
+		return nil
+	}
+}
+
+// WithAuthorizer sets an external tool authorizer, such as an OPA sidecar.
+// It is consulted for every tool call the policy engine allows.
+func WithAuthorizer(authorizer security.Authorizer) Option {
+	return func(sc *ServerContext) error {
+		sc.authorizer = authorizer
+		return nil
+	}
+}
+
+// WithNamespaceLabelGuard restricts tool calls targeting namespaces whose
+// labels match the guard's selector.
+func WithNamespaceLabelGuard(guard *security.NamespaceLabelGuard) Option {
+	return func(sc *ServerContext) error {
+		sc.namespaceLabelGuard = guard
+		return nil
+	}
+}
+
+// WithNamespaceAccess restricts reads and writes by namespace: tool calls
+// may not read what readRestrictions lists, as namespace or
+// namespace/resource glob patterns, and mutating tools may only write in
+// namespaces matching writeNamespaces, unless it is empty. While they are
+// limited to some namespaces, cluster-scoped objects may only be written
+// when clusterScopedWrites is set.
+func WithNamespaceAccess(readRestrictions, writeNamespaces []string, clusterScopedWrites bool) Option {
+	return func(sc *ServerContext) error {
+		access, err := security.NewNamespaceAccess(readRestrictions, writeNamespaces, clusterScopedWrites)
+		if err != nil {
+			return err
+		}
+		if sc.config == nil {
+			sc.config = NewDefaultConfig()
+		}
+		sc.config.ReadRestrictions = access.ReadRestrictions()
+		sc.config.WriteNamespaces = access.WriteNamespaces()
+		sc.config.AllowClusterScopedWrites = access.ClusterScopedWrites()
+		sc.namespaceAccess = access
+		return nil
+	}
+}
+
+// WithToolRateLimiter sets the per-caller tool rate limiter. Every tool call
+// takes a token from the caller's bucket before it runs.
+func WithToolRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(sc *ServerContext) error {
+		sc.toolRateLimiter = limiter
+		return nil
+	}
+}
+
+// WithConfirmations holds destructive tool calls for confirmation in store
+// instead of running them right away.
+func WithConfirmations(store *confirmation.Store) Option {
+	return func(sc *ServerContext) error {
+		sc.confirmations = store
+		return nil
+	}
+}
+
+// WithPreferences applies the per-user preferences in store to tool calls
+// and enables the preferences tools.
+func WithPreferences(store *preferences.Store) Option {
+	return func(sc *ServerContext) error {
+		sc.preferences = store
+		return nil
+	}
+}
+
+// WithUndoJournal records the prior state of objects changed by tool calls
+// in j, so that the undo tool can restore it.
+func WithUndoJournal(j *journal.Journal) Option {
+	return func(sc *ServerContext) error {
+		sc.undoJournal = j
+		return nil
+	}
+}
+
+// WithPlans enables the plan tools, which keep change-sets in store.
+func WithPlans(store *plan.Store) Option {
+	return func(sc *ServerContext) error {
+		sc.plans = store
+		return nil
+	}
+}
+
+// WithSessionDefaults replaces the store of the default cluster and
+// namespace of MCP sessions, for example to change their TTL.
+func WithSessionDefaults(store *sessions.DefaultsStore) Option {
+	return func(sc *ServerContext) error {
+		sc.sessionDefaults = store
+		return nil
+	}
+}
+
+// WithListVersions replaces the store of the resource versions that
+// changedSince lists compare against, for example to change their TTL.
+func WithListVersions(store *sessions.ListVersionStore) Option {
+	return func(sc *ServerContext) error {
+		sc.listVersions = store
+		return nil
+	}
+}
+
+// WithReplicaID sets the ID of this replica, which tool results report so
+// that callers behind a load balancer can tell replicas apart.
+func WithReplicaID(id string) Option {
+	return func(sc *ServerContext) error {
+		sc.replicaID = id
+		return nil
+	}
+}
+
+// WithDebugCapture records the sanitized arguments and results of tool calls
+// in recorder, for debugging.
+func WithDebugCapture(recorder *capture.Recorder) Option {
+	return func(sc *ServerContext) error {
+		sc.debugCapture = recorder
+		return nil
+	}
+}
+
+// WithImpersonationExtras adds the configured details of each tool call to
+// the impersonation extras of its Kubernetes API requests.
+func WithImpersonationExtras(config *ImpersonationExtrasConfig) Option {
+	return func(sc *ServerContext) error {
+		sc.impersonationExtras = config
+		return nil
+	}
+}
+
+// WithSessionRegistry shares the ownership of port-forward sessions with
+// other replicas. The registry is closed when the context shuts down.
+func WithSessionRegistry(registry *sessions.Registry) Option {
+	return func(sc *ServerContext) error {
+		sc.sessionRegistry = registry
+		return nil
+	}
+}
+
 // WithOutputConfig sets the output processing configuration.
 // This controls how large responses are handled to prevent context overflow.
 func WithOutputConfig(output *OutputConfig) Option {
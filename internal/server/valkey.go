@@ -0,0 +1,33 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// NewValkeyClient connects to the Valkey server described by cfg. The
+// session, undo journal, preferences, rate limit and key rotation backends
+// and the readiness check all connect through it; cfg.KeyPrefix is left to
+// the caller.
+func NewValkeyClient(cfg ValkeyStorageConfig) (valkey.Client, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("valkey address is required")
+	}
+
+	opts := valkey.ClientOption{
+		InitAddress: []string{cfg.URL},
+		SelectDB:    cfg.DB,
+		Password:    cfg.Password,
+	}
+	if cfg.TLSEnabled {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	client, err := valkey.NewClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create valkey client: %w", err)
+	}
+	return client, nil
+}
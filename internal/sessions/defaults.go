@@ -0,0 +1,131 @@
+package sessions
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultDefaultsTTL is how long the defaults of an MCP session are kept
+// after they were last read or changed, for transports that do not report
+// when a session ends.
+const DefaultDefaultsTTL = 12 * time.Hour
+
+// DefaultMaxDefaults caps the number of sessions a DefaultsStore holds.
+const DefaultMaxDefaults = 10000
+
+// ErrTooManySessions is returned when a full DefaultsStore is asked to hold
+// the defaults of another session.
+var ErrTooManySessions = errors.New("too many sessions with defaults")
+
+// Defaults are the cluster and namespace an MCP session uses when a tool
+// call leaves them out. Empty fields have no default.
+type Defaults struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// IsZero reports whether no default is set.
+func (d Defaults) IsZero() bool {
+	return d.Cluster == "" && d.Namespace == ""
+}
+
+// DefaultsStore holds the Defaults of MCP sessions, keyed by session ID.
+// Defaults live in the memory of the replica serving the session and are
+// dropped when the session ends (see Delete) or has been idle for the TTL.
+type DefaultsStore struct {
+	mu       sync.Mutex
+	sessions map[string]*defaultsEntry
+	ttl      time.Duration
+	max      int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+type defaultsEntry struct {
+	defaults  Defaults
+	expiresAt time.Time
+}
+
+// NewDefaultsStore creates a store whose entries expire ttl after they were
+// last used. A ttl of zero or less uses DefaultDefaultsTTL.
+func NewDefaultsStore(ttl time.Duration) *DefaultsStore {
+	if ttl <= 0 {
+		ttl = DefaultDefaultsTTL
+	}
+	return &DefaultsStore{
+		sessions: make(map[string]*defaultsEntry),
+		ttl:      ttl,
+		max:      DefaultMaxDefaults,
+		now:      time.Now,
+	}
+}
+
+// Get returns the defaults of session, which are zero when none are set.
+func (s *DefaultsStore) Get(session string) Defaults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.sessions[session]
+	if !ok {
+		return Defaults{}
+	}
+	if !s.now().Before(e.expiresAt) {
+		delete(s.sessions, session)
+		return Defaults{}
+	}
+	e.expiresAt = s.now().Add(s.ttl)
+	return e.defaults
+}
+
+// Update changes the defaults of session with update and returns the
+// result. Clearing every default removes the session from the store.
+func (s *DefaultsStore) Update(session string, update func(*Defaults)) (Defaults, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+
+	e, ok := s.sessions[session]
+	if !ok {
+		e = &defaultsEntry{}
+	}
+	d := e.defaults
+	update(&d)
+	if d.IsZero() {
+		delete(s.sessions, session)
+		return d, nil
+	}
+	if !ok {
+		if len(s.sessions) >= s.max {
+			return Defaults{}, ErrTooManySessions
+		}
+		s.sessions[session] = e
+	}
+	e.defaults = d
+	e.expiresAt = s.now().Add(s.ttl)
+	return d, nil
+}
+
+// Delete drops the defaults of session, for example when it ends.
+func (s *DefaultsStore) Delete(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, session)
+}
+
+// Len returns the number of sessions with defaults.
+func (s *DefaultsStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+	return len(s.sessions)
+}
+
+func (s *DefaultsStore) evictExpiredLocked() {
+	now := s.now()
+	for id, e := range s.sessions {
+		if !now.Before(e.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
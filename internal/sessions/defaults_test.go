@@ -0,0 +1,64 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultsStore(t *testing.T) {
+	s := NewDefaultsStore(time.Hour)
+	assert.True(t, s.Get("s1").IsZero())
+
+	d, err := s.Update("s1", func(d *Defaults) { d.Cluster = "prod-wc-01" })
+	require.NoError(t, err)
+	assert.Equal(t, Defaults{Cluster: "prod-wc-01"}, d)
+
+	d, err = s.Update("s1", func(d *Defaults) { d.Namespace = "team-a" })
+	require.NoError(t, err)
+	assert.Equal(t, Defaults{Cluster: "prod-wc-01", Namespace: "team-a"}, d)
+	assert.Equal(t, d, s.Get("s1"))
+	assert.True(t, s.Get("s2").IsZero(), "defaults are per session")
+
+	_, err = s.Update("s1", func(d *Defaults) { *d = Defaults{} })
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.Len(), "clearing every default removes the session")
+
+	_, err = s.Update("s2", func(d *Defaults) { d.Namespace = "team-b" })
+	require.NoError(t, err)
+	s.Delete("s2")
+	assert.True(t, s.Get("s2").IsZero())
+}
+
+func TestDefaultsStore_Expiry(t *testing.T) {
+	now := time.Now()
+	s := NewDefaultsStore(time.Hour)
+	s.now = func() time.Time { return now }
+
+	_, err := s.Update("s1", func(d *Defaults) { d.Namespace = "team-a" })
+	require.NoError(t, err)
+
+	now = now.Add(50 * time.Minute)
+	assert.Equal(t, "team-a", s.Get("s1").Namespace, "reading keeps the defaults alive")
+
+	now = now.Add(50 * time.Minute)
+	assert.Equal(t, "team-a", s.Get("s1").Namespace)
+
+	now = now.Add(time.Hour)
+	assert.True(t, s.Get("s1").IsZero(), "idle defaults expire")
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestDefaultsStore_Full(t *testing.T) {
+	s := NewDefaultsStore(time.Hour)
+	s.max = 1
+
+	_, err := s.Update("s1", func(d *Defaults) { d.Namespace = "a" })
+	require.NoError(t, err)
+	_, err = s.Update("s2", func(d *Defaults) { d.Namespace = "b" })
+	assert.ErrorIs(t, err, ErrTooManySessions)
+	_, err = s.Update("s1", func(d *Defaults) { d.Namespace = "c" })
+	assert.NoError(t, err, "existing sessions can still change")
+}
@@ -0,0 +1,17 @@
+// Package sessions records which replica owns a long-running session, so
+// that several replicas behind a load balancer can tell a session they do
+// not hold from one that does not exist.
+//
+// Port-forward sessions live in the process that opened them: the tunnel
+// and the local port belong to one replica. A Registry publishes every
+// session it opens to a shared Store (Valkey) under the replica's ID, keeps
+// the records alive while the session runs and removes them when it stops.
+// A replica asked about a session it does not hold looks it up in the store
+// and reports the owner instead of answering "not found".
+//
+// Records expire when their replica stops refreshing them, so a crashed
+// replica does not leave sessions behind for longer than the record TTL.
+//
+// Kubernetes list continue tokens need no registry: they are issued and
+// resolved by the API server, so any replica can continue a list.
+package sessions
@@ -0,0 +1,286 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a session record outlives the last refresh by its
+// replica. Records are refreshed every third of it.
+const DefaultTTL = time.Minute
+
+// KindPortForward is the kind of port-forward sessions.
+const KindPortForward = "port-forward"
+
+// KindInteractive is the kind of interactive exec and attach sessions.
+const KindInteractive = "interactive"
+
+// keySegment namespaces session records under the key prefix.
+const keySegment = "session:"
+
+// scanCount is the SCAN batch size used to list sessions.
+const scanCount = 100
+
+// Store is the key-value store holding session records.
+type Store interface {
+	// Set stores value under key, expiring after ttl.
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+
+	// Get returns the value of key and whether it exists.
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Delete removes key. Removing a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Scan returns keys matching pattern starting at cursor, and the cursor
+	// for the next call (0 when done).
+	Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error)
+
+	// Close releases the store's resources.
+	Close() error
+}
+
+// Session is the record of a session held by a replica.
+type Session struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Replica   string    `json:"replica"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DefaultReplicaID identifies this process when no replica ID is
+// configured: the pod name, falling back to the host name.
+func DefaultReplicaID() string {
+	if name := os.Getenv("K8S_POD_NAME"); name != "" {
+		return name
+	}
+	name, _ := os.Hostname()
+	return name
+}
+
+// Registry publishes the sessions held by this replica to a shared Store
+// and looks up sessions held by other replicas. It is safe for concurrent
+// use.
+type Registry struct {
+	store     Store
+	keyPrefix string
+	replicaID string
+	ttl       time.Duration
+	logger    *slog.Logger
+
+	mu    sync.Mutex
+	owned map[string]Session
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+// NewRegistry creates a registry for replicaID keeping its records under
+// keyPrefix in store, and starts refreshing them. Close stops it.
+func NewRegistry(store Store, keyPrefix, replicaID string, logger *slog.Logger) (*Registry, error) {
+	if replicaID == "" {
+		return nil, fmt.Errorf("a replica ID is required for the session registry")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	r := &Registry{
+		store:     store,
+		keyPrefix: keyPrefix,
+		replicaID: replicaID,
+		ttl:       DefaultTTL,
+		logger:    logger,
+		owned:     make(map[string]Session),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		now:       time.Now,
+	}
+	go r.refreshLoop()
+	return r, nil
+}
+
+// ReplicaID returns the ID of this replica.
+func (r *Registry) ReplicaID() string {
+	return r.replicaID
+}
+
+// Register records that this replica holds the session id.
+func (r *Registry) Register(ctx context.Context, id, kind string) error {
+	s := Session{ID: id, Kind: kind, Replica: r.replicaID, CreatedAt: r.now().UTC()}
+
+	r.mu.Lock()
+	r.owned[id] = s
+	r.mu.Unlock()
+
+	return r.put(ctx, s)
+}
+
+// Unregister removes the record of the session id held by this replica.
+// Records of other replicas are left alone.
+func (r *Registry) Unregister(ctx context.Context, id string) error {
+	r.mu.Lock()
+	_, ok := r.owned[id]
+	delete(r.owned, id)
+	r.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return r.deleteOwned(ctx, id)
+}
+
+// UnregisterAll removes the records of all sessions held by this replica.
+func (r *Registry) UnregisterAll(ctx context.Context) error {
+	r.mu.Lock()
+	ids := make([]string, 0, len(r.owned))
+	for id := range r.owned {
+		ids = append(ids, id)
+	}
+	r.owned = make(map[string]Session)
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, id := range ids {
+		if err := r.deleteOwned(ctx, id); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Lookup returns the record of the session id, or nil if no replica holds
+// it.
+func (r *Registry) Lookup(ctx context.Context, id string) (*Session, error) {
+	value, ok, err := r.store.Get(ctx, r.key(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up session %s: %w", id, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	var s Session
+	if err := json.Unmarshal([]byte(value), &s); err != nil {
+		return nil, fmt.Errorf("invalid record of session %s: %w", id, err)
+	}
+	return &s, nil
+}
+
+// List returns the sessions held by all replicas, ordered by ID.
+func (r *Registry) List(ctx context.Context) ([]Session, error) {
+	var all []Session
+	var cursor uint64
+	for {
+		keys, next, err := r.store.Scan(ctx, cursor, r.keyPrefix+keySegment+"*", scanCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		for _, key := range keys {
+			s, err := r.Lookup(ctx, strings.TrimPrefix(key, r.keyPrefix+keySegment))
+			if err != nil {
+				return nil, err
+			}
+			// The record may have expired since the scan.
+			if s != nil {
+				all = append(all, *s)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all, nil
+}
+
+// Close stops refreshing, removes the records of this replica's sessions
+// and closes the store.
+func (r *Registry) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+
+	if err := r.UnregisterAll(context.Background()); err != nil {
+		r.logger.Warn("failed to remove sessions from the registry", slog.Any("error", err))
+	}
+	return r.store.Close()
+}
+
+// refreshLoop keeps the records of this replica's sessions from expiring.
+func (r *Registry) refreshLoop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.refresh(context.Background())
+		}
+	}
+}
+
+// refresh rewrites the records of this replica's sessions.
+func (r *Registry) refresh(ctx context.Context) {
+	r.mu.Lock()
+	owned := make([]Session, 0, len(r.owned))
+	for _, s := range r.owned {
+		owned = append(owned, s)
+	}
+	r.mu.Unlock()
+
+	for _, s := range owned {
+		if err := r.put(ctx, s); err != nil {
+			r.logger.Warn("failed to refresh session in the registry",
+				slog.String("session_id", s.ID),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// deleteOwned removes the record of the session id unless another replica
+// has since opened a session with the same ID.
+func (r *Registry) deleteOwned(ctx context.Context, id string) error {
+	s, err := r.Lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s == nil || s.Replica != r.replicaID {
+		return nil
+	}
+	if err := r.store.Delete(ctx, r.key(id)); err != nil {
+		return fmt.Errorf("failed to remove session %s from the registry: %w", id, err)
+	}
+	return nil
+}
+
+// put writes the record of s.
+func (r *Registry) put(ctx context.Context, s Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := r.store.Set(ctx, r.key(s.ID), string(data), r.ttl); err != nil {
+		return fmt.Errorf("failed to record session %s in the registry: %w", s.ID, err)
+	}
+	return nil
+}
+
+// key returns the store key of the session id.
+func (r *Registry) key(id string) string {
+	return r.keyPrefix + keySegment + id
+}
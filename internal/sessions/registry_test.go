@@ -0,0 +1,193 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store shared by the registries of several
+// simulated replicas. Scan returns one key per call so tests exercise
+// cursor handling.
+type memoryStore struct {
+	mu     sync.Mutex
+	data   map[string]string
+	ttls   map[string]time.Duration
+	closed bool
+	err    error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{data: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (s *memoryStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.data[key] = value
+	s.ttls[key] = ttl
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return "", false, s.err
+	}
+	v, ok := s.data[key]
+	return v, ok, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memoryStore) Scan(_ context.Context, cursor uint64, pattern string, _ int64) ([]string, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	prefix := strings.TrimSuffix(pattern, "*")
+	for k := range s.data {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	start := min(int(cursor), len(keys))
+	end := min(start+1, len(keys))
+	next := uint64(end)
+	if end == len(keys) {
+		next = 0
+	}
+	return keys[start:end], next, nil
+}
+
+func (s *memoryStore) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newTestRegistry(t *testing.T, store Store, replicaID string) *Registry {
+	t.Helper()
+	r, err := NewRegistry(store, "mcp:", replicaID, nil)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRegistry_RegisterAndLookup(t *testing.T) {
+	store := newMemoryStore()
+	a := newTestRegistry(t, store, "replica-a")
+	b := newTestRegistry(t, store, "replica-b")
+	defer func() { _ = b.Close() }()
+
+	ctx := context.Background()
+	require.NoError(t, a.Register(ctx, "default/pod/web:8080", KindPortForward))
+	assert.Contains(t, store.data, "mcp:session:default/pod/web:8080")
+	assert.Equal(t, DefaultTTL, store.ttls["mcp:session:default/pod/web:8080"])
+
+	// Another replica sees who holds the session.
+	s, err := b.Lookup(ctx, "default/pod/web:8080")
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, "replica-a", s.Replica)
+	assert.Equal(t, KindPortForward, s.Kind)
+
+	s, err = b.Lookup(ctx, "default/pod/missing:80")
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	// A replica cannot unregister a session it does not hold.
+	require.NoError(t, b.Unregister(ctx, "default/pod/web:8080"))
+	assert.Contains(t, store.data, "mcp:session:default/pod/web:8080")
+
+	require.NoError(t, a.Unregister(ctx, "default/pod/web:8080"))
+	assert.NotContains(t, store.data, "mcp:session:default/pod/web:8080")
+
+	// Nor remove a session another replica reopened under the same ID.
+	require.NoError(t, a.Register(ctx, "default/pod/web:8080", KindPortForward))
+	require.NoError(t, b.Register(ctx, "default/pod/web:8080", KindPortForward))
+	require.NoError(t, a.Unregister(ctx, "default/pod/web:8080"))
+	s, err = b.Lookup(ctx, "default/pod/web:8080")
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, "replica-b", s.Replica)
+	require.NoError(t, b.Unregister(ctx, "default/pod/web:8080"))
+
+	// Closing removes the remaining sessions of the replica and the store.
+	require.NoError(t, a.Register(ctx, "default/service/api:80", KindPortForward))
+	require.NoError(t, a.Close())
+	assert.Empty(t, store.data)
+	assert.True(t, store.closed)
+}
+
+func TestRegistry_List(t *testing.T) {
+	store := newMemoryStore()
+	a := newTestRegistry(t, store, "replica-a")
+	b := newTestRegistry(t, store, "replica-b")
+	defer func() { _ = a.Close() }()
+	defer func() { _ = b.Close() }()
+
+	ctx := context.Background()
+	require.NoError(t, b.Register(ctx, "ns/pod/b:80", KindPortForward))
+	require.NoError(t, a.Register(ctx, "ns/pod/a:80", KindPortForward))
+	require.NoError(t, a.Register(ctx, "ns/pod/c:80", KindPortForward))
+	store.data["other:key"] = "ignored"
+
+	all, err := a.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "ns/pod/a:80", all[0].ID)
+	assert.Equal(t, "replica-b", all[1].Replica)
+	assert.Equal(t, "ns/pod/c:80", all[2].ID)
+
+	require.NoError(t, a.UnregisterAll(ctx))
+	all, err = b.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "replica-b", all[0].Replica)
+}
+
+func TestRegistry_Refresh(t *testing.T) {
+	store := newMemoryStore()
+	r := newTestRegistry(t, store, "replica-a")
+	defer func() { _ = r.Close() }()
+
+	ctx := context.Background()
+	require.NoError(t, r.Register(ctx, "ns/pod/a:80", KindPortForward))
+
+	// An expired record comes back with the next refresh.
+	delete(store.data, "mcp:session:ns/pod/a:80")
+	r.refresh(ctx)
+	assert.Contains(t, store.data, "mcp:session:ns/pod/a:80")
+}
+
+func TestRegistry_StoreErrors(t *testing.T) {
+	store := newMemoryStore()
+	r := newTestRegistry(t, store, "replica-a")
+	defer func() { _ = r.Close() }()
+
+	store.err = errors.New("connection refused")
+	ctx := context.Background()
+	assert.ErrorContains(t, r.Register(ctx, "ns/pod/a:80", KindPortForward), "failed to record session")
+	_, err := r.Lookup(ctx, "ns/pod/a:80")
+	assert.ErrorContains(t, err, "failed to look up session")
+}
+
+func TestNewRegistry_RequiresReplicaID(t *testing.T) {
+	_, err := NewRegistry(newMemoryStore(), "mcp:", "", nil)
+	assert.Error(t, err)
+}
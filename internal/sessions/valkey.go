@@ -0,0 +1,55 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// ValkeyStore implements Store on a Valkey connection.
+type ValkeyStore struct {
+	client valkey.Client
+}
+
+// NewValkeyStore creates a store on client.
+func NewValkeyStore(client valkey.Client) *ValkeyStore {
+	return &ValkeyStore{client: client}
+}
+
+// Set implements Store.
+func (s *ValkeyStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return s.client.Do(ctx, s.client.B().Set().Key(key).Value(value).Px(ttl).Build()).Error()
+}
+
+// Get implements Store.
+func (s *ValkeyStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := s.client.Do(ctx, s.client.B().Get().Key(key).Build()).ToString()
+	if valkey.IsValkeyNil(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// Delete implements Store.
+func (s *ValkeyStore) Delete(ctx context.Context, key string) error {
+	return s.client.Do(ctx, s.client.B().Del().Key(key).Build()).Error()
+}
+
+// Scan implements Store.
+func (s *ValkeyStore) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	entry, err := s.client.Do(ctx, s.client.B().Scan().Cursor(cursor).Match(pattern).Count(count).Build()).AsScanEntry()
+	if err != nil {
+		return nil, 0, err
+	}
+	return entry.Elements, entry.Cursor, nil
+}
+
+// Close closes the Valkey connection.
+func (s *ValkeyStore) Close() error {
+	s.client.Close()
+	return nil
+}
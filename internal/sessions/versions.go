@@ -0,0 +1,132 @@
+package sessions
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMaxListVersionSessions caps the number of sessions a
+// ListVersionStore holds.
+const DefaultMaxListVersionSessions = 1000
+
+// DefaultMaxListVersionQueries caps the lists remembered per session. The
+// least recently used list is forgotten first.
+const DefaultMaxListVersionQueries = 16
+
+// ListVersionStore remembers, per MCP session and list query, the
+// resourceVersion of every object the session was last shown, so that a
+// repeated list can return only what changed. Like DefaultsStore, it lives
+// in the memory of the replica serving the session and entries are dropped
+// when the session ends (see Delete) or has been idle for the TTL.
+type ListVersionStore struct {
+	mu         sync.Mutex
+	sessions   map[string]*listVersionsEntry
+	ttl        time.Duration
+	maxSession int
+	maxQueries int
+
+	// now is injectable for tests.
+	now func() time.Time
+}
+
+type listVersionsEntry struct {
+	queries   map[string]*listSnapshot
+	expiresAt time.Time
+}
+
+type listSnapshot struct {
+	versions map[string]string
+	usedAt   time.Time
+}
+
+// NewListVersionStore creates a store whose sessions expire ttl after they
+// were last used. A ttl of zero or less uses DefaultDefaultsTTL.
+func NewListVersionStore(ttl time.Duration) *ListVersionStore {
+	if ttl <= 0 {
+		ttl = DefaultDefaultsTTL
+	}
+	return &ListVersionStore{
+		sessions:   make(map[string]*listVersionsEntry),
+		ttl:        ttl,
+		maxSession: DefaultMaxListVersionSessions,
+		maxQueries: DefaultMaxListVersionQueries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the versions session was last shown for query, by object key,
+// and whether there are any.
+func (s *ListVersionStore) Get(session, query string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.sessions[session]
+	if !ok {
+		return nil, false
+	}
+	now := s.now()
+	if !now.Before(e.expiresAt) {
+		delete(s.sessions, session)
+		return nil, false
+	}
+	e.expiresAt = now.Add(s.ttl)
+	snapshot, ok := e.queries[query]
+	if !ok {
+		return nil, false
+	}
+	snapshot.usedAt = now
+	return snapshot.versions, true
+}
+
+// Put records the versions session was shown for query. The store keeps
+// versions; callers must not change it afterwards.
+func (s *ListVersionStore) Put(session, query string, versions map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+
+	now := s.now()
+	e, ok := s.sessions[session]
+	if !ok {
+		if len(s.sessions) >= s.maxSession {
+			return ErrTooManySessions
+		}
+		e = &listVersionsEntry{queries: make(map[string]*listSnapshot)}
+		s.sessions[session] = e
+	}
+	e.expiresAt = now.Add(s.ttl)
+	if _, ok := e.queries[query]; !ok && len(e.queries) >= s.maxQueries {
+		oldest := ""
+		for q, snapshot := range e.queries {
+			if oldest == "" || snapshot.usedAt.Before(e.queries[oldest].usedAt) {
+				oldest = q
+			}
+		}
+		delete(e.queries, oldest)
+	}
+	e.queries[query] = &listSnapshot{versions: versions, usedAt: now}
+	return nil
+}
+
+// Delete drops the versions of session, for example when it ends.
+func (s *ListVersionStore) Delete(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, session)
+}
+
+// Len returns the number of sessions with remembered lists.
+func (s *ListVersionStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+	return len(s.sessions)
+}
+
+func (s *ListVersionStore) evictExpiredLocked() {
+	now := s.now()
+	for id, e := range s.sessions {
+		if !now.Before(e.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
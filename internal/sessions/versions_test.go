@@ -0,0 +1,75 @@
+package sessions
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListVersionStore(t *testing.T) {
+	s := NewListVersionStore(time.Hour)
+	_, ok := s.Get("s1", "pods")
+	assert.False(t, ok)
+
+	require.NoError(t, s.Put("s1", "pods", map[string]string{"default/a": "1"}))
+	versions, ok := s.Get("s1", "pods")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"default/a": "1"}, versions)
+
+	_, ok = s.Get("s1", "deployments")
+	assert.False(t, ok, "versions are per query")
+	_, ok = s.Get("s2", "pods")
+	assert.False(t, ok, "versions are per session")
+
+	s.Delete("s1")
+	_, ok = s.Get("s1", "pods")
+	assert.False(t, ok)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestListVersionStore_Expiry(t *testing.T) {
+	now := time.Now()
+	s := NewListVersionStore(time.Hour)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Put("s1", "pods", map[string]string{}))
+	now = now.Add(50 * time.Minute)
+	_, ok := s.Get("s1", "pods")
+	assert.True(t, ok, "reading keeps the versions alive")
+
+	now = now.Add(50 * time.Minute)
+	_, ok = s.Get("s1", "pods")
+	assert.True(t, ok)
+
+	now = now.Add(time.Hour)
+	_, ok = s.Get("s1", "pods")
+	assert.False(t, ok, "idle versions expire")
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestListVersionStore_Limits(t *testing.T) {
+	now := time.Now()
+	s := NewListVersionStore(time.Hour)
+	s.now = func() time.Time { return now }
+	s.maxSession = 1
+	s.maxQueries = 2
+
+	require.NoError(t, s.Put("s1", "q0", map[string]string{}))
+	assert.ErrorIs(t, s.Put("s2", "q0", map[string]string{}), ErrTooManySessions)
+
+	now = now.Add(time.Second)
+	require.NoError(t, s.Put("s1", "q1", map[string]string{}))
+	now = now.Add(time.Second)
+	_, ok := s.Get("s1", "q0")
+	require.True(t, ok)
+
+	now = now.Add(time.Second)
+	require.NoError(t, s.Put("s1", "q2", map[string]string{}))
+	for i, want := range []bool{true, false, true} {
+		_, ok := s.Get("s1", fmt.Sprintf("q%d", i))
+		assert.Equal(t, want, ok, "q%d", i)
+	}
+}
@@ -0,0 +1,22 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// callTool calls an access tool with client through mcptest.
+func callTool(t *testing.T, client k8s.Client, toolName string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	register := func(s *mcpserver.MCPServer, sc *server.ServerContext) error {
+		RegisterTools(s, sc)
+		return nil
+	}
+	return mcptest.CallRegisteredTool(t, client, register, toolName, args)
+}
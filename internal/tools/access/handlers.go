@@ -12,6 +12,7 @@ import (
 	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // CanIResponse represents the response from the can_i tool.
@@ -61,12 +62,12 @@ func HandleCanI(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 	// Extract required parameters
 	verb, ok := args["verb"].(string)
 	if !ok || verb == "" {
-		return mcp.NewToolResultError("verb is required"), nil
+		return toolerrors.Required("verb").Result(), nil
 	}
 
 	resource, ok := args["resource"].(string)
 	if !ok || resource == "" {
-		return mcp.NewToolResultError("resource is required"), nil
+		return toolerrors.Required("resource").Result(), nil
 	}
 
 	// Extract optional parameters
@@ -79,13 +80,13 @@ func HandleCanI(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 	// Check if federation is enabled
 	fedManager := sc.FederationManager()
 	if fedManager == nil {
-		return mcp.NewToolResultError("permission checks require federation mode to be enabled"), nil
+		return toolerrors.New(toolerrors.CodeNotEnabled, "permission checks require federation mode to be enabled").Result(), nil
 	}
 
 	// Get user info from OAuth context
 	userInfo, ok := oauth.UserInfoFromContext(ctx)
 	if !ok || userInfo == nil {
-		return mcp.NewToolResultError("authentication required: no user info in context"), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, "authentication required: no user info in context").Result(), nil
 	}
 
 	// Convert OAuth user info to federation UserInfo using the helper function
@@ -106,11 +107,11 @@ func HandleCanI(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 	if err != nil {
 		// Check if it's a validation error
 		if isValidationError(err) {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid request: %v", err)), nil
+			return toolerrors.InvalidArgumentf("invalid request: %v", err).Result(), nil
 		}
 		// For other errors, provide a generic message
 		sc.Logger().Error("Access check failed", "error", err)
-		return mcp.NewToolResultError("failed to check permissions - please try again"), nil
+		return toolerrors.New(toolerrors.CodeUnavailable, "failed to check permissions - please try again").Result(), nil
 	}
 
 	// Build the response
@@ -148,7 +149,7 @@ func HandleCanI(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 	// Marshal the response to JSON
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		return toolerrors.Internalf("failed to format response: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -0,0 +1,624 @@
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// rbacAPIGroup is the API group of Roles, ClusterRoles and their
+	// bindings.
+	rbacAPIGroup = "rbac.authorization.k8s.io"
+
+	// rbacListPageSize is the page size used to list roles and bindings.
+	rbacListPageSize = 500
+
+	// defaultRBACLimit is the default cap on the subjects or rules returned.
+	defaultRBACLimit = 100
+
+	// maxRBACLimit caps the limit argument.
+	maxRBACLimit = 1000
+
+	// clusterScope is the scope of grants from ClusterRoleBindings.
+	clusterScope = "cluster"
+)
+
+// Groups every authenticated user and service account belongs to.
+const (
+	groupAuthenticated   = "system:authenticated"
+	groupServiceAccounts = "system:serviceaccounts"
+)
+
+// RBACGrant is one binding that grants a role.
+type RBACGrant struct {
+	// Binding is ClusterRoleBinding/name or RoleBinding/namespace/name.
+	Binding string `json:"binding"`
+	// Role is ClusterRole/name or Role/namespace/name.
+	Role string `json:"role"`
+	// Scope is "cluster" for ClusterRoleBindings, otherwise the namespace
+	// of the RoleBinding.
+	Scope string `json:"scope"`
+	// As is the binding subject that matched, when it is a group the
+	// subject belongs to rather than the subject itself.
+	As string `json:"as,omitempty"`
+}
+
+// RBACSubject is a subject and the bindings that grant it access.
+type RBACSubject struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Grants    []RBACGrant `json:"grants"`
+}
+
+// WhoCanOutput is the response of the who_can tool.
+type WhoCanOutput struct {
+	Check *AccessCheckInfo `json:"check"`
+
+	// Subjects are sorted by kind, namespace and name.
+	Subjects      []RBACSubject `json:"subjects"`
+	TotalSubjects int           `json:"totalSubjects"`
+	Truncated     bool          `json:"truncated,omitempty"`
+
+	// Warnings lists bindings to roles that do not exist.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PermissionRule is a policy rule granted to a subject in one scope.
+type PermissionRule struct {
+	Scope           string   `json:"scope"`
+	Verbs           []string `json:"verbs"`
+	APIGroups       []string `json:"apiGroups,omitempty"`
+	Resources       []string `json:"resources,omitempty"`
+	ResourceNames   []string `json:"resourceNames,omitempty"`
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty"`
+
+	// Via lists the roles granting the rule.
+	Via []string `json:"via"`
+}
+
+// SubjectPermissionsOutput is the response of the subject_permissions tool.
+type SubjectPermissionsOutput struct {
+	Subject RBACSubject `json:"subject"`
+
+	// Groups are the groups whose bindings were included.
+	Groups []string `json:"groups,omitempty"`
+
+	// Rules are sorted by scope, cluster first, then by API group and
+	// resource, with non-resource URLs last.
+	Rules      []PermissionRule `json:"rules"`
+	TotalRules int              `json:"totalRules"`
+	Truncated  bool             `json:"truncated,omitempty"`
+
+	// Warnings lists bindings to roles that do not exist.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// rbacBinding is a RoleBinding or ClusterRoleBinding.
+type rbacBinding struct {
+	// namespace is empty for ClusterRoleBindings.
+	namespace string
+	name      string
+	roleRef   rbacv1.RoleRef
+	subjects  []rbacv1.Subject
+}
+
+func (b rbacBinding) String() string {
+	if b.namespace == "" {
+		return "ClusterRoleBinding/" + b.name
+	}
+	return "RoleBinding/" + b.namespace + "/" + b.name
+}
+
+func (b rbacBinding) scope() string {
+	if b.namespace == "" {
+		return clusterScope
+	}
+	return b.namespace
+}
+
+func (b rbacBinding) role() string {
+	if b.roleRef.Kind == "ClusterRole" {
+		return "ClusterRole/" + b.roleRef.Name
+	}
+	return "Role/" + b.namespace + "/" + b.roleRef.Name
+}
+
+// rbacSnapshot holds the roles and bindings of a cluster.
+type rbacSnapshot struct {
+	clusterRoles map[string][]rbacv1.PolicyRule
+	// roles are keyed by namespace/name.
+	roles    map[string][]rbacv1.PolicyRule
+	bindings []rbacBinding
+}
+
+// rules returns the rules of the role a binding grants, and false when the
+// role does not exist.
+func (s *rbacSnapshot) rules(b rbacBinding) ([]rbacv1.PolicyRule, bool) {
+	if b.roleRef.Kind == "ClusterRole" {
+		rules, ok := s.clusterRoles[b.roleRef.Name]
+		return rules, ok
+	}
+	rules, ok := s.roles[b.namespace+"/"+b.roleRef.Name]
+	return rules, ok
+}
+
+// missingRole returns the warning for a binding to a role that does not
+// exist.
+func missingRole(b rbacBinding) string {
+	return fmt.Sprintf("%s refers to %s, which does not exist", b, b.role())
+}
+
+// whoCanRequest holds the validated who_can tool arguments.
+type whoCanRequest struct {
+	kubeContext string
+	check       AccessCheckInfo
+	limit       int
+}
+
+// subjectPermissionsRequest holds the validated subject_permissions tool
+// arguments.
+type subjectPermissionsRequest struct {
+	kubeContext string
+	subject     rbacv1.Subject
+	groups      []string
+	namespace   string
+	limit       int
+}
+
+// HandleWhoCan handles the who_can tool: it lists the subjects whose
+// bindings allow an action, by evaluating the cluster's RBAC objects.
+func HandleWhoCan(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req, errMsg := parseWhoCanRequest(args)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	snapshot, err := loadRBAC(ctx, client.K8s(), req.kubeContext, req.check.Namespace)
+	if err != nil {
+		return tools.K8sError("Failed to list RBAC roles and bindings", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(whoCan(snapshot, req), "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("failed to format response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// HandleSubjectPermissions handles the subject_permissions tool: it expands
+// the rules granted to a user, group or service account.
+func HandleSubjectPermissions(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req, errMsg := parseSubjectPermissionsRequest(args)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	snapshot, err := loadRBAC(ctx, client.K8s(), req.kubeContext, req.namespace)
+	if err != nil {
+		return tools.K8sError("Failed to list RBAC roles and bindings", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(subjectPermissions(snapshot, req), "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("failed to format response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseWhoCanRequest validates the who_can tool arguments. The resource may
+// name a subresource as pods/exec.
+func parseWhoCanRequest(args map[string]interface{}) (whoCanRequest, string) {
+	req := whoCanRequest{limit: defaultRBACLimit}
+	req.kubeContext, _ = args["kubeContext"].(string)
+
+	verb, _ := args["verb"].(string)
+	if verb = strings.TrimSpace(strings.ToLower(verb)); verb == "" {
+		return req, "verb is required"
+	}
+	resource, _ := args["resource"].(string)
+	if resource = strings.TrimSpace(strings.ToLower(resource)); resource == "" {
+		return req, "resource is required"
+	}
+	subresource, _ := args["subresource"].(string)
+	if before, after, ok := strings.Cut(resource, "/"); ok {
+		if subresource != "" && subresource != after {
+			return req, "specify the subresource either in resource or in subresource, not both"
+		}
+		resource, subresource = before, after
+	}
+
+	req.check = AccessCheckInfo{Verb: verb, Resource: resource, Subresource: subresource}
+	req.check.APIGroup, _ = args["apiGroup"].(string)
+	req.check.Namespace, _ = args["namespace"].(string)
+	req.check.Name, _ = args["name"].(string)
+	if req.check.APIGroup == "core" {
+		req.check.APIGroup = ""
+	}
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > maxRBACLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", maxRBACLimit)
+		}
+		req.limit = int(v)
+	}
+	return req, ""
+}
+
+// parseSubjectPermissionsRequest validates the subject_permissions tool
+// arguments.
+func parseSubjectPermissionsRequest(args map[string]interface{}) (subjectPermissionsRequest, string) {
+	req := subjectPermissionsRequest{limit: defaultRBACLimit}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	kind, _ := args["subjectKind"].(string)
+	name, _ := args["subjectName"].(string)
+	subjectNamespace, _ := args["subjectNamespace"].(string)
+	if name == "" {
+		return req, "subjectName is required"
+	}
+	switch strings.ToLower(kind) {
+	case "user":
+		req.subject = rbacv1.Subject{Kind: rbacv1.UserKind, Name: name}
+	case "group":
+		req.subject = rbacv1.Subject{Kind: rbacv1.GroupKind, Name: name}
+	case "serviceaccount":
+		if subjectNamespace == "" {
+			return req, "subjectNamespace is required for a ServiceAccount"
+		}
+		req.subject = rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: subjectNamespace}
+	default:
+		return req, "subjectKind must be User, Group or ServiceAccount"
+	}
+
+	if groups, _ := args["groups"].(string); groups != "" {
+		if req.subject.Kind == rbacv1.GroupKind {
+			return req, "groups only apply to a User or ServiceAccount"
+		}
+		for _, group := range strings.Split(groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				req.groups = append(req.groups, group)
+			}
+		}
+	}
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > maxRBACLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", maxRBACLimit)
+		}
+		req.limit = int(v)
+	}
+	return req, ""
+}
+
+// loadRBAC lists the cluster's ClusterRoles and ClusterRoleBindings, and its
+// Roles and RoleBindings in the namespace, or in every namespace when it is
+// empty.
+func loadRBAC(ctx context.Context, client k8s.Client, kubeContext, namespace string) (*rbacSnapshot, error) {
+	s := &rbacSnapshot{
+		clusterRoles: map[string][]rbacv1.PolicyRule{},
+		roles:        map[string][]rbacv1.PolicyRule{},
+	}
+	err := listRBAC(ctx, client, kubeContext, "", "clusterroles", func(role *rbacv1.ClusterRole) {
+		s.clusterRoles[role.Name] = role.Rules
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listRBAC(ctx, client, kubeContext, "", "clusterrolebindings", func(b *rbacv1.ClusterRoleBinding) {
+		s.bindings = append(s.bindings, rbacBinding{name: b.Name, roleRef: b.RoleRef, subjects: b.Subjects})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listRBAC(ctx, client, kubeContext, namespace, "roles", func(role *rbacv1.Role) {
+		s.roles[role.Namespace+"/"+role.Name] = role.Rules
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listRBAC(ctx, client, kubeContext, namespace, "rolebindings", func(b *rbacv1.RoleBinding) {
+		s.bindings = append(s.bindings, rbacBinding{namespace: b.Namespace, name: b.Name, roleRef: b.RoleRef, subjects: b.Subjects})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// listRBAC lists every page of an RBAC resource type and passes each item,
+// converted to T, to fn.
+func listRBAC[T any](ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType string, fn func(*T)) error {
+	opts := k8s.ListOptions{Limit: rbacListPageSize, AllNamespaces: namespace == ""}
+	for {
+		resp, err := client.List(ctx, kubeContext, namespace, resourceType, rbacAPIGroup, opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			obj := new(T)
+			if err := fromObject(item, obj); err != nil {
+				return err
+			}
+			fn(obj)
+		}
+		if resp.Continue == "" {
+			return nil
+		}
+		opts.Continue = resp.Continue
+	}
+}
+
+// whoCan evaluates the bindings of a snapshot against an access check.
+func whoCan(s *rbacSnapshot, req whoCanRequest) *WhoCanOutput {
+	check := req.check
+	out := &WhoCanOutput{Check: &check, Subjects: []RBACSubject{}}
+
+	subjects := map[string]*RBACSubject{}
+	for _, b := range s.bindings {
+		if check.Namespace != "" && b.namespace != "" && b.namespace != check.Namespace {
+			continue
+		}
+		rules, ok := s.rules(b)
+		if !ok {
+			out.Warnings = append(out.Warnings, missingRole(b))
+			continue
+		}
+		if !rulesAllow(rules, check) {
+			continue
+		}
+		for _, subject := range b.subjects {
+			key := subject.Kind + "/" + subject.Namespace + "/" + subject.Name
+			entry, ok := subjects[key]
+			if !ok {
+				entry = &RBACSubject{Kind: subject.Kind, Name: subject.Name, Namespace: subject.Namespace}
+				subjects[key] = entry
+			}
+			entry.Grants = append(entry.Grants, RBACGrant{Binding: b.String(), Role: b.role(), Scope: b.scope()})
+		}
+	}
+
+	var results []RBACSubject
+	for _, subject := range subjects {
+		results = append(results, *subject)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	out.TotalSubjects = len(results)
+	if len(results) > req.limit {
+		results = results[:req.limit]
+		out.Truncated = true
+	}
+	out.Subjects = append(out.Subjects, results...)
+	sort.Strings(out.Warnings)
+	return out
+}
+
+// rulesAllow reports whether any of the rules allows the check, following
+// the matching of the Kubernetes RBAC authorizer. Rules limited to resource
+// names only match a check for one of those names.
+func rulesAllow(rules []rbacv1.PolicyRule, check AccessCheckInfo) bool {
+	for _, rule := range rules {
+		if !matches(rule.Verbs, check.Verb) || !matches(rule.APIGroups, check.APIGroup) {
+			continue
+		}
+		if !resourceMatches(rule.Resources, check.Resource, check.Subresource) {
+			continue
+		}
+		if len(rule.ResourceNames) > 0 && (check.Name == "" || !slices.Contains(rule.ResourceNames, check.Name)) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// resourceMatches matches a resource and subresource against the resources
+// of a rule: "*" matches everything, "*/sub" any resource's subresource.
+func resourceMatches(ruleResources []string, resource, subresource string) bool {
+	combined := resource
+	if subresource != "" {
+		combined += "/" + subresource
+	}
+	for _, r := range ruleResources {
+		if r == rbacv1.ResourceAll || r == combined || (subresource != "" && r == "*/"+subresource) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether values contains want or the "*" wildcard.
+func matches(values []string, want string) bool {
+	for _, v := range values {
+		if v == "*" || v == want {
+			return true
+		}
+	}
+	return false
+}
+
+// subjectGroups returns the groups whose bindings apply to a subject: the
+// given ones plus those Kubernetes adds to every authenticated user and
+// service account.
+func subjectGroups(req subjectPermissionsRequest) []string {
+	switch req.subject.Kind {
+	case rbacv1.GroupKind:
+		return nil
+	case rbacv1.ServiceAccountKind:
+		return append(append([]string{}, req.groups...), groupServiceAccounts, groupServiceAccounts+":"+req.subject.Namespace, groupAuthenticated)
+	default:
+		return append(append([]string{}, req.groups...), groupAuthenticated)
+	}
+}
+
+// subjectMatch returns whether a binding subject applies to the requested
+// subject and, when it matched through a group, that group.
+func subjectMatch(bound rbacv1.Subject, req subjectPermissionsRequest, groups map[string]bool) (bool, string) {
+	subject := req.subject
+	switch bound.Kind {
+	case rbacv1.GroupKind:
+		if subject.Kind == rbacv1.GroupKind {
+			return bound.Name == subject.Name, ""
+		}
+		if groups[bound.Name] {
+			return true, "Group/" + bound.Name
+		}
+	case rbacv1.UserKind:
+		switch subject.Kind {
+		case rbacv1.UserKind:
+			return bound.Name == subject.Name, ""
+		case rbacv1.ServiceAccountKind:
+			// Service accounts authenticate as this user name.
+			return bound.Name == "system:serviceaccount:"+subject.Namespace+":"+subject.Name, ""
+		}
+	case rbacv1.ServiceAccountKind:
+		return subject.Kind == rbacv1.ServiceAccountKind && bound.Name == subject.Name && bound.Namespace == subject.Namespace, ""
+	}
+	return false, ""
+}
+
+// subjectPermissions collects the rules the bindings of a snapshot grant to
+// a subject. Identical rules granted by several roles in the same scope are
+// merged.
+func subjectPermissions(s *rbacSnapshot, req subjectPermissionsRequest) *SubjectPermissionsOutput {
+	groups := subjectGroups(req)
+	groupSet := map[string]bool{}
+	for _, g := range groups {
+		groupSet[g] = true
+	}
+	out := &SubjectPermissionsOutput{
+		Subject: RBACSubject{Kind: req.subject.Kind, Name: req.subject.Name, Namespace: req.subject.Namespace, Grants: []RBACGrant{}},
+		Groups:  groups,
+		Rules:   []PermissionRule{},
+	}
+
+	rules := map[string]*PermissionRule{}
+	for _, b := range s.bindings {
+		if req.namespace != "" && b.namespace != "" && b.namespace != req.namespace {
+			continue
+		}
+		var matched bool
+		var as string
+		for _, bound := range b.subjects {
+			if ok, group := subjectMatch(bound, req, groupSet); ok {
+				matched, as = true, group
+				if group == "" {
+					break
+				}
+			}
+		}
+		if !matched {
+			continue
+		}
+		out.Subject.Grants = append(out.Subject.Grants, RBACGrant{Binding: b.String(), Role: b.role(), Scope: b.scope(), As: as})
+
+		roleRules, ok := s.rules(b)
+		if !ok {
+			out.Warnings = append(out.Warnings, missingRole(b))
+			continue
+		}
+		for _, rule := range roleRules {
+			p := PermissionRule{
+				Scope:           b.scope(),
+				Verbs:           rule.Verbs,
+				APIGroups:       rule.APIGroups,
+				Resources:       rule.Resources,
+				ResourceNames:   rule.ResourceNames,
+				NonResourceURLs: rule.NonResourceURLs,
+			}
+			if p.Scope != clusterScope && len(p.NonResourceURLs) > 0 {
+				// Non-resource URLs are only granted cluster-wide.
+				continue
+			}
+			key, _ := json.Marshal(p)
+			existing, ok := rules[string(key)]
+			if !ok {
+				existing = &p
+				rules[string(key)] = existing
+			}
+			if !slices.Contains(existing.Via, b.role()) {
+				existing.Via = append(existing.Via, b.role())
+			}
+		}
+	}
+
+	var results []PermissionRule
+	for _, rule := range rules {
+		results = append(results, *rule)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Scope != b.Scope {
+			if a.Scope == clusterScope || b.Scope == clusterScope {
+				return a.Scope == clusterScope
+			}
+			return a.Scope < b.Scope
+		}
+		if an, bn := len(a.NonResourceURLs) > 0, len(b.NonResourceURLs) > 0; an != bn {
+			return bn
+		}
+		if ag, bg := strings.Join(a.APIGroups, ","), strings.Join(b.APIGroups, ","); ag != bg {
+			return ag < bg
+		}
+		if ar, br := strings.Join(a.Resources, ","), strings.Join(b.Resources, ","); ar != br {
+			return ar < br
+		}
+		if au, bu := strings.Join(a.NonResourceURLs, ","), strings.Join(b.NonResourceURLs, ","); au != bu {
+			return au < bu
+		}
+		return strings.Join(a.Verbs, ",") < strings.Join(b.Verbs, ",")
+	})
+	out.TotalRules = len(results)
+	if len(results) > req.limit {
+		results = results[:req.limit]
+		out.Truncated = true
+	}
+	out.Rules = append(out.Rules, results...)
+	sort.Strings(out.Warnings)
+	return out
+}
+
+// fromObject converts a typed or unstructured object into a typed one.
+func fromObject(obj runtime.Object, into interface{}) error {
+	if obj == nil {
+		return fmt.Errorf("empty object")
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, into)
+}
@@ -0,0 +1,191 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// newRBACClient serves ClusterRoles, Roles and their bindings granting
+// pod, exec and secret access in the shop and billing namespaces.
+func newRBACClient() *mcptest.FakeClient {
+	podReader := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get", "list"}}}
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"clusterroles." + rbacAPIGroup: {
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}, Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+				{NonResourceURLs: []string{"*"}, Verbs: []string{"*"}},
+			}},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "pod-reader"}, Rules: podReader},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "exec"}, Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}},
+			}},
+		},
+		"clusterrolebindings." + rbacAPIGroup: {
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "platform"}},
+			},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "stale"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "removed"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}},
+			},
+		},
+		"roles." + rbacAPIGroup: {
+			&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "secret-reader"}, Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}, Verbs: []string{"get"}},
+			}},
+		},
+		"rolebindings." + rbacAPIGroup: {
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "devs"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "pod-reader"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.UserKind, Name: "alice"},
+					{Kind: rbacv1.ServiceAccountKind, Namespace: "shop", Name: "ci"},
+				},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "debuggers"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "exec"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:shop"}},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db-secret"},
+				RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "secret-reader"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "bob"}},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "billing", Name: "devs"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "pod-reader"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "carol"}},
+			},
+		},
+	})
+}
+
+func runWhoCan(t *testing.T, client *mcptest.FakeClient, args map[string]interface{}) WhoCanOutput {
+	t.Helper()
+	return mcptest.DecodeResult[WhoCanOutput](t, callTool(t, client, "who_can", args))
+}
+
+func runSubjectPermissions(t *testing.T, client *mcptest.FakeClient, args map[string]interface{}) SubjectPermissionsOutput {
+	t.Helper()
+	return mcptest.DecodeResult[SubjectPermissionsOutput](t, callTool(t, client, "subject_permissions", args))
+}
+
+func subjectNames(subjects []RBACSubject) []string {
+	var names []string
+	for _, s := range subjects {
+		names = append(names, s.Kind+"/"+s.Name)
+	}
+	return names
+}
+
+func TestWhoCan(t *testing.T) {
+	client := newRBACClient()
+	out := runWhoCan(t, client, map[string]interface{}{"verb": "list", "resource": "pods", "namespace": "shop"})
+
+	assert.Equal(t, "shop", client.Namespaces["rolebindings"])
+	assert.Equal(t, []string{"Group/platform", "ServiceAccount/ci", "User/alice"}, subjectNames(out.Subjects))
+	assert.Equal(t, 3, out.TotalSubjects)
+	assert.Equal(t, []RBACGrant{{Binding: "RoleBinding/shop/devs", Role: "ClusterRole/pod-reader", Scope: "shop"}}, out.Subjects[2].Grants)
+	assert.Equal(t, []string{"ClusterRoleBinding/stale refers to ClusterRole/removed, which does not exist"}, out.Warnings)
+
+	out = runWhoCan(t, client, map[string]interface{}{"verb": "list", "resource": "pods", "limit": float64(2)})
+	assert.Equal(t, 4, out.TotalSubjects, "RoleBindings in every namespace are evaluated without a namespace")
+	assert.True(t, out.Truncated)
+	assert.Len(t, out.Subjects, 2)
+}
+
+func TestWhoCan_SubresourcesAndNames(t *testing.T) {
+	client := newRBACClient()
+
+	out := runWhoCan(t, client, map[string]interface{}{"verb": "create", "resource": "pods/exec", "namespace": "shop"})
+	assert.Equal(t, []string{"Group/platform", "Group/system:serviceaccounts:shop"}, subjectNames(out.Subjects))
+
+	out = runWhoCan(t, client, map[string]interface{}{"verb": "get", "resource": "pods", "subresource": "log", "namespace": "shop"})
+	assert.Equal(t, []string{"Group/platform", "ServiceAccount/ci", "User/alice"}, subjectNames(out.Subjects))
+
+	out = runWhoCan(t, client, map[string]interface{}{"verb": "get", "resource": "secrets", "namespace": "shop"})
+	assert.Equal(t, []string{"Group/platform"}, subjectNames(out.Subjects), "rules limited to names need a name")
+
+	out = runWhoCan(t, client, map[string]interface{}{"verb": "get", "resource": "secrets", "namespace": "shop", "name": "db"})
+	assert.Equal(t, []string{"Group/platform", "User/bob"}, subjectNames(out.Subjects))
+}
+
+func TestSubjectPermissions(t *testing.T) {
+	client := newRBACClient()
+	out := runSubjectPermissions(t, client, map[string]interface{}{
+		"subjectKind":      "ServiceAccount",
+		"subjectName":      "ci",
+		"subjectNamespace": "shop",
+	})
+	assert.Equal(t, []string{"system:serviceaccounts", "system:serviceaccounts:shop", "system:authenticated"}, out.Groups)
+	assert.Equal(t, []RBACGrant{
+		{Binding: "RoleBinding/shop/devs", Role: "ClusterRole/pod-reader", Scope: "shop"},
+		{Binding: "RoleBinding/shop/debuggers", Role: "ClusterRole/exec", Scope: "shop", As: "Group/system:serviceaccounts:shop"},
+	}, out.Subject.Grants)
+
+	require.Equal(t, 2, out.TotalRules)
+	assert.Equal(t, PermissionRule{
+		Scope:     "shop",
+		Verbs:     []string{"create"},
+		APIGroups: []string{""},
+		Resources: []string{"pods/exec"},
+		Via:       []string{"ClusterRole/exec"},
+	}, out.Rules[1])
+	assert.Equal(t, []string{"pods", "pods/log"}, out.Rules[0].Resources)
+}
+
+func TestSubjectPermissions_UserGroups(t *testing.T) {
+	client := newRBACClient()
+	out := runSubjectPermissions(t, client, map[string]interface{}{
+		"subjectKind": "User",
+		"subjectName": "alice",
+		"groups":      "platform, dev",
+		"namespace":   "billing",
+	})
+	assert.Equal(t, []string{"platform", "dev", "system:authenticated"}, out.Groups)
+	require.Len(t, out.Subject.Grants, 2, "bindings in other namespaces are left out")
+	assert.Equal(t, "Group/platform", out.Subject.Grants[0].As)
+	assert.Equal(t, []string{"ClusterRoleBinding/stale refers to ClusterRole/removed, which does not exist"}, out.Warnings)
+
+	require.Equal(t, 2, out.TotalRules)
+	assert.Equal(t, "cluster", out.Rules[0].Scope)
+	assert.Equal(t, []string{"*"}, out.Rules[0].Resources)
+	assert.Equal(t, []string{"*"}, out.Rules[1].NonResourceURLs)
+}
+
+func TestParseRBACRequests(t *testing.T) {
+	_, errMsg := parseWhoCanRequest(map[string]interface{}{"verb": "get"})
+	assert.Equal(t, "resource is required", errMsg)
+
+	_, errMsg = parseWhoCanRequest(map[string]interface{}{"verb": "get", "resource": "pods/exec", "subresource": "log"})
+	assert.Contains(t, errMsg, "not both")
+
+	req, errMsg := parseWhoCanRequest(map[string]interface{}{"verb": "GET", "resource": "deployments", "apiGroup": "core"})
+	require.Empty(t, errMsg)
+	assert.Equal(t, "get", req.check.Verb)
+	assert.Empty(t, req.check.APIGroup)
+
+	_, errMsg = parseWhoCanRequest(map[string]interface{}{"verb": "get", "resource": "pods", "limit": float64(maxRBACLimit + 1)})
+	assert.Contains(t, errMsg, "limit must be between")
+
+	_, errMsg = parseSubjectPermissionsRequest(map[string]interface{}{"subjectKind": "ServiceAccount", "subjectName": "ci"})
+	assert.Contains(t, errMsg, "subjectNamespace is required")
+
+	_, errMsg = parseSubjectPermissionsRequest(map[string]interface{}{"subjectKind": "Group", "subjectName": "dev", "groups": "ops"})
+	assert.Contains(t, errMsg, "groups only apply")
+
+	_, errMsg = parseSubjectPermissionsRequest(map[string]interface{}{"subjectKind": "Robot", "subjectName": "r2"})
+	assert.Contains(t, errMsg, "subjectKind must be")
+}
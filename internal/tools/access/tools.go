@@ -1,6 +1,8 @@
 package access
 
 import (
+	"fmt"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
@@ -47,4 +49,72 @@ var CanITool = mcp.NewTool("can_i",
 // RegisterTools registers the access tools with the MCP server.
 func RegisterTools(mcpServer *server.MCPServer, sc *mcpserver.ServerContext) {
 	mcpServer.AddTool(CanITool, tools.WrapWithAuditLogging("can_i", HandleCanI, sc))
+
+	clusterContextParams := tools.AddClusterContextParams(sc)
+
+	whoCanOpts := []mcp.ToolOption{
+		mcp.WithDescription("List the users, groups and service accounts allowed to perform an action, " +
+			"by evaluating the cluster's Roles, ClusterRoles and their bindings. Unlike can_i, which checks " +
+			"the current user, this answers for every subject and names the bindings that grant the access."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("verb",
+			mcp.Required(),
+			mcp.Description("The action to check (get, list, watch, create, update, patch, delete)"),
+		),
+		mcp.WithString("resource",
+			mcp.Required(),
+			mcp.Description("The resource type to check (pods, deployments, secrets, etc.), optionally with a subresource as pods/exec"),
+		),
+		mcp.WithString("apiGroup",
+			mcp.Description("API group for the resource (empty for core resources, 'apps' for deployments, etc.)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to check (empty checks cluster-wide access and RoleBindings in every namespace)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Specific resource name, to include rules limited to resource names"),
+		),
+		mcp.WithString("subresource",
+			mcp.Description("Subresource to check (e.g., 'log', 'exec', 'portforward' for pods)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of subjects to return (default: %d, max: %d)", defaultRBACLimit, maxRBACLimit)),
+		),
+	}
+	whoCanOpts = append(whoCanOpts, clusterContextParams...)
+	mcpServer.AddTool(mcp.NewTool("who_can", whoCanOpts...), tools.WrapWithAuditLogging("who_can", HandleWhoCan, sc))
+
+	subjectPermissionsOpts := []mcp.ToolOption{
+		mcp.WithDescription("Expand the effective RBAC permissions of a user, group or service account: " +
+			"the rules of every Role and ClusterRole bound to it, directly or through its groups, merged per scope."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("subjectKind",
+			mcp.Required(),
+			mcp.Description("Kind of the subject"),
+			mcp.Enum("User", "Group", "ServiceAccount"),
+		),
+		mcp.WithString("subjectName",
+			mcp.Required(),
+			mcp.Description("Name of the user, group or service account"),
+		),
+		mcp.WithString("subjectNamespace",
+			mcp.Description("Namespace of the service account (required for a ServiceAccount)"),
+		),
+		mcp.WithString("groups",
+			mcp.Description("Comma-separated groups the user or service account belongs to, whose bindings are included too"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Only include permissions effective in this namespace (empty for every namespace)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of rules to return (default: %d, max: %d)", defaultRBACLimit, maxRBACLimit)),
+		),
+	}
+	subjectPermissionsOpts = append(subjectPermissionsOpts, clusterContextParams...)
+	mcpServer.AddTool(mcp.NewTool("subject_permissions", subjectPermissionsOpts...),
+		tools.WrapWithAuditLogging("subject_permissions", HandleSubjectPermissions, sc))
 }
@@ -3,12 +3,20 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // ToolHandler is the signature for MCP tool handler functions that take ServerContext.
@@ -21,6 +29,32 @@ type ToolHandler func(ctx context.Context, request mcp.CallToolRequest, sc *serv
 //   - Cluster and resource information from request arguments
 //   - Success/error status from the handler result
 //   - OpenTelemetry trace context for correlation
+//   - The impersonation override (impersonateUser / impersonateGroups), which
+//     is authorized here before the handler runs
+//   - Rejection of a kubeContext outside the configured kubeconfig contexts
+//   - Denials by the per-caller rate limiter, the tool authorization policy,
+//     the external authorizer (e.g. OPA) and label-restricted namespaces, all
+//     enforced here
+//   - The tool call timeout, which cancels the handler's context
+//   - Confirmation of destructive calls when enabled: the call is previewed
+//     as a dry-run and only runs once confirmed with the returned token
+//   - The replica ID, reported in the result's _meta when configured
+//   - A per-call dryRun argument, which makes the call's mutations
+//     server-side dry-runs; dry-run results are flagged in the result's _meta
+//   - The cluster and namespace defaults of the caller's MCP session, filled
+//     in for arguments the call leaves out and reported in the result's _meta
+//   - The caller's preferences: the output format, cluster and limit filled
+//     in for arguments the call and session defaults leave out, reported in
+//     the result's _meta, and fields masked by the output processor
+//   - A tool span covering the whole call, parent of the client
+//     acquisition, Kubernetes API and output processing spans
+//   - Per-tool metrics: call count, duration and result size, with the
+//     estimated tokens the result takes up in the model's context
+//   - Debug capture when enabled: the sanitized arguments and result are
+//     kept in a ring buffer operators can read
+//
+// Error results leave the wrapper finalized by toolerrors.Finalize, so every
+// failed call carries a structured error envelope with the trace ID.
 //
 // The wrapper logs tool invocations using the AuditLogger from the instrumentation provider.
 // If no instrumentation provider is available, the handler is called without audit logging.
@@ -30,52 +64,208 @@ func WrapWithAuditLogging(
 	sc *server.ServerContext,
 ) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		// Get the instrumentation provider
-		provider := sc.InstrumentationProvider()
-		if provider == nil || provider.AuditLogger() == nil {
-			// No audit logging available, just call the handler
-			return handler(ctx, request, sc)
+		ctx, span := instrumentation.StartToolSpan(ctx, toolName)
+		defer span.End()
+
+		request, sessionDefaults := withSessionDefaults(ctx, sc, toolName, request)
+		ctx, request, prefs := withPreferences(ctx, sc, toolName, request)
+		ctx = withDryRun(ctx, request.GetArguments())
+		start := time.Now()
+		result, err := invokeWithAuditLogging(ctx, toolName, handler, sc, request)
+		result = toolerrors.Finalize(result, instrumentation.GetTraceID(ctx))
+		result = withDryRunMeta(result, IsDryRun(ctx, sc))
+		result = withSessionDefaultsMeta(result, sessionDefaults)
+		result = withPreferencesMeta(result, prefs)
+		result = withTokenEstimate(ctx, sc, toolName, result)
+		recordToolMetrics(ctx, sc, toolName, time.Since(start), result, err)
+		recordDebugCapture(ctx, sc, toolName, request, start, result, err)
+		endToolSpan(span, result, err)
+		return withReplicaID(result, sc.ReplicaID()), err
+	}
+}
+
+// endToolSpan records the outcome and response size of a tool call on its
+// span.
+func endToolSpan(span trace.Span, result *mcp.CallToolResult, err error) {
+	span.SetAttributes(attribute.Int(instrumentation.SpanAttrResponseBytes, resultBytes(result)))
+	switch {
+	case err != nil:
+		instrumentation.SetSpanError(span, err)
+	case result != nil && result.IsError:
+		span.SetStatus(codes.Error, "tool returned an error result")
+	default:
+		instrumentation.SetSpanSuccess(span)
+	}
+}
+
+// recordToolMetrics records a finished tool call in the per-tool metrics.
+func recordToolMetrics(ctx context.Context, sc *server.ServerContext, toolName string, duration time.Duration, result *mcp.CallToolResult, err error) {
+	provider := sc.InstrumentationProvider()
+	if provider == nil || provider.Metrics() == nil {
+		return
+	}
+	status := instrumentation.StatusSuccess
+	if err != nil || (result != nil && result.IsError) {
+		status = instrumentation.StatusError
+	}
+	provider.Metrics().RecordToolInvocation(ctx, toolName, status, duration, resultBytes(result))
+}
+
+// resultBytes returns the size of the text a result puts into the model's
+// context: its text content and its structured content.
+func resultBytes(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			n += len(text.Text)
 		}
+	}
+	if result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			n += len(data)
+		}
+	}
+	return n
+}
 
-		auditLogger := provider.AuditLogger()
+// withReplicaID reports the replica that served a call in the result's
+// _meta, so that callers behind a load balancer can tell replicas apart.
+func withReplicaID(result *mcp.CallToolResult, replicaID string) *mcp.CallToolResult {
+	if result == nil || replicaID == "" {
+		return result
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields["replicaId"] = replicaID
+	return result
+}
+
+// invokeWithAuditLogging runs the checks and the handler of a tool call and
+// audit logs it; see WrapWithAuditLogging.
+func invokeWithAuditLogging(
+	ctx context.Context,
+	toolName string,
+	handler ToolHandler,
+	sc *server.ServerContext,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	// The caller's Kubernetes API requests draw from their own budget when
+	// a k8s.UserRateLimiter is configured, also when impersonating.
+	ctx = k8s.ContextWithRateLimitUser(ctx, rateLimitCaller(ctx).Identity())
 
-		// Create tool invocation with span context
-		invocation := instrumentation.NewToolInvocation(toolName).
-			WithSpanContext(ctx)
+	// Configured details of the call, such as the MCP client, are sent as
+	// impersonation extras to show up in the clusters' audit logs.
+	ctx = withImpersonationExtras(ctx, sc.ImpersonationExtras(), request)
 
-		// Extract user info from OAuth context
-		if user, ok := oauth.UserInfoFromContext(ctx); ok && user != nil {
-			invocation.WithUser(user.Email, user.Groups)
+	// Enforce the caller's rate limit first, then authorize an
+	// impersonation override so the handler only ever sees an identity
+	// the caller may act as.
+	args := request.GetArguments()
+	ctx = contextWithToolCall(ctx, toolName, args)
+	var impersonated *federation.UserInfo
+	var denyErr *toolerrors.Error
+	if msg := checkRateLimit(ctx, sc, toolName); msg != "" {
+		denyErr = toolerrors.New(toolerrors.CodeRateLimited, msg)
+	}
+	if denyErr == nil {
+		if msg := checkKubeContext(sc, args); msg != "" {
+			denyErr = toolerrors.New(toolerrors.CodeInvalidArgument, msg)
 		}
+	}
+	if denyErr == nil {
+		ctx, impersonated, denyErr = authorizeImpersonateAs(ctx, sc, args)
+	}
+	if denyErr == nil {
+		if msg := checkPolicy(ctx, sc, toolName, args); msg != "" {
+			denyErr = toolerrors.New(toolerrors.CodePolicyDenied, msg)
+		}
+	}
+	if denyErr == nil {
+		if msg := checkNamespaceAccess(ctx, sc, toolName, args); msg != "" {
+			denyErr = toolerrors.New(toolerrors.CodePolicyDenied, msg)
+		}
+	}
+	if denyErr == nil {
+		if msg := checkNamespaceLabels(ctx, sc, toolName, args); msg != "" {
+			denyErr = toolerrors.New(toolerrors.CodePolicyDenied, msg)
+		}
+	}
 
-		// Extract cluster and resource info from request arguments
-		args := request.GetArguments()
-		extractAuditInfoFromArgs(invocation, args)
-
-		// Execute the actual handler
-		result, err := handler(ctx, request, sc)
-
-		// Determine success/error status
-		if err != nil {
-			invocation.CompleteWithError(err)
-		} else if result != nil && result.IsError {
-			// MCP tool errors are returned in the result, not as Go errors
-			invocation.Complete(false, nil)
-			// Try to extract error message from result content
-			if len(result.Content) > 0 {
-				if textContent, ok := result.Content[0].(mcp.TextContent); ok {
-					invocation.Error = textContent.Text
-				}
-			}
-		} else {
-			invocation.CompleteSuccess()
+	// Get the instrumentation provider
+	provider := sc.InstrumentationProvider()
+	if provider == nil || provider.AuditLogger() == nil {
+		if denyErr != nil {
+			return denyErr.Result(), nil
 		}
+		// No audit logging available, just call the handler
+		return callOrPropose(ctx, toolName, handler, sc, request)
+	}
 
-		// Log the tool invocation (metrics-safe, uses cardinality-controlled values)
+	auditLogger := provider.AuditLogger()
+
+	// Create tool invocation with span context
+	invocation := instrumentation.NewToolInvocation(toolName).
+		WithSpanContext(ctx)
+
+	// Extract user info from OAuth context
+	if user, ok := oauth.UserInfoFromContext(ctx); ok && user != nil {
+		invocation.WithUser(user.Email, user.Groups)
+	}
+
+	// Extract cluster and resource info from request arguments
+	extractAuditInfoFromArgs(invocation, args)
+
+	if impersonated != nil {
+		invocation.WithImpersonation(impersonated.Email, impersonated.Groups)
+	} else if requested, _ := args[ParamImpersonateUser].(string); requested != "" {
+		// Record denied attempts too.
+		invocation.WithImpersonation(requested, nil)
+	}
+
+	if denyErr != nil {
+		invocation.Complete(false, nil)
+		invocation.Error = denyErr.Message
 		auditLogger.LogToolInvocation(invocation)
+		auditLogger.LogToolAudit(invocation)
+		return denyErr.Result(), nil
+	}
+
+	// Execute the actual handler
+	result, err := callOrPropose(ctx, toolName, handler, sc, request)
 
-		return result, err
+	// Determine success/error status
+	if err != nil {
+		invocation.CompleteWithError(err)
+	} else if result != nil && result.IsError {
+		// MCP tool errors are returned in the result, not as Go errors
+		invocation.Complete(false, nil)
+		// Try to extract error message from result content
+		if len(result.Content) > 0 {
+			if textContent, ok := result.Content[0].(mcp.TextContent); ok {
+				invocation.Error = textContent.Text
+			}
+		}
+	} else {
+		invocation.CompleteSuccess()
+	}
+
+	// Log the tool invocation (metrics-safe, uses cardinality-controlled values)
+	auditLogger.LogToolInvocation(invocation)
+
+	// Impersonated calls always get a full audit record naming both the
+	// caller and the identity they acted as.
+	if invocation.ImpersonatedUser != "" {
+		auditLogger.LogToolAudit(invocation)
 	}
+
+	return result, err
 }
 
 // extractAuditInfoFromArgs extracts cluster, namespace, and resource information
@@ -103,7 +293,7 @@ func extractAuditInfoFromArgs(invocation *instrumentation.ToolInvocation, args m
 // Different tools use different parameter names for the resource name.
 func extractResourceName(args map[string]interface{}) string {
 	// Try common parameter names for resource name
-	nameKeys := []string{"name", "podName", "resourceName", "pattern", "sessionID"}
+	nameKeys := []string{"name", "podName", "nodeName", "resourceName", "pattern", "sessionID"}
 	for _, key := range nameKeys {
 		if name, ok := args[key].(string); ok && name != "" {
 			return name
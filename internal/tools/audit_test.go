@@ -11,9 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
 )
 
@@ -159,6 +161,35 @@ func TestWrapWithAuditLogging_HandlesMCPToolError(t *testing.T) {
 	require.NoError(t, err) // No Go error
 	require.NotNil(t, result)
 	assert.True(t, result.IsError)
+
+	// Plain error results are given an INTERNAL envelope.
+	toolErr := toolerrors.FromResult(result)
+	require.NotNil(t, toolErr)
+	assert.Equal(t, toolerrors.CodeInternal, toolErr.Code)
+	assert.Equal(t, "tool error message", toolErr.Message)
+	require.Len(t, result.Content, 2)
+	assert.Contains(t, result.Content[1].(mcp.TextContent).Text, `"code":"INTERNAL"`)
+}
+
+func TestWrapWithAuditLogging_KeepsStructuredError(t *testing.T) {
+	provider := createTestProvider(t)
+	sc := createTestServerContext(t, provider)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+
+	wrapped := WrapWithAuditLogging("test_tool", handler, sc)
+
+	result, err := wrapped(context.Background(), createTestRequest(nil))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	toolErr := toolerrors.FromResult(result)
+	require.NotNil(t, toolErr)
+	assert.Equal(t, toolerrors.CodeInvalidArgument, toolErr.Code)
+	assert.Equal(t, toolerrors.CategoryInvalidArgument, toolErr.Category)
+	assert.Equal(t, "namespace is required", result.Content[0].(mcp.TextContent).Text)
 }
 
 func TestWrapWithAuditLogging_NoProvider(t *testing.T) {
@@ -180,6 +211,102 @@ func TestWrapWithAuditLogging_NoProvider(t *testing.T) {
 	assert.False(t, result.IsError)
 }
 
+func TestWrapWithAuditLogging_ReportsReplicaID(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("success"), nil
+	}
+
+	sc := newVisibilityServerContext(t, server.WithReplicaID("mcp-0"))
+	result, err := WrapWithAuditLogging("test_tool", handler, sc)(context.Background(), createTestRequest(nil))
+	require.NoError(t, err)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, "mcp-0", result.Meta.AdditionalFields["replicaId"])
+
+	// Without a replica ID results carry none.
+	sc = newVisibilityServerContext(t)
+	result, err = WrapWithAuditLogging("test_tool", handler, sc)(context.Background(), createTestRequest(nil))
+	require.NoError(t, err)
+	assert.NotContains(t, result.Meta.AdditionalFields, "replicaId")
+}
+
+func TestWrapWithAuditLogging_DebugCapture(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("token: Bearer abcdefghijklmnopqrstuvwxyz0123456789"), nil
+	}
+
+	recorder := capture.NewRecorder(5, 0)
+	sc := newVisibilityServerContext(t, server.WithDebugCapture(recorder))
+	_, err := WrapWithAuditLogging("test_tool", handler, sc)(context.Background(), createTestRequest(map[string]interface{}{
+		"namespace": "prod",
+		"password":  "hunter2",
+	}))
+	require.NoError(t, err)
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "test_tool", entries[0].Tool)
+	assert.Contains(t, entries[0].Arguments, `"namespace":"prod"`)
+	assert.NotContains(t, entries[0].Arguments, "hunter2")
+	assert.NotContains(t, entries[0].Result, "abcdefghijklmnopqrstuvwxyz0123456789")
+	assert.False(t, entries[0].IsError)
+}
+
+func TestWrapWithAuditLogging_DryRun(t *testing.T) {
+	var dryRun bool
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		dryRun = IsDryRun(ctx, sc)
+		return mcp.NewToolResultText("success"), nil
+	}
+
+	sc := newVisibilityServerContext(t)
+	wrapped := WrapWithAuditLogging("test_tool", handler, sc)
+
+	result, err := wrapped(context.Background(), createTestRequest(map[string]interface{}{ParamDryRun: true}))
+	require.NoError(t, err)
+	assert.True(t, dryRun)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, true, result.Meta.AdditionalFields[ParamDryRun])
+
+	result, err = wrapped(context.Background(), createTestRequest(map[string]interface{}{ParamDryRun: false}))
+	require.NoError(t, err)
+	assert.False(t, dryRun)
+	assert.NotContains(t, result.Meta.AdditionalFields, ParamDryRun)
+
+	// dryRun=false cannot turn off the server's dry-run mode.
+	sc = newVisibilityServerContext(t, server.WithDryRun(true))
+	result, err = WrapWithAuditLogging("test_tool", handler, sc)(context.Background(), createTestRequest(map[string]interface{}{ParamDryRun: false}))
+	require.NoError(t, err)
+	assert.True(t, dryRun)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, true, result.Meta.AdditionalFields[ParamDryRun])
+}
+
+func TestWrapWithAuditLogging_RejectsDisallowedKubeContext(t *testing.T) {
+	called := false
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("success"), nil
+	}
+
+	sc := newVisibilityServerContext(t, server.WithKubeconfigContexts([]string{"kind-dev", "kind-staging"}))
+	wrapped := WrapWithAuditLogging("test_tool", handler, sc)
+
+	result, err := wrapped(context.Background(), createTestRequest(map[string]interface{}{"kubeContext": "prod"}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.False(t, called)
+	toolErr := toolerrors.FromResult(result)
+	require.NotNil(t, toolErr)
+	assert.Equal(t, toolerrors.CodeInvalidArgument, toolErr.Code)
+	assert.Contains(t, toolErr.Message, "kind-dev, kind-staging")
+
+	for _, kubeContext := range []string{"kind-staging", ""} {
+		result, err = wrapped(context.Background(), createTestRequest(map[string]interface{}{"kubeContext": kubeContext}))
+		require.NoError(t, err)
+		assert.False(t, result.IsError, "kubeContext %q", kubeContext)
+	}
+}
+
 func TestExtractAuditInfoFromArgs(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -336,6 +463,35 @@ func TestExtractResourceName(t *testing.T) {
 	}
 }
 
+func TestWrapWithAuditLogging_RecordsToolMetrics(t *testing.T) {
+	provider := createTestProvider(t)
+	sc := createTestServerContext(t, provider)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pods"), nil
+	}
+	wrapped := WrapWithAuditLogging("list", handler, sc)
+
+	result, err := wrapped(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	// Recording must not fail without instrumentation either.
+	wrapped = WrapWithAuditLogging("list", handler, createTestServerContextNoInstrumentation(t))
+	_, err = wrapped(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+}
+
+func TestResultBytes(t *testing.T) {
+	assert.Equal(t, 0, resultBytes(nil))
+	assert.Equal(t, 4, resultBytes(mcp.NewToolResultText("pods")))
+
+	result := mcp.NewToolResultText("ok")
+	result.Content = append(result.Content, mcp.NewImageContent("aGk=", "image/png"))
+	result.StructuredContent = map[string]any{"a": 1}
+	assert.Equal(t, len("ok")+len(`{"a":1}`), resultBytes(result), "only text and structured content count")
+}
+
 // Helper functions
 
 func createTestProvider(t *testing.T) *instrumentation.Provider {
@@ -0,0 +1,450 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+var (
+	// appGVR is the GroupVersionResource of Giant Swarm App objects.
+	appGVR = schema.GroupVersionResource{
+		Group:    "application.giantswarm.io",
+		Version:  "v1alpha1",
+		Resource: "apps",
+	}
+
+	// appCatalogEntryGVR is the GroupVersionResource of Giant Swarm
+	// AppCatalogEntry objects, one per app version published in a Catalog.
+	appCatalogEntryGVR = schema.GroupVersionResource{
+		Group:    "application.giantswarm.io",
+		Version:  "v1alpha1",
+		Resource: "appcatalogentries",
+	}
+)
+
+// Labels of Giant Swarm AppCatalogEntry objects.
+const (
+	// labelCatalog names the Catalog an entry belongs to.
+	labelCatalog = "application.giantswarm.io/catalog"
+
+	// labelAppName names the app of an entry.
+	labelAppName = "app.kubernetes.io/name"
+
+	// labelLatest marks the entry of the latest version of an app.
+	labelLatest = "latest"
+)
+
+// defaultCatalogNamespace is the namespace of the Catalogs an App names
+// without spec.catalogNamespace.
+const defaultCatalogNamespace = "default"
+
+// maxAvailableVersions caps the newer versions capi_get_app reports.
+const maxAvailableVersions = 10
+
+// handleListApps handles the capi_list_apps tool request.
+// It lists the Giant Swarm Apps installed in a workload cluster with their
+// release status and the latest version in their catalog.
+func handleListApps(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	status, _ := args["status"].(string)
+	upgradableOnly, _ := args["upgradableOnly"].(bool)
+
+	limit := DefaultMaxResults
+	if limitArg, ok := args["limit"].(float64); ok && limitArg > 0 {
+		limit = int(limitArg)
+		if limit > MaxResultsLimit {
+			limit = MaxResultsLimit
+		}
+	}
+
+	apps, err := listClusterApps(ctx, dynamicClient, cluster)
+	if err != nil {
+		return handleCAPIObjectError(err, "list apps")
+	}
+
+	catalog := newCatalogVersions(dynamicClient)
+	output := AppListOutput{
+		Cluster:   cluster.Name,
+		Namespace: cluster.Namespace,
+		Apps:      make([]AppItem, 0, len(apps)),
+		Statuses:  make(map[string]int),
+	}
+	for i := range apps {
+		app := appFromUnstructured(&apps[i])
+		if status != "" && !strings.EqualFold(app.Status, status) {
+			continue
+		}
+		catalog.annotate(ctx, &app, appCatalogNamespace(&apps[i]))
+		if upgradableOnly && !app.UpgradeAvailable {
+			continue
+		}
+		output.Statuses[app.Status]++
+		output.Apps = append(output.Apps, app)
+	}
+
+	output.TotalCount = len(output.Apps)
+	if len(output.Apps) > limit {
+		output.Apps = output.Apps[:limit]
+		output.Truncated = true
+	}
+	output.ReturnedCount = len(output.Apps)
+
+	return formatJSONResult(output)
+}
+
+// handleGetApp handles the capi_get_app tool request.
+// It returns an App of a workload cluster with its status and the newer
+// versions its catalog offers.
+func handleGetApp(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return toolerrors.InvalidArgument("name parameter is required").Result(), nil
+	}
+
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	obj, errResult := getClusterApp(ctx, dynamicClient, cluster, name)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	app := appFromUnstructured(obj)
+	catalog := newCatalogVersions(dynamicClient)
+	catalog.annotate(ctx, &app, appCatalogNamespace(obj))
+
+	output := AppDetailOutput{
+		AppItem:          app,
+		Cluster:          cluster.Name,
+		Namespace:        cluster.Namespace,
+		CatalogNamespace: appCatalogNamespace(obj),
+		Reason:           firstNestedString(obj.Object, []string{"status", "release", "reason"}),
+		LastDeployed:     firstNestedString(obj.Object, []string{"status", "release", "lastDeployed"}),
+		ConfigMaps:       appConfigRefs(obj, "configMap"),
+		Secrets:          appConfigRefs(obj, "secret"),
+		Conditions:       problemConditions(obj.Object),
+	}
+	if entries, err := catalog.entries(ctx, output.CatalogNamespace, app.Catalog, app.App); err == nil {
+		output.AvailableVersions = newerVersions(entries, app.Version)
+	}
+
+	return formatJSONResult(output)
+}
+
+// handleUpgradeApp handles the capi_upgrade_app tool request.
+// It upgrades an App of a workload cluster by setting spec.version of the
+// App object to a version published in its catalog. The app operator then
+// rolls out the new version.
+func handleUpgradeApp(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := tools.CheckMutatingOperation(sc, "patch"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return toolerrors.InvalidArgument("name parameter is required").Result(), nil
+	}
+	target, ok := args["version"].(string)
+	if !ok || target == "" {
+		return toolerrors.InvalidArgument("version parameter is required").Result(), nil
+	}
+
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	obj, errResult := getClusterApp(ctx, dynamicClient, cluster, name)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// The App lives in the organization namespace on the management
+	// cluster, which the call's own checks do not see.
+	mcArgs := maps.Clone(args)
+	delete(mcArgs, "cluster")
+	if msg := tools.ObjectWriteDenied(ctx, sc, "capi_upgrade_app", mcArgs, cluster.Namespace, obj); msg != "" {
+		return toolerrors.New(toolerrors.CodePolicyDenied, msg).Result(), nil
+	}
+
+	app := appFromUnstructured(obj)
+	output := AppUpgradeOutput{
+		Cluster:         cluster.Name,
+		Namespace:       cluster.Namespace,
+		Name:            name,
+		App:             app.App,
+		Catalog:         app.Catalog,
+		PreviousVersion: app.Version,
+		Version:         target,
+		DryRun:          tools.IsDryRun(ctx, sc),
+	}
+	if app.Version == target {
+		output.Message = fmt.Sprintf("App %q already has version %s", name, target)
+		return formatJSONResult(output)
+	}
+
+	// The version is checked against the catalog when its entries can be
+	// read, so that a typo does not leave the App failing to install.
+	// Catalogs that are not indexed as AppCatalogEntries are not checked.
+	catalog := newCatalogVersions(dynamicClient)
+	entries, err := catalog.entries(ctx, appCatalogNamespace(obj), app.Catalog, app.App)
+	if err == nil && len(entries) > 0 {
+		if !hasVersion(entries, target) {
+			return toolerrors.Newf(toolerrors.CodeInvalidArgument,
+				"version %s of app %q is not published in catalog %q. Use capi_get_app to see the available versions.",
+				target, app.App, app.Catalog).Result(), nil
+		}
+		output.VersionVerified = true
+	}
+
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"version": target}})
+	if err != nil {
+		return toolerrors.Internalf("failed to build patch: %v", err).Result(), nil
+	}
+	opts := metav1.PatchOptions{}
+	if output.DryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err := dynamicClient.Resource(appGVR).Namespace(cluster.Namespace).Patch(ctx, name, types.MergePatchType, patch, opts); err != nil {
+		return handleCAPIObjectError(err, "patch app")
+	}
+
+	verb := "Upgrade"
+	if output.DryRun {
+		verb = "Dry-run: upgrade"
+	}
+	output.Message = fmt.Sprintf("%s of app %q from %s to %s requested; use capi_get_app to follow the rollout", verb, name, displayAppVersion(app.Version), target)
+	if !output.VersionVerified {
+		output.Message += ". The catalog entries could not be read, so the version was not verified"
+	}
+	return formatJSONResult(output)
+}
+
+// listClusterApps lists the Apps of the cluster, sorted by name. Apps live
+// in the cluster's organization namespace and belong to the cluster by the
+// giantswarm.io/cluster label or by deploying with its kubeconfig.
+func listClusterApps(ctx context.Context, dynamicClient dynamic.Interface, cluster *federation.ClusterSummary) ([]unstructured.Unstructured, error) {
+	list, err := dynamicClient.Resource(appGVR).Namespace(cluster.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []unstructured.Unstructured
+	for _, item := range list.Items {
+		if appBelongsToCluster(&item, cluster) {
+			apps = append(apps, item)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].GetName() < apps[j].GetName() })
+	return apps, nil
+}
+
+// getClusterApp returns the App of the cluster with the given name. On
+// failure it returns the tool result to report instead.
+func getClusterApp(ctx context.Context, dynamicClient dynamic.Interface, cluster *federation.ClusterSummary, name string) (*unstructured.Unstructured, *mcp.CallToolResult) {
+	obj, err := dynamicClient.Resource(appGVR).Namespace(cluster.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil && !appBelongsToCluster(obj, cluster) {
+		// Only report apps of the requested cluster, even when the
+		// organization namespace holds several clusters.
+		err = apierrors.NewNotFound(appGVR.GroupResource(), name)
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, toolerrors.Newf(toolerrors.CodeNotFound, "App %q not found in cluster %q. Use capi_list_apps to see its apps.", name, cluster.Name).Result()
+	}
+	if err != nil {
+		result, _ := handleCAPIObjectError(err, "get app")
+		return nil, result
+	}
+	return obj, nil
+}
+
+// appBelongsToCluster reports whether the App deploys to the cluster.
+func appBelongsToCluster(app *unstructured.Unstructured, cluster *federation.ClusterSummary) bool {
+	if app.GetLabels()[federation.LabelGiantSwarmCluster] == cluster.Name {
+		return true
+	}
+	secret := firstNestedString(app.Object, []string{"spec", "kubeConfig", "secret", "name"})
+	return secret == cluster.Name+"-kubeconfig"
+}
+
+// appFromUnstructured converts a Giant Swarm App to an AppItem.
+func appFromUnstructured(app *unstructured.Unstructured) AppItem {
+	status := firstNestedString(app.Object, []string{"status", "release", "status"})
+	if status == "" {
+		status = AppStatusUnknown
+	}
+	return AppItem{
+		Name:            app.GetName(),
+		App:             firstNestedString(app.Object, []string{"spec", "name"}),
+		Catalog:         firstNestedString(app.Object, []string{"spec", "catalog"}),
+		Version:         firstNestedString(app.Object, []string{"spec", "version"}),
+		DeployedVersion: firstNestedString(app.Object, []string{"status", "version"}),
+		AppVersion:      firstNestedString(app.Object, []string{"status", "appVersion"}),
+		TargetNamespace: firstNestedString(app.Object, []string{"spec", "namespace"}),
+		Status:          status,
+		Age:             formatAge(time.Since(app.GetCreationTimestamp().Time)),
+	}
+}
+
+// appCatalogNamespace returns the namespace of the Catalog the App installs from.
+func appCatalogNamespace(app *unstructured.Unstructured) string {
+	if ns := firstNestedString(app.Object, []string{"spec", "catalogNamespace"}); ns != "" {
+		return ns
+	}
+	return defaultCatalogNamespace
+}
+
+// appConfigRefs returns the ConfigMaps or Secrets configuring the App, as
+// namespace/name. kind is configMap or secret.
+func appConfigRefs(app *unstructured.Unstructured, kind string) []string {
+	var refs []string
+	for _, path := range [][]string{
+		{"spec", "config", kind},
+		{"spec", "userConfig", kind},
+	} {
+		name := firstNestedString(app.Object, append(path, "name"))
+		if name == "" {
+			continue
+		}
+		ns := firstNestedString(app.Object, append(path, "namespace"))
+		refs = append(refs, ns+"/"+name)
+	}
+	return refs
+}
+
+// catalogVersions reads the versions of apps published in catalogs, caching
+// them per app for the duration of a tool call.
+type catalogVersions struct {
+	dynamicClient dynamic.Interface
+	cache         map[string][]unstructured.Unstructured
+}
+
+// newCatalogVersions creates a catalogVersions reading with dynamicClient.
+func newCatalogVersions(dynamicClient dynamic.Interface) *catalogVersions {
+	return &catalogVersions{dynamicClient: dynamicClient, cache: make(map[string][]unstructured.Unstructured)}
+}
+
+// entries lists the AppCatalogEntries of app in catalog.
+func (c *catalogVersions) entries(ctx context.Context, namespace, catalog, app string) ([]unstructured.Unstructured, error) {
+	if catalog == "" || app == "" {
+		return nil, fmt.Errorf("app has no catalog")
+	}
+	key := namespace + "/" + catalog + "/" + app
+	if entries, ok := c.cache[key]; ok {
+		return entries, nil
+	}
+	list, err := c.dynamicClient.Resource(appCatalogEntryGVR).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{labelCatalog: catalog, labelAppName: app}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.cache[key] = list.Items
+	return list.Items, nil
+}
+
+// annotate sets the latest version of the App in its catalog, whose
+// entries are in namespace. It is left empty when the catalog cannot be
+// read, for example without access to it.
+func (c *catalogVersions) annotate(ctx context.Context, app *AppItem, namespace string) {
+	entries, err := c.entries(ctx, namespace, app.Catalog, app.App)
+	if err != nil {
+		return
+	}
+	app.LatestVersion = latestVersion(entries)
+	app.UpgradeAvailable = versionLess(app.Version, app.LatestVersion)
+}
+
+// latestVersion returns the version of the entry labelled latest, or else
+// the highest semantic version among the entries.
+func latestVersion(entries []unstructured.Unstructured) string {
+	latest := ""
+	for i := range entries {
+		v := firstNestedString(entries[i].Object, []string{"spec", "version"})
+		if entries[i].GetLabels()[labelLatest] == "true" {
+			return v
+		}
+		if latest == "" || versionLess(latest, v) {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// newerVersions returns the versions among the entries that are newer than
+// current, newest first, capped at maxAvailableVersions.
+func newerVersions(entries []unstructured.Unstructured, current string) []string {
+	var newer []string
+	for i := range entries {
+		v := firstNestedString(entries[i].Object, []string{"spec", "version"})
+		if versionLess(current, v) {
+			newer = append(newer, v)
+		}
+	}
+	sort.Slice(newer, func(i, j int) bool { return versionLess(newer[j], newer[i]) })
+	if len(newer) > maxAvailableVersions {
+		newer = newer[:maxAvailableVersions]
+	}
+	return newer
+}
+
+// hasVersion reports whether one of the entries publishes version v.
+func hasVersion(entries []unstructured.Unstructured, v string) bool {
+	for i := range entries {
+		if firstNestedString(entries[i].Object, []string{"spec", "version"}) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// versionLess reports whether semantic version a is lower than b. Versions
+// that do not parse are never lower or higher than another.
+func versionLess(a, b string) bool {
+	va, err := version.ParseSemantic(strings.TrimPrefix(a, "v"))
+	if err != nil {
+		return false
+	}
+	vb, err := version.ParseSemantic(strings.TrimPrefix(b, "v"))
+	if err != nil {
+		return false
+	}
+	return va.LessThan(vb)
+}
+
+// displayAppVersion formats an app version for messages.
+func displayAppVersion(v string) string {
+	if v == "" {
+		return "no version"
+	}
+	return v
+}
@@ -0,0 +1,245 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/capi/testdata"
+)
+
+// appObject creates a Giant Swarm App in org-acme.
+func appObject(name string, labels map[string]interface{}, spec, status map[string]interface{}) *unstructured.Unstructured {
+	app := capiObject("App", name, labels, spec, status)
+	app.SetAPIVersion("application.giantswarm.io/v1alpha1")
+	return app
+}
+
+// catalogEntry creates an AppCatalogEntry of app in the giantswarm catalog.
+func catalogEntry(app, version string, latest bool) *unstructured.Unstructured {
+	labels := map[string]interface{}{labelCatalog: "giantswarm", labelAppName: app}
+	if latest {
+		labels[labelLatest] = "true"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "application.giantswarm.io/v1alpha1",
+		"kind":       "AppCatalogEntry",
+		"metadata": map[string]interface{}{
+			"name":      "giantswarm-" + app + "-" + version,
+			"namespace": "giantswarm",
+			"labels":    labels,
+		},
+		"spec": map[string]interface{}{"appName": app, "version": version},
+	}}
+}
+
+// newAppsServerContext returns a server context whose federation manager
+// serves the given App objects from the Management Cluster, and the fake
+// dynamic client to inspect them.
+func newAppsServerContext(t *testing.T, opts []server.Option, objects ...runtime.Object) (*server.ServerContext, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			appGVR:             "AppList",
+			appCatalogEntryGVR: "AppCatalogEntryList",
+		}, objects...)
+
+	opts = append([]server.Option{
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithFederationManager(&testdata.MockFederationManager{
+			ClusterDetails: testdata.CreateTestClusterDetailsMap(),
+			DynamicClient:  dynamicClient,
+		}),
+	}, opts...)
+	sc, err := server.NewServerContext(context.Background(), opts...)
+	require.NoError(t, err)
+	return sc, dynamicClient
+}
+
+func testApps() []runtime.Object {
+	return []runtime.Object{
+		appObject("prod-wc-01-cert-manager", map[string]interface{}{federation.LabelGiantSwarmCluster: "prod-wc-01"},
+			map[string]interface{}{
+				"name": "cert-manager-app", "catalog": "giantswarm", "catalogNamespace": "giantswarm",
+				"version": "3.8.0", "namespace": "kube-system",
+				"config": map[string]interface{}{"configMap": map[string]interface{}{"name": "prod-wc-01-cluster-values", "namespace": "org-acme"}},
+			},
+			map[string]interface{}{
+				"version": "3.8.0", "appVersion": "v1.14.2",
+				"release": map[string]interface{}{"status": "deployed", "lastDeployed": "2024-02-01T00:00:00Z"},
+			}),
+		// Belongs to the cluster by its kubeconfig.
+		appObject("prod-wc-01-ingress", nil,
+			map[string]interface{}{
+				"name": "ingress-nginx", "catalog": "giantswarm", "catalogNamespace": "giantswarm", "version": "3.0.0",
+				"kubeConfig": map[string]interface{}{"secret": map[string]interface{}{"name": "prod-wc-01-kubeconfig"}},
+			},
+			map[string]interface{}{
+				"release": map[string]interface{}{"status": "failed", "reason": "chart not found"},
+			}),
+		appObject("other-cluster-app", map[string]interface{}{federation.LabelGiantSwarmCluster: "staging-wc-01"},
+			map[string]interface{}{"name": "cert-manager-app", "catalog": "giantswarm", "version": "3.8.0"},
+			nil),
+		catalogEntry("cert-manager-app", "3.8.0", false),
+		catalogEntry("cert-manager-app", "3.9.0", false),
+		catalogEntry("cert-manager-app", "3.10.1", true),
+		catalogEntry("ingress-nginx", "3.0.0", true),
+	}
+}
+
+func TestHandleListApps(t *testing.T) {
+	sc, _ := newAppsServerContext(t, nil, testApps()...)
+
+	result := callCAPITool(t, handleListApps, sc, map[string]interface{}{"cluster": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output AppListOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+
+	assert.Equal(t, "prod-wc-01", output.Cluster)
+	assert.Equal(t, 2, output.TotalCount)
+	require.Len(t, output.Apps, 2)
+	assert.Equal(t, map[string]int{"deployed": 1, "failed": 1}, output.Statuses)
+
+	certManager := output.Apps[0]
+	assert.Equal(t, "prod-wc-01-cert-manager", certManager.Name)
+	assert.Equal(t, "cert-manager-app", certManager.App)
+	assert.Equal(t, "3.8.0", certManager.Version)
+	assert.Equal(t, "3.8.0", certManager.DeployedVersion)
+	assert.Equal(t, "v1.14.2", certManager.AppVersion)
+	assert.Equal(t, "kube-system", certManager.TargetNamespace)
+	assert.Equal(t, "3.10.1", certManager.LatestVersion)
+	assert.True(t, certManager.UpgradeAvailable)
+
+	ingress := output.Apps[1]
+	assert.Equal(t, "prod-wc-01-ingress", ingress.Name)
+	assert.Equal(t, "failed", ingress.Status)
+	assert.Equal(t, "3.0.0", ingress.LatestVersion)
+	assert.False(t, ingress.UpgradeAvailable)
+}
+
+func TestHandleListApps_Filters(t *testing.T) {
+	sc, _ := newAppsServerContext(t, nil, testApps()...)
+
+	result := callCAPITool(t, handleListApps, sc, map[string]interface{}{"cluster": "prod-wc-01", "status": "Failed"})
+	var output AppListOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	require.Len(t, output.Apps, 1)
+	assert.Equal(t, "prod-wc-01-ingress", output.Apps[0].Name)
+
+	result = callCAPITool(t, handleListApps, sc, map[string]interface{}{"cluster": "prod-wc-01", "upgradableOnly": true})
+	output = AppListOutput{}
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	require.Len(t, output.Apps, 1)
+	assert.Equal(t, "prod-wc-01-cert-manager", output.Apps[0].Name)
+}
+
+func TestHandleGetApp(t *testing.T) {
+	sc, _ := newAppsServerContext(t, nil, testApps()...)
+
+	result := callCAPITool(t, handleGetApp, sc, map[string]interface{}{"cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output AppDetailOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Equal(t, "giantswarm", output.CatalogNamespace)
+	assert.Equal(t, "2024-02-01T00:00:00Z", output.LastDeployed)
+	assert.Equal(t, []string{"org-acme/prod-wc-01-cluster-values"}, output.ConfigMaps)
+	assert.Equal(t, []string{"3.10.1", "3.9.0"}, output.AvailableVersions)
+
+	// Apps of other clusters in the organization namespace are not found.
+	result = callCAPITool(t, handleGetApp, sc, map[string]interface{}{"cluster": "prod-wc-01", "name": "other-cluster-app"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), "not found")
+}
+
+func TestHandleUpgradeApp(t *testing.T) {
+	sc, dynamicClient := newAppsServerContext(t, []server.Option{server.WithNonDestructiveMode(false)}, testApps()...)
+
+	result := callCAPITool(t, handleUpgradeApp, sc, map[string]interface{}{
+		"cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager", "version": "3.9.0",
+	})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output AppUpgradeOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Equal(t, "3.8.0", output.PreviousVersion)
+	assert.Equal(t, "3.9.0", output.Version)
+	assert.True(t, output.VersionVerified)
+	assert.False(t, output.DryRun)
+
+	app, err := dynamicClient.Resource(appGVR).Namespace("org-acme").Get(context.Background(), "prod-wc-01-cert-manager", metav1.GetOptions{})
+	require.NoError(t, err)
+	v, _, _ := unstructured.NestedString(app.Object, "spec", "version")
+	assert.Equal(t, "3.9.0", v)
+}
+
+func TestHandleUpgradeApp_DryRun(t *testing.T) {
+	sc, dynamicClient := newAppsServerContext(t, []server.Option{server.WithDryRun(true)}, testApps()...)
+
+	result := callCAPITool(t, handleUpgradeApp, sc, map[string]interface{}{
+		"cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager", "version": "3.9.0",
+	})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output AppUpgradeOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.True(t, output.DryRun)
+	assert.Contains(t, output.Message, "Dry-run")
+
+	actions := dynamicClient.Actions()
+	require.NotEmpty(t, actions)
+	patch, ok := actions[len(actions)-1].(clienttesting.PatchActionImpl)
+	require.True(t, ok)
+	assert.Equal(t, "apps", patch.GetResource().Resource)
+	assert.Equal(t, []string{metav1.DryRunAll}, patch.PatchOptions.DryRun)
+}
+
+func TestHandleUpgradeApp_Errors(t *testing.T) {
+	sc, _ := newAppsServerContext(t, []server.Option{server.WithNonDestructiveMode(false)}, testApps()...)
+
+	// Versions missing from the catalog are rejected.
+	result := callCAPITool(t, handleUpgradeApp, sc, map[string]interface{}{
+		"cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager", "version": "9.9.9",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), "not published in catalog")
+
+	result = callCAPITool(t, handleUpgradeApp, sc, map[string]interface{}{
+		"cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), "version parameter is required")
+
+	// Non-destructive mode blocks the upgrade.
+	sc, _ = newAppsServerContext(t, nil, testApps()...)
+	result = callCAPITool(t, handleUpgradeApp, sc, map[string]interface{}{
+		"cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager", "version": "3.9.0",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), "non-destructive mode")
+}
+
+func TestLatestVersion(t *testing.T) {
+	entries := []unstructured.Unstructured{
+		*catalogEntry("app", "1.2.0", false),
+		*catalogEntry("app", "1.10.0", false),
+		*catalogEntry("app", "not-semver", false),
+	}
+	assert.Equal(t, "1.10.0", latestVersion(entries))
+
+	entries = append(entries, *catalogEntry("app", "1.5.0", true))
+	assert.Equal(t, "1.5.0", latestVersion(entries))
+}
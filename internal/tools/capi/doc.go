@@ -6,6 +6,10 @@
 //   - Get detailed information about specific clusters
 //   - Resolve fuzzy cluster name patterns
 //   - Check cluster health status
+//   - Inspect the Machines and node pools of a cluster
+//   - Follow the progress of a cluster upgrade
+//   - Follow a cluster's conditions and events over time
+//   - Inspect and upgrade the Giant Swarm Apps installed in a cluster
 //
 // # Security Model
 //
@@ -14,6 +18,10 @@
 // identity, ensuring that users can only see and interact with clusters they
 // have permission to access.
 //
+// capi_upgrade_app is the only tool that changes objects. Like the other
+// patching tools it is not registered in non-destructive mode unless patch is
+// an allowed operation, and it honours dry-run mode.
+//
 // # Requirements
 //
 // These tools require:
@@ -42,4 +50,32 @@
 // Check cluster health:
 //
 //	capi_cluster_health { "name": "prod-wc-01" }
+//
+// Check that the cluster's API server actually answers:
+//
+//	capi_cluster_health { "name": "prod-wc-01", "probe": true }
+//
+// List the machines of a node pool:
+//
+//	capi_list_machines { "cluster": "prod-wc-01", "nodePool": "prod-wc-01-worker" }
+//
+// List node pools:
+//
+//	capi_list_nodepools { "cluster": "prod-wc-01" }
+//
+// Check whether an upgrade is done:
+//
+//	capi_upgrade_status { "cluster": "prod-wc-01" }
+//
+// Diagnose a provisioning failure:
+//
+//	capi_cluster_events { "cluster": "prod-wc-01", "warningsOnly": true }
+//
+// List the apps of a cluster that have a newer version:
+//
+//	capi_list_apps { "cluster": "prod-wc-01", "upgradableOnly": true }
+//
+// Upgrade an app, previewing the change first:
+//
+//	capi_upgrade_app { "cluster": "prod-wc-01", "name": "prod-wc-01-cert-manager", "version": "3.9.0", "dryRun": true }
 package capi
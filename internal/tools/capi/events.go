@@ -0,0 +1,272 @@
+package capi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// handleClusterEvents handles the capi_cluster_events tool request.
+// It merges the conditions of the Cluster, its control plane and its
+// infrastructure cluster with the Management Cluster events of the
+// cluster's CAPI objects into one timeline.
+func handleClusterEvents(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	warningsOnly, _ := args["warningsOnly"].(bool)
+
+	limit := DefaultMaxResults
+	if limitArg, ok := args["limit"].(float64); ok && limitArg > 0 {
+		limit = int(limitArg)
+		if limit > MaxResultsLimit {
+			limit = MaxResultsLimit
+		}
+	}
+
+	// resolveClusterScope has already checked the user.
+	user, _ := getUserFromContext(ctx)
+	client, err := sc.FederationManager().GetClient(ctx, "", user)
+	if err != nil {
+		return handleFederationError(err, "access management cluster")
+	}
+
+	clusterObj, err := dynamicClient.Resource(federation.CAPIClusterGVR).Namespace(cluster.Namespace).Get(ctx, cluster.Name, metav1.GetOptions{})
+	if err != nil {
+		return handleCAPIObjectError(err, "get cluster")
+	}
+
+	output := ClusterEventsOutput{
+		Cluster:   cluster.Name,
+		Namespace: cluster.Namespace,
+	}
+
+	// involved holds the Kind/name of every object whose events belong to
+	// the cluster.
+	involved := map[string]bool{}
+	var timeline []TimelineEntry
+
+	objects := []*unstructured.Unstructured{clusterObj}
+	for _, refField := range []string{"controlPlaneRef", "infrastructureRef"} {
+		obj, err := getReferencedObject(ctx, client.Discovery(), dynamicClient, clusterObj, refField)
+		if err != nil {
+			output.Unavailable = append(output.Unavailable, fmt.Sprintf("spec.%s: %v", refField, err))
+			continue
+		}
+		if obj != nil {
+			objects = append(objects, obj)
+		}
+	}
+	for _, obj := range objects {
+		ref := obj.GetKind() + "/" + obj.GetName()
+		involved[ref] = true
+		timeline = append(timeline, conditionEntries(obj, ref)...)
+	}
+
+	// Events of the cluster's Machines and node pools are part of the
+	// provisioning story too.
+	machines, err := listClusterObjects(ctx, dynamicClient, federation.CAPIMachineGVR, cluster, nil)
+	if err != nil {
+		output.Unavailable = append(output.Unavailable, "machines: "+errorSummary(err))
+	}
+	for i := range machines {
+		involved["Machine/"+machines[i].GetName()] = true
+	}
+	pools, err := listNodePools(ctx, dynamicClient, cluster)
+	if err != nil {
+		output.Unavailable = append(output.Unavailable, "node pools: "+errorSummary(err))
+	}
+	for _, pool := range pools {
+		involved[pool.kind+"/"+pool.obj.GetName()] = true
+	}
+
+	events, err := client.CoreV1().Events(cluster.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		output.Unavailable = append(output.Unavailable, "events: "+errorSummary(err))
+	} else {
+		for i := range events.Items {
+			event := &events.Items[i]
+			ref := event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+			if involved[ref] {
+				timeline = append(timeline, eventEntry(event, ref))
+			}
+		}
+	}
+
+	if warningsOnly {
+		filtered := timeline[:0]
+		for _, entry := range timeline {
+			if isWarningEntry(entry) {
+				filtered = append(filtered, entry)
+			}
+		}
+		timeline = filtered
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.After(timeline[j].Time)
+	})
+
+	output.TotalCount = len(timeline)
+	if len(timeline) > limit {
+		timeline = timeline[:limit]
+		output.Truncated = true
+	}
+	output.Timeline = timeline
+	if output.Timeline == nil {
+		output.Timeline = []TimelineEntry{}
+	}
+
+	return formatJSONResult(output)
+}
+
+// getReferencedObject fetches the object referenced by spec.<refField> of
+// a CAPI Cluster. It returns nil if the reference is not set.
+//
+// v1beta2 references carry apiGroup and kind, so the served version is
+// looked up with discovery; v1beta1 references carry apiVersion.
+func getReferencedObject(ctx context.Context, disco discovery.DiscoveryInterface, dynamicClient dynamic.Interface, clusterObj *unstructured.Unstructured, refField string) (*unstructured.Unstructured, error) {
+	ref, found, err := unstructured.NestedStringMap(clusterObj.Object, "spec", refField)
+	if err != nil || !found || ref["kind"] == "" || ref["name"] == "" {
+		return nil, nil
+	}
+
+	var gv schema.GroupVersion
+	if apiVersion := ref["apiVersion"]; apiVersion != "" {
+		gv, err = schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiVersion %q", apiVersion)
+		}
+	} else {
+		gv, err = preferredGroupVersion(disco, ref["apiGroup"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resources, err := disco.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		return nil, fmt.Errorf("%s is not served", gv)
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind != ref["kind"] {
+			continue
+		}
+		obj, err := dynamicClient.Resource(gv.WithResource(resource.Name)).Namespace(clusterObj.GetNamespace()).Get(ctx, ref["name"], metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %s", ref["kind"], ref["name"], errorSummary(err))
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("kind %s is not served by %s", ref["kind"], gv)
+}
+
+// preferredGroupVersion returns the version of an API group the server prefers.
+func preferredGroupVersion(disco discovery.DiscoveryInterface, group string) (schema.GroupVersion, error) {
+	groups, err := disco.ServerGroups()
+	if err != nil {
+		return schema.GroupVersion{}, fmt.Errorf("discovery failed: %s", errorSummary(err))
+	}
+	for _, g := range groups.Groups {
+		if g.Name == group {
+			return schema.ParseGroupVersion(g.PreferredVersion.GroupVersion)
+		}
+	}
+	return schema.GroupVersion{}, fmt.Errorf("API group %q is not served", group)
+}
+
+// conditionEntries converts the status.conditions of an object to timeline entries.
+func conditionEntries(obj *unstructured.Unstructured, ref string) []TimelineEntry {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return nil
+	}
+
+	entries := make([]TimelineEntry, 0, len(conditions))
+	for _, c := range conditions {
+		condMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry := TimelineEntry{
+			Source:  TimelineSourceCondition,
+			Object:  ref,
+			Type:    firstNestedString(condMap, []string{"type"}),
+			Status:  firstNestedString(condMap, []string{"status"}),
+			Reason:  firstNestedString(condMap, []string{"reason"}),
+			Message: firstNestedString(condMap, []string{"message"}),
+		}
+		if t, err := time.Parse(time.RFC3339, firstNestedString(condMap, []string{"lastTransitionTime"})); err == nil {
+			entry.Time = t
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// eventEntry converts an event to a timeline entry, using the most recent
+// of the times an event may carry.
+func eventEntry(event *corev1.Event, ref string) TimelineEntry {
+	entry := TimelineEntry{
+		Source:  TimelineSourceEvent,
+		Object:  ref,
+		Type:    event.Type,
+		Reason:  event.Reason,
+		Message: event.Message,
+		Count:   int(event.Count),
+	}
+
+	switch {
+	case event.Series != nil:
+		entry.Time = event.Series.LastObservedTime.Time
+		entry.Count = int(event.Series.Count)
+	case !event.LastTimestamp.IsZero():
+		entry.Time = event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		entry.Time = event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		entry.Time = event.FirstTimestamp.Time
+	default:
+		entry.Time = event.CreationTimestamp.Time
+	}
+	return entry
+}
+
+// isWarningEntry reports whether an entry is a Warning event or a condition
+// that is not in its normal state.
+func isWarningEntry(entry TimelineEntry) bool {
+	if entry.Source == TimelineSourceEvent {
+		return entry.Type == corev1.EventTypeWarning
+	}
+	return isProblemCondition(entry.Type, entry.Status)
+}
+
+// errorSummary returns a short description of an API error for partial
+// results. Like handleFederationError, it does not pass on error details.
+func errorSummary(err error) string {
+	switch {
+	case apierrors.IsForbidden(err):
+		return "access denied"
+	case apierrors.IsNotFound(err), meta.IsNoMatchError(err):
+		return "not found"
+	default:
+		return "an unexpected error occurred"
+	}
+}
@@ -0,0 +1,165 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/capi/testdata"
+)
+
+var (
+	kcpGVR        = schema.GroupVersionResource{Group: "controlplane.cluster.x-k8s.io", Version: "v1beta2", Resource: "kubeadmcontrolplanes"}
+	awsClusterGVR = schema.GroupVersionResource{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta2", Resource: "awsclusters"}
+)
+
+func condition(conditionType, status, reason, message string, at time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"type":               conditionType,
+		"status":             status,
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": at.UTC().Format(time.RFC3339),
+	}
+}
+
+func withAPIVersion(obj *unstructured.Unstructured, apiVersion string) *unstructured.Unstructured {
+	obj.SetAPIVersion(apiVersion)
+	return obj
+}
+
+func event(name, kind, objName, eventType, reason string, at time.Time) runtime.Object {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "org-acme"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objName},
+		Type:           eventType,
+		Reason:         reason,
+		LastTimestamp:  metav1.NewTime(at),
+		Count:          2,
+	}
+}
+
+// newEventsServerContext serves a prod-wc-01 cluster whose infrastructure
+// cluster is not readable.
+func newEventsServerContext(t *testing.T) *server.ServerContext {
+	t.Helper()
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	clusterObj := capiObject("Cluster", "prod-wc-01", nil,
+		map[string]interface{}{
+			"controlPlaneRef":   map[string]interface{}{"apiGroup": "controlplane.cluster.x-k8s.io", "kind": "KubeadmControlPlane", "name": "prod-wc-01"},
+			"infrastructureRef": map[string]interface{}{"apiGroup": "infrastructure.cluster.x-k8s.io", "kind": "AWSCluster", "name": "prod-wc-01"},
+		},
+		map[string]interface{}{"conditions": []interface{}{
+			condition("InfrastructureReady", "False", "NotReady", "AWSCluster is not ready", base),
+		}})
+	kcp := withAPIVersion(capiObject("KubeadmControlPlane", "prod-wc-01", nil, nil,
+		map[string]interface{}{"conditions": []interface{}{
+			condition("Available", "True", "Available", "", base.Add(time.Minute)),
+		}}), "controlplane.cluster.x-k8s.io/v1beta2")
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			federation.CAPIClusterGVR:           "ClusterList",
+			federation.CAPIMachineGVR:           "MachineList",
+			federation.CAPIMachineDeploymentGVR: "MachineDeploymentList",
+			federation.CAPIMachinePoolGVR:       "MachinePoolList",
+			kcpGVR:                              "KubeadmControlPlaneList",
+			awsClusterGVR:                       "AWSClusterList",
+		},
+		withAPIVersion(clusterObj, "cluster.x-k8s.io/v1beta2"), kcp,
+		capiObject("Machine", "prod-wc-01-worker-a", machineLabels("prod-wc-01", "prod-wc-01-worker"), nil, nil),
+	)
+
+	client := fake.NewClientset(
+		event("e1", "Machine", "prod-wc-01-worker-a", corev1.EventTypeWarning, "FailedCreate", base.Add(2*time.Minute)),
+		event("e2", "KubeadmControlPlane", "prod-wc-01", corev1.EventTypeNormal, "ScalingUp", base.Add(-time.Minute)),
+		event("e3", "Machine", "other-cluster-worker", corev1.EventTypeWarning, "FailedCreate", base),
+	)
+	client.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "controlplane.cluster.x-k8s.io/v1beta2", APIResources: []metav1.APIResource{{Name: "kubeadmcontrolplanes", Kind: "KubeadmControlPlane", Namespaced: true}}},
+		{GroupVersion: "infrastructure.cluster.x-k8s.io/v1beta2", APIResources: []metav1.APIResource{{Name: "awsclusters", Kind: "AWSCluster", Namespaced: true}}},
+	}
+
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithFederationManager(&testdata.MockFederationManager{
+			ClusterDetails: testdata.CreateTestClusterDetailsMap(),
+			Client:         client,
+			DynamicClient:  dynamicClient,
+		}),
+	)
+	require.NoError(t, err)
+	return sc
+}
+
+func TestHandleClusterEvents(t *testing.T) {
+	sc := newEventsServerContext(t)
+
+	result := callCAPITool(t, handleClusterEvents, sc, map[string]interface{}{"cluster": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output ClusterEventsOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+
+	assert.Equal(t, 4, output.TotalCount)
+	var got []string
+	for _, entry := range output.Timeline {
+		got = append(got, entry.Source+" "+entry.Object+" "+entry.Type)
+	}
+	assert.Equal(t, []string{
+		"event Machine/prod-wc-01-worker-a Warning",
+		"condition KubeadmControlPlane/prod-wc-01 Available",
+		"condition Cluster/prod-wc-01 InfrastructureReady",
+		"event KubeadmControlPlane/prod-wc-01 Normal",
+	}, got)
+	assert.Equal(t, 2, output.Timeline[0].Count)
+	assert.Equal(t, []string{"spec.infrastructureRef: AWSCluster/prod-wc-01: not found"}, output.Unavailable)
+}
+
+func TestHandleClusterEvents_WarningsOnlyAndLimit(t *testing.T) {
+	sc := newEventsServerContext(t)
+
+	result := callCAPITool(t, handleClusterEvents, sc, map[string]interface{}{
+		"cluster":      "prod-wc-01",
+		"warningsOnly": true,
+		"limit":        float64(1),
+	})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output ClusterEventsOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Equal(t, 2, output.TotalCount)
+	assert.True(t, output.Truncated)
+	require.Len(t, output.Timeline, 1)
+	assert.Equal(t, "FailedCreate", output.Timeline[0].Reason)
+}
+
+func TestEventEntry_Time(t *testing.T) {
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	entry := eventEntry(&corev1.Event{FirstTimestamp: metav1.NewTime(first), LastTimestamp: metav1.NewTime(last)}, "Cluster/a")
+	assert.Equal(t, last, entry.Time.UTC())
+
+	entry = eventEntry(&corev1.Event{
+		EventTime: metav1.NewMicroTime(first),
+		Series:    &corev1.EventSeries{Count: 5, LastObservedTime: metav1.NewMicroTime(last)},
+	}, "Cluster/a")
+	assert.Equal(t, last, entry.Time.UTC())
+	assert.Equal(t, 5, entry.Count)
+}
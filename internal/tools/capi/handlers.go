@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"k8s.io/apimachinery/pkg/labels"
@@ -13,6 +14,7 @@ import (
 	"github.com/giantswarm/mcp-kubernetes/internal/federation"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // Generic error messages that don't reveal internal architecture details.
@@ -43,13 +45,13 @@ func handleListClusters(ctx context.Context, request mcp.CallToolRequest, sc *se
 	// Get federation manager
 	fedManager := sc.FederationManager()
 	if fedManager == nil {
-		return mcp.NewToolResultError(errOperationNotAvailable), nil
+		return toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result(), nil
 	}
 
 	// Get authenticated user
 	user, err := getUserFromContext(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(errAuthRequired), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, errAuthRequired).Result(), nil
 	}
 
 	// Extract filter parameters
@@ -119,20 +121,20 @@ func handleGetCluster(ctx context.Context, request mcp.CallToolRequest, sc *serv
 	// Get federation manager
 	fedManager := sc.FederationManager()
 	if fedManager == nil {
-		return mcp.NewToolResultError(errOperationNotAvailable), nil
+		return toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result(), nil
 	}
 
 	// Get authenticated user
 	user, err := getUserFromContext(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(errAuthRequired), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, errAuthRequired).Result(), nil
 	}
 
 	// Extract required name parameter
 	args := request.GetArguments()
 	name, ok := args["name"].(string)
 	if !ok || name == "" {
-		return mcp.NewToolResultError("name parameter is required"), nil
+		return toolerrors.InvalidArgument("name parameter is required").Result(), nil
 	}
 
 	// Get cluster summary
@@ -153,20 +155,20 @@ func handleResolveCluster(ctx context.Context, request mcp.CallToolRequest, sc *
 	// Get federation manager
 	fedManager := sc.FederationManager()
 	if fedManager == nil {
-		return mcp.NewToolResultError(errOperationNotAvailable), nil
+		return toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result(), nil
 	}
 
 	// Get authenticated user
 	user, err := getUserFromContext(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(errAuthRequired), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, errAuthRequired).Result(), nil
 	}
 
 	// Extract required pattern parameter
 	args := request.GetArguments()
 	pattern, ok := args["pattern"].(string)
 	if !ok || pattern == "" {
-		return mcp.NewToolResultError("pattern parameter is required"), nil
+		return toolerrors.InvalidArgument("pattern parameter is required").Result(), nil
 	}
 
 	// Get all clusters and resolve the pattern
@@ -237,20 +239,20 @@ func handleClusterHealth(ctx context.Context, request mcp.CallToolRequest, sc *s
 	// Get federation manager
 	fedManager := sc.FederationManager()
 	if fedManager == nil {
-		return mcp.NewToolResultError(errOperationNotAvailable), nil
+		return toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result(), nil
 	}
 
 	// Get authenticated user
 	user, err := getUserFromContext(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(errAuthRequired), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, errAuthRequired).Result(), nil
 	}
 
 	// Extract required name parameter
 	args := request.GetArguments()
 	name, ok := args["name"].(string)
 	if !ok || name == "" {
-		return mcp.NewToolResultError("name parameter is required"), nil
+		return toolerrors.InvalidArgument("name parameter is required").Result(), nil
 	}
 
 	// Get cluster summary (contains status information)
@@ -262,6 +264,18 @@ func handleClusterHealth(ctx context.Context, request mcp.CallToolRequest, sc *s
 	// Build health output from cluster status
 	output := buildHealthOutput(cluster)
 
+	// Optionally check that the API server actually answers
+	if probe, _ := args["probe"].(bool); probe {
+		timeout := DefaultProbeTimeout
+		if seconds, ok := args["probeTimeout"].(float64); ok && seconds > 0 {
+			timeout = time.Duration(seconds * float64(time.Second))
+			if timeout > MaxProbeTimeout {
+				timeout = MaxProbeTimeout
+			}
+		}
+		applyAPIServerProbe(&output, probeClusterAPIServer(ctx, fedManager, cluster.Name, user, timeout))
+	}
+
 	return formatJSONResult(output)
 }
 
@@ -358,40 +372,40 @@ func handleFederationError(err error, operation string) (*mcp.CallToolResult, er
 	// Handle specific federation error types
 	var clusterNotFound *federation.ClusterNotFoundError
 	if errors.As(err, &clusterNotFound) {
-		return mcp.NewToolResultError(clusterNotFound.UserFacingError()), nil
+		return toolerrors.New(toolerrors.CodeClusterUnavailable, clusterNotFound.UserFacingError()).Result(), nil
 	}
 
 	var discoveryErr *federation.ClusterDiscoveryError
 	if errors.As(err, &discoveryErr) {
-		return mcp.NewToolResultError(discoveryErr.UserFacingError()), nil
+		return toolerrors.New(toolerrors.CodeUnavailable, discoveryErr.UserFacingError()).Result(), nil
 	}
 
 	var accessDeniedErr *federation.AccessDeniedError
 	if errors.As(err, &accessDeniedErr) {
-		return mcp.NewToolResultError(accessDeniedErr.UserFacingError()), nil
+		return toolerrors.New(toolerrors.CodeForbidden, accessDeniedErr.UserFacingError()).Result(), nil
 	}
 
 	// Handle sentinel errors with generic messages to prevent information disclosure.
 	// Security: These messages intentionally don't reveal internal system details.
 	switch {
 	case errors.Is(err, federation.ErrUserInfoRequired):
-		return mcp.NewToolResultError(errAuthRequired), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, errAuthRequired).Result(), nil
 	case errors.Is(err, federation.ErrManagerClosed):
-		return mcp.NewToolResultError(errServiceUnavailable), nil
+		return toolerrors.New(toolerrors.CodeUnavailable, errServiceUnavailable).Result(), nil
 	case errors.Is(err, federation.ErrCAPICRDNotInstalled):
 		// Intentionally generic - don't reveal whether CAPI is installed
-		return mcp.NewToolResultError(errOperationNotAvailable), nil
+		return toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result(), nil
 	}
 
 	// Generic error message that doesn't leak internal details
-	return mcp.NewToolResultError(fmt.Sprintf("failed to %s: an unexpected error occurred", operation)), nil
+	return toolerrors.Internalf("failed to %s: an unexpected error occurred", operation).Result(), nil
 }
 
 // formatJSONResult marshals the output to JSON and returns a tool result.
 func formatJSONResult(output interface{}) (*mcp.CallToolResult, error) {
 	jsonData, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to format output: %v", err)), nil
+		return toolerrors.Internalf("failed to format output: %v", err).Result(), nil
 	}
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
@@ -0,0 +1,430 @@
+package capi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Node pool kinds reported by capi_list_nodepools.
+const (
+	kindMachineDeployment = "MachineDeployment"
+	kindMachinePool       = "MachinePool"
+)
+
+// negativePolarityConditions are CAPI conditions that are normally False;
+// they are reported when True because they signal an ongoing change.
+var negativePolarityConditions = map[string]bool{
+	"Deleting":    true,
+	"Paused":      true,
+	"Remediating": true,
+	"RollingOut":  true,
+	"ScalingDown": true,
+	"ScalingUp":   true,
+}
+
+// handleListMachines handles the capi_list_machines tool request.
+// It lists the Machines of a workload cluster with their status.
+func handleListMachines(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	args := request.GetArguments()
+	nodePool, _ := args["nodePool"].(string)
+	phase, _ := args["phase"].(string)
+
+	limit := DefaultMaxResults
+	if limitArg, ok := args["limit"].(float64); ok && limitArg > 0 {
+		limit = int(limitArg)
+		if limit > MaxResultsLimit {
+			limit = MaxResultsLimit
+		}
+	}
+
+	machines, err := listClusterMachines(ctx, dynamicClient, cluster, nil)
+	if err != nil {
+		return handleCAPIObjectError(err, "list machines")
+	}
+
+	output := MachineListOutput{
+		Cluster:   cluster.Name,
+		Namespace: cluster.Namespace,
+		Machines:  make([]MachineItem, 0, len(machines)),
+		Phases:    make(map[string]int),
+	}
+	for _, machine := range machines {
+		if nodePool != "" && machine.NodePool != nodePool {
+			continue
+		}
+		if phase != "" && machine.Phase != phase {
+			continue
+		}
+		output.Phases[machine.Phase]++
+		output.Machines = append(output.Machines, machine)
+	}
+
+	output.TotalCount = len(output.Machines)
+	if len(output.Machines) > limit {
+		output.Machines = output.Machines[:limit]
+		output.Truncated = true
+	}
+	output.ReturnedCount = len(output.Machines)
+
+	return formatJSONResult(output)
+}
+
+// handleGetMachineDeployment handles the capi_get_machinedeployment tool request.
+// It returns a MachineDeployment of a workload cluster together with its Machines.
+func handleGetMachineDeployment(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return toolerrors.InvalidArgument("name parameter is required").Result(), nil
+	}
+
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	md, err := dynamicClient.Resource(federation.CAPIMachineDeploymentGVR).Namespace(cluster.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil && md.GetLabels()[federation.LabelCAPIClusterName] != cluster.Name {
+		// Only report deployments of the requested cluster, even when
+		// the organization namespace holds several clusters.
+		err = apierrors.NewNotFound(federation.CAPIMachineDeploymentGVR.GroupResource(), name)
+	}
+	if apierrors.IsNotFound(err) {
+		return toolerrors.Newf(toolerrors.CodeNotFound, "MachineDeployment %q not found in cluster %q. Use capi_list_nodepools to see its node pools.", name, cluster.Name).Result(), nil
+	}
+	if err != nil {
+		return handleCAPIObjectError(err, "get machine deployment")
+	}
+
+	machines, err := listClusterMachines(ctx, dynamicClient, cluster, map[string]string{federation.LabelCAPIDeploymentName: name})
+	if err != nil {
+		return handleCAPIObjectError(err, "list machines")
+	}
+
+	output := MachineDeploymentDetailOutput{
+		NodePoolItem:           nodePoolFromUnstructured(md, kindMachineDeployment),
+		Cluster:                cluster.Name,
+		Namespace:              cluster.Namespace,
+		RolloutStrategy:        firstNestedString(md.Object, []string{"spec", "rollout", "strategy", "type"}, []string{"spec", "strategy", "type"}),
+		InfrastructureTemplate: objectRef(md.Object, "spec", "template", "spec", "infrastructureRef"),
+		FailureDomain:          firstNestedString(md.Object, []string{"spec", "template", "spec", "failureDomain"}),
+		Machines:               machines,
+	}
+
+	return formatJSONResult(output)
+}
+
+// handleListNodePools handles the capi_list_nodepools tool request.
+// It lists the MachineDeployments and MachinePools of a workload cluster.
+func handleListNodePools(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	pools, err := listNodePools(ctx, dynamicClient, cluster)
+	if err != nil {
+		return handleCAPIObjectError(err, "list node pools")
+	}
+
+	output := NodePoolListOutput{
+		Cluster:   cluster.Name,
+		Namespace: cluster.Namespace,
+		NodePools: make([]NodePoolItem, 0, len(pools)),
+	}
+	for _, pool := range pools {
+		output.NodePools = append(output.NodePools, nodePoolFromUnstructured(pool.obj, pool.kind))
+	}
+
+	return formatJSONResult(output)
+}
+
+// nodePool is a MachineDeployment or MachinePool object.
+type nodePool struct {
+	obj  *unstructured.Unstructured
+	kind string
+}
+
+// listNodePools lists the MachineDeployments and MachinePools of the
+// cluster, sorted by name.
+func listNodePools(ctx context.Context, dynamicClient dynamic.Interface, cluster *federation.ClusterSummary) ([]nodePool, error) {
+	var pools []nodePool
+	for _, resource := range []struct {
+		gvr  schema.GroupVersionResource
+		kind string
+	}{
+		{federation.CAPIMachineDeploymentGVR, kindMachineDeployment},
+		{federation.CAPIMachinePoolGVR, kindMachinePool},
+	} {
+		items, err := listClusterObjects(ctx, dynamicClient, resource.gvr, cluster, nil)
+		if err != nil {
+			// MachinePools are an optional CAPI feature whose CRD may be absent.
+			if resource.kind == kindMachinePool && (apierrors.IsNotFound(err) || meta.IsNoMatchError(err)) {
+				continue
+			}
+			return nil, err
+		}
+		for i := range items {
+			pools = append(pools, nodePool{obj: &items[i], kind: resource.kind})
+		}
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].obj.GetName() < pools[j].obj.GetName()
+	})
+	return pools, nil
+}
+
+// resolveClusterScope looks up the workload cluster named by the cluster
+// parameter and returns it with a Management Cluster dynamic client acting
+// as the user. On failure it returns the tool result to report instead.
+func resolveClusterScope(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*federation.ClusterSummary, dynamic.Interface, *mcp.CallToolResult) {
+	fedManager := sc.FederationManager()
+	if fedManager == nil {
+		return nil, nil, toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result()
+	}
+
+	user, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, nil, toolerrors.New(toolerrors.CodeUnauthenticated, errAuthRequired).Result()
+	}
+
+	args := request.GetArguments()
+	clusterName, ok := args["cluster"].(string)
+	if !ok || clusterName == "" {
+		return nil, nil, toolerrors.InvalidArgument("cluster parameter is required").Result()
+	}
+
+	cluster, err := fedManager.GetClusterSummary(ctx, clusterName, user)
+	if err != nil {
+		result, _ := handleFederationError(err, "get cluster")
+		return nil, nil, result
+	}
+
+	dynamicClient, err := fedManager.GetDynamicClient(ctx, "", user)
+	if err != nil {
+		result, _ := handleFederationError(err, "access management cluster")
+		return nil, nil, result
+	}
+
+	return cluster, dynamicClient, nil
+}
+
+// listClusterObjects lists the objects of the given resource that belong to
+// the cluster, optionally narrowed by additional labels, sorted by name.
+func listClusterObjects(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, cluster *federation.ClusterSummary, extraLabels map[string]string) ([]unstructured.Unstructured, error) {
+	selector := labels.Set{federation.LabelCAPIClusterName: cluster.Name}
+	for k, v := range extraLabels {
+		selector[k] = v
+	}
+
+	list, err := dynamicClient.Resource(gvr).Namespace(cluster.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := list.Items
+	sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+	return items, nil
+}
+
+// listClusterMachines lists the Machines of the cluster as MachineItems.
+func listClusterMachines(ctx context.Context, dynamicClient dynamic.Interface, cluster *federation.ClusterSummary, extraLabels map[string]string) ([]MachineItem, error) {
+	items, err := listClusterObjects(ctx, dynamicClient, federation.CAPIMachineGVR, cluster, extraLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	machines := make([]MachineItem, 0, len(items))
+	for i := range items {
+		machines = append(machines, machineFromUnstructured(&items[i]))
+	}
+	return machines, nil
+}
+
+// handleCAPIObjectError converts errors from reading CAPI objects on the
+// Management Cluster to user-friendly tool results.
+func handleCAPIObjectError(err error, operation string) (*mcp.CallToolResult, error) {
+	if apierrors.IsForbidden(err) {
+		return toolerrors.Newf(toolerrors.CodeForbidden, "failed to %s: access denied", operation).Result(), nil
+	}
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		// Intentionally generic - don't reveal whether CAPI is installed
+		return toolerrors.New(toolerrors.CodeNotEnabled, errOperationNotAvailable).Result(), nil
+	}
+	return handleFederationError(err, operation)
+}
+
+// machineFromUnstructured converts a CAPI Machine to a MachineItem.
+func machineFromUnstructured(machine *unstructured.Unstructured) MachineItem {
+	machineLabels := machine.GetLabels()
+	_, controlPlane := machineLabels[federation.LabelCAPIControlPlane]
+
+	nodePool := machineLabels[federation.LabelCAPIDeploymentName]
+	if nodePool == "" {
+		nodePool = machineLabels[federation.LabelCAPIPoolName]
+	}
+
+	phase := firstNestedString(machine.Object, []string{"status", "phase"})
+	if phase == "" {
+		phase = string(federation.ClusterPhaseUnknown)
+	}
+
+	ready, _ := findConditionStatus(machine.Object, "Ready")
+	failureReason, failureMessage := failureDetails(machine.Object)
+
+	return MachineItem{
+		Name:           machine.GetName(),
+		Phase:          phase,
+		Ready:          ready,
+		Version:        firstNestedString(machine.Object, []string{"spec", "version"}),
+		NodeName:       firstNestedString(machine.Object, []string{"status", "nodeRef", "name"}),
+		ProviderID:     firstNestedString(machine.Object, []string{"spec", "providerID"}),
+		ControlPlane:   controlPlane,
+		NodePool:       nodePool,
+		FailureReason:  failureReason,
+		FailureMessage: failureMessage,
+		Conditions:     problemConditions(machine.Object),
+		Age:            formatAge(time.Since(machine.GetCreationTimestamp().Time)),
+	}
+}
+
+// nodePoolFromUnstructured converts a MachineDeployment or MachinePool to a
+// NodePoolItem. Replica counts are read from the v1beta2 status fields,
+// falling back to their v1beta1 names.
+func nodePoolFromUnstructured(obj *unstructured.Unstructured, kind string) NodePoolItem {
+	failureReason, failureMessage := failureDetails(obj.Object)
+
+	return NodePoolItem{
+		Name:    obj.GetName(),
+		Kind:    kind,
+		Phase:   firstNestedString(obj.Object, []string{"status", "phase"}),
+		Version: firstNestedString(obj.Object, []string{"spec", "template", "spec", "version"}),
+		Replicas: NodePoolReplicas{
+			Desired:   firstNestedInt(obj.Object, []string{"spec", "replicas"}),
+			Current:   firstNestedInt(obj.Object, []string{"status", "replicas"}),
+			Ready:     firstNestedInt(obj.Object, []string{"status", "readyReplicas"}),
+			Available: firstNestedInt(obj.Object, []string{"status", "availableReplicas"}),
+			UpToDate:  firstNestedInt(obj.Object, []string{"status", "upToDateReplicas"}, []string{"status", "updatedReplicas"}),
+		},
+		FailureReason:  failureReason,
+		FailureMessage: failureMessage,
+		Conditions:     problemConditions(obj.Object),
+		Age:            formatAge(time.Since(obj.GetCreationTimestamp().Time)),
+	}
+}
+
+// failureDetails returns the terminal failure reason and message of a CAPI
+// object. v1beta2 moved them under status.deprecated.v1beta1.
+func failureDetails(obj map[string]interface{}) (string, string) {
+	reason := firstNestedString(obj,
+		[]string{"status", "failureReason"},
+		[]string{"status", "deprecated", "v1beta1", "failureReason"})
+	message := firstNestedString(obj,
+		[]string{"status", "failureMessage"},
+		[]string{"status", "deprecated", "v1beta1", "failureMessage"})
+	return reason, message
+}
+
+// problemConditions returns the conditions that are not in their normal state.
+func problemConditions(obj map[string]interface{}) []ConditionSummary {
+	conditions, found, err := unstructured.NestedSlice(obj, "status", "conditions")
+	if err != nil || !found {
+		return nil
+	}
+
+	var problems []ConditionSummary
+	for _, c := range conditions {
+		condMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := ConditionSummary{
+			Type:    firstNestedString(condMap, []string{"type"}),
+			Status:  firstNestedString(condMap, []string{"status"}),
+			Reason:  firstNestedString(condMap, []string{"reason"}),
+			Message: firstNestedString(condMap, []string{"message"}),
+		}
+		if isProblemCondition(condition.Type, condition.Status) {
+			problems = append(problems, condition)
+		}
+	}
+	return problems
+}
+
+// isProblemCondition reports whether a condition is not in its normal
+// state: a positive condition that is not True or a negative-polarity
+// condition that is True.
+func isProblemCondition(conditionType, status string) bool {
+	return (status == federation.ConditionStatusTrue) == negativePolarityConditions[conditionType]
+}
+
+// findConditionStatus reports whether the condition of the given type in
+// status.conditions is True, and whether it was found.
+func findConditionStatus(obj map[string]interface{}, conditionType string) (bool, bool) {
+	conditions, found, err := unstructured.NestedSlice(obj, "status", "conditions")
+	if err != nil || !found {
+		return false, false
+	}
+	for _, c := range conditions {
+		condMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if firstNestedString(condMap, []string{"type"}) == conditionType {
+			return firstNestedString(condMap, []string{"status"}) == federation.ConditionStatusTrue, true
+		}
+	}
+	return false, false
+}
+
+// objectRef formats the object reference at the given path as Kind/name.
+func objectRef(obj map[string]interface{}, path ...string) string {
+	kind, _, _ := unstructured.NestedString(obj, append(path, "kind")...)
+	name, _, _ := unstructured.NestedString(obj, append(path, "name")...)
+	if kind == "" || name == "" {
+		return ""
+	}
+	return kind + "/" + name
+}
+
+// firstNestedString returns the first non-empty string found at the given paths.
+func firstNestedString(obj map[string]interface{}, paths ...[]string) string {
+	for _, path := range paths {
+		if value, found, err := unstructured.NestedString(obj, path...); err == nil && found && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// firstNestedInt returns the first integer found at the given paths.
+func firstNestedInt(obj map[string]interface{}, paths ...[]string) int {
+	for _, path := range paths {
+		if value, found, err := unstructured.NestedInt64(obj, path...); err == nil && found {
+			return int(value)
+		}
+	}
+	return 0
+}
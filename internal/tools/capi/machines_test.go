@@ -0,0 +1,271 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/capi/testdata"
+)
+
+// capiObject creates an unstructured CAPI object of the given kind in org-acme.
+func capiObject(kind, name string, labels map[string]interface{}, spec, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cluster.x-k8s.io/v1beta2",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "org-acme",
+			"labels":            labels,
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+		},
+		"spec":   spec,
+		"status": status,
+	}}
+}
+
+func machineLabels(cluster, deployment string) map[string]interface{} {
+	labels := map[string]interface{}{federation.LabelCAPIClusterName: cluster}
+	if deployment != "" {
+		labels[federation.LabelCAPIDeploymentName] = deployment
+	}
+	return labels
+}
+
+// newMachinesServerContext returns a server context whose federation manager
+// serves the given CAPI objects from the Management Cluster.
+func newMachinesServerContext(t *testing.T, objects ...runtime.Object) *server.ServerContext {
+	t.Helper()
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			federation.CAPIMachineGVR:           "MachineList",
+			federation.CAPIMachineDeploymentGVR: "MachineDeploymentList",
+			federation.CAPIMachinePoolGVR:       "MachinePoolList",
+		}, objects...)
+
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithFederationManager(&testdata.MockFederationManager{
+			ClusterDetails: testdata.CreateTestClusterDetailsMap(),
+			DynamicClient:  dynamicClient,
+		}),
+	)
+	require.NoError(t, err)
+	return sc
+}
+
+func testMachines() []runtime.Object {
+	return []runtime.Object{
+		capiObject("Machine", "prod-wc-01-worker-b", machineLabels("prod-wc-01", "prod-wc-01-worker"),
+			map[string]interface{}{"version": "v1.28.5"},
+			map[string]interface{}{
+				"phase": "Failed",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False", "reason": "NotReady"},
+					map[string]interface{}{"type": "InfrastructureReady", "status": "False", "reason": "InstanceProvisionFailed", "message": "insufficient capacity"},
+					map[string]interface{}{"type": "Deleting", "status": "False"},
+				},
+				"deprecated": map[string]interface{}{"v1beta1": map[string]interface{}{
+					"failureReason":  "CreateError",
+					"failureMessage": "instance could not be launched",
+				}},
+			}),
+		capiObject("Machine", "prod-wc-01-worker-a", machineLabels("prod-wc-01", "prod-wc-01-worker"),
+			map[string]interface{}{"version": "v1.28.5", "providerID": "aws:///eu-west-1a/i-123"},
+			map[string]interface{}{
+				"phase":   "Running",
+				"nodeRef": map[string]interface{}{"name": "ip-10-0-1-1"},
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			}),
+		capiObject("Machine", "prod-wc-01-cp-1", map[string]interface{}{
+			federation.LabelCAPIClusterName:  "prod-wc-01",
+			federation.LabelCAPIControlPlane: "",
+		}, map[string]interface{}{"version": "v1.28.5"}, map[string]interface{}{"phase": "Running"}),
+		capiObject("Machine", "staging-wc-worker-a", machineLabels("staging-wc", "staging-wc-worker"),
+			nil, map[string]interface{}{"phase": "Running"}),
+	}
+}
+
+func callCAPITool(t *testing.T, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), sc *server.ServerContext, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	ctx := contextWithUserInfo("test@example.com", []string{"developers"})
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(ctx, request, sc)
+	require.NoError(t, err)
+	return result
+}
+
+func TestHandleListMachines(t *testing.T) {
+	sc := newMachinesServerContext(t, testMachines()...)
+
+	result := callCAPITool(t, handleListMachines, sc, map[string]interface{}{"cluster": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output MachineListOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+
+	assert.Equal(t, "org-acme", output.Namespace)
+	assert.Equal(t, 3, output.TotalCount)
+	assert.Equal(t, map[string]int{"Running": 2, "Failed": 1}, output.Phases)
+	require.Len(t, output.Machines, 3)
+
+	cp := output.Machines[0]
+	assert.Equal(t, "prod-wc-01-cp-1", cp.Name)
+	assert.True(t, cp.ControlPlane)
+
+	running := output.Machines[1]
+	assert.True(t, running.Ready)
+	assert.Equal(t, "ip-10-0-1-1", running.NodeName)
+	assert.Equal(t, "prod-wc-01-worker", running.NodePool)
+	assert.Empty(t, running.Conditions)
+
+	failed := output.Machines[2]
+	assert.Equal(t, "Failed", failed.Phase)
+	assert.False(t, failed.Ready)
+	assert.Equal(t, "CreateError", failed.FailureReason)
+	assert.Equal(t, "instance could not be launched", failed.FailureMessage)
+	assert.Equal(t, []ConditionSummary{
+		{Type: "Ready", Status: "False", Reason: "NotReady"},
+		{Type: "InfrastructureReady", Status: "False", Reason: "InstanceProvisionFailed", Message: "insufficient capacity"},
+	}, failed.Conditions)
+}
+
+func TestHandleListMachines_FiltersAndLimit(t *testing.T) {
+	sc := newMachinesServerContext(t, testMachines()...)
+
+	result := callCAPITool(t, handleListMachines, sc, map[string]interface{}{
+		"cluster":  "prod-wc-01",
+		"nodePool": "prod-wc-01-worker",
+		"limit":    float64(1),
+	})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output MachineListOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Equal(t, 2, output.TotalCount)
+	assert.Equal(t, 1, output.ReturnedCount)
+	assert.True(t, output.Truncated)
+	assert.Equal(t, "prod-wc-01-worker-a", output.Machines[0].Name)
+
+	result = callCAPITool(t, handleListMachines, sc, map[string]interface{}{"cluster": "prod-wc-01", "phase": "Failed"})
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	require.Len(t, output.Machines, 1)
+	assert.Equal(t, "prod-wc-01-worker-b", output.Machines[0].Name)
+}
+
+func TestHandleListMachines_Errors(t *testing.T) {
+	sc := newMachinesServerContext(t)
+
+	result := callCAPITool(t, handleListMachines, sc, map[string]interface{}{})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), "cluster parameter is required")
+
+	result = callCAPITool(t, handleListMachines, sc, map[string]interface{}{"cluster": "unknown"})
+	assert.True(t, result.IsError)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"cluster": "prod-wc-01"}
+	result, err := handleListMachines(context.Background(), request, sc)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), errAuthRequired)
+}
+
+func TestHandleListNodePools(t *testing.T) {
+	objects := append(testMachines(),
+		capiObject("MachineDeployment", "prod-wc-01-worker", machineLabels("prod-wc-01", ""),
+			map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{"spec": map[string]interface{}{"version": "v1.28.5"}},
+			},
+			map[string]interface{}{
+				"phase":             "ScalingUp",
+				"replicas":          int64(2),
+				"readyReplicas":     int64(1),
+				"availableReplicas": int64(1),
+				"upToDateReplicas":  int64(2),
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "False", "reason": "NotEnoughReplicas"},
+					map[string]interface{}{"type": "ScalingUp", "status": "True", "message": "Scaling up from 2 to 3 replicas"},
+				},
+			}),
+		capiObject("MachinePool", "prod-wc-01-spot", machineLabels("prod-wc-01", ""),
+			map[string]interface{}{"replicas": int64(5)},
+			map[string]interface{}{"phase": "Running", "replicas": int64(5), "readyReplicas": int64(5), "updatedReplicas": int64(4)}),
+		capiObject("MachineDeployment", "staging-wc-worker", machineLabels("staging-wc", ""), nil, nil),
+	)
+	sc := newMachinesServerContext(t, objects...)
+
+	result := callCAPITool(t, handleListNodePools, sc, map[string]interface{}{"cluster": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output NodePoolListOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	require.Len(t, output.NodePools, 2)
+
+	spot := output.NodePools[0]
+	assert.Equal(t, "MachinePool", spot.Kind)
+	assert.Equal(t, NodePoolReplicas{Desired: 5, Current: 5, Ready: 5, UpToDate: 4}, spot.Replicas)
+
+	worker := output.NodePools[1]
+	assert.Equal(t, "MachineDeployment", worker.Kind)
+	assert.Equal(t, "v1.28.5", worker.Version)
+	assert.Equal(t, NodePoolReplicas{Desired: 3, Current: 2, Ready: 1, Available: 1, UpToDate: 2}, worker.Replicas)
+	assert.Equal(t, []ConditionSummary{
+		{Type: "Available", Status: "False", Reason: "NotEnoughReplicas"},
+		{Type: "ScalingUp", Status: "True", Message: "Scaling up from 2 to 3 replicas"},
+	}, worker.Conditions)
+}
+
+func TestHandleGetMachineDeployment(t *testing.T) {
+	objects := append(testMachines(),
+		capiObject("MachineDeployment", "prod-wc-01-worker", machineLabels("prod-wc-01", ""),
+			map[string]interface{}{
+				"replicas": int64(2),
+				"rollout":  map[string]interface{}{"strategy": map[string]interface{}{"type": "RollingUpdate"}},
+				"template": map[string]interface{}{"spec": map[string]interface{}{
+					"version":           "v1.28.5",
+					"infrastructureRef": map[string]interface{}{"kind": "AWSMachineTemplate", "name": "prod-wc-01-worker-abc"},
+				}},
+			},
+			map[string]interface{}{"phase": "Running"}),
+		capiObject("MachineDeployment", "staging-wc-worker", machineLabels("staging-wc", ""), nil, nil),
+	)
+	sc := newMachinesServerContext(t, objects...)
+
+	result := callCAPITool(t, handleGetMachineDeployment, sc, map[string]interface{}{
+		"cluster": "prod-wc-01",
+		"name":    "prod-wc-01-worker",
+	})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output MachineDeploymentDetailOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Equal(t, "prod-wc-01-worker", output.Name)
+	assert.Equal(t, "RollingUpdate", output.RolloutStrategy)
+	assert.Equal(t, "AWSMachineTemplate/prod-wc-01-worker-abc", output.InfrastructureTemplate)
+	require.Len(t, output.Machines, 2)
+	assert.Equal(t, "prod-wc-01-worker-a", output.Machines[0].Name)
+
+	// A deployment of another cluster in the same namespace is not found.
+	result = callCAPITool(t, handleGetMachineDeployment, sc, map[string]interface{}{
+		"cluster": "prod-wc-01",
+		"name":    "staging-wc-worker",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getResultText(result), "not found")
+}
@@ -0,0 +1,212 @@
+package capi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+)
+
+// API server probe settings.
+const (
+	// DefaultProbeTimeout bounds a live API server probe.
+	DefaultProbeTimeout = 5 * time.Second
+
+	// MaxProbeTimeout is the longest probe timeout a caller may request.
+	MaxProbeTimeout = 30 * time.Second
+
+	// ProbeCacheTTL is how long a probe result is reused, so that agents
+	// polling cluster health do not probe an API server on every call.
+	ProbeCacheTTL = 30 * time.Second
+)
+
+// Probed API server endpoints.
+const (
+	probeEndpointReadyz  = "/readyz"
+	probeEndpointVersion = "/version"
+)
+
+// apiServerProbes caches probe results of all capi_cluster_health calls.
+var apiServerProbes = newProbeCache(ProbeCacheTTL)
+
+// probeCache caches API server probe results per cluster and user. Results
+// are kept per user because the probe runs with the user's credentials.
+type probeCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]APIServerProbe
+}
+
+func newProbeCache(ttl time.Duration) *probeCache {
+	return &probeCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]APIServerProbe),
+	}
+}
+
+// get returns an unexpired probe result, marked as cached.
+func (c *probeCache) get(cluster, user string) (APIServerProbe, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	probe, ok := c.entries[cluster+"\x00"+user]
+	if !ok || c.now().Sub(probe.CheckedAt) >= c.ttl {
+		return APIServerProbe{}, false
+	}
+	probe.Cached = true
+	return probe, true
+}
+
+// put stores a probe result and drops expired ones.
+func (c *probeCache) put(cluster, user string, probe APIServerProbe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.Sub(entry.CheckedAt) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[cluster+"\x00"+user] = probe
+}
+
+// probeClusterAPIServer probes the API server of a workload cluster with the
+// user's credentials, reusing a recent result if there is one.
+func probeClusterAPIServer(ctx context.Context, fedManager federation.ClusterClientManager, cluster string, user *federation.UserInfo, timeout time.Duration) APIServerProbe {
+	if probe, ok := apiServerProbes.get(cluster, user.Email); ok {
+		return probe
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var probe APIServerProbe
+	client, err := fedManager.GetClient(ctx, cluster, user)
+	switch {
+	case err != nil:
+		probe = APIServerProbe{Message: "Could not create a client for the workload cluster: " + probeErrorSummary(ctx, err, timeout)}
+	case client == nil:
+		probe = APIServerProbe{Message: "Could not create a client for the workload cluster"}
+	default:
+		probe = probeAPIServer(ctx, client, timeout)
+	}
+	probe.CheckedAt = apiServerProbes.now()
+
+	apiServerProbes.put(cluster, user.Email, probe)
+	return probe
+}
+
+// probeAPIServer calls /readyz on the API server. API servers that do not
+// serve /readyz to the user are probed with /version instead.
+func probeAPIServer(ctx context.Context, client kubernetes.Interface, timeout time.Duration) APIServerProbe {
+	start := time.Now()
+	disco := client.Discovery()
+
+	if restClient := disco.RESTClient(); restClient != nil {
+		var statusCode int
+		body, err := restClient.Get().AbsPath(probeEndpointReadyz).Do(ctx).StatusCode(&statusCode).Raw()
+		probe := APIServerProbe{
+			Endpoint:  probeEndpointReadyz,
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+		switch {
+		case err == nil:
+			probe.Reachable = true
+			probe.Ready = true
+			probe.Message = "API server is ready"
+			return probe
+		case statusCode == http.StatusInternalServerError:
+			probe.Reachable = true
+			probe.FailedChecks = failedReadyzChecks(body)
+			probe.Message = "API server is reachable but not ready"
+			return probe
+		case statusCode == 0:
+			probe.Message = "API server is not reachable: " + probeErrorSummary(ctx, err, timeout)
+			return probe
+		}
+		// Any other status, such as 403 or 404, means /readyz is not
+		// available to the user; fall back to /version.
+	}
+
+	version, err := disco.ServerVersion()
+	probe := APIServerProbe{
+		Endpoint:  probeEndpointVersion,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		probe.Message = "API server is not reachable: " + probeErrorSummary(ctx, err, timeout)
+		return probe
+	}
+	probe.Reachable = true
+	probe.Ready = true
+	probe.Version = version.GitVersion
+	probe.Message = "API server answered the version request"
+	return probe
+}
+
+// failedReadyzChecks returns the names of the failed checks listed in a
+// /readyz response, such as "etcd" for "[-]etcd failed: reason withheld".
+func failedReadyzChecks(body []byte) []string {
+	var checks []string
+	for _, line := range strings.Split(string(body), "\n") {
+		name, found := strings.CutPrefix(strings.TrimSpace(line), "[-]")
+		if !found {
+			continue
+		}
+		if i := strings.IndexByte(name, ' '); i >= 0 {
+			name = name[:i]
+		}
+		checks = append(checks, name)
+	}
+	return checks
+}
+
+// probeErrorSummary describes a probe failure without passing on error
+// details, except for timeouts.
+func probeErrorSummary(ctx context.Context, err error, timeout time.Duration) string {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "timed out after " + timeout.String()
+	}
+	return errorSummary(err)
+}
+
+// applyAPIServerProbe adds the probe result to a health output. A cluster that
+// CAPI reports healthy but whose API server does not answer is unhealthy.
+func applyAPIServerProbe(output *ClusterHealthOutput, probe APIServerProbe) {
+	output.APIServer = &probe
+
+	check := HealthCheck{Name: "api-server-ready", Message: probe.Message}
+	switch {
+	case probe.Ready:
+		check.Status = CheckStatusPass
+	case probe.Reachable:
+		check.Status = CheckStatusWarn
+		if len(probe.FailedChecks) > 0 {
+			check.Message += " (failed checks: " + strings.Join(probe.FailedChecks, ", ") + ")"
+		}
+	default:
+		check.Status = CheckStatusFail
+	}
+	output.Checks = append(output.Checks, check)
+
+	if output.Status != HealthStatusHealthy || probe.Ready {
+		return
+	}
+	if probe.Reachable {
+		output.Status = HealthStatusDegraded
+		output.Message = "CAPI reports the cluster ready, but its API server is not ready"
+		return
+	}
+	output.Status = HealthStatusUnhealthy
+	output.Message = "CAPI reports the cluster ready, but its API server is not reachable"
+}
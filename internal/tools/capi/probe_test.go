@@ -0,0 +1,251 @@
+package capi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/capi/testdata"
+)
+
+// newProbeTestClient returns a clientset for an API server served by handler.
+func newProbeTestClient(t *testing.T, handler http.HandlerFunc) kubernetes.Interface {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	require.NoError(t, err)
+	return client
+}
+
+// resetProbeCache gives a test its own probe cache.
+func resetProbeCache(t *testing.T) {
+	t.Helper()
+	previous := apiServerProbes
+	apiServerProbes = newProbeCache(ProbeCacheTTL)
+	t.Cleanup(func() { apiServerProbes = previous })
+}
+
+func TestProbeAPIServer(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		wantReachable bool
+		wantReady     bool
+		wantEndpoint  string
+		wantFailed    []string
+		wantVersion   string
+	}{
+		{
+			name: "ready",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantReachable: true,
+			wantReady:     true,
+			wantEndpoint:  probeEndpointReadyz,
+		},
+		{
+			name: "not ready",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("[+]ping ok\n[-]etcd failed: reason withheld\n[-]informer-sync failed: reason withheld\nreadyz check failed\n"))
+			},
+			wantReachable: true,
+			wantEndpoint:  probeEndpointReadyz,
+			wantFailed:    []string{"etcd", "informer-sync"},
+		},
+		{
+			name: "readyz forbidden falls back to version",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == probeEndpointReadyz {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"gitVersion":"v1.31.2"}`))
+			},
+			wantReachable: true,
+			wantReady:     true,
+			wantEndpoint:  probeEndpointVersion,
+			wantVersion:   "v1.31.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newProbeTestClient(t, tt.handler)
+
+			probe := probeAPIServer(context.Background(), client, DefaultProbeTimeout)
+			assert.Equal(t, tt.wantReachable, probe.Reachable)
+			assert.Equal(t, tt.wantReady, probe.Ready)
+			assert.Equal(t, tt.wantEndpoint, probe.Endpoint)
+			assert.Equal(t, tt.wantFailed, probe.FailedChecks)
+			assert.Equal(t, tt.wantVersion, probe.Version)
+		})
+	}
+}
+
+func TestProbeAPIServer_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	require.NoError(t, err)
+
+	probe := probeAPIServer(context.Background(), client, DefaultProbeTimeout)
+	assert.False(t, probe.Reachable)
+	assert.False(t, probe.Ready)
+	assert.Equal(t, "API server is not reachable: an unexpected error occurred", probe.Message)
+}
+
+func TestProbeAPIServer_Timeout(t *testing.T) {
+	client := newProbeTestClient(t, func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	probe := probeAPIServer(ctx, client, 50*time.Millisecond)
+	assert.False(t, probe.Reachable)
+	assert.Equal(t, "API server is not reachable: timed out after 50ms", probe.Message)
+}
+
+func TestProbeCache(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cache := newProbeCache(30 * time.Second)
+	cache.now = func() time.Time { return now }
+
+	cache.put("prod-wc-01", "jane@example.com", APIServerProbe{Ready: true, CheckedAt: now})
+
+	probe, ok := cache.get("prod-wc-01", "jane@example.com")
+	require.True(t, ok)
+	assert.True(t, probe.Cached)
+
+	_, ok = cache.get("prod-wc-01", "john@example.com")
+	assert.False(t, ok, "results are cached per user")
+
+	now = now.Add(30 * time.Second)
+	_, ok = cache.get("prod-wc-01", "jane@example.com")
+	assert.False(t, ok, "results expire")
+}
+
+func TestApplyAPIServerProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		probe       APIServerProbe
+		wantStatus  string
+		wantCheck   string
+		wantMessage string
+	}{
+		{
+			name:       "ready",
+			status:     HealthStatusHealthy,
+			probe:      APIServerProbe{Reachable: true, Ready: true, Message: "API server is ready"},
+			wantStatus: HealthStatusHealthy,
+			wantCheck:  CheckStatusPass,
+		},
+		{
+			name:        "not ready",
+			status:      HealthStatusHealthy,
+			probe:       APIServerProbe{Reachable: true, FailedChecks: []string{"etcd"}, Message: "API server is reachable but not ready"},
+			wantStatus:  HealthStatusDegraded,
+			wantCheck:   CheckStatusWarn,
+			wantMessage: "API server is reachable but not ready (failed checks: etcd)",
+		},
+		{
+			name:       "unreachable",
+			status:     HealthStatusHealthy,
+			probe:      APIServerProbe{Message: "API server is not reachable: timed out after 5s"},
+			wantStatus: HealthStatusUnhealthy,
+			wantCheck:  CheckStatusFail,
+		},
+		{
+			name:       "unreachable while provisioning keeps the CAPI status",
+			status:     HealthStatusDegraded,
+			probe:      APIServerProbe{Message: "API server is not reachable: timed out after 5s"},
+			wantStatus: HealthStatusDegraded,
+			wantCheck:  CheckStatusFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := ClusterHealthOutput{Status: tt.status}
+			applyAPIServerProbe(&output, tt.probe)
+
+			assert.Equal(t, tt.wantStatus, output.Status)
+			require.NotNil(t, output.APIServer)
+			require.Len(t, output.Checks, 1)
+			assert.Equal(t, "api-server-ready", output.Checks[0].Name)
+			assert.Equal(t, tt.wantCheck, output.Checks[0].Status)
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, output.Checks[0].Message)
+			}
+		})
+	}
+}
+
+func TestHandleClusterHealth_Probe(t *testing.T) {
+	resetProbeCache(t)
+
+	requests := 0
+	client := newProbeTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("[-]etcd failed: reason withheld\n"))
+	})
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithFederationManager(&testdata.MockFederationManager{
+			ClusterDetails: testdata.CreateTestClusterDetailsMap(),
+			Client:         client,
+		}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		result := callCAPITool(t, handleClusterHealth, sc, map[string]interface{}{"name": "prod-wc-01", "probe": true})
+		require.False(t, result.IsError, getResultText(result))
+
+		var output ClusterHealthOutput
+		require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+		assert.Equal(t, HealthStatusDegraded, output.Status)
+		require.NotNil(t, output.APIServer)
+		assert.Equal(t, []string{"etcd"}, output.APIServer.FailedChecks)
+		assert.Equal(t, i > 0, output.APIServer.Cached)
+	}
+	assert.Equal(t, 1, requests, "the second call reuses the probe result")
+}
+
+func TestHandleClusterHealth_NoProbeByDefault(t *testing.T) {
+	resetProbeCache(t)
+
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithFederationManager(&testdata.MockFederationManager{
+			ClusterDetails: testdata.CreateTestClusterDetailsMap(),
+			Client:         fake.NewClientset(),
+		}),
+	)
+	require.NoError(t, err)
+
+	result := callCAPITool(t, handleClusterHealth, sc, map[string]interface{}{"name": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output ClusterHealthOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Nil(t, output.APIServer)
+	assert.Equal(t, HealthStatusHealthy, output.Status)
+}
@@ -16,6 +16,14 @@ import (
 //   - capi_get_cluster: Get detailed information about a specific cluster
 //   - capi_resolve_cluster: Resolve a partial cluster name to its full identifier
 //   - capi_cluster_health: Check the health status of a cluster
+//   - capi_list_machines: List the Machines of a cluster with their status
+//   - capi_get_machinedeployment: Get a MachineDeployment and its Machines
+//   - capi_list_nodepools: List the MachineDeployments and MachinePools of a cluster
+//   - capi_upgrade_status: Report the progress of a cluster upgrade
+//   - capi_cluster_events: Show a timeline of a cluster's conditions and events
+//   - capi_list_apps: List the Giant Swarm Apps of a cluster and available upgrades
+//   - capi_get_app: Get the status and available versions of an App
+//   - capi_upgrade_app: Upgrade an App to a catalog version (mutating)
 func RegisterCAPITools(s *mcpserver.MCPServer, sc *server.ServerContext) error {
 	// Only register CAPI tools when federation is enabled
 	if !sc.FederationEnabled() {
@@ -80,7 +88,7 @@ func RegisterCAPITools(s *mcpserver.MCPServer, sc *server.ServerContext) error {
 
 	// capi_cluster_health tool
 	clusterHealthTool := mcp.NewTool("capi_cluster_health",
-		mcp.WithDescription("Check the health status of a CAPI cluster. Returns overall health, component status, and individual health checks."),
+		mcp.WithDescription("Check the health status of a CAPI cluster. Returns overall health, component status, and individual health checks. Health is derived from CAPI conditions; set probe to also check that the cluster's API server actually answers."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithOpenWorldHintAnnotation(false),
 		mcp.WithSchemaAdditionalProperties(false),
@@ -88,9 +96,172 @@ func RegisterCAPITools(s *mcpserver.MCPServer, sc *server.ServerContext) error {
 			mcp.Required(),
 			mcp.Description("The name of the cluster to check health for"),
 		),
+		mcp.WithBoolean("probe",
+			mcp.Description("Also call /readyz (or /version) on the workload cluster API server with your credentials. Results are cached for 30 seconds (default: false)"),
+		),
+		mcp.WithNumber("probeTimeout",
+			mcp.Description("Probe timeout in seconds (default: 5, max: 30)"),
+		),
 	)
 
 	s.AddTool(clusterHealthTool, tools.WrapWithAuditLogging("capi_cluster_health", handleClusterHealth, sc))
 
+	// capi_list_machines tool
+	listMachinesTool := mcp.NewTool("capi_list_machines",
+		mcp.WithDescription("List the CAPI Machines of a workload cluster from the Management Cluster. Returns each machine's phase, readiness, Kubernetes version, node name, node pool, failure reason and message, and the conditions that are not in their normal state. Use this to diagnose machines that fail to provision or join."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+		mcp.WithString("nodePool",
+			mcp.Description("Only show machines of this MachineDeployment or MachinePool"),
+		),
+		mcp.WithString("phase",
+			mcp.Description("Filter by machine phase (Pending, Provisioning, Provisioned, Running, Deleting, Failed)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of machines to return (default: 100, max: 500). Use filters to narrow results if truncated."),
+		),
+	)
+
+	s.AddTool(listMachinesTool, tools.WrapWithAuditLogging("capi_list_machines", handleListMachines, sc))
+
+	// capi_get_machinedeployment tool
+	getMachineDeploymentTool := mcp.NewTool("capi_get_machinedeployment",
+		mcp.WithDescription("Get a CAPI MachineDeployment of a workload cluster, including its replica counts, version, rollout strategy, failure details, conditions, and the status of each of its Machines."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the MachineDeployment"),
+		),
+	)
+
+	s.AddTool(getMachineDeploymentTool, tools.WrapWithAuditLogging("capi_get_machinedeployment", handleGetMachineDeployment, sc))
+
+	// capi_list_nodepools tool
+	listNodePoolsTool := mcp.NewTool("capi_list_nodepools",
+		mcp.WithDescription("List the node pools (CAPI MachineDeployments and MachinePools) of a workload cluster with their phase, Kubernetes version, desired/ready/available/up-to-date replicas, failure details, and conditions."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+	)
+
+	s.AddTool(listNodePoolsTool, tools.WrapWithAuditLogging("capi_list_nodepools", handleListNodePools, sc))
+
+	// capi_upgrade_status tool
+	upgradeStatusTool := mcp.NewTool("capi_upgrade_status",
+		mcp.WithDescription("Report whether a CAPI workload cluster upgrade is done. Compares the desired Kubernetes version (spec.topology.version) and Giant Swarm release with the versions the control plane and node pool machines run, and reports the rollout progress of each MachineDeployment and MachinePool."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+	)
+
+	s.AddTool(upgradeStatusTool, tools.WrapWithAuditLogging("capi_upgrade_status", handleUpgradeStatus, sc))
+
+	// capi_cluster_events tool
+	clusterEventsTool := mcp.NewTool("capi_cluster_events",
+		mcp.WithDescription("Show a timeline, newest first, of a CAPI workload cluster's conditions and Management Cluster events. Combines the conditions of the Cluster, its control plane (e.g. KubeadmControlPlane) and its infrastructure cluster with the events of those objects and of the cluster's Machines and node pools. Use this to diagnose provisioning failures."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+		mcp.WithBoolean("warningsOnly",
+			mcp.Description("Only show Warning events and conditions that report a problem (default: false)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of timeline entries to return, newest first (default: 100, max: 500)"),
+		),
+	)
+
+	s.AddTool(clusterEventsTool, tools.WrapWithAuditLogging("capi_cluster_events", handleClusterEvents, sc))
+
+	// capi_list_apps tool
+	listAppsTool := mcp.NewTool("capi_list_apps",
+		mcp.WithDescription("List the Giant Swarm Apps (App CRs on the Management Cluster) installed in a workload cluster. Returns each app's catalog, desired and deployed version, target namespace, Helm release status, and the latest version in its catalog with whether an upgrade is available."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Filter by release status (e.g., 'deployed', 'failed', 'pending-install')"),
+		),
+		mcp.WithBoolean("upgradableOnly",
+			mcp.Description("Only show apps with a newer version in their catalog (default: false)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of apps to return (default: 100, max: 500)"),
+		),
+	)
+
+	s.AddTool(listAppsTool, tools.WrapWithAuditLogging("capi_list_apps", handleListApps, sc))
+
+	// capi_get_app tool
+	getAppTool := mcp.NewTool("capi_get_app",
+		mcp.WithDescription("Get a Giant Swarm App of a workload cluster, including its release status and reason, last deployment, configuration ConfigMaps and Secrets, and the newer versions published in its catalog."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("cluster",
+			mcp.Required(),
+			mcp.Description("The name of the workload cluster"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the App object (see capi_list_apps)"),
+		),
+	)
+
+	s.AddTool(getAppTool, tools.WrapWithAuditLogging("capi_get_app", handleGetApp, sc))
+
+	// capi_upgrade_app tool, only exposed when patching is allowed
+	if tools.IsMutatingOperationAllowed(sc, "patch") {
+		upgradeAppTool := mcp.NewTool("capi_upgrade_app",
+			mcp.WithDescription("Upgrade a Giant Swarm App of a workload cluster by setting the version of its App CR. The version must be published in the app's catalog; the app operator then rolls it out. Follow the rollout with capi_get_app."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+			mcp.WithString("cluster",
+				mcp.Required(),
+				mcp.Description("The name of the workload cluster"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the App object (see capi_list_apps)"),
+			),
+			mcp.WithString("version",
+				mcp.Required(),
+				mcp.Description("The app version to install (e.g., '2.3.1')"),
+			),
+			tools.DryRunParam(),
+		)
+
+		s.AddTool(upgradeAppTool, tools.WrapWithAuditLogging("capi_upgrade_app", handleUpgradeApp, sc))
+	}
+
 	return nil
 }
@@ -152,6 +152,42 @@ type ClusterHealthOutput struct {
 
 	// Checks contains individual health check results.
 	Checks []HealthCheck `json:"checks,omitempty"`
+
+	// APIServer is the result of the live API server probe (present when
+	// probe is requested).
+	APIServer *APIServerProbe `json:"apiServer,omitempty"`
+}
+
+// APIServerProbe is the result of a live probe of a workload cluster's API
+// server, made with the user's credentials.
+type APIServerProbe struct {
+	// Reachable indicates the API server answered the probe.
+	Reachable bool `json:"reachable"`
+
+	// Ready indicates the API server reported itself ready on /readyz, or
+	// answered /version where /readyz is not available to the user.
+	Ready bool `json:"ready"`
+
+	// Endpoint is the probed path (/readyz or /version).
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Version is the API server's Kubernetes version (only for /version).
+	Version string `json:"version,omitempty"`
+
+	// FailedChecks lists the /readyz checks that failed.
+	FailedChecks []string `json:"failedChecks,omitempty"`
+
+	// LatencyMs is how long the probe took in milliseconds.
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Message provides a human-readable probe result.
+	Message string `json:"message,omitempty"`
+
+	// CheckedAt is when the API server was probed.
+	CheckedAt time.Time `json:"checkedAt"`
+
+	// Cached indicates the result was reused from a recent probe.
+	Cached bool `json:"cached,omitempty"`
 }
 
 // ClusterHealthComponents contains health status of cluster components.
@@ -193,6 +229,438 @@ type HealthCheck struct {
 	Message string `json:"message,omitempty"`
 }
 
+// MachineListOutput represents the output for the capi_list_machines tool.
+type MachineListOutput struct {
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace holding the cluster's CAPI objects.
+	Namespace string `json:"namespace"`
+
+	// Machines contains the machines, sorted by name.
+	Machines []MachineItem `json:"machines"`
+
+	// Phases counts the matching machines per phase, before the limit is applied.
+	Phases map[string]int `json:"phases,omitempty"`
+
+	// TotalCount is the number of matching machines (before pagination).
+	TotalCount int `json:"totalCount"`
+
+	// ReturnedCount is the number of machines returned in this response.
+	ReturnedCount int `json:"returnedCount"`
+
+	// Truncated indicates whether the results were truncated due to limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// MachineItem represents a single CAPI Machine.
+type MachineItem struct {
+	// Name is the Machine name.
+	Name string `json:"name"`
+
+	// Phase is the Machine lifecycle phase (Pending, Provisioning, Provisioned, Running, Deleting, Failed).
+	Phase string `json:"phase"`
+
+	// Ready reports the Machine's Ready condition.
+	Ready bool `json:"ready"`
+
+	// Version is the Kubernetes version of the Machine.
+	Version string `json:"version,omitempty"`
+
+	// NodeName is the workload cluster node backing the Machine, once it has joined.
+	NodeName string `json:"nodeName,omitempty"`
+
+	// ProviderID is the infrastructure provider's identifier for the Machine.
+	ProviderID string `json:"providerID,omitempty"`
+
+	// ControlPlane indicates a control plane Machine.
+	ControlPlane bool `json:"controlPlane,omitempty"`
+
+	// NodePool is the MachineDeployment or MachinePool owning the Machine.
+	NodePool string `json:"nodePool,omitempty"`
+
+	// FailureReason is the terminal failure reason reported by CAPI, if any.
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// FailureMessage is the terminal failure message reported by CAPI, if any.
+	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// Conditions lists the conditions that report a problem or ongoing change.
+	Conditions []ConditionSummary `json:"conditions,omitempty"`
+
+	// Age is the human-readable age of the Machine.
+	Age string `json:"age"`
+}
+
+// ConditionSummary is a condition of a CAPI object.
+type ConditionSummary struct {
+	// Type is the condition type, such as Ready or InfrastructureReady.
+	Type string `json:"type"`
+
+	// Status is True, False or Unknown.
+	Status string `json:"status"`
+
+	// Reason is the machine-readable reason for the status.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human-readable detail for the status.
+	Message string `json:"message,omitempty"`
+}
+
+// NodePoolListOutput represents the output for the capi_list_nodepools tool.
+type NodePoolListOutput struct {
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace holding the cluster's CAPI objects.
+	Namespace string `json:"namespace"`
+
+	// NodePools contains the MachineDeployments and MachinePools, sorted by name.
+	NodePools []NodePoolItem `json:"nodePools"`
+}
+
+// NodePoolItem represents a MachineDeployment or MachinePool.
+type NodePoolItem struct {
+	// Name is the object name.
+	Name string `json:"name"`
+
+	// Kind is MachineDeployment or MachinePool.
+	Kind string `json:"kind"`
+
+	// Phase is the lifecycle phase (ScalingUp, ScalingDown, Running, Failed, Unknown).
+	Phase string `json:"phase,omitempty"`
+
+	// Version is the Kubernetes version of the machine template.
+	Version string `json:"version,omitempty"`
+
+	// Replicas contains the replica counts.
+	Replicas NodePoolReplicas `json:"replicas"`
+
+	// FailureReason is the terminal failure reason reported by CAPI, if any.
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// FailureMessage is the terminal failure message reported by CAPI, if any.
+	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// Conditions lists the conditions that report a problem or ongoing change.
+	Conditions []ConditionSummary `json:"conditions,omitempty"`
+
+	// Age is the human-readable age of the node pool.
+	Age string `json:"age"`
+}
+
+// NodePoolReplicas contains the replica counts of a node pool.
+type NodePoolReplicas struct {
+	// Desired is the requested number of replicas.
+	Desired int `json:"desired"`
+
+	// Current is the number of existing replicas.
+	Current int `json:"current"`
+
+	// Ready is the number of ready replicas.
+	Ready int `json:"ready"`
+
+	// Available is the number of available replicas.
+	Available int `json:"available"`
+
+	// UpToDate is the number of replicas matching the current template.
+	UpToDate int `json:"upToDate"`
+}
+
+// MachineDeploymentDetailOutput represents the output for the
+// capi_get_machinedeployment tool.
+type MachineDeploymentDetailOutput struct {
+	NodePoolItem
+
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace.
+	Namespace string `json:"namespace"`
+
+	// RolloutStrategy is the rollout strategy type (RollingUpdate, OnDelete).
+	RolloutStrategy string `json:"rolloutStrategy,omitempty"`
+
+	// InfrastructureTemplate is the Kind/name of the infrastructure machine template.
+	InfrastructureTemplate string `json:"infrastructureTemplate,omitempty"`
+
+	// FailureDomain is the failure domain the machines are placed in, if pinned.
+	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// Machines contains the machines of the deployment, sorted by name.
+	Machines []MachineItem `json:"machines"`
+}
+
+// UpgradeStatusOutput represents the output for the capi_upgrade_status tool.
+type UpgradeStatusOutput struct {
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace.
+	Namespace string `json:"namespace"`
+
+	// Release is the desired Giant Swarm release version of the cluster.
+	Release string `json:"release,omitempty"`
+
+	// DesiredVersion is the desired Kubernetes version of the cluster.
+	DesiredVersion string `json:"desiredVersion,omitempty"`
+
+	// Status is the overall upgrade status (Completed, InProgress, Unknown).
+	Status string `json:"status"`
+
+	// Done indicates that the control plane and all node pools run the desired version.
+	Done bool `json:"done"`
+
+	// Message provides a human-readable summary of the upgrade progress.
+	Message string `json:"message"`
+
+	// ControlPlane contains the upgrade progress of the control plane.
+	ControlPlane ComponentUpgrade `json:"controlPlane"`
+
+	// NodePools contains the upgrade progress of each node pool, sorted by name.
+	NodePools []NodePoolUpgrade `json:"nodePools"`
+}
+
+// ComponentUpgrade contains the upgrade progress of a set of machines.
+type ComponentUpgrade struct {
+	// Versions counts the machines per Kubernetes version.
+	Versions map[string]int `json:"versions,omitempty"`
+
+	// Machines is the number of machines.
+	Machines int `json:"machines"`
+
+	// UpToDate is the number of machines running the desired version.
+	UpToDate int `json:"upToDate"`
+
+	// Done indicates that every machine runs the desired version.
+	Done bool `json:"done"`
+
+	// Message provides additional context.
+	Message string `json:"message,omitempty"`
+}
+
+// NodePoolUpgrade contains the upgrade progress of a MachineDeployment or MachinePool.
+type NodePoolUpgrade struct {
+	ComponentUpgrade
+
+	// Name is the node pool name.
+	Name string `json:"name"`
+
+	// Kind is MachineDeployment or MachinePool.
+	Kind string `json:"kind"`
+
+	// Release is the Giant Swarm release label of the node pool, if set.
+	Release string `json:"release,omitempty"`
+
+	// TemplateVersion is the Kubernetes version of the node pool's machine template.
+	TemplateVersion string `json:"templateVersion,omitempty"`
+
+	// Replicas contains the replica counts of the node pool.
+	Replicas NodePoolReplicas `json:"replicas"`
+}
+
+// ClusterEventsOutput represents the output for the capi_cluster_events tool.
+type ClusterEventsOutput struct {
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace.
+	Namespace string `json:"namespace"`
+
+	// Timeline contains the conditions and events, newest first.
+	Timeline []TimelineEntry `json:"timeline"`
+
+	// TotalCount is the number of entries (before pagination).
+	TotalCount int `json:"totalCount"`
+
+	// Truncated indicates whether the oldest entries were dropped due to limit.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Unavailable lists the sources that could not be read, such as an
+	// infrastructure cluster the user may not get.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// TimelineEntry is a condition transition or event of a cluster object.
+type TimelineEntry struct {
+	// Time is when the condition last transitioned or the event last occurred.
+	Time time.Time `json:"time"`
+
+	// Source is condition or event.
+	Source string `json:"source"`
+
+	// Object is the Kind/name of the object the entry is about.
+	Object string `json:"object"`
+
+	// Type is the condition type, or the event type (Normal, Warning).
+	Type string `json:"type"`
+
+	// Status is the condition status (True, False, Unknown); empty for events.
+	Status string `json:"status,omitempty"`
+
+	// Reason is the machine-readable reason.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human-readable detail.
+	Message string `json:"message,omitempty"`
+
+	// Count is how often the event occurred.
+	Count int `json:"count,omitempty"`
+}
+
+// AppListOutput represents the output for the capi_list_apps tool.
+type AppListOutput struct {
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace holding the cluster's App objects.
+	Namespace string `json:"namespace"`
+
+	// Apps contains the apps, sorted by name.
+	Apps []AppItem `json:"apps"`
+
+	// Statuses counts the matching apps per release status, before the limit is applied.
+	Statuses map[string]int `json:"statuses,omitempty"`
+
+	// TotalCount is the number of matching apps (before pagination).
+	TotalCount int `json:"totalCount"`
+
+	// ReturnedCount is the number of apps returned in this response.
+	ReturnedCount int `json:"returnedCount"`
+
+	// Truncated indicates whether the results were truncated due to limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// AppItem represents a single Giant Swarm App.
+type AppItem struct {
+	// Name is the App object name.
+	Name string `json:"name"`
+
+	// App is the name of the app in its catalog (spec.name).
+	App string `json:"app"`
+
+	// Catalog is the Catalog the app is installed from.
+	Catalog string `json:"catalog,omitempty"`
+
+	// Version is the desired app version (spec.version).
+	Version string `json:"version,omitempty"`
+
+	// DeployedVersion is the app version currently deployed (status.version).
+	DeployedVersion string `json:"deployedVersion,omitempty"`
+
+	// AppVersion is the version of the application the deployed chart ships.
+	AppVersion string `json:"appVersion,omitempty"`
+
+	// TargetNamespace is the workload cluster namespace the app is deployed to.
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// Status is the Helm release status, such as deployed or failed.
+	Status string `json:"status"`
+
+	// LatestVersion is the latest version of the app in its catalog, when
+	// the catalog can be read.
+	LatestVersion string `json:"latestVersion,omitempty"`
+
+	// UpgradeAvailable indicates that LatestVersion is newer than Version.
+	UpgradeAvailable bool `json:"upgradeAvailable,omitempty"`
+
+	// Age is the human-readable age of the App.
+	Age string `json:"age"`
+}
+
+// AppDetailOutput represents the output for the capi_get_app tool.
+type AppDetailOutput struct {
+	AppItem
+
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace holding the App object.
+	Namespace string `json:"namespace"`
+
+	// CatalogNamespace is the namespace of the Catalog.
+	CatalogNamespace string `json:"catalogNamespace"`
+
+	// Reason explains the release status, such as the error of a failed release.
+	Reason string `json:"reason,omitempty"`
+
+	// LastDeployed is the time the release was last deployed.
+	LastDeployed string `json:"lastDeployed,omitempty"`
+
+	// ConfigMaps lists the ConfigMaps configuring the app, as namespace/name.
+	ConfigMaps []string `json:"configMaps,omitempty"`
+
+	// Secrets lists the Secrets configuring the app, as namespace/name.
+	Secrets []string `json:"secrets,omitempty"`
+
+	// Conditions lists the conditions that report a problem or ongoing change.
+	Conditions []ConditionSummary `json:"conditions,omitempty"`
+
+	// AvailableVersions lists the catalog versions newer than Version, newest first.
+	AvailableVersions []string `json:"availableVersions,omitempty"`
+}
+
+// AppUpgradeOutput represents the output for the capi_upgrade_app tool.
+type AppUpgradeOutput struct {
+	// Cluster is the workload cluster name.
+	Cluster string `json:"cluster"`
+
+	// Namespace is the organization namespace holding the App object.
+	Namespace string `json:"namespace"`
+
+	// Name is the App object name.
+	Name string `json:"name"`
+
+	// App is the name of the app in its catalog.
+	App string `json:"app"`
+
+	// Catalog is the Catalog the app is installed from.
+	Catalog string `json:"catalog,omitempty"`
+
+	// PreviousVersion is the app version before the upgrade.
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// Version is the requested app version.
+	Version string `json:"version"`
+
+	// VersionVerified indicates that Version was found in the catalog.
+	VersionVerified bool `json:"versionVerified"`
+
+	// DryRun indicates that the change was validated but not persisted.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Message provides a human-readable summary of the result.
+	Message string `json:"message"`
+}
+
+// Timeline entry sources.
+const (
+	// TimelineSourceCondition marks an entry taken from status.conditions.
+	TimelineSourceCondition = "condition"
+
+	// TimelineSourceEvent marks an entry taken from a Kubernetes Event.
+	TimelineSourceEvent = "event"
+)
+
+// Upgrade status constants.
+const (
+	// UpgradeStatusCompleted indicates the cluster runs the desired version everywhere.
+	UpgradeStatusCompleted = "Completed"
+
+	// UpgradeStatusInProgress indicates some machines do not run the desired version yet.
+	UpgradeStatusInProgress = "InProgress"
+
+	// UpgradeStatusUnknown indicates the desired version cannot be determined.
+	UpgradeStatusUnknown = "Unknown"
+)
+
+// App status constants.
+const (
+	// AppStatusUnknown indicates an App whose release has not reported a status yet.
+	AppStatusUnknown = "unknown"
+)
+
 // Health status constants.
 const (
 	// HealthStatusHealthy indicates the cluster is healthy.
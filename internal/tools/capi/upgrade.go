@@ -0,0 +1,171 @@
+package capi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// handleUpgradeStatus handles the capi_upgrade_status tool request.
+// It compares the desired Kubernetes version and release of a cluster with
+// the versions its control plane and node pool machines run.
+func handleUpgradeStatus(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	cluster, dynamicClient, errResult := resolveClusterScope(ctx, request, sc)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	machines, err := listClusterMachines(ctx, dynamicClient, cluster, nil)
+	if err != nil {
+		return handleCAPIObjectError(err, "list machines")
+	}
+
+	pools, err := listNodePools(ctx, dynamicClient, cluster)
+	if err != nil {
+		return handleCAPIObjectError(err, "list node pools")
+	}
+
+	var controlPlane []MachineItem
+	poolMachines := make(map[string][]MachineItem)
+	for _, machine := range machines {
+		switch {
+		case machine.ControlPlane:
+			controlPlane = append(controlPlane, machine)
+		case machine.NodePool != "":
+			poolMachines[machine.NodePool] = append(poolMachines[machine.NodePool], machine)
+		}
+	}
+
+	output := UpgradeStatusOutput{
+		Cluster:        cluster.Name,
+		Namespace:      cluster.Namespace,
+		Release:        cluster.Release,
+		DesiredVersion: cluster.KubernetesVersion,
+		ControlPlane:   machineUpgrade(controlPlane, cluster.KubernetesVersion),
+		NodePools:      make([]NodePoolUpgrade, 0, len(pools)),
+	}
+	if len(controlPlane) == 0 {
+		output.ControlPlane.Message = "No control plane machines found; the control plane may be managed by the infrastructure provider"
+	}
+	for _, pool := range pools {
+		output.NodePools = append(output.NodePools,
+			nodePoolUpgrade(pool.obj, pool.kind, poolMachines[pool.obj.GetName()], cluster))
+	}
+
+	output.Status, output.Done, output.Message = summarizeUpgrade(&output)
+
+	return formatJSONResult(output)
+}
+
+// machineUpgrade counts the machines per version and those running the
+// desired version.
+func machineUpgrade(machines []MachineItem, desired string) ComponentUpgrade {
+	upgrade := ComponentUpgrade{Machines: len(machines)}
+	if len(machines) > 0 {
+		upgrade.Versions = make(map[string]int)
+	}
+	for _, machine := range machines {
+		upgrade.Versions[machine.Version]++
+		if desired != "" && sameVersion(machine.Version, desired) {
+			upgrade.UpToDate++
+		}
+	}
+	upgrade.Done = desired != "" && len(machines) > 0 && upgrade.UpToDate == len(machines)
+	return upgrade
+}
+
+// nodePoolUpgrade reports the rollout progress of a MachineDeployment or
+// MachinePool towards the cluster's desired version and release. A pool is
+// done when its template has the desired version and every replica is up
+// to date with it.
+func nodePoolUpgrade(obj *unstructured.Unstructured, kind string, machines []MachineItem, cluster *federation.ClusterSummary) NodePoolUpgrade {
+	pool := nodePoolFromUnstructured(obj, kind)
+	desired := cluster.KubernetesVersion
+
+	upgrade := NodePoolUpgrade{
+		Name:            pool.Name,
+		Kind:            kind,
+		Release:         obj.GetLabels()[federation.LabelGiantSwarmRelease],
+		TemplateVersion: pool.Version,
+		Replicas:        pool.Replicas,
+	}
+
+	templateCurrent := desired != "" && sameVersion(pool.Version, desired)
+	if len(machines) > 0 {
+		upgrade.ComponentUpgrade = machineUpgrade(machines, desired)
+	} else {
+		// MachinePools of some providers have no Machine objects; rely on
+		// the pool's replica counts instead.
+		upgrade.Machines = pool.Replicas.Current
+		if templateCurrent {
+			upgrade.UpToDate = pool.Replicas.UpToDate
+		}
+		upgrade.Done = true
+	}
+
+	var pending []string
+	if !templateCurrent {
+		pending = append(pending, fmt.Sprintf("template version %s does not match desired version %s", displayVersion(pool.Version), displayVersion(desired)))
+	}
+	if upgrade.Release != "" && cluster.Release != "" && upgrade.Release != cluster.Release {
+		pending = append(pending, fmt.Sprintf("release %s does not match cluster release %s", upgrade.Release, cluster.Release))
+	}
+	if pool.Replicas.UpToDate < pool.Replicas.Desired || pool.Replicas.Current > pool.Replicas.Desired {
+		pending = append(pending, fmt.Sprintf("%d of %d replicas up to date, %d replicas exist", pool.Replicas.UpToDate, pool.Replicas.Desired, pool.Replicas.Current))
+	}
+	if upgrade.UpToDate < upgrade.Machines {
+		pending = append(pending, fmt.Sprintf("%d of %d machines run the desired version", upgrade.UpToDate, upgrade.Machines))
+	}
+
+	upgrade.Done = upgrade.Done && len(pending) == 0
+	if len(pending) > 0 {
+		upgrade.Message = "Rollout in progress: " + strings.Join(pending, "; ")
+	} else {
+		upgrade.Message = "Rollout complete"
+	}
+	return upgrade
+}
+
+// summarizeUpgrade derives the overall upgrade status from the control
+// plane and node pools.
+func summarizeUpgrade(output *UpgradeStatusOutput) (string, bool, string) {
+	if output.DesiredVersion == "" {
+		return UpgradeStatusUnknown, false, "The desired Kubernetes version of the cluster cannot be determined"
+	}
+	if output.ControlPlane.Machines == 0 {
+		return UpgradeStatusUnknown, false, "The control plane version cannot be determined because the cluster has no control plane machines"
+	}
+
+	poolsDone := 0
+	for _, pool := range output.NodePools {
+		if pool.Done {
+			poolsDone++
+		}
+	}
+
+	if output.ControlPlane.Done && poolsDone == len(output.NodePools) {
+		return UpgradeStatusCompleted, true, fmt.Sprintf("The control plane and all %d node pools run Kubernetes %s",
+			len(output.NodePools), displayVersion(output.DesiredVersion))
+	}
+	return UpgradeStatusInProgress, false, fmt.Sprintf("Upgrade to Kubernetes %s in progress: %d of %d control plane machines and %d of %d node pools done",
+		displayVersion(output.DesiredVersion), output.ControlPlane.UpToDate, output.ControlPlane.Machines, poolsDone, len(output.NodePools))
+}
+
+// sameVersion compares Kubernetes versions, ignoring a leading "v".
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// displayVersion formats a Kubernetes version for messages.
+func displayVersion(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return "v" + strings.TrimPrefix(v, "v")
+}
@@ -0,0 +1,123 @@
+package capi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+)
+
+// upgradeObjects returns a prod-wc-01 cluster upgrading to 1.28.5 (the
+// version in testdata.CreateTestClusters): the control plane is done, the
+// "worker" pool is half rolled out and the "spot" pool is done.
+func upgradeObjects() []runtime.Object {
+	machine := func(name, pool, version string, controlPlane bool) runtime.Object {
+		labels := machineLabels("prod-wc-01", pool)
+		if controlPlane {
+			labels[federation.LabelCAPIControlPlane] = ""
+		}
+		return capiObject("Machine", name, labels, map[string]interface{}{"version": version}, map[string]interface{}{"phase": "Running"})
+	}
+	pool := func(kind, name string, replicas, upToDate int64) runtime.Object {
+		labels := machineLabels("prod-wc-01", "")
+		labels[federation.LabelGiantSwarmRelease] = "20.1.0"
+		return capiObject(kind, name, labels,
+			map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{"spec": map[string]interface{}{"version": "v1.28.5"}},
+			},
+			map[string]interface{}{"replicas": replicas, "readyReplicas": replicas, "upToDateReplicas": upToDate})
+	}
+
+	return []runtime.Object{
+		machine("cp-1", "", "v1.28.5", true),
+		machine("cp-2", "", "v1.28.5", true),
+		machine("worker-a", "worker", "v1.28.5", false),
+		machine("worker-b", "worker", "v1.27.9", false),
+		pool("MachineDeployment", "worker", 2, 1),
+		pool("MachinePool", "spot", 3, 3),
+	}
+}
+
+func TestHandleUpgradeStatus_InProgress(t *testing.T) {
+	sc := newMachinesServerContext(t, upgradeObjects()...)
+
+	result := callCAPITool(t, handleUpgradeStatus, sc, map[string]interface{}{"cluster": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output UpgradeStatusOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+
+	assert.Equal(t, "1.28.5", output.DesiredVersion)
+	assert.Equal(t, "20.1.0", output.Release)
+	assert.Equal(t, UpgradeStatusInProgress, output.Status)
+	assert.False(t, output.Done)
+	assert.Equal(t, "Upgrade to Kubernetes v1.28.5 in progress: 2 of 2 control plane machines and 1 of 2 node pools done", output.Message)
+	assert.True(t, output.ControlPlane.Done)
+
+	require.Len(t, output.NodePools, 2)
+	spot := output.NodePools[0]
+	assert.Equal(t, "spot", spot.Name)
+	assert.True(t, spot.Done)
+	assert.Equal(t, 3, spot.UpToDate, "machine-less pools use the replica counts")
+
+	worker := output.NodePools[1]
+	assert.False(t, worker.Done)
+	assert.Equal(t, map[string]int{"v1.28.5": 1, "v1.27.9": 1}, worker.Versions)
+	assert.Equal(t, 1, worker.UpToDate)
+	assert.Contains(t, worker.Message, "1 of 2 replicas up to date")
+}
+
+func TestHandleUpgradeStatus_Completed(t *testing.T) {
+	objects := upgradeObjects()
+	objects[3] = capiObject("Machine", "worker-b", machineLabels("prod-wc-01", "worker"),
+		map[string]interface{}{"version": "v1.28.5"}, map[string]interface{}{"phase": "Running"})
+	objects[4] = capiObject("MachineDeployment", "worker", machineLabels("prod-wc-01", ""),
+		map[string]interface{}{
+			"replicas": int64(2),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"version": "v1.28.5"}},
+		},
+		map[string]interface{}{"replicas": int64(2), "upToDateReplicas": int64(2)})
+	sc := newMachinesServerContext(t, objects...)
+
+	result := callCAPITool(t, handleUpgradeStatus, sc, map[string]interface{}{"cluster": "prod-wc-01"})
+	require.False(t, result.IsError, getResultText(result))
+
+	var output UpgradeStatusOutput
+	require.NoError(t, json.Unmarshal([]byte(getResultText(result)), &output))
+	assert.Equal(t, UpgradeStatusCompleted, output.Status)
+	assert.True(t, output.Done)
+	assert.Equal(t, "The control plane and all 2 node pools run Kubernetes v1.28.5", output.Message)
+}
+
+func TestNodePoolUpgrade_ReleaseMismatch(t *testing.T) {
+	labels := machineLabels("prod-wc-01", "")
+	labels[federation.LabelGiantSwarmRelease] = "20.0.0"
+	obj := capiObject("MachineDeployment", "worker", labels,
+		map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"version": "v1.28.5"}},
+		},
+		map[string]interface{}{"replicas": int64(1), "upToDateReplicas": int64(1)})
+
+	upgrade := nodePoolUpgrade(obj, kindMachineDeployment, nil, &federation.ClusterSummary{
+		KubernetesVersion: "1.28.5",
+		Release:           "20.1.0",
+	})
+	assert.False(t, upgrade.Done)
+	assert.Equal(t, "Rollout in progress: release 20.0.0 does not match cluster release 20.1.0", upgrade.Message)
+}
+
+func TestSummarizeUpgrade_Unknown(t *testing.T) {
+	status, done, _ := summarizeUpgrade(&UpgradeStatusOutput{})
+	assert.Equal(t, UpgradeStatusUnknown, status)
+	assert.False(t, done)
+
+	status, _, message := summarizeUpgrade(&UpgradeStatusOutput{DesiredVersion: "1.28.5"})
+	assert.Equal(t, UpgradeStatusUnknown, status)
+	assert.Contains(t, message, "no control plane machines")
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/capture"
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// recordDebugCapture records a finished tool call, sanitized, when debug
+// capture is enabled.
+func recordDebugCapture(
+	ctx context.Context,
+	sc *server.ServerContext,
+	toolName string,
+	request mcp.CallToolRequest,
+	start time.Time,
+	result *mcp.CallToolResult,
+	err error,
+) {
+	recorder := sc.DebugCapture()
+	if recorder == nil {
+		return
+	}
+	entry := capture.Entry{
+		Time:      start,
+		Tool:      toolName,
+		TraceID:   instrumentation.GetTraceID(ctx),
+		Duration:  time.Since(start),
+		Arguments: recorder.SanitizeArguments(request.GetArguments()),
+	}
+	if session := mcpserver.ClientSessionFromContext(ctx); session != nil {
+		entry.Session = session.SessionID()
+	}
+	if user, ok := oauth.UserInfoFromContext(ctx); ok && user != nil && user.Email != "" {
+		entry.User = federation.AnonymizeEmail(user.Email)
+	}
+	if err != nil {
+		entry.IsError = true
+		entry.Error = recorder.SanitizeText(err.Error())
+	}
+	if result != nil {
+		entry.IsError = entry.IsError || result.IsError
+		entry.Result = recorder.SanitizeText(resultText(result))
+	}
+	recorder.Record(entry)
+}
+
+// resultText returns the text content of a result.
+func resultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, c := range result.Content {
+		if t, ok := c.(mcp.TextContent); ok {
+			if text != "" {
+				text += "\n"
+			}
+			text += t.Text
+		}
+	}
+	return text
+}
@@ -0,0 +1,550 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the autoscaling tool's limit and
+// eventsLimit params.
+const (
+	DefaultAutoscalingLimit       = 50
+	MaxAutoscalingLimit           = 500
+	DefaultAutoscalingEventsLimit = 5
+	MaxAutoscalingEventsLimit     = 50
+)
+
+// vpaAPIGroup is the API group of the VerticalPodAutoscaler CRD.
+const vpaAPIGroup = "autoscaling.k8s.io"
+
+// AutoscalingOutput is the response of the autoscaling tool.
+type AutoscalingOutput struct {
+	Namespace string `json:"namespace,omitempty"`
+
+	HPAs          []HPAInsight `json:"hpas"`
+	TotalHPAs     int          `json:"totalHPAs"`
+	HPAsTruncated bool         `json:"hpasTruncated,omitempty"`
+
+	// VPAs is set when the VerticalPodAutoscaler CRD is installed.
+	VPAs []VPAInsight `json:"vpas,omitempty"`
+
+	// Unavailable lists the data that could not be read, such as events
+	// the user may not list.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// HPAInsight condenses a HorizontalPodAutoscaler.
+type HPAInsight struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Target is the scaled workload as Kind/name.
+	Target          string `json:"target"`
+	MinReplicas     int32  `json:"minReplicas"`
+	MaxReplicas     int32  `json:"maxReplicas"`
+	CurrentReplicas int32  `json:"currentReplicas"`
+	DesiredReplicas int32  `json:"desiredReplicas"`
+	LastScaleTime   string `json:"lastScaleTime,omitempty"`
+
+	Metrics    []HPAMetric    `json:"metrics"`
+	Conditions []HPACondition `json:"conditions,omitempty"`
+	Events     []HPAEvent     `json:"events,omitempty"`
+
+	// Warnings point out why the HPA is not scaling as expected, e.g. it
+	// sits at maxReplicas or cannot read its metrics.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// HPAMetric is a metric of an HPA with its current value and target, e.g.
+// cpu at 92% of a 80% target.
+type HPAMetric struct {
+	Name string `json:"name"`
+
+	// Current is empty when the metric could not be read.
+	Current string `json:"current,omitempty"`
+	Target  string `json:"target"`
+}
+
+// HPACondition is a condition of an HPA, e.g. ScalingLimited.
+type HPACondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// HPAEvent is a recent event about an HPA, newest first.
+type HPAEvent struct {
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen string `json:"lastSeen,omitempty"`
+}
+
+// VPAInsight condenses a VerticalPodAutoscaler and its recommendations.
+type VPAInsight struct {
+	Namespace  string         `json:"namespace"`
+	Name       string         `json:"name"`
+	Target     string         `json:"target"`
+	UpdateMode string         `json:"updateMode"`
+	Containers []VPAContainer `json:"containers,omitempty"`
+}
+
+// VPAContainer is the recommendation for one container. Each map holds cpu
+// and memory quantities.
+type VPAContainer struct {
+	Name       string            `json:"name"`
+	Target     map[string]string `json:"target,omitempty"`
+	LowerBound map[string]string `json:"lowerBound,omitempty"`
+	UpperBound map[string]string `json:"upperBound,omitempty"`
+}
+
+// vpaObject holds the fields of a VerticalPodAutoscaler the tool reads. The
+// CRD has no Go types in the API module.
+type vpaObject struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		TargetRef struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"targetRef"`
+		UpdatePolicy *struct {
+			UpdateMode string `json:"updateMode"`
+		} `json:"updatePolicy"`
+	} `json:"spec"`
+	Status struct {
+		Recommendation *struct {
+			ContainerRecommendations []struct {
+				ContainerName string              `json:"containerName"`
+				Target        corev1.ResourceList `json:"target"`
+				LowerBound    corev1.ResourceList `json:"lowerBound"`
+				UpperBound    corev1.ResourceList `json:"upperBound"`
+			} `json:"containerRecommendations"`
+		} `json:"recommendation"`
+	} `json:"status"`
+}
+
+// autoscalingRequest holds the validated autoscaling tool arguments.
+type autoscalingRequest struct {
+	cluster     string
+	kubeContext string
+	namespace   string
+	name        string
+	events      bool
+	eventsLimit int
+	limit       int
+}
+
+// handleAutoscaling handles the autoscaling tool.
+func handleAutoscaling(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseAutoscalingRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	out, err := inspectAutoscaling(ctx, client.K8s(), req)
+	if err != nil {
+		return tools.K8sError("Failed to list HorizontalPodAutoscalers", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal autoscaling report: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseAutoscalingRequest validates the tool arguments. name requires a
+// namespace, and limit and eventsLimit must lie within 1 and their maximum.
+func parseAutoscalingRequest(args map[string]interface{}) (autoscalingRequest, string) {
+	req := autoscalingRequest{
+		cluster:     tools.ExtractClusterParam(args),
+		events:      true,
+		eventsLimit: DefaultAutoscalingEventsLimit,
+		limit:       DefaultAutoscalingLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	req.name, _ = args["name"].(string)
+	if req.name != "" && req.namespace == "" {
+		return req, "name requires namespace"
+	}
+	if v, ok := args["includeEvents"].(bool); ok {
+		req.events = v
+	}
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxAutoscalingLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxAutoscalingLimit)
+		}
+		req.limit = int(v)
+	}
+	if v, ok := args["eventsLimit"].(float64); ok {
+		if v < 1 || v > MaxAutoscalingEventsLimit {
+			return req, fmt.Sprintf("eventsLimit must be between 1 and %d", MaxAutoscalingEventsLimit)
+		}
+		req.eventsLimit = int(v)
+	}
+	return req, ""
+}
+
+// inspectAutoscaling reads the HPAs in scope, their events and the VPAs of
+// the same namespaces.
+func inspectAutoscaling(ctx context.Context, client k8s.Client, req autoscalingRequest) (*AutoscalingOutput, error) {
+	out := &AutoscalingOutput{Namespace: req.namespace, HPAs: []HPAInsight{}}
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == ""}
+	if req.name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", req.name).String()
+	}
+
+	var hpas []*autoscalingv2.HorizontalPodAutoscaler
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "horizontalpodautoscalers", "autoscaling/v2", opts, func(hpa *autoscalingv2.HorizontalPodAutoscaler) {
+		hpas = append(hpas, hpa)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(hpas, func(i, j int) bool {
+		if hpas[i].Namespace != hpas[j].Namespace {
+			return hpas[i].Namespace < hpas[j].Namespace
+		}
+		return hpas[i].Name < hpas[j].Name
+	})
+	out.TotalHPAs = len(hpas)
+	if len(hpas) > req.limit {
+		hpas = hpas[:req.limit]
+		out.HPAsTruncated = true
+	}
+
+	vpas, err := listVPAs(ctx, client, req)
+	switch {
+	case err == nil:
+		out.VPAs = vpas
+	case errors.Is(err, k8s.ErrUnknownResourceType):
+		// The VPA CRD is not installed.
+	default:
+		out.Unavailable = append(out.Unavailable, fmt.Sprintf("verticalpodautoscalers: %v", err))
+	}
+
+	var events map[string][]corev1.Event
+	if req.events && len(hpas) > 0 {
+		events, err = hpaEvents(ctx, client, req)
+		if err != nil {
+			out.Unavailable = append(out.Unavailable, fmt.Sprintf("events: %v", err))
+		}
+	}
+
+	for _, hpa := range hpas {
+		insight := hpaInsight(hpa)
+		insight.Events = limitEvents(events[hpa.Namespace+"/"+hpa.Name], req.eventsLimit)
+		for _, vpa := range out.VPAs {
+			if vpa.Namespace == hpa.Namespace && vpa.Target == insight.Target && vpa.UpdateMode != "Off" && hpaScalesOnCPUOrMemory(hpa) {
+				insight.Warnings = append(insight.Warnings, fmt.Sprintf("VPA %s also resizes %s in %s mode; an HPA on cpu or memory and an active VPA on the same workload work against each other", vpa.Name, vpa.Target, vpa.UpdateMode))
+			}
+		}
+		out.HPAs = append(out.HPAs, insight)
+	}
+	return out, nil
+}
+
+// hpaInsight condenses one HPA, pairing each metric target with its current
+// value and explaining the conditions that hold scaling back.
+func hpaInsight(hpa *autoscalingv2.HorizontalPodAutoscaler) HPAInsight {
+	insight := HPAInsight{
+		Namespace:       hpa.Namespace,
+		Name:            hpa.Name,
+		Target:          hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+		MinReplicas:     1,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         []HPAMetric{},
+	}
+	if hpa.Spec.MinReplicas != nil {
+		insight.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	if hpa.Status.LastScaleTime != nil {
+		insight.LastScaleTime = hpa.Status.LastScaleTime.UTC().Format(time.RFC3339)
+	}
+
+	current := map[string]autoscalingv2.MetricStatus{}
+	for _, status := range hpa.Status.CurrentMetrics {
+		current[metricStatusName(status)] = status
+	}
+	var missing []string
+	for _, spec := range hpa.Spec.Metrics {
+		name := metricSpecName(spec)
+		metric := HPAMetric{Name: name, Target: formatMetricTarget(metricSpecTarget(spec))}
+		if status, ok := current[name]; ok {
+			metric.Current = formatMetricValue(metricStatusValue(status), metricSpecTarget(spec).Type)
+		}
+		if metric.Current == "" {
+			missing = append(missing, name)
+		}
+		insight.Metrics = append(insight.Metrics, metric)
+	}
+
+	for _, c := range hpa.Status.Conditions {
+		insight.Conditions = append(insight.Conditions, HPACondition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+		switch {
+		case c.Type == autoscalingv2.AbleToScale && c.Status == corev1.ConditionFalse:
+			insight.Warnings = append(insight.Warnings, fmt.Sprintf("cannot scale the target: %s", c.Reason))
+		case c.Type == autoscalingv2.ScalingActive && c.Status == corev1.ConditionFalse:
+			insight.Warnings = append(insight.Warnings, fmt.Sprintf("scaling is inactive: %s", c.Reason))
+		}
+	}
+	if len(missing) > 0 && len(hpa.Status.Conditions) > 0 {
+		insight.Warnings = append(insight.Warnings, fmt.Sprintf("no current value for %s; check that the metrics API serves it", strings.Join(missing, ", ")))
+	}
+	if insight.MaxReplicas > 0 && insight.CurrentReplicas >= insight.MaxReplicas {
+		insight.Warnings = append(insight.Warnings, fmt.Sprintf("at maxReplicas (%d); raise it if the load keeps exceeding the targets", insight.MaxReplicas))
+	}
+	return insight
+}
+
+// metricSpecName names a metric the same way for its spec and status, e.g.
+// "cpu", "cpu (container app)", "pods/http_requests",
+// "Ingress/main/requests" or "external/queue_depth".
+func metricSpecName(spec autoscalingv2.MetricSpec) string {
+	switch {
+	case spec.Resource != nil:
+		return string(spec.Resource.Name)
+	case spec.ContainerResource != nil:
+		return fmt.Sprintf("%s (container %s)", spec.ContainerResource.Name, spec.ContainerResource.Container)
+	case spec.Pods != nil:
+		return "pods/" + spec.Pods.Metric.Name
+	case spec.Object != nil:
+		return fmt.Sprintf("%s/%s/%s", spec.Object.DescribedObject.Kind, spec.Object.DescribedObject.Name, spec.Object.Metric.Name)
+	case spec.External != nil:
+		return "external/" + spec.External.Metric.Name
+	}
+	return string(spec.Type)
+}
+
+func metricStatusName(status autoscalingv2.MetricStatus) string {
+	switch {
+	case status.Resource != nil:
+		return string(status.Resource.Name)
+	case status.ContainerResource != nil:
+		return fmt.Sprintf("%s (container %s)", status.ContainerResource.Name, status.ContainerResource.Container)
+	case status.Pods != nil:
+		return "pods/" + status.Pods.Metric.Name
+	case status.Object != nil:
+		return fmt.Sprintf("%s/%s/%s", status.Object.DescribedObject.Kind, status.Object.DescribedObject.Name, status.Object.Metric.Name)
+	case status.External != nil:
+		return "external/" + status.External.Metric.Name
+	}
+	return string(status.Type)
+}
+
+func metricSpecTarget(spec autoscalingv2.MetricSpec) autoscalingv2.MetricTarget {
+	switch {
+	case spec.Resource != nil:
+		return spec.Resource.Target
+	case spec.ContainerResource != nil:
+		return spec.ContainerResource.Target
+	case spec.Pods != nil:
+		return spec.Pods.Target
+	case spec.Object != nil:
+		return spec.Object.Target
+	case spec.External != nil:
+		return spec.External.Target
+	}
+	return autoscalingv2.MetricTarget{}
+}
+
+func metricStatusValue(status autoscalingv2.MetricStatus) autoscalingv2.MetricValueStatus {
+	switch {
+	case status.Resource != nil:
+		return status.Resource.Current
+	case status.ContainerResource != nil:
+		return status.ContainerResource.Current
+	case status.Pods != nil:
+		return status.Pods.Current
+	case status.Object != nil:
+		return status.Object.Current
+	case status.External != nil:
+		return status.External.Current
+	}
+	return autoscalingv2.MetricValueStatus{}
+}
+
+// formatMetricTarget renders a target like kubectl: "80%" for utilization,
+// "500m (avg)" for an average value and "10" for a value.
+func formatMetricTarget(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String() + " (avg)"
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return ""
+}
+
+// formatMetricValue renders a current value in the form of its target.
+func formatMetricValue(value autoscalingv2.MetricValueStatus, targetType autoscalingv2.MetricTargetType) string {
+	quantity := func(q *resource.Quantity) string {
+		if q == nil {
+			return ""
+		}
+		return q.String()
+	}
+	switch targetType {
+	case autoscalingv2.UtilizationMetricType:
+		if value.AverageUtilization != nil {
+			return fmt.Sprintf("%d%%", *value.AverageUtilization)
+		}
+	case autoscalingv2.AverageValueMetricType:
+		if v := quantity(value.AverageValue); v != "" {
+			return v + " (avg)"
+		}
+	case autoscalingv2.ValueMetricType:
+		return quantity(value.Value)
+	}
+	return ""
+}
+
+// hpaScalesOnCPUOrMemory reports whether an HPA scales on cpu or memory,
+// the resources a VPA also changes.
+func hpaScalesOnCPUOrMemory(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	for _, spec := range hpa.Spec.Metrics {
+		var name corev1.ResourceName
+		switch {
+		case spec.Resource != nil:
+			name = spec.Resource.Name
+		case spec.ContainerResource != nil:
+			name = spec.ContainerResource.Name
+		}
+		if name == corev1.ResourceCPU || name == corev1.ResourceMemory {
+			return true
+		}
+	}
+	return false
+}
+
+// hpaEvents lists the events about HPAs in scope, keyed by
+// namespace/name.
+func hpaEvents(ctx context.Context, client k8s.Client, req autoscalingRequest) (map[string][]corev1.Event, error) {
+	selector := fields.OneTermEqualSelector("involvedObject.kind", "HorizontalPodAutoscaler")
+	if req.name != "" {
+		selector = fields.AndSelectors(selector, fields.OneTermEqualSelector("involvedObject.name", req.name))
+	}
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == "", FieldSelector: selector.String()}
+
+	events := map[string][]corev1.Event{}
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "events", "", opts, func(ev *corev1.Event) {
+		key := ev.InvolvedObject.Namespace + "/" + ev.InvolvedObject.Name
+		events[key] = append(events[key], *ev)
+	})
+	return events, err
+}
+
+// limitEvents returns the newest n events.
+func limitEvents(events []corev1.Event, n int) []HPAEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).After(eventTime(events[j]))
+	})
+	if len(events) > n {
+		events = events[:n]
+	}
+	out := make([]HPAEvent, 0, len(events))
+	for _, ev := range events {
+		e := HPAEvent{Type: ev.Type, Reason: ev.Reason, Message: ev.Message, Count: ev.Count}
+		if t := eventTime(ev); !t.IsZero() {
+			e.LastSeen = t.UTC().Format(time.RFC3339)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// eventTime returns when an event last happened.
+func eventTime(ev corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	}
+	return ev.FirstTimestamp.Time
+}
+
+// listVPAs lists the VerticalPodAutoscalers in scope with their
+// recommendations.
+func listVPAs(ctx context.Context, client k8s.Client, req autoscalingRequest) ([]VPAInsight, error) {
+	var vpas []VPAInsight
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "verticalpodautoscalers", vpaAPIGroup, k8s.ListOptions{AllNamespaces: req.namespace == ""}, func(vpa *vpaObject) {
+		insight := VPAInsight{
+			Namespace:  vpa.Namespace,
+			Name:       vpa.Name,
+			Target:     vpa.Spec.TargetRef.Kind + "/" + vpa.Spec.TargetRef.Name,
+			UpdateMode: "Auto",
+		}
+		if vpa.Spec.UpdatePolicy != nil && vpa.Spec.UpdatePolicy.UpdateMode != "" {
+			insight.UpdateMode = vpa.Spec.UpdatePolicy.UpdateMode
+		}
+		if rec := vpa.Status.Recommendation; rec != nil {
+			for _, c := range rec.ContainerRecommendations {
+				insight.Containers = append(insight.Containers, VPAContainer{
+					Name:       c.ContainerName,
+					Target:     quantities(c.Target),
+					LowerBound: quantities(c.LowerBound),
+					UpperBound: quantities(c.UpperBound),
+				})
+			}
+		}
+		vpas = append(vpas, insight)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(vpas, func(i, j int) bool {
+		if vpas[i].Namespace != vpas[j].Namespace {
+			return vpas[i].Namespace < vpas[j].Namespace
+		}
+		return vpas[i].Name < vpas[j].Name
+	})
+	return vpas, nil
+}
+
+// quantities renders a resource list as strings, or nil when it is empty.
+func quantities(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(list))
+	for name, q := range list {
+		out[string(name)] = q.String()
+	}
+	return out
+}
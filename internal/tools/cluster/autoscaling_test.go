@@ -0,0 +1,198 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func newAutoscalingClient() *mcptest.FakeClient {
+	two := int32(2)
+	eighty := int32(80)
+	ninetyTwo := int32(92)
+	avg := resource.MustParse("100")
+
+	web := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+			MinReplicas:    &two,
+			MaxReplicas:    10,
+			Metrics: []autoscalingv2.MetricSpec{
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &eighty},
+				}},
+				{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "http_requests"},
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &avg},
+				}},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 10,
+			DesiredReplicas: 10,
+			LastScaleTime:   &metav1.Time{Time: time.Date(2026, 5, 10, 11, 0, 0, 0, time.UTC)},
+			CurrentMetrics: []autoscalingv2.MetricStatus{
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2.MetricValueStatus{AverageUtilization: &ninetyTwo},
+				}},
+			},
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.AbleToScale, Status: corev1.ConditionTrue, Reason: "ReadyForNewScale"},
+				{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas"},
+			},
+		},
+	}
+	api := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "api"},
+			MaxReplicas:    5,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 1, DesiredReplicas: 1},
+	}
+
+	event := func(name, reason string, minutesAgo int) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: fmt.Sprintf("%s.%d", name, minutesAgo)},
+			InvolvedObject: corev1.ObjectReference{Kind: "HorizontalPodAutoscaler", Namespace: "shop", Name: name},
+			Type:           corev1.EventTypeNormal,
+			Reason:         reason,
+			LastTimestamp:  metav1.Time{Time: time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC).Add(-time.Duration(minutesAgo) * time.Minute)},
+		}
+	}
+
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling.k8s.io/v1",
+		"kind":       "VerticalPodAutoscaler",
+		"metadata":   map[string]interface{}{"namespace": "shop", "name": "web-vpa"},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{"kind": "Deployment", "name": "web"},
+		},
+		"status": map[string]interface{}{
+			"recommendation": map[string]interface{}{
+				"containerRecommendations": []interface{}{
+					map[string]interface{}{
+						"containerName": "web",
+						"target":        map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+						"upperBound":    map[string]interface{}{"cpu": "1"},
+					},
+				},
+			},
+		},
+	}}
+
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"horizontalpodautoscalers": {web, api},
+		"events":                   {event("web", "SuccessfulRescale", 50), event("web", "SuccessfulRescale", 10), event("web", "SuccessfulRescale", 30)},
+		"verticalpodautoscalers":   {vpa},
+	})
+}
+
+func TestInspectAutoscaling(t *testing.T) {
+	client := newAutoscalingClient()
+	req, errMsg := parseAutoscalingRequest(map[string]interface{}{"namespace": "shop", "eventsLimit": float64(2)})
+	require.Empty(t, errMsg)
+	out, err := inspectAutoscaling(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, out.TotalHPAs)
+	require.Len(t, out.HPAs, 2)
+	assert.Equal(t, "api", out.HPAs[0].Name)
+	assert.Equal(t, int32(1), out.HPAs[0].MinReplicas, "minReplicas defaults to 1")
+	assert.Empty(t, out.HPAs[0].Warnings)
+
+	web := out.HPAs[1]
+	assert.Equal(t, "Deployment/web", web.Target)
+	assert.Equal(t, int32(2), web.MinReplicas)
+	assert.Equal(t, "2026-05-10T11:00:00Z", web.LastScaleTime)
+	assert.Equal(t, []HPAMetric{
+		{Name: "cpu", Current: "92%", Target: "80%"},
+		{Name: "pods/http_requests", Target: "100 (avg)"},
+	}, web.Metrics)
+	require.Len(t, web.Conditions, 2)
+	assert.Equal(t, "TooManyReplicas", web.Conditions[1].Reason)
+
+	require.Len(t, web.Events, 2)
+	assert.Equal(t, "2026-05-10T11:50:00Z", web.Events[0].LastSeen, "newest first")
+	assert.Equal(t, "2026-05-10T11:30:00Z", web.Events[1].LastSeen)
+
+	require.Len(t, web.Warnings, 3)
+	assert.Contains(t, web.Warnings[0], "no current value for pods/http_requests")
+	assert.Contains(t, web.Warnings[1], "at maxReplicas (10)")
+	assert.Contains(t, web.Warnings[2], "VPA web-vpa also resizes Deployment/web in Auto mode")
+
+	require.Len(t, out.VPAs, 1)
+	assert.Equal(t, VPAInsight{
+		Namespace:  "shop",
+		Name:       "web-vpa",
+		Target:     "Deployment/web",
+		UpdateMode: "Auto",
+		Containers: []VPAContainer{{
+			Name:       "web",
+			Target:     map[string]string{"cpu": "250m", "memory": "256Mi"},
+			UpperBound: map[string]string{"cpu": "1"},
+		}},
+	}, out.VPAs[0])
+	assert.Empty(t, out.Unavailable)
+
+	assert.Equal(t, "involvedObject.kind=HorizontalPodAutoscaler", client.ListOpts["events"][0].FieldSelector)
+	assert.False(t, client.ListOpts["horizontalpodautoscalers"][0].AllNamespaces)
+}
+
+func TestInspectAutoscaling_MissingVPAAndEvents(t *testing.T) {
+	client := newAutoscalingClient()
+	client.Errs = map[string]error{
+		"verticalpodautoscalers": fmt.Errorf("%w: verticalpodautoscalers", k8s.ErrUnknownResourceType),
+		"events":                 fmt.Errorf("events is forbidden"),
+	}
+	req, _ := parseAutoscalingRequest(map[string]interface{}{"namespace": "shop", "name": "web"})
+	out, err := inspectAutoscaling(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Nil(t, out.VPAs)
+	assert.Equal(t, []string{"events: events is forbidden"}, out.Unavailable, "a cluster without the VPA CRD is not an error")
+	assert.Equal(t, "metadata.name=web", client.ListOpts["horizontalpodautoscalers"][0].FieldSelector)
+	for _, hpa := range out.HPAs {
+		for _, w := range hpa.Warnings {
+			assert.NotContains(t, w, "VPA")
+		}
+	}
+}
+
+func TestParseAutoscalingRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "name without namespace", args: map[string]interface{}{"name": "web"}, wantErr: "name requires namespace"},
+		{name: "limit too small", args: map[string]interface{}{"limit": float64(0)}, wantErr: "limit must be between"},
+		{name: "eventsLimit too large", args: map[string]interface{}{"eventsLimit": float64(MaxAutoscalingEventsLimit + 1)}, wantErr: "eventsLimit must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseAutoscalingRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+
+	req, errMsg := parseAutoscalingRequest(map[string]interface{}{"includeEvents": false})
+	require.Empty(t, errMsg)
+	assert.False(t, req.events)
+}
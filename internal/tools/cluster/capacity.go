@@ -0,0 +1,580 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Limits for the capacity tool.
+const (
+	// DefaultCapacityNodesLimit is the default cap on the number of nodes returned.
+	DefaultCapacityNodesLimit = 20
+
+	// MaxCapacityNodesLimit is the absolute maximum allowed for nodesLimit.
+	MaxCapacityNodesLimit = 1000
+
+	// DefaultCapacityNamespacesLimit is the default cap on the number of
+	// namespaces returned.
+	DefaultCapacityNamespacesLimit = 20
+
+	// MaxCapacityNamespacesLimit is the absolute maximum allowed for namespacesLimit.
+	MaxCapacityNamespacesLimit = 1000
+
+	// capacityAllClusters selects every cluster the caller can access.
+	capacityAllClusters = "*"
+
+	// capacityListPageSize is the page size used to list nodes and pods.
+	capacityListPageSize = 500
+
+	// capacityFleetConcurrency bounds the clusters analysed in parallel.
+	capacityFleetConcurrency = 5
+)
+
+// activePodsFieldSelector skips completed pods, which no longer hold their
+// resource requests.
+const activePodsFieldSelector = "status.phase!=Succeeded,status.phase!=Failed"
+
+// ResourceAmounts is an amount of CPU and memory.
+type ResourceAmounts struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryMiB     int64 `json:"memoryMiB"`
+}
+
+// ResourcePercent is a share of allocatable CPU and memory, in percent.
+type ResourcePercent struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+// CapacitySummary aggregates the capacity of all selected nodes.
+type CapacitySummary struct {
+	Nodes            int `json:"nodes"`
+	SchedulableNodes int `json:"schedulableNodes"`
+
+	// Allocatable is summed over all selected nodes.
+	Allocatable ResourceAmounts `json:"allocatable"`
+
+	// Requested and Limits are summed over the active pods on those nodes.
+	// Containers without a limit do not add to Limits.
+	Requested ResourceAmounts `json:"requested"`
+	Limits    ResourceAmounts `json:"limits"`
+
+	RequestedPercent ResourcePercent `json:"requestedPercent"`
+	LimitsPercent    ResourcePercent `json:"limitsPercent"`
+
+	// Headroom is the unrequested capacity on ready, schedulable nodes.
+	Headroom ResourceAmounts `json:"headroom"`
+
+	// LargestNodeHeadroom is the most CPU and the most memory still
+	// requestable on a single ready, schedulable node, so callers can tell
+	// whether a pod of a given size fits anywhere. The two values may come
+	// from different nodes.
+	LargestNodeHeadroom ResourceAmounts `json:"largestNodeHeadroom"`
+
+	Pods        int   `json:"pods"`
+	PodCapacity int64 `json:"podCapacity"`
+
+	// PendingPods are active pods not yet bound to a node, across the whole
+	// cluster, and PendingRequests is what they ask for.
+	PendingPods     int             `json:"pendingPods"`
+	PendingRequests ResourceAmounts `json:"pendingRequests"`
+}
+
+// NodeCapacity is the capacity of a single node.
+type NodeCapacity struct {
+	Name        string `json:"name"`
+	Ready       bool   `json:"ready"`
+	Schedulable bool   `json:"schedulable"`
+
+	Allocatable      ResourceAmounts `json:"allocatable"`
+	Requested        ResourceAmounts `json:"requested"`
+	Limits           ResourceAmounts `json:"limits"`
+	Headroom         ResourceAmounts `json:"headroom"`
+	RequestedPercent ResourcePercent `json:"requestedPercent"`
+	LimitsPercent    ResourcePercent `json:"limitsPercent"`
+
+	Pods        int   `json:"pods"`
+	PodCapacity int64 `json:"podCapacity"`
+}
+
+// NamespaceCapacity is the resource usage of the pods in a namespace.
+type NamespaceCapacity struct {
+	Name      string          `json:"name"`
+	Pods      int             `json:"pods"`
+	Requested ResourceAmounts `json:"requested"`
+	Limits    ResourceAmounts `json:"limits"`
+}
+
+// CapacityOutput is the response of the capacity tool for one cluster.
+// Nodes are sorted busiest first (by the higher of their CPU and memory
+// requested percentages) and namespaces by CPU requests, largest first.
+type CapacityOutput struct {
+	// Cluster is set for fleet queries.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Error is set when a cluster of a fleet query could not be analysed.
+	Error string `json:"error,omitempty"`
+
+	Summary *CapacitySummary `json:"summary,omitempty"`
+
+	Nodes          []NodeCapacity `json:"nodes,omitempty"`
+	TotalNodes     int            `json:"totalNodes"`
+	NodesTruncated bool           `json:"nodesTruncated,omitempty"`
+
+	Namespaces          []NamespaceCapacity `json:"namespaces,omitempty"`
+	TotalNamespaces     int                 `json:"totalNamespaces"`
+	NamespacesTruncated bool                `json:"namespacesTruncated,omitempty"`
+}
+
+// FleetCapacityOutput is the response of the capacity tool across clusters.
+type FleetCapacityOutput struct {
+	Clusters          []CapacityOutput `json:"clusters"`
+	TotalClusters     int              `json:"totalClusters"`
+	FailedClusters    int              `json:"failedClusters"`
+	ClustersTruncated bool             `json:"clustersTruncated,omitempty"`
+}
+
+// capacityRequest holds the validated capacity tool arguments.
+type capacityRequest struct {
+	cluster         string
+	kubeContext     string
+	clusters        []string
+	nodeSelector    string
+	nodesLimit      int
+	namespacesLimit int
+}
+
+// amounts is an exact amount of CPU (millicores) and memory (bytes).
+type amounts struct {
+	cpu int64
+	mem int64
+}
+
+func (a amounts) plus(b amounts) amounts {
+	return amounts{cpu: a.cpu + b.cpu, mem: a.mem + b.mem}
+}
+
+func (a amounts) minus(b amounts) amounts {
+	return amounts{cpu: max(a.cpu-b.cpu, 0), mem: max(a.mem-b.mem, 0)}
+}
+
+func (a amounts) max(b amounts) amounts {
+	return amounts{cpu: max(a.cpu, b.cpu), mem: max(a.mem, b.mem)}
+}
+
+func (a amounts) output() ResourceAmounts {
+	return ResourceAmounts{CPUMillicores: a.cpu, MemoryMiB: a.mem / (1 << 20)}
+}
+
+func (a amounts) percentOf(total amounts) ResourcePercent {
+	return ResourcePercent{CPU: percent(a.cpu, total.cpu), Memory: percent(a.mem, total.mem)}
+}
+
+func amountsFromList(list corev1.ResourceList) amounts {
+	return amounts{cpu: list.Cpu().MilliValue(), mem: list.Memory().Value()}
+}
+
+// percent returns part as a percentage of total, rounded to one decimal.
+func percent(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// handleCapacity handles the capacity tool.
+func handleCapacity(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseCapacityRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	var result interface{}
+	if req.clusters != nil {
+		fleet, toolErr := fleetCapacity(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = fleet
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out, err := analyzeCapacity(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to analyse capacity", err, client.User()).Result(), nil
+		}
+		result = out
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal capacity: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseCapacityRequest validates the tool arguments: nodesLimit and
+// namespacesLimit must lie within 1 and their maximum, and nodeSelector must
+// parse as a label selector.
+func parseCapacityRequest(args map[string]interface{}) (capacityRequest, string) {
+	req := capacityRequest{
+		cluster:         tools.ExtractClusterParam(args),
+		nodesLimit:      DefaultCapacityNodesLimit,
+		namespacesLimit: DefaultCapacityNamespacesLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+
+	if v, ok := args["nodesLimit"].(float64); ok {
+		if v < 1 || v > MaxCapacityNodesLimit {
+			return req, fmt.Sprintf("nodesLimit must be between 1 and %d", MaxCapacityNodesLimit)
+		}
+		req.nodesLimit = int(v)
+	}
+	if v, ok := args["namespacesLimit"].(float64); ok {
+		if v < 1 || v > MaxCapacityNamespacesLimit {
+			return req, fmt.Sprintf("namespacesLimit must be between 1 and %d", MaxCapacityNamespacesLimit)
+		}
+		req.namespacesLimit = int(v)
+	}
+
+	req.nodeSelector, _ = args["nodeSelector"].(string)
+	if req.nodeSelector != "" {
+		if _, err := labels.Parse(req.nodeSelector); err != nil {
+			return req, fmt.Sprintf("invalid nodeSelector: %v", err)
+		}
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// fleetCapacity analyses every requested cluster in parallel. A cluster that
+// fails is reported in the output instead of failing the whole call.
+func fleetCapacity(ctx context.Context, sc *server.ServerContext, req capacityRequest) (*FleetCapacityOutput, *toolerrors.Error) {
+	names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	out := &FleetCapacityOutput{
+		TotalClusters:     total,
+		ClustersTruncated: len(names) < total,
+		Clusters:          make([]CapacityOutput, len(names)),
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(capacityFleetConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			result := CapacityOutput{Cluster: name}
+			client, toolErr := tools.GetClusterClient(gctx, sc, name)
+			if toolErr != nil {
+				result.Error = toolErr.Message
+			} else {
+				clusterReq := req
+				clusterReq.cluster = name
+				analysed, err := analyzeCapacity(gctx, client.K8s(), clusterReq)
+				if err != nil {
+					result.Error = tools.FormatK8sError("Failed to analyse capacity", err, client.User())
+				} else {
+					result = *analysed
+					result.Cluster = name
+				}
+			}
+
+			mu.Lock()
+			out.Clusters[i] = result
+			if result.Error != "" {
+				out.FailedClusters++
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return out, nil
+}
+
+// resolveFleetClusters expands the clusters argument of a fleet query into
+// sorted cluster names, capped by the output maxClusters setting. It also
+// returns the number of clusters before the cap. Every cluster is checked
+// with tools.CheckClusterAccess: a denied cluster named in clusters denies
+// the query, and one of "all" is left out.
+func resolveFleetClusters(ctx context.Context, sc *server.ServerContext, clusters []string) ([]string, int, *toolerrors.Error) {
+	all := len(clusters) == 1 && clusters[0] == capacityAllClusters
+	candidates := clusters
+	if all {
+		var toolErr *toolerrors.Error
+		candidates, toolErr = tools.ListAccessibleClusters(ctx, sc)
+		if toolErr != nil {
+			return nil, 0, toolErr
+		}
+	} else if sc.FederationManager() == nil {
+		return nil, 0, toolerrors.New(toolerrors.CodeNotEnabled, "multi-cluster operations require federation mode to be enabled")
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if msg := tools.CheckClusterAccess(ctx, sc, name); msg != "" {
+			if all {
+				continue
+			}
+			return nil, 0, toolerrors.Newf(toolerrors.CodePolicyDenied, "cluster %s: %s", name, msg)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	total := len(names)
+	if maxClusters := sc.OutputConfig().MaxClusters; maxClusters > 0 && len(names) > maxClusters {
+		names = names[:maxClusters]
+	}
+	return names, total, nil
+}
+
+// parseClustersArg splits the comma-separated clusters argument of a fleet
+// query. It returns nil when the argument is not set.
+func parseClustersArg(args map[string]interface{}, cluster string) ([]string, string) {
+	value, _ := args["clusters"].(string)
+	if value == "" {
+		return nil, ""
+	}
+	if cluster != "" {
+		return nil, "specify either cluster or clusters, not both"
+	}
+	clusters := []string{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			clusters = append(clusters, name)
+		}
+	}
+	if len(clusters) == 0 {
+		return nil, "clusters must name at least one cluster"
+	}
+	return clusters, ""
+}
+
+// nodeUsage accumulates the pods bound to a node or running in a namespace.
+type nodeUsage struct {
+	node      *corev1.Node
+	requested amounts
+	limits    amounts
+	pods      int
+}
+
+func (u *nodeUsage) add(requested, limits amounts) {
+	u.requested = u.requested.plus(requested)
+	u.limits = u.limits.plus(limits)
+	u.pods++
+}
+
+// analyzeCapacity lists the cluster's nodes and active pods and aggregates
+// allocatable against requested and limited resources.
+func analyzeCapacity(ctx context.Context, client k8s.Client, req capacityRequest) (*CapacityOutput, error) {
+	nodes := map[string]*nodeUsage{}
+	err := listAll(ctx, client, req.kubeContext, "", "nodes", "", k8s.ListOptions{LabelSelector: req.nodeSelector}, func(node *corev1.Node) {
+		nodes[node.Name] = &nodeUsage{node: node}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	summary := &CapacitySummary{}
+	var pending amounts
+	namespaces := map[string]*nodeUsage{}
+	err = listAll(ctx, client, req.kubeContext, "", "pods", "", k8s.ListOptions{AllNamespaces: true, FieldSelector: activePodsFieldSelector}, func(pod *corev1.Pod) {
+		requested := podAmounts(&pod.Spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests })
+		if pod.Spec.NodeName == "" {
+			summary.PendingPods++
+			pending = pending.plus(requested)
+			return
+		}
+		usage, ok := nodes[pod.Spec.NodeName]
+		if !ok {
+			// Bound to a node outside nodeSelector.
+			return
+		}
+		limits := podAmounts(&pod.Spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits })
+		usage.add(requested, limits)
+
+		ns, ok := namespaces[pod.Namespace]
+		if !ok {
+			ns = &nodeUsage{}
+			namespaces[pod.Namespace] = ns
+		}
+		ns.add(requested, limits)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	summary.PendingRequests = pending.output()
+
+	var allocatable, requested, limits, headroom, largest amounts
+	nodeOut := make([]NodeCapacity, 0, len(nodes))
+	for _, usage := range nodes {
+		alloc := amountsFromList(usage.node.Status.Allocatable)
+		entry := NodeCapacity{
+			Name:             usage.node.Name,
+			Ready:            nodeReady(usage.node),
+			Schedulable:      !usage.node.Spec.Unschedulable,
+			Allocatable:      alloc.output(),
+			Requested:        usage.requested.output(),
+			Limits:           usage.limits.output(),
+			Headroom:         alloc.minus(usage.requested).output(),
+			RequestedPercent: usage.requested.percentOf(alloc),
+			LimitsPercent:    usage.limits.percentOf(alloc),
+			Pods:             usage.pods,
+			PodCapacity:      usage.node.Status.Allocatable.Pods().Value(),
+		}
+		nodeOut = append(nodeOut, entry)
+
+		allocatable = allocatable.plus(alloc)
+		requested = requested.plus(usage.requested)
+		limits = limits.plus(usage.limits)
+		summary.Pods += usage.pods
+		summary.PodCapacity += entry.PodCapacity
+		if entry.Ready && entry.Schedulable {
+			summary.SchedulableNodes++
+			free := alloc.minus(usage.requested)
+			headroom = headroom.plus(free)
+			largest = largest.max(free)
+		}
+	}
+	summary.Nodes = len(nodes)
+	summary.Allocatable = allocatable.output()
+	summary.Requested = requested.output()
+	summary.Limits = limits.output()
+	summary.RequestedPercent = requested.percentOf(allocatable)
+	summary.LimitsPercent = limits.percentOf(allocatable)
+	summary.Headroom = headroom.output()
+	summary.LargestNodeHeadroom = largest.output()
+
+	sort.Slice(nodeOut, func(i, j int) bool {
+		bi, bj := busiest(nodeOut[i].RequestedPercent), busiest(nodeOut[j].RequestedPercent)
+		if bi != bj {
+			return bi > bj
+		}
+		return nodeOut[i].Name < nodeOut[j].Name
+	})
+
+	nsOut := make([]NamespaceCapacity, 0, len(namespaces))
+	for name, usage := range namespaces {
+		nsOut = append(nsOut, NamespaceCapacity{
+			Name:      name,
+			Pods:      usage.pods,
+			Requested: usage.requested.output(),
+			Limits:    usage.limits.output(),
+		})
+	}
+	sort.Slice(nsOut, func(i, j int) bool {
+		if nsOut[i].Requested.CPUMillicores != nsOut[j].Requested.CPUMillicores {
+			return nsOut[i].Requested.CPUMillicores > nsOut[j].Requested.CPUMillicores
+		}
+		return nsOut[i].Name < nsOut[j].Name
+	})
+
+	out := &CapacityOutput{
+		Summary:         summary,
+		TotalNodes:      len(nodeOut),
+		TotalNamespaces: len(nsOut),
+	}
+	if len(nodeOut) > req.nodesLimit {
+		nodeOut = nodeOut[:req.nodesLimit]
+		out.NodesTruncated = true
+	}
+	if len(nsOut) > req.namespacesLimit {
+		nsOut = nsOut[:req.namespacesLimit]
+		out.NamespacesTruncated = true
+	}
+	out.Nodes = nodeOut
+	out.Namespaces = nsOut
+	return out, nil
+}
+
+// busiest returns the higher of the CPU and memory percentages.
+func busiest(p ResourcePercent) float64 {
+	return math.Max(p.CPU, p.Memory)
+}
+
+// nodeReady reports whether the node's Ready condition is true.
+func nodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podAmounts returns the effective resources of a pod the way the scheduler
+// accounts for them: the larger of the regular containers (plus sidecars)
+// and any single init container (plus the sidecars started before it), plus
+// the pod overhead. Pod-level resources, when set, take precedence.
+func podAmounts(spec *corev1.PodSpec, pick func(corev1.ResourceRequirements) corev1.ResourceList) amounts {
+	var containers, sidecars, initMax amounts
+	for _, c := range spec.Containers {
+		containers = containers.plus(amountsFromList(pick(c.Resources)))
+	}
+	for _, c := range spec.InitContainers {
+		a := amountsFromList(pick(c.Resources))
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars = sidecars.plus(a)
+			continue
+		}
+		initMax = initMax.max(a.plus(sidecars))
+	}
+	total := containers.plus(sidecars).max(initMax)
+
+	if spec.Resources != nil {
+		podLevel := pick(*spec.Resources)
+		if _, ok := podLevel[corev1.ResourceCPU]; ok {
+			total.cpu = podLevel.Cpu().MilliValue()
+		}
+		if _, ok := podLevel[corev1.ResourceMemory]; ok {
+			total.mem = podLevel.Memory().Value()
+		}
+	}
+	return total.plus(amountsFromList(spec.Overhead))
+}
+
+// listAll pages through a resource list and converts each item to T.
+func listAll[T any](ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType, apiGroup string, opts k8s.ListOptions, fn func(*T)) error {
+	opts.Limit = capacityListPageSize
+	for {
+		resp, err := client.List(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			obj := new(T)
+			if err := fromObject(item, obj); err != nil {
+				return err
+			}
+			fn(obj)
+		}
+		if resp.Continue == "" {
+			return nil
+		}
+		opts.Continue = resp.Continue
+	}
+}
@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func capacityNode(name, cpu, mem string, ready, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": "general"}},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(mem),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func capacityPod(namespace, node string, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: namespace},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: containers},
+	}
+}
+
+func container(cpuReq, memReq, cpuLim, memLim string) corev1.Container {
+	c := corev1.Container{Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}}
+	if cpuReq != "" {
+		c.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpuReq)
+	}
+	if memReq != "" {
+		c.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(memReq)
+	}
+	if cpuLim != "" {
+		c.Resources.Limits[corev1.ResourceCPU] = resource.MustParse(cpuLim)
+	}
+	if memLim != "" {
+		c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(memLim)
+	}
+	return c
+}
+
+// newCapacityClient returns a client serving one node or pod per page to
+// exercise paging.
+func newCapacityClient() *mcptest.FakeClient {
+	client := mcptest.NewFakeClient(map[string][]runtime.Object{
+		"nodes": {
+			capacityNode("node-a", "4", "8Gi", true, false),
+			capacityNode("node-b", "4", "8Gi", true, false),
+			capacityNode("node-c", "2", "4Gi", true, true),
+		},
+		"pods": {
+			capacityPod("shop", "node-a", container("2", "2Gi", "4", "4Gi"), container("500m", "", "", "")),
+			capacityPod("shop", "node-b", container("1", "1Gi", "", "2Gi")),
+			capacityPod("monitoring", "node-b", container("250m", "3Gi", "", "")),
+			capacityPod("shop", "", container("8", "1Gi", "", "")),
+		},
+	})
+	client.PageSize = 1
+	return client
+}
+
+func TestCapacity(t *testing.T) {
+	client := newCapacityClient()
+	out := mcptest.DecodeResult[CapacityOutput](t, callTool(t, client, "capacity", map[string]interface{}{}))
+
+	assert.Len(t, client.ListOpts["pods"], 4, "one page per pod")
+	assert.Equal(t, activePodsFieldSelector, client.ListOpts["pods"][0].FieldSelector)
+	assert.True(t, client.ListOpts["pods"][0].AllNamespaces)
+
+	s := out.Summary
+	require.NotNil(t, s)
+	assert.Equal(t, 3, s.Nodes)
+	assert.Equal(t, 2, s.SchedulableNodes)
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 10000, MemoryMiB: 20480}, s.Allocatable)
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 3750, MemoryMiB: 6144}, s.Requested)
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 4000, MemoryMiB: 6144}, s.Limits)
+	assert.Equal(t, 37.5, s.RequestedPercent.CPU)
+	// Headroom only counts the two schedulable nodes.
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 4250, MemoryMiB: 10240}, s.Headroom)
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 2750, MemoryMiB: 6144}, s.LargestNodeHeadroom)
+	assert.Equal(t, 3, s.Pods)
+	assert.Equal(t, int64(330), s.PodCapacity)
+	assert.Equal(t, 1, s.PendingPods)
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 8000, MemoryMiB: 1024}, s.PendingRequests)
+
+	// node-b has 50% memory requested, node-a 62.5% CPU: node-a is busiest.
+	require.Len(t, out.Nodes, 3)
+	assert.Equal(t, "node-a", out.Nodes[0].Name)
+	assert.Equal(t, 62.5, out.Nodes[0].RequestedPercent.CPU)
+	assert.Equal(t, ResourceAmounts{CPUMillicores: 1500, MemoryMiB: 6144}, out.Nodes[0].Headroom)
+	assert.Equal(t, "node-b", out.Nodes[1].Name)
+	assert.Equal(t, 2, out.Nodes[1].Pods)
+	assert.False(t, out.Nodes[2].Schedulable)
+
+	require.Len(t, out.Namespaces, 2)
+	assert.Equal(t, NamespaceCapacity{
+		Name:      "shop",
+		Pods:      2,
+		Requested: ResourceAmounts{CPUMillicores: 3500, MemoryMiB: 3072},
+		Limits:    ResourceAmounts{CPUMillicores: 4000, MemoryMiB: 6144},
+	}, out.Namespaces[0])
+	assert.Equal(t, "monitoring", out.Namespaces[1].Name)
+}
+
+func TestCapacity_Limits(t *testing.T) {
+	client := newCapacityClient()
+	out := mcptest.DecodeResult[CapacityOutput](t, callTool(t, client, "capacity", map[string]interface{}{
+		"nodesLimit":      float64(1),
+		"namespacesLimit": float64(1),
+		"nodeSelector":    "pool=general",
+	}))
+	assert.Equal(t, "pool=general", client.ListOpts["nodes"][0].LabelSelector)
+	assert.Len(t, out.Nodes, 1)
+	assert.True(t, out.NodesTruncated)
+	assert.Equal(t, 3, out.TotalNodes)
+	assert.Len(t, out.Namespaces, 1)
+	assert.True(t, out.NamespacesTruncated)
+}
+
+func TestParseCapacityRequest_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "nodesLimit too large", args: map[string]interface{}{"nodesLimit": float64(MaxCapacityNodesLimit + 1)}, wantErr: "nodesLimit"},
+		{name: "namespacesLimit too small", args: map[string]interface{}{"namespacesLimit": float64(0)}, wantErr: "namespacesLimit"},
+		{name: "bad node selector", args: map[string]interface{}{"nodeSelector": "a in ("}, wantErr: "invalid nodeSelector"},
+		{name: "cluster and clusters", args: map[string]interface{}{"cluster": "a", "clusters": "b"}, wantErr: "either cluster or clusters"},
+		{name: "empty clusters", args: map[string]interface{}{"clusters": " , "}, wantErr: "at least one cluster"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseCapacityRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+
+	req, errMsg := parseCapacityRequest(map[string]interface{}{"clusters": "prod-a, prod-b"})
+	assert.Empty(t, errMsg)
+	assert.Equal(t, []string{"prod-a", "prod-b"}, req.clusters)
+}
+
+func TestCapacity_FleetRequiresFederation(t *testing.T) {
+	result := callTool(t, newCapacityClient(), "capacity", map[string]interface{}{"clusters": "prod-a"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "federation")
+}
+
+func TestCapacity_FleetDeniedCluster(t *testing.T) {
+	result := callTool(t, newCapacityClient(), "capacity", map[string]interface{}{"clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: capacity")
+}
+
+func TestPodAmounts(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	requests := func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }
+
+	sidecar := container("100m", "64Mi", "", "")
+	sidecar.RestartPolicy = &always
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			container("2", "128Mi", "", ""), // runs before the sidecar
+			sidecar,
+			container("500m", "1Gi", "", ""), // runs next to the sidecar
+		},
+		Containers: []corev1.Container{container("1", "256Mi", "", "")},
+		Overhead:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+	}
+	// CPU: max(1 + 0.1, 2) + 0.01; memory: max(256Mi + 64Mi, 1Gi + 64Mi).
+	assert.Equal(t, amounts{cpu: 2010, mem: (1024 + 64) << 20}, podAmounts(spec, requests))
+
+	spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}}
+	assert.Equal(t, amounts{cpu: 2010, mem: 2 << 30}, podAmounts(spec, requests))
+}
@@ -0,0 +1,417 @@
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Limits for the cert_expiry tool.
+const (
+	// DefaultCertExpiryDays is the default expiry window in days.
+	DefaultCertExpiryDays = 30
+
+	// MaxCertExpiryDays is the absolute maximum allowed for withinDays.
+	MaxCertExpiryDays = 3650
+
+	// DefaultCertExpiryLimit is the default cap on the number of
+	// certificates reported per cluster.
+	DefaultCertExpiryLimit = 50
+
+	// MaxCertExpiryLimit is the absolute maximum allowed for limit.
+	MaxCertExpiryLimit = 1000
+)
+
+// certManagerAPIGroup is the API group of cert-manager Certificates.
+const certManagerAPIGroup = "cert-manager.io"
+
+// Certificate statuses reported by the cert_expiry tool.
+const (
+	certExpired  = "expired"
+	certExpiring = "expiring"
+	certValid    = "valid"
+	// certNotIssued is a cert-manager Certificate whose secret holds no
+	// certificate yet.
+	certNotIssued = "not-issued"
+)
+
+// CertificateInfo is a certificate found by the cert_expiry tool, read from
+// a TLS secret or, when its secret does not exist yet, from a cert-manager
+// Certificate.
+type CertificateInfo struct {
+	Namespace string `json:"namespace"`
+	Secret    string `json:"secret"`
+
+	// Certificate is the cert-manager Certificate that manages the secret.
+	Certificate string `json:"certificate,omitempty"`
+	// IssuerRef is the cert-manager issuer, e.g. "ClusterIssuer/letsencrypt".
+	IssuerRef string `json:"issuerRef,omitempty"`
+
+	Status    string   `json:"status"`
+	Subject   string   `json:"subject,omitempty"`
+	Issuer    string   `json:"issuer,omitempty"`
+	DNSNames  []string `json:"dnsNames,omitempty"`
+	NotBefore string   `json:"notBefore,omitempty"`
+	NotAfter  string   `json:"notAfter,omitempty"`
+	// DaysLeft is negative for expired certificates.
+	DaysLeft int `json:"daysLeft"`
+
+	// RenewalTime is when cert-manager plans to renew the certificate.
+	RenewalTime string `json:"renewalTime,omitempty"`
+	// Ready is the cert-manager Ready condition; nil for secrets no
+	// Certificate manages.
+	Ready   *bool  `json:"ready,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// CertExpiryOutput is the response of the cert_expiry tool for one cluster.
+// Certificates are sorted by expiry, soonest first.
+type CertExpiryOutput struct {
+	// Cluster is set for fleet queries.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Error is set when a cluster of a fleet query could not be scanned.
+	Error string `json:"error,omitempty"`
+
+	WithinDays int `json:"withinDays"`
+	// Scanned counts the TLS secrets inspected.
+	Scanned int `json:"scanned"`
+
+	Certificates      []CertificateInfo `json:"certificates"`
+	TotalCertificates int               `json:"totalCertificates"`
+	Truncated         bool              `json:"truncated,omitempty"`
+
+	// Skipped lists secrets whose certificate could not be parsed and data
+	// that could not be read, such as cert-manager Certificates the user
+	// may not list.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// FleetCertExpiryOutput is the response of the cert_expiry tool across
+// clusters.
+type FleetCertExpiryOutput struct {
+	Clusters          []CertExpiryOutput `json:"clusters"`
+	TotalClusters     int                `json:"totalClusters"`
+	FailedClusters    int                `json:"failedClusters"`
+	ClustersTruncated bool               `json:"clustersTruncated,omitempty"`
+}
+
+// certManagerCertificate holds the fields of a cert-manager Certificate the
+// tool reads. The CRD has no Go types in the API module.
+type certManagerCertificate struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		SecretName string   `json:"secretName"`
+		DNSNames   []string `json:"dnsNames"`
+		IssuerRef  struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"issuerRef"`
+	} `json:"spec"`
+	Status struct {
+		NotAfter    *metav1.Time       `json:"notAfter"`
+		RenewalTime *metav1.Time       `json:"renewalTime"`
+		Conditions  []metav1.Condition `json:"conditions"`
+	} `json:"status"`
+}
+
+// certExpiryRequest holds the validated cert_expiry tool arguments.
+type certExpiryRequest struct {
+	cluster     string
+	kubeContext string
+	clusters    []string
+	namespace   string
+	withinDays  int
+	limit       int
+	now         time.Time
+}
+
+// handleCertExpiry handles the cert_expiry tool.
+func handleCertExpiry(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseCertExpiryRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	req.now = time.Now()
+
+	var result interface{}
+	if req.clusters != nil {
+		fleet, toolErr := fleetCertExpiry(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = fleet
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out, err := scanCertificates(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to scan certificates", err, client.User()).Result(), nil
+		}
+		result = out
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal certificates: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseCertExpiryRequest validates the tool arguments. withinDays must lie
+// within 0 and MaxCertExpiryDays and limit within 1 and MaxCertExpiryLimit.
+func parseCertExpiryRequest(args map[string]interface{}) (certExpiryRequest, string) {
+	req := certExpiryRequest{
+		cluster:    tools.ExtractClusterParam(args),
+		withinDays: DefaultCertExpiryDays,
+		limit:      DefaultCertExpiryLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if v, ok := args["withinDays"].(float64); ok {
+		if v < 0 || v > MaxCertExpiryDays {
+			return req, fmt.Sprintf("withinDays must be between 0 and %d", MaxCertExpiryDays)
+		}
+		req.withinDays = int(v)
+	}
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxCertExpiryLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxCertExpiryLimit)
+		}
+		req.limit = int(v)
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// fleetCertExpiry scans every requested cluster in parallel. A cluster that
+// fails is reported in the output instead of failing the whole call.
+func fleetCertExpiry(ctx context.Context, sc *server.ServerContext, req certExpiryRequest) (*FleetCertExpiryOutput, *toolerrors.Error) {
+	names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	out := &FleetCertExpiryOutput{
+		TotalClusters:     total,
+		ClustersTruncated: len(names) < total,
+		Clusters:          make([]CertExpiryOutput, len(names)),
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(capacityFleetConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			result := CertExpiryOutput{Cluster: name, WithinDays: req.withinDays}
+			client, toolErr := tools.GetClusterClient(gctx, sc, name)
+			if toolErr != nil {
+				result.Error = toolErr.Message
+			} else {
+				scanned, err := scanCertificates(gctx, client.K8s(), req)
+				if err != nil {
+					result.Error = tools.FormatK8sError("Failed to scan certificates", err, client.User())
+				} else {
+					result = *scanned
+					result.Cluster = name
+				}
+			}
+
+			mu.Lock()
+			out.Clusters[i] = result
+			if result.Error != "" {
+				out.FailedClusters++
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return out, nil
+}
+
+// scanCertificates reads the leaf certificate of every TLS secret in one
+// cluster and returns those expiring within the window. cert-manager
+// Certificates, where the CRD is installed, add their issuer, renewal time
+// and readiness; Certificates that are not ready are always reported. Only
+// tls.crt is read, never the private key.
+func scanCertificates(ctx context.Context, client k8s.Client, req certExpiryRequest) (*CertExpiryOutput, error) {
+	out := &CertExpiryOutput{WithinDays: req.withinDays}
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == ""}
+
+	managed := map[string]*certManagerCertificate{}
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "certificates", certManagerAPIGroup, opts, func(c *certManagerCertificate) {
+		managed[c.Namespace+"/"+c.Spec.SecretName] = c
+	})
+	if err != nil && !errors.Is(err, k8s.ErrUnknownResourceType) {
+		out.Skipped = append(out.Skipped, fmt.Sprintf("certificates.%s: %v", certManagerAPIGroup, err))
+	}
+
+	deadline := req.now.AddDate(0, 0, req.withinDays)
+	var certs []CertificateInfo
+	seen := map[string]bool{}
+	secretOpts := opts
+	secretOpts.FieldSelector = "type=" + string(corev1.SecretTypeTLS)
+	err = listAll(ctx, client, req.kubeContext, req.namespace, "secrets", "", secretOpts, func(secret *corev1.Secret) {
+		out.Scanned++
+		key := secret.Namespace + "/" + secret.Name
+		seen[key] = true
+
+		info := CertificateInfo{Namespace: secret.Namespace, Secret: secret.Name}
+		cert, err := leafCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			out.Skipped = append(out.Skipped, fmt.Sprintf("%s: %v", key, err))
+			return
+		}
+		describeCertificate(&info, cert, req.now, deadline)
+		if c := managed[key]; c != nil {
+			addCertManagerStatus(&info, c)
+		} else {
+			info.Certificate = secret.Annotations[certManagerCertificateAnnotation]
+		}
+		if cert.NotAfter.Before(deadline) || (info.Ready != nil && !*info.Ready) {
+			certs = append(certs, info)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Certificates whose secret was never written have not been issued.
+	for key, c := range managed {
+		if seen[key] {
+			continue
+		}
+		info := CertificateInfo{Namespace: c.Namespace, Secret: c.Spec.SecretName, Status: certNotIssued, DNSNames: c.Spec.DNSNames}
+		addCertManagerStatus(&info, c)
+		certs = append(certs, info)
+	}
+
+	sort.Slice(certs, func(i, j int) bool {
+		a, b := certs[i], certs[j]
+		// Not issued certificates have no expiry and sort first.
+		if (a.NotAfter == "") != (b.NotAfter == "") {
+			return a.NotAfter == ""
+		}
+		if a.NotAfter != b.NotAfter {
+			return a.NotAfter < b.NotAfter
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Secret < b.Secret
+	})
+	sort.Strings(out.Skipped)
+
+	out.TotalCertificates = len(certs)
+	if len(certs) > req.limit {
+		certs = certs[:req.limit]
+		out.Truncated = true
+	}
+	out.Certificates = certs
+	if out.Certificates == nil {
+		out.Certificates = []CertificateInfo{}
+	}
+	return out, nil
+}
+
+// leafCertificate parses the first certificate of a PEM bundle, which is the
+// leaf; the rest of the chain is ignored.
+func leafCertificate(data []byte) (*x509.Certificate, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no %s", corev1.TLSCertKey)
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate in %s", corev1.TLSCertKey)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %v", corev1.TLSCertKey, err)
+		}
+		return cert, nil
+	}
+}
+
+// describeCertificate fills in what the certificate itself says. It is
+// expiring when it expires before the deadline.
+func describeCertificate(info *CertificateInfo, cert *x509.Certificate, now, deadline time.Time) {
+	info.Subject = cert.Subject.CommonName
+	info.Issuer = cert.Issuer.CommonName
+	if info.Issuer == "" && len(cert.Issuer.Organization) > 0 {
+		info.Issuer = cert.Issuer.Organization[0]
+	}
+	info.DNSNames = append(info.DNSNames, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		info.DNSNames = append(info.DNSNames, ip.String())
+	}
+	info.NotBefore = cert.NotBefore.UTC().Format(time.RFC3339)
+	info.NotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+	info.DaysLeft = int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+
+	switch {
+	case !cert.NotAfter.After(now):
+		info.Status = certExpired
+	case cert.NotAfter.Before(deadline):
+		info.Status = certExpiring
+	default:
+		info.Status = certValid
+	}
+}
+
+// addCertManagerStatus adds what cert-manager knows about the certificate.
+func addCertManagerStatus(info *CertificateInfo, c *certManagerCertificate) {
+	info.Certificate = c.Name
+	if ref := c.Spec.IssuerRef; ref.Name != "" {
+		kind := ref.Kind
+		if kind == "" {
+			kind = "Issuer"
+		}
+		info.IssuerRef = kind + "/" + ref.Name
+	}
+	if t := c.Status.RenewalTime; t != nil {
+		info.RenewalTime = t.UTC().Format(time.RFC3339)
+	}
+	if info.NotAfter == "" && c.Status.NotAfter != nil {
+		info.NotAfter = c.Status.NotAfter.UTC().Format(time.RFC3339)
+	}
+	for _, cond := range c.Status.Conditions {
+		if cond.Type != "Ready" {
+			continue
+		}
+		ready := cond.Status == metav1.ConditionTrue
+		info.Ready = &ready
+		if !ready {
+			info.Message = strings.TrimSpace(cond.Message)
+		}
+	}
+}
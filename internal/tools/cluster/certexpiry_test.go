@@ -0,0 +1,183 @@
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// certExpiryNow is the time the cert_expiry tests run at.
+var certExpiryNow = time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+// testCertificate returns a PEM self-signed certificate for host expiring
+// after the given number of days.
+func testCertificate(t *testing.T, host string, days int) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		Issuer:       pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:    certExpiryNow.AddDate(0, 0, -90),
+		NotAfter:     certExpiryNow.AddDate(0, 0, days),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func tlsSecret(namespace, name string, cert []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: []byte("secret")},
+	}
+}
+
+func certManagerCert(namespace, name, secretName string, ready bool, message string) *unstructured.Unstructured {
+	status := "True"
+	if !ready {
+		status = "False"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+		"spec": map[string]interface{}{
+			"secretName": secretName,
+			"dnsNames":   []interface{}{name + ".example.com"},
+			"issuerRef":  map[string]interface{}{"kind": "ClusterIssuer", "name": "letsencrypt"},
+		},
+		"status": map[string]interface{}{
+			"renewalTime": "2026-05-20T12:00:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": status, "message": message, "reason": "Test", "lastTransitionTime": "2026-05-01T00:00:00Z"},
+			},
+		},
+	}}
+}
+
+func TestScanCertificates(t *testing.T) {
+	client := mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+		"secrets": {
+			tlsSecret("web", "api-tls", testCertificate(t, "api.example.com", 10)),
+			tlsSecret("web", "old-tls", testCertificate(t, "old.example.com", -3)),
+			tlsSecret("web", "fresh-tls", testCertificate(t, "fresh.example.com", 80)),
+			tlsSecret("web", "broken-tls", []byte("not a certificate")),
+		},
+		"certificates": {
+			certManagerCert("web", "api", "api-tls", true, ""),
+			certManagerCert("web", "fresh", "fresh-tls", false, "Issuing certificate as Secret was previously issued by a different issuer"),
+			certManagerCert("web", "shop", "shop-tls", false, "order is pending"),
+		},
+	})
+	req, errMsg := parseCertExpiryRequest(map[string]interface{}{})
+	require.Empty(t, errMsg)
+	req.now = certExpiryNow
+
+	out, err := scanCertificates(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, out.Scanned)
+	assert.Equal(t, 4, out.TotalCertificates)
+	require.Len(t, out.Certificates, 4)
+
+	notIssued := out.Certificates[0]
+	assert.Equal(t, "shop-tls", notIssued.Secret)
+	assert.Equal(t, certNotIssued, notIssued.Status)
+	assert.Equal(t, "order is pending", notIssued.Message)
+	assert.Equal(t, []string{"shop.example.com"}, notIssued.DNSNames)
+
+	old := out.Certificates[1]
+	assert.Equal(t, "old-tls", old.Secret)
+	assert.Equal(t, certExpired, old.Status)
+	assert.Equal(t, -3, old.DaysLeft)
+	assert.Nil(t, old.Ready)
+
+	api := out.Certificates[2]
+	assert.Equal(t, "api-tls", api.Secret)
+	assert.Equal(t, certExpiring, api.Status)
+	assert.Equal(t, 10, api.DaysLeft)
+	assert.Equal(t, "api.example.com", api.Subject)
+	assert.Equal(t, "api.example.com", api.Issuer)
+	assert.Equal(t, []string{"api.example.com", "10.0.0.1"}, api.DNSNames)
+	assert.Equal(t, "api", api.Certificate)
+	assert.Equal(t, "ClusterIssuer/letsencrypt", api.IssuerRef)
+	assert.Equal(t, "2026-05-20T12:00:00Z", api.RenewalTime)
+	require.NotNil(t, api.Ready)
+	assert.True(t, *api.Ready)
+
+	fresh := out.Certificates[3]
+	assert.Equal(t, certValid, fresh.Status, "a Certificate that is not ready is reported outside the window")
+	assert.Contains(t, fresh.Message, "different issuer")
+
+	require.Len(t, out.Skipped, 1)
+	assert.Contains(t, out.Skipped[0], "web/broken-tls: no PEM certificate")
+
+	assert.Equal(t, "type=kubernetes.io/tls", client.ListOpts["secrets"][0].FieldSelector)
+	assert.True(t, client.ListOpts["secrets"][0].AllNamespaces)
+}
+
+func TestScanCertificates_WithoutCertManager(t *testing.T) {
+	client := mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+		"secrets": {
+			tlsSecret("web", "api-tls", testCertificate(t, "api.example.com", 10)),
+			tlsSecret("web", "old-tls", testCertificate(t, "old.example.com", -3)),
+		},
+	})
+	req, _ := parseCertExpiryRequest(map[string]interface{}{"namespace": "web", "withinDays": float64(5), "limit": float64(1)})
+	req.now = certExpiryNow
+
+	out, err := scanCertificates(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Empty(t, out.Skipped, "a cluster without cert-manager is not an error")
+	assert.Equal(t, 1, out.TotalCertificates, "only the expired certificate is within 5 days")
+	assert.False(t, out.Truncated)
+	assert.Equal(t, "old-tls", out.Certificates[0].Secret)
+	assert.False(t, client.ListOpts["secrets"][0].AllNamespaces)
+}
+
+func TestParseCertExpiryRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "negative days", args: map[string]interface{}{"withinDays": float64(-1)}, wantErr: "withinDays must be between"},
+		{name: "limit too large", args: map[string]interface{}{"limit": float64(MaxCertExpiryLimit + 1)}, wantErr: "limit must be between"},
+		{name: "cluster and clusters", args: map[string]interface{}{"cluster": "a", "clusters": "b"}, wantErr: "either cluster or clusters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseCertExpiryRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+}
+
+func TestCertExpiry_FleetDeniedCluster(t *testing.T) {
+	result := callTool(t, mcptest.NewFakeClient(nil), "cert_expiry", map[string]interface{}{"clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: cert_expiry")
+}
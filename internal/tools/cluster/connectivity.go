@@ -0,0 +1,644 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Connectivity test modes.
+const (
+	// ConnectivityModeProxy checks the Service, its endpoints and HTTP
+	// reachability through the API server's service proxy. It creates
+	// nothing in the cluster.
+	ConnectivityModeProxy = "proxy"
+
+	// ConnectivityModePod runs a short-lived pod that resolves the Service
+	// name and fetches the URL from inside the cluster network, so DNS and
+	// NetworkPolicies are exercised the way a workload would see them.
+	ConnectivityModePod = "pod"
+)
+
+// Connectivity check outcomes.
+const (
+	checkPass    = "pass"
+	checkFail    = "fail"
+	checkSkipped = "skipped"
+)
+
+// Limits and defaults for the connectivity_test tool.
+const (
+	// DefaultConnectivityTimeoutSeconds bounds each probe by default.
+	DefaultConnectivityTimeoutSeconds = 10
+
+	// MaxConnectivityTimeoutSeconds is the largest accepted timeoutSeconds.
+	MaxConnectivityTimeoutSeconds = 60
+
+	// connectivityMaxBodyBytes caps the response body returned to the agent.
+	connectivityMaxBodyBytes = 1024
+
+	// connectivityTestImage runs the probes in pod mode. busybox ships
+	// nslookup and wget in a few megabytes.
+	connectivityTestImage = "busybox:1.36"
+
+	// connectivityPodStartupAllowance is added to the probe timeouts when
+	// waiting for the test pod, to cover scheduling and image pulls.
+	connectivityPodStartupAllowance = 60 * time.Second
+
+	// connectivityCleanupTimeout bounds deleting the test pod.
+	connectivityCleanupTimeout = 10 * time.Second
+
+	// connectivityMarker prefixes the lines the test pod script prints to
+	// separate probe output.
+	connectivityMarker = "@@mcp:"
+)
+
+// connectivityPollInterval is how often pod mode checks the test pod's phase.
+// A variable so tests can shorten it.
+var connectivityPollInterval = time.Second
+
+// connectivityScript runs in the test pod. Targets are passed as environment
+// variables so no caller input is interpolated into the shell script.
+const connectivityScript = `echo "@@mcp:dns"
+nslookup "$TARGET_HOST" 2>&1
+echo "@@mcp:dns:$?"
+echo "@@mcp:http"
+wget -q -T "$TIMEOUT" -O /tmp/body "$TARGET_URL" 2>&1
+echo "@@mcp:http:$?"
+echo "@@mcp:body"
+head -c 1024 /tmp/body 2>/dev/null
+`
+
+// ConnectivityTestResult is the response of the connectivity_test tool.
+type ConnectivityTestResult struct {
+	// Mode is the test mode that ran: proxy or pod.
+	Mode string `json:"mode"`
+
+	// Success is true when every check that ran passed.
+	Success bool `json:"success"`
+
+	// Target is the service endpoint that was tested.
+	Target ConnectivityTarget `json:"target"`
+
+	// Checks lists the individual checks in the order they ran.
+	Checks []ConnectivityCheck `json:"checks"`
+
+	// Pod is the test pod name in pod mode.
+	Pod string `json:"pod,omitempty"`
+
+	// Warnings reports non-fatal problems such as a failed pod cleanup.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ConnectivityTarget identifies the tested service endpoint.
+type ConnectivityTarget struct {
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	Port      string `json:"port,omitempty"`
+	Host      string `json:"host"`
+	URL       string `json:"url"`
+}
+
+// ConnectivityCheck is the outcome of a single check.
+type ConnectivityCheck struct {
+	// Name is one of service, endpoints, dns or http.
+	Name string `json:"name"`
+
+	// Status is pass, fail or skipped.
+	Status string `json:"status"`
+
+	// Message explains the outcome.
+	Message string `json:"message,omitempty"`
+
+	// StatusCode is the HTTP status of the http check in proxy mode.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Body is the start of the HTTP response body.
+	Body string `json:"body,omitempty"`
+}
+
+// connectivityRequest holds the validated tool arguments.
+type connectivityRequest struct {
+	kubeContext     string
+	namespace       string
+	service         string
+	port            string
+	path            string
+	scheme          string
+	mode            string
+	sourceNamespace string
+	timeout         time.Duration
+}
+
+// handleConnectivityTest checks whether a Service is reachable, either through
+// the API server proxy or from a short-lived pod inside the cluster.
+func handleConnectivityTest(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+
+	req, errMsg := parseConnectivityRequest(args)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	if req.mode == ConnectivityModePod {
+		// Pod mode creates and deletes a pod, so it needs both verbs.
+		for _, op := range []string{"create", "delete"} {
+			if errorResult := tools.CheckMutatingOperation(sc, op); errorResult != nil {
+				return errorResult, nil
+			}
+		}
+		if sc.Config().DryRun {
+			return toolerrors.New(toolerrors.CodeFailedPrecondition, "pod mode is unavailable in dry-run mode because the test pod would never run; use mode=proxy").Result(), nil
+		}
+		// The call's own checks only see the target namespace; the test
+		// pod is created and deleted in the source namespace.
+		testPod := &unstructured.Unstructured{}
+		testPod.SetAPIVersion("v1")
+		testPod.SetKind("Pod")
+		testPod.SetNamespace(req.sourceNamespace)
+		if msg := tools.ObjectWriteDenied(ctx, sc, "connectivity_test", args, req.sourceNamespace, testPod); msg != "" {
+			return toolerrors.New(toolerrors.CodePolicyDenied, msg).Result(), nil
+		}
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	result := &ConnectivityTestResult{
+		Mode: req.mode,
+		Target: ConnectivityTarget{
+			Namespace: req.namespace,
+			Service:   req.service,
+			Port:      req.port,
+			Host:      fmt.Sprintf("%s.%s.svc", req.service, req.namespace),
+		},
+	}
+
+	svc, check := checkService(ctx, k8sClient, req)
+	result.Checks = append(result.Checks, check)
+	if svc != nil {
+		result.Checks = append(result.Checks, checkEndpoints(ctx, k8sClient, req))
+	}
+
+	var port int32
+	if svc != nil {
+		var err error
+		port, err = resolveServicePort(svc, req.port)
+		if err != nil {
+			result.Checks = append(result.Checks, ConnectivityCheck{Name: "http", Status: checkFail, Message: err.Error()})
+		}
+	}
+	result.Target.URL = connectivityURL(req, result.Target.Host, port)
+
+	if svc != nil && port != 0 {
+		switch req.mode {
+		case ConnectivityModeProxy:
+			result.Checks = append(result.Checks,
+				ConnectivityCheck{Name: "dns", Status: checkSkipped, Message: "DNS resolution is only tested in pod mode"},
+				checkHTTPViaProxy(ctx, k8sClient, req, port))
+		case ConnectivityModePod:
+			checks, podName, warnings := runConnectivityPod(ctx, k8sClient, req, result.Target)
+			result.Checks = append(result.Checks, checks...)
+			result.Pod = podName
+			result.Warnings = append(result.Warnings, warnings...)
+		}
+	}
+
+	result.Success = true
+	for _, c := range result.Checks {
+		if c.Status == checkFail {
+			result.Success = false
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal connectivity test result: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseConnectivityRequest validates the tool arguments. namespace and
+// service must be DNS labels, port a port number or name, and path free of
+// whitespace, as pod mode passes them to a container; timeoutSeconds must
+// lie within 1 and MaxConnectivityTimeoutSeconds.
+func parseConnectivityRequest(args map[string]interface{}) (connectivityRequest, string) {
+	req := connectivityRequest{
+		mode:    ConnectivityModeProxy,
+		scheme:  "http",
+		path:    "/",
+		timeout: DefaultConnectivityTimeoutSeconds * time.Second,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	req.service, _ = args["service"].(string)
+	req.port, _ = args["port"].(string)
+	req.sourceNamespace, _ = args["sourceNamespace"].(string)
+
+	if req.namespace == "" {
+		return req, "namespace is required"
+	}
+	if errs := validation.IsDNS1123Label(req.namespace); len(errs) > 0 {
+		return req, fmt.Sprintf("invalid namespace %q: %s", req.namespace, strings.Join(errs, "; "))
+	}
+	if req.service == "" {
+		return req, "service is required"
+	}
+	if errs := validation.IsDNS1035Label(req.service); len(errs) > 0 {
+		return req, fmt.Sprintf("invalid service %q: %s", req.service, strings.Join(errs, "; "))
+	}
+	if req.port != "" {
+		if n, err := strconv.Atoi(req.port); err == nil {
+			if errs := validation.IsValidPortNum(n); len(errs) > 0 {
+				return req, fmt.Sprintf("invalid port %q: %s", req.port, strings.Join(errs, "; "))
+			}
+		} else if errs := validation.IsValidPortName(req.port); len(errs) > 0 {
+			return req, fmt.Sprintf("invalid port %q: %s", req.port, strings.Join(errs, "; "))
+		}
+	}
+	if path, ok := args["path"].(string); ok && path != "" {
+		if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t\r\n") {
+			return req, "path must start with / and must not contain whitespace"
+		}
+		req.path = path
+	}
+	if scheme, ok := args["scheme"].(string); ok && scheme != "" {
+		if scheme != "http" && scheme != "https" {
+			return req, "scheme must be http or https"
+		}
+		req.scheme = scheme
+	}
+	if mode, ok := args["mode"].(string); ok && mode != "" {
+		if mode != ConnectivityModeProxy && mode != ConnectivityModePod {
+			return req, fmt.Sprintf("mode must be %s or %s", ConnectivityModeProxy, ConnectivityModePod)
+		}
+		req.mode = mode
+	}
+	if req.sourceNamespace == "" {
+		req.sourceNamespace = req.namespace
+	} else if errs := validation.IsDNS1123Label(req.sourceNamespace); len(errs) > 0 {
+		return req, fmt.Sprintf("invalid sourceNamespace %q: %s", req.sourceNamespace, strings.Join(errs, "; "))
+	}
+	if v, ok := args["timeoutSeconds"].(float64); ok {
+		if v < 1 || v > MaxConnectivityTimeoutSeconds {
+			return req, fmt.Sprintf("timeoutSeconds must be between 1 and %d", MaxConnectivityTimeoutSeconds)
+		}
+		req.timeout = time.Duration(v) * time.Second
+	}
+	return req, ""
+}
+
+// checkService fetches the target Service.
+func checkService(ctx context.Context, client k8s.Client, req connectivityRequest) (*corev1.Service, ConnectivityCheck) {
+	check := ConnectivityCheck{Name: "service"}
+	resp, err := client.Get(ctx, req.kubeContext, req.namespace, "services", "", req.service)
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("failed to get service: %v", err)
+		return nil, check
+	}
+	svc := &corev1.Service{}
+	if err := fromObject(resp.Resource, svc); err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("failed to decode service: %v", err)
+		return nil, check
+	}
+
+	check.Status = checkPass
+	check.Message = fmt.Sprintf("type %s", svc.Spec.Type)
+	switch {
+	case svc.Spec.ClusterIP == corev1.ClusterIPNone:
+		check.Message += ", headless"
+	case svc.Spec.ClusterIP != "":
+		check.Message += ", clusterIP " + svc.Spec.ClusterIP
+	}
+	return svc, check
+}
+
+// checkEndpoints counts the ready endpoints behind the Service.
+func checkEndpoints(ctx context.Context, client k8s.Client, req connectivityRequest) ConnectivityCheck {
+	check := ConnectivityCheck{Name: "endpoints"}
+	resp, err := client.List(ctx, req.kubeContext, req.namespace, "endpointslices", "discovery.k8s.io", k8s.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + req.service,
+	})
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("failed to list endpoint slices: %v", err)
+		return check
+	}
+
+	ready, total := 0, 0
+	for _, item := range resp.Items {
+		slice := &discoveryv1.EndpointSlice{}
+		if err := fromObject(item, slice); err != nil {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			total++
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+
+	check.Message = fmt.Sprintf("%d of %d endpoints ready", ready, total)
+	if ready == 0 {
+		check.Status = checkFail
+		check.Message += "; the service selector may not match any ready pod"
+	} else {
+		check.Status = checkPass
+	}
+	return check
+}
+
+// checkHTTPViaProxy fetches the path through the API server's service proxy.
+func checkHTTPViaProxy(ctx context.Context, client k8s.Client, req connectivityRequest, port int32) ConnectivityCheck {
+	check := ConnectivityCheck{Name: "http"}
+	proxyCtx, cancel := context.WithTimeout(ctx, req.timeout)
+	defer cancel()
+
+	resp, err := client.ProxyGetService(proxyCtx, req.kubeContext, req.namespace, k8s.ServiceProxyRequest{
+		Scheme:   req.scheme,
+		Service:  req.service,
+		Port:     strconv.Itoa(int(port)),
+		Path:     req.path,
+		MaxBytes: connectivityMaxBodyBytes,
+	})
+	if err != nil {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("service proxy request failed: %v", err)
+		return check
+	}
+
+	check.StatusCode = resp.StatusCode
+	check.Body = resp.Body
+	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		check.Status = checkPass
+		check.Message = fmt.Sprintf("HTTP %d via the API server proxy", resp.StatusCode)
+	} else {
+		check.Status = checkFail
+		check.Message = fmt.Sprintf("HTTP %d via the API server proxy", resp.StatusCode)
+	}
+	return check
+}
+
+// resolveServicePort returns the port number for a port name or number, or
+// the first port when port is empty.
+func resolveServicePort(svc *corev1.Service, port string) (int32, error) {
+	if len(svc.Spec.Ports) == 0 {
+		return 0, fmt.Errorf("service %s has no ports", svc.Name)
+	}
+	if port == "" {
+		return svc.Spec.Ports[0].Port, nil
+	}
+	for _, p := range svc.Spec.Ports {
+		if p.Name == port || strconv.Itoa(int(p.Port)) == port {
+			return p.Port, nil
+		}
+	}
+	return 0, fmt.Errorf("service %s has no port %q", svc.Name, port)
+}
+
+// connectivityURL builds the in-cluster URL of the target.
+func connectivityURL(req connectivityRequest, host string, port int32) string {
+	if port == 0 {
+		return fmt.Sprintf("%s://%s%s", req.scheme, host, req.path)
+	}
+	return fmt.Sprintf("%s://%s:%d%s", req.scheme, host, port, req.path)
+}
+
+// runConnectivityPod runs the probes from a short-lived pod in the source
+// namespace and always deletes it afterwards.
+func runConnectivityPod(ctx context.Context, client k8s.Client, req connectivityRequest, target ConnectivityTarget) (checks []ConnectivityCheck, podName string, warnings []string) {
+	fail := func(msg string) []ConnectivityCheck {
+		return []ConnectivityCheck{
+			{Name: "dns", Status: checkFail, Message: msg},
+			{Name: "http", Status: checkFail, Message: msg},
+		}
+	}
+
+	created, err := client.Create(ctx, req.kubeContext, req.sourceNamespace, connectivityPod(req, target))
+	if err != nil {
+		return fail(fmt.Sprintf("failed to create test pod: %v", err)), "", nil
+	}
+	pod := &corev1.Pod{}
+	if err := fromObject(created, pod); err != nil || pod.Name == "" {
+		return fail("failed to read the created test pod's name"), "", nil
+	}
+
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), connectivityCleanupTimeout)
+		defer cancel()
+		if _, err := client.Delete(cleanupCtx, req.kubeContext, req.sourceNamespace, "pods", "", pod.Name); err != nil {
+			slog.Warn("failed to delete connectivity test pod",
+				"namespace", req.sourceNamespace, "pod", pod.Name, "error", err)
+			warnings = append(warnings, fmt.Sprintf("failed to delete test pod %s/%s: %v", req.sourceNamespace, pod.Name, err))
+		}
+	}()
+
+	phase, err := waitForPodCompletion(ctx, client, req, pod.Name)
+	if err != nil {
+		return fail(err.Error()), pod.Name, nil
+	}
+
+	logs, err := readPodLogs(ctx, client, req, pod.Name)
+	if err != nil {
+		return fail(fmt.Sprintf("test pod finished with phase %s but its logs could not be read: %v", phase, err)), pod.Name, nil
+	}
+	return parseConnectivityLog(logs), pod.Name, nil
+}
+
+// connectivityPod builds the test pod. It is locked down to satisfy the
+// restricted Pod Security Standard and capped in CPU, memory and runtime.
+func connectivityPod(req connectivityRequest, target ConnectivityTarget) *corev1.Pod {
+	timeoutSeconds := int64(req.timeout / time.Second)
+	deadline := 2*timeoutSeconds + int64(connectivityPodStartupAllowance/time.Second)
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:              resource.MustParse("50m"),
+		corev1.ResourceMemory:           resource.MustParse("32Mi"),
+		corev1.ResourceEphemeralStorage: resource.MustParse("16Mi"),
+	}
+	bodyLimit := resource.MustParse("1Mi")
+
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "mcp-connectivity-test-",
+			Namespace:    req.sourceNamespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "mcp-connectivity-test",
+				"app.kubernetes.io/managed-by": "mcp-kubernetes",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         &deadline,
+			AutomountServiceAccountToken:  ptr(false),
+			TerminationGracePeriodSeconds: ptr(int64(0)),
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   ptr(true),
+				RunAsUser:      ptr(int64(65534)),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   connectivityTestImage,
+				Command: []string{"sh", "-c", connectivityScript},
+				Env: []corev1.EnvVar{
+					{Name: "TARGET_HOST", Value: target.Host},
+					{Name: "TARGET_URL", Value: target.URL},
+					{Name: "TIMEOUT", Value: strconv.FormatInt(timeoutSeconds, 10)},
+				},
+				Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: ptr(false),
+					ReadOnlyRootFilesystem:   ptr(true),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+				VolumeMounts: []corev1.VolumeMount{{Name: "tmp", MountPath: "/tmp"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "tmp",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &bodyLimit}},
+			}},
+		},
+	}
+}
+
+// waitForPodCompletion polls the test pod until it succeeds or fails.
+func waitForPodCompletion(ctx context.Context, client k8s.Client, req connectivityRequest, name string) (corev1.PodPhase, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, 2*req.timeout+connectivityPodStartupAllowance)
+	defer cancel()
+
+	ticker := time.NewTicker(connectivityPollInterval)
+	defer ticker.Stop()
+	for {
+		resp, err := client.Get(waitCtx, req.kubeContext, req.sourceNamespace, "pods", "", name)
+		if err == nil {
+			pod := &corev1.Pod{}
+			if err := fromObject(resp.Resource, pod); err == nil {
+				if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+					return pod.Status.Phase, nil
+				}
+			}
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return "", fmt.Errorf("test pod %s did not finish in time (it may be unschedulable or unable to pull %s)", name, connectivityTestImage)
+		case <-ticker.C:
+		}
+	}
+}
+
+// readPodLogs reads the test pod's output.
+func readPodLogs(ctx context.Context, client k8s.Client, req connectivityRequest, name string) (string, error) {
+	tailLines := int64(200)
+	stream, err := client.GetLogs(ctx, req.kubeContext, req.sourceNamespace, name, "probe", k8s.LogOptions{TailLines: &tailLines})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = stream.Close() }()
+	data, err := io.ReadAll(io.LimitReader(stream, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseConnectivityLog turns the test pod script output into dns and http
+// checks.
+func parseConnectivityLog(logs string) []ConnectivityCheck {
+	dns := ConnectivityCheck{Name: "dns", Status: checkFail, Message: "no DNS result in test pod output"}
+	http := ConnectivityCheck{Name: "http", Status: checkFail, Message: "no HTTP result in test pod output"}
+
+	var section string
+	var output, body []string
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, connectivityMarker) {
+			if section == "body" {
+				body = append(body, line)
+			} else {
+				output = append(output, line)
+			}
+			continue
+		}
+
+		marker := strings.TrimPrefix(line, connectivityMarker)
+		name, code, hasCode := strings.Cut(marker, ":")
+		if !hasCode {
+			section = name
+			output = nil
+			continue
+		}
+		check := ConnectivityCheck{Name: name, Message: strings.TrimSpace(strings.Join(output, "\n"))}
+		if code == "0" {
+			check.Status = checkPass
+		} else {
+			check.Status = checkFail
+		}
+		switch name {
+		case "dns":
+			dns = check
+		case "http":
+			http = check
+		}
+	}
+
+	if http.Status == checkPass {
+		http.Message = "fetched from inside the cluster"
+		http.Body = truncateString(strings.Join(body, "\n"), connectivityMaxBodyBytes)
+	}
+	return []ConnectivityCheck{dns, http}
+}
+
+// fromObject converts a runtime.Object (typically unstructured) to a typed
+// object.
+func fromObject(obj runtime.Object, into interface{}) error {
+	if obj == nil {
+		return fmt.Errorf("empty object")
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, into)
+}
+
+// truncateString cuts s to at most n bytes.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
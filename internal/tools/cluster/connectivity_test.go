@@ -0,0 +1,405 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// connectivityMock serves a Service, its EndpointSlices, the proxy response
+// and a test pod that finishes immediately.
+type connectivityMock struct {
+	*testdata.MockK8sClient
+
+	service    *corev1.Service
+	slices     []discoveryv1.EndpointSlice
+	proxy      *k8s.ServiceProxyResponse
+	proxyReq   k8s.ServiceProxyRequest
+	podLogs    string
+	podPhase   corev1.PodPhase
+	created    *corev1.Pod
+	deleted    []string
+	deleteErr  error
+	getSvcErr  error
+	proxyError error
+}
+
+func (m *connectivityMock) Get(_ context.Context, _, _, resourceType, _, name string) (*k8s.GetResponse, error) {
+	switch resourceType {
+	case "services":
+		if m.getSvcErr != nil {
+			return nil, m.getSvcErr
+		}
+		content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(m.service)
+		return &k8s.GetResponse{Resource: &unstructured.Unstructured{Object: content}}, nil
+	case "pods":
+		pod := m.created.DeepCopy()
+		pod.Status.Phase = m.podPhase
+		content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+		return &k8s.GetResponse{Resource: &unstructured.Unstructured{Object: content}}, nil
+	}
+	return nil, errors.New("unexpected resource " + resourceType + "/" + name)
+}
+
+func (m *connectivityMock) List(_ context.Context, _, _, _, _ string, _ k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	items := make([]runtime.Object, 0, len(m.slices))
+	for i := range m.slices {
+		content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(&m.slices[i])
+		items = append(items, &unstructured.Unstructured{Object: content})
+	}
+	return &k8s.PaginatedListResponse{Items: items}, nil
+}
+
+func (m *connectivityMock) ProxyGetService(_ context.Context, _, _ string, req k8s.ServiceProxyRequest) (*k8s.ServiceProxyResponse, error) {
+	m.proxyReq = req
+	return m.proxy, m.proxyError
+}
+
+func (m *connectivityMock) Create(_ context.Context, _, namespace string, obj runtime.Object) (runtime.Object, error) {
+	pod := obj.(*corev1.Pod).DeepCopy()
+	pod.Name = pod.GenerateName + "abcde"
+	pod.Namespace = namespace
+	m.created = pod
+	return pod, nil
+}
+
+func (m *connectivityMock) GetLogs(_ context.Context, _, _, _, _ string, _ k8s.LogOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(m.podLogs)), nil
+}
+
+func (m *connectivityMock) Delete(_ context.Context, _, namespace, _, _, name string) (*k8s.DeleteResponse, error) {
+	m.deleted = append(m.deleted, namespace+"/"+name)
+	return &k8s.DeleteResponse{}, m.deleteErr
+}
+
+func newConnectivityMock() *connectivityMock {
+	ready := true
+	notReady := false
+	return &connectivityMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		service: &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.96.0.42",
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80},
+					{Name: "metrics", Port: 9090},
+				},
+			},
+		},
+		slices: []discoveryv1.EndpointSlice{{
+			Endpoints: []discoveryv1.Endpoint{
+				{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+				{Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			},
+		}},
+		proxy:    &k8s.ServiceProxyResponse{StatusCode: 200, Body: "ok"},
+		podPhase: corev1.PodSucceeded,
+	}
+}
+
+// mutatingConfig turns off non-destructive mode, as mutating tools need.
+func mutatingConfig() *server.Config {
+	config := server.NewDefaultConfig()
+	config.NonDestructiveMode = false
+	return config
+}
+
+func callConnectivityTest(t *testing.T, mock *connectivityMock, config *server.Config, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	opts := []server.Option{
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+	}
+	if config != nil {
+		opts = append(opts, server.WithConfig(config))
+	}
+	sc, err := server.NewServerContext(context.Background(), opts...)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "connectivity_test"
+	request.Params.Arguments = args
+	result, err := handleConnectivityTest(context.Background(), request, sc)
+	require.NoError(t, err)
+	return result
+}
+
+func unmarshalConnectivity(t *testing.T, result *mcp.CallToolResult) ConnectivityTestResult {
+	t.Helper()
+	require.False(t, result.IsError, "expected success result, got %v", result.Content)
+	var out ConnectivityTestResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	return out
+}
+
+func checksByName(result ConnectivityTestResult) map[string]ConnectivityCheck {
+	out := make(map[string]ConnectivityCheck, len(result.Checks))
+	for _, c := range result.Checks {
+		out[c.Name] = c
+	}
+	return out
+}
+
+func TestConnectivityTest_ProxyMode(t *testing.T) {
+	mock := newConnectivityMock()
+	out := unmarshalConnectivity(t, callConnectivityTest(t, mock, nil, map[string]interface{}{
+		"namespace": "shop",
+		"service":   "checkout",
+		"port":      "metrics",
+		"path":      "/healthz",
+	}))
+
+	assert.True(t, out.Success)
+	assert.Equal(t, ConnectivityModeProxy, out.Mode)
+	assert.Equal(t, "http://checkout.shop.svc:9090/healthz", out.Target.URL)
+	assert.Equal(t, "9090", mock.proxyReq.Port)
+	assert.Equal(t, "/healthz", mock.proxyReq.Path)
+
+	checks := checksByName(out)
+	assert.Equal(t, checkPass, checks["service"].Status)
+	assert.Contains(t, checks["service"].Message, "10.96.0.42")
+	assert.Equal(t, checkPass, checks["endpoints"].Status)
+	assert.Equal(t, "1 of 2 endpoints ready", checks["endpoints"].Message)
+	assert.Equal(t, checkSkipped, checks["dns"].Status)
+	assert.Equal(t, checkPass, checks["http"].Status)
+	assert.Equal(t, 200, checks["http"].StatusCode)
+	assert.Equal(t, "ok", checks["http"].Body)
+	assert.Nil(t, mock.created, "proxy mode must not create pods")
+}
+
+func TestConnectivityTest_ProxyModeFailures(t *testing.T) {
+	t.Run("no ready endpoints and 503", func(t *testing.T) {
+		mock := newConnectivityMock()
+		mock.slices = nil
+		mock.proxy = &k8s.ServiceProxyResponse{StatusCode: 503, Body: "no endpoints available"}
+
+		out := unmarshalConnectivity(t, callConnectivityTest(t, mock, nil, map[string]interface{}{
+			"namespace": "shop", "service": "checkout",
+		}))
+		assert.False(t, out.Success)
+		checks := checksByName(out)
+		assert.Equal(t, checkFail, checks["endpoints"].Status)
+		assert.Equal(t, checkFail, checks["http"].Status)
+		assert.Equal(t, 503, checks["http"].StatusCode)
+	})
+
+	t.Run("missing service", func(t *testing.T) {
+		mock := newConnectivityMock()
+		mock.getSvcErr = errors.New(`services "checkout" not found`)
+
+		out := unmarshalConnectivity(t, callConnectivityTest(t, mock, nil, map[string]interface{}{
+			"namespace": "shop", "service": "checkout",
+		}))
+		assert.False(t, out.Success)
+		require.Len(t, out.Checks, 1)
+		assert.Equal(t, "service", out.Checks[0].Name)
+		assert.Contains(t, out.Checks[0].Message, "not found")
+	})
+
+	t.Run("unknown port", func(t *testing.T) {
+		mock := newConnectivityMock()
+		out := unmarshalConnectivity(t, callConnectivityTest(t, mock, nil, map[string]interface{}{
+			"namespace": "shop", "service": "checkout", "port": "grpc",
+		}))
+		assert.False(t, out.Success)
+		assert.Contains(t, checksByName(out)["http"].Message, `no port "grpc"`)
+	})
+}
+
+func TestConnectivityTest_PodMode(t *testing.T) {
+	original := connectivityPollInterval
+	connectivityPollInterval = time.Millisecond
+	defer func() { connectivityPollInterval = original }()
+
+	mock := newConnectivityMock()
+	mock.podLogs = strings.Join([]string{
+		"@@mcp:dns",
+		"Name:	checkout.shop.svc.cluster.local",
+		"Address: 10.96.0.42",
+		"@@mcp:dns:0",
+		"@@mcp:http",
+		"@@mcp:http:0",
+		"@@mcp:body",
+		"hello",
+	}, "\n")
+
+	out := unmarshalConnectivity(t, callConnectivityTest(t, mock, mutatingConfig(), map[string]interface{}{
+		"namespace":       "shop",
+		"service":         "checkout",
+		"mode":            "pod",
+		"sourceNamespace": "frontend",
+		"timeoutSeconds":  float64(5),
+	}))
+
+	assert.True(t, out.Success)
+	assert.Equal(t, "mcp-connectivity-test-abcde", out.Pod)
+	checks := checksByName(out)
+	assert.Equal(t, checkPass, checks["dns"].Status)
+	assert.Contains(t, checks["dns"].Message, "10.96.0.42")
+	assert.Equal(t, checkPass, checks["http"].Status)
+	assert.Equal(t, "hello", checks["http"].Body)
+
+	// The pod runs in the source namespace, is locked down and capped, and
+	// is deleted afterwards.
+	require.NotNil(t, mock.created)
+	assert.Equal(t, "frontend", mock.created.Namespace)
+	assert.Equal(t, []string{"frontend/mcp-connectivity-test-abcde"}, mock.deleted)
+	spec := mock.created.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, spec.RestartPolicy)
+	assert.False(t, *spec.AutomountServiceAccountToken)
+	require.NotNil(t, spec.ActiveDeadlineSeconds)
+	assert.Equal(t, int64(70), *spec.ActiveDeadlineSeconds)
+	container := spec.Containers[0]
+	assert.Equal(t, "50m", container.Resources.Limits.Cpu().String())
+	assert.Equal(t, "32Mi", container.Resources.Limits.Memory().String())
+	assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "TARGET_URL", Value: "http://checkout.shop.svc:80/"})
+	assert.NotContains(t, container.Command[2], "checkout", "targets must be passed via env, not the script")
+}
+
+func TestConnectivityTest_PodModeFailures(t *testing.T) {
+	original := connectivityPollInterval
+	connectivityPollInterval = time.Millisecond
+	defer func() { connectivityPollInterval = original }()
+
+	mock := newConnectivityMock()
+	mock.podPhase = corev1.PodFailed
+	mock.deleteErr = errors.New("forbidden")
+	mock.podLogs = strings.Join([]string{
+		"@@mcp:dns",
+		"nslookup: can't resolve 'checkout.shop.svc'",
+		"@@mcp:dns:1",
+		"@@mcp:http",
+		"wget: bad address 'checkout.shop.svc:80'",
+		"@@mcp:http:1",
+		"@@mcp:body",
+	}, "\n")
+
+	out := unmarshalConnectivity(t, callConnectivityTest(t, mock, mutatingConfig(), map[string]interface{}{
+		"namespace": "shop", "service": "checkout", "mode": "pod",
+	}))
+
+	assert.False(t, out.Success)
+	checks := checksByName(out)
+	assert.Equal(t, checkFail, checks["dns"].Status)
+	assert.Contains(t, checks["dns"].Message, "can't resolve")
+	assert.Equal(t, checkFail, checks["http"].Status)
+	assert.Contains(t, checks["http"].Message, "bad address")
+	require.Len(t, out.Warnings, 1)
+	assert.Contains(t, out.Warnings[0], "failed to delete test pod")
+}
+
+func TestConnectivityTest_PodModeGating(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *server.Config
+		wantErr string
+	}{
+		{
+			name: "non-destructive mode",
+			config: &server.Config{
+				NonDestructiveMode: true,
+			},
+			wantErr: "Create operations are not allowed",
+		},
+		{
+			name: "only create allowed",
+			config: &server.Config{
+				NonDestructiveMode: true,
+				AllowedOperations:  []string{"create"},
+			},
+			wantErr: "Delete operations are not allowed",
+		},
+		{
+			name: "dry run",
+			config: &server.Config{
+				NonDestructiveMode: true,
+				DryRun:             true,
+			},
+			wantErr: "dry-run",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newConnectivityMock()
+			result := callConnectivityTest(t, mock, tt.config, map[string]interface{}{
+				"namespace": "shop", "service": "checkout", "mode": "pod",
+			})
+			require.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.wantErr)
+			assert.Nil(t, mock.created)
+		})
+	}
+
+	t.Run("source namespace outside the write allowlist", func(t *testing.T) {
+		mock := newConnectivityMock()
+		config := mutatingConfig()
+		config.WriteNamespaces = []string{"shop"}
+		result := callConnectivityTest(t, mock, config, map[string]interface{}{
+			"namespace": "shop", "service": "checkout", "mode": "pod", "sourceNamespace": "kube-system",
+		})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "connectivity_test in namespace kube-system is not allowed")
+		assert.Nil(t, mock.created)
+	})
+
+	t.Run("proxy mode works in non-destructive mode", func(t *testing.T) {
+		mock := newConnectivityMock()
+		out := unmarshalConnectivity(t, callConnectivityTest(t, mock, &server.Config{NonDestructiveMode: true}, map[string]interface{}{
+			"namespace": "shop", "service": "checkout",
+		}))
+		assert.True(t, out.Success)
+	})
+}
+
+func TestParseConnectivityRequest_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"missing namespace", map[string]interface{}{"service": "a"}, "namespace is required"},
+		{"missing service", map[string]interface{}{"namespace": "a"}, "service is required"},
+		{"invalid service", map[string]interface{}{"namespace": "a", "service": "$(reboot)"}, "invalid service"},
+		{"invalid port", map[string]interface{}{"namespace": "a", "service": "b", "port": "99999"}, "invalid port"},
+		{"relative path", map[string]interface{}{"namespace": "a", "service": "b", "path": "healthz"}, "path must start with /"},
+		{"path with space", map[string]interface{}{"namespace": "a", "service": "b", "path": "/a b"}, "whitespace"},
+		{"bad scheme", map[string]interface{}{"namespace": "a", "service": "b", "scheme": "ftp"}, "scheme must be"},
+		{"bad mode", map[string]interface{}{"namespace": "a", "service": "b", "mode": "ssh"}, "mode must be"},
+		{"bad timeout", map[string]interface{}{"namespace": "a", "service": "b", "timeoutSeconds": float64(120)}, "timeoutSeconds"},
+		{"bad source namespace", map[string]interface{}{"namespace": "a", "service": "b", "sourceNamespace": "A_B"}, "invalid sourceNamespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseConnectivityRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+
+	req, errMsg := parseConnectivityRequest(map[string]interface{}{"namespace": "a", "service": "b"})
+	require.Empty(t, errMsg)
+	assert.Equal(t, ConnectivityModeProxy, req.mode)
+	assert.Equal(t, "a", req.sourceNamespace)
+	assert.Equal(t, "/", req.path)
+	assert.Equal(t, DefaultConnectivityTimeoutSeconds*time.Second, req.timeout)
+}
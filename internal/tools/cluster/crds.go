@@ -0,0 +1,423 @@
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the crds tool's limit param.
+const (
+	DefaultCRDsLimit = 100
+	MaxCRDsLimit     = 1000
+)
+
+// crdAPIGroup is the API group of CustomResourceDefinitions.
+const crdAPIGroup = "apiextensions.k8s.io"
+
+// CRDInfo describes an installed CustomResourceDefinition.
+type CRDInfo struct {
+	Name  string `json:"name"`
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	Scope string `json:"scope"`
+
+	Versions []CRDVersion `json:"versions"`
+	// StoredVersions are the versions objects have ever been stored in.
+	StoredVersions []string `json:"storedVersions,omitempty"`
+
+	Established   bool `json:"established"`
+	NamesAccepted bool `json:"namesAccepted"`
+
+	// Problems explain conditions that are not true and stored versions
+	// the CRD no longer serves.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// CRDVersion is a version of a CustomResourceDefinition.
+type CRDVersion struct {
+	Name       string `json:"name"`
+	Served     bool   `json:"served"`
+	Storage    bool   `json:"storage,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// CRDsOutput is the response of the crds tool listing one cluster's CRDs,
+// sorted by group and name.
+type CRDsOutput struct {
+	CRDs      []CRDInfo `json:"crds"`
+	TotalCRDs int       `json:"totalCRDs"`
+	Truncated bool      `json:"truncated,omitempty"`
+
+	// Unhealthy counts the CRDs with problems, including those cut by the
+	// limit.
+	Unhealthy int `json:"unhealthy"`
+}
+
+// CRDDiffOutput is the response of the crds tool comparing two clusters.
+type CRDDiffOutput struct {
+	// Cluster is the cluster compared, or "current" for the default one.
+	Cluster     string `json:"cluster"`
+	CompareWith string `json:"compareWith"`
+
+	OnlyInCluster     []string    `json:"onlyInCluster"`
+	OnlyInCompareWith []string    `json:"onlyInCompareWith"`
+	Changed           []CRDChange `json:"changed"`
+	Identical         int         `json:"identical"`
+
+	// Truncated is set when the limit cut one of the lists.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// CRDChange lists how a CRD installed in both clusters differs.
+type CRDChange struct {
+	Name        string   `json:"name"`
+	Differences []string `json:"differences"`
+}
+
+// crdObject holds the fields of a CustomResourceDefinition the tool reads.
+// The apiextensions types are not a dependency of this module.
+type crdObject struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		Group string `json:"group"`
+		Scope string `json:"scope"`
+		Names struct {
+			Kind string `json:"kind"`
+		} `json:"names"`
+		Versions []struct {
+			Name       string `json:"name"`
+			Served     bool   `json:"served"`
+			Storage    bool   `json:"storage"`
+			Deprecated bool   `json:"deprecated"`
+			Schema     *struct {
+				OpenAPIV3Schema map[string]interface{} `json:"openAPIV3Schema"`
+			} `json:"schema"`
+		} `json:"versions"`
+	} `json:"spec"`
+	Status struct {
+		StoredVersions []string `json:"storedVersions"`
+		Conditions     []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// crdRecord is a CRD as read from a cluster, with a fingerprint of each
+// version's schema for comparisons.
+type crdRecord struct {
+	info    CRDInfo
+	schemas map[string]string
+}
+
+// crdsRequest holds the validated crds tool arguments.
+type crdsRequest struct {
+	cluster     string
+	kubeContext string
+	group       string
+	compareWith string
+	limit       int
+}
+
+// handleCRDs lists the CustomResourceDefinitions of a cluster or, with
+// compareWith, the differences between the CRDs of two clusters.
+func handleCRDs(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseCRDsRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	var result interface{}
+	if req.compareWith != "" {
+		diff, toolErr := diffCRDs(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = diff
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		records, err := listCRDs(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to list custom resource definitions", err, client.User()).Result(), nil
+		}
+		result = crdInventory(records, req.limit)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal custom resource definitions: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseCRDsRequest validates the tool arguments. limit must lie within 1 and
+// MaxCRDsLimit, and compareWith must name another cluster.
+func parseCRDsRequest(args map[string]interface{}) (crdsRequest, string) {
+	req := crdsRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultCRDsLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	group, _ := args["group"].(string)
+	req.group = strings.ToLower(strings.TrimSpace(group))
+	compareWith, _ := args["compareWith"].(string)
+	req.compareWith = strings.TrimSpace(compareWith)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxCRDsLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxCRDsLimit)
+		}
+		req.limit = int(v)
+	}
+	if req.compareWith != "" && req.compareWith == req.cluster {
+		return req, "compareWith must name a cluster other than cluster"
+	}
+	return req, ""
+}
+
+// listCRDs reads the CRDs of one cluster, filtered by group, sorted by
+// group and name. A group matches itself and its subgroups, so
+// "cert-manager.io" also matches "acme.cert-manager.io".
+func listCRDs(ctx context.Context, client k8s.Client, req crdsRequest) ([]crdRecord, error) {
+	var records []crdRecord
+	err := listAll(ctx, client, req.kubeContext, "", "customresourcedefinitions", crdAPIGroup, k8s.ListOptions{}, func(crd *crdObject) {
+		if req.group != "" && crd.Spec.Group != req.group && !strings.HasSuffix(crd.Spec.Group, "."+req.group) {
+			return
+		}
+		records = append(records, crdRecordOf(crd))
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i].info, records[j].info
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return a.Name < b.Name
+	})
+	return records, nil
+}
+
+func crdRecordOf(crd *crdObject) crdRecord {
+	info := CRDInfo{
+		Name:           crd.Name,
+		Group:          crd.Spec.Group,
+		Kind:           crd.Spec.Names.Kind,
+		Scope:          crd.Spec.Scope,
+		Versions:       []CRDVersion{},
+		StoredVersions: crd.Status.StoredVersions,
+	}
+	schemas := map[string]string{}
+	var served []string
+	for _, v := range crd.Spec.Versions {
+		info.Versions = append(info.Versions, CRDVersion{Name: v.Name, Served: v.Served, Storage: v.Storage, Deprecated: v.Deprecated})
+		if v.Served {
+			served = append(served, v.Name)
+		}
+		if v.Schema != nil {
+			// Maps marshal with sorted keys, so equal schemas hash equally.
+			content, _ := json.Marshal(v.Schema.OpenAPIV3Schema)
+			sum := sha256.Sum256(content)
+			schemas[v.Name] = hex.EncodeToString(sum[:])
+		}
+	}
+
+	for _, c := range crd.Status.Conditions {
+		switch c.Type {
+		case "Established":
+			info.Established = c.Status == string(metav1.ConditionTrue)
+		case "NamesAccepted":
+			info.NamesAccepted = c.Status == string(metav1.ConditionTrue)
+		default:
+			continue
+		}
+		if c.Status != string(metav1.ConditionTrue) {
+			problem := fmt.Sprintf("%s is %s (%s)", c.Type, c.Status, c.Reason)
+			if c.Message != "" {
+				problem += ": " + c.Message
+			}
+			info.Problems = append(info.Problems, problem)
+		}
+	}
+	for _, stored := range crd.Status.StoredVersions {
+		if !slices.Contains(served, stored) {
+			info.Problems = append(info.Problems, fmt.Sprintf("objects may still be stored as %s, which is no longer served; migrate them before removing the version from storedVersions", stored))
+		}
+	}
+	return crdRecord{info: info, schemas: schemas}
+}
+
+// crdInventory builds the listing of one cluster's CRDs.
+func crdInventory(records []crdRecord, limit int) *CRDsOutput {
+	out := &CRDsOutput{CRDs: []CRDInfo{}, TotalCRDs: len(records)}
+	for _, r := range records {
+		if len(r.info.Problems) > 0 {
+			out.Unhealthy++
+		}
+		if len(out.CRDs) == limit {
+			out.Truncated = true
+			continue
+		}
+		out.CRDs = append(out.CRDs, r.info)
+	}
+	return out
+}
+
+// diffCRDs reads the CRDs of both clusters in parallel and compares them.
+// The call is checked on compareWith as it was on cluster.
+func diffCRDs(ctx context.Context, sc *server.ServerContext, req crdsRequest) (*CRDDiffOutput, *toolerrors.Error) {
+	if msg := tools.CheckClusterAccess(ctx, sc, req.compareWith); msg != "" {
+		return nil, toolerrors.Newf(toolerrors.CodePolicyDenied, "cluster %s: %s", req.compareWith, msg)
+	}
+
+	var base, other []crdRecord
+	g, gctx := errgroup.WithContext(ctx)
+	for _, side := range []struct {
+		cluster string
+		into    *[]crdRecord
+	}{
+		{req.cluster, &base},
+		{req.compareWith, &other},
+	} {
+		g.Go(func() error {
+			client, toolErr := tools.GetClusterClient(gctx, sc, side.cluster)
+			if toolErr != nil {
+				return toolErr
+			}
+			records, err := listCRDs(gctx, client.K8s(), req)
+			if err != nil {
+				name := side.cluster
+				if name == "" {
+					name = "the current cluster"
+				}
+				return tools.K8sError("Failed to list custom resource definitions in "+name, err, client.User())
+			}
+			*side.into = records
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		// Both sides return tool errors, which keep their code.
+		return nil, toolerrors.FromError(err, err.Error())
+	}
+
+	cluster := req.cluster
+	if cluster == "" {
+		cluster = "current"
+	}
+	return compareCRDs(cluster, req.compareWith, base, other, req.limit), nil
+}
+
+// compareCRDs reports the CRDs installed in only one cluster and how those
+// installed in both differ: scope, served and storage versions, stored
+// versions, conditions and the schema of each served version.
+func compareCRDs(cluster, compareWith string, base, other []crdRecord, limit int) *CRDDiffOutput {
+	out := &CRDDiffOutput{
+		Cluster:           cluster,
+		CompareWith:       compareWith,
+		OnlyInCluster:     []string{},
+		OnlyInCompareWith: []string{},
+		Changed:           []CRDChange{},
+	}
+	otherByName := map[string]crdRecord{}
+	for _, r := range other {
+		otherByName[r.info.Name] = r
+	}
+	add := func(list *[]string, name string) {
+		if len(*list) == limit {
+			out.Truncated = true
+			return
+		}
+		*list = append(*list, name)
+	}
+
+	for _, a := range base {
+		b, ok := otherByName[a.info.Name]
+		if !ok {
+			add(&out.OnlyInCluster, a.info.Name)
+			continue
+		}
+		delete(otherByName, a.info.Name)
+		differences := crdDifferences(a, b)
+		if len(differences) == 0 {
+			out.Identical++
+			continue
+		}
+		if len(out.Changed) == limit {
+			out.Truncated = true
+			continue
+		}
+		out.Changed = append(out.Changed, CRDChange{Name: a.info.Name, Differences: differences})
+	}
+	for _, b := range other {
+		if _, ok := otherByName[b.info.Name]; ok {
+			add(&out.OnlyInCompareWith, b.info.Name)
+		}
+	}
+	return out
+}
+
+// crdDifferences describes how a CRD differs between the two clusters, each
+// difference as "<what>: <cluster> vs <compareWith>".
+func crdDifferences(a, b crdRecord) []string {
+	var diffs []string
+	differ := func(what, x, y string) {
+		if x != y {
+			diffs = append(diffs, fmt.Sprintf("%s: %s vs %s", what, orNone(x), orNone(y)))
+		}
+	}
+	versions := func(info CRDInfo, pick func(CRDVersion) bool) string {
+		var names []string
+		for _, v := range info.Versions {
+			if pick(v) {
+				names = append(names, v.Name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+
+	differ("scope", a.info.Scope, b.info.Scope)
+	differ("served versions", versions(a.info, func(v CRDVersion) bool { return v.Served }), versions(b.info, func(v CRDVersion) bool { return v.Served }))
+	differ("storage version", versions(a.info, func(v CRDVersion) bool { return v.Storage }), versions(b.info, func(v CRDVersion) bool { return v.Storage }))
+	differ("deprecated versions", versions(a.info, func(v CRDVersion) bool { return v.Deprecated }), versions(b.info, func(v CRDVersion) bool { return v.Deprecated }))
+	differ("stored versions", strings.Join(a.info.StoredVersions, ", "), strings.Join(b.info.StoredVersions, ", "))
+	differ("established", fmt.Sprint(a.info.Established), fmt.Sprint(b.info.Established))
+
+	for _, v := range a.info.Versions {
+		if !v.Served {
+			continue
+		}
+		if hb, ok := b.schemas[v.Name]; ok && a.schemas[v.Name] != hb {
+			diffs = append(diffs, fmt.Sprintf("schema of %s differs", v.Name))
+		}
+	}
+	return diffs
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// testCRD returns a CRD serving the versions, the last one stored, with a
+// one-field schema of the given type.
+func testCRD(name, group, fieldType string, versions ...string) *unstructured.Unstructured {
+	var specVersions []interface{}
+	for i, v := range versions {
+		specVersions = append(specVersions, map[string]interface{}{
+			"name":    v,
+			"served":  true,
+			"storage": i == len(versions)-1,
+			"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"spec": map[string]interface{}{"type": fieldType}},
+			}},
+		})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"group":    group,
+			"scope":    "Namespaced",
+			"names":    map[string]interface{}{"kind": "Thing"},
+			"versions": specVersions,
+		},
+		"status": map[string]interface{}{
+			"storedVersions": []interface{}{versions[len(versions)-1]},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True", "reason": "NoConflicts"},
+				map[string]interface{}{"type": "Established", "status": "True", "reason": "InitialNamesAccepted"},
+			},
+		},
+	}}
+}
+
+func TestCRDs_Inventory(t *testing.T) {
+	broken := testCRD("widgets.acme.cert-manager.io", "acme.cert-manager.io", "object", "v1")
+	broken.Object["status"] = map[string]interface{}{
+		"storedVersions": []interface{}{"v1alpha1", "v1"},
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "NamesAccepted", "status": "False", "reason": "KindConflict", "message": "Thing is already in use"},
+			map[string]interface{}{"type": "Established", "status": "False", "reason": "NotAccepted"},
+		},
+	}
+	client := mcptest.NewFakeClient(map[string][]runtime.Object{
+		"customresourcedefinitions": {
+			testCRD("certificates.cert-manager.io", "cert-manager.io", "object", "v1"),
+			broken,
+			testCRD("apps.example.com", "example.com", "object", "v1beta1", "v1"),
+		},
+	})
+	out := mcptest.DecodeResult[CRDsOutput](t, callTool(t, client, "crds", map[string]interface{}{"group": "cert-manager.io"}))
+	assert.Equal(t, 2, out.TotalCRDs, "subgroups match the group filter")
+	assert.Equal(t, 1, out.Unhealthy)
+	require.Len(t, out.CRDs, 2)
+
+	ok := out.CRDs[1]
+	assert.Equal(t, "certificates.cert-manager.io", ok.Name)
+	assert.Equal(t, []CRDVersion{{Name: "v1", Served: true, Storage: true}}, ok.Versions)
+	assert.True(t, ok.Established)
+	assert.True(t, ok.NamesAccepted)
+	assert.Empty(t, ok.Problems)
+
+	bad := out.CRDs[0]
+	assert.Equal(t, "acme.cert-manager.io", bad.Group, "sorted by group")
+	assert.False(t, bad.Established)
+	require.Len(t, bad.Problems, 3)
+	assert.Equal(t, "NamesAccepted is False (KindConflict): Thing is already in use", bad.Problems[0])
+	assert.Equal(t, "Established is False (NotAccepted)", bad.Problems[1])
+	assert.Contains(t, bad.Problems[2], "stored as v1alpha1, which is no longer served")
+}
+
+func TestCompareCRDs(t *testing.T) {
+	records := func(objs ...*unstructured.Unstructured) []crdRecord {
+		var out []crdRecord
+		for _, obj := range objs {
+			crd := &crdObject{}
+			require.NoError(t, fromObject(obj, crd))
+			out = append(out, crdRecordOf(crd))
+		}
+		return out
+	}
+	staging := records(
+		testCRD("apps.example.com", "example.com", "object", "v1beta1", "v1"),
+		testCRD("dbs.example.com", "example.com", "object", "v1"),
+		testCRD("same.example.com", "example.com", "object", "v1"),
+		testCRD("staging.example.com", "example.com", "object", "v1"),
+	)
+	prod := records(
+		testCRD("apps.example.com", "example.com", "object", "v1beta1"),
+		testCRD("dbs.example.com", "example.com", "string", "v1"),
+		testCRD("prod.example.com", "example.com", "object", "v1"),
+		testCRD("same.example.com", "example.com", "object", "v1"),
+	)
+
+	out := compareCRDs("staging", "prod", staging, prod, DefaultCRDsLimit)
+	assert.Equal(t, []string{"staging.example.com"}, out.OnlyInCluster)
+	assert.Equal(t, []string{"prod.example.com"}, out.OnlyInCompareWith)
+	assert.Equal(t, 1, out.Identical)
+	assert.Equal(t, []CRDChange{
+		{Name: "apps.example.com", Differences: []string{
+			"served versions: v1beta1, v1 vs v1beta1",
+			"storage version: v1 vs v1beta1",
+			"stored versions: v1 vs v1beta1",
+		}},
+		{Name: "dbs.example.com", Differences: []string{"schema of v1 differs"}},
+	}, out.Changed)
+	assert.False(t, out.Truncated)
+
+	out = compareCRDs("staging", "prod", staging, prod, 1)
+	assert.Len(t, out.Changed, 1)
+	assert.True(t, out.Truncated)
+}
+
+func TestCRDs_CompareRequiresFederation(t *testing.T) {
+	result := callTool(t, mcptest.NewFakeClient(nil), "crds", map[string]interface{}{"compareWith": "prod"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "federation")
+}
+
+func TestCRDs_CompareWithDeniedCluster(t *testing.T) {
+	result := callTool(t, mcptest.NewFakeClient(nil), "crds", map[string]interface{}{"compareWith": "prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: crds")
+}
+
+func TestParseCRDsRequest(t *testing.T) {
+	_, errMsg := parseCRDsRequest(map[string]interface{}{"cluster": "prod", "compareWith": "prod"})
+	assert.Contains(t, errMsg, "other than cluster")
+
+	_, errMsg = parseCRDsRequest(map[string]interface{}{"limit": float64(0)})
+	assert.Contains(t, errMsg, "limit must be between")
+
+	req, errMsg := parseCRDsRequest(map[string]interface{}{"group": " Cert-Manager.io "})
+	require.Empty(t, errMsg)
+	assert.Equal(t, "cert-manager.io", req.group)
+}
@@ -0,0 +1,441 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Limits for the deprecated_apis tool.
+const (
+	// DefaultDeprecatedAPIsLimit is the default cap on the number of objects
+	// reported per cluster.
+	DefaultDeprecatedAPIsLimit = 50
+
+	// MaxDeprecatedAPIsLimit is the absolute maximum allowed for limit.
+	MaxDeprecatedAPIsLimit = 1000
+)
+
+// Finding statuses reported by the deprecated_apis tool.
+const (
+	// deprecationRemoved means the API version is no longer served by the
+	// cluster; manifests using it fail when they are applied again.
+	deprecationRemoved = "removed"
+
+	// deprecationDeprecated means the API version is still served but is
+	// removed in a later Kubernetes version.
+	deprecationDeprecated = "deprecated"
+)
+
+// lastAppliedAnnotation holds the manifest last applied with kubectl apply.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// apiDeprecation is a Kubernetes API version removed from the API server.
+type apiDeprecation struct {
+	apiVersion string
+	kind       string
+	// resource is the plural resource name used to list the kind.
+	resource string
+	// removedIn is the Kubernetes minor version that stopped serving it.
+	removedIn string
+	// replacement is the API version to migrate to; empty when the kind was
+	// removed without one.
+	replacement string
+}
+
+// apiDeprecations are the removals of built-in kinds that users manage, from
+// the Kubernetes deprecated API migration guide. Kinds written only by
+// controllers, such as Events and Leases, are left out.
+var apiDeprecations = []apiDeprecation{
+	{"extensions/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "replicasets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "replicasets", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "networkpolicies", "1.16", "networking.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "mutatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "validatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "customresourcedefinitions", "1.22", "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "apiservices", "1.22", "apiregistration.k8s.io/v1"},
+	{"extensions/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "ingressclasses", "1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "clusterroles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "clusterrolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "roles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "rolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "priorityclasses", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "csidrivers", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "storageclasses", "1.22", "storage.k8s.io/v1"},
+	{"batch/v1beta1", "CronJob", "cronjobs", "1.25", "batch/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.25", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "poddisruptionbudgets", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.25", ""},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "runtimeclasses", "1.25", "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "flowschemas", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "flowschemas", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "flowschemas", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// DeprecatedAPIFinding is an object last written with a deprecated or
+// removed API version.
+type DeprecatedAPIFinding struct {
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	RemovedIn   string `json:"removedIn"`
+	Replacement string `json:"replacement,omitempty"`
+
+	// Source says how the API version was found: "last-applied" (the
+	// kubectl apply annotation), "managedFields" (a field manager wrote it)
+	// or "served" (the cluster only serves the kind in this version).
+	Source string `json:"source"`
+}
+
+// DeprecatedAPISummary counts the findings for one API version and kind.
+type DeprecatedAPISummary struct {
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind"`
+	Status      string `json:"status"`
+	RemovedIn   string `json:"removedIn"`
+	Replacement string `json:"replacement,omitempty"`
+	Objects     int    `json:"objects"`
+}
+
+// DeprecatedAPIsOutput is the response of the deprecated_apis tool for one
+// cluster. Findings are sorted by removal version, then by object.
+type DeprecatedAPIsOutput struct {
+	// Cluster is set for fleet queries.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Error is set when a cluster of a fleet query could not be scanned.
+	Error string `json:"error,omitempty"`
+
+	ServerVersion string `json:"serverVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+
+	Summary           []DeprecatedAPISummary `json:"summary"`
+	Findings          []DeprecatedAPIFinding `json:"findings"`
+	TotalFindings     int                    `json:"totalFindings"`
+	FindingsTruncated bool                   `json:"findingsTruncated,omitempty"`
+
+	// Skipped lists resources that could not be listed, for example for
+	// lack of permissions.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// FleetDeprecatedAPIsOutput is the response of the deprecated_apis tool
+// across clusters.
+type FleetDeprecatedAPIsOutput struct {
+	Clusters          []DeprecatedAPIsOutput `json:"clusters"`
+	TotalClusters     int                    `json:"totalClusters"`
+	FailedClusters    int                    `json:"failedClusters"`
+	ClustersTruncated bool                   `json:"clustersTruncated,omitempty"`
+}
+
+// deprecatedAPIsRequest holds the validated deprecated_apis tool arguments.
+type deprecatedAPIsRequest struct {
+	cluster       string
+	kubeContext   string
+	clusters      []string
+	targetVersion *version.Version
+	limit         int
+}
+
+// handleDeprecatedAPIs handles the deprecated_apis tool.
+func handleDeprecatedAPIs(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseDeprecatedAPIsRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	var result interface{}
+	if req.clusters != nil {
+		fleet, toolErr := fleetDeprecatedAPIs(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = fleet
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out, err := scanDeprecatedAPIs(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to scan for deprecated APIs", err, client.User()).Result(), nil
+		}
+		result = out
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal deprecated APIs: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseDeprecatedAPIsRequest validates the tool arguments.
+func parseDeprecatedAPIsRequest(args map[string]interface{}) (deprecatedAPIsRequest, string) {
+	req := deprecatedAPIsRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultDeprecatedAPIsLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxDeprecatedAPIsLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxDeprecatedAPIsLimit)
+		}
+		req.limit = int(v)
+	}
+
+	if target, _ := args["targetVersion"].(string); target != "" {
+		v, err := version.ParseGeneric(target)
+		if err != nil {
+			return req, fmt.Sprintf("invalid targetVersion %q: use a Kubernetes version such as 1.29", target)
+		}
+		req.targetVersion = v
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// fleetDeprecatedAPIs scans every requested cluster in parallel. A cluster
+// that fails is reported in the output instead of failing the whole call.
+func fleetDeprecatedAPIs(ctx context.Context, sc *server.ServerContext, req deprecatedAPIsRequest) (*FleetDeprecatedAPIsOutput, *toolerrors.Error) {
+	names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	out := &FleetDeprecatedAPIsOutput{
+		TotalClusters:     total,
+		ClustersTruncated: len(names) < total,
+		Clusters:          make([]DeprecatedAPIsOutput, len(names)),
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(capacityFleetConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			result := DeprecatedAPIsOutput{Cluster: name}
+			client, toolErr := tools.GetClusterClient(gctx, sc, name)
+			if toolErr != nil {
+				result.Error = toolErr.Message
+			} else {
+				scanned, err := scanDeprecatedAPIs(gctx, client.K8s(), req)
+				if err != nil {
+					result.Error = tools.FormatK8sError("Failed to scan for deprecated APIs", err, client.User())
+				} else {
+					result = *scanned
+					result.Cluster = name
+				}
+			}
+
+			mu.Lock()
+			out.Clusters[i] = result
+			if result.Error != "" {
+				out.FailedClusters++
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return out, nil
+}
+
+// deprecationScan groups the deprecations of one kind, which are checked
+// with a single list call.
+type deprecationScan struct {
+	resource string
+	// listAPIVersion is the API version the objects are listed with: the
+	// replacement where one exists, otherwise the deprecated version. The
+	// cluster falls back to another version of the group if it does not
+	// serve this one.
+	listAPIVersion string
+	deprecations   []apiDeprecation
+}
+
+// scanDeprecatedAPIs finds the objects of one cluster that were written with
+// a deprecated or removed API version. Objects are read with a current API
+// version; the version they were written with comes from the kubectl apply
+// annotation and the managed fields. Kinds removed without a replacement are
+// listed in the deprecated version, where every object is a finding.
+func scanDeprecatedAPIs(ctx context.Context, client k8s.Client, req deprecatedAPIsRequest) (*DeprecatedAPIsOutput, error) {
+	health, err := client.GetClusterHealth(ctx, req.kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	current, err := version.ParseGeneric(health.Version)
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine the Kubernetes version of the cluster: %q", health.Version)
+	}
+	return findDeprecatedAPIs(ctx, client, req, health.Version, current)
+}
+
+// findDeprecatedAPIs scans a cluster running the given version.
+func findDeprecatedAPIs(ctx context.Context, client k8s.Client, req deprecatedAPIsRequest, serverVersion string, current *version.Version) (*DeprecatedAPIsOutput, error) {
+	out := &DeprecatedAPIsOutput{
+		ServerVersion: serverVersion,
+		Summary:       []DeprecatedAPISummary{},
+		Findings:      []DeprecatedAPIFinding{},
+	}
+	if req.targetVersion != nil {
+		out.TargetVersion = req.targetVersion.String()
+	}
+
+	var findings []DeprecatedAPIFinding
+	for _, scan := range deprecationScans(req.targetVersion) {
+		opts := k8s.ListOptions{AllNamespaces: true}
+		err := listAll(ctx, client, req.kubeContext, "", scan.resource, scan.listAPIVersion, opts, func(obj *metav1.PartialObjectMetadata) {
+			findings = append(findings, objectDeprecations(obj, scan, current)...)
+		})
+		if err != nil {
+			if errors.Is(err, k8s.ErrUnknownResourceType) {
+				// The cluster does not serve this kind in any version.
+				continue
+			}
+			out.Skipped = append(out.Skipped, fmt.Sprintf("%s %s: %v", scan.listAPIVersion, scan.resource, err))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.RemovedIn != b.RemovedIn {
+			return version.MustParseGeneric(a.RemovedIn).LessThan(version.MustParseGeneric(b.RemovedIn))
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	summaryIndex := map[string]int{}
+	for _, f := range findings {
+		key := f.APIVersion + "/" + f.Kind
+		i, ok := summaryIndex[key]
+		if !ok {
+			i = len(out.Summary)
+			summaryIndex[key] = i
+			out.Summary = append(out.Summary, DeprecatedAPISummary{
+				APIVersion:  f.APIVersion,
+				Kind:        f.Kind,
+				Status:      f.Status,
+				RemovedIn:   f.RemovedIn,
+				Replacement: f.Replacement,
+			})
+		}
+		out.Summary[i].Objects++
+	}
+
+	out.TotalFindings = len(findings)
+	if len(findings) > req.limit {
+		findings = findings[:req.limit]
+		out.FindingsTruncated = true
+	}
+	out.Findings = append(out.Findings, findings...)
+	return out, nil
+}
+
+// deprecationScans returns the kinds to check. Without a target version every
+// known deprecation is relevant; with one, only those removed by then.
+func deprecationScans(target *version.Version) []deprecationScan {
+	var scans []deprecationScan
+	index := map[string]int{}
+	for _, d := range apiDeprecations {
+		if target != nil && target.LessThan(version.MustParseGeneric(d.removedIn)) {
+			continue
+		}
+
+		listAPIVersion := d.replacement
+		if listAPIVersion == "" {
+			listAPIVersion = d.apiVersion
+		}
+		key := d.resource + "@" + listAPIVersion
+		if i, ok := index[key]; ok {
+			scans[i].deprecations = append(scans[i].deprecations, d)
+			continue
+		}
+		index[key] = len(scans)
+		scans = append(scans, deprecationScan{resource: d.resource, listAPIVersion: listAPIVersion, deprecations: []apiDeprecation{d}})
+	}
+	return scans
+}
+
+// objectDeprecations returns the findings for one listed object.
+func objectDeprecations(obj *metav1.PartialObjectMetadata, scan deprecationScan, current *version.Version) []DeprecatedAPIFinding {
+	used := map[string]string{}
+	for _, entry := range obj.ManagedFields {
+		if entry.APIVersion != "" {
+			used[entry.APIVersion] = "managedFields"
+		}
+	}
+	if lastApplied := obj.Annotations[lastAppliedAnnotation]; lastApplied != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if json.Unmarshal([]byte(lastApplied), &applied) == nil && applied.APIVersion != "" {
+			used[applied.APIVersion] = "last-applied"
+		}
+	}
+	// The version the object was listed in is the only one the cluster
+	// serves for the kind when no replacement is available yet.
+	if obj.APIVersion != "" {
+		used[obj.APIVersion] = "served"
+	}
+
+	var findings []DeprecatedAPIFinding
+	for _, d := range scan.deprecations {
+		source, ok := used[d.apiVersion]
+		if !ok {
+			continue
+		}
+		status := deprecationDeprecated
+		if !current.LessThan(version.MustParseGeneric(d.removedIn)) {
+			status = deprecationRemoved
+		}
+		findings = append(findings, DeprecatedAPIFinding{
+			APIVersion:  d.apiVersion,
+			Kind:        d.kind,
+			Namespace:   obj.Namespace,
+			Name:        obj.Name,
+			Status:      status,
+			RemovedIn:   d.removedIn,
+			Replacement: d.replacement,
+			Source:      source,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,131 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func deprecatedObject(apiVersion, kind, namespace, name string, metadata map[string]interface{}) runtime.Object {
+	meta := map[string]interface{}{"name": name}
+	if namespace != "" {
+		meta["namespace"] = namespace
+	}
+	for k, v := range metadata {
+		meta[k] = v
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   meta,
+	}}
+}
+
+func callDeprecatedAPIs(t *testing.T, client *mcptest.FakeClient, args map[string]interface{}, opts ...server.Option) (*mcp.CallToolResult, DeprecatedAPIsOutput) {
+	t.Helper()
+	result := callTool(t, client, "deprecated_apis", args, opts...)
+	var out DeprecatedAPIsOutput
+	if !result.IsError {
+		out = mcptest.DecodeResult[DeprecatedAPIsOutput](t, result)
+	}
+	return result, out
+}
+
+// newDeprecationsClient returns a v1.24 client serving objects per
+// "resource.group/version"; the other kinds are not served.
+func newDeprecationsClient() *mcptest.FakeClient {
+	return newUpgradeClient("v1.24.17", map[string][]runtime.Object{
+		"ingresses.networking.k8s.io/v1": {
+			deprecatedObject("networking.k8s.io/v1", "Ingress", "shop", "web", map[string]interface{}{
+				"annotations": map[string]interface{}{
+					lastAppliedAnnotation: `{"apiVersion":"extensions/v1beta1","kind":"Ingress"}`,
+				},
+			}),
+			deprecatedObject("networking.k8s.io/v1", "Ingress", "shop", "api", nil),
+		},
+		"poddisruptionbudgets.policy/v1": {
+			deprecatedObject("policy/v1", "PodDisruptionBudget", "shop", "web", map[string]interface{}{
+				"managedFields": []interface{}{
+					map[string]interface{}{"manager": "helm", "operation": "Update", "apiVersion": "policy/v1beta1"},
+				},
+			}),
+		},
+		"podsecuritypolicies.policy/v1beta1": {
+			deprecatedObject("policy/v1beta1", "PodSecurityPolicy", "", "restricted", nil),
+		},
+	})
+}
+
+func TestDeprecatedAPIs(t *testing.T) {
+	client := newDeprecationsClient()
+	result, out := callDeprecatedAPIs(t, client, map[string]interface{}{})
+	require.False(t, result.IsError, "unexpected error: %v", result.Content)
+
+	assert.Equal(t, "v1.24.17", out.ServerVersion)
+	assert.Equal(t, 3, out.TotalFindings)
+	assert.Equal(t, []DeprecatedAPIFinding{
+		{APIVersion: "extensions/v1beta1", Kind: "Ingress", Namespace: "shop", Name: "web", Status: deprecationRemoved, RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", Source: "last-applied"},
+		{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Namespace: "shop", Name: "web", Status: deprecationDeprecated, RemovedIn: "1.25", Replacement: "policy/v1", Source: "managedFields"},
+		{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", Name: "restricted", Status: deprecationDeprecated, RemovedIn: "1.25", Source: "served"},
+	}, out.Findings)
+	require.Len(t, out.Summary, 3)
+	assert.Equal(t, 1, out.Summary[0].Objects)
+	assert.Empty(t, out.Skipped, "unserved kinds are not reported as skipped")
+
+	assert.Len(t, client.ListOpts["deployments"], 1, "deployments are listed once for the three deprecated versions")
+}
+
+func TestDeprecatedAPIs_TargetVersionAndLimit(t *testing.T) {
+	client := newDeprecationsClient()
+	result, out := callDeprecatedAPIs(t, client, map[string]interface{}{
+		"targetVersion": "1.22",
+		"limit":         float64(1),
+	})
+	require.False(t, result.IsError)
+	assert.Equal(t, "1.22", out.TargetVersion)
+	assert.Equal(t, 1, out.TotalFindings)
+	assert.Equal(t, "Ingress", out.Findings[0].Kind)
+	assert.NotContains(t, client.ListOpts, "poddisruptionbudgets")
+
+	_, out = callDeprecatedAPIs(t, newDeprecationsClient(), map[string]interface{}{"limit": float64(2)})
+	assert.Len(t, out.Findings, 2)
+	assert.True(t, out.FindingsTruncated)
+	assert.Len(t, out.Summary, 3)
+}
+
+func TestParseDeprecatedAPIsRequest_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "limit too large", args: map[string]interface{}{"limit": float64(MaxDeprecatedAPIsLimit + 1)}, wantErr: "limit"},
+		{name: "bad target version", args: map[string]interface{}{"targetVersion": "next"}, wantErr: "invalid targetVersion"},
+		{name: "cluster and clusters", args: map[string]interface{}{"cluster": "a", "clusters": "b"}, wantErr: "either cluster or clusters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseDeprecatedAPIsRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+}
+
+func TestDeprecatedAPIs_FleetRequiresFederation(t *testing.T) {
+	result, _ := callDeprecatedAPIs(t, newDeprecationsClient(), map[string]interface{}{"clusters": "prod-a"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "federation")
+}
+
+func TestDeprecatedAPIs_FleetDeniedCluster(t *testing.T) {
+	result, _ := callDeprecatedAPIs(t, newDeprecationsClient(), map[string]interface{}{"clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: deprecated_apis")
+}
@@ -0,0 +1,449 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Probable causes reported by the diagnose_node tool.
+const (
+	NodeCauseNotReady           = "NodeNotReady"
+	NodeCauseMemoryPressure     = "MemoryPressure"
+	NodeCauseDiskPressure       = "DiskPressure"
+	NodeCausePIDPressure        = "PIDPressure"
+	NodeCauseNetworkUnavailable = "NetworkUnavailable"
+	NodeCauseCordoned           = "Cordoned"
+	NodeCauseResourcesExhausted = "ResourcesExhausted"
+	NodeCauseTaintsBlockPods    = "TaintsBlockPods"
+)
+
+const (
+	// maxNodeDiagnoseEvents caps the events in a node diagnosis, newest
+	// first.
+	maxNodeDiagnoseEvents = 15
+
+	// maxNodeBlockedPods caps the pending pods listed as blocked by the
+	// node's taints.
+	maxNodeBlockedPods = 20
+
+	// nodeExhaustedPercent is the share of allocatable CPU or memory
+	// requested, or of pod capacity used, above which a node is reported
+	// as exhausted.
+	nodeExhaustedPercent = 95
+
+	// nodeRoleLabelPrefix prefixes the labels naming a node's roles.
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+)
+
+// NodeDiagnosis is the response of the diagnose_node tool.
+type NodeDiagnosis struct {
+	Node NodeInfo `json:"node"`
+
+	// Healthy is true when the node is ready and no probable cause was
+	// found.
+	Healthy bool `json:"healthy"`
+
+	// Causes are the probable causes of the node's trouble, most severe
+	// first.
+	Causes []NodeCause `json:"causes,omitempty"`
+
+	Conditions []NodeConditionSummary `json:"conditions"`
+	Taints     []string               `json:"taints,omitempty"`
+	Resources  NodeResources          `json:"resources"`
+	Events     []NodeEvent            `json:"events,omitempty"`
+
+	// BlockedPods are pending pods whose node selector matches the node
+	// but which do not tolerate its taints.
+	BlockedPods      []BlockedPod `json:"blockedPods,omitempty"`
+	TotalBlockedPods int          `json:"totalBlockedPods,omitempty"`
+
+	// Unavailable lists the details that could not be fetched, such as
+	// events the user may not list.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// NodeInfo is the node's identity and software versions.
+type NodeInfo struct {
+	Name             string   `json:"name"`
+	Roles            []string `json:"roles,omitempty"`
+	Ready            bool     `json:"ready"`
+	Unschedulable    bool     `json:"unschedulable,omitempty"`
+	KubeletVersion   string   `json:"kubeletVersion,omitempty"`
+	ContainerRuntime string   `json:"containerRuntime,omitempty"`
+	KernelVersion    string   `json:"kernelVersion,omitempty"`
+	OSImage          string   `json:"osImage,omitempty"`
+	Architecture     string   `json:"architecture,omitempty"`
+	ProviderID       string   `json:"providerID,omitempty"`
+	CreatedAt        string   `json:"createdAt,omitempty"`
+}
+
+// NodeCause is a probable cause with the evidence for it.
+type NodeCause struct {
+	Cause      string `json:"cause"`
+	Detail     string `json:"detail"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// NodeConditionSummary is a node condition.
+type NodeConditionSummary struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	Since   string `json:"since,omitempty"`
+}
+
+// NodeResources compares the node's allocatable resources with what its
+// active pods request.
+type NodeResources struct {
+	Allocatable      ResourceAmounts `json:"allocatable"`
+	Requested        ResourceAmounts `json:"requested"`
+	Limits           ResourceAmounts `json:"limits"`
+	RequestedPercent ResourcePercent `json:"requestedPercent"`
+	LimitsPercent    ResourcePercent `json:"limitsPercent"`
+	Pods             int             `json:"pods"`
+	PodCapacity      int64           `json:"podCapacity"`
+}
+
+// NodeEvent is an event about the node.
+type NodeEvent struct {
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen string `json:"lastSeen,omitempty"`
+}
+
+// BlockedPod is a pending pod kept off the node by its taints.
+type BlockedPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Taints are the node's taints the pod does not tolerate.
+	Taints []string `json:"taints"`
+}
+
+// nodeDiagnoser collects the diagnosis of one node.
+type nodeDiagnoser struct {
+	client      k8s.Client
+	kubeContext string
+	node        *corev1.Node
+	out         *NodeDiagnosis
+}
+
+func (d *nodeDiagnoser) cause(cause, detail, suggestion string) {
+	d.out.Causes = append(d.out.Causes, NodeCause{Cause: cause, Detail: detail, Suggestion: suggestion})
+}
+
+// handleDiagnoseNode gathers a node's conditions, taints, resource
+// allocation, events and the pending pods its taints keep away, and names
+// probable causes.
+func handleDiagnoseNode(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	kubeContext, _ := args["kubeContext"].(string)
+
+	nodeName, ok := args["nodeName"].(string)
+	if !ok || nodeName == "" {
+		return toolerrors.Required("nodeName").Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	out, err := diagnoseNode(ctx, client.K8s(), kubeContext, nodeName)
+	if err != nil {
+		return tools.K8sError("Failed to diagnose node", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal node diagnosis: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// diagnoseNode reads a node and its pods. Only the node and its pods are
+// required; events and pending pods are reported as unavailable when they
+// cannot be listed.
+func diagnoseNode(ctx context.Context, client k8s.Client, kubeContext, nodeName string) (*NodeDiagnosis, error) {
+	resp, err := client.Get(ctx, kubeContext, "", "nodes", "", nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	node := &corev1.Node{}
+	if err := fromObject(resp.Resource, node); err != nil {
+		return nil, fmt.Errorf("failed to decode node: %w", err)
+	}
+
+	d := &nodeDiagnoser{
+		client:      client,
+		kubeContext: kubeContext,
+		node:        node,
+		out:         &NodeDiagnosis{Conditions: []NodeConditionSummary{}},
+	}
+	d.summarise()
+	if err := d.collectResources(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list pods on the node: %w", err)
+	}
+	d.collectEvents(ctx)
+	d.collectBlockedPods(ctx)
+	d.classify()
+	return d.out, nil
+}
+
+// summarise fills in the node info, conditions and taints.
+func (d *nodeDiagnoser) summarise() {
+	node := d.node
+	info := node.Status.NodeInfo
+	d.out.Node = NodeInfo{
+		Name:             node.Name,
+		Ready:            nodeReady(node),
+		Unschedulable:    node.Spec.Unschedulable,
+		KubeletVersion:   info.KubeletVersion,
+		ContainerRuntime: info.ContainerRuntimeVersion,
+		KernelVersion:    info.KernelVersion,
+		OSImage:          info.OSImage,
+		Architecture:     info.Architecture,
+		ProviderID:       node.Spec.ProviderID,
+	}
+	if !node.CreationTimestamp.IsZero() {
+		d.out.Node.CreatedAt = node.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, nodeRoleLabelPrefix); ok && role != "" {
+			d.out.Node.Roles = append(d.out.Node.Roles, role)
+		}
+	}
+	sort.Strings(d.out.Node.Roles)
+
+	for _, c := range node.Status.Conditions {
+		summary := NodeConditionSummary{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		}
+		if !c.LastTransitionTime.IsZero() {
+			summary.Since = c.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+		d.out.Conditions = append(d.out.Conditions, summary)
+	}
+	for _, taint := range node.Spec.Taints {
+		d.out.Taints = append(d.out.Taints, taintString(taint))
+	}
+}
+
+// collectResources adds up the requests and limits of the node's active
+// pods.
+func (d *nodeDiagnoser) collectResources(ctx context.Context) error {
+	selector := "spec.nodeName=" + d.node.Name + "," + activePodsFieldSelector
+
+	var requested, limits amounts
+	pods := 0
+	err := listAll(ctx, d.client, d.kubeContext, "", "pods", "", k8s.ListOptions{AllNamespaces: true, FieldSelector: selector}, func(pod *corev1.Pod) {
+		pods++
+		requested = requested.plus(podAmounts(&pod.Spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }))
+		limits = limits.plus(podAmounts(&pod.Spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits }))
+	})
+	if err != nil {
+		return err
+	}
+
+	allocatable := amountsFromList(d.node.Status.Allocatable)
+	d.out.Resources = NodeResources{
+		Allocatable:      allocatable.output(),
+		Requested:        requested.output(),
+		Limits:           limits.output(),
+		RequestedPercent: requested.percentOf(allocatable),
+		LimitsPercent:    limits.percentOf(allocatable),
+		Pods:             pods,
+		PodCapacity:      d.node.Status.Allocatable.Pods().Value(),
+	}
+	return nil
+}
+
+// collectEvents lists the newest events about the node, such as pressure
+// and eviction events from the kubelet.
+func (d *nodeDiagnoser) collectEvents(ctx context.Context) {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", "Node"),
+		fields.OneTermEqualSelector("involvedObject.name", d.node.Name),
+	).String()
+
+	type timedEvent struct {
+		event NodeEvent
+		at    time.Time
+	}
+	var events []timedEvent
+	err := listAll(ctx, d.client, d.kubeContext, "", "events", "", k8s.ListOptions{AllNamespaces: true, FieldSelector: selector}, func(ev *corev1.Event) {
+		at := eventTime(*ev)
+		ne := NodeEvent{Type: ev.Type, Reason: ev.Reason, Message: ev.Message, Count: ev.Count}
+		if !at.IsZero() {
+			ne.LastSeen = at.UTC().Format(time.RFC3339)
+		}
+		events = append(events, timedEvent{event: ne, at: at})
+	})
+	if err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("events: %v", err))
+		return
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.After(events[j].at) })
+	if len(events) > maxNodeDiagnoseEvents {
+		events = events[:maxNodeDiagnoseEvents]
+	}
+	for _, ev := range events {
+		d.out.Events = append(d.out.Events, ev.event)
+	}
+}
+
+// collectBlockedPods finds the pending, unscheduled pods whose node
+// selector matches the node but which do not tolerate one of its
+// scheduling taints. Node affinity is not evaluated.
+func (d *nodeDiagnoser) collectBlockedPods(ctx context.Context) {
+	var taints []corev1.Taint
+	for _, taint := range d.node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			taints = append(taints, taint)
+		}
+	}
+	if len(taints) == 0 {
+		return
+	}
+
+	nodeLabels := labels.Set(d.node.Labels)
+	opts := k8s.ListOptions{AllNamespaces: true, FieldSelector: "spec.nodeName=,status.phase=Pending"}
+	err := listAll(ctx, d.client, d.kubeContext, "", "pods", "", opts, func(pod *corev1.Pod) {
+		if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(nodeLabels) {
+			return
+		}
+		var untolerated []string
+		for _, taint := range taints {
+			if !tolerates(pod.Spec.Tolerations, taint) {
+				untolerated = append(untolerated, taintString(taint))
+			}
+		}
+		if len(untolerated) == 0 {
+			return
+		}
+		d.out.TotalBlockedPods++
+		d.out.BlockedPods = append(d.out.BlockedPods, BlockedPod{Namespace: pod.Namespace, Name: pod.Name, Taints: untolerated})
+	})
+	if err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("pending pods: %v", err))
+		return
+	}
+
+	sort.Slice(d.out.BlockedPods, func(i, j int) bool {
+		a, b := d.out.BlockedPods[i], d.out.BlockedPods[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if len(d.out.BlockedPods) > maxNodeBlockedPods {
+		d.out.BlockedPods = d.out.BlockedPods[:maxNodeBlockedPods]
+	}
+}
+
+// classify names the probable causes from the collected state.
+func (d *nodeDiagnoser) classify() {
+	for _, c := range d.node.Status.Conditions {
+		switch {
+		case c.Type == corev1.NodeReady && c.Status != corev1.ConditionTrue:
+			detail := "node is not ready"
+			if c.Status == corev1.ConditionUnknown {
+				detail = "the kubelet stopped reporting node status"
+			}
+			if c.Message != "" {
+				detail += ": " + c.Message
+			}
+			d.cause(NodeCauseNotReady, detail,
+				"Check that the kubelet and container runtime run on the node and that it reaches the API server. Pods on it are evicted after the toleration timeout.")
+		case c.Status != corev1.ConditionTrue:
+		case c.Type == corev1.NodeMemoryPressure:
+			d.cause(NodeCauseMemoryPressure, c.Message,
+				"The kubelet evicts pods to reclaim memory. Check for pods using more memory than they request, and set requests that reflect actual usage.")
+		case c.Type == corev1.NodeDiskPressure:
+			d.cause(NodeCauseDiskPressure, c.Message,
+				"The kubelet garbage-collects images and evicts pods to free disk. Check for large container logs, emptyDir volumes and unused images.")
+		case c.Type == corev1.NodePIDPressure:
+			d.cause(NodeCausePIDPressure, c.Message,
+				"Processes are running out on the node. Look for pods forking many processes and consider a pod PID limit.")
+		case c.Type == corev1.NodeNetworkUnavailable:
+			d.cause(NodeCauseNetworkUnavailable, c.Message,
+				"The node's network is not configured. Check the CNI pods on the node.")
+		}
+	}
+
+	if d.node.Spec.Unschedulable {
+		d.cause(NodeCauseCordoned, "the node is cordoned, so no new pods are scheduled to it",
+			"Uncordon the node once maintenance is done.")
+	}
+
+	res := d.out.Resources
+	var exhausted []string
+	if res.RequestedPercent.CPU >= nodeExhaustedPercent {
+		exhausted = append(exhausted, fmt.Sprintf("%.1f%% of CPU requested", res.RequestedPercent.CPU))
+	}
+	if res.RequestedPercent.Memory >= nodeExhaustedPercent {
+		exhausted = append(exhausted, fmt.Sprintf("%.1f%% of memory requested", res.RequestedPercent.Memory))
+	}
+	if res.PodCapacity > 0 && int64(res.Pods)*100 >= res.PodCapacity*nodeExhaustedPercent {
+		exhausted = append(exhausted, fmt.Sprintf("%d of %d pods", res.Pods, res.PodCapacity))
+	}
+	if len(exhausted) > 0 {
+		d.cause(NodeCauseResourcesExhausted, strings.Join(exhausted, ", "),
+			"New pods with requests will not fit on the node. Use the capacity tool to find nodes with headroom, or add nodes.")
+	}
+
+	if d.out.TotalBlockedPods > 0 {
+		d.cause(NodeCauseTaintsBlockPods, fmt.Sprintf("%d pending pods select the node but do not tolerate its taints", d.out.TotalBlockedPods),
+			"Add tolerations for the taints to the pods, or remove the taints from the node if they are no longer needed.")
+	}
+
+	d.out.Healthy = d.out.Node.Ready && len(d.out.Causes) == 0
+}
+
+// tolerates reports whether any of the tolerations tolerates the taint.
+func tolerates(tolerations []corev1.Toleration, taint corev1.Taint) bool {
+	for _, t := range tolerations {
+		if t.Effect != "" && t.Effect != taint.Effect {
+			continue
+		}
+		if t.Key == "" && t.Operator == corev1.TolerationOpExists {
+			return true
+		}
+		if t.Key != taint.Key {
+			continue
+		}
+		if t.Operator == corev1.TolerationOpExists || t.Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// taintString formats a taint as key=value:effect.
+func taintString(taint corev1.Taint) string {
+	if taint.Value == "" {
+		return taint.Key + ":" + string(taint.Effect)
+	}
+	return taint.Key + "=" + taint.Value + ":" + string(taint.Effect)
+}
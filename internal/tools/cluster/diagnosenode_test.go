@@ -0,0 +1,175 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func nodePod(name, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		Spec: corev1.PodSpec{NodeName: "worker-1", Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}}},
+	}
+}
+
+func pendingPod(name string, nodeSelector map[string]string, tolerations ...corev1.Toleration) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Spec:       corev1.PodSpec{NodeSelector: nodeSelector, Tolerations: tolerations},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func newDiagnoseNodeClient() *mcptest.FakeClient {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	nodeEvent := func(eventType, reason, message string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "worker-1." + reason},
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "worker-1"},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"nodes": {&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{
+				"node-role.kubernetes.io/worker": "",
+				"pool":                           "gpu",
+			}},
+			Spec: corev1.NodeSpec{
+				Unschedulable: true,
+				Taints: []corev1.Taint{
+					{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+				},
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Reason: "KubeletReady"},
+					{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasInsufficientMemory", Message: "kubelet has insufficient memory available"},
+					{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+				},
+				NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.31.2", ContainerRuntimeVersion: "containerd://1.7.20"},
+			},
+		}},
+		"pods": {
+			nodePod("api-1", "1500m", "1Gi"),
+			nodePod("api-2", "500m", "1Gi"),
+			pendingPod("train-1", map[string]string{"pool": "gpu"}),
+			pendingPod("train-2", map[string]string{"pool": "gpu"},
+				corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists},
+				corev1.Toleration{Key: "node.kubernetes.io/unschedulable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			),
+			pendingPod("web-1", map[string]string{"pool": "general"}),
+		},
+		"events": {
+			nodeEvent("Warning", "EvictionThresholdMet", "Attempting to reclaim memory", now),
+			nodeEvent("Normal", "NodeSchedulable", "", now.Add(-time.Hour)),
+		},
+	})
+}
+
+func TestDiagnoseNode(t *testing.T) {
+	client := newDiagnoseNodeClient()
+	out, err := diagnoseNode(context.Background(), client, "", "worker-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "spec.nodeName=worker-1,"+activePodsFieldSelector, client.ListOpts["pods"][0].FieldSelector)
+	assert.Equal(t, "involvedObject.kind=Node,involvedObject.name=worker-1", client.ListOpts["events"][0].FieldSelector)
+
+	assert.Equal(t, []string{"worker"}, out.Node.Roles)
+	assert.Equal(t, "v1.31.2", out.Node.KubeletVersion)
+	assert.Equal(t, "containerd://1.7.20", out.Node.ContainerRuntime)
+	assert.True(t, out.Node.Ready)
+	assert.False(t, out.Healthy)
+	assert.Len(t, out.Conditions, 3)
+	assert.Equal(t, []string{"nvidia.com/gpu=true:NoSchedule", "node.kubernetes.io/unschedulable:NoSchedule", "spot:PreferNoSchedule"}, out.Taints)
+
+	assert.Equal(t, NodeResources{
+		Allocatable:      ResourceAmounts{CPUMillicores: 2000, MemoryMiB: 4096},
+		Requested:        ResourceAmounts{CPUMillicores: 2000, MemoryMiB: 2048},
+		RequestedPercent: ResourcePercent{CPU: 100, Memory: 50},
+		Pods:             2,
+		PodCapacity:      110,
+	}, out.Resources)
+
+	require.Len(t, out.Events, 2)
+	assert.Equal(t, "EvictionThresholdMet", out.Events[0].Reason, "newest event first")
+
+	assert.Equal(t, 1, out.TotalBlockedPods)
+	assert.Equal(t, []BlockedPod{{
+		Namespace: "shop",
+		Name:      "train-1",
+		Taints:    []string{"nvidia.com/gpu=true:NoSchedule", "node.kubernetes.io/unschedulable:NoSchedule"},
+	}}, out.BlockedPods)
+
+	var causes []string
+	for _, c := range out.Causes {
+		causes = append(causes, c.Cause)
+	}
+	assert.Equal(t, []string{NodeCauseMemoryPressure, NodeCauseCordoned, NodeCauseResourcesExhausted, NodeCauseTaintsBlockPods}, causes)
+	assert.Equal(t, "100.0% of CPU requested", out.Causes[2].Detail)
+	assert.Empty(t, out.Unavailable)
+}
+
+func TestDiagnoseNode_NotReady(t *testing.T) {
+	client := newDiagnoseNodeClient()
+	node := client.Objects["nodes"][0].(*corev1.Node)
+	node.Spec = corev1.NodeSpec{}
+	node.Status.Conditions = []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Message: "Kubelet stopped posting node status."},
+	}
+	client.Objects["pods"] = client.Objects["pods"][2:]
+	client.Errs["events"] = errors.New("events is forbidden")
+
+	out, err := diagnoseNode(context.Background(), client, "", "worker-1")
+	require.NoError(t, err)
+
+	assert.False(t, out.Node.Ready)
+	require.Len(t, out.Causes, 1)
+	assert.Equal(t, NodeCauseNotReady, out.Causes[0].Cause)
+	assert.Equal(t, "the kubelet stopped reporting node status: Kubelet stopped posting node status.", out.Causes[0].Detail)
+	assert.Equal(t, []string{"events: events is forbidden"}, out.Unavailable)
+	assert.Len(t, client.ListOpts["pods"], 1, "pending pods are not listed for a node without scheduling taints")
+}
+
+func TestDiagnoseNode_NotFound(t *testing.T) {
+	_, err := diagnoseNode(context.Background(), newDiagnoseNodeClient(), "", "missing")
+	assert.ErrorContains(t, err, "failed to get node")
+}
+
+func TestTolerates(t *testing.T) {
+	taint := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+
+	assert.True(t, tolerates([]corev1.Toleration{{Operator: corev1.TolerationOpExists}}, taint), "an empty key with Exists tolerates everything")
+	assert.True(t, tolerates([]corev1.Toleration{{Key: "dedicated", Value: "gpu"}}, taint))
+	assert.False(t, tolerates([]corev1.Toleration{{Key: "dedicated", Value: "cpu"}}, taint))
+	assert.False(t, tolerates([]corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}}, taint))
+	assert.False(t, tolerates(nil, taint))
+}
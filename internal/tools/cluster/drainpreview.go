@@ -0,0 +1,350 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// maxDrainPreviewNodes caps the nodes one preview covers.
+	maxDrainPreviewNodes = 50
+
+	// maxDrainPreviewPods caps the pods listed as evicted.
+	maxDrainPreviewPods = 200
+)
+
+// DrainBudgetImpact is a PodDisruptionBudget covering pods a drain would
+// evict.
+type DrainBudgetImpact struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+
+	// Pods are the covered pods the drain would evict, sorted.
+	Pods []string `json:"pods"`
+
+	// Blocks is set when the drain would evict more covered pods than the
+	// budget allows, so some evictions are refused until replacements
+	// become healthy.
+	Blocks bool `json:"blocks"`
+}
+
+// DrainWorkloadImpact is a workload losing pods to a drain.
+type DrainWorkloadImpact struct {
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+
+	// Evicted counts the workload's pods on the drained nodes, and
+	// ReadyElsewhere its ready pods on other nodes.
+	Evicted        int `json:"evicted"`
+	ReadyElsewhere int `json:"readyElsewhere"`
+
+	// Downtime is set when no ready pod of the workload remains while the
+	// evicted pods are rescheduled.
+	Downtime bool `json:"downtime"`
+
+	// Singleton is set when the workload runs a single pod.
+	Singleton bool `json:"singleton,omitempty"`
+}
+
+// DrainPreview is the response of the drain_preview tool.
+type DrainPreview struct {
+	Nodes []string `json:"nodes"`
+
+	// Clean is true when drain would evict every pod without refusing to
+	// start, having evictions refused by a budget, or causing downtime.
+	Clean bool `json:"clean"`
+
+	// Evictions are the pods drain would evict, sorted.
+	Evictions          []string `json:"evictions"`
+	TotalEvictions     int      `json:"totalEvictions"`
+	EvictionsTruncated bool     `json:"evictionsTruncated,omitempty"`
+
+	Skipped []DrainPod `json:"skipped,omitempty"`
+
+	// Refused are pods that make drain refuse to start with the given
+	// options.
+	Refused []DrainPod `json:"refused,omitempty"`
+
+	// PodDisruptionBudgets are sorted with blocking budgets first.
+	PodDisruptionBudgets []DrainBudgetImpact `json:"podDisruptionBudgets,omitempty"`
+
+	// Workloads are sorted with those facing downtime first.
+	Workloads []DrainWorkloadImpact `json:"workloads,omitempty"`
+
+	// Unavailable lists the details that could not be fetched, such as
+	// budgets the user may not list.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// drainPreviewRequest holds the validated drain_preview arguments.
+type drainPreviewRequest struct {
+	drain        drainRequest
+	nodes        []string
+	nodeSelector string
+}
+
+// handleDrainPreview previews a drain of one or more nodes without changing
+// anything.
+func handleDrainPreview(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseDrainPreviewRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.drain.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	preview, err := previewDrain(ctx, client.K8s(), req)
+	if err != nil {
+		return tools.K8sError("Failed to preview drain", err, client.User()).Result(), nil
+	}
+	return jsonResult(preview)
+}
+
+// parseDrainPreviewRequest validates the arguments. Nodes are named by
+// nodeNames or selected by nodeSelector; the drain options default as for
+// the drain tool.
+func parseDrainPreviewRequest(args map[string]interface{}) (drainPreviewRequest, string) {
+	var req drainPreviewRequest
+	names, _ := args["nodeNames"].(string)
+	req.nodeSelector, _ = args["nodeSelector"].(string)
+	switch {
+	case names == "" && req.nodeSelector == "":
+		return req, "nodeNames or nodeSelector is required"
+	case names != "" && req.nodeSelector != "":
+		return req, "specify either nodeNames or nodeSelector, not both"
+	}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(req.nodes, name) {
+			req.nodes = append(req.nodes, name)
+		}
+	}
+	if len(req.nodes) > maxDrainPreviewNodes {
+		return req, fmt.Sprintf("at most %d nodes can be previewed at once", maxDrainPreviewNodes)
+	}
+	if req.nodeSelector != "" {
+		if _, err := labels.Parse(req.nodeSelector); err != nil {
+			return req, fmt.Sprintf("invalid nodeSelector: %v", err)
+		}
+	}
+
+	// The drain options are parsed like drain's, with a placeholder node.
+	drainArgs := map[string]interface{}{"nodeName": "-"}
+	for key, value := range args {
+		if key != "nodeName" {
+			drainArgs[key] = value
+		}
+	}
+	var errMsg string
+	req.drain, errMsg = parseDrainRequest(drainArgs)
+	return req, errMsg
+}
+
+// previewDrain plans the drain of every selected node as the drain tool
+// would, then checks the PodDisruptionBudgets covering the pods to evict
+// and whether their workloads keep a ready pod elsewhere.
+func previewDrain(ctx context.Context, client k8s.Client, req drainPreviewRequest) (*DrainPreview, error) {
+	nodes, err := drainPreviewNodes(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	preview := &DrainPreview{Nodes: nodes, Evictions: []string{}}
+	if len(nodes) == 0 {
+		return preview, nil
+	}
+
+	drained := map[string]bool{}
+	var evict []*corev1.Pod
+	for _, node := range nodes {
+		drained[node] = true
+		var pods []*corev1.Pod
+		err := listAll(ctx, client, req.drain.kubeContext, "", "pods", "",
+			k8s.ListOptions{AllNamespaces: true, FieldSelector: "spec.nodeName=" + node},
+			func(pod *corev1.Pod) { pods = append(pods, pod) })
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods on node %s: %w", node, err)
+		}
+		plan := planDrain(pods, req.drain)
+		evict = append(evict, plan.evict...)
+		preview.Skipped = append(preview.Skipped, plan.skipped...)
+		preview.Refused = append(preview.Refused, plan.refused...)
+	}
+
+	for _, pod := range evict {
+		preview.Evictions = append(preview.Evictions, podKey(pod))
+	}
+	sort.Strings(preview.Evictions)
+	preview.TotalEvictions = len(preview.Evictions)
+	if len(preview.Evictions) > maxDrainPreviewPods {
+		preview.Evictions = preview.Evictions[:maxDrainPreviewPods]
+		preview.EvictionsTruncated = true
+	}
+
+	// Finished pods are evicted without disrupting anything.
+	var active []*corev1.Pod
+	for _, pod := range evict {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			active = append(active, pod)
+		}
+	}
+	preview.PodDisruptionBudgets = drainBudgetImpact(ctx, client, req.drain.kubeContext, active, preview)
+	preview.Workloads = drainWorkloadImpact(ctx, client, req.drain.kubeContext, active, drained, preview)
+
+	preview.Clean = len(preview.Refused) == 0
+	for _, b := range preview.PodDisruptionBudgets {
+		preview.Clean = preview.Clean && !b.Blocks
+	}
+	for _, w := range preview.Workloads {
+		preview.Clean = preview.Clean && !w.Downtime
+	}
+	return preview, nil
+}
+
+// drainPreviewNodes returns the names of the nodes to preview, sorted.
+func drainPreviewNodes(ctx context.Context, client k8s.Client, req drainPreviewRequest) ([]string, error) {
+	if req.nodeSelector == "" {
+		for _, name := range req.nodes {
+			if _, err := client.Get(ctx, req.drain.kubeContext, "", "nodes", "", name); err != nil {
+				return nil, fmt.Errorf("failed to get node %s: %w", name, err)
+			}
+		}
+		nodes := append([]string{}, req.nodes...)
+		sort.Strings(nodes)
+		return nodes, nil
+	}
+
+	nodes := []string{}
+	err := listAll(ctx, client, req.drain.kubeContext, "", "nodes", "", k8s.ListOptions{LabelSelector: req.nodeSelector}, func(node *corev1.Node) {
+		nodes = append(nodes, node.Name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if len(nodes) > maxDrainPreviewNodes {
+		return nil, fmt.Errorf("nodeSelector matches %d nodes; at most %d can be previewed at once", len(nodes), maxDrainPreviewNodes)
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// drainBudgetImpact groups the pods to evict by the budgets covering them.
+// Drain evicts pods one by one, and a budget's allowance only recovers once
+// replacements are healthy, so a budget covering more evicted pods than it
+// allows disruptions blocks the drain.
+func drainBudgetImpact(ctx context.Context, client k8s.Client, kubeContext string, pods []*corev1.Pod, preview *DrainPreview) []DrainBudgetImpact {
+	budgets := newPDBLookup(client, kubeContext)
+	impacts := map[string]*DrainBudgetImpact{}
+	failed := map[string]bool{}
+	for _, pod := range pods {
+		covering, err := budgets.covering(ctx, pod)
+		if err != nil {
+			if !failed[pod.Namespace] {
+				failed[pod.Namespace] = true
+				preview.Unavailable = append(preview.Unavailable, fmt.Sprintf("poddisruptionbudgets in %s: %v", pod.Namespace, err))
+			}
+			continue
+		}
+		for _, ref := range covering {
+			key := pod.Namespace + "/" + ref.Name
+			impact, ok := impacts[key]
+			if !ok {
+				impact = &DrainBudgetImpact{
+					Namespace:          pod.Namespace,
+					Name:               ref.Name,
+					DisruptionsAllowed: ref.DisruptionsAllowed,
+					CurrentHealthy:     ref.CurrentHealthy,
+					DesiredHealthy:     ref.DesiredHealthy,
+				}
+				impacts[key] = impact
+			}
+			impact.Pods = append(impact.Pods, podKey(pod))
+		}
+	}
+
+	var out []DrainBudgetImpact
+	for _, impact := range impacts {
+		sort.Strings(impact.Pods)
+		impact.Blocks = len(impact.Pods) > int(impact.DisruptionsAllowed)
+		out = append(out, *impact)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Blocks != out[j].Blocks {
+			return out[i].Blocks
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// drainWorkloadImpact counts, for each workload losing pods, its ready pods
+// on nodes that are not drained. Pods are listed once per namespace.
+func drainWorkloadImpact(ctx context.Context, client k8s.Client, kubeContext string, pods []*corev1.Pod, drained map[string]bool, preview *DrainPreview) []DrainWorkloadImpact {
+	impacts := map[string]*DrainWorkloadImpact{}
+	namespaces := map[string]bool{}
+	for _, pod := range pods {
+		workload := podWorkload(pod)
+		key := pod.Namespace + "/" + workload
+		impact, ok := impacts[key]
+		if !ok {
+			impact = &DrainWorkloadImpact{Namespace: pod.Namespace, Workload: workload}
+			impacts[key] = impact
+		}
+		impact.Evicted++
+		namespaces[pod.Namespace] = true
+	}
+
+	total := map[string]int{}
+	for namespace := range namespaces {
+		err := listAll(ctx, client, kubeContext, namespace, "pods", "", k8s.ListOptions{FieldSelector: activePodsFieldSelector}, func(pod *corev1.Pod) {
+			key := pod.Namespace + "/" + podWorkload(pod)
+			impact, ok := impacts[key]
+			if !ok {
+				return
+			}
+			total[key]++
+			if !drained[pod.Spec.NodeName] && podReady(pod) {
+				impact.ReadyElsewhere++
+			}
+		})
+		if err != nil {
+			preview.Unavailable = append(preview.Unavailable, fmt.Sprintf("pods in %s: %v", namespace, err))
+		}
+	}
+
+	var out []DrainWorkloadImpact
+	for key, impact := range impacts {
+		impact.Downtime = impact.ReadyElsewhere == 0
+		impact.Singleton = total[key] == 1
+		out = append(out, *impact)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Downtime != out[j].Downtime {
+			return out[i].Downtime
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Workload < out[j].Workload
+	})
+	return out
+}
@@ -0,0 +1,133 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func drainPreviewPod(name, node, ownerKind, owner string, ready bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, Labels: map[string]string{"app": owner}},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: owner, Controller: ptr(true)}}
+	}
+	if ready {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func drainPreviewBudget(name string, allowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed, CurrentHealthy: 3, DesiredHealthy: 2},
+	}
+}
+
+func newDrainPreviewClient() *mcptest.FakeClient {
+	node := func(name, pool string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}}}
+	}
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"nodes": {node("worker-1", "a"), node("worker-2", "a"), node("worker-3", "b")},
+		"pods": {
+			drainPreviewPod("db-0", "worker-1", "StatefulSet", "db", true),
+			drainPreviewPod("db-1", "worker-2", "StatefulSet", "db", true),
+			drainPreviewPod("db-2", "worker-3", "StatefulSet", "db", true),
+			drainPreviewPod("cache-0", "worker-1", "StatefulSet", "cache", true),
+			drainPreviewPod("web-1", "worker-1", "ReplicaSet", "web", true),
+			drainPreviewPod("web-2", "worker-3", "ReplicaSet", "web", false),
+			drainPreviewPod("agent-1", "worker-1", "DaemonSet", "agent", true),
+		},
+		"poddisruptionbudgets": {drainPreviewBudget("db", 1), drainPreviewBudget("web", 1)},
+	})
+}
+
+func TestPreviewDrain(t *testing.T) {
+	req, errMsg := parseDrainPreviewRequest(map[string]interface{}{"nodeSelector": "pool=a"})
+	require.Empty(t, errMsg)
+
+	out, err := previewDrain(context.Background(), newDrainPreviewClient(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"worker-1", "worker-2"}, out.Nodes)
+	assert.False(t, out.Clean)
+	assert.Equal(t, []string{"shop/cache-0", "shop/db-0", "shop/db-1", "shop/web-1"}, out.Evictions)
+	assert.Equal(t, 4, out.TotalEvictions)
+	require.Len(t, out.Skipped, 1)
+	assert.Equal(t, "shop/agent-1", out.Skipped[0].Pod)
+	assert.Empty(t, out.Refused)
+
+	assert.Equal(t, []DrainBudgetImpact{
+		{Namespace: "shop", Name: "db", DisruptionsAllowed: 1, CurrentHealthy: 3, DesiredHealthy: 2, Pods: []string{"shop/db-0", "shop/db-1"}, Blocks: true},
+		{Namespace: "shop", Name: "web", DisruptionsAllowed: 1, CurrentHealthy: 3, DesiredHealthy: 2, Pods: []string{"shop/web-1"}},
+	}, out.PodDisruptionBudgets)
+
+	assert.Equal(t, []DrainWorkloadImpact{
+		{Namespace: "shop", Workload: "ReplicaSet/web", Evicted: 1, Downtime: true},
+		{Namespace: "shop", Workload: "StatefulSet/cache", Evicted: 1, Downtime: true, Singleton: true},
+		{Namespace: "shop", Workload: "StatefulSet/db", Evicted: 2, ReadyElsewhere: 1},
+	}, out.Workloads)
+	assert.Empty(t, out.Unavailable)
+}
+
+func TestPreviewDrain_RefusedAndUnavailable(t *testing.T) {
+	client := newDrainPreviewClient()
+	client.Objects["pods"] = append(client.Objects["pods"], drainPreviewPod("debug", "worker-2", "", "debug", true))
+	client.Errs["poddisruptionbudgets"] = errors.New("poddisruptionbudgets is forbidden")
+
+	req, errMsg := parseDrainPreviewRequest(map[string]interface{}{"nodeNames": "worker-2"})
+	require.Empty(t, errMsg)
+
+	out, err := previewDrain(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.False(t, out.Clean)
+	require.Len(t, out.Refused, 1)
+	assert.Equal(t, "shop/debug", out.Refused[0].Pod)
+	assert.Equal(t, []string{"shop/db-1"}, out.Evictions)
+	assert.Empty(t, out.PodDisruptionBudgets)
+	assert.Equal(t, []string{"poddisruptionbudgets in shop: poddisruptionbudgets is forbidden"}, out.Unavailable)
+	assert.Equal(t, []DrainWorkloadImpact{
+		{Namespace: "shop", Workload: "StatefulSet/db", Evicted: 1, ReadyElsewhere: 2},
+	}, out.Workloads)
+}
+
+func TestPreviewDrain_NodeNotFound(t *testing.T) {
+	req, errMsg := parseDrainPreviewRequest(map[string]interface{}{"nodeNames": "worker-1, missing"})
+	require.Empty(t, errMsg)
+
+	_, err := previewDrain(context.Background(), newDrainPreviewClient(), req)
+	assert.ErrorContains(t, err, "failed to get node missing")
+}
+
+func TestParseDrainPreviewRequest(t *testing.T) {
+	req, errMsg := parseDrainPreviewRequest(map[string]interface{}{"nodeNames": "worker-1, worker-1,worker-2", "force": true})
+	require.Empty(t, errMsg)
+	assert.Equal(t, []string{"worker-1", "worker-2"}, req.nodes)
+	assert.True(t, req.drain.ignoreDaemonSets)
+	assert.True(t, req.drain.force)
+
+	_, errMsg = parseDrainPreviewRequest(map[string]interface{}{})
+	assert.Equal(t, "nodeNames or nodeSelector is required", errMsg)
+
+	_, errMsg = parseDrainPreviewRequest(map[string]interface{}{"nodeNames": "worker-1", "nodeSelector": "pool=a"})
+	assert.Equal(t, "specify either nodeNames or nodeSelector, not both", errMsg)
+
+	_, errMsg = parseDrainPreviewRequest(map[string]interface{}{"nodeSelector": "pool in ("})
+	assert.Contains(t, errMsg, "invalid nodeSelector")
+}
@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	accesstestdata "github.com/giantswarm/mcp-kubernetes/internal/tools/access/testdata"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// denyCluster returns the server options of a federated deployment whose
+// policy denies every call on cluster.
+func denyCluster(t *testing.T, cluster string) []server.Option {
+	t.Helper()
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    clusters: [" + cluster + "]\n"))
+	require.NoError(t, err)
+	return []server.Option{
+		server.WithFederationManager(&accesstestdata.MockFederationManager{}),
+		server.WithPolicyEngine(security.NewEngine(policy)),
+	}
+}
+
+// callTool calls a cluster tool with client through mcptest. opts
+// configure the server context.
+func callTool(t *testing.T, client k8s.Client, toolName string, args map[string]interface{}, opts ...server.Option) *mcp.CallToolResult {
+	t.Helper()
+	return mcptest.CallRegisteredTool(t, client, RegisterClusterTools, toolName, args, opts...)
+}
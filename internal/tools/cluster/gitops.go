@@ -0,0 +1,551 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the gitops_status tool's limit param.
+const (
+	DefaultGitOpsLimit = 100
+	MaxGitOpsLimit     = 1000
+)
+
+// GitOps controllers reported by gitops_status.
+const (
+	GitOpsFlux   = "flux"
+	GitOpsArgoCD = "argocd"
+)
+
+// Annotations that ask a GitOps controller to reconcile an object.
+const (
+	fluxReconcileAnnotation = "reconcile.fluxcd.io/requestedAt"
+	argoRefreshAnnotation   = "argocd.argoproj.io/refresh"
+)
+
+// gitOpsKind is a GitOps custom resource gitops_status reads and
+// gitops_reconcile can trigger.
+type gitOpsKind struct {
+	kind       string
+	resource   string
+	apiGroup   string
+	controller string
+}
+
+// gitOpsKinds are the GitOps resources, in the order gitops_status lists
+// them. Kinds whose CRDs are not installed are skipped.
+var gitOpsKinds = []gitOpsKind{
+	{"Kustomization", "kustomizations", "kustomize.toolkit.fluxcd.io", GitOpsFlux},
+	{"HelmRelease", "helmreleases", "helm.toolkit.fluxcd.io", GitOpsFlux},
+	{"GitRepository", "gitrepositories", "source.toolkit.fluxcd.io", GitOpsFlux},
+	{"OCIRepository", "ocirepositories", "source.toolkit.fluxcd.io", GitOpsFlux},
+	{"HelmRepository", "helmrepositories", "source.toolkit.fluxcd.io", GitOpsFlux},
+	{"Application", "applications", "argoproj.io", GitOpsArgoCD},
+}
+
+// GitOpsResource is the reconciliation state of a Flux or Argo CD object.
+type GitOpsResource struct {
+	Controller string `json:"controller"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+
+	// Ready is false while the object has not applied its latest revision
+	// or its last reconciliation failed.
+	Ready bool `json:"ready"`
+
+	// Status is the Ready condition reason of Flux objects, or the sync
+	// and health status of Argo CD Applications, e.g. "OutOfSync/Healthy".
+	Status    string `json:"status,omitempty"`
+	Suspended bool   `json:"suspended,omitempty"`
+
+	// Source is where the object's manifests come from, e.g.
+	// "GitRepository/flux-system/flux-system" or a repository URL and path.
+	Source string `json:"source,omitempty"`
+
+	// Revision is the revision last applied or fetched; AttemptedRevision
+	// is set when the controller tried a different one.
+	Revision          string `json:"revision,omitempty"`
+	AttemptedRevision string `json:"attemptedRevision,omitempty"`
+
+	// Message is the last error or status message.
+	Message        string `json:"message,omitempty"`
+	LastReconciled string `json:"lastReconciled,omitempty"`
+}
+
+// GitOpsStatusOutput is the response of the gitops_status tool. Resources
+// that are not ready come first.
+type GitOpsStatusOutput struct {
+	// Controllers are the GitOps controllers whose resources are installed.
+	Controllers []string         `json:"controllers"`
+	Resources   []GitOpsResource `json:"resources"`
+	Total       int              `json:"total"`
+	NotReady    int              `json:"notReady"`
+	Truncated   bool             `json:"truncated,omitempty"`
+	Message     string           `json:"message,omitempty"`
+
+	// Unavailable lists the resources that could not be read, e.g. for
+	// lack of list permission.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// GitOpsReconcileResult is the response of the gitops_reconcile tool.
+type GitOpsReconcileResult struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Annotation string `json:"annotation"`
+	Value      string `json:"value"`
+
+	// Source is the Flux source also asked to reconcile with withSource.
+	Source  string `json:"source,omitempty"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+	Message string `json:"message"`
+}
+
+// fluxObject holds the fields of the Flux objects gitops_status reads.
+type fluxObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Suspend   bool           `json:"suspend"`
+		SourceRef *fluxSourceRef `json:"sourceRef"`
+		Path      string         `json:"path"`
+		URL       string         `json:"url"`
+		Chart     *struct {
+			Spec struct {
+				Chart     string         `json:"chart"`
+				Version   string         `json:"version"`
+				SourceRef *fluxSourceRef `json:"sourceRef"`
+			} `json:"spec"`
+		} `json:"chart"`
+		ChartRef *fluxSourceRef `json:"chartRef"`
+	} `json:"spec"`
+	Status struct {
+		Conditions            []metav1.Condition `json:"conditions"`
+		LastAppliedRevision   string             `json:"lastAppliedRevision"`
+		LastAttemptedRevision string             `json:"lastAttemptedRevision"`
+		Artifact              *struct {
+			Revision string `json:"revision"`
+		} `json:"artifact"`
+	} `json:"status"`
+}
+
+// fluxSourceRef refers to the Flux source of an object.
+type fluxSourceRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// argoApplication holds the fields of an Argo CD Application gitops_status
+// reads.
+type argoApplication struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Source  *argoSource  `json:"source"`
+		Sources []argoSource `json:"sources"`
+	} `json:"spec"`
+	Status struct {
+		Sync struct {
+			Status   string   `json:"status"`
+			Revision string   `json:"revision"`
+			Revs     []string `json:"revisions"`
+		} `json:"sync"`
+		Health struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"health"`
+		Conditions []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+		OperationState *struct {
+			Phase   string `json:"phase"`
+			Message string `json:"message"`
+		} `json:"operationState"`
+		ReconciledAt string `json:"reconciledAt"`
+	} `json:"status"`
+}
+
+// argoSource is where an Argo CD Application's manifests come from.
+type argoSource struct {
+	RepoURL        string `json:"repoURL"`
+	Path           string `json:"path"`
+	Chart          string `json:"chart"`
+	TargetRevision string `json:"targetRevision"`
+}
+
+// gitOpsStatusRequest holds the validated gitops_status tool arguments.
+type gitOpsStatusRequest struct {
+	cluster      string
+	kubeContext  string
+	namespace    string
+	name         string
+	notReadyOnly bool
+	limit        int
+}
+
+// handleGitOpsStatus summarizes the reconciliation state of the Flux and
+// Argo CD objects of a cluster, answering why a change is not live yet.
+func handleGitOpsStatus(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req := gitOpsStatusRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultGitOpsLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	req.name, _ = args["name"].(string)
+	req.notReadyOnly, _ = args["notReadyOnly"].(bool)
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxGitOpsLimit {
+			return toolerrors.InvalidArgument(fmt.Sprintf("limit must be between 1 and %d", MaxGitOpsLimit)).Result(), nil
+		}
+		req.limit = int(v)
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	return jsonResult(collectGitOpsStatus(ctx, client.K8s(), req))
+}
+
+// collectGitOpsStatus lists the GitOps objects matching the request.
+func collectGitOpsStatus(ctx context.Context, client k8s.Client, req gitOpsStatusRequest) *GitOpsStatusOutput {
+	out := &GitOpsStatusOutput{Controllers: []string{}, Resources: []GitOpsResource{}}
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == ""}
+
+	var resources []GitOpsResource
+	for _, kind := range gitOpsKinds {
+		var err error
+		add := func(r GitOpsResource) {
+			if req.name != "" && r.Name != req.name {
+				return
+			}
+			if req.notReadyOnly && r.Ready {
+				return
+			}
+			resources = append(resources, r)
+		}
+		if kind.controller == GitOpsArgoCD {
+			err = listAll(ctx, client, req.kubeContext, req.namespace, kind.resource, kind.apiGroup, opts, func(app *argoApplication) {
+				add(argoResource(app))
+			})
+		} else {
+			err = listAll(ctx, client, req.kubeContext, req.namespace, kind.resource, kind.apiGroup, opts, func(obj *fluxObject) {
+				add(fluxResource(kind.kind, obj))
+			})
+		}
+		if errors.Is(err, k8s.ErrUnknownResourceType) {
+			continue
+		}
+		if !slices.Contains(out.Controllers, kind.controller) {
+			out.Controllers = append(out.Controllers, kind.controller)
+		}
+		if err != nil {
+			out.Unavailable = append(out.Unavailable, fmt.Sprintf("%s: %v", kind.resource, err))
+		}
+	}
+
+	if len(out.Controllers) == 0 {
+		out.Message = "Neither Flux nor Argo CD is installed: none of their custom resources are served by this cluster"
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return !resources[i].Ready && resources[j].Ready
+	})
+	out.Total = len(resources)
+	for _, r := range resources {
+		if !r.Ready {
+			out.NotReady++
+		}
+	}
+	if len(resources) > req.limit {
+		resources = resources[:req.limit]
+		out.Truncated = true
+	}
+	if resources != nil {
+		out.Resources = resources
+	}
+	return out
+}
+
+// fluxResource summarizes a Flux object. It is ready when its Ready
+// condition is True and the revision it last attempted was applied.
+func fluxResource(kind string, obj *fluxObject) GitOpsResource {
+	r := GitOpsResource{
+		Controller:        GitOpsFlux,
+		Kind:              kind,
+		Namespace:         obj.Metadata.Namespace,
+		Name:              obj.Metadata.Name,
+		Suspended:         obj.Spec.Suspend,
+		Source:            fluxSource(obj),
+		Revision:          obj.Status.LastAppliedRevision,
+		AttemptedRevision: obj.Status.LastAttemptedRevision,
+	}
+	if r.Revision == "" && obj.Status.Artifact != nil {
+		r.Revision = obj.Status.Artifact.Revision
+	}
+	if r.AttemptedRevision == r.Revision {
+		r.AttemptedRevision = ""
+	}
+
+	ready := apimeta.FindStatusCondition(obj.Status.Conditions, "Ready")
+	switch {
+	case ready == nil:
+		r.Status = "Unknown"
+		r.Message = "not reconciled yet"
+	default:
+		r.Ready = ready.Status == metav1.ConditionTrue
+		r.Status = ready.Reason
+		r.Message = ready.Message
+		if !ready.LastTransitionTime.IsZero() {
+			r.LastReconciled = ready.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+	}
+	if stalled := apimeta.FindStatusCondition(obj.Status.Conditions, "Stalled"); stalled != nil && stalled.Status == metav1.ConditionTrue {
+		r.Status = "Stalled"
+		r.Message = stalled.Message
+	}
+	if r.AttemptedRevision != "" {
+		r.Ready = false
+	}
+	if r.Suspended {
+		r.Ready = false
+		r.Status = "Suspended"
+	}
+	return r
+}
+
+// fluxSource formats where a Flux object's manifests come from.
+func fluxSource(obj *fluxObject) string {
+	ref := obj.Spec.SourceRef
+	if ref == nil && obj.Spec.Chart != nil {
+		ref = obj.Spec.Chart.Spec.SourceRef
+	}
+	if ref == nil {
+		ref = obj.Spec.ChartRef
+	}
+	if ref == nil {
+		return obj.Spec.URL
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = obj.Metadata.Namespace
+	}
+	source := ref.Kind + "/" + namespace + "/" + ref.Name
+	switch {
+	case obj.Spec.Chart != nil:
+		source += " chart " + obj.Spec.Chart.Spec.Chart
+		if obj.Spec.Chart.Spec.Version != "" {
+			source += "@" + obj.Spec.Chart.Spec.Version
+		}
+	case obj.Spec.Path != "":
+		source += " path " + obj.Spec.Path
+	}
+	return source
+}
+
+// argoResource summarizes an Argo CD Application. It is ready when it is
+// Synced and Healthy.
+func argoResource(app *argoApplication) GitOpsResource {
+	st := app.Status
+	r := GitOpsResource{
+		Controller:     GitOpsArgoCD,
+		Kind:           "Application",
+		Namespace:      app.Metadata.Namespace,
+		Name:           app.Metadata.Name,
+		Ready:          st.Sync.Status == "Synced" && st.Health.Status == "Healthy",
+		Status:         orUnknown(st.Sync.Status) + "/" + orUnknown(st.Health.Status),
+		Revision:       st.Sync.Revision,
+		LastReconciled: st.ReconciledAt,
+	}
+	if r.Revision == "" {
+		r.Revision = strings.Join(st.Sync.Revs, ",")
+	}
+
+	sources := app.Spec.Sources
+	if app.Spec.Source != nil {
+		sources = append([]argoSource{*app.Spec.Source}, sources...)
+	}
+	var refs []string
+	for _, s := range sources {
+		ref := s.RepoURL
+		switch {
+		case s.Chart != "":
+			ref += " chart " + s.Chart
+		case s.Path != "":
+			ref += " path " + s.Path
+		}
+		if s.TargetRevision != "" {
+			ref += "@" + s.TargetRevision
+		}
+		refs = append(refs, ref)
+	}
+	r.Source = strings.Join(refs, ", ")
+
+	// Errors reported in conditions or by the last sync explain more than
+	// the health message.
+	var messages []string
+	for _, c := range st.Conditions {
+		messages = append(messages, c.Type+": "+c.Message)
+	}
+	if op := st.OperationState; op != nil && op.Phase != "Succeeded" && op.Message != "" {
+		messages = append(messages, "sync "+op.Phase+": "+op.Message)
+	}
+	if st.Health.Message != "" {
+		messages = append(messages, st.Health.Message)
+	}
+	r.Message = strings.Join(messages, "; ")
+	return r
+}
+
+// orUnknown returns s, or "Unknown" when it is empty.
+func orUnknown(s string) string {
+	if s == "" {
+		return "Unknown"
+	}
+	return s
+}
+
+// handleGitOpsReconcile asks Flux or Argo CD to reconcile an object now
+// instead of at its next interval, by setting the annotation the
+// controller watches.
+func handleGitOpsReconcile(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := tools.CheckMutatingOperation(sc, "gitops_reconcile"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	kubeContext, _ := args["kubeContext"].(string)
+	kindName, _ := args["kind"].(string)
+	namespace, _ := args["namespace"].(string)
+	name, _ := args["name"].(string)
+	withSource, _ := args["withSource"].(bool)
+	hard, _ := args["hard"].(bool)
+	for _, p := range []struct{ param, value string }{{"kind", kindName}, {"namespace", namespace}, {"name", name}} {
+		if p.value == "" {
+			return toolerrors.Required(p.param).Result(), nil
+		}
+	}
+	kind, ok := findGitOpsKind(kindName)
+	if !ok {
+		return toolerrors.InvalidArgument(fmt.Sprintf("kind must be one of %s", strings.Join(gitOpsKindNames(), ", "))).Result(), nil
+	}
+	if hard && kind.controller != GitOpsArgoCD {
+		return toolerrors.InvalidArgument("hard only applies to Argo CD Applications").Result(), nil
+	}
+	if withSource && kind.controller != GitOpsFlux {
+		return toolerrors.InvalidArgument("withSource only applies to Flux objects").Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	resp, err := k8sClient.Get(ctx, kubeContext, namespace, kind.resource, kind.apiGroup, name)
+	if err != nil {
+		return tools.K8sError(fmt.Sprintf("Failed to get %s", kind.kind), err, client.User()).Result(), nil
+	}
+
+	result := GitOpsReconcileResult{
+		Kind:      kind.kind,
+		Namespace: namespace,
+		Name:      name,
+		DryRun:    tools.IsDryRun(ctx, sc),
+	}
+	if kind.controller == GitOpsArgoCD {
+		result.Annotation, result.Value = argoRefreshAnnotation, "normal"
+		if hard {
+			result.Value = "hard"
+		}
+	} else {
+		var obj fluxObject
+		if err := fromObject(resp.Resource, &obj); err != nil {
+			return toolerrors.Internalf("Failed to decode %s: %v", kind.kind, err).Result(), nil
+		}
+		if obj.Spec.Suspend {
+			return toolerrors.Newf(toolerrors.CodeFailedPrecondition,
+				"%s %s/%s is suspended; Flux does not reconcile it until spec.suspend is unset", kind.kind, namespace, name).Result(), nil
+		}
+		result.Annotation, result.Value = fluxReconcileAnnotation, time.Now().UTC().Format(time.RFC3339Nano)
+
+		// Like flux reconcile --with-source, the source is reconciled first
+		// so that the object picks up its latest revision.
+		if ref := obj.Spec.SourceRef; withSource && ref != nil {
+			sourceKind, ok := findGitOpsKind(ref.Kind)
+			if !ok {
+				return toolerrors.InvalidArgument(fmt.Sprintf("source kind %s cannot be reconciled", ref.Kind)).Result(), nil
+			}
+			sourceNamespace := ref.Namespace
+			if sourceNamespace == "" {
+				sourceNamespace = namespace
+			}
+			if err := requestReconcile(ctx, k8sClient, kubeContext, sourceNamespace, sourceKind, ref.Name, result.Annotation, result.Value); err != nil {
+				return tools.K8sError(fmt.Sprintf("Failed to reconcile %s", ref.Kind), err, client.User()).Result(), nil
+			}
+			result.Source = ref.Kind + "/" + sourceNamespace + "/" + ref.Name
+		}
+	}
+
+	if err := requestReconcile(ctx, k8sClient, kubeContext, namespace, kind, name, result.Annotation, result.Value); err != nil {
+		return tools.K8sError(fmt.Sprintf("Failed to reconcile %s", kind.kind), err, client.User()).Result(), nil
+	}
+
+	result.Message = fmt.Sprintf("Reconciliation of %s %s/%s requested; use gitops_status to follow it", kind.kind, namespace, name)
+	if result.DryRun {
+		result.Message = fmt.Sprintf("Dry-run: reconciliation of %s %s/%s was validated but not requested", kind.kind, namespace, name)
+	}
+	return jsonResult(result)
+}
+
+// requestReconcile sets the reconcile annotation of an object.
+func requestReconcile(ctx context.Context, client k8s.Client, kubeContext, namespace string, kind gitOpsKind, name, annotation, value string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]string{annotation: value}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.Patch(ctx, kubeContext, namespace, kind.resource, kind.apiGroup, name, types.MergePatchType, patch)
+	return err
+}
+
+// findGitOpsKind returns the GitOps resource of a kind, ignoring case.
+func findGitOpsKind(kind string) (gitOpsKind, bool) {
+	for _, k := range gitOpsKinds {
+		if strings.EqualFold(k.kind, kind) {
+			return k, true
+		}
+	}
+	return gitOpsKind{}, false
+}
+
+// gitOpsKindNames returns the kinds gitops_reconcile accepts.
+func gitOpsKindNames() []string {
+	names := make([]string, 0, len(gitOpsKinds))
+	for _, k := range gitOpsKinds {
+		names = append(names, k.kind)
+	}
+	return names
+}
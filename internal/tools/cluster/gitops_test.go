@@ -0,0 +1,212 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// gitOpsMock serves GitOps objects and records patches as
+// resource/namespace/name: patch.
+type gitOpsMock struct {
+	*mcptest.FakeClient
+
+	patches []string
+}
+
+func (m *gitOpsMock) Patch(_ context.Context, _, namespace, resourceType, _, name string, _ types.PatchType, data []byte) (*k8s.PatchResponse, error) {
+	m.patches = append(m.patches, resourceType+"/"+namespace+"/"+name+": "+string(data))
+	return &k8s.PatchResponse{}, nil
+}
+
+func gitOpsObject(apiVersion, kind, namespace, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+		"spec":       spec,
+		"status":     status,
+	}}
+}
+
+func readyCondition(status, reason, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "Ready", "status": status, "reason": reason, "message": message,
+		"lastTransitionTime": "2024-05-01T10:00:00Z",
+	}
+}
+
+func newGitOpsMock() *gitOpsMock {
+	source := map[string]interface{}{"kind": "GitRepository", "name": "flux-system"}
+	return &gitOpsMock{FakeClient: mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+		"kustomizations": {
+			gitOpsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "apps",
+				map[string]interface{}{"sourceRef": source, "path": "./apps"},
+				map[string]interface{}{
+					"conditions":            []interface{}{readyCondition("False", "BuildFailed", "kustomize build failed: missing resource")},
+					"lastAppliedRevision":   "main@sha1:aaa",
+					"lastAttemptedRevision": "main@sha1:bbb",
+				}),
+			gitOpsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "infra",
+				map[string]interface{}{"sourceRef": source, "path": "./infra"},
+				map[string]interface{}{
+					"conditions":            []interface{}{readyCondition("True", "ReconciliationSucceeded", "Applied revision: main@sha1:bbb")},
+					"lastAppliedRevision":   "main@sha1:bbb",
+					"lastAttemptedRevision": "main@sha1:bbb",
+				}),
+			gitOpsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "paused",
+				map[string]interface{}{"sourceRef": source, "suspend": true},
+				nil),
+		},
+		"gitrepositories": {
+			gitOpsObject("source.toolkit.fluxcd.io/v1", "GitRepository", "flux-system", "flux-system",
+				map[string]interface{}{"url": "https://github.com/example/fleet"},
+				map[string]interface{}{
+					"conditions": []interface{}{readyCondition("True", "Succeeded", "stored artifact")},
+					"artifact":   map[string]interface{}{"revision": "main@sha1:bbb"},
+				}),
+		},
+		"applications": {
+			gitOpsObject("argoproj.io/v1alpha1", "Application", "argocd", "shop",
+				map[string]interface{}{"source": map[string]interface{}{"repoURL": "https://github.com/example/shop", "path": "deploy", "targetRevision": "HEAD"}},
+				map[string]interface{}{
+					"sync":           map[string]interface{}{"status": "OutOfSync", "revision": "ccc"},
+					"health":         map[string]interface{}{"status": "Healthy"},
+					"conditions":     []interface{}{map[string]interface{}{"type": "SyncError", "message": "Deployment.apps \"web\" is invalid"}},
+					"operationState": map[string]interface{}{"phase": "Failed", "message": "one or more objects failed to apply"},
+				}),
+		},
+	})}
+}
+
+func TestCollectGitOpsStatus(t *testing.T) {
+	out := collectGitOpsStatus(context.Background(), newGitOpsMock(), gitOpsStatusRequest{limit: DefaultGitOpsLimit})
+
+	assert.Equal(t, []string{GitOpsFlux, GitOpsArgoCD}, out.Controllers)
+	assert.Equal(t, 5, out.Total)
+	assert.Equal(t, 3, out.NotReady)
+	assert.Empty(t, out.Unavailable)
+
+	// Not-ready resources come first, in listing order.
+	require.Len(t, out.Resources, 5)
+	apps := out.Resources[0]
+	assert.Equal(t, "apps", apps.Name)
+	assert.False(t, apps.Ready)
+	assert.Equal(t, "BuildFailed", apps.Status)
+	assert.Equal(t, "GitRepository/flux-system/flux-system path ./apps", apps.Source)
+	assert.Equal(t, "main@sha1:aaa", apps.Revision)
+	assert.Equal(t, "main@sha1:bbb", apps.AttemptedRevision)
+	assert.Equal(t, "kustomize build failed: missing resource", apps.Message)
+	assert.Equal(t, "2024-05-01T10:00:00Z", apps.LastReconciled)
+
+	paused := out.Resources[1]
+	assert.Equal(t, "paused", paused.Name)
+	assert.True(t, paused.Suspended)
+	assert.Equal(t, "Suspended", paused.Status)
+
+	shop := out.Resources[2]
+	assert.Equal(t, GitOpsArgoCD, shop.Controller)
+	assert.Equal(t, "OutOfSync/Healthy", shop.Status)
+	assert.Equal(t, "https://github.com/example/shop path deploy@HEAD", shop.Source)
+	assert.Equal(t, `SyncError: Deployment.apps "web" is invalid; sync Failed: one or more objects failed to apply`, shop.Message)
+
+	infra := out.Resources[3]
+	assert.Equal(t, "infra", infra.Name)
+	assert.True(t, infra.Ready)
+	assert.Empty(t, infra.AttemptedRevision)
+
+	repo := out.Resources[4]
+	assert.Equal(t, "GitRepository", repo.Kind)
+	assert.Equal(t, "https://github.com/example/fleet", repo.Source)
+	assert.Equal(t, "main@sha1:bbb", repo.Revision)
+}
+
+func TestCollectGitOpsStatus_Filters(t *testing.T) {
+	out := collectGitOpsStatus(context.Background(), newGitOpsMock(), gitOpsStatusRequest{notReadyOnly: true, limit: 2})
+	assert.Equal(t, 3, out.Total)
+	assert.True(t, out.Truncated)
+	require.Len(t, out.Resources, 2)
+
+	out = collectGitOpsStatus(context.Background(), newGitOpsMock(), gitOpsStatusRequest{name: "shop", limit: DefaultGitOpsLimit})
+	require.Len(t, out.Resources, 1)
+	assert.Equal(t, "Application", out.Resources[0].Kind)
+}
+
+func TestCollectGitOpsStatus_NotInstalled(t *testing.T) {
+	mock := mcptest.NewStrictFakeClient(map[string][]runtime.Object{})
+	out := collectGitOpsStatus(context.Background(), mock, gitOpsStatusRequest{limit: DefaultGitOpsLimit})
+	assert.Empty(t, out.Controllers)
+	assert.Empty(t, out.Resources)
+	assert.Contains(t, out.Message, "Neither Flux nor Argo CD is installed")
+}
+
+func TestGitOpsReconcile(t *testing.T) {
+	mock := newGitOpsMock()
+	args := map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "apps", "withSource": true}
+
+	result := callGitOpsReconcile(t, mock, args)
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	var out GitOpsReconcileResult
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.Equal(t, fluxReconcileAnnotation, out.Annotation)
+	assert.Equal(t, "GitRepository/flux-system/flux-system", out.Source)
+
+	// The source is reconciled first.
+	require.Len(t, mock.patches, 2)
+	assert.Equal(t, `gitrepositories/flux-system/flux-system: {"metadata":{"annotations":{"reconcile.fluxcd.io/requestedAt":"`+out.Value+`"}}}`, mock.patches[0])
+	assert.Equal(t, `kustomizations/flux-system/apps: {"metadata":{"annotations":{"reconcile.fluxcd.io/requestedAt":"`+out.Value+`"}}}`, mock.patches[1])
+
+	result = callGitOpsReconcile(t, mock, map[string]interface{}{"kind": "Application", "namespace": "argocd", "name": "shop", "hard": true})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	assert.Equal(t, `applications/argocd/shop: {"metadata":{"annotations":{"argocd.argoproj.io/refresh":"hard"}}}`, mock.patches[2])
+}
+
+func TestGitOpsReconcile_Errors(t *testing.T) {
+	mock := newGitOpsMock()
+
+	for _, tc := range []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"suspended", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "paused"}, "is suspended"},
+		{"unknown kind", map[string]interface{}{"kind": "Deployment", "namespace": "shop", "name": "web"}, "kind must be one of"},
+		{"hard on flux", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system", "name": "apps", "hard": true}, "only applies to Argo CD"},
+		{"missing name", map[string]interface{}{"kind": "Kustomization", "namespace": "flux-system"}, "name"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := callGitOpsReconcile(t, mock, tc.args)
+			assert.True(t, result.IsError)
+			assert.Contains(t, mcptest.ResultText(result), tc.want)
+		})
+	}
+	assert.Empty(t, mock.patches)
+}
+
+func callGitOpsReconcile(t *testing.T, mock *gitOpsMock, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithConfig(mutatingConfig()),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handleGitOpsReconcile(context.Background(), request, sc)
+	require.NoError(t, err)
+	return result
+}
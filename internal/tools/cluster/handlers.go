@@ -11,6 +11,7 @@ import (
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // handleGetAPIResources handles kubectl api-resources operations
@@ -49,20 +50,20 @@ func handleGetAPIResources(ctx context.Context, request mcp.CallToolRequest, sc
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 	paginatedResponse, err := k8sClient.GetAPIResources(ctx, kubeContext, limit, offset, apiGroup, namespacedOnly, verbs)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get API resources: %v", err)), nil
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to get API resources: %v", err)).Result(), nil
 	}
 
 	// Convert paginated response to JSON
 	jsonData, err := json.MarshalIndent(paginatedResponse, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal API resources: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal API resources: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -83,28 +84,28 @@ func handleGetClusterHealth(ctx context.Context, request mcp.CallToolRequest, sc
 	if v, ok := args["nodesLimit"].(float64); ok {
 		val := int(v)
 		if val < 1 || val > MaxNodesLimit {
-			return mcp.NewToolResultError(fmt.Sprintf("nodesLimit must be between 1 and %d", MaxNodesLimit)), nil
+			return toolerrors.InvalidArgumentf("nodesLimit must be between 1 and %d", MaxNodesLimit).Result(), nil
 		}
 		nodesLimit = val
 	}
 	includeNodeConditions, _ := args["includeNodeConditions"].(bool)
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 	health, err := k8sClient.GetClusterHealth(ctx, kubeContext)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get cluster health: %v", err)), nil
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to get cluster health: %v", err)).Result(), nil
 	}
 
 	output := buildClusterHealthOutput(health, nodesLimit, includeNodeConditions)
 
 	jsonData, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal cluster health: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal cluster health: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
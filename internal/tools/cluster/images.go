@@ -0,0 +1,516 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Limits for the images tool.
+const (
+	// DefaultImagesLimit is the default cap on the number of images and of
+	// findings reported per cluster.
+	DefaultImagesLimit = 100
+
+	// MaxImagesLimit is the absolute maximum allowed for limit.
+	MaxImagesLimit = 1000
+
+	// maxImageWorkloads caps the workloads listed per image.
+	maxImageWorkloads = 10
+)
+
+// dockerHub is the registry of image references without one.
+const dockerHub = "docker.io"
+
+// Findings reported by the images tool, most severe first.
+const (
+	// imageNotAllowed means the image comes from a registry outside the
+	// allowlist.
+	imageNotAllowed = "registry-not-allowed"
+
+	// imageLatest means the image uses the latest tag, explicitly or by
+	// having no tag, so what runs changes with every pull.
+	imageLatest = "latest-tag"
+
+	// imageMixedTags means the pods of one workload run several tags of
+	// the same repository, for example during a stuck rollout.
+	imageMixedTags = "mixed-tags"
+)
+
+// imageFindingOrder ranks the findings, most severe first.
+var imageFindingOrder = map[string]int{imageNotAllowed: 0, imageLatest: 1, imageMixedTags: 2}
+
+// ImageUsage is one image and where it runs.
+type ImageUsage struct {
+	Image      string `json:"image"`
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	Pods       int    `json:"pods"`
+
+	// Workloads lists the first workloads running the image, sorted, as
+	// namespace/Kind/name.
+	Workloads []string `json:"workloads"`
+
+	// Flags are the findings about the image itself.
+	Flags []string `json:"flags,omitempty"`
+}
+
+// RegistrySummary counts the images pulled from one registry.
+type RegistrySummary struct {
+	Registry string `json:"registry"`
+	Images   int    `json:"images"`
+	Pods     int    `json:"pods"`
+
+	// NotAllowed counts the images outside the allowlist.
+	NotAllowed int `json:"notAllowed,omitempty"`
+}
+
+// ImageFinding is a flagged image of a workload.
+type ImageFinding struct {
+	Finding   string   `json:"finding"`
+	Namespace string   `json:"namespace"`
+	Workload  string   `json:"workload"`
+	Images    []string `json:"images"`
+	Detail    string   `json:"detail"`
+}
+
+// ImagesOutput is the response of the images tool for one cluster.
+type ImagesOutput struct {
+	// Cluster is set for fleet queries.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Error is set when a cluster of a fleet query could not be scanned.
+	Error string `json:"error,omitempty"`
+
+	PodsScanned int               `json:"podsScanned"`
+	Registries  []RegistrySummary `json:"registries"`
+
+	// Images are sorted by registry, repository and tag.
+	Images          []ImageUsage `json:"images"`
+	TotalImages     int          `json:"totalImages"`
+	ImagesTruncated bool         `json:"imagesTruncated,omitempty"`
+
+	// Findings are sorted by severity, then by workload.
+	Findings          []ImageFinding `json:"findings"`
+	TotalFindings     int            `json:"totalFindings"`
+	FindingsTruncated bool           `json:"findingsTruncated,omitempty"`
+}
+
+// FleetImagesOutput is the response of the images tool across clusters.
+type FleetImagesOutput struct {
+	Clusters          []ImagesOutput `json:"clusters"`
+	TotalClusters     int            `json:"totalClusters"`
+	FailedClusters    int            `json:"failedClusters"`
+	ClustersTruncated bool           `json:"clustersTruncated,omitempty"`
+}
+
+// imagesRequest holds the validated images tool arguments.
+type imagesRequest struct {
+	cluster     string
+	kubeContext string
+	clusters    []string
+	namespace   string
+	// allowed are the allowed registries or repository prefixes; nil
+	// when no allowlist was given.
+	allowed []string
+	limit   int
+}
+
+// imageRef is a parsed container image reference.
+type imageRef struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// handleImages handles the images tool.
+func handleImages(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseImagesRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	var result interface{}
+	if req.clusters != nil {
+		fleet, toolErr := fleetImages(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = fleet
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out, err := inventoryImages(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to list pods", err, client.User()).Result(), nil
+		}
+		result = out
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal images: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseImagesRequest validates the tool arguments.
+func parseImagesRequest(args map[string]interface{}) (imagesRequest, string) {
+	req := imagesRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultImagesLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxImagesLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxImagesLimit)
+		}
+		req.limit = int(v)
+	}
+
+	if value, _ := args["allowedRegistries"].(string); value != "" {
+		req.allowed = []string{}
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.ToLower(strings.TrimSpace(entry))
+			entry = strings.TrimPrefix(strings.TrimPrefix(entry, "https://"), "http://")
+			entry = strings.TrimSuffix(entry, "/")
+			if entry == "" {
+				continue
+			}
+			if strings.ContainsAny(entry, "@*") {
+				return req, fmt.Sprintf("invalid allowedRegistries entry %q: use a registry host or a repository prefix such as docker.io/giantswarm", entry)
+			}
+			req.allowed = append(req.allowed, entry)
+		}
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// fleetImages inventories every requested cluster in parallel. A cluster
+// that fails is reported in the output instead of failing the whole call.
+func fleetImages(ctx context.Context, sc *server.ServerContext, req imagesRequest) (*FleetImagesOutput, *toolerrors.Error) {
+	names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	out := &FleetImagesOutput{
+		TotalClusters:     total,
+		ClustersTruncated: len(names) < total,
+		Clusters:          make([]ImagesOutput, len(names)),
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(capacityFleetConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			result := ImagesOutput{Cluster: name}
+			client, toolErr := tools.GetClusterClient(gctx, sc, name)
+			if toolErr != nil {
+				result.Error = toolErr.Message
+			} else {
+				scanned, err := inventoryImages(gctx, client.K8s(), req)
+				if err != nil {
+					result.Error = tools.FormatK8sError("Failed to list pods", err, client.User())
+				} else {
+					result = *scanned
+					result.Cluster = name
+				}
+			}
+
+			mu.Lock()
+			out.Clusters[i] = result
+			if result.Error != "" {
+				out.FailedClusters++
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return out, nil
+}
+
+// inventoryImages lists the active pods of one cluster and groups their
+// container images.
+func inventoryImages(ctx context.Context, client k8s.Client, req imagesRequest) (*ImagesOutput, error) {
+	out := &ImagesOutput{
+		Registries: []RegistrySummary{},
+		Images:     []ImageUsage{},
+		Findings:   []ImageFinding{},
+	}
+
+	images := map[string]*ImageUsage{}
+	imagePods := map[string]map[string]bool{}
+	imageWorkloads := map[string]map[string]bool{}
+	// workloadTags holds the tags per repository of each workload.
+	workloadTags := map[string]map[string]map[string]bool{}
+	workloadNamespace := map[string]string{}
+	workloadName := map[string]string{}
+
+	opts := k8s.ListOptions{FieldSelector: activePodsFieldSelector, AllNamespaces: req.namespace == ""}
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "pods", "", opts, func(pod *corev1.Pod) {
+		out.PodsScanned++
+		workload := podWorkload(pod)
+		workloadKey := pod.Namespace + "/" + workload
+		workloadNamespace[workloadKey] = pod.Namespace
+		workloadName[workloadKey] = workload
+
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, c := range containers {
+			if c.Image == "" {
+				continue
+			}
+			ref := parseImageRef(c.Image)
+			usage, ok := images[c.Image]
+			if !ok {
+				usage = &ImageUsage{
+					Image:      c.Image,
+					Registry:   ref.registry,
+					Repository: ref.repository,
+					Tag:        ref.tag,
+					Digest:     ref.digest,
+					Workloads:  []string{},
+				}
+				images[c.Image] = usage
+				imagePods[c.Image] = map[string]bool{}
+				imageWorkloads[c.Image] = map[string]bool{}
+			}
+			imagePods[c.Image][pod.Namespace+"/"+pod.Name] = true
+			imageWorkloads[c.Image][workloadKey] = true
+
+			repo := ref.registry + "/" + ref.repository
+			if workloadTags[workloadKey] == nil {
+				workloadTags[workloadKey] = map[string]map[string]bool{}
+			}
+			if workloadTags[workloadKey][repo] == nil {
+				workloadTags[workloadKey][repo] = map[string]bool{}
+			}
+			workloadTags[workloadKey][repo][c.Image] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	registries := map[string]*RegistrySummary{}
+	registryPods := map[string]map[string]bool{}
+	var findings []ImageFinding
+	for image, usage := range images {
+		ref := imageRef{registry: usage.Registry, repository: usage.Repository, tag: usage.Tag, digest: usage.Digest}
+		allowed := registryAllowed(ref, req.allowed)
+
+		summary, ok := registries[usage.Registry]
+		if !ok {
+			summary = &RegistrySummary{Registry: usage.Registry}
+			registries[usage.Registry] = summary
+			registryPods[usage.Registry] = map[string]bool{}
+		}
+		summary.Images++
+		for pod := range imagePods[image] {
+			registryPods[usage.Registry][pod] = true
+		}
+		usage.Pods = len(imagePods[image])
+
+		workloads := make([]string, 0, len(imageWorkloads[image]))
+		for workloadKey := range imageWorkloads[image] {
+			workloads = append(workloads, workloadKey)
+		}
+		sort.Strings(workloads)
+		usage.Workloads = workloads
+		if len(usage.Workloads) > maxImageWorkloads {
+			usage.Workloads = usage.Workloads[:maxImageWorkloads]
+		}
+
+		if !allowed {
+			summary.NotAllowed++
+			usage.Flags = append(usage.Flags, imageNotAllowed)
+		}
+		if ref.digest == "" && ref.tag == "latest" {
+			usage.Flags = append(usage.Flags, imageLatest)
+		}
+		for _, flag := range usage.Flags {
+			for _, workloadKey := range workloads {
+				finding := ImageFinding{
+					Finding:   flag,
+					Namespace: workloadNamespace[workloadKey],
+					Workload:  workloadName[workloadKey],
+					Images:    []string{image},
+				}
+				if flag == imageNotAllowed {
+					finding.Detail = fmt.Sprintf("%s is not an allowed registry", usage.Registry)
+				} else {
+					finding.Detail = "the latest tag changes with every pull; pin a version or digest"
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	for workloadKey, repos := range workloadTags {
+		for repo, tags := range repos {
+			if len(tags) < 2 {
+				continue
+			}
+			var names []string
+			for image := range tags {
+				names = append(names, image)
+			}
+			sort.Strings(names)
+			findings = append(findings, ImageFinding{
+				Finding:   imageMixedTags,
+				Namespace: workloadNamespace[workloadKey],
+				Workload:  workloadName[workloadKey],
+				Images:    names,
+				Detail:    fmt.Sprintf("%d different images of %s run in one workload", len(names), repo),
+			})
+		}
+	}
+
+	for name, summary := range registries {
+		summary.Pods = len(registryPods[name])
+		out.Registries = append(out.Registries, *summary)
+	}
+	sort.Slice(out.Registries, func(i, j int) bool {
+		if out.Registries[i].Pods != out.Registries[j].Pods {
+			return out.Registries[i].Pods > out.Registries[j].Pods
+		}
+		return out.Registries[i].Registry < out.Registries[j].Registry
+	})
+
+	var usages []ImageUsage
+	for _, usage := range images {
+		usages = append(usages, *usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		if a.Registry != b.Registry {
+			return a.Registry < b.Registry
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.Image < b.Image
+	})
+	out.TotalImages = len(usages)
+	if len(usages) > req.limit {
+		usages = usages[:req.limit]
+		out.ImagesTruncated = true
+	}
+	out.Images = append(out.Images, usages...)
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Finding != b.Finding {
+			return imageFindingOrder[a.Finding] < imageFindingOrder[b.Finding]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		return strings.Join(a.Images, ",") < strings.Join(b.Images, ",")
+	})
+	out.TotalFindings = len(findings)
+	if len(findings) > req.limit {
+		findings = findings[:req.limit]
+		out.FindingsTruncated = true
+	}
+	out.Findings = append(out.Findings, findings...)
+	return out, nil
+}
+
+// parseImageRef splits an image reference the way container runtimes
+// resolve it: a first path component with a dot or port, or localhost, is
+// the registry; otherwise the image is on Docker Hub, where single-component
+// names are official images under library/. A reference with neither tag
+// nor digest uses the latest tag.
+func parseImageRef(image string) imageRef {
+	var ref imageRef
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.digest = name[i+1:]
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.tag = name[i+1:]
+		name = name[:i]
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+
+	ref.registry = dockerHub
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry = strings.ToLower(first)
+		name = rest
+	}
+	if ref.registry == "index.docker.io" || ref.registry == "registry-1.docker.io" {
+		ref.registry = dockerHub
+	}
+	if ref.registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.repository = name
+	return ref
+}
+
+// registryAllowed reports whether an image matches the allowlist: a
+// registry host, or a repository prefix matched at a path boundary. Every
+// image is allowed without an allowlist.
+func registryAllowed(ref imageRef, allowed []string) bool {
+	if allowed == nil {
+		return true
+	}
+	path := ref.registry + "/" + ref.repository
+	for _, entry := range allowed {
+		if entry == ref.registry || strings.HasPrefix(path, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// podWorkload returns the controller of a pod as Kind/name. Pods of a
+// ReplicaSet created by a Deployment are attributed to the Deployment, whose
+// name is the ReplicaSet's without the pod template hash.
+func podWorkload(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels["pod-template-hash"]; hash != "" {
+				if name, ok := strings.CutSuffix(ref.Name, "-"+hash); ok {
+					return "Deployment/" + name
+				}
+			}
+		}
+		return ref.Kind + "/" + ref.Name
+	}
+	return "Pod/" + pod.Name
+}
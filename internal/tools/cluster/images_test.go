@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// imagesPod returns a pod of a Deployment's ReplicaSet running the images.
+func imagesPod(name, deployment string, images ...string) *corev1.Pod {
+	controller := true
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "shop",
+		Name:            name,
+		Labels:          map[string]string{"pod-template-hash": "5c6d7"},
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: deployment + "-5c6d7", Controller: &controller}},
+	}}
+	for i, image := range images {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: fmt.Sprintf("c%d", i), Image: image})
+	}
+	return pod
+}
+
+func TestInventoryImages(t *testing.T) {
+	client := mcptest.NewFakeClient(map[string][]runtime.Object{
+		"pods": {
+			imagesPod("web-1", "web", "gsoci.azurecr.io/giantswarm/web:1.2.0", "nginx"),
+			imagesPod("web-2", "web", "gsoci.azurecr.io/giantswarm/web:1.3.0", "nginx"),
+			imagesPod("api-1", "api", "quay.io/acme/api@sha256:abc"),
+		},
+	})
+	req, errMsg := parseImagesRequest(map[string]interface{}{"allowedRegistries": "gsoci.azurecr.io, https://docker.io/library/"})
+	require.Empty(t, errMsg)
+
+	out, err := inventoryImages(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, out.PodsScanned)
+	assert.Equal(t, activePodsFieldSelector, client.ListOpts["pods"][0].FieldSelector)
+	assert.True(t, client.ListOpts["pods"][0].AllNamespaces)
+
+	assert.Equal(t, []RegistrySummary{
+		{Registry: "docker.io", Images: 1, Pods: 2},
+		{Registry: "gsoci.azurecr.io", Images: 2, Pods: 2},
+		{Registry: "quay.io", Images: 1, Pods: 1, NotAllowed: 1},
+	}, out.Registries)
+
+	require.Equal(t, 4, out.TotalImages)
+	nginx := out.Images[0]
+	assert.Equal(t, "nginx", nginx.Image)
+	assert.Equal(t, "library/nginx", nginx.Repository)
+	assert.Equal(t, "latest", nginx.Tag)
+	assert.Equal(t, 2, nginx.Pods)
+	assert.Equal(t, []string{"shop/Deployment/web"}, nginx.Workloads)
+	assert.Equal(t, []string{imageLatest}, nginx.Flags)
+
+	api := out.Images[3]
+	assert.Equal(t, "acme/api", api.Repository)
+	assert.Equal(t, "sha256:abc", api.Digest)
+	assert.Empty(t, api.Tag, "a digest is not the latest tag")
+
+	require.Len(t, out.Findings, 3)
+	assert.Equal(t, imageNotAllowed, out.Findings[0].Finding)
+	assert.Equal(t, "Deployment/api", out.Findings[0].Workload)
+	assert.Equal(t, imageLatest, out.Findings[1].Finding)
+	assert.Equal(t, ImageFinding{
+		Finding:   imageMixedTags,
+		Namespace: "shop",
+		Workload:  "Deployment/web",
+		Images:    []string{"gsoci.azurecr.io/giantswarm/web:1.2.0", "gsoci.azurecr.io/giantswarm/web:1.3.0"},
+		Detail:    "2 different images of gsoci.azurecr.io/giantswarm/web run in one workload",
+	}, out.Findings[2])
+}
+
+func TestImages_FleetDeniedCluster(t *testing.T) {
+	result := callTool(t, mcptest.NewFakeClient(nil), "images", map[string]interface{}{"clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: images")
+}
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		image string
+		want  imageRef
+	}{
+		{"nginx", imageRef{registry: "docker.io", repository: "library/nginx", tag: "latest"}},
+		{"bitnami/redis:7.2", imageRef{registry: "docker.io", repository: "bitnami/redis", tag: "7.2"}},
+		{"index.docker.io/library/busybox:1", imageRef{registry: "docker.io", repository: "library/busybox", tag: "1"}},
+		{"localhost:5000/app", imageRef{registry: "localhost:5000", repository: "app", tag: "latest"}},
+		{"registry.k8s.io/pause:3.9@sha256:def", imageRef{registry: "registry.k8s.io", repository: "pause", tag: "3.9", digest: "sha256:def"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseImageRef(tt.image))
+		})
+	}
+}
+
+func TestRegistryAllowed(t *testing.T) {
+	allowed := []string{"quay.io/giantswarm", "gsoci.azurecr.io"}
+	assert.True(t, registryAllowed(parseImageRef("quay.io/giantswarm/app:1"), allowed))
+	assert.False(t, registryAllowed(parseImageRef("quay.io/giantswarmx/app:1"), allowed), "prefixes match whole path components")
+	assert.True(t, registryAllowed(parseImageRef("gsoci.azurecr.io/any/app:1"), allowed))
+	assert.True(t, registryAllowed(parseImageRef("nginx"), nil), "everything is allowed without an allowlist")
+}
+
+func TestParseImagesRequest(t *testing.T) {
+	_, errMsg := parseImagesRequest(map[string]interface{}{"allowedRegistries": "*.example.com"})
+	assert.Contains(t, errMsg, "invalid allowedRegistries entry")
+
+	_, errMsg = parseImagesRequest(map[string]interface{}{"limit": float64(MaxImagesLimit + 1)})
+	assert.Contains(t, errMsg, "limit must be between")
+}
@@ -0,0 +1,445 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// MaxGracePeriodSeconds caps the gracePeriodSeconds argument of evict and
+// drain.
+const MaxGracePeriodSeconds = 3600
+
+// mirrorPodAnnotation marks the API server's copy of a static pod. Mirror
+// pods cannot be evicted; the kubelet owns them.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// CordonResult is the output of the cordon and uncordon tools.
+type CordonResult struct {
+	Node          string `json:"node"`
+	Unschedulable bool   `json:"unschedulable"`
+	// Changed is false when the node was already in the requested state.
+	Changed bool `json:"changed"`
+	DryRun  bool `json:"dryRun,omitempty"`
+}
+
+// PodDisruptionBudgetRef summarises a PodDisruptionBudget covering a pod.
+type PodDisruptionBudgetRef struct {
+	Name               string `json:"name"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+}
+
+// EvictResult is the output of the evict tool.
+type EvictResult struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Evicted   bool   `json:"evicted"`
+	DryRun    bool   `json:"dryRun,omitempty"`
+}
+
+// DrainPod names a pod the drain tool did not evict, and why.
+type DrainPod struct {
+	Pod    string `json:"pod"`
+	Reason string `json:"reason"`
+	// PodDisruptionBudgets are the budgets that refused the eviction.
+	PodDisruptionBudgets []PodDisruptionBudgetRef `json:"podDisruptionBudgets,omitempty"`
+}
+
+// DrainResult is the output of the drain tool.
+type DrainResult struct {
+	Node string `json:"node"`
+	// Cordoned is false when the node was already unschedulable.
+	Cordoned bool `json:"cordoned"`
+	DryRun   bool `json:"dryRun,omitempty"`
+	// Complete is true when every pod that had to go was evicted.
+	Complete bool       `json:"complete"`
+	Evicted  []string   `json:"evicted"`
+	Skipped  []DrainPod `json:"skipped,omitempty"`
+	Blocked  []DrainPod `json:"blocked,omitempty"`
+	Failed   []DrainPod `json:"failed,omitempty"`
+	Hint     string     `json:"hint,omitempty"`
+}
+
+// drainRequest holds the validated drain arguments.
+type drainRequest struct {
+	cluster            string
+	kubeContext        string
+	node               string
+	ignoreDaemonSets   bool
+	deleteEmptyDirData bool
+	force              bool
+	evict              k8s.EvictOptions
+}
+
+// drainPlan sorts the pods on a node by what drain does with them.
+type drainPlan struct {
+	evict   []*corev1.Pod
+	skipped []DrainPod
+	refused []DrainPod
+}
+
+// handleCordon marks a node unschedulable.
+func handleCordon(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	return setUnschedulable(ctx, request, sc, "cordon", true)
+}
+
+// handleUncordon marks a node schedulable again.
+func handleUncordon(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	return setUnschedulable(ctx, request, sc, "uncordon", false)
+}
+
+func setUnschedulable(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext, operation string, unschedulable bool) (*mcp.CallToolResult, error) {
+	if result := tools.CheckMutatingOperation(sc, operation); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	kubeContext, _ := args["kubeContext"].(string)
+	nodeName, _ := args["nodeName"].(string)
+	if nodeName == "" {
+		return toolerrors.Required("nodeName").Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	changed, err := cordonNode(ctx, client.K8s(), kubeContext, nodeName, unschedulable)
+	if err != nil {
+		return tools.K8sError(fmt.Sprintf("Failed to %s node", operation), err, client.User()).Result(), nil
+	}
+
+	return jsonResult(CordonResult{
+		Node:          nodeName,
+		Unschedulable: unschedulable,
+		Changed:       changed,
+		DryRun:        tools.IsDryRun(ctx, sc),
+	})
+}
+
+// cordonNode sets spec.unschedulable on a node. It reports whether the node
+// had to be changed.
+func cordonNode(ctx context.Context, client k8s.Client, kubeContext, nodeName string, unschedulable bool) (bool, error) {
+	resp, err := client.Get(ctx, kubeContext, "", "nodes", "", nodeName)
+	if err != nil {
+		return false, err
+	}
+	var node corev1.Node
+	if err := fromObject(resp.Resource, &node); err != nil {
+		return false, fmt.Errorf("failed to decode node: %w", err)
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return false, nil
+	}
+
+	// Uncordon removes the field, as kubectl does.
+	patch := []byte(`{"spec":{"unschedulable":null}}`)
+	if unschedulable {
+		patch = []byte(`{"spec":{"unschedulable":true}}`)
+	}
+	if _, err := client.Patch(ctx, kubeContext, "", "nodes", "", nodeName, types.MergePatchType, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// handleEvict evicts a single pod through the Eviction API.
+func handleEvict(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := tools.CheckMutatingOperation(sc, "evict"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	kubeContext, _ := args["kubeContext"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	podName, _ := args["podName"].(string)
+	if podName == "" {
+		return toolerrors.Required("podName").Result(), nil
+	}
+	opts, errMsg := parseEvictOptions(args)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	if err := k8sClient.EvictPod(ctx, kubeContext, namespace, podName, opts); err != nil {
+		if apierrors.IsTooManyRequests(err) {
+			return toolerrors.New(toolerrors.CodeFailedPrecondition, blockedEvictionMessage(ctx, k8sClient, kubeContext, namespace, podName)).Result(), nil
+		}
+		return tools.K8sError("Failed to evict pod", err, client.User()).Result(), nil
+	}
+
+	return jsonResult(EvictResult{
+		Namespace: namespace,
+		Pod:       podName,
+		Evicted:   true,
+		DryRun:    tools.IsDryRun(ctx, sc),
+	})
+}
+
+// blockedEvictionMessage explains an eviction refused by a
+// PodDisruptionBudget, naming the budgets when they can be read.
+func blockedEvictionMessage(ctx context.Context, client k8s.Client, kubeContext, namespace, podName string) string {
+	msg := fmt.Sprintf("Eviction of pod %s/%s is blocked by a PodDisruptionBudget", namespace, podName)
+
+	resp, err := client.Get(ctx, kubeContext, namespace, "pods", "", podName)
+	if err != nil {
+		return msg
+	}
+	var pod corev1.Pod
+	if err := fromObject(resp.Resource, &pod); err != nil {
+		return msg
+	}
+	budgets, err := newPDBLookup(client, kubeContext).covering(ctx, &pod)
+	if err != nil || len(budgets) == 0 {
+		return msg
+	}
+
+	names := make([]string, 0, len(budgets))
+	for _, b := range budgets {
+		names = append(names, fmt.Sprintf("%s (disruptionsAllowed=%d, currentHealthy=%d, desiredHealthy=%d)",
+			b.Name, b.DisruptionsAllowed, b.CurrentHealthy, b.DesiredHealthy))
+	}
+	return fmt.Sprintf("%s: %s. Retry once the workload has recovered, or scale it up first.", msg, strings.Join(names, ", "))
+}
+
+// handleDrain cordons a node and evicts its pods.
+func handleDrain(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := tools.CheckMutatingOperation(sc, "drain"); result != nil {
+		return result, nil
+	}
+
+	req, errMsg := parseDrainRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	var pods []*corev1.Pod
+	err := listAll(ctx, k8sClient, req.kubeContext, "", "pods", "",
+		k8s.ListOptions{AllNamespaces: true, FieldSelector: "spec.nodeName=" + req.node},
+		func(pod *corev1.Pod) { pods = append(pods, pod) })
+	if err != nil {
+		return tools.K8sError("Failed to list pods on node", err, client.User()).Result(), nil
+	}
+
+	plan := planDrain(pods, req)
+	if len(plan.refused) > 0 {
+		return toolerrors.New(toolerrors.CodeFailedPrecondition, refusedDrainMessage(req.node, plan.refused)).Result(), nil
+	}
+
+	cordoned, err := cordonNode(ctx, k8sClient, req.kubeContext, req.node, true)
+	if err != nil {
+		return tools.K8sError("Failed to cordon node", err, client.User()).Result(), nil
+	}
+
+	result := DrainResult{
+		Node:     req.node,
+		Cordoned: cordoned,
+		DryRun:   tools.IsDryRun(ctx, sc),
+		Evicted:  []string{},
+		Skipped:  plan.skipped,
+	}
+	budgets := newPDBLookup(k8sClient, req.kubeContext)
+	for _, pod := range plan.evict {
+		name := podKey(pod)
+		err := k8sClient.EvictPod(ctx, req.kubeContext, pod.Namespace, pod.Name, req.evict)
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+			result.Evicted = append(result.Evicted, name)
+		case apierrors.IsTooManyRequests(err):
+			blocked := DrainPod{Pod: name, Reason: "eviction refused by a PodDisruptionBudget"}
+			blocked.PodDisruptionBudgets, _ = budgets.covering(ctx, pod)
+			result.Blocked = append(result.Blocked, blocked)
+		default:
+			result.Failed = append(result.Failed, DrainPod{Pod: name, Reason: err.Error()})
+		}
+	}
+
+	result.Complete = len(result.Blocked) == 0 && len(result.Failed) == 0
+	if !result.Complete {
+		result.Hint = "The node stays cordoned. Call drain again to retry the remaining pods once their workloads have recovered; pods blocked by a PodDisruptionBudget need another replica to become healthy first."
+	}
+	return jsonResult(result)
+}
+
+// parseDrainRequest validates the drain arguments. DaemonSet pods are
+// skipped unless ignoreDaemonSets is explicitly false.
+func parseDrainRequest(args map[string]interface{}) (drainRequest, string) {
+	req := drainRequest{
+		cluster:          tools.ExtractClusterParam(args),
+		ignoreDaemonSets: true,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.node, _ = args["nodeName"].(string)
+	if req.node == "" {
+		return req, "nodeName is required"
+	}
+	if v, ok := args["ignoreDaemonSets"].(bool); ok {
+		req.ignoreDaemonSets = v
+	}
+	req.deleteEmptyDirData, _ = args["deleteEmptyDirData"].(bool)
+	req.force, _ = args["force"].(bool)
+
+	var errMsg string
+	req.evict, errMsg = parseEvictOptions(args)
+	return req, errMsg
+}
+
+// parseEvictOptions reads the optional gracePeriodSeconds argument.
+func parseEvictOptions(args map[string]interface{}) (k8s.EvictOptions, string) {
+	var opts k8s.EvictOptions
+	if v, ok := args["gracePeriodSeconds"].(float64); ok {
+		if v < 0 || v > MaxGracePeriodSeconds {
+			return opts, fmt.Sprintf("gracePeriodSeconds must be between 0 and %d", MaxGracePeriodSeconds)
+		}
+		seconds := int64(v)
+		opts.GracePeriodSeconds = &seconds
+	}
+	return opts, ""
+}
+
+// planDrain decides, like kubectl drain, which pods are evicted, which are
+// left alone and which prevent the drain.
+func planDrain(pods []*corev1.Pod, req drainRequest) drainPlan {
+	var plan drainPlan
+	for _, pod := range pods {
+		name := podKey(pod)
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			plan.skipped = append(plan.skipped, DrainPod{Pod: name, Reason: "static pod managed by the kubelet"})
+			continue
+		}
+		// Finished pods hold nothing worth protecting.
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			plan.evict = append(plan.evict, pod)
+			continue
+		}
+
+		controller := metav1.GetControllerOf(pod)
+		if controller != nil && controller.Kind == "DaemonSet" {
+			if req.ignoreDaemonSets {
+				plan.skipped = append(plan.skipped, DrainPod{Pod: name, Reason: "managed by DaemonSet " + controller.Name})
+			} else {
+				plan.refused = append(plan.refused, DrainPod{Pod: name, Reason: "managed by a DaemonSet, which would recreate it on the node (set ignoreDaemonSets=true)"})
+			}
+			continue
+		}
+		if controller == nil && !req.force {
+			plan.refused = append(plan.refused, DrainPod{Pod: name, Reason: "not managed by a controller and would not be recreated (set force=true)"})
+			continue
+		}
+		if usesEmptyDir(pod) && !req.deleteEmptyDirData {
+			plan.refused = append(plan.refused, DrainPod{Pod: name, Reason: "uses emptyDir volumes whose data would be lost (set deleteEmptyDirData=true)"})
+			continue
+		}
+		plan.evict = append(plan.evict, pod)
+	}
+	return plan
+}
+
+func usesEmptyDir(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// refusedDrainMessage lists the pods that prevent a drain.
+func refusedDrainMessage(node string, refused []DrainPod) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cannot drain node %s; nothing was changed. %d pod(s) cannot be evicted safely:\n", node, len(refused))
+	for _, p := range refused {
+		fmt.Fprintf(&b, "- %s: %s\n", p.Pod, p.Reason)
+	}
+	return b.String()
+}
+
+// pdbLookup finds the PodDisruptionBudgets covering pods. Budgets are listed
+// once per namespace.
+type pdbLookup struct {
+	client      k8s.Client
+	kubeContext string
+	byNamespace map[string][]policyv1.PodDisruptionBudget
+}
+
+func newPDBLookup(client k8s.Client, kubeContext string) *pdbLookup {
+	return &pdbLookup{
+		client:      client,
+		kubeContext: kubeContext,
+		byNamespace: map[string][]policyv1.PodDisruptionBudget{},
+	}
+}
+
+// covering returns the budgets whose selector matches pod, sorted by name.
+func (l *pdbLookup) covering(ctx context.Context, pod *corev1.Pod) ([]PodDisruptionBudgetRef, error) {
+	budgets, ok := l.byNamespace[pod.Namespace]
+	if !ok {
+		err := listAll(ctx, l.client, l.kubeContext, pod.Namespace, "poddisruptionbudgets", "policy", k8s.ListOptions{},
+			func(pdb *policyv1.PodDisruptionBudget) { budgets = append(budgets, *pdb) })
+		if err != nil {
+			return nil, err
+		}
+		l.byNamespace[pod.Namespace] = budgets
+	}
+
+	var out []PodDisruptionBudgetRef
+	for _, pdb := range budgets {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		out = append(out, PodDisruptionBudgetRef{
+			Name:               pdb.Name,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func podKey(pod *corev1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+func jsonResult(v interface{}) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,266 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// maintenanceMock serves one node, the pods on it and the budgets covering
+// them, and records patches and evictions.
+type maintenanceMock struct {
+	*testdata.MockK8sClient
+
+	node    *corev1.Node
+	pods    []*corev1.Pod
+	budgets []runtime.Object
+	blocked map[string]bool
+
+	patches   []string
+	evictions []string
+}
+
+func (m *maintenanceMock) Get(_ context.Context, _, namespace, resourceType, _, name string) (*k8s.GetResponse, error) {
+	if resourceType == "pods" {
+		for _, pod := range m.pods {
+			if pod.Namespace == namespace && pod.Name == name {
+				return &k8s.GetResponse{Resource: pod}, nil
+			}
+		}
+		return nil, apierrors.NewNotFound(corev1.Resource("pods"), name)
+	}
+	return &k8s.GetResponse{Resource: m.node}, nil
+}
+
+func (m *maintenanceMock) List(_ context.Context, _, namespace, resourceType, _ string, _ k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	if resourceType == "poddisruptionbudgets" {
+		var items []runtime.Object
+		for _, b := range m.budgets {
+			if b.(*policyv1.PodDisruptionBudget).Namespace == namespace {
+				items = append(items, b)
+			}
+		}
+		return &k8s.PaginatedListResponse{Items: items}, nil
+	}
+	items := make([]runtime.Object, 0, len(m.pods))
+	for _, pod := range m.pods {
+		items = append(items, pod)
+	}
+	return &k8s.PaginatedListResponse{Items: items}, nil
+}
+
+func (m *maintenanceMock) Patch(_ context.Context, _, _, _, _, _ string, _ types.PatchType, data []byte) (*k8s.PatchResponse, error) {
+	m.patches = append(m.patches, string(data))
+	return &k8s.PatchResponse{}, nil
+}
+
+func (m *maintenanceMock) EvictPod(_ context.Context, _, namespace, podName string, _ k8s.EvictOptions) error {
+	key := namespace + "/" + podName
+	if m.blocked[key] {
+		return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+	}
+	m.evictions = append(m.evictions, key)
+	return nil
+}
+
+func maintenancePod(namespace, name, ownerKind string, labels map[string]string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: "worker-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ownerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: name + "-owner", Controller: &controller}}
+	}
+	return pod
+}
+
+func newMaintenanceMock() *maintenanceMock {
+	mirror := maintenancePod("kube-system", "kube-proxy-worker-1", "", nil)
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+	finished := maintenancePod("shop", "migration", "", nil)
+	finished.Status.Phase = corev1.PodSucceeded
+
+	return &maintenanceMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		node:          &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		pods: []*corev1.Pod{
+			maintenancePod("kube-system", "node-exporter", "DaemonSet", nil),
+			mirror,
+			maintenancePod("shop", "web-1", "ReplicaSet", map[string]string{"app": "web"}),
+			maintenancePod("shop", "db-0", "StatefulSet", map[string]string{"app": "db"}),
+			finished,
+		},
+		budgets: []runtime.Object{
+			&policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 2, DesiredHealthy: 2},
+			},
+			&policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+			},
+		},
+		blocked: map[string]bool{"shop/db-0": true},
+	}
+}
+
+func callMaintenanceTool(t *testing.T, mock *maintenanceMock, config *server.Config, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithConfig(config),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request, sc)
+	require.NoError(t, err)
+	return result
+}
+
+func TestCordonAndUncordon(t *testing.T) {
+	mock := newMaintenanceMock()
+	args := map[string]interface{}{"nodeName": "worker-1"}
+
+	result := callMaintenanceTool(t, mock, mutatingConfig(), handleCordon, args)
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	var out CordonResult
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.Equal(t, CordonResult{Node: "worker-1", Unschedulable: true, Changed: true}, out)
+	assert.Equal(t, []string{`{"spec":{"unschedulable":true}}`}, mock.patches)
+
+	// Uncordoning a schedulable node changes nothing.
+	result = callMaintenanceTool(t, mock, mutatingConfig(), handleUncordon, args)
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.False(t, out.Changed)
+	assert.Len(t, mock.patches, 1)
+
+	mock.node.Spec.Unschedulable = true
+	result = callMaintenanceTool(t, mock, mutatingConfig(), handleUncordon, args)
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	assert.Equal(t, `{"spec":{"unschedulable":null}}`, mock.patches[1])
+}
+
+func TestDrain(t *testing.T) {
+	mock := newMaintenanceMock()
+	result := callMaintenanceTool(t, mock, mutatingConfig(), handleDrain, map[string]interface{}{"nodeName": "worker-1"})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+
+	var out DrainResult
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.True(t, out.Cordoned)
+	assert.False(t, out.Complete)
+	assert.NotEmpty(t, out.Hint)
+	assert.Equal(t, []string{"shop/web-1", "shop/migration"}, out.Evicted)
+	assert.Equal(t, mock.evictions, out.Evicted)
+	assert.Equal(t, []DrainPod{
+		{Pod: "kube-system/node-exporter", Reason: "managed by DaemonSet node-exporter-owner"},
+		{Pod: "kube-system/kube-proxy-worker-1", Reason: "static pod managed by the kubelet"},
+	}, out.Skipped)
+	require.Len(t, out.Blocked, 1)
+	assert.Equal(t, "shop/db-0", out.Blocked[0].Pod)
+	assert.Equal(t, []PodDisruptionBudgetRef{{Name: "db", CurrentHealthy: 2, DesiredHealthy: 2}}, out.Blocked[0].PodDisruptionBudgets)
+}
+
+func TestDrain_RefusesUnsafePods(t *testing.T) {
+	mock := newMaintenanceMock()
+	bare := maintenancePod("shop", "debug", "", nil)
+	scratch := maintenancePod("shop", "cache-0", "StatefulSet", nil)
+	scratch.Spec.Volumes = []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	mock.pods = append(mock.pods, bare, scratch)
+
+	result := callMaintenanceTool(t, mock, mutatingConfig(), handleDrain, map[string]interface{}{
+		"nodeName":         "worker-1",
+		"ignoreDaemonSets": false,
+	})
+	require.True(t, result.IsError)
+	text := mcptest.ResultText(result)
+	assert.Contains(t, text, "3 pod(s) cannot be evicted safely")
+	assert.Contains(t, text, "kube-system/node-exporter: managed by a DaemonSet")
+	assert.Contains(t, text, "shop/debug: not managed by a controller")
+	assert.Contains(t, text, "shop/cache-0: uses emptyDir volumes")
+	assert.Empty(t, mock.patches, "a refused drain must not cordon the node")
+	assert.Empty(t, mock.evictions)
+
+	mock.blocked = nil
+	result = callMaintenanceTool(t, mock, mutatingConfig(), handleDrain, map[string]interface{}{
+		"nodeName":           "worker-1",
+		"force":              true,
+		"deleteEmptyDirData": true,
+	})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	var out DrainResult
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.True(t, out.Complete)
+	assert.Contains(t, out.Evicted, "shop/debug")
+	assert.Contains(t, out.Evicted, "shop/cache-0")
+}
+
+func TestEvict(t *testing.T) {
+	mock := newMaintenanceMock()
+
+	result := callMaintenanceTool(t, mock, mutatingConfig(), handleEvict, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "web-1",
+	})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	assert.Equal(t, []string{"shop/web-1"}, mock.evictions)
+
+	result = callMaintenanceTool(t, mock, mutatingConfig(), handleEvict, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "db-0",
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "blocked by a PodDisruptionBudget: db (disruptionsAllowed=0, currentHealthy=2, desiredHealthy=2)")
+
+	result = callMaintenanceTool(t, mock, mutatingConfig(), handleEvict, map[string]interface{}{
+		"namespace":          "shop",
+		"podName":            "web-1",
+		"gracePeriodSeconds": float64(-1),
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "gracePeriodSeconds must be between 0 and 3600")
+}
+
+func TestMaintenanceTools_NonDestructiveMode(t *testing.T) {
+	for name, handler := range map[string]func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error){
+		"cordon":   handleCordon,
+		"uncordon": handleUncordon,
+		"drain":    handleDrain,
+		"evict":    handleEvict,
+	} {
+		t.Run(name, func(t *testing.T) {
+			mock := newMaintenanceMock()
+			result := callMaintenanceTool(t, mock, server.NewDefaultConfig(), handler, map[string]interface{}{
+				"nodeName":  "worker-1",
+				"namespace": "shop",
+				"podName":   "web-1",
+			})
+			require.True(t, result.IsError)
+			assert.Contains(t, mcptest.ResultText(result), "not allowed in non-destructive mode")
+			assert.Empty(t, mock.patches)
+			assert.Empty(t, mock.evictions)
+		})
+	}
+}
@@ -0,0 +1,559 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the find_orphans tool's parameters.
+const (
+	DefaultOrphansLimit = 50
+	MaxOrphansLimit     = 500
+
+	DefaultOrphanUnboundDays = 7
+	MaxOrphanUnboundDays     = 365
+)
+
+// The kinds find_orphans checks, as accepted by its kinds argument.
+const (
+	orphanReplicaSets = "replicasets"
+	orphanClaims      = "persistentvolumeclaims"
+	orphanConfigMaps  = "configmaps"
+	orphanSecrets     = "secrets"
+	orphanServices    = "services"
+)
+
+// orphanKinds are the checks in report order, with the kind and API group
+// of the objects they find.
+var orphanKinds = []struct {
+	resource, kind, apiGroup string
+}{
+	{orphanReplicaSets, "ReplicaSet", "apps"},
+	{orphanClaims, "PersistentVolumeClaim", ""},
+	{orphanConfigMaps, "ConfigMap", ""},
+	{orphanSecrets, "Secret", ""},
+	{orphanServices, "Service", ""},
+}
+
+// Secrets that are consumed by something other than pods, and so are never
+// reported as unreferenced.
+var orphanIgnoredSecretTypes = map[corev1.SecretType]bool{
+	corev1.SecretTypeServiceAccountToken: true,
+	corev1.SecretTypeBootstrapToken:      true,
+	"helm.sh/release.v1":                 true,
+}
+
+// orphanIgnoredConfigMaps are published to every namespace by Kubernetes.
+var orphanIgnoredConfigMaps = map[string]bool{
+	"kube-root-ca.crt": true,
+}
+
+// certManagerCertificateAnnotation marks Secrets written by cert-manager for
+// a Certificate, which keeps them up to date whether or not they are used.
+const certManagerCertificateAnnotation = "cert-manager.io/certificate-name"
+
+// OrphanFinding is an object find_orphans considers likely unused.
+type OrphanFinding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	AgeDays   int    `json:"ageDays"`
+}
+
+// OrphanDeleteStep holds the arguments of the delete tool call that would
+// remove a finding.
+type OrphanDeleteStep struct {
+	ResourceType string `json:"resourceType"`
+	APIGroup     string `json:"apiGroup,omitempty"`
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+}
+
+// OrphansOutput is the response of the find_orphans tool. Findings are
+// grouped by kind, then sorted by namespace and name.
+type OrphansOutput struct {
+	// Namespace is empty when every namespace was checked.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Summary counts the findings per kind, including those cut by limit.
+	Summary           map[string]int  `json:"summary"`
+	Findings          []OrphanFinding `json:"findings"`
+	TotalFindings     int             `json:"totalFindings"`
+	FindingsTruncated bool            `json:"findingsTruncated,omitempty"`
+
+	// DeletePlan is set when requested. Nothing is deleted by find_orphans.
+	DeletePlan []OrphanDeleteStep `json:"deletePlan,omitempty"`
+
+	// Skipped lists checks that could not run, for example for lack of
+	// permissions.
+	Skipped []string `json:"skipped,omitempty"`
+	Notes   []string `json:"notes,omitempty"`
+}
+
+// orphansRequest holds the validated find_orphans arguments.
+type orphansRequest struct {
+	cluster     string
+	kubeContext string
+	namespace   string
+	kinds       map[string]bool
+	unboundFor  time.Duration
+	limit       int
+	deletePlan  bool
+}
+
+// handleFindOrphans handles the find_orphans tool.
+func handleFindOrphans(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseOrphansRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	out := findOrphans(ctx, client.K8s(), req, time.Now())
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal orphans: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseOrphansRequest validates the tool arguments. limit must lie within 1
+// and MaxOrphansLimit, unboundDays within 0 and MaxOrphanUnboundDays, and
+// kinds may only name the kinds in orphanKinds.
+func parseOrphansRequest(args map[string]interface{}) (orphansRequest, string) {
+	req := orphansRequest{
+		cluster:    tools.ExtractClusterParam(args),
+		kinds:      map[string]bool{},
+		unboundFor: DefaultOrphanUnboundDays * 24 * time.Hour,
+		limit:      DefaultOrphansLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	req.deletePlan, _ = args["deletePlan"].(bool)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxOrphansLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxOrphansLimit)
+		}
+		req.limit = int(v)
+	}
+	if v, ok := args["unboundDays"].(float64); ok {
+		if v < 0 || v > MaxOrphanUnboundDays {
+			return req, fmt.Sprintf("unboundDays must be between 0 and %d", MaxOrphanUnboundDays)
+		}
+		req.unboundFor = time.Duration(v * float64(24*time.Hour))
+	}
+
+	kinds, _ := args["kinds"].(string)
+	if kinds == "" {
+		for _, k := range orphanKinds {
+			req.kinds[k.resource] = true
+		}
+		return req, ""
+	}
+	for _, kind := range strings.Split(kinds, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !isOrphanKind(kind) {
+			return req, fmt.Sprintf("unknown kind %q: use replicasets, persistentvolumeclaims, configmaps, secrets or services", kind)
+		}
+		req.kinds[kind] = true
+	}
+	return req, ""
+}
+
+func isOrphanKind(resource string) bool {
+	for _, k := range orphanKinds {
+		if k.resource == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// orphanScan collects the findings of one find_orphans call.
+type orphanScan struct {
+	client k8s.Client
+	req    orphansRequest
+	now    time.Time
+	out    *OrphansOutput
+
+	findings map[string][]OrphanFinding
+}
+
+// findOrphans runs the requested checks. A check that cannot list what it
+// needs is reported in Skipped instead of failing the call.
+func findOrphans(ctx context.Context, client k8s.Client, req orphansRequest, now time.Time) *OrphansOutput {
+	s := &orphanScan{
+		client:   client,
+		req:      req,
+		now:      now,
+		out:      &OrphansOutput{Namespace: req.namespace, Summary: map[string]int{}, Findings: []OrphanFinding{}},
+		findings: map[string][]OrphanFinding{},
+	}
+
+	// ConfigMaps and Secrets share the references, collected at most once.
+	var refs *podReferences
+	var refsErr error
+	references := func(ctx context.Context) (*podReferences, error) {
+		if refs == nil && refsErr == nil {
+			refs, refsErr = s.collectReferences(ctx)
+			if refsErr != nil {
+				refsErr = fmt.Errorf("cannot tell which objects are in use: %w", refsErr)
+			}
+		}
+		return refs, refsErr
+	}
+
+	checks := map[string]func(context.Context) error{
+		orphanReplicaSets: s.checkReplicaSets,
+		orphanClaims:      s.checkClaims,
+		orphanConfigMaps: func(ctx context.Context) error {
+			refs, err := references(ctx)
+			if err != nil {
+				return err
+			}
+			return s.checkConfigMaps(ctx, refs)
+		},
+		orphanSecrets: func(ctx context.Context) error {
+			refs, err := references(ctx)
+			if err != nil {
+				return err
+			}
+			return s.checkSecrets(ctx, refs)
+		},
+		orphanServices: s.checkServices,
+	}
+	for _, k := range orphanKinds {
+		if !req.kinds[k.resource] {
+			continue
+		}
+		if err := checks[k.resource](ctx); err != nil {
+			s.skip(k.resource, err)
+		}
+	}
+	if req.kinds[orphanConfigMaps] || req.kinds[orphanSecrets] {
+		s.out.Notes = append(s.out.Notes,
+			"ConfigMaps and Secrets count as used when a pod, a workload's pod template, a ServiceAccount or an Ingress in the same namespace refers to them. Other consumers, such as operators or applications reading them through the API, are not seen; check before deleting.",
+			"Objects with owner references, and ConfigMaps and Secrets in kube-* namespaces, are not reported.")
+	}
+
+	for _, k := range orphanKinds {
+		found := s.findings[k.resource]
+		if len(found) == 0 {
+			continue
+		}
+		s.out.Summary[k.kind] = len(found)
+		s.out.TotalFindings += len(found)
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].Namespace != found[j].Namespace {
+				return found[i].Namespace < found[j].Namespace
+			}
+			return found[i].Name < found[j].Name
+		})
+		for _, f := range found {
+			if len(s.out.Findings) == req.limit {
+				s.out.FindingsTruncated = true
+				break
+			}
+			s.out.Findings = append(s.out.Findings, f)
+			if req.deletePlan {
+				s.out.DeletePlan = append(s.out.DeletePlan, OrphanDeleteStep{
+					ResourceType: k.resource,
+					APIGroup:     k.apiGroup,
+					Namespace:    f.Namespace,
+					Name:         f.Name,
+				})
+			}
+		}
+	}
+	return s.out
+}
+
+func (s *orphanScan) skip(resource string, err error) {
+	s.out.Skipped = append(s.out.Skipped, fmt.Sprintf("%s: %v", resource, err))
+}
+
+func (s *orphanScan) add(resource, kind string, obj metav1.Object, reason string) {
+	s.findings[resource] = append(s.findings[resource], OrphanFinding{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Reason:    reason,
+		AgeDays:   int(s.now.Sub(obj.GetCreationTimestamp().Time).Hours() / 24),
+	})
+}
+
+// orphanList lists a resource in the requested namespace, or in all of
+// them.
+func orphanList[T any](ctx context.Context, s *orphanScan, resourceType, apiGroup string, opts k8s.ListOptions, fn func(*T)) error {
+	opts.AllNamespaces = s.req.namespace == ""
+	return listAll(ctx, s.client, s.req.kubeContext, s.req.namespace, resourceType, apiGroup, opts, fn)
+}
+
+// checkReplicaSets finds ReplicaSets scaled to zero that no Deployment owns.
+// Zero-replica ReplicaSets owned by a Deployment are its rollout history.
+func (s *orphanScan) checkReplicaSets(ctx context.Context) error {
+	deployments := map[types.UID]bool{}
+	err := orphanList(ctx, s, "deployments", "apps", k8s.ListOptions{}, func(d *appsv1.Deployment) {
+		deployments[d.UID] = true
+	})
+	if err != nil {
+		return err
+	}
+
+	return orphanList(ctx, s, "replicasets", "apps", k8s.ListOptions{}, func(rs *appsv1.ReplicaSet) {
+		if rs.Spec.Replicas == nil || *rs.Spec.Replicas != 0 || rs.Status.Replicas != 0 {
+			return
+		}
+		owner := metav1.GetControllerOf(rs)
+		switch {
+		case owner == nil:
+			s.add(orphanReplicaSets, "ReplicaSet", rs, "scaled to zero and not owned by a Deployment")
+		case owner.Kind == "Deployment" && !deployments[owner.UID]:
+			s.add(orphanReplicaSets, "ReplicaSet", rs, fmt.Sprintf("scaled to zero and its Deployment %s no longer exists", owner.Name))
+		}
+	})
+}
+
+// checkClaims finds PersistentVolumeClaims that have not been bound for
+// longer than the requested period and that no pod mounts. Pods keep
+// claims of WaitForFirstConsumer storage classes pending until they start.
+func (s *orphanScan) checkClaims(ctx context.Context) error {
+	mounted := map[string]bool{}
+	err := orphanList(ctx, s, "pods", "", k8s.ListOptions{}, func(pod *corev1.Pod) {
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil {
+				mounted[pod.Namespace+"/"+v.PersistentVolumeClaim.ClaimName] = true
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return orphanList(ctx, s, "persistentvolumeclaims", "", k8s.ListOptions{}, func(pvc *corev1.PersistentVolumeClaim) {
+		if pvc.Status.Phase == corev1.ClaimBound || mounted[pvc.Namespace+"/"+pvc.Name] {
+			return
+		}
+		age := s.now.Sub(pvc.CreationTimestamp.Time)
+		if age < s.req.unboundFor {
+			return
+		}
+		phase := pvc.Status.Phase
+		if phase == "" {
+			phase = corev1.ClaimPending
+		}
+		s.add(orphanClaims, "PersistentVolumeClaim", pvc, fmt.Sprintf("%s for %d days and not mounted by any pod", phase, int(age.Hours()/24)))
+	})
+}
+
+// checkServices finds Services with a selector but no endpoints at all.
+// Services without a selector have their endpoints managed by hand.
+func (s *orphanScan) checkServices(ctx context.Context) error {
+	endpoints := map[string]int{}
+	err := orphanList(ctx, s, "endpointslices", "discovery.k8s.io", k8s.ListOptions{}, func(slice *discoveryv1.EndpointSlice) {
+		if name := slice.Labels[discoveryv1.LabelServiceName]; name != "" {
+			endpoints[slice.Namespace+"/"+name] += len(slice.Endpoints)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return orphanList(ctx, s, "services", "", k8s.ListOptions{}, func(svc *corev1.Service) {
+		if svc.Spec.Type == corev1.ServiceTypeExternalName || len(svc.Spec.Selector) == 0 {
+			return
+		}
+		if endpoints[svc.Namespace+"/"+svc.Name] > 0 {
+			return
+		}
+		s.add(orphanServices, "Service", svc, fmt.Sprintf("no endpoints; selector %s matches no pods", labels.SelectorFromSet(svc.Spec.Selector)))
+	})
+}
+
+// checkConfigMaps finds ConfigMaps nothing in their namespace refers to.
+func (s *orphanScan) checkConfigMaps(ctx context.Context, refs *podReferences) error {
+	return orphanList(ctx, s, "configmaps", "", k8s.ListOptions{}, func(cm *corev1.ConfigMap) {
+		if ignoreUnreferenced(cm) || orphanIgnoredConfigMaps[cm.Name] || refs.configMaps[cm.Namespace+"/"+cm.Name] {
+			return
+		}
+		s.add(orphanConfigMaps, "ConfigMap", cm, "not referenced by any pod, pod template, ServiceAccount or Ingress in the namespace")
+	})
+}
+
+// checkSecrets finds Secrets nothing in their namespace refers to.
+func (s *orphanScan) checkSecrets(ctx context.Context, refs *podReferences) error {
+	return orphanList(ctx, s, "secrets", "", k8s.ListOptions{}, func(secret *corev1.Secret) {
+		if ignoreUnreferenced(secret) || orphanIgnoredSecretTypes[secret.Type] || refs.secrets[secret.Namespace+"/"+secret.Name] {
+			return
+		}
+		if _, ok := secret.Annotations[certManagerCertificateAnnotation]; ok {
+			return
+		}
+		s.add(orphanSecrets, "Secret", secret, "not referenced by any pod, pod template, ServiceAccount or Ingress in the namespace")
+	})
+}
+
+// ignoreUnreferenced reports whether a ConfigMap or Secret is left out of
+// the unreferenced checks: owned objects are garbage collected with their
+// owner, and kube-* namespaces belong to the cluster's own components.
+func ignoreUnreferenced(obj metav1.Object) bool {
+	return len(obj.GetOwnerReferences()) > 0 || strings.HasPrefix(obj.GetNamespace(), "kube-")
+}
+
+// podReferences holds the ConfigMaps and Secrets in use, by namespace/name.
+type podReferences struct {
+	configMaps map[string]bool
+	secrets    map[string]bool
+}
+
+// collectReferences gathers the ConfigMaps and Secrets referred to by pods,
+// workload pod templates, ServiceAccounts and Ingress TLS settings. Pod
+// templates count so that workloads scaled to zero keep their config.
+func (s *orphanScan) collectReferences(ctx context.Context) (*podReferences, error) {
+	refs := &podReferences{configMaps: map[string]bool{}, secrets: map[string]bool{}}
+
+	sources := []func() error{
+		func() error {
+			return orphanList(ctx, s, "pods", "", k8s.ListOptions{}, func(pod *corev1.Pod) {
+				refs.addPodSpec(pod.Namespace, &pod.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "deployments", "apps", k8s.ListOptions{}, func(d *appsv1.Deployment) {
+				refs.addPodSpec(d.Namespace, &d.Spec.Template.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "statefulsets", "apps", k8s.ListOptions{}, func(sts *appsv1.StatefulSet) {
+				refs.addPodSpec(sts.Namespace, &sts.Spec.Template.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "daemonsets", "apps", k8s.ListOptions{}, func(ds *appsv1.DaemonSet) {
+				refs.addPodSpec(ds.Namespace, &ds.Spec.Template.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "replicasets", "apps", k8s.ListOptions{}, func(rs *appsv1.ReplicaSet) {
+				refs.addPodSpec(rs.Namespace, &rs.Spec.Template.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "jobs", "batch", k8s.ListOptions{}, func(job *batchv1.Job) {
+				refs.addPodSpec(job.Namespace, &job.Spec.Template.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "cronjobs", "batch", k8s.ListOptions{}, func(cj *batchv1.CronJob) {
+				refs.addPodSpec(cj.Namespace, &cj.Spec.JobTemplate.Spec.Template.Spec)
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "serviceaccounts", "", k8s.ListOptions{}, func(sa *corev1.ServiceAccount) {
+				for _, ref := range sa.Secrets {
+					refs.secrets[sa.Namespace+"/"+ref.Name] = true
+				}
+				for _, ref := range sa.ImagePullSecrets {
+					refs.secrets[sa.Namespace+"/"+ref.Name] = true
+				}
+			})
+		},
+		func() error {
+			return orphanList(ctx, s, "ingresses", "networking.k8s.io", k8s.ListOptions{}, func(ing *networkingv1.Ingress) {
+				for _, tls := range ing.Spec.TLS {
+					if tls.SecretName != "" {
+						refs.secrets[ing.Namespace+"/"+tls.SecretName] = true
+					}
+				}
+			})
+		},
+	}
+	for _, source := range sources {
+		if err := source(); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// addPodSpec records the ConfigMaps and Secrets a pod spec refers to.
+func (r *podReferences) addPodSpec(namespace string, spec *corev1.PodSpec) {
+	configMap := func(name string) { r.configMaps[namespace+"/"+name] = true }
+	secret := func(name string) { r.secrets[namespace+"/"+name] = true }
+
+	for _, ref := range spec.ImagePullSecrets {
+		secret(ref.Name)
+	}
+	for _, v := range spec.Volumes {
+		switch {
+		case v.ConfigMap != nil:
+			configMap(v.ConfigMap.Name)
+		case v.Secret != nil:
+			secret(v.Secret.SecretName)
+		case v.Projected != nil:
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					configMap(src.ConfigMap.Name)
+				}
+				if src.Secret != nil {
+					secret(src.Secret.Name)
+				}
+			}
+		case v.CSI != nil && v.CSI.NodePublishSecretRef != nil:
+			secret(v.CSI.NodePublishSecretRef.Name)
+		}
+	}
+
+	containers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers)+len(spec.EphemeralContainers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, ec := range spec.EphemeralContainers {
+		containers = append(containers, corev1.Container(ec.EphemeralContainerCommon))
+	}
+	for _, c := range containers {
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				configMap(ref.Name)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				secret(ref.Name)
+			}
+		}
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				configMap(from.ConfigMapRef.Name)
+			}
+			if from.SecretRef != nil {
+				secret(from.SecretRef.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+var orphansNow = time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func orphanMeta(name string, daysOld int) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:         "shop",
+		Name:              name,
+		CreationTimestamp: metav1.NewTime(orphansNow.Add(-time.Duration(daysOld) * 24 * time.Hour)),
+	}
+}
+
+// orphanObjects returns a namespace with one unused object of every kind
+// find_orphans reports, next to objects that are in use.
+func orphanObjects() map[string][]runtime.Object {
+	zero := int32(0)
+	controller := true
+
+	deployment := &appsv1.Deployment{ObjectMeta: orphanMeta("web", 30)}
+	deployment.UID = "web-uid"
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:    "web",
+		EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}},
+	}}
+
+	historyRS := &appsv1.ReplicaSet{ObjectMeta: orphanMeta("web-old", 20), Spec: appsv1.ReplicaSetSpec{Replicas: &zero}}
+	historyRS.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "web", UID: "web-uid", Controller: &controller}}
+	strayRS := &appsv1.ReplicaSet{ObjectMeta: orphanMeta("stray", 40), Spec: appsv1.ReplicaSetSpec{Replicas: &zero}}
+	goneRS := &appsv1.ReplicaSet{ObjectMeta: orphanMeta("api-old", 50), Spec: appsv1.ReplicaSetSpec{Replicas: &zero}}
+	goneRS.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "api", UID: "api-uid", Controller: &controller}}
+
+	pod := &corev1.Pod{ObjectMeta: orphanMeta("web-abc", 1), Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "web", Env: []corev1.EnvVar{{
+			Name:      "PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db-password"}}},
+		}}}},
+		Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "waiting"},
+		}}},
+	}}
+
+	pending := func(name string, daysOld int) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: orphanMeta(name, daysOld), Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}}
+	}
+	bound := &corev1.PersistentVolumeClaim{ObjectMeta: orphanMeta("bound", 90), Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}}
+
+	ownedCM := &corev1.ConfigMap{ObjectMeta: orphanMeta("owned", 10)}
+	ownedCM.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "web"}}
+	systemCM := &corev1.ConfigMap{ObjectMeta: orphanMeta("coredns", 10)}
+	systemCM.Namespace = "kube-system"
+
+	tlsSecret := &corev1.Secret{ObjectMeta: orphanMeta("web-tls", 10), Type: corev1.SecretTypeTLS}
+	helmSecret := &corev1.Secret{ObjectMeta: orphanMeta("sh.helm.release.v1.web.v1", 10), Type: "helm.sh/release.v1"}
+
+	ingress := &networkingv1.Ingress{ObjectMeta: orphanMeta("web", 10), Spec: networkingv1.IngressSpec{
+		TLS: []networkingv1.IngressTLS{{SecretName: "web-tls"}},
+	}}
+
+	selected := func(name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: orphanMeta(name, 10), Spec: corev1.ServiceSpec{Selector: map[string]string{"app": name}}}
+	}
+	manual := &corev1.Service{ObjectMeta: orphanMeta("external-db", 10)}
+	webSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-xyz", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+		Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+	}
+
+	return map[string][]runtime.Object{
+		"deployments":            {deployment},
+		"replicasets":            {historyRS, strayRS, goneRS},
+		"pods":                   {pod},
+		"persistentvolumeclaims": {pending("old-claim", 30), pending("new-claim", 2), pending("waiting", 30), bound},
+		"configmaps": {
+			&corev1.ConfigMap{ObjectMeta: orphanMeta("web-config", 10)},
+			&corev1.ConfigMap{ObjectMeta: orphanMeta("leftover", 10)},
+			&corev1.ConfigMap{ObjectMeta: orphanMeta("kube-root-ca.crt", 10)},
+			ownedCM,
+			systemCM,
+		},
+		"secrets": {
+			&corev1.Secret{ObjectMeta: orphanMeta("db-password", 10)},
+			&corev1.Secret{ObjectMeta: orphanMeta("old-password", 10)},
+			tlsSecret,
+			helmSecret,
+		},
+		"ingresses":      {ingress},
+		"services":       {selected("web"), selected("legacy"), manual},
+		"endpointslices": {webSlice},
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	req, errMsg := parseOrphansRequest(map[string]interface{}{"deletePlan": true})
+	require.Empty(t, errMsg)
+	out := findOrphans(context.Background(), mcptest.NewFakeClient(orphanObjects()), req, orphansNow)
+
+	type finding struct{ kind, name string }
+	var got []finding
+	for _, f := range out.Findings {
+		got = append(got, finding{f.Kind, f.Name})
+	}
+	assert.Equal(t, []finding{
+		{"ReplicaSet", "api-old"},
+		{"ReplicaSet", "stray"},
+		{"PersistentVolumeClaim", "old-claim"},
+		{"ConfigMap", "leftover"},
+		{"Secret", "old-password"},
+		{"Service", "legacy"},
+	}, got)
+	assert.Equal(t, map[string]int{"ReplicaSet": 2, "PersistentVolumeClaim": 1, "ConfigMap": 1, "Secret": 1, "Service": 1}, out.Summary)
+	assert.Equal(t, 6, out.TotalFindings)
+	assert.Empty(t, out.Skipped)
+
+	assert.Contains(t, out.Findings[0].Reason, "Deployment api no longer exists")
+	assert.Equal(t, "Pending for 30 days and not mounted by any pod", out.Findings[2].Reason)
+	assert.Equal(t, 30, out.Findings[2].AgeDays)
+	assert.Contains(t, out.Findings[5].Reason, "app=legacy")
+
+	require.Len(t, out.DeletePlan, 6)
+	assert.Equal(t, OrphanDeleteStep{ResourceType: "replicasets", APIGroup: "apps", Namespace: "shop", Name: "api-old"}, out.DeletePlan[0])
+	assert.Equal(t, OrphanDeleteStep{ResourceType: "services", Namespace: "shop", Name: "legacy"}, out.DeletePlan[5])
+}
+
+func TestFindOrphans_LimitAndKinds(t *testing.T) {
+	req, errMsg := parseOrphansRequest(map[string]interface{}{"kinds": "replicasets, persistentvolumeclaims", "limit": float64(1), "unboundDays": float64(1)})
+	require.Empty(t, errMsg)
+	out := findOrphans(context.Background(), mcptest.NewFakeClient(orphanObjects()), req, orphansNow)
+
+	assert.Equal(t, map[string]int{"ReplicaSet": 2, "PersistentVolumeClaim": 2}, out.Summary)
+	assert.Equal(t, 4, out.TotalFindings)
+	assert.Len(t, out.Findings, 1)
+	assert.True(t, out.FindingsTruncated)
+	assert.Empty(t, out.DeletePlan)
+	assert.Empty(t, out.Notes, "the reference notes only apply to ConfigMaps and Secrets")
+}
+
+func TestFindOrphans_SkipsUnreadableChecks(t *testing.T) {
+	client := mcptest.NewFakeClient(orphanObjects())
+	client.Errs = map[string]error{"ingresses": mcptest.Forbidden("ingresses"), "services": mcptest.Forbidden("services")}
+	req, _ := parseOrphansRequest(map[string]interface{}{})
+	out := findOrphans(context.Background(), client, req, orphansNow)
+
+	require.Len(t, out.Skipped, 3)
+	assert.Contains(t, out.Skipped[0], "configmaps: cannot tell which objects are in use")
+	assert.Contains(t, out.Skipped[1], "secrets: cannot tell which objects are in use")
+	assert.Contains(t, out.Skipped[2], "services:")
+	assert.Equal(t, map[string]int{"ReplicaSet": 2, "PersistentVolumeClaim": 1}, out.Summary)
+}
+
+func TestHandleFindOrphans_Validation(t *testing.T) {
+	for wantErr, args := range map[string]map[string]interface{}{
+		"unknown kind":                {"kinds": "pods"},
+		"limit must be between":       {"limit": float64(0)},
+		"unboundDays must be between": {"unboundDays": float64(-1)},
+	} {
+		result := callTool(t, mcptest.NewFakeClient(orphanObjects()), "find_orphans", args)
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), wantErr)
+	}
+
+	out := mcptest.DecodeResult[OrphansOutput](t, callTool(t, mcptest.NewFakeClient(orphanObjects()), "find_orphans", map[string]interface{}{"namespace": "shop"}))
+	assert.Equal(t, "shop", out.Namespace)
+}
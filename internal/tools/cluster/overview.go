@@ -0,0 +1,356 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// Limits for the cluster_overview tool.
+const (
+	// DefaultOverviewSinceMinutes is the default window for warning events.
+	DefaultOverviewSinceMinutes = 60
+
+	// MaxOverviewSinceMinutes caps sinceMinutes at a day.
+	MaxOverviewSinceMinutes = 24 * 60
+
+	// DefaultOverviewTop is the default number of top consumers returned.
+	DefaultOverviewTop = 5
+
+	// MaxOverviewTop is the absolute maximum allowed for top.
+	MaxOverviewTop = 50
+
+	// maxOverviewNames caps the names listed for each problem, such as the
+	// failing pods; the counts cover all of them.
+	maxOverviewNames = 10
+)
+
+// ClusterOverviewOutput is the response of the cluster_overview tool.
+type ClusterOverviewOutput struct {
+	// Version is the API server version and Status the outcome of its
+	// health checks.
+	Version string `json:"version,omitempty"`
+	Status  string `json:"status,omitempty"`
+
+	Nodes         OverviewNodes   `json:"nodes"`
+	Namespaces    int             `json:"namespaces"`
+	Pods          OverviewPods    `json:"pods"`
+	PendingPVCs   OverviewProblem `json:"pendingPVCs"`
+	WarningEvents OverviewEvents  `json:"warningEvents"`
+
+	// RequestedPercent is the share of allocatable CPU and memory the
+	// active pods request.
+	RequestedPercent ResourcePercent `json:"requestedPercent"`
+
+	// TopNamespaces and TopPods are the largest consumers by CPU requests.
+	TopNamespaces []NamespaceCapacity `json:"topNamespaces,omitempty"`
+	TopPods       []OverviewPod       `json:"topPods,omitempty"`
+
+	// Unavailable lists the data that could not be read, e.g. for lack of
+	// list permission.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// OverviewNodes counts the nodes by readiness.
+type OverviewNodes struct {
+	Total         int `json:"total"`
+	Ready         int `json:"ready"`
+	Unschedulable int `json:"unschedulable,omitempty"`
+
+	// NotReady names up to maxOverviewNames nodes that are not ready.
+	NotReady []string `json:"notReady,omitempty"`
+}
+
+// OverviewPods counts the pods by phase. Failing pods are crash looping,
+// cannot pull their image, cannot start, or failed.
+type OverviewPods struct {
+	Total   int `json:"total"`
+	Running int `json:"running"`
+	Pending int `json:"pending"`
+	Failing int `json:"failing"`
+
+	// FailingPods lists up to maxOverviewNames failing pods.
+	FailingPods []OverviewPod `json:"failingPods,omitempty"`
+}
+
+// OverviewPod is a pod in the overview.
+type OverviewPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status,omitempty"`
+
+	// Requested is set for the top consumers.
+	Requested *ResourceAmounts `json:"requested,omitempty"`
+}
+
+// OverviewProblem counts objects in a bad state and names up to
+// maxOverviewNames of them as namespace/name.
+type OverviewProblem struct {
+	Count int      `json:"count"`
+	Names []string `json:"names,omitempty"`
+}
+
+// OverviewEvents counts the recent warning events and their most common
+// reasons.
+type OverviewEvents struct {
+	SinceMinutes int           `json:"sinceMinutes"`
+	Count        int           `json:"count"`
+	TopReasons   []ReasonCount `json:"topReasons,omitempty"`
+}
+
+// ReasonCount is the number of events with a reason.
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// clusterOverviewRequest holds the validated cluster_overview arguments.
+type clusterOverviewRequest struct {
+	cluster      string
+	kubeContext  string
+	sinceMinutes int
+	top          int
+}
+
+// handleClusterOverview returns a one-shot snapshot of a cluster's health.
+func handleClusterOverview(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req := clusterOverviewRequest{
+		cluster:      tools.ExtractClusterParam(args),
+		sinceMinutes: DefaultOverviewSinceMinutes,
+		top:          DefaultOverviewTop,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	if v, ok := args["sinceMinutes"].(float64); ok {
+		if v < 1 || v > MaxOverviewSinceMinutes {
+			return toolerrors.InvalidArgument(fmt.Sprintf("sinceMinutes must be between 1 and %d", MaxOverviewSinceMinutes)).Result(), nil
+		}
+		req.sinceMinutes = int(v)
+	}
+	if v, ok := args["top"].(float64); ok {
+		if v < 1 || v > MaxOverviewTop {
+			return toolerrors.InvalidArgument(fmt.Sprintf("top must be between 1 and %d", MaxOverviewTop)).Result(), nil
+		}
+		req.top = int(v)
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	return jsonResult(collectClusterOverview(ctx, client.K8s(), req, time.Now()))
+}
+
+// overviewScan collects the data of one cluster_overview call. Each part is
+// read independently, so a missing permission only leaves out that part.
+type overviewScan struct {
+	client k8s.Client
+	req    clusterOverviewRequest
+	out    *ClusterOverviewOutput
+
+	// allocatable is summed over the nodes and requested over the active
+	// pods.
+	allocatable, requested amounts
+}
+
+func (s *overviewScan) unavailable(format string, args ...interface{}) {
+	s.out.Unavailable = append(s.out.Unavailable, fmt.Sprintf(format, args...))
+}
+
+// collectClusterOverview reads the version, nodes, namespaces, pods,
+// PersistentVolumeClaims and warning events of a cluster.
+func collectClusterOverview(ctx context.Context, client k8s.Client, req clusterOverviewRequest, now time.Time) *ClusterOverviewOutput {
+	s := &overviewScan{
+		client: client,
+		req:    req,
+		out:    &ClusterOverviewOutput{WarningEvents: OverviewEvents{SinceMinutes: req.sinceMinutes}},
+	}
+
+	health, err := client.GetClusterHealth(ctx, req.kubeContext)
+	switch {
+	case err != nil:
+		s.unavailable("version: %v", err)
+	case health != nil:
+		s.out.Version = health.Version
+		s.out.Status = health.Status
+	}
+
+	s.readNodes(ctx)
+	s.readNamespaces(ctx)
+	s.readPods(ctx)
+	s.readPVCs(ctx)
+	s.readEvents(ctx, now)
+	s.out.RequestedPercent = s.requested.percentOf(s.allocatable)
+	return s.out
+}
+
+func (s *overviewScan) readNodes(ctx context.Context) {
+	nodes := &s.out.Nodes
+	err := listAll(ctx, s.client, s.req.kubeContext, "", "nodes", "", k8s.ListOptions{}, func(node *corev1.Node) {
+		nodes.Total++
+		s.allocatable = s.allocatable.plus(amountsFromList(node.Status.Allocatable))
+		if node.Spec.Unschedulable {
+			nodes.Unschedulable++
+		}
+		switch {
+		case nodeReady(node):
+			nodes.Ready++
+		case len(nodes.NotReady) < maxOverviewNames:
+			nodes.NotReady = append(nodes.NotReady, node.Name)
+		}
+	})
+	if err != nil {
+		s.unavailable("nodes: %v", err)
+	}
+}
+
+func (s *overviewScan) readNamespaces(ctx context.Context) {
+	err := listAll(ctx, s.client, s.req.kubeContext, "", "namespaces", "", k8s.ListOptions{}, func(*corev1.Namespace) {
+		s.out.Namespaces++
+	})
+	if err != nil {
+		s.unavailable("namespaces: %v", err)
+	}
+}
+
+// readPods counts the pods by phase, names the failing ones and sums the
+// requests of the active ones per namespace and pod.
+func (s *overviewScan) readPods(ctx context.Context) {
+	pods := &s.out.Pods
+	namespaces := map[string]*nodeUsage{}
+	var consumers []OverviewPod
+	err := listAll(ctx, s.client, s.req.kubeContext, "", "pods", "", k8s.ListOptions{AllNamespaces: true}, func(pod *corev1.Pod) {
+		pods.Total++
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			pods.Running++
+		case corev1.PodPending:
+			pods.Pending++
+		}
+		if status, failing := podFailing(pod); failing {
+			pods.Failing++
+			if len(pods.FailingPods) < maxOverviewNames {
+				pods.FailingPods = append(pods.FailingPods, OverviewPod{Namespace: pod.Namespace, Name: pod.Name, Status: status})
+			}
+		}
+
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return
+		}
+		requested := podAmounts(&pod.Spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests })
+		limits := podAmounts(&pod.Spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits })
+		s.requested = s.requested.plus(requested)
+		ns, ok := namespaces[pod.Namespace]
+		if !ok {
+			ns = &nodeUsage{}
+			namespaces[pod.Namespace] = ns
+		}
+		ns.add(requested, limits)
+		amounts := requested.output()
+		consumers = append(consumers, OverviewPod{Namespace: pod.Namespace, Name: pod.Name, Requested: &amounts})
+	})
+	if err != nil {
+		s.unavailable("pods: %v", err)
+		return
+	}
+
+	for name, usage := range namespaces {
+		s.out.TopNamespaces = append(s.out.TopNamespaces, NamespaceCapacity{
+			Name:      name,
+			Pods:      usage.pods,
+			Requested: usage.requested.output(),
+			Limits:    usage.limits.output(),
+		})
+	}
+	sort.Slice(s.out.TopNamespaces, func(i, j int) bool {
+		a, b := s.out.TopNamespaces[i], s.out.TopNamespaces[j]
+		if a.Requested.CPUMillicores != b.Requested.CPUMillicores {
+			return a.Requested.CPUMillicores > b.Requested.CPUMillicores
+		}
+		return a.Name < b.Name
+	})
+	if len(s.out.TopNamespaces) > s.req.top {
+		s.out.TopNamespaces = s.out.TopNamespaces[:s.req.top]
+	}
+
+	sort.SliceStable(consumers, func(i, j int) bool {
+		a, b := consumers[i].Requested, consumers[j].Requested
+		if a.CPUMillicores != b.CPUMillicores {
+			return a.CPUMillicores > b.CPUMillicores
+		}
+		return a.MemoryMiB > b.MemoryMiB
+	})
+	if len(consumers) > s.req.top {
+		consumers = consumers[:s.req.top]
+	}
+	s.out.TopPods = consumers
+}
+
+// podFailing reports whether a pod is failing, and its kubectl status.
+func podFailing(pod *corev1.Pod) (string, bool) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return "", false
+	}
+	return output.PodFailing(content)
+}
+
+func (s *overviewScan) readPVCs(ctx context.Context) {
+	pvcs := &s.out.PendingPVCs
+	err := listAll(ctx, s.client, s.req.kubeContext, "", "persistentvolumeclaims", "", k8s.ListOptions{AllNamespaces: true}, func(pvc *corev1.PersistentVolumeClaim) {
+		if pvc.Status.Phase != corev1.ClaimPending {
+			return
+		}
+		pvcs.Count++
+		if len(pvcs.Names) < maxOverviewNames {
+			pvcs.Names = append(pvcs.Names, pvc.Namespace+"/"+pvc.Name)
+		}
+	})
+	if err != nil {
+		s.unavailable("persistentvolumeclaims: %v", err)
+	}
+}
+
+// readEvents counts the warning events last seen within the window and
+// ranks their reasons.
+func (s *overviewScan) readEvents(ctx context.Context, now time.Time) {
+	events := &s.out.WarningEvents
+	reasons := map[string]int{}
+	since := now.Add(-time.Duration(s.req.sinceMinutes) * time.Minute)
+	err := listAll(ctx, s.client, s.req.kubeContext, "", "events", "", k8s.ListOptions{AllNamespaces: true, FieldSelector: "type=" + corev1.EventTypeWarning}, func(ev *corev1.Event) {
+		if eventLastSeen(ev).Before(since) {
+			return
+		}
+		events.Count++
+		reasons[ev.Reason]++
+	})
+	if err != nil {
+		s.unavailable("events: %v", err)
+	}
+
+	for reason, count := range reasons {
+		events.TopReasons = append(events.TopReasons, ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(events.TopReasons, func(i, j int) bool {
+		a, b := events.TopReasons[i], events.TopReasons[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Reason < b.Reason
+	})
+	if len(events.TopReasons) > s.req.top {
+		events.TopReasons = events.TopReasons[:s.req.top]
+	}
+}
@@ -0,0 +1,137 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func overviewNode(name string, ready bool, cpu string) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse("8Gi")},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func overviewPod(namespace, name string, phase corev1.PodPhase, cpu string, waiting string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "app",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	if waiting != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waiting}}}}
+	}
+	return pod
+}
+
+func overviewEvent(name, reason string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:    metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Type:          corev1.EventTypeWarning,
+		Reason:        reason,
+		LastTimestamp: metav1.NewTime(lastSeen),
+	}
+}
+
+func TestCollectClusterOverview(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cordoned := overviewNode("node-b", true, "4")
+	cordoned.Spec.Unschedulable = true
+	failed := overviewPod("batch", "report", corev1.PodFailed, "100m", "")
+	failed.Status.Reason = "Evicted"
+
+	client := mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+		"nodes": {overviewNode("node-a", true, "4"), cordoned, overviewNode("node-c", false, "4")},
+		"namespaces": {
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "batch"}},
+		},
+		"pods": {
+			overviewPod("shop", "web-1", corev1.PodRunning, "2", ""),
+			overviewPod("shop", "web-2", corev1.PodRunning, "1", "CrashLoopBackOff"),
+			overviewPod("shop", "api", corev1.PodPending, "500m", "ContainerCreating"),
+			overviewPod("batch", "job", corev1.PodPending, "3", ""),
+			failed,
+		},
+		"events": {
+			overviewEvent("a", "BackOff", now.Add(-time.Minute)),
+			overviewEvent("b", "BackOff", now.Add(-10*time.Minute)),
+			overviewEvent("c", "FailedScheduling", now.Add(-30*time.Minute)),
+			overviewEvent("d", "FailedMount", now.Add(-2*time.Hour)),
+		},
+	})
+	client.Health = &k8s.ClusterHealth{Status: "Healthy", Version: "v1.30.2"}
+
+	out := collectClusterOverview(context.Background(), client, clusterOverviewRequest{sinceMinutes: 60, top: 2}, now)
+
+	assert.Equal(t, "v1.30.2", out.Version)
+	assert.Equal(t, "Healthy", out.Status)
+	assert.Equal(t, OverviewNodes{Total: 3, Ready: 2, Unschedulable: 1, NotReady: []string{"node-c"}}, out.Nodes)
+	assert.Equal(t, 2, out.Namespaces)
+
+	assert.Equal(t, 5, out.Pods.Total)
+	assert.Equal(t, 2, out.Pods.Running)
+	assert.Equal(t, 2, out.Pods.Pending)
+	assert.Equal(t, 2, out.Pods.Failing)
+	assert.Equal(t, []OverviewPod{
+		{Namespace: "shop", Name: "web-2", Status: "CrashLoopBackOff"},
+		{Namespace: "batch", Name: "report", Status: "Evicted"},
+	}, out.Pods.FailingPods)
+
+	// Completed pods hold no requests: 6.5 of 12 CPUs.
+	assert.InDelta(t, 54.2, out.RequestedPercent.CPU, 0.1)
+	require.Len(t, out.TopNamespaces, 2)
+	assert.Equal(t, "shop", out.TopNamespaces[0].Name)
+	assert.Equal(t, int64(3500), out.TopNamespaces[0].Requested.CPUMillicores)
+	require.Len(t, out.TopPods, 2)
+	assert.Equal(t, "job", out.TopPods[0].Name)
+	assert.Equal(t, "web-1", out.TopPods[1].Name)
+
+	assert.Equal(t, OverviewEvents{SinceMinutes: 60, Count: 3, TopReasons: []ReasonCount{{"BackOff", 2}, {"FailedScheduling", 1}}}, out.WarningEvents)
+
+	// PersistentVolumeClaims cannot be listed.
+	assert.Equal(t, 0, out.PendingPVCs.Count)
+	require.Len(t, out.Unavailable, 1)
+	assert.Contains(t, out.Unavailable[0], "persistentvolumeclaims")
+}
+
+func TestPodFailing(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		pod     *corev1.Pod
+		status  string
+		failing bool
+	}{
+		{"running", overviewPod("ns", "p", corev1.PodRunning, "1", ""), "Running", false},
+		{"creating", overviewPod("ns", "p", corev1.PodPending, "1", "ContainerCreating"), "ContainerCreating", false},
+		{"image pull", overviewPod("ns", "p", corev1.PodPending, "1", "ImagePullBackOff"), "ImagePullBackOff", true},
+		{"succeeded", overviewPod("ns", "p", corev1.PodSucceeded, "1", ""), "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status, failing := podFailing(tc.pod)
+			assert.Equal(t, tc.failing, failing)
+			assert.Equal(t, tc.status, status)
+		})
+	}
+}
@@ -0,0 +1,576 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Limits for the pod_security tool.
+const (
+	// DefaultPodSecurityLimit is the default cap on the number of
+	// namespaces and of violating workloads reported per cluster.
+	DefaultPodSecurityLimit = 100
+
+	// MaxPodSecurityLimit is the absolute maximum allowed for limit.
+	MaxPodSecurityLimit = 1000
+
+	// maxPodSecurityDetails caps the violation details listed per workload.
+	maxPodSecurityDetails = 10
+)
+
+// Pod Security Standards profiles, least restrictive first.
+const (
+	profilePrivileged = "privileged"
+	profileBaseline   = "baseline"
+	profileRestricted = "restricted"
+)
+
+// profileRank orders the profiles, least restrictive first.
+var profileRank = map[string]int{profilePrivileged: 0, profileBaseline: 1, profileRestricted: 2}
+
+// podSecurityLabelPrefix prefixes the Pod Security admission namespace
+// labels.
+const podSecurityLabelPrefix = "pod-security.kubernetes.io/"
+
+// baselineCapabilities are the capabilities the baseline profile allows
+// containers to add.
+var baselineCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// safeSysctls are the sysctls the baseline profile allows.
+var safeSysctls = []string{
+	"kernel.shm_rmid_forced", "net.ipv4.ip_local_port_range", "net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.tcp_syncookies", "net.ipv4.ping_group_range", "net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.tcp_keepalive_time", "net.ipv4.tcp_fin_timeout", "net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+}
+
+// NamespacePodSecurity is the Pod Security admission posture of one
+// namespace.
+type NamespacePodSecurity struct {
+	Namespace string `json:"namespace"`
+
+	// Enforce, Audit and Warn are the levels of the Pod Security admission
+	// labels, with the pinned version when one is set, e.g.
+	// restricted:v1.29. Empty means the label is not set.
+	Enforce string `json:"enforce,omitempty"`
+	Audit   string `json:"audit,omitempty"`
+	Warn    string `json:"warn,omitempty"`
+
+	Pods                    int `json:"pods"`
+	PodsViolatingBaseline   int `json:"podsViolatingBaseline"`
+	PodsViolatingRestricted int `json:"podsViolatingRestricted"`
+
+	// PodsViolatingEnforce counts running pods that the enforce level
+	// would reject when they are recreated.
+	PodsViolatingEnforce int `json:"podsViolatingEnforce,omitempty"`
+}
+
+// PodSecurityViolation is a workload whose pods fail a profile.
+type PodSecurityViolation struct {
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+	Pods      int    `json:"pods"`
+
+	// Level is the most restrictive profile the workload meets:
+	// privileged when it fails baseline, otherwise baseline.
+	Level string `json:"level"`
+
+	// Checks are the failed Pod Security Standards checks, sorted.
+	Checks []string `json:"checks"`
+
+	// Details describe the first violations, e.g. "container app:
+	// privileged".
+	Details []string `json:"details"`
+
+	// RejectedByEnforce is set when the namespace's enforce level would
+	// reject the workload's pods when they are recreated.
+	RejectedByEnforce bool `json:"rejectedByEnforce,omitempty"`
+}
+
+// PodSecurityOutput is the response of the pod_security tool for one
+// cluster.
+type PodSecurityOutput struct {
+	// Cluster is set for fleet queries.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Error is set when a cluster of a fleet query could not be scanned.
+	Error string `json:"error,omitempty"`
+
+	PodsScanned int `json:"podsScanned"`
+
+	// UnlabeledNamespaces counts namespaces without an enforce label,
+	// which the cluster's admission defaults apply to.
+	UnlabeledNamespaces int `json:"unlabeledNamespaces"`
+
+	// Namespaces are sorted by name.
+	Namespaces          []NamespacePodSecurity `json:"namespaces"`
+	TotalNamespaces     int                    `json:"totalNamespaces"`
+	NamespacesTruncated bool                   `json:"namespacesTruncated,omitempty"`
+
+	// Violations are sorted by level, least restrictive first, then by
+	// namespace and workload.
+	Violations          []PodSecurityViolation `json:"violations"`
+	TotalViolations     int                    `json:"totalViolations"`
+	ViolationsTruncated bool                   `json:"violationsTruncated,omitempty"`
+}
+
+// FleetPodSecurityOutput is the response of the pod_security tool across
+// clusters.
+type FleetPodSecurityOutput struct {
+	Clusters          []PodSecurityOutput `json:"clusters"`
+	TotalClusters     int                 `json:"totalClusters"`
+	FailedClusters    int                 `json:"failedClusters"`
+	ClustersTruncated bool                `json:"clustersTruncated,omitempty"`
+}
+
+// podSecurityRequest holds the validated pod_security tool arguments.
+type podSecurityRequest struct {
+	cluster     string
+	kubeContext string
+	clusters    []string
+	namespace   string
+	// profile is the profile workloads are evaluated against.
+	profile string
+	limit   int
+}
+
+// podSecurityIssue is one failed check of a pod.
+type podSecurityIssue struct {
+	check string
+	// profile is the least restrictive profile the check belongs to.
+	profile string
+	detail  string
+}
+
+// handlePodSecurity handles the pod_security tool.
+func handlePodSecurity(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parsePodSecurityRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	var result interface{}
+	if req.clusters != nil {
+		fleet, toolErr := fleetPodSecurity(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = fleet
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out, err := reportPodSecurity(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to evaluate pod security", err, client.User()).Result(), nil
+		}
+		result = out
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal pod security report: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parsePodSecurityRequest validates the tool arguments.
+func parsePodSecurityRequest(args map[string]interface{}) (podSecurityRequest, string) {
+	req := podSecurityRequest{
+		cluster: tools.ExtractClusterParam(args),
+		profile: profileRestricted,
+		limit:   DefaultPodSecurityLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if profile, _ := args["profile"].(string); profile != "" {
+		if profile != profileBaseline && profile != profileRestricted {
+			return req, "profile must be baseline or restricted"
+		}
+		req.profile = profile
+	}
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxPodSecurityLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxPodSecurityLimit)
+		}
+		req.limit = int(v)
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// fleetPodSecurity evaluates every requested cluster in parallel. A cluster
+// that fails is reported in the output instead of failing the whole call.
+func fleetPodSecurity(ctx context.Context, sc *server.ServerContext, req podSecurityRequest) (*FleetPodSecurityOutput, *toolerrors.Error) {
+	names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	out := &FleetPodSecurityOutput{
+		TotalClusters:     total,
+		ClustersTruncated: len(names) < total,
+		Clusters:          make([]PodSecurityOutput, len(names)),
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(capacityFleetConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			result := PodSecurityOutput{Cluster: name}
+			client, toolErr := tools.GetClusterClient(gctx, sc, name)
+			if toolErr != nil {
+				result.Error = toolErr.Message
+			} else {
+				scanned, err := reportPodSecurity(gctx, client.K8s(), req)
+				if err != nil {
+					result.Error = tools.FormatK8sError("Failed to evaluate pod security", err, client.User())
+				} else {
+					result = *scanned
+					result.Cluster = name
+				}
+			}
+
+			mu.Lock()
+			out.Clusters[i] = result
+			if result.Error != "" {
+				out.FailedClusters++
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return out, nil
+}
+
+// reportPodSecurity reads the Pod Security admission labels of the
+// namespaces of one cluster and evaluates their active pods against the
+// baseline and restricted profiles.
+func reportPodSecurity(ctx context.Context, client k8s.Client, req podSecurityRequest) (*PodSecurityOutput, error) {
+	out := &PodSecurityOutput{
+		Namespaces: []NamespacePodSecurity{},
+		Violations: []PodSecurityViolation{},
+	}
+
+	namespaces := map[string]*NamespacePodSecurity{}
+	addNamespace := func(ns *corev1.Namespace) {
+		namespaces[ns.Name] = &NamespacePodSecurity{
+			Namespace: ns.Name,
+			Enforce:   podSecurityLevel(ns.Labels, "enforce"),
+			Audit:     podSecurityLevel(ns.Labels, "audit"),
+			Warn:      podSecurityLevel(ns.Labels, "warn"),
+		}
+	}
+	if req.namespace != "" {
+		resp, err := client.Get(ctx, req.kubeContext, "", "namespaces", "", req.namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace: %w", err)
+		}
+		ns := &corev1.Namespace{}
+		if err := fromObject(resp.Resource, ns); err != nil {
+			return nil, err
+		}
+		addNamespace(ns)
+	} else {
+		if err := listAll(ctx, client, req.kubeContext, "", "namespaces", "", k8s.ListOptions{}, addNamespace); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+	}
+
+	violations := map[string]*PodSecurityViolation{}
+	opts := k8s.ListOptions{FieldSelector: activePodsFieldSelector, AllNamespaces: req.namespace == ""}
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "pods", "", opts, func(pod *corev1.Pod) {
+		out.PodsScanned++
+		ns, ok := namespaces[pod.Namespace]
+		if !ok {
+			ns = &NamespacePodSecurity{Namespace: pod.Namespace}
+			namespaces[pod.Namespace] = ns
+		}
+		ns.Pods++
+
+		issues := evaluatePodSecurity(&pod.Spec)
+		level := podSecurityProfile(issues)
+		if level == profilePrivileged {
+			ns.PodsViolatingBaseline++
+		}
+		if level != profileRestricted {
+			ns.PodsViolatingRestricted++
+		}
+		enforce, _, _ := strings.Cut(ns.Enforce, ":")
+		rejected := enforce != "" && profileRank[level] < profileRank[enforce]
+		if rejected {
+			ns.PodsViolatingEnforce++
+		}
+
+		// Only report the issues of the requested profile.
+		var reported []podSecurityIssue
+		for _, issue := range issues {
+			if profileRank[issue.profile] <= profileRank[req.profile] {
+				reported = append(reported, issue)
+			}
+		}
+		if len(reported) == 0 {
+			return
+		}
+
+		workload := podWorkload(pod)
+		key := pod.Namespace + "/" + workload
+		v, ok := violations[key]
+		if !ok {
+			v = &PodSecurityViolation{Namespace: pod.Namespace, Workload: workload, Level: level, Checks: []string{}, Details: []string{}}
+			violations[key] = v
+		}
+		v.Pods++
+		if profileRank[level] < profileRank[v.Level] {
+			v.Level = level
+		}
+		v.RejectedByEnforce = v.RejectedByEnforce || rejected
+		for _, issue := range reported {
+			if !slices.Contains(v.Checks, issue.check) {
+				v.Checks = append(v.Checks, issue.check)
+			}
+			if len(v.Details) < maxPodSecurityDetails && !slices.Contains(v.Details, issue.detail) {
+				v.Details = append(v.Details, issue.detail)
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var nsList []NamespacePodSecurity
+	for _, ns := range namespaces {
+		if ns.Enforce == "" {
+			out.UnlabeledNamespaces++
+		}
+		nsList = append(nsList, *ns)
+	}
+	sort.Slice(nsList, func(i, j int) bool { return nsList[i].Namespace < nsList[j].Namespace })
+	out.TotalNamespaces = len(nsList)
+	if len(nsList) > req.limit {
+		nsList = nsList[:req.limit]
+		out.NamespacesTruncated = true
+	}
+	out.Namespaces = append(out.Namespaces, nsList...)
+
+	var vList []PodSecurityViolation
+	for _, v := range violations {
+		sort.Strings(v.Checks)
+		vList = append(vList, *v)
+	}
+	sort.Slice(vList, func(i, j int) bool {
+		a, b := vList[i], vList[j]
+		if a.Level != b.Level {
+			return profileRank[a.Level] < profileRank[b.Level]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload < b.Workload
+	})
+	out.TotalViolations = len(vList)
+	if len(vList) > req.limit {
+		vList = vList[:req.limit]
+		out.ViolationsTruncated = true
+	}
+	out.Violations = append(out.Violations, vList...)
+
+	return out, nil
+}
+
+// podSecurityLevel returns the level of a Pod Security admission mode label
+// of a namespace, with the pinned version when it is not latest.
+func podSecurityLevel(labels map[string]string, mode string) string {
+	level := labels[podSecurityLabelPrefix+mode]
+	if level == "" {
+		return ""
+	}
+	if v := labels[podSecurityLabelPrefix+mode+"-version"]; v != "" && v != "latest" {
+		return level + ":" + v
+	}
+	return level
+}
+
+// podSecurityProfile returns the most restrictive profile a pod with the
+// issues meets.
+func podSecurityProfile(issues []podSecurityIssue) string {
+	level := profileRestricted
+	for _, issue := range issues {
+		if issue.profile == profileBaseline {
+			return profilePrivileged
+		}
+		level = profileBaseline
+	}
+	return level
+}
+
+// podContainer is the part of a container, init container or ephemeral
+// container the Pod Security Standards check.
+type podContainer struct {
+	name            string
+	securityContext *corev1.SecurityContext
+	ports           []corev1.ContainerPort
+}
+
+// podContainers returns every container of a pod spec.
+func podContainers(spec *corev1.PodSpec) []podContainer {
+	var containers []podContainer
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		containers = append(containers, podContainer{name: c.Name, securityContext: c.SecurityContext, ports: c.Ports})
+	}
+	for _, c := range spec.EphemeralContainers {
+		containers = append(containers, podContainer{name: c.Name, securityContext: c.SecurityContext, ports: c.Ports})
+	}
+	return containers
+}
+
+// evaluatePodSecurity checks a pod spec against the baseline and restricted
+// Pod Security Standards. Issues are named after the checks of the Pod
+// Security admission controller; profile is baseline for checks that
+// already fail the baseline profile.
+func evaluatePodSecurity(spec *corev1.PodSpec) []podSecurityIssue {
+	var issues []podSecurityIssue
+	baseline := func(check, detail string) {
+		issues = append(issues, podSecurityIssue{check: check, profile: profileBaseline, detail: detail})
+	}
+	restricted := func(check, detail string) {
+		issues = append(issues, podSecurityIssue{check: check, profile: profileRestricted, detail: detail})
+	}
+
+	if spec.HostNetwork {
+		baseline("hostNamespaces", "hostNetwork: true")
+	}
+	if spec.HostPID {
+		baseline("hostNamespaces", "hostPID: true")
+	}
+	if spec.HostIPC {
+		baseline("hostNamespaces", "hostIPC: true")
+	}
+
+	podSC := spec.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+	for _, sysctl := range podSC.Sysctls {
+		if !slices.Contains(safeSysctls, sysctl.Name) {
+			baseline("sysctls", fmt.Sprintf("unsafe sysctl %s", sysctl.Name))
+		}
+	}
+
+	for _, v := range spec.Volumes {
+		switch {
+		case v.HostPath != nil:
+			baseline("hostPathVolumes", fmt.Sprintf("volume %s: hostPath %s", v.Name, v.HostPath.Path))
+		case v.ConfigMap != nil, v.CSI != nil, v.DownwardAPI != nil, v.EmptyDir != nil, v.Ephemeral != nil,
+			v.PersistentVolumeClaim != nil, v.Projected != nil, v.Secret != nil:
+		default:
+			restricted("restrictedVolumes", fmt.Sprintf("volume %s: type not allowed", v.Name))
+		}
+	}
+
+	podSeccomp := ""
+	if podSC.SeccompProfile != nil {
+		podSeccomp = string(podSC.SeccompProfile.Type)
+	}
+	if podSeccomp == string(corev1.SeccompProfileTypeUnconfined) {
+		baseline("seccompProfile_baseline", "pod seccompProfile: Unconfined")
+	}
+	podNonRoot := podSC.RunAsNonRoot != nil && *podSC.RunAsNonRoot
+	if podSC.RunAsNonRoot != nil && !*podSC.RunAsNonRoot {
+		restricted("runAsNonRoot", "pod runAsNonRoot: false")
+	}
+	if podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+		restricted("runAsUser", "pod runAsUser: 0")
+	}
+
+	for _, c := range podContainers(spec) {
+		sc := c.securityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		prefix := "container " + c.name + ": "
+
+		if sc.Privileged != nil && *sc.Privileged {
+			baseline("privileged", prefix+"privileged")
+		}
+		for _, port := range c.ports {
+			if port.HostPort != 0 {
+				baseline("hostPorts", prefix+fmt.Sprintf("hostPort %d", port.HostPort))
+			}
+		}
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			baseline("procMount", prefix+fmt.Sprintf("procMount %s", *sc.ProcMount))
+		}
+
+		var added, dropped []string
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				added = append(added, string(capability))
+			}
+			for _, capability := range sc.Capabilities.Drop {
+				dropped = append(dropped, string(capability))
+			}
+		}
+		for _, capability := range added {
+			if !slices.Contains(baselineCapabilities, capability) {
+				baseline("capabilities_baseline", prefix+fmt.Sprintf("adds capability %s", capability))
+			} else if capability != "NET_BIND_SERVICE" {
+				restricted("capabilities_restricted", prefix+fmt.Sprintf("adds capability %s", capability))
+			}
+		}
+		if !slices.Contains(dropped, "ALL") {
+			restricted("capabilities_restricted", prefix+"does not drop ALL capabilities")
+		}
+
+		seccomp := podSeccomp
+		if sc.SeccompProfile != nil {
+			seccomp = string(sc.SeccompProfile.Type)
+			if seccomp == string(corev1.SeccompProfileTypeUnconfined) {
+				baseline("seccompProfile_baseline", prefix+"seccompProfile: Unconfined")
+			}
+		}
+		if seccomp != string(corev1.SeccompProfileTypeRuntimeDefault) && seccomp != string(corev1.SeccompProfileTypeLocalhost) {
+			restricted("seccompProfile_restricted", prefix+"seccompProfile is not RuntimeDefault or Localhost")
+		}
+
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			restricted("allowPrivilegeEscalation", prefix+"allowPrivilegeEscalation is not false")
+		}
+
+		switch {
+		case sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot:
+			restricted("runAsNonRoot", prefix+"runAsNonRoot: false")
+		case sc.RunAsNonRoot == nil && !podNonRoot:
+			restricted("runAsNonRoot", prefix+"runAsNonRoot is not set")
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			restricted("runAsUser", prefix+"runAsUser: 0")
+		}
+	}
+	return issues
+}
@@ -0,0 +1,147 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// restrictedSpec returns a pod spec meeting the restricted profile.
+func restrictedSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   ptr(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Containers: []corev1.Container{{
+			Name: "app",
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr(false),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+			},
+		}},
+		Volumes: []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+	}
+}
+
+func podSecurityPod(namespace, name, deployment string, spec corev1.PodSpec) *corev1.Pod {
+	pod := imagesPod(name, deployment)
+	pod.Namespace = namespace
+	pod.Spec = spec
+	return pod
+}
+
+func newPodSecurityClient() *mcptest.FakeClient {
+	privileged := restrictedSpec()
+	privileged.HostNetwork = true
+	privileged.Containers[0].SecurityContext.Privileged = ptr(true)
+	privileged.Volumes = append(privileged.Volumes, corev1.Volume{
+		Name:         "host",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run"}},
+	})
+
+	baseline := corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}}
+
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"namespaces": {
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{
+				"pod-security.kubernetes.io/enforce":         "baseline",
+				"pod-security.kubernetes.io/enforce-version": "v1.29",
+				"pod-security.kubernetes.io/warn":            "restricted",
+				"pod-security.kubernetes.io/warn-version":    "latest",
+			}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}},
+		},
+		"pods": {
+			podSecurityPod("shop", "api-1", "api", restrictedSpec()),
+			podSecurityPod("shop", "web-1", "web", baseline),
+			podSecurityPod("shop", "agent-1", "agent", privileged),
+			podSecurityPod("monitoring", "exporter-1", "exporter", privileged),
+		},
+	})
+}
+
+func TestReportPodSecurity(t *testing.T) {
+	req, errMsg := parsePodSecurityRequest(map[string]interface{}{})
+	require.Empty(t, errMsg)
+
+	out, err := reportPodSecurity(context.Background(), newPodSecurityClient(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, out.PodsScanned)
+	assert.Equal(t, 1, out.UnlabeledNamespaces)
+	assert.Equal(t, []NamespacePodSecurity{
+		{Namespace: "monitoring", Pods: 1, PodsViolatingBaseline: 1, PodsViolatingRestricted: 1},
+		{Namespace: "shop", Enforce: "baseline:v1.29", Warn: "restricted", Pods: 3, PodsViolatingBaseline: 1, PodsViolatingRestricted: 2, PodsViolatingEnforce: 1},
+	}, out.Namespaces)
+
+	require.Equal(t, 3, out.TotalViolations)
+	agent := out.Violations[1]
+	assert.Equal(t, "Deployment/agent", agent.Workload)
+	assert.Equal(t, profilePrivileged, agent.Level)
+	assert.Equal(t, []string{"hostNamespaces", "hostPathVolumes", "privileged"}, agent.Checks)
+	assert.Contains(t, agent.Details, "volume host: hostPath /var/run")
+	assert.True(t, agent.RejectedByEnforce)
+	assert.False(t, out.Violations[0].RejectedByEnforce, "unlabeled namespaces enforce nothing")
+
+	web := out.Violations[2]
+	assert.Equal(t, profileBaseline, web.Level)
+	assert.Equal(t, []string{"allowPrivilegeEscalation", "capabilities_restricted", "runAsNonRoot", "seccompProfile_restricted"}, web.Checks)
+	assert.False(t, web.RejectedByEnforce)
+}
+
+func TestReportPodSecurity_BaselineProfile(t *testing.T) {
+	req, errMsg := parsePodSecurityRequest(map[string]interface{}{"namespace": "shop", "profile": "baseline"})
+	require.Empty(t, errMsg)
+
+	out, err := reportPodSecurity(context.Background(), newPodSecurityClient(), req)
+	require.NoError(t, err)
+
+	require.Len(t, out.Namespaces, 1)
+	assert.Equal(t, "baseline:v1.29", out.Namespaces[0].Enforce)
+	require.Equal(t, 1, out.TotalViolations, "workloads meeting baseline are not listed")
+	assert.Equal(t, "Deployment/agent", out.Violations[0].Workload)
+}
+
+func TestEvaluatePodSecurity(t *testing.T) {
+	assert.Empty(t, evaluatePodSecurity(ptr(restrictedSpec())))
+
+	spec := restrictedSpec()
+	spec.SecurityContext.RunAsUser = ptr(int64(0))
+	spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"SYS_ADMIN"}
+	spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 80, HostPort: 80}}
+	spec.SecurityContext.Sysctls = []corev1.Sysctl{{Name: "kernel.msgmax", Value: "1"}}
+
+	var checks []string
+	for _, issue := range evaluatePodSecurity(&spec) {
+		checks = append(checks, issue.profile+"/"+issue.check)
+	}
+	assert.ElementsMatch(t, []string{
+		"baseline/sysctls",
+		"restricted/runAsUser",
+		"baseline/hostPorts",
+		"baseline/capabilities_baseline",
+	}, checks)
+	assert.Equal(t, profilePrivileged, podSecurityProfile(evaluatePodSecurity(&spec)))
+}
+
+func TestPodSecurity_FleetDeniedCluster(t *testing.T) {
+	result := callTool(t, mcptest.NewFakeClient(nil), "pod_security", map[string]interface{}{"clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: pod_security")
+}
+
+func TestParsePodSecurityRequest(t *testing.T) {
+	_, errMsg := parsePodSecurityRequest(map[string]interface{}{"profile": "privileged"})
+	assert.Equal(t, "profile must be baseline or restricted", errMsg)
+
+	_, errMsg = parsePodSecurityRequest(map[string]interface{}{"limit": float64(MaxPodSecurityLimit + 1)})
+	assert.Contains(t, errMsg, "limit must be between")
+}
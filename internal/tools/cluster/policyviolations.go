@@ -0,0 +1,509 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the policy_violations tool's limit param.
+const (
+	DefaultPolicyViolationsLimit = 100
+	MaxPolicyViolationsLimit     = 1000
+)
+
+// maxPolicyDenials caps the admission denials policy_violations reports.
+const maxPolicyDenials = 20
+
+// Policy engines reported by policy_violations.
+const (
+	PolicyEngineKyverno    = "kyverno"
+	PolicyEngineGatekeeper = "gatekeeper"
+)
+
+// gatekeeperConstraintsGroup is the API group of the constraint kinds
+// Gatekeeper creates from its ConstraintTemplates.
+const gatekeeperConstraintsGroup = "constraints.gatekeeper.sh"
+
+// policyReportKind is a PolicyReport resource, as written by Kyverno and
+// other engines following the Policy Report API.
+type policyReportKind struct {
+	resource   string
+	apiGroup   string
+	namespaced bool
+}
+
+// policyReportKinds are the report resources policy_violations reads. The
+// openreports.io group succeeds wgpolicyk8s.io in recent Kyverno releases.
+var policyReportKinds = []policyReportKind{
+	{"policyreports", "wgpolicyk8s.io", true},
+	{"clusterpolicyreports", "wgpolicyk8s.io", false},
+	{"reports", "openreports.io", true},
+	{"clusterreports", "openreports.io", false},
+}
+
+// policyDenialReasons are the event reasons of admission denials: Kyverno's
+// PolicyViolation and Gatekeeper's FailedAdmission, emitted with
+// --emit-admission-events.
+var policyDenialReasons = []struct{ reason, engine string }{
+	{"PolicyViolation", PolicyEngineKyverno},
+	{"FailedAdmission", PolicyEngineGatekeeper},
+}
+
+// PolicyViolation is a resource that does not comply with a policy.
+type PolicyViolation struct {
+	Engine string `json:"engine"`
+	Policy string `json:"policy"`
+	Rule   string `json:"rule,omitempty"`
+
+	// Result is fail, warn or error for report results, or the
+	// enforcement action (deny, warn, dryrun) of a Gatekeeper constraint.
+	Result   string `json:"result"`
+	Severity string `json:"severity,omitempty"`
+	Category string `json:"category,omitempty"`
+
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// PolicyViolationGroup counts the violations of one policy.
+type PolicyViolationGroup struct {
+	Engine   string `json:"engine"`
+	Policy   string `json:"policy"`
+	Severity string `json:"severity,omitempty"`
+	Category string `json:"category,omitempty"`
+	Count    int    `json:"count"`
+
+	// Namespaces are the namespaces of the violating resources, sorted;
+	// cluster-scoped resources are not listed.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Message is the message of one of the violations.
+	Message string `json:"message,omitempty"`
+}
+
+// PolicyDenial is an admission request a policy engine rejected, taken
+// from its events.
+type PolicyDenial struct {
+	Engine   string `json:"engine"`
+	Object   string `json:"object"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen string `json:"lastSeen,omitempty"`
+}
+
+// PolicyViolationsOutput is the response of the policy_violations tool.
+// Policies are sorted by severity, then by their number of violations.
+type PolicyViolationsOutput struct {
+	// Engines are the policy engines whose reports or constraints are
+	// installed.
+	Engines    []string               `json:"engines"`
+	Total      int                    `json:"total"`
+	BySeverity map[string]int         `json:"bySeverity,omitempty"`
+	Policies   []PolicyViolationGroup `json:"policies"`
+	Violations []PolicyViolation      `json:"violations"`
+	Truncated  bool                   `json:"truncated,omitempty"`
+
+	// Denials are recent policy violation events of the engines, newest
+	// first. They include rejected admission requests, which explain why an
+	// object was never created.
+	Denials []PolicyDenial `json:"denials,omitempty"`
+	Message string         `json:"message,omitempty"`
+
+	// Unavailable lists the data that could not be read, e.g. for lack of
+	// list permission.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// policyReport holds the fields of a PolicyReport policy_violations reads.
+type policyReport struct {
+	Scope *struct {
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"scope"`
+	Results []struct {
+		Policy    string `json:"policy"`
+		Rule      string `json:"rule"`
+		Result    string `json:"result"`
+		Severity  string `json:"severity"`
+		Category  string `json:"category"`
+		Message   string `json:"message"`
+		Source    string `json:"source"`
+		Resources []struct {
+			Kind      string `json:"kind"`
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+		} `json:"resources"`
+	} `json:"results"`
+}
+
+// gatekeeperConstraint holds the fields of a Gatekeeper constraint
+// policy_violations reads.
+type gatekeeperConstraint struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		EnforcementAction string `json:"enforcementAction"`
+	} `json:"spec"`
+	Status struct {
+		Violations []struct {
+			EnforcementAction string `json:"enforcementAction"`
+			Kind              string `json:"kind"`
+			Namespace         string `json:"namespace"`
+			Name              string `json:"name"`
+			Message           string `json:"message"`
+		} `json:"violations"`
+	} `json:"status"`
+}
+
+// policyViolationsRequest holds the validated policy_violations tool
+// arguments.
+type policyViolationsRequest struct {
+	cluster     string
+	kubeContext string
+	namespace   string
+	policy      string
+	severity    string
+	kind        string
+	name        string
+	limit       int
+}
+
+// handlePolicyViolations lists the policy violations Kyverno and Gatekeeper
+// report, aggregated by policy and severity, with recent admission denials.
+func handlePolicyViolations(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req := policyViolationsRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultPolicyViolationsLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	req.policy, _ = args["policy"].(string)
+	req.severity, _ = args["severity"].(string)
+	req.kind, _ = args["kind"].(string)
+	req.name, _ = args["name"].(string)
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxPolicyViolationsLimit {
+			return toolerrors.InvalidArgument(fmt.Sprintf("limit must be between 1 and %d", MaxPolicyViolationsLimit)).Result(), nil
+		}
+		req.limit = int(v)
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	return jsonResult(collectPolicyViolations(ctx, client.K8s(), req))
+}
+
+// policyScan collects the violations of one policy_violations call.
+type policyScan struct {
+	client     k8s.Client
+	req        policyViolationsRequest
+	out        *PolicyViolationsOutput
+	violations []PolicyViolation
+}
+
+func (s *policyScan) unavailable(format string, args ...interface{}) {
+	s.out.Unavailable = append(s.out.Unavailable, fmt.Sprintf(format, args...))
+}
+
+func (s *policyScan) addEngine(engine string) {
+	if !slices.Contains(s.out.Engines, engine) {
+		s.out.Engines = append(s.out.Engines, engine)
+	}
+}
+
+// add records v when it matches the request.
+func (s *policyScan) add(v PolicyViolation) {
+	r := s.req
+	switch {
+	case r.namespace != "" && v.Namespace != r.namespace,
+		r.policy != "" && v.Policy != r.policy,
+		r.severity != "" && !strings.EqualFold(v.Severity, r.severity),
+		r.kind != "" && !strings.EqualFold(v.Kind, r.kind),
+		r.name != "" && v.Name != r.name:
+		return
+	}
+	s.violations = append(s.violations, v)
+}
+
+// collectPolicyViolations reads the policy reports, Gatekeeper constraints
+// and admission denial events matching the request.
+func collectPolicyViolations(ctx context.Context, client k8s.Client, req policyViolationsRequest) *PolicyViolationsOutput {
+	s := &policyScan{
+		client: client,
+		req:    req,
+		out:    &PolicyViolationsOutput{Engines: []string{}, Policies: []PolicyViolationGroup{}, Violations: []PolicyViolation{}},
+	}
+
+	s.readPolicyReports(ctx)
+	s.readGatekeeperConstraints(ctx)
+	s.readDenials(ctx)
+
+	if len(s.out.Engines) == 0 {
+		s.out.Message = "Neither Kyverno policy reports nor Gatekeeper constraints are installed in this cluster"
+	}
+
+	s.aggregate()
+	return s.out
+}
+
+// readPolicyReports reads the failed, warned and errored results of the
+// PolicyReports. Cluster-wide reports are only read without a namespace.
+func (s *policyScan) readPolicyReports(ctx context.Context) {
+	for _, kind := range policyReportKinds {
+		if !kind.namespaced && s.req.namespace != "" {
+			continue
+		}
+		namespace := ""
+		if kind.namespaced {
+			namespace = s.req.namespace
+		}
+		opts := k8s.ListOptions{AllNamespaces: kind.namespaced && namespace == ""}
+		err := listAll(ctx, s.client, s.req.kubeContext, namespace, kind.resource, kind.apiGroup, opts, func(report *policyReport) {
+			for _, r := range report.Results {
+				if r.Result != "fail" && r.Result != "warn" && r.Result != "error" {
+					continue
+				}
+				engine := strings.ToLower(r.Source)
+				if engine == "" {
+					engine = PolicyEngineKyverno
+				}
+				v := PolicyViolation{
+					Engine:   engine,
+					Policy:   r.Policy,
+					Rule:     r.Rule,
+					Result:   r.Result,
+					Severity: r.Severity,
+					Category: r.Category,
+					Message:  r.Message,
+				}
+				// Reports scoped to one resource leave results' resources empty.
+				resources := r.Resources
+				if len(resources) == 0 && report.Scope != nil {
+					v.Kind, v.Namespace, v.Name = report.Scope.Kind, report.Scope.Namespace, report.Scope.Name
+					s.add(v)
+					continue
+				}
+				for _, res := range resources {
+					v.Kind, v.Namespace, v.Name = res.Kind, res.Namespace, res.Name
+					s.add(v)
+				}
+			}
+		})
+		if errors.Is(err, k8s.ErrUnknownResourceType) {
+			continue
+		}
+		s.addEngine(PolicyEngineKyverno)
+		if err != nil {
+			s.unavailable("%s.%s: %v", kind.resource, kind.apiGroup, err)
+		}
+	}
+}
+
+// readGatekeeperConstraints reads the audit violations in the status of
+// every Gatekeeper constraint. Gatekeeper truncates them to its
+// --constraint-violations-limit.
+func (s *policyScan) readGatekeeperConstraints(ctx context.Context) {
+	resources, err := s.client.GetAPIResources(ctx, s.req.kubeContext, 0, 0, gatekeeperConstraintsGroup, false, []string{"list"})
+	if err != nil {
+		s.unavailable("%s: %v", gatekeeperConstraintsGroup, err)
+		return
+	}
+	if len(resources.Items) == 0 {
+		return
+	}
+	s.addEngine(PolicyEngineGatekeeper)
+	for _, res := range resources.Items {
+		err := listAll(ctx, s.client, s.req.kubeContext, "", res.Name, gatekeeperConstraintsGroup, k8s.ListOptions{}, func(c *gatekeeperConstraint) {
+			action := c.Spec.EnforcementAction
+			if action == "" {
+				action = "deny"
+			}
+			for _, v := range c.Status.Violations {
+				result := v.EnforcementAction
+				if result == "" {
+					result = action
+				}
+				s.add(PolicyViolation{
+					Engine:    PolicyEngineGatekeeper,
+					Policy:    res.Kind + "/" + c.Metadata.Name,
+					Result:    result,
+					Severity:  c.Metadata.Annotations["policy.severity"],
+					Kind:      v.Kind,
+					Namespace: v.Namespace,
+					Name:      v.Name,
+					Message:   v.Message,
+				})
+			}
+		})
+		if err != nil {
+			s.unavailable("%s.%s: %v", res.Name, gatekeeperConstraintsGroup, err)
+		}
+	}
+}
+
+// readDenials reads the events of rejected admission requests. Kyverno
+// records them on the policy and Gatekeeper in its own namespace, so they
+// are searched in every namespace and matched by object and message.
+func (s *policyScan) readDenials(ctx context.Context) {
+	if len(s.out.Engines) == 0 {
+		return
+	}
+	type denial struct {
+		PolicyDenial
+		seen time.Time
+	}
+	var denials []denial
+	for _, r := range policyDenialReasons {
+		opts := k8s.ListOptions{AllNamespaces: true, FieldSelector: "reason=" + r.reason}
+		err := listAll(ctx, s.client, s.req.kubeContext, "", "events", "", opts, func(ev *corev1.Event) {
+			if !s.denialMatches(ev) {
+				return
+			}
+			obj := ev.InvolvedObject
+			object := obj.Kind + "/" + obj.Name
+			if obj.Namespace != "" {
+				object = obj.Kind + "/" + obj.Namespace + "/" + obj.Name
+			}
+			d := denial{
+				PolicyDenial: PolicyDenial{Engine: r.engine, Object: object, Message: ev.Message, Count: ev.Count},
+				seen:         eventLastSeen(ev),
+			}
+			if !d.seen.IsZero() {
+				d.LastSeen = d.seen.UTC().Format(time.RFC3339)
+			}
+			denials = append(denials, d)
+		})
+		if err != nil {
+			s.unavailable("events (reason=%s): %v", r.reason, err)
+		}
+	}
+
+	sort.SliceStable(denials, func(i, j int) bool { return denials[i].seen.After(denials[j].seen) })
+	for _, d := range denials {
+		if len(s.out.Denials) == maxPolicyDenials {
+			break
+		}
+		s.out.Denials = append(s.out.Denials, d.PolicyDenial)
+	}
+}
+
+// denialMatches reports whether a denial event concerns the requested
+// namespace and resource. Rejected objects do not exist, so they are found
+// in the event message.
+func (s *policyScan) denialMatches(ev *corev1.Event) bool {
+	mentions := func(v string) bool {
+		return ev.InvolvedObject.Namespace == v || ev.InvolvedObject.Name == v || strings.Contains(ev.Message, v)
+	}
+	if s.req.namespace != "" && !mentions(s.req.namespace) {
+		return false
+	}
+	if s.req.name != "" && !mentions(s.req.name) {
+		return false
+	}
+	if s.req.policy != "" && !mentions(s.req.policy) {
+		return false
+	}
+	return true
+}
+
+// eventLastSeen returns when an event last occurred.
+func eventLastSeen(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// severityRank orders severities from the most to the least severe.
+var severityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3, "info": 4}
+
+// rankSeverity returns the rank of a severity; unknown ones come last.
+func rankSeverity(severity string) int {
+	if rank, ok := severityRank[strings.ToLower(severity)]; ok {
+		return rank
+	}
+	return len(severityRank)
+}
+
+// aggregate groups the violations by policy and severity, sorts them most
+// severe first and applies the limit.
+func (s *policyScan) aggregate() {
+	groups := map[string]*PolicyViolationGroup{}
+	namespaces := map[string]map[string]bool{}
+	for _, v := range s.violations {
+		if s.out.BySeverity == nil {
+			s.out.BySeverity = map[string]int{}
+		}
+		severity := v.Severity
+		if severity == "" {
+			severity = "unknown"
+		}
+		s.out.BySeverity[severity]++
+
+		key := v.Engine + "\x00" + v.Policy
+		g, ok := groups[key]
+		if !ok {
+			g = &PolicyViolationGroup{Engine: v.Engine, Policy: v.Policy, Severity: v.Severity, Category: v.Category, Message: v.Message}
+			groups[key] = g
+			namespaces[key] = map[string]bool{}
+		}
+		g.Count++
+		if v.Namespace != "" && !namespaces[key][v.Namespace] {
+			namespaces[key][v.Namespace] = true
+			g.Namespaces = append(g.Namespaces, v.Namespace)
+		}
+	}
+	for _, g := range groups {
+		sort.Strings(g.Namespaces)
+		s.out.Policies = append(s.out.Policies, *g)
+	}
+	sort.Slice(s.out.Policies, func(i, j int) bool {
+		a, b := s.out.Policies[i], s.out.Policies[j]
+		if ra, rb := rankSeverity(a.Severity), rankSeverity(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Policy < b.Policy
+	})
+
+	violations := s.violations
+	sort.SliceStable(violations, func(i, j int) bool {
+		return rankSeverity(violations[i].Severity) < rankSeverity(violations[j].Severity)
+	})
+	s.out.Total = len(violations)
+	if len(violations) > s.req.limit {
+		violations = violations[:s.req.limit]
+		s.out.Truncated = true
+	}
+	if violations != nil {
+		s.out.Violations = violations
+	}
+}
@@ -0,0 +1,158 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// policyMock serves policy reports, constraints and events, and the
+// constraint kinds through discovery.
+type policyMock struct {
+	*mcptest.FakeClient
+
+	constraintKinds []k8s.APIResourceInfo
+}
+
+func (m *policyMock) GetAPIResources(_ context.Context, _ string, _, _ int, apiGroup string, _ bool, _ []string) (*k8s.PaginatedAPIResourceResponse, error) {
+	if apiGroup != gatekeeperConstraintsGroup {
+		return &k8s.PaginatedAPIResourceResponse{}, nil
+	}
+	return &k8s.PaginatedAPIResourceResponse{Items: m.constraintKinds}, nil
+}
+
+func reportResult(policy, result, severity, kind, namespace, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"policy": policy, "rule": "check", "result": result, "severity": severity, "category": "Pod Security",
+		"message": policy + " violated", "source": "kyverno",
+		"resources": []interface{}{map[string]interface{}{"kind": kind, "namespace": namespace, "name": name}},
+	}
+}
+
+func newPolicyMock() *policyMock {
+	report := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "PolicyReport",
+		"metadata":   map[string]interface{}{"namespace": "shop", "name": "polr-shop"},
+		"results": []interface{}{
+			reportResult("disallow-privileged", "fail", "high", "Deployment", "shop", "web"),
+			reportResult("require-labels", "fail", "medium", "Deployment", "shop", "web"),
+			reportResult("require-labels", "warn", "medium", "Deployment", "shop", "db"),
+			reportResult("require-labels", "pass", "medium", "Deployment", "shop", "api"),
+		},
+	}}
+	// Per-resource reports name the resource in their scope.
+	scoped := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "PolicyReport",
+		"metadata":   map[string]interface{}{"namespace": "billing", "name": "abc-123"},
+		"scope":      map[string]interface{}{"kind": "Pod", "namespace": "billing", "name": "invoice-1"},
+		"results": []interface{}{map[string]interface{}{
+			"policy": "disallow-privileged", "result": "fail", "severity": "high", "message": "privileged containers are not allowed",
+		}},
+	}}
+	constraint := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       "K8sRequiredLabels",
+		"metadata":   map[string]interface{}{"name": "must-have-owner"},
+		"spec":       map[string]interface{}{"enforcementAction": "dryrun"},
+		"status": map[string]interface{}{"violations": []interface{}{
+			map[string]interface{}{"kind": "Namespace", "name": "legacy", "message": "you must provide labels: owner"},
+		}},
+	}}
+	denial := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "require-labels.1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "ClusterPolicy", Name: "require-labels"},
+		Reason:         "PolicyViolation",
+		Message:        "Deployment shop/checkout: [require-labels] fail (blocked); label app.kubernetes.io/name is required",
+		LastTimestamp:  metav1.NewTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+		Count:          3,
+	}
+	other := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns.1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "kube-system", Name: "coredns"},
+		Reason:         "BackOff",
+	}
+
+	return &policyMock{
+		FakeClient: mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+			"policyreports":        {report, scoped},
+			"clusterpolicyreports": {},
+			"k8srequiredlabels":    {constraint},
+			"events":               {denial, other},
+		}),
+		constraintKinds: []k8s.APIResourceInfo{{Name: "k8srequiredlabels", Kind: "K8sRequiredLabels", Group: gatekeeperConstraintsGroup}},
+	}
+}
+
+func TestCollectPolicyViolations(t *testing.T) {
+	out := collectPolicyViolations(context.Background(), newPolicyMock(), policyViolationsRequest{limit: DefaultPolicyViolationsLimit})
+
+	assert.Equal(t, []string{PolicyEngineKyverno, PolicyEngineGatekeeper}, out.Engines)
+	assert.Empty(t, out.Unavailable)
+	assert.Equal(t, 5, out.Total)
+	assert.Equal(t, map[string]int{"high": 2, "medium": 2, "unknown": 1}, out.BySeverity)
+
+	require.Len(t, out.Policies, 3)
+	assert.Equal(t, PolicyViolationGroup{
+		Engine: PolicyEngineKyverno, Policy: "disallow-privileged", Severity: "high", Category: "Pod Security",
+		Count: 2, Namespaces: []string{"billing", "shop"}, Message: "disallow-privileged violated",
+	}, out.Policies[0])
+	assert.Equal(t, "require-labels", out.Policies[1].Policy)
+	assert.Equal(t, 2, out.Policies[1].Count)
+	assert.Equal(t, "K8sRequiredLabels/must-have-owner", out.Policies[2].Policy)
+
+	// The most severe violations come first.
+	require.Len(t, out.Violations, 5)
+	assert.Equal(t, "high", out.Violations[0].Severity)
+	assert.Equal(t, "invoice-1", out.Violations[1].Name)
+	gatekeeper := out.Violations[4]
+	assert.Equal(t, "dryrun", gatekeeper.Result)
+	assert.Equal(t, "legacy", gatekeeper.Name)
+
+	require.Len(t, out.Denials, 1)
+	assert.Equal(t, PolicyDenial{
+		Engine: PolicyEngineKyverno, Object: "ClusterPolicy/require-labels",
+		Message: "Deployment shop/checkout: [require-labels] fail (blocked); label app.kubernetes.io/name is required",
+		Count:   3, LastSeen: "2024-05-01T10:00:00Z",
+	}, out.Denials[0])
+}
+
+func TestCollectPolicyViolations_Filters(t *testing.T) {
+	out := collectPolicyViolations(context.Background(), newPolicyMock(), policyViolationsRequest{
+		namespace: "shop", kind: "Deployment", name: "web", limit: DefaultPolicyViolationsLimit,
+	})
+	assert.Equal(t, 2, out.Total)
+	for _, v := range out.Violations {
+		assert.Equal(t, "web", v.Name)
+	}
+	// The denial of another deployment is not reported.
+	assert.Empty(t, out.Denials)
+
+	out = collectPolicyViolations(context.Background(), newPolicyMock(), policyViolationsRequest{name: "checkout", limit: DefaultPolicyViolationsLimit})
+	assert.Equal(t, 0, out.Total)
+	assert.Len(t, out.Denials, 1)
+
+	out = collectPolicyViolations(context.Background(), newPolicyMock(), policyViolationsRequest{severity: "HIGH", limit: 1})
+	assert.Equal(t, 2, out.Total)
+	assert.True(t, out.Truncated)
+	assert.Len(t, out.Violations, 1)
+}
+
+func TestCollectPolicyViolations_NotInstalled(t *testing.T) {
+	mock := &policyMock{FakeClient: mcptest.NewStrictFakeClient(map[string][]runtime.Object{})}
+	out := collectPolicyViolations(context.Background(), mock, policyViolationsRequest{limit: DefaultPolicyViolationsLimit})
+	assert.Empty(t, out.Engines)
+	assert.Empty(t, out.Violations)
+	assert.Contains(t, out.Message, "Neither Kyverno policy reports nor Gatekeeper constraints are installed")
+}
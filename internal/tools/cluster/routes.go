@@ -0,0 +1,657 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the routes tool's limit param.
+const (
+	DefaultRoutesLimit = 100
+	MaxRoutesLimit     = 1000
+)
+
+// routeAnyHost is the host of rules that match every host.
+const routeAnyHost = "*"
+
+// RouteRule is one host and path of an Ingress or Gateway API route, and
+// the backends its traffic goes to.
+type RouteRule struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Host is "*" for rules that match every host.
+	Host     string `json:"host"`
+	Path     string `json:"path,omitempty"`
+	PathType string `json:"pathType,omitempty"`
+
+	Backends []RouteBackend `json:"backends"`
+
+	// TLS names the secrets holding the certificate for the host, or is
+	// "default certificate" when TLS uses the controller's own.
+	TLS string `json:"tls,omitempty"`
+
+	// Class is the Ingress class or the GatewayClass; Gateways are the
+	// parents of a route.
+	Class     string   `json:"class,omitempty"`
+	Gateways  []string `json:"gateways,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+	Problems  []string `json:"problems,omitempty"`
+}
+
+// RouteBackend is where a route rule sends traffic.
+type RouteBackend struct {
+	// Service is the backend Service, or kind/name for other backends.
+	Service string `json:"service"`
+	// Namespace is set when the backend is in another namespace.
+	Namespace string `json:"namespace,omitempty"`
+	Port      string `json:"port,omitempty"`
+	Weight    *int32 `json:"weight,omitempty"`
+
+	// Ready is the ready endpoints out of all, e.g. "2/3".
+	Ready   string `json:"ready,omitempty"`
+	Problem string `json:"problem,omitempty"`
+}
+
+// RoutesOutput is the response of the routes tool. Rules are sorted by
+// host and path; when host or path is set, the most specific match is
+// first.
+type RoutesOutput struct {
+	Host       string      `json:"host,omitempty"`
+	Path       string      `json:"path,omitempty"`
+	Rules      []RouteRule `json:"rules"`
+	TotalRules int         `json:"totalRules"`
+	Truncated  bool        `json:"truncated,omitempty"`
+
+	// Unavailable lists the data that could not be read, e.g. for lack of
+	// list permission.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// gatewayObject holds the fields of a Gateway API Gateway the routes tool
+// reads.
+type gatewayObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		GatewayClassName string `json:"gatewayClassName"`
+		Listeners        []struct {
+			Name     string  `json:"name"`
+			Hostname *string `json:"hostname"`
+			Protocol string  `json:"protocol"`
+			TLS      *struct {
+				CertificateRefs []gatewayRef `json:"certificateRefs"`
+			} `json:"tls"`
+		} `json:"listeners"`
+	} `json:"spec"`
+	Status struct {
+		Addresses []struct {
+			Value string `json:"value"`
+		} `json:"addresses"`
+	} `json:"status"`
+}
+
+// routesRequest holds the validated routes tool arguments.
+type routesRequest struct {
+	cluster     string
+	kubeContext string
+	namespace   string
+	host        string
+	path        string
+	limit       int
+}
+
+// endpointCount counts the ready and all endpoints of a Service.
+type endpointCount struct {
+	ready, total int
+}
+
+// routeScan collects the rules of one routes call. Services, endpoints and
+// Gateways are read once per namespace or name; a nil entry means they
+// could not be read.
+type routeScan struct {
+	client      k8s.Client
+	kubeContext string
+	req         routesRequest
+	out         *RoutesOutput
+
+	services  map[string]map[string]*corev1.Service
+	endpoints map[string]map[string]endpointCount
+	gateways  map[string]*gatewayObject
+
+	// missingGateways are the Gateways routes refer to that do not exist.
+	missingGateways map[string]bool
+}
+
+func (s *routeScan) unavailable(format string, args ...interface{}) {
+	s.out.Unavailable = append(s.out.Unavailable, fmt.Sprintf(format, args...))
+}
+
+// handleRoutes lists the Ingress and Gateway API rules of a cluster, with
+// their backends and the readiness of those, answering where traffic to a
+// host and path goes.
+func handleRoutes(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseRoutesRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	out := collectRoutes(ctx, client.K8s(), req)
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal routes: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseRoutesRequest validates the tool arguments. limit must lie within 1
+// and MaxRoutesLimit, host must be a bare hostname and path must start
+// with a slash.
+func parseRoutesRequest(args map[string]interface{}) (routesRequest, string) {
+	req := routesRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultRoutesLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxRoutesLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxRoutesLimit)
+		}
+		req.limit = int(v)
+	}
+
+	host, _ := args["host"].(string)
+	req.host = strings.ToLower(strings.TrimSpace(host))
+	if strings.ContainsAny(req.host, "/:") {
+		return req, "host must be a hostname, without scheme, port or path"
+	}
+	req.path, _ = args["path"].(string)
+	if req.path != "" && !strings.HasPrefix(req.path, "/") {
+		return req, "path must start with /"
+	}
+	return req, ""
+}
+
+// collectRoutes lists the Ingresses, HTTPRoutes and GRPCRoutes matching the
+// request. Clusters without the Gateway API only report Ingresses.
+func collectRoutes(ctx context.Context, client k8s.Client, req routesRequest) *RoutesOutput {
+	s := &routeScan{
+		client:      client,
+		kubeContext: req.kubeContext,
+		req:         req,
+		out:         &RoutesOutput{Host: req.host, Path: req.path},
+		services:    map[string]map[string]*corev1.Service{},
+		endpoints:   map[string]map[string]endpointCount{},
+		gateways:    map[string]*gatewayObject{},
+
+		missingGateways: map[string]bool{},
+	}
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == ""}
+
+	var rules []RouteRule
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "ingresses", "networking.k8s.io", opts, func(ing *networkingv1.Ingress) {
+		rules = append(rules, s.ingressRules(ctx, ing)...)
+	})
+	if err != nil && !errors.Is(err, k8s.ErrUnknownResourceType) {
+		s.unavailable("ingresses: %v", err)
+	}
+	for _, kind := range []struct{ name, resource string }{
+		{"HTTPRoute", "httproutes"},
+		{"GRPCRoute", "grpcroutes"},
+	} {
+		err := listAll(ctx, client, req.kubeContext, req.namespace, kind.resource, gatewayAPIGroup, opts, func(r *gatewayRoute) {
+			rules = append(rules, s.gatewayRules(ctx, kind.name, r)...)
+		})
+		if err != nil && !errors.Is(err, k8s.ErrUnknownResourceType) {
+			s.unavailable("%s: %v", kind.resource, err)
+		}
+	}
+
+	s.sortRules(rules)
+	s.out.TotalRules = len(rules)
+	if len(rules) > req.limit {
+		rules = rules[:req.limit]
+		s.out.Truncated = true
+	}
+	s.out.Rules = rules
+	if s.out.Rules == nil {
+		s.out.Rules = []RouteRule{}
+	}
+	return s.out
+}
+
+// matches reports whether a rule's host and path match the request.
+func (s *routeScan) matches(host, pathType, path string) bool {
+	if s.req.host != "" && !routeHostMatches(host, s.req.host) {
+		return false
+	}
+	return s.req.path == "" || routePathMatches(pathType, path, s.req.path)
+}
+
+// ingressRules returns the rules of an Ingress that match the request.
+func (s *routeScan) ingressRules(ctx context.Context, ing *networkingv1.Ingress) []RouteRule {
+	base := RouteRule{Kind: "Ingress", Namespace: ing.Namespace, Name: ing.Name}
+	if ing.Spec.IngressClassName != nil {
+		base.Class = *ing.Spec.IngressClassName
+	}
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			base.Addresses = append(base.Addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			base.Addresses = append(base.Addresses, lb.Hostname)
+		}
+	}
+	if len(base.Addresses) == 0 {
+		base.Problems = append(base.Problems, fmt.Sprintf("no load balancer address yet; check that an ingress controller serves class %q", base.Class))
+	}
+
+	var rules []RouteRule
+	add := func(host, pathType, path string, backend *networkingv1.IngressBackend) {
+		if backend == nil || !s.matches(host, pathType, path) {
+			return
+		}
+		rule := base
+		rule.Problems = append([]string(nil), base.Problems...)
+		rule.Host = host
+		rule.Path = path
+		rule.PathType = pathType
+		rule.TLS = ingressTLS(ing, host)
+		rule.Backends = []RouteBackend{s.ingressBackend(ctx, ing.Namespace, backend)}
+		rules = append(rules, rule)
+	}
+
+	add(routeAnyHost, "", "", ing.Spec.DefaultBackend)
+	for _, r := range ing.Spec.Rules {
+		host := r.Host
+		if host == "" {
+			host = routeAnyHost
+		}
+		if r.HTTP == nil {
+			continue
+		}
+		for _, p := range r.HTTP.Paths {
+			pathType := ""
+			if p.PathType != nil {
+				pathType = string(*p.PathType)
+			}
+			add(host, pathType, p.Path, &p.Backend)
+		}
+	}
+	return rules
+}
+
+// ingressTLS returns the secret serving TLS for host, if any.
+func ingressTLS(ing *networkingv1.Ingress, host string) string {
+	for _, tls := range ing.Spec.TLS {
+		covered := len(tls.Hosts) == 0
+		for _, h := range tls.Hosts {
+			if host != routeAnyHost && routeHostMatches(h, host) {
+				covered = true
+			}
+		}
+		if !covered {
+			continue
+		}
+		if tls.SecretName == "" {
+			return "default certificate"
+		}
+		return tls.SecretName
+	}
+	return ""
+}
+
+func (s *routeScan) ingressBackend(ctx context.Context, namespace string, backend *networkingv1.IngressBackend) RouteBackend {
+	if backend.Service == nil {
+		if ref := backend.Resource; ref != nil {
+			return RouteBackend{Service: ref.Kind + "/" + ref.Name}
+		}
+		return RouteBackend{Problem: "backend names no service"}
+	}
+	port := backend.Service.Port.Name
+	if port == "" {
+		port = strconv.Itoa(int(backend.Service.Port.Number))
+	}
+	out := RouteBackend{Service: backend.Service.Name, Port: port}
+	s.checkBackend(ctx, &out, namespace, backend.Service.Port.Name, backend.Service.Port.Number)
+	return out
+}
+
+// gatewayRules returns the rules of an HTTPRoute or GRPCRoute that match
+// the request, one per hostname and path.
+func (s *routeScan) gatewayRules(ctx context.Context, kind string, r *gatewayRoute) []RouteRule {
+	base := RouteRule{Kind: kind, Namespace: r.Metadata.Namespace, Name: r.Metadata.Name, Problems: r.conditionProblems()}
+	var parents []*gatewayObject
+	for _, ref := range r.Spec.ParentRefs {
+		namespace := base.Namespace
+		if ref.Namespace != nil {
+			namespace = *ref.Namespace
+		}
+		name := ref.Name
+		if namespace != base.Namespace {
+			name = namespace + "/" + ref.Name
+		}
+		base.Gateways = append(base.Gateways, name)
+		gw := s.gateway(ctx, namespace, ref.Name, &base)
+		if gw == nil {
+			continue
+		}
+		parents = append(parents, gw)
+		if base.Class == "" {
+			base.Class = gw.Spec.GatewayClassName
+		}
+		for _, a := range gw.Status.Addresses {
+			base.Addresses = append(base.Addresses, a.Value)
+		}
+	}
+
+	hosts := r.Spec.Hostnames
+	if len(hosts) == 0 {
+		hosts = []string{routeAnyHost}
+	}
+	var rules []RouteRule
+	for _, rr := range r.Spec.Rules {
+		type match struct{ pathType, path string }
+		var paths []match
+		for _, m := range rr.Matches {
+			if m.Path != nil {
+				paths = append(paths, match{m.Path.Type, m.Path.Value})
+			}
+		}
+		if len(paths) == 0 {
+			paths = []match{{}}
+		}
+		for _, host := range hosts {
+			for _, p := range paths {
+				if !s.matches(host, p.pathType, p.path) {
+					continue
+				}
+				rule := base
+				rule.Problems = append([]string(nil), base.Problems...)
+				rule.Host = host
+				rule.Path = p.path
+				rule.PathType = p.pathType
+				rule.TLS = gatewayTLS(parents, host)
+				rule.Backends = []RouteBackend{}
+				for _, ref := range rr.BackendRefs {
+					rule.Backends = append(rule.Backends, s.gatewayBackend(ctx, base.Namespace, ref))
+				}
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+func (s *routeScan) gatewayBackend(ctx context.Context, routeNamespace string, ref gatewayRef) RouteBackend {
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = *ref.Namespace
+	}
+	out := RouteBackend{Service: ref.Name, Weight: ref.Weight}
+	if namespace != routeNamespace {
+		out.Namespace = namespace
+	}
+	if !ref.isService(ref.Name, namespace) {
+		kind := "Service"
+		if ref.Kind != nil {
+			kind = *ref.Kind
+		}
+		out.Service = kind + "/" + ref.Name
+		return out
+	}
+	if ref.Port == nil {
+		out.Problem = "backend port is not set"
+		return out
+	}
+	out.Port = strconv.Itoa(int(*ref.Port))
+	s.checkBackend(ctx, &out, namespace, "", *ref.Port)
+	return out
+}
+
+// gateway returns the Gateway a route is attached to, reading each Gateway
+// once.
+func (s *routeScan) gateway(ctx context.Context, namespace, name string, rule *RouteRule) *gatewayObject {
+	key := namespace + "/" + name
+	if s.missingGateways[key] {
+		rule.Problems = append(rule.Problems, fmt.Sprintf("gateway %s does not exist", key))
+		return nil
+	}
+	if gw, ok := s.gateways[key]; ok {
+		return gw
+	}
+	s.gateways[key] = nil
+	resp, err := s.client.Get(ctx, s.kubeContext, namespace, "gateways", gatewayAPIGroup, name)
+	switch {
+	case apierrors.IsNotFound(err):
+		s.missingGateways[key] = true
+		rule.Problems = append(rule.Problems, fmt.Sprintf("gateway %s does not exist", key))
+		return nil
+	case err != nil:
+		s.unavailable("gateway %s: %v", key, err)
+		return nil
+	}
+	gw := &gatewayObject{}
+	if err := fromObject(resp.Resource, gw); err != nil {
+		s.unavailable("gateway %s: %v", key, err)
+		return nil
+	}
+	s.gateways[key] = gw
+	return gw
+}
+
+// gatewayTLS returns the certificates of the HTTPS and TLS listeners of the
+// Gateways that serve host.
+func gatewayTLS(gateways []*gatewayObject, host string) string {
+	var secrets []string
+	for _, gw := range gateways {
+		for _, l := range gw.Spec.Listeners {
+			if l.TLS == nil || (l.Protocol != "HTTPS" && l.Protocol != "TLS") {
+				continue
+			}
+			if l.Hostname != nil && (host == routeAnyHost || !routeHostMatches(*l.Hostname, host)) {
+				continue
+			}
+			for _, ref := range l.TLS.CertificateRefs {
+				name := ref.Name
+				if ref.Namespace != nil && *ref.Namespace != gw.Metadata.Namespace {
+					name = *ref.Namespace + "/" + ref.Name
+				}
+				secrets = append(secrets, name)
+			}
+		}
+	}
+	return strings.Join(secrets, ", ")
+}
+
+// checkBackend fills in the readiness of a Service backend and whether its
+// port exists. A port is matched by name when portName is set.
+func (s *routeScan) checkBackend(ctx context.Context, out *RouteBackend, namespace, portName string, portNumber int32) {
+	services := s.namespaceServices(ctx, namespace)
+	if services == nil {
+		return
+	}
+	svc, ok := services[out.Service]
+	if !ok {
+		out.Problem = "service does not exist"
+		return
+	}
+	found := false
+	for _, p := range svc.Spec.Ports {
+		if (portName != "" && p.Name == portName) || (portName == "" && p.Port == portNumber) {
+			found = true
+		}
+	}
+	if !found && svc.Spec.Type != corev1.ServiceTypeExternalName {
+		out.Problem = fmt.Sprintf("port %s is not a port of the service", out.Port)
+		return
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		out.Ready = "external: " + svc.Spec.ExternalName
+		return
+	}
+
+	endpoints := s.namespaceEndpoints(ctx, namespace)
+	if endpoints == nil {
+		return
+	}
+	count := endpoints[out.Service]
+	out.Ready = fmt.Sprintf("%d/%d", count.ready, count.total)
+	if count.ready == 0 {
+		out.Problem = "no ready endpoints"
+	}
+}
+
+// namespaceServices returns the Services of a namespace by name.
+func (s *routeScan) namespaceServices(ctx context.Context, namespace string) map[string]*corev1.Service {
+	if services, ok := s.services[namespace]; ok {
+		return services
+	}
+	services := map[string]*corev1.Service{}
+	err := listAll(ctx, s.client, s.kubeContext, namespace, "services", "", k8s.ListOptions{}, func(svc *corev1.Service) {
+		services[svc.Name] = svc
+	})
+	if err != nil {
+		s.unavailable("services in %s: %v", namespace, err)
+		services = nil
+	}
+	s.services[namespace] = services
+	return services
+}
+
+// namespaceEndpoints counts the endpoints of each Service in a namespace.
+// Endpoints in several slices, as with dual-stack Services, count once.
+func (s *routeScan) namespaceEndpoints(ctx context.Context, namespace string) map[string]endpointCount {
+	if endpoints, ok := s.endpoints[namespace]; ok {
+		return endpoints
+	}
+	seen := map[string]bool{}
+	endpoints := map[string]endpointCount{}
+	err := listAll(ctx, s.client, s.kubeContext, namespace, "endpointslices", "discovery.k8s.io", k8s.ListOptions{}, func(slice *discoveryv1.EndpointSlice) {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		if service == "" {
+			return
+		}
+		count := endpoints[service]
+		for _, ep := range slice.Endpoints {
+			id := ""
+			if ep.TargetRef != nil {
+				id = ep.TargetRef.Name
+			} else if len(ep.Addresses) > 0 {
+				id = ep.Addresses[0]
+			}
+			key := service + "/" + id
+			if id != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			count.total++
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				count.ready++
+			}
+		}
+		endpoints[service] = count
+	})
+	if err != nil {
+		s.unavailable("endpointslices in %s: %v", namespace, err)
+		endpoints = nil
+	}
+	s.endpoints[namespace] = endpoints
+	return endpoints
+}
+
+// sortRules orders rules by host and path. When the request names a host,
+// exact hosts come before wildcards and catch-alls; when it names a path,
+// the longest matching path comes first, as routers prefer it.
+func (s *routeScan) sortRules(rules []RouteRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if s.req.host != "" {
+			if ra, rb := routeHostRank(a.Host), routeHostRank(b.Host); ra != rb {
+				return ra < rb
+			}
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if s.req.path != "" && len(a.Path) != len(b.Path) {
+			return len(a.Path) > len(b.Path)
+		}
+		for _, pair := range [][2]string{{a.Path, b.Path}, {a.Kind, b.Kind}, {a.Namespace, b.Namespace}, {a.Name, b.Name}} {
+			if pair[0] != pair[1] {
+				return pair[0] < pair[1]
+			}
+		}
+		return false
+	})
+}
+
+// routeHostRank ranks exact hosts before wildcards and catch-alls.
+func routeHostRank(host string) int {
+	switch {
+	case host == routeAnyHost:
+		return 2
+	case strings.HasPrefix(host, "*."):
+		return 1
+	}
+	return 0
+}
+
+// routeHostMatches reports whether a rule host, which may be "*" or a
+// wildcard like "*.example.com", matches host. A wildcard matches exactly
+// one label.
+func routeHostMatches(pattern, host string) bool {
+	pattern = strings.ToLower(pattern)
+	host = strings.ToLower(host)
+	switch {
+	case pattern == routeAnyHost || pattern == host:
+		return true
+	case strings.HasPrefix(pattern, "*."):
+		label, rest, ok := strings.Cut(host, ".")
+		return ok && label != "" && "*."+rest == pattern
+	}
+	return false
+}
+
+// routePathMatches reports whether a rule path matches path. Rules without
+// a path match every path; prefixes match whole path segments. Ingress
+// ImplementationSpecific paths are taken to be prefixes.
+func routePathMatches(pathType, rulePath, path string) bool {
+	if rulePath == "" {
+		return true
+	}
+	switch pathType {
+	case string(networkingv1.PathTypeExact):
+		return rulePath == path
+	case "RegularExpression":
+		matched, err := regexp.MatchString("^(?:"+rulePath+")$", path)
+		return err == nil && matched
+	}
+	prefix := strings.TrimSuffix(rulePath, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
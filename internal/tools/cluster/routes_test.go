@@ -0,0 +1,191 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func routeService(name string, port int32) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: port}}},
+	}
+}
+
+func routeSlice(service string, ready ...bool) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: service + "-abc", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+	}
+	for i, r := range ready {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{fmt.Sprintf("10.0.0.%d", i)},
+			Conditions: discoveryv1.EndpointConditions{Ready: &r},
+		})
+	}
+	return slice
+}
+
+// newRoutesClient returns a client without GRPCRoutes, like a cluster
+// whose Gateway API install predates them.
+func newRoutesClient() *mcptest.FakeClient {
+	prefix := networkingv1.PathTypePrefix
+	class := "nginx"
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "shop"},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &class,
+			TLS:              []networkingv1.IngressTLS{{Hosts: []string{"*.example.com"}, SecretName: "wildcard-tls"}},
+			Rules: []networkingv1.IngressRule{{
+				Host: "shop.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+					{Path: "/", PathType: &prefix, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}}},
+					{Path: "/api", PathType: &prefix, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api", Port: networkingv1.ServiceBackendPort{Name: "grpc"}}}},
+				}}},
+			}},
+		},
+		Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "203.0.113.10"}}}},
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]interface{}{"namespace": "shop", "name": "checkout"},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "public", "namespace": "gateways"}},
+			"hostnames":  []interface{}{"checkout.example.com"},
+			"rules": []interface{}{map[string]interface{}{
+				"matches": []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/pay"}}},
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": "checkout", "port": int64(8080), "weight": int64(90)},
+					map[string]interface{}{"name": "checkout-canary", "port": int64(8080), "weight": int64(10)},
+				},
+			}},
+		},
+	}}
+	gateway := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "Gateway",
+		"metadata":   map[string]interface{}{"namespace": "gateways", "name": "public"},
+		"spec": map[string]interface{}{
+			"gatewayClassName": "envoy",
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "http", "protocol": "HTTP", "port": int64(80)},
+				map[string]interface{}{
+					"name": "https", "protocol": "HTTPS", "port": int64(443), "hostname": "*.example.com",
+					"tls": map[string]interface{}{"certificateRefs": []interface{}{map[string]interface{}{"name": "example-tls"}}},
+				},
+			},
+		},
+		"status": map[string]interface{}{"addresses": []interface{}{map[string]interface{}{"value": "198.51.100.7"}}},
+	}}
+
+	return mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+		"ingresses":      {ingress},
+		"httproutes":     {route},
+		"gateways":       {gateway},
+		"services":       {routeService("web", 80), routeService("api", 9000), routeService("checkout", 8080)},
+		"endpointslices": {routeSlice("web", true, false), routeSlice("api", true), routeSlice("checkout", false)},
+	})
+}
+
+func TestCollectRoutes(t *testing.T) {
+	req, errMsg := parseRoutesRequest(map[string]interface{}{})
+	require.Empty(t, errMsg)
+	out := collectRoutes(context.Background(), newRoutesClient(), req)
+
+	assert.Empty(t, out.Unavailable, "a cluster without GRPCRoutes is not an error")
+	assert.Equal(t, 3, out.TotalRules)
+	require.Len(t, out.Rules, 3)
+
+	checkout := out.Rules[0]
+	assert.Equal(t, "HTTPRoute", checkout.Kind)
+	assert.Equal(t, "checkout.example.com", checkout.Host)
+	assert.Equal(t, "/pay", checkout.Path)
+	assert.Equal(t, "PathPrefix", checkout.PathType)
+	assert.Equal(t, []string{"gateways/public"}, checkout.Gateways)
+	assert.Equal(t, "envoy", checkout.Class)
+	assert.Equal(t, "example-tls", checkout.TLS)
+	assert.Equal(t, []string{"198.51.100.7"}, checkout.Addresses)
+	require.Len(t, checkout.Backends, 2)
+	assert.Equal(t, "0/1", checkout.Backends[0].Ready)
+	assert.Equal(t, "no ready endpoints", checkout.Backends[0].Problem)
+	assert.Equal(t, int32(90), *checkout.Backends[0].Weight)
+	assert.Equal(t, "service does not exist", checkout.Backends[1].Problem)
+
+	root := out.Rules[1]
+	assert.Equal(t, "Ingress", root.Kind)
+	assert.Equal(t, "/", root.Path)
+	assert.Equal(t, "nginx", root.Class)
+	assert.Equal(t, "wildcard-tls", root.TLS)
+	assert.Equal(t, []RouteBackend{{Service: "web", Port: "80", Ready: "1/2"}}, root.Backends)
+	assert.Empty(t, root.Problems)
+
+	api := out.Rules[2]
+	assert.Equal(t, "/api", api.Path)
+	assert.Equal(t, "port grpc is not a port of the service", api.Backends[0].Problem)
+}
+
+func TestCollectRoutes_HostAndPath(t *testing.T) {
+	req, errMsg := parseRoutesRequest(map[string]interface{}{"host": "Shop.Example.com", "path": "/api/v1/orders"})
+	require.Empty(t, errMsg)
+	out := collectRoutes(context.Background(), newRoutesClient(), req)
+
+	require.Len(t, out.Rules, 2)
+	assert.Equal(t, "/api", out.Rules[0].Path, "the longest matching path is first")
+	assert.Equal(t, "/", out.Rules[1].Path)
+
+	req, _ = parseRoutesRequest(map[string]interface{}{"host": "shop.example.com", "path": "/apis"})
+	out = collectRoutes(context.Background(), newRoutesClient(), req)
+	require.Len(t, out.Rules, 1, "prefixes match whole path segments")
+	assert.Equal(t, "/", out.Rules[0].Path)
+}
+
+func TestCollectRoutes_MissingGateway(t *testing.T) {
+	client := newRoutesClient()
+	delete(client.Objects, "ingresses")
+	client.Objects["gateways"] = []runtime.Object{}
+
+	req, _ := parseRoutesRequest(map[string]interface{}{"namespace": "shop"})
+	out := collectRoutes(context.Background(), client, req)
+	require.Len(t, out.Rules, 1)
+	assert.Equal(t, []string{"gateway gateways/public does not exist"}, out.Rules[0].Problems)
+	assert.Empty(t, out.Rules[0].TLS)
+}
+
+func TestRouteHostMatches(t *testing.T) {
+	assert.True(t, routeHostMatches("*", "a.example.com"))
+	assert.True(t, routeHostMatches("*.example.com", "a.example.com"))
+	assert.False(t, routeHostMatches("*.example.com", "a.b.example.com"), "a wildcard matches one label")
+	assert.False(t, routeHostMatches("*.example.com", "example.com"))
+	assert.True(t, routeHostMatches("A.example.com", "a.example.com"))
+}
+
+func TestParseRoutesRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "host with scheme", args: map[string]interface{}{"host": "https://a.example.com"}, wantErr: "host must be a hostname"},
+		{name: "relative path", args: map[string]interface{}{"path": "api"}, wantErr: "path must start with /"},
+		{name: "limit too large", args: map[string]interface{}{"limit": float64(MaxRoutesLimit + 1)}, wantErr: "limit must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseRoutesRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// Default and maximum values for the search tool's limit param.
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 500
+)
+
+// How a search match was found, best first.
+const (
+	SearchMatchExact    = "exact"
+	SearchMatchPrefix   = "prefix"
+	SearchMatchContains = "contains"
+	SearchMatchPattern  = "pattern"
+	SearchMatchLabels   = "labels"
+)
+
+// searchMatchRank orders matches: the closer the name, the higher it ranks.
+var searchMatchRank = map[string]int{
+	SearchMatchExact:    0,
+	SearchMatchPrefix:   1,
+	SearchMatchContains: 2,
+	SearchMatchPattern:  2,
+	SearchMatchLabels:   3,
+}
+
+// searchType is a resource type the search tool lists.
+type searchType struct {
+	resource string
+	apiGroup string
+	kind     string
+}
+
+// defaultSearchTypes are searched when resourceTypes is not set: the types
+// agents most often look for by name.
+var defaultSearchTypes = []searchType{
+	{"pods", "", "Pod"},
+	{"deployments", "apps", "Deployment"},
+	{"statefulsets", "apps", "StatefulSet"},
+	{"daemonsets", "apps", "DaemonSet"},
+	{"cronjobs", "batch", "CronJob"},
+	{"jobs", "batch", "Job"},
+	{"services", "", "Service"},
+	{"ingresses", "networking.k8s.io", "Ingress"},
+	{"configmaps", "", "ConfigMap"},
+	{"secrets", "", "Secret"},
+	{"persistentvolumeclaims", "", "PersistentVolumeClaim"},
+}
+
+// SearchMatch is an object found by the search tool.
+type SearchMatch struct {
+	// Cluster is set for fleet searches.
+	Cluster string `json:"cluster,omitempty"`
+
+	Kind string `json:"kind"`
+	// ResourceType and APIGroup are the arguments to get the object with.
+	ResourceType string `json:"resourceType"`
+	APIGroup     string `json:"apiGroup,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+	Name         string `json:"name"`
+
+	// Match is how the object matched: exact, prefix, contains, pattern or,
+	// when only a label selector was given, labels.
+	Match  string `json:"match"`
+	Status string `json:"status,omitempty"`
+}
+
+// SearchOutput is the response of the search tool.
+type SearchOutput struct {
+	Matches      []SearchMatch `json:"matches"`
+	TotalMatches int           `json:"totalMatches"`
+	Truncated    bool          `json:"truncated,omitempty"`
+
+	// Clusters counts the clusters searched in a fleet search.
+	Clusters          int  `json:"clusters,omitempty"`
+	FailedClusters    int  `json:"failedClusters,omitempty"`
+	ClustersTruncated bool `json:"clustersTruncated,omitempty"`
+
+	// Skipped lists the clusters and resource types that could not be
+	// searched, e.g. for lack of list permission.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// searchRequest holds the validated search tool arguments.
+type searchRequest struct {
+	cluster       string
+	kubeContext   string
+	clusters      []string
+	namespace     string
+	query         string
+	pattern       *regexp.Regexp
+	labelSelector string
+	types         []searchType
+	limit         int
+}
+
+// handleSearch finds objects by name and labels across resource types,
+// namespaces and, in federation mode, clusters.
+func handleSearch(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseSearchRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	out := &SearchOutput{}
+	var matches []SearchMatch
+	if req.clusters != nil {
+		names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out.Clusters = len(names)
+		out.ClustersTruncated = len(names) < total
+
+		var mu sync.Mutex
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(capacityFleetConcurrency)
+		for _, name := range names {
+			g.Go(func() error {
+				var found []SearchMatch
+				var skipped []string
+				client, toolErr := tools.GetClusterClient(gctx, sc, name)
+				if toolErr != nil {
+					skipped = []string{fmt.Sprintf("%s: %s", name, toolErr.Message)}
+				} else {
+					found, skipped = searchCluster(gctx, client.K8s(), req, name)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				matches = append(matches, found...)
+				out.Skipped = append(out.Skipped, skipped...)
+				if toolErr != nil || (len(skipped) == len(req.types) && len(found) == 0) {
+					out.FailedClusters++
+				}
+				return nil
+			})
+		}
+		_ = g.Wait()
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		matches, out.Skipped = searchCluster(ctx, client.K8s(), req, "")
+	}
+
+	rankSearchMatches(matches)
+	sort.Strings(out.Skipped)
+	out.TotalMatches = len(matches)
+	if len(matches) > req.limit {
+		matches = matches[:req.limit]
+		out.Truncated = true
+	}
+	out.Matches = matches
+	if out.Matches == nil {
+		out.Matches = []SearchMatch{}
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal search results: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseSearchRequest validates the tool arguments. It needs a query or a
+// label selector, limits limit to 1..MaxSearchLimit, and compiles the query
+// when regex is set.
+func parseSearchRequest(args map[string]interface{}) (searchRequest, string) {
+	req := searchRequest{
+		cluster: tools.ExtractClusterParam(args),
+		types:   defaultSearchTypes,
+		limit:   DefaultSearchLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxSearchLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxSearchLimit)
+		}
+		req.limit = int(v)
+	}
+
+	query, _ := args["query"].(string)
+	req.query = strings.ToLower(strings.TrimSpace(query))
+	req.labelSelector, _ = args["labelSelector"].(string)
+	if req.query == "" && req.labelSelector == "" {
+		return req, "query or labelSelector is required"
+	}
+	if useRegex, _ := args["regex"].(bool); useRegex && req.query != "" {
+		pattern, err := regexp.Compile("(?i)" + strings.TrimSpace(query))
+		if err != nil {
+			return req, fmt.Sprintf("invalid regex: %v", err)
+		}
+		req.pattern = pattern
+	}
+	if req.labelSelector != "" {
+		if _, err := labels.Parse(req.labelSelector); err != nil {
+			return req, fmt.Sprintf("invalid labelSelector: %v", err)
+		}
+	}
+
+	if v, _ := args["resourceTypes"].(string); v != "" {
+		req.types = nil
+		for _, t := range strings.Split(v, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" {
+				continue
+			}
+			resource, group, _ := strings.Cut(t, ".")
+			req.types = append(req.types, searchType{resource: resource, apiGroup: group})
+		}
+		if len(req.types) == 0 {
+			return req, "resourceTypes must name at least one resource type"
+		}
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// searchCluster lists every requested type in one cluster and returns the
+// matches, and the types that could not be listed. cluster labels the
+// results of fleet searches.
+func searchCluster(ctx context.Context, client k8s.Client, req searchRequest, cluster string) ([]SearchMatch, []string) {
+	opts := k8s.ListOptions{LabelSelector: req.labelSelector, AllNamespaces: req.namespace == ""}
+	var matches []SearchMatch
+	var skipped []string
+	for _, t := range req.types {
+		err := listEach(ctx, client, req.kubeContext, req.namespace, t.resource, t.apiGroup, opts, func(obj map[string]interface{}) {
+			meta, _ := obj["metadata"].(map[string]interface{})
+			name, _ := meta["name"].(string)
+			how := matchSearchName(req, name)
+			if how == "" {
+				return
+			}
+			if kind, _ := obj["kind"].(string); kind == "" && t.kind != "" {
+				obj["kind"] = t.kind
+			}
+			kind, _ := obj["kind"].(string)
+			if kind == "" {
+				kind = t.resource
+			}
+			namespace, _ := meta["namespace"].(string)
+			matches = append(matches, SearchMatch{
+				Cluster:      cluster,
+				Kind:         kind,
+				ResourceType: t.resource,
+				APIGroup:     t.apiGroup,
+				Namespace:    namespace,
+				Name:         name,
+				Match:        how,
+				Status:       output.ObjectStatus(obj).Status,
+			})
+		})
+		if err != nil {
+			prefix := t.resource
+			if cluster != "" {
+				prefix = cluster + "/" + prefix
+			}
+			skipped = append(skipped, fmt.Sprintf("%s: %v", prefix, err))
+		}
+	}
+	return matches, skipped
+}
+
+// matchSearchName returns how name matches the query, or "" when it does
+// not. Without a query every object the label selector returned matches.
+func matchSearchName(req searchRequest, name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case req.query == "":
+		return SearchMatchLabels
+	case lower == req.query:
+		return SearchMatchExact
+	case req.pattern != nil:
+		if req.pattern.MatchString(name) {
+			return SearchMatchPattern
+		}
+	case strings.HasPrefix(lower, req.query):
+		return SearchMatchPrefix
+	case strings.Contains(lower, req.query):
+		return SearchMatchContains
+	}
+	return ""
+}
+
+// rankSearchMatches sorts the closest name matches first and, among equally
+// close matches, the shortest names, which are nearest to the query.
+func rankSearchMatches(matches []SearchMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if ra, rb := searchMatchRank[a.Match], searchMatchRank[b.Match]; ra != rb {
+			return ra < rb
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		for _, pair := range [][2]string{{a.Name, b.Name}, {a.Cluster, b.Cluster}, {a.Namespace, b.Namespace}, {a.Kind, b.Kind}} {
+			if pair[0] != pair[1] {
+				return pair[0] < pair[1]
+			}
+		}
+		return false
+	})
+}
+
+// listEach pages through every object of a resource type and calls fn with
+// each as an unstructured map, whatever the API type the client returns.
+func listEach(ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType, apiGroup string, opts k8s.ListOptions, fn func(map[string]interface{})) error {
+	opts.Limit = capacityListPageSize
+	for {
+		resp, err := client.List(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+			if err != nil {
+				return err
+			}
+			fn(obj)
+		}
+		if resp.Continue == "" {
+			return nil
+		}
+		opts.Continue = resp.Continue
+	}
+}
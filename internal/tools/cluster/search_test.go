@@ -0,0 +1,124 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func newSearchClient() *mcptest.FakeClient {
+	meta := func(namespace, name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": name}}
+	}
+	one := int32(1)
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"pods": {
+			&corev1.Pod{ObjectMeta: meta("shop", "checkout-7d9f-abc"), Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			&corev1.Pod{ObjectMeta: meta("shop", "cart-1"), Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		},
+		"deployments": {
+			&appsv1.Deployment{ObjectMeta: meta("shop", "checkout"), Spec: appsv1.DeploymentSpec{Replicas: &one}, Status: appsv1.DeploymentStatus{ReadyReplicas: 1, AvailableReplicas: 1}},
+			&appsv1.Deployment{ObjectMeta: meta("payments", "legacy-checkout")},
+		},
+		"services": {
+			&corev1.Service{ObjectMeta: meta("shop", "checkout")},
+		},
+		"configmaps": {
+			&corev1.ConfigMap{ObjectMeta: meta("shop", "checkout-config")},
+		},
+	})
+}
+
+func TestSearch_RanksMatches(t *testing.T) {
+	client := newSearchClient()
+	client.Errs["secrets"] = mcptest.Forbidden("secrets")
+	out := mcptest.DecodeResult[SearchOutput](t, callTool(t, client, "search", map[string]interface{}{"query": "Checkout"}))
+
+	type match struct{ kind, namespace, name, how string }
+	var got []match
+	for _, m := range out.Matches {
+		got = append(got, match{m.Kind, m.Namespace, m.Name, m.Match})
+	}
+	assert.Equal(t, []match{
+		{"Deployment", "shop", "checkout", SearchMatchExact},
+		{"Service", "shop", "checkout", SearchMatchExact},
+		{"ConfigMap", "shop", "checkout-config", SearchMatchPrefix},
+		{"Pod", "shop", "checkout-7d9f-abc", SearchMatchPrefix},
+		{"Deployment", "payments", "legacy-checkout", SearchMatchContains},
+	}, got)
+	assert.Equal(t, 5, out.TotalMatches)
+	assert.Equal(t, "apps", out.Matches[0].APIGroup)
+	assert.Equal(t, "deployments", out.Matches[0].ResourceType)
+	assert.Equal(t, "Ready", out.Matches[0].Status)
+	assert.Equal(t, "Running", out.Matches[3].Status)
+
+	require.Len(t, out.Skipped, 1)
+	assert.Contains(t, out.Skipped[0], "secrets:")
+	assert.True(t, client.ListOpts["pods"][0].AllNamespaces)
+}
+
+func TestSearch_RegexLabelsAndLimit(t *testing.T) {
+	client := newSearchClient()
+	out := mcptest.DecodeResult[SearchOutput](t, callTool(t, client, "search", map[string]interface{}{
+		"query":         "^(cart|checkout)-",
+		"regex":         true,
+		"resourceTypes": "pods, deployments.apps",
+		"namespace":     "shop",
+		"labelSelector": "app",
+		"limit":         float64(1),
+	}))
+
+	assert.Equal(t, 2, out.TotalMatches)
+	assert.True(t, out.Truncated)
+	require.Len(t, out.Matches, 1)
+	assert.Equal(t, "cart-1", out.Matches[0].Name)
+	assert.Equal(t, SearchMatchPattern, out.Matches[0].Match)
+
+	require.Len(t, client.ListOpts, 2)
+	assert.False(t, client.ListOpts["pods"][0].AllNamespaces)
+	assert.Equal(t, "app", client.ListOpts["pods"][0].LabelSelector)
+
+	out = mcptest.DecodeResult[SearchOutput](t, callTool(t, newSearchClient(), "search", map[string]interface{}{"labelSelector": "app", "resourceTypes": "services"}))
+	require.Len(t, out.Matches, 1)
+	assert.Equal(t, SearchMatchLabels, out.Matches[0].Match)
+}
+
+func TestParseSearchRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "nothing to search for", args: map[string]interface{}{}, wantErr: "query or labelSelector is required"},
+		{name: "invalid regex", args: map[string]interface{}{"query": "web-(", "regex": true}, wantErr: "invalid regex"},
+		{name: "invalid selector", args: map[string]interface{}{"labelSelector": "a in ("}, wantErr: "invalid labelSelector"},
+		{name: "empty types", args: map[string]interface{}{"query": "a", "resourceTypes": " , "}, wantErr: "at least one resource type"},
+		{name: "limit too large", args: map[string]interface{}{"query": "a", "limit": float64(MaxSearchLimit + 1)}, wantErr: "limit must be between"},
+		{name: "cluster and clusters", args: map[string]interface{}{"query": "a", "cluster": "a", "clusters": "b"}, wantErr: "either cluster or clusters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseSearchRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+}
+
+func TestSearch_FleetRequiresFederation(t *testing.T) {
+	result := callTool(t, newSearchClient(), "search", map[string]interface{}{"query": "web", "clusters": "prod-a"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "federation")
+}
+
+func TestSearch_FleetDeniedCluster(t *testing.T) {
+	result := callTool(t, newSearchClient(), "search", map[string]interface{}{"query": "web", "clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: search")
+}
@@ -0,0 +1,461 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Limits for the service_accounts tool.
+const (
+	// DefaultServiceAccountsLimit is the default cap on the number of
+	// service accounts and of findings reported.
+	DefaultServiceAccountsLimit = 100
+
+	// MaxServiceAccountsLimit is the absolute maximum allowed for limit.
+	MaxServiceAccountsLimit = 1000
+
+	// maxServiceAccountWorkloads caps the workloads listed per service
+	// account.
+	maxServiceAccountWorkloads = 10
+)
+
+// defaultServiceAccount is the service account pods run as when they do
+// not name one.
+const defaultServiceAccount = "default"
+
+// Flags and findings reported by the service_accounts tool, most severe
+// first.
+const (
+	// saUnneededToken means pods mount an API token of a service account
+	// that no RoleBinding or ClusterRoleBinding grants anything, so the
+	// token only widens what a compromised container can reach.
+	saUnneededToken = "automounted-token-unneeded"
+
+	// saDefault means pods run as their namespace's default service
+	// account instead of one of their own.
+	saDefault = "default-service-account"
+
+	// saLegacyToken means the service account has a long-lived token
+	// stored in a secret rather than only short-lived projected tokens.
+	saLegacyToken = "legacy-token-secret"
+
+	// saUnused means no active pod runs as the service account.
+	saUnused = "unused"
+)
+
+// saFindingOrder ranks the findings, most severe first.
+var saFindingOrder = map[string]int{saUnneededToken: 0, saDefault: 1, saLegacyToken: 2}
+
+// workloadIdentityAnnotations maps the annotations that bind a service
+// account to a cloud identity to the name of the provider.
+var workloadIdentityAnnotations = map[string]string{
+	"eks.amazonaws.com/role-arn":        "aws",
+	"iam.gke.io/gcp-service-account":    "gcp",
+	"azure.workload.identity/client-id": "azure",
+}
+
+// ServiceAccountUsage is one service account and the pods running as it.
+type ServiceAccountUsage struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// AutomountToken is the service account's automountServiceAccountToken
+	// setting; unset means tokens are mounted unless a pod opts out.
+	AutomountToken *bool `json:"automountToken,omitempty"`
+
+	// TokenSecrets are the long-lived token secrets of the service account.
+	TokenSecrets     []string `json:"tokenSecrets,omitempty"`
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// WorkloadIdentity is the cloud identity the service account is bound
+	// to, as provider:identity.
+	WorkloadIdentity string `json:"workloadIdentity,omitempty"`
+
+	// Bindings counts the RoleBindings and ClusterRoleBindings naming the
+	// service account; nil when they could not be listed.
+	Bindings *int `json:"bindings,omitempty"`
+
+	Pods              int `json:"pods"`
+	PodsMountingToken int `json:"podsMountingToken"`
+
+	// Workloads lists the first workloads running as the service account,
+	// sorted.
+	Workloads []string `json:"workloads,omitempty"`
+
+	Flags []string `json:"flags,omitempty"`
+}
+
+// ServiceAccountFinding is a flagged workload or service account.
+type ServiceAccountFinding struct {
+	Finding        string `json:"finding"`
+	Namespace      string `json:"namespace"`
+	ServiceAccount string `json:"serviceAccount"`
+	// Workload is empty for findings about the service account itself.
+	Workload string `json:"workload,omitempty"`
+	Pods     int    `json:"pods,omitempty"`
+	Detail   string `json:"detail"`
+}
+
+// ServiceAccountsSummary counts the findings of a scan.
+type ServiceAccountsSummary struct {
+	DefaultServiceAccountPods int `json:"defaultServiceAccountPods"`
+	TokenMountingPods         int `json:"tokenMountingPods"`
+	UnneededTokenPods         int `json:"unneededTokenPods"`
+	LegacyTokenSecrets        int `json:"legacyTokenSecrets"`
+	UnusedServiceAccounts     int `json:"unusedServiceAccounts"`
+}
+
+// ServiceAccountsOutput is the response of the service_accounts tool.
+type ServiceAccountsOutput struct {
+	PodsScanned int                    `json:"podsScanned"`
+	Summary     ServiceAccountsSummary `json:"summary"`
+
+	// ServiceAccounts are sorted by namespace and name.
+	ServiceAccounts          []ServiceAccountUsage `json:"serviceAccounts"`
+	TotalServiceAccounts     int                   `json:"totalServiceAccounts"`
+	ServiceAccountsTruncated bool                  `json:"serviceAccountsTruncated,omitempty"`
+
+	// Findings are sorted by severity, then by namespace and workload.
+	Findings          []ServiceAccountFinding `json:"findings"`
+	TotalFindings     int                     `json:"totalFindings"`
+	FindingsTruncated bool                    `json:"findingsTruncated,omitempty"`
+
+	// Unavailable lists the data that could not be read, such as secrets
+	// or bindings the user may not list.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// serviceAccountsRequest holds the validated service_accounts tool
+// arguments.
+type serviceAccountsRequest struct {
+	cluster     string
+	kubeContext string
+	namespace   string
+	limit       int
+}
+
+// serviceAccountState accumulates what a scan learns about one service
+// account.
+type serviceAccountState struct {
+	usage     ServiceAccountUsage
+	workloads map[string]int
+	// tokenWorkloads counts the pods of each workload mounting a token.
+	tokenWorkloads map[string]int
+}
+
+// handleServiceAccounts handles the service_accounts tool.
+func handleServiceAccounts(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseServiceAccountsRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	out, err := auditServiceAccounts(ctx, client.K8s(), req)
+	if err != nil {
+		return tools.K8sError("Failed to audit service accounts", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal service accounts: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseServiceAccountsRequest validates the tool arguments.
+func parseServiceAccountsRequest(args map[string]interface{}) (serviceAccountsRequest, string) {
+	req := serviceAccountsRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultServiceAccountsLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxServiceAccountsLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxServiceAccountsLimit)
+		}
+		req.limit = int(v)
+	}
+	return req, ""
+}
+
+// auditServiceAccounts lists the service accounts and active pods of a
+// namespace, or of every namespace, and flags risky token use. Token
+// secrets and RBAC bindings are optional: when they cannot be listed the
+// findings depending on them are skipped and the gap is reported.
+func auditServiceAccounts(ctx context.Context, client k8s.Client, req serviceAccountsRequest) (*ServiceAccountsOutput, error) {
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == ""}
+	out := &ServiceAccountsOutput{}
+
+	accounts := map[string]*serviceAccountState{}
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "serviceaccounts", "", opts, func(sa *corev1.ServiceAccount) {
+		state := &serviceAccountState{
+			usage: ServiceAccountUsage{
+				Namespace:      sa.Namespace,
+				Name:           sa.Name,
+				AutomountToken: sa.AutomountServiceAccountToken,
+			},
+			workloads:      map[string]int{},
+			tokenWorkloads: map[string]int{},
+		}
+		for _, ref := range sa.Secrets {
+			state.usage.TokenSecrets = append(state.usage.TokenSecrets, ref.Name)
+		}
+		for _, ref := range sa.ImagePullSecrets {
+			state.usage.ImagePullSecrets = append(state.usage.ImagePullSecrets, ref.Name)
+		}
+		for annotation, provider := range workloadIdentityAnnotations {
+			if identity := sa.Annotations[annotation]; identity != "" {
+				state.usage.WorkloadIdentity = provider + ":" + identity
+			}
+		}
+		accounts[sa.Namespace+"/"+sa.Name] = state
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+
+	podOpts := opts
+	podOpts.FieldSelector = activePodsFieldSelector
+	err = listAll(ctx, client, req.kubeContext, req.namespace, "pods", "", podOpts, func(pod *corev1.Pod) {
+		out.PodsScanned++
+		name := pod.Spec.ServiceAccountName
+		if name == "" {
+			name = defaultServiceAccount
+		}
+		state, ok := accounts[pod.Namespace+"/"+name]
+		if !ok {
+			// The service account was deleted after the pod started.
+			return
+		}
+		workload := podWorkload(pod)
+		state.usage.Pods++
+		state.workloads[workload]++
+		if mountsToken(pod, state.usage.AutomountToken) {
+			state.usage.PodsMountingToken++
+			state.tokenWorkloads[workload]++
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	secretOpts := opts
+	secretOpts.FieldSelector = "type=" + string(corev1.SecretTypeServiceAccountToken)
+	err = listAll(ctx, client, req.kubeContext, req.namespace, "secrets", "", secretOpts, func(secret *corev1.Secret) {
+		state, ok := accounts[secret.Namespace+"/"+secret.Annotations[corev1.ServiceAccountNameKey]]
+		if ok && !slices.Contains(state.usage.TokenSecrets, secret.Name) {
+			state.usage.TokenSecrets = append(state.usage.TokenSecrets, secret.Name)
+		}
+	})
+	secretsListed := err == nil
+	if err != nil {
+		out.Unavailable = append(out.Unavailable, fmt.Sprintf("secrets: %v", err))
+	}
+
+	bindings, err := serviceAccountBindings(ctx, client, req)
+	if err != nil {
+		out.Unavailable = append(out.Unavailable, fmt.Sprintf("bindings: %v", err))
+	}
+
+	var usages []ServiceAccountUsage
+	var findings []ServiceAccountFinding
+	for key, state := range accounts {
+		usage := &state.usage
+		if bindings != nil {
+			count := bindings[key]
+			usage.Bindings = &count
+		}
+		findings = append(findings, state.findings(secretsListed)...)
+		usage.Workloads = sortedKeys(state.workloads)
+		if len(usage.Workloads) > maxServiceAccountWorkloads {
+			usage.Workloads = usage.Workloads[:maxServiceAccountWorkloads]
+		}
+		sort.Strings(usage.TokenSecrets)
+
+		if usage.Name == defaultServiceAccount {
+			out.Summary.DefaultServiceAccountPods += usage.Pods
+		}
+		out.Summary.TokenMountingPods += usage.PodsMountingToken
+		if slices.Contains(usage.Flags, saUnneededToken) {
+			out.Summary.UnneededTokenPods += usage.PodsMountingToken
+		}
+		out.Summary.LegacyTokenSecrets += len(usage.TokenSecrets)
+		if slices.Contains(usage.Flags, saUnused) {
+			out.Summary.UnusedServiceAccounts++
+		}
+		usages = append(usages, *usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		return usages[i].Name < usages[j].Name
+	})
+	out.TotalServiceAccounts = len(usages)
+	if len(usages) > req.limit {
+		usages = usages[:req.limit]
+		out.ServiceAccountsTruncated = true
+	}
+	out.ServiceAccounts = append([]ServiceAccountUsage{}, usages...)
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Finding != b.Finding {
+			return saFindingOrder[a.Finding] < saFindingOrder[b.Finding]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.ServiceAccount != b.ServiceAccount {
+			return a.ServiceAccount < b.ServiceAccount
+		}
+		return a.Workload < b.Workload
+	})
+	out.TotalFindings = len(findings)
+	if len(findings) > req.limit {
+		findings = findings[:req.limit]
+		out.FindingsTruncated = true
+	}
+	out.Findings = append([]ServiceAccountFinding{}, findings...)
+
+	return out, nil
+}
+
+// findings flags the service account and the workloads running as it. The
+// legacy token finding is only reported when secrets could be listed, since
+// the service account's own secret references are incomplete without them.
+func (s *serviceAccountState) findings(secretsListed bool) []ServiceAccountFinding {
+	usage := &s.usage
+	var findings []ServiceAccountFinding
+
+	if usage.Bindings != nil && *usage.Bindings == 0 && usage.PodsMountingToken > 0 {
+		usage.Flags = append(usage.Flags, saUnneededToken)
+		for _, workload := range sortedKeys(s.tokenWorkloads) {
+			findings = append(findings, ServiceAccountFinding{
+				Finding:        saUnneededToken,
+				Namespace:      usage.Namespace,
+				ServiceAccount: usage.Name,
+				Workload:       workload,
+				Pods:           s.tokenWorkloads[workload],
+				Detail:         fmt.Sprintf("pods mount an API token of service account %s, which no RoleBinding or ClusterRoleBinding names; set automountServiceAccountToken: false", usage.Name),
+			})
+		}
+	}
+
+	if usage.Name == defaultServiceAccount && usage.Pods > 0 {
+		usage.Flags = append(usage.Flags, saDefault)
+		for _, workload := range sortedKeys(s.workloads) {
+			findings = append(findings, ServiceAccountFinding{
+				Finding:        saDefault,
+				Namespace:      usage.Namespace,
+				ServiceAccount: usage.Name,
+				Workload:       workload,
+				Pods:           s.workloads[workload],
+				Detail:         "pods run as the namespace's default service account; give the workload its own",
+			})
+		}
+	}
+
+	if secretsListed && len(usage.TokenSecrets) > 0 {
+		usage.Flags = append(usage.Flags, saLegacyToken)
+		findings = append(findings, ServiceAccountFinding{
+			Finding:        saLegacyToken,
+			Namespace:      usage.Namespace,
+			ServiceAccount: usage.Name,
+			Detail:         fmt.Sprintf("long-lived token secrets %s never expire; prefer projected tokens", strings.Join(usage.TokenSecrets, ", ")),
+		})
+	}
+
+	if usage.Pods == 0 && usage.Name != defaultServiceAccount {
+		usage.Flags = append(usage.Flags, saUnused)
+	}
+	return findings
+}
+
+// mountsToken reports whether a pod gets an API token mounted: its own
+// automountServiceAccountToken wins over its service account's, and tokens
+// are mounted when neither is set.
+func mountsToken(pod *corev1.Pod, serviceAccountAutomount *bool) bool {
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		return *pod.Spec.AutomountServiceAccountToken
+	}
+	if serviceAccountAutomount != nil {
+		return *serviceAccountAutomount
+	}
+	return true
+}
+
+// serviceAccountBindings counts, per namespace/name, the RoleBindings and
+// ClusterRoleBindings naming a service account directly or by its user
+// name. Bindings to groups of service accounts are not counted.
+func serviceAccountBindings(ctx context.Context, client k8s.Client, req serviceAccountsRequest) (map[string]int, error) {
+	counts := map[string]int{}
+	count := func(subjects []rbacv1.Subject) {
+		seen := map[string]bool{}
+		for _, subject := range subjects {
+			var key string
+			switch subject.Kind {
+			case rbacv1.ServiceAccountKind:
+				key = subject.Namespace + "/" + subject.Name
+			case rbacv1.UserKind:
+				rest, ok := strings.CutPrefix(subject.Name, "system:serviceaccount:")
+				if !ok {
+					continue
+				}
+				namespace, name, _ := strings.Cut(rest, ":")
+				key = namespace + "/" + name
+			default:
+				continue
+			}
+			if !seen[key] {
+				seen[key] = true
+				counts[key]++
+			}
+		}
+	}
+
+	opts := k8s.ListOptions{AllNamespaces: req.namespace == ""}
+	err := listAll(ctx, client, req.kubeContext, req.namespace, "rolebindings", rbacv1.GroupName, opts, func(b *rbacv1.RoleBinding) {
+		count(b.Subjects)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = listAll(ctx, client, req.kubeContext, "", "clusterrolebindings", rbacv1.GroupName, k8s.ListOptions{}, func(b *rbacv1.ClusterRoleBinding) {
+		count(b.Subjects)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// sortedKeys returns the keys of a map, sorted.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,157 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// serviceAccountPod returns a pod of a Deployment running as a service
+// account.
+func serviceAccountPod(name, deployment, serviceAccount string, automount *bool) *corev1.Pod {
+	pod := imagesPod(name, deployment, "app:1")
+	pod.Spec.ServiceAccountName = serviceAccount
+	pod.Spec.AutomountServiceAccountToken = automount
+	return pod
+}
+
+func newServiceAccountsClient() *mcptest.FakeClient {
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"serviceaccounts": {
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "default"}},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api", Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::1:role/api"}},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta:                   metav1.ObjectMeta{Namespace: "shop", Name: "worker"},
+				AutomountServiceAccountToken: ptr(false),
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "deployer"},
+				Secrets:    []corev1.ObjectReference{{Name: "deployer-token-abc"}},
+			},
+		},
+		"pods": {
+			serviceAccountPod("web-1", "web", "", nil),
+			serviceAccountPod("web-2", "web", "default", ptr(false)),
+			serviceAccountPod("api-1", "api", "api", nil),
+			serviceAccountPod("worker-1", "worker", "worker", nil),
+			serviceAccountPod("ghost-1", "ghost", "deleted", nil),
+		},
+		"secrets": {
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "deployer-token-abc", Annotations: map[string]string{corev1.ServiceAccountNameKey: "deployer"}},
+				Type:       corev1.SecretTypeServiceAccountToken,
+			},
+		},
+		"rolebindings": {
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "deployer"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: "shop", Name: "deployer"}},
+			},
+		},
+		"clusterrolebindings": {
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "system:serviceaccount:shop:worker"}},
+			},
+		},
+	})
+}
+
+func TestAuditServiceAccounts(t *testing.T) {
+	client := newServiceAccountsClient()
+	req, errMsg := parseServiceAccountsRequest(map[string]interface{}{"namespace": "shop"})
+	require.Empty(t, errMsg)
+
+	out, err := auditServiceAccounts(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, out.PodsScanned)
+	assert.Equal(t, activePodsFieldSelector, client.ListOpts["pods"][0].FieldSelector)
+	assert.Equal(t, "type=kubernetes.io/service-account-token", client.ListOpts["secrets"][0].FieldSelector)
+	assert.False(t, client.ListOpts["serviceaccounts"][0].AllNamespaces)
+	assert.Empty(t, out.Unavailable)
+
+	assert.Equal(t, ServiceAccountsSummary{
+		DefaultServiceAccountPods: 2,
+		TokenMountingPods:         2,
+		UnneededTokenPods:         2,
+		LegacyTokenSecrets:        1,
+		UnusedServiceAccounts:     1,
+	}, out.Summary)
+
+	require.Equal(t, 4, out.TotalServiceAccounts)
+	api := out.ServiceAccounts[0]
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, "aws:arn:aws:iam::1:role/api", api.WorkloadIdentity)
+	assert.Equal(t, []string{"Deployment/api"}, api.Workloads)
+	assert.Equal(t, []string{saUnneededToken}, api.Flags)
+
+	deployer := out.ServiceAccounts[2]
+	assert.Equal(t, "deployer", deployer.Name)
+	assert.Equal(t, []string{"deployer-token-abc"}, deployer.TokenSecrets, "secret references and token secrets are merged")
+	require.NotNil(t, deployer.Bindings)
+	assert.Equal(t, 1, *deployer.Bindings)
+	assert.Equal(t, []string{saLegacyToken, saUnused}, deployer.Flags)
+
+	worker := out.ServiceAccounts[3]
+	assert.Equal(t, 0, worker.PodsMountingToken, "the service account opts out of automounting")
+	assert.Empty(t, worker.Flags)
+
+	require.Equal(t, 4, out.TotalFindings)
+	assert.Equal(t, ServiceAccountFinding{
+		Finding:        saUnneededToken,
+		Namespace:      "shop",
+		ServiceAccount: "default",
+		Workload:       "Deployment/web",
+		Pods:           1,
+		Detail:         "pods mount an API token of service account default, which no RoleBinding or ClusterRoleBinding names; set automountServiceAccountToken: false",
+	}, out.Findings[1])
+	assert.Equal(t, saUnneededToken, out.Findings[0].Finding)
+	assert.Equal(t, "api", out.Findings[0].ServiceAccount)
+	assert.Equal(t, saDefault, out.Findings[2].Finding)
+	assert.Equal(t, 2, out.Findings[2].Pods)
+	assert.Equal(t, saLegacyToken, out.Findings[3].Finding)
+}
+
+func TestAuditServiceAccounts_Unavailable(t *testing.T) {
+	client := newServiceAccountsClient()
+	client.Errs = map[string]error{
+		"secrets":             errors.New("secrets is forbidden"),
+		"clusterrolebindings": errors.New("clusterrolebindings is forbidden"),
+	}
+	req, _ := parseServiceAccountsRequest(map[string]interface{}{"limit": float64(1)})
+
+	out, err := auditServiceAccounts(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"secrets: secrets is forbidden", "bindings: clusterrolebindings is forbidden"}, out.Unavailable)
+	assert.True(t, client.ListOpts["pods"][0].AllNamespaces)
+	assert.Equal(t, 1, out.TotalFindings, "only findings independent of secrets and bindings are reported")
+	assert.Equal(t, saDefault, out.Findings[0].Finding)
+	assert.True(t, out.ServiceAccountsTruncated)
+	assert.Nil(t, out.ServiceAccounts[0].Bindings)
+}
+
+func TestMountsToken(t *testing.T) {
+	pod := &corev1.Pod{}
+	assert.True(t, mountsToken(pod, nil))
+	assert.False(t, mountsToken(pod, ptr(false)))
+	pod.Spec.AutomountServiceAccountToken = ptr(true)
+	assert.True(t, mountsToken(pod, ptr(false)), "the pod setting wins")
+}
+
+func TestParseServiceAccountsRequest(t *testing.T) {
+	_, errMsg := parseServiceAccountsRequest(map[string]interface{}{"limit": float64(MaxServiceAccountsLimit + 1)})
+	assert.Contains(t, errMsg, "limit must be between")
+}
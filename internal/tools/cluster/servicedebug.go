@@ -0,0 +1,725 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// serviceDebugMaxPods caps the matching pods listed by service_debug.
+	serviceDebugMaxPods = 20
+
+	// serviceDebugMaxNotReady caps the not-ready endpoint pods listed.
+	serviceDebugMaxNotReady = 20
+
+	// gatewayAPIGroup is the API group of Gateway API routes.
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+)
+
+// ServiceDebugResult is the response of the service_debug tool.
+type ServiceDebugResult struct {
+	Service ServiceDebugService `json:"service"`
+	DNS     ServiceDNS          `json:"dns"`
+
+	// Healthy is true when no problems were found.
+	Healthy bool `json:"healthy"`
+
+	// Problems explain why traffic to the Service fails or is incomplete.
+	Problems []string `json:"problems,omitempty"`
+
+	// Warnings are findings that may, but need not, break traffic.
+	Warnings []string `json:"warnings,omitempty"`
+
+	Pods      ServiceDebugPods      `json:"pods"`
+	Endpoints ServiceDebugEndpoints `json:"endpoints"`
+	Ports     []ServiceDebugPort    `json:"ports"`
+	Routes    []ServiceDebugRoute   `json:"routes"`
+
+	// Unavailable lists the checks that could not run, such as route kinds
+	// the user may not list.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// ServiceDebugService summarises the Service.
+type ServiceDebugService struct {
+	Name                     string            `json:"name"`
+	Namespace                string            `json:"namespace"`
+	Type                     string            `json:"type"`
+	ClusterIP                string            `json:"clusterIP,omitempty"`
+	ExternalName             string            `json:"externalName,omitempty"`
+	Selector                 map[string]string `json:"selector,omitempty"`
+	PublishNotReadyAddresses bool              `json:"publishNotReadyAddresses,omitempty"`
+}
+
+// ServiceDNS describes what a DNS lookup of the Service returns.
+type ServiceDNS struct {
+	Name   string `json:"name"`
+	Answer string `json:"answer"`
+}
+
+// ServiceDebugPods describes the pods the Service selector matches.
+type ServiceDebugPods struct {
+	Selector  string            `json:"selector,omitempty"`
+	Matching  int               `json:"matching"`
+	Ready     int               `json:"ready"`
+	Pods      []ServiceDebugPod `json:"pods,omitempty"`
+	Truncated bool              `json:"truncated,omitempty"`
+
+	// LabelMatches counts the pods each selector label matches on its own.
+	// It is set when the whole selector matches no pod, to show which
+	// label is off.
+	LabelMatches map[string]int `json:"labelMatches,omitempty"`
+}
+
+// ServiceDebugPod is a pod matched by the Service selector.
+type ServiceDebugPod struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+	Phase string `json:"phase"`
+	IP    string `json:"ip,omitempty"`
+	Node  string `json:"node,omitempty"`
+}
+
+// ServiceDebugEndpoints summarises the EndpointSlices of the Service.
+type ServiceDebugEndpoints struct {
+	Slices      int `json:"slices"`
+	Ready       int `json:"ready"`
+	NotReady    int `json:"notReady"`
+	Terminating int `json:"terminating"`
+
+	// NotReadyPods names the pods behind endpoints that are not ready.
+	NotReadyPods []string `json:"notReadyPods,omitempty"`
+
+	// Ports are the ports published in the slices, as name:port/protocol.
+	Ports []string `json:"ports,omitempty"`
+}
+
+// ServiceDebugPort is a Service port and how its target port resolves on
+// the matching pods.
+type ServiceDebugPort struct {
+	Name       string `json:"name,omitempty"`
+	Port       int32  `json:"port"`
+	Protocol   string `json:"protocol"`
+	TargetPort string `json:"targetPort"`
+	NodePort   int32  `json:"nodePort,omitempty"`
+
+	// ContainerPorts are the container ports the target port resolves to.
+	ContainerPorts []int32 `json:"containerPorts,omitempty"`
+	Problem        string  `json:"problem,omitempty"`
+}
+
+// ServiceDebugRoute is an Ingress or Gateway API route sending traffic to
+// the Service.
+type ServiceDebugRoute struct {
+	Kind  string   `json:"kind"`
+	Name  string   `json:"name"`
+	Hosts []string `json:"hosts,omitempty"`
+	Paths []string `json:"paths,omitempty"`
+
+	// Ports are the Service ports the route's backends use.
+	Ports []string `json:"ports,omitempty"`
+
+	// Class is the Ingress class; Parents are the Gateways of a route.
+	Class     string   `json:"class,omitempty"`
+	Parents   []string `json:"parents,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+	Problem   string   `json:"problem,omitempty"`
+}
+
+// serviceDebug collects the findings of one service_debug call.
+type serviceDebug struct {
+	client      k8s.Client
+	kubeContext string
+	svc         *corev1.Service
+	pods        []corev1.Pod
+	out         *ServiceDebugResult
+}
+
+func (d *serviceDebug) problem(format string, args ...interface{}) {
+	d.out.Problems = append(d.out.Problems, fmt.Sprintf(format, args...))
+}
+
+func (d *serviceDebug) warning(format string, args ...interface{}) {
+	d.out.Warnings = append(d.out.Warnings, fmt.Sprintf(format, args...))
+}
+
+// handleServiceDebug reports why traffic to a Service might not arrive: what
+// its selector matches, the readiness of its EndpointSlices, target ports
+// that do not resolve, and the Ingresses and routes pointing at it.
+func handleServiceDebug(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+	kubeContext, _ := args["kubeContext"].(string)
+	namespace, _ := args["namespace"].(string)
+	service, _ := args["service"].(string)
+
+	if namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return toolerrors.InvalidArgumentf("invalid namespace %q: %s", namespace, strings.Join(errs, "; ")).Result(), nil
+	}
+	if service == "" {
+		return toolerrors.Required("service").Result(), nil
+	}
+	if errs := validation.IsDNS1035Label(service); len(errs) > 0 {
+		return toolerrors.InvalidArgumentf("invalid service %q: %s", service, strings.Join(errs, "; ")).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	resp, err := k8sClient.Get(ctx, kubeContext, namespace, "services", "", service)
+	if err != nil {
+		return tools.K8sError("Failed to get service", err, client.User()).Result(), nil
+	}
+	svc := &corev1.Service{}
+	if err := fromObject(resp.Resource, svc); err != nil {
+		return toolerrors.Internalf("Failed to decode service: %v", err).Result(), nil
+	}
+
+	d := &serviceDebug{
+		client:      k8sClient,
+		kubeContext: kubeContext,
+		svc:         svc,
+		out: &ServiceDebugResult{
+			Service: ServiceDebugService{
+				Name:                     svc.Name,
+				Namespace:                svc.Namespace,
+				Type:                     string(svc.Spec.Type),
+				ClusterIP:                svc.Spec.ClusterIP,
+				ExternalName:             svc.Spec.ExternalName,
+				Selector:                 svc.Spec.Selector,
+				PublishNotReadyAddresses: svc.Spec.PublishNotReadyAddresses,
+			},
+			Ports:  []ServiceDebugPort{},
+			Routes: []ServiceDebugRoute{},
+		},
+	}
+	if d.out.Service.Namespace == "" {
+		d.out.Service.Namespace = namespace
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		d.out.DNS = ServiceDNS{Name: serviceDNSName(d.out.Service), Answer: "CNAME to " + svc.Spec.ExternalName}
+		d.warning("ExternalName services have no selector or endpoints; traffic goes to %s", svc.Spec.ExternalName)
+	} else {
+		if err := d.checkPods(ctx); err != nil {
+			return tools.K8sError("Failed to list pods", err, client.User()).Result(), nil
+		}
+		if err := d.checkEndpoints(ctx); err != nil {
+			return tools.K8sError("Failed to list endpoint slices", err, client.User()).Result(), nil
+		}
+		d.checkPorts()
+		d.checkDNS()
+	}
+	d.checkIngresses(ctx)
+	d.checkGatewayRoutes(ctx)
+
+	d.out.Healthy = len(d.out.Problems) == 0
+
+	jsonData, err := json.MarshalIndent(d.out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal service debug result: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// checkPods lists the pods the selector matches. When it matches none, each
+// selector label is tried on its own to show which one is off.
+func (d *serviceDebug) checkPods(ctx context.Context) error {
+	selector := d.svc.Spec.Selector
+	if len(selector) == 0 {
+		return nil
+	}
+	out := &d.out.Pods
+	out.Selector = labels.SelectorFromSet(selector).String()
+
+	err := listAll(ctx, d.client, d.kubeContext, d.out.Service.Namespace, "pods", "", k8s.ListOptions{LabelSelector: out.Selector}, func(pod *corev1.Pod) {
+		d.pods = append(d.pods, *pod)
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(d.pods, func(i, j int) bool { return d.pods[i].Name < d.pods[j].Name })
+
+	for i := range d.pods {
+		pod := &d.pods[i]
+		ready := podReady(pod)
+		if ready {
+			out.Ready++
+		}
+		if len(out.Pods) < serviceDebugMaxPods {
+			out.Pods = append(out.Pods, ServiceDebugPod{
+				Name:  pod.Name,
+				Ready: ready,
+				Phase: string(pod.Status.Phase),
+				IP:    pod.Status.PodIP,
+				Node:  pod.Spec.NodeName,
+			})
+		}
+	}
+	out.Matching = len(d.pods)
+	out.Truncated = out.Matching > len(out.Pods)
+
+	switch {
+	case out.Matching == 0:
+		d.problem("selector %s matches no pods in namespace %s", out.Selector, d.out.Service.Namespace)
+		if len(selector) > 1 {
+			out.LabelMatches = map[string]int{}
+			for key, value := range selector {
+				label := key + "=" + value
+				count := 0
+				err := listAll(ctx, d.client, d.kubeContext, d.out.Service.Namespace, "pods", "", k8s.ListOptions{LabelSelector: label}, func(*corev1.Pod) {
+					count++
+				})
+				if err != nil {
+					return err
+				}
+				out.LabelMatches[label] = count
+			}
+		}
+	case out.Ready == 0:
+		d.problem("none of the %d pods matching the selector is ready; check their readiness probes and container states", out.Matching)
+	case out.Ready < out.Matching:
+		d.warning("%d of %d pods matching the selector are not ready", out.Matching-out.Ready, out.Matching)
+	}
+	return nil
+}
+
+// checkEndpoints summarises the EndpointSlices of the Service.
+func (d *serviceDebug) checkEndpoints(ctx context.Context) error {
+	out := &d.out.Endpoints
+	ports := map[string]bool{}
+	err := listAll(ctx, d.client, d.kubeContext, d.out.Service.Namespace, "endpointslices", "discovery.k8s.io", k8s.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + d.svc.Name,
+	}, func(slice *discoveryv1.EndpointSlice) {
+		out.Slices++
+		for _, p := range slice.Ports {
+			ports[endpointPortString(p)] = true
+		}
+		for _, ep := range slice.Endpoints {
+			switch {
+			case ep.Conditions.Ready == nil || *ep.Conditions.Ready:
+				out.Ready++
+			case ep.Conditions.Terminating != nil && *ep.Conditions.Terminating:
+				out.Terminating++
+			default:
+				out.NotReady++
+				if ep.TargetRef != nil && len(out.NotReadyPods) < serviceDebugMaxNotReady {
+					out.NotReadyPods = append(out.NotReadyPods, ep.TargetRef.Name)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for port := range ports {
+		out.Ports = append(out.Ports, port)
+	}
+	sort.Strings(out.Ports)
+	sort.Strings(out.NotReadyPods)
+
+	switch {
+	case len(d.svc.Spec.Selector) == 0 && out.Slices == 0:
+		d.problem("the service has no selector and no EndpointSlices; add a selector or create the EndpointSlices yourself")
+	case out.Ready == 0 && d.out.Pods.Ready > 0:
+		d.problem("%d pods are ready but the service has no ready endpoints; the target ports may not resolve on the pods", d.out.Pods.Ready)
+	case out.Ready == 0 && len(d.svc.Spec.Selector) == 0:
+		d.problem("the service has no ready endpoints")
+	}
+	return nil
+}
+
+// endpointPortString formats an EndpointSlice port as name:port/protocol.
+func endpointPortString(p discoveryv1.EndpointPort) string {
+	var s string
+	if p.Name != nil {
+		s = *p.Name
+	}
+	if p.Port != nil {
+		s += ":" + strconv.Itoa(int(*p.Port))
+	}
+	if p.Protocol != nil {
+		s += "/" + string(*p.Protocol)
+	}
+	return s
+}
+
+// checkPorts resolves each Service target port on the matching pods.
+func (d *serviceDebug) checkPorts() {
+	for _, sp := range d.svc.Spec.Ports {
+		protocol := sp.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		target := sp.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt32(sp.Port)
+		}
+		out := ServiceDebugPort{
+			Name:       sp.Name,
+			Port:       sp.Port,
+			Protocol:   string(protocol),
+			TargetPort: target.String(),
+			NodePort:   sp.NodePort,
+		}
+		portRef := servicePortRef(sp)
+
+		if len(d.pods) > 0 {
+			if target.Type == intstr.String {
+				d.resolveNamedPort(&out, target.StrVal, protocol)
+			} else {
+				d.resolveNumberedPort(&out, target.IntVal, protocol)
+			}
+		}
+		if out.Problem != "" {
+			if target.Type == intstr.String {
+				d.problem("port %s: %s", portRef, out.Problem)
+			} else {
+				d.warning("port %s: %s", portRef, out.Problem)
+			}
+		}
+		d.out.Ports = append(d.out.Ports, out)
+	}
+}
+
+// resolveNamedPort looks up a named target port in the pods' containers.
+// Pods without the name get no endpoint for the port.
+func (d *serviceDebug) resolveNamedPort(out *ServiceDebugPort, name string, protocol corev1.Protocol) {
+	missing := 0
+	var wrongProtocol []string
+	for i := range d.pods {
+		found := false
+		for _, c := range d.pods[i].Spec.Containers {
+			for _, cp := range c.Ports {
+				if cp.Name != name {
+					continue
+				}
+				cpProtocol := cp.Protocol
+				if cpProtocol == "" {
+					cpProtocol = corev1.ProtocolTCP
+				}
+				if cpProtocol != protocol {
+					wrongProtocol = append(wrongProtocol, string(cpProtocol))
+					continue
+				}
+				found = true
+				if !slices.Contains(out.ContainerPorts, cp.ContainerPort) {
+					out.ContainerPorts = append(out.ContainerPorts, cp.ContainerPort)
+				}
+			}
+		}
+		if !found {
+			missing++
+		}
+	}
+	slices.Sort(out.ContainerPorts)
+	switch {
+	case missing == 0:
+	case len(wrongProtocol) > 0:
+		out.Problem = fmt.Sprintf("targetPort %q is a %s port on the pods, but the service port is %s", name, wrongProtocol[0], protocol)
+	default:
+		out.Problem = fmt.Sprintf("targetPort %q is not a named container port on %d of %d matching pods, which get no endpoint for this port", name, missing, len(d.pods))
+	}
+}
+
+// resolveNumberedPort checks that the pods declare a numbered target port.
+// Undeclared ports still receive traffic, so a missing declaration is only
+// suspicious when the pods declare other ports.
+func (d *serviceDebug) resolveNumberedPort(out *ServiceDebugPort, port int32, protocol corev1.Protocol) {
+	var declared []int32
+	for i := range d.pods {
+		for _, c := range d.pods[i].Spec.Containers {
+			for _, cp := range c.Ports {
+				cpProtocol := cp.Protocol
+				if cpProtocol == "" {
+					cpProtocol = corev1.ProtocolTCP
+				}
+				if cp.ContainerPort == port && cpProtocol == protocol {
+					out.ContainerPorts = []int32{port}
+					return
+				}
+				if !slices.Contains(declared, cp.ContainerPort) {
+					declared = append(declared, cp.ContainerPort)
+				}
+			}
+		}
+	}
+	if len(declared) > 0 {
+		slices.Sort(declared)
+		ports := make([]string, 0, len(declared))
+		for _, p := range declared {
+			ports = append(ports, strconv.Itoa(int(p)))
+		}
+		out.Problem = fmt.Sprintf("targetPort %d/%s is not declared by the matching pods, which declare %s; check that the application listens on %d", port, protocol, strings.Join(ports, ", "), port)
+	}
+}
+
+// servicePortRef names a Service port for messages.
+func servicePortRef(sp corev1.ServicePort) string {
+	if sp.Name != "" {
+		return fmt.Sprintf("%s (%d)", sp.Name, sp.Port)
+	}
+	return strconv.Itoa(int(sp.Port))
+}
+
+// checkDNS describes the DNS answer for the Service name.
+func (d *serviceDebug) checkDNS() {
+	d.out.DNS.Name = serviceDNSName(d.out.Service)
+	if d.svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		d.out.DNS.Answer = "the cluster IP " + d.svc.Spec.ClusterIP
+		return
+	}
+
+	addresses := d.out.Endpoints.Ready
+	if d.svc.Spec.PublishNotReadyAddresses {
+		addresses += d.out.Endpoints.NotReady
+	}
+	d.out.DNS.Answer = fmt.Sprintf("headless: one record per endpoint address (%d)", addresses)
+	if addresses == 0 {
+		d.problem("the service is headless and has no ready endpoints, so its DNS name does not resolve")
+	}
+}
+
+// serviceDNSName returns the in-cluster DNS name of a Service.
+func serviceDNSName(svc ServiceDebugService) string {
+	return fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace)
+}
+
+// servicePortMatches reports whether a backend port, given by name or
+// number, is a port of the Service.
+func (d *serviceDebug) servicePortMatches(name string, number int32) bool {
+	for _, sp := range d.svc.Spec.Ports {
+		if (name != "" && sp.Name == name) || (number != 0 && sp.Port == number) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIngresses finds the Ingresses in the namespace with a backend on the
+// Service.
+func (d *serviceDebug) checkIngresses(ctx context.Context) {
+	err := listAll(ctx, d.client, d.kubeContext, d.out.Service.Namespace, "ingresses", "networking.k8s.io", k8s.ListOptions{}, func(ing *networkingv1.Ingress) {
+		route := ServiceDebugRoute{Kind: "Ingress", Name: ing.Name}
+		if ing.Spec.IngressClassName != nil {
+			route.Class = *ing.Spec.IngressClassName
+		}
+		var badPorts []string
+		addBackend := func(backend *networkingv1.IngressBackend, host, path string) {
+			if backend == nil || backend.Service == nil || backend.Service.Name != d.svc.Name {
+				return
+			}
+			port := backend.Service.Port.Name
+			if port == "" {
+				port = strconv.Itoa(int(backend.Service.Port.Number))
+			}
+			if !slices.Contains(route.Ports, port) {
+				route.Ports = append(route.Ports, port)
+				if !d.servicePortMatches(backend.Service.Port.Name, backend.Service.Port.Number) {
+					badPorts = append(badPorts, port)
+				}
+			}
+			if !slices.Contains(route.Hosts, host) {
+				route.Hosts = append(route.Hosts, host)
+			}
+			if path != "" && !slices.Contains(route.Paths, path) {
+				route.Paths = append(route.Paths, path)
+			}
+		}
+
+		addBackend(ing.Spec.DefaultBackend, "*", "")
+		for _, rule := range ing.Spec.Rules {
+			host := rule.Host
+			if host == "" {
+				host = "*"
+			}
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, p := range rule.HTTP.Paths {
+				addBackend(&p.Backend, host, p.Path)
+			}
+		}
+		if len(route.Ports) == 0 {
+			return
+		}
+
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			if lb.IP != "" {
+				route.Addresses = append(route.Addresses, lb.IP)
+			} else if lb.Hostname != "" {
+				route.Addresses = append(route.Addresses, lb.Hostname)
+			}
+		}
+		if len(badPorts) > 0 {
+			route.Problem = fmt.Sprintf("backend port %s is not a port of the service", strings.Join(badPorts, ", "))
+			d.problem("Ingress %s: %s", ing.Name, route.Problem)
+		} else if len(route.Addresses) == 0 {
+			d.warning("Ingress %s has no load balancer address yet; check that an ingress controller serves class %q", ing.Name, route.Class)
+		}
+		d.out.Routes = append(d.out.Routes, route)
+	})
+	if err != nil && !errors.Is(err, k8s.ErrUnknownResourceType) {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("ingresses: %v", err))
+	}
+}
+
+// gatewayRoute holds the fields of a Gateway API HTTPRoute or GRPCRoute that
+// service_debug reads. Routes are read without the Gateway API types, which
+// this module does not depend on.
+type gatewayRoute struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		ParentRefs []gatewayRef `json:"parentRefs"`
+		Hostnames  []string     `json:"hostnames"`
+		Rules      []struct {
+			BackendRefs []gatewayRef `json:"backendRefs"`
+			Matches     []struct {
+				Path *struct {
+					Type  string `json:"type"`
+					Value string `json:"value"`
+				} `json:"path"`
+			} `json:"matches"`
+		} `json:"rules"`
+	} `json:"spec"`
+	Status struct {
+		Parents []struct {
+			ParentRef  gatewayRef         `json:"parentRef"`
+			Conditions []metav1.Condition `json:"conditions"`
+		} `json:"parents"`
+	} `json:"status"`
+}
+
+// gatewayRef is a Gateway API parent or backend reference.
+type gatewayRef struct {
+	Group       *string `json:"group"`
+	Kind        *string `json:"kind"`
+	Name        string  `json:"name"`
+	Namespace   *string `json:"namespace"`
+	SectionName *string `json:"sectionName"`
+	Port        *int32  `json:"port"`
+	Weight      *int32  `json:"weight"`
+}
+
+// isService reports whether a backend reference points at the named
+// Service in namespace.
+func (r gatewayRef) isService(name, namespace string) bool {
+	return (r.Group == nil || *r.Group == "") &&
+		(r.Kind == nil || *r.Kind == "Service") &&
+		r.Name == name &&
+		(r.Namespace == nil || *r.Namespace == namespace)
+}
+
+// checkGatewayRoutes finds the HTTPRoutes and GRPCRoutes in the namespace
+// with a backend on the Service. Clusters without the Gateway API are skipped.
+func (d *serviceDebug) checkGatewayRoutes(ctx context.Context) {
+	for _, kind := range []struct{ name, resource string }{
+		{"HTTPRoute", "httproutes"},
+		{"GRPCRoute", "grpcroutes"},
+	} {
+		err := listAll(ctx, d.client, d.kubeContext, d.out.Service.Namespace, kind.resource, gatewayAPIGroup, k8s.ListOptions{}, func(r *gatewayRoute) {
+			if route, ok := d.gatewayRoute(kind.name, r); ok {
+				d.out.Routes = append(d.out.Routes, route)
+			}
+		})
+		if err != nil && !errors.Is(err, k8s.ErrUnknownResourceType) {
+			d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("%s: %v", kind.resource, err))
+		}
+	}
+}
+
+func (d *serviceDebug) gatewayRoute(kind string, r *gatewayRoute) (ServiceDebugRoute, bool) {
+	route := ServiceDebugRoute{Kind: kind, Name: r.Metadata.Name, Hosts: r.Spec.Hostnames}
+	var problems []string
+	for _, rule := range r.Spec.Rules {
+		matched := false
+		for _, backend := range rule.BackendRefs {
+			if !backend.isService(d.svc.Name, d.out.Service.Namespace) {
+				continue
+			}
+			matched = true
+			port := "unset"
+			if backend.Port != nil {
+				port = strconv.Itoa(int(*backend.Port))
+			}
+			if slices.Contains(route.Ports, port) {
+				continue
+			}
+			route.Ports = append(route.Ports, port)
+			if backend.Port == nil || !d.servicePortMatches("", *backend.Port) {
+				problems = append(problems, fmt.Sprintf("backend port %s is not a port of the service", port))
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, m := range rule.Matches {
+			if m.Path != nil && !slices.Contains(route.Paths, m.Path.Value) {
+				route.Paths = append(route.Paths, m.Path.Value)
+			}
+		}
+	}
+	if len(route.Ports) == 0 {
+		return route, false
+	}
+
+	for _, parent := range r.Spec.ParentRefs {
+		route.Parents = append(route.Parents, parent.Name)
+	}
+	problems = append(problems, r.conditionProblems()...)
+	if len(problems) > 0 {
+		route.Problem = strings.Join(problems, "; ")
+		d.problem("%s %s: %s", kind, route.Name, route.Problem)
+	}
+	return route, true
+}
+
+// conditionProblems reports the gateways that have not accepted the route
+// or could not resolve its backends.
+func (r *gatewayRoute) conditionProblems() []string {
+	var problems []string
+	for _, parent := range r.Status.Parents {
+		for _, cond := range parent.Conditions {
+			if (cond.Type == "Accepted" || cond.Type == "ResolvedRefs") && cond.Status == metav1.ConditionFalse {
+				problems = append(problems, fmt.Sprintf("%s is %s for gateway %s (%s): %s", cond.Type, cond.Status, parent.ParentRef.Name, cond.Reason, cond.Message))
+			}
+		}
+	}
+	return problems
+}
+
+// podReady reports whether the pod's Ready condition is true.
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func debugPod(name string, ready bool, ports ...corev1.ContainerPort) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "checkout", "tier": "web"}},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []corev1.Container{{Name: "app", Ports: ports}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+// debugSlice returns an EndpointSlice of the checkout Service.
+func debugSlice(slice *discoveryv1.EndpointSlice) *discoveryv1.EndpointSlice {
+	slice.Namespace = "shop"
+	slice.Labels = map[string]string{discoveryv1.LabelServiceName: "checkout"}
+	return slice
+}
+
+// newServiceDebugClient returns a client serving the checkout Service, its
+// pods and EndpointSlice, and no Ingresses. The Gateway API kinds are
+// unknown.
+func newServiceDebugClient() *mcptest.FakeClient {
+	ready := true
+	notReady := false
+	portName := "http"
+	port := int32(8080)
+	slice := debugSlice(&discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-abc"},
+		Ports:      []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+		Endpoints: []discoveryv1.Endpoint{
+			{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			{
+				Conditions: discoveryv1.EndpointConditions{Ready: &notReady},
+				TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "checkout-2"},
+			},
+		},
+	})
+	return mcptest.NewStrictFakeClient(map[string][]runtime.Object{
+		"services": {&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.96.0.42",
+				Selector:  map[string]string{"app": "checkout", "tier": "web"},
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				},
+			},
+		}},
+		"pods": {
+			debugPod("checkout-1", true, corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+			debugPod("checkout-2", false, corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+		},
+		"endpointslices": {slice},
+		"ingresses":      {},
+	})
+}
+
+// debugService returns the Service client serves.
+func debugService(client *mcptest.FakeClient) *corev1.Service {
+	return client.Objects["services"][0].(*corev1.Service)
+}
+
+func callServiceDebug(t *testing.T, client *mcptest.FakeClient, args map[string]interface{}) (*mcp.CallToolResult, ServiceDebugResult) {
+	t.Helper()
+	result := callTool(t, client, "service_debug", args)
+	var out ServiceDebugResult
+	if !result.IsError {
+		out = mcptest.DecodeResult[ServiceDebugResult](t, result)
+	}
+	return result, out
+}
+
+func serviceDebugArgs() map[string]interface{} {
+	return map[string]interface{}{"namespace": "shop", "service": "checkout"}
+}
+
+func TestServiceDebug_Healthy(t *testing.T) {
+	result, out := callServiceDebug(t, newServiceDebugClient(), serviceDebugArgs())
+	require.False(t, result.IsError)
+
+	assert.True(t, out.Healthy)
+	assert.Empty(t, out.Problems)
+	assert.Equal(t, []string{"1 of 2 pods matching the selector are not ready"}, out.Warnings)
+
+	assert.Equal(t, "app=checkout,tier=web", out.Pods.Selector)
+	assert.Equal(t, 2, out.Pods.Matching)
+	assert.Equal(t, 1, out.Pods.Ready)
+	assert.Len(t, out.Pods.Pods, 2)
+
+	assert.Equal(t, 1, out.Endpoints.Slices)
+	assert.Equal(t, 1, out.Endpoints.Ready)
+	assert.Equal(t, 1, out.Endpoints.NotReady)
+	assert.Equal(t, []string{"checkout-2"}, out.Endpoints.NotReadyPods)
+	assert.Equal(t, []string{"http:8080"}, out.Endpoints.Ports)
+
+	require.Len(t, out.Ports, 1)
+	assert.Equal(t, "http", out.Ports[0].TargetPort)
+	assert.Equal(t, []int32{8080}, out.Ports[0].ContainerPorts)
+	assert.Empty(t, out.Ports[0].Problem)
+
+	assert.Equal(t, ServiceDNS{Name: "checkout.shop.svc", Answer: "the cluster IP 10.96.0.42"}, out.DNS)
+	assert.Empty(t, out.Routes)
+	assert.Empty(t, out.Unavailable, "missing Gateway API kinds are skipped")
+}
+
+func TestServiceDebug_SelectorMatchesNothing(t *testing.T) {
+	client := newServiceDebugClient()
+	debugService(client).Spec.Selector = map[string]string{"app": "checkout", "tier": "frontend"}
+	client.Objects["endpointslices"] = nil
+
+	_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+	assert.False(t, out.Healthy)
+	assert.Equal(t, 0, out.Pods.Matching)
+	assert.Equal(t, map[string]int{"app=checkout": 2, "tier=frontend": 0}, out.Pods.LabelMatches)
+	assert.Contains(t, out.Problems, "selector app=checkout,tier=frontend matches no pods in namespace shop")
+}
+
+func TestServiceDebug_TargetPorts(t *testing.T) {
+	t.Run("named port missing on pods", func(t *testing.T) {
+		client := newServiceDebugClient()
+		debugService(client).Spec.Ports[0].TargetPort = intstr.FromString("web")
+
+		_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+		assert.False(t, out.Healthy)
+		assert.Contains(t, out.Ports[0].Problem, `targetPort "web" is not a named container port on 2 of 2 matching pods`)
+		require.NotEmpty(t, out.Problems)
+		assert.Contains(t, out.Problems[0], "port http (80)")
+	})
+
+	t.Run("named port with the wrong protocol", func(t *testing.T) {
+		client := newServiceDebugClient()
+		for _, pod := range client.Objects["pods"] {
+			pod.(*corev1.Pod).Spec.Containers[0].Ports[0].Protocol = corev1.ProtocolUDP
+		}
+
+		_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+		assert.False(t, out.Healthy)
+		assert.Equal(t, `targetPort "http" is a UDP port on the pods, but the service port is TCP`, out.Ports[0].Problem)
+	})
+
+	t.Run("undeclared numbered port is a warning", func(t *testing.T) {
+		client := newServiceDebugClient()
+		debugService(client).Spec.Ports[0].TargetPort = intstr.FromInt32(9000)
+
+		_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+		assert.True(t, out.Healthy)
+		assert.Contains(t, out.Ports[0].Problem, "targetPort 9000/TCP is not declared by the matching pods, which declare 8080")
+		assert.Contains(t, out.Warnings, "port http (80): "+out.Ports[0].Problem)
+	})
+
+	t.Run("unset target port defaults to the port", func(t *testing.T) {
+		client := newServiceDebugClient()
+		debugService(client).Spec.Ports[0].TargetPort = intstr.IntOrString{}
+		debugService(client).Spec.Ports[0].Port = 8080
+
+		_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+		assert.Equal(t, "8080", out.Ports[0].TargetPort)
+		assert.Equal(t, []int32{8080}, out.Ports[0].ContainerPorts)
+		assert.Empty(t, out.Ports[0].Problem)
+	})
+}
+
+func TestServiceDebug_NoReadyEndpoints(t *testing.T) {
+	client := newServiceDebugClient()
+	notReady := false
+	client.Objects["endpointslices"] = []runtime.Object{debugSlice(&discoveryv1.EndpointSlice{
+		Endpoints: []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &notReady}}},
+	})}
+	debugService(client).Spec.ClusterIP = corev1.ClusterIPNone
+
+	_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+	assert.False(t, out.Healthy)
+	assert.Contains(t, out.Problems, "1 pods are ready but the service has no ready endpoints; the target ports may not resolve on the pods")
+	assert.Contains(t, out.Problems, "the service is headless and has no ready endpoints, so its DNS name does not resolve")
+	assert.Equal(t, "headless: one record per endpoint address (0)", out.DNS.Answer)
+}
+
+func TestServiceDebug_ExternalName(t *testing.T) {
+	client := newServiceDebugClient()
+	debugService(client).Spec = corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "db.example.com"}
+
+	_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+	assert.True(t, out.Healthy)
+	assert.Equal(t, "CNAME to db.example.com", out.DNS.Answer)
+	assert.Equal(t, 0, out.Endpoints.Slices)
+	assert.Empty(t, out.Ports)
+}
+
+func TestServiceDebug_Ingresses(t *testing.T) {
+	client := newServiceDebugClient()
+	className := "nginx"
+	backend := func(service string, port networkingv1.ServiceBackendPort) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: service, Port: port}}
+	}
+	ingress := func(name string, backends ...networkingv1.IngressBackend) runtime.Object {
+		var paths []networkingv1.HTTPIngressPath
+		for _, b := range backends {
+			paths = append(paths, networkingv1.HTTPIngressPath{Path: "/" + b.Service.Name, Backend: b})
+		}
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &className,
+				Rules: []networkingv1.IngressRule{{
+					Host:             "shop.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths}},
+				}},
+			},
+		}
+	}
+	client.Objects["ingresses"] = []runtime.Object{
+		ingress("good", backend("checkout", networkingv1.ServiceBackendPort{Name: "http"})),
+		ingress("wrong-port", backend("checkout", networkingv1.ServiceBackendPort{Number: 8080})),
+		ingress("other", backend("cart", networkingv1.ServiceBackendPort{Number: 80})),
+	}
+
+	_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+	require.Len(t, out.Routes, 2)
+	assert.Equal(t, ServiceDebugRoute{
+		Kind:  "Ingress",
+		Name:  "good",
+		Hosts: []string{"shop.example.com"},
+		Paths: []string{"/checkout"},
+		Ports: []string{"http"},
+		Class: "nginx",
+	}, out.Routes[0])
+	assert.Equal(t, "backend port 8080 is not a port of the service", out.Routes[1].Problem)
+	assert.Contains(t, out.Problems, "Ingress wrong-port: backend port 8080 is not a port of the service")
+	assert.Contains(t, out.Warnings, `Ingress good has no load balancer address yet; check that an ingress controller serves class "nginx"`)
+}
+
+func TestServiceDebug_GatewayRoutes(t *testing.T) {
+	client := newServiceDebugClient()
+	route := func(name string, port int64, conditions ...interface{}) runtime.Object {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "shop"},
+			"spec": map[string]interface{}{
+				"parentRefs": []interface{}{map[string]interface{}{"name": "public"}},
+				"hostnames":  []interface{}{"shop.example.com"},
+				"rules": []interface{}{map[string]interface{}{
+					"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/checkout"}}},
+					"backendRefs": []interface{}{map[string]interface{}{"name": "checkout", "port": port}},
+				}},
+			},
+			"status": map[string]interface{}{
+				"parents": []interface{}{map[string]interface{}{
+					"parentRef":  map[string]interface{}{"name": "public"},
+					"conditions": conditions,
+				}},
+			},
+		}}
+	}
+	condition := func(conditionType, status, reason string) interface{} {
+		return map[string]interface{}{
+			"type": conditionType, "status": status, "reason": reason, "message": "",
+			"lastTransitionTime": "2024-01-01T00:00:00Z",
+		}
+	}
+	client.Objects["httproutes"] = []runtime.Object{
+		route("good", 80, condition("Accepted", "True", "Accepted")),
+		route("rejected", 80, condition("Accepted", "False", "NotAllowedByListeners")),
+		route("wrong-port", 8080),
+	}
+	client.Objects["grpcroutes"] = []runtime.Object{}
+
+	_, out := callServiceDebug(t, client, serviceDebugArgs())
+
+	require.Len(t, out.Routes, 3)
+	assert.Equal(t, ServiceDebugRoute{
+		Kind:    "HTTPRoute",
+		Name:    "good",
+		Hosts:   []string{"shop.example.com"},
+		Paths:   []string{"/checkout"},
+		Ports:   []string{"80"},
+		Parents: []string{"public"},
+	}, out.Routes[0])
+	assert.Contains(t, out.Routes[1].Problem, "Accepted is False for gateway public (NotAllowedByListeners)")
+	assert.Equal(t, "backend port 8080 is not a port of the service", out.Routes[2].Problem)
+	assert.False(t, out.Healthy)
+}
+
+func TestServiceDebug_Errors(t *testing.T) {
+	t.Run("missing arguments", func(t *testing.T) {
+		result, _ := callServiceDebug(t, newServiceDebugClient(), map[string]interface{}{"namespace": "shop"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "service is required")
+	})
+
+	t.Run("invalid service name", func(t *testing.T) {
+		result, _ := callServiceDebug(t, newServiceDebugClient(), map[string]interface{}{"namespace": "shop", "service": "Checkout"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "invalid service")
+	})
+
+	t.Run("service not found", func(t *testing.T) {
+		result, _ := callServiceDebug(t, newServiceDebugClient(), map[string]interface{}{"namespace": "shop", "service": "cart"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "Failed to get service")
+	})
+
+	t.Run("route listing denied", func(t *testing.T) {
+		client := newServiceDebugClient()
+		delete(client.Objects, "ingresses")
+		client.Errs["httproutes"] = errors.New("forbidden")
+
+		_, out := callServiceDebug(t, client, serviceDebugArgs())
+		require.Len(t, out.Unavailable, 1)
+		assert.Contains(t, out.Unavailable[0], "httproutes: forbidden")
+	})
+}
@@ -0,0 +1,644 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// storageDebugMaxPods caps the pods using a claim whose events are read.
+	storageDebugMaxPods = 5
+
+	// storageDebugMaxEvents caps the events listed per claim.
+	storageDebugMaxEvents = 10
+
+	// storageDebugProvisionGrace is how long a claim may wait for an
+	// external provisioner before the wait is reported.
+	storageDebugProvisionGrace = 5 * time.Minute
+
+	// defaultStorageClassAnnotation marks the cluster's default StorageClass.
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// storageEventReasons are the event reasons that explain a stuck volume.
+// FailedScheduling only counts when its message is about volumes.
+var storageEventReasons = map[string]bool{
+	"ProvisioningFailed":    true,
+	"ExternalProvisioning":  true,
+	"FailedBinding":         true,
+	"VolumeResizeFailed":    true,
+	"FailedAttachVolume":    true,
+	"FailedMount":           true,
+	"FailedMapVolume":       true,
+	"FailedScheduling":      true,
+	"ProvisioningSucceeded": true,
+}
+
+// StorageDebugResult is the response of the storage_debug tool.
+type StorageDebugResult struct {
+	Namespace string `json:"namespace"`
+	// Pod is set when the claims were found through the pod mounting them.
+	Pod    string           `json:"pod,omitempty"`
+	Claims []ClaimDiagnosis `json:"claims"`
+
+	// Unavailable lists the data that could not be read, such as
+	// cluster-scoped objects the user may not get.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// ClaimDiagnosis is what storage_debug found about one claim.
+type ClaimDiagnosis struct {
+	Claim        ClaimSummary         `json:"claim"`
+	StorageClass *StorageClassSummary `json:"storageClass,omitempty"`
+	Volume       *VolumeSummary       `json:"volume,omitempty"`
+	Attachments  []AttachmentSummary  `json:"attachments,omitempty"`
+	Pods         []StoragePod         `json:"pods,omitempty"`
+	Events       []StorageEvent       `json:"events,omitempty"`
+
+	// Healthy is true when no problems were found.
+	Healthy bool `json:"healthy"`
+
+	// Problems explain why the volume is pending or stuck.
+	Problems []string `json:"problems,omitempty"`
+
+	// Warnings are findings that may explain a delay, but need not.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ClaimSummary summarises a PersistentVolumeClaim.
+type ClaimSummary struct {
+	Name         string   `json:"name"`
+	Phase        string   `json:"phase"`
+	StorageClass string   `json:"storageClass,omitempty"`
+	Requested    string   `json:"requested,omitempty"`
+	Capacity     string   `json:"capacity,omitempty"`
+	AccessModes  []string `json:"accessModes,omitempty"`
+	VolumeName   string   `json:"volumeName,omitempty"`
+	VolumeMode   string   `json:"volumeMode,omitempty"`
+	Conditions   []string `json:"conditions,omitempty"`
+}
+
+// StorageClassSummary summarises the StorageClass of a claim.
+type StorageClassSummary struct {
+	Name                 string `json:"name"`
+	Provisioner          string `json:"provisioner"`
+	VolumeBindingMode    string `json:"volumeBindingMode,omitempty"`
+	ReclaimPolicy        string `json:"reclaimPolicy,omitempty"`
+	AllowVolumeExpansion bool   `json:"allowVolumeExpansion,omitempty"`
+
+	// Default is true when the claim names no class and gets the default.
+	Default bool `json:"default,omitempty"`
+}
+
+// VolumeSummary summarises the PersistentVolume bound to a claim.
+type VolumeSummary struct {
+	Name          string `json:"name"`
+	Phase         string `json:"phase"`
+	Capacity      string `json:"capacity,omitempty"`
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+	ClaimRef      string `json:"claimRef,omitempty"`
+	Driver        string `json:"driver,omitempty"`
+	VolumeHandle  string `json:"volumeHandle,omitempty"`
+	Message       string `json:"message,omitempty"`
+
+	// NodeAffinity lists the node terms the volume is restricted to, e.g.
+	// a zone.
+	NodeAffinity []string `json:"nodeAffinity,omitempty"`
+}
+
+// AttachmentSummary summarises a VolumeAttachment of the claim's volume.
+type AttachmentSummary struct {
+	Name        string `json:"name"`
+	Node        string `json:"node"`
+	Attacher    string `json:"attacher"`
+	Attached    bool   `json:"attached"`
+	AttachError string `json:"attachError,omitempty"`
+	DetachError string `json:"detachError,omitempty"`
+}
+
+// StoragePod is a pod using the claim.
+type StoragePod struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	Node  string `json:"node,omitempty"`
+}
+
+// StorageEvent is a recent event about the claim or a pod using it, newest
+// first.
+type StorageEvent struct {
+	Object   string `json:"object"`
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen string `json:"lastSeen,omitempty"`
+}
+
+// storageDebug collects the findings of one storage_debug call.
+type storageDebug struct {
+	client      k8s.Client
+	kubeContext string
+	namespace   string
+	now         time.Time
+	out         *StorageDebugResult
+
+	// pods are the pods of the namespace, listed once.
+	pods []corev1.Pod
+}
+
+func (d *storageDebug) unavailable(format string, args ...interface{}) {
+	d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf(format, args...))
+}
+
+// handleStorageDebug explains why a PersistentVolumeClaim is Pending or its
+// volume is stuck, by correlating the claim with its StorageClass,
+// PersistentVolume, VolumeAttachments, the pods using it and their events.
+func handleStorageDebug(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+	kubeContext, _ := args["kubeContext"].(string)
+	namespace, _ := args["namespace"].(string)
+	claim, _ := args["claim"].(string)
+	pod, _ := args["pod"].(string)
+
+	if namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return toolerrors.InvalidArgumentf("invalid namespace %q: %s", namespace, strings.Join(errs, "; ")).Result(), nil
+	}
+	if (claim == "") == (pod == "") {
+		return toolerrors.InvalidArgument("exactly one of claim or pod is required").Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	d := &storageDebug{
+		client:      client.K8s(),
+		kubeContext: kubeContext,
+		namespace:   namespace,
+		now:         time.Now(),
+		out:         &StorageDebugResult{Namespace: namespace, Pod: pod, Claims: []ClaimDiagnosis{}},
+	}
+
+	claims := []string{claim}
+	if pod != "" {
+		resp, err := d.client.Get(ctx, kubeContext, namespace, "pods", "", pod)
+		if err != nil {
+			return tools.K8sError("Failed to get pod", err, client.User()).Result(), nil
+		}
+		p := &corev1.Pod{}
+		if err := fromObject(resp.Resource, p); err != nil {
+			return toolerrors.Internalf("Failed to decode pod: %v", err).Result(), nil
+		}
+		claims = podClaims(p)
+		if len(claims) == 0 {
+			return toolerrors.InvalidArgumentf("pod %s mounts no PersistentVolumeClaims", pod).Result(), nil
+		}
+	}
+
+	if err := listAll(ctx, d.client, kubeContext, namespace, "pods", "", k8s.ListOptions{}, func(p *corev1.Pod) {
+		d.pods = append(d.pods, *p)
+	}); err != nil {
+		d.unavailable("pods: %v", err)
+	}
+
+	for _, name := range claims {
+		resp, err := d.client.Get(ctx, kubeContext, namespace, "persistentvolumeclaims", "", name)
+		if err != nil {
+			if pod != "" && apierrors.IsNotFound(err) {
+				d.out.Claims = append(d.out.Claims, ClaimDiagnosis{
+					Claim:    ClaimSummary{Name: name, Phase: "Missing"},
+					Problems: []string{fmt.Sprintf("pod %s mounts claim %s, which does not exist", pod, name)},
+				})
+				continue
+			}
+			return tools.K8sError("Failed to get persistent volume claim", err, client.User()).Result(), nil
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := fromObject(resp.Resource, pvc); err != nil {
+			return toolerrors.Internalf("Failed to decode persistent volume claim: %v", err).Result(), nil
+		}
+		d.out.Claims = append(d.out.Claims, d.diagnose(ctx, pvc))
+	}
+
+	jsonData, err := json.MarshalIndent(d.out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal storage debug result: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// podClaims returns the claims a pod mounts, in volume order.
+func podClaims(pod *corev1.Pod) []string {
+	var claims []string
+	for _, v := range pod.Spec.Volumes {
+		switch {
+		case v.PersistentVolumeClaim != nil:
+			claims = append(claims, v.PersistentVolumeClaim.ClaimName)
+		case v.Ephemeral != nil:
+			// Generic ephemeral volumes get a claim named <pod>-<volume>.
+			claims = append(claims, pod.Name+"-"+v.Name)
+		}
+	}
+	return claims
+}
+
+// diagnose correlates one claim with its class, volume, attachments, pods
+// and events.
+func (d *storageDebug) diagnose(ctx context.Context, pvc *corev1.PersistentVolumeClaim) ClaimDiagnosis {
+	diag := ClaimDiagnosis{Claim: claimSummary(pvc)}
+	problem := func(format string, args ...interface{}) {
+		diag.Problems = append(diag.Problems, fmt.Sprintf(format, args...))
+	}
+	warning := func(format string, args ...interface{}) {
+		diag.Warnings = append(diag.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	pods := d.podsUsing(pvc.Name)
+	for _, p := range pods {
+		diag.Pods = append(diag.Pods, StoragePod{Name: p.Name, Phase: string(p.Status.Phase), Node: p.Spec.NodeName})
+	}
+
+	class := d.storageClass(ctx, pvc, &diag)
+	diag.StorageClass = class
+
+	var volume *corev1.PersistentVolume
+	if pvc.Spec.VolumeName != "" {
+		volume = d.volume(ctx, pvc.Spec.VolumeName, &diag)
+	}
+	if volume != nil {
+		diag.Volume = volumeSummary(volume)
+		diag.Attachments = d.attachments(ctx, volume.Name, &diag)
+	}
+
+	diag.Events = d.claimEvents(ctx, pvc, pods)
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimPending:
+		switch {
+		case pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName == "":
+			problem("claim sets storageClassName to \"\", so it only binds to a pre-created PersistentVolume and none matches its size, access modes and selector")
+		case pvc.Spec.StorageClassName == nil && class == nil && pvc.Spec.VolumeName == "":
+			problem("claim names no StorageClass and the cluster has no default StorageClass, so nothing provisions a volume for it")
+		case class != nil && class.VolumeBindingMode == string(storagev1.VolumeBindingWaitForFirstConsumer) && len(pods) == 0:
+			warning("StorageClass %s binds on first use (WaitForFirstConsumer); the claim stays Pending until a pod using it is scheduled", class.Name)
+		}
+	case corev1.ClaimLost:
+		problem("claim lost its PersistentVolume %s; the volume was deleted or bound elsewhere", pvc.Spec.VolumeName)
+	}
+
+	if volume != nil {
+		if ref := volume.Spec.ClaimRef; ref != nil && (ref.Namespace != pvc.Namespace || ref.Name != pvc.Name) {
+			problem("PersistentVolume %s is bound to claim %s/%s, not to this claim", volume.Name, ref.Namespace, ref.Name)
+		}
+		switch volume.Status.Phase {
+		case corev1.VolumeFailed:
+			problem("PersistentVolume %s failed: %s", volume.Name, volume.Status.Message)
+		case corev1.VolumeReleased:
+			problem("PersistentVolume %s is Released; its previous claim was deleted and it is not reused until its claimRef is cleared", volume.Name)
+		}
+	}
+
+	for _, a := range diag.Attachments {
+		if a.AttachError != "" {
+			problem("attaching to node %s fails: %s", a.Node, a.AttachError)
+		}
+		if a.DetachError != "" {
+			problem("detaching from node %s fails: %s", a.Node, a.DetachError)
+		}
+	}
+
+	for _, c := range pvc.Status.Conditions {
+		if c.Type == corev1.PersistentVolumeClaimFileSystemResizePending && c.Status == corev1.ConditionTrue {
+			warning("the filesystem resize waits for a pod using the claim to start on a node")
+		}
+	}
+
+	d.eventFindings(pvc, diag.Events, problem, warning)
+	diag.Healthy = len(diag.Problems) == 0
+	return diag
+}
+
+// eventFindings turns the warning events about a claim and its pods into
+// problems, with the provisioner's own error message.
+func (d *storageDebug) eventFindings(pvc *corev1.PersistentVolumeClaim, events []StorageEvent, problem, warning func(string, ...interface{})) {
+	seen := map[string]bool{}
+	provisioned := false
+	waiting := ""
+	for _, ev := range events {
+		if ev.Reason == "ProvisioningSucceeded" {
+			provisioned = true
+		}
+		if ev.Type != corev1.EventTypeWarning {
+			if ev.Reason == "ExternalProvisioning" && waiting == "" {
+				waiting = ev.Message
+			}
+			continue
+		}
+		// Events repeat; report each reason once, from the newest event.
+		key := ev.Object + "/" + ev.Reason
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch ev.Reason {
+		case "ProvisioningFailed":
+			problem("provisioning failed: %s", ev.Message)
+		case "FailedBinding":
+			problem("binding failed: %s", ev.Message)
+		case "VolumeResizeFailed":
+			problem("resize failed: %s", ev.Message)
+		case "FailedAttachVolume":
+			if strings.Contains(ev.Message, "Multi-Attach") {
+				problem("%s: the volume is still attached to another node; it can only attach to one node at a time (ReadWriteOnce)", ev.Object)
+			} else {
+				problem("%s: attach failed: %s", ev.Object, ev.Message)
+			}
+		case "FailedMount", "FailedMapVolume":
+			problem("%s: mount failed: %s", ev.Object, ev.Message)
+		case "FailedScheduling":
+			problem("%s: cannot be scheduled: %s", ev.Object, ev.Message)
+		}
+	}
+	if pvc.Status.Phase == corev1.ClaimPending && waiting != "" && !provisioned &&
+		d.now.Sub(pvc.CreationTimestamp.Time) > storageDebugProvisionGrace {
+		warning("still waiting for the external provisioner after %s (%s); check that the CSI driver's controller is running", d.now.Sub(pvc.CreationTimestamp.Time).Round(time.Minute), waiting)
+	}
+}
+
+func claimSummary(pvc *corev1.PersistentVolumeClaim) ClaimSummary {
+	out := ClaimSummary{
+		Name:       pvc.Name,
+		Phase:      string(pvc.Status.Phase),
+		VolumeName: pvc.Spec.VolumeName,
+	}
+	if pvc.Spec.StorageClassName != nil {
+		out.StorageClass = *pvc.Spec.StorageClassName
+	}
+	if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		out.Requested = q.String()
+	}
+	if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		out.Capacity = q.String()
+	}
+	for _, m := range pvc.Spec.AccessModes {
+		out.AccessModes = append(out.AccessModes, string(m))
+	}
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode != corev1.PersistentVolumeFilesystem {
+		out.VolumeMode = string(*pvc.Spec.VolumeMode)
+	}
+	for _, c := range pvc.Status.Conditions {
+		if c.Status == corev1.ConditionTrue {
+			out.Conditions = append(out.Conditions, string(c.Type))
+		}
+	}
+	return out
+}
+
+// podsUsing returns the pods of the namespace that mount the claim.
+func (d *storageDebug) podsUsing(claim string) []corev1.Pod {
+	var pods []corev1.Pod
+	for i := range d.pods {
+		for _, name := range podClaims(&d.pods[i]) {
+			if name == claim {
+				pods = append(pods, d.pods[i])
+				break
+			}
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods
+}
+
+// storageClass returns the class of the claim, or the default class when
+// it names none. It returns nil when there is no such class.
+func (d *storageDebug) storageClass(ctx context.Context, pvc *corev1.PersistentVolumeClaim, diag *ClaimDiagnosis) *StorageClassSummary {
+	if pvc.Spec.StorageClassName != nil {
+		name := *pvc.Spec.StorageClassName
+		if name == "" {
+			return nil
+		}
+		resp, err := d.client.Get(ctx, d.kubeContext, "", "storageclasses", "storage.k8s.io", name)
+		if apierrors.IsNotFound(err) {
+			diag.Problems = append(diag.Problems, fmt.Sprintf("StorageClass %s does not exist", name))
+			return nil
+		}
+		if err != nil {
+			d.unavailable("storageclass %s: %v", name, err)
+			return nil
+		}
+		class := &storagev1.StorageClass{}
+		if err := fromObject(resp.Resource, class); err != nil {
+			d.unavailable("storageclass %s: %v", name, err)
+			return nil
+		}
+		return storageClassSummary(class)
+	}
+
+	var defaults []*storagev1.StorageClass
+	err := listAll(ctx, d.client, d.kubeContext, "", "storageclasses", "storage.k8s.io", k8s.ListOptions{}, func(class *storagev1.StorageClass) {
+		if class.Annotations[defaultStorageClassAnnotation] == "true" {
+			defaults = append(defaults, class)
+		}
+	})
+	if err != nil {
+		d.unavailable("storageclasses: %v", err)
+		return nil
+	}
+	if len(defaults) == 0 {
+		return nil
+	}
+	// With several defaults, the newest one wins.
+	sort.Slice(defaults, func(i, j int) bool {
+		return defaults[i].CreationTimestamp.After(defaults[j].CreationTimestamp.Time)
+	})
+	summary := storageClassSummary(defaults[0])
+	summary.Default = true
+	return summary
+}
+
+func storageClassSummary(class *storagev1.StorageClass) *StorageClassSummary {
+	out := &StorageClassSummary{Name: class.Name, Provisioner: class.Provisioner}
+	if class.VolumeBindingMode != nil {
+		out.VolumeBindingMode = string(*class.VolumeBindingMode)
+	}
+	if class.ReclaimPolicy != nil {
+		out.ReclaimPolicy = string(*class.ReclaimPolicy)
+	}
+	if class.AllowVolumeExpansion != nil {
+		out.AllowVolumeExpansion = *class.AllowVolumeExpansion
+	}
+	return out
+}
+
+// volume returns the named PersistentVolume, or nil when it cannot be read.
+func (d *storageDebug) volume(ctx context.Context, name string, diag *ClaimDiagnosis) *corev1.PersistentVolume {
+	resp, err := d.client.Get(ctx, d.kubeContext, "", "persistentvolumes", "", name)
+	if apierrors.IsNotFound(err) {
+		diag.Problems = append(diag.Problems, fmt.Sprintf("PersistentVolume %s does not exist", name))
+		diag.Volume = &VolumeSummary{Name: name, Phase: "Missing"}
+		return nil
+	}
+	if err != nil {
+		d.unavailable("persistentvolume %s: %v", name, err)
+		return nil
+	}
+	volume := &corev1.PersistentVolume{}
+	if err := fromObject(resp.Resource, volume); err != nil {
+		d.unavailable("persistentvolume %s: %v", name, err)
+		return nil
+	}
+	return volume
+}
+
+func volumeSummary(pv *corev1.PersistentVolume) *VolumeSummary {
+	out := &VolumeSummary{
+		Name:          pv.Name,
+		Phase:         string(pv.Status.Phase),
+		ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+		Message:       pv.Status.Message,
+	}
+	if q, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		out.Capacity = q.String()
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		out.ClaimRef = ref.Namespace + "/" + ref.Name
+	}
+	if csi := pv.Spec.CSI; csi != nil {
+		out.Driver = csi.Driver
+		out.VolumeHandle = csi.VolumeHandle
+	}
+	if affinity := pv.Spec.NodeAffinity; affinity != nil && affinity.Required != nil {
+		for _, term := range affinity.Required.NodeSelectorTerms {
+			var parts []string
+			for _, expr := range term.MatchExpressions {
+				parts = append(parts, fmt.Sprintf("%s %s %s", expr.Key, strings.ToLower(string(expr.Operator)), strings.Join(expr.Values, ",")))
+			}
+			if len(parts) > 0 {
+				out.NodeAffinity = append(out.NodeAffinity, strings.Join(parts, " and "))
+			}
+		}
+	}
+	return out
+}
+
+// attachments returns the VolumeAttachments of a volume.
+func (d *storageDebug) attachments(ctx context.Context, volume string, diag *ClaimDiagnosis) []AttachmentSummary {
+	var out []AttachmentSummary
+	err := listAll(ctx, d.client, d.kubeContext, "", "volumeattachments", "storage.k8s.io", k8s.ListOptions{}, func(va *storagev1.VolumeAttachment) {
+		if va.Spec.Source.PersistentVolumeName == nil || *va.Spec.Source.PersistentVolumeName != volume {
+			return
+		}
+		a := AttachmentSummary{Name: va.Name, Node: va.Spec.NodeName, Attacher: va.Spec.Attacher, Attached: va.Status.Attached}
+		if e := va.Status.AttachError; e != nil {
+			a.AttachError = e.Message
+		}
+		if e := va.Status.DetachError; e != nil {
+			a.DetachError = e.Message
+		}
+		out = append(out, a)
+	})
+	if err != nil {
+		d.unavailable("volumeattachments: %v", err)
+		return nil
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Node < out[j].Node })
+	return out
+}
+
+// claimEvents returns the storage events about the claim and the first
+// pods using it, newest first.
+func (d *storageDebug) claimEvents(ctx context.Context, pvc *corev1.PersistentVolumeClaim, pods []corev1.Pod) []StorageEvent {
+	type object struct{ kind, name string }
+	objects := []object{{"PersistentVolumeClaim", pvc.Name}}
+	for i := range pods {
+		if i == storageDebugMaxPods {
+			break
+		}
+		objects = append(objects, object{"Pod", pods[i].Name})
+	}
+
+	var events []corev1.Event
+	for _, o := range objects {
+		selector := fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", o.kind),
+			fields.OneTermEqualSelector("involvedObject.name", o.name),
+		).String()
+		err := listAll(ctx, d.client, d.kubeContext, d.namespace, "events", "", k8s.ListOptions{FieldSelector: selector}, func(ev *corev1.Event) {
+			if !storageEventReasons[ev.Reason] || (ev.Reason == "FailedScheduling" && !volumeSchedulingMessage(ev.Message)) {
+				return
+			}
+			if o.kind == "Pod" && !aboutClaim(ev.Message, pvc) {
+				return
+			}
+			events = append(events, *ev)
+		})
+		if err != nil {
+			d.unavailable("events of %s %s: %v", strings.ToLower(o.kind), o.name, err)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).After(eventTime(events[j]))
+	})
+	if len(events) > storageDebugMaxEvents {
+		events = events[:storageDebugMaxEvents]
+	}
+	out := make([]StorageEvent, 0, len(events))
+	for _, ev := range events {
+		e := StorageEvent{
+			Object:  strings.ToLower(ev.InvolvedObject.Kind) + "/" + ev.InvolvedObject.Name,
+			Type:    ev.Type,
+			Reason:  ev.Reason,
+			Message: ev.Message,
+			Count:   ev.Count,
+		}
+		if t := eventTime(ev); !t.IsZero() {
+			e.LastSeen = t.UTC().Format(time.RFC3339)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// volumeSchedulingMessage reports whether a FailedScheduling message is
+// about volumes, e.g. an unbound claim or a volume node affinity conflict.
+func volumeSchedulingMessage(message string) bool {
+	lower := strings.ToLower(message)
+	return strings.Contains(lower, "volume") || strings.Contains(lower, "persistentvolumeclaim")
+}
+
+// aboutClaim reports whether a pod event message is about the claim. Mount
+// and attach messages quote the volume they are about; messages that quote
+// no volume are taken to be about all of the pod's claims.
+func aboutClaim(message string, pvc *corev1.PersistentVolumeClaim) bool {
+	if !strings.Contains(message, `volume "`) {
+		return true
+	}
+	for _, name := range []string{pvc.Name, pvc.Spec.VolumeName} {
+		if name != "" && strings.Contains(message, `"`+name+`"`) {
+			return true
+		}
+	}
+	return false
+}
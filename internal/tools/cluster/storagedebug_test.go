@@ -0,0 +1,199 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+func storageEvent(kind, name, eventType, reason, message string, minutesAgo int) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: name + "." + reason},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: "shop", Name: name},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.Time{Time: time.Now().Add(-time.Duration(minutesAgo) * time.Minute)},
+	}
+}
+
+func storageClaim(name string, class *string, phase corev1.PersistentVolumeClaimPhase, volume string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, CreationTimestamp: metav1.Time{Time: time.Now().Add(-time.Hour)}},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: class,
+			VolumeName:       volume,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func newStorageDebugClient() *mcptest.FakeClient {
+	fast := "fast"
+	immediate := storagev1.VolumeBindingImmediate
+	waitForConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	pvName := "pv-data"
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db-0"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-b",
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+				{Name: "cache", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cache"}}},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"persistentvolumeclaims": {
+			storageClaim("data", &fast, corev1.ClaimBound, pvName),
+			storageClaim("cache", nil, corev1.ClaimPending, ""),
+		},
+		"storageclasses": {
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast"}, Provisioner: "ebs.csi.aws.com", VolumeBindingMode: &immediate},
+			&storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{defaultStorageClassAnnotation: "true"}},
+				Provisioner:       "ebs.csi.aws.com",
+				VolumeBindingMode: &waitForConsumer,
+			},
+		},
+		"persistentvolumes": {
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvName},
+				Spec: corev1.PersistentVolumeSpec{
+					Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+					ClaimRef:                      &corev1.ObjectReference{Namespace: "shop", Name: "data"},
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-123"},
+					},
+				},
+				Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+			},
+		},
+		"volumeattachments": {
+			&storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi-abc"},
+				Spec: storagev1.VolumeAttachmentSpec{
+					Attacher: "ebs.csi.aws.com",
+					NodeName: "node-b",
+					Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+				},
+				Status: storagev1.VolumeAttachmentStatus{AttachError: &storagev1.VolumeError{Message: "volume is in use by another instance"}},
+			},
+		},
+		"pods": {pod},
+		"events": {
+			storageEvent("Pod", "db-0", corev1.EventTypeWarning, "FailedAttachVolume", `Multi-Attach error for volume "pv-data" Volume is already exclusively attached to one node`, 2),
+			storageEvent("Pod", "db-0", corev1.EventTypeWarning, "FailedScheduling", "0/3 nodes are available: 3 Insufficient cpu.", 3),
+			storageEvent("PersistentVolumeClaim", "cache", corev1.EventTypeWarning, "ProvisioningFailed", "failed to provision volume: quota exceeded", 1),
+			storageEvent("PersistentVolumeClaim", "cache", corev1.EventTypeWarning, "ProvisioningFailed", "failed to provision volume: older error", 10),
+		},
+	})
+}
+
+func TestStorageDebug_Pod(t *testing.T) {
+	out := mcptest.DecodeResult[StorageDebugResult](t, callTool(t, newStorageDebugClient(), "storage_debug", map[string]interface{}{"namespace": "shop", "pod": "db-0"}))
+	require.Len(t, out.Claims, 2)
+	assert.Empty(t, out.Unavailable)
+
+	data := out.Claims[0]
+	assert.Equal(t, "data", data.Claim.Name)
+	assert.Equal(t, "10Gi", data.Claim.Requested)
+	assert.Equal(t, []string{"ReadWriteOnce"}, data.Claim.AccessModes)
+	require.NotNil(t, data.StorageClass)
+	assert.Equal(t, "fast", data.StorageClass.Name)
+	assert.False(t, data.StorageClass.Default)
+	require.NotNil(t, data.Volume)
+	assert.Equal(t, "vol-123", data.Volume.VolumeHandle)
+	assert.Equal(t, "shop/data", data.Volume.ClaimRef)
+	require.Len(t, data.Attachments, 1)
+	assert.Equal(t, []StoragePod{{Name: "db-0", Phase: "Pending", Node: "node-b"}}, data.Pods)
+
+	require.Len(t, data.Events, 1, "scheduling failures not about volumes are left out")
+	assert.Equal(t, "pod/db-0", data.Events[0].Object)
+	assert.False(t, data.Healthy)
+	require.Len(t, data.Problems, 2)
+	assert.Contains(t, data.Problems[0], "attaching to node node-b fails: volume is in use")
+	assert.Contains(t, data.Problems[1], "still attached to another node")
+
+	cache := out.Claims[1]
+	require.NotNil(t, cache.StorageClass)
+	assert.Equal(t, "standard", cache.StorageClass.Name)
+	assert.True(t, cache.StorageClass.Default)
+	assert.Nil(t, cache.Volume)
+	require.Len(t, cache.Events, 2, "the attach failure quotes the other claim's volume")
+	assert.Equal(t, "persistentvolumeclaim/cache", cache.Events[0].Object, "newest first")
+	assert.Equal(t, []string{"provisioning failed: failed to provision volume: quota exceeded"}, cache.Problems, "each reason is reported once, from the newest event")
+	assert.Empty(t, cache.Warnings, "a pod uses the claim, so WaitForFirstConsumer does not hold it back")
+}
+
+func TestStorageDebug_PendingClaims(t *testing.T) {
+	client := newStorageDebugClient()
+	client.Objects["pods"] = nil
+	client.Objects["events"] = nil
+
+	out := mcptest.DecodeResult[StorageDebugResult](t, callTool(t, client, "storage_debug", map[string]interface{}{"namespace": "shop", "claim": "cache"}))
+	require.Len(t, out.Claims, 1)
+	assert.True(t, out.Claims[0].Healthy)
+	require.Len(t, out.Claims[0].Warnings, 1)
+	assert.Contains(t, out.Claims[0].Warnings[0], "WaitForFirstConsumer")
+
+	client.Objects["storageclasses"] = client.Objects["storageclasses"][:1]
+	out = mcptest.DecodeResult[StorageDebugResult](t, callTool(t, client, "storage_debug", map[string]interface{}{"namespace": "shop", "claim": "cache"}))
+	assert.Nil(t, out.Claims[0].StorageClass)
+	require.Len(t, out.Claims[0].Problems, 1)
+	assert.Contains(t, out.Claims[0].Problems[0], "no default StorageClass")
+
+	missing := "gold"
+	client.Objects["persistentvolumeclaims"] = []runtime.Object{storageClaim("cache", &missing, corev1.ClaimPending, "")}
+	out = mcptest.DecodeResult[StorageDebugResult](t, callTool(t, client, "storage_debug", map[string]interface{}{"namespace": "shop", "claim": "cache"}))
+	assert.Equal(t, []string{"StorageClass gold does not exist"}, out.Claims[0].Problems)
+}
+
+func TestStorageDebug_Unavailable(t *testing.T) {
+	client := newStorageDebugClient()
+	client.Errs = map[string]error{"persistentvolumes": mcptest.Forbidden("persistentvolumes"), "volumeattachments": mcptest.Forbidden("volumeattachments")}
+
+	out := mcptest.DecodeResult[StorageDebugResult](t, callTool(t, client, "storage_debug", map[string]interface{}{"namespace": "shop", "claim": "data"}))
+	require.Len(t, out.Claims, 1)
+	assert.Nil(t, out.Claims[0].Volume)
+	assert.Nil(t, out.Claims[0].Attachments)
+	require.Len(t, out.Unavailable, 1, "attachments are not listed without the volume")
+	assert.Contains(t, out.Unavailable[0], "persistentvolume pv-data")
+}
+
+func TestStorageDebug_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "missing namespace", args: map[string]interface{}{"claim": "data"}, wantErr: "namespace"},
+		{name: "neither claim nor pod", args: map[string]interface{}{"namespace": "shop"}, wantErr: "exactly one of claim or pod"},
+		{name: "both claim and pod", args: map[string]interface{}{"namespace": "shop", "claim": "data", "pod": "db-0"}, wantErr: "exactly one of claim or pod"},
+		{name: "pod without claims", args: map[string]interface{}{"namespace": "shop", "pod": "web"}, wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, newStorageDebugClient(), "storage_debug", tt.args)
+			require.True(t, result.IsError)
+			assert.Contains(t, mcptest.ResultText(result), tt.wantErr)
+		})
+	}
+}
@@ -43,6 +43,33 @@ func RegisterClusterTools(s *mcpserver.MCPServer, sc *server.ServerContext) erro
 	s.AddTool(apiResourcesTool, tools.WrapWithAuditLogging("api_resources", handleGetAPIResources, sc))
 	tools.MaybeAddDeprecatedAlias(s, sc, "api_resources", handleGetAPIResources, apiResourcesOpts...)
 
+	// crds tool
+	crdsOpts := []mcp.ToolOption{
+		mcp.WithDescription("List the installed CustomResourceDefinitions with their group, kind, scope, served and storage versions, stored versions and Established/NamesAccepted conditions. CRDs with conditions that are not true, or objects stored in a version no longer served, are reported as problems. Set compareWith to diff the CRDs of two clusters instead: CRDs missing on either side, and differences in versions, scope, conditions and schemas (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	crdsOpts = append(crdsOpts, clusterContextParams...)
+	crdsOpts = append(crdsOpts,
+		mcp.WithString("group",
+			mcp.Description("Only include CRDs of this API group and its subgroups, e.g. 'cert-manager.io' (optional)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxCRDsLimit),
+			mcp.Description("Maximum number of CRDs to return, or of entries per list when comparing. Default: 100. Maximum: 1000."),
+		),
+	)
+	if sc.FederationEnabled() {
+		crdsOpts = append(crdsOpts,
+			mcp.WithString("compareWith",
+				mcp.Description("Workload cluster to compare the CRDs of cluster (or the current cluster) with (optional)"),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("crds", crdsOpts...), tools.WrapWithAuditLogging("crds", handleCRDs, sc))
+
 	// cluster_health tool
 	clusterHealthOpts := []mcp.ToolOption{
 		mcp.WithDescription("Check the health status of cluster components. Returns overall status, component health, and a node list (capped by nodesLimit). Per-node conditions are omitted by default; set includeNodeConditions=true to include them."),
@@ -66,5 +93,701 @@ func RegisterClusterTools(s *mcpserver.MCPServer, sc *server.ServerContext) erro
 	s.AddTool(clusterHealthTool, tools.WrapWithAuditLogging("cluster_health", handleGetClusterHealth, sc))
 	tools.MaybeAddDeprecatedAlias(s, sc, "cluster_health", handleGetClusterHealth, clusterHealthOpts...)
 
+	// cluster_overview tool
+	clusterOverviewOpts := []mcp.ToolOption{
+		mcp.WithDescription("Get a one-shot snapshot of a cluster, the first call to make when asked how a cluster is doing: API server version and health, node count and readiness, namespace count, pods by phase with the failing ones (crash looping, image pull errors, failed), pending PersistentVolumeClaims, recent warning events by reason, the share of allocatable CPU and memory requested, and the namespaces and pods requesting the most CPU. Follow up with capacity, diagnose_pod or diagnose_node for details."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	clusterOverviewOpts = append(clusterOverviewOpts, clusterContextParams...)
+	clusterOverviewOpts = append(clusterOverviewOpts,
+		mcp.WithNumber("sinceMinutes",
+			mcp.Min(1),
+			mcp.Max(MaxOverviewSinceMinutes),
+			mcp.Description("Window for warning events, in minutes. Default: 60. Maximum: 1440."),
+		),
+		mcp.WithNumber("top",
+			mcp.Min(1),
+			mcp.Max(MaxOverviewTop),
+			mcp.Description("Number of top namespaces, pods and event reasons to return. Default: 5. Maximum: 50."),
+		),
+	)
+	s.AddTool(mcp.NewTool("cluster_overview", clusterOverviewOpts...), tools.WrapWithAuditLogging("cluster_overview", handleClusterOverview, sc))
+
+	// capacity tool
+	capacityOpts := []mcp.ToolOption{
+		mcp.WithDescription("Summarise CPU and memory capacity: node allocatable versus the requests and limits of active pods, with headroom per node and usage per namespace. Answers questions like 'is there room for 4 more replicas of 2 CPU / 4Gi' without listing nodes and pods. Nodes are sorted busiest first, namespaces by CPU requests. Set clusters to compare several workload clusters (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	capacityOpts = append(capacityOpts, clusterContextParams...)
+	capacityOpts = append(capacityOpts,
+		mcp.WithString("nodeSelector",
+			mcp.Description("Label selector limiting the nodes analysed, e.g. a node pool (optional)"),
+		),
+		mcp.WithNumber("nodesLimit",
+			mcp.Min(1),
+			mcp.Max(MaxCapacityNodesLimit),
+			mcp.Description("Maximum number of nodes to include. Default: 20. Maximum: 1000. The summary always covers all nodes."),
+		),
+		mcp.WithNumber("namespacesLimit",
+			mcp.Min(1),
+			mcp.Max(MaxCapacityNamespacesLimit),
+			mcp.Description("Maximum number of namespaces to include. Default: 20. Maximum: 1000."),
+		),
+	)
+	if sc.FederationEnabled() {
+		capacityOpts = append(capacityOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to analyse in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	capacityTool := mcp.NewTool("capacity", capacityOpts...)
+
+	s.AddTool(capacityTool, tools.WrapWithAuditLogging("capacity", handleCapacity, sc))
+
+	// deprecated_apis tool
+	deprecatedAPIsOpts := []mcp.ToolOption{
+		mcp.WithDescription("Find resources written with deprecated or removed Kubernetes API versions, such as extensions/v1beta1 Ingresses or policy/v1beta1 PodDisruptionBudgets, relative to the cluster's Kubernetes version. Returns a migration report with the replacement API for each kind. The API version used comes from the kubectl apply annotation and the managed fields; kinds without a replacement, like PodSecurityPolicy, are reported while the cluster still serves them. Set targetVersion before an upgrade to report only what that version removes. Set clusters to scan several workload clusters (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	deprecatedAPIsOpts = append(deprecatedAPIsOpts, clusterContextParams...)
+	deprecatedAPIsOpts = append(deprecatedAPIsOpts,
+		mcp.WithString("targetVersion",
+			mcp.Description("Kubernetes version planned for an upgrade, e.g. '1.29'. Only APIs removed in or before it are reported (optional, default: every known removal)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxDeprecatedAPIsLimit),
+			mcp.Description("Maximum number of objects to report per cluster. Default: 50. Maximum: 1000. The summary always counts all objects."),
+		),
+	)
+	if sc.FederationEnabled() {
+		deprecatedAPIsOpts = append(deprecatedAPIsOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to scan in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("deprecated_apis", deprecatedAPIsOpts...), tools.WrapWithAuditLogging("deprecated_apis", handleDeprecatedAPIs, sc))
+
+	// upgrade_readiness tool
+	upgradeReadinessOpts := []mcp.ToolOption{
+		mcp.WithDescription("Report the API server version and whether the cluster is ready to upgrade to a target version (default: the next minor version). Groups nodes by kubelet version and checks their skew against the Kubernetes version skew policy now and after the upgrade, counts objects using APIs the target removes, and lists feature gates set on kubeadm-style static control plane pods. Set clusters to compare several workload clusters in a version matrix, oldest first (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	upgradeReadinessOpts = append(upgradeReadinessOpts, clusterContextParams...)
+	upgradeReadinessOpts = append(upgradeReadinessOpts,
+		mcp.WithString("targetVersion",
+			mcp.Description("Kubernetes version to upgrade to, e.g. '1.30' (optional, default: the next minor version of each cluster)"),
+		),
+	)
+	if sc.FederationEnabled() {
+		upgradeReadinessOpts = append(upgradeReadinessOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to check in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("upgrade_readiness", upgradeReadinessOpts...), tools.WrapWithAuditLogging("upgrade_readiness", handleUpgradeReadiness, sc))
+
+	// images tool
+	imagesOpts := []mcp.ToolOption{
+		mcp.WithDescription("Inventory the container images of running pods, grouped by registry, repository and tag, with the pods and workloads using each. Flags images using the latest tag (explicitly or by having no tag), workloads running several tags of the same repository, and, when allowedRegistries is set, images from registries outside that allowlist. Set clusters to inventory several workload clusters (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	imagesOpts = append(imagesOpts, clusterContextParams...)
+	imagesOpts = append(imagesOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to scan (optional, default: all namespaces)"),
+		),
+		mcp.WithString("allowedRegistries",
+			mcp.Description("Comma-separated allowed registries or repository prefixes, e.g. 'gsoci.azurecr.io,docker.io/giantswarm'. Images from anywhere else are flagged (optional)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxImagesLimit),
+			mcp.Description("Maximum number of images and of findings to report per cluster. Default: 100. Maximum: 1000."),
+		),
+	)
+	if sc.FederationEnabled() {
+		imagesOpts = append(imagesOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to scan in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("images", imagesOpts...), tools.WrapWithAuditLogging("images", handleImages, sc))
+
+	// service_accounts tool
+	serviceAccountsOpts := []mcp.ToolOption{
+		mcp.WithDescription("Audit ServiceAccounts: their token secrets, automountServiceAccountToken settings, workload identity annotations and the pods and workloads running as each. Flags workloads running as the default service account, pods automounting a token of a service account no RoleBinding or ClusterRoleBinding names, and long-lived token secrets."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	serviceAccountsOpts = append(serviceAccountsOpts, clusterContextParams...)
+	serviceAccountsOpts = append(serviceAccountsOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to audit (optional, default: all namespaces)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxServiceAccountsLimit),
+			mcp.Description("Maximum number of service accounts and of findings to report. Default: 100. Maximum: 1000."),
+		),
+	)
+	s.AddTool(mcp.NewTool("service_accounts", serviceAccountsOpts...), tools.WrapWithAuditLogging("service_accounts", handleServiceAccounts, sc))
+
+	// pod_security tool
+	podSecurityOpts := []mcp.ToolOption{
+		mcp.WithDescription("Report the Pod Security admission labels (enforce, audit, warn) of every namespace and evaluate running workloads against the baseline and restricted Pod Security Standards, listing the failed checks such as privileged containers, hostPath volumes and running as root, and whether the namespace's enforce level would reject the pods when recreated. Set clusters to report on several workload clusters (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	podSecurityOpts = append(podSecurityOpts, clusterContextParams...)
+	podSecurityOpts = append(podSecurityOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to report on (optional, default: all namespaces)"),
+		),
+		mcp.WithString("profile",
+			mcp.Description("Profile to list violations of: 'restricted' also lists baseline violations (default: restricted)"),
+			mcp.Enum("baseline", "restricted"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxPodSecurityLimit),
+			mcp.Description("Maximum number of namespaces and of violating workloads to report per cluster. Default: 100. Maximum: 1000."),
+		),
+	)
+	if sc.FederationEnabled() {
+		podSecurityOpts = append(podSecurityOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to scan in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("pod_security", podSecurityOpts...), tools.WrapWithAuditLogging("pod_security", handlePodSecurity, sc))
+
+	// cert_expiry tool
+	certExpiryOpts := []mcp.ToolOption{
+		mcp.WithDescription("Find TLS certificates that expire soon. Reads the leaf certificate of every kubernetes.io/tls Secret, never the private key, and returns its subject, issuer, SANs and expiry, soonest first. Where cert-manager is installed, its Certificates add the issuer reference, renewal time and readiness; Certificates that are not ready or not yet issued are always reported. Set clusters to scan several workload clusters (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	certExpiryOpts = append(certExpiryOpts, clusterContextParams...)
+	certExpiryOpts = append(certExpiryOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to scan (optional, default: all namespaces)"),
+		),
+		mcp.WithNumber("withinDays",
+			mcp.Min(0),
+			mcp.Max(MaxCertExpiryDays),
+			mcp.Description("Report certificates expiring within this many days; expired ones are always included. Default: 30. Maximum: 3650."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxCertExpiryLimit),
+			mcp.Description("Maximum number of certificates to report per cluster. Default: 50. Maximum: 1000."),
+		),
+	)
+	if sc.FederationEnabled() {
+		certExpiryOpts = append(certExpiryOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to scan in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("cert_expiry", certExpiryOpts...), tools.WrapWithAuditLogging("cert_expiry", handleCertExpiry, sc))
+
+	// find_orphans tool
+	findOrphansOpts := []mcp.ToolOption{
+		mcp.WithDescription("Find likely orphaned resources for cleanup: ReplicaSets scaled to zero that no Deployment owns, PersistentVolumeClaims left unbound for unboundDays and mounted by no pod, ConfigMaps and Secrets that no pod, pod template, ServiceAccount or Ingress in their namespace refers to, and Services whose selector has no endpoints. Findings are candidates, not certainties: consumers outside pods are not seen. Set deletePlan to get the delete tool arguments for each finding; nothing is deleted by this tool."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	findOrphansOpts = append(findOrphansOpts, clusterContextParams...)
+	findOrphansOpts = append(findOrphansOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to check (optional, default: all namespaces)"),
+		),
+		mcp.WithString("kinds",
+			mcp.Description("Comma-separated checks to run: replicasets, persistentvolumeclaims, configmaps, secrets, services (optional, default: all)"),
+		),
+		mcp.WithNumber("unboundDays",
+			mcp.Min(0),
+			mcp.Max(MaxOrphanUnboundDays),
+			mcp.Description("Report PersistentVolumeClaims unbound for at least this many days. Default: 7."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxOrphansLimit),
+			mcp.Description("Maximum number of findings to return. Default: 50. Maximum: 500. The summary always counts all findings."),
+		),
+		mcp.WithBoolean("deletePlan",
+			mcp.Description("Include the delete tool arguments for each returned finding (default: false)"),
+		),
+	)
+	s.AddTool(mcp.NewTool("find_orphans", findOrphansOpts...), tools.WrapWithAuditLogging("find_orphans", handleFindOrphans, sc))
+
+	// search tool
+	searchOpts := []mcp.ToolOption{
+		mcp.WithDescription("Find objects by name and labels when you do not know their namespace or type. Searches Pods, Deployments, StatefulSets, DaemonSets, CronJobs, Jobs, Services, Ingresses, ConfigMaps, Secrets and PersistentVolumeClaims in every namespace unless narrowed down. query matches names case-insensitively as a substring, or as a regular expression with regex=true. Matches are ranked exact name first, then prefix, then substring, shortest names first, and returned compactly with the resourceType and apiGroup to fetch them with. Set clusters to search several workload clusters (federation mode)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	searchOpts = append(searchOpts, clusterContextParams...)
+	searchOpts = append(searchOpts,
+		mcp.WithString("query",
+			mcp.Description("Name or part of a name to look for, e.g. 'checkout'. At least one of query and labelSelector is required."),
+		),
+		mcp.WithBoolean("regex",
+			mcp.Description("Treat query as a case-insensitive regular expression, e.g. '^web-(api|worker)$' (default: false)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label selector the objects must match, e.g. 'app.kubernetes.io/part-of=shop' (optional)"),
+		),
+		mcp.WithString("resourceTypes",
+			mcp.Description("Comma-separated resource types to search, as resource or resource.group, e.g. 'deployments,helmreleases.helm.toolkit.fluxcd.io' (optional, default: common workload, networking, config and storage types)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to search (optional, default: all namespaces)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxSearchLimit),
+			mcp.Description("Maximum number of matches to return. Default: 50. Maximum: 500. totalMatches always counts all matches."),
+		),
+	)
+	if sc.FederationEnabled() {
+		searchOpts = append(searchOpts,
+			mcp.WithString("clusters",
+				mcp.Description("Comma-separated workload cluster names to search in one call, or '*' for every cluster you can access. Replaces cluster."),
+			),
+		)
+	}
+	s.AddTool(mcp.NewTool("search", searchOpts...), tools.WrapWithAuditLogging("search", handleSearch, sc))
+
+	// autoscaling tool
+	autoscalingOpts := []mcp.ToolOption{
+		mcp.WithDescription("Report HorizontalPodAutoscalers compactly: target workload, min/current/desired/max replicas, each metric's current value against its target (e.g. cpu 92% of 80%), conditions such as ScalingLimited, recent scaling events, and warnings when an HPA sits at maxReplicas or cannot read its metrics. VerticalPodAutoscaler recommendations are included when the VPA CRD is installed, with a warning when an active VPA and a cpu or memory HPA target the same workload."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	autoscalingOpts = append(autoscalingOpts, clusterContextParams...)
+	autoscalingOpts = append(autoscalingOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to report on (optional, default: all namespaces)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of one HorizontalPodAutoscaler to report on; requires namespace (optional)"),
+		),
+		mcp.WithBoolean("includeEvents",
+			mcp.Description("Include recent events of each HPA (default: true)"),
+		),
+		mcp.WithNumber("eventsLimit",
+			mcp.Min(1),
+			mcp.Max(MaxAutoscalingEventsLimit),
+			mcp.Description("Maximum number of events per HPA, newest first. Default: 5. Maximum: 50."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxAutoscalingLimit),
+			mcp.Description("Maximum number of HPAs to return. Default: 50. Maximum: 500. totalHPAs always counts all HPAs."),
+		),
+	)
+	s.AddTool(mcp.NewTool("autoscaling", autoscalingOpts...), tools.WrapWithAuditLogging("autoscaling", handleAutoscaling, sc))
+
+	// connectivity_test tool. Proxy mode only reads, so the tool is always
+	// registered; pod mode is checked against the safety settings per call.
+	connectivityOpts := []mcp.ToolOption{
+		mcp.WithDescription("Test whether a Service is reachable. Checks that the Service exists and has ready endpoints, then fetches a URL on it. mode=proxy (default) goes through the API server's service proxy and creates nothing. mode=pod runs a short-lived, resource-capped busybox pod in sourceNamespace that resolves the Service's DNS name and fetches the URL from inside the cluster network, so DNS and NetworkPolicies are exercised; it needs create and delete to be allowed and is deleted afterwards."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	connectivityOpts = append(connectivityOpts, clusterContextParams...)
+	connectivityOpts = append(connectivityOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace of the target Service"),
+		),
+		mcp.WithString("service",
+			mcp.Required(),
+			mcp.Description("Name of the target Service"),
+		),
+		mcp.WithString("port",
+			mcp.Description("Service port name or number (default: the Service's first port)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("HTTP path to fetch (default: /)"),
+		),
+		mcp.WithString("scheme",
+			mcp.Description("URL scheme (default: http)"),
+			mcp.Enum("http", "https"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("proxy (default) tests via the API server; pod tests DNS and HTTP from a temporary pod"),
+			mcp.Enum(ConnectivityModeProxy, ConnectivityModePod),
+		),
+		mcp.WithString("sourceNamespace",
+			mcp.Description("Namespace to run the test pod in, to test cross-namespace traffic (pod mode only, default: the Service's namespace)"),
+		),
+		mcp.WithNumber("timeoutSeconds",
+			mcp.Min(1),
+			mcp.Max(MaxConnectivityTimeoutSeconds),
+			mcp.Description("Timeout for each probe in seconds. Default: 10. Maximum: 60."),
+		),
+	)
+	connectivityTool := mcp.NewTool("connectivity_test", connectivityOpts...)
+
+	s.AddTool(connectivityTool, tools.WrapWithAuditLogging("connectivity_test", handleConnectivityTest, sc))
+
+	// service_debug tool
+	serviceDebugOpts := []mcp.ToolOption{
+		mcp.WithDescription("Debug why traffic does not reach a Service. Reports the pods its selector matches (and, if none, which selector label is off), EndpointSlice readiness, target ports that do not resolve on the pods, the DNS answer for its name, and the Ingresses, HTTPRoutes and GRPCRoutes that send traffic to it, with their problems."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	serviceDebugOpts = append(serviceDebugOpts, clusterContextParams...)
+	serviceDebugOpts = append(serviceDebugOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace of the Service"),
+		),
+		mcp.WithString("service",
+			mcp.Required(),
+			mcp.Description("Name of the Service"),
+		),
+	)
+	serviceDebugTool := mcp.NewTool("service_debug", serviceDebugOpts...)
+
+	s.AddTool(serviceDebugTool, tools.WrapWithAuditLogging("service_debug", handleServiceDebug, sc))
+
+	// storage_debug tool
+	storageDebugOpts := []mcp.ToolOption{
+		mcp.WithDescription("Explain why a PersistentVolumeClaim is Pending or its volume is stuck. Correlates the claim with its StorageClass (or the default one), the bound PersistentVolume, VolumeAttachments and the pods using it, and reports provisioning, binding, attach and mount errors taken from their events."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	storageDebugOpts = append(storageDebugOpts, clusterContextParams...)
+	storageDebugOpts = append(storageDebugOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace of the claim or pod"),
+		),
+		mcp.WithString("claim",
+			mcp.Description("Name of the PersistentVolumeClaim to diagnose. Set either claim or pod."),
+		),
+		mcp.WithString("pod",
+			mcp.Description("Name of a pod; diagnoses every claim it mounts. Set either claim or pod."),
+		),
+	)
+	storageDebugTool := mcp.NewTool("storage_debug", storageDebugOpts...)
+
+	s.AddTool(storageDebugTool, tools.WrapWithAuditLogging("storage_debug", handleStorageDebug, sc))
+
+	// routes tool
+	routesOpts := []mcp.ToolOption{
+		mcp.WithDescription("Show where traffic to a host and path goes. Lists the rules of Ingresses, HTTPRoutes and GRPCRoutes with their host, path, TLS certificate, load balancer address and backend Services, and how many endpoints of each backend are ready. Set host (and path) to get only the rules that match, most specific first. Problems such as missing Services, unknown ports, backends without ready endpoints and routes a Gateway has not accepted are reported per rule."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	routesOpts = append(routesOpts, clusterContextParams...)
+	routesOpts = append(routesOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Ingresses and routes (optional, default: all namespaces)"),
+		),
+		mcp.WithString("host",
+			mcp.Description("Hostname to route, e.g. 'shop.example.com'. Wildcard and catch-all rules that match it are included (optional)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Request path to route, e.g. '/api/orders' (optional)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxRoutesLimit),
+			mcp.Description("Maximum number of rules to return. Default: 100. Maximum: 1000."),
+		),
+	)
+	routesTool := mcp.NewTool("routes", routesOpts...)
+
+	s.AddTool(routesTool, tools.WrapWithAuditLogging("routes", handleRoutes, sc))
+
+	// policy_violations tool
+	policyViolationsOpts := []mcp.ToolOption{
+		mcp.WithDescription("List admission policy violations reported by Kyverno (PolicyReports and ClusterPolicyReports) and Gatekeeper (constraint audit results), aggregated by policy and severity, with the recent policy violation and admission denial events. Use this to explain why a deployment was rejected or to find non-compliant workloads. Engines that are not installed are skipped."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	policyViolationsOpts = append(policyViolationsOpts, clusterContextParams...)
+	policyViolationsOpts = append(policyViolationsOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Only report violations of resources in this namespace (optional, default: the whole cluster)"),
+		),
+		mcp.WithString("policy",
+			mcp.Description("Only report violations of this policy; Gatekeeper policies are named Kind/name (optional)"),
+		),
+		mcp.WithString("severity",
+			mcp.Enum("critical", "high", "medium", "low", "info"),
+			mcp.Description("Only report violations of this severity (optional)"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Only report violations of resources of this kind, e.g. 'Deployment' (optional)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Only report violations of resources with this name (optional)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxPolicyViolationsLimit),
+			mcp.Description("Maximum number of violations to return, most severe first. Default: 100. Maximum: 1000."),
+		),
+	)
+	policyViolationsTool := mcp.NewTool("policy_violations", policyViolationsOpts...)
+
+	s.AddTool(policyViolationsTool, tools.WrapWithAuditLogging("policy_violations", handlePolicyViolations, sc))
+
+	// gitops_status tool
+	gitOpsStatusOpts := []mcp.ToolOption{
+		mcp.WithDescription("Summarize the reconciliation state of GitOps resources: Flux Kustomizations, HelmReleases and sources (GitRepository, OCIRepository, HelmRepository), and Argo CD Applications. Reports for each whether it is ready, suspended, its source, the revision applied and the one attempted, and the last error. Not-ready resources come first. Use this to answer why a change pushed to Git is not live yet. Controllers whose CRDs are not installed are skipped."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	gitOpsStatusOpts = append(gitOpsStatusOpts, clusterContextParams...)
+	gitOpsStatusOpts = append(gitOpsStatusOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the GitOps resources (optional, default: all namespaces)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Only report resources with this name (optional)"),
+		),
+		mcp.WithBoolean("notReadyOnly",
+			mcp.Description("Only report resources that are not ready, suspended or have not applied their latest revision (default: false)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxGitOpsLimit),
+			mcp.Description("Maximum number of resources to return. Default: 100. Maximum: 1000."),
+		),
+	)
+	gitOpsStatusTool := mcp.NewTool("gitops_status", gitOpsStatusOpts...)
+
+	s.AddTool(gitOpsStatusTool, tools.WrapWithAuditLogging("gitops_status", handleGitOpsStatus, sc))
+
+	// gitops_reconcile tool
+	if tools.IsMutatingOperationAllowed(sc, "gitops_reconcile") {
+		gitOpsReconcileOpts := []mcp.ToolOption{
+			mcp.WithDescription("Ask Flux or Argo CD to reconcile a resource now instead of at its next interval, like flux reconcile or an Argo CD refresh. Sets the reconcile.fluxcd.io/requestedAt or argocd.argoproj.io/refresh annotation; the controller does the rest. Follow the result with gitops_status."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		gitOpsReconcileOpts = append(gitOpsReconcileOpts, clusterContextParams...)
+		gitOpsReconcileOpts = append(gitOpsReconcileOpts,
+			mcp.WithString("kind",
+				mcp.Required(),
+				mcp.Enum(gitOpsKindNames()...),
+				mcp.Description("Kind of the resource to reconcile"),
+			),
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace of the resource"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the resource"),
+			),
+			mcp.WithBoolean("withSource",
+				mcp.Description("Flux only: reconcile the resource's source first, so that it fetches the latest revision (default: false)"),
+			),
+			mcp.WithBoolean("hard",
+				mcp.Description("Argo CD only: hard refresh, which also invalidates the manifest cache (default: false)"),
+			),
+			tools.DryRunParam(),
+		)
+		gitOpsReconcileTool := mcp.NewTool("gitops_reconcile", gitOpsReconcileOpts...)
+
+		s.AddTool(gitOpsReconcileTool, tools.WrapWithAuditLogging("gitops_reconcile", handleGitOpsReconcile, sc))
+	}
+
+	// diagnose_node tool
+	diagnoseNodeOpts := []mcp.ToolOption{
+		mcp.WithDescription("Diagnose a node in one call. Returns its conditions, taints, kubelet and container runtime versions, allocatable resources against what its pods request and limit, recent events such as pressure and evictions, and the pending pods its taints keep away, together with probable causes such as NodeNotReady, MemoryPressure, DiskPressure, PIDPressure, Cordoned, ResourcesExhausted and TaintsBlockPods, each with a suggestion."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	diagnoseNodeOpts = append(diagnoseNodeOpts, clusterContextParams...)
+	diagnoseNodeOpts = append(diagnoseNodeOpts,
+		mcp.WithString("nodeName",
+			mcp.Required(),
+			mcp.Description("Name of the node to diagnose"),
+		),
+	)
+	diagnoseNodeTool := mcp.NewTool("diagnose_node", diagnoseNodeOpts...)
+
+	s.AddTool(diagnoseNodeTool, tools.WrapWithAuditLogging("diagnose_node", handleDiagnoseNode, sc))
+
+	// drain_preview tool
+	drainPreviewOpts := []mcp.ToolOption{
+		mcp.WithDescription("Preview a drain of one or more nodes without changing anything. Plans the drain like the drain tool and returns the pods it would evict, the pods that would make it refuse to start, the PodDisruptionBudgets covering the evicted pods with whether they would block it, and the workloads that would be left without a ready pod, such as singletons, while their pods are rescheduled."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	drainPreviewOpts = append(drainPreviewOpts, clusterContextParams...)
+	drainPreviewOpts = append(drainPreviewOpts,
+		mcp.WithString("nodeNames",
+			mcp.Description("Comma-separated names of the nodes to drain, e.g. 'worker-1,worker-2'. Required unless nodeSelector is set"),
+		),
+		mcp.WithString("nodeSelector",
+			mcp.Description("Label selector choosing the nodes to drain, e.g. 'node.kubernetes.io/instance-type=m5.xlarge'. Required unless nodeNames is set"),
+		),
+		mcp.WithBoolean("ignoreDaemonSets",
+			mcp.Description("Leave DaemonSet pods on the nodes (default: true)"),
+		),
+		mcp.WithBoolean("deleteEmptyDirData",
+			mcp.Description("Evict pods using emptyDir volumes (default: false)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Evict pods not managed by a controller (default: false)"),
+		),
+	)
+	drainPreviewTool := mcp.NewTool("drain_preview", drainPreviewOpts...)
+
+	s.AddTool(drainPreviewTool, tools.WrapWithAuditLogging("drain_preview", handleDrainPreview, sc))
+
+	registerMaintenanceTools(s, sc, clusterContextParams)
+
 	return nil
 }
+
+// registerMaintenanceTools registers the node maintenance tools permitted by
+// the safety settings.
+func registerMaintenanceTools(s *mcpserver.MCPServer, sc *server.ServerContext, clusterContextParams []mcp.ToolOption) {
+	nodeNameParam := mcp.WithString("nodeName",
+		mcp.Required(),
+		mcp.Description("Name of the node"),
+	)
+	gracePeriodParam := mcp.WithNumber("gracePeriodSeconds",
+		mcp.Min(0),
+		mcp.Max(MaxGracePeriodSeconds),
+		mcp.Description("Override the pod's termination grace period in seconds (optional)"),
+	)
+
+	// cordon and uncordon tools
+	for _, t := range []struct {
+		name, description string
+		handler           tools.ToolHandler
+	}{
+		{"cordon", "Mark a node unschedulable so no new pods are placed on it. Running pods are not affected.", handleCordon},
+		{"uncordon", "Mark a cordoned node schedulable again", handleUncordon},
+	} {
+		if !tools.IsMutatingOperationAllowed(sc, t.name) {
+			continue
+		}
+		opts := []mcp.ToolOption{
+			mcp.WithDescription(t.description),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		opts = append(opts, clusterContextParams...)
+		opts = append(opts, nodeNameParam, tools.DryRunParam())
+		s.AddTool(mcp.NewTool(t.name, opts...), tools.WrapWithAuditLogging(t.name, t.handler, sc))
+	}
+
+	// evict tool
+	if tools.IsMutatingOperationAllowed(sc, "evict") {
+		evictOpts := []mcp.ToolOption{
+			mcp.WithDescription("Evict a pod through the Eviction API. Unlike delete, eviction honours PodDisruptionBudgets: when a budget allows no disruption the pod is left running and the blocking budgets are reported."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		evictOpts = append(evictOpts, clusterContextParams...)
+		evictOpts = append(evictOpts,
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace of the pod"),
+			),
+			mcp.WithString("podName",
+				mcp.Required(),
+				mcp.Description("Name of the pod to evict"),
+			),
+			gracePeriodParam,
+			tools.DryRunParam(),
+		)
+		s.AddTool(mcp.NewTool("evict", evictOpts...), tools.WrapWithAuditLogging("evict", handleEvict, sc))
+	}
+
+	// drain tool
+	if tools.IsMutatingOperationAllowed(sc, "drain") {
+		drainOpts := []mcp.ToolOption{
+			mcp.WithDescription("Drain a node for maintenance: cordon it, then evict its pods through the Eviction API so PodDisruptionBudgets are honoured. Like kubectl drain, nothing is changed if a pod cannot be evicted safely (unmanaged pods, emptyDir data, DaemonSet pods when ignoreDaemonSets=false). Evictions blocked by a budget are reported and can be retried by calling drain again. Drain does not wait for evicted pods to terminate."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		drainOpts = append(drainOpts, clusterContextParams...)
+		drainOpts = append(drainOpts,
+			nodeNameParam,
+			mcp.WithBoolean("ignoreDaemonSets",
+				mcp.Description("Leave DaemonSet pods on the node (default: true)"),
+			),
+			mcp.WithBoolean("deleteEmptyDirData",
+				mcp.Description("Evict pods using emptyDir volumes, losing their data (default: false)"),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Evict pods not managed by a controller; they are not recreated (default: false)"),
+			),
+			gracePeriodParam,
+			tools.DryRunParam(),
+		)
+		s.AddTool(mcp.NewTool("drain", drainOpts...), tools.WrapWithAuditLogging("drain", handleDrain, sc))
+	}
+}
@@ -0,0 +1,476 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// maxKubeletNodeNames caps the node names listed per kubelet version.
+const maxKubeletNodeNames = 10
+
+// controlPlaneSelector selects the static control plane pods of kubeadm
+// based clusters, whose command lines carry the feature gates.
+const controlPlaneSelector = "tier=control-plane"
+
+// KubeletVersionGroup counts the nodes running one kubelet version.
+type KubeletVersionGroup struct {
+	Version string `json:"version"`
+	Nodes   int    `json:"nodes"`
+
+	// NodeNames lists the first nodes running this version.
+	NodeNames []string `json:"nodeNames"`
+
+	// Skew is the number of minor versions the kubelet is behind the API
+	// server; it is negative when the kubelet is newer.
+	Skew int `json:"skew"`
+
+	// Supported and SupportedAtTarget say whether the skew is within the
+	// Kubernetes version skew policy now and after upgrading the control
+	// plane to the target version.
+	Supported         bool `json:"supported"`
+	SupportedAtTarget bool `json:"supportedAtTarget"`
+}
+
+// FeatureGate is a feature gate set on the command line of a control plane
+// component.
+type FeatureGate struct {
+	Component string `json:"component"`
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// UpgradeReadinessOutput is the response of the upgrade_readiness tool for
+// one cluster.
+type UpgradeReadinessOutput struct {
+	// Cluster is set for fleet queries.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Error is set when a cluster of a fleet query could not be checked.
+	Error string `json:"error,omitempty"`
+
+	ServerVersion string `json:"serverVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+
+	// MinorsBehind is set for fleet queries: the number of minor versions
+	// the cluster is behind the newest cluster of the fleet.
+	MinorsBehind *int `json:"minorsBehind,omitempty"`
+
+	// Ready is true when nothing blocks the upgrade to the target version.
+	Ready    bool     `json:"ready"`
+	Blockers []string `json:"blockers"`
+	Warnings []string `json:"warnings"`
+
+	// Kubelets are sorted by version, oldest first.
+	Kubelets   []KubeletVersionGroup `json:"kubelets"`
+	TotalNodes int                   `json:"totalNodes"`
+
+	// DeprecatedAPIs counts the objects using API versions removed by the
+	// target version.
+	DeprecatedAPIs []DeprecatedAPISummary `json:"deprecatedAPIs"`
+
+	// FeatureGates are only found on clusters whose control plane runs as
+	// visible static pods, such as kubeadm clusters.
+	FeatureGates []FeatureGate `json:"featureGates,omitempty"`
+
+	// Skipped lists checks that could not be run, for example for lack of
+	// permissions.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// FleetUpgradeReadinessOutput is the response of the upgrade_readiness tool
+// across clusters. Clusters are sorted by version, oldest first, so the
+// clusters lagging behind come first; clusters that failed are last.
+type FleetUpgradeReadinessOutput struct {
+	NewestVersion     string                   `json:"newestVersion,omitempty"`
+	Clusters          []UpgradeReadinessOutput `json:"clusters"`
+	TotalClusters     int                      `json:"totalClusters"`
+	FailedClusters    int                      `json:"failedClusters"`
+	ClustersTruncated bool                     `json:"clustersTruncated,omitempty"`
+}
+
+// upgradeReadinessRequest holds the validated upgrade_readiness tool
+// arguments.
+type upgradeReadinessRequest struct {
+	cluster     string
+	kubeContext string
+	clusters    []string
+	// targetVersion is nil to target the next minor version of each
+	// cluster.
+	targetVersion *version.Version
+}
+
+// handleUpgradeReadiness handles the upgrade_readiness tool.
+func handleUpgradeReadiness(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseUpgradeReadinessRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	var result interface{}
+	if req.clusters != nil {
+		fleet, toolErr := fleetUpgradeReadiness(ctx, sc, req)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		result = fleet
+	} else {
+		client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		out, err := checkUpgradeReadiness(ctx, client.K8s(), req)
+		if err != nil {
+			return tools.K8sError("Failed to check upgrade readiness", err, client.User()).Result(), nil
+		}
+		result = out
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal upgrade readiness: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseUpgradeReadinessRequest validates the tool arguments.
+func parseUpgradeReadinessRequest(args map[string]interface{}) (upgradeReadinessRequest, string) {
+	req := upgradeReadinessRequest{cluster: tools.ExtractClusterParam(args)}
+	req.kubeContext, _ = args["kubeContext"].(string)
+
+	if target, _ := args["targetVersion"].(string); target != "" {
+		v, err := version.ParseGeneric(target)
+		if err != nil {
+			return req, fmt.Sprintf("invalid targetVersion %q: use a Kubernetes version such as 1.30", target)
+		}
+		req.targetVersion = version.MajorMinor(v.Major(), v.Minor())
+	}
+
+	var errMsg string
+	req.clusters, errMsg = parseClustersArg(args, req.cluster)
+	return req, errMsg
+}
+
+// fleetUpgradeReadiness checks every requested cluster in parallel and
+// compares their versions. A cluster that fails is reported in the output
+// instead of failing the whole call.
+func fleetUpgradeReadiness(ctx context.Context, sc *server.ServerContext, req upgradeReadinessRequest) (*FleetUpgradeReadinessOutput, *toolerrors.Error) {
+	names, total, toolErr := resolveFleetClusters(ctx, sc, req.clusters)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+
+	out := &FleetUpgradeReadinessOutput{
+		TotalClusters:     total,
+		ClustersTruncated: len(names) < total,
+		Clusters:          make([]UpgradeReadinessOutput, len(names)),
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(capacityFleetConcurrency)
+	for i, name := range names {
+		g.Go(func() error {
+			result := UpgradeReadinessOutput{Cluster: name}
+			client, toolErr := tools.GetClusterClient(gctx, sc, name)
+			if toolErr != nil {
+				result.Error = toolErr.Message
+			} else {
+				checked, err := checkUpgradeReadiness(gctx, client.K8s(), req)
+				if err != nil {
+					result.Error = tools.FormatK8sError("Failed to check upgrade readiness", err, client.User())
+				} else {
+					result = *checked
+					result.Cluster = name
+				}
+			}
+
+			mu.Lock()
+			out.Clusters[i] = result
+			if result.Error != "" {
+				out.FailedClusters++
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	versionMatrix(out)
+	return out, nil
+}
+
+// versionMatrix sorts the clusters of a fleet query by version and sets how
+// far each lags behind the newest.
+func versionMatrix(out *FleetUpgradeReadinessOutput) {
+	versions := make(map[string]*version.Version, len(out.Clusters))
+	var newest *version.Version
+	for _, c := range out.Clusters {
+		v, err := version.ParseGeneric(c.ServerVersion)
+		if c.Error != "" || err != nil {
+			continue
+		}
+		versions[c.Cluster] = v
+		if newest == nil || newest.LessThan(v) {
+			newest = v
+		}
+	}
+	if newest == nil {
+		return
+	}
+	out.NewestVersion = newest.String()
+
+	for i := range out.Clusters {
+		if v, ok := versions[out.Clusters[i].Cluster]; ok {
+			out.Clusters[i].MinorsBehind = ptr(minorSkew(newest, v))
+		}
+	}
+	sort.SliceStable(out.Clusters, func(i, j int) bool {
+		a, aok := versions[out.Clusters[i].Cluster]
+		b, bok := versions[out.Clusters[j].Cluster]
+		if aok != bok {
+			return aok
+		}
+		if aok && !a.EqualTo(b) {
+			return a.LessThan(b)
+		}
+		return out.Clusters[i].Cluster < out.Clusters[j].Cluster
+	})
+}
+
+// checkUpgradeReadiness reports the versions of one cluster and what blocks
+// its upgrade to the target version.
+func checkUpgradeReadiness(ctx context.Context, client k8s.Client, req upgradeReadinessRequest) (*UpgradeReadinessOutput, error) {
+	health, err := client.GetClusterHealth(ctx, req.kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	current, err := version.ParseGeneric(health.Version)
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine the Kubernetes version of the cluster: %q", health.Version)
+	}
+
+	target := req.targetVersion
+	if target == nil {
+		target = version.MajorMinor(current.Major(), current.Minor()+1)
+	}
+
+	out := &UpgradeReadinessOutput{
+		ServerVersion:  health.Version,
+		TargetVersion:  target.String(),
+		Blockers:       []string{},
+		Warnings:       []string{},
+		Kubelets:       []KubeletVersionGroup{},
+		DeprecatedAPIs: []DeprecatedAPISummary{},
+	}
+
+	switch skew := minorSkew(target, current); {
+	case skew <= 0:
+		out.Warnings = append(out.Warnings, fmt.Sprintf("the cluster already runs %s, which is not older than the target %s", health.Version, target))
+	case skew > 1:
+		out.Warnings = append(out.Warnings, fmt.Sprintf("the control plane upgrades one minor version at a time: upgrade to %s first", version.MajorMinor(current.Major(), current.Minor()+1)))
+	}
+
+	if err := checkKubelets(ctx, client, req.kubeContext, current, target, out); err != nil {
+		out.Skipped = append(out.Skipped, fmt.Sprintf("nodes: %v", err))
+	}
+
+	deprecations, err := findDeprecatedAPIs(ctx, client, deprecatedAPIsRequest{kubeContext: req.kubeContext, targetVersion: target, limit: 1}, health.Version, current)
+	if err != nil {
+		out.Skipped = append(out.Skipped, fmt.Sprintf("deprecated APIs: %v", err))
+	} else {
+		out.DeprecatedAPIs = append(out.DeprecatedAPIs, deprecations.Summary...)
+		out.Skipped = append(out.Skipped, deprecations.Skipped...)
+		for _, d := range deprecations.Summary {
+			msg := fmt.Sprintf("%d %s objects use %s, removed in %s", d.Objects, d.Kind, d.APIVersion, d.RemovedIn)
+			if d.Replacement != "" {
+				msg += "; migrate them to " + d.Replacement
+			}
+			out.Blockers = append(out.Blockers, msg)
+		}
+	}
+
+	if err := controlPlaneFeatureGates(ctx, client, req.kubeContext, out); err != nil {
+		out.Skipped = append(out.Skipped, fmt.Sprintf("feature gates: %v", err))
+	}
+
+	out.Ready = len(out.Blockers) == 0
+	return out, nil
+}
+
+// checkKubelets groups the nodes by kubelet version and checks the skew to
+// the API server now and at the target version.
+func checkKubelets(ctx context.Context, client k8s.Client, kubeContext string, current, target *version.Version, out *UpgradeReadinessOutput) error {
+	groups := map[string]*KubeletVersionGroup{}
+	parsed := map[string]*version.Version{}
+	err := listAll(ctx, client, kubeContext, "", "nodes", "", k8s.ListOptions{}, func(node *corev1.Node) {
+		out.TotalNodes++
+		kubelet := node.Status.NodeInfo.KubeletVersion
+		g, ok := groups[kubelet]
+		if !ok {
+			g = &KubeletVersionGroup{Version: kubelet, NodeNames: []string{}}
+			groups[kubelet] = g
+		}
+		g.Nodes++
+		if len(g.NodeNames) < maxKubeletNodeNames {
+			g.NodeNames = append(g.NodeNames, node.Name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for kubelet, g := range groups {
+		if v, err := version.ParseGeneric(kubelet); err == nil {
+			parsed[kubelet] = v
+			g.Skew = minorSkew(current, v)
+			g.Supported = kubeletSkewSupported(current, v)
+			g.SupportedAtTarget = kubeletSkewSupported(target, v)
+		}
+		out.Kubelets = append(out.Kubelets, *g)
+	}
+	sort.Slice(out.Kubelets, func(i, j int) bool {
+		a, aok := parsed[out.Kubelets[i].Version]
+		b, bok := parsed[out.Kubelets[j].Version]
+		if aok && bok && !a.EqualTo(b) {
+			return a.LessThan(b)
+		}
+		if aok != bok {
+			return aok
+		}
+		return out.Kubelets[i].Version < out.Kubelets[j].Version
+	})
+
+	for _, g := range out.Kubelets {
+		switch {
+		case parsed[g.Version] == nil:
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%d nodes report an unknown kubelet version %q", g.Nodes, g.Version))
+		case g.Skew < 0:
+			out.Blockers = append(out.Blockers, fmt.Sprintf("%d nodes run kubelet %s, newer than the API server %s", g.Nodes, g.Version, out.ServerVersion))
+		case !g.SupportedAtTarget:
+			out.Blockers = append(out.Blockers, fmt.Sprintf("%d nodes run kubelet %s, more than %d minor versions behind %s; upgrade them first", g.Nodes, g.Version, kubeletSkewLimit(target), target))
+		case g.Skew > 0:
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%d nodes run kubelet %s, %d minor versions behind the API server", g.Nodes, g.Version, g.Skew))
+		}
+	}
+	if len(groups) > 1 {
+		out.Warnings = append(out.Warnings, fmt.Sprintf("nodes run %d different kubelet versions", len(groups)))
+	}
+	return nil
+}
+
+// minorSkew returns how many minor versions b is behind a.
+func minorSkew(a, b *version.Version) int {
+	if a.Major() != b.Major() {
+		// Kubernetes has had one major version; treat another as far apart.
+		return (int(a.Major()) - int(b.Major())) * 100
+	}
+	return int(a.Minor()) - int(b.Minor())
+}
+
+// kubeletSkewLimit is the number of minor versions a kubelet may be behind
+// an API server of the given version: three since 1.28, two before.
+func kubeletSkewLimit(apiServer *version.Version) int {
+	if apiServer.AtLeast(version.MajorMinor(1, 28)) {
+		return 3
+	}
+	return 2
+}
+
+// kubeletSkewSupported says whether a kubelet is supported with an API
+// server. A kubelet must never be newer than the API server.
+func kubeletSkewSupported(apiServer, kubelet *version.Version) bool {
+	skew := minorSkew(apiServer, kubelet)
+	return skew >= 0 && skew <= kubeletSkewLimit(apiServer)
+}
+
+// controlPlaneFeatureGates reads the feature gates set on the command lines
+// of the static control plane pods. Clusters with a managed or hidden control
+// plane have none; that is not an error.
+func controlPlaneFeatureGates(ctx context.Context, client k8s.Client, kubeContext string, out *UpgradeReadinessOutput) error {
+	seen := map[string]bool{}
+	err := listAll(ctx, client, kubeContext, "kube-system", "pods", "", k8s.ListOptions{LabelSelector: controlPlaneSelector}, func(pod *corev1.Pod) {
+		for _, c := range pod.Spec.Containers {
+			component := pod.Labels["component"]
+			if component == "" {
+				component = c.Name
+			}
+			for _, gate := range parseFeatureGates(append(append([]string{}, c.Command...), c.Args...)) {
+				gate.Component = component
+				key := fmt.Sprintf("%s/%s=%t", component, gate.Name, gate.Enabled)
+				if !seen[key] {
+					seen[key] = true
+					out.FeatureGates = append(out.FeatureGates, gate)
+				}
+			}
+		}
+	})
+	if err != nil {
+		if errors.Is(err, k8s.ErrUnknownResourceType) {
+			return nil
+		}
+		return err
+	}
+
+	sort.Slice(out.FeatureGates, func(i, j int) bool {
+		a, b := out.FeatureGates[i], out.FeatureGates[j]
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		return a.Name < b.Name
+	})
+
+	var byComponent []string
+	for i, gate := range out.FeatureGates {
+		if i == 0 || out.FeatureGates[i-1].Component != gate.Component {
+			byComponent = append(byComponent, gate.Component)
+		}
+	}
+	for _, component := range byComponent {
+		var gates []string
+		for _, gate := range out.FeatureGates {
+			if gate.Component == component {
+				gates = append(gates, fmt.Sprintf("%s=%t", gate.Name, gate.Enabled))
+			}
+		}
+		out.Warnings = append(out.Warnings, fmt.Sprintf("%s sets feature gates %s; check they still exist in %s", component, strings.Join(gates, ","), out.TargetVersion))
+	}
+	return nil
+}
+
+// parseFeatureGates returns the feature gates of a command line, given as
+// --feature-gates=A=true,B=false or --feature-gates A=true.
+func parseFeatureGates(args []string) []FeatureGate {
+	var gates []FeatureGate
+	for i, arg := range args {
+		value, ok := strings.CutPrefix(arg, "--feature-gates=")
+		if !ok {
+			if arg != "--feature-gates" || i+1 >= len(args) {
+				continue
+			}
+			value = args[i+1]
+		}
+		for _, pair := range strings.Split(value, ",") {
+			name, enabled, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || name == "" {
+				continue
+			}
+			gates = append(gates, FeatureGate{Name: name, Enabled: strings.EqualFold(enabled, "true")})
+		}
+	}
+	return gates
+}
@@ -0,0 +1,140 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// newUpgradeClient returns a client running version that serves objects
+// per resource type, keyed "resource.group/version" where the tool asks
+// for an API version.
+func newUpgradeClient(version string, objects map[string][]runtime.Object) *mcptest.FakeClient {
+	client := mcptest.NewStrictFakeClient(objects)
+	client.Health = &k8s.ClusterHealth{Status: "healthy", Version: version}
+	return client
+}
+
+func kubeletNode(name, kubelet string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: kubelet}},
+	}
+}
+
+func TestCheckUpgradeReadiness(t *testing.T) {
+	client := newUpgradeClient("v1.28.4", map[string][]runtime.Object{
+		"nodes": {
+			kubeletNode("worker-1", "v1.28.4"),
+			kubeletNode("worker-2", "v1.25.9"),
+			kubeletNode("worker-3", "v1.27.2-eks-1"),
+		},
+		"pods": {&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-apiserver-cp-1", Labels: map[string]string{"component": "kube-apiserver", "tier": "control-plane"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:    "kube-apiserver",
+				Command: []string{"kube-apiserver", "--feature-gates=ValidatingAdmissionPolicy=true,InPlacePodVerticalScaling=false"},
+			}}},
+		}},
+		"flowschemas.flowcontrol.apiserver.k8s.io/v1": {
+			deprecatedObject("flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "", "custom", nil),
+		},
+	})
+
+	req, errMsg := parseUpgradeReadinessRequest(map[string]interface{}{})
+	require.Empty(t, errMsg)
+	out, err := checkUpgradeReadiness(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.29", out.TargetVersion, "defaults to the next minor version")
+	assert.False(t, out.Ready)
+	assert.Equal(t, 3, out.TotalNodes)
+
+	require.Len(t, out.Kubelets, 3)
+	oldest := out.Kubelets[0]
+	assert.Equal(t, "v1.25.9", oldest.Version)
+	assert.Equal(t, 3, oldest.Skew)
+	assert.True(t, oldest.Supported)
+	assert.False(t, oldest.SupportedAtTarget)
+	assert.Equal(t, []string{"worker-2"}, oldest.NodeNames)
+	assert.Equal(t, "v1.27.2-eks-1", out.Kubelets[1].Version)
+	assert.True(t, out.Kubelets[1].SupportedAtTarget)
+
+	assert.Equal(t, []string{
+		"1 nodes run kubelet v1.25.9, more than 3 minor versions behind 1.29; upgrade them first",
+		"1 FlowSchema objects use flowcontrol.apiserver.k8s.io/v1beta2, removed in 1.29; migrate them to flowcontrol.apiserver.k8s.io/v1",
+	}, out.Blockers)
+	require.Len(t, out.DeprecatedAPIs, 1)
+	assert.Equal(t, 1, out.DeprecatedAPIs[0].Objects)
+
+	assert.Equal(t, []FeatureGate{
+		{Component: "kube-apiserver", Name: "InPlacePodVerticalScaling", Enabled: false},
+		{Component: "kube-apiserver", Name: "ValidatingAdmissionPolicy", Enabled: true},
+	}, out.FeatureGates)
+	assert.Contains(t, out.Warnings, "kube-apiserver sets feature gates InPlacePodVerticalScaling=false,ValidatingAdmissionPolicy=true; check they still exist in 1.29")
+	assert.Contains(t, out.Warnings, "1 nodes run kubelet v1.27.2-eks-1, 1 minor versions behind the API server")
+	assert.Contains(t, out.Warnings, "nodes run 3 different kubelet versions")
+	assert.Empty(t, out.Skipped)
+}
+
+func TestCheckUpgradeReadiness_Target(t *testing.T) {
+	client := newUpgradeClient("v1.30.1", map[string][]runtime.Object{
+		"nodes": {kubeletNode("worker-1", "v1.31.0")},
+	})
+
+	req, _ := parseUpgradeReadinessRequest(map[string]interface{}{"targetVersion": "v1.32.0"})
+	out, err := checkUpgradeReadiness(context.Background(), client, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.32", out.TargetVersion)
+	assert.Equal(t, []string{"1 nodes run kubelet v1.31.0, newer than the API server v1.30.1"}, out.Blockers)
+	assert.Contains(t, out.Warnings, "the control plane upgrades one minor version at a time: upgrade to 1.31 first")
+	assert.Empty(t, out.FeatureGates, "a control plane without static pods has no feature gates")
+}
+
+func TestVersionMatrix(t *testing.T) {
+	out := &FleetUpgradeReadinessOutput{Clusters: []UpgradeReadinessOutput{
+		{Cluster: "prod", ServerVersion: "v1.30.2"},
+		{Cluster: "broken", Error: "unreachable"},
+		{Cluster: "staging", ServerVersion: "v1.31.0"},
+		{Cluster: "legacy", ServerVersion: "v1.28.9"},
+	}}
+	versionMatrix(out)
+
+	assert.Equal(t, "1.31.0", out.NewestVersion)
+	var order []string
+	for _, c := range out.Clusters {
+		order = append(order, c.Cluster)
+	}
+	assert.Equal(t, []string{"legacy", "prod", "staging", "broken"}, order)
+	assert.Equal(t, 3, *out.Clusters[0].MinorsBehind)
+	assert.Equal(t, 0, *out.Clusters[2].MinorsBehind)
+	assert.Nil(t, out.Clusters[3].MinorsBehind)
+}
+
+func TestParseFeatureGates(t *testing.T) {
+	gates := parseFeatureGates([]string{"--v=2", "--feature-gates", "A=true, B=False", "--feature-gates=C=TRUE,broken"})
+	assert.Equal(t, []FeatureGate{{Name: "A", Enabled: true}, {Name: "B"}, {Name: "C", Enabled: true}}, gates)
+}
+
+func TestParseUpgradeReadinessRequest(t *testing.T) {
+	_, errMsg := parseUpgradeReadinessRequest(map[string]interface{}{"targetVersion": "latest"})
+	assert.Contains(t, errMsg, "invalid targetVersion")
+
+	_, errMsg = parseUpgradeReadinessRequest(map[string]interface{}{"cluster": "a", "clusters": "b"})
+	assert.Contains(t, errMsg, "either cluster or clusters")
+}
+
+func TestUpgradeReadiness_FleetDeniedCluster(t *testing.T) {
+	result := callTool(t, mcptest.NewFakeClient(nil), "upgrade_readiness", map[string]interface{}{"clusters": "prod-a,prod-b"}, denyCluster(t, "prod-b")...)
+	assert.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "cluster prod-b: upgrade_readiness")
+}
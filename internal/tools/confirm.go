@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/confirmation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// ConfirmToolName is the tool that runs an operation awaiting confirmation.
+const ConfirmToolName = "confirm"
+
+// ConfirmationRequest is returned instead of running a destructive call
+// when confirmations are enabled.
+type ConfirmationRequest struct {
+	ConfirmationRequired bool      `json:"confirmationRequired"`
+	Token                string    `json:"token"`
+	Tool                 string    `json:"tool"`
+	Summary              string    `json:"summary"`
+	ExpiresAt            time.Time `json:"expiresAt"`
+	Message              string    `json:"message"`
+	// Preview is the result of the call run as a server-side dry-run.
+	Preview any `json:"preview,omitempty"`
+}
+
+// confirmedKey marks the context of a confirmed operation.
+type confirmedKey struct{}
+
+// ContextWithConfirmed marks ctx as the context of a call that the caller
+// already confirmed, such as an operation of a plan they reviewed and
+// applied, so that it runs without asking for confirmation again.
+func ContextWithConfirmed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, confirmedKey{}, true)
+}
+
+// requiresConfirmation reports whether a call must be confirmed before it
+// runs: confirmations are enabled, the call is destructive (delete,
+// delete_namespace, drain, or scale to zero replicas) and it is neither a
+// dry-run nor already confirmed.
+func requiresConfirmation(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}) bool {
+	if sc.Confirmations() == nil {
+		return false
+	}
+	if confirmed, _ := ctx.Value(confirmedKey{}).(bool); confirmed || IsDryRun(ctx, sc) {
+		return false
+	}
+	switch primaryToolName(toolName) {
+	case "delete", "delete_namespace", "drain":
+		return true
+	case "scale":
+		replicas, ok := args["replicas"].(float64)
+		return ok && replicas == 0
+	}
+	return false
+}
+
+// callOrPropose runs the handler of a tool call, or proposes the call for
+// confirmation when it requires one.
+func callOrPropose(
+	ctx context.Context,
+	toolName string,
+	handler ToolHandler,
+	sc *server.ServerContext,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	if !requiresConfirmation(ctx, sc, toolName, request.GetArguments()) {
+		return callWithTimeout(ctx, toolName, handler, sc, request)
+	}
+	return proposeConfirmation(ctx, toolName, handler, sc, request)
+}
+
+// proposeConfirmation previews a destructive call as a server-side dry-run
+// and parks it under a confirmation token. A call whose preview fails is
+// not parked, since it would fail when confirmed too.
+func proposeConfirmation(
+	ctx context.Context,
+	toolName string,
+	handler ToolHandler,
+	sc *server.ServerContext,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	preview, err := callWithTimeout(k8s.ContextWithDryRun(ctx), toolName, handler, sc, request)
+	if err != nil || preview == nil || preview.IsError {
+		return preview, err
+	}
+
+	args := request.GetArguments()
+	store := sc.Confirmations()
+	op := &confirmation.Operation{
+		Tool:   toolName,
+		Caller: rateLimitCaller(ctx).Identity(),
+		Run: func(ctx context.Context) (*mcp.CallToolResult, error) {
+			// The confirmed call goes through every check again, as the
+			// caller's permissions or the policy may have changed.
+			ctx = ContextWithConfirmed(ctx)
+			return invokeWithAuditLogging(ctx, toolName, handler, sc, request)
+		},
+	}
+	if err := store.Propose(op); err != nil {
+		if errors.Is(err, confirmation.ErrTooManyPending) {
+			return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+		}
+		return toolerrors.New(toolerrors.CodeInternal, err.Error()).Result(), nil
+	}
+
+	response := ConfirmationRequest{
+		ConfirmationRequired: true,
+		Token:                op.Token,
+		Tool:                 toolName,
+		Summary:              confirmationSummary(toolName, args),
+		ExpiresAt:            op.ExpiresAt,
+		Message: fmt.Sprintf("Nothing was changed. Review the preview, then call %s with this token within %s to run %s.",
+			ConfirmToolName, store.TTL(), toolName),
+		Preview: previewContent(preview),
+	}
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return toolerrors.New(toolerrors.CodeInternal, fmt.Sprintf("failed to marshal confirmation request: %v", err)).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// confirmationSummary describes the target of a destructive call.
+func confirmationSummary(toolName string, args map[string]interface{}) string {
+	parts := []string{toolName}
+	if resourceType, _ := args["resourceType"].(string); resourceType != "" {
+		parts = append(parts, resourceType+"/"+extractResourceName(args))
+	} else if name := extractResourceName(args); name != "" {
+		parts = append(parts, name)
+	}
+	switch primaryToolName(toolName) {
+	case "delete_namespace":
+		if namespace, _ := args["namespace"].(string); namespace != "" {
+			parts = append(parts, "namespace "+namespace+" and everything in it")
+		}
+	case "scale":
+		parts = append(parts, "to 0 replicas")
+		fallthrough
+	default:
+		if namespace, _ := args["namespace"].(string); namespace != "" {
+			parts = append(parts, "in namespace "+namespace)
+		}
+	}
+	if cluster, _ := args["cluster"].(string); cluster != "" {
+		parts = append(parts, "on cluster "+cluster)
+	} else if kubeContext, _ := args["kubeContext"].(string); kubeContext != "" {
+		parts = append(parts, "in context "+kubeContext)
+	}
+	return strings.Join(parts, " ")
+}
+
+// previewContent returns the text of a dry-run result, decoded when it is
+// JSON.
+func previewContent(result *mcp.CallToolResult) any {
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if json.Valid([]byte(text.Text)) {
+			return json.RawMessage(text.Text)
+		}
+		return text.Text
+	}
+	return nil
+}
+
+// RegisterConfirmTool registers the confirm tool when confirmations are
+// enabled.
+func RegisterConfirmTool(s *mcpserver.MCPServer, sc *server.ServerContext) {
+	if sc.Confirmations() == nil {
+		return
+	}
+	s.AddTool(mcp.NewTool(ConfirmToolName,
+		mcp.WithDescription("Run a destructive operation (delete, delete_namespace, drain, or scale to 0 replicas) proposed by an earlier call. Those calls only preview the change and return a confirmation token; pass the token here before it expires to run the operation. Tokens are single-use and only valid for the caller they were issued to."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString("token",
+			mcp.Required(),
+			mcp.Description("Confirmation token returned by the destructive call"),
+		),
+	), WrapWithAuditLogging(ConfirmToolName, handleConfirm, sc))
+}
+
+// handleConfirm runs the operation of a confirmation token.
+func handleConfirm(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	store := sc.Confirmations()
+	if store == nil {
+		return toolerrors.New(toolerrors.CodeNotEnabled, "confirmations are not enabled on this server").Result(), nil
+	}
+	token, err := request.RequireString("token")
+	if err != nil {
+		return toolerrors.InvalidArgument(err.Error()).Result(), nil
+	}
+
+	op, err := store.Take(token, rateLimitCaller(ctx).Identity())
+	switch {
+	case errors.Is(err, confirmation.ErrNotFound):
+		return toolerrors.New(toolerrors.CodeNotFound, err.Error()+"; call the destructive tool again for a new token").Result(), nil
+	case errors.Is(err, confirmation.ErrCallerMismatch):
+		return toolerrors.New(toolerrors.CodeForbidden, err.Error()).Result(), nil
+	case err != nil:
+		return toolerrors.New(toolerrors.CodeInternal, err.Error()).Result(), nil
+	}
+	return op.Run(ctx)
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/confirmation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+func TestWrapWithAuditLogging_ConfirmsDestructiveCalls(t *testing.T) {
+	var calls []bool
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		calls = append(calls, IsDryRun(ctx, sc))
+		return mcp.NewToolResultText(`{"message":"Resource deployments/web deleted successfully"}`), nil
+	}
+
+	sc := newVisibilityServerContext(t, server.WithConfirmations(confirmation.NewStore(time.Minute)))
+	deleteTool := WrapWithAuditLogging("delete", handler, sc)
+	confirmTool := WrapWithAuditLogging(ConfirmToolName, handleConfirm, sc)
+	ctx := middleware.ContextWithAuthenticatedSubject(context.Background(), "agent")
+
+	result, err := deleteTool(ctx, createTestRequest(map[string]interface{}{
+		"namespace": "prod", "resourceType": "deployments", "name": "web",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, []bool{true}, calls, "the proposal runs the call as a dry-run")
+
+	var proposal struct {
+		ConfirmationRequest
+		Preview map[string]any `json:"preview"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &proposal))
+	assert.True(t, proposal.ConfirmationRequired)
+	assert.Equal(t, "delete deployments/web in namespace prod", proposal.Summary)
+	assert.Equal(t, "Resource deployments/web deleted successfully", proposal.Preview["message"])
+
+	// Only the caller that was given the token can confirm it.
+	other := middleware.ContextWithAuthenticatedSubject(context.Background(), "someone-else")
+	result, err = confirmTool(other, createTestRequest(map[string]interface{}{"token": proposal.Token}))
+	require.NoError(t, err)
+	assert.Equal(t, toolerrors.CodeForbidden, toolerrors.FromResult(result).Code)
+
+	result, err = confirmTool(ctx, createTestRequest(map[string]interface{}{"token": proposal.Token}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, []bool{true, false}, calls, "the confirmed call runs for real")
+
+	result, err = confirmTool(ctx, createTestRequest(map[string]interface{}{"token": proposal.Token}))
+	require.NoError(t, err)
+	assert.Equal(t, toolerrors.CodeNotFound, toolerrors.FromResult(result).Code, "tokens are single-use")
+	assert.Len(t, calls, 2)
+}
+
+func TestRequiresConfirmation(t *testing.T) {
+	sc := newVisibilityServerContext(t, server.WithConfirmations(confirmation.NewStore(time.Minute)))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		tool string
+		args map[string]interface{}
+		want bool
+	}{
+		{name: "delete", tool: "delete", want: true},
+		{name: "deprecated delete alias", tool: "kubernetes_delete", want: true},
+		{name: "delete_namespace", tool: "delete_namespace", want: true},
+		{name: "drain", tool: "drain", want: true},
+		{name: "scale to zero", tool: "scale", args: map[string]interface{}{"replicas": float64(0)}, want: true},
+		{name: "scale up", tool: "scale", args: map[string]interface{}{"replicas": float64(3)}},
+		{name: "apply", tool: "apply"},
+		{name: "cordon", tool: "cordon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, requiresConfirmation(ctx, sc, tt.tool, tt.args))
+		})
+	}
+
+	t.Run("dry-runs need no confirmation", func(t *testing.T) {
+		dryRun := withDryRun(ctx, map[string]interface{}{ParamDryRun: true})
+		assert.False(t, requiresConfirmation(dryRun, sc, "delete", nil))
+	})
+
+	t.Run("disabled without a store", func(t *testing.T) {
+		assert.False(t, requiresConfirmation(ctx, newVisibilityServerContext(t), "delete", nil))
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // handleListContexts handles kubectl context list operations
@@ -16,17 +17,17 @@ func handleListContexts(ctx context.Context, request mcp.CallToolRequest, sc *se
 	// Use appropriate k8s client (per-user if OAuth downstream enabled)
 	k8sClient, err := tools.GetK8sClient(ctx, sc)
 	if err != nil {
-		return mcp.NewToolResultError(tools.FormatAuthenticationError(err)), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, tools.FormatAuthenticationError(err)).Result(), nil
 	}
 	contexts, err := k8sClient.ListContexts(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to list contexts: %v", err)), nil
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to list contexts: %v", err)).Result(), nil
 	}
 
 	// Convert contexts to JSON for output
 	jsonData, err := json.MarshalIndent(contexts, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal contexts: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal contexts: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -37,17 +38,17 @@ func handleGetCurrentContext(ctx context.Context, request mcp.CallToolRequest, s
 	// Use appropriate k8s client (per-user if OAuth downstream enabled)
 	k8sClient, err := tools.GetK8sClient(ctx, sc)
 	if err != nil {
-		return mcp.NewToolResultError(tools.FormatAuthenticationError(err)), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, tools.FormatAuthenticationError(err)).Result(), nil
 	}
 	currentContext, err := k8sClient.GetCurrentContext(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get current context: %v", err)), nil
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to get current context: %v", err)).Result(), nil
 	}
 
 	// Convert current context to JSON for output
 	jsonData, err := json.MarshalIndent(currentContext, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal current context: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal current context: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -59,17 +60,17 @@ func handleUseContext(ctx context.Context, request mcp.CallToolRequest, sc *serv
 
 	contextName, ok := args["contextName"].(string)
 	if !ok || contextName == "" {
-		return mcp.NewToolResultError("contextName is required"), nil
+		return toolerrors.Required("contextName").Result(), nil
 	}
 
 	// Use appropriate k8s client (per-user if OAuth downstream enabled)
 	k8sClient, err := tools.GetK8sClient(ctx, sc)
 	if err != nil {
-		return mcp.NewToolResultError(tools.FormatAuthenticationError(err)), nil
+		return toolerrors.New(toolerrors.CodeUnauthenticated, tools.FormatAuthenticationError(err)).Result(), nil
 	}
 	err = k8sClient.SwitchContext(ctx, contextName)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to switch context: %v", err)), nil
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to switch context: %v", err)).Result(), nil
 	}
 
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully switched to context: %s", contextName)), nil
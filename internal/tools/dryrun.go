@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// ParamDryRun is the tool parameter that runs a mutating call as a
+// server-side dry-run.
+const ParamDryRun = "dryRun"
+
+// DryRunParam returns the dryRun parameter of mutating tools. A call with
+// dryRun=true is validated by the API server, including admission, without
+// being persisted. It cannot turn off the server's --dry-run mode.
+func DryRunParam() mcp.ToolOption {
+	return mcp.WithBoolean(ParamDryRun,
+		mcp.Description("Preview the change as a server-side dry-run: it is validated and admitted by the API server but not persisted (default: false). Cannot turn off a server running in dry-run mode."),
+	)
+}
+
+// IsDryRun reports whether mutations of the tool call with ctx are
+// dry-runs, because the server runs in dry-run mode or the call asked for it.
+func IsDryRun(ctx context.Context, sc *server.ServerContext) bool {
+	if cfg := sc.Config(); cfg != nil && cfg.DryRun {
+		return true
+	}
+	return k8s.DryRunFromContext(ctx)
+}
+
+// withDryRun marks ctx for a dry-run when the call's dryRun argument is true.
+func withDryRun(ctx context.Context, args map[string]interface{}) context.Context {
+	if dryRun, _ := args[ParamDryRun].(bool); dryRun {
+		return k8s.ContextWithDryRun(ctx)
+	}
+	return ctx
+}
+
+// withDryRunMeta reports in the result's _meta that the call was a dry-run,
+// so that callers can tell a preview from a real change.
+func withDryRunMeta(result *mcp.CallToolResult, dryRun bool) *mcp.CallToolResult {
+	if result == nil || !dryRun {
+		return result
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[ParamDryRun] = true
+	return result
+}
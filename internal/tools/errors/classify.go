@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+// FromError returns an error with the given message and the code that
+// CodeOf classifies err as.
+func FromError(err error, message string) *Error {
+	return New(CodeOf(err), message)
+}
+
+// CodeOf classifies a Go error returned by the Kubernetes client or the
+// federation manager. Errors it does not recognise are CodeInternal.
+func CodeOf(err error) Code {
+	if err == nil {
+		return CodeInternal
+	}
+	var toolErr *Error
+	if errors.As(err, &toolErr) {
+		return toolErr.Code
+	}
+
+	// Federation errors come first: they may wrap Kubernetes API errors,
+	// such as a missing kubeconfig Secret, that must not show through.
+	if code, ok := federationCode(err); ok {
+		return code
+	}
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return CodeNotFound
+	case apierrors.IsAlreadyExists(err):
+		return CodeAlreadyExists
+	case apierrors.IsConflict(err):
+		return CodeConflict
+	case apierrors.IsForbidden(err):
+		return CodeForbidden
+	case apierrors.IsUnauthorized(err):
+		return CodeUnauthenticated
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err), apierrors.IsRequestEntityTooLargeError(err):
+		return CodeInvalidArgument
+	case apierrors.IsTooManyRequests(err):
+		return CodeRateLimited
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeCancelled
+	case apierrors.IsServiceUnavailable(err):
+		return CodeUnavailable
+	case apierrors.IsMethodNotSupported(err), apierrors.IsGone(err):
+		return CodeFailedPrecondition
+	case errors.Is(err, k8s.ErrContextNotAllowed):
+		return CodeInvalidArgument
+	case errors.Is(err, k8s.ErrExecPluginNotAllowed):
+		return CodeFailedPrecondition
+	case errors.Is(err, k8s.ErrExecPluginTimeout):
+		return CodeTimeout
+	case errors.Is(err, k8s.ErrExecPluginFailed):
+		return CodeUnauthenticated
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return CodeTimeout
+		}
+		return CodeUnavailable
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return CodeUnavailable
+	}
+	return CodeInternal
+}
+
+// federationCode classifies the errors of the federation package.
+func federationCode(err error) (Code, bool) {
+	var accessDenied *federation.AccessDeniedError
+	var impersonation *federation.ImpersonationError
+	switch {
+	case errors.As(err, &accessDenied), errors.As(err, &impersonation), errors.Is(err, federation.ErrAccessDenied):
+		return CodeForbidden, true
+	case errors.Is(err, federation.ErrConnectionTimeout):
+		// Checked before the cluster errors below, which a
+		// ConnectivityTimeoutError also matches.
+		return CodeTimeout, true
+	case errors.Is(err, federation.ErrClusterNotFound),
+		errors.Is(err, federation.ErrKubeconfigSecretNotFound),
+		errors.Is(err, federation.ErrKubeconfigInvalid),
+		errors.Is(err, federation.ErrConnectionFailed),
+		errors.Is(err, federation.ErrClusterUnreachable),
+		errors.Is(err, federation.ErrTLSHandshakeFailed):
+		return CodeClusterUnavailable, true
+	case errors.Is(err, federation.ErrAccessCheckFailed), errors.Is(err, federation.ErrManagerClosed):
+		return CodeUnavailable, true
+	case errors.Is(err, federation.ErrConcurrencyLimitExceeded):
+		return CodeRateLimited, true
+	case errors.Is(err, federation.ErrUserInfoRequired),
+		errors.Is(err, federation.ErrSSOTokenMissing),
+		errors.Is(err, federation.ErrTokenExchangeFailed):
+		return CodeUnauthenticated, true
+	case errors.Is(err, federation.ErrInvalidClusterName), errors.Is(err, federation.ErrInvalidAccessCheck):
+		return CodeInvalidArgument, true
+	case errors.Is(err, federation.ErrImpersonationFailed):
+		return CodeForbidden, true
+	}
+	return "", false
+}
@@ -0,0 +1,260 @@
+// Package errors defines the structured errors returned by MCP tools.
+//
+// Every failed tool call carries an [Error] envelope next to its message, so
+// clients and agents can branch on a stable code and category, and know
+// whether a retry may help, instead of parsing English:
+//
+//	{
+//	  "error": {
+//	    "code": "FORBIDDEN",
+//	    "category": "permission_denied",
+//	    "retryable": false,
+//	    "message": "Failed to get resource: pods \"web-0\" is forbidden: ...",
+//	    "traceId": "4bf92f3577b34da6a3ce929d0e0e4736"
+//	  }
+//	}
+//
+// Handlers build errors with [New] or one of its shorthands, or classify a
+// Go error with [FromError], and return [Error.Result]:
+//
+//	if namespace == "" {
+//	    return toolerrors.Required("namespace").Result(), nil
+//	}
+//
+// The envelope is carried in the result's structured content. [Finalize],
+// called once per tool call by the audit wrapper, adds the trace ID, gives
+// results that were not built here an INTERNAL envelope, and appends the
+// envelope as JSON text for clients that only read text content.
+//
+// The package is imported as toolerrors to keep it apart from the standard
+// library errors package.
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Category groups error codes by what the caller can do about them. New
+// codes may be added over time; the categories are meant to stay stable.
+type Category string
+
+const (
+	// CategoryInvalidArgument means the call itself is wrong and must be changed.
+	CategoryInvalidArgument Category = "invalid_argument"
+
+	// CategoryNotFound means the object the call refers to does not exist.
+	CategoryNotFound Category = "not_found"
+
+	// CategoryConflict means the object exists or was changed concurrently.
+	CategoryConflict Category = "conflict"
+
+	// CategoryPermissionDenied means the caller is not allowed to do this, by
+	// Kubernetes RBAC, the server's policy or its safety settings.
+	CategoryPermissionDenied Category = "permission_denied"
+
+	// CategoryUnauthenticated means the caller has no valid identity.
+	CategoryUnauthenticated Category = "unauthenticated"
+
+	// CategoryRateLimited means too many requests were made; retry later.
+	CategoryRateLimited Category = "rate_limited"
+
+	// CategoryUnavailable means a cluster or service could not be reached.
+	CategoryUnavailable Category = "unavailable"
+
+	// CategoryTimeout means the operation did not finish in time.
+	CategoryTimeout Category = "timeout"
+
+	// CategoryFailedPrecondition means the call is valid but the system is
+	// not in a state that allows it, e.g. a feature is not enabled.
+	CategoryFailedPrecondition Category = "failed_precondition"
+
+	// CategoryCancelled means the caller cancelled the call.
+	CategoryCancelled Category = "cancelled"
+
+	// CategoryInternal means the server failed unexpectedly.
+	CategoryInternal Category = "internal"
+)
+
+// Code identifies a kind of error more precisely than its category.
+type Code string
+
+const (
+	CodeInvalidArgument     Code = "INVALID_ARGUMENT"
+	CodeNotFound            Code = "NOT_FOUND"
+	CodeAlreadyExists       Code = "ALREADY_EXISTS"
+	CodeConflict            Code = "CONFLICT"
+	CodeForbidden           Code = "FORBIDDEN"
+	CodePolicyDenied        Code = "POLICY_DENIED"
+	CodeOperationNotAllowed Code = "OPERATION_NOT_ALLOWED"
+	CodeUnauthenticated     Code = "UNAUTHENTICATED"
+	CodeRateLimited         Code = "RATE_LIMITED"
+	CodeClusterUnavailable  Code = "CLUSTER_UNAVAILABLE"
+	CodeUnavailable         Code = "UNAVAILABLE"
+	CodeTimeout             Code = "TIMEOUT"
+	CodeFailedPrecondition  Code = "FAILED_PRECONDITION"
+	CodeNotEnabled          Code = "NOT_ENABLED"
+	CodeWrongReplica        Code = "WRONG_REPLICA"
+	CodeCancelled           Code = "CANCELLED"
+	CodeInternal            Code = "INTERNAL"
+)
+
+// codeInfo holds the category of a code and whether a retry may succeed.
+type codeInfo struct {
+	category  Category
+	retryable bool
+}
+
+var codes = map[Code]codeInfo{
+	CodeInvalidArgument:     {CategoryInvalidArgument, false},
+	CodeNotFound:            {CategoryNotFound, false},
+	CodeAlreadyExists:       {CategoryConflict, false},
+	CodeConflict:            {CategoryConflict, true},
+	CodeForbidden:           {CategoryPermissionDenied, false},
+	CodePolicyDenied:        {CategoryPermissionDenied, false},
+	CodeOperationNotAllowed: {CategoryPermissionDenied, false},
+	CodeUnauthenticated:     {CategoryUnauthenticated, false},
+	CodeRateLimited:         {CategoryRateLimited, true},
+	// A missing cluster is reported like an unreachable one so that errors
+	// do not reveal which clusters exist; see federation.ClusterNotFoundError.
+	CodeClusterUnavailable: {CategoryUnavailable, false},
+	CodeUnavailable:        {CategoryUnavailable, true},
+	CodeTimeout:            {CategoryTimeout, true},
+	CodeFailedPrecondition: {CategoryFailedPrecondition, false},
+	CodeNotEnabled:         {CategoryFailedPrecondition, false},
+	CodeWrongReplica:       {CategoryFailedPrecondition, false},
+	CodeCancelled:          {CategoryCancelled, false},
+	CodeInternal:           {CategoryInternal, false},
+}
+
+// Codes returns all error codes.
+func Codes() []Code {
+	all := make([]Code, 0, len(codes))
+	for code := range codes {
+		all = append(all, code)
+	}
+	return all
+}
+
+// Error is the structured error of a failed tool call.
+type Error struct {
+	Code      Code     `json:"code"`
+	Category  Category `json:"category"`
+	Retryable bool     `json:"retryable"`
+
+	// Message is the user-facing description, the same text the result
+	// carries as its content.
+	Message string `json:"message"`
+
+	// TraceID correlates the error with the server's traces and audit log.
+	// It is set by Finalize when tracing is enabled.
+	TraceID string `json:"traceId,omitempty"`
+}
+
+// Envelope is the structured content of an error result.
+type Envelope struct {
+	Error *Error `json:"error"`
+}
+
+// New returns an error with the given code. Unknown codes are treated as
+// CodeInternal.
+func New(code Code, message string) *Error {
+	info, ok := codes[code]
+	if !ok {
+		code, info = CodeInternal, codes[CodeInternal]
+	}
+	return &Error{
+		Code:      code,
+		Category:  info.category,
+		Retryable: info.retryable,
+		Message:   message,
+	}
+}
+
+// Newf is New with a formatted message.
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// InvalidArgument returns a CodeInvalidArgument error.
+func InvalidArgument(message string) *Error {
+	return New(CodeInvalidArgument, message)
+}
+
+// InvalidArgumentf is InvalidArgument with a formatted message.
+func InvalidArgumentf(format string, args ...any) *Error {
+	return Newf(CodeInvalidArgument, format, args...)
+}
+
+// Required returns the error for a missing required parameter.
+func Required(param string) *Error {
+	return New(CodeInvalidArgument, param+" is required")
+}
+
+// Internal returns a CodeInternal error.
+func Internal(message string) *Error {
+	return New(CodeInternal, message)
+}
+
+// Internalf is Internal with a formatted message.
+func Internalf(format string, args ...any) *Error {
+	return Newf(CodeInternal, format, args...)
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Result returns the error as an MCP tool error result.
+func (e *Error) Result() *mcp.CallToolResult {
+	// Finalize sets the trace ID on the envelope, so it gets its own copy.
+	envelopeErr := *e
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent(e.Message)},
+		StructuredContent: Envelope{Error: &envelopeErr},
+		IsError:           true,
+	}
+}
+
+// FromResult returns the error carried by a result built with Error.Result,
+// or nil if it has none.
+func FromResult(result *mcp.CallToolResult) *Error {
+	if result == nil {
+		return nil
+	}
+	if envelope, ok := result.StructuredContent.(Envelope); ok {
+		return envelope.Error
+	}
+	return nil
+}
+
+// Finalize completes an error result before it is sent: it sets the trace
+// ID, gives a result without an envelope a CodeInternal one built from its
+// text, and appends the envelope as JSON text content. Results that are not
+// errors are returned unchanged.
+func Finalize(result *mcp.CallToolResult, traceID string) *mcp.CallToolResult {
+	if result == nil || !result.IsError {
+		return result
+	}
+	e := FromResult(result)
+	if e == nil {
+		var message string
+		if len(result.Content) > 0 {
+			if text, ok := result.Content[0].(mcp.TextContent); ok {
+				message = text.Text
+			}
+		}
+		e = New(CodeInternal, message)
+		result.StructuredContent = Envelope{Error: e}
+	}
+	e.TraceID = traceID
+
+	data, err := json.Marshal(Envelope{Error: e})
+	if err == nil {
+		result.Content = append(result.Content, mcp.NewTextContent(string(data)))
+	}
+	return result
+}
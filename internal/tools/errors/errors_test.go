@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+func TestNew(t *testing.T) {
+	for _, code := range Codes() {
+		e := New(code, "message")
+		assert.Equal(t, code, e.Code)
+		assert.NotEmpty(t, e.Category, "code %s has no category", code)
+	}
+
+	e := New(CodeConflict, "changed")
+	assert.Equal(t, CategoryConflict, e.Category)
+	assert.True(t, e.Retryable)
+
+	e = New(CodeClusterUnavailable, "cluster access denied or unavailable")
+	assert.Equal(t, CategoryUnavailable, e.Category)
+	assert.False(t, e.Retryable, "a missing cluster must look like an unreachable one")
+
+	e = New(Code("BOGUS"), "message")
+	assert.Equal(t, CodeInternal, e.Code)
+	assert.Equal(t, CategoryInternal, e.Category)
+
+	assert.Equal(t, "namespace is required", Required("namespace").Message)
+	assert.Equal(t, CodeInvalidArgument, Required("namespace").Code)
+}
+
+func TestResult(t *testing.T) {
+	e := InvalidArgumentf("sample must be between 1 and %d", 50)
+	result := e.Result()
+
+	assert.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "sample must be between 1 and 50", result.Content[0].(mcp.TextContent).Text)
+
+	got := FromResult(result)
+	require.NotNil(t, got)
+	assert.Equal(t, CodeInvalidArgument, got.Code)
+	assert.NotSame(t, e, got, "the envelope holds its own copy")
+
+	assert.Nil(t, FromResult(mcp.NewToolResultError("plain")))
+	assert.Nil(t, FromResult(nil))
+}
+
+func TestFinalize(t *testing.T) {
+	t.Run("adds trace ID and JSON content", func(t *testing.T) {
+		result := Finalize(New(CodeForbidden, "denied").Result(), "trace-1")
+
+		require.Len(t, result.Content, 2)
+		assert.Equal(t, "denied", result.Content[0].(mcp.TextContent).Text)
+
+		var envelope Envelope
+		require.NoError(t, json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &envelope))
+		assert.Equal(t, &Error{
+			Code:      CodeForbidden,
+			Category:  CategoryPermissionDenied,
+			Retryable: false,
+			Message:   "denied",
+			TraceID:   "trace-1",
+		}, envelope.Error)
+		assert.Equal(t, "trace-1", FromResult(result).TraceID)
+	})
+
+	t.Run("omits an empty trace ID", func(t *testing.T) {
+		result := Finalize(New(CodeNotFound, "gone").Result(), "")
+		require.Len(t, result.Content, 2)
+		assert.NotContains(t, result.Content[1].(mcp.TextContent).Text, "traceId")
+	})
+
+	t.Run("wraps plain error results as internal", func(t *testing.T) {
+		result := Finalize(mcp.NewToolResultError("something broke"), "trace-2")
+
+		e := FromResult(result)
+		require.NotNil(t, e)
+		assert.Equal(t, CodeInternal, e.Code)
+		assert.Equal(t, "something broke", e.Message)
+		assert.Equal(t, "trace-2", e.TraceID)
+		assert.Len(t, result.Content, 2)
+	})
+
+	t.Run("leaves successful results alone", func(t *testing.T) {
+		result := Finalize(mcp.NewToolResultText("ok"), "trace-3")
+		assert.Len(t, result.Content, 1)
+		assert.Nil(t, result.StructuredContent)
+		assert.Nil(t, Finalize(nil, "trace-4"))
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, CodeInternal},
+		{"unknown", fmt.Errorf("boom"), CodeInternal},
+		{"tool error", fmt.Errorf("wrapped: %w", New(CodePolicyDenied, "no")), CodePolicyDenied},
+		{"not found", apierrors.NewNotFound(pods, "web-0"), CodeNotFound},
+		{"already exists", apierrors.NewAlreadyExists(pods, "web-0"), CodeAlreadyExists},
+		{"conflict", apierrors.NewConflict(pods, "web-0", fmt.Errorf("changed")), CodeConflict},
+		{"forbidden", apierrors.NewForbidden(pods, "web-0", fmt.Errorf("rbac")), CodeForbidden},
+		{"unauthorized", apierrors.NewUnauthorized("token expired"), CodeUnauthenticated},
+		{"bad request", apierrors.NewBadRequest("bad"), CodeInvalidArgument},
+		{"too many requests", apierrors.NewTooManyRequests("slow down", 1), CodeRateLimited},
+		{"server timeout", apierrors.NewServerTimeout(pods, "list", 1), CodeTimeout},
+		{"deadline", fmt.Errorf("list: %w", context.DeadlineExceeded), CodeTimeout},
+		{"service unavailable", apierrors.NewServiceUnavailable("down"), CodeUnavailable},
+		{"cluster not found", &federation.ClusterNotFoundError{ClusterName: "prod"}, CodeClusterUnavailable},
+		{"kubeconfig missing", fmt.Errorf("load: %w", federation.ErrKubeconfigSecretNotFound), CodeClusterUnavailable},
+		{"connection timeout", &federation.ConnectivityTimeoutError{ClusterName: "prod"}, CodeTimeout},
+		{"access denied", &federation.AccessDeniedError{ClusterName: "prod", Verb: "delete", Resource: "pods"}, CodeForbidden},
+		{"concurrency limit", federation.ErrConcurrencyLimitExceeded, CodeRateLimited},
+		{"user info missing", federation.ErrUserInfoRequired, CodeUnauthenticated},
+		{"token exchange failed", fmt.Errorf("cluster wc1: %w", federation.ErrTokenExchangeFailed), CodeUnauthenticated},
+		{"invalid cluster name", federation.ErrInvalidClusterName, CodeInvalidArgument},
+		{"context not allowed", fmt.Errorf("context %q: %w", "prod", k8s.ErrContextNotAllowed), CodeInvalidArgument},
+		{"exec plugin not allowed", fmt.Errorf("context %q: %w", "eks", k8s.ErrExecPluginNotAllowed), CodeFailedPrecondition},
+		{"exec plugin timeout", &url.Error{Op: "Get", URL: "https://eks", Err: fmt.Errorf("getting credentials: %w", k8s.ErrExecPluginTimeout)}, CodeTimeout},
+		{"exec plugin failed", &url.Error{Op: "Get", URL: "https://eks", Err: fmt.Errorf("getting credentials: %w", k8s.ErrExecPluginFailed)}, CodeUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CodeOf(tt.err))
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	err := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web-0")
+	e := FromError(err, "Failed to get resource: pods \"web-0\" not found")
+	assert.Equal(t, CodeNotFound, e.Code)
+	assert.Equal(t, CategoryNotFound, e.Category)
+	assert.Equal(t, "Failed to get resource: pods \"web-0\" not found", e.Message)
+}
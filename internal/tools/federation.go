@@ -6,17 +6,26 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
 	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // Error message constants for consistent user-facing error messages.
 const (
-	errMsgInvalidClusterName = "invalid cluster name provided"
+	errMsgInvalidClusterName   = "invalid cluster name provided"
+	errMsgRequiresFederation   = "multi-cluster operations require federation mode to be enabled"
+	errMsgUnexpectedClusterErr = "failed to access cluster: an unexpected error occurred"
+	errMsgMCAccessRestricted   = "management cluster access is not permitted for this identity (restricted to specific workload clusters)"
 )
 
 // ClusterClient provides access to Kubernetes operations with multi-cluster support.
@@ -26,9 +35,9 @@ const (
 //
 // Tool handlers should use GetClusterClient to get the appropriate client:
 //
-//	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-//	if errMsg != "" {
-//	    return mcp.NewToolResultError(errMsg), nil
+//	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+//	if toolErr != nil {
+//	    return toolErr.Result(), nil
 //	}
 //	// Use client.K8s() for standard operations, or client.User() for user info
 type ClusterClient struct {
@@ -83,9 +92,25 @@ func (cc *ClusterClient) IsFederated() bool {
 //
 // # Return Values
 //
-// Returns (ClusterClient, "") on success or (nil, errorMessage) on failure.
-// The error message is suitable for direct use in MCP tool responses.
-func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName string) (*ClusterClient, string) {
+// Returns (ClusterClient, nil) on success or (nil, error) on failure.
+// The error is suitable for direct use in MCP tool responses.
+//
+// Acquiring the client is traced as a "federation.get_client" span.
+func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName string) (*ClusterClient, *toolerrors.Error) {
+	ctx, span := instrumentation.StartFederationSpan(ctx, "get_client", clusterName)
+	defer span.End()
+
+	client, toolErr := getClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		span.SetStatus(codes.Error, toolErr.Message)
+		return nil, toolErr
+	}
+	span.SetAttributes(attribute.Bool(instrumentation.SpanAttrFederated, client.IsFederated()))
+	return client, nil
+}
+
+// getClusterClient implements GetClusterClient.
+func getClusterClient(ctx context.Context, sc *server.ServerContext, clusterName string) (*ClusterClient, *toolerrors.Error) {
 	slog.Debug("GetClusterClient called", slog.String("cluster", clusterName))
 	fedManager := sc.FederationManager()
 
@@ -93,48 +118,38 @@ func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName
 	// and prevent unnecessary processing with invalid input
 	if clusterName != "" {
 		if err := federation.ValidateClusterName(clusterName); err != nil {
-			return nil, errMsgInvalidClusterName
+			return nil, toolerrors.InvalidArgument(errMsgInvalidClusterName)
 		}
 	}
 
 	// If a cluster is specified but federation isn't enabled, return an error
 	if clusterName != "" && fedManager == nil {
-		return nil, "multi-cluster operations require federation mode to be enabled"
+		return nil, toolerrors.New(toolerrors.CodeNotEnabled, errMsgRequiresFederation)
+	}
+
+	// An admin impersonation override (impersonateUser / impersonateGroups)
+	// has been authorized by WrapWithAuditLogging on the call's cluster and is
+	// verified here on any other cluster the call reaches. It is served
+	// through the federation manager, which handles the local cluster too
+	// when clusterName is empty.
+	override, impersonating := impersonateAsFromContext(ctx)
+	if impersonating && fedManager == nil {
+		return nil, toolerrors.New(toolerrors.CodeNotEnabled, errMsgImpersonateAsRequiresFederation)
+	}
+	if impersonating {
+		if toolErr := verifyImpersonateAs(ctx, sc, clusterName); toolErr != nil {
+			return nil, toolErr
+		}
 	}
 
 	// If a cluster is specified, we need federation support
-	if clusterName != "" {
-		var user *federation.UserInfo
-
-		// For external-issuer (OBO) tokens the middleware sets an ImpersonationIdentity
-		// instead of an ID token. Use the impersonated human subject as Impersonate-User
-		// so workload-cluster clients carry the same identity as the local cluster path.
-		if identity, ok := server.ImpersonationIdentityFromContext(ctx); ok {
-			if len(identity.AllowedTargetClusters) > 0 {
-				allowed := false
-				for _, c := range identity.AllowedTargetClusters {
-					if c == clusterName {
-						allowed = true
-						break
-					}
-				}
-				if !allowed {
-					return nil, fmt.Sprintf("cluster %q is not in the allowed target clusters for this identity", clusterName)
-				}
-			}
-			user = &federation.UserInfo{
-				Email:  identity.UserName,
-				Groups: identity.Groups,
-			}
-		} else {
-			// SSO / normal OAuth path: extract user info from context
-			oauthUser, ok := oauth.UserInfoFromContext(ctx)
-			if !ok || oauthUser == nil {
-				return nil, "authentication required: no user info in context"
-			}
-			user = oauth.ToFederationUserInfo(oauthUser)
-			if user == nil {
-				return nil, "failed to convert user info for federation"
+	if clusterName != "" || impersonating {
+		user := override
+		if !impersonating {
+			var toolErr *toolerrors.Error
+			user, toolErr = callerUserInfo(ctx, clusterName)
+			if toolErr != nil {
+				return nil, toolErr
 			}
 		}
 
@@ -144,7 +159,7 @@ func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName
 			slog.Warn("failed to get federated clientset",
 				slog.String("cluster", clusterName),
 				slog.Any("error", err))
-			return nil, FormatClusterError(err, clusterName)
+			return nil, ClusterError(err, clusterName)
 		}
 
 		dynamicClient, err := fedManager.GetDynamicClient(ctx, clusterName, user)
@@ -152,7 +167,7 @@ func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName
 			slog.Warn("failed to get federated dynamic client",
 				slog.String("cluster", clusterName),
 				slog.Any("error", err))
-			return nil, FormatClusterError(err, clusterName)
+			return nil, ClusterError(err, clusterName)
 		}
 
 		restConfig, err := fedManager.GetRestConfig(ctx, clusterName, user)
@@ -160,21 +175,25 @@ func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName
 			slog.Warn("failed to get federated rest config",
 				slog.String("cluster", clusterName),
 				slog.Any("error", err))
-			return nil, FormatClusterError(err, clusterName)
+			return nil, ClusterError(err, clusterName)
 		}
 
 		// Create federated k8s.Client wrapper
-		federatedClient, err := k8s.NewFederatedClient(&k8s.FederatedClientConfig{
+		clientConfig := &k8s.FederatedClientConfig{
 			ClusterName:   clusterName,
 			Clientset:     clientset,
 			DynamicClient: dynamicClient,
 			RestConfig:    restConfig,
-		})
+		}
+		if user != nil {
+			clientConfig.User, clientConfig.Groups = user.Email, user.Groups
+		}
+		federatedClient, err := k8s.NewFederatedClient(clientConfig)
 		if err != nil {
 			slog.Error("failed to create federated client",
 				slog.String("cluster", clusterName),
 				slog.Any("error", err))
-			return nil, "failed to initialize cluster client"
+			return nil, toolerrors.Internal("failed to initialize cluster client")
 		}
 
 		slog.Debug("created federated client",
@@ -185,26 +204,96 @@ func GetClusterClient(ctx context.Context, sc *server.ServerContext, clusterName
 			user:        user,
 			clusterName: clusterName,
 			federated:   true,
-		}, ""
+		}, nil
 	}
 
 	// No cluster specified - use local client (management cluster).
 	// OBO identities with AllowedTargetClusters restrictions cannot access the MC.
 	if identity, ok := server.ImpersonationIdentityFromContext(ctx); ok {
 		if len(identity.AllowedTargetClusters) > 0 {
-			return nil, "management cluster access is not permitted for this identity (restricted to specific workload clusters)"
+			return nil, toolerrors.New(toolerrors.CodeForbidden, errMsgMCAccessRestricted)
 		}
 	}
 	k8sClient, err := sc.K8sClientForContext(ctx)
 	if err != nil {
 		// Authentication failed in strict mode
-		return nil, FormatAuthenticationError(err)
+		return nil, toolerrors.New(toolerrors.CodeUnauthenticated, FormatAuthenticationError(err))
 	}
 	return &ClusterClient{
 		k8sClient:   k8sClient,
 		clusterName: "",
 		federated:   false,
-	}, ""
+	}, nil
+}
+
+// callerUserInfo returns the federation identity of the authenticated caller.
+// clusterName is checked against the AllowedTargetClusters of an OBO identity;
+// pass an empty string to skip that check.
+func callerUserInfo(ctx context.Context, clusterName string) (*federation.UserInfo, *toolerrors.Error) {
+	// For external-issuer (OBO) tokens the middleware sets an ImpersonationIdentity
+	// instead of an ID token. Use the impersonated human subject as Impersonate-User
+	// so workload-cluster clients carry the same identity as the local cluster path.
+	if identity, ok := server.ImpersonationIdentityFromContext(ctx); ok {
+		if clusterName != "" && len(identity.AllowedTargetClusters) > 0 {
+			allowed := false
+			for _, c := range identity.AllowedTargetClusters {
+				if c == clusterName {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return nil, toolerrors.Newf(toolerrors.CodeForbidden, "cluster %q is not in the allowed target clusters for this identity", clusterName)
+			}
+		}
+		return &federation.UserInfo{
+			Email:  identity.UserName,
+			Groups: identity.Groups,
+		}, nil
+	}
+
+	// SSO / normal OAuth path: extract user info from context
+	oauthUser, ok := oauth.UserInfoFromContext(ctx)
+	if !ok || oauthUser == nil {
+		return nil, toolerrors.New(toolerrors.CodeUnauthenticated, "authentication required: no user info in context")
+	}
+	user := oauth.ToFederationUserInfo(oauthUser)
+	if user == nil {
+		return nil, toolerrors.Internal("failed to convert user info for federation")
+	}
+	return user, nil
+}
+
+// ListAccessibleClusters returns the names of the workload clusters the
+// caller can see through the federation manager, for tools that fan out
+// across the fleet. Returns (nil, error) on failure; the error is suitable
+// for direct use in MCP tool responses.
+func ListAccessibleClusters(ctx context.Context, sc *server.ServerContext) ([]string, *toolerrors.Error) {
+	fedManager := sc.FederationManager()
+	if fedManager == nil {
+		return nil, toolerrors.New(toolerrors.CodeNotEnabled, errMsgRequiresFederation)
+	}
+	user, toolErr := callerUserInfo(ctx, "")
+	if toolErr != nil {
+		return nil, toolErr
+	}
+	clusters, err := fedManager.ListClusters(ctx, user)
+	if err != nil {
+		return nil, ClusterError(err, "")
+	}
+	// An OBO identity may be limited to some target clusters.
+	var allowed []string
+	if identity, ok := server.ImpersonationIdentityFromContext(ctx); ok {
+		allowed = identity.AllowedTargetClusters
+	}
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		if len(allowed) > 0 && !slices.Contains(allowed, c.Name) {
+			continue
+		}
+		names = append(names, c.Name)
+	}
+	return names, nil
 }
 
 // ExtractClusterParam extracts the cluster parameter from request arguments.
@@ -287,6 +376,8 @@ func FormatClusterError(err error, clusterName string) string {
 		return "secure connection to cluster failed"
 	case errors.Is(err, federation.ErrManagerClosed):
 		return "federation manager is unavailable"
+	case errors.Is(err, federation.ErrConcurrencyLimitExceeded):
+		return "too many clusters are being contacted at the same time - try again later or query fewer clusters"
 	case errors.Is(err, federation.ErrUserInfoRequired):
 		return "authentication required for multi-cluster operations"
 	case errors.Is(err, federation.ErrInvalidClusterName):
@@ -295,7 +386,19 @@ func FormatClusterError(err error, clusterName string) string {
 
 	// For unhandled errors, return a generic message that doesn't leak internal details.
 	// The actual error should be logged server-side for debugging purposes.
-	return "failed to access cluster: an unexpected error occurred"
+	return errMsgUnexpectedClusterErr
+}
+
+// ClusterError returns a federation error as a structured tool error with
+// the message of FormatClusterError. Errors that FormatClusterError does not
+// recognise are reported as internal, so that the code does not reveal more
+// than the message.
+func ClusterError(err error, clusterName string) *toolerrors.Error {
+	msg := FormatClusterError(err, clusterName)
+	if msg == errMsgUnexpectedClusterErr {
+		return toolerrors.Internal(msg)
+	}
+	return toolerrors.FromError(err, msg)
 }
 
 // ValidateClusterParam validates that the cluster parameter can be used.
@@ -311,7 +414,7 @@ func ValidateClusterParam(sc *server.ServerContext, clusterName string) string {
 
 	fedManager := sc.FederationManager()
 	if fedManager == nil {
-		return "multi-cluster operations require federation mode to be enabled"
+		return errMsgRequiresFederation
 	}
 
 	// Validate cluster name format
@@ -345,3 +448,9 @@ func FormatK8sError(prefix string, err error, user *federation.UserInfo) string
 	}
 	return msg
 }
+
+// K8sError returns a failed Kubernetes operation as a structured tool error,
+// classified by err, with the message of FormatK8sError.
+func K8sError(prefix string, err error, user *federation.UserInfo) *toolerrors.Error {
+	return toolerrors.FromError(err, FormatK8sError(prefix, err, user))
+}
@@ -404,9 +404,9 @@ func TestGetClusterClientWithOAuth(t *testing.T) {
 		defer func() { _ = sc.Shutdown() }()
 
 		// With no cluster name and no federation, should succeed using local client
-		client, errMsg := GetClusterClient(ctx, sc, "")
-		if errMsg != "" {
-			t.Errorf("GetClusterClient with empty cluster should succeed, got error: %s", errMsg)
+		client, toolErr := GetClusterClient(ctx, sc, "")
+		if toolErr != nil {
+			t.Errorf("GetClusterClient with empty cluster should succeed, got error: %s", toolErr)
 		}
 		if client == nil {
 			t.Error("GetClusterClient should return a client")
@@ -429,18 +429,18 @@ func TestGetClusterClientWithOAuth(t *testing.T) {
 		defer func() { _ = sc.Shutdown() }()
 
 		// With cluster name but no federation, should fail
-		client, errMsg := GetClusterClient(ctx, sc, "my-cluster")
-		if errMsg == "" {
+		client, toolErr := GetClusterClient(ctx, sc, "my-cluster")
+		if toolErr == nil {
 			t.Error("GetClusterClient with cluster name but no federation should fail")
 		}
 		if client != nil {
 			t.Error("GetClusterClient should not return a client on error")
 		}
-		if !strings.Contains(errMsg, "federation") {
-			t.Errorf("Error message should mention federation, got: %s", errMsg)
+		if !strings.Contains(toolErr.Error(), "federation") {
+			t.Errorf("Error message should mention federation, got: %s", toolErr)
 		}
-		if errMsg != errMsgFederationRequired {
-			t.Errorf("Expected error message %q, got %q", errMsgFederationRequired, errMsg)
+		if toolErr.Message != errMsgFederationRequired {
+			t.Errorf("Expected error message %q, got %q", errMsgFederationRequired, toolErr)
 		}
 	})
 
@@ -466,15 +466,15 @@ func TestGetClusterClientWithOAuth(t *testing.T) {
 		}
 
 		for _, invalidName := range invalidNames {
-			client, errMsg := GetClusterClient(ctx, sc, invalidName)
-			if errMsg == "" {
+			client, toolErr := GetClusterClient(ctx, sc, invalidName)
+			if toolErr == nil {
 				t.Errorf("GetClusterClient with invalid name %q should fail", invalidName)
 			}
 			if client != nil {
 				t.Errorf("GetClusterClient should not return a client for invalid name %q", invalidName)
 			}
-			if !strings.Contains(errMsg, "invalid cluster name") {
-				t.Errorf("Error message for %q should mention 'invalid cluster name', got: %s", invalidName, errMsg)
+			if !strings.Contains(toolErr.Error(), "invalid cluster name") {
+				t.Errorf("Error message for %q should mention 'invalid cluster name', got: %s", invalidName, toolErr)
 			}
 		}
 	})
@@ -530,15 +530,15 @@ func TestGetClusterClientWithDownstreamOAuth(t *testing.T) {
 		defer func() { _ = sc.Shutdown() }()
 
 		// With no token, should fail with auth error (fail closed)
-		client, errMsg := GetClusterClient(ctx, sc, "")
-		if errMsg == "" {
+		client, toolErr := GetClusterClient(ctx, sc, "")
+		if toolErr == nil {
 			t.Error("GetClusterClient without token should fail")
 		}
 		if client != nil {
 			t.Error("GetClusterClient should not return a client on auth failure")
 		}
-		if !strings.Contains(errMsg, "authentication") {
-			t.Errorf("Error message should mention authentication, got: %s", errMsg)
+		if !strings.Contains(toolErr.Error(), "authentication") {
+			t.Errorf("Error message should mention authentication, got: %s", toolErr)
 		}
 	})
 
@@ -562,9 +562,9 @@ func TestGetClusterClientWithDownstreamOAuth(t *testing.T) {
 		defer func() { _ = sc.Shutdown() }()
 
 		// With valid token, should succeed
-		client, errMsg := GetClusterClient(ctx, sc, "")
-		if errMsg != "" {
-			t.Errorf("GetClusterClient with valid token should succeed, got error: %s", errMsg)
+		client, toolErr := GetClusterClient(ctx, sc, "")
+		if toolErr != nil {
+			t.Errorf("GetClusterClient with valid token should succeed, got error: %s", toolErr)
 		}
 		if client == nil {
 			t.Error("GetClusterClient should return a client")
@@ -594,15 +594,15 @@ func TestGetClusterClientWithDownstreamOAuth(t *testing.T) {
 		defer func() { _ = sc.Shutdown() }()
 
 		// With invalid token, should fail with auth error (fail closed)
-		client, errMsg := GetClusterClient(ctx, sc, "")
-		if errMsg == "" {
+		client, toolErr := GetClusterClient(ctx, sc, "")
+		if toolErr == nil {
 			t.Error("GetClusterClient with invalid token should fail")
 		}
 		if client != nil {
 			t.Error("GetClusterClient should not return a client on auth failure")
 		}
-		if !strings.Contains(errMsg, "authentication") {
-			t.Errorf("Error message should mention authentication, got: %s", errMsg)
+		if !strings.Contains(toolErr.Error(), "authentication") {
+			t.Errorf("Error message should mention authentication, got: %s", toolErr)
 		}
 	})
 }
@@ -669,3 +669,31 @@ func TestIsAuthenticationError(t *testing.T) {
 		}
 	})
 }
+
+func TestListAccessibleClusters(t *testing.T) {
+	fed := &listingFedManager{clusters: []federation.ClusterSummary{{Name: "prod-a"}, {Name: "prod-b"}}}
+	sc := newVisibilityServerContext(t, server.WithFederationManager(fed))
+
+	names, toolErr := ListAccessibleClusters(userContext(), sc)
+	if toolErr != nil || len(names) != 2 {
+		t.Fatalf("ListAccessibleClusters() = %v, %q", names, toolErr)
+	}
+
+	// An OBO identity only sees its allowed target clusters.
+	ctx := server.ContextWithImpersonationIdentity(context.Background(), k8s.ImpersonationIdentity{
+		UserName:              "jane@example.com",
+		AllowedTargetClusters: []string{"prod-b"},
+	})
+	names, toolErr = ListAccessibleClusters(ctx, sc)
+	if toolErr != nil || len(names) != 1 || names[0] != "prod-b" {
+		t.Errorf("ListAccessibleClusters() with OBO identity = %v, %q", names, toolErr)
+	}
+
+	if _, toolErr := ListAccessibleClusters(context.Background(), sc); !strings.Contains(toolErr.Error(), "authentication required") {
+		t.Errorf("expected authentication error, got %q", toolErr)
+	}
+
+	if _, toolErr := ListAccessibleClusters(userContext(), newVisibilityServerContext(t)); !strings.Contains(toolErr.Error(), "federation") {
+		t.Errorf("expected federation error, got %q", toolErr)
+	}
+}
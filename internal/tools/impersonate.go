@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Tool parameters for the admin impersonation override.
+const (
+	ParamImpersonateUser   = "impersonateUser"
+	ParamImpersonateGroups = "impersonateGroups"
+)
+
+// Error message constants for the impersonation override.
+const (
+	errMsgImpersonateAsDisabled           = "impersonation override is not enabled on this server"
+	errMsgImpersonateAsRequiresFederation = "impersonation override requires federation mode to be enabled"
+	errMsgImpersonateAsGroupsWithoutUser  = "impersonateGroups requires impersonateUser"
+)
+
+// impersonateAsContextKey is the context key for an authorized impersonation override.
+type impersonateAsContextKey struct{}
+
+// impersonateAsGrant is an impersonation target together with the clusters
+// on which the caller was verified to be allowed to impersonate it.
+type impersonateAsGrant struct {
+	target *federation.UserInfo
+
+	mu       sync.Mutex
+	verified map[string]bool
+}
+
+// contextWithImpersonateAs stores an impersonation target, authorized on
+// clusterName, in ctx. GetClusterClient uses it in place of the caller's own
+// identity, after verifying it on any other cluster the call reaches.
+func contextWithImpersonateAs(ctx context.Context, target *federation.UserInfo, clusterName string) context.Context {
+	grant := &impersonateAsGrant{target: target, verified: map[string]bool{clusterName: true}}
+	return context.WithValue(ctx, impersonateAsContextKey{}, grant)
+}
+
+// impersonateAsFromContext returns the impersonation target stored by
+// contextWithImpersonateAs, if any.
+func impersonateAsFromContext(ctx context.Context) (*federation.UserInfo, bool) {
+	grant, ok := ctx.Value(impersonateAsContextKey{}).(*impersonateAsGrant)
+	if !ok || grant == nil {
+		return nil, false
+	}
+	return grant.target, true
+}
+
+// verifyImpersonateAs checks the impersonation override stored in ctx on
+// clusterName, unless it was already verified there. Tools that fan out over
+// several clusters reach clusters other than the one the override was
+// authorized on, and each needs its own access check.
+func verifyImpersonateAs(ctx context.Context, sc *server.ServerContext, clusterName string) *toolerrors.Error {
+	grant, ok := ctx.Value(impersonateAsContextKey{}).(*impersonateAsGrant)
+	if !ok || grant == nil {
+		return nil
+	}
+	grant.mu.Lock()
+	defer grant.mu.Unlock()
+	if grant.verified[clusterName] {
+		return nil
+	}
+	if toolErr := checkImpersonateAs(ctx, sc, clusterName, grant.target); toolErr != nil {
+		return toolErr
+	}
+	grant.verified[clusterName] = true
+	return nil
+}
+
+// extractImpersonateAs reads the impersonation override from tool arguments.
+// Returns nil when no override was requested.
+func extractImpersonateAs(args map[string]interface{}) (*federation.UserInfo, *toolerrors.Error) {
+	userName, _ := args[ParamImpersonateUser].(string)
+	userName = strings.TrimSpace(userName)
+
+	var groups []string
+	switch v := args[ParamImpersonateGroups].(type) {
+	case []interface{}:
+		for _, g := range v {
+			if s, ok := g.(string); ok && strings.TrimSpace(s) != "" {
+				groups = append(groups, strings.TrimSpace(s))
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if strings.TrimSpace(s) != "" {
+				groups = append(groups, strings.TrimSpace(s))
+			}
+		}
+	}
+
+	if userName == "" {
+		if len(groups) > 0 {
+			return nil, toolerrors.InvalidArgument(errMsgImpersonateAsGroupsWithoutUser)
+		}
+		return nil, nil
+	}
+
+	target := &federation.UserInfo{Email: userName, Groups: groups}
+	if err := federation.ValidateUserInfo(target); err != nil {
+		return nil, toolerrors.InvalidArgumentf("invalid impersonation target: %v", err)
+	}
+	return target, nil
+}
+
+// authorizeImpersonateAs validates an impersonation override requested in
+// args and, when allowed, returns a context that makes GetClusterClient act
+// as the target identity.
+//
+// The override is only honoured when Config.AllowImpersonateAs is set and
+// federation is enabled. Before use, the caller's own permission to
+// impersonate the target user and every target group is verified with a
+// SubjectAccessReview on the target cluster, and again on every other
+// cluster the call reaches, so the feature never grants more than the
+// caller's RBAC already allows.
+//
+// Returns (ctx, nil, nil) unchanged when no override was requested.
+func authorizeImpersonateAs(ctx context.Context, sc *server.ServerContext, args map[string]interface{}) (context.Context, *federation.UserInfo, *toolerrors.Error) {
+	target, toolErr := extractImpersonateAs(args)
+	if toolErr != nil || target == nil {
+		return ctx, nil, toolErr
+	}
+
+	if cfg := sc.Config(); cfg == nil || !cfg.AllowImpersonateAs {
+		return ctx, nil, toolerrors.New(toolerrors.CodeNotEnabled, errMsgImpersonateAsDisabled)
+	}
+
+	if sc.FederationManager() == nil {
+		return ctx, nil, toolerrors.New(toolerrors.CodeNotEnabled, errMsgImpersonateAsRequiresFederation)
+	}
+
+	clusterName := ExtractClusterParam(args)
+	if toolErr := checkImpersonateAs(ctx, sc, clusterName, target); toolErr != nil {
+		return ctx, nil, toolErr
+	}
+	return contextWithImpersonateAs(ctx, target, clusterName), target, nil
+}
+
+// checkImpersonateAs verifies with SubjectAccessReviews on clusterName that
+// the caller may impersonate the target user and every target group.
+func checkImpersonateAs(ctx context.Context, sc *server.ServerContext, clusterName string, target *federation.UserInfo) *toolerrors.Error {
+	fedManager := sc.FederationManager()
+	if fedManager == nil {
+		return toolerrors.New(toolerrors.CodeNotEnabled, errMsgImpersonateAsRequiresFederation)
+	}
+
+	if clusterName != "" {
+		if err := federation.ValidateClusterName(clusterName); err != nil {
+			return toolerrors.InvalidArgument(errMsgInvalidClusterName)
+		}
+	} else if identity, ok := server.ImpersonationIdentityFromContext(ctx); ok && len(identity.AllowedTargetClusters) > 0 {
+		return toolerrors.New(toolerrors.CodeForbidden, errMsgMCAccessRestricted)
+	}
+
+	caller, toolErr := callerUserInfo(ctx, clusterName)
+	if toolErr != nil {
+		return toolErr
+	}
+
+	checks := []*federation.AccessCheck{{Verb: "impersonate", Resource: "users", Name: target.Email}}
+	for _, group := range target.Groups {
+		checks = append(checks, &federation.AccessCheck{Verb: "impersonate", Resource: "groups", Name: group})
+	}
+
+	for _, check := range checks {
+		result, err := fedManager.CheckAccess(ctx, clusterName, caller, check)
+		if err != nil {
+			slog.Warn("impersonation access check failed",
+				slog.String("cluster", clusterName),
+				slog.String("resource", check.Resource),
+				slog.Any("error", err))
+			return ClusterError(err, clusterName)
+		}
+		if !result.Allowed {
+			return toolerrors.Newf(toolerrors.CodeForbidden, "impersonation denied: you are not allowed to impersonate %s %q", strings.TrimSuffix(check.Resource, "s"), check.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	oauthhandler "github.com/giantswarm/mcp-oauth/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// impersonationFedManager is a federation.ClusterClientManager that records
+// access checks and answers them from a fixed allow-list.
+type impersonationFedManager struct {
+	allowed  map[string]bool // "resource/name" -> allowed
+	checkErr error
+	checks   []federation.AccessCheck
+	callers  []string
+	clusters []string
+}
+
+func (m *impersonationFedManager) GetClient(context.Context, string, *federation.UserInfo) (kubernetes.Interface, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *impersonationFedManager) GetDynamicClient(context.Context, string, *federation.UserInfo) (dynamic.Interface, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *impersonationFedManager) GetRestConfig(context.Context, string, *federation.UserInfo) (*rest.Config, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *impersonationFedManager) ListClusters(context.Context, *federation.UserInfo) ([]federation.ClusterSummary, error) {
+	return nil, nil
+}
+
+func (m *impersonationFedManager) GetClusterSummary(context.Context, string, *federation.UserInfo) (*federation.ClusterSummary, error) {
+	return nil, nil
+}
+
+func (m *impersonationFedManager) CheckAccess(_ context.Context, clusterName string, user *federation.UserInfo, check *federation.AccessCheck) (*federation.AccessCheckResult, error) {
+	m.checks = append(m.checks, *check)
+	m.callers = append(m.callers, user.Email)
+	m.clusters = append(m.clusters, clusterName)
+	if m.checkErr != nil {
+		return nil, m.checkErr
+	}
+	return &federation.AccessCheckResult{Allowed: m.allowed[check.Resource+"/"+check.Name]}, nil
+}
+
+func (m *impersonationFedManager) Close() error { return nil }
+
+func (m *impersonationFedManager) Stats() federation.ManagerStats { return federation.ManagerStats{} }
+
+func newImpersonationServerContext(t *testing.T, enabled bool, fed federation.ClusterClientManager) *server.ServerContext {
+	t.Helper()
+	opts := []server.Option{
+		server.WithK8sClient(&mockK8sClient{}),
+		server.WithLogger(&mockLogger{}),
+		server.WithAllowImpersonateAs(enabled),
+	}
+	if fed != nil {
+		opts = append(opts, server.WithFederationManager(fed))
+	}
+	sc, err := server.NewServerContext(context.Background(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+	return sc
+}
+
+func callerContext() context.Context {
+	return oauthhandler.ContextWithUserInfo(context.Background(), &oauth.UserInfo{
+		Email:  "admin@example.com",
+		Groups: []string{"platform-admins"},
+	})
+}
+
+func TestExtractImpersonateAs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]interface{}
+		wantUser   string
+		wantGroups []string
+		wantErr    string
+	}{
+		{name: "no override", args: map[string]interface{}{}},
+		{
+			name:     "user only",
+			args:     map[string]interface{}{ParamImpersonateUser: " jane@example.com "},
+			wantUser: "jane@example.com",
+		},
+		{
+			name: "user and groups",
+			args: map[string]interface{}{
+				ParamImpersonateUser:   "jane@example.com",
+				ParamImpersonateGroups: []interface{}{"team-a", "", "team-b"},
+			},
+			wantUser:   "jane@example.com",
+			wantGroups: []string{"team-a", "team-b"},
+		},
+		{
+			name:    "groups without user",
+			args:    map[string]interface{}{ParamImpersonateGroups: []interface{}{"team-a"}},
+			wantErr: errMsgImpersonateAsGroupsWithoutUser,
+		},
+		{
+			name:    "invalid user",
+			args:    map[string]interface{}{ParamImpersonateUser: "not an email"},
+			wantErr: "invalid impersonation target",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, toolErr := extractImpersonateAs(tt.args)
+			if tt.wantErr != "" {
+				require.NotNil(t, toolErr)
+				assert.Contains(t, toolErr.Message, tt.wantErr)
+				assert.Equal(t, toolerrors.CodeInvalidArgument, toolErr.Code)
+				assert.Nil(t, target)
+				return
+			}
+			assert.Nil(t, toolErr)
+			if tt.wantUser == "" {
+				assert.Nil(t, target)
+				return
+			}
+			require.NotNil(t, target)
+			assert.Equal(t, tt.wantUser, target.Email)
+			assert.Equal(t, tt.wantGroups, target.Groups)
+		})
+	}
+}
+
+func TestAuthorizeImpersonateAs(t *testing.T) {
+	args := map[string]interface{}{
+		ParamImpersonateUser:   "jane@example.com",
+		ParamImpersonateGroups: []interface{}{"team-a"},
+	}
+
+	t.Run("no override leaves context untouched", func(t *testing.T) {
+		sc := newImpersonationServerContext(t, false, nil)
+		ctx := callerContext()
+		got, target, toolErr := authorizeImpersonateAs(ctx, sc, map[string]interface{}{})
+		assert.Nil(t, toolErr)
+		assert.Nil(t, target)
+		_, ok := impersonateAsFromContext(got)
+		assert.False(t, ok)
+	})
+
+	t.Run("disabled by config", func(t *testing.T) {
+		sc := newImpersonationServerContext(t, false, &impersonationFedManager{})
+		_, _, toolErr := authorizeImpersonateAs(callerContext(), sc, args)
+		require.NotNil(t, toolErr)
+		assert.Equal(t, errMsgImpersonateAsDisabled, toolErr.Message)
+		assert.Equal(t, toolerrors.CodeNotEnabled, toolErr.Code)
+	})
+
+	t.Run("requires federation", func(t *testing.T) {
+		sc := newImpersonationServerContext(t, true, nil)
+		_, _, toolErr := authorizeImpersonateAs(callerContext(), sc, args)
+		require.NotNil(t, toolErr)
+		assert.Equal(t, errMsgImpersonateAsRequiresFederation, toolErr.Message)
+	})
+
+	t.Run("requires authenticated caller", func(t *testing.T) {
+		fed := &impersonationFedManager{}
+		sc := newImpersonationServerContext(t, true, fed)
+		_, _, toolErr := authorizeImpersonateAs(context.Background(), sc, args)
+		require.NotNil(t, toolErr)
+		assert.Contains(t, toolErr.Message, "authentication required")
+		assert.Equal(t, toolerrors.CodeUnauthenticated, toolErr.Code)
+		assert.Empty(t, fed.checks)
+	})
+
+	t.Run("denied when caller cannot impersonate a group", func(t *testing.T) {
+		fed := &impersonationFedManager{allowed: map[string]bool{"users/jane@example.com": true}}
+		sc := newImpersonationServerContext(t, true, fed)
+		_, _, toolErr := authorizeImpersonateAs(callerContext(), sc, args)
+		require.NotNil(t, toolErr)
+		assert.Contains(t, toolErr.Message, `impersonate group "team-a"`)
+		assert.Equal(t, toolerrors.CodeForbidden, toolErr.Code)
+	})
+
+	t.Run("access check failure is reported", func(t *testing.T) {
+		fed := &impersonationFedManager{checkErr: errors.New("boom")}
+		sc := newImpersonationServerContext(t, true, fed)
+		_, _, toolErr := authorizeImpersonateAs(callerContext(), sc, args)
+		assert.NotNil(t, toolErr)
+	})
+
+	t.Run("allowed override is stored in context", func(t *testing.T) {
+		fed := &impersonationFedManager{allowed: map[string]bool{
+			"users/jane@example.com": true,
+			"groups/team-a":          true,
+		}}
+		sc := newImpersonationServerContext(t, true, fed)
+		ctx, target, toolErr := authorizeImpersonateAs(callerContext(), sc, args)
+		require.Nil(t, toolErr)
+		require.NotNil(t, target)
+
+		got, ok := impersonateAsFromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, "jane@example.com", got.Email)
+		assert.Equal(t, []string{"team-a"}, got.Groups)
+
+		// The SubjectAccessReview is evaluated for the caller, not the target.
+		require.Len(t, fed.checks, 2)
+		assert.Equal(t, []string{"admin@example.com", "admin@example.com"}, fed.callers)
+		assert.Equal(t, "impersonate", fed.checks[0].Verb)
+		assert.Equal(t, "users", fed.checks[0].Resource)
+		assert.Equal(t, "groups", fed.checks[1].Resource)
+	})
+}
+
+func TestGetClusterClient_VerifiesImpersonateAsPerCluster(t *testing.T) {
+	fed := &impersonationFedManager{allowed: map[string]bool{"users/jane@example.com": true}}
+	sc := newImpersonationServerContext(t, true, fed)
+	ctx, _, toolErr := authorizeImpersonateAs(callerContext(), sc, map[string]interface{}{
+		"cluster":            "prod-a",
+		ParamImpersonateUser: "jane@example.com",
+	})
+	require.Nil(t, toolErr)
+	require.Equal(t, []string{"prod-a"}, fed.clusters)
+
+	_, toolErr = GetClusterClient(ctx, sc, "prod-a")
+	require.NotNil(t, toolErr, "the fake manager serves no clients")
+	assert.Equal(t, []string{"prod-a"}, fed.clusters, "the authorized cluster is not checked again")
+
+	fed.allowed = nil
+	_, toolErr = GetClusterClient(ctx, sc, "prod-b")
+	require.NotNil(t, toolErr)
+	assert.Equal(t, toolerrors.CodeForbidden, toolErr.Code)
+	assert.Contains(t, toolErr.Message, "impersonation denied")
+	assert.Equal(t, []string{"prod-a", "prod-b"}, fed.clusters, "another cluster is checked before use")
+}
+
+func TestWrapWithAuditLogging_ImpersonateAsDenied(t *testing.T) {
+	provider := createTestProvider(t)
+	fed := &impersonationFedManager{}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&mockK8sClient{}),
+		server.WithLogger(&mockLogger{}),
+		server.WithInstrumentationProvider(provider),
+		server.WithFederationManager(fed),
+		server.WithAllowImpersonateAs(true),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+
+	called := false
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("success"), nil
+	}
+
+	wrapped := WrapWithAuditLogging("test_tool", handler, sc)
+	result, err := wrapped(callerContext(), createTestRequest(map[string]interface{}{
+		ParamImpersonateUser: "jane@example.com",
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	assert.False(t, called, "handler must not run when impersonation is denied")
+}
+
+func TestAddClusterContextParams_ImpersonateAs(t *testing.T) {
+	hasParam := func(sc *server.ServerContext) bool {
+		tool := mcp.NewTool("t", AddClusterContextParams(sc)...)
+		_, ok := tool.InputSchema.Properties[ParamImpersonateUser]
+		return ok
+	}
+
+	assert.False(t, hasParam(newImpersonationServerContext(t, true, nil)), "needs federation")
+	assert.False(t, hasParam(newImpersonationServerContext(t, false, &impersonationFedManager{})), "needs config flag")
+	assert.True(t, hasParam(newImpersonationServerContext(t, true, &impersonationFedManager{})))
+}
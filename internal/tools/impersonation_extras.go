@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// Impersonation extra keys for the details of a tool call.
+const (
+	// ImpersonationExtraClient is the MCP client's name and version.
+	ImpersonationExtraClient = "mcp.giantswarm.io/client"
+
+	// ImpersonationExtraSessionID is the MCP session ID.
+	ImpersonationExtraSessionID = "mcp.giantswarm.io/session-id"
+)
+
+// withImpersonationExtras adds the details of the tool call selected by
+// config to the impersonation extras of the Kubernetes API requests made
+// with ctx.
+func withImpersonationExtras(ctx context.Context, config *server.ImpersonationExtrasConfig, request mcp.CallToolRequest) context.Context {
+	if !config.Enabled() {
+		return ctx
+	}
+
+	extras := make(map[string][]string)
+	if session := mcpserver.ClientSessionFromContext(ctx); session != nil {
+		if config.SessionID && session.SessionID() != "" {
+			extras[ImpersonationExtraSessionID] = []string{session.SessionID()}
+		}
+		if withInfo, ok := session.(mcpserver.SessionWithClientInfo); ok && config.ClientInfo {
+			if info := withInfo.GetClientInfo(); info.Name != "" {
+				client := info.Name
+				if info.Version != "" {
+					client += "/" + info.Version
+				}
+				extras[ImpersonationExtraClient] = []string{client}
+			}
+		}
+	}
+
+	if request.Params.Meta != nil {
+		for field, key := range config.RequestMeta {
+			if value, ok := request.Params.Meta.AdditionalFields[field].(string); ok && value != "" {
+				extras[key] = []string{value}
+			}
+		}
+	}
+
+	return k8s.ContextWithImpersonationExtras(ctx, extras)
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// clientInfoSession is a ClientSession that reports client info.
+type clientInfoSession struct {
+	id   string
+	info mcp.Implementation
+}
+
+func (s *clientInfoSession) Initialize()                                         {}
+func (s *clientInfoSession) Initialized() bool                                   { return true }
+func (s *clientInfoSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *clientInfoSession) SessionID() string                                   { return s.id }
+func (s *clientInfoSession) GetClientInfo() mcp.Implementation                   { return s.info }
+func (s *clientInfoSession) SetClientInfo(info mcp.Implementation)               { s.info = info }
+func (s *clientInfoSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{}
+}
+func (s *clientInfoSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+
+func TestWithImpersonationExtras(t *testing.T) {
+	session := &clientInfoSession{id: "session-1", info: mcp.Implementation{Name: "claude-desktop", Version: "1.2.0"}}
+	ctx := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), session)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{
+		"ticketId": "OPS-42",
+		"count":    3,
+	}}
+
+	t.Run("selected details are added", func(t *testing.T) {
+		config := &server.ImpersonationExtrasConfig{
+			ClientInfo:  true,
+			SessionID:   true,
+			RequestMeta: map[string]string{"ticketId": "ticket-id", "count": "count", "missing": "missing"},
+		}
+
+		extras := k8s.ImpersonationExtrasFromContext(withImpersonationExtras(ctx, config, request))
+		assert.Equal(t, map[string][]string{
+			ImpersonationExtraClient:    {"claude-desktop/1.2.0"},
+			ImpersonationExtraSessionID: {"session-1"},
+			"ticket-id":                 {"OPS-42"},
+		}, extras)
+	})
+
+	t.Run("nothing is added when not configured", func(t *testing.T) {
+		assert.Nil(t, k8s.ImpersonationExtrasFromContext(withImpersonationExtras(ctx, nil, request)))
+		assert.Nil(t, k8s.ImpersonationExtrasFromContext(withImpersonationExtras(ctx, &server.ImpersonationExtrasConfig{}, request)))
+	})
+
+	t.Run("only the selected details are added", func(t *testing.T) {
+		config := &server.ImpersonationExtrasConfig{SessionID: true}
+
+		extras := k8s.ImpersonationExtrasFromContext(withImpersonationExtras(ctx, config, request))
+		assert.Equal(t, map[string][]string{ImpersonationExtraSessionID: {"session-1"}}, extras)
+	})
+}
@@ -0,0 +1,25 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// checkKubeContext rejects a kubeContext argument outside the configured
+// kubeconfig contexts. Returns an empty string when the call is allowed or
+// no allowlist is configured.
+func checkKubeContext(sc *server.ServerContext, args map[string]interface{}) string {
+	cfg := sc.Config()
+	if cfg == nil || len(cfg.KubeconfigContexts) == 0 {
+		return ""
+	}
+	kubeContext, _ := args["kubeContext"].(string)
+	if kubeContext == "" || slices.Contains(cfg.KubeconfigContexts, kubeContext) {
+		return ""
+	}
+	return fmt.Sprintf("kubeContext %q is not allowed; available contexts: %s",
+		kubeContext, strings.Join(cfg.KubeconfigContexts, ", "))
+}
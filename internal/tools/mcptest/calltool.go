@@ -11,6 +11,10 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
 )
 
 // CallTool builds a minimal JSON-RPC tools/call envelope, hands it to the
@@ -36,3 +40,45 @@ func CallTool(t *testing.T, srv *mcpserver.MCPServer, toolName string, args map[
 	require.NoError(t, err)
 	return srv.HandleMessage(context.Background(), raw)
 }
+
+// CallRegisteredTool calls toolName through an MCP server serving the tools
+// register adds with client, so the call passes WrapWithAuditLogging like a
+// real one, and returns its tool result. opts configure the server context.
+func CallRegisteredTool(t *testing.T, client k8s.Client, register func(*mcpserver.MCPServer, *server.ServerContext) error, toolName string, args map[string]any, opts ...server.Option) *mcp.CallToolResult {
+	t.Helper()
+	opts = append([]server.Option{
+		server.WithK8sClient(client),
+		server.WithLogger(&testdata.MockLogger{}),
+	}, opts...)
+	sc, err := server.NewServerContext(context.Background(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+
+	srv := mcpserver.NewMCPServer("test", "0.0.0", mcpserver.WithToolCapabilities(true))
+	require.NoError(t, register(srv, sc))
+
+	resp, ok := CallTool(t, srv, toolName, args).(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a JSON-RPC response")
+	result, ok := resp.Result.(*mcp.CallToolResult)
+	require.True(t, ok, "expected a tool result, got %T", resp.Result)
+	return result
+}
+
+// ResultText returns the first text content of result, or an empty string
+// when it has none.
+func ResultText(result *mcp.CallToolResult) string {
+	if result == nil || len(result.Content) == 0 {
+		return ""
+	}
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+// DecodeResult unmarshals the JSON output of a successful tool result.
+func DecodeResult[T any](t *testing.T, result *mcp.CallToolResult) T {
+	t.Helper()
+	text := ResultText(result)
+	require.False(t, result.IsError, text)
+	var out T
+	require.NoError(t, json.Unmarshal([]byte(text), &out))
+	return out
+}
@@ -0,0 +1,194 @@
+package mcptest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// FakeClient serves the objects of tool tests from memory. Objects are
+// keyed by plural resource type, or by "resource.group" where the group
+// matters.
+//
+// List returns the objects of a type in the requested namespace, unless it
+// lists all namespaces, that match its label and field selectors, as
+// stored: typed objects without a kind, like objects decoded from protobuf.
+// Get accepts a kind or singular name as the API server does, finds an
+// object by namespace and name and fills in its kind.
+//
+// A type in Errs fails with its error; with Strict set, a type without an
+// Objects entry is unknown, like a cluster without the CRD. With PageSize
+// set, List pages its results. The options and namespace of every List
+// call are recorded per type. Tests embed it to fake the other methods a
+// tool calls.
+type FakeClient struct {
+	*testdata.MockK8sClient
+
+	Objects  map[string][]runtime.Object
+	Errs     map[string]error
+	Strict   bool
+	PageSize int
+	Health   *k8s.ClusterHealth
+
+	// ListOpts and Namespaces record the options of every List call and
+	// the namespace of the last one, per type.
+	ListOpts   map[string][]k8s.ListOptions
+	Namespaces map[string]string
+}
+
+// NewFakeClient returns a FakeClient serving objects.
+func NewFakeClient(objects map[string][]runtime.Object) *FakeClient {
+	return &FakeClient{
+		MockK8sClient: &testdata.MockK8sClient{},
+		Objects:       objects,
+		Errs:          map[string]error{},
+		ListOpts:      map[string][]k8s.ListOptions{},
+		Namespaces:    map[string]string{},
+	}
+}
+
+// NewStrictFakeClient returns a FakeClient serving objects that reports the
+// types missing from objects as unknown.
+func NewStrictFakeClient(objects map[string][]runtime.Object) *FakeClient {
+	c := NewFakeClient(objects)
+	c.Strict = true
+	return c
+}
+
+// Forbidden returns the error of an API server refusing resourceType.
+func Forbidden(resourceType string) error {
+	return apierrors.NewForbidden(corev1.Resource(resourceType), "", fmt.Errorf("no access"))
+}
+
+// lookup returns the key and objects of resourceType in apiGroup, or the
+// error the type fails with.
+func (c *FakeClient) lookup(resourceType, apiGroup string) (string, []runtime.Object, error) {
+	key := resourceType
+	if _, ok := c.Objects[resourceType+"."+apiGroup]; ok && apiGroup != "" {
+		key = resourceType + "." + apiGroup
+	}
+	if err := c.Errs[key]; err != nil {
+		return key, nil, err
+	}
+	items, ok := c.Objects[key]
+	if !ok && c.Strict {
+		return key, nil, fmt.Errorf("%w: %s", k8s.ErrUnknownResourceType, resourceType)
+	}
+	return key, items, nil
+}
+
+func (c *FakeClient) List(_ context.Context, _, namespace, resourceType, apiGroup string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	c.ListOpts[resourceType] = append(c.ListOpts[resourceType], opts)
+	c.Namespaces[resourceType] = namespace
+	_, items, err := c.lookup(resourceType, apiGroup)
+	if err != nil {
+		return nil, err
+	}
+	labelSelector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &k8s.PaginatedListResponse{}
+	for _, obj := range items {
+		u := ToUnstructured(obj)
+		if namespace != "" && !opts.AllNamespaces && u.GetNamespace() != namespace {
+			continue
+		}
+		if labelSelector.Matches(labels.Set(u.GetLabels())) && fieldSelector.Matches(objectFields(u, fieldSelector)) {
+			resp.Items = append(resp.Items, obj)
+		}
+	}
+	if c.PageSize > 0 {
+		start, _ := strconv.Atoi(opts.Continue)
+		end := min(start+c.PageSize, len(resp.Items))
+		if end < len(resp.Items) {
+			resp.Continue = strconv.Itoa(end)
+		}
+		resp.Items = resp.Items[min(start, end):end]
+	}
+	resp.TotalItems = len(resp.Items)
+	return resp, nil
+}
+
+func (c *FakeClient) Get(_ context.Context, _, namespace, resourceType, apiGroup, name string) (*k8s.GetResponse, error) {
+	key, items, err := c.lookup(resourceKey(resourceType), apiGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range items {
+		u := ToUnstructured(obj)
+		if u.GetName() != name || (namespace != "" && u.GetNamespace() != namespace) {
+			continue
+		}
+		if u.GetKind() == "" {
+			gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil {
+				return nil, err
+			}
+			u.SetGroupVersionKind(gvks[0])
+		}
+		return &k8s.GetResponse{Resource: u}, nil
+	}
+	return nil, apierrors.NewNotFound(corev1.Resource(key), name)
+}
+
+func (c *FakeClient) GetClusterHealth(context.Context, string) (*k8s.ClusterHealth, error) {
+	if c.Health == nil {
+		return &k8s.ClusterHealth{Status: "healthy"}, nil
+	}
+	return c.Health, nil
+}
+
+// resourceKey returns the plural lowercase resource of a kind or resource
+// name, such as "replicasets" for "ReplicaSet".
+func resourceKey(resourceType string) string {
+	key := strings.ToLower(resourceType)
+	if !strings.HasSuffix(key, "s") {
+		key += "s"
+	}
+	return key
+}
+
+// objectFields returns the fields of u that selector reads.
+func objectFields(u *unstructured.Unstructured, selector fields.Selector) fields.Set {
+	set := fields.Set{}
+	for _, r := range selector.Requirements() {
+		value, _, _ := unstructured.NestedFieldNoCopy(u.Object, strings.Split(r.Field, ".")...)
+		if value != nil {
+			set[r.Field] = fmt.Sprint(value)
+		} else {
+			set[r.Field] = ""
+		}
+	}
+	return set
+}
+
+// ToUnstructured converts a typed object to its unstructured form. An
+// unstructured object is copied.
+func ToUnstructured(obj runtime.Object) *unstructured.Unstructured {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.DeepCopy()
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		panic(err)
+	}
+	return &unstructured.Unstructured{Object: content}
+}
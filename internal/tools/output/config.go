@@ -56,6 +56,19 @@ type Config struct {
 	// Default: true (security critical - should rarely be disabled)
 	MaskSecrets bool `json:"maskSecrets" yaml:"maskSecrets"`
 
+	// SecretRedaction is how masked secret data is shown: SecretRedactionFull,
+	// SecretRedactionKeys or SecretRedactionHash. Default: keys
+	SecretRedaction string `json:"secretRedaction,omitempty" yaml:"secretRedaction,omitempty"`
+
+	// ScrubPII masks common PII patterns (emails, IP addresses, bearer
+	// tokens, AWS keys) in annotations, ConfigMap data and container env
+	// values. Default: false
+	ScrubPII bool `json:"scrubPII" yaml:"scrubPII"`
+
+	// PIIPatterns selects which PII patterns are scrubbed when ScrubPII is
+	// enabled. Empty means all patterns (see PIIPatternSet).
+	PIIPatterns []string `json:"piiPatterns,omitempty" yaml:"piiPatterns,omitempty"`
+
 	// SummaryThreshold is the item count above which summary mode is suggested.
 	// Default: 500
 	SummaryThreshold int `json:"summaryThreshold" yaml:"summaryThreshold"`
@@ -63,6 +76,15 @@ type Config struct {
 	// ExcludedFields lists JSON paths of fields to exclude in slim mode.
 	// Default: common verbose fields (managedFields, last-applied-configuration, etc.)
 	ExcludedFields []string `json:"excludedFields,omitempty" yaml:"excludedFields,omitempty"`
+
+	// MaskedFields lists JSON paths of fields whose values are replaced with
+	// MaskedValue in every output mode, such as those a user asked to hide.
+	MaskedFields []string `json:"maskedFields,omitempty" yaml:"maskedFields,omitempty"`
+
+	// KindProfiles slim list items of the given kinds down to the listed
+	// JSON paths, on top of KindShaping. Keys are "Kind" or "Kind.group";
+	// see ApplyKindProfile. Default: none
+	KindProfiles map[string][]string `json:"kindProfiles,omitempty" yaml:"kindProfiles,omitempty"`
 }
 
 // DefaultConfig returns a Config with sensible defaults for fleet-scale operations.
@@ -189,6 +211,20 @@ func (c *Config) Clone() *Config {
 		clone.ExcludedFields = make([]string, len(c.ExcludedFields))
 		copy(clone.ExcludedFields, c.ExcludedFields)
 	}
+	if c.PIIPatterns != nil {
+		clone.PIIPatterns = make([]string, len(c.PIIPatterns))
+		copy(clone.PIIPatterns, c.PIIPatterns)
+	}
+	if c.MaskedFields != nil {
+		clone.MaskedFields = make([]string, len(c.MaskedFields))
+		copy(clone.MaskedFields, c.MaskedFields)
+	}
+	if c.KindProfiles != nil {
+		clone.KindProfiles = make(map[string][]string, len(c.KindProfiles))
+		for kind, paths := range c.KindProfiles {
+			clone.KindProfiles[kind] = append([]string(nil), paths...)
+		}
+	}
 
 	return &clone
 }
@@ -243,6 +279,9 @@ type ProcessingMetadata struct {
 	// SecretsMasked indicates if secrets were masked
 	SecretsMasked bool `json:"secretsMasked"`
 
+	// PIIScrubbed counts PII matches masked, by pattern
+	PIIScrubbed PIIScrubCounts `json:"piiScrubbed,omitempty"`
+
 	// BytesReduced is the estimated bytes saved by processing
 	BytesReduced int64 `json:"bytesReduced,omitempty"`
 }
@@ -141,6 +141,8 @@ func TestConfigClone(t *testing.T) {
 		MaskSecrets:      true,
 		SummaryThreshold: 100,
 		ExcludedFields:   []string{"field1", "field2"},
+		ScrubPII:         true,
+		PIIPatterns:      []string{PIIPatternEmail},
 	}
 
 	clone := original.Clone()
@@ -166,6 +168,11 @@ func TestConfigClone(t *testing.T) {
 	if original.ExcludedFields[0] == testModifiedValue {
 		t.Error("Modifying clone affected original ExcludedFields")
 	}
+
+	clone.PIIPatterns[0] = testModifiedValue
+	if original.PIIPatterns[0] == testModifiedValue {
+		t.Error("Modifying clone affected original PIIPatterns")
+	}
 }
 
 func TestConfigClone_Nil(t *testing.T) {
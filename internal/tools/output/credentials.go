@@ -0,0 +1,48 @@
+package output
+
+import "regexp"
+
+// Credential pattern names accepted in CredentialPatterns.
+const (
+	CredentialPatternPrivateKey  = "private_key"
+	CredentialPatternJWT         = "jwt"
+	CredentialPatternBearerToken = "bearer_token"
+	CredentialPatternAWSKey      = "aws_key"
+)
+
+// CredentialPatternSet detects credentials that end up in pod logs and
+// command output. Private key blocks run first so a key is replaced as a
+// whole, and JWTs before bearer tokens so "Bearer <jwt>" is counted as a JWT.
+var CredentialPatternSet = PatternSet{
+	{
+		name: CredentialPatternPrivateKey,
+		// PEM private key blocks. A block cut off by the end of the output,
+		// e.g. by tailLines, is redacted up to the end.
+		re:          regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----.*?(?:-----END [A-Z0-9 ]*PRIVATE KEY-----|\z)`),
+		placeholder: "***REDACTED:private_key***",
+	},
+	{
+		name:        CredentialPatternJWT,
+		re:          regexp.MustCompile(jwtExpr),
+		placeholder: "***REDACTED:jwt***",
+	},
+	{
+		name:        CredentialPatternBearerToken,
+		re:          regexp.MustCompile(bearerTokenExpr),
+		placeholder: "***REDACTED:bearer_token***",
+	},
+	{
+		name:        CredentialPatternAWSKey,
+		re:          regexp.MustCompile(awsKeyExpr),
+		placeholder: "***REDACTED:aws_key***",
+	},
+}
+
+// ScrubCredentials redacts likely credentials (private key blocks, JWTs,
+// bearer tokens and AWS keys) in free text such as pod logs and exec output.
+// patterns selects which detectors run; an empty list enables all. Returns
+// the scrubbed text and the number of matches per pattern.
+func ScrubCredentials(text string, patterns []string) (string, PIIScrubCounts) {
+	s := newScrubber(CredentialPatternSet, patterns)
+	return s.scrubString(text), s.counts
+}
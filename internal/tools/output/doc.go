@@ -13,7 +13,8 @@
 // such as managedFields, last-applied-configuration annotations, and timestamps.
 //
 // Secret Masking: Never returns secret data in responses - all secret values are replaced
-// with "***REDACTED***" to prevent accidental exposure.
+// with "***REDACTED***" to prevent accidental exposure. The redaction level can instead
+// drop the keys too, or replace each value with a short hash for comparison.
 //
 // Summary Mode: For large queries, offers summary counts by status and cluster instead of
 // raw data, dramatically reducing response size while maintaining usefulness.
@@ -0,0 +1,40 @@
+package output
+
+import "strings"
+
+// MaskedValue is the placeholder for the values of masked fields.
+const MaskedValue = "***MASKED***"
+
+// MaskFields replaces the values of the fields at paths with MaskedValue.
+// Paths use the notation of ExcludedFields. Unlike slim output, masking
+// applies to every output format, so that a field a caller asked to hide
+// never shows up.
+func MaskFields(obj map[string]interface{}, paths []string) map[string]interface{} {
+	if obj == nil || len(paths) == 0 {
+		return obj
+	}
+
+	result := deepCopyMap(obj)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		visitFieldRecursive(result, strings.Split(path, "."), func(m map[string]interface{}, key string) {
+			m[key] = MaskedValue
+		})
+	}
+	return result
+}
+
+// MaskFieldsInList applies MaskFields to a list of resources.
+func MaskFieldsInList(objects []map[string]interface{}, paths []string) []map[string]interface{} {
+	if len(objects) == 0 || len(paths) == 0 {
+		return objects
+	}
+
+	result := make([]map[string]interface{}, len(objects))
+	for i, obj := range objects {
+		result[i] = MaskFields(obj, paths)
+	}
+	return result
+}
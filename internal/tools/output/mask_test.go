@@ -0,0 +1,68 @@
+package output
+
+import (
+	"testing"
+)
+
+func TestMaskFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "web",
+			"labels": map[string]interface{}{
+				"team.example.com/owner": "jane",
+				"app":                    "web",
+			},
+		},
+		"status": map[string]interface{}{
+			"podIPs": []interface{}{map[string]interface{}{"ip": "10.0.0.1"}, map[string]interface{}{"ip": "10.0.0.2"}},
+		},
+	}
+
+	masked := MaskFields(obj, []string{"metadata.labels.team.example.com/owner", "status.podIPs[*].ip", "spec.missing"})
+
+	labels := masked["metadata"].(map[string]interface{})["labels"].(map[string]interface{})
+	if labels["team.example.com/owner"] != MaskedValue {
+		t.Errorf("expected dotted label key to be masked, got %v", labels["team.example.com/owner"])
+	}
+	if labels["app"] != "web" {
+		t.Errorf("expected other labels to be kept, got %v", labels["app"])
+	}
+	for _, ip := range masked["status"].(map[string]interface{})["podIPs"].([]interface{}) {
+		if ip.(map[string]interface{})["ip"] != MaskedValue {
+			t.Errorf("expected every array element to be masked, got %v", ip)
+		}
+	}
+	if _, ok := masked["spec"]; ok {
+		t.Error("expected missing fields not to be added")
+	}
+
+	original := obj["metadata"].(map[string]interface{})["labels"].(map[string]interface{})
+	if original["team.example.com/owner"] != "jane" {
+		t.Error("expected the original object to be left unchanged")
+	}
+}
+
+func TestProcessor_MaskedFields(t *testing.T) {
+	item := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "annotations": map[string]interface{}{"owner": "jane"}},
+	}
+
+	for _, slim := range []bool{true, false} {
+		cfg := DefaultConfig()
+		cfg.SlimOutput = slim
+		cfg.MaskedFields = []string{"metadata.annotations.owner"}
+		p := NewProcessor(cfg)
+
+		result := p.Process([]map[string]interface{}{item})
+		got := result.Items[0]["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})["owner"]
+		if got != MaskedValue {
+			t.Errorf("slim=%v: expected masked field in list output, got %v", slim, got)
+		}
+
+		single := p.ProcessSingle(item)
+		got = single["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})["owner"]
+		if got != MaskedValue {
+			t.Errorf("slim=%v: expected masked field in single output, got %v", slim, got)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PII pattern names accepted in Config.PIIPatterns.
+const (
+	PIIPatternEmail       = "email"
+	PIIPatternIP          = "ip"
+	PIIPatternBearerToken = "bearer_token"
+	PIIPatternAWSKey      = "aws_key"
+)
+
+// piiPattern is a named detector. Matches are replaced with its placeholder.
+type piiPattern struct {
+	name        string
+	re          *regexp.Regexp
+	placeholder string
+}
+
+// PatternSet is an ordered set of named detectors, such as PIIPatternSet or
+// CredentialPatternSet.
+type PatternSet []piiPattern
+
+// Names returns the names of the patterns in s, sorted.
+func (s PatternSet) Names() []string {
+	names := make([]string, 0, len(s))
+	for _, p := range s {
+		names = append(names, p.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsValid reports whether name is a pattern in s.
+func (s PatternSet) IsValid(name string) bool {
+	for _, p := range s {
+		if p.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Detector expressions shared by PIIPatternSet and CredentialPatternSet, so
+// that both recognise a token the same way. Case-insensitive parts use
+// scoped flags so the expressions can be combined.
+const (
+	// Bare JWTs: three base64url segments.
+	jwtExpr = `\beyJ[a-zA-Z0-9_-]{5,}\.[a-zA-Z0-9_-]{5,}\.[a-zA-Z0-9_-]{5,}`
+
+	// "Bearer <token>" headers, with a token long enough not to be a word.
+	bearerTokenExpr = `(?i:\bbearer\s+[a-z0-9\-._~+/]{8,}=*)`
+
+	// Access key IDs, and secret access keys when labelled as such.
+	awsKeyExpr = `\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b|(?i:aws_secret_access_key\s*[=:]\s*[A-Za-z0-9/+=]{40})`
+)
+
+// PIIPatternSet detects the PII named in Config.PIIPatterns, applied in
+// order. Token patterns run before the email and IP patterns so that a token
+// containing an "@" or dotted quad is replaced as a whole.
+var PIIPatternSet = PatternSet{
+	{
+		name:        PIIPatternBearerToken,
+		re:          regexp.MustCompile(bearerTokenExpr + "|" + jwtExpr),
+		placeholder: "***REDACTED:bearer_token***",
+	},
+	{
+		name:        PIIPatternAWSKey,
+		re:          regexp.MustCompile(awsKeyExpr),
+		placeholder: "***REDACTED:aws_key***",
+	},
+	{
+		name:        PIIPatternEmail,
+		re:          regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+		placeholder: "***REDACTED:email***",
+	},
+	{
+		name:        PIIPatternIP,
+		re:          regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\b`),
+		placeholder: "***REDACTED:ip***",
+	},
+}
+
+// PIIScrubCounts maps a pattern name to the number of matches replaced.
+type PIIScrubCounts map[string]int
+
+// Add merges other into c.
+func (c PIIScrubCounts) Add(other PIIScrubCounts) {
+	for k, v := range other {
+		c[k] += v
+	}
+}
+
+// piiScrubber replaces PII matches in strings for a set of enabled patterns.
+type piiScrubber struct {
+	patterns []piiPattern
+	counts   PIIScrubCounts
+}
+
+// newPIIScrubber enables the named patterns; an empty list enables all.
+func newPIIScrubber(enabled []string) *piiScrubber {
+	return newScrubber(PIIPatternSet, enabled)
+}
+
+// newScrubber enables the named patterns of all; an empty list enables all.
+func newScrubber(all PatternSet, enabled []string) *piiScrubber {
+	s := &piiScrubber{counts: PIIScrubCounts{}}
+	if len(enabled) == 0 {
+		s.patterns = all
+		return s
+	}
+	want := make(map[string]bool, len(enabled))
+	for _, name := range enabled {
+		want[strings.TrimSpace(name)] = true
+	}
+	for _, p := range all {
+		if want[p.name] {
+			s.patterns = append(s.patterns, p)
+		}
+	}
+	return s
+}
+
+func (s *piiScrubber) scrubString(v string) string {
+	for _, p := range s.patterns {
+		n := 0
+		v = p.re.ReplaceAllStringFunc(v, func(string) string {
+			n++
+			return p.placeholder
+		})
+		if n > 0 {
+			s.counts[p.name] += n
+		}
+	}
+	return v
+}
+
+// scrubStringMap scrubs every string value of a map[string]interface{} in place.
+func (s *piiScrubber) scrubStringMap(m map[string]interface{}) {
+	for k, v := range m {
+		if str, ok := v.(string); ok {
+			m[k] = s.scrubString(str)
+		}
+	}
+}
+
+// scrubContainers scrubs env values of a containers list in place.
+func (s *piiScrubber) scrubContainers(containers interface{}) {
+	list, ok := containers.([]interface{})
+	if !ok {
+		return
+	}
+	for _, c := range list {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		env, ok := container["env"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, e := range env {
+			if entry, ok := e.(map[string]interface{}); ok {
+				if value, ok := entry["value"].(string); ok {
+					entry["value"] = s.scrubString(value)
+				}
+			}
+		}
+	}
+}
+
+// scrubPodSpec scrubs env values of all container lists in a PodSpec.
+func (s *piiScrubber) scrubPodSpec(spec map[string]interface{}) {
+	for _, key := range []string{"containers", "initContainers", "ephemeralContainers"} {
+		s.scrubContainers(spec[key])
+	}
+}
+
+// scrub applies the scrubber to the fields PII typically hides in:
+// annotations on every object, ConfigMap data, and container env values in
+// Pods and workload pod templates.
+func (s *piiScrubber) scrub(obj map[string]interface{}) {
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			s.scrubStringMap(annotations)
+		}
+	}
+
+	kind, _ := obj["kind"].(string)
+	if strings.EqualFold(kind, "ConfigMap") {
+		if data, ok := obj["data"].(map[string]interface{}); ok {
+			s.scrubStringMap(data)
+		}
+		return
+	}
+
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	s.scrubPodSpec(spec)
+
+	// Deployments, StatefulSets, DaemonSets, ReplicaSets, Jobs.
+	if template, ok := spec["template"].(map[string]interface{}); ok {
+		if podSpec, ok := template["spec"].(map[string]interface{}); ok {
+			s.scrubPodSpec(podSpec)
+		}
+	}
+	// CronJobs.
+	if jobTemplate, ok := spec["jobTemplate"].(map[string]interface{}); ok {
+		if jobSpec, ok := jobTemplate["spec"].(map[string]interface{}); ok {
+			if template, ok := jobSpec["template"].(map[string]interface{}); ok {
+				if podSpec, ok := template["spec"].(map[string]interface{}); ok {
+					s.scrubPodSpec(podSpec)
+				}
+			}
+		}
+	}
+}
+
+// ScrubPII masks common PII patterns (emails, IP addresses, bearer tokens and
+// AWS keys) in annotations, ConfigMap data and container env values. patterns
+// selects which detectors run; an empty list enables all. The input is not
+// modified. Returns the scrubbed copy and the number of matches per pattern.
+func ScrubPII(obj map[string]interface{}, patterns []string) (map[string]interface{}, PIIScrubCounts) {
+	if obj == nil {
+		return nil, PIIScrubCounts{}
+	}
+	s := newPIIScrubber(patterns)
+	result := deepCopyMap(obj)
+	s.scrub(result)
+	return result, s.counts
+}
+
+// ScrubPIIInList applies ScrubPII to every object and returns the combined counts.
+func ScrubPIIInList(objects []map[string]interface{}, patterns []string) ([]map[string]interface{}, PIIScrubCounts) {
+	total := PIIScrubCounts{}
+	if len(objects) == 0 {
+		return objects, total
+	}
+	result := make([]map[string]interface{}, len(objects))
+	for i, obj := range objects {
+		var counts PIIScrubCounts
+		result[i], counts = ScrubPII(obj, patterns)
+		total.Add(counts)
+	}
+	return result, total
+}
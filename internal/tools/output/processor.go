@@ -7,6 +7,10 @@ import (
 // Processor applies output transformations based on configuration.
 type Processor struct {
 	config *Config
+
+	// scrubRecorder, when set, is called with the PII match count per
+	// pattern after each processing run.
+	scrubRecorder func(pattern string, count int)
 }
 
 // NewProcessor creates a new output processor with the given configuration.
@@ -19,6 +23,30 @@ func NewProcessor(config *Config) *Processor {
 	}
 }
 
+// WithScrubRecorder sets a callback that receives PII scrub counts per
+// pattern, typically to feed metrics. It returns the processor for chaining.
+func (p *Processor) WithScrubRecorder(record func(pattern string, count int)) *Processor {
+	p.scrubRecorder = record
+	return p
+}
+
+// ScrubRecorder returns the callback set by WithScrubRecorder, or nil.
+func (p *Processor) ScrubRecorder() func(pattern string, count int) {
+	return p.scrubRecorder
+}
+
+// recordScrubs reports non-zero PII scrub counts to the recorder.
+func (p *Processor) recordScrubs(counts PIIScrubCounts) {
+	if p.scrubRecorder == nil {
+		return
+	}
+	for pattern, n := range counts {
+		if n > 0 {
+			p.scrubRecorder(pattern, n)
+		}
+	}
+}
+
 // Process applies all configured transformations to a list of resources.
 // It returns the processed items and a result containing metadata and warnings.
 func (p *Processor) Process(items []map[string]interface{}) *ProcessingResult {
@@ -54,20 +82,35 @@ func (p *Processor) processInternal(items []map[string]interface{}, limit int) *
 
 	// Apply secret masking first (security critical)
 	if p.config.MaskSecrets {
-		processed = MaskSecretsInList(processed)
+		processed = MaskSecretsInListWithLevel(processed, p.config.SecretRedaction)
 		result.Metadata.SecretsMasked = true
 	}
 
+	// Scrub PII after secrets so redacted secret values are left alone
+	if p.config.ScrubPII {
+		var counts PIIScrubCounts
+		processed, counts = ScrubPIIInList(processed, p.config.PIIPatterns)
+		if len(counts) > 0 {
+			result.Metadata.PIIScrubbed = counts
+		}
+		p.recordScrubs(counts)
+	}
+
+	// Mask the fields the caller asked to hide, whatever the output mode
+	processed = MaskFieldsInList(processed, p.config.MaskedFields)
+
 	// Apply slim output (remove verbose fields), then layer Kind-aware
 	// shaping on top. KindShaping is gated on SlimOutput because it
 	// relies on bookkeeping fields already being gone, and a "wide"
 	// output (SlimOutput=false) with surprise per-Kind drops would
-	// violate the documented contract.
+	// violate the documented contract. The configured kind profiles
+	// only apply to lists, alongside KindShaping.
 	if p.config.SlimOutput {
 		processed = SlimResources(processed, p.config.ExcludedFields)
 		result.Metadata.SlimApplied = true
 		if p.config.KindShaping {
 			processed = ShapeResources(processed)
+			processed = ApplyKindProfiles(processed, p.config.KindProfiles)
 		}
 	}
 
@@ -109,9 +152,17 @@ func (p *Processor) ProcessSingle(item map[string]interface{}) map[string]interf
 
 	// Apply secret masking first (security critical)
 	if p.config.MaskSecrets {
-		processed = MaskSecrets(processed)
+		processed = MaskSecretsWithLevel(processed, p.config.SecretRedaction)
+	}
+
+	if p.config.ScrubPII {
+		var counts PIIScrubCounts
+		processed, counts = ScrubPII(processed, p.config.PIIPatterns)
+		p.recordScrubs(counts)
 	}
 
+	processed = MaskFields(processed, p.config.MaskedFields)
+
 	// Apply slim output, then layer Kind-aware shaping (HelmRelease drops
 	// spec.values / status.history; workload templates collapse long env
 	// lists). KindShaping only runs alongside SlimOutput so callers
@@ -305,3 +305,51 @@ func TestQuickProcessSingle(t *testing.T) {
 		t.Error("QuickProcessSingle should mask secrets by default")
 	}
 }
+
+func TestProcessor_ScrubPII(t *testing.T) {
+	recorded := map[string]int{}
+	p := NewProcessor(&Config{ScrubPII: true}).WithScrubRecorder(func(pattern string, count int) {
+		recorded[pattern] += count
+	})
+
+	items := []map[string]interface{}{
+		{
+			"kind": "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":        "app-config",
+				"annotations": map[string]interface{}{"owner": "jane@example.com"},
+			},
+			"data": map[string]interface{}{"upstream": "10.0.0.1"},
+		},
+	}
+
+	result := p.Process(items)
+
+	if result.Metadata.PIIScrubbed[PIIPatternEmail] != 1 || result.Metadata.PIIScrubbed[PIIPatternIP] != 1 {
+		t.Errorf("PIIScrubbed = %v", result.Metadata.PIIScrubbed)
+	}
+	if recorded[PIIPatternEmail] != 1 || recorded[PIIPatternIP] != 1 {
+		t.Errorf("recorded = %v", recorded)
+	}
+
+	single := p.ProcessSingle(items[0])
+	if single["data"].(map[string]interface{})["upstream"] != "***REDACTED:ip***" {
+		t.Error("ProcessSingle should scrub PII")
+	}
+	if recorded[PIIPatternIP] != 2 {
+		t.Errorf("ProcessSingle should report scrubs, recorded = %v", recorded)
+	}
+}
+
+func TestProcessor_ScrubPII_DisabledByDefault(t *testing.T) {
+	item := map[string]interface{}{
+		"kind": "ConfigMap",
+		"data": map[string]interface{}{"owner": "jane@example.com"},
+	}
+
+	result := NewProcessor(nil).ProcessSingle(item)
+
+	if result["data"].(map[string]interface{})["owner"] != "jane@example.com" {
+		t.Error("PII must not be scrubbed unless ScrubPII is enabled")
+	}
+}
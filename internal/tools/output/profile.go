@@ -0,0 +1,161 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// profileIdentityFields are kept by every kind profile, so that a slimmed
+// resource can still be told apart and looked up.
+var profileIdentityFields = []string{
+	"apiVersion",
+	"kind",
+	"metadata.name",
+	"metadata.namespace",
+}
+
+// ApplyKindProfile slims obj down to the fields of the kind profile that
+// matches it in profiles, plus its apiVersion, kind, name and namespace.
+// Profiles are keyed by "Kind" for any API group, or "Kind.group" (e.g.
+// "HelmRelease.helm.toolkit.fluxcd.io") for one group, which takes
+// precedence. Paths use the notation of ExcludedFields. obj is returned
+// unchanged when no profile matches; otherwise a new map is returned and
+// obj is left untouched.
+func ApplyKindProfile(obj map[string]interface{}, profiles map[string][]string) map[string]interface{} {
+	if obj == nil || len(profiles) == 0 {
+		return obj
+	}
+	key, ok := lookupKey(obj)
+	if !ok {
+		return obj
+	}
+	keep, ok := profiles[key.kind]
+	if key.group != "" {
+		if groupKeep, found := profiles[key.kind+"."+key.group]; found {
+			keep, ok = groupKeep, true
+		}
+	}
+	if !ok {
+		return obj
+	}
+
+	result := make(map[string]interface{})
+	for _, path := range append(append([]string{}, profileIdentityFields...), keep...) {
+		if path != "" {
+			copyField(obj, result, strings.Split(path, "."))
+		}
+	}
+	return result
+}
+
+// ApplyKindProfiles applies ApplyKindProfile to every item in objects,
+// returning a freshly-allocated slice.
+func ApplyKindProfiles(objects []map[string]interface{}, profiles map[string][]string) []map[string]interface{} {
+	if len(objects) == 0 || len(profiles) == 0 {
+		return objects
+	}
+	result := make([]map[string]interface{}, len(objects))
+	for i, o := range objects {
+		result[i] = ApplyKindProfile(o, profiles)
+	}
+	return result
+}
+
+// ValidateKindProfiles checks the keys and paths of kind profiles.
+func ValidateKindProfiles(profiles map[string][]string) error {
+	for key, paths := range profiles {
+		kind, _, _ := strings.Cut(key, ".")
+		if kind == "" {
+			return fmt.Errorf("invalid kind profile %q: the kind is empty", key)
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("invalid kind profile %q: no fields to keep", key)
+		}
+		for _, path := range paths {
+			if path == "" || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") {
+				return fmt.Errorf("invalid kind profile %q: invalid field path %q", key, path)
+			}
+		}
+	}
+	return nil
+}
+
+// copyField copies the field of src at parts to dst, creating the maps and
+// arrays leading to it. Paths resolve like removeField: array wildcards
+// apply to every element and literal dotted keys, such as annotation names,
+// are matched first.
+func copyField(src, dst map[string]interface{}, parts []string) {
+	if len(parts) == 0 || src == nil {
+		return
+	}
+
+	current := parts[0]
+	remaining := parts[1:]
+
+	if strings.HasSuffix(current, "[*]") {
+		fieldName := strings.TrimSuffix(current, "[*]")
+		array, ok := src[fieldName].([]interface{})
+		if !ok {
+			return
+		}
+		if len(remaining) == 0 {
+			dst[fieldName] = deepCopyValue(array)
+			return
+		}
+		dstArray, ok := dst[fieldName].([]interface{})
+		if !ok || len(dstArray) != len(array) {
+			dstArray = make([]interface{}, len(array))
+		}
+		for i, elem := range array {
+			elemMap, ok := elem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			dstElem, ok := dstArray[i].(map[string]interface{})
+			if !ok {
+				dstElem = make(map[string]interface{})
+			}
+			copyField(elemMap, dstElem, remaining)
+			dstArray[i] = dstElem
+		}
+		dst[fieldName] = dstArray
+		return
+	}
+
+	// Literal dotted keys first, as in visitFieldRecursive.
+	maxJoin := len(parts)
+	if i := indexOfArrayWildcard(parts); i >= 0 {
+		maxJoin = i
+	}
+	for end := maxJoin; end > 1; end-- {
+		joined := strings.Join(parts[:end], ".")
+		if val, ok := src[joined]; ok {
+			copyInto(val, dst, joined, parts[end:])
+			return
+		}
+	}
+	if val, ok := src[current]; ok {
+		copyInto(val, dst, current, remaining)
+	}
+}
+
+// copyInto copies val, the value of key, to dst: whole when remaining is
+// empty, otherwise only the field at remaining.
+func copyInto(val interface{}, dst map[string]interface{}, key string, remaining []string) {
+	if len(remaining) == 0 {
+		dst[key] = deepCopyValue(val)
+		return
+	}
+	valMap, ok := val.(map[string]interface{})
+	if !ok {
+		return
+	}
+	dstMap, ok := dst[key].(map[string]interface{})
+	if !ok {
+		dstMap = make(map[string]interface{})
+	}
+	copyField(valMap, dstMap, remaining)
+	if len(dstMap) > 0 {
+		dst[key] = dstMap
+	}
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplePod() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "web-0",
+			"namespace": "prod",
+			"labels":    map[string]interface{}{"app": "web"},
+			"annotations": map[string]interface{}{
+				"example.com/owner": "team-a",
+				"example.com/notes": "long text",
+			},
+		},
+		"spec": map[string]interface{}{
+			"nodeName": "node-1",
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx:1.27", "args": []interface{}{"--verbose"}},
+			},
+		},
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"podIP": "10.0.0.1",
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "web", "restartCount": int64(3), "image": "nginx:1.27", "imageID": "sha256:abc"},
+				map[string]interface{}{"name": "sidecar", "restartCount": int64(0), "image": "envoy:1.30", "imageID": "sha256:def"},
+			},
+		},
+	}
+}
+
+func TestApplyKindProfile(t *testing.T) {
+	profiles := map[string][]string{
+		"Pod": {
+			"status.phase",
+			"status.containerStatuses[*].name",
+			"status.containerStatuses[*].restartCount",
+			"status.containerStatuses[*].image",
+			"metadata.annotations.example.com/owner",
+		},
+	}
+	obj := samplePod()
+
+	got := ApplyKindProfile(obj, profiles)
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":        "web-0",
+			"namespace":   "prod",
+			"annotations": map[string]interface{}{"example.com/owner": "team-a"},
+		},
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "web", "restartCount": int64(3), "image": "nginx:1.27"},
+				map[string]interface{}{"name": "sidecar", "restartCount": int64(0), "image": "envoy:1.30"},
+			},
+		},
+	}, got)
+	assert.Equal(t, samplePod(), obj, "the input is not changed")
+}
+
+func TestApplyKindProfile_WholeFields(t *testing.T) {
+	got := ApplyKindProfile(samplePod(), map[string][]string{
+		"Pod": {"spec.containers[*]", "status", "status.phase", "missing.field"},
+	})
+	assert.Equal(t, samplePod()["status"], got["status"])
+	assert.Equal(t, samplePod()["spec"].(map[string]interface{})["containers"], got["spec"].(map[string]interface{})["containers"])
+	assert.NotContains(t, got, "missing")
+}
+
+func TestApplyKindProfile_Matching(t *testing.T) {
+	release := map[string]interface{}{
+		"apiVersion": "helm.toolkit.fluxcd.io/v2",
+		"kind":       "HelmRelease",
+		"metadata":   map[string]interface{}{"name": "app"},
+		"spec":       map[string]interface{}{"chart": "app", "interval": "5m"},
+	}
+
+	got := ApplyKindProfile(release, map[string][]string{"HelmRelease": {"spec.chart"}})
+	assert.Equal(t, map[string]interface{}{"chart": "app"}, got["spec"], "a kind profile applies to any group")
+
+	got = ApplyKindProfile(release, map[string][]string{
+		"HelmRelease":                        {"spec.chart"},
+		"HelmRelease.helm.toolkit.fluxcd.io": {"spec.interval"},
+	})
+	assert.Equal(t, map[string]interface{}{"interval": "5m"}, got["spec"], "a group profile takes precedence")
+
+	got = ApplyKindProfile(release, map[string][]string{"HelmRelease.example.com": {"spec.chart"}})
+	assert.Equal(t, release, got, "a profile of another group does not apply")
+
+	pod := samplePod()
+	assert.Equal(t, pod, ApplyKindProfile(pod, map[string][]string{"Node": {"status.allocatable"}}))
+	assert.Equal(t, pod, ApplyKindProfile(pod, nil))
+	assert.Nil(t, ApplyKindProfile(nil, map[string][]string{"Pod": {"status"}}))
+}
+
+func TestValidateKindProfiles(t *testing.T) {
+	assert.NoError(t, ValidateKindProfiles(nil))
+	assert.NoError(t, ValidateKindProfiles(map[string][]string{
+		"Pod":                                {"status.phase", "status.containerStatuses[*].restartCount"},
+		"Deployment.apps":                    {"status"},
+		"HelmRelease.helm.toolkit.fluxcd.io": {"status.conditions"},
+	}))
+	assert.ErrorContains(t, ValidateKindProfiles(map[string][]string{".apps": {"status"}}), "the kind is empty")
+	assert.ErrorContains(t, ValidateKindProfiles(map[string][]string{"Pod": nil}), "no fields to keep")
+	assert.ErrorContains(t, ValidateKindProfiles(map[string][]string{"Pod": {"status."}}), `invalid field path "status."`)
+}
+
+func TestProcessor_KindProfilesApplyToLists(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.KindProfiles = map[string][]string{"Pod": {"status.phase"}}
+	p := NewProcessor(cfg)
+
+	result := p.Process([]map[string]interface{}{samplePod()})
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, map[string]interface{}{"phase": "Running"}, result.Items[0]["status"])
+	assert.NotContains(t, result.Items[0], "spec")
+
+	single := p.ProcessSingle(samplePod())
+	assert.Contains(t, single, "spec", "single resources keep every field")
+
+	cfg.KindShaping = false
+	result = NewProcessor(cfg).Process([]map[string]interface{}{samplePod()})
+	assert.Contains(t, result.Items[0], "spec", "profiles only apply with kind shaping")
+
+	clone := cfg.Clone()
+	clone.KindProfiles["Pod"][0] = "spec"
+	assert.Equal(t, "status.phase", cfg.KindProfiles["Pod"][0], "clones do not share profiles")
+}
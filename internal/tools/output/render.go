@@ -0,0 +1,63 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+)
+
+// Table text formats, besides JSON.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// markdownEscaper keeps cell text from breaking a Markdown table row.
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// Markdown renders t as a GitHub-flavoured Markdown table, which most chat
+// clients display as a table. Empty cells are rendered as empty.
+func (t *Table) Markdown() string {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" ")
+			b.WriteString(markdownEscaper.Replace(cell))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(t.Columns)
+	b.WriteString("|")
+	for range t.Columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+// CSV renders t as RFC 4180 CSV with the column headers as first record.
+func (t *Table) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Columns); err != nil {
+		return "", err
+	}
+	if err := w.WriteAll(t.Rows); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Render renders t in format, FormatMarkdown or FormatCSV.
+func (t *Table) Render(format string) (string, error) {
+	if format == FormatCSV {
+		return t.CSV()
+	}
+	return t.Markdown(), nil
+}
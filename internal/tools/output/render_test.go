@@ -0,0 +1,36 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableMarkdown(t *testing.T) {
+	table := &Table{
+		Columns: []string{"NAME", "STATUS"},
+		Rows:    [][]string{{"web-1", "Running"}, {"a|b", "line\nbreak"}, {"empty", ""}},
+	}
+	assert.Equal(t, "| NAME | STATUS |\n| --- | --- |\n| web-1 | Running |\n| a\\|b | line break |\n| empty |  |\n", table.Markdown())
+
+	empty := &Table{Columns: []string{"NAME"}}
+	assert.Equal(t, "| NAME |\n| --- |\n", empty.Markdown())
+}
+
+func TestTableCSV(t *testing.T) {
+	table := &Table{
+		Columns: []string{"NAME", "LABELS"},
+		Rows:    [][]string{{"web-1", "app=web,tier=frontend"}, {"web-2", `say "hi"`}},
+	}
+	out, err := table.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, "NAME,LABELS\nweb-1,\"app=web,tier=frontend\"\nweb-2,\"say \"\"hi\"\"\"\n", out)
+
+	rendered, err := table.Render(FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, out, rendered)
+	rendered, err = table.Render(FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, table.Markdown(), rendered)
+}
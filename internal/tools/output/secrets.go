@@ -1,12 +1,47 @@
 package output
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"strings"
 )
 
 // RedactedValue is the placeholder used for masked secret data.
 const RedactedValue = "***REDACTED***"
 
+// Secret redaction levels.
+const (
+	// SecretRedactionFull drops the data and stringData of a Secret, keeping
+	// only how many keys it had.
+	SecretRedactionFull = "full"
+
+	// SecretRedactionKeys keeps the key names and replaces every value with
+	// RedactedValue. This is the default.
+	SecretRedactionKeys = "keys"
+
+	// SecretRedactionHash keeps the key names and replaces every value with
+	// a short SHA-256 of the decoded value, so values can be compared across
+	// Secrets and clusters without being revealed.
+	SecretRedactionHash = "hash"
+)
+
+// SecretRedactionLevels returns the supported secret redaction levels.
+func SecretRedactionLevels() []string {
+	return []string{SecretRedactionFull, SecretRedactionKeys, SecretRedactionHash}
+}
+
+// ValidateSecretRedaction returns an error for an unknown redaction level.
+// Empty means the default level.
+func ValidateSecretRedaction(level string) error {
+	switch level {
+	case "", SecretRedactionFull, SecretRedactionKeys, SecretRedactionHash:
+		return nil
+	}
+	return fmt.Errorf("unknown secret redaction level %q (valid: %s)", level, strings.Join(SecretRedactionLevels(), ", "))
+}
+
 // secretTypes lists Kubernetes secret types that should always be masked.
 var secretTypes = map[string]bool{
 	"kubernetes.io/service-account-token": true,
@@ -40,6 +75,12 @@ var sensitiveConfigMapPatterns = []string{
 // MaskSecrets replaces secret data with redacted placeholders.
 // This prevents accidental exposure of sensitive data in tool responses.
 func MaskSecrets(obj map[string]interface{}) map[string]interface{} {
+	return MaskSecretsWithLevel(obj, SecretRedactionKeys)
+}
+
+// MaskSecretsWithLevel masks secret data at the given redaction level. An
+// empty or unknown level masks at SecretRedactionKeys.
+func MaskSecretsWithLevel(obj map[string]interface{}, level string) map[string]interface{} {
 	if obj == nil {
 		return nil
 	}
@@ -50,7 +91,7 @@ func MaskSecrets(obj map[string]interface{}) map[string]interface{} {
 	// Check if this is a Secret resource
 	kind, _ := result["kind"].(string)
 	if strings.EqualFold(kind, "Secret") {
-		maskSecretData(result)
+		maskSecretData(result, level)
 	}
 
 	return result
@@ -58,25 +99,45 @@ func MaskSecrets(obj map[string]interface{}) map[string]interface{} {
 
 // MaskSecretsInList masks secrets in a list of resources.
 func MaskSecretsInList(objects []map[string]interface{}) []map[string]interface{} {
+	return MaskSecretsInListWithLevel(objects, SecretRedactionKeys)
+}
+
+// MaskSecretsInListWithLevel masks secrets in a list of resources at the
+// given redaction level.
+func MaskSecretsInListWithLevel(objects []map[string]interface{}, level string) []map[string]interface{} {
 	if len(objects) == 0 {
 		return objects
 	}
 
 	result := make([]map[string]interface{}, len(objects))
 	for i, obj := range objects {
-		result[i] = MaskSecrets(obj)
+		result[i] = MaskSecretsWithLevel(obj, level)
 	}
 
 	return result
 }
 
 // maskSecretData masks the data and stringData fields of a Secret.
-func maskSecretData(secret map[string]interface{}) {
+func maskSecretData(secret map[string]interface{}, level string) {
+	if level == SecretRedactionFull {
+		keys := 0
+		for _, field := range []string{"data", "stringData"} {
+			if values, ok := secret[field].(map[string]interface{}); ok {
+				keys += len(values)
+				delete(secret, field)
+			}
+		}
+		secret["dataKeys"] = keys
+		secret["_dataRedacted"] = true
+		maskSensitiveAnnotations(secret)
+		return
+	}
+
 	// Mask data field (base64 encoded values)
 	if data, ok := secret["data"].(map[string]interface{}); ok {
 		maskedData := make(map[string]interface{}, len(data))
-		for key := range data {
-			maskedData[key] = RedactedValue
+		for key, value := range data {
+			maskedData[key] = redactSecretValue(value, true, level)
 		}
 		secret["data"] = maskedData
 	}
@@ -84,8 +145,8 @@ func maskSecretData(secret map[string]interface{}) {
 	// Mask stringData field (plain text values)
 	if stringData, ok := secret["stringData"].(map[string]interface{}); ok {
 		maskedStringData := make(map[string]interface{}, len(stringData))
-		for key := range stringData {
-			maskedStringData[key] = RedactedValue
+		for key, value := range stringData {
+			maskedStringData[key] = redactSecretValue(value, false, level)
 		}
 		secret["stringData"] = maskedStringData
 	}
@@ -95,6 +156,27 @@ func maskSecretData(secret map[string]interface{}) {
 	maskSensitiveAnnotations(secret)
 }
 
+// redactSecretValue returns the placeholder for one secret value. At
+// SecretRedactionHash, data values are hashed after base64 decoding so the
+// same value hashes alike in data and stringData.
+func redactSecretValue(value interface{}, encoded bool, level string) interface{} {
+	if level != SecretRedactionHash {
+		return RedactedValue
+	}
+	s, ok := value.(string)
+	if !ok {
+		return RedactedValue
+	}
+	raw := []byte(s)
+	if encoded {
+		if decoded, err := base64.StdEncoding.DecodeString(s); err == nil {
+			raw = decoded
+		}
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
 // maskSensitiveAnnotations masks known sensitive annotations.
 func maskSensitiveAnnotations(obj map[string]interface{}) {
 	metadata, ok := obj["metadata"].(map[string]interface{})
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -417,3 +418,69 @@ func TestRedactedValue(t *testing.T) {
 		t.Errorf("RedactedValue = %q, want %q", RedactedValue, "***REDACTED***")
 	}
 }
+
+func TestMaskSecretsWithLevel(t *testing.T) {
+	secret := map[string]interface{}{
+		"kind": "Secret",
+		"data": map[string]interface{}{
+			"password": "aHVudGVyMg==", // hunter2
+		},
+		"stringData": map[string]interface{}{
+			"copy": "hunter2",
+		},
+	}
+
+	t.Run("keys", func(t *testing.T) {
+		result := MaskSecretsWithLevel(secret, SecretRedactionKeys)
+		if result["data"].(map[string]interface{})["password"] != RedactedValue {
+			t.Error("data value should be redacted")
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		result := MaskSecretsWithLevel(secret, SecretRedactionFull)
+		if _, ok := result["data"]; ok {
+			t.Error("data should be dropped")
+		}
+		if _, ok := result["stringData"]; ok {
+			t.Error("stringData should be dropped")
+		}
+		if result["dataKeys"] != 2 {
+			t.Errorf("dataKeys = %v, want 2", result["dataKeys"])
+		}
+		if result["_dataRedacted"] != true {
+			t.Error("_dataRedacted should be set")
+		}
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		result := MaskSecretsWithLevel(secret, SecretRedactionHash)
+		hashed := result["data"].(map[string]interface{})["password"].(string)
+		if !strings.HasPrefix(hashed, "sha256:") || len(hashed) != len("sha256:")+16 {
+			t.Errorf("unexpected hash %q", hashed)
+		}
+		if strings.Contains(hashed, "hunter2") || strings.Contains(hashed, "aHVudGVyMg") {
+			t.Error("hash must not contain the value")
+		}
+		if copied := result["stringData"].(map[string]interface{})["copy"]; copied != hashed {
+			t.Errorf("stringData hash %v should equal the decoded data hash %v", copied, hashed)
+		}
+	})
+
+	t.Run("original untouched", func(t *testing.T) {
+		if secret["data"].(map[string]interface{})["password"] != "aHVudGVyMg==" {
+			t.Error("original object was modified")
+		}
+	})
+}
+
+func TestValidateSecretRedaction(t *testing.T) {
+	for _, level := range append(SecretRedactionLevels(), "") {
+		if err := ValidateSecretRedaction(level); err != nil {
+			t.Errorf("level %q: unexpected error %v", level, err)
+		}
+	}
+	if err := ValidateSecretRedaction("partial"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -65,11 +65,14 @@ func removeField(obj map[string]interface{}, path string) {
 	}
 
 	parts := strings.Split(path, ".")
-	removeFieldRecursive(obj, parts)
+	visitFieldRecursive(obj, parts, func(m map[string]interface{}, key string) {
+		delete(m, key)
+	})
 }
 
-// removeFieldRecursive handles the recursive field removal.
-func removeFieldRecursive(obj map[string]interface{}, parts []string) {
+// visitFieldRecursive finds the fields at parts, resolved like removeField,
+// and calls visit with the map holding each of them and its key.
+func visitFieldRecursive(obj map[string]interface{}, parts []string, visit func(m map[string]interface{}, key string)) {
 	if len(parts) == 0 || obj == nil {
 		return
 	}
@@ -94,10 +97,10 @@ func removeFieldRecursive(obj map[string]interface{}, parts []string) {
 		for _, elem := range array {
 			if elemMap, ok := elem.(map[string]interface{}); ok {
 				if len(remaining) == 0 {
-					// Can't remove array element itself via this API
+					// Can't visit array element itself via this API
 					continue
 				}
-				removeFieldRecursive(elemMap, remaining)
+				visitFieldRecursive(elemMap, remaining, visit)
 			}
 		}
 		return
@@ -105,7 +108,9 @@ func removeFieldRecursive(obj map[string]interface{}, parts []string) {
 
 	// Check if this is the last part
 	if len(remaining) == 0 {
-		delete(obj, current)
+		if _, ok := obj[current]; ok {
+			visit(obj, current)
+		}
 		return
 	}
 
@@ -126,12 +131,12 @@ func removeFieldRecursive(obj map[string]interface{}, parts []string) {
 			continue
 		}
 		if end == len(parts) {
-			delete(obj, joined)
+			visit(obj, joined)
 			return
 		}
 		// Joined key matched but path continues — recurse into its value.
 		if subMap, ok := val.(map[string]interface{}); ok {
-			removeFieldRecursive(subMap, parts[end:])
+			visitFieldRecursive(subMap, parts[end:], visit)
 			return
 		}
 	}
@@ -147,7 +152,7 @@ func removeFieldRecursive(obj map[string]interface{}, parts []string) {
 		return
 	}
 
-	removeFieldRecursive(nextMap, remaining)
+	visitFieldRecursive(nextMap, remaining, visit)
 }
 
 // indexOfArrayWildcard returns the index of the first part ending in "[*]",
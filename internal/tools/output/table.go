@@ -0,0 +1,645 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Table is a column-oriented rendering of a list of resources, like
+// `kubectl get -o wide`: one row of cell strings per resource instead of a
+// nested object. Keys are written once per response instead of once per
+// item, which makes tables far cheaper in tokens than even slim JSON.
+type Table struct {
+	// Kind is the kind of the listed resources, e.g. "Pod".
+	Kind string `json:"kind,omitempty"`
+
+	// Namespace is set when every row is in the same namespace, in which
+	// case the NAMESPACE column is left out.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Columns are the column headers.
+	Columns []string `json:"columns"`
+
+	// Rows hold one cell per column. An empty cell means the resource has
+	// no value for the column.
+	Rows [][]string `json:"rows"`
+}
+
+// tableColumn is a column of a table and how to fill its cells.
+type tableColumn struct {
+	name string
+	cell func(obj map[string]interface{}, now time.Time) string
+}
+
+// Columns shared by most kinds.
+var (
+	nameColumn = tableColumn{"NAME", func(obj map[string]interface{}, _ time.Time) string {
+		return nestedString(obj, "metadata", "name")
+	}}
+	ageColumn = tableColumn{"AGE", func(obj map[string]interface{}, now time.Time) string {
+		return ageSince(nestedString(obj, "metadata", "creationTimestamp"), now)
+	}}
+	kindColumn = tableColumn{"KIND", func(obj map[string]interface{}, _ time.Time) string {
+		return extractKind(obj)
+	}}
+	namespaceColumn = tableColumn{"NAMESPACE", func(obj map[string]interface{}, _ time.Time) string {
+		return extractNamespace(obj)
+	}}
+	labelsColumn = tableColumn{"LABELS", func(obj map[string]interface{}, _ time.Time) string {
+		return joinMap(nestedMap(obj, "metadata", "labels"))
+	}}
+	containersColumn = tableColumn{"CONTAINERS", func(obj map[string]interface{}, _ time.Time) string {
+		return joinContainers(obj, "name")
+	}}
+	imagesColumn = tableColumn{"IMAGES", func(obj map[string]interface{}, _ time.Time) string {
+		return joinContainers(obj, "image")
+	}}
+	phaseColumn = tableColumn{"STATUS", func(obj map[string]interface{}, _ time.Time) string {
+		return extractPhaseStatus(obj)
+	}}
+)
+
+// intColumn is a column showing the integer at path.
+func intColumn(name string, path ...string) tableColumn {
+	return tableColumn{name, func(obj map[string]interface{}, _ time.Time) string {
+		if v, ok := getNestedInt(obj, path...); ok {
+			return strconv.Itoa(v)
+		}
+		return ""
+	}}
+}
+
+// stringColumn is a column showing the string at path.
+func stringColumn(name string, path ...string) tableColumn {
+	return tableColumn{name, func(obj map[string]interface{}, _ time.Time) string {
+		return nestedString(obj, path...)
+	}}
+}
+
+// ratioColumn is a column showing "<done>/<desired>", e.g. ready replicas.
+func ratioColumn(name string, donePath, desiredPath []string) tableColumn {
+	return tableColumn{name, func(obj map[string]interface{}, _ time.Time) string {
+		done, _ := getNestedInt(obj, donePath...)
+		desired, _ := getNestedInt(obj, desiredPath...)
+		return fmt.Sprintf("%d/%d", done, desired)
+	}}
+}
+
+// tableColumns are the columns per lower-case kind, after NAMESPACE. Kinds
+// not listed get defaultTableColumns.
+var tableColumns = map[string][]tableColumn{
+	"pod": {
+		nameColumn,
+		{"READY", podReady},
+		{"STATUS", podStatus},
+		{"RESTARTS", podRestarts},
+		ageColumn,
+		stringColumn("IP", "status", "podIP"),
+		stringColumn("NODE", "spec", "nodeName"),
+	},
+	"deployment": {
+		nameColumn,
+		ratioColumn("READY", []string{"status", "readyReplicas"}, []string{"spec", "replicas"}),
+		intColumn("UP-TO-DATE", "status", "updatedReplicas"),
+		intColumn("AVAILABLE", "status", "availableReplicas"),
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+	},
+	"statefulset": {
+		nameColumn,
+		ratioColumn("READY", []string{"status", "readyReplicas"}, []string{"spec", "replicas"}),
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+	},
+	"replicaset": {
+		nameColumn,
+		intColumn("DESIRED", "spec", "replicas"),
+		intColumn("CURRENT", "status", "replicas"),
+		intColumn("READY", "status", "readyReplicas"),
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+	},
+	"daemonset": {
+		nameColumn,
+		intColumn("DESIRED", "status", "desiredNumberScheduled"),
+		intColumn("CURRENT", "status", "currentNumberScheduled"),
+		intColumn("READY", "status", "numberReady"),
+		intColumn("UP-TO-DATE", "status", "updatedNumberScheduled"),
+		intColumn("AVAILABLE", "status", "numberAvailable"),
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+	},
+	"job": {
+		nameColumn,
+		{"STATUS", func(obj map[string]interface{}, _ time.Time) string { return extractJobStatus(obj) }},
+		ratioColumn("COMPLETIONS", []string{"status", "succeeded"}, []string{"spec", "completions"}),
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+	},
+	"cronjob": {
+		nameColumn,
+		stringColumn("SCHEDULE", "spec", "schedule"),
+		{"SUSPEND", func(obj map[string]interface{}, _ time.Time) string {
+			suspend, _ := nestedValue(obj, "spec", "suspend").(bool)
+			return strconv.FormatBool(suspend)
+		}},
+		{"ACTIVE", func(obj map[string]interface{}, _ time.Time) string {
+			active, _ := nestedValue(obj, "status", "active").([]interface{})
+			return strconv.Itoa(len(active))
+		}},
+		{"LAST SCHEDULE", func(obj map[string]interface{}, now time.Time) string {
+			return ageSince(nestedString(obj, "status", "lastScheduleTime"), now)
+		}},
+		ageColumn,
+	},
+	"service": {
+		nameColumn,
+		stringColumn("TYPE", "spec", "type"),
+		stringColumn("CLUSTER-IP", "spec", "clusterIP"),
+		{"EXTERNAL-IP", serviceExternalIP},
+		{"PORTS", servicePorts},
+		ageColumn,
+		{"SELECTOR", func(obj map[string]interface{}, _ time.Time) string {
+			return joinMap(nestedMap(obj, "spec", "selector"))
+		}},
+	},
+	"ingress": {
+		nameColumn,
+		stringColumn("CLASS", "spec", "ingressClassName"),
+		{"HOSTS", ingressHosts},
+		{"ADDRESS", func(obj map[string]interface{}, _ time.Time) string {
+			return loadBalancerAddresses(nestedValue(obj, "status", "loadBalancer", "ingress"))
+		}},
+		ageColumn,
+	},
+	"node": {
+		nameColumn,
+		{"STATUS", func(obj map[string]interface{}, _ time.Time) string {
+			status := extractNodeStatus(obj)
+			if unschedulable, _ := nestedValue(obj, "spec", "unschedulable").(bool); unschedulable {
+				status += ",SchedulingDisabled"
+			}
+			return status
+		}},
+		{"ROLES", nodeRoles},
+		ageColumn,
+		stringColumn("VERSION", "status", "nodeInfo", "kubeletVersion"),
+		{"INTERNAL-IP", func(obj map[string]interface{}, _ time.Time) string {
+			return nodeAddress(obj, "InternalIP")
+		}},
+		stringColumn("OS-IMAGE", "status", "nodeInfo", "osImage"),
+	},
+	"namespace": {
+		nameColumn,
+		phaseColumn,
+		ageColumn,
+	},
+	"persistentvolumeclaim": {
+		nameColumn,
+		phaseColumn,
+		stringColumn("VOLUME", "spec", "volumeName"),
+		stringColumn("CAPACITY", "status", "capacity", "storage"),
+		{"ACCESS MODES", func(obj map[string]interface{}, _ time.Time) string {
+			return joinStrings(nestedValue(obj, "spec", "accessModes"))
+		}},
+		stringColumn("STORAGECLASS", "spec", "storageClassName"),
+		ageColumn,
+	},
+	"persistentvolume": {
+		nameColumn,
+		stringColumn("CAPACITY", "spec", "capacity", "storage"),
+		{"ACCESS MODES", func(obj map[string]interface{}, _ time.Time) string {
+			return joinStrings(nestedValue(obj, "spec", "accessModes"))
+		}},
+		stringColumn("RECLAIM POLICY", "spec", "persistentVolumeReclaimPolicy"),
+		phaseColumn,
+		{"CLAIM", func(obj map[string]interface{}, _ time.Time) string {
+			name := nestedString(obj, "spec", "claimRef", "name")
+			if ns := nestedString(obj, "spec", "claimRef", "namespace"); ns != "" && name != "" {
+				return ns + "/" + name
+			}
+			return name
+		}},
+		stringColumn("STORAGECLASS", "spec", "storageClassName"),
+		ageColumn,
+	},
+	"configmap": {
+		nameColumn,
+		{"DATA", func(obj map[string]interface{}, _ time.Time) string {
+			return strconv.Itoa(len(nestedMap(obj, "data")) + len(nestedMap(obj, "binaryData")))
+		}},
+		ageColumn,
+	},
+	// Secret values never make it into a table; the key count is left out
+	// too, since some redaction levels drop the keys.
+	"secret": {
+		nameColumn,
+		stringColumn("TYPE", "type"),
+		ageColumn,
+	},
+	"event": {
+		{"LAST SEEN", func(obj map[string]interface{}, now time.Time) string {
+			for _, k := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+				if v := nestedString(obj, k); v != "" {
+					return ageSince(v, now)
+				}
+			}
+			return ""
+		}},
+		stringColumn("TYPE", "type"),
+		stringColumn("REASON", "reason"),
+		{"OBJECT", func(obj map[string]interface{}, _ time.Time) string {
+			kind := strings.ToLower(nestedString(obj, "involvedObject", "kind"))
+			name := nestedString(obj, "involvedObject", "name")
+			if kind == "" {
+				return name
+			}
+			return kind + "/" + name
+		}},
+		intColumn("COUNT", "count"),
+		stringColumn("MESSAGE", "message"),
+	},
+}
+
+// defaultTableColumns are the columns of kinds without their own, such as
+// custom resources.
+var defaultTableColumns = []tableColumn{
+	nameColumn,
+	{"STATUS", func(obj map[string]interface{}, _ time.Time) string { return extractStatus(obj) }},
+	{"READY", func(obj map[string]interface{}, _ time.Time) string { return conditionStatus(obj, "Ready") }},
+	ageColumn,
+}
+
+// BuildTable renders objects as a Table with the columns of their kind,
+// like `kubectl get -o wide`. Objects of mixed kinds get generic columns
+// and a KIND column. A NAMESPACE column is added when the objects span
+// several namespaces, and a LABELS column when showLabels is set. Columns
+// that are empty in every row are left out.
+//
+// BuildTable reads already processed objects, so fields removed or masked
+// by the Processor stay hidden.
+func BuildTable(objects []map[string]interface{}, showLabels bool) *Table {
+	return buildTable(objects, showLabels, time.Now())
+}
+
+func buildTable(objects []map[string]interface{}, showLabels bool, now time.Time) *Table {
+	table := &Table{Rows: make([][]string, 0, len(objects))}
+
+	kinds := map[string]bool{}
+	namespaces := map[string]bool{}
+	for _, obj := range objects {
+		kinds[extractKind(obj)] = true
+		namespaces[extractNamespace(obj)] = true
+	}
+
+	columns := defaultTableColumns
+	switch {
+	case len(kinds) > 1:
+		columns = append([]tableColumn{kindColumn}, defaultTableColumns...)
+	case len(objects) > 0:
+		table.Kind = extractKind(objects[0])
+		if kc, ok := tableColumns[strings.ToLower(table.Kind)]; ok {
+			columns = kc
+		}
+	}
+	if len(namespaces) > 1 {
+		columns = append([]tableColumn{namespaceColumn}, columns...)
+	} else if len(objects) > 0 {
+		table.Namespace = extractNamespace(objects[0])
+	}
+	if showLabels {
+		columns = append(columns[:len(columns):len(columns)], labelsColumn)
+	}
+
+	rows := make([][]string, len(objects))
+	used := make([]bool, len(columns))
+	for i, obj := range objects {
+		rows[i] = make([]string, len(columns))
+		for j, col := range columns {
+			rows[i][j] = col.cell(obj, now)
+			if rows[i][j] != "" {
+				used[j] = true
+			}
+		}
+	}
+
+	for j, col := range columns {
+		if used[j] || len(objects) == 0 {
+			table.Columns = append(table.Columns, col.name)
+		}
+	}
+	for _, row := range rows {
+		kept := make([]string, 0, len(table.Columns))
+		for j, cell := range row {
+			if used[j] {
+				kept = append(kept, cell)
+			}
+		}
+		table.Rows = append(table.Rows, kept)
+	}
+	return table
+}
+
+// ObjectState is the one-line state of an object: its status and, for pods
+// and workloads, how many of its containers or replicas are ready.
+type ObjectState struct {
+	Status   string `json:"status,omitempty"`
+	Ready    string `json:"ready,omitempty"`
+	Restarts int    `json:"restarts,omitempty"`
+}
+
+// ObjectStatus returns the state of obj the way the table output shows it:
+// a waiting container's reason wins over a pod's phase, workloads report
+// Ready, Partially Ready, NotReady or Scaled to Zero, and other kinds their
+// phase or Ready condition.
+func ObjectStatus(obj map[string]interface{}) ObjectState {
+	switch strings.ToLower(extractKind(obj)) {
+	case "pod":
+		restarts, _ := strconv.Atoi(podRestarts(obj, time.Time{}))
+		return ObjectState{Status: podStatus(obj, time.Time{}), Ready: podReady(obj, time.Time{}), Restarts: restarts}
+	case "deployment", "statefulset", "replicaset":
+		ready := ratioColumn("", []string{"status", "readyReplicas"}, []string{"spec", "replicas"})
+		return ObjectState{Status: extractWorkloadStatus(obj), Ready: ready.cell(obj, time.Time{})}
+	case "daemonset":
+		desired, _ := getNestedInt(obj, "status", "desiredNumberScheduled")
+		ready, _ := getNestedInt(obj, "status", "numberReady")
+		state := ObjectState{Status: statusNotReady, Ready: fmt.Sprintf("%d/%d", ready, desired)}
+		switch {
+		case ready >= desired:
+			state.Status = statusReady
+		case ready > 0:
+			state.Status = statusPartiallyReady
+		}
+		return state
+	}
+	status := extractStatus(obj)
+	if status == "" {
+		switch conditionStatus(obj, statusReady) {
+		case "True":
+			status = statusReady
+		case "False":
+			status = statusNotReady
+		}
+	}
+	return ObjectState{Status: status}
+}
+
+// progressingPodStatuses are the statuses of pods that are starting, running
+// or stopping normally.
+var progressingPodStatuses = []string{"Running", "Pending", "ContainerCreating", "PodInitializing", "Terminating", "Completed", "Succeeded"}
+
+// PodFailing reports whether a pod is failing, with its status as
+// ObjectStatus shows it: failed, or with a container crash looping, unable
+// to pull its image or unable to start. Pending pods are not failing.
+func PodFailing(obj map[string]interface{}) (string, bool) {
+	phase := extractPhaseStatus(obj)
+	if phase == "Succeeded" {
+		return "", false
+	}
+	status := podStatus(obj, time.Time{})
+	if phase == "Failed" {
+		return status, true
+	}
+	if status == "" {
+		return "", false
+	}
+	return status, !slices.Contains(progressingPodStatuses, strings.TrimPrefix(status, "Init:"))
+}
+
+// podReady shows ready containers out of all containers.
+func podReady(obj map[string]interface{}, _ time.Time) string {
+	containers, _ := nestedValue(obj, "spec", "containers").([]interface{})
+	statuses, _ := nestedValue(obj, "status", "containerStatuses").([]interface{})
+	ready := 0
+	for _, s := range statuses {
+		if m, ok := s.(map[string]interface{}); ok && m["ready"] == true {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, max(len(containers), len(statuses)))
+}
+
+// podStatus shows the pod's status the way kubectl does: the reason a
+// container is waiting or terminated wins over the phase.
+func podStatus(obj map[string]interface{}, _ time.Time) string {
+	if nestedString(obj, "metadata", "deletionTimestamp") != "" {
+		return "Terminating"
+	}
+	if reason := nestedString(obj, "status", "reason"); reason != "" {
+		return reason
+	}
+	for _, key := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := nestedValue(obj, "status", key).([]interface{})
+		for _, s := range statuses {
+			for _, state := range []string{"waiting", "terminated"} {
+				reason := nestedString(s, "state", state, "reason")
+				if reason == "" || (state == "terminated" && reason == "Completed") {
+					continue
+				}
+				if key == "initContainerStatuses" {
+					return "Init:" + reason
+				}
+				return reason
+			}
+		}
+	}
+	return extractPhaseStatus(obj)
+}
+
+// podRestarts sums the restarts of the pod's containers.
+func podRestarts(obj map[string]interface{}, _ time.Time) string {
+	statuses, _ := nestedValue(obj, "status", "containerStatuses").([]interface{})
+	restarts := 0
+	for _, s := range statuses {
+		n, _ := getNestedInt(s, "restartCount")
+		restarts += n
+	}
+	return strconv.Itoa(restarts)
+}
+
+// serviceExternalIP shows a service's load balancer or external addresses.
+func serviceExternalIP(obj map[string]interface{}, _ time.Time) string {
+	if addrs := loadBalancerAddresses(nestedValue(obj, "status", "loadBalancer", "ingress")); addrs != "" {
+		return addrs
+	}
+	if ips := joinStrings(nestedValue(obj, "spec", "externalIPs")); ips != "" {
+		return ips
+	}
+	return nestedString(obj, "spec", "externalName")
+}
+
+// servicePorts shows a service's ports as kubectl does, e.g. "80:30080/TCP".
+func servicePorts(obj map[string]interface{}, _ time.Time) string {
+	ports, _ := nestedValue(obj, "spec", "ports").([]interface{})
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		port, _ := getNestedInt(p, "port")
+		s := strconv.Itoa(port)
+		if nodePort, ok := getNestedInt(p, "nodePort"); ok && nodePort > 0 {
+			s += ":" + strconv.Itoa(nodePort)
+		}
+		if protocol := nestedString(p, "protocol"); protocol != "" {
+			s += "/" + protocol
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ingressHosts shows the hosts an ingress routes.
+func ingressHosts(obj map[string]interface{}, _ time.Time) string {
+	rules, _ := nestedValue(obj, "spec", "rules").([]interface{})
+	hosts := make([]string, 0, len(rules))
+	for _, r := range rules {
+		host := nestedString(r, "host")
+		if host == "" {
+			host = "*"
+		}
+		hosts = append(hosts, host)
+	}
+	return strings.Join(hosts, ",")
+}
+
+// loadBalancerAddresses joins the IPs or host names of load balancer
+// ingress points.
+func loadBalancerAddresses(ingress interface{}) string {
+	points, _ := ingress.([]interface{})
+	addrs := make([]string, 0, len(points))
+	for _, p := range points {
+		if ip := nestedString(p, "ip"); ip != "" {
+			addrs = append(addrs, ip)
+		} else if host := nestedString(p, "hostname"); host != "" {
+			addrs = append(addrs, host)
+		}
+	}
+	return strings.Join(addrs, ",")
+}
+
+// nodeRoles shows the roles from a node's node-role.kubernetes.io labels.
+func nodeRoles(obj map[string]interface{}, _ time.Time) string {
+	var roles []string
+	for label := range nestedMap(obj, "metadata", "labels") {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ",")
+}
+
+// nodeAddress returns a node's first address of addrType.
+func nodeAddress(obj map[string]interface{}, addrType string) string {
+	addrs, _ := nestedValue(obj, "status", "addresses").([]interface{})
+	for _, a := range addrs {
+		if nestedString(a, "type") == addrType {
+			return nestedString(a, "address")
+		}
+	}
+	return ""
+}
+
+// conditionStatus returns the status of the condition of condType, or ""
+// if there is none.
+func conditionStatus(obj map[string]interface{}, condType string) string {
+	conditions, _ := nestedValue(obj, "status", "conditions").([]interface{})
+	for _, c := range conditions {
+		if nestedString(c, "type") == condType {
+			return nestedString(c, "status")
+		}
+	}
+	return ""
+}
+
+// joinContainers joins a field of the pod template's containers, e.g. their
+// images.
+func joinContainers(obj map[string]interface{}, field string) string {
+	containers, _ := nestedValue(obj, "spec", "template", "spec", "containers").([]interface{})
+	if len(containers) == 0 {
+		containers, _ = nestedValue(obj, "spec", "jobTemplate", "spec", "template", "spec", "containers").([]interface{})
+	}
+	values := make([]string, 0, len(containers))
+	for _, c := range containers {
+		if v := nestedString(c, field); v != "" {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// ageSince formats the time since an RFC 3339 timestamp like kubectl, e.g.
+// "3d" or "5h". It returns "" for a missing or invalid timestamp.
+func ageSince(timestamp string, now time.Time) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+	d := now.Sub(t)
+	switch {
+	case d < 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// nestedValue returns the value at keys in nested maps, or nil.
+func nestedValue(obj interface{}, keys ...string) interface{} {
+	current := obj
+	for _, key := range keys {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// nestedString returns the string at keys in nested maps, or "".
+func nestedString(obj interface{}, keys ...string) string {
+	s, _ := nestedValue(obj, keys...).(string)
+	return s
+}
+
+// nestedMap returns the map at keys in nested maps, or nil.
+func nestedMap(obj interface{}, keys ...string) map[string]interface{} {
+	m, _ := nestedValue(obj, keys...).(map[string]interface{})
+	return m
+}
+
+// joinMap joins a map as sorted "key=value" pairs.
+func joinMap(m map[string]interface{}) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// joinStrings joins a list of strings with commas.
+func joinStrings(list interface{}) string {
+	items, _ := list.([]interface{})
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return strings.Join(values, ",")
+}
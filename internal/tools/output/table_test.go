@@ -0,0 +1,276 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tableNow = time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func tablePod(namespace, name string, extra map[string]interface{}) map[string]interface{} {
+	pod := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         namespace,
+			"creationTimestamp": "2026-05-07T12:00:00Z",
+			"labels":            map[string]interface{}{"app": "web", "tier": "frontend"},
+		},
+		"spec": map[string]interface{}{
+			"nodeName":   "node-1",
+			"containers": []interface{}{map[string]interface{}{"name": "app"}, map[string]interface{}{"name": "sidecar"}},
+		},
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"podIP": "10.0.0.1",
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "app", "ready": true, "restartCount": int64(2)},
+				map[string]interface{}{"name": "sidecar", "ready": true, "restartCount": int64(1)},
+			},
+		},
+	}
+	for k, v := range extra {
+		pod[k] = v
+	}
+	return pod
+}
+
+func TestBuildTable_Pods(t *testing.T) {
+	crashing := tablePod("default", "web-1", map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"ready": false, "restartCount": int64(7), "state": map[string]interface{}{
+					"waiting": map[string]interface{}{"reason": "CrashLoopBackOff"},
+				}},
+			},
+		},
+	})
+
+	table := buildTable([]map[string]interface{}{tablePod("default", "web-0", nil), crashing}, false, tableNow)
+
+	assert.Equal(t, "Pod", table.Kind)
+	assert.Equal(t, "default", table.Namespace)
+	assert.Equal(t, []string{"NAME", "READY", "STATUS", "RESTARTS", "AGE", "IP", "NODE"}, table.Columns)
+	assert.Equal(t, [][]string{
+		{"web-0", "2/2", "Running", "3", "3d", "10.0.0.1", "node-1"},
+		{"web-1", "0/2", "CrashLoopBackOff", "7", "3d", "", "node-1"},
+	}, table.Rows)
+}
+
+func TestBuildTable_NamespacesAndLabels(t *testing.T) {
+	table := buildTable([]map[string]interface{}{
+		tablePod("default", "web-0", nil),
+		tablePod("staging", "web-0", nil),
+	}, true, tableNow)
+
+	assert.Empty(t, table.Namespace)
+	assert.Equal(t, []string{"NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE", "IP", "NODE", "LABELS"}, table.Columns)
+	assert.Equal(t, "staging", table.Rows[1][0])
+	assert.Equal(t, "app=web,tier=frontend", table.Rows[1][8])
+}
+
+func TestBuildTable_Kinds(t *testing.T) {
+	tests := []struct {
+		name        string
+		obj         map[string]interface{}
+		wantColumns []string
+		wantRow     []string
+	}{
+		{
+			name: "deployment",
+			obj: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"name": "web", "creationTimestamp": "2026-05-10T11:30:00Z"},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+					"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "nginx:1.27"},
+					}}},
+				},
+				"status": map[string]interface{}{"readyReplicas": int64(2), "updatedReplicas": int64(3), "availableReplicas": int64(2)},
+			},
+			wantColumns: []string{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE", "CONTAINERS", "IMAGES"},
+			wantRow:     []string{"web", "2/3", "3", "2", "30m", "app", "nginx:1.27"},
+		},
+		{
+			name: "service",
+			obj: map[string]interface{}{
+				"kind":     "Service",
+				"metadata": map[string]interface{}{"name": "web", "creationTimestamp": "2026-05-10T11:59:30Z"},
+				"spec": map[string]interface{}{
+					"type":      "LoadBalancer",
+					"clusterIP": "10.96.0.10",
+					"selector":  map[string]interface{}{"app": "web"},
+					"ports": []interface{}{
+						map[string]interface{}{"port": int64(80), "nodePort": int64(30080), "protocol": "TCP"},
+						map[string]interface{}{"port": int64(443), "protocol": "TCP"},
+					},
+				},
+				"status": map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{
+					map[string]interface{}{"hostname": "lb.example.com"},
+				}}},
+			},
+			wantColumns: []string{"NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORTS", "AGE", "SELECTOR"},
+			wantRow:     []string{"web", "LoadBalancer", "10.96.0.10", "lb.example.com", "80:30080/TCP,443/TCP", "30s", "app=web"},
+		},
+		{
+			name: "node",
+			obj: map[string]interface{}{
+				"kind": "Node",
+				"metadata": map[string]interface{}{"name": "node-1", "creationTimestamp": "2026-04-10T12:00:00Z", "labels": map[string]interface{}{
+					"node-role.kubernetes.io/control-plane": "",
+					"kubernetes.io/os":                      "linux",
+				}},
+				"spec": map[string]interface{}{"unschedulable": true},
+				"status": map[string]interface{}{
+					"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+					"addresses":  []interface{}{map[string]interface{}{"type": "InternalIP", "address": "192.168.1.10"}},
+					"nodeInfo":   map[string]interface{}{"kubeletVersion": "v1.33.1"},
+				},
+			},
+			wantColumns: []string{"NAME", "STATUS", "ROLES", "AGE", "VERSION", "INTERNAL-IP"},
+			wantRow:     []string{"node-1", "Ready,SchedulingDisabled", "control-plane", "30d", "v1.33.1", "192.168.1.10"},
+		},
+		{
+			name: "event",
+			obj: map[string]interface{}{
+				"kind":           "Event",
+				"metadata":       map[string]interface{}{"name": "web-0.17f"},
+				"type":           "Warning",
+				"reason":         "BackOff",
+				"message":        "Back-off restarting failed container",
+				"count":          int64(12),
+				"lastTimestamp":  "2026-05-10T11:55:00Z",
+				"involvedObject": map[string]interface{}{"kind": "Pod", "name": "web-0"},
+			},
+			wantColumns: []string{"LAST SEEN", "TYPE", "REASON", "OBJECT", "COUNT", "MESSAGE"},
+			wantRow:     []string{"5m", "Warning", "BackOff", "pod/web-0", "12", "Back-off restarting failed container"},
+		},
+		{
+			name: "custom resource",
+			obj: map[string]interface{}{
+				"kind":     "Certificate",
+				"metadata": map[string]interface{}{"name": "tls", "creationTimestamp": "2026-05-09T12:00:00Z"},
+				"status":   map[string]interface{}{"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}}},
+			},
+			wantColumns: []string{"NAME", "READY", "AGE"},
+			wantRow:     []string{"tls", "False", "24h"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := buildTable([]map[string]interface{}{tt.obj}, false, tableNow)
+			assert.Equal(t, tt.wantColumns, table.Columns)
+			require.Len(t, table.Rows, 1)
+			assert.Equal(t, tt.wantRow, table.Rows[0])
+		})
+	}
+}
+
+func TestBuildTable_MixedKinds(t *testing.T) {
+	table := buildTable([]map[string]interface{}{
+		tablePod("default", "web-0", nil),
+		{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": "cfg", "namespace": "default"}},
+	}, false, tableNow)
+
+	assert.Empty(t, table.Kind)
+	assert.Equal(t, []string{"KIND", "NAME", "STATUS", "AGE"}, table.Columns)
+	assert.Equal(t, []string{"ConfigMap", "cfg", "", ""}, table.Rows[1])
+}
+
+func TestBuildTable_Empty(t *testing.T) {
+	table := buildTable(nil, false, tableNow)
+
+	assert.Equal(t, []string{"NAME", "STATUS", "READY", "AGE"}, table.Columns)
+	assert.NotNil(t, table.Rows)
+	assert.Empty(t, table.Rows)
+}
+
+func TestObjectStatus(t *testing.T) {
+	crashing := tablePod("default", "web-1", map[string]interface{}{
+		"status": map[string]interface{}{
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"restartCount": int64(7), "state": map[string]interface{}{
+					"waiting": map[string]interface{}{"reason": "CrashLoopBackOff"},
+				}},
+			},
+		},
+	})
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want ObjectState
+	}{
+		{name: "running pod", obj: tablePod("default", "web-0", nil), want: ObjectState{Status: "Running", Ready: "2/2", Restarts: 3}},
+		{name: "crashing pod", obj: crashing, want: ObjectState{Status: "CrashLoopBackOff", Ready: "0/2", Restarts: 7}},
+		{name: "deployment", obj: map[string]interface{}{
+			"kind":   "Deployment",
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(1), "availableReplicas": int64(1)},
+		}, want: ObjectState{Status: "Partially Ready", Ready: "1/3"}},
+		{name: "daemonset", obj: map[string]interface{}{
+			"kind":   "DaemonSet",
+			"status": map[string]interface{}{"desiredNumberScheduled": int64(2), "numberReady": int64(2)},
+		}, want: ObjectState{Status: "Ready", Ready: "2/2"}},
+		{name: "ready condition", obj: map[string]interface{}{
+			"kind": "Certificate",
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			}},
+		}, want: ObjectState{Status: "NotReady"}},
+		{name: "no status", obj: map[string]interface{}{"kind": "ConfigMap"}, want: ObjectState{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ObjectStatus(tt.obj))
+		})
+	}
+}
+
+func TestPodFailing(t *testing.T) {
+	pod := func(phase, key, state, reason string) map[string]interface{} {
+		status := map[string]interface{}{"phase": phase}
+		if reason != "" {
+			status[key] = []interface{}{map[string]interface{}{"state": map[string]interface{}{
+				state: map[string]interface{}{"reason": reason},
+			}}}
+		}
+		return map[string]interface{}{"kind": "Pod", "status": status}
+	}
+	tests := []struct {
+		name    string
+		obj     map[string]interface{}
+		status  string
+		failing bool
+	}{
+		{name: "running", obj: pod("Running", "", "", ""), status: "Running"},
+		{name: "creating", obj: pod("Pending", "containerStatuses", "waiting", "ContainerCreating"), status: "ContainerCreating"},
+		{name: "initializing", obj: pod("Pending", "initContainerStatuses", "waiting", "PodInitializing"), status: "Init:PodInitializing"},
+		{name: "crash loop", obj: pod("Running", "containerStatuses", "waiting", "CrashLoopBackOff"), status: "CrashLoopBackOff", failing: true},
+		{name: "init error", obj: pod("Pending", "initContainerStatuses", "terminated", "Error"), status: "Init:Error", failing: true},
+		{name: "failed", obj: pod("Failed", "", "", ""), status: "Failed", failing: true},
+		{name: "succeeded", obj: pod("Succeeded", "", "", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, failing := PodFailing(tt.obj)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.failing, failing)
+		})
+	}
+}
+
+func TestAgeSince(t *testing.T) {
+	assert.Equal(t, "45s", ageSince("2026-05-10T11:59:15Z", tableNow))
+	assert.Equal(t, "47h", ageSince("2026-05-08T13:00:00Z", tableNow))
+	assert.Equal(t, "2d", ageSince("2026-05-08T12:00:00Z", tableNow))
+	assert.Equal(t, "0s", ageSince("2026-05-11T12:00:00Z", tableNow))
+	assert.Empty(t, ageSince("", tableNow))
+	assert.Empty(t, ageSince("yesterday", tableNow))
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultTokenWarningThreshold is the estimated token count of a response
+// above which a warning is added to it.
+const DefaultTokenWarningThreshold = 20000
+
+// TokenWarning returns the warning for a response of about tokens tokens,
+// or "" when it does not exceed threshold or threshold is 0. suggestions
+// are the arguments of the tool that make its responses smaller, such as
+// "summary=true"; without any the warning asks for a narrower request.
+func TokenWarning(tokens, threshold int, suggestions []string) string {
+	if threshold <= 0 || tokens <= threshold {
+		return ""
+	}
+	advice := "Narrow the request to reduce it."
+	if len(suggestions) > 0 {
+		advice = "To reduce it, use " + joinAlternatives(suggestions) + "."
+	}
+	return fmt.Sprintf("WARNING: this response is about %d tokens, above the warning threshold of %d. %s", tokens, threshold, advice)
+}
+
+// joinAlternatives joins items as "a, b or c".
+func joinAlternatives(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
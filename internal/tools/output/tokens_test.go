@@ -0,0 +1,22 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenWarning(t *testing.T) {
+	assert.Empty(t, TokenWarning(100, 0, nil), "a threshold of 0 disables the warning")
+	assert.Empty(t, TokenWarning(100, 100, nil))
+
+	assert.Equal(t,
+		"WARNING: this response is about 101 tokens, above the warning threshold of 100. Narrow the request to reduce it.",
+		TokenWarning(101, 100, nil))
+	assert.Equal(t,
+		"WARNING: this response is about 101 tokens, above the warning threshold of 100. To reduce it, use summary=true.",
+		TokenWarning(101, 100, []string{"summary=true"}))
+	assert.Equal(t,
+		"WARNING: this response is about 101 tokens, above the warning threshold of 100. To reduce it, use summary=true, a smaller limit or labelSelector.",
+		TokenWarning(101, 100, []string{"summary=true", "a smaller limit", "labelSelector"}))
+}
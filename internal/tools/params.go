@@ -11,6 +11,8 @@ import (
 // based on the server's operating mode. This ensures backwards compatibility:
 //   - cluster parameter is only added when federation is enabled
 //   - kubeContext parameter is only added when NOT in in-cluster mode
+//   - impersonateUser/impersonateGroups are only added when AllowImpersonateAs
+//     is enabled and federation is available
 //
 // Usage in tool registration:
 //
@@ -30,11 +32,33 @@ func AddClusterContextParams(sc *server.ServerContext) []mcp.ToolOption {
 		))
 	}
 
-	// Add kubeContext parameter only when NOT in in-cluster mode
+	// Add kubeContext parameter only when NOT in in-cluster mode. An
+	// allowlist of contexts is offered as the parameter's values.
 	if !sc.InClusterMode() {
-		opts = append(opts, mcp.WithString("kubeContext",
-			mcp.Description("Kubernetes context to use (optional, uses current context if not specified)"),
-		))
+		if cfg := sc.Config(); cfg != nil && len(cfg.KubeconfigContexts) > 0 {
+			opts = append(opts, mcp.WithString("kubeContext",
+				mcp.Description("Kubernetes context (cluster) to use (optional, uses current context if not specified)"),
+				mcp.Enum(cfg.KubeconfigContexts...),
+			))
+		} else {
+			opts = append(opts, mcp.WithString("kubeContext",
+				mcp.Description("Kubernetes context to use (optional, uses current context if not specified)"),
+			))
+		}
+	}
+
+	// Add the impersonation override only when the operator enabled it and
+	// federation (which provides the impersonating clients) is available.
+	if cfg := sc.Config(); cfg != nil && cfg.AllowImpersonateAs && sc.FederationEnabled() {
+		opts = append(opts,
+			mcp.WithString(ParamImpersonateUser,
+				mcp.Description("Run this tool as another user (admin debugging, e.g. \"what would jane@example.com see?\"). Requires impersonate RBAC for the user and every group; the call is audited."),
+			),
+			mcp.WithArray(ParamImpersonateGroups,
+				mcp.Description("Groups to impersonate together with impersonateUser"),
+				mcp.WithStringItems(),
+			),
+		)
 	}
 
 	return opts
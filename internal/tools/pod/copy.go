@@ -0,0 +1,468 @@
+package pod
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// copyTarOverhead is the room left for tar headers and padding on top of
+	// the file size limit when reading an archive from a pod.
+	copyTarOverhead = 64 * 1024
+
+	// copyTransportInline returns the content in the JSON response.
+	copyTransportInline = "inline"
+
+	// copyTransportResource returns the content as an embedded MCP resource.
+	copyTransportResource = "resource"
+
+	encodingUTF8   = "utf-8"
+	encodingBase64 = "base64"
+)
+
+// errCopyLimit is returned by limitedBuffer once the limit is exceeded.
+var errCopyLimit = errors.New("copy size limit exceeded")
+
+// CopyFromPodResponse is the response of the cp_from_pod tool.
+type CopyFromPodResponse struct {
+	Namespace     string `json:"namespace"`
+	PodName       string `json:"podName"`
+	ContainerName string `json:"containerName,omitempty"`
+	Path          string `json:"path"`
+
+	// Kind is "file" for a single regular file, or "archive" when the path
+	// is a directory and the content is a tar archive of it.
+	Kind     string `json:"kind"`
+	Size     int64  `json:"size"`
+	Files    int    `json:"files,omitempty"`
+	MIMEType string `json:"mimeType"`
+
+	// Encoding is utf-8 when Content is the file's text, or base64.
+	Encoding string `json:"encoding,omitempty"`
+	Content  string `json:"content,omitempty"`
+
+	// ResourceURI names the embedded resource holding the content when the
+	// resource transport is used.
+	ResourceURI string `json:"resourceURI,omitempty"`
+}
+
+// CopyToPodResponse is the response of the cp_to_pod tool.
+type CopyToPodResponse struct {
+	Success       bool   `json:"success"`
+	Message       string `json:"message"`
+	Namespace     string `json:"namespace"`
+	PodName       string `json:"podName"`
+	ContainerName string `json:"containerName,omitempty"`
+	Path          string `json:"path"`
+	Size          int64  `json:"size"`
+	Mode          string `json:"mode"`
+	DryRun        bool   `json:"dryRun,omitempty"`
+}
+
+// copyTarget holds the arguments shared by cp_from_pod and cp_to_pod.
+type copyTarget struct {
+	clusterName   string
+	kubeContext   string
+	namespace     string
+	podName       string
+	containerName string
+	path          string
+}
+
+// limitedBuffer is an io.Writer that fails once more than limit bytes are
+// written to it, which ends the exec stream.
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int64
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if int64(b.buf.Len()+len(p)) > b.limit {
+		return 0, errCopyLimit
+	}
+	return b.buf.Write(p)
+}
+
+// handleCopyFromPod reads a file or directory from a pod container by
+// streaming a tar archive of it through exec, like kubectl cp.
+func handleCopyFromPod(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "cp"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	config := sc.Config()
+	target, errMsg := parseCopyTarget(args, config)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	maxBytes, errMsg := copyMaxBytes(args, config)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	transport := copyTransportInline
+	if v, ok := args["transport"].(string); ok && v != "" {
+		if v != copyTransportInline && v != copyTransportResource {
+			return toolerrors.InvalidArgumentf("transport must be %q or %q", copyTransportInline, copyTransportResource).Result(), nil
+		}
+		transport = v
+	}
+
+	// Get the appropriate k8s client (local or federated)
+	client, toolErr := tools.GetClusterClient(ctx, sc, target.clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	dir, base := path.Split(target.path)
+	stdout := &limitedBuffer{limit: maxBytes + copyTarOverhead}
+	var stderr bytes.Buffer
+	_, err := client.K8s().Exec(ctx, target.kubeContext, target.namespace, target.podName, target.containerName,
+		[]string{"tar", "cf", "-", "-C", dir, base},
+		k8s.ExecOptions{Stdout: stdout, Stderr: &stderr})
+	if errors.Is(err, errCopyLimit) {
+		return toolerrors.InvalidArgumentf("%s is larger than the copy limit of %d bytes", target.path, maxBytes).Result(), nil
+	}
+	if err != nil {
+		return copyExecError("Failed to copy from pod", err, stderr.String(), client.User()).Result(), nil
+	}
+	if stdout.buf.Len() == 0 {
+		return copyExecError("Failed to copy from pod", errors.New("tar produced no output"), stderr.String(), client.User()).Result(), nil
+	}
+
+	response := CopyFromPodResponse{
+		Namespace:     target.namespace,
+		PodName:       target.podName,
+		ContainerName: target.containerName,
+		Path:          target.path,
+	}
+	content, err := readCopyArchive(stdout.buf.Bytes(), maxBytes, &response)
+	if err != nil {
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to read %s: %v", target.path, err)).Result(), nil
+	}
+
+	if transport == copyTransportResource {
+		response.ResourceURI = fmt.Sprintf("k8s://%s/pods/%s%s", target.namespace, target.podName, target.path)
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+		}
+		return mcp.NewToolResultResource(string(jsonData), mcp.BlobResourceContents{
+			URI:      response.ResourceURI,
+			MIMEType: response.MIMEType,
+			Blob:     base64.StdEncoding.EncodeToString(content),
+		}), nil
+	}
+
+	if response.Kind == "file" && utf8.Valid(content) && !bytes.ContainsRune(content, 0) {
+		response.Encoding = encodingUTF8
+		response.Content = string(content)
+	} else {
+		response.Encoding = encodingBase64
+		response.Content = base64.StdEncoding.EncodeToString(content)
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// readCopyArchive checks the tar archive read from a pod. A single regular
+// file is returned as its content; a directory is returned as the archive
+// itself. Either way the files in it may hold at most maxBytes.
+func readCopyArchive(archive []byte, maxBytes int64, response *CopyFromPodResponse) ([]byte, error) {
+	tr := tar.NewReader(bytes.NewReader(archive))
+	var first *tar.Header
+	var firstContent []byte
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid tar stream: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			response.Files++
+			total += hdr.Size
+			if total > maxBytes {
+				return nil, fmt.Errorf("larger than the copy limit of %d bytes", maxBytes)
+			}
+		}
+		if first == nil {
+			first = hdr
+			if hdr.Typeflag == tar.TypeReg {
+				if firstContent, err = io.ReadAll(tr); err != nil {
+					return nil, fmt.Errorf("invalid tar stream: %w", err)
+				}
+			}
+		}
+	}
+	if first == nil {
+		return nil, errors.New("the archive is empty")
+	}
+
+	switch first.Typeflag {
+	case tar.TypeReg:
+		response.Kind = "file"
+		response.Files = 0
+		response.Size = int64(len(firstContent))
+		response.MIMEType = "application/octet-stream"
+		if utf8.Valid(firstContent) && !bytes.ContainsRune(firstContent, 0) {
+			response.MIMEType = "text/plain"
+		}
+		return firstContent, nil
+	case tar.TypeDir:
+		response.Kind = "archive"
+		response.Size = total
+		response.MIMEType = "application/x-tar"
+		return archive, nil
+	case tar.TypeSymlink:
+		return nil, fmt.Errorf("it is a symbolic link to %s; copy the target instead", first.Linkname)
+	default:
+		return nil, errors.New("it is not a regular file or directory")
+	}
+}
+
+// handleCopyToPod writes a file into a pod container by streaming a
+// one-entry tar archive to tar through exec, like kubectl cp.
+func handleCopyToPod(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "cp"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	config := sc.Config()
+	target, errMsg := parseCopyTarget(args, config)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	maxBytes, errMsg := copyMaxBytes(args, config)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	if isCopyDir(target.path, copyPaths(config)) {
+		return toolerrors.InvalidArgumentf("path %s is an allowed directory; give the path of the file to write", target.path).Result(), nil
+	}
+
+	content, errMsg := decodeCopyContent(args, maxBytes)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	mode := int64(0o644)
+	if v, ok := args["mode"].(string); ok && v != "" {
+		parsed, err := strconv.ParseInt(v, 8, 32)
+		if err != nil || parsed < 0 || parsed > 0o777 {
+			return toolerrors.InvalidArgumentf("mode %q must be an octal permission such as 0644", v).Result(), nil
+		}
+		mode = parsed
+	}
+
+	response := CopyToPodResponse{
+		Success:       true,
+		Namespace:     target.namespace,
+		PodName:       target.podName,
+		ContainerName: target.containerName,
+		Path:          target.path,
+		Size:          int64(len(content)),
+		Mode:          fmt.Sprintf("%04o", mode),
+		DryRun:        tools.IsDryRun(ctx, sc),
+	}
+
+	if response.DryRun {
+		response.Message = fmt.Sprintf("Dry run: %d bytes would be written to %s in pod %s/%s", response.Size, target.path, target.namespace, target.podName)
+	} else {
+		// Get the appropriate k8s client (local or federated)
+		client, toolErr := tools.GetClusterClient(ctx, sc, target.clusterName)
+		if toolErr != nil {
+			return toolErr.Result(), nil
+		}
+
+		dir, base := path.Split(target.path)
+		archive, err := buildCopyArchive(base, content, mode)
+		if err != nil {
+			return toolerrors.Internalf("Failed to build archive: %v", err).Result(), nil
+		}
+		var stdout, stderr bytes.Buffer
+		_, err = client.K8s().Exec(ctx, target.kubeContext, target.namespace, target.podName, target.containerName,
+			[]string{"tar", "xf", "-", "-C", dir},
+			k8s.ExecOptions{Stdin: bytes.NewReader(archive), Stdout: &stdout, Stderr: &stderr})
+		if err != nil {
+			return copyExecError("Failed to copy to pod", err, stderr.String(), client.User()).Result(), nil
+		}
+		response.Message = fmt.Sprintf("Wrote %d bytes to %s in pod %s/%s", response.Size, target.path, target.namespace, target.podName)
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseCopyTarget validates the pod and path arguments against the copy
+// path allowlist.
+func parseCopyTarget(args map[string]interface{}, config *server.Config) (copyTarget, string) {
+	target := copyTarget{clusterName: tools.ExtractClusterParam(args)}
+	target.kubeContext, _ = args["kubeContext"].(string)
+	target.containerName, _ = args["containerName"].(string)
+
+	var ok bool
+	if target.namespace, ok = args["namespace"].(string); !ok || target.namespace == "" {
+		return target, "namespace is required"
+	}
+	if target.podName, ok = args["podName"].(string); !ok || target.podName == "" {
+		return target, "podName is required"
+	}
+	raw, ok := args["path"].(string)
+	if !ok || raw == "" {
+		return target, "path is required"
+	}
+	if !path.IsAbs(raw) {
+		return target, fmt.Sprintf("path %q must be absolute", raw)
+	}
+	target.path = path.Clean(raw)
+	if target.path == "/" {
+		return target, "path must not be the root directory"
+	}
+
+	allowed := copyPaths(config)
+	if !copyPathAllowed(target.path, allowed) {
+		return target, fmt.Sprintf("path %s is outside the allowed copy paths (allowed: %s)", target.path, strings.Join(allowed, ", "))
+	}
+	return target, ""
+}
+
+// copyPaths returns the configured copy directories, or the defaults.
+func copyPaths(config *server.Config) []string {
+	if len(config.CopyPaths) == 0 {
+		return server.DefaultCopyPaths
+	}
+	return config.CopyPaths
+}
+
+// copyPathAllowed reports whether p is one of the allowed directories or
+// lies beneath one. Symbolic links in the container are not resolved, so
+// the allowlist limits what is asked for rather than sandboxing the copy.
+func copyPathAllowed(p string, allowed []string) bool {
+	for _, dir := range allowed {
+		dir = path.Clean(dir)
+		if dir == "/" || p == dir || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isCopyDir reports whether p is itself one of the allowed directories.
+func isCopyDir(p string, allowed []string) bool {
+	for _, dir := range allowed {
+		if p == path.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyMaxBytes returns the size limit for a copy: the maxBytes argument if
+// given, which may only lower the configured limit.
+func copyMaxBytes(args map[string]interface{}, config *server.Config) (int64, string) {
+	limit := config.CopyMaxBytes
+	if limit <= 0 {
+		limit = server.DefaultCopyMaxBytes
+	}
+	if v, ok := args["maxBytes"].(float64); ok {
+		if v < 1 || int64(v) > limit {
+			return 0, fmt.Sprintf("maxBytes must be between 1 and %d", limit)
+		}
+		limit = int64(v)
+	}
+	return limit, ""
+}
+
+// decodeCopyContent decodes the content argument of cp_to_pod.
+func decodeCopyContent(args map[string]interface{}, maxBytes int64) ([]byte, string) {
+	raw, ok := args["content"].(string)
+	if !ok {
+		return nil, "content is required"
+	}
+	encoding, _ := args["encoding"].(string)
+	var content []byte
+	switch encoding {
+	case "", encodingUTF8:
+		content = []byte(raw)
+	case encodingBase64:
+		if int64(base64.StdEncoding.DecodedLen(len(raw))) > maxBytes+2 {
+			return nil, fmt.Sprintf("content is larger than the copy limit of %d bytes", maxBytes)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Sprintf("content is not valid base64: %v", err)
+		}
+		content = decoded
+	default:
+		return nil, fmt.Sprintf("encoding must be %q or %q", encodingUTF8, encodingBase64)
+	}
+	if int64(len(content)) > maxBytes {
+		return nil, fmt.Sprintf("content is larger than the copy limit of %d bytes", maxBytes)
+	}
+	return content, ""
+}
+
+// buildCopyArchive returns a tar archive holding one regular file.
+func buildCopyArchive(name string, content []byte, mode int64) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     mode,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyExecError formats a failed tar exec, adding what tar wrote to stderr
+// and a hint when the container has no tar.
+func copyExecError(prefix string, err error, stderr string, user *federation.UserInfo) *toolerrors.Error {
+	msg := tools.FormatK8sError(prefix, err, user)
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	if strings.Contains(err.Error()+stderr, "executable file not found") || strings.Contains(stderr, "tar: not found") {
+		msg += ". Copying needs tar in the container; for images without it, add a debug container with debug_pod and copy through it"
+	}
+	return toolerrors.FromError(err, msg)
+}
@@ -0,0 +1,364 @@
+package pod
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// copyMock answers tar execs: it writes archive to stdout for tar cf, and
+// records the archive read from stdin for tar xf.
+type copyMock struct {
+	*testdata.MockK8sClient
+
+	archive []byte
+	stderr  string
+	execErr error
+
+	command  []string
+	received []byte
+}
+
+func (m *copyMock) Exec(_ context.Context, _, _, _, _ string, command []string, opts k8s.ExecOptions) (*k8s.ExecResult, error) {
+	m.command = command
+	if m.stderr != "" && opts.Stderr != nil {
+		_, _ = io.WriteString(opts.Stderr, m.stderr)
+	}
+	if m.execErr != nil {
+		return nil, m.execErr
+	}
+	if opts.Stdin != nil {
+		data, err := io.ReadAll(opts.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		m.received = data
+	}
+	if opts.Stdout != nil && m.archive != nil {
+		if _, err := opts.Stdout.Write(m.archive); err != nil {
+			return nil, err
+		}
+	}
+	return &k8s.ExecResult{}, nil
+}
+
+// tarEntry is one entry of a test archive; an empty body with dir set makes
+// a directory.
+type tarEntry struct {
+	name string
+	body string
+	dir  bool
+}
+
+func makeTar(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(e.body))}
+		if e.dir {
+			hdr = &tar.Header{Name: e.name + "/", Typeflag: tar.TypeDir, Mode: 0o755}
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func callCopy(t *testing.T, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), mock *copyMock, args map[string]interface{}, opts ...server.Option) *mcp.CallToolResult {
+	t.Helper()
+	baseOpts := []server.Option{
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	}
+	sc, err := server.NewServerContext(context.Background(), append(baseOpts, opts...)...)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request, sc)
+	require.NoError(t, err)
+	return result
+}
+
+func TestCopyFromPod_File(t *testing.T) {
+	mock := &copyMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		archive:       makeTar(t, tarEntry{name: "app.conf", body: "listen 8080\n"}),
+	}
+	result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-7d9f",
+		"path":      "/tmp/conf/../app.conf",
+	})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	assert.Equal(t, []string{"tar", "cf", "-", "-C", "/tmp/", "app.conf"}, mock.command)
+
+	var out CopyFromPodResponse
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.Equal(t, "/tmp/app.conf", out.Path)
+	assert.Equal(t, "file", out.Kind)
+	assert.Equal(t, "text/plain", out.MIMEType)
+	assert.Equal(t, encodingUTF8, out.Encoding)
+	assert.Equal(t, "listen 8080\n", out.Content)
+	assert.EqualValues(t, 12, out.Size)
+}
+
+func TestCopyFromPod_BinaryAndDirectory(t *testing.T) {
+	t.Run("binary file is base64", func(t *testing.T) {
+		mock := &copyMock{
+			MockK8sClient: &testdata.MockK8sClient{},
+			archive:       makeTar(t, tarEntry{name: "heap.bin", body: "\x00\x01\xff"}),
+		}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/heap.bin"})
+		require.False(t, result.IsError, mcptest.ResultText(result))
+
+		var out CopyFromPodResponse
+		require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+		assert.Equal(t, encodingBase64, out.Encoding)
+		assert.Equal(t, "application/octet-stream", out.MIMEType)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("\x00\x01\xff")), out.Content)
+	})
+
+	t.Run("directory is a tar archive", func(t *testing.T) {
+		archive := makeTar(t, tarEntry{name: "dumps", dir: true}, tarEntry{name: "dumps/a.txt", body: "a"}, tarEntry{name: "dumps/b.txt", body: "bb"})
+		mock := &copyMock{MockK8sClient: &testdata.MockK8sClient{}, archive: archive}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/dumps"})
+		require.False(t, result.IsError, mcptest.ResultText(result))
+
+		var out CopyFromPodResponse
+		require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+		assert.Equal(t, "archive", out.Kind)
+		assert.Equal(t, 2, out.Files)
+		assert.EqualValues(t, 3, out.Size)
+		assert.Equal(t, encodingBase64, out.Encoding)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(archive), out.Content)
+	})
+}
+
+func TestCopyFromPod_ResourceTransport(t *testing.T) {
+	mock := &copyMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		archive:       makeTar(t, tarEntry{name: "app.log", body: "started\n"}),
+	}
+	result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-7d9f",
+		"path":      "/tmp/app.log",
+		"transport": "resource",
+	})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	require.Len(t, result.Content, 2)
+
+	var out CopyFromPodResponse
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.Empty(t, out.Content, "the content is only in the resource")
+	assert.Equal(t, "k8s://shop/pods/api-7d9f/tmp/app.log", out.ResourceURI)
+
+	resource, ok := result.Content[1].(mcp.EmbeddedResource)
+	require.True(t, ok)
+	blob, ok := resource.Resource.(mcp.BlobResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, out.ResourceURI, blob.URI)
+	assert.Equal(t, "text/plain", blob.MIMEType)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("started\n")), blob.Blob)
+}
+
+func TestCopyFromPod_Limits(t *testing.T) {
+	t.Run("file over maxBytes", func(t *testing.T) {
+		mock := &copyMock{
+			MockK8sClient: &testdata.MockK8sClient{},
+			archive:       makeTar(t, tarEntry{name: "big.txt", body: "0123456789"}),
+		}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/big.txt", "maxBytes": float64(5)})
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "larger than the copy limit of 5 bytes")
+	})
+
+	t.Run("stream over the limit is cut off", func(t *testing.T) {
+		mock := &copyMock{
+			MockK8sClient: &testdata.MockK8sClient{},
+			archive:       makeTar(t, tarEntry{name: "big.txt", body: string(make([]byte, 2*copyTarOverhead))}),
+		}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/big.txt"},
+			server.WithCopyLimits(nil, 1024))
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "larger than the copy limit of 1024 bytes")
+	})
+
+	t.Run("maxBytes cannot raise the server limit", func(t *testing.T) {
+		result := callCopy(t, handleCopyFromPod, &copyMock{MockK8sClient: &testdata.MockK8sClient{}},
+			map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/a", "maxBytes": float64(server.DefaultCopyMaxBytes + 1)})
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "maxBytes must be between 1 and")
+	})
+}
+
+func TestCopyPathAllowlist(t *testing.T) {
+	paths := server.WithCopyLimits([]string{"/tmp", "/var/log/app/"}, 0)
+	tests := []struct {
+		path    string
+		wantErr string
+	}{
+		{path: "/tmp/a.txt"},
+		{path: "/var/log/app/current.log"},
+		{path: "/tmp/../etc/passwd", wantErr: "outside the allowed copy paths"},
+		{path: "/tmpfoo/a.txt", wantErr: "outside the allowed copy paths"},
+		{path: "/var/log/application.log", wantErr: "outside the allowed copy paths"},
+		{path: "tmp/a.txt", wantErr: "must be absolute"},
+		{path: "/", wantErr: "must not be the root directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			mock := &copyMock{
+				MockK8sClient: &testdata.MockK8sClient{},
+				archive:       makeTar(t, tarEntry{name: "a", body: "x"}),
+			}
+			result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": tt.path}, paths)
+			if tt.wantErr == "" {
+				assert.False(t, result.IsError, mcptest.ResultText(result))
+				return
+			}
+			require.True(t, result.IsError)
+			assert.Contains(t, mcptest.ResultText(result), tt.wantErr)
+			assert.Nil(t, mock.command, "nothing is executed for a refused path")
+		})
+	}
+}
+
+func TestCopyFromPod_ExecErrors(t *testing.T) {
+	t.Run("missing tar", func(t *testing.T) {
+		mock := &copyMock{
+			MockK8sClient: &testdata.MockK8sClient{},
+			execErr:       errors.New(`exec: "tar": executable file not found in $PATH`),
+		}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/a"})
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "needs tar in the container")
+		assert.Contains(t, mcptest.ResultText(result), "debug_pod")
+	})
+
+	t.Run("stderr is reported", func(t *testing.T) {
+		mock := &copyMock{
+			MockK8sClient: &testdata.MockK8sClient{},
+			stderr:        "tar: missing.txt: No such file or directory\n",
+			execErr:       errors.New("command terminated with exit code 2"),
+		}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/missing.txt"})
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "No such file or directory")
+	})
+
+	t.Run("symbolic link", func(t *testing.T) {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "current", Typeflag: tar.TypeSymlink, Linkname: "/etc/shadow"}))
+		require.NoError(t, tw.Close())
+
+		mock := &copyMock{MockK8sClient: &testdata.MockK8sClient{}, archive: buf.Bytes()}
+		result := callCopy(t, handleCopyFromPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/current"})
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "symbolic link to /etc/shadow")
+	})
+}
+
+func TestCopyToPod(t *testing.T) {
+	mock := &copyMock{MockK8sClient: &testdata.MockK8sClient{}}
+	result := callCopy(t, handleCopyToPod, mock, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-7d9f",
+		"path":      "/tmp/scripts/check.sh",
+		"content":   base64.StdEncoding.EncodeToString([]byte("#!/bin/sh\necho ok\n")),
+		"encoding":  "base64",
+		"mode":      "0755",
+	})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	assert.Equal(t, []string{"tar", "xf", "-", "-C", "/tmp/scripts/"}, mock.command)
+
+	tr := tar.NewReader(bytes.NewReader(mock.received))
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "check.sh", hdr.Name)
+	assert.EqualValues(t, 0o755, hdr.Mode)
+	body, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho ok\n", string(body))
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err, "the archive holds one file")
+
+	var out CopyToPodResponse
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	assert.True(t, out.Success)
+	assert.EqualValues(t, 18, out.Size)
+	assert.Equal(t, "0755", out.Mode)
+}
+
+func TestCopyToPod_Errors(t *testing.T) {
+	t.Run("blocked in non-destructive mode", func(t *testing.T) {
+		mock := &copyMock{MockK8sClient: &testdata.MockK8sClient{}}
+		result := callCopy(t, handleCopyToPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/a", "content": "x"},
+			server.WithNonDestructiveMode(true))
+		require.True(t, result.IsError)
+		assert.Contains(t, mcptest.ResultText(result), "not allowed in non-destructive mode")
+		assert.Nil(t, mock.command)
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		mock := &copyMock{MockK8sClient: &testdata.MockK8sClient{}}
+		result := callCopy(t, handleCopyToPod, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/a", "content": "x"},
+			server.WithDryRun(true))
+		require.False(t, result.IsError, mcptest.ResultText(result))
+		assert.Contains(t, mcptest.ResultText(result), "Dry run")
+		assert.Nil(t, mock.command)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		base := map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "path": "/tmp/a", "content": "x"}
+		for _, tc := range []struct {
+			override map[string]interface{}
+			want     string
+		}{
+			{map[string]interface{}{"path": "/tmp"}, "is an allowed directory"},
+			{map[string]interface{}{"path": "/etc/a"}, "outside the allowed copy paths"},
+			{map[string]interface{}{"content": nil}, "content is required"},
+			{map[string]interface{}{"encoding": "base64", "content": "not base64!"}, "not valid base64"},
+			{map[string]interface{}{"encoding": "hex"}, "encoding must be"},
+			{map[string]interface{}{"mode": "rwx"}, "must be an octal permission"},
+			{map[string]interface{}{"content": "0123456789", "maxBytes": float64(4)}, "larger than the copy limit of 4 bytes"},
+		} {
+			args := map[string]interface{}{}
+			for k, v := range base {
+				args[k] = v
+			}
+			for k, v := range tc.override {
+				if v == nil {
+					delete(args, k)
+				} else {
+					args[k] = v
+				}
+			}
+			mock := &copyMock{MockK8sClient: &testdata.MockK8sClient{}}
+			result := callCopy(t, handleCopyToPod, mock, args)
+			require.True(t, result.IsError, tc.want)
+			assert.Contains(t, mcptest.ResultText(result), tc.want)
+			assert.Nil(t, mock.command)
+		}
+	})
+}
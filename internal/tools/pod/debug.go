@@ -0,0 +1,296 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// defaultDebugTTLSeconds is how long the default debug command keeps the
+	// ephemeral container alive. Ephemeral containers cannot be removed, so
+	// the container ends itself.
+	defaultDebugTTLSeconds = 3600
+
+	// maxDebugTTLSeconds caps the ttlSeconds argument.
+	maxDebugTTLSeconds = 8 * 3600
+
+	// defaultDebugWaitSeconds is how long debug_pod waits for the container
+	// to start.
+	defaultDebugWaitSeconds = 30
+
+	// maxDebugWaitSeconds caps the waitSeconds argument.
+	maxDebugWaitSeconds = 120
+)
+
+// debugPollInterval is how often debug_pod checks the container's state.
+var debugPollInterval = time.Second
+
+// DebugPodResponse is the response of the debug_pod tool.
+type DebugPodResponse struct {
+	Success         bool     `json:"success"`
+	Message         string   `json:"message"`
+	Namespace       string   `json:"namespace"`
+	PodName         string   `json:"podName"`
+	ContainerName   string   `json:"containerName"`
+	Image           string   `json:"image"`
+	TargetContainer string   `json:"targetContainer,omitempty"`
+	Command         []string `json:"command"`
+	DryRun          bool     `json:"dryRun,omitempty"`
+
+	// State is the container's state when debug_pod returned: running,
+	// waiting or terminated.
+	State   string `json:"state,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Details string `json:"details,omitempty"`
+
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// debugRequest holds the validated arguments of a debug_pod call.
+type debugRequest struct {
+	clusterName     string
+	kubeContext     string
+	namespace       string
+	podName         string
+	image           string
+	targetContainer string
+	command         []string
+	wait            time.Duration
+}
+
+// handleDebugPod adds an ephemeral debug container to a running pod, like
+// kubectl debug, so pods whose images have no shell can be inspected with exec.
+func handleDebugPod(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "debug"); result != nil {
+		return result, nil
+	}
+
+	req, errMsg := parseDebugRequest(request.GetArguments(), sc.Config())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	// Get the appropriate k8s client (local or federated)
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	container := corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     "debugger-" + utilrand.String(5),
+			Image:                    req.image,
+			Command:                  req.command,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: req.targetContainer,
+	}
+
+	if _, err := k8sClient.AddEphemeralContainer(ctx, req.kubeContext, req.namespace, req.podName, container); err != nil {
+		return tools.K8sError("Failed to add debug container", err, client.User()).Result(), nil
+	}
+
+	response := DebugPodResponse{
+		Success:         true,
+		Namespace:       req.namespace,
+		PodName:         req.podName,
+		ContainerName:   container.Name,
+		Image:           req.image,
+		TargetContainer: req.targetContainer,
+		Command:         req.command,
+		DryRun:          tools.IsDryRun(ctx, sc),
+	}
+	if response.DryRun {
+		response.Message = fmt.Sprintf("Dry run: debug container %s would be added to pod %s/%s", container.Name, req.namespace, req.podName)
+	} else {
+		status := waitForDebugContainer(ctx, k8sClient, req, container.Name)
+		describeDebugState(&response, status)
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseDebugRequest validates the debug_pod arguments against the image
+// allowlist.
+func parseDebugRequest(args map[string]interface{}, config *server.Config) (debugRequest, string) {
+	req := debugRequest{
+		clusterName: tools.ExtractClusterParam(args),
+		wait:        defaultDebugWaitSeconds * time.Second,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+
+	var ok bool
+	if req.namespace, ok = args["namespace"].(string); !ok || req.namespace == "" {
+		return req, "namespace is required"
+	}
+	if req.podName, ok = args["podName"].(string); !ok || req.podName == "" {
+		return req, "podName is required"
+	}
+	req.targetContainer, _ = args["targetContainer"].(string)
+
+	allowed := config.DebugImages
+	if len(allowed) == 0 {
+		allowed = []string{server.DefaultDebugImage}
+	}
+	req.image, _ = args["image"].(string)
+	if req.image == "" {
+		if strings.HasSuffix(allowed[0], "*") {
+			return req, "image is required: the configured debug images are patterns: " + strings.Join(allowed, ", ")
+		}
+		req.image = allowed[0]
+	} else if !debugImageAllowed(req.image, allowed) {
+		return req, fmt.Sprintf("image %q is not an allowed debug image (allowed: %s)", req.image, strings.Join(allowed, ", "))
+	}
+
+	ttl := int64(defaultDebugTTLSeconds)
+	if v, ok := args["ttlSeconds"].(float64); ok {
+		ttl = int64(v)
+		if ttl < 1 || ttl > maxDebugTTLSeconds {
+			return req, fmt.Sprintf("ttlSeconds must be between 1 and %d", maxDebugTTLSeconds)
+		}
+	}
+	if v, ok := args["waitSeconds"].(float64); ok {
+		if v < 0 || v > maxDebugWaitSeconds {
+			return req, fmt.Sprintf("waitSeconds must be between 0 and %d", maxDebugWaitSeconds)
+		}
+		req.wait = time.Duration(v) * time.Second
+	}
+
+	if raw, ok := args["command"]; ok && raw != nil {
+		items, ok := raw.([]interface{})
+		if !ok {
+			return req, "command must be an array of strings"
+		}
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return req, "command must be an array of strings"
+			}
+			req.command = append(req.command, s)
+		}
+	}
+	if len(req.command) == 0 {
+		// Keep the container alive for exec, then let it end by itself.
+		req.command = []string{"sleep", fmt.Sprintf("%d", ttl)}
+	}
+	return req, ""
+}
+
+// debugImageAllowed reports whether image matches an allowlist entry. An
+// entry ending in "*" matches by prefix.
+func debugImageAllowed(image string, allowed []string) bool {
+	for _, entry := range allowed {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(image, prefix) {
+				return true
+			}
+		} else if image == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForDebugContainer polls the pod until the debug container has left
+// the waiting state for good, or the wait ends. It returns the last status
+// seen, or nil if none was reported.
+func waitForDebugContainer(ctx context.Context, client k8s.Client, req debugRequest, name string) *corev1.ContainerStatus {
+	waitCtx, cancel := context.WithTimeout(ctx, req.wait)
+	defer cancel()
+
+	ticker := time.NewTicker(debugPollInterval)
+	defer ticker.Stop()
+	var last *corev1.ContainerStatus
+	for {
+		resp, err := client.Get(waitCtx, req.kubeContext, req.namespace, "pods", "", req.podName)
+		if err == nil {
+			pod := &corev1.Pod{}
+			if err := fromObject(resp.Resource, pod); err == nil {
+				for i := range pod.Status.EphemeralContainerStatuses {
+					if status := &pod.Status.EphemeralContainerStatuses[i]; status.Name == name {
+						last = status
+					}
+				}
+			}
+		}
+		if last != nil && debugContainerSettled(last) {
+			return last
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return last
+		case <-ticker.C:
+		}
+	}
+}
+
+// debugContainerSettled reports whether a container is running, has ended,
+// or is stuck waiting for a reason that will not clear by itself.
+func debugContainerSettled(status *corev1.ContainerStatus) bool {
+	if status.State.Running != nil || status.State.Terminated != nil {
+		return true
+	}
+	if w := status.State.Waiting; w != nil {
+		switch w.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+			return true
+		}
+	}
+	return false
+}
+
+// describeDebugState fills in the state of the debug container and how to
+// use it.
+func describeDebugState(response *DebugPodResponse, status *corev1.ContainerStatus) {
+	switch {
+	case status == nil:
+		response.State = "waiting"
+		response.Message = fmt.Sprintf("Debug container %s was added to pod %s/%s but has not reported a status yet", response.ContainerName, response.Namespace, response.PodName)
+	case status.State.Running != nil:
+		response.State = "running"
+		response.Message = fmt.Sprintf("Debug container %s is running in pod %s/%s", response.ContainerName, response.Namespace, response.PodName)
+	case status.State.Terminated != nil:
+		response.Success = false
+		response.State = "terminated"
+		response.Reason = status.State.Terminated.Reason
+		response.Details = status.State.Terminated.Message
+		response.Message = fmt.Sprintf("Debug container %s exited with code %d", response.ContainerName, status.State.Terminated.ExitCode)
+		response.Instructions = fmt.Sprintf("Use the 'logs' tool with containerName=%s to read its output. Ephemeral containers cannot be restarted; run debug_pod again to start a new one.", response.ContainerName)
+		return
+	default:
+		response.State = "waiting"
+		response.Reason = status.State.Waiting.Reason
+		response.Details = status.State.Waiting.Message
+		response.Message = fmt.Sprintf("Debug container %s is waiting", response.ContainerName)
+		if debugContainerSettled(status) {
+			response.Success = false
+			response.Message = fmt.Sprintf("Debug container %s cannot start: %s", response.ContainerName, response.Reason)
+			return
+		}
+	}
+
+	instructions := fmt.Sprintf("Use the 'exec' tool with podName=%s and containerName=%s to run commands in it", response.PodName, response.ContainerName)
+	if response.TargetContainer != "" {
+		instructions += fmt.Sprintf("; it shares the process namespace of container %s, whose filesystem is usually under /proc/1/root", response.TargetContainer)
+	}
+	response.Instructions = instructions + ". Ephemeral containers cannot be removed; the container stays in the pod spec until the pod is replaced."
+}
@@ -0,0 +1,206 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// debugMock records the ephemeral container added to a pod and reports it
+// in the given state.
+type debugMock struct {
+	*testdata.MockK8sClient
+
+	added    *corev1.EphemeralContainer
+	state    corev1.ContainerState
+	addErr   error
+	getCalls int
+}
+
+func (m *debugMock) AddEphemeralContainer(_ context.Context, _, _, _ string, container corev1.EphemeralContainer) (*corev1.Pod, error) {
+	if m.addErr != nil {
+		return nil, m.addErr
+	}
+	m.added = &container
+	return &corev1.Pod{}, nil
+}
+
+func (m *debugMock) Get(_ context.Context, _, namespace, _, _, name string) (*k8s.GetResponse, error) {
+	m.getCalls++
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if m.added != nil {
+		pod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{{Name: m.added.Name, State: m.state}}
+	}
+	return &k8s.GetResponse{Resource: mcptest.ToUnstructured(pod)}, nil
+}
+
+func callDebugPod(t *testing.T, mock *debugMock, args map[string]interface{}, opts ...server.Option) (*mcp.CallToolResult, DebugPodResponse) {
+	t.Helper()
+	origInterval := debugPollInterval
+	debugPollInterval = time.Millisecond
+	t.Cleanup(func() { debugPollInterval = origInterval })
+
+	baseOpts := []server.Option{
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	}
+	sc, err := server.NewServerContext(context.Background(), append(baseOpts, opts...)...)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "debug_pod"
+	request.Params.Arguments = args
+	result, err := handleDebugPod(context.Background(), request, sc)
+	require.NoError(t, err)
+
+	var out DebugPodResponse
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	}
+	return result, out
+}
+
+func TestDebugPod_Running(t *testing.T) {
+	mock := &debugMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		state:         corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}
+	result, out := callDebugPod(t, mock, map[string]interface{}{
+		"namespace":       "shop",
+		"podName":         "api-7d9f",
+		"targetContainer": "api",
+		"ttlSeconds":      float64(600),
+	})
+	require.False(t, result.IsError)
+
+	require.NotNil(t, mock.added)
+	assert.Regexp(t, `^debugger-[a-z0-9]{5}$`, mock.added.Name)
+	assert.Equal(t, server.DefaultDebugImage, mock.added.Image)
+	assert.Equal(t, []string{"sleep", "600"}, mock.added.Command)
+	assert.Equal(t, "api", mock.added.TargetContainerName)
+
+	assert.True(t, out.Success)
+	assert.Equal(t, "running", out.State)
+	assert.Equal(t, mock.added.Name, out.ContainerName)
+	assert.Contains(t, out.Instructions, "containerName="+mock.added.Name)
+	assert.Contains(t, out.Instructions, "process namespace of container api")
+}
+
+func TestDebugPod_ImageAllowlist(t *testing.T) {
+	images := server.WithDebugImages([]string{"registry.example.com/debug/netshoot:v0.13", "registry.example.com/tools/*"})
+
+	tests := []struct {
+		name      string
+		image     string
+		wantImage string
+		wantErr   string
+	}{
+		{name: "default is the first entry", wantImage: "registry.example.com/debug/netshoot:v0.13"},
+		{name: "exact entry", image: "registry.example.com/debug/netshoot:v0.13", wantImage: "registry.example.com/debug/netshoot:v0.13"},
+		{name: "prefix entry", image: "registry.example.com/tools/busybox:1.36", wantImage: "registry.example.com/tools/busybox:1.36"},
+		{name: "other tag", image: "registry.example.com/debug/netshoot:latest", wantErr: "is not an allowed debug image"},
+		{name: "other registry", image: "docker.io/nicolaka/netshoot", wantErr: "is not an allowed debug image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &debugMock{
+				MockK8sClient: &testdata.MockK8sClient{},
+				state:         corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}
+			args := map[string]interface{}{"namespace": "shop", "podName": "api-7d9f"}
+			if tt.image != "" {
+				args["image"] = tt.image
+			}
+			result, _ := callDebugPod(t, mock, args, images)
+
+			if tt.wantErr != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.wantErr)
+				assert.Nil(t, mock.added, "nothing is added for a refused image")
+				return
+			}
+			require.False(t, result.IsError)
+			assert.Equal(t, tt.wantImage, mock.added.Image)
+		})
+	}
+
+	t.Run("default image only when none configured", func(t *testing.T) {
+		result, _ := callDebugPod(t, &debugMock{MockK8sClient: &testdata.MockK8sClient{}},
+			map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "image": "alpine:3.20"})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "allowed: "+server.DefaultDebugImage)
+	})
+}
+
+func TestDebugPod_ContainerCannotStart(t *testing.T) {
+	mock := &debugMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		state: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason:  "ImagePullBackOff",
+			Message: "Back-off pulling image",
+		}},
+	}
+	_, out := callDebugPod(t, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f"})
+
+	assert.False(t, out.Success)
+	assert.Equal(t, "waiting", out.State)
+	assert.Equal(t, "ImagePullBackOff", out.Reason)
+	assert.Equal(t, 1, mock.getCalls, "a container that cannot start is reported without waiting")
+}
+
+func TestDebugPod_DryRun(t *testing.T) {
+	mock := &debugMock{MockK8sClient: &testdata.MockK8sClient{}}
+	_, out := callDebugPod(t, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f"}, server.WithDryRun(true))
+
+	assert.True(t, out.Success)
+	assert.True(t, out.DryRun)
+	assert.Contains(t, out.Message, "Dry run")
+	assert.Zero(t, mock.getCalls, "a dry-run container never starts, so it is not waited for")
+}
+
+func TestDebugPod_Errors(t *testing.T) {
+	t.Run("blocked in non-destructive mode", func(t *testing.T) {
+		mock := &debugMock{MockK8sClient: &testdata.MockK8sClient{}}
+		result, _ := callDebugPod(t, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f"}, server.WithNonDestructiveMode(true))
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "not allowed in non-destructive mode")
+		assert.Nil(t, mock.added)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, tc := range []struct {
+			args map[string]interface{}
+			want string
+		}{
+			{map[string]interface{}{"podName": "api-7d9f"}, "namespace is required"},
+			{map[string]interface{}{"namespace": "shop"}, "podName is required"},
+			{map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "ttlSeconds": float64(0)}, "ttlSeconds must be between"},
+			{map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "command": "sh"}, "command must be an array of strings"},
+		} {
+			result, _ := callDebugPod(t, &debugMock{MockK8sClient: &testdata.MockK8sClient{}}, tc.args)
+			require.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.want)
+		}
+	})
+
+	t.Run("pod not running", func(t *testing.T) {
+		mock := &debugMock{MockK8sClient: &testdata.MockK8sClient{}, addErr: errors.New("pod shop/api-7d9f is not running")}
+		result, _ := callDebugPod(t, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f"})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "is not running")
+	})
+}
@@ -0,0 +1,608 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Probable causes reported by the diagnose_pod tool.
+const (
+	CauseImagePull      = "ImagePullBackOff"
+	CauseOOMKilled      = "OOMKilled"
+	CauseCrashLoop      = "CrashLoopBackOff"
+	CauseUnschedulable  = "Unschedulable"
+	CauseConfigError    = "ContainerConfigError"
+	CauseProbeFailure   = "ProbeFailure"
+	CauseNodeNotReady   = "NodeNotReady"
+	CauseEvicted        = "Evicted"
+	CauseContainerError = "ContainerError"
+)
+
+const (
+	// defaultDiagnoseLogLines is the number of previous-container log lines
+	// fetched per crashed container.
+	defaultDiagnoseLogLines = 20
+
+	// maxDiagnoseLogLines caps the tailLines argument.
+	maxDiagnoseLogLines = 200
+
+	// maxDiagnoseLogBytes caps the log excerpt of each container.
+	maxDiagnoseLogBytes = 8 * 1024
+
+	// maxDiagnoseEvents caps the events in a diagnosis, newest first.
+	maxDiagnoseEvents = 15
+)
+
+// PodDiagnosis is the response of the diagnose_pod tool.
+type PodDiagnosis struct {
+	Pod PodSummary `json:"pod"`
+
+	// Healthy is true when the pod is running or completed with all
+	// containers ready and no probable cause was found.
+	Healthy bool `json:"healthy"`
+
+	// Causes are the probable causes of the pod's trouble, most severe first.
+	Causes []PodCause `json:"causes,omitempty"`
+
+	Conditions []PodConditionSummary `json:"conditions,omitempty"`
+	Containers []ContainerDiagnosis  `json:"containers"`
+	Events     []PodEvent            `json:"events,omitempty"`
+	Node       *NodeSummary          `json:"node,omitempty"`
+
+	// Unavailable lists the details that could not be fetched, such as
+	// node conditions the user may not read.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// PodSummary is the pod's identity and overall status.
+type PodSummary struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Phase     string `json:"phase"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Node      string `json:"node,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	QOSClass  string `json:"qosClass,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+	StartTime string `json:"startTime,omitempty"`
+}
+
+// PodCause is a probable cause with the evidence for it.
+type PodCause struct {
+	Cause      string `json:"cause"`
+	Container  string `json:"container,omitempty"`
+	Detail     string `json:"detail"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// PodConditionSummary is a pod condition.
+type PodConditionSummary struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ContainerDiagnosis is the state of one container.
+type ContainerDiagnosis struct {
+	Name         string `json:"name"`
+	Init         bool   `json:"init,omitempty"`
+	Image        string `json:"image"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+
+	// State is waiting, running or terminated.
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
+	LastTerminated *TerminationSummary `json:"lastTerminated,omitempty"`
+
+	// MemoryLimit is shown with OOM kills.
+	MemoryLimit string `json:"memoryLimit,omitempty"`
+
+	// Logs are the last lines of the previous instance, or of the current
+	// one when it has terminated.
+	Logs string `json:"logs,omitempty"`
+}
+
+// TerminationSummary describes how a container instance ended.
+type TerminationSummary struct {
+	Reason     string `json:"reason,omitempty"`
+	ExitCode   int32  `json:"exitCode"`
+	Signal     int32  `json:"signal,omitempty"`
+	Message    string `json:"message,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
+
+// PodEvent is an event about the pod.
+type PodEvent struct {
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count,omitempty"`
+	LastSeen string `json:"lastSeen,omitempty"`
+}
+
+// NodeSummary is the state of the node the pod is on.
+type NodeSummary struct {
+	Name          string `json:"name"`
+	Ready         bool   `json:"ready"`
+	Unschedulable bool   `json:"unschedulable,omitempty"`
+
+	// Problems are the node conditions in a bad state, such as
+	// MemoryPressure=True.
+	Problems []PodConditionSummary `json:"problems,omitempty"`
+}
+
+// podDiagnoser collects the diagnosis of one pod.
+type podDiagnoser struct {
+	client      k8s.Client
+	kubeContext string
+	tailLines   int64
+	pod         *corev1.Pod
+	out         *PodDiagnosis
+
+	// scrubLogs redacts credentials from fetched logs.
+	scrubLogs func(string) string
+}
+
+func (d *podDiagnoser) cause(cause, container, detail, suggestion string) {
+	d.out.Causes = append(d.out.Causes, PodCause{Cause: cause, Container: container, Detail: detail, Suggestion: suggestion})
+}
+
+// handleDiagnosePod gathers a pod's status, container states, events,
+// crashed-container logs and node conditions, and names probable causes.
+func handleDiagnosePod(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	// Extract cluster parameter for multi-cluster support
+	clusterName := tools.ExtractClusterParam(args)
+
+	kubeContext, _ := args["kubeContext"].(string)
+
+	namespace, ok := args["namespace"].(string)
+	if !ok || namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+
+	podName, ok := args["podName"].(string)
+	if !ok || podName == "" {
+		return toolerrors.Required("podName").Result(), nil
+	}
+
+	tailLines := int64(defaultDiagnoseLogLines)
+	if tailLinesFloat, ok := args["tailLines"].(float64); ok {
+		tailLines = int64(tailLinesFloat)
+		if tailLines < 0 || tailLines > maxDiagnoseLogLines {
+			return toolerrors.InvalidArgumentf("tailLines must be between 0 and %d", maxDiagnoseLogLines).Result(), nil
+		}
+	}
+
+	// Get the appropriate k8s client (local or federated)
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	resp, err := k8sClient.Get(ctx, kubeContext, namespace, "pods", "", podName)
+	if err != nil {
+		return tools.K8sError("Failed to get pod", err, client.User()).Result(), nil
+	}
+	pod := &corev1.Pod{}
+	if err := fromObject(resp.Resource, pod); err != nil {
+		return toolerrors.Internalf("Failed to decode pod: %v", err).Result(), nil
+	}
+
+	d := &podDiagnoser{
+		client:      k8sClient,
+		kubeContext: kubeContext,
+		tailLines:   tailLines,
+		pod:         pod,
+		out:         &PodDiagnosis{Containers: []ContainerDiagnosis{}},
+		scrubLogs: func(logs string) string {
+			return scrubCredentials(ctx, sc, instrumentation.OperationLogs, logs)
+		},
+	}
+	d.summarise()
+	d.collectEvents(ctx)
+	d.collectNode(ctx)
+	d.collectContainers(ctx)
+	d.classify()
+
+	jsonData, err := json.MarshalIndent(d.out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal pod diagnosis: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// summarise fills in the pod summary and conditions.
+func (d *podDiagnoser) summarise() {
+	pod := d.pod
+	d.out.Pod = PodSummary{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Phase:     string(pod.Status.Phase),
+		Reason:    pod.Status.Reason,
+		Message:   pod.Status.Message,
+		Node:      pod.Spec.NodeName,
+		IP:        pod.Status.PodIP,
+		QOSClass:  string(pod.Status.QOSClass),
+	}
+	if pod.Status.StartTime != nil {
+		d.out.Pod.StartTime = pod.Status.StartTime.UTC().Format(time.RFC3339)
+	}
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			d.out.Pod.Owner = ref.Kind + "/" + ref.Name
+		}
+	}
+	for _, c := range pod.Status.Conditions {
+		d.out.Conditions = append(d.out.Conditions, PodConditionSummary{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+}
+
+// collectEvents lists the newest events about the pod.
+func (d *podDiagnoser) collectEvents(ctx context.Context) {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+		fields.OneTermEqualSelector("involvedObject.name", d.pod.Name),
+	).String()
+	resp, err := d.client.List(ctx, d.kubeContext, d.pod.Namespace, "events", "", k8s.ListOptions{FieldSelector: selector})
+	if err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("events: %v", err))
+		return
+	}
+
+	type timedEvent struct {
+		event PodEvent
+		at    time.Time
+	}
+	var events []timedEvent
+	for _, item := range resp.Items {
+		var ev corev1.Event
+		if err := fromObject(item, &ev); err != nil {
+			continue
+		}
+		// Events of an earlier pod with the same name are not about this one.
+		if ev.InvolvedObject.UID != "" && d.pod.UID != "" && ev.InvolvedObject.UID != d.pod.UID {
+			continue
+		}
+		at := eventTime(&ev)
+		pe := PodEvent{Type: ev.Type, Reason: ev.Reason, Message: ev.Message, Count: ev.Count}
+		if !at.IsZero() {
+			pe.LastSeen = at.UTC().Format(time.RFC3339)
+		}
+		events = append(events, timedEvent{event: pe, at: at})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.After(events[j].at) })
+	if len(events) > maxDiagnoseEvents {
+		events = events[:maxDiagnoseEvents]
+	}
+	for _, ev := range events {
+		d.out.Events = append(d.out.Events, ev.event)
+	}
+}
+
+// eventTime returns when an event was last seen.
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case ev.Series != nil && !ev.Series.LastObservedTime.IsZero():
+		return ev.Series.LastObservedTime.Time
+	default:
+		return ev.FirstTimestamp.Time
+	}
+}
+
+// collectNode reads the conditions of the pod's node.
+func (d *podDiagnoser) collectNode(ctx context.Context) {
+	if d.pod.Spec.NodeName == "" {
+		return
+	}
+	resp, err := d.client.Get(ctx, d.kubeContext, "", "nodes", "", d.pod.Spec.NodeName)
+	if err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("node %s: %v", d.pod.Spec.NodeName, err))
+		return
+	}
+	node := &corev1.Node{}
+	if err := fromObject(resp.Resource, node); err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("node %s: %v", d.pod.Spec.NodeName, err))
+		return
+	}
+
+	summary := &NodeSummary{Name: node.Name, Unschedulable: node.Spec.Unschedulable}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			summary.Ready = c.Status == corev1.ConditionTrue
+			if summary.Ready {
+				continue
+			}
+		} else if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		summary.Problems = append(summary.Problems, PodConditionSummary{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+	d.out.Node = summary
+}
+
+// collectContainers summarises the init and app containers and fetches the
+// logs of those that have crashed.
+func (d *podDiagnoser) collectContainers(ctx context.Context) {
+	specs := map[string]corev1.Container{}
+	for _, c := range d.pod.Spec.InitContainers {
+		specs[c.Name] = c
+	}
+	for _, c := range d.pod.Spec.Containers {
+		specs[c.Name] = c
+	}
+
+	add := func(status corev1.ContainerStatus, init bool) {
+		spec := specs[status.Name]
+		c := ContainerDiagnosis{
+			Name:         status.Name,
+			Init:         init,
+			Image:        status.Image,
+			Ready:        status.Ready,
+			RestartCount: status.RestartCount,
+		}
+		if c.Image == "" {
+			c.Image = spec.Image
+		}
+		switch state := status.State; {
+		case state.Waiting != nil:
+			c.State = "waiting"
+			c.Reason = state.Waiting.Reason
+			c.Message = state.Waiting.Message
+		case state.Terminated != nil:
+			c.State = "terminated"
+			c.Reason = state.Terminated.Reason
+			c.Message = state.Terminated.Message
+			c.ExitCode = &state.Terminated.ExitCode
+		case state.Running != nil:
+			c.State = "running"
+		default:
+			c.State = "unknown"
+		}
+		if last := status.LastTerminationState.Terminated; last != nil {
+			c.LastTerminated = &TerminationSummary{
+				Reason:   last.Reason,
+				ExitCode: last.ExitCode,
+				Signal:   last.Signal,
+				Message:  last.Message,
+			}
+			if !last.FinishedAt.IsZero() {
+				c.LastTerminated.FinishedAt = last.FinishedAt.UTC().Format(time.RFC3339)
+			}
+		}
+		if limit, ok := spec.Resources.Limits[corev1.ResourceMemory]; ok {
+			c.MemoryLimit = limit.String()
+		}
+
+		switch {
+		case c.LastTerminated != nil:
+			c.Logs = d.fetchLogs(ctx, c.Name, true)
+		case c.State == "terminated" && c.ExitCode != nil && *c.ExitCode != 0:
+			c.Logs = d.fetchLogs(ctx, c.Name, false)
+		}
+		d.out.Containers = append(d.out.Containers, c)
+	}
+
+	seen := map[string]bool{}
+	for _, status := range d.pod.Status.InitContainerStatuses {
+		add(status, true)
+		seen[status.Name] = true
+	}
+	for _, status := range d.pod.Status.ContainerStatuses {
+		add(status, false)
+		seen[status.Name] = true
+	}
+	// Containers without a status have not been created yet, for example
+	// because the pod is not scheduled.
+	for _, spec := range d.pod.Spec.Containers {
+		if !seen[spec.Name] {
+			d.out.Containers = append(d.out.Containers, ContainerDiagnosis{Name: spec.Name, Image: spec.Image, State: "pending"})
+		}
+	}
+}
+
+// fetchLogs returns the last log lines of a container instance. Failures
+// are recorded as unavailable rather than failing the diagnosis.
+func (d *podDiagnoser) fetchLogs(ctx context.Context, container string, previous bool) string {
+	if d.tailLines == 0 {
+		return ""
+	}
+	tailLines := d.tailLines
+	logs, err := d.client.GetLogs(ctx, d.kubeContext, d.pod.Namespace, d.pod.Name, container, k8s.LogOptions{
+		Previous:  previous,
+		TailLines: &tailLines,
+	})
+	if err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("logs of container %s: %v", container, err))
+		return ""
+	}
+	defer func() { _ = logs.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(logs, maxDiagnoseLogBytes))
+	if err != nil {
+		d.out.Unavailable = append(d.out.Unavailable, fmt.Sprintf("logs of container %s: %v", container, err))
+	}
+	return d.scrubLogs(string(data))
+}
+
+// classify names the probable causes from the collected state.
+func (d *podDiagnoser) classify() {
+	pod := d.pod
+
+	if pod.Status.Reason == "Evicted" {
+		d.cause(CauseEvicted, "", pod.Status.Message,
+			"The kubelet evicted the pod under node resource pressure. Check the node's conditions and set resource requests that reflect actual usage.")
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+			d.cause(CauseUnschedulable, "", c.Message,
+				"No node fits the pod. Compare its requests, node selector, affinity and tolerations with the nodes, or add capacity.")
+		}
+	}
+	if d.out.Node != nil && !d.out.Node.Ready {
+		detail := "node " + d.out.Node.Name + " is not ready"
+		for _, p := range d.out.Node.Problems {
+			if p.Type == string(corev1.NodeReady) && p.Message != "" {
+				detail += ": " + p.Message
+			}
+		}
+		d.cause(CauseNodeNotReady, "", detail,
+			"Pods on a node that is not ready stop being updated and are evicted after the toleration timeout. Check the node with the cluster's node tools.")
+	}
+
+	for _, c := range d.out.Containers {
+		d.classifyContainer(c)
+	}
+
+	if len(d.out.Causes) == 0 && pod.Status.Phase == corev1.PodRunning {
+		for _, c := range d.out.Containers {
+			if c.Init || c.Ready || c.State != "running" {
+				continue
+			}
+			if ev := d.latestEvent("Unhealthy"); ev != nil {
+				d.cause(CauseProbeFailure, c.Name, ev.Message,
+					"The container runs but fails its probe. Check the probe's path, port and timing against the application.")
+				break
+			}
+		}
+	}
+
+	d.out.Healthy = len(d.out.Causes) == 0 && podHealthy(pod)
+}
+
+// classifyContainer names the probable cause for one container.
+func (d *podDiagnoser) classifyContainer(c ContainerDiagnosis) {
+	switch c.Reason {
+	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull":
+		detail := c.Message
+		if ev := d.latestEvent("Failed"); ev != nil && strings.Contains(ev.Message, "pull") {
+			detail = ev.Message
+		}
+		if detail == "" {
+			detail = "cannot pull image " + c.Image
+		}
+		d.cause(CauseImagePull, c.Name, detail,
+			"Check that the image name and tag exist, that the registry is reachable from the node, and that the pod has imagePullSecrets for private registries.")
+		return
+	case "CreateContainerConfigError", "CreateContainerError", "RunContainerError":
+		d.cause(CauseConfigError, c.Name, c.Message,
+			"The container could not be created. Check the ConfigMaps, Secrets and volumes it references, and its command.")
+		return
+	}
+
+	if oomKilled(c) {
+		detail := "the container was killed for exceeding its memory limit"
+		if c.MemoryLimit != "" {
+			detail += " of " + c.MemoryLimit
+		}
+		if c.RestartCount > 0 {
+			detail += fmt.Sprintf(" (%d restarts)", c.RestartCount)
+		}
+		d.cause(CauseOOMKilled, c.Name, detail,
+			"Raise the memory limit or reduce the application's memory use.")
+		return
+	}
+
+	if c.Reason == "CrashLoopBackOff" {
+		detail := fmt.Sprintf("the container keeps exiting (%d restarts)", c.RestartCount)
+		if last := c.LastTerminated; last != nil {
+			detail += fmt.Sprintf("; last exit code %d", last.ExitCode)
+			if last.Reason != "" {
+				detail += " (" + last.Reason + ")"
+			}
+		}
+		d.cause(CauseCrashLoop, c.Name, detail,
+			"Read the logs of the previous instance for the error. Exit code 1 is usually an application error; 137 is a kill, 139 a segfault.")
+		return
+	}
+
+	if c.State == "terminated" && c.ExitCode != nil && *c.ExitCode != 0 && d.pod.Status.Phase == corev1.PodFailed {
+		d.cause(CauseContainerError, c.Name, fmt.Sprintf("the container exited with code %d (%s)", *c.ExitCode, c.Reason),
+			"Read the container's logs for the error.")
+	}
+}
+
+// oomKilled reports whether the current or previous instance of the
+// container was OOM-killed.
+func oomKilled(c ContainerDiagnosis) bool {
+	if c.State == "terminated" && c.Reason == "OOMKilled" {
+		return true
+	}
+	return c.LastTerminated != nil && c.LastTerminated.Reason == "OOMKilled"
+}
+
+// latestEvent returns the newest event with the given reason.
+func (d *podDiagnoser) latestEvent(reason string) *PodEvent {
+	for i := range d.out.Events {
+		if d.out.Events[i].Reason == reason {
+			return &d.out.Events[i]
+		}
+	}
+	return nil
+}
+
+// podHealthy reports whether a pod has completed or is running with all
+// its containers ready.
+func podHealthy(pod *corev1.Pod) bool {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true
+	case corev1.PodRunning:
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				return c.Status == corev1.ConditionTrue
+			}
+		}
+	}
+	return false
+}
+
+// fromObject converts a typed or unstructured object into a typed one.
+func fromObject(obj runtime.Object, into interface{}) error {
+	if obj == nil {
+		return fmt.Errorf("empty object")
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, into)
+}
@@ -0,0 +1,378 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// diagnoseMock serves one pod, its node and events, and records log
+// requests.
+type diagnoseMock struct {
+	*testdata.MockK8sClient
+
+	pod        *corev1.Pod
+	node       *corev1.Node
+	events     []corev1.Event
+	nodeErr    error
+	logs       string
+	logOpts    []k8s.LogOptions
+	logTargets []string
+	eventsOpts k8s.ListOptions
+}
+
+func (m *diagnoseMock) Get(_ context.Context, _, _, resourceType, _, name string) (*k8s.GetResponse, error) {
+	switch {
+	case resourceType == "pods" && name == m.pod.Name:
+		return &k8s.GetResponse{Resource: mcptest.ToUnstructured(m.pod)}, nil
+	case resourceType == "nodes" && m.nodeErr != nil:
+		return nil, m.nodeErr
+	case resourceType == "nodes" && m.node != nil && name == m.node.Name:
+		return &k8s.GetResponse{Resource: mcptest.ToUnstructured(m.node)}, nil
+	}
+	return nil, errors.New(resourceType + " \"" + name + "\" not found")
+}
+
+func (m *diagnoseMock) List(_ context.Context, _, _, resourceType, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	if resourceType != "events" {
+		return nil, errors.New("unexpected resource " + resourceType)
+	}
+	m.eventsOpts = opts
+	items := make([]runtime.Object, 0, len(m.events))
+	for i := range m.events {
+		items = append(items, mcptest.ToUnstructured(&m.events[i]))
+	}
+	return &k8s.PaginatedListResponse{Items: items}, nil
+}
+
+func (m *diagnoseMock) GetLogs(_ context.Context, _, _, _, containerName string, opts k8s.LogOptions) (io.ReadCloser, error) {
+	m.logOpts = append(m.logOpts, opts)
+	m.logTargets = append(m.logTargets, containerName)
+	return io.NopCloser(strings.NewReader(m.logs)), nil
+}
+
+func newDiagnoseMock() *diagnoseMock {
+	return &diagnoseMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-7d9f",
+				Namespace: "shop",
+				UID:       "pod-uid",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "api-5c8", Controller: ptrTo(true)},
+				},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node-1",
+				Containers: []corev1.Container{{
+					Name:  "api",
+					Image: "example.com/api:1.2.3",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSBurstable,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "api",
+					Image: "example.com/api:1.2.3",
+					Ready: true,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		},
+		node: &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			}},
+		},
+		logs: "panic: something broke\n",
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func podEvent(reason, message string, age time.Duration) corev1.Event {
+	return corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "api-7d9f." + reason, Namespace: "shop"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-7d9f", UID: "pod-uid"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		Count:          3,
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+	}
+}
+
+func callDiagnosePod(t *testing.T, mock *diagnoseMock, args map[string]interface{}) (*mcp.CallToolResult, PodDiagnosis) {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "diagnose_pod"
+	request.Params.Arguments = args
+	result, err := handleDiagnosePod(context.Background(), request, sc)
+	require.NoError(t, err)
+
+	var out PodDiagnosis
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	}
+	return result, out
+}
+
+func diagnoseArgs() map[string]interface{} {
+	return map[string]interface{}{"namespace": "shop", "podName": "api-7d9f"}
+}
+
+func causeNames(causes []PodCause) []string {
+	names := make([]string, 0, len(causes))
+	for _, c := range causes {
+		names = append(names, c.Cause)
+	}
+	return names
+}
+
+func TestDiagnosePod_Healthy(t *testing.T) {
+	mock := newDiagnoseMock()
+	result, out := callDiagnosePod(t, mock, diagnoseArgs())
+	require.False(t, result.IsError)
+
+	assert.True(t, out.Healthy)
+	assert.Empty(t, out.Causes)
+	assert.Equal(t, "ReplicaSet/api-5c8", out.Pod.Owner)
+	assert.Equal(t, "Burstable", out.Pod.QOSClass)
+	require.Len(t, out.Containers, 1)
+	assert.Equal(t, "running", out.Containers[0].State)
+	assert.Empty(t, out.Containers[0].Logs)
+	assert.Empty(t, mock.logTargets, "logs are only fetched for crashed containers")
+
+	require.NotNil(t, out.Node)
+	assert.True(t, out.Node.Ready)
+	assert.Empty(t, out.Node.Problems)
+	assert.Empty(t, out.Unavailable)
+	assert.Equal(t, "involvedObject.kind=Pod,involvedObject.name=api-7d9f", mock.eventsOpts.FieldSelector)
+}
+
+func TestDiagnosePod_Causes(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(m *diagnoseMock)
+		wantCause   string
+		wantDetail  string
+		wantLogsFor string
+	}{
+		{
+			name: "image pull back-off",
+			mutate: func(m *diagnoseMock) {
+				m.pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+					Name:  "api",
+					Image: "example.com/api:1.2.3",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+				}
+				m.events = []corev1.Event{podEvent("Failed", `Failed to pull image "example.com/api:1.2.3": not found`, time.Minute)}
+			},
+			wantCause:  CauseImagePull,
+			wantDetail: `Failed to pull image "example.com/api:1.2.3": not found`,
+		},
+		{
+			name: "OOM killed",
+			mutate: func(m *diagnoseMock) {
+				m.pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+					Name:                 "api",
+					RestartCount:         4,
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+				}
+			},
+			wantCause:   CauseOOMKilled,
+			wantDetail:  "the container was killed for exceeding its memory limit of 256Mi (4 restarts)",
+			wantLogsFor: "api",
+		},
+		{
+			name: "crash loop",
+			mutate: func(m *diagnoseMock) {
+				m.pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+					Name:                 "api",
+					RestartCount:         7,
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+				}
+			},
+			wantCause:   CauseCrashLoop,
+			wantDetail:  "the container keeps exiting (7 restarts); last exit code 1 (Error)",
+			wantLogsFor: "api",
+		},
+		{
+			name: "unschedulable",
+			mutate: func(m *diagnoseMock) {
+				m.pod.Spec.NodeName = ""
+				m.pod.Status = corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: "0/3 nodes are available: 3 Insufficient memory.",
+					}},
+				}
+			},
+			wantCause:  CauseUnschedulable,
+			wantDetail: "0/3 nodes are available: 3 Insufficient memory.",
+		},
+		{
+			name: "missing config",
+			mutate: func(m *diagnoseMock) {
+				m.pod.Status.ContainerStatuses[0].Ready = false
+				m.pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "CreateContainerConfigError",
+					Message: `secret "api-db" not found`,
+				}}
+			},
+			wantCause:  CauseConfigError,
+			wantDetail: `secret "api-db" not found`,
+		},
+		{
+			name: "failing readiness probe",
+			mutate: func(m *diagnoseMock) {
+				m.pod.Status.ContainerStatuses[0].Ready = false
+				m.pod.Status.Conditions[1].Status = corev1.ConditionFalse
+				m.events = []corev1.Event{
+					podEvent("Unhealthy", "Readiness probe failed: HTTP probe failed with statuscode: 503", time.Minute),
+					podEvent("Unhealthy", "Readiness probe failed: connection refused", time.Hour),
+				}
+			},
+			wantCause:  CauseProbeFailure,
+			wantDetail: "Readiness probe failed: HTTP probe failed with statuscode: 503",
+		},
+		{
+			name: "node not ready",
+			mutate: func(m *diagnoseMock) {
+				m.node.Status.Conditions[0] = corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Message: "Kubelet stopped posting node status."}
+			},
+			wantCause:  CauseNodeNotReady,
+			wantDetail: "node node-1 is not ready: Kubelet stopped posting node status.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newDiagnoseMock()
+			tt.mutate(mock)
+
+			_, out := callDiagnosePod(t, mock, diagnoseArgs())
+
+			assert.False(t, out.Healthy)
+			require.NotEmpty(t, out.Causes, "expected a probable cause")
+			assert.Equal(t, []string{tt.wantCause}, causeNames(out.Causes))
+			assert.Equal(t, tt.wantDetail, out.Causes[0].Detail)
+			assert.NotEmpty(t, out.Causes[0].Suggestion)
+
+			if tt.wantLogsFor != "" {
+				assert.Equal(t, []string{tt.wantLogsFor}, mock.logTargets)
+				require.Len(t, mock.logOpts, 1)
+				assert.True(t, mock.logOpts[0].Previous)
+				assert.Equal(t, int64(defaultDiagnoseLogLines), *mock.logOpts[0].TailLines)
+				assert.Equal(t, "panic: something broke\n", out.Containers[0].Logs)
+			}
+		})
+	}
+}
+
+func TestDiagnosePod_FailedContainerLogs(t *testing.T) {
+	mock := newDiagnoseMock()
+	mock.pod.Status.Phase = corev1.PodFailed
+	mock.pod.Status.ContainerStatuses[0].Ready = false
+	mock.pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 2}}
+
+	_, out := callDiagnosePod(t, mock, map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "tailLines": float64(50)})
+
+	assert.Equal(t, []string{CauseContainerError}, causeNames(out.Causes))
+	require.Len(t, mock.logOpts, 1)
+	assert.False(t, mock.logOpts[0].Previous, "a terminated container's own logs are read")
+	assert.Equal(t, int64(50), *mock.logOpts[0].TailLines)
+	require.NotNil(t, out.Containers[0].ExitCode)
+	assert.Equal(t, int32(2), *out.Containers[0].ExitCode)
+}
+
+func TestDiagnosePod_Events(t *testing.T) {
+	mock := newDiagnoseMock()
+	for i := 0; i < maxDiagnoseEvents+5; i++ {
+		mock.events = append(mock.events, podEvent("Pulled", "pulled", time.Duration(i)*time.Minute))
+	}
+	stale := podEvent("Killing", "from an earlier pod", 0)
+	stale.InvolvedObject.UID = "old-uid"
+	mock.events = append(mock.events, stale)
+
+	_, out := callDiagnosePod(t, mock, diagnoseArgs())
+
+	require.Len(t, out.Events, maxDiagnoseEvents)
+	for _, ev := range out.Events {
+		assert.Equal(t, "Pulled", ev.Reason, "events of an earlier pod with the same name are skipped")
+	}
+	assert.GreaterOrEqual(t, out.Events[0].LastSeen, out.Events[1].LastSeen, "newest first")
+}
+
+func TestDiagnosePod_PartialAccess(t *testing.T) {
+	mock := newDiagnoseMock()
+	mock.nodeErr = errors.New("nodes is forbidden")
+
+	result, out := callDiagnosePod(t, mock, diagnoseArgs())
+
+	require.False(t, result.IsError)
+	assert.Nil(t, out.Node)
+	assert.Equal(t, []string{"node node-1: nodes is forbidden"}, out.Unavailable)
+	assert.True(t, out.Healthy)
+}
+
+func TestDiagnosePod_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing namespace", map[string]interface{}{"podName": "api-7d9f"}, "namespace is required"},
+		{"missing pod", map[string]interface{}{"namespace": "shop"}, "podName is required"},
+		{"tailLines too large", map[string]interface{}{"namespace": "shop", "podName": "api-7d9f", "tailLines": float64(1000)}, "tailLines must be between 0 and 200"},
+		{"pod not found", map[string]interface{}{"namespace": "shop", "podName": "web"}, "Failed to get pod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := callDiagnosePod(t, newDiagnoseMock(), tt.args)
+			require.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tt.want)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// callTool calls a pod tool with client through mcptest.
+func callTool(t *testing.T, client k8s.Client, toolName string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	return mcptest.CallRegisteredTool(t, client, RegisterPodTools, toolName, args)
+}
@@ -1,6 +1,7 @@
 package pod
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,9 +14,27 @@ import (
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
 )
 
+// scrubCredentials redacts likely credentials from pod log or exec output
+// (source) when the server's ScrubCredentials setting is on, and records
+// the redactions.
+func scrubCredentials(ctx context.Context, sc *server.ServerContext, source, text string) string {
+	outputCfg := sc.OutputConfig()
+	if !outputCfg.ScrubCredentials || text == "" {
+		return text
+	}
+	scrubbed, counts := output.ScrubCredentials(text, outputCfg.CredentialPatterns)
+	for pattern, count := range counts {
+		sc.RecordCredentialsRedacted(ctx, source, pattern, count)
+	}
+	return scrubbed
+}
+
 // checkMutatingOperation is a convenience wrapper around tools.CheckMutatingOperation.
 // It verifies if a mutating operation is allowed given the current server configuration.
 func checkMutatingOperation(sc *server.ServerContext, operation string) *mcp.CallToolResult {
@@ -35,6 +54,10 @@ type PortForwardResponse struct {
 	Namespace    string        `json:"namespace"`
 	PortMappings []PortMapping `json:"portMappings"`
 	Instructions string        `json:"instructions"`
+
+	// ReplicaID is the server replica holding the session. Later calls
+	// about the session must reach the same replica.
+	ReplicaID string `json:"replicaId,omitempty"`
 }
 
 // PortMapping represents a single port mapping
@@ -72,12 +95,12 @@ func handleGetLogs(ctx context.Context, request mcp.CallToolRequest, sc *server.
 
 	namespace, ok := args["namespace"].(string)
 	if !ok || namespace == "" {
-		return mcp.NewToolResultError("namespace is required"), nil
+		return toolerrors.Required("namespace").Result(), nil
 	}
 
 	podName, ok := args["podName"].(string)
 	if !ok || podName == "" {
-		return mcp.NewToolResultError("podName is required"), nil
+		return toolerrors.Required("podName").Result(), nil
 	}
 
 	containerName, _ := args["containerName"].(string)
@@ -92,7 +115,7 @@ func handleGetLogs(ctx context.Context, request mcp.CallToolRequest, sc *server.
 		if tailLinesFloat, ok := tailLinesVal.(float64); ok {
 			val := int64(tailLinesFloat)
 			if val < 1 || val > 1000 {
-				return mcp.NewToolResultError("tailLines must be between 1 and 1000"), nil
+				return toolerrors.InvalidArgument("tailLines must be between 1 and 1000").Result(), nil
 			}
 			tailLines = &val
 		}
@@ -102,7 +125,7 @@ func handleGetLogs(ctx context.Context, request mcp.CallToolRequest, sc *server.
 	if sinceTimeVal, ok := args["sinceTime"].(string); ok && sinceTimeVal != "" {
 		t, err := time.Parse(time.RFC3339, sinceTimeVal)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid sinceTime: %v (expected RFC3339, e.g. 2026-04-29T10:00:00Z)", err)), nil
+			return toolerrors.InvalidArgumentf("invalid sinceTime: %v (expected RFC3339, e.g. 2026-04-29T10:00:00Z)", err).Result(), nil
 		}
 		sinceTime = &t
 	}
@@ -116,9 +139,9 @@ func handleGetLogs(ctx context.Context, request mcp.CallToolRequest, sc *server.
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
@@ -128,7 +151,7 @@ func handleGetLogs(ctx context.Context, request mcp.CallToolRequest, sc *server.
 
 	if err != nil {
 		recordPodOperation(ctx, sc, instrumentation.OperationLogs, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to get logs", err, client.User())), nil
+		return tools.K8sError("Failed to get logs", err, client.User()).Result(), nil
 	}
 	defer func() { _ = logs.Close() }()
 
@@ -136,10 +159,10 @@ func handleGetLogs(ctx context.Context, request mcp.CallToolRequest, sc *server.
 
 	logData, err := io.ReadAll(logs)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to read logs: %v", err)), nil
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to read logs: %v", err)).Result(), nil
 	}
 
-	return mcp.NewToolResultText(string(logData)), nil
+	return mcp.NewToolResultText(scrubCredentials(ctx, sc, instrumentation.OperationLogs, string(logData))), nil
 }
 
 // handleExec handles kubectl exec operations.
@@ -160,19 +183,19 @@ func handleExec(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 
 	namespace, ok := args["namespace"].(string)
 	if !ok || namespace == "" {
-		return mcp.NewToolResultError("namespace is required"), nil
+		return toolerrors.Required("namespace").Result(), nil
 	}
 
 	podName, ok := args["podName"].(string)
 	if !ok || podName == "" {
-		return mcp.NewToolResultError("podName is required"), nil
+		return toolerrors.Required("podName").Result(), nil
 	}
 
 	containerName, _ := args["containerName"].(string)
 
 	commandInterface, ok := args["command"]
 	if !ok || commandInterface == nil {
-		return mcp.NewToolResultError("command is required"), nil
+		return toolerrors.Required("command").Result(), nil
 	}
 
 	// Convert command interface to []string
@@ -184,23 +207,26 @@ func handleExec(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 			}
 		}
 	} else {
-		return mcp.NewToolResultError("command must be an array of strings"), nil
+		return toolerrors.InvalidArgument("command must be an array of strings").Result(), nil
 	}
 
 	if len(command) == 0 {
-		return mcp.NewToolResultError("command cannot be empty"), nil
+		return toolerrors.InvalidArgument("command cannot be empty").Result(), nil
 	}
 
 	tty, _ := args["tty"].(bool)
 
+	var stdout, stderr bytes.Buffer
 	opts := k8s.ExecOptions{
-		TTY: tty,
+		Stdout: &stdout,
+		Stderr: &stderr,
+		TTY:    tty,
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
@@ -210,19 +236,27 @@ func handleExec(ctx context.Context, request mcp.CallToolRequest, sc *server.Ser
 
 	if err != nil {
 		recordPodOperation(ctx, sc, instrumentation.OperationExec, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to execute command", err, client.User())), nil
+		return tools.K8sError("Failed to execute command", err, client.User()).Result(), nil
 	}
 
 	recordPodOperation(ctx, sc, instrumentation.OperationExec, namespace, instrumentation.StatusSuccess, duration)
 
+	// The client streams into the writers; some clients fill the result instead.
+	if result.Stdout == "" {
+		result.Stdout = stdout.String()
+	}
+	if result.Stderr == "" {
+		result.Stderr = stderr.String()
+	}
+
 	// Format the result
 	var output strings.Builder
 	fmt.Fprintf(&output, "Exit Code: %d\n", result.ExitCode)
 	if result.Stdout != "" {
-		fmt.Fprintf(&output, "Stdout:\n%s\n", result.Stdout)
+		fmt.Fprintf(&output, "Stdout:\n%s\n", scrubCredentials(ctx, sc, instrumentation.OperationExec, result.Stdout))
 	}
 	if result.Stderr != "" {
-		fmt.Fprintf(&output, "Stderr:\n%s\n", result.Stderr)
+		fmt.Fprintf(&output, "Stderr:\n%s\n", scrubCredentials(ctx, sc, instrumentation.OperationExec, result.Stderr))
 	}
 
 	return mcp.NewToolResultText(output.String()), nil
@@ -247,7 +281,7 @@ func handlePortForward(ctx context.Context, request mcp.CallToolRequest, sc *ser
 
 	namespace, ok := args["namespace"].(string)
 	if !ok || namespace == "" {
-		return mcp.NewToolResultError("namespace is required"), nil
+		return toolerrors.Required("namespace").Result(), nil
 	}
 
 	// Get resource type (default to "pod" for backward compatibility)
@@ -264,13 +298,13 @@ func handlePortForward(ctx context.Context, request mcp.CallToolRequest, sc *ser
 			resourceName = podName
 			resourceType = defaultResourceTypePod // Ensure it's treated as a pod
 		} else {
-			return mcp.NewToolResultError("resourceName is required"), nil
+			return toolerrors.Required("resourceName").Result(), nil
 		}
 	}
 
 	portsInterface, ok := args["ports"]
 	if !ok || portsInterface == nil {
-		return mcp.NewToolResultError("ports is required"), nil
+		return toolerrors.Required("ports").Result(), nil
 	}
 
 	// Convert ports interface to []string
@@ -282,11 +316,11 @@ func handlePortForward(ctx context.Context, request mcp.CallToolRequest, sc *ser
 			}
 		}
 	} else {
-		return mcp.NewToolResultError("ports must be an array of strings"), nil
+		return toolerrors.InvalidArgument("ports must be an array of strings").Result(), nil
 	}
 
 	if len(ports) == 0 {
-		return mcp.NewToolResultError("ports cannot be empty"), nil
+		return toolerrors.InvalidArgument("ports cannot be empty").Result(), nil
 	}
 
 	opts := k8s.PortForwardOptions{}
@@ -300,9 +334,9 @@ func handlePortForward(ctx context.Context, request mcp.CallToolRequest, sc *ser
 	defer setupCancel()
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
@@ -311,23 +345,24 @@ func handlePortForward(ctx context.Context, request mcp.CallToolRequest, sc *ser
 	case defaultResourceTypePod:
 		session, err = k8sClient.PortForward(setupCtx, kubeContext, namespace, resourceName, ports, opts)
 		if err != nil {
-			return mcp.NewToolResultError(tools.FormatK8sError("Failed to setup port forwarding to pod", err, client.User())), nil
+			return tools.K8sError("Failed to setup port forwarding to pod", err, client.User()).Result(), nil
 		}
 		sessionID = fmt.Sprintf("%s/%s:%s", namespace, resourceName, strings.Join(ports, ","))
 
 	case "service":
 		session, err = k8sClient.PortForwardToService(setupCtx, kubeContext, namespace, resourceName, ports, opts)
 		if err != nil {
-			return mcp.NewToolResultError(tools.FormatK8sError("Failed to setup port forwarding to service", err, client.User())), nil
+			return tools.K8sError("Failed to setup port forwarding to service", err, client.User()).Result(), nil
 		}
 		sessionID = fmt.Sprintf("%s/service/%s:%s", namespace, resourceName, strings.Join(ports, ","))
 
 	default:
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid resource type: %s. Must be 'pod' or 'service'", resourceType)), nil
+		return toolerrors.InvalidArgumentf("Invalid resource type: %s. Must be 'pod' or 'service'", resourceType).Result(), nil
 	}
 
 	// Register the session for cleanup during shutdown
 	sc.RegisterPortForwardSession(sessionID, session)
+	registerSharedSession(ctx, sc, sessionID, sessions.KindPortForward)
 
 	// Increment active sessions metric
 	incrementActiveSessions(ctx, sc)
@@ -353,22 +388,25 @@ func handlePortForward(ctx context.Context, request mcp.CallToolRequest, sc *ser
 		Namespace:    namespace,
 		PortMappings: portMappings,
 		Instructions: "This is a long-running session. Use 'list_port_forward_sessions' to view active sessions and 'stop_port_forward_session' to stop this session.",
+		ReplicaID:    sc.ReplicaID(),
 	}
 
 	// Marshal response to JSON
 	jsonResponse, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonResponse)), nil
 }
 
-// handleListPortForwardSessions handles listing all active port forwarding sessions
+// handleListPortForwardSessions handles listing all active port forwarding sessions.
+// With a session registry, sessions held by other replicas are listed too.
 func handleListPortForwardSessions(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
 	sessions := sc.GetActiveSessions()
+	remote := remoteSessions(ctx, sc)
 
-	if len(sessions) == 0 {
+	if len(sessions) == 0 && len(remote) == 0 {
 		return mcp.NewToolResultText("No active port forwarding sessions."), nil
 	}
 
@@ -386,6 +424,13 @@ func handleListPortForwardSessions(ctx context.Context, request mcp.CallToolRequ
 		output.WriteString("\n")
 	}
 
+	if len(remote) > 0 {
+		fmt.Fprintf(&output, "Sessions held by other replicas (%d):\n\n", len(remote))
+		for _, session := range remote {
+			fmt.Fprintf(&output, "Session ID: %s\nReplica: %s\n\n", session.ID, session.Replica)
+		}
+	}
+
 	return mcp.NewToolResultText(output.String()), nil
 }
 
@@ -395,13 +440,17 @@ func handleStopPortForwardSession(ctx context.Context, request mcp.CallToolReque
 
 	sessionID, ok := args["sessionID"].(string)
 	if !ok || sessionID == "" {
-		return mcp.NewToolResultError("sessionID is required"), nil
+		return toolerrors.Required("sessionID").Result(), nil
 	}
 
 	err := sc.StopPortForwardSession(sessionID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to stop session: %v", err)), nil
+		if toolErr := otherReplicaError(ctx, sc, sessionID); toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		return toolerrors.FromError(err, fmt.Sprintf("Failed to stop session: %v", err)).Result(), nil
 	}
+	unregisterSharedSession(ctx, sc, sessionID)
 
 	// Decrement active sessions metric
 	decrementActiveSessions(ctx, sc)
@@ -412,6 +461,7 @@ func handleStopPortForwardSession(ctx context.Context, request mcp.CallToolReque
 // handleStopAllPortForwardSessions handles stopping all active port forwarding sessions
 func handleStopAllPortForwardSessions(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
 	count := sc.StopAllPortForwardSessions()
+	unregisterAllSharedSessions(ctx, sc)
 
 	if count == 0 {
 		return mcp.NewToolResultText("No active port forwarding sessions to stop."), nil
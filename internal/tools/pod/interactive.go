@@ -0,0 +1,594 @@
+package pod
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Interactive sessions keep an exec or attach stream open between tool
+// calls, so that clients without a terminal can drive a shell or REPL: input
+// is written to the process's stdin by session_input and its output is
+// buffered until session_read collects it. The stream lives on the replica
+// that started it, like a port-forward, and is closed when the session is
+// stopped, has been idle too long or reaches its maximum lifetime.
+const (
+	// defaultSessionIdleSeconds is how long a session survives without
+	// input or reads, unless idleTimeoutSeconds says otherwise.
+	defaultSessionIdleSeconds = 600
+
+	// maxSessionIdleSeconds caps idleTimeoutSeconds.
+	maxSessionIdleSeconds = 3600
+
+	// maxSessionLifetime is how long a session lives however busy it is.
+	maxSessionLifetime = 4 * time.Hour
+
+	// maxInteractiveSessions caps the sessions open on one replica.
+	maxInteractiveSessions = 20
+
+	// maxSessionOutputBytes caps the unread output kept per session; the
+	// oldest output is dropped beyond it.
+	maxSessionOutputBytes = 256 << 10
+
+	// maxSessionInputBytes caps the input of one session_input call.
+	maxSessionInputBytes = 64 << 10
+
+	// defaultSessionReadWaitSeconds is how long session_read waits for
+	// output by default, and maxSessionReadWaitSeconds caps it.
+	defaultSessionReadWaitSeconds = 2
+	maxSessionReadWaitSeconds     = 30
+
+	// sessionStartWait is how long session_start waits for the stream to
+	// fail, so that a wrong pod or container is reported by the call itself.
+	sessionStartWait = time.Second
+
+	// sessionSweepInterval is how often expired sessions are closed.
+	sessionSweepInterval = 30 * time.Second
+)
+
+// interactiveSession is an open exec or attach stream.
+type interactiveSession struct {
+	id        string
+	owner     string
+	namespace string
+	pod       string
+	container string
+	command   []string
+	attach    bool
+	tty       bool
+	started   time.Time
+	idle      time.Duration
+
+	cancel context.CancelFunc
+	stdin  *io.PipeWriter
+	done   chan struct{}
+
+	mu       sync.Mutex
+	lastUsed time.Time
+	output   []byte
+	dropped  int
+	exited   bool
+	exitCode int
+	err      error
+	stopped  bool
+
+	// changed is closed, and replaced, when output arrives or the process
+	// exits.
+	changed chan struct{}
+}
+
+// Write buffers output of the process. It never fails, so a client that
+// does not read cannot stall the stream; output beyond
+// maxSessionOutputBytes drops the oldest bytes instead.
+func (s *interactiveSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = append(s.output, p...)
+	if excess := len(s.output) - maxSessionOutputBytes; excess > 0 {
+		s.output = append([]byte(nil), s.output[excess:]...)
+		s.dropped += excess
+	}
+	s.notify()
+	return len(p), nil
+}
+
+// notify wakes up readers waiting for output. s.mu must be held.
+func (s *interactiveSession) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// finish records how the stream ended.
+func (s *interactiveSession) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exited = true
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		s.exitCode = exitErr.ExitStatus()
+	case !s.stopped:
+		s.err = err
+	}
+	s.notify()
+	close(s.done)
+}
+
+// stop closes the stream. Output not yet read is discarded.
+func (s *interactiveSession) stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	_ = s.stdin.Close()
+	s.cancel()
+}
+
+// touch marks the session as used now.
+func (s *interactiveSession) touch(now time.Time) {
+	s.mu.Lock()
+	s.lastUsed = now
+	s.mu.Unlock()
+}
+
+// expired reports whether the session has been idle too long or reached its
+// maximum lifetime.
+func (s *interactiveSession) expired(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Sub(s.lastUsed) > s.idle || now.Sub(s.started) > maxSessionLifetime
+}
+
+// read waits up to wait for output or the end of the process, then returns
+// and consumes the buffered output.
+func (s *interactiveSession) read(ctx context.Context, wait time.Duration) SessionOutput {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		if len(s.output) > 0 || s.exited || wait <= 0 {
+			break
+		}
+		changed := s.changed
+		s.mu.Unlock()
+		select {
+		case <-changed:
+		case <-timer.C:
+			wait = 0
+		case <-ctx.Done():
+			wait = 0
+		}
+	}
+	defer s.mu.Unlock()
+
+	out := SessionOutput{
+		SessionID:    s.id,
+		Output:       string(s.output),
+		DroppedBytes: s.dropped,
+		Exited:       s.exited,
+	}
+	if s.exited {
+		if s.err != nil {
+			out.Error = s.err.Error()
+		} else {
+			code := s.exitCode
+			out.ExitCode = &code
+		}
+	}
+	s.output = nil
+	s.dropped = 0
+	return out
+}
+
+// info describes the session for session_start and session_list.
+func (s *interactiveSession) info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expires := s.lastUsed.Add(s.idle)
+	if limit := s.started.Add(maxSessionLifetime); limit.Before(expires) {
+		expires = limit
+	}
+	return SessionInfo{
+		SessionID: s.id,
+		Namespace: s.namespace,
+		PodName:   s.pod,
+		Container: s.container,
+		Command:   s.command,
+		Attach:    s.attach,
+		TTY:       s.tty,
+		StartedAt: s.started,
+		ExpiresAt: expires,
+		Exited:    s.exited,
+	}
+}
+
+// SessionInfo describes an interactive session.
+type SessionInfo struct {
+	SessionID string    `json:"sessionId"`
+	Namespace string    `json:"namespace"`
+	PodName   string    `json:"podName"`
+	Container string    `json:"container,omitempty"`
+	Command   []string  `json:"command,omitempty"`
+	Attach    bool      `json:"attach,omitempty"`
+	TTY       bool      `json:"tty,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+
+	// ExpiresAt is when the session is closed unless it is used before.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	Exited bool `json:"exited,omitempty"`
+}
+
+// SessionStartResponse is the response of the session_start tool.
+type SessionStartResponse struct {
+	SessionInfo
+	ReplicaID    string `json:"replicaId,omitempty"`
+	Instructions string `json:"instructions"`
+}
+
+// SessionOutput is the response of the session_read tool.
+type SessionOutput struct {
+	SessionID string `json:"sessionId"`
+
+	// Output is stdout and stderr written since the last read, interleaved.
+	Output string `json:"output"`
+
+	// DroppedBytes counts output lost because it was not read in time.
+	DroppedBytes int `json:"droppedBytes,omitempty"`
+
+	// Exited is set once the process has ended, with its exit code, or
+	// Error if the stream failed. Output may still follow until it is read.
+	Exited   bool   `json:"exited"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// sessionManager holds the interactive sessions of this replica. Its
+// methods are the handlers of the session tools.
+type sessionManager struct {
+	sc        *server.ServerContext
+	now       func() time.Time
+	startWait time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*interactiveSession
+}
+
+// newSessionManager returns a manager closing expired sessions until the
+// server context is done, when it closes them all.
+func newSessionManager(sc *server.ServerContext) *sessionManager {
+	m := &sessionManager{
+		sc:        sc,
+		now:       time.Now,
+		startWait: sessionStartWait,
+		sessions:  make(map[string]*interactiveSession),
+	}
+	go m.sweep(sc.Context())
+	return m
+}
+
+// sweep closes expired sessions every sessionSweepInterval.
+func (m *sessionManager) sweep(ctx context.Context) {
+	ticker := time.NewTicker(sessionSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.expire()
+		case <-ctx.Done():
+			m.mu.Lock()
+			for id, session := range m.sessions {
+				session.stop()
+				delete(m.sessions, id)
+			}
+			m.mu.Unlock()
+			return
+		}
+	}
+}
+
+// expire closes the sessions that have expired.
+func (m *sessionManager) expire() {
+	now := m.now()
+	var expired []string
+	m.mu.Lock()
+	for id, session := range m.sessions {
+		if session.expired(now) {
+			session.stop()
+			delete(m.sessions, id)
+			expired = append(expired, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range expired {
+		m.sc.Logger().Info("Interactive session expired", "sessionID", id)
+		unregisterSharedSession(context.Background(), m.sc, id)
+	}
+}
+
+// lookup returns the caller's session. Sessions of other users are reported
+// as not found.
+func (m *sessionManager) lookup(ctx context.Context, sessionID string) (*interactiveSession, *toolerrors.Error) {
+	m.mu.Lock()
+	session, ok := m.sessions[sessionID]
+	m.mu.Unlock()
+	if ok && session.owner == tools.CallerIdentity(ctx) {
+		return session, nil
+	}
+	if toolErr := otherReplicaError(ctx, m.sc, sessionID); toolErr != nil {
+		return nil, toolErr
+	}
+	return nil, toolerrors.New(toolerrors.CodeNotFound, fmt.Sprintf("session %s not found; it may have expired", sessionID))
+}
+
+// newSessionID returns a random session ID.
+func newSessionID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "session-" + hex.EncodeToString(b), nil
+}
+
+// handleStart starts an exec or attach stream and returns its session ID.
+// Like exec, it is blocked in non-destructive mode unless explicitly allowed.
+func (m *sessionManager) handleStart(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "exec"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+	kubeContext, _ := args["kubeContext"].(string)
+
+	session := &interactiveSession{idle: defaultSessionIdleSeconds * time.Second}
+	session.namespace, _ = args["namespace"].(string)
+	if session.namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	session.pod, _ = args["podName"].(string)
+	if session.pod == "" {
+		return toolerrors.Required("podName").Result(), nil
+	}
+	session.container, _ = args["containerName"].(string)
+	session.attach, _ = args["attach"].(bool)
+	session.tty, _ = args["tty"].(bool)
+
+	if commandInterface, ok := args["command"]; ok && commandInterface != nil {
+		commandSlice, ok := commandInterface.([]interface{})
+		if !ok {
+			return toolerrors.InvalidArgument("command must be an array of strings").Result(), nil
+		}
+		for _, item := range commandSlice {
+			if str, ok := item.(string); ok {
+				session.command = append(session.command, str)
+			}
+		}
+	}
+	switch {
+	case session.attach && len(session.command) > 0:
+		return toolerrors.InvalidArgument("command cannot be given with attach").Result(), nil
+	case !session.attach && len(session.command) == 0:
+		return toolerrors.Required("command").Result(), nil
+	}
+
+	if v, ok := args["idleTimeoutSeconds"].(float64); ok {
+		if v < 1 || v > maxSessionIdleSeconds {
+			return toolerrors.InvalidArgumentf("idleTimeoutSeconds must be between 1 and %d", maxSessionIdleSeconds).Result(), nil
+		}
+		session.idle = time.Duration(v) * time.Second
+	}
+
+	m.mu.Lock()
+	open := len(m.sessions)
+	m.mu.Unlock()
+	if open >= maxInteractiveSessions {
+		return toolerrors.Newf(toolerrors.CodeFailedPrecondition,
+			"%d interactive sessions are open on this replica, the maximum; stop one with session_stop", open).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		return toolerrors.Internalf("Failed to create session ID: %v", err).Result(), nil
+	}
+	session.id = id
+	session.owner = tools.CallerIdentity(ctx)
+	session.started = m.now()
+	session.lastUsed = session.started
+	session.done = make(chan struct{})
+	session.changed = make(chan struct{})
+
+	// The stream outlives this call; it ends when the session is stopped
+	// or the server shuts down.
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stopOnShutdown := context.AfterFunc(sc.Context(), cancel)
+	session.cancel = cancel
+
+	stdin, stdinWriter := io.Pipe()
+	session.stdin = stdinWriter
+	opts := k8s.ExecOptions{Stdin: stdin, Stdout: session, TTY: session.tty, Attach: session.attach}
+	if !session.tty {
+		// A TTY merges stderr into stdout.
+		opts.Stderr = session
+	}
+
+	go func() {
+		start := time.Now()
+		_, err := client.K8s().Exec(streamCtx, kubeContext, session.namespace, session.pod, session.container, session.command, opts)
+		status := instrumentation.StatusSuccess
+		if err != nil {
+			status = instrumentation.StatusError
+		}
+		recordPodOperation(streamCtx, sc, instrumentation.OperationExec, session.namespace, status, time.Since(start))
+		_ = stdin.Close()
+		session.finish(err)
+		stopOnShutdown()
+		cancel()
+	}()
+
+	select {
+	case <-session.done:
+		session.mu.Lock()
+		err := session.err
+		session.mu.Unlock()
+		if err != nil {
+			return tools.K8sError("Failed to start session", err, client.User()).Result(), nil
+		}
+	case <-time.After(m.startWait):
+	case <-ctx.Done():
+	}
+
+	m.mu.Lock()
+	m.sessions[id] = session
+	m.mu.Unlock()
+	registerSharedSession(ctx, sc, id, sessions.KindInteractive)
+
+	return sessionJSONResult(SessionStartResponse{
+		SessionInfo:  session.info(),
+		ReplicaID:    sc.ReplicaID(),
+		Instructions: "Send input with session_input and collect output with session_read. Stop the session with session_stop when done; it is closed automatically once idle until expiresAt.",
+	})
+}
+
+// handleInput writes to the stdin of a session's process.
+func (m *sessionManager) handleInput(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	sessionID, _ := args["sessionID"].(string)
+	if sessionID == "" {
+		return toolerrors.Required("sessionID").Result(), nil
+	}
+	input, _ := args["input"].(string)
+	newline := true
+	if v, ok := args["newline"].(bool); ok {
+		newline = v
+	}
+	if newline {
+		input += "\n"
+	}
+	if len(input) > maxSessionInputBytes {
+		return toolerrors.InvalidArgumentf("input must be at most %d bytes", maxSessionInputBytes).Result(), nil
+	}
+	eof, _ := args["eof"].(bool)
+
+	session, toolErr := m.lookup(ctx, sessionID)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	session.touch(m.now())
+
+	if input != "" {
+		// The pipe blocks until the stream consumes the input.
+		written := make(chan error, 1)
+		go func() {
+			_, err := io.WriteString(session.stdin, input)
+			written <- err
+		}()
+		select {
+		case err := <-written:
+			if err != nil {
+				return toolerrors.Newf(toolerrors.CodeFailedPrecondition,
+					"Failed to write to session %s: the process no longer reads input (%v)", sessionID, err).Result(), nil
+			}
+		case <-ctx.Done():
+			return toolerrors.Newf(toolerrors.CodeTimeout, "Timed out writing to session %s", sessionID).Result(), nil
+		}
+	}
+	if eof {
+		_ = session.stdin.Close()
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Wrote %d bytes to session %s.", len(input), sessionID)), nil
+}
+
+// handleRead returns the output of a session's process since the last read.
+func (m *sessionManager) handleRead(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	sessionID, _ := args["sessionID"].(string)
+	if sessionID == "" {
+		return toolerrors.Required("sessionID").Result(), nil
+	}
+	wait := defaultSessionReadWaitSeconds * time.Second
+	if v, ok := args["waitSeconds"].(float64); ok {
+		if v < 0 || v > maxSessionReadWaitSeconds {
+			return toolerrors.InvalidArgumentf("waitSeconds must be between 0 and %d", maxSessionReadWaitSeconds).Result(), nil
+		}
+		wait = time.Duration(v * float64(time.Second))
+	}
+
+	session, toolErr := m.lookup(ctx, sessionID)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	session.touch(m.now())
+
+	out := session.read(ctx, wait)
+	out.Output = scrubCredentials(ctx, sc, instrumentation.OperationExec, out.Output)
+	return sessionJSONResult(out)
+}
+
+// handleStop closes a session.
+func (m *sessionManager) handleStop(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	sessionID, _ := request.GetArguments()["sessionID"].(string)
+	if sessionID == "" {
+		return toolerrors.Required("sessionID").Result(), nil
+	}
+
+	session, toolErr := m.lookup(ctx, sessionID)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	m.mu.Lock()
+	delete(m.sessions, sessionID)
+	m.mu.Unlock()
+	session.stop()
+	unregisterSharedSession(ctx, sc, sessionID)
+
+	return mcp.NewToolResultText(fmt.Sprintf("Session %s stopped.", sessionID)), nil
+}
+
+// handleList lists the caller's sessions on this replica.
+func (m *sessionManager) handleList(ctx context.Context, _ mcp.CallToolRequest, _ *server.ServerContext) (*mcp.CallToolResult, error) {
+	owner := tools.CallerIdentity(ctx)
+	list := []SessionInfo{}
+	m.mu.Lock()
+	for _, session := range m.sessions {
+		if session.owner == owner {
+			list = append(list, session.info())
+		}
+	}
+	m.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].StartedAt.Before(list[j].StartedAt) })
+	return sessionJSONResult(list)
+}
+
+// sessionJSONResult returns v as indented JSON.
+func sessionJSONResult(v any) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
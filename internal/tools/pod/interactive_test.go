@@ -0,0 +1,261 @@
+package pod
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/mcp-oauth/handler"
+	"github.com/giantswarm/mcp-oauth/providers"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// replMock runs a REPL for exec and attach: each input line is echoed back
+// as "> line", and "exit N" ends it with exit code N.
+type replMock struct {
+	*testdata.MockK8sClient
+
+	execErr error
+	opts    chan k8s.ExecOptions
+}
+
+func (m *replMock) Exec(ctx context.Context, _, _, _, _ string, _ []string, opts k8s.ExecOptions) (*k8s.ExecResult, error) {
+	m.opts <- opts
+	if m.execErr != nil {
+		return nil, m.execErr
+	}
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(opts.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return &k8s.ExecResult{}, nil
+			}
+			var code int
+			if _, err := fmt.Sscanf(line, "exit %d", &code); err == nil {
+				return nil, utilexec.CodeExitError{Err: errors.New("command terminated"), Code: code}
+			}
+			_, _ = fmt.Fprintf(opts.Stdout, "> %s\n", line)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func newSessionTest(t *testing.T, mock *replMock) (*sessionManager, *server.ServerContext) {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+	m := newSessionManager(sc)
+	m.startWait = 50 * time.Millisecond
+	return m, sc
+}
+
+func newReplMock() *replMock {
+	return &replMock{MockK8sClient: &testdata.MockK8sClient{}, opts: make(chan k8s.ExecOptions, 1)}
+}
+
+func callSession(t *testing.T, ctx context.Context, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), sc *server.ServerContext, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(ctx, request, sc)
+	require.NoError(t, err)
+	return result
+}
+
+func startSession(t *testing.T, ctx context.Context, m *sessionManager, sc *server.ServerContext, args map[string]interface{}) SessionStartResponse {
+	t.Helper()
+	result := callSession(t, ctx, m.handleStart, sc, args)
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	var started SessionStartResponse
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &started))
+	return started
+}
+
+func readSession(t *testing.T, ctx context.Context, m *sessionManager, sc *server.ServerContext, sessionID string) SessionOutput {
+	t.Helper()
+	result := callSession(t, ctx, m.handleRead, sc, map[string]interface{}{"sessionID": sessionID, "waitSeconds": float64(5)})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	var out SessionOutput
+	require.NoError(t, json.Unmarshal([]byte(mcptest.ResultText(result)), &out))
+	return out
+}
+
+func TestInteractiveSession(t *testing.T) {
+	mock := newReplMock()
+	m, sc := newSessionTest(t, mock)
+	ctx := context.Background()
+
+	started := startSession(t, ctx, m, sc, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-0",
+		"command":   []interface{}{"sh"},
+	})
+	assert.Equal(t, []string{"sh"}, started.Command)
+	assert.Equal(t, started.StartedAt.Add(defaultSessionIdleSeconds*time.Second), started.ExpiresAt)
+	opts := <-mock.opts
+	assert.NotNil(t, opts.Stderr, "stderr is captured without a TTY")
+	assert.False(t, opts.Attach)
+
+	result := callSession(t, ctx, m.handleInput, sc, map[string]interface{}{"sessionID": started.SessionID, "input": "echo hi"})
+	require.False(t, result.IsError, mcptest.ResultText(result))
+	out := readSession(t, ctx, m, sc, started.SessionID)
+	assert.Equal(t, "> echo hi\n", out.Output)
+	assert.False(t, out.Exited)
+
+	callSession(t, ctx, m.handleInput, sc, map[string]interface{}{"sessionID": started.SessionID, "input": "exit 3"})
+	out = readSession(t, ctx, m, sc, started.SessionID)
+	for !out.Exited {
+		out = readSession(t, ctx, m, sc, started.SessionID)
+	}
+	require.NotNil(t, out.ExitCode)
+	assert.Equal(t, 3, *out.ExitCode)
+	assert.Empty(t, out.Error)
+
+	result = callSession(t, ctx, m.handleStop, sc, map[string]interface{}{"sessionID": started.SessionID})
+	require.False(t, result.IsError)
+	result = callSession(t, ctx, m.handleRead, sc, map[string]interface{}{"sessionID": started.SessionID})
+	require.True(t, result.IsError)
+	assert.Equal(t, toolerrors.CodeNotFound, toolerrors.FromResult(result).Code)
+}
+
+func TestInteractiveSession_Attach(t *testing.T) {
+	mock := newReplMock()
+	m, sc := newSessionTest(t, mock)
+	ctx := context.Background()
+
+	started := startSession(t, ctx, m, sc, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-0",
+		"attach":    true,
+		"tty":       true,
+	})
+	opts := <-mock.opts
+	assert.True(t, opts.Attach)
+	assert.True(t, opts.TTY)
+	assert.Nil(t, opts.Stderr, "a TTY merges stderr into stdout")
+
+	// Closing stdin ends the process.
+	callSession(t, ctx, m.handleInput, sc, map[string]interface{}{"sessionID": started.SessionID, "input": "", "newline": false, "eof": true})
+	out := readSession(t, ctx, m, sc, started.SessionID)
+	assert.True(t, out.Exited)
+	require.NotNil(t, out.ExitCode)
+	assert.Equal(t, 0, *out.ExitCode)
+}
+
+func TestInteractiveSession_StartFailure(t *testing.T) {
+	mock := newReplMock()
+	mock.execErr = errors.New("pods \"api-0\" not found")
+	m, sc := newSessionTest(t, mock)
+
+	result := callSession(t, context.Background(), m.handleStart, sc, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-0",
+		"command":   []interface{}{"sh"},
+	})
+	require.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "Failed to start session")
+	assert.Empty(t, m.sessions)
+}
+
+func TestInteractiveSession_InvalidArguments(t *testing.T) {
+	m, sc := newSessionTest(t, newReplMock())
+	base := map[string]interface{}{"namespace": "shop", "podName": "api-0"}
+
+	result := callSession(t, context.Background(), m.handleStart, sc, base)
+	require.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "command")
+
+	base["attach"] = true
+	base["command"] = []interface{}{"sh"}
+	result = callSession(t, context.Background(), m.handleStart, sc, base)
+	require.True(t, result.IsError)
+	assert.Contains(t, mcptest.ResultText(result), "command cannot be given with attach")
+}
+
+func TestInteractiveSession_OwnerAndExpiry(t *testing.T) {
+	mock := newReplMock()
+	m, sc := newSessionTest(t, mock)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	alice := handler.ContextWithUserInfo(context.Background(), &providers.UserInfo{Email: "alice@example.com"})
+	bob := handler.ContextWithUserInfo(context.Background(), &providers.UserInfo{Email: "bob@example.com"})
+
+	started := startSession(t, alice, m, sc, map[string]interface{}{
+		"namespace":          "shop",
+		"podName":            "api-0",
+		"command":            []interface{}{"sh"},
+		"idleTimeoutSeconds": float64(60),
+	})
+	<-mock.opts
+
+	// Other users cannot see or use the session.
+	result := callSession(t, bob, m.handleInput, sc, map[string]interface{}{"sessionID": started.SessionID, "input": "id"})
+	require.True(t, result.IsError)
+	assert.Equal(t, toolerrors.CodeNotFound, toolerrors.FromResult(result).Code)
+	assert.Equal(t, "[]", mcptest.ResultText(callSession(t, bob, m.handleList, sc, nil)))
+	assert.Contains(t, mcptest.ResultText(callSession(t, alice, m.handleList, sc, nil)), started.SessionID)
+
+	// Use postpones expiry; idleness expires the session.
+	now = now.Add(50 * time.Second)
+	readSession(t, alice, m, sc, started.SessionID)
+	now = now.Add(50 * time.Second)
+	m.expire()
+	assert.Len(t, m.sessions, 1)
+
+	now = now.Add(61 * time.Second)
+	m.expire()
+	assert.Empty(t, m.sessions)
+}
+
+func TestInteractiveSession_BearerOwners(t *testing.T) {
+	mock := newReplMock()
+	m, sc := newSessionTest(t, mock)
+
+	// Under --auth-mode static-token or tokenreview there is no OAuth user.
+	alice := middleware.ContextWithAuthenticatedSubject(context.Background(), "system:serviceaccount:ops:alice")
+	bob := middleware.ContextWithAuthenticatedSubject(context.Background(), "system:serviceaccount:ops:bob")
+
+	started := startSession(t, alice, m, sc, map[string]interface{}{
+		"namespace": "shop",
+		"podName":   "api-0",
+		"command":   []interface{}{"sh"},
+	})
+	<-mock.opts
+
+	result := callSession(t, bob, m.handleInput, sc, map[string]interface{}{"sessionID": started.SessionID, "input": "id"})
+	require.True(t, result.IsError)
+	assert.Equal(t, toolerrors.CodeNotFound, toolerrors.FromResult(result).Code)
+	result = callSession(t, bob, m.handleRead, sc, map[string]interface{}{"sessionID": started.SessionID})
+	require.True(t, result.IsError)
+	assert.Equal(t, "[]", mcptest.ResultText(callSession(t, bob, m.handleList, sc, nil)))
+	assert.Contains(t, mcptest.ResultText(callSession(t, alice, m.handleList, sc, nil)), started.SessionID)
+}
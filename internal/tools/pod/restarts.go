@@ -0,0 +1,392 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// defaultRestartsWindowMinutes is the default window of the restarts
+	// tool.
+	defaultRestartsWindowMinutes = 60
+
+	// maxRestartsWindowMinutes caps the sinceMinutes argument at a week.
+	maxRestartsWindowMinutes = 7 * 24 * 60
+
+	// defaultRestartsLimit is the default cap on the workloads returned.
+	defaultRestartsLimit = 20
+
+	// maxRestartsLimit caps the limit argument.
+	maxRestartsLimit = 500
+
+	// restartsListPageSize is the page size used to list pods and events.
+	restartsListPageSize = 500
+)
+
+// Severities reported by the restarts tool, most severe first.
+const (
+	// SeverityCritical means a container is crash-looping now or was
+	// OOM-killed in the window.
+	SeverityCritical = "critical"
+
+	// SeverityWarning means containers restarted in the window.
+	SeverityWarning = "warning"
+)
+
+// RestartsOutput is the response of the restarts tool.
+type RestartsOutput struct {
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	SinceMinutes  int    `json:"sinceMinutes"`
+	PodsScanned   int    `json:"podsScanned"`
+
+	// Workloads are ranked by severity, then by crash-looping containers,
+	// OOM kills and restarts in the window.
+	Workloads      []WorkloadRestarts `json:"workloads"`
+	TotalWorkloads int                `json:"totalWorkloads"`
+	Truncated      bool               `json:"truncated,omitempty"`
+
+	// Unavailable lists the details that could not be fetched, such as
+	// events the user may not read.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// WorkloadRestarts aggregates the restarts of the pods of one workload.
+type WorkloadRestarts struct {
+	Namespace string `json:"namespace"`
+
+	// Workload is the pods' controller, such as Deployment/web; a pod
+	// without one is reported as Pod/name.
+	Workload string `json:"workload"`
+	Severity string `json:"severity"`
+	Pods     int    `json:"pods"`
+
+	// Restarts is the restart count of the current pods over their
+	// lifetime; the other counts are limited to the window.
+	Restarts       int32 `json:"restarts"`
+	RecentRestarts int   `json:"recentRestarts"`
+	OOMKills       int   `json:"oomKills"`
+	CrashLooping   int   `json:"crashLooping"`
+	BackOffEvents  int32 `json:"backOffEvents"`
+
+	// Containers names the containers that restarted in the window.
+	Containers []string `json:"containers"`
+
+	// LastRestart is when a container of the workload last terminated,
+	// with the reason and exit code.
+	LastRestart  string `json:"lastRestart,omitempty"`
+	LastReason   string `json:"lastReason,omitempty"`
+	LastExitCode *int32 `json:"lastExitCode,omitempty"`
+
+	// WorstPod is the pod to pass to diagnose_pod.
+	WorstPod string `json:"worstPod"`
+}
+
+// restartsRequest holds the validated restarts tool arguments.
+type restartsRequest struct {
+	kubeContext   string
+	namespace     string
+	labelSelector string
+	sinceMinutes  int
+	limit         int
+	now           time.Time
+}
+
+// workloadRestarts accumulates one workload while scanning.
+type workloadRestarts struct {
+	out        WorkloadRestarts
+	containers map[string]bool
+	lastAt     time.Time
+	// worstScore ranks the pods of the workload to pick WorstPod.
+	worstScore int
+}
+
+// handleRestarts aggregates container restarts, OOM kills and
+// CrashLoopBackOffs per workload.
+func handleRestarts(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req, errMsg := parseRestartsRequest(args)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	out, err := aggregateRestarts(ctx, client.K8s(), req)
+	if err != nil {
+		return tools.K8sError("Failed to list pods", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal restarts: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseRestartsRequest validates the tool arguments.
+func parseRestartsRequest(args map[string]interface{}) (restartsRequest, string) {
+	req := restartsRequest{
+		sinceMinutes: defaultRestartsWindowMinutes,
+		limit:        defaultRestartsLimit,
+		now:          time.Now(),
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+
+	if selector, _ := args["labelSelector"].(string); selector != "" {
+		if _, err := labels.Parse(selector); err != nil {
+			return req, fmt.Sprintf("invalid labelSelector: %v", err)
+		}
+		req.labelSelector = selector
+	}
+	if v, ok := args["sinceMinutes"].(float64); ok {
+		if v < 1 || v > maxRestartsWindowMinutes {
+			return req, fmt.Sprintf("sinceMinutes must be between 1 and %d", maxRestartsWindowMinutes)
+		}
+		req.sinceMinutes = int(v)
+	}
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > maxRestartsLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", maxRestartsLimit)
+		}
+		req.limit = int(v)
+	}
+	return req, ""
+}
+
+// aggregateRestarts lists the selected pods and their back-off events and
+// groups them by workload. Only workloads with trouble in the window are
+// reported.
+func aggregateRestarts(ctx context.Context, client k8s.Client, req restartsRequest) (*RestartsOutput, error) {
+	out := &RestartsOutput{
+		Namespace:     req.namespace,
+		LabelSelector: req.labelSelector,
+		SinceMinutes:  req.sinceMinutes,
+		Workloads:     []WorkloadRestarts{},
+	}
+	since := req.now.Add(-time.Duration(req.sinceMinutes) * time.Minute)
+
+	workloads := map[string]*workloadRestarts{}
+	podWorkload := map[types.UID]*workloadRestarts{}
+	podKeys := map[string]*workloadRestarts{}
+
+	opts := k8s.ListOptions{LabelSelector: req.labelSelector, AllNamespaces: req.namespace == ""}
+	err := listEach(ctx, client, req.kubeContext, req.namespace, "pods", opts, func(pod *corev1.Pod) {
+		out.PodsScanned++
+		name := podWorkloadName(pod)
+		key := pod.Namespace + "/" + name
+		w, ok := workloads[key]
+		if !ok {
+			w = &workloadRestarts{
+				out:        WorkloadRestarts{Namespace: pod.Namespace, Workload: name, Containers: []string{}},
+				containers: map[string]bool{},
+				worstScore: -1,
+			}
+			workloads[key] = w
+		}
+		w.addPod(pod, since)
+		podWorkload[pod.UID] = w
+		podKeys[pod.Namespace+"/"+pod.Name] = w
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Back-off events count how often the kubelet delayed a restart, which
+	// the container statuses only show for the current state.
+	eventSelector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+		fields.OneTermEqualSelector("reason", "BackOff"),
+	).String()
+	err = listEach(ctx, client, req.kubeContext, req.namespace, "events", k8s.ListOptions{FieldSelector: eventSelector, AllNamespaces: req.namespace == ""}, func(ev *corev1.Event) {
+		if !strings.Contains(ev.Message, "restarting failed container") || eventTime(ev).Before(since) {
+			return
+		}
+		w := podKeys[ev.InvolvedObject.Namespace+"/"+ev.InvolvedObject.Name]
+		if ev.InvolvedObject.UID != "" {
+			if byUID, ok := podWorkload[ev.InvolvedObject.UID]; ok {
+				w = byUID
+			} else {
+				// The event is about an earlier pod with the same name.
+				w = nil
+			}
+		}
+		if w == nil {
+			return
+		}
+		count := ev.Count
+		if count == 0 {
+			count = 1
+		}
+		w.out.BackOffEvents += count
+	})
+	if err != nil {
+		out.Unavailable = append(out.Unavailable, fmt.Sprintf("events: %v", err))
+	}
+
+	var results []WorkloadRestarts
+	for _, w := range workloads {
+		switch {
+		case w.out.CrashLooping > 0 || w.out.OOMKills > 0:
+			w.out.Severity = SeverityCritical
+		case w.out.RecentRestarts > 0 || w.out.BackOffEvents > 0:
+			w.out.Severity = SeverityWarning
+		default:
+			continue
+		}
+		if !w.lastAt.IsZero() {
+			w.out.LastRestart = w.lastAt.UTC().Format(time.RFC3339)
+		}
+		sort.Strings(w.out.Containers)
+		results = append(results, w.out)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Severity != b.Severity {
+			return a.Severity == SeverityCritical
+		}
+		if a.CrashLooping != b.CrashLooping {
+			return a.CrashLooping > b.CrashLooping
+		}
+		if a.OOMKills != b.OOMKills {
+			return a.OOMKills > b.OOMKills
+		}
+		if a.RecentRestarts+int(a.BackOffEvents) != b.RecentRestarts+int(b.BackOffEvents) {
+			return a.RecentRestarts+int(a.BackOffEvents) > b.RecentRestarts+int(b.BackOffEvents)
+		}
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload < b.Workload
+	})
+
+	out.TotalWorkloads = len(results)
+	if len(results) > req.limit {
+		results = results[:req.limit]
+		out.Truncated = true
+	}
+	out.Workloads = append(out.Workloads, results...)
+	return out, nil
+}
+
+// addPod adds the container statuses of one pod of the workload.
+func (w *workloadRestarts) addPod(pod *corev1.Pod, since time.Time) {
+	w.out.Pods++
+	score := 0
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		w.out.Restarts += status.RestartCount
+		score += int(status.RestartCount)
+
+		if status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+			w.out.CrashLooping++
+			w.container(status.Name)
+			score += 1000
+		}
+		// The previous instance ended with the last restart.
+		if last := status.LastTerminationState.Terminated; last != nil && !last.FinishedAt.Time.Before(since) {
+			w.out.RecentRestarts++
+			score += 10
+			w.terminated(status.Name, last)
+			if last.Reason == "OOMKilled" {
+				w.out.OOMKills++
+				score += 100
+			}
+		}
+		// A current instance killed for memory is not restarted when the
+		// pod's restart policy is Never.
+		if cur := status.State.Terminated; cur != nil && cur.Reason == "OOMKilled" && !cur.FinishedAt.Time.Before(since) {
+			w.out.OOMKills++
+			score += 100
+			w.terminated(status.Name, cur)
+		}
+	}
+	if score > w.worstScore {
+		w.worstScore = score
+		w.out.WorstPod = pod.Name
+	}
+}
+
+// container records a container that had trouble in the window.
+func (w *workloadRestarts) container(name string) {
+	if !w.containers[name] {
+		w.containers[name] = true
+		w.out.Containers = append(w.out.Containers, name)
+	}
+}
+
+// terminated records a container instance that ended in the window.
+func (w *workloadRestarts) terminated(name string, term *corev1.ContainerStateTerminated) {
+	w.container(name)
+	if term.FinishedAt.After(w.lastAt) {
+		w.lastAt = term.FinishedAt.Time
+		w.out.LastReason = term.Reason
+		w.out.LastExitCode = &term.ExitCode
+	}
+}
+
+// podWorkloadName returns the workload that controls a pod. Pods of a
+// ReplicaSet created by a Deployment are attributed to the Deployment, whose
+// name is the ReplicaSet's without the pod template hash.
+func podWorkloadName(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+				if name, ok := strings.CutSuffix(ref.Name, "-"+hash); ok {
+					return "Deployment/" + name
+				}
+			}
+		}
+		return ref.Kind + "/" + ref.Name
+	}
+	return "Pod/" + pod.Name
+}
+
+// listEach lists every page of a resource type and passes each item,
+// converted to T, to fn.
+func listEach[T any](ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType string, opts k8s.ListOptions, fn func(*T)) error {
+	opts.Limit = restartsListPageSize
+	for {
+		resp, err := client.List(ctx, kubeContext, namespace, resourceType, "", opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			obj := new(T)
+			if err := fromObject(item, obj); err != nil {
+				return err
+			}
+			fn(obj)
+		}
+		if resp.Continue == "" {
+			return nil
+		}
+		opts.Continue = resp.Continue
+	}
+}
@@ -0,0 +1,197 @@
+package pod
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// restartsNow is the time the restarts fixtures are relative to. The tool
+// reads the clock itself, so it is the start of the test run.
+var restartsNow = time.Now().UTC().Truncate(time.Second)
+
+// restartsPod returns a pod of a Deployment's ReplicaSet, or a bare pod
+// when deployment is empty.
+func restartsPod(name, deployment string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, UID: k8stypes.UID("uid-" + name), Labels: map[string]string{"app": "shop"}},
+		Status:     corev1.PodStatus{ContainerStatuses: statuses},
+	}
+	if deployment != "" {
+		controller := true
+		pod.Labels["pod-template-hash"] = "7d9f8"
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: deployment + "-7d9f8", Controller: &controller}}
+	}
+	return pod
+}
+
+func terminated(reason string, exitCode int32, minutesAgo int) *corev1.ContainerStateTerminated {
+	return &corev1.ContainerStateTerminated{
+		Reason:     reason,
+		ExitCode:   exitCode,
+		FinishedAt: metav1.NewTime(restartsNow.Add(-time.Duration(minutesAgo) * time.Minute)),
+	}
+}
+
+// newRestartsClient serves the pods of four Deployments and a bare pod
+// with their back-off events.
+func newRestartsClient() *mcptest.FakeClient {
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"pods": {
+			restartsPod("api-1", "api", corev1.ContainerStatus{
+				Name:         "app",
+				RestartCount: 12,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: terminated("Error", 1, 2),
+				},
+			}),
+			restartsPod("api-2", "api", corev1.ContainerStatus{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}),
+			restartsPod("worker-1", "worker", corev1.ContainerStatus{
+				Name:                 "worker",
+				RestartCount:         3,
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				LastTerminationState: corev1.ContainerState{Terminated: terminated("OOMKilled", 137, 20)},
+			}),
+			restartsPod("cache-1", "cache", corev1.ContainerStatus{
+				Name:                 "redis",
+				RestartCount:         1,
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				LastTerminationState: corev1.ContainerState{Terminated: terminated("Error", 1, 30)},
+			}),
+			restartsPod("old-1", "old", corev1.ContainerStatus{
+				Name:                 "app",
+				RestartCount:         40,
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				LastTerminationState: corev1.ContainerState{Terminated: terminated("Error", 1, 600)},
+			}),
+			restartsPod("debug", "", corev1.ContainerStatus{
+				Name:  "shell",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}),
+		},
+		"events": {
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "api-1.1"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-1", UID: "uid-api-1"},
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container app in pod api-1",
+				Count:          8,
+				LastTimestamp:  metav1.NewTime(restartsNow.Add(-time.Minute)),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "api-1.0"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-1", UID: "uid-earlier-pod"},
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container app in pod api-1",
+				Count:          50,
+				LastTimestamp:  metav1.NewTime(restartsNow.Add(-time.Minute)),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: "old-1.0"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "old-1", UID: "uid-old-1"},
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container app in pod old-1",
+				Count:          9,
+				LastTimestamp:  metav1.NewTime(restartsNow.Add(-5 * time.Hour)),
+			},
+		},
+	})
+}
+
+func TestRestarts(t *testing.T) {
+	client := newRestartsClient()
+	out := mcptest.DecodeResult[RestartsOutput](t, callTool(t, client, "restarts", map[string]interface{}{"namespace": "shop"}))
+
+	assert.Equal(t, 6, out.PodsScanned)
+	assert.Equal(t, 3, out.TotalWorkloads, "workloads without restarts in the window are left out")
+	require.Len(t, out.Workloads, 3)
+
+	api := out.Workloads[0]
+	assert.Equal(t, "Deployment/api", api.Workload)
+	assert.Equal(t, SeverityCritical, api.Severity)
+	assert.Equal(t, 2, api.Pods)
+	assert.Equal(t, int32(12), api.Restarts)
+	assert.Equal(t, 1, api.CrashLooping)
+	assert.Equal(t, 1, api.RecentRestarts)
+	assert.Equal(t, int32(8), api.BackOffEvents, "events of an earlier pod with the same name are ignored")
+	assert.Equal(t, []string{"app"}, api.Containers)
+	assert.Equal(t, "api-1", api.WorstPod)
+	assert.Equal(t, "Error", api.LastReason)
+	assert.Equal(t, restartsNow.Add(-2*time.Minute).Format(time.RFC3339), api.LastRestart)
+
+	worker := out.Workloads[1]
+	assert.Equal(t, "Deployment/worker", worker.Workload)
+	assert.Equal(t, SeverityCritical, worker.Severity)
+	assert.Equal(t, 1, worker.OOMKills)
+	require.NotNil(t, worker.LastExitCode)
+	assert.Equal(t, int32(137), *worker.LastExitCode)
+
+	cache := out.Workloads[2]
+	assert.Equal(t, "Deployment/cache", cache.Workload)
+	assert.Equal(t, SeverityWarning, cache.Severity)
+
+	assert.False(t, client.ListOpts["pods"][0].AllNamespaces)
+	assert.Equal(t, "involvedObject.kind=Pod,reason=BackOff", client.ListOpts["events"][0].FieldSelector)
+}
+
+func TestRestarts_WindowAndLimit(t *testing.T) {
+	client := newRestartsClient()
+	client.Errs["events"] = errors.New("events is forbidden")
+	out := mcptest.DecodeResult[RestartsOutput](t, callTool(t, client, "restarts", map[string]interface{}{
+		"sinceMinutes":  float64(720),
+		"limit":         float64(2),
+		"labelSelector": "app=shop",
+	}))
+
+	assert.Equal(t, 4, out.TotalWorkloads, "a wider window includes older restarts")
+	assert.True(t, out.Truncated)
+	require.Len(t, out.Workloads, 2)
+	assert.Equal(t, []string{"events: events is forbidden"}, out.Unavailable)
+	assert.True(t, client.ListOpts["pods"][0].AllNamespaces)
+	assert.Equal(t, "app=shop", client.ListOpts["pods"][0].LabelSelector)
+}
+
+func TestPodWorkloadName(t *testing.T) {
+	controller := true
+	statefulSetPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "db-0",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}},
+	}}
+	assert.Equal(t, "StatefulSet/db", podWorkloadName(statefulSetPod))
+
+	replicaSetPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "rs-abc",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: &controller}},
+	}}
+	assert.Equal(t, "ReplicaSet/rs", podWorkloadName(replicaSetPod), "a ReplicaSet without a pod template hash is not a Deployment's")
+
+	assert.Equal(t, "Pod/lonely", podWorkloadName(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "lonely"}}))
+}
+
+func TestParseRestartsRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "window too long", args: map[string]interface{}{"sinceMinutes": float64(maxRestartsWindowMinutes + 1)}, wantErr: "sinceMinutes must be between"},
+		{name: "limit too small", args: map[string]interface{}{"limit": float64(0)}, wantErr: "limit must be between"},
+		{name: "bad selector", args: map[string]interface{}{"labelSelector": "app in ("}, wantErr: "invalid labelSelector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseRestartsRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Port-forward and interactive sessions are held by the replica that opened
+// them. With a session registry configured, their ownership is shared so
+// that other replicas can tell callers where a session lives. Registry
+// failures are logged and never fail the call: the session itself works
+// regardless.
+
+// registerSharedSession publishes a session of the given kind opened by this
+// replica.
+func registerSharedSession(ctx context.Context, sc *server.ServerContext, sessionID, kind string) {
+	registry := sc.SessionRegistry()
+	if registry == nil {
+		return
+	}
+	if err := registry.Register(ctx, sessionID, kind); err != nil {
+		sc.Logger().Warn("Failed to share session with other replicas", "sessionID", sessionID, "kind", kind, "error", err)
+	}
+}
+
+// unregisterSharedSession removes a session stopped by this replica.
+func unregisterSharedSession(ctx context.Context, sc *server.ServerContext, sessionID string) {
+	registry := sc.SessionRegistry()
+	if registry == nil {
+		return
+	}
+	if err := registry.Unregister(ctx, sessionID); err != nil {
+		sc.Logger().Warn("Failed to remove session from the registry", "sessionID", sessionID, "error", err)
+	}
+}
+
+// unregisterAllSharedSessions removes all sessions of this replica.
+func unregisterAllSharedSessions(ctx context.Context, sc *server.ServerContext) {
+	registry := sc.SessionRegistry()
+	if registry == nil {
+		return
+	}
+	if err := registry.UnregisterAll(ctx); err != nil {
+		sc.Logger().Warn("Failed to remove port forward sessions from the registry", "error", err)
+	}
+}
+
+// remoteSessions returns the port-forward sessions held by other replicas.
+func remoteSessions(ctx context.Context, sc *server.ServerContext) []sessions.Session {
+	registry := sc.SessionRegistry()
+	if registry == nil {
+		return nil
+	}
+	all, err := registry.List(ctx)
+	if err != nil {
+		sc.Logger().Warn("Failed to list port forward sessions of other replicas", "error", err)
+		return nil
+	}
+	var remote []sessions.Session
+	for _, session := range all {
+		if session.Kind == sessions.KindPortForward && session.Replica != registry.ReplicaID() {
+			remote = append(remote, session)
+		}
+	}
+	return remote
+}
+
+// otherReplicaError returns a CodeWrongReplica error if the session is
+// held by another replica, or nil.
+func otherReplicaError(ctx context.Context, sc *server.ServerContext, sessionID string) *toolerrors.Error {
+	registry := sc.SessionRegistry()
+	if registry == nil {
+		return nil
+	}
+	session, err := registry.Lookup(ctx, sessionID)
+	if err != nil {
+		sc.Logger().Warn("Failed to look up session", "sessionID", sessionID, "error", err)
+		return nil
+	}
+	if session == nil || session.Replica == registry.ReplicaID() {
+		return nil
+	}
+	return toolerrors.New(toolerrors.CodeWrongReplica, fmt.Sprintf(
+		"session %s is held by replica %s; this call reached replica %s. Route it to %s, e.g. with session affinity on the load balancer",
+		sessionID, session.Replica, registry.ReplicaID(), session.Replica))
+}
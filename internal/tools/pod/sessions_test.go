@@ -0,0 +1,115 @@
+package pod
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// sharedStore is an in-memory sessions.Store shared by simulated replicas.
+type sharedStore struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (s *sharedStore) Set(_ context.Context, key, value string, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	return nil
+}
+
+func (s *sharedStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	return v, ok, nil
+}
+
+func (s *sharedStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *sharedStore) Scan(_ context.Context, _ uint64, pattern string, _ int64) ([]string, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for k := range s.data {
+		if strings.HasPrefix(k, strings.TrimSuffix(pattern, "*")) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, 0, nil
+}
+
+func (s *sharedStore) Close() error { return nil }
+
+// newReplica returns the server context of a replica sharing store.
+func newReplica(t *testing.T, store sessions.Store, replicaID string) *server.ServerContext {
+	t.Helper()
+	registry, err := sessions.NewRegistry(store, "mcp:", replicaID, nil)
+	require.NoError(t, err)
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithReplicaID(replicaID),
+		server.WithSessionRegistry(registry),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+	return sc
+}
+
+func TestPortForwardSessionsAcrossReplicas(t *testing.T) {
+	store := &sharedStore{data: make(map[string]string)}
+	a := newReplica(t, store, "mcp-0")
+	b := newReplica(t, store, "mcp-1")
+
+	ctx := context.Background()
+	const sessionID = "default/web:8080"
+	a.RegisterPortForwardSession(sessionID, &k8s.PortForwardSession{})
+	registerSharedSession(ctx, a, sessionID, sessions.KindPortForward)
+
+	stop := mcp.CallToolRequest{}
+	stop.Params.Arguments = map[string]interface{}{"sessionID": sessionID}
+
+	// The other replica names the owner instead of "not found".
+	result, err := handleStopPortForwardSession(ctx, stop, b)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	toolErr := toolerrors.FromResult(result)
+	require.NotNil(t, toolErr)
+	assert.Equal(t, toolerrors.CodeWrongReplica, toolErr.Code)
+	assert.Contains(t, toolErr.Message, "mcp-0")
+
+	list, err := handleListPortForwardSessions(ctx, mcp.CallToolRequest{}, b)
+	require.NoError(t, err)
+	assert.Contains(t, mcptest.ResultText(list), "Replica: mcp-0")
+
+	// The owner stops it and the record goes away.
+	result, err = handleStopPortForwardSession(ctx, stop, a)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Empty(t, store.data)
+
+	result, err = handleStopPortForwardSession(ctx, stop, b)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.NotEqual(t, toolerrors.CodeWrongReplica, toolerrors.FromResult(result).Code)
+}
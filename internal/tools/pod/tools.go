@@ -60,6 +60,65 @@ func RegisterPodTools(s *mcpserver.MCPServer, sc *server.ServerContext) error {
 	s.AddTool(logsTool, tools.WrapWithAuditLogging("logs", handleGetLogs, sc))
 	tools.MaybeAddDeprecatedAlias(s, sc, "logs", handleGetLogs, logsOpts...)
 
+	// diagnose_pod tool
+	diagnoseOpts := []mcp.ToolOption{
+		mcp.WithDescription("Diagnose a pod in one call. Returns its status and conditions, each container's state, restart count and last termination, the logs of crashed containers' previous instances, recent events, and its node's conditions, together with probable causes such as ImagePullBackOff, OOMKilled, CrashLoopBackOff, Unschedulable, ContainerConfigError, ProbeFailure and NodeNotReady, each with a suggestion."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	diagnoseOpts = append(diagnoseOpts, clusterContextParams...)
+	diagnoseOpts = append(diagnoseOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace where the pod is located"),
+		),
+		mcp.WithString("podName",
+			mcp.Required(),
+			mcp.Description("Name of the pod to diagnose"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Min(0),
+			mcp.Max(maxDiagnoseLogLines),
+			mcp.Description("Log lines to include per crashed container; 0 skips logs. Default: 20. Maximum: 200."),
+		),
+	)
+	diagnoseTool := mcp.NewTool("diagnose_pod", diagnoseOpts...)
+
+	s.AddTool(diagnoseTool, tools.WrapWithAuditLogging("diagnose_pod", handleDiagnosePod, sc))
+
+	// restarts tool
+	restartsOpts := []mcp.ToolOption{
+		mcp.WithDescription("Find the noisiest workloads without listing every pod. Aggregates container restart counts, OOM kills, containers in CrashLoopBackOff and back-off events over a recent window per workload (Deployment, StatefulSet, DaemonSet, Job or bare pod), ranked by severity: critical for crash loops and OOM kills, warning for other restarts. Each workload names its worst pod to pass to diagnose_pod."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	restartsOpts = append(restartsOpts, clusterContextParams...)
+	restartsOpts = append(restartsOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to scan (optional, default: all namespaces)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Only include pods matching this label selector, e.g. 'app=web' (optional)"),
+		),
+		mcp.WithNumber("sinceMinutes",
+			mcp.Min(1),
+			mcp.Max(maxRestartsWindowMinutes),
+			mcp.Description("Window in minutes for restarts, OOM kills and events. Default: 60. Maximum: 10080 (a week)."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(maxRestartsLimit),
+			mcp.Description("Maximum number of workloads to return. Default: 20. Maximum: 500."),
+		),
+	)
+	s.AddTool(mcp.NewTool("restarts", restartsOpts...), tools.WrapWithAuditLogging("restarts", handleRestarts, sc))
+
 	// exec tool
 	if tools.IsMutatingOperationAllowed(sc, "exec") {
 		execOpts := []mcp.ToolOption{
@@ -96,6 +155,142 @@ func RegisterPodTools(s *mcpserver.MCPServer, sc *server.ServerContext) error {
 
 		s.AddTool(execTool, tools.WrapWithAuditLogging("exec", handleExec, sc))
 		tools.MaybeAddDeprecatedAlias(s, sc, "exec", handleExec, execOpts...)
+
+		registerSessionTools(s, sc, clusterContextParams)
+	}
+
+	// debug_pod tool
+	if tools.IsMutatingOperationAllowed(sc, "debug") {
+		debugOpts := []mcp.ToolOption{
+			mcp.WithDescription("Add an ephemeral debug container to a running pod, like kubectl debug, for pods whose images have no shell (e.g. distroless). The image must be on the server's debug image allowlist. By default the container runs sleep for ttlSeconds so it can be used with the exec tool; with targetContainer it shares that container's process namespace. Ephemeral containers cannot be removed from a pod."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		debugOpts = append(debugOpts, clusterContextParams...)
+		debugOpts = append(debugOpts,
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace where the pod is located"),
+			),
+			mcp.WithString("podName",
+				mcp.Required(),
+				mcp.Description("Name of the running pod to debug"),
+			),
+			mcp.WithString("image",
+				mcp.Description("Debug image (default: the first allowed debug image)"),
+			),
+			mcp.WithString("targetContainer",
+				mcp.Description("Container whose process namespace the debug container joins (optional)"),
+			),
+			mcp.WithArray("command",
+				mcp.Description("Command to run instead of the default sleep, as an array of strings"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithNumber("ttlSeconds",
+				mcp.Min(1),
+				mcp.Max(maxDebugTTLSeconds),
+				mcp.Description("How long the default sleep command keeps the container alive. Default: 3600. Maximum: 28800."),
+			),
+			mcp.WithNumber("waitSeconds",
+				mcp.Min(0),
+				mcp.Max(maxDebugWaitSeconds),
+				mcp.Description("How long to wait for the container to start. Default: 30. Maximum: 120."),
+			),
+		)
+		debugOpts = append(debugOpts, tools.DryRunParam())
+		debugTool := mcp.NewTool("debug_pod", debugOpts...)
+
+		s.AddTool(debugTool, tools.WrapWithAuditLogging("debug_pod", handleDebugPod, sc))
+	}
+
+	// cp_from_pod and cp_to_pod tools
+	if tools.IsMutatingOperationAllowed(sc, "cp") {
+		cpFromOpts := []mcp.ToolOption{
+			mcp.WithDescription("Copy a file or directory out of a pod container, like kubectl cp. Works over exec, so the container needs tar. The path must be absolute and inside one of the server's allowed copy directories (default /tmp), and the copy is size limited. A text file is returned as text and anything else base64-encoded; a directory is returned as a tar archive. With transport=resource the content is attached as an embedded MCP resource instead."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		cpFromOpts = append(cpFromOpts, clusterContextParams...)
+		cpFromOpts = append(cpFromOpts,
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace where the pod is located"),
+			),
+			mcp.WithString("podName",
+				mcp.Required(),
+				mcp.Description("Name of the pod to copy from"),
+			),
+			mcp.WithString("containerName",
+				mcp.Description("Name of the container (optional for single-container pods)"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Absolute path of the file or directory in the container"),
+			),
+			mcp.WithNumber("maxBytes",
+				mcp.Min(1),
+				mcp.Description("Refuse files larger than this many bytes. Default and maximum: the server's copy limit."),
+			),
+			mcp.WithString("transport",
+				mcp.Description("How to return the content: 'inline' in the JSON response (default) or 'resource' as an embedded MCP resource"),
+				mcp.Enum(copyTransportInline, copyTransportResource),
+			),
+		)
+		cpFromTool := mcp.NewTool("cp_from_pod", cpFromOpts...)
+
+		s.AddTool(cpFromTool, tools.WrapWithAuditLogging("cp_from_pod", handleCopyFromPod, sc))
+
+		cpToOpts := []mcp.ToolOption{
+			mcp.WithDescription("Copy a file into a pod container, like kubectl cp. Works over exec, so the container needs tar. The path must be absolute and inside one of the server's allowed copy directories (default /tmp), and the copy is size limited. An existing file at the path is overwritten."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		cpToOpts = append(cpToOpts, clusterContextParams...)
+		cpToOpts = append(cpToOpts,
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("Namespace where the pod is located"),
+			),
+			mcp.WithString("podName",
+				mcp.Required(),
+				mcp.Description("Name of the pod to copy to"),
+			),
+			mcp.WithString("containerName",
+				mcp.Description("Name of the container (optional for single-container pods)"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Absolute path of the file to write in the container; its directory must exist"),
+			),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("File content, as text or base64 depending on encoding"),
+			),
+			mcp.WithString("encoding",
+				mcp.Description("Encoding of content: 'utf-8' (default) or 'base64' for binary files"),
+				mcp.Enum(encodingUTF8, encodingBase64),
+			),
+			mcp.WithString("mode",
+				mcp.Description("Octal file permissions (default: 0644)"),
+			),
+			mcp.WithNumber("maxBytes",
+				mcp.Min(1),
+				mcp.Description("Refuse content larger than this many bytes. Default and maximum: the server's copy limit."),
+			),
+		)
+		cpToOpts = append(cpToOpts, tools.DryRunParam())
+		cpToTool := mcp.NewTool("cp_to_pod", cpToOpts...)
+
+		s.AddTool(cpToTool, tools.WrapWithAuditLogging("cp_to_pod", handleCopyToPod, sc))
 	}
 
 	// Port forwarding tools are only registered when NOT running in in-cluster mode
@@ -181,3 +376,108 @@ func RegisterPodTools(s *mcpserver.MCPServer, sc *server.ServerContext) error {
 
 	return nil
 }
+
+// registerSessionTools registers the interactive session tools. They are
+// gated alongside exec, whose streams they keep open between calls.
+func registerSessionTools(s *mcpserver.MCPServer, sc *server.ServerContext, clusterContextParams []mcp.ToolOption) {
+	m := newSessionManager(sc)
+	sessionIDParam := mcp.WithString("sessionID",
+		mcp.Required(),
+		mcp.Description("ID returned by session_start"),
+	)
+
+	// session_start tool
+	startOpts := []mcp.ToolOption{
+		mcp.WithDescription("Start an interactive session in a pod container for REPL-style debugging: run a command such as a shell with its stdin kept open, or attach to the container's running process like kubectl attach. Returns a session ID for session_input and session_read. Sessions are held by the replica that started them and closed by session_stop, after idleTimeoutSeconds without use, or after 4 hours."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	startOpts = append(startOpts, clusterContextParams...)
+	startOpts = append(startOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace where the pod is located"),
+		),
+		mcp.WithString("podName",
+			mcp.Required(),
+			mcp.Description("Name of the pod"),
+		),
+		mcp.WithString("containerName",
+			mcp.Description("Name of the container (optional for single-container pods)"),
+		),
+		mcp.WithArray("command",
+			mcp.Description("Command to run as an array of strings, e.g. ['sh']. Required unless attach is set"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("attach",
+			mcp.Description("Attach to the container's running process instead of running a command; input needs a container with stdin enabled (default: false)"),
+		),
+		mcp.WithBoolean("tty",
+			mcp.Description("Allocate a TTY, so shells prompt and echo as in a terminal; stderr is merged into stdout (default: false)"),
+		),
+		mcp.WithNumber("idleTimeoutSeconds",
+			mcp.Min(1),
+			mcp.Max(maxSessionIdleSeconds),
+			mcp.Description("Close the session after this many seconds without input or reads. Default: 600. Maximum: 3600."),
+		),
+	)
+	s.AddTool(mcp.NewTool("session_start", startOpts...), tools.WrapWithAuditLogging("session_start", m.handleStart, sc))
+
+	// session_input tool
+	s.AddTool(mcp.NewTool("session_input",
+		mcp.WithDescription("Write input to the stdin of an interactive session's process. Collect the response with session_read."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		sessionIDParam,
+		mcp.WithString("input",
+			mcp.Description("Text to write, e.g. a command line. Maximum: 64 KiB."),
+		),
+		mcp.WithBoolean("newline",
+			mcp.Description("Append a newline to the input, like pressing enter (default: true)"),
+		),
+		mcp.WithBoolean("eof",
+			mcp.Description("Close stdin after writing, like pressing Ctrl-D, so the process sees end of input (default: false)"),
+		),
+	), tools.WrapWithAuditLogging("session_input", m.handleInput, sc))
+
+	// session_read tool
+	s.AddTool(mcp.NewTool("session_read",
+		mcp.WithDescription("Read the output of an interactive session's process written since the last read, and whether it has exited. Waits up to waitSeconds for output to arrive. Unread output beyond 256 KiB is dropped, oldest first."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		sessionIDParam,
+		mcp.WithNumber("waitSeconds",
+			mcp.Min(0),
+			mcp.Max(maxSessionReadWaitSeconds),
+			mcp.Description("How long to wait for output when there is none yet. Default: 2. Maximum: 30."),
+		),
+	), tools.WrapWithAuditLogging("session_read", m.handleRead, sc))
+
+	// session_stop tool
+	s.AddTool(mcp.NewTool("session_stop",
+		mcp.WithDescription("Stop an interactive session, closing its stream and discarding unread output. An attached process keeps running."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		sessionIDParam,
+	), tools.WrapWithAuditLogging("session_stop", m.handleStop, sc))
+
+	// session_list tool
+	s.AddTool(mcp.NewTool("session_list",
+		mcp.WithDescription("List your interactive sessions on this replica"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	), tools.WrapWithAuditLogging("session_list", m.handleList, sc))
+}
@@ -23,12 +23,16 @@ var (
 	// non-destructive mode.
 	readOnlyPodTools = []string{
 		"logs",
+		"diagnose_pod",
 	}
 	// mutatingPodTools are pod tools gated by IsMutatingOperationAllowed —
 	// registered only when non-destructive mode is off, dry-run is on, or the
 	// operation is whitelisted.
 	mutatingPodTools = []string{
 		"exec",
+		"debug_pod",
+		"cp_from_pod",
+		"cp_to_pod",
 	}
 )
 
@@ -123,6 +127,7 @@ func TestRegisterPodTools_Whitelist_RegistersWhitelisted(t *testing.T) {
 		assert.Contains(t, tools, name)
 	}
 	assert.Contains(t, tools, "exec", "exec should be registered when whitelisted")
+	assert.NotContains(t, tools, "cp_to_pod", "cp needs its own operation, not exec")
 
 	// port-forward is not whitelisted, so the whole port-forward family stays hidden.
 	for _, name := range portForwardTools {
@@ -0,0 +1,797 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// impliedResources maps tools that always act on one resource type, and so
+// take no resourceType argument, to that type.
+var impliedResources = map[string]string{
+	"logs":        "pods",
+	"exec":        "pods",
+	"debug_pod":   "pods",
+	"cp_from_pod": "pods",
+	"cp_to_pod":   "pods",
+	"evict":       "pods",
+
+	// port_forward connects to a pod, or to a service with resourceType
+	// service.
+	"port_forward": "pods",
+
+	"cordon":   "nodes",
+	"uncordon": "nodes",
+	"drain":    "nodes",
+
+	// session_start opens an exec or attach stream; the other session
+	// tools only use it.
+	"session_start": "pods",
+
+	"diagnose_node": "nodes",
+	"drain_preview": "nodes",
+
+	// cert_expiry reads the certificates of TLS secrets.
+	"cert_expiry": "secrets",
+
+	"service_accounts": "serviceaccounts",
+	"pod_security":     "pods",
+	"images":           "pods",
+	"restarts":         "pods",
+
+	"list_namespaces":  "namespaces",
+	"create_namespace": "namespaces",
+	"delete_namespace": "namespaces",
+}
+
+// listedResources maps tools that read several resource types to the
+// argument listing them, comma-separated as resource or resource.group, and
+// the types they read when it is omitted. The defaults follow the search and
+// find_orphans tools. Tools without an argument, such as diagnose_pod, always
+// read their types, besides the one their resourceType argument or
+// impliedResources names.
+var listedResources = map[string]struct {
+	param    string
+	defaults []string
+}{
+	"search": {"resourceTypes", []string{
+		"pods", "deployments.apps", "statefulsets.apps", "daemonsets.apps", "cronjobs.batch", "jobs.batch",
+		"services", "ingresses.networking.k8s.io", "configmaps", "secrets", "persistentvolumeclaims",
+	}},
+	"find_orphans": {"kinds", []string{"replicasets.apps", "persistentvolumeclaims", "configmaps", "secrets", "services"}},
+
+	// diagnose_pod returns container logs, like logs.
+	"diagnose_pod":  {"", []string{"pods", "events", "nodes"}},
+	"diagnose_node": {"", []string{"nodes", "pods", "events"}},
+	"drain_preview": {"", []string{"nodes", "pods", "poddisruptionbudgets.policy"}},
+	"restarts":      {"", []string{"pods", "events"}},
+	"pod_security":  {"", []string{"pods", "namespaces"}},
+	"cert_expiry":   {"", []string{"secrets", "certificates.cert-manager.io"}},
+	"service_accounts": {"", []string{
+		"serviceaccounts", "secrets", "pods", "rolebindings.rbac.authorization.k8s.io", "clusterrolebindings.rbac.authorization.k8s.io",
+	}},
+	"namespace_overview": {"", []string{
+		"namespaces", "pods", "deployments.apps", "statefulsets.apps", "daemonsets.apps", "jobs.batch", "cronjobs.batch",
+		"services", "ingresses.networking.k8s.io", "configmaps", "secrets", "persistentvolumeclaims", "resourcequotas",
+	}},
+	"resource_quotas": {"", []string{"resourcequotas", "limitranges"}},
+	"tree": {"", []string{
+		"deployments.apps", "replicasets.apps", "statefulsets.apps", "daemonsets.apps", "jobs.batch", "cronjobs.batch",
+		"pods", "services", "endpointslices.discovery.k8s.io", "configmaps", "persistentvolumeclaims",
+	}},
+	"service_debug": {"", []string{
+		"services", "endpoints", "endpointslices.discovery.k8s.io", "pods", "ingresses.networking.k8s.io", "httproutes.gateway.networking.k8s.io",
+	}},
+	"storage_debug": {"", []string{
+		"persistentvolumeclaims", "persistentvolumes", "storageclasses.storage.k8s.io", "volumeattachments.storage.k8s.io", "pods", "events",
+	}},
+	"routes": {"", []string{
+		"ingresses.networking.k8s.io", "httproutes.gateway.networking.k8s.io", "gateways.gateway.networking.k8s.io", "services", "endpointslices.discovery.k8s.io",
+	}},
+	"connectivity_test": {"", []string{"services", "endpointslices.discovery.k8s.io", "pods"}},
+	"autoscaling":       {"", []string{"horizontalpodautoscalers.autoscaling", "verticalpodautoscalers.autoscaling.k8s.io", "events"}},
+	"gitops_status": {"", []string{
+		"kustomizations.kustomize.toolkit.fluxcd.io", "helmreleases.helm.toolkit.fluxcd.io", "gitrepositories.source.toolkit.fluxcd.io",
+		"helmrepositories.source.toolkit.fluxcd.io", "ocirepositories.source.toolkit.fluxcd.io", "applications.argoproj.io",
+	}},
+	"policy_violations": {"", []string{
+		"policyreports.wgpolicyk8s.io", "clusterpolicyreports.wgpolicyk8s.io", "reports.openreports.io", "clusterreports.openreports.io", "events",
+	}},
+	"capacity":          {"", []string{"nodes", "pods"}},
+	"cluster_overview":  {"", []string{"nodes", "pods", "namespaces", "events", "persistentvolumeclaims"}},
+	"upgrade_readiness": {"", []string{"nodes", "pods"}},
+	"crds":              {"", []string{"customresourcedefinitions.apiextensions.k8s.io"}},
+	"cluster_health":    {"", []string{"componentstatuses", "nodes"}},
+
+	// validate dry-runs the objects it is given, which are not read, and
+	// reads the quotas they count against.
+	"validate": {"", []string{"resourcequotas", "limitranges"}},
+	"gitops_reconcile": {"kind", []string{
+		"kustomizations.kustomize.toolkit.fluxcd.io", "helmreleases.helm.toolkit.fluxcd.io", "applications.argoproj.io",
+	}},
+	"deprecated_apis": {"", []string{
+		"deployments.apps", "statefulsets.apps", "daemonsets.apps", "replicasets.apps", "cronjobs.batch",
+		"horizontalpodautoscalers.autoscaling", "ingresses.networking.k8s.io", "networkpolicies.networking.k8s.io",
+		"poddisruptionbudgets.policy", "priorityclasses.scheduling.k8s.io", "storageclasses.storage.k8s.io",
+		"roles.rbac.authorization.k8s.io", "rolebindings.rbac.authorization.k8s.io",
+		"clusterroles.rbac.authorization.k8s.io", "clusterrolebindings.rbac.authorization.k8s.io",
+		"customresourcedefinitions.apiextensions.k8s.io",
+	}},
+	"who_can": {"", []string{
+		"roles.rbac.authorization.k8s.io", "rolebindings.rbac.authorization.k8s.io",
+		"clusterroles.rbac.authorization.k8s.io", "clusterrolebindings.rbac.authorization.k8s.io",
+	}},
+	"subject_permissions": {"", []string{
+		"roles.rbac.authorization.k8s.io", "rolebindings.rbac.authorization.k8s.io",
+		"clusterroles.rbac.authorization.k8s.io", "clusterrolebindings.rbac.authorization.k8s.io",
+	}},
+
+	// The CAPI tools read the Cluster they are asked about, then its
+	// objects in the organization namespace.
+	"capi_list_clusters":         {"", []string{"clusters.cluster.x-k8s.io"}},
+	"capi_get_cluster":           {"", []string{"clusters.cluster.x-k8s.io"}},
+	"capi_resolve_cluster":       {"", []string{"clusters.cluster.x-k8s.io"}},
+	"capi_cluster_health":        {"", []string{"clusters.cluster.x-k8s.io"}},
+	"capi_list_machines":         {"", []string{"clusters.cluster.x-k8s.io", "machines.cluster.x-k8s.io"}},
+	"capi_get_machinedeployment": {"", []string{"clusters.cluster.x-k8s.io", "machinedeployments.cluster.x-k8s.io"}},
+	"capi_list_nodepools": {"", []string{
+		"clusters.cluster.x-k8s.io", "machinedeployments.cluster.x-k8s.io", "machinepools.cluster.x-k8s.io",
+	}},
+	"capi_upgrade_status": {"", []string{
+		"clusters.cluster.x-k8s.io", "machines.cluster.x-k8s.io", "machinedeployments.cluster.x-k8s.io", "machinepools.cluster.x-k8s.io",
+	}},
+	"capi_cluster_events": {"", []string{"clusters.cluster.x-k8s.io", "machines.cluster.x-k8s.io", "events"}},
+	"capi_list_apps":      {"", []string{"clusters.cluster.x-k8s.io", "apps.application.giantswarm.io"}},
+	"capi_get_app":        {"", []string{"clusters.cluster.x-k8s.io", "apps.application.giantswarm.io"}},
+	"capi_upgrade_app": {"", []string{
+		"clusters.cluster.x-k8s.io", "apps.application.giantswarm.io", "appcatalogentries.application.giantswarm.io",
+	}},
+}
+
+// allNamespacesTools are the read tools that list every namespace when
+// their namespace argument is omitted, rather than the default namespace.
+var allNamespacesTools = map[string]bool{
+	"search":              true,
+	"find_orphans":        true,
+	"images":              true,
+	"restarts":            true,
+	"cert_expiry":         true,
+	"gitops_status":       true,
+	"autoscaling":         true,
+	"pod_security":        true,
+	"service_accounts":    true,
+	"routes":              true,
+	"who_can":             true,
+	"subject_permissions": true,
+}
+
+// clusterWideTools are the read tools that list every namespace whatever
+// their arguments.
+var clusterWideTools = map[string]bool{
+	"cluster_overview":  true,
+	"capacity":          true,
+	"deprecated_apis":   true,
+	"upgrade_readiness": true,
+	"policy_violations": true,
+}
+
+// namespaceWriteTools are the tools a write allowlist restricts: those that
+// change objects or run commands in containers. Node tools act across
+// namespaces; connectivity_test creates a pod in pod mode; port_forward opens
+// connections into the pods of its namespace.
+var namespaceWriteTools = map[string]bool{
+	"create":            true,
+	"apply":             true,
+	"apply_all":         true,
+	"delete":            true,
+	"patch":             true,
+	"scale":             true,
+	"label":             true,
+	"annotate":          true,
+	"create_namespace":  true,
+	"delete_namespace":  true,
+	"evict":             true,
+	"exec":              true,
+	"debug_pod":         true,
+	"cp_to_pod":         true,
+	"session_start":     true,
+	"port_forward":      true,
+	"cordon":            true,
+	"uncordon":          true,
+	"drain":             true,
+	"gitops_reconcile":  true,
+	"capi_upgrade_app":  true,
+	"connectivity_test": true,
+	"undo":              true,
+	"apply_plan":        true,
+}
+
+// targetedWriteTools are the write tools whose objects are not named by
+// their namespace argument: undo and apply_plan write the objects of journal
+// and plan entries, capi_upgrade_app an App in the organization namespace of
+// its cluster. They check each object they write with ObjectWriteDenied, so
+// the call itself is not checked against the write allowlist.
+var targetedWriteTools = map[string]bool{
+	"capi_upgrade_app": true,
+	"undo":             true,
+	"apply_plan":       true,
+}
+
+// objectlessTools are the tools whose calls touch no objects of their own:
+// they manage the server, its sessions and contexts, or ask the API server
+// about permissions and resource types. create, apply and apply_all, and
+// the targetedWriteTools, check each object they write with
+// ObjectWriteDenied instead. The calls of every other tool without a
+// resource type have an unknown resource, which deny rules and read
+// restrictions for any resource cover.
+var objectlessTools = map[string]bool{
+	"create":                         true,
+	"apply":                          true,
+	"apply_all":                      true,
+	"undo":                           true,
+	"apply_plan":                     true,
+	"add_to_plan":                    true,
+	"show_plan":                      true,
+	"discard_plan":                   true,
+	"confirm":                        true,
+	"api_resources":                  true,
+	"can_i":                          true,
+	"context_get_current":            true,
+	"context_list":                   true,
+	"context_use":                    true,
+	"get_session_context":            true,
+	"set_default_cluster":            true,
+	"set_default_namespace":          true,
+	"get_preferences":                true,
+	"set_preferences":                true,
+	"session_input":                  true,
+	"session_list":                   true,
+	"session_read":                   true,
+	"session_stop":                   true,
+	"list_port_forward_sessions":     true,
+	"stop_port_forward_session":      true,
+	"stop_all_port_forward_sessions": true,
+}
+
+// accessOf returns whether verb, a primary tool name, reads or writes.
+func accessOf(verb string) security.Access {
+	if namespaceWriteTools[verb] {
+		return security.AccessWrite
+	}
+	return security.AccessRead
+}
+
+// primaryToolName maps a deprecated alias back to the tool it aliases, so
+// policies only need to name the current tool.
+func primaryToolName(name string) string {
+	for primary, alias := range deprecatedAliasFor {
+		if alias == name {
+			return primary
+		}
+	}
+	return name
+}
+
+// policyRequestFromArgs builds the policy request for a tool call. The verb is
+// the (primary) tool name; cluster, namespace and resource type come from the
+// standard tool arguments. A call across all namespaces has no namespace and
+// AllNamespaces set.
+func policyRequestFromArgs(toolName string, args map[string]interface{}) security.Request {
+	verb := primaryToolName(toolName)
+
+	cluster := ExtractClusterParam(args)
+	if cluster == "" {
+		cluster, _ = args["kubeContext"].(string)
+	}
+
+	namespace, _ := args["namespace"].(string)
+	resource, _ := args["resourceType"].(string)
+	if resource == "" {
+		resource = impliedResources[verb]
+	}
+
+	req := security.Request{
+		Cluster:   cluster,
+		Namespace: namespace,
+		Resource:  resource,
+		Verb:      verb,
+		NoObjects: objectlessTools[verb],
+	}
+	if spansAllNamespaces(verb, args) {
+		req.Namespace = ""
+		req.AllNamespaces = true
+	}
+	return req
+}
+
+// spansAllNamespaces reports whether a call of the tool verb with args acts
+// across all namespaces: it sets allNamespaces, its tool is one of
+// clusterWideTools or a node tool, or it omits the namespace of a tool of
+// allNamespacesTools.
+func spansAllNamespaces(verb string, args map[string]interface{}) bool {
+	if allNamespaces, _ := args["allNamespaces"].(bool); allNamespaces {
+		return true
+	}
+	if clusterWideTools[verb] || impliedResources[verb] == "nodes" {
+		return true
+	}
+	namespace, _ := args["namespace"].(string)
+	return namespace == "" && allNamespacesTools[verb]
+}
+
+// resolveResource returns the plural name of resource, a resource type as the
+// caller gave it, so that policies evaluate "secret", "Secret" and "secrets"
+// alike. Built-in resources resolve from the fallback table, others through
+// discovery on the call's cluster; a resource discovery does not know is
+// returned in lower case.
+func resolveResource(ctx context.Context, sc *server.ServerContext, args map[string]interface{}, resource, apiGroup string) string {
+	if resource == "" {
+		return ""
+	}
+	if plural, ok := k8s.CanonicalResource(resource, apiGroup); ok {
+		return plural
+	}
+	resource = strings.ToLower(resource)
+
+	client, toolErr := GetClusterClient(ctx, sc, ExtractClusterParam(args))
+	if toolErr != nil {
+		return resource
+	}
+	kubeContext, _ := args["kubeContext"].(string)
+	group, _, _ := strings.Cut(apiGroup, "/")
+	resp, err := client.K8s().GetAPIResources(ctx, kubeContext, 0, 0, group, false, nil)
+	if err != nil || resp == nil {
+		return resource
+	}
+	for _, r := range resp.Items {
+		if strings.EqualFold(r.Name, resource) || strings.EqualFold(r.SingularName, resource) || strings.EqualFold(r.Kind, resource) {
+			return r.Name
+		}
+	}
+	return resource
+}
+
+// resolvedPolicyRequests builds the policy requests for a tool call, like
+// policyRequestFromArgs, with the resource type resolved to its plural name.
+// A tool of listedResources gets a request for each type it reads, and one
+// for the type its resourceType argument or impliedResources names.
+func resolvedPolicyRequests(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}) []security.Request {
+	req := policyRequestFromArgs(toolName, args)
+	listed, ok := listedResources[req.Verb]
+	if !ok {
+		apiGroup, _ := args["apiGroup"].(string)
+		req.Resource = resolveResource(ctx, sc, args, req.Resource, apiGroup)
+		return []security.Request{req}
+	}
+
+	// The defaults are plural names already; only the types a caller lists
+	// are resolved.
+	types, resolve := listed.defaults, false
+	if v, _ := args[listed.param].(string); listed.param != "" && v != "" {
+		types, resolve = strings.Split(v, ","), true
+	}
+	var reqs []security.Request
+	seen := map[string]bool{}
+	add := func(resource string) {
+		if !seen[resource] {
+			seen[resource] = true
+			r := req
+			r.Resource = resource
+			reqs = append(reqs, r)
+		}
+	}
+	if req.Resource != "" {
+		apiGroup, _ := args["apiGroup"].(string)
+		add(resolveResource(ctx, sc, args, req.Resource, apiGroup))
+	}
+	for _, t := range types {
+		resource, apiGroup, _ := strings.Cut(strings.TrimSpace(t), ".")
+		switch {
+		case resource == "":
+		case resolve:
+			add(resolveResource(ctx, sc, args, resource, apiGroup))
+		default:
+			add(resource)
+		}
+	}
+	if len(reqs) == 0 {
+		return []security.Request{req}
+	}
+	return reqs
+}
+
+// checkPolicy authorizes a tool call against the server's policy engine and,
+// if that allows it, the external authorizer. Returns an empty string when the
+// call is allowed or neither is configured.
+func checkPolicy(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}) string {
+	if sc.PolicyEngine() == nil && sc.Authorizer() == nil {
+		return ""
+	}
+	name := extractResourceName(args)
+	for _, req := range resolvedPolicyRequests(ctx, sc, toolName, args) {
+		if msg := authorizeRequest(ctx, sc, toolName, req, name); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// authorizeRequest evaluates req, naming the object name, against the
+// policy engine and then the external authorizer, as checkPolicy does.
+func authorizeRequest(ctx context.Context, sc *server.ServerContext, toolName string, req security.Request, name string) string {
+	if engine := sc.PolicyEngine(); engine != nil {
+		if decision := engine.Evaluate(req); !decision.Allowed {
+			return decision.Reason
+		}
+	}
+
+	authorizer := sc.Authorizer()
+	if authorizer == nil {
+		return ""
+	}
+
+	input := security.AuthorizationInput{
+		Tool:      toolName,
+		Verb:      req.Verb,
+		Cluster:   req.Cluster,
+		Namespace: req.Namespace,
+		Resource:  req.Resource,
+		Name:      name,
+		Access:    accessOf(req.Verb),
+
+		AllNamespaces:   req.AllNamespaces,
+		ResourceUnknown: req.ResourceUnknown(),
+	}
+	if user, _ := callerUserInfo(ctx, ""); user != nil {
+		input.User = security.UserInfo{Email: user.Email, Groups: user.Groups}
+	}
+	if target, ok := impersonateAsFromContext(ctx); ok {
+		input.ImpersonatedUser = &security.UserInfo{Email: target.Email, Groups: target.Groups}
+	}
+
+	decision, err := authorizer.Authorize(ctx, input)
+	if err != nil {
+		slog.Warn("external authorization failed",
+			slog.String("tool", toolName),
+			slog.Bool("allowed", decision.Allowed),
+			slog.Any("error", err))
+	}
+	if !decision.Allowed {
+		return decision.Reason
+	}
+	return ""
+}
+
+// checkNamespaceAccess denies a tool call that reads where the server's read
+// restrictions forbid it, or writes outside its write allowlist. A call
+// without a namespace argument targets the default namespace, unless it sets
+// allNamespaces or its tool lists every namespace then; node tools and
+// clusterWideTools act across all namespaces. Each resource type a tool of
+// listedResources reads is checked. Returns an empty string when the call is
+// allowed or no restrictions are configured.
+func checkNamespaceAccess(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}) string {
+	access := sc.NamespaceAccess()
+	if access == nil {
+		return ""
+	}
+	for _, req := range resolvedPolicyRequests(ctx, sc, toolName, args) {
+		if targetedWriteTools[req.Verb] {
+			continue
+		}
+		if req.Namespace == "" && !req.AllNamespaces {
+			req.Namespace = k8s.DefaultNamespace
+		}
+		if decision := access.Evaluate(req, accessOf(req.Verb)); !decision.Allowed {
+			return decision.Reason
+		}
+	}
+	return ""
+}
+
+// ObjectWriteDenied returns why toolName may not write obj in namespace, for
+// tools that write the objects of a manifest: the tool call's own checks
+// see no resource type, and manifests may set their own namespace. The
+// object is evaluated in namespace, or without one when ObjectClusterScoped
+// reports it cluster-scoped, against the policy engine, the external
+// authorizer, the write allowlist and, unless it is cluster-scoped, the
+// namespace label guard. A cluster-scoped object is only written under a
+// write allowlist when cluster-scoped writes are allowed. Returns an empty
+// string when the write is allowed.
+func ObjectWriteDenied(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}, namespace string, obj *unstructured.Unstructured) string {
+	clusterScoped, err := ObjectClusterScoped(ctx, sc, args, obj)
+	if err != nil {
+		return err.Error()
+	}
+	if clusterScoped {
+		namespace = ""
+	}
+	gv, _ := schema.ParseGroupVersion(obj.GetAPIVersion())
+	return writeDenied(ctx, sc, toolName, args, namespace, obj.GetKind(), gv.Group, obj.GetName())
+}
+
+// ObjectClusterScoped reports whether obj is cluster-scoped on the cluster
+// of a call with args. Built-in kinds resolve from the fallback table,
+// others through discovery, like resolveResource; a kind the cluster does
+// not serve is taken to be namespaced, as it cannot be written.
+func ObjectClusterScoped(ctx context.Context, sc *server.ServerContext, args map[string]interface{}, obj *unstructured.Unstructured) (bool, error) {
+	gv, _ := schema.ParseGroupVersion(obj.GetAPIVersion())
+	kind := obj.GetKind()
+	group := gv.Group
+	if group == "" {
+		group = "core"
+	}
+	if namespaced, ok := k8s.BuiltinNamespaced(kind, group); ok {
+		return !namespaced, nil
+	}
+
+	client, toolErr := GetClusterClient(ctx, sc, ExtractClusterParam(args))
+	if toolErr != nil {
+		return false, fmt.Errorf("cannot determine whether %s is cluster-scoped: %w", kind, toolErr)
+	}
+	kubeContext, _ := args["kubeContext"].(string)
+	resp, err := client.K8s().GetAPIResources(ctx, kubeContext, 0, 0, gv.Group, false, nil)
+	if err != nil {
+		return false, fmt.Errorf("cannot determine whether %s is cluster-scoped: %w", kind, err)
+	}
+	if resp != nil {
+		for _, r := range resp.Items {
+			if r.Kind == kind && r.Group == gv.Group {
+				return !r.Namespaced, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// TargetWriteDenied returns why toolName may not write target, for tools
+// whose objects come from journal or plan entries rather than their
+// arguments. target is checked like an object of ObjectWriteDenied, in a
+// call naming its cluster, kubeContext and namespace.
+func TargetWriteDenied(ctx context.Context, sc *server.ServerContext, toolName string, target UndoTarget) string {
+	args := map[string]interface{}{
+		"cluster":     target.Cluster,
+		"kubeContext": target.KubeContext,
+		"namespace":   target.Namespace,
+	}
+	return writeDenied(ctx, sc, toolName, args, target.Namespace, target.ResourceType, target.APIGroup, target.Name)
+}
+
+// writeDenied evaluates a write of the object resource/name, of apiGroup,
+// in namespace for ObjectWriteDenied and TargetWriteDenied.
+func writeDenied(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}, namespace, resource, apiGroup, name string) string {
+	req := policyRequestFromArgs(toolName, args)
+	req.Namespace = namespace
+	req.AllNamespaces = false
+	req.NoObjects = false
+	if sc.PolicyEngine() != nil || sc.Authorizer() != nil || sc.NamespaceAccess() != nil {
+		req.Resource = resolveResource(ctx, sc, args, resource, apiGroup)
+	}
+	if sc.PolicyEngine() != nil || sc.Authorizer() != nil {
+		if msg := authorizeRequest(ctx, sc, toolName, req, name); msg != "" {
+			return msg
+		}
+	}
+
+	if access := sc.NamespaceAccess(); access != nil {
+		if decision := access.Evaluate(req, security.AccessWrite); !decision.Allowed {
+			return decision.Reason
+		}
+	}
+	return namespaceLabelsDenied(ctx, sc, args, namespace)
+}
+
+// toolCallContextKey is the context key for the tool call being served.
+type toolCallContextKey struct{}
+
+// toolCall is the name and arguments of the tool call being served.
+type toolCall struct {
+	name string
+	args map[string]interface{}
+}
+
+// contextWithToolCall stores the tool call being served in ctx, for
+// CheckClusterAccess.
+func contextWithToolCall(ctx context.Context, toolName string, args map[string]interface{}) context.Context {
+	return context.WithValue(ctx, toolCallContextKey{}, toolCall{name: toolName, args: args})
+}
+
+// CheckClusterAccess returns why the tool call being served may not reach
+// cluster, for tools that act on clusters other than the one their cluster
+// argument names, such as fleet queries over clusters or crds with
+// compareWith. The call is checked as if it named cluster, against the
+// policy engine, the external authorizer, namespace access and the
+// namespace label guard. Returns an empty string when the call may reach
+// cluster or is not served through WrapWithAuditLogging.
+func CheckClusterAccess(ctx context.Context, sc *server.ServerContext, cluster string) string {
+	call, ok := ctx.Value(toolCallContextKey{}).(toolCall)
+	if !ok {
+		return ""
+	}
+	args := maps.Clone(call.args)
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	delete(args, "clusters")
+	args["cluster"] = cluster
+
+	if msg := checkPolicy(ctx, sc, call.name, args); msg != "" {
+		return msg
+	}
+	if msg := checkNamespaceAccess(ctx, sc, call.name, args); msg != "" {
+		return msg
+	}
+	return checkNamespaceLabels(ctx, sc, call.name, args)
+}
+
+// checkNamespaceLabels denies a tool call whose namespace or sourceNamespace
+// argument names a namespace restricted by its labels, or that reads across
+// all namespaces while any namespace of its cluster is restricted. A call
+// that omits its namespace is checked against the default namespace when
+// defaultsToNamespace reports it acts there. The labels are read with the
+// caller's own client, so they need get and list access to namespaces
+// unless the guard fails open. Returns an empty string when the call is
+// allowed or no guard is configured.
+func checkNamespaceLabels(ctx context.Context, sc *server.ServerContext, toolName string, args map[string]interface{}) string {
+	guard := sc.NamespaceLabelGuard()
+	if guard == nil {
+		return ""
+	}
+	verb := primaryToolName(toolName)
+	for _, param := range []string{"namespace", "sourceNamespace"} {
+		namespace, _ := args[param].(string)
+		if namespace == "" && param == "namespace" && defaultsToNamespace(ctx, sc, verb, args) {
+			namespace = k8s.DefaultNamespace
+		}
+		if msg := namespaceLabelsDenied(ctx, sc, args, namespace); msg != "" {
+			return msg
+		}
+	}
+
+	if accessOf(verb) != security.AccessRead || !spansAllNamespaces(verb, args) {
+		return ""
+	}
+	kubeContext, _ := args["kubeContext"].(string)
+	lookup := func(ctx context.Context, cluster, selector string) ([]string, error) {
+		client, toolErr := GetClusterClient(ctx, sc, cluster)
+		if toolErr != nil {
+			return nil, toolErr
+		}
+		resp, err := client.K8s().List(ctx, kubeContext, "", "namespaces", "", k8s.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, item := range resp.Items {
+			obj, err := meta.Accessor(item)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, obj.GetName())
+		}
+		return names, nil
+	}
+
+	decision, err := guard.CheckAllNamespaces(ctx, ExtractClusterParam(args), lookup)
+	if err != nil {
+		slog.Warn("failed to list label-restricted namespaces",
+			slog.Bool("allowed", decision.Allowed),
+			slog.Any("error", err))
+	}
+	if !decision.Allowed {
+		return decision.Reason
+	}
+	return ""
+}
+
+// defaultsToNamespace reports whether a call of the tool verb with args
+// acts in the default namespace when it omits its namespace argument, as the
+// handlers of namespaced resources do. Calls across all namespaces, of
+// objectlessTools and of targetedWriteTools do not, nor do calls on
+// cluster-scoped resources. A tool of listedResources does when any of its
+// types is a namespaced built-in; a resource of unknown scope is taken to
+// be namespaced.
+func defaultsToNamespace(ctx context.Context, sc *server.ServerContext, verb string, args map[string]interface{}) bool {
+	if objectlessTools[verb] || targetedWriteTools[verb] || spansAllNamespaces(verb, args) {
+		return false
+	}
+	resource, _ := args["resourceType"].(string)
+	apiGroup, _ := args["apiGroup"].(string)
+	if resource == "" {
+		resource = impliedResources[verb]
+	}
+	if resource != "" {
+		return resourceNamespaced(ctx, sc, args, resource, apiGroup)
+	}
+
+	listed, ok := listedResources[verb]
+	if !ok {
+		return true
+	}
+	for _, t := range listed.defaults {
+		name, group, _ := strings.Cut(t, ".")
+		if namespaced, known := k8s.BuiltinNamespaced(name, group); known && namespaced {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceNamespaced reports whether resource, of apiGroup, is namespaced on
+// the cluster of a call with args. Built-in resources resolve from the
+// fallback table, others through discovery, like resolveResource; a resource
+// discovery does not find is taken to be namespaced.
+func resourceNamespaced(ctx context.Context, sc *server.ServerContext, args map[string]interface{}, resource, apiGroup string) bool {
+	if namespaced, ok := k8s.BuiltinNamespaced(resource, apiGroup); ok {
+		return namespaced
+	}
+
+	client, toolErr := GetClusterClient(ctx, sc, ExtractClusterParam(args))
+	if toolErr != nil {
+		return true
+	}
+	kubeContext, _ := args["kubeContext"].(string)
+	group, _, _ := strings.Cut(apiGroup, "/")
+	resp, err := client.K8s().GetAPIResources(ctx, kubeContext, 0, 0, group, false, nil)
+	if err != nil || resp == nil {
+		return true
+	}
+	for _, r := range resp.Items {
+		if strings.EqualFold(r.Name, resource) || strings.EqualFold(r.SingularName, resource) || strings.EqualFold(r.Kind, resource) {
+			return r.Namespaced
+		}
+	}
+	return true
+}
+
+// namespaceLabelsDenied returns why namespace, in the cluster of a call with
+// args, is restricted by its labels. Returns an empty string when it is not,
+// namespace is empty or no guard is configured.
+func namespaceLabelsDenied(ctx context.Context, sc *server.ServerContext, args map[string]interface{}, namespace string) string {
+	guard := sc.NamespaceLabelGuard()
+	if guard == nil || namespace == "" {
+		return ""
+	}
+	kubeContext, _ := args["kubeContext"].(string)
+
+	lookup := func(ctx context.Context, cluster, namespace string) (map[string]string, bool, error) {
+		client, toolErr := GetClusterClient(ctx, sc, cluster)
+		if toolErr != nil {
+			return nil, false, toolErr
+		}
+		resp, err := client.K8s().Get(ctx, kubeContext, "", "namespaces", "", namespace)
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		obj, err := meta.Accessor(resp.Resource)
+		if err != nil {
+			return nil, false, err
+		}
+		return obj.GetLabels(), true, nil
+	}
+
+	decision, err := guard.Check(ctx, ExtractClusterParam(args), namespace, lookup)
+	if err != nil {
+		slog.Warn("failed to read namespace labels",
+			slog.String("namespace", namespace),
+			slog.Bool("allowed", decision.Allowed),
+			slog.Any("error", err))
+	}
+	if !decision.Allowed {
+		return decision.Reason
+	}
+	return ""
+}
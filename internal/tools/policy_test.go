@@ -0,0 +1,505 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+func TestPolicyRequestFromArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolName string
+		args     map[string]interface{}
+		want     security.Request
+	}{
+		{
+			name:     "resource tool",
+			toolName: "delete",
+			args:     map[string]interface{}{"cluster": "prod-eu", "namespace": "app", "resourceType": "deployments"},
+			want:     security.Request{Cluster: "prod-eu", Namespace: "app", Resource: "deployments", Verb: "delete"},
+		},
+		{
+			name:     "deprecated alias maps to primary verb",
+			toolName: "kubernetes_get",
+			args:     map[string]interface{}{"resourceType": "pods"},
+			want:     security.Request{Resource: "pods", Verb: "get"},
+		},
+		{
+			name:     "pod tools imply pods",
+			toolName: "logs",
+			args:     map[string]interface{}{"namespace": "default", "kubeContext": "kind"},
+			want:     security.Request{Cluster: "kind", Namespace: "default", Resource: "pods", Verb: "logs"},
+		},
+		{
+			name:     "port_forward implies pods",
+			toolName: "port_forward",
+			args:     map[string]interface{}{"namespace": "app", "resourceName": "web-0"},
+			want:     security.Request{Namespace: "app", Resource: "pods", Verb: "port_forward"},
+		},
+		{
+			name:     "port_forward to a service",
+			toolName: "port_forward",
+			args:     map[string]interface{}{"namespace": "app", "resourceType": "service", "resourceName": "web"},
+			want:     security.Request{Namespace: "app", Resource: "service", Verb: "port_forward"},
+		},
+		{
+			name:     "node tools imply nodes",
+			toolName: "drain",
+			args:     map[string]interface{}{"nodeName": "worker-1"},
+			want:     security.Request{Resource: "nodes", Verb: "drain", AllNamespaces: true},
+		},
+		{
+			name:     "allNamespaces drops the namespace",
+			toolName: "list",
+			args:     map[string]interface{}{"namespace": "app", "resourceType": "secrets", "allNamespaces": true},
+			want:     security.Request{Resource: "secrets", Verb: "list", AllNamespaces: true},
+		},
+		{
+			name:     "omitted namespace of a tool listing every namespace",
+			toolName: "cert_expiry",
+			args:     map[string]interface{}{},
+			want:     security.Request{Resource: "secrets", Verb: "cert_expiry", AllNamespaces: true},
+		},
+		{
+			name:     "cert_expiry implies secrets",
+			toolName: "cert_expiry",
+			args:     map[string]interface{}{"namespace": "ingress"},
+			want:     security.Request{Namespace: "ingress", Resource: "secrets", Verb: "cert_expiry"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policyRequestFromArgs(tt.toolName, tt.args))
+		})
+	}
+}
+
+func TestWrapWithAuditLogging_PolicyDenied(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    namespaces: [kube-system]\n"))
+	require.NoError(t, err)
+
+	provider := createTestProvider(t)
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&mockK8sClient{}),
+		server.WithLogger(&mockLogger{}),
+		server.WithInstrumentationProvider(provider),
+		server.WithPolicyEngine(security.NewEngine(policy)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+
+	calls := 0
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("success"), nil
+	}
+	wrapped := WrapWithAuditLogging("list", handler, sc)
+
+	result, err := wrapped(context.Background(), createTestRequest(map[string]interface{}{
+		"resourceType": "pods",
+		"namespace":    "kube-system",
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 0, calls, "handler must not run when the policy denies the call")
+
+	result, err = wrapped(context.Background(), createTestRequest(map[string]interface{}{
+		"resourceType": "pods",
+		"namespace":    "default",
+	}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, 1, calls)
+}
+
+// recordingAuthorizer is a security.Authorizer returning a fixed decision.
+type recordingAuthorizer struct {
+	decision security.Decision
+	err      error
+	inputs   []security.AuthorizationInput
+}
+
+func (a *recordingAuthorizer) Authorize(_ context.Context, input security.AuthorizationInput) (security.Decision, error) {
+	a.inputs = append(a.inputs, input)
+	return a.decision, a.err
+}
+
+func TestCheckPolicy_ExternalAuthorizer(t *testing.T) {
+	args := map[string]interface{}{"cluster": "prod-eu", "namespace": "app", "resourceType": "pods", "name": "web-0"}
+
+	t.Run("sends the call and caller to the authorizer", func(t *testing.T) {
+		authz := &recordingAuthorizer{decision: security.Decision{Allowed: true}}
+		sc := newVisibilityServerContext(t, server.WithAuthorizer(authz))
+
+		assert.Empty(t, checkPolicy(userContext("devs"), sc, "kubernetes_delete", args))
+		require.Len(t, authz.inputs, 1)
+		assert.Equal(t, security.AuthorizationInput{
+			Tool:      "kubernetes_delete",
+			Verb:      "delete",
+			Cluster:   "prod-eu",
+			Namespace: "app",
+			Resource:  "pods",
+			Name:      "web-0",
+			User:      security.UserInfo{Email: "jane@example.com", Groups: []string{"devs"}},
+			Access:    security.AccessWrite,
+		}, authz.inputs[0])
+	})
+
+	t.Run("denial is enforced", func(t *testing.T) {
+		authz := &recordingAuthorizer{decision: security.Decision{Reason: "change freeze"}}
+		sc := newVisibilityServerContext(t, server.WithAuthorizer(authz))
+		assert.Equal(t, "change freeze", checkPolicy(context.Background(), sc, "delete", args))
+	})
+
+	t.Run("policy engine denial skips the authorizer", func(t *testing.T) {
+		authz := &recordingAuthorizer{decision: security.Decision{Allowed: true}}
+		policy := &security.Policy{Rules: []security.Rule{{Effect: security.EffectDeny, Verbs: []string{"delete"}}}}
+		sc := newVisibilityServerContext(t,
+			server.WithAuthorizer(authz),
+			server.WithPolicyEngine(security.NewEngine(policy)))
+
+		assert.NotEmpty(t, checkPolicy(context.Background(), sc, "delete", args))
+		assert.Empty(t, authz.inputs)
+	})
+
+	t.Run("fail-open decision with error is honoured", func(t *testing.T) {
+		authz := &recordingAuthorizer{decision: security.Decision{Allowed: true}, err: errors.New("unreachable")}
+		sc := newVisibilityServerContext(t, server.WithAuthorizer(authz))
+		assert.Empty(t, checkPolicy(context.Background(), sc, "get", args))
+	})
+}
+
+// apiResourcesK8sClient serves a fixed discovery result.
+type apiResourcesK8sClient struct {
+	mockK8sClient
+	resources []k8s.APIResourceInfo
+}
+
+func (c *apiResourcesK8sClient) GetAPIResources(_ context.Context, _ string, _, _ int, _ string, _ bool, _ []string) (*k8s.PaginatedAPIResourceResponse, error) {
+	return &k8s.PaginatedAPIResourceResponse{Items: c.resources}, nil
+}
+
+func TestCheckPolicy_ResolvesResourceType(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    resources: [secrets, widgets]\n"))
+	require.NoError(t, err)
+	client := &apiResourcesK8sClient{resources: []k8s.APIResourceInfo{
+		{Name: "widgets", SingularName: "widget", Kind: "Widget", Group: "example.com"},
+	}}
+	sc := newVisibilityServerContext(t, server.WithK8sClient(client), server.WithPolicyEngine(security.NewEngine(policy)))
+
+	for _, resourceType := range []string{"secrets", "secret", "Secret", "widgets", "Widget"} {
+		got := checkPolicy(context.Background(), sc, "get", map[string]interface{}{"namespace": "app", "resourceType": resourceType})
+		assert.Contains(t, got, "is denied by policy", resourceType)
+	}
+	assert.Empty(t, checkPolicy(context.Background(), sc, "get", map[string]interface{}{"namespace": "app", "resourceType": "gadget"}))
+
+	assert.Contains(t, checkPolicy(context.Background(), sc, "search", map[string]interface{}{"resourceTypes": "pods,Widget.example.com"}), "is denied by policy",
+		"every listed type is checked")
+	assert.Contains(t, checkPolicy(context.Background(), sc, "search", nil), "is denied by policy", "the default types include secrets")
+	assert.Empty(t, checkPolicy(context.Background(), sc, "search", map[string]interface{}{"resourceTypes": "pods,deployments.apps"}))
+}
+
+func TestCheckPolicy_AllNamespaces(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    namespaces: [kube-system]\n    resources: [secrets]\n"))
+	require.NoError(t, err)
+	sc := newVisibilityServerContext(t, server.WithPolicyEngine(security.NewEngine(policy)))
+
+	tests := []struct {
+		name   string
+		tool   string
+		args   map[string]interface{}
+		denied bool
+	}{
+		{name: "list across all namespaces", tool: "list", args: map[string]interface{}{"resourceType": "secrets", "allNamespaces": true}, denied: true},
+		{name: "omitted namespace", tool: "cert_expiry", args: map[string]interface{}{}, denied: true},
+		{name: "other namespace", tool: "cert_expiry", args: map[string]interface{}{"namespace": "ingress"}},
+		{name: "other resource across all namespaces", tool: "list", args: map[string]interface{}{"resourceType": "pods", "allNamespaces": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkPolicy(context.Background(), sc, tt.tool, tt.args)
+			if tt.denied {
+				assert.Contains(t, got, "across all namespaces is denied by policy")
+			} else {
+				assert.Empty(t, got)
+			}
+		})
+	}
+}
+
+func TestCheckPolicy_UnknownResource(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    resources: [secrets]\n"))
+	require.NoError(t, err)
+	sc := newVisibilityServerContext(t, server.WithPolicyEngine(security.NewEngine(policy)))
+
+	assert.Equal(t, "echo of unknown resources in namespace app is denied by policy",
+		checkPolicy(context.Background(), sc, "echo", map[string]interface{}{"namespace": "app"}), "a tool without a resource type fails closed")
+	assert.Contains(t, checkPolicy(context.Background(), sc, "namespace_overview", map[string]interface{}{"namespace": "app"}), "namespace_overview secrets",
+		"composite tools are checked for the types they read")
+	assert.Empty(t, checkPolicy(context.Background(), sc, "context_list", nil), "context_list touches no objects")
+	assert.Empty(t, checkPolicy(context.Background(), sc, "apply", map[string]interface{}{"namespace": "app"}), "apply checks each object")
+	assert.Empty(t, checkPolicy(context.Background(), sc, "tree", map[string]interface{}{"namespace": "app", "resourceType": "deployments", "name": "web"}))
+
+	authz := &recordingAuthorizer{decision: security.Decision{Allowed: true}}
+	sc = newVisibilityServerContext(t, server.WithAuthorizer(authz))
+	assert.Empty(t, checkPolicy(context.Background(), sc, "echo", map[string]interface{}{"namespace": "app"}))
+	assert.Empty(t, checkPolicy(context.Background(), sc, "context_list", nil))
+	require.Len(t, authz.inputs, 2)
+	assert.True(t, authz.inputs[0].ResourceUnknown)
+	assert.False(t, authz.inputs[1].ResourceUnknown)
+}
+
+func TestCheckClusterAccess(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    clusters: [prod-b]\n    verbs: [capacity]\n"))
+	require.NoError(t, err)
+	sc := newVisibilityServerContext(t, server.WithPolicyEngine(security.NewEngine(policy)))
+
+	ctx := contextWithToolCall(context.Background(), "capacity", map[string]interface{}{"clusters": "prod-a,prod-b"})
+	assert.Empty(t, CheckClusterAccess(ctx, sc, "prod-a"))
+	assert.Equal(t, "capacity nodes across all namespaces on cluster prod-b is denied by policy", CheckClusterAccess(ctx, sc, "prod-b"))
+	assert.Empty(t, CheckClusterAccess(context.Background(), sc, "prod-b"), "only calls served through WrapWithAuditLogging are checked")
+}
+
+func TestObjectWriteDenied(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    resources: [secrets]\n    verbs: [apply]\n"))
+	require.NoError(t, err)
+	sc := newVisibilityServerContext(t,
+		server.WithPolicyEngine(security.NewEngine(policy)),
+		server.WithNamespaceAccess(nil, []string{"team-*"}, false))
+
+	object := func(apiVersion, kind string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName("x")
+		return obj
+	}
+	args := map[string]interface{}{"namespace": "team-a"}
+
+	assert.Equal(t, "apply secrets in namespace team-a is denied by policy",
+		ObjectWriteDenied(context.Background(), sc, "apply", args, "team-a", object("v1", "Secret")))
+	assert.Empty(t, ObjectWriteDenied(context.Background(), sc, "apply", args, "team-a", object("v1", "ConfigMap")))
+	assert.Contains(t, ObjectWriteDenied(context.Background(), sc, "apply", args, "prod", object("apps/v1", "Deployment")),
+		"writes are limited to namespaces team-*")
+	assert.Equal(t, "apply of cluster-scoped clusterroles is not allowed: writes are limited to namespaces team-*",
+		ObjectWriteDenied(context.Background(), sc, "apply", args, "", object("rbac.authorization.k8s.io/v1", "ClusterRole")))
+}
+
+func TestTargetWriteDenied(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    clusters: [prod]\n    resources: [configmaps]\n    verbs: [undo]\n"))
+	require.NoError(t, err)
+	sc := newVisibilityServerContext(t,
+		server.WithPolicyEngine(security.NewEngine(policy)),
+		server.WithNamespaceAccess(nil, []string{"team-*"}, false))
+
+	target := UndoTarget{Cluster: "prod", Namespace: "team-a", ResourceType: "ConfigMap", APIGroup: "core/v1", Name: "web"}
+	assert.Equal(t, "undo configmaps in namespace team-a on cluster prod is denied by policy", TargetWriteDenied(context.Background(), sc, "undo", target))
+
+	target.Cluster = "staging"
+	assert.Empty(t, TargetWriteDenied(context.Background(), sc, "undo", target))
+	target.Namespace = "prod"
+	assert.Contains(t, TargetWriteDenied(context.Background(), sc, "apply_plan", target), "apply_plan in namespace prod is not allowed")
+}
+
+func TestCheckNamespaceAccess(t *testing.T) {
+	sc := newVisibilityServerContext(t, server.WithNamespaceAccess([]string{"kube-system/secrets"}, []string{"team-*"}, false))
+
+	tests := []struct {
+		name   string
+		tool   string
+		args   map[string]interface{}
+		denied string
+	}{
+		{name: "read anywhere", tool: "get", args: map[string]interface{}{"namespace": "kube-system", "resourceType": "pods"}},
+		{name: "restricted read", tool: "get", args: map[string]interface{}{"namespace": "kube-system", "resourceType": "secrets"}, denied: "reading secrets in namespace kube-system is restricted"},
+		{name: "restricted read across namespaces", tool: "list", args: map[string]interface{}{"resourceType": "secrets", "allNamespaces": true}, denied: "across all namespaces"},
+		{name: "write in allowlist", tool: "kubernetes_delete", args: map[string]interface{}{"namespace": "team-a", "resourceType": "pods"}},
+		{name: "write outside allowlist", tool: "scale", args: map[string]interface{}{"namespace": "prod", "resourceType": "deployments"}, denied: "scale in namespace prod is not allowed"},
+		{name: "write to default namespace", tool: "patch", args: map[string]interface{}{"resourceType": "configmaps"}, denied: "namespace default"},
+		{name: "write across namespaces", tool: "label", args: map[string]interface{}{"resourceType": "pods", "allNamespaces": true}, denied: "across all namespaces"},
+		{name: "node tools write across namespaces", tool: "drain", args: map[string]interface{}{"nodeName": "worker-1"}, denied: "across all namespaces"},
+		{name: "omitted namespace reads across namespaces", tool: "cert_expiry", args: map[string]interface{}{}, denied: "reading secrets across all namespaces"},
+		{name: "namespaced read of implied type", tool: "cert_expiry", args: map[string]interface{}{"namespace": "team-a"}},
+		{name: "default listed types", tool: "search", args: map[string]interface{}{"pattern": "db"}, denied: "reading secrets across all namespaces"},
+		{name: "listed types", tool: "search", args: map[string]interface{}{"namespace": "kube-system", "resourceTypes": "pods,Secret"}, denied: "reading secrets in namespace kube-system is restricted"},
+		{name: "listed types without restricted type", tool: "search", args: map[string]interface{}{"resourceTypes": "pods,deployments.apps"}},
+		{name: "pod-mode tool outside allowlist", tool: "connectivity_test", args: map[string]interface{}{"namespace": "prod", "service": "web", "mode": "pod"}, denied: "connectivity_test in namespace prod is not allowed"},
+		{name: "unknown resource", tool: "echo", args: map[string]interface{}{"namespace": "kube-system"}, denied: "reading unknown resources in namespace kube-system is restricted"},
+		{name: "no objects", tool: "session_list", args: map[string]interface{}{"namespace": "kube-system"}},
+		{name: "port_forward in allowlist", tool: "port_forward", args: map[string]interface{}{"namespace": "team-a", "resourceName": "web-0"}},
+		{name: "port_forward outside allowlist", tool: "port_forward", args: map[string]interface{}{"namespace": "prod", "resourceName": "web-0"}, denied: "port_forward in namespace prod is not allowed"},
+		{name: "objects of undo are checked on their own", tool: "undo", args: map[string]interface{}{"count": float64(1)}},
+		{name: "objects of apply_plan are checked on their own", tool: "apply_plan", args: map[string]interface{}{"plan": "rollout"}},
+		{name: "listed kinds", tool: "find_orphans", args: map[string]interface{}{"namespace": "kube-system", "kinds": "configmaps,secrets"}, denied: "reading secrets in namespace kube-system is restricted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkNamespaceAccess(context.Background(), sc, tt.tool, tt.args)
+			if tt.denied == "" {
+				assert.Empty(t, got)
+			} else {
+				assert.Contains(t, got, tt.denied)
+			}
+		})
+	}
+
+	assert.Empty(t, checkNamespaceAccess(context.Background(), newVisibilityServerContext(t), "drain", nil), "unrestricted without configuration")
+
+	sc = newVisibilityServerContext(t, server.WithNamespaceAccess([]string{"kube-system"}, nil, false))
+	assert.Contains(t, checkNamespaceAccess(context.Background(), sc, "images", nil), "across all namespaces")
+	assert.Empty(t, checkNamespaceAccess(context.Background(), sc, "images", map[string]interface{}{"namespace": "apps"}))
+	assert.Contains(t, checkNamespaceAccess(context.Background(), sc, "cluster_overview", map[string]interface{}{"namespace": "apps"}), "across all namespaces",
+		"cluster-wide tools read every namespace")
+	assert.Empty(t, checkNamespaceAccess(context.Background(), sc, "get", map[string]interface{}{"resourceType": "pods"}), "get reads the default namespace")
+}
+
+func TestCheckNamespaceAccess_CompositeTools(t *testing.T) {
+	sc := newVisibilityServerContext(t, server.WithNamespaceAccess([]string{"kube-system/pods", "vault/secrets"}, nil, false))
+
+	tests := []struct {
+		name   string
+		tool   string
+		args   map[string]interface{}
+		denied string
+	}{
+		{name: "diagnose_pod reads the logs of pods", tool: "diagnose_pod", args: map[string]interface{}{"namespace": "kube-system", "podName": "etcd-0"}, denied: "reading pods in namespace kube-system is restricted"},
+		{name: "diagnose_pod elsewhere", tool: "diagnose_pod", args: map[string]interface{}{"namespace": "shop", "podName": "api-0"}},
+		{name: "namespace_overview counts secrets", tool: "namespace_overview", args: map[string]interface{}{"namespace": "vault"}, denied: "reading secrets in namespace vault is restricted"},
+		{name: "tree reads the pods of its root", tool: "tree", args: map[string]interface{}{"namespace": "kube-system", "resourceType": "deployments", "name": "coredns"}, denied: "reading pods in namespace kube-system"},
+		{name: "service_debug reads pods", tool: "service_debug", args: map[string]interface{}{"namespace": "kube-system", "service": "kube-dns"}, denied: "reading pods"},
+		{name: "capacity reads pods across all namespaces", tool: "capacity", args: map[string]interface{}{}, denied: "reading pods across all namespaces"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkNamespaceAccess(context.Background(), sc, tt.tool, tt.args)
+			if tt.denied == "" {
+				assert.Empty(t, got)
+			} else {
+				assert.Contains(t, got, tt.denied)
+			}
+		})
+	}
+}
+
+// namespaceK8sClient serves namespaces with fixed labels.
+type namespaceK8sClient struct {
+	mockK8sClient
+	labels map[string]map[string]string
+}
+
+func (c *namespaceK8sClient) Get(_ context.Context, _, _, resourceType, _, name string) (*k8s.GetResponse, error) {
+	nsLabels, ok := c.labels[name]
+	if resourceType != "namespaces" || !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	ns := &unstructured.Unstructured{}
+	ns.SetName(name)
+	ns.SetLabels(nsLabels)
+	return &k8s.GetResponse{Resource: ns}, nil
+}
+
+func (c *namespaceK8sClient) List(_ context.Context, _, _, _, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	resp := &k8s.PaginatedListResponse{}
+	for name, nsLabels := range c.labels {
+		if selector.Matches(labels.Set(nsLabels)) {
+			ns := &unstructured.Unstructured{}
+			ns.SetName(name)
+			resp.Items = append(resp.Items, ns)
+		}
+	}
+	return resp, nil
+}
+
+func TestCheckNamespaceLabels(t *testing.T) {
+	guard, err := security.NewNamespaceLabelGuard("policy.giantswarm.io/mcp-access=deny", 0, false)
+	require.NoError(t, err)
+	client := &namespaceK8sClient{labels: map[string]map[string]string{
+		"secrets": {"policy.giantswarm.io/mcp-access": "deny"},
+		"apps":    {"team": "a"},
+	}}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(client),
+		server.WithLogger(&mockLogger{}),
+		server.WithNamespaceLabelGuard(guard),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+
+	assert.Contains(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"namespace": "secrets"}),
+		`access to namespace "secrets" is restricted`)
+	assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"namespace": "apps"}))
+	assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"namespace": "missing"}))
+	assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"resourceType": "nodes"}))
+	assert.Contains(t, checkNamespaceLabels(context.Background(), sc, "connectivity_test", map[string]interface{}{"namespace": "apps", "sourceNamespace": "secrets"}),
+		`access to namespace "secrets" is restricted`)
+
+	assert.Contains(t, checkNamespaceLabels(context.Background(), sc, "list", map[string]interface{}{"resourceType": "pods", "allNamespaces": true}),
+		"access across all namespaces is not allowed: namespaces secrets are restricted")
+	assert.Contains(t, checkNamespaceLabels(context.Background(), sc, "images", map[string]interface{}{}), "access across all namespaces is not allowed",
+		"images lists every namespace without a namespace argument")
+	assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "images", map[string]interface{}{"namespace": "apps"}))
+
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetName("creds")
+	assert.Contains(t, ObjectWriteDenied(context.Background(), sc, "apply_all", map[string]interface{}{}, "secrets", secret),
+		`access to namespace "secrets" is restricted`, "the namespace a manifest sets is checked")
+	assert.Empty(t, ObjectWriteDenied(context.Background(), sc, "apply_all", map[string]interface{}{}, "apps", secret))
+
+	t.Run("an omitted namespace is the default namespace", func(t *testing.T) {
+		client := &namespaceK8sClient{labels: map[string]map[string]string{
+			"default": {"policy.giantswarm.io/mcp-access": "deny"},
+		}}
+		sc, err := server.NewServerContext(context.Background(),
+			server.WithK8sClient(client),
+			server.WithLogger(&mockLogger{}),
+			server.WithNamespaceLabelGuard(guard),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = sc.Shutdown() })
+
+		for _, call := range []struct {
+			tool string
+			args map[string]interface{}
+		}{
+			{"get", map[string]interface{}{"resourceType": "pods", "name": "web-0"}},
+			{"kubernetes_list", map[string]interface{}{"resourceType": "configmaps"}},
+			{"delete", map[string]interface{}{"resourceType": "Deployment", "apiGroup": "apps", "name": "web"}},
+			{"patch", map[string]interface{}{"resourceType": "services", "name": "web"}},
+			{"exec", map[string]interface{}{"podName": "web-0"}},
+			{"diagnose_pod", map[string]interface{}{"podName": "web-0"}},
+		} {
+			assert.Contains(t, checkNamespaceLabels(context.Background(), sc, call.tool, call.args), `access to namespace "default" is restricted`, call.tool)
+		}
+		assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"resourceType": "nodes", "name": "worker-1"}),
+			"cluster-scoped resources have no namespace")
+		assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "crds", nil))
+		assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "context_list", nil))
+		assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"namespace": "apps", "resourceType": "pods"}))
+	})
+
+	t.Run("without a guard nothing is restricted", func(t *testing.T) {
+		sc := newVisibilityServerContext(t)
+		assert.Empty(t, checkNamespaceLabels(context.Background(), sc, "get", map[string]interface{}{"namespace": "secrets"}))
+	})
+}
@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Names of the preferences tools.
+const (
+	GetPreferencesToolName = "get_preferences"
+	SetPreferencesToolName = "set_preferences"
+)
+
+// Parameters filled from the caller's preferences when a call leaves them
+// out, besides the cluster.
+const (
+	paramOutput       = "output"
+	paramLimit        = "limit"
+	paramMaskedFields = "maskedFields"
+	paramReset        = "reset"
+)
+
+// preferencesTools are the preferences tools, whose own arguments are never
+// filled from the preferences, like those of the session context tools.
+var preferencesTools = map[string]bool{
+	GetPreferencesToolName: true,
+	SetPreferencesToolName: true,
+}
+
+// PreferencesResponse reports the preferences of the caller.
+type PreferencesResponse struct {
+	Preferences preferences.Preferences `json:"preferences"`
+	Message     string                  `json:"message"`
+}
+
+// RegisterPreferencesTools registers the tools that show and change the
+// caller's preferences. They are only registered when preferences are
+// enabled.
+func RegisterPreferencesTools(s *mcpserver.MCPServer, sc *server.ServerContext) {
+	if sc.Preferences() == nil {
+		return
+	}
+
+	s.AddTool(mcp.NewTool(SetPreferencesToolName,
+		mcp.WithDescription(`Change your preferences, which apply to your tool calls in every session: the output format, cluster and limit used when a call leaves them out, and fields masked in read results.
+
+Only the arguments you pass are changed; pass an empty value to clear one, or reset to clear all. Arguments passed to a tool call and session defaults take precedence over preferences.`),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString(paramOutput,
+			mcp.Description("Output format of read tools that take one: 'slim', 'normal', 'wide', 'full' or 'table' (list only)"),
+			mcp.Enum(append([]string{""}, preferences.OutputFormats...)...),
+		),
+		mcp.WithString(paramCluster,
+			mcp.Description("Workload cluster of tools that take one (federation mode)"),
+		),
+		mcp.WithNumber(paramLimit,
+			mcp.Description("Number of items tools that take a limit return, e.g. the page size of list (0 clears it)"),
+			mcp.Min(0),
+			mcp.Max(preferences.MaxLimit),
+		),
+		mcp.WithArray(paramMaskedFields,
+			mcp.Description("Field paths whose values are masked in read results, in dot notation, e.g. 'metadata.labels.team.example.com/owner' or 'status.podIPs[*].ip'. Replaces the current list; an empty list clears it."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean(paramReset,
+			mcp.Description("Clear all preferences before applying the other arguments"),
+		),
+	), WrapWithAuditLogging(SetPreferencesToolName, handleSetPreferences, sc))
+
+	s.AddTool(mcp.NewTool(GetPreferencesToolName,
+		mcp.WithDescription("Show your preferences, set with set_preferences."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	), WrapWithAuditLogging(GetPreferencesToolName, handleGetPreferences, sc))
+}
+
+// errNoPreferencesUser is returned by the preferences tools when the caller
+// is not authenticated.
+var errNoPreferencesUser = toolerrors.New(toolerrors.CodeFailedPrecondition, "preferences need an authenticated user")
+
+// preferencesUser returns the email preferences are keyed by, or "" when
+// the caller is not authenticated.
+func preferencesUser(ctx context.Context) string {
+	if user, _ := callerUserInfo(ctx, ""); user != nil {
+		return user.Email
+	}
+	return ""
+}
+
+func handleSetPreferences(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	user := preferencesUser(ctx)
+	if user == "" {
+		return errNoPreferencesUser.Result(), nil
+	}
+	store := sc.Preferences()
+
+	var p preferences.Preferences
+	args := request.GetArguments()
+	if reset, _ := args[paramReset].(bool); !reset {
+		current, err := store.Get(ctx, user)
+		if err != nil {
+			return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+		}
+		p = current
+	}
+	if v, ok := args[paramOutput].(string); ok {
+		p.Output = v
+	}
+	if v, ok := args[paramCluster].(string); ok {
+		if v != "" {
+			if err := federation.ValidateClusterName(v); err != nil {
+				return toolerrors.InvalidArgument(errMsgInvalidClusterName).Result(), nil
+			}
+		}
+		p.Cluster = v
+	}
+	if v, ok := args[paramLimit].(float64); ok {
+		if v != float64(int(v)) {
+			return toolerrors.InvalidArgumentf("limit must be a whole number, got %v", v).Result(), nil
+		}
+		p.Limit = int(v)
+	}
+	if _, ok := args[paramMaskedFields]; ok {
+		fields, err := request.RequireStringSlice(paramMaskedFields)
+		if err != nil {
+			return toolerrors.InvalidArgument("maskedFields must be a list of field paths").Result(), nil
+		}
+		p.MaskedFields = fields
+		if len(fields) == 0 {
+			p.MaskedFields = nil
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return toolerrors.InvalidArgument(err.Error()).Result(), nil
+	}
+	if err := store.Set(ctx, user, p); errors.Is(err, preferences.ErrTooManyUsers) {
+		return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+	} else if err != nil {
+		return toolerrors.Internalf("Failed to store preferences: %v", err).Result(), nil
+	}
+	return preferencesResult(p, "Preferences updated")
+}
+
+func handleGetPreferences(ctx context.Context, _ mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	user := preferencesUser(ctx)
+	if user == "" {
+		return errNoPreferencesUser.Result(), nil
+	}
+	p, err := sc.Preferences().Get(ctx, user)
+	if err != nil {
+		return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+	}
+	message := "Preferences apply to tool calls that leave the argument out"
+	if p.IsZero() {
+		message = "No preferences are set"
+	}
+	return preferencesResult(p, message)
+}
+
+func preferencesResult(p preferences.Preferences, message string) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(PreferencesResponse{Preferences: p, Message: message}, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// withPreferences applies the caller's preferences to a call: it fills the
+// output, cluster and limit arguments the call left out, for tools that take
+// them, and carries the preferences in the returned context for the output
+// processor to mask fields. It returns the preferences it filled in.
+// Preferences are best effort: when they cannot be read, the call goes
+// ahead without them.
+func withPreferences(ctx context.Context, sc *server.ServerContext, toolName string, request mcp.CallToolRequest) (context.Context, mcp.CallToolRequest, preferences.Preferences) {
+	store := sc.Preferences()
+	if name := primaryToolName(toolName); store == nil || preferencesTools[name] || sessionTools[name] {
+		return ctx, request, preferences.Preferences{}
+	}
+	user := preferencesUser(ctx)
+	if user == "" {
+		return ctx, request, preferences.Preferences{}
+	}
+	p, err := store.Get(ctx, user)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read user preferences", "tool", toolName, "error", err)
+		return ctx, request, preferences.Preferences{}
+	}
+	if p.IsZero() {
+		return ctx, request, preferences.Preferences{}
+	}
+	ctx = preferences.NewContext(ctx, p)
+
+	srv := mcpserver.ServerFromContext(ctx)
+	if srv == nil {
+		return ctx, request, preferences.Preferences{}
+	}
+	tool := srv.GetTool(toolName)
+	if tool == nil {
+		return ctx, request, preferences.Preferences{}
+	}
+
+	args := request.GetArguments()
+	var applied preferences.Preferences
+	fill := map[string]interface{}{}
+	if p.Output != "" && takesParam(tool.Tool, paramOutput) && isUnset(args, paramOutput) &&
+		paramAccepts(tool.Tool, paramOutput, p.Output) {
+		applied.Output = p.Output
+		fill[paramOutput] = p.Output
+	}
+	if p.Cluster != "" && takesParam(tool.Tool, paramCluster) && isUnset(args, paramCluster) {
+		applied.Cluster = p.Cluster
+		fill[paramCluster] = p.Cluster
+	}
+	if _, set := args[paramLimit].(float64); p.Limit > 0 && !set && takesParam(tool.Tool, paramLimit) {
+		applied.Limit = p.Limit
+		if maximum, ok := paramMaximum(tool.Tool, paramLimit); ok && float64(applied.Limit) > maximum {
+			applied.Limit = int(maximum)
+		}
+		fill[paramLimit] = float64(applied.Limit)
+	}
+	return ctx, withArguments(request, fill), applied
+}
+
+// paramAccepts reports whether the parameter name of tool accepts value,
+// which it does unless the parameter is limited to an enum without it.
+func paramAccepts(tool mcp.Tool, name, value string) bool {
+	property, _ := tool.InputSchema.Properties[name].(map[string]any)
+	switch enum := property["enum"].(type) {
+	case []string:
+		return slices.Contains(enum, value)
+	case []any:
+		return slices.Contains(enum, any(value))
+	default:
+		return true
+	}
+}
+
+// paramMaximum returns the maximum the number parameter name of tool
+// declares, if any.
+func paramMaximum(tool mcp.Tool, name string) (float64, bool) {
+	property, _ := tool.InputSchema.Properties[name].(map[string]any)
+	switch maximum := property["maximum"].(type) {
+	case float64:
+		return maximum, true
+	case int:
+		return float64(maximum), true
+	case int64:
+		return float64(maximum), true
+	default:
+		return 0, false
+	}
+}
+
+// withPreferencesMeta reports the preferences a call used in the result's
+// _meta, so that callers can tell where an argument came from.
+func withPreferencesMeta(result *mcp.CallToolResult, applied preferences.Preferences) *mcp.CallToolResult {
+	if result == nil || applied.IsZero() {
+		return result
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields["preferences"] = applied
+	return result
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	oauthhandler "github.com/giantswarm/mcp-oauth/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// preferencesTestServer registers the preferences and session tools and a
+// "read" tool that returns its arguments and the masked fields it was given.
+func preferencesTestServer(t *testing.T) (*mcpserver.MCPServer, *server.ServerContext) {
+	t.Helper()
+	sc, err := server.NewServerContext(
+		context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithPreferences(preferences.New(preferences.NewMemoryBackend(), "test:")),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+
+	s := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithToolCapabilities(true))
+	RegisterPreferencesTools(s, sc)
+	RegisterSessionTools(s, sc)
+
+	read := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		data, err := json.Marshal(map[string]any{
+			"args":         request.GetArguments(),
+			"maskedFields": preferences.FromContext(ctx).MaskedFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+	s.AddTool(mcp.NewTool("read",
+		mcp.WithString("cluster"),
+		mcp.WithString("output", mcp.Enum("slim", "normal", "wide", "full")),
+		mcp.WithNumber("limit", mcp.Max(500)),
+	), WrapWithAuditLogging("read", read, sc))
+	return s, sc
+}
+
+func preferencesUserContext(s *mcpserver.MCPServer, email, sessionID string) context.Context {
+	ctx := oauthhandler.ContextWithUserInfo(context.Background(), &oauth.UserInfo{Email: email})
+	return s.WithContext(ctx, &clientInfoSession{id: sessionID})
+}
+
+type readResult struct {
+	Args         map[string]any `json:"args"`
+	MaskedFields []string       `json:"maskedFields"`
+}
+
+func readWith(t *testing.T, ctx context.Context, s *mcpserver.MCPServer, args map[string]any) (readResult, *mcp.CallToolResult) {
+	t.Helper()
+	result := callToolWithContext(t, ctx, s, "read", args)
+	require.False(t, result.IsError)
+	var r readResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &r))
+	return r, result
+}
+
+func TestPreferences(t *testing.T) {
+	s, sc := preferencesTestServer(t)
+	jane := preferencesUserContext(s, "jane@example.com", "s1")
+
+	result := callToolWithContext(t, jane, s, SetPreferencesToolName, map[string]any{
+		"output":       "table",
+		"cluster":      "prod-wc-01",
+		"limit":        800,
+		"maskedFields": []any{"metadata.labels.owner"},
+	})
+	require.False(t, result.IsError, "%v", result.Content)
+
+	// table is not an output format of read, and its limit is capped at 500.
+	r, result := readWith(t, jane, s, map[string]any{})
+	assert.Equal(t, map[string]any{"cluster": "prod-wc-01", "limit": float64(500)}, r.Args)
+	assert.Equal(t, []string{"metadata.labels.owner"}, r.MaskedFields)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, preferences.Preferences{Cluster: "prod-wc-01", Limit: 500}, result.Meta.AdditionalFields["preferences"])
+
+	// Only the arguments passed are changed.
+	result = callToolWithContext(t, jane, s, SetPreferencesToolName, map[string]any{"output": "wide"})
+	require.False(t, result.IsError)
+	r, _ = readWith(t, jane, s, map[string]any{})
+	assert.Equal(t, "wide", r.Args["output"])
+	assert.Equal(t, "prod-wc-01", r.Args["cluster"])
+
+	r, _ = readWith(t, jane, s, map[string]any{"output": "slim", "limit": 10})
+	assert.Equal(t, "slim", r.Args["output"], "an explicit argument wins")
+	assert.Equal(t, float64(10), r.Args["limit"])
+
+	// Session defaults take precedence over preferences.
+	_, err := sc.SessionDefaults().Update("s1", func(d *sessions.Defaults) { d.Cluster = "dev-wc-01" })
+	require.NoError(t, err)
+	r, _ = readWith(t, jane, s, map[string]any{})
+	assert.Equal(t, "dev-wc-01", r.Args["cluster"])
+
+	// Preferences follow the user into other sessions, and only them.
+	r, _ = readWith(t, preferencesUserContext(s, "jane@example.com", "s2"), s, map[string]any{})
+	assert.Equal(t, "prod-wc-01", r.Args["cluster"])
+	r, _ = readWith(t, preferencesUserContext(s, "john@example.com", "s3"), s, map[string]any{})
+	assert.Empty(t, r.Args)
+	assert.Empty(t, r.MaskedFields)
+
+	result = callToolWithContext(t, jane, s, GetPreferencesToolName, map[string]any{})
+	require.False(t, result.IsError)
+	var response PreferencesResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	assert.Equal(t, preferences.Preferences{Output: "wide", Cluster: "prod-wc-01", Limit: 800, MaskedFields: []string{"metadata.labels.owner"}}, response.Preferences)
+
+	result = callToolWithContext(t, jane, s, SetPreferencesToolName, map[string]any{"reset": true, "limit": 30})
+	require.False(t, result.IsError)
+	p, err := sc.Preferences().Get(context.Background(), "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, preferences.Preferences{Limit: 30}, p)
+}
+
+func TestPreferences_Errors(t *testing.T) {
+	s, _ := preferencesTestServer(t)
+	jane := preferencesUserContext(s, "jane@example.com", "s1")
+
+	result := callToolWithContext(t, jane, s, SetPreferencesToolName, map[string]any{"output": "yaml"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "output must be one of")
+
+	result = callToolWithContext(t, jane, s, SetPreferencesToolName, map[string]any{"maskedFields": []any{"metadata labels"}})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid masked field path")
+
+	result = callToolWithContext(t, context.Background(), s, GetPreferencesToolName, map[string]any{})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "need an authenticated user")
+}
+
+func TestRegisterPreferencesTools_Disabled(t *testing.T) {
+	s := mcpserver.NewMCPServer("test", "1.0.0")
+	RegisterPreferencesTools(s, createTestServerContextNoInstrumentation(t))
+	assert.Nil(t, s.GetTool(SetPreferencesToolName))
+	assert.Nil(t, s.GetTool(GetPreferencesToolName))
+}
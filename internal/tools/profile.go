@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// Tool exposure profiles, selecting which tools are exposed at all.
+const (
+	// ProfileReadOnly exposes only read-only tools.
+	ProfileReadOnly = "read-only"
+
+	// ProfileOperator adds the tools that change workloads: create, apply,
+	// patch, scale, label, annotate, delete and evict, plus port forwarding
+	// and the confirm, undo and plan tools.
+	ProfileOperator = "operator"
+
+	// ProfileAdmin exposes every tool. It is the default.
+	ProfileAdmin = "admin"
+)
+
+// Profiles lists the tool exposure profiles, from least to most exposed.
+var Profiles = []string{ProfileReadOnly, ProfileOperator, ProfileAdmin}
+
+// operatorTools are the mutating tools the operator profile exposes. The
+// admin profile adds the rest: namespace lifecycle, node maintenance and
+// shell access to containers (exec, debug_pod, cp_from_pod, cp_to_pod and
+// the session tools).
+var operatorTools = map[string]bool{
+	"create":                         true,
+	"apply":                          true,
+	"apply_all":                      true,
+	"patch":                          true,
+	"scale":                          true,
+	"label":                          true,
+	"annotate":                       true,
+	"delete":                         true,
+	"evict":                          true,
+	"port_forward":                   true,
+	"stop_port_forward_session":      true,
+	"stop_all_port_forward_sessions": true,
+	"connectivity_test":              true,
+	"capi_upgrade_app":               true,
+	"gitops_reconcile":               true,
+	"context_use":                    true,
+	ConfirmToolName:                  true,
+	UndoToolName:                     true,
+	"add_to_plan":                    true,
+	"apply_plan":                     true,
+	"discard_plan":                   true,
+}
+
+// ValidateProfile returns an error unless profile names a tool exposure
+// profile. An empty profile is the admin profile.
+func ValidateProfile(profile string) error {
+	if profile == "" {
+		return nil
+	}
+	for _, p := range Profiles {
+		if p == profile {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown tool profile %q: must be one of %s", profile, strings.Join(Profiles, ", "))
+}
+
+// ToolExposure controls which registered tools are exposed: those of a
+// profile, minus tools disabled by name. Both can change at runtime, for
+// example to turn off exec during an incident; the MCP server then notifies
+// clients that the tool list changed. A tool that is not exposed is neither
+// listed nor callable.
+//
+// Deprecated aliases follow the tool they alias. A tool is read-only when
+// its ReadOnlyHint annotation says so.
+type ToolExposure struct {
+	s *mcpserver.MCPServer
+
+	mu       sync.Mutex
+	all      map[string]mcpserver.ServerTool
+	profile  string
+	disabled map[string]bool
+}
+
+// ToolExposureStatus reports the exposed tools.
+type ToolExposureStatus struct {
+	Profile  string   `json:"profile"`
+	Disabled []string `json:"disabled"`
+	Exposed  []string `json:"exposed"`
+	Hidden   []string `json:"hidden"`
+}
+
+// NewToolExposure takes over the tools registered with s, which must all be
+// registered already, and exposes those of profile. An empty profile is the
+// admin profile.
+func NewToolExposure(s *mcpserver.MCPServer, profile string) (*ToolExposure, error) {
+	if err := ValidateProfile(profile); err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		profile = ProfileAdmin
+	}
+	e := &ToolExposure{
+		s:        s,
+		all:      make(map[string]mcpserver.ServerTool),
+		profile:  profile,
+		disabled: make(map[string]bool),
+	}
+	for name, t := range s.ListTools() {
+		e.all[name] = *t
+	}
+	e.apply()
+	return e, nil
+}
+
+// SetProfile switches to profile.
+func (e *ToolExposure) SetProfile(profile string) error {
+	if err := ValidateProfile(profile); err != nil {
+		return err
+	}
+	if profile == "" {
+		profile = ProfileAdmin
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.profile = profile
+	e.apply()
+	return nil
+}
+
+// DisableTools hides the named tools whatever the profile, until they are
+// enabled again.
+func (e *ToolExposure) DisableTools(names ...string) error {
+	return e.setDisabled(names, true)
+}
+
+// EnableTools undoes DisableTools for the named tools. They are exposed
+// again if the profile exposes them.
+func (e *ToolExposure) EnableTools(names ...string) error {
+	return e.setDisabled(names, false)
+}
+
+// CheckToolNames returns an error unless every name is a registered tool.
+func (e *ToolExposure) CheckToolNames(names ...string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.checkToolNames(names)
+}
+
+// checkToolNames is CheckToolNames for callers holding e.mu.
+func (e *ToolExposure) checkToolNames(names []string) error {
+	for _, name := range names {
+		if _, ok := e.all[name]; !ok {
+			return fmt.Errorf("unknown tool %q", name)
+		}
+	}
+	return nil
+}
+
+func (e *ToolExposure) setDisabled(names []string, disabled bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.checkToolNames(names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if disabled {
+			e.disabled[primaryToolName(name)] = true
+		} else {
+			delete(e.disabled, primaryToolName(name))
+		}
+	}
+	e.apply()
+	return nil
+}
+
+// Status reports the profile, the disabled tools and which tools are exposed.
+func (e *ToolExposure) Status() ToolExposureStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	status := ToolExposureStatus{
+		Profile:  e.profile,
+		Disabled: []string{},
+		Exposed:  []string{},
+		Hidden:   []string{},
+	}
+	for name := range e.disabled {
+		status.Disabled = append(status.Disabled, name)
+	}
+	for name, t := range e.all {
+		if e.exposes(name, t) {
+			status.Exposed = append(status.Exposed, name)
+		} else {
+			status.Hidden = append(status.Hidden, name)
+		}
+	}
+	sort.Strings(status.Disabled)
+	sort.Strings(status.Exposed)
+	sort.Strings(status.Hidden)
+	return status
+}
+
+// exposes reports whether the tool name is exposed. Callers hold e.mu.
+func (e *ToolExposure) exposes(name string, t mcpserver.ServerTool) bool {
+	primary := primaryToolName(name)
+	if e.disabled[primary] {
+		return false
+	}
+	switch e.profile {
+	case ProfileReadOnly:
+		return !isMutatingTool(t.Tool)
+	case ProfileOperator:
+		return !isMutatingTool(t.Tool) || operatorTools[primary]
+	default:
+		return true
+	}
+}
+
+// apply adds and removes tools on the MCP server to match the exposure.
+// Callers hold e.mu.
+func (e *ToolExposure) apply() {
+	current := e.s.ListTools()
+	var add []mcpserver.ServerTool
+	var remove []string
+	for name, t := range e.all {
+		_, registered := current[name]
+		switch exposed := e.exposes(name, t); {
+		case exposed && !registered:
+			add = append(add, t)
+		case !exposed && registered:
+			remove = append(remove, name)
+		}
+	}
+	if len(remove) > 0 {
+		sort.Strings(remove)
+		e.s.DeleteTools(remove...)
+	}
+	if len(add) > 0 {
+		e.s.AddTools(add...)
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func profileTestServer() *mcpserver.MCPServer {
+	s := mcpserver.NewMCPServer("test", "1.0.0")
+	handler := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	for _, t := range visibilityTestTools() {
+		s.AddTool(t, handler)
+	}
+	s.AddTool(mcp.NewTool("delete_namespace", mcp.WithReadOnlyHintAnnotation(false)), handler)
+	return s
+}
+
+func registeredToolNames(s *mcpserver.MCPServer) []string {
+	var names []string
+	for name := range s.ListTools() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestToolExposure_Profiles(t *testing.T) {
+	tests := []struct {
+		profile string
+		want    []string
+	}{
+		{profile: ProfileReadOnly, want: []string{"capi_list_clusters", "get"}},
+		{profile: ProfileOperator, want: []string{"capi_list_clusters", "delete", "get", "kubernetes_delete"}},
+		{profile: ProfileAdmin, want: []string{"capi_list_clusters", "delete", "delete_namespace", "exec", "get", "kubernetes_delete"}},
+		{profile: "", want: []string{"capi_list_clusters", "delete", "delete_namespace", "exec", "get", "kubernetes_delete"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			s := profileTestServer()
+			e, err := NewToolExposure(s, tt.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, registeredToolNames(s))
+			assert.Equal(t, tt.want, e.Status().Exposed)
+		})
+	}
+
+	_, err := NewToolExposure(profileTestServer(), "root")
+	assert.ErrorContains(t, err, "unknown tool profile")
+}
+
+func TestToolExposure_RuntimeChanges(t *testing.T) {
+	s := profileTestServer()
+	e, err := NewToolExposure(s, ProfileAdmin)
+	require.NoError(t, err)
+
+	require.NoError(t, e.DisableTools("kubernetes_delete", "exec"))
+	assert.Equal(t, []string{"capi_list_clusters", "delete_namespace", "get"}, registeredToolNames(s), "aliases follow their tool")
+	assert.Equal(t, []string{"delete", "exec"}, e.Status().Disabled)
+
+	require.NoError(t, e.SetProfile(ProfileReadOnly))
+	assert.Equal(t, []string{"capi_list_clusters", "get"}, registeredToolNames(s))
+
+	require.NoError(t, e.SetProfile(ProfileOperator))
+	require.NoError(t, e.EnableTools("delete"))
+	assert.Equal(t, []string{"capi_list_clusters", "delete", "get", "kubernetes_delete"}, registeredToolNames(s))
+	assert.Equal(t, []string{"exec"}, e.Status().Disabled, "exec stays disabled across profiles")
+
+	assert.ErrorContains(t, e.DisableTools("get", "rm"), `unknown tool "rm"`)
+	assert.Contains(t, registeredToolNames(s), "get", "nothing changes on error")
+	assert.ErrorContains(t, e.SetProfile("root"), "unknown tool profile")
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+)
+
+// checkRateLimit takes a token from the caller's budget for toolName.
+// Returns an empty string when the call may proceed or no limiter is
+// configured.
+func checkRateLimit(ctx context.Context, sc *server.ServerContext, toolName string) string {
+	limiter := sc.ToolRateLimiter()
+	if limiter == nil {
+		return ""
+	}
+
+	if !limiter.Allow(ctx, rateLimitCaller(ctx), primaryToolName(toolName)) {
+		return ratelimit.ExceededMessage(toolName)
+	}
+	return ""
+}
+
+// rateLimitCaller returns who is making a tool call: the authenticated
+// user, falling back to the subject authenticated by --auth-mode and then
+// the client IP.
+func rateLimitCaller(ctx context.Context) ratelimit.Caller {
+	caller := ratelimit.Caller{
+		Subject: middleware.AuthenticatedSubjectFromContext(ctx),
+		IP:      middleware.ClientIPFromContext(ctx),
+	}
+	if user, _ := callerUserInfo(ctx, ""); user != nil {
+		caller.Email = user.Email
+	}
+	return caller
+}
+
+// CallerIdentity returns a stable identity for who is making a tool call,
+// for state that must stay private to them.
+func CallerIdentity(ctx context.Context) string {
+	return rateLimitCaller(ctx).Identity()
+}
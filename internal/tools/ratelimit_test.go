@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/ratelimit"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/server/middleware"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+func TestCheckRateLimit(t *testing.T) {
+	t.Run("disabled without a limiter", func(t *testing.T) {
+		sc := newVisibilityServerContext(t)
+		assert.Empty(t, checkRateLimit(userContext(), sc, "get"))
+	})
+
+	t.Run("budget is per user", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(0.001, 1), false, nil)
+		sc := newVisibilityServerContext(t, server.WithToolRateLimiter(limiter))
+
+		assert.Empty(t, checkRateLimit(userContext(), sc, "get"))
+		assert.Contains(t, checkRateLimit(userContext(), sc, "list"), "rate limit exceeded")
+
+		anonymous := middleware.ContextWithClientIP(context.Background(), "192.0.2.10")
+		assert.Empty(t, checkRateLimit(anonymous, sc, "get"), "anonymous callers are keyed by client IP")
+		assert.NotEmpty(t, checkRateLimit(anonymous, sc, "get"))
+	})
+
+	t.Run("token-authenticated callers are keyed by subject", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(0.001, 1), false, nil)
+		sc := newVisibilityServerContext(t, server.WithToolRateLimiter(limiter))
+
+		ctx := middleware.ContextWithClientIP(context.Background(), "192.0.2.10")
+		runner := middleware.ContextWithAuthenticatedSubject(ctx, "static-token-1")
+		assert.Empty(t, checkRateLimit(runner, sc, "get"))
+		assert.NotEmpty(t, checkRateLimit(runner, sc, "get"))
+		assert.Empty(t, checkRateLimit(middleware.ContextWithAuthenticatedSubject(ctx, "static-token-2"), sc, "get"),
+			"subjects sharing an IP have separate budgets")
+	})
+
+	t.Run("per-tool budgets share with deprecated aliases", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(0.001, 1), true, nil)
+		sc := newVisibilityServerContext(t, server.WithToolRateLimiter(limiter))
+
+		assert.Empty(t, checkRateLimit(userContext(), sc, "get"))
+		assert.Empty(t, checkRateLimit(userContext(), sc, "list"), "each tool has its own budget")
+		assert.NotEmpty(t, checkRateLimit(userContext(), sc, "kubernetes_get"), "aliases draw from the primary tool's budget")
+	})
+}
+
+func TestWrapWithAuditLogging_RateLimited(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(0.001, 1), false, nil)
+	provider := createTestProvider(t)
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&mockK8sClient{}),
+		server.WithLogger(&mockLogger{}),
+		server.WithInstrumentationProvider(provider),
+		server.WithToolRateLimiter(limiter),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+
+	calls := 0
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("success"), nil
+	}
+	wrapped := WrapWithAuditLogging("get", handler, sc)
+
+	result, err := wrapped(userContext(), createTestRequest(map[string]interface{}{"resourceType": "pods"}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	result, err = wrapped(userContext(), createTestRequest(map[string]interface{}{"resourceType": "pods"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	require.NotNil(t, toolerrors.FromResult(result))
+	assert.Equal(t, toolerrors.CodeRateLimited, toolerrors.FromResult(result).Code)
+	assert.Equal(t, 1, calls, "handler must not run once the budget is spent")
+}
@@ -0,0 +1,270 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// MaxApplyAllObjects limits how many objects one apply_all call may apply.
+const MaxApplyAllObjects = 100
+
+// Per-object statuses reported by apply_all.
+const (
+	applyStatusApplied = "applied"
+	applyStatusFailed  = "failed"
+	applyStatusInvalid = "invalid"
+	applyStatusSkipped = "skipped"
+)
+
+// applyOrder is the order in which kinds are applied, following Helm's
+// install order: namespaces, policies and CRDs before the workloads that
+// depend on them. Kinds not listed, such as custom resources, come last.
+var applyOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// applyRank returns the position of kind in applyOrder.
+func applyRank(kind string) int {
+	for i, k := range applyOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(applyOrder)
+}
+
+// ApplyAllResult is the output of the apply_all tool.
+type ApplyAllResult struct {
+	DryRun  bool             `json:"dryRun,omitempty"`
+	Applied int              `json:"applied"`
+	Failed  int              `json:"failed"`
+	Skipped int              `json:"skipped,omitempty"`
+	Invalid int              `json:"invalid,omitempty"`
+	Objects []ApplyAllObject `json:"objects"`
+	Message string           `json:"message,omitempty"`
+}
+
+// ApplyAllObject is the outcome for one object, in the order applied.
+type ApplyAllObject struct {
+	// Index is the position of the object in the bundle, starting at 0.
+	Index      int    `json:"index"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// handleApplyAll applies a bundle of manifests in dependency order.
+func handleApplyAll(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "apply"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+	kubeContext, _ := args["kubeContext"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = k8s.DefaultNamespace
+	}
+	stopOnError, _ := args["stopOnError"].(bool)
+
+	manifests, _ := args["manifests"].(string)
+	if strings.TrimSpace(manifests) == "" {
+		return toolerrors.Required("manifests").Result(), nil
+	}
+	objects, err := decodeManifests(manifests)
+	if err != nil {
+		return toolerrors.InvalidArgumentf("Failed to parse manifests: %v", err).Result(), nil
+	}
+	if len(objects) == 0 {
+		return toolerrors.InvalidArgument("manifests contains no objects").Result(), nil
+	}
+	if len(objects) > MaxApplyAllObjects {
+		return toolerrors.InvalidArgumentf("manifests contains %d objects; at most %d can be applied at once", len(objects), MaxApplyAllObjects).Result(), nil
+	}
+	if result := checkSecretObjects(sc, args, objects); result != nil {
+		return result, nil
+	}
+
+	result := &ApplyAllResult{DryRun: tools.IsDryRun(ctx, sc)}
+	clusterScoped := func(obj *unstructured.Unstructured) bool {
+		// A kind whose scope is unknown is checked, and denied, by
+		// checkApplyAllObjects.
+		scoped, _ := tools.ObjectClusterScoped(ctx, sc, args, obj)
+		return scoped
+	}
+	plan := checkApplyAllObjects(ctx, sc, args, objects, planApplyAll(objects, namespace, clusterScoped, result), result)
+	if result.Invalid > 0 {
+		// Nothing is applied unless every object is valid.
+		for _, i := range plan {
+			result.Objects[i].Status = applyStatusSkipped
+			result.Skipped++
+		}
+		result.Message = fmt.Sprintf("%d object(s) are invalid; nothing was applied", result.Invalid)
+		return applyAllResult(result)
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	for _, i := range plan {
+		out := &result.Objects[i]
+		if stopOnError && result.Failed > 0 {
+			out.Status = applyStatusSkipped
+			result.Skipped++
+			continue
+		}
+
+		undo := tools.SnapshotForUndo(ctx, sc, k8sClient, "apply_all", undoTargetOf(clusterName, kubeContext, out.Namespace, objects[out.Index]))
+		start := time.Now()
+		_, err := k8sClient.Apply(ctx, kubeContext, out.Namespace, objects[out.Index])
+		duration := time.Since(start)
+		if err != nil {
+			recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationApply, out.Kind, out.Namespace, instrumentation.StatusError, duration)
+			out.Status = applyStatusFailed
+			out.Error = tools.FormatK8sError("Failed to apply resource", err, client.User())
+			result.Failed++
+			continue
+		}
+		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationApply, out.Kind, out.Namespace, instrumentation.StatusSuccess, duration)
+		tools.RecordForUndo(ctx, sc, undo)
+		out.Status = applyStatusApplied
+		result.Applied++
+	}
+	if result.Failed > 0 {
+		result.Message = fmt.Sprintf("%d of %d object(s) failed to apply", result.Failed, len(objects))
+	}
+	return applyAllResult(result)
+}
+
+// planApplyAll validates objects and fills result.Objects in apply order.
+// Objects clusterScoped reports have no namespace; others without one get
+// namespace. It returns the positions in result.Objects of the valid
+// objects.
+func planApplyAll(objects []*unstructured.Unstructured, namespace string, clusterScoped func(*unstructured.Unstructured) bool, result *ApplyAllResult) []int {
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return applyRank(objects[order[a]].GetKind()) < applyRank(objects[order[b]].GetKind())
+	})
+
+	seen := make(map[string]int, len(objects))
+	var plan []int
+	for _, idx := range order {
+		obj := objects[idx]
+		out := ApplyAllObject{
+			Index:      idx,
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		}
+		switch {
+		case clusterScoped(obj):
+			out.Namespace = ""
+		case out.Namespace == "":
+			out.Namespace = namespace
+		}
+
+		key := out.APIVersion + "/" + out.Kind + "/" + out.Namespace + "/" + out.Name
+		switch {
+		case out.APIVersion == "" || out.Kind == "":
+			out.Error = "apiVersion and kind are required"
+		case out.Name == "":
+			out.Error = "metadata.name is required"
+		default:
+			if first, ok := seen[key]; ok {
+				out.Error = fmt.Sprintf("duplicate of object %d", first)
+			} else {
+				seen[key] = idx
+			}
+		}
+
+		if out.Error != "" {
+			out.Status = applyStatusInvalid
+			result.Invalid++
+		} else {
+			plan = append(plan, len(result.Objects))
+		}
+		result.Objects = append(result.Objects, out)
+	}
+	return plan
+}
+
+// checkApplyAllObjects marks the planned objects the server's policy or
+// write allowlist denies invalid, as each object has its own resource type
+// and manifests may set their own namespace. It returns the positions of the
+// objects that remain valid.
+func checkApplyAllObjects(ctx context.Context, sc *server.ServerContext, args map[string]interface{}, objects []*unstructured.Unstructured, plan []int, result *ApplyAllResult) []int {
+	valid := plan[:0]
+	for _, i := range plan {
+		out := &result.Objects[i]
+		if msg := tools.ObjectWriteDenied(ctx, sc, "apply_all", args, out.Namespace, objects[out.Index]); msg != "" {
+			out.Status = applyStatusInvalid
+			out.Error = msg
+			result.Invalid++
+			continue
+		}
+		valid = append(valid, i)
+	}
+	return valid
+}
+
+func applyAllResult(result *ApplyAllResult) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
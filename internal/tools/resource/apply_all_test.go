@@ -0,0 +1,228 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// applyAllMock records the objects applied and fails those named in failNames.
+type applyAllMock struct {
+	*testdata.MockK8sClient
+
+	applied   []string
+	failNames map[string]bool
+}
+
+func (m *applyAllMock) Apply(_ context.Context, _, namespace string, obj runtime.Object) (runtime.Object, error) {
+	u := obj.(*unstructured.Unstructured)
+	if m.failNames[u.GetName()] {
+		return nil, errors.New("admission webhook denied the request")
+	}
+	m.applied = append(m.applied, u.GetKind()+"/"+namespace+"/"+u.GetName())
+	return obj, nil
+}
+
+const applyAllBundle = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+# empty documents are skipped
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: settings
+    namespace: shop
+`
+
+func callApplyAll(t *testing.T, mock *applyAllMock, args map[string]interface{}, opts ...server.Option) (*mcp.CallToolResult, ApplyAllResult) {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(), append([]server.Option{
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handleApplyAll(context.Background(), request, sc)
+	require.NoError(t, err)
+
+	var out ApplyAllResult
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	}
+	return result, out
+}
+
+func TestDecodeManifests(t *testing.T) {
+	objects, err := decodeManifests(applyAllBundle)
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetKind())
+	}
+	assert.Equal(t, []string{"Deployment", "Service", "Namespace", "ConfigMap"}, kinds)
+
+	objects, err = decodeManifests(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "shop"}}`)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "shop", objects[0].GetName())
+
+	_, err = decodeManifests("kind: Namespace\n---\nkind: [unclosed")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "document 2")
+}
+
+func TestApplyAll_AppliesInDependencyOrder(t *testing.T) {
+	mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+
+	result, out := callApplyAll(t, mock, map[string]interface{}{
+		"namespace": "shop",
+		"manifests": applyAllBundle,
+	})
+	require.False(t, result.IsError)
+	assert.Equal(t, []string{
+		"Namespace//shop",
+		"ConfigMap/shop/settings",
+		"Service/shop/web",
+		"Deployment/shop/web",
+	}, mock.applied)
+	assert.Equal(t, 4, out.Applied)
+	assert.Zero(t, out.Failed)
+	assert.Equal(t, 2, out.Objects[0].Index, "the namespace is the third object in the bundle")
+}
+
+func TestApplyAll_ReportsFailuresPerObject(t *testing.T) {
+	mock := &applyAllMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		failNames:     map[string]bool{"settings": true},
+	}
+
+	result, out := callApplyAll(t, mock, map[string]interface{}{
+		"manifests": applyAllBundle,
+	})
+	require.False(t, result.IsError)
+	assert.Equal(t, 3, out.Applied)
+	assert.Equal(t, 1, out.Failed)
+	assert.Equal(t, applyStatusFailed, out.Objects[1].Status)
+	assert.Contains(t, out.Objects[1].Error, "admission webhook denied")
+
+	mock = &applyAllMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		failNames:     map[string]bool{"settings": true},
+	}
+	_, out = callApplyAll(t, mock, map[string]interface{}{
+		"manifests":   applyAllBundle,
+		"stopOnError": true,
+	})
+	assert.Equal(t, 1, out.Applied)
+	assert.Equal(t, 1, out.Failed)
+	assert.Equal(t, 2, out.Skipped)
+}
+
+func TestApplyAll_InvalidObjectAppliesNothing(t *testing.T) {
+	mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+
+	result, out := callApplyAll(t, mock, map[string]interface{}{
+		"manifests": applyAllBundle + "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n---\napiVersion: v1\nkind: Secret\n",
+	})
+	require.False(t, result.IsError)
+	assert.Empty(t, mock.applied)
+	assert.Equal(t, 2, out.Invalid)
+	assert.Equal(t, 4, out.Skipped)
+	assert.Contains(t, out.Message, "nothing was applied")
+
+	var errs []string
+	for _, obj := range out.Objects {
+		if obj.Status == applyStatusInvalid {
+			errs = append(errs, obj.Error)
+		}
+	}
+	assert.ElementsMatch(t, []string{"metadata.name is required", "duplicate of object 1"}, errs)
+}
+
+func TestApplyAll_ObjectOutsideWriteNamespaces(t *testing.T) {
+	mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+
+	result, out := callApplyAll(t, mock, map[string]interface{}{
+		"namespace": "team-a",
+		"manifests": applyAllBundle,
+	}, server.WithNamespaceAccess(nil, []string{"team-*"}, false))
+	require.False(t, result.IsError)
+	assert.Empty(t, mock.applied)
+	assert.Equal(t, 2, out.Invalid)
+	errs := map[string]string{}
+	for _, obj := range out.Objects {
+		if obj.Status == applyStatusInvalid {
+			errs[obj.Kind] = obj.Error
+		}
+	}
+	assert.Contains(t, errs["ConfigMap"], "apply_all in namespace shop is not allowed: writes are limited to namespaces team-*")
+	assert.Contains(t, errs["Namespace"], "apply_all of cluster-scoped namespaces is not allowed")
+
+	// With cluster-scoped writes allowed, only the ConfigMap is outside the
+	// allowlist.
+	result, out = callApplyAll(t, mock, map[string]interface{}{
+		"namespace": "team-a",
+		"manifests": applyAllBundle,
+	}, server.WithNamespaceAccess(nil, []string{"team-*"}, true))
+	require.False(t, result.IsError)
+	assert.Empty(t, mock.applied)
+	assert.Equal(t, 1, out.Invalid)
+}
+
+func TestApplyAll_PolicyDeniesObjectKind(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    resources: [configmaps]\n"))
+	require.NoError(t, err)
+	mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+
+	result, out := callApplyAll(t, mock, map[string]interface{}{
+		"manifests": applyAllBundle,
+	}, server.WithPolicyEngine(security.NewEngine(policy)))
+	require.False(t, result.IsError)
+	assert.Empty(t, mock.applied)
+	assert.Equal(t, 1, out.Invalid)
+	for _, obj := range out.Objects {
+		if obj.Status == applyStatusInvalid {
+			assert.Equal(t, "ConfigMap", obj.Kind)
+			assert.Contains(t, obj.Error, "apply_all configmaps in namespace shop is denied by policy")
+		}
+	}
+}
+
+func TestApplyAll_RejectsEmptyBundle(t *testing.T) {
+	result, _ := callApplyAll(t, &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}, map[string]interface{}{
+		"manifests": "---\n---\n",
+	})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,169 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+// ChangedSinceMetadata describes a changedSince list response.
+type ChangedSinceMetadata struct {
+	// Baseline is true when the session had not listed the query before, so
+	// every item is returned and recorded.
+	Baseline bool `json:"baseline"`
+
+	// Changed is the number of new or changed items, including those left
+	// out by the item cap.
+	Changed int `json:"changed"`
+
+	// Unchanged is the number of items left out because their
+	// resourceVersion did not change.
+	Unchanged int `json:"unchanged"`
+
+	// Pending is the number of changed items left out by the item cap. They
+	// are returned by the next call.
+	Pending int `json:"pending,omitempty"`
+
+	// Removed lists the items of the previous call that no longer exist or
+	// no longer match, as namespace/name or name.
+	Removed []string `json:"removed,omitempty"`
+}
+
+// scannedList is the outcome of scanList.
+type scannedList struct {
+	Items           []runtime.Object
+	ResourceVersion string
+	Meta            *k8s.ResponseMeta
+}
+
+// scanList pages through a complete list, applying the client-side filter
+// criteria per page. Like sampleList it stops after maxSamplePages pages,
+// but a changedSince diff of part of a list would report the rest as
+// removed, so an incomplete scan is an error.
+func scanList(ctx context.Context, fetch listPageFunc, criteria FilterCriteria) (*scannedList, error) {
+	result := &scannedList{}
+	continueToken := ""
+	for page := 0; page < maxSamplePages; page++ {
+		resp, err := fetch(ctx, continueToken)
+		if err != nil {
+			return nil, err
+		}
+		if page == 0 {
+			result.ResourceVersion = resp.ResourceVersion
+			result.Meta = resp.Meta
+		}
+
+		items := resp.Items
+		if len(criteria) > 0 {
+			if items, err = ApplyClientSideFilter(items, criteria); err != nil {
+				return nil, fmt.Errorf("%w: %v", errInvalidFilter, err)
+			}
+		}
+		result.Items = append(result.Items, items...)
+
+		continueToken = resp.Continue
+		if continueToken == "" {
+			return result, nil
+		}
+	}
+	return nil, errListTooLarge
+}
+
+// errListTooLarge is returned by scanList when a list has more pages than
+// it scans.
+var errListTooLarge = fmt.Errorf("the list has more than %d items; narrow it with namespace, labelSelector or fieldSelector", int64(maxSamplePages)*samplePageSize)
+
+// changedSinceQuery identifies a list for changedSince: two calls compare
+// their items only when every argument that selects them is the same.
+func changedSinceQuery(cluster, kubeContext, apiGroup, resourceType, namespace string, allNamespaces bool, labelSelector, fieldSelector string, criteria FilterCriteria) string {
+	filter := ""
+	if len(criteria) > 0 {
+		// Map keys are marshalled sorted, so equal filters give equal keys.
+		data, _ := json.Marshal(criteria)
+		filter = string(data)
+	}
+	return strings.Join([]string{
+		cluster, kubeContext, apiGroup, resourceType, namespace,
+		fmt.Sprint(allNamespaces), labelSelector, fieldSelector, filter,
+	}, "\x00")
+}
+
+// listedObject is an item of a changedSince list with its key and
+// resourceVersion.
+type listedObject struct {
+	Object  runtime.Object
+	Key     string
+	Version string
+}
+
+// diffListVersions compares items with the versions previously returned to
+// the session (nil for a baseline) and returns the new or changed items, in
+// list order, and the metadata of the response without Pending.
+func diffListVersions(items []runtime.Object, previous map[string]string, baseline bool) ([]listedObject, *ChangedSinceMetadata, error) {
+	metadata := &ChangedSinceMetadata{Baseline: baseline}
+	seen := make(map[string]bool, len(items))
+	var changed []listedObject
+	for _, item := range items {
+		key, version, err := listedVersion(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		seen[key] = true
+		if v, ok := previous[key]; ok && v == version && version != "" {
+			metadata.Unchanged++
+			continue
+		}
+		changed = append(changed, listedObject{Object: item, Key: key, Version: version})
+	}
+	metadata.Changed = len(changed)
+	for key := range previous {
+		if !seen[key] {
+			metadata.Removed = append(metadata.Removed, key)
+		}
+	}
+	slices.Sort(metadata.Removed)
+	return changed, metadata, nil
+}
+
+// nextListVersions returns the versions to record after a changedSince
+// call: unchanged items keep their version, the changed items in shown take
+// their new one, and changed items left out keep their previous version, or
+// none when they are new, so the next call returns them. Removed items are
+// dropped.
+func nextListVersions(items []runtime.Object, previous map[string]string, shown []listedObject) map[string]string {
+	next := make(map[string]string, len(items))
+	for _, item := range items {
+		key, _, err := listedVersion(item)
+		if err != nil {
+			continue
+		}
+		if v, ok := previous[key]; ok {
+			next[key] = v
+		}
+	}
+	for _, o := range shown {
+		next[o.Key] = o.Version
+	}
+	return next
+}
+
+// listedVersion returns the key of a list item, namespace/name or name, and
+// its resourceVersion.
+func listedVersion(item runtime.Object) (string, string, error) {
+	accessor, err := meta.Accessor(item)
+	if err != nil {
+		return "", "", err
+	}
+	key := accessor.GetName()
+	if ns := accessor.GetNamespace(); ns != "" {
+		key = ns + "/" + key
+	}
+	return key, accessor.GetResourceVersion(), nil
+}
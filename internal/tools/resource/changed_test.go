@@ -0,0 +1,198 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// listSession is a ClientSession with an ID.
+type listSession struct{ id string }
+
+func (s *listSession) Initialize()                                         {}
+func (s *listSession) Initialized() bool                                   { return true }
+func (s *listSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *listSession) SessionID() string                                   { return s.id }
+
+// versionedPods returns pods with the given names in the default namespace
+// and the resourceVersions versions maps them to.
+func versionedPods(names []string, versions map[string]string) []runtime.Object {
+	pods := make([]runtime.Object, 0, len(names))
+	for _, name := range names {
+		pod := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+		}}
+		pod.SetName(name)
+		pod.SetNamespace("default")
+		pod.SetResourceVersion(versions[name])
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func TestDiffListVersions(t *testing.T) {
+	pods := versionedPods([]string{"a", "b", "c"}, map[string]string{"a": "1", "b": "2", "c": "3"})
+
+	changed, metadata, err := diffListVersions(pods, nil, true)
+	require.NoError(t, err)
+	assert.Len(t, changed, 3)
+	assert.Equal(t, &ChangedSinceMetadata{Baseline: true, Changed: 3}, metadata)
+
+	previous := map[string]string{"default/a": "1", "default/b": "1", "default/gone": "7"}
+	changed, metadata, err = diffListVersions(pods, previous, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default/b", "default/c"}, []string{changed[0].Key, changed[1].Key})
+	assert.Equal(t, &ChangedSinceMetadata{Changed: 2, Unchanged: 1, Removed: []string{"default/gone"}}, metadata)
+
+	next := nextListVersions(pods, previous, changed[:1])
+	assert.Equal(t, map[string]string{"default/a": "1", "default/b": "2"}, next,
+		"changed items not shown keep their old version and removed ones are dropped")
+}
+
+func TestChangedSinceQuery(t *testing.T) {
+	a := changedSinceQuery("", "", "", "pods", "default", false, "", "", FilterCriteria{"x": "1", "y": "2"})
+	b := changedSinceQuery("", "", "", "pods", "default", false, "", "", FilterCriteria{"y": "2", "x": "1"})
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, changedSinceQuery("", "", "", "pods", "kube-system", false, "", "", FilterCriteria{"x": "1", "y": "2"}))
+	assert.NotEqual(t, a, changedSinceQuery("", "", "", "pods", "default", false, "app=x", "", FilterCriteria{"x": "1", "y": "2"}))
+}
+
+func TestScanList_TooLarge(t *testing.T) {
+	pages := 0
+	_, err := scanList(context.Background(), pagedFetch(samplePods(maxSamplePages+1), 1, &pages), nil)
+	assert.ErrorIs(t, err, errListTooLarge)
+}
+
+func TestHandleListResources_ChangedSince(t *testing.T) {
+	versions := map[string]string{"a": "1", "b": "1", "c": "1"}
+	client := &pagingK8sClient{objects: versionedPods([]string{"a", "b", "c"}, versions)}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(client),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+	ctx := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), &listSession{id: "s1"})
+
+	list := func(ctx context.Context) (PaginatedSummaryResponse, ChangedSinceMetadata) {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"resourceType": "pods",
+			"changedSince": true,
+		}
+		result, err := handleListResources(ctx, request, sc)
+		require.NoError(t, err)
+		require.False(t, result.IsError, getErrorText(t, result))
+
+		var response PaginatedSummaryResponse
+		require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &response))
+		data, err := json.Marshal(response.Metadata["changedSince"])
+		require.NoError(t, err)
+		var metadata ChangedSinceMetadata
+		require.NoError(t, json.Unmarshal(data, &metadata))
+		return response, metadata
+	}
+	names := func(response PaginatedSummaryResponse) []string {
+		var names []string
+		for _, item := range response.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	response, metadata := list(ctx)
+	assert.Equal(t, []string{"a", "b", "c"}, names(response))
+	assert.Equal(t, ChangedSinceMetadata{Baseline: true, Changed: 3}, metadata)
+
+	response, metadata = list(ctx)
+	assert.Empty(t, response.Items)
+	assert.Equal(t, ChangedSinceMetadata{Unchanged: 3}, metadata)
+
+	versions["b"] = "2"
+	client.objects = versionedPods([]string{"b", "c", "d"}, versions)
+	response, metadata = list(ctx)
+	assert.Equal(t, []string{"b", "d"}, names(response))
+	assert.Equal(t, ChangedSinceMetadata{Changed: 2, Unchanged: 1, Removed: []string{"default/a"}}, metadata)
+
+	other := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), &listSession{id: "s2"})
+	_, metadata = list(other)
+	assert.True(t, metadata.Baseline, "versions are per session")
+}
+
+func TestHandleListResources_ChangedSincePending(t *testing.T) {
+	versions := map[string]string{"a": "1", "b": "1", "c": "1"}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&pagingK8sClient{objects: versionedPods([]string{"a", "b", "c"}, versions)}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithOutputConfig(&server.OutputConfig{MaxItems: 2, SlimOutput: true, MaskSecrets: true}),
+	)
+	require.NoError(t, err)
+	ctx := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), &listSession{id: "s1"})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resourceType": "pods",
+		"changedSince": true,
+		"fullOutput":   true,
+	}
+	var response struct {
+		Items        []map[string]interface{} `json:"items"`
+		ChangedSince ChangedSinceMetadata     `json:"changedSince"`
+	}
+	result, err := handleListResources(ctx, request, sc)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getErrorText(t, result))
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &response))
+	assert.Len(t, response.Items, 2)
+	assert.Equal(t, ChangedSinceMetadata{Baseline: true, Changed: 3, Pending: 1}, response.ChangedSince)
+
+	response.Items, response.ChangedSince = nil, ChangedSinceMetadata{}
+	result, err = handleListResources(ctx, request, sc)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &response))
+	require.Len(t, response.Items, 1, "items left out by the cap are returned next")
+	assert.Equal(t, ChangedSinceMetadata{Changed: 1, Unchanged: 2}, response.ChangedSince)
+}
+
+func TestHandleListResources_ChangedSinceValidation(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&pagingK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+	ctx := mcpserver.NewMCPServer("test", "1.0").WithContext(context.Background(), &listSession{id: "s1"})
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		args map[string]interface{}
+		want string
+	}{
+		"no session": {context.Background(), map[string]interface{}{}, "MCP session"},
+		"continue":   {ctx, map[string]interface{}{"continue": "x"}, "cannot be combined"},
+		"summary":    {ctx, map[string]interface{}{"summary": true}, "cannot be combined"},
+		"sample":     {ctx, map[string]interface{}{"sample": float64(3)}, "cannot be combined"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{"resourceType": "pods", "changedSince": true}
+			for k, v := range tc.args {
+				request.Params.Arguments.(map[string]interface{})[k] = v
+			}
+			result, err := handleListResources(tc.ctx, request, sc)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, getErrorText(t, result), tc.want)
+		})
+	}
+}
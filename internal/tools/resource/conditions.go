@@ -0,0 +1,356 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the conditions tool's limit param, and the
+// longest condition message returned.
+const (
+	DefaultConditionsLimit  = 50
+	MaxConditionsLimit      = 1000
+	maxConditionMessageRune = 300
+)
+
+// Normalized condition statuses.
+const (
+	conditionTrue    = "True"
+	conditionFalse   = "False"
+	conditionUnknown = "Unknown"
+)
+
+// abnormalTrueSuffixes end the types of conditions that report a problem
+// when True, such as Stalled, Degraded, MemoryPressure or InstallFailed.
+// The API conventions prefer positive conditions, where False is the
+// problem, but many operators and Kubernetes itself use both.
+var abnormalTrueSuffixes = []string{"Stalled", "Degraded", "Pressure", "Failed", "Failure", "Error", "Unavailable"}
+
+// neutralConditionTypes report progress rather than health and are never a
+// problem on their own.
+var neutralConditionTypes = []string{"Reconciling"}
+
+// ConditionsResult is the output of the conditions tool.
+type ConditionsResult struct {
+	Kind string `json:"kind,omitempty"`
+
+	// Total counts the objects matched, Healthy and Unhealthy those with
+	// conditions. WithoutConditions have none, or none of the requested
+	// types.
+	Total             int `json:"total"`
+	Healthy           int `json:"healthy"`
+	Unhealthy         int `json:"unhealthy"`
+	WithoutConditions int `json:"withoutConditions,omitempty"`
+
+	// Types counts the statuses of each condition type across all objects,
+	// e.g. Ready: {True: 8, False: 2}.
+	Types map[string]map[string]int `json:"types,omitempty"`
+
+	// Objects lists unhealthy objects first.
+	Objects   []ObjectConditions `json:"objects"`
+	Returned  int                `json:"returned"`
+	Truncated bool               `json:"truncated,omitempty"`
+
+	// Incomplete is set when the list was too long to scan entirely; the
+	// counts then cover the scanned objects only.
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// ObjectConditions is an object in the output of the conditions tool.
+type ObjectConditions struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+
+	// Problems lists the types of the conditions in an abnormal state.
+	Problems   []string    `json:"problems,omitempty"`
+	Conditions []Condition `json:"conditions"`
+}
+
+// Condition is a status condition normalized to the metav1.Condition
+// fields, whatever the object's own condition type.
+type Condition struct {
+	Type   string `json:"type"`
+	Status string `json:"status,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// Message is cut to maxConditionMessageRune runes.
+	Message string `json:"message,omitempty"`
+
+	// LastTransition falls back to lastUpdateTime or lastHeartbeatTime for
+	// condition types without lastTransitionTime.
+	LastTransition string `json:"lastTransition,omitempty"`
+	Age            string `json:"age,omitempty"`
+
+	// Stale is set when the condition was computed for an older generation
+	// of the object, so it says nothing about the current spec.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// conditionsRequest holds the validated conditions arguments.
+type conditionsRequest struct {
+	cluster        string
+	kubeContext    string
+	namespace      string
+	allNamespaces  bool
+	resourceType   string
+	apiGroup       string
+	labelSelector  string
+	conditionTypes []string
+	problemsOnly   bool
+	limit          int
+}
+
+// handleConditions summarizes the status conditions of every object of a
+// type, for a health overview of custom resources without kind-specific
+// code.
+func handleConditions(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseConditionsRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	result, err := collectConditions(ctx, client.K8s(), req)
+	if err != nil {
+		return tools.K8sError("Failed to list resources", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseConditionsRequest validates the conditions arguments. The schema
+// enforces the ranges, but they are checked again for non-compliant
+// clients.
+func parseConditionsRequest(args map[string]interface{}) (conditionsRequest, string) {
+	req := conditionsRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultConditionsLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.apiGroup, _ = args["apiGroup"].(string)
+	req.resourceType, _ = args["resourceType"].(string)
+	if req.resourceType == "" {
+		return req, "resourceType is required"
+	}
+	req.allNamespaces, _ = args["allNamespaces"].(bool)
+	req.namespace, _ = args["namespace"].(string)
+	if !req.allNamespaces && req.namespace == "" {
+		req.namespace = k8s.DefaultNamespace
+	}
+	req.problemsOnly, _ = args["problemsOnly"].(bool)
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxConditionsLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxConditionsLimit)
+		}
+		req.limit = int(v)
+	}
+
+	req.labelSelector, _ = args["labelSelector"].(string)
+	if req.labelSelector != "" {
+		if _, err := labels.Parse(req.labelSelector); err != nil {
+			return req, fmt.Sprintf("invalid labelSelector: %v", err)
+		}
+	}
+
+	if v, _ := args["conditionTypes"].(string); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				req.conditionTypes = append(req.conditionTypes, t)
+			}
+		}
+	}
+	return req, ""
+}
+
+// collectConditions pages through the objects of a type and normalizes
+// their conditions. Like sampling, it stops after maxSamplePages pages.
+func collectConditions(ctx context.Context, client k8s.Client, req conditionsRequest) (*ConditionsResult, error) {
+	result := &ConditionsResult{Types: map[string]map[string]int{}}
+	opts := k8s.ListOptions{
+		LabelSelector: req.labelSelector,
+		AllNamespaces: req.allNamespaces,
+		Limit:         samplePageSize,
+	}
+
+	var objects []ObjectConditions
+	result.Incomplete = true
+	for page := 0; page < maxSamplePages; page++ {
+		resp, err := client.List(ctx, req.kubeContext, req.namespace, req.resourceType, req.apiGroup, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			obj, err := toUnstructuredObject(item)
+			if err != nil {
+				continue
+			}
+			if result.Kind == "" {
+				result.Kind = obj.GetKind()
+			}
+			result.Total++
+
+			oc, ok := objectConditions(obj, req.conditionTypes)
+			if !ok {
+				result.WithoutConditions++
+				continue
+			}
+			for _, c := range oc.Conditions {
+				if result.Types[c.Type] == nil {
+					result.Types[c.Type] = map[string]int{}
+				}
+				result.Types[c.Type][c.Status]++
+			}
+			if oc.Healthy {
+				result.Healthy++
+				if req.problemsOnly {
+					continue
+				}
+			} else {
+				result.Unhealthy++
+			}
+			objects = append(objects, oc)
+		}
+		if resp.Continue == "" {
+			result.Incomplete = false
+			break
+		}
+		opts.Continue = resp.Continue
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		if objects[i].Healthy != objects[j].Healthy {
+			return !objects[i].Healthy
+		}
+		if objects[i].Namespace != objects[j].Namespace {
+			return objects[i].Namespace < objects[j].Namespace
+		}
+		return objects[i].Name < objects[j].Name
+	})
+	if len(objects) > req.limit {
+		objects = objects[:req.limit]
+		result.Truncated = true
+	}
+	if objects == nil {
+		objects = []ObjectConditions{}
+	}
+	result.Objects = objects
+	result.Returned = len(objects)
+	if len(result.Types) == 0 {
+		result.Types = nil
+	}
+	return result, nil
+}
+
+// objectConditions normalizes the status.conditions of obj, keeping only
+// the given types when there are any. It returns false when no condition
+// is left.
+func objectConditions(obj *unstructured.Unstructured, types []string) (ObjectConditions, bool) {
+	oc := ObjectConditions{Namespace: obj.GetNamespace(), Name: obj.GetName(), Healthy: true}
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := normalizeCondition(m, obj.GetGeneration())
+		if c.Type == "" || (len(types) > 0 && !containsFold(types, c.Type)) {
+			continue
+		}
+		if conditionProblem(c) {
+			oc.Healthy = false
+			oc.Problems = append(oc.Problems, c.Type)
+		}
+		oc.Conditions = append(oc.Conditions, c)
+	}
+	return oc, len(oc.Conditions) > 0
+}
+
+// normalizeCondition reads a condition into the metav1.Condition fields.
+// Statuses are normalized to True, False and Unknown whatever their case.
+func normalizeCondition(m map[string]interface{}, generation int64) Condition {
+	c := Condition{}
+	c.Type, _ = m["type"].(string)
+	c.Status, _ = m["status"].(string)
+	for _, s := range []string{conditionTrue, conditionFalse, conditionUnknown} {
+		if strings.EqualFold(c.Status, s) {
+			c.Status = s
+		}
+	}
+	c.Reason, _ = m["reason"].(string)
+	if msg, _ := m["message"].(string); msg != "" {
+		c.Message, _ = truncateRunes(strings.TrimSpace(msg), maxConditionMessageRune)
+	}
+	for _, field := range []string{"lastTransitionTime", "lastUpdateTime", "lastHeartbeatTime"} {
+		if v, _ := m[field].(string); v != "" {
+			c.LastTransition = v
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				c.Age = formatAge(t)
+			}
+			break
+		}
+	}
+	switch gen := m["observedGeneration"].(type) {
+	case int64:
+		c.Stale = gen > 0 && gen < generation
+	case float64:
+		c.Stale = gen > 0 && int64(gen) < generation
+	}
+	return c
+}
+
+// conditionProblem reports whether a condition is in an abnormal state:
+// Unknown, False for a positive condition such as Ready, or True for a
+// negative one such as Stalled. Conditions without a status, as Argo CD
+// sets them, are present only while they hold.
+func conditionProblem(c Condition) bool {
+	if containsFold(neutralConditionTypes, c.Type) {
+		return false
+	}
+	abnormalTrue := false
+	for _, suffix := range abnormalTrueSuffixes {
+		if strings.HasSuffix(c.Type, suffix) {
+			abnormalTrue = true
+			break
+		}
+	}
+	switch c.Status {
+	case conditionUnknown:
+		return true
+	case conditionFalse:
+		return !abnormalTrue
+	}
+	return abnormalTrue
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
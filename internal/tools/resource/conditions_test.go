@@ -0,0 +1,175 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// conditionsMock serves its objects in pages of one.
+type conditionsMock struct {
+	*testdata.MockK8sClient
+
+	objects []runtime.Object
+	opts    []k8s.ListOptions
+}
+
+func (m *conditionsMock) List(_ context.Context, _, _, _, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	m.opts = append(m.opts, opts)
+	i := 0
+	if opts.Continue != "" {
+		i = int(opts.Continue[0] - '0')
+	}
+	resp := &k8s.PaginatedListResponse{Items: m.objects[i : i+1]}
+	if i+1 < len(m.objects) {
+		resp.Continue = string(rune('0' + i + 1))
+	}
+	return resp, nil
+}
+
+func conditionsObject(namespace, name string, generation int64, conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name, "generation": generation},
+		"status":     map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func newConditionsMock() *conditionsMock {
+	return &conditionsMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects: []runtime.Object{
+			conditionsObject("shop", "web", 1,
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready", "lastTransitionTime": "2024-05-01T10:00:00Z", "observedGeneration": int64(1)}),
+			conditionsObject("shop", "api", 2,
+				map[string]interface{}{"type": "Ready", "status": "false", "reason": "DoesNotExist", "message": "  Issuing certificate as Secret does not exist\n", "observedGeneration": int64(1)},
+				map[string]interface{}{"type": "Issuing", "status": "True"}),
+			conditionsObject("billing", "invoice", 1,
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Stalled", "status": "True", "reason": "RetryExhausted"},
+				map[string]interface{}{"type": "Reconciling", "status": "False"}),
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "cert-manager.io/v1",
+				"kind":       "Certificate",
+				"metadata":   map[string]interface{}{"namespace": "shop", "name": "new"},
+			}},
+		},
+	}
+}
+
+func TestCollectConditions(t *testing.T) {
+	mock := newConditionsMock()
+	result, err := collectConditions(context.Background(), mock, conditionsRequest{allNamespaces: true, limit: DefaultConditionsLimit})
+	require.NoError(t, err)
+
+	// Every page is read.
+	require.Len(t, mock.opts, 4)
+	assert.True(t, mock.opts[0].AllNamespaces)
+	assert.Equal(t, "3", mock.opts[3].Continue)
+
+	assert.Equal(t, "Certificate", result.Kind)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 1, result.Healthy)
+	assert.Equal(t, 2, result.Unhealthy)
+	assert.Equal(t, 1, result.WithoutConditions)
+	assert.False(t, result.Incomplete)
+	assert.Equal(t, map[string]int{"True": 2, "False": 1}, result.Types["Ready"])
+	assert.Equal(t, map[string]int{"True": 1}, result.Types["Stalled"])
+
+	// Unhealthy objects come first, by namespace and name.
+	require.Len(t, result.Objects, 3)
+	invoice := result.Objects[0]
+	assert.Equal(t, "invoice", invoice.Name)
+	assert.False(t, invoice.Healthy)
+	assert.Equal(t, []string{"Stalled"}, invoice.Problems)
+
+	api := result.Objects[1]
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, []string{"Ready"}, api.Problems)
+	assert.Equal(t, Condition{
+		Type: "Ready", Status: "False", Reason: "DoesNotExist",
+		Message: "Issuing certificate as Secret does not exist", Stale: true,
+	}, api.Conditions[0])
+
+	web := result.Objects[2]
+	assert.True(t, web.Healthy)
+	assert.Equal(t, "2024-05-01T10:00:00Z", web.Conditions[0].LastTransition)
+	assert.NotEmpty(t, web.Conditions[0].Age)
+	assert.False(t, web.Conditions[0].Stale)
+}
+
+func TestCollectConditions_Filters(t *testing.T) {
+	result, err := collectConditions(context.Background(), newConditionsMock(), conditionsRequest{problemsOnly: true, limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Unhealthy)
+	assert.True(t, result.Truncated)
+	require.Len(t, result.Objects, 1)
+	assert.Equal(t, "invoice", result.Objects[0].Name)
+
+	result, err = collectConditions(context.Background(), newConditionsMock(), conditionsRequest{conditionTypes: []string{"ready"}, limit: DefaultConditionsLimit})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Healthy)
+	assert.Equal(t, 1, result.Unhealthy)
+	assert.Equal(t, []string{"Ready"}, slices.Collect(maps.Keys(result.Types)))
+}
+
+func TestConditionProblem(t *testing.T) {
+	for _, tc := range []struct {
+		condition Condition
+		want      bool
+	}{
+		{Condition{Type: "Ready", Status: "True"}, false},
+		{Condition{Type: "Ready", Status: "False"}, true},
+		{Condition{Type: "Ready", Status: "Unknown"}, true},
+		{Condition{Type: "MemoryPressure", Status: "False"}, false},
+		{Condition{Type: "MemoryPressure", Status: "True"}, true},
+		{Condition{Type: "InstallFailed", Status: "True"}, true},
+		{Condition{Type: "Reconciling", Status: "True"}, false},
+		// Argo CD sets conditions without a status while they hold.
+		{Condition{Type: "SyncError"}, true},
+	} {
+		assert.Equal(t, tc.want, conditionProblem(tc.condition), "%s=%s", tc.condition.Type, tc.condition.Status)
+	}
+}
+
+func TestHandleConditions(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(newConditionsMock()),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"resourceType": "certificates", "limit": float64(0)},
+		{"resourceType": "certificates", "labelSelector": "app in ("},
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handleConditions(context.Background(), request, sc)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "%v", args)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"resourceType": "certificates", "allNamespaces": true}
+	result, err := handleConditions(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var out ConditionsResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, 4, out.Total)
+}
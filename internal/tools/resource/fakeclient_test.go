@@ -0,0 +1,16 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// callResourceTool calls a resource tool with client through mcptest.
+func callResourceTool(t *testing.T, client k8s.Client, toolName string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	return mcptest.CallRegisteredTool(t, client, RegisterResourceTools, toolName, args)
+}
@@ -3,23 +3,30 @@ package resource
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/logging"
+	"github.com/giantswarm/mcp-kubernetes/internal/preferences"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
 )
 
@@ -35,6 +42,63 @@ func checkMutatingOperation(sc *server.ServerContext, operation string) *mcp.Cal
 	return tools.CheckMutatingOperation(sc, operation)
 }
 
+// isSecretResourceType reports whether resourceType and apiGroup name core
+// Secrets.
+func isSecretResourceType(resourceType, apiGroup string) bool {
+	switch strings.ToLower(resourceType) {
+	case "secret", "secrets":
+		return apiGroup == "" || apiGroup == "core"
+	}
+	return false
+}
+
+// isSecretObject reports whether obj is a core Secret.
+func isSecretObject(obj *unstructured.Unstructured) bool {
+	return obj.GetKind() == "Secret" && obj.GroupVersionKind().Group == ""
+}
+
+// undoTargetOf identifies a manifest object for the undo journal by its
+// kind and API group and version.
+func undoTargetOf(cluster, kubeContext, namespace string, obj *unstructured.Unstructured) tools.UndoTarget {
+	gvk := obj.GroupVersionKind()
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	// Like the create and apply calls, the namespace argument wins over
+	// the object's.
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	return tools.UndoTarget{
+		Cluster:      cluster,
+		KubeContext:  kubeContext,
+		Namespace:    namespace,
+		ResourceType: gvk.Kind,
+		APIGroup:     group + "/" + gvk.Version,
+		Name:         obj.GetName(),
+	}
+}
+
+// checkSecretObjects refuses a manifest holding a Secret when the server
+// blocks Secret writes. Returns nil if the manifest holds no Secret or the
+// write is allowed.
+func checkSecretObjects(sc *server.ServerContext, args map[string]interface{}, objects []*unstructured.Unstructured) *mcp.CallToolResult {
+	var names []string
+	for _, obj := range objects {
+		if isSecretObject(obj) {
+			names = append(names, "Secret/"+obj.GetName())
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if reason := tools.SecretWriteDenied(sc, args); reason != "" {
+		return toolerrors.Newf(toolerrors.CodeOperationNotAllowed, "%s (manifest contains %s)", reason, strings.Join(names, ", ")).Result()
+	}
+	return nil
+}
+
 // handleGetResource handles kubectl get operations
 func handleGetResource(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
@@ -54,12 +118,12 @@ func handleGetResource(ctx context.Context, request mcp.CallToolRequest, sc *ser
 
 	resourceType, ok := args["resourceType"].(string)
 	if !ok || resourceType == "" {
-		return mcp.NewToolResultError("resourceType is required"), nil
+		return toolerrors.Required("resourceType").Result(), nil
 	}
 
 	name, ok := args["name"].(string)
 	if !ok || name == "" {
-		return mcp.NewToolResultError("name is required"), nil
+		return toolerrors.Required("name").Result(), nil
 	}
 
 	// Output format mirrors the list tool: slim (default) and normal go through
@@ -67,9 +131,9 @@ func handleGetResource(ctx context.Context, request mcp.CallToolRequest, sc *ser
 	outputFormat, _ := args["output"].(string)
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
@@ -79,16 +143,16 @@ func handleGetResource(ctx context.Context, request mcp.CallToolRequest, sc *ser
 
 	if err != nil {
 		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationGet, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to get resource", err, client.User())), nil
+		return tools.K8sError("Failed to get resource", err, client.User()).Result(), nil
 	}
 
 	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationGet, resourceType, namespace, instrumentation.StatusSuccess, duration)
 
 	// Apply output processing (slim output, secret masking)
-	processor := getOutputProcessorForFormat(sc, outputFormat)
-	processedObj, err := output.ProcessSingleRuntimeObject(processor, getResponse.Resource)
+	processor := getOutputProcessorForFormat(ctx, sc, clusterName, outputFormat)
+	processedObj, err := tools.ProcessSingleRuntimeObject(ctx, processor, getResponse.Resource)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to process resource: %v", err)), nil
+		return toolerrors.Internalf("Failed to process resource: %v", err).Result(), nil
 	}
 
 	// Build response with metadata
@@ -98,14 +162,17 @@ func handleGetResource(ctx context.Context, request mcp.CallToolRequest, sc *ser
 	}
 
 	// Convert the response to JSON for output
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+	jsonData, err := tools.MarshalResponse(ctx, response, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal resource: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal resource: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// outputTable is the list output format that renders items as table rows.
+const outputTable = "table"
+
 // getOutputProcessorForFormat builds an output processor that honours the
 // per-call output format, while preserving server-level secret masking.
 //
@@ -119,13 +186,18 @@ func handleGetResource(ctx context.Context, request mcp.CallToolRequest, sc *ser
 //   - "slim" (and empty/default): SlimOutput=true, KindShaping=true.
 //     Blacklist exclusion + per-Kind shaping (HelmRelease drops spec.values
 //     and status.history; Deployment / StatefulSet / DaemonSet collapse long
-//     container env lists). This is the LLM-friendly default per #410.
+//     container env lists) + the configured kind profiles on lists. This is
+//     the LLM-friendly default per #410.
+//   - "table" (list only): processed like "slim", without the configured
+//     kind profiles, then rendered as compact rows by output.BuildTable.
 //
-// Secret masking is always driven by the server-level MaskSecrets setting
-// and is never disabled by output format — every read tool honours that
-// contract uniformly.
-func getOutputProcessorForFormat(sc *server.ServerContext, outputFormat string) *output.Processor {
-	outputCfg := sc.OutputConfig()
+// Secret masking and PII scrubbing are always driven by the server-level
+// MaskSecrets / ScrubPII settings, with the overrides of clusterName
+// applied, and are never disabled by output format — every read tool
+// honours that contract uniformly. The fields the caller
+// asked to mask in their preferences are masked on top, in every format.
+func getOutputProcessorForFormat(ctx context.Context, sc *server.ServerContext, clusterName, outputFormat string) *output.Processor {
+	outputCfg := sc.OutputConfigFor(clusterName)
 	slim := outputCfg.SlimOutput
 	kindShaping := slim
 	switch outputFormat {
@@ -134,9 +206,10 @@ func getOutputProcessorForFormat(sc *server.ServerContext, outputFormat string)
 		kindShaping = false
 	case "normal":
 		kindShaping = false
-	case "slim", "":
+	case "slim", "table", "":
 		// keep slim from server config; kindShaping mirrors slim so a
-		// server with SlimOutput=false still returns wide for slim.
+		// server with SlimOutput=false still returns wide for slim. Tables
+		// are built from the slim items.
 	}
 	cfg := &output.Config{
 		MaxItems:         outputCfg.MaxItems,
@@ -145,9 +218,20 @@ func getOutputProcessorForFormat(sc *server.ServerContext, outputFormat string)
 		SlimOutput:       slim,
 		KindShaping:      kindShaping,
 		MaskSecrets:      outputCfg.MaskSecrets,
+		SecretRedaction:  outputCfg.SecretRedaction,
+		ScrubPII:         outputCfg.ScrubPII,
+		PIIPatterns:      outputCfg.PIIPatterns,
 		SummaryThreshold: outputCfg.SummaryThreshold,
+		MaskedFields:     preferences.FromContext(ctx).MaskedFields,
 	}
-	return output.NewProcessor(cfg)
+	// Tables read their columns from the items, so the configured kind
+	// profiles only slim objects.
+	if outputFormat != outputTable {
+		cfg.KindProfiles = outputCfg.KindProfiles
+	}
+	return output.NewProcessor(cfg).WithScrubRecorder(func(pattern string, count int) {
+		sc.RecordPIIScrubbed(context.Background(), pattern, count)
+	})
 }
 
 // processorWithExtraExcluded returns a new Processor whose ExcludedFields
@@ -159,7 +243,7 @@ func getOutputProcessorForFormat(sc *server.ServerContext, outputFormat string)
 func processorWithExtraExcluded(p *output.Processor, extra []string) *output.Processor {
 	cfg := p.Config().Clone()
 	cfg.ExcludedFields = append(cfg.ExcludedFields, extra...)
-	return output.NewProcessor(cfg)
+	return output.NewProcessor(cfg).WithScrubRecorder(p.ScrubRecorder())
 }
 
 // listKindRules bundles the per-resourceType slim-mode customisations
@@ -300,7 +384,7 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 
 	resourceType, ok := args["resourceType"].(string)
 	if !ok || resourceType == "" {
-		return mcp.NewToolResultError("resourceType is required"), nil
+		return toolerrors.Required("resourceType").Result(), nil
 	}
 
 	namespace, _ := args["namespace"].(string)
@@ -321,7 +405,7 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 	if filterArg, ok := args["filter"]; ok {
 		filterMap, ok := filterArg.(map[string]interface{})
 		if !ok {
-			return mcp.NewToolResultError("filter parameter must be an object/map"), nil
+			return toolerrors.InvalidArgument("filter parameter must be an object/map").Result(), nil
 		}
 		filterCriteria = filterMap
 	}
@@ -338,6 +422,22 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 	// server-configured slim setting via getOutputProcessorForFormat.
 	outputFormat, _ := args["output"].(string)
 
+	// Markdown and CSV render the items as a table, like output=table.
+	textFormat, _ := args["format"].(string)
+	switch textFormat {
+	case "", output.FormatJSON:
+		textFormat = output.FormatJSON
+	case output.FormatMarkdown, output.FormatCSV:
+		if fullOutput {
+			return toolerrors.InvalidArgumentf("format %s cannot be combined with fullOutput", textFormat).Result(), nil
+		}
+		if outputFormat == "" || outputFormat == "slim" {
+			outputFormat = outputTable
+		}
+	default:
+		return toolerrors.InvalidArgumentf("format must be one of %s, %s or %s", output.FormatJSON, output.FormatMarkdown, output.FormatCSV).Result(), nil
+	}
+
 	// Per-resourceType slim extensions. The list path historically only
 	// applies DefaultExcludedFields to items, but Events benefit from the
 	// same per-event strip already used by handleDescribeResource — see
@@ -356,6 +456,35 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 	}
 	continueToken, _ := args["continue"].(string)
 
+	// Sampling scans every page and returns a uniform random subset plus
+	// the exact total, so it replaces pagination and summary mode.
+	sampleSize := 0
+	if v, ok := args["sample"].(float64); ok {
+		sampleSize = int(v)
+		if sampleSize < 1 {
+			return toolerrors.InvalidArgument("sample must be a positive number of items").Result(), nil
+		}
+		if continueToken != "" || summaryMode {
+			return toolerrors.InvalidArgument("sample cannot be combined with continue or summary").Result(), nil
+		}
+	}
+
+	// changedSince scans the whole list too and returns the items whose
+	// resourceVersion changed since the session's previous call.
+	changedSince, _ := args["changedSince"].(bool)
+	session := ""
+	if changedSince {
+		if continueToken != "" || summaryMode || sampleSize > 0 {
+			return toolerrors.InvalidArgument("changedSince cannot be combined with continue, summary or sample").Result(), nil
+		}
+		if s := mcpserver.ClientSessionFromContext(ctx); s != nil {
+			session = s.SessionID()
+		}
+		if session == "" {
+			return toolerrors.New(toolerrors.CodeFailedPrecondition, "changedSince needs an MCP session, which this connection does not have").Result(), nil
+		}
+	}
+
 	opts := k8s.ListOptions{
 		LabelSelector: labelSelector,
 		FieldSelector: fieldSelector,
@@ -372,15 +501,98 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 	slog.Debug("acquired cluster client", slog.Duration("elapsed", time.Since(handlerStart)))
 
+	// Build output processor honouring the per-call format. SlimOutput is
+	// flipped off for output=wide; secret masking always runs regardless of
+	// format so the documented contract holds across every read tool.
+	processor := getOutputProcessorForFormat(ctx, sc, clusterName, outputFormat)
+	if len(extraExcluded) > 0 && processor.Config().SlimOutput {
+		processor = processorWithExtraExcluded(processor, extraExcluded)
+	}
+
+	// A sample larger than the processor's item cap would be truncated to a
+	// biased prefix, so reject it up front.
+	if maxSample := processor.Config().MaxItems; sampleSize > maxSample {
+		return toolerrors.InvalidArgumentf("sample must be between 1 and %d", maxSample).Result(), nil
+	}
+
 	k8sStart := time.Now()
-	paginatedResponse, err := k8sClient.List(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+	var paginatedResponse *k8s.PaginatedListResponse
+	var sampleMeta *SampleMetadata
+	var changedMeta *ChangedSinceMetadata
+	var changedQuery string
+	var scanned []runtime.Object
+	var previous map[string]string
+	var shown []listedObject
+	var err error
+	if changedSince {
+		var list *scannedList
+		list, err = scanList(ctx, func(ctx context.Context, continueToken string) (*k8s.PaginatedListResponse, error) {
+			pageOpts := opts
+			pageOpts.Limit = samplePageSize
+			pageOpts.Continue = continueToken
+			return k8sClient.List(ctx, kubeContext, namespace, resourceType, apiGroup, pageOpts)
+		}, filterCriteria)
+		if errors.Is(err, errInvalidFilter) {
+			return toolerrors.InvalidArgumentf("Invalid filter criteria: %v", err).Result(), nil
+		}
+		if errors.Is(err, errListTooLarge) {
+			return toolerrors.New(toolerrors.CodeFailedPrecondition, "changedSince: "+err.Error()).Result(), nil
+		}
+		if list != nil {
+			changedQuery = changedSinceQuery(clusterName, kubeContext, apiGroup, resourceType, namespace, allNamespaces, labelSelector, fieldSelector, filterCriteria)
+			var known bool
+			previous, known = sc.ListVersions().Get(session, changedQuery)
+			var changed []listedObject
+			changed, changedMeta, err = diffListVersions(list.Items, previous, !known)
+			if err != nil {
+				return toolerrors.Internalf("Failed to read resource versions: %v", err).Result(), nil
+			}
+			// Changed items beyond the item cap are returned by the next
+			// call, rather than truncated by the processor.
+			shown = changed[:min(len(changed), processor.Config().MaxItems)]
+			changedMeta.Pending = len(changed) - len(shown)
+			scanned = list.Items
+			items := make([]runtime.Object, len(shown))
+			for i, o := range shown {
+				items[i] = o.Object
+			}
+			paginatedResponse = &k8s.PaginatedListResponse{
+				Items:           items,
+				TotalItems:      len(items),
+				ResourceVersion: list.ResourceVersion,
+				Meta:            list.Meta,
+			}
+		}
+	} else if sampleSize > 0 {
+		var sample *sampledList
+		sample, err = sampleList(ctx, func(ctx context.Context, continueToken string) (*k8s.PaginatedListResponse, error) {
+			pageOpts := opts
+			pageOpts.Limit = samplePageSize
+			pageOpts.Continue = continueToken
+			return k8sClient.List(ctx, kubeContext, namespace, resourceType, apiGroup, pageOpts)
+		}, sampleSize, filterCriteria, nil)
+		if errors.Is(err, errInvalidFilter) {
+			return toolerrors.InvalidArgumentf("Invalid filter criteria: %v", err).Result(), nil
+		}
+		if sample != nil {
+			paginatedResponse = &k8s.PaginatedListResponse{
+				Items:           sample.Items,
+				TotalItems:      len(sample.Items),
+				ResourceVersion: sample.ResourceVersion,
+				Meta:            sample.Meta,
+			}
+			sampleMeta = &sample.Metadata
+		}
+	} else {
+		paginatedResponse, err = k8sClient.List(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+	}
 	k8sDuration := time.Since(k8sStart)
 
 	if err != nil {
@@ -389,18 +601,19 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 			slog.String("resourceType", resourceType),
 			slog.Duration("duration", k8sDuration),
 			logging.SanitizedErr(err))
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to list resources", err, client.User())), nil
+		return tools.K8sError("Failed to list resources", err, client.User()).Result(), nil
 	}
 	slog.Debug("K8s list completed",
 		slog.String("resourceType", resourceType),
 		slog.Int("items", paginatedResponse.TotalItems),
 		slog.Duration("duration", k8sDuration))
 
-	// Apply client-side filtering if criteria provided
-	if len(filterCriteria) > 0 {
+	// Apply client-side filtering if criteria provided. Sampling and
+	// changedSince already filtered every page.
+	if len(filterCriteria) > 0 && sampleMeta == nil && changedMeta == nil {
 		filteredItems, err := ApplyClientSideFilter(paginatedResponse.Items, filterCriteria)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid filter criteria: %v", err)), nil
+			return toolerrors.InvalidArgumentf("Invalid filter criteria: %v", err).Result(), nil
 		}
 		paginatedResponse.Items = filteredItems
 		paginatedResponse.TotalItems = len(paginatedResponse.Items)
@@ -415,28 +628,29 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 	}
 	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationList, resourceType, metricsNamespace, instrumentation.StatusSuccess, k8sDuration)
 
-	// Build output processor honouring the per-call format. SlimOutput is
-	// flipped off for output=wide; secret masking always runs regardless of
-	// format so the documented contract holds across every read tool.
-	processor := getOutputProcessorForFormat(sc, outputFormat)
-	if len(extraExcluded) > 0 && processor.Config().SlimOutput {
-		processor = processorWithExtraExcluded(processor, extraExcluded)
-	}
-
 	// Handle summary mode - return aggregated counts instead of full items
 	if summaryMode {
-		return handleSummaryResponse(paginatedResponse.Items, processor, resourceType)
+		return handleSummaryResponse(ctx, paginatedResponse.Items, processor, resourceType, textFormat)
 	}
 
 	// Always run items through the processor: slim/normal apply field
 	// stripping, wide skips it, but every format applies secret masking and
 	// the MaxItems safety cap.
-	processedItems, result, err := output.ProcessRuntimeObjects(processor, paginatedResponse.Items)
+	processedItems, result, err := tools.ProcessRuntimeObjects(ctx, processor, paginatedResponse.Items)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to process resources: %v", err)), nil
+		return toolerrors.Internalf("Failed to process resources: %v", err).Result(), nil
 	}
 	paginatedResponse.Items = processedItems
 
+	if changedMeta != nil {
+		err := sc.ListVersions().Put(session, changedQuery, nextListVersions(scanned, previous, shown))
+		if errors.Is(err, sessions.ErrTooManySessions) {
+			return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+		} else if err != nil {
+			return toolerrors.Internalf("Failed to record resource versions: %v", err).Result(), nil
+		}
+	}
+
 	// Per-resourceType post-compaction. Used for value-conditional cleanup
 	// that the path-based ExcludedFields mechanism cannot express — e.g.
 	// dropping eventTime / firstTimestamp / lastTimestamp on Events when
@@ -453,11 +667,57 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 			slog.Int("original_count", result.Metadata.OriginalCount))
 	}
 
+	// Table mode renders the processed items as compact rows. JSON is not
+	// indented: one line per row is what makes tables cheap in tokens.
+	if outputFormat == outputTable || textFormat != output.FormatJSON {
+		maps, err := output.FromRuntimeObjects(paginatedResponse.Items)
+		if err != nil {
+			return toolerrors.Internalf("Failed to process resources: %v", err).Result(), nil
+		}
+		table := &PaginatedTableResponse{
+			Table: output.BuildTable(maps, includeLabels),
+			TableEnvelope: TableEnvelope{
+				Continue:        paginatedResponse.Continue,
+				RemainingItems:  paginatedResponse.RemainingItems,
+				ResourceVersion: paginatedResponse.ResourceVersion,
+				TotalItems:      len(maps),
+				Metadata:        listMetadata(paginatedResponse, sampleMeta, changedMeta),
+			},
+		}
+		if textFormat != output.FormatJSON {
+			return renderedTableResult(ctx, table.Table, struct {
+				Kind      string `json:"kind,omitempty"`
+				Namespace string `json:"namespace,omitempty"`
+				TableEnvelope
+			}{table.Kind, table.Namespace, table.TableEnvelope}, textFormat)
+		}
+		jsonData, err := tools.MarshalResponse(ctx, table, false)
+		if err != nil {
+			return toolerrors.Internalf("Failed to marshal resource table: %v", err).Result(), nil
+		}
+		slog.Debug("list resources handler completed",
+			slog.Int("bytes", len(jsonData)),
+			slog.Duration("elapsed", time.Since(handlerStart)))
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+
 	if fullOutput {
 		// Return full paginated output with any processing warnings
-		jsonData, err := json.MarshalIndent(paginatedResponse, "", "  ")
+		var response interface{} = paginatedResponse
+		if sampleMeta != nil {
+			response = struct {
+				*k8s.PaginatedListResponse
+				Sample *SampleMetadata `json:"sample"`
+			}{paginatedResponse, sampleMeta}
+		} else if changedMeta != nil {
+			response = struct {
+				*k8s.PaginatedListResponse
+				ChangedSince *ChangedSinceMetadata `json:"changedSince"`
+			}{paginatedResponse, changedMeta}
+		}
+		jsonData, err := tools.MarshalResponse(ctx, response, true)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal paginated resources: %v", err)), nil
+			return toolerrors.Internalf("Failed to marshal paginated resources: %v", err).Result(), nil
 		}
 		slog.Debug("list resources handler completed",
 			slog.Int("bytes", len(jsonData)),
@@ -474,9 +734,10 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 		paginatedResponse.ResourceVersion,
 		paginatedResponse.RemainingItems,
 	)
-	jsonData, err := json.MarshalIndent(summary, "", "  ")
+	summary.Metadata = listMetadata(paginatedResponse, sampleMeta, changedMeta)
+	jsonData, err := tools.MarshalResponse(ctx, summary, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal paginated resource summary: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal paginated resource summary: %v", err).Result(), nil
 	}
 
 	slog.Debug("list resources handler completed",
@@ -485,6 +746,44 @@ func handleListResources(ctx context.Context, request mcp.CallToolRequest, sc *s
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// renderedTableResult returns table rendered in format, Markdown or CSV,
+// followed by the rest of the response as JSON, so that the caller still
+// gets the continue token and metadata.
+func renderedTableResult(ctx context.Context, table *output.Table, envelope interface{}, format string) (*mcp.CallToolResult, error) {
+	text, err := table.Render(format)
+	if err != nil {
+		return toolerrors.Internalf("Failed to render resource table: %v", err).Result(), nil
+	}
+	jsonData, err := tools.MarshalResponse(ctx, envelope, false)
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal resource table: %v", err).Result(), nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent(text),
+		mcp.NewTextContent(string(jsonData)),
+	}}, nil
+}
+
+// listMetadata returns the metadata of a summarized or table list response:
+// the sample taken, the changedSince diff and whether the list came from the
+// list cache. It is nil when there is none of them.
+func listMetadata(resp *k8s.PaginatedListResponse, sampleMeta *SampleMetadata, changedMeta *ChangedSinceMetadata) map[string]interface{} {
+	var metadata map[string]interface{}
+	if sampleMeta != nil {
+		metadata = map[string]interface{}{"sample": sampleMeta}
+	}
+	if changedMeta != nil {
+		metadata = map[string]interface{}{"changedSince": changedMeta}
+	}
+	if resp.Meta != nil && resp.Meta.ListCache != nil {
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		metadata["listCache"] = resp.Meta.ListCache
+	}
+	return metadata
+}
+
 // handleDescribeResource handles kubectl describe operations
 func handleDescribeResource(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
@@ -504,12 +803,12 @@ func handleDescribeResource(ctx context.Context, request mcp.CallToolRequest, sc
 
 	resourceType, ok := args["resourceType"].(string)
 	if !ok || resourceType == "" {
-		return mcp.NewToolResultError("resourceType is required"), nil
+		return toolerrors.Required("resourceType").Result(), nil
 	}
 
 	name, ok := args["name"].(string)
 	if !ok || name == "" {
-		return mcp.NewToolResultError("name is required"), nil
+		return toolerrors.Required("name").Result(), nil
 	}
 
 	// Parse output-shaping params. The schema enforces range via mcp.Min/Max,
@@ -518,7 +817,7 @@ func handleDescribeResource(ctx context.Context, request mcp.CallToolRequest, sc
 	if v, ok := args["eventsLimit"].(float64); ok {
 		val := int(v)
 		if val < 1 || val > MaxEventsLimit {
-			return mcp.NewToolResultError(fmt.Sprintf("eventsLimit must be between 1 and %d", MaxEventsLimit)), nil
+			return toolerrors.InvalidArgumentf("eventsLimit must be between 1 and %d", MaxEventsLimit).Result(), nil
 		}
 		eventsLimit = val
 	}
@@ -528,9 +827,9 @@ func handleDescribeResource(ctx context.Context, request mcp.CallToolRequest, sc
 	outputFormat, _ := args["output"].(string)
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
@@ -540,16 +839,16 @@ func handleDescribeResource(ctx context.Context, request mcp.CallToolRequest, sc
 
 	if err != nil {
 		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationGet, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to describe resource", err, client.User())), nil
+		return tools.K8sError("Failed to describe resource", err, client.User()).Result(), nil
 	}
 
 	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationGet, resourceType, namespace, instrumentation.StatusSuccess, duration)
 
 	// Apply output processing (slim output, secret masking)
-	processor := getOutputProcessorForFormat(sc, outputFormat)
-	processedResource, err := output.ProcessSingleRuntimeObject(processor, description.Resource)
+	processor := getOutputProcessorForFormat(ctx, sc, clusterName, outputFormat)
+	processedResource, err := tools.ProcessSingleRuntimeObject(ctx, processor, description.Resource)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to process resource: %v", err)), nil
+		return toolerrors.Internalf("Failed to process resource: %v", err).Result(), nil
 	}
 
 	// The convenience metadata map duplicates resource.metadata.{labels,
@@ -564,9 +863,9 @@ func handleDescribeResource(ctx context.Context, request mcp.CallToolRequest, sc
 
 	result := buildDescribeOutput(processedResource, processedMetadata, description.Meta, description.Events, eventsLimit)
 
-	jsonData, err := json.MarshalIndent(result, "", "  ")
+	jsonData, err := tools.MarshalResponse(ctx, result, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal description: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal description: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -674,65 +973,7 @@ func handleCreateResource(ctx context.Context, request mcp.CallToolRequest, sc *
 	if result := checkMutatingOperation(sc, "create"); result != nil {
 		return result, nil
 	}
-
-	// Extract cluster parameter for multi-cluster support
-	clusterName := tools.ExtractClusterParam(request.GetArguments())
-
-	kubeContext := request.GetString("kubeContext", "")
-	namespace, err := request.RequireString("namespace")
-	if err != nil {
-		return mcp.NewToolResultError("namespace is required"), nil
-	}
-
-	manifestData, ok := request.GetArguments()["manifest"]
-	if !ok || manifestData == nil {
-		return mcp.NewToolResultError("manifest is required"), nil
-	}
-
-	// Convert the manifest to a runtime.Object
-	manifestJSON, err := json.Marshal(manifestData)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal manifest: %v", err)), nil
-	}
-
-	var obj runtime.Object
-	if err := json.Unmarshal(manifestJSON, &obj); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse manifest: %v", err)), nil
-	}
-
-	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
-	}
-	k8sClient := client.K8s()
-
-	start := time.Now()
-	createdObj, err := k8sClient.Create(ctx, kubeContext, namespace, obj)
-	duration := time.Since(start)
-
-	// Extract resource type from manifest for metrics (use "unknown" if not available)
-	resourceType := "unknown"
-	if m, ok := manifestData.(map[string]interface{}); ok {
-		if kind, ok := m["kind"].(string); ok {
-			resourceType = kind
-		}
-	}
-
-	if err != nil {
-		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationCreate, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to create resource", err, client.User())), nil
-	}
-
-	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationCreate, resourceType, namespace, instrumentation.StatusSuccess, duration)
-
-	// Convert the created resource to JSON for output
-	jsonData, err := json.MarshalIndent(createdObj, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal created resource: %v", err)), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return handleManifestOperation(ctx, request, sc, instrumentation.OperationCreate)
 }
 
 // handleApplyResource handles kubectl apply operations
@@ -740,6 +981,17 @@ func handleApplyResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 	if result := checkMutatingOperation(sc, "apply"); result != nil {
 		return result, nil
 	}
+	return handleManifestOperation(ctx, request, sc, instrumentation.OperationApply)
+}
+
+// handleManifestOperation creates or applies the objects in the manifest
+// argument, in document order. It stops at the first failure; the error
+// names the failing object and the objects already handled.
+func handleManifestOperation(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext, operation string) (*mcp.CallToolResult, error) {
+	past := "applied"
+	if operation == instrumentation.OperationCreate {
+		past = "created"
+	}
 
 	// Extract cluster parameter for multi-cluster support
 	clusterName := tools.ExtractClusterParam(request.GetArguments())
@@ -747,55 +999,95 @@ func handleApplyResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 	kubeContext := request.GetString("kubeContext", "")
 	namespace, err := request.RequireString("namespace")
 	if err != nil {
-		return mcp.NewToolResultError("namespace is required"), nil
+		return toolerrors.Required("namespace").Result(), nil
 	}
 
 	manifestData, ok := request.GetArguments()["manifest"]
 	if !ok || manifestData == nil {
-		return mcp.NewToolResultError("manifest is required"), nil
+		return toolerrors.Required("manifest").Result(), nil
 	}
 
-	// Convert the manifest to a runtime.Object
-	manifestJSON, err := json.Marshal(manifestData)
+	objects, err := parseManifestArg(manifestData)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal manifest: %v", err)), nil
+		return toolerrors.InvalidArgumentf("Failed to parse manifest: %v", err).Result(), nil
+	}
+	if result := checkSecretObjects(sc, request.GetArguments(), objects); result != nil {
+		return result, nil
 	}
 
-	var obj runtime.Object
-	if err := json.Unmarshal(manifestJSON, &obj); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse manifest: %v", err)), nil
+	// The call's own policy check saw no resource type, so each object is
+	// authorized for its kind before any is written.
+	toolName := "apply"
+	if operation == instrumentation.OperationCreate {
+		toolName = "create"
+	}
+	for i, obj := range objects {
+		if msg := tools.ObjectWriteDenied(ctx, sc, toolName, request.GetArguments(), namespace, obj); msg != "" {
+			if len(objects) > 1 {
+				msg = fmt.Sprintf("Object %d (%s/%s): %s", i+1, obj.GetKind(), obj.GetName(), msg)
+			}
+			return toolerrors.New(toolerrors.CodePolicyDenied, msg).Result(), nil
+		}
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
-	start := time.Now()
-	appliedObj, err := k8sClient.Apply(ctx, kubeContext, namespace, obj)
-	duration := time.Since(start)
+	results := make([]runtime.Object, 0, len(objects))
+	var done []string
+	for i, obj := range objects {
+		target := undoTargetOf(clusterName, kubeContext, namespace, obj)
+		start := time.Now()
+		var result runtime.Object
+		var undo *journal.Entry
+		if operation == instrumentation.OperationCreate {
+			undo = tools.CreationForUndo(ctx, sc, "create", target)
+			result, err = k8sClient.Create(ctx, kubeContext, namespace, obj)
+		} else {
+			undo = tools.SnapshotForUndo(ctx, sc, k8sClient, "apply", target)
+			result, err = k8sClient.Apply(ctx, kubeContext, namespace, obj)
+		}
+		duration := time.Since(start)
 
-	// Extract resource type from manifest for metrics (use "unknown" if not available)
-	resourceType := "unknown"
-	if m, ok := manifestData.(map[string]interface{}); ok {
-		if kind, ok := m["kind"].(string); ok {
-			resourceType = kind
+		if err != nil {
+			recordK8sOperation(ctx, sc, clusterName, operation, obj.GetKind(), namespace, instrumentation.StatusError, duration)
+			msg := tools.FormatK8sError(fmt.Sprintf("Failed to %s resource", operation), err, client.User())
+			if len(objects) > 1 {
+				msg = fmt.Sprintf("Object %d (%s/%s): %s", i+1, obj.GetKind(), obj.GetName(), msg)
+				if len(done) > 0 {
+					msg += fmt.Sprintf("\nAlready %s: %s", past, strings.Join(done, ", "))
+				}
+			}
+			return toolerrors.FromError(err, msg).Result(), nil
 		}
-	}
 
-	if err != nil {
-		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationApply, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to apply resource", err, client.User())), nil
+		recordK8sOperation(ctx, sc, clusterName, operation, obj.GetKind(), namespace, instrumentation.StatusSuccess, duration)
+		tools.RecordForUndo(ctx, sc, undo)
+		results = append(results, result)
+		done = append(done, obj.GetKind()+"/"+obj.GetName())
 	}
 
-	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationApply, resourceType, namespace, instrumentation.StatusSuccess, duration)
+	// The API server echoes Secret values back, so mask them like any read.
+	if outputCfg := sc.OutputConfigFor(clusterName); outputCfg.MaskSecrets {
+		maps, err := output.FromRuntimeObjects(results)
+		if err != nil {
+			return toolerrors.Internalf("Failed to process %s resource: %v", past, err).Result(), nil
+		}
+		results = output.ToRuntimeObjects(output.MaskSecretsInListWithLevel(maps, outputCfg.SecretRedaction))
+	}
 
-	// Convert the applied resource to JSON for output
-	jsonData, err := json.MarshalIndent(appliedObj, "", "  ")
+	// Convert the resulting resources to JSON for output
+	var out interface{} = results
+	if len(results) == 1 {
+		out = results[0]
+	}
+	jsonData, err := tools.MarshalResponse(ctx, out, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal applied resource: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal %s resource: %v", past, err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -821,36 +1113,45 @@ func handleDeleteResource(ctx context.Context, request mcp.CallToolRequest, sc *
 
 	resourceType, err := request.RequireString("resourceType")
 	if err != nil {
-		return mcp.NewToolResultError("resourceType is required"), nil
+		return toolerrors.Required("resourceType").Result(), nil
 	}
 
 	name, err := request.RequireString("name")
 	if err != nil {
-		return mcp.NewToolResultError("name is required"), nil
+		return toolerrors.Required("name").Result(), nil
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
+	undo := tools.SnapshotForUndo(ctx, sc, k8sClient, "delete", tools.UndoTarget{
+		Cluster:      clusterName,
+		KubeContext:  kubeContext,
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		APIGroup:     apiGroup,
+		Name:         name,
+	})
 	start := time.Now()
 	deleteResponse, err := k8sClient.Delete(ctx, kubeContext, namespace, resourceType, apiGroup, name)
 	duration := time.Since(start)
 
 	if err != nil {
 		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationDelete, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to delete resource", err, client.User())), nil
+		return tools.K8sError("Failed to delete resource", err, client.User()).Result(), nil
 	}
 
 	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationDelete, resourceType, namespace, instrumentation.StatusSuccess, duration)
+	tools.RecordForUndo(ctx, sc, undo)
 
 	// Convert the response to JSON for output (includes _meta)
-	jsonData, err := json.MarshalIndent(deleteResponse, "", "  ")
+	jsonData, err := tools.MarshalResponse(ctx, deleteResponse, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -876,22 +1177,22 @@ func handlePatchResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 
 	resourceType, err := request.RequireString("resourceType")
 	if err != nil {
-		return mcp.NewToolResultError("resourceType is required"), nil
+		return toolerrors.Required("resourceType").Result(), nil
 	}
 
 	name, err := request.RequireString("name")
 	if err != nil {
-		return mcp.NewToolResultError("name is required"), nil
+		return toolerrors.Required("name").Result(), nil
 	}
 
 	patchTypeStr, err := request.RequireString("patchType")
 	if err != nil {
-		return mcp.NewToolResultError("patchType is required"), nil
+		return toolerrors.Required("patchType").Result(), nil
 	}
 
 	patchData, ok := request.GetArguments()["patch"]
 	if !ok || patchData == nil {
-		return mcp.NewToolResultError("patch is required"), nil
+		return toolerrors.Required("patch").Result(), nil
 	}
 
 	// Convert patch type string to types.PatchType
@@ -904,38 +1205,53 @@ func handlePatchResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 	case "json":
 		patchType = types.JSONPatchType
 	default:
-		return mcp.NewToolResultError("Invalid patch type. Must be one of: strategic, merge, json"), nil
+		return toolerrors.InvalidArgument("Invalid patch type. Must be one of: strategic, merge, json").Result(), nil
 	}
 
 	// Convert patch data to JSON bytes
-	patchBytes, err := json.Marshal(patchData)
+	patchBytes, err := parsePatchArg(patchData)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal patch data: %v", err)), nil
+		return toolerrors.InvalidArgumentf("Failed to parse patch: %v", err).Result(), nil
+	}
+
+	if isSecretResourceType(resourceType, apiGroup) {
+		if result := tools.CheckSecretWrite(sc, request.GetArguments()); result != nil {
+			return result, nil
+		}
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
+	undo := tools.SnapshotForUndo(ctx, sc, k8sClient, "patch", tools.UndoTarget{
+		Cluster:      clusterName,
+		KubeContext:  kubeContext,
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		APIGroup:     apiGroup,
+		Name:         name,
+	})
 	start := time.Now()
 	patchResponse, err := k8sClient.Patch(ctx, kubeContext, namespace, resourceType, apiGroup, name, patchType, patchBytes)
 	duration := time.Since(start)
 
 	if err != nil {
 		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationPatch, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to patch resource", err, client.User())), nil
+		return tools.K8sError("Failed to patch resource", err, client.User()).Result(), nil
 	}
 
 	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationPatch, resourceType, namespace, instrumentation.StatusSuccess, duration)
+	tools.RecordForUndo(ctx, sc, undo)
 
 	// Apply output processing (slim output, secret masking)
-	processor := getOutputProcessorForFormat(sc, "")
-	processedObj, err := output.ProcessSingleRuntimeObject(processor, patchResponse.Resource)
+	processor := getOutputProcessorForFormat(ctx, sc, clusterName, "")
+	processedObj, err := tools.ProcessSingleRuntimeObject(ctx, processor, patchResponse.Resource)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to process resource: %v", err)), nil
+		return toolerrors.Internalf("Failed to process resource: %v", err).Result(), nil
 	}
 
 	// Build response with metadata
@@ -945,9 +1261,9 @@ func handlePatchResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 	}
 
 	// Convert the response to JSON for output
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+	jsonData, err := tools.MarshalResponse(ctx, response, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal patched resource: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal patched resource: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -966,46 +1282,55 @@ func handleScaleResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 	apiGroup := request.GetString("apiGroup", "")
 	namespace, err := request.RequireString("namespace")
 	if err != nil {
-		return mcp.NewToolResultError("namespace is required"), nil
+		return toolerrors.Required("namespace").Result(), nil
 	}
 
 	resourceType, err := request.RequireString("resourceType")
 	if err != nil {
-		return mcp.NewToolResultError("resourceType is required"), nil
+		return toolerrors.Required("resourceType").Result(), nil
 	}
 
 	name, err := request.RequireString("name")
 	if err != nil {
-		return mcp.NewToolResultError("name is required"), nil
+		return toolerrors.Required("name").Result(), nil
 	}
 
 	replicas, err := request.RequireFloat("replicas")
 	if err != nil {
-		return mcp.NewToolResultError("replicas is required"), nil
+		return toolerrors.Required("replicas").Result(), nil
 	}
 
 	// Get the appropriate k8s client (local or federated)
-	client, errMsg := tools.GetClusterClient(ctx, sc, clusterName)
-	if errMsg != "" {
-		return mcp.NewToolResultError(errMsg), nil
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
 	}
 	k8sClient := client.K8s()
 
+	undo := tools.SnapshotForUndo(ctx, sc, k8sClient, "scale", tools.UndoTarget{
+		Cluster:      clusterName,
+		KubeContext:  kubeContext,
+		Namespace:    namespace,
+		ResourceType: resourceType,
+		APIGroup:     apiGroup,
+		Name:         name,
+	})
 	start := time.Now()
 	scaleResponse, err := k8sClient.Scale(ctx, kubeContext, namespace, resourceType, apiGroup, name, int32(replicas))
 	duration := time.Since(start)
 
 	if err != nil {
 		recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationScale, resourceType, namespace, instrumentation.StatusError, duration)
-		return mcp.NewToolResultError(tools.FormatK8sError("Failed to scale resource", err, client.User())), nil
+		return tools.K8sError("Failed to scale resource", err, client.User()).Result(), nil
 	}
 
 	recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationScale, resourceType, namespace, instrumentation.StatusSuccess, duration)
+	tools.RecordForUndo(ctx, sc, undo)
 
 	// Convert the scale response to JSON for output (includes _meta)
-	jsonData, err := json.MarshalIndent(scaleResponse, "", "  ")
+	jsonData, err := tools.MarshalResponse(ctx, scaleResponse, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
@@ -1013,11 +1338,12 @@ func handleScaleResource(ctx context.Context, request mcp.CallToolRequest, sc *s
 
 // handleSummaryResponse generates a summary response for large result sets.
 // This provides aggregated counts by status, namespace, etc. instead of full items.
-func handleSummaryResponse(items []runtime.Object, processor *output.Processor, resourceType string) (*mcp.CallToolResult, error) {
+// With format markdown or csv the counts are rendered as a table.
+func handleSummaryResponse(ctx context.Context, items []runtime.Object, processor *output.Processor, resourceType, format string) (*mcp.CallToolResult, error) {
 	// Convert to maps for summary generation
 	maps, err := output.FromRuntimeObjects(items)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to process resources for summary: %v", err)), nil
+		return toolerrors.Internalf("Failed to process resources for summary: %v", err).Result(), nil
 	}
 
 	// Generate summary
@@ -1057,10 +1383,34 @@ func handleSummaryResponse(items []runtime.Object, processor *output.Processor,
 		}
 	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if format != output.FormatJSON {
+		byStatus, _ := response["byStatus"].(map[string]int)
+		byNamespace, _ := response["byNamespace"].(map[string]int)
+		table := summaryCountTable(byStatus, byNamespace)
+		delete(response, "byStatus")
+		delete(response, "byNamespace")
+		return renderedTableResult(ctx, table, response, format)
+	}
+
+	jsonData, err := tools.MarshalResponse(ctx, response, true)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal summary: %v", err)), nil
+		return toolerrors.Internalf("Failed to marshal summary: %v", err).Result(), nil
 	}
 
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
+
+// summaryCountTable returns the status and namespace counts of a summary
+// response as a table, most frequent first.
+func summaryCountTable(byStatus, byNamespace map[string]int) *output.Table {
+	table := &output.Table{Columns: []string{"GROUP", "VALUE", "COUNT"}, Rows: [][]string{}}
+	for _, group := range []struct {
+		name   string
+		counts map[string]int
+	}{{"status", byStatus}, {"namespace", byNamespace}} {
+		for _, entry := range output.TopCounts(group.counts, len(group.counts)) {
+			table.Rows = append(table.Rows, []string{group.name, entry.Key, strconv.Itoa(entry.Count)})
+		}
+	}
+	return table
+}
@@ -264,7 +264,7 @@ func TestDescribeResource_ConvenienceMetadataIsSlimmed(t *testing.T) {
 		"kind":            "Deployment",
 		"apiVersion":      "apps/v1",
 	}
-	processor := getOutputProcessorForFormat(serverContextWithSlim(t), "slim")
+	processor := getOutputProcessorForFormat(context.Background(), serverContextWithSlim(t), "", "slim")
 	cfg := processor.Config()
 	require.True(t, cfg.SlimOutput, "precondition: SlimOutput must be on")
 
@@ -3,6 +3,8 @@ package resource
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
 )
 
@@ -721,7 +724,7 @@ func TestGetOutputProcessorForFormat(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			processor := getOutputProcessorForFormat(sc, tt.outputFormat)
+			processor := getOutputProcessorForFormat(context.Background(), sc, "", tt.outputFormat)
 			require.NotNil(t, processor)
 			cfg := processor.Config()
 			assert.Equal(t, tt.wantSlim, cfg.SlimOutput,
@@ -733,3 +736,141 @@ func TestGetOutputProcessorForFormat(t *testing.T) {
 		})
 	}
 }
+
+// TestGetOutputProcessorForFormat_ScrubPII verifies the server-level PII
+// scrubbing settings reach every output format, including the per-kind
+// processors derived via processorWithExtraExcluded.
+func TestGetOutputProcessorForFormat_ScrubPII(t *testing.T) {
+	outputCfg := server.NewDefaultOutputConfig()
+	outputCfg.ScrubPII = true
+	outputCfg.PIIPatterns = []string{output.PIIPatternEmail}
+
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithOutputConfig(outputCfg),
+	)
+	require.NoError(t, err)
+
+	for _, format := range []string{"", "normal", "wide"} {
+		processor := getOutputProcessorForFormat(context.Background(), sc, "", format)
+		cfg := processor.Config()
+		assert.True(t, cfg.ScrubPII, "ScrubPII must stay enabled for output=%q", format)
+		assert.Equal(t, []string{output.PIIPatternEmail}, cfg.PIIPatterns)
+		assert.NotNil(t, processor.ScrubRecorder())
+
+		derived := processorWithExtraExcluded(processor, []string{"metadata.labels"})
+		assert.True(t, derived.Config().ScrubPII)
+		assert.NotNil(t, derived.ScrubRecorder(), "derived processors must keep reporting scrub metrics")
+	}
+}
+
+// TestGetOutputProcessorForFormat_ClusterOverrides verifies the overrides of
+// the cluster a call targets reach its output processor.
+func TestGetOutputProcessorForFormat_ClusterOverrides(t *testing.T) {
+	maxItems, slim := 20, false
+	outputCfg := server.NewDefaultOutputConfig()
+	outputCfg.ClusterTypeOverrides = map[string]server.OutputOverride{
+		"production": {MaxItems: &maxItems},
+	}
+	outputCfg.ClusterOverrides = map[string]server.OutputOverride{
+		"prod-wc-01": {SlimOutput: &slim},
+	}
+	outputCfg.KindProfiles = map[string][]string{"Pod": {"status.phase"}}
+
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithOutputConfig(outputCfg),
+	)
+	require.NoError(t, err)
+
+	cfg := getOutputProcessorForFormat(context.Background(), sc, "prod-wc-01", "").Config()
+	assert.Equal(t, 20, cfg.MaxItems)
+	assert.False(t, cfg.SlimOutput)
+	assert.True(t, cfg.MaskSecrets)
+	assert.Equal(t, outputCfg.KindProfiles, cfg.KindProfiles)
+	assert.Nil(t, getOutputProcessorForFormat(context.Background(), sc, "prod-wc-01", outputTable).Config().KindProfiles,
+		"tables read their columns from the items")
+
+	cfg = getOutputProcessorForFormat(context.Background(), sc, "dev-wc-01", "").Config()
+	assert.Equal(t, 100, cfg.MaxItems)
+	assert.True(t, cfg.SlimOutput)
+}
+
+// TestHandleListResourcesTableOutput verifies that output "table" returns
+// kubectl-style columns and rows instead of items.
+func TestHandleListResourcesTableOutput(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&pagingK8sClient{objects: samplePods(3)}),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resourceType": "pods",
+		"output":       "table",
+	}
+	result, err := handleListResources(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var response PaginatedTableResponse
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &response))
+	require.NotNil(t, response.Table)
+	assert.Equal(t, "Pod", response.Kind)
+	assert.Equal(t, "default", response.Namespace)
+	assert.Equal(t, "NAME", response.Columns[0])
+	assert.Len(t, response.Rows, 3)
+	assert.Equal(t, "pod-0", response.Rows[0][0])
+	assert.Equal(t, 3, response.TotalItems)
+}
+
+func TestHandleListResourcesTextFormat(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&pagingK8sClient{objects: samplePods(3)}),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	list := func(args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handleListResources(context.Background(), request, sc)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := list(map[string]interface{}{"resourceType": "pods", "format": "markdown", "limit": float64(2)})
+	require.False(t, result.IsError, getErrorText(t, result))
+	require.Len(t, result.Content, 2)
+	markdown := result.Content[0].(mcp.TextContent).Text
+	assert.True(t, strings.HasPrefix(markdown, "| NAME |"), markdown)
+	assert.Contains(t, markdown, "| pod-1 |")
+	assert.NotContains(t, markdown, "pod-2")
+
+	var envelope map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[1].(mcp.TextContent).Text), &envelope))
+	assert.Equal(t, "Pod", envelope["kind"])
+	assert.Equal(t, "default", envelope["namespace"])
+	assert.Equal(t, "2", envelope["continue"], "the continue token follows the table")
+	assert.Equal(t, float64(2), envelope["totalItems"])
+
+	result = list(map[string]interface{}{"resourceType": "pods", "format": "csv"})
+	require.False(t, result.IsError, getErrorText(t, result))
+	csvText := result.Content[0].(mcp.TextContent).Text
+	assert.True(t, strings.HasPrefix(csvText, "NAME"), csvText)
+	assert.Len(t, strings.Split(strings.TrimSpace(csvText), "\n"), 4, "a header and a record per pod")
+
+	result = list(map[string]interface{}{"resourceType": "pods", "format": "csv", "summary": true})
+	require.False(t, result.IsError, getErrorText(t, result))
+	assert.Equal(t, "GROUP,VALUE,COUNT\nstatus,Unknown,3\nnamespace,default,3\n", result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[1].(mcp.TextContent).Text, `"total":3`)
+
+	result = list(map[string]interface{}{"resourceType": "pods", "format": "markdown", "fullOutput": true})
+	assert.True(t, result.IsError)
+	result = list(map[string]interface{}{"resourceType": "pods", "format": "yaml"})
+	assert.True(t, result.IsError)
+}
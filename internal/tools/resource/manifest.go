@@ -0,0 +1,195 @@
+package resource
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestBufferSize is how far the decoder looks ahead to tell JSON from YAML.
+const manifestBufferSize = 4096
+
+// yamlLinePattern matches the line number in YAML parser errors, which count
+// from the start of the document rather than the whole bundle.
+var yamlLinePattern = regexp.MustCompile(`\bline (\d+)\b`)
+
+// manifestDocument is one document of a YAML bundle.
+type manifestDocument struct {
+	// line is the line of the bundle the document starts on, starting at 1.
+	line int
+	data string
+}
+
+// decodeManifests splits a multi-document YAML or JSON bundle into objects.
+// Empty documents are skipped and List kinds are expanded into their items.
+// Errors name the document and the line of the bundle they occur on.
+func decodeManifests(data string) ([]*unstructured.Unstructured, error) {
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return decodeJSONManifests(trimmed)
+	}
+
+	var objects []*unstructured.Unstructured
+	for i, doc := range splitYAMLDocuments(data) {
+		var content map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc.data), &content); err != nil {
+			msg := strings.TrimPrefix(err.Error(), "error converting YAML to JSON: ")
+			return nil, fmt.Errorf("document %d: %s", i+1, shiftYAMLLines(msg, doc.line-1))
+		}
+		expanded, err := expandManifest(content)
+		if err != nil {
+			return nil, fmt.Errorf("document %d (line %d): %w", i+1, doc.line, err)
+		}
+		objects = append(objects, expanded...)
+	}
+	return objects, nil
+}
+
+// decodeJSONManifests decodes a stream of JSON objects.
+func decodeJSONManifests(data string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(data), manifestBufferSize)
+	var objects []*unstructured.Unstructured
+	for doc := 1; ; doc++ {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		expanded, err := expandManifest(content)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		objects = append(objects, expanded...)
+	}
+	return objects, nil
+}
+
+// expandManifest turns one decoded document into objects. An empty document
+// yields none and a List yields its items.
+func expandManifest(content map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	if len(content) == 0 {
+		return nil, nil
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	if !obj.IsList() {
+		return []*unstructured.Unstructured{obj}, nil
+	}
+	list, err := obj.ToList()
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+// splitYAMLDocuments splits data on '---' separator lines, like kubectl.
+func splitYAMLDocuments(data string) []manifestDocument {
+	var docs []manifestDocument
+	var current strings.Builder
+	start := 1
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, manifestBufferSize), len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "---" || strings.HasPrefix(text, "--- ") {
+			docs = append(docs, manifestDocument{line: start, data: current.String()})
+			current.Reset()
+			start = line + 1
+			continue
+		}
+		current.WriteString(text)
+		current.WriteByte('\n')
+	}
+	return append(docs, manifestDocument{line: start, data: current.String()})
+}
+
+// shiftYAMLLines adds offset to the line numbers in a YAML parser error so
+// they refer to the whole bundle.
+func shiftYAMLLines(msg string, offset int) string {
+	return yamlLinePattern.ReplaceAllStringFunc(msg, func(match string) string {
+		n, err := strconv.Atoi(strings.TrimPrefix(match, "line "))
+		if err != nil {
+			return match
+		}
+		return "line " + strconv.Itoa(n+offset)
+	})
+}
+
+// parseManifestArg converts a manifest tool argument into objects. The
+// argument is either a JSON object or a YAML or JSON string, which may hold
+// several documents. Every object must have apiVersion, kind and a name.
+func parseManifestArg(value interface{}) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	switch v := value.(type) {
+	case map[string]interface{}:
+		expanded, err := expandManifest(v)
+		if err != nil {
+			return nil, err
+		}
+		objects = expanded
+	case string:
+		decoded, err := decodeManifests(v)
+		if err != nil {
+			return nil, err
+		}
+		objects = decoded
+	default:
+		return nil, fmt.Errorf("must be a JSON object or a YAML string, got %T", value)
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.New("contains no objects")
+	}
+	for i, obj := range objects {
+		if err := validateManifestObject(obj); err != nil {
+			if len(objects) == 1 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("object %d: %w", i+1, err)
+		}
+	}
+	return objects, nil
+}
+
+// validateManifestObject checks the fields the API server needs to route an
+// object. A generateName stands in for the name.
+func validateManifestObject(obj *unstructured.Unstructured) error {
+	switch {
+	case obj.GetAPIVersion() == "" || obj.GetKind() == "":
+		return errors.New("apiVersion and kind are required")
+	case obj.GetName() == "" && obj.GetGenerateName() == "":
+		return errors.New("metadata.name is required")
+	}
+	return nil
+}
+
+// parsePatchArg converts a patch tool argument into JSON. Objects and arrays
+// are marshalled as they are; strings are parsed as YAML or JSON.
+func parsePatchArg(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return json.Marshal(value)
+	}
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("is empty")
+	}
+	data, err := yaml.YAMLToJSON([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,302 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+func TestParseManifestArg(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name: "JSON object",
+			value: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "settings"},
+			},
+			wantNames: []string{"settings"},
+		},
+		{
+			name:      "YAML string",
+			value:     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  mode: fast\n",
+			wantNames: []string{"settings"},
+		},
+		{
+			name:      "JSON string",
+			value:     `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings"}}`,
+			wantNames: []string{"settings"},
+		},
+		{
+			name:      "multi-document YAML",
+			value:     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  generateName: b-\n",
+			wantNames: []string{"a", ""},
+		},
+		{
+			name:    "YAML syntax error reports the bundle line",
+			value:   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n   labels: {}\n",
+			wantErr: "document 2: yaml: line 10:",
+		},
+		{
+			name:    "missing kind",
+			value:   "apiVersion: v1\nmetadata:\n  name: a\n",
+			wantErr: "apiVersion and kind are required",
+		},
+		{
+			name:    "missing name in second object",
+			value:   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\n",
+			wantErr: "object 2: metadata.name is required",
+		},
+		{
+			name:    "only comments",
+			value:   "# nothing here\n---\n",
+			wantErr: "contains no objects",
+		},
+		{
+			name:    "unsupported type",
+			value:   float64(1),
+			wantErr: "must be a JSON object or a YAML string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := parseManifestArg(tt.value)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, obj := range objects {
+				names = append(names, obj.GetName())
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestParsePatchArg(t *testing.T) {
+	data, err := parsePatchArg(map[string]interface{}{"spec": map[string]interface{}{"replicas": 2}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"spec": {"replicas": 2}}`, string(data))
+
+	data, err = parsePatchArg("spec:\n  replicas: 2\n")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"spec": {"replicas": 2}}`, string(data))
+
+	data, err = parsePatchArg("- op: replace\n  path: /spec/replicas\n  value: 2\n")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op": "replace", "path": "/spec/replicas", "value": 2}]`, string(data))
+
+	_, err = parsePatchArg("  ")
+	assert.Error(t, err)
+}
+
+func TestApplyResource_YAMLManifest(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"
+	mock := &applyAllMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		failNames:     map[string]bool{"web": true},
+	}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"namespace": "shop",
+		"manifest":  manifest,
+	}
+	result, err := handleApplyResource(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Equal(t, []string{"ConfigMap/shop/settings", "Secret/shop/token"}, mock.applied)
+
+	text := getErrorText(t, result)
+	assert.Contains(t, text, "Object 3 (Service/web)")
+	assert.Contains(t, text, "Already applied: ConfigMap/settings, Secret/token")
+}
+
+func TestApplyResource_SecretWrites(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\nstringData:\n  token: s3cr3t\n"
+	call := func(t *testing.T, mock *applyAllMock, opts ...server.Option) *mcp.CallToolResult {
+		t.Helper()
+		sc, err := server.NewServerContext(context.Background(), append([]server.Option{
+			server.WithK8sClient(mock),
+			server.WithLogger(&testdata.MockLogger{}),
+			server.WithNonDestructiveMode(false),
+		}, opts...)...)
+		require.NoError(t, err)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"namespace": "shop", "manifest": manifest}
+		result, err := handleApplyResource(context.Background(), request, sc)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("denied", func(t *testing.T) {
+		mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+		result := call(t, mock, server.WithSecretWrites(server.SecretWritesDeny))
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorText(t, result), "manifest contains Secret/token")
+		assert.Empty(t, mock.applied, "nothing is applied when the manifest holds a blocked Secret")
+	})
+
+	t.Run("allowed output is masked", func(t *testing.T) {
+		mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+		result := call(t, mock)
+		require.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.NotContains(t, text, "s3cr3t")
+		assert.Contains(t, text, output.RedactedValue)
+	})
+}
+
+func TestApplyResource_PolicyPerObject(t *testing.T) {
+	policy, err := security.ParsePolicy([]byte("rules:\n  - effect: deny\n    resources: [secrets]\n    verbs: [apply]\n"))
+	require.NoError(t, err)
+	mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+		server.WithPolicyEngine(security.NewEngine(policy)),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"namespace": "shop",
+		"manifest":  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n",
+	}
+	result, err := handleApplyResource(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorText(t, result), "Object 2 (Secret/token): apply secrets in namespace shop is denied by policy")
+	assert.Empty(t, mock.applied, "nothing is applied when the policy denies an object")
+}
+
+func TestApplyResource_ClusterScopedUnderWriteNamespaces(t *testing.T) {
+	manifest := "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRoleBinding\nmetadata:\n  name: team-a-admin\nroleRef:\n  apiGroup: rbac.authorization.k8s.io\n  kind: ClusterRole\n  name: cluster-admin\n"
+	call := func(t *testing.T, clusterScopedWrites bool) (*applyAllMock, *mcp.CallToolResult) {
+		t.Helper()
+		mock := &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}}
+		sc, err := server.NewServerContext(context.Background(),
+			server.WithK8sClient(mock),
+			server.WithLogger(&testdata.MockLogger{}),
+			server.WithNonDestructiveMode(false),
+			server.WithNamespaceAccess(nil, []string{"team-*"}, clusterScopedWrites),
+		)
+		require.NoError(t, err)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"namespace": "team-a", "manifest": manifest}
+		result, err := handleApplyResource(context.Background(), request, sc)
+		require.NoError(t, err)
+		return mock, result
+	}
+
+	t.Run("denied", func(t *testing.T) {
+		mock, result := call(t, false)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorText(t, result), "apply of cluster-scoped clusterrolebindings is not allowed: writes are limited to namespaces team-*")
+		assert.Empty(t, mock.applied)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		mock, result := call(t, true)
+		require.False(t, result.IsError, getErrorText(t, result))
+		assert.Equal(t, []string{"ClusterRoleBinding/team-a/team-a-admin"}, mock.applied)
+	})
+}
+
+// discoveryApplyMock serves resources from discovery.
+type discoveryApplyMock struct {
+	*applyAllMock
+	resources []k8s.APIResourceInfo
+}
+
+func (m *discoveryApplyMock) GetAPIResources(context.Context, string, int, int, string, bool, []string) (*k8s.PaginatedAPIResourceResponse, error) {
+	return &k8s.PaginatedAPIResourceResponse{Items: m.resources}, nil
+}
+
+func TestApplyResource_ScopeFromDiscovery(t *testing.T) {
+	mock := &discoveryApplyMock{
+		applyAllMock: &applyAllMock{MockK8sClient: &testdata.MockK8sClient{}},
+		resources: []k8s.APIResourceInfo{
+			{Name: "clusterissuers", Kind: "ClusterIssuer", Group: "cert-manager.io", Version: "v1"},
+			{Name: "issuers", Kind: "Issuer", Group: "cert-manager.io", Version: "v1", Namespaced: true},
+		},
+	}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+		server.WithNamespaceAccess(nil, []string{"team-*"}, false),
+	)
+	require.NoError(t, err)
+
+	apply := func(kind string) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"namespace": "team-a",
+			"manifest":  "apiVersion: cert-manager.io/v1\nkind: " + kind + "\nmetadata:\n  name: letsencrypt\n",
+		}
+		result, err := handleApplyResource(context.Background(), request, sc)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := apply("ClusterIssuer")
+	require.True(t, result.IsError)
+	assert.Contains(t, getErrorText(t, result), "apply of cluster-scoped clusterissuers is not allowed")
+
+	result = apply("Issuer")
+	require.False(t, result.IsError, getErrorText(t, result))
+	assert.Equal(t, []string{"Issuer/team-a/letsencrypt"}, mock.applied)
+}
+
+func TestPatchResource_SecretWrites(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+		server.WithSecretWrites(server.SecretWritesDeny),
+	)
+	require.NoError(t, err)
+
+	for _, resourceType := range []string{"secrets", "Secret"} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"namespace":    "shop",
+			"resourceType": resourceType,
+			"name":         "token",
+			"patchType":    "merge",
+			"patch":        map[string]interface{}{"stringData": map[string]interface{}{"token": "s3cr3t"}},
+		}
+		result, err := handlePatchResource(context.Background(), request, sc)
+		require.NoError(t, err)
+		require.True(t, result.IsError, resourceType)
+		assert.Contains(t, getErrorText(t, result), "writing Secrets is disabled")
+	}
+}
@@ -0,0 +1,372 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// MaxMetadataEditObjects limits how many objects one label or annotate call
+// may change.
+const MaxMetadataEditObjects = 100
+
+// The metadata fields edited by the label and annotate tools.
+const (
+	metadataLabels      = "labels"
+	metadataAnnotations = "annotations"
+)
+
+// Per-object outcomes of the label and annotate tools.
+const (
+	MetadataEditUpdated   = "updated"
+	MetadataEditPlanned   = "planned"
+	MetadataEditUnchanged = "unchanged"
+	MetadataEditFailed    = "failed"
+)
+
+// MetadataEditResult is the output of the label and annotate tools.
+type MetadataEditResult struct {
+	// Field is "labels" or "annotations".
+	Field string `json:"field"`
+
+	// Preview is true when nothing was changed because preview was
+	// requested.
+	Preview bool `json:"preview,omitempty"`
+	DryRun  bool `json:"dryRun,omitempty"`
+
+	Matched   int                  `json:"matched"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Failed    int                  `json:"failed"`
+	Objects   []MetadataEditObject `json:"objects"`
+}
+
+// MetadataEditObject is the outcome for one object.
+type MetadataEditObject struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+
+	// Set holds the keys added or changed, with their new values.
+	Set     map[string]string `json:"set,omitempty"`
+	Removed []string          `json:"removed,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// metadataEditRequest holds the validated label or annotate arguments.
+type metadataEditRequest struct {
+	field         string
+	cluster       string
+	kubeContext   string
+	namespace     string
+	allNamespaces bool
+	resourceType  string
+	apiGroup      string
+	name          string
+	labelSelector string
+	set           map[string]string
+	remove        []string
+	overwrite     bool
+	preview       bool
+}
+
+// handleLabel adds, changes and removes labels on one object or every
+// object matching a label selector.
+func handleLabel(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	return editMetadata(ctx, request, sc, metadataLabels)
+}
+
+// handleAnnotate adds, changes and removes annotations on one object or
+// every object matching a label selector.
+func handleAnnotate(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	return editMetadata(ctx, request, sc, metadataAnnotations)
+}
+
+func editMetadata(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext, field string) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "patch"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	req, errMsg := parseMetadataEditRequest(args, field)
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	if isSecretResourceType(req.resourceType, req.apiGroup) {
+		if result := tools.CheckSecretWrite(sc, args); result != nil {
+			return result, nil
+		}
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	targets, err := metadataEditTargets(ctx, k8sClient, req)
+	if err != nil {
+		return tools.K8sError(fmt.Sprintf("Failed to find objects to edit %s of", field), err, client.User()).Result(), nil
+	}
+	if len(targets) > MaxMetadataEditObjects {
+		return toolerrors.InvalidArgumentf("labelSelector matches more than %d objects; narrow it down", MaxMetadataEditObjects).Result(), nil
+	}
+
+	result := &MetadataEditResult{
+		Field:   field,
+		Preview: req.preview,
+		DryRun:  tools.IsDryRun(ctx, sc),
+		Matched: len(targets),
+		Objects: make([]MetadataEditObject, 0, len(targets)),
+	}
+	for _, target := range targets {
+		out := editObjectMetadata(ctx, sc, k8sClient, req, target)
+		switch out.Status {
+		case MetadataEditUpdated, MetadataEditPlanned:
+			result.Updated++
+		case MetadataEditUnchanged:
+			result.Unchanged++
+		case MetadataEditFailed:
+			result.Failed++
+		}
+		result.Objects = append(result.Objects, out)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseMetadataEditRequest validates the label or annotate arguments.
+func parseMetadataEditRequest(args map[string]interface{}, field string) (metadataEditRequest, string) {
+	req := metadataEditRequest{
+		field:   field,
+		cluster: tools.ExtractClusterParam(args),
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.apiGroup, _ = args["apiGroup"].(string)
+	req.resourceType, _ = args["resourceType"].(string)
+	if req.resourceType == "" {
+		return req, "resourceType is required"
+	}
+	req.namespace, _ = args["namespace"].(string)
+	req.allNamespaces, _ = args["allNamespaces"].(bool)
+	if !req.allNamespaces && req.namespace == "" {
+		req.namespace = k8s.DefaultNamespace
+	}
+	req.overwrite, _ = args["overwrite"].(bool)
+	req.preview, _ = args["preview"].(bool)
+
+	req.name, _ = args["name"].(string)
+	req.labelSelector, _ = args["labelSelector"].(string)
+	switch {
+	case req.name == "" && req.labelSelector == "":
+		return req, "either name or labelSelector is required"
+	case req.name != "" && req.labelSelector != "":
+		return req, "name and labelSelector cannot be combined"
+	case req.name != "" && req.allNamespaces:
+		return req, "name cannot be combined with allNamespaces"
+	}
+	if req.labelSelector != "" {
+		if _, err := labels.Parse(req.labelSelector); err != nil {
+			return req, fmt.Sprintf("invalid labelSelector: %v", err)
+		}
+	}
+
+	var errMsg string
+	req.set, errMsg = stringMapArg(args, "set")
+	if errMsg != "" {
+		return req, errMsg
+	}
+	if raw, ok := args["remove"]; ok && raw != nil {
+		keys, ok := raw.([]interface{})
+		if !ok {
+			return req, "remove must be an array of strings"
+		}
+		for _, k := range keys {
+			key, ok := k.(string)
+			if !ok || key == "" {
+				return req, "remove must be an array of non-empty strings"
+			}
+			req.remove = append(req.remove, key)
+		}
+	}
+	if len(req.set) == 0 && len(req.remove) == 0 {
+		return req, "set or remove is required"
+	}
+
+	for key, value := range req.set {
+		if slices.Contains(req.remove, key) {
+			return req, fmt.Sprintf("key %q is both set and removed", key)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return req, fmt.Sprintf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if field == metadataLabels {
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return req, fmt.Sprintf("invalid value for label %q: %s", key, strings.Join(errs, "; "))
+			}
+		}
+	}
+	return req, ""
+}
+
+// metadataEditTarget is an object the label or annotate tool edits.
+type metadataEditTarget struct {
+	namespace string
+	name      string
+	current   map[string]string
+}
+
+// metadataEditTargets returns the named object, or every object matching
+// the label selector.
+func metadataEditTargets(ctx context.Context, client k8s.Client, req metadataEditRequest) ([]metadataEditTarget, error) {
+	if req.name != "" {
+		resp, err := client.Get(ctx, req.kubeContext, req.namespace, req.resourceType, req.apiGroup, req.name)
+		if err != nil {
+			return nil, err
+		}
+		target, err := newMetadataEditTarget(resp.Resource, req.field)
+		if err != nil {
+			return nil, err
+		}
+		return []metadataEditTarget{target}, nil
+	}
+
+	var targets []metadataEditTarget
+	opts := k8s.ListOptions{LabelSelector: req.labelSelector, AllNamespaces: req.allNamespaces}
+	for {
+		resp, err := client.List(ctx, req.kubeContext, req.namespace, req.resourceType, req.apiGroup, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			target, err := newMetadataEditTarget(item, req.field)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+		}
+		// One more than the cap is enough to refuse the call.
+		if resp.Continue == "" || len(targets) > MaxMetadataEditObjects {
+			break
+		}
+		opts.Continue = resp.Continue
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].namespace != targets[j].namespace {
+			return targets[i].namespace < targets[j].namespace
+		}
+		return targets[i].name < targets[j].name
+	})
+	return targets, nil
+}
+
+func newMetadataEditTarget(obj runtime.Object, field string) (metadataEditTarget, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return metadataEditTarget{}, fmt.Errorf("failed to read object metadata: %w", err)
+	}
+	target := metadataEditTarget{namespace: accessor.GetNamespace(), name: accessor.GetName()}
+	if field == metadataLabels {
+		target.current = accessor.GetLabels()
+	} else {
+		target.current = accessor.GetAnnotations()
+	}
+	return target, nil
+}
+
+// editObjectMetadata works out the change for one object and, unless
+// previewing, applies it with a merge patch.
+func editObjectMetadata(ctx context.Context, sc *server.ServerContext, client k8s.Client, req metadataEditRequest, target metadataEditTarget) MetadataEditObject {
+	out := MetadataEditObject{Namespace: target.namespace, Name: target.name}
+	patch := map[string]interface{}{}
+
+	var conflicts []string
+	for key, value := range req.set {
+		current, exists := target.current[key]
+		switch {
+		case exists && current == value:
+			continue
+		case exists && !req.overwrite:
+			conflicts = append(conflicts, fmt.Sprintf("%s=%s", key, current))
+			continue
+		}
+		if out.Set == nil {
+			out.Set = map[string]string{}
+		}
+		out.Set[key] = value
+		patch[key] = value
+	}
+	for _, key := range req.remove {
+		if _, exists := target.current[key]; exists {
+			out.Removed = append(out.Removed, key)
+			patch[key] = nil
+		}
+	}
+	slices.Sort(out.Removed)
+
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		out.Status = MetadataEditFailed
+		out.Set, out.Removed = nil, nil
+		out.Error = fmt.Sprintf("already has %s; set overwrite to replace", strings.Join(conflicts, ", "))
+		return out
+	}
+	if len(patch) == 0 {
+		out.Status = MetadataEditUnchanged
+		return out
+	}
+	if req.preview {
+		out.Status = MetadataEditPlanned
+		return out
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{req.field: patch}})
+	if err != nil {
+		out.Status = MetadataEditFailed
+		out.Error = err.Error()
+		return out
+	}
+	namespace := target.namespace
+	if namespace == "" {
+		namespace = req.namespace
+	}
+	tool := "annotate"
+	if req.field == metadataLabels {
+		tool = "label"
+	}
+	undo := tools.SnapshotForUndo(ctx, sc, client, tool, tools.UndoTarget{
+		Cluster:      req.cluster,
+		KubeContext:  req.kubeContext,
+		Namespace:    namespace,
+		ResourceType: req.resourceType,
+		APIGroup:     req.apiGroup,
+		Name:         target.name,
+	})
+	if _, err := client.Patch(ctx, req.kubeContext, namespace, req.resourceType, req.apiGroup, target.name, types.MergePatchType, data); err != nil {
+		out.Status = MetadataEditFailed
+		out.Error = err.Error()
+		return out
+	}
+	tools.RecordForUndo(ctx, sc, undo)
+	out.Status = MetadataEditUpdated
+	return out
+}
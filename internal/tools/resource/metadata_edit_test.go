@@ -0,0 +1,210 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// metadataMock serves deployments and records the patches sent to them.
+type metadataMock struct {
+	*testdata.MockK8sClient
+
+	objects  []*unstructured.Unstructured
+	failName string
+
+	listOpts []k8s.ListOptions
+	patches  map[string]string
+}
+
+func (m *metadataMock) Get(_ context.Context, _, namespace, _, _, name string) (*k8s.GetResponse, error) {
+	for _, obj := range m.objects {
+		if obj.GetNamespace() == namespace && obj.GetName() == name {
+			return &k8s.GetResponse{Resource: obj}, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *metadataMock) List(_ context.Context, _, _, _, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	m.listOpts = append(m.listOpts, opts)
+	items := make([]runtime.Object, 0, len(m.objects))
+	for _, obj := range m.objects {
+		items = append(items, obj)
+	}
+	return &k8s.PaginatedListResponse{Items: items}, nil
+}
+
+func (m *metadataMock) Patch(_ context.Context, _, namespace, _, _, name string, patchType types.PatchType, data []byte) (*k8s.PatchResponse, error) {
+	if patchType != types.MergePatchType {
+		return nil, errors.New("unexpected patch type")
+	}
+	if name == m.failName {
+		return nil, errors.New("conflict")
+	}
+	if m.patches == nil {
+		m.patches = map[string]string{}
+	}
+	m.patches[namespace+"/"+name] = string(data)
+	return &k8s.PatchResponse{}, nil
+}
+
+func metadataObject(name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("Deployment")
+	obj.SetNamespace("shop")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func callEditMetadata(t *testing.T, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), mock *metadataMock, args map[string]interface{}) (*mcp.CallToolResult, MetadataEditResult) {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request, sc)
+	require.NoError(t, err)
+
+	var out MetadataEditResult
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+	}
+	return result, out
+}
+
+func TestHandleLabel_Selector(t *testing.T) {
+	mock := &metadataMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects: []*unstructured.Unstructured{
+			metadataObject("web", map[string]string{"app": "web", "tier": "frontend", "legacy": "true"}),
+			metadataObject("api", map[string]string{"app": "api", "tier": "backend"}),
+			metadataObject("done", map[string]string{"tier": "frontend"}),
+		},
+	}
+	result, out := callEditMetadata(t, handleLabel, mock, map[string]interface{}{
+		"resourceType":  "deployments",
+		"namespace":     "shop",
+		"labelSelector": "team!=payments",
+		"set":           map[string]interface{}{"tier": "frontend"},
+		"remove":        []interface{}{"legacy"},
+	})
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	assert.Equal(t, "labels", out.Field)
+	assert.Equal(t, 3, out.Matched)
+	assert.Equal(t, 1, out.Updated)
+	assert.Equal(t, 1, out.Unchanged)
+	assert.Equal(t, 1, out.Failed)
+	assert.Equal(t, "team!=payments", mock.listOpts[0].LabelSelector)
+
+	byName := map[string]MetadataEditObject{}
+	for _, o := range out.Objects {
+		byName[o.Name] = o
+	}
+	assert.Equal(t, MetadataEditFailed, byName["api"].Status)
+	assert.Contains(t, byName["api"].Error, "tier=backend")
+	assert.Equal(t, MetadataEditUnchanged, byName["done"].Status)
+	assert.Equal(t, MetadataEditUpdated, byName["web"].Status)
+	assert.Equal(t, []string{"legacy"}, byName["web"].Removed)
+	assert.Equal(t, map[string]string{"shop/web": `{"metadata":{"labels":{"legacy":null}}}`}, mock.patches)
+}
+
+func TestHandleLabel_OverwriteAndPreview(t *testing.T) {
+	newMock := func() *metadataMock {
+		return &metadataMock{
+			MockK8sClient: &testdata.MockK8sClient{},
+			objects:       []*unstructured.Unstructured{metadataObject("api", map[string]string{"tier": "backend"})},
+		}
+	}
+	args := func(preview bool) map[string]interface{} {
+		return map[string]interface{}{
+			"resourceType": "deployments",
+			"namespace":    "shop",
+			"name":         "api",
+			"set":          map[string]interface{}{"tier": "frontend"},
+			"overwrite":    true,
+			"preview":      preview,
+		}
+	}
+
+	mock := newMock()
+	_, out := callEditMetadata(t, handleLabel, mock, args(true))
+	assert.True(t, out.Preview)
+	require.Len(t, out.Objects, 1)
+	assert.Equal(t, MetadataEditPlanned, out.Objects[0].Status)
+	assert.Equal(t, map[string]string{"tier": "frontend"}, out.Objects[0].Set)
+	assert.Empty(t, mock.patches, "preview changes nothing")
+
+	mock = newMock()
+	_, out = callEditMetadata(t, handleLabel, mock, args(false))
+	assert.Equal(t, MetadataEditUpdated, out.Objects[0].Status)
+	assert.Equal(t, `{"metadata":{"labels":{"tier":"frontend"}}}`, mock.patches["shop/api"])
+}
+
+func TestHandleAnnotate_PatchFailure(t *testing.T) {
+	mock := &metadataMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects:       []*unstructured.Unstructured{metadataObject("web", nil)},
+		failName:      "web",
+	}
+	result, out := callEditMetadata(t, handleAnnotate, mock, map[string]interface{}{
+		"resourceType": "deployments",
+		"namespace":    "shop",
+		"name":         "web",
+		"set":          map[string]interface{}{"example.com/owner": "alice"},
+	})
+	require.False(t, result.IsError, getErrorText(t, result))
+	assert.Equal(t, "annotations", out.Field)
+	assert.Equal(t, 1, out.Failed)
+	assert.Equal(t, "conflict", out.Objects[0].Error)
+}
+
+func TestParseMetadataEditRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "no target", args: map[string]interface{}{"set": map[string]interface{}{"a": "b"}}, wantErr: "either name or labelSelector"},
+		{name: "both targets", args: map[string]interface{}{"name": "x", "labelSelector": "a=b", "set": map[string]interface{}{"a": "b"}}, wantErr: "cannot be combined"},
+		{name: "nothing to do", args: map[string]interface{}{"name": "x"}, wantErr: "set or remove is required"},
+		{name: "set and removed", args: map[string]interface{}{"name": "x", "set": map[string]interface{}{"a": "b"}, "remove": []interface{}{"a"}}, wantErr: "both set and removed"},
+		{name: "invalid key", args: map[string]interface{}{"name": "x", "set": map[string]interface{}{"bad key": "b"}}, wantErr: "invalid key"},
+		{name: "invalid label value", args: map[string]interface{}{"name": "x", "set": map[string]interface{}{"a": "not valid!"}}, wantErr: "invalid value"},
+		{name: "invalid selector", args: map[string]interface{}{"labelSelector": "a in (", "set": map[string]interface{}{"a": "b"}}, wantErr: "invalid labelSelector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["resourceType"] = "deployments"
+			_, errMsg := parseMetadataEditRequest(tt.args, metadataLabels)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+
+	_, errMsg := parseMetadataEditRequest(map[string]interface{}{
+		"resourceType": "deployments",
+		"name":         "x",
+		"set":          map[string]interface{}{"note": "free text is fine!"},
+	}, metadataAnnotations)
+	assert.Empty(t, errMsg, "annotation values are not label values")
+}
@@ -0,0 +1,465 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// Default and maximum values for the namespace_overview tool's limit param.
+const (
+	DefaultNamespaceOverviewLimit = 20
+	MaxNamespaceOverviewLimit     = 500
+)
+
+// namespaceWorkloadTypes are the workloads namespace_overview lists.
+var namespaceWorkloadTypes = []treeChildType{
+	{"deployments", "apps", "Deployment"},
+	{"statefulsets", "apps", "StatefulSet"},
+	{"daemonsets", "apps", "DaemonSet"},
+}
+
+// NamespaceOverviewResult is the output of the namespace_overview tool. Each
+// list holds at most limit entries; the counts always cover everything.
+type NamespaceOverviewResult struct {
+	Namespace string `json:"namespace"`
+	Status    string `json:"status,omitempty"`
+	Age       string `json:"age,omitempty"`
+
+	// Workloads are the Deployments, StatefulSets and DaemonSets, those not
+	// ready first.
+	Workloads      []NamespaceWorkload `json:"workloads"`
+	TotalWorkloads int                 `json:"totalWorkloads"`
+
+	Jobs NamespaceJobs `json:"jobs"`
+	Pods NamespacePods `json:"pods"`
+
+	Services      []NamespaceService `json:"services"`
+	TotalServices int                `json:"totalServices"`
+
+	Ingresses      []NamespaceIngress `json:"ingresses"`
+	TotalIngresses int                `json:"totalIngresses"`
+
+	ConfigMaps             int             `json:"configMaps"`
+	Secrets                int             `json:"secrets"`
+	PersistentVolumeClaims NamespaceClaims `json:"persistentVolumeClaims"`
+
+	// Quotas is set when the namespace has ResourceQuotas.
+	Quotas *NamespaceQuotaSummary `json:"quotas,omitempty"`
+
+	// Truncated names the lists cut to limit.
+	Truncated []string `json:"truncated,omitempty"`
+
+	// Unavailable lists the data that could not be read, e.g. for lack of
+	// list permission.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// NamespaceWorkload is a workload in the output of namespace_overview.
+type NamespaceWorkload struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	output.ObjectState
+	Age string `json:"age,omitempty"`
+}
+
+// NamespaceJobs counts the Jobs and CronJobs of a namespace.
+type NamespaceJobs struct {
+	Total    int `json:"total"`
+	Active   int `json:"active"`
+	Failed   int `json:"failed"`
+	CronJobs int `json:"cronJobs"`
+
+	// FailedJobs names the failed Jobs, up to limit.
+	FailedJobs []string `json:"failedJobs,omitempty"`
+}
+
+// NamespacePods counts the pods of a namespace by phase. Failing pods are
+// failed, or have a container crash looping, unable to pull its image or
+// unable to start.
+type NamespacePods struct {
+	Total   int `json:"total"`
+	Running int `json:"running"`
+	Pending int `json:"pending"`
+	Failing int `json:"failing"`
+
+	// FailingPods lists the failing pods, up to limit.
+	FailingPods []NamespacePod `json:"failingPods,omitempty"`
+}
+
+// NamespacePod is a failing pod in the output of namespace_overview.
+type NamespacePod struct {
+	Name string `json:"name"`
+	output.ObjectState
+	Age string `json:"age,omitempty"`
+}
+
+// NamespaceService is a Service in the output of namespace_overview.
+type NamespaceService struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	ClusterIP string   `json:"clusterIP,omitempty"`
+	External  string   `json:"external,omitempty"`
+	Ports     []string `json:"ports,omitempty"`
+}
+
+// NamespaceIngress is an Ingress in the output of namespace_overview.
+type NamespaceIngress struct {
+	Name    string   `json:"name"`
+	Class   string   `json:"class,omitempty"`
+	Hosts   []string `json:"hosts,omitempty"`
+	Address string   `json:"address,omitempty"`
+}
+
+// NamespaceClaims counts the PersistentVolumeClaims of a namespace.
+type NamespaceClaims struct {
+	Total int `json:"total"`
+	Bound int `json:"bound"`
+
+	// Pending names the claims not bound yet, up to limit.
+	Pending []string `json:"pending,omitempty"`
+}
+
+// namespaceOverviewRequest holds the validated namespace_overview arguments.
+type namespaceOverviewRequest struct {
+	cluster     string
+	kubeContext string
+	namespace   string
+	limit       int
+}
+
+// handleNamespaceOverview summarizes everything in a namespace in one call.
+func handleNamespaceOverview(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req := namespaceOverviewRequest{
+		cluster: tools.ExtractClusterParam(args),
+		limit:   DefaultNamespaceOverviewLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	if req.namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxNamespaceOverviewLimit {
+			return toolerrors.InvalidArgumentf("limit must be between 1 and %d", MaxNamespaceOverviewLimit).Result(), nil
+		}
+		req.limit = int(v)
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	resp, err := client.K8s().Get(ctx, req.kubeContext, "", "namespaces", "", req.namespace)
+	if err != nil {
+		return tools.K8sError("Failed to get namespace", err, client.User()).Result(), nil
+	}
+	ns, err := toUnstructuredObject(resp.Resource)
+	if err != nil {
+		return toolerrors.Internalf("Failed to read namespace: %v", err).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(collectNamespaceOverview(ctx, client.K8s(), req, ns), "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// namespaceScan collects the data of one namespace_overview call. Each part
+// is read independently, so a missing permission only leaves out that part.
+type namespaceScan struct {
+	client k8s.Client
+	req    namespaceOverviewRequest
+	out    *NamespaceOverviewResult
+}
+
+func (s *namespaceScan) unavailable(what string, err error) {
+	s.out.Unavailable = append(s.out.Unavailable, fmt.Sprintf("%s: %v", what, err))
+}
+
+func (s *namespaceScan) truncate(what string, n int) int {
+	if n <= s.req.limit {
+		return n
+	}
+	s.out.Truncated = append(s.out.Truncated, what)
+	return s.req.limit
+}
+
+// collectNamespaceOverview reads the workloads, pods, services, ingresses,
+// configuration, claims and quotas of a namespace.
+func collectNamespaceOverview(ctx context.Context, client k8s.Client, req namespaceOverviewRequest, ns *unstructured.Unstructured) *NamespaceOverviewResult {
+	s := &namespaceScan{
+		client: client,
+		req:    req,
+		out: &NamespaceOverviewResult{
+			Namespace: req.namespace,
+			Workloads: []NamespaceWorkload{},
+			Services:  []NamespaceService{},
+			Ingresses: []NamespaceIngress{},
+		},
+	}
+	s.out.Status, _, _ = unstructured.NestedString(ns.Object, "status", "phase")
+	if created := ns.GetCreationTimestamp(); !created.IsZero() {
+		s.out.Age = formatAge(created.Time)
+	}
+
+	s.readWorkloads(ctx)
+	s.readJobs(ctx)
+	s.readPods(ctx)
+	s.readServices(ctx)
+	s.readIngresses(ctx)
+	s.out.ConfigMaps = s.count(ctx, "configmaps")
+	s.out.Secrets = s.count(ctx, "secrets")
+	s.readClaims(ctx)
+
+	quotas, err := listNamespaced[corev1.ResourceQuota](ctx, client, req.kubeContext, req.namespace, "resourcequotas")
+	if err != nil {
+		s.unavailable("resourcequotas", err)
+	}
+	s.out.Quotas = summarizeQuotas(quotas)
+	return s.out
+}
+
+// list pages through the objects of a type in the namespace. Objects decoded
+// from protobuf carry no kind, so it is set from t.
+func (s *namespaceScan) list(ctx context.Context, t treeChildType) ([]*unstructured.Unstructured, error) {
+	var items []*unstructured.Unstructured
+	opts := k8s.ListOptions{}
+	for {
+		resp, err := s.client.List(ctx, s.req.kubeContext, s.req.namespace, t.resource, t.apiGroup, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			obj, err := toUnstructuredObject(item)
+			if err != nil {
+				return nil, err
+			}
+			if obj.GetKind() == "" {
+				obj.SetKind(t.kind)
+			}
+			items = append(items, obj)
+		}
+		if resp.Continue == "" {
+			return items, nil
+		}
+		opts.Continue = resp.Continue
+	}
+}
+
+// count returns the number of objects of a core type in the namespace, or 0
+// when they cannot be listed.
+func (s *namespaceScan) count(ctx context.Context, resourceType string) int {
+	n := 0
+	opts := k8s.ListOptions{}
+	for {
+		resp, err := s.client.List(ctx, s.req.kubeContext, s.req.namespace, resourceType, "", opts)
+		if err != nil {
+			s.unavailable(resourceType, err)
+			return n
+		}
+		n += len(resp.Items)
+		if resp.Continue == "" {
+			return n
+		}
+		opts.Continue = resp.Continue
+	}
+}
+
+func (s *namespaceScan) readWorkloads(ctx context.Context) {
+	var workloads []NamespaceWorkload
+	for _, t := range namespaceWorkloadTypes {
+		objects, err := s.list(ctx, t)
+		if err != nil {
+			s.unavailable(t.resource, err)
+			continue
+		}
+		for _, obj := range objects {
+			w := NamespaceWorkload{Kind: t.kind, Name: obj.GetName(), ObjectState: output.ObjectStatus(obj.Object)}
+			if created := obj.GetCreationTimestamp(); !created.IsZero() {
+				w.Age = formatAge(created.Time)
+			}
+			workloads = append(workloads, w)
+		}
+	}
+	sort.SliceStable(workloads, func(i, j int) bool {
+		ri, rj := workloadSettled(workloads[i].Status), workloadSettled(workloads[j].Status)
+		if ri != rj {
+			return !ri
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	s.out.TotalWorkloads = len(workloads)
+	s.out.Workloads = append(s.out.Workloads, workloads[:s.truncate("workloads", len(workloads))]...)
+}
+
+// workloadSettled reports whether a workload status needs no attention.
+func workloadSettled(status string) bool {
+	return status == "Ready" || status == "Scaled to Zero"
+}
+
+func (s *namespaceScan) readJobs(ctx context.Context) {
+	jobs := &s.out.Jobs
+	items, err := listGroupObjects[batchv1.Job](ctx, s.client, s.req.kubeContext, s.req.namespace, "jobs", "batch", k8s.ListOptions{})
+	if err != nil {
+		s.unavailable("jobs", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	for i := range items {
+		job := &items[i]
+		jobs.Total++
+		if job.Status.Active > 0 {
+			jobs.Active++
+		}
+		if jobFailed(job) {
+			jobs.Failed++
+			if len(jobs.FailedJobs) < s.req.limit {
+				jobs.FailedJobs = append(jobs.FailedJobs, job.Name)
+			}
+		}
+	}
+
+	cronJobs, err := listGroupObjects[batchv1.CronJob](ctx, s.client, s.req.kubeContext, s.req.namespace, "cronjobs", "batch", k8s.ListOptions{})
+	if err != nil {
+		s.unavailable("cronjobs", err)
+	}
+	jobs.CronJobs = len(cronJobs)
+}
+
+// jobFailed reports whether a job's Failed condition is true.
+func jobFailed(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *namespaceScan) readPods(ctx context.Context) {
+	pods := &s.out.Pods
+	objects, err := s.list(ctx, treeChildType{"pods", "", "Pod"})
+	if err != nil {
+		s.unavailable("pods", err)
+		return
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].GetName() < objects[j].GetName() })
+	for _, obj := range objects {
+		pods.Total++
+		switch phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); corev1.PodPhase(phase) {
+		case corev1.PodRunning:
+			pods.Running++
+		case corev1.PodPending:
+			pods.Pending++
+		}
+		if _, failing := output.PodFailing(obj.Object); !failing {
+			continue
+		}
+		pods.Failing++
+		if len(pods.FailingPods) < s.req.limit {
+			pod := NamespacePod{Name: obj.GetName(), ObjectState: output.ObjectStatus(obj.Object)}
+			if created := obj.GetCreationTimestamp(); !created.IsZero() {
+				pod.Age = formatAge(created.Time)
+			}
+			pods.FailingPods = append(pods.FailingPods, pod)
+		}
+	}
+}
+
+func (s *namespaceScan) readServices(ctx context.Context) {
+	services, err := listNamespaced[corev1.Service](ctx, s.client, s.req.kubeContext, s.req.namespace, "services")
+	if err != nil {
+		s.unavailable("services", err)
+		return
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	s.out.TotalServices = len(services)
+	for i := range services[:s.truncate("services", len(services))] {
+		svc := &services[i]
+		out := NamespaceService{Name: svc.Name, Type: string(svc.Spec.Type), ClusterIP: svc.Spec.ClusterIP}
+		var external []string
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			external = append(external, ing.IP+ing.Hostname)
+		}
+		external = append(external, svc.Spec.ExternalIPs...)
+		if svc.Spec.ExternalName != "" {
+			external = append(external, svc.Spec.ExternalName)
+		}
+		out.External = strings.Join(external, ",")
+		for _, p := range svc.Spec.Ports {
+			port := strconv.Itoa(int(p.Port))
+			if p.NodePort > 0 {
+				port += ":" + strconv.Itoa(int(p.NodePort))
+			}
+			out.Ports = append(out.Ports, port+"/"+string(p.Protocol))
+		}
+		s.out.Services = append(s.out.Services, out)
+	}
+}
+
+func (s *namespaceScan) readIngresses(ctx context.Context) {
+	ingresses, err := listGroupObjects[networkingv1.Ingress](ctx, s.client, s.req.kubeContext, s.req.namespace, "ingresses", "networking.k8s.io", k8s.ListOptions{})
+	if err != nil {
+		s.unavailable("ingresses", err)
+		return
+	}
+	sort.Slice(ingresses, func(i, j int) bool { return ingresses[i].Name < ingresses[j].Name })
+	s.out.TotalIngresses = len(ingresses)
+	for i := range ingresses[:s.truncate("ingresses", len(ingresses))] {
+		ing := &ingresses[i]
+		out := NamespaceIngress{Name: ing.Name}
+		if ing.Spec.IngressClassName != nil {
+			out.Class = *ing.Spec.IngressClassName
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != "" {
+				out.Hosts = append(out.Hosts, rule.Host)
+			}
+		}
+		var addresses []string
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			addresses = append(addresses, lb.IP+lb.Hostname)
+		}
+		out.Address = strings.Join(addresses, ",")
+		s.out.Ingresses = append(s.out.Ingresses, out)
+	}
+}
+
+func (s *namespaceScan) readClaims(ctx context.Context) {
+	claims := &s.out.PersistentVolumeClaims
+	items, err := listNamespaced[corev1.PersistentVolumeClaim](ctx, s.client, s.req.kubeContext, s.req.namespace, "persistentvolumeclaims")
+	if err != nil {
+		s.unavailable("persistentvolumeclaims", err)
+		return
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	for i := range items {
+		claims.Total++
+		switch items[i].Status.Phase {
+		case corev1.ClaimBound:
+			claims.Bound++
+		case corev1.ClaimPending:
+			if len(claims.Pending) < s.req.limit {
+				claims.Pending = append(claims.Pending, items[i].Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// newNamespaceOverviewMock serves the shop namespace, whose ResourceQuotas
+// cannot be listed.
+func newNamespaceOverviewClient() *mcptest.FakeClient {
+	meta := func(name string) metav1.ObjectMeta { return metav1.ObjectMeta{Namespace: "shop", Name: name} }
+	two, one := int32(2), int32(1)
+	nginx := "nginx"
+
+	client := mcptest.NewFakeClient(map[string][]runtime.Object{
+		"namespaces": {
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+		},
+		"deployments": {
+			&appsv1.Deployment{ObjectMeta: meta("web"), Spec: appsv1.DeploymentSpec{Replicas: &two},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2, AvailableReplicas: 2}},
+			&appsv1.Deployment{ObjectMeta: meta("api"), Spec: appsv1.DeploymentSpec{Replicas: &one}},
+		},
+		"statefulsets": {
+			&appsv1.StatefulSet{ObjectMeta: meta("db"), Spec: appsv1.StatefulSetSpec{Replicas: &one},
+				Status: appsv1.StatefulSetStatus{ReadyReplicas: 1, AvailableReplicas: 1}},
+		},
+		"jobs": {
+			&batchv1.Job{ObjectMeta: meta("migrate"), Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+			}}},
+			&batchv1.Job{ObjectMeta: meta("report"), Status: batchv1.JobStatus{Active: 1}},
+		},
+		"cronjobs": {&batchv1.CronJob{ObjectMeta: meta("nightly")}},
+		"pods": {
+			&corev1.Pod{ObjectMeta: meta("web-a"), Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			&corev1.Pod{ObjectMeta: meta("api-a"), Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "api", State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+				}}},
+			}},
+			&corev1.Pod{ObjectMeta: meta("db-0"), Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		},
+		"services": {
+			&corev1.Service{ObjectMeta: meta("web"), Spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.10",
+				Ports: []corev1.ServicePort{{Port: 80, NodePort: 30080, Protocol: corev1.ProtocolTCP}},
+			}, Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "203.0.113.7"}}}}},
+			&corev1.Service{ObjectMeta: meta("db"), Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "None"}},
+		},
+		"ingresses": {
+			&networkingv1.Ingress{ObjectMeta: meta("web"), Spec: networkingv1.IngressSpec{
+				IngressClassName: &nginx,
+				Rules:            []networkingv1.IngressRule{{Host: "shop.example.com"}},
+			}},
+		},
+		"configmaps": {&corev1.ConfigMap{ObjectMeta: meta("a")}, &corev1.ConfigMap{ObjectMeta: meta("b")}},
+		"secrets":    {&corev1.Secret{ObjectMeta: meta("tls")}},
+		"persistentvolumeclaims": {
+			&corev1.PersistentVolumeClaim{ObjectMeta: meta("data-db-0"), Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}},
+			&corev1.PersistentVolumeClaim{ObjectMeta: meta("scratch"), Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+		},
+	})
+	client.Errs["resourcequotas"] = apierrors.NewForbidden(corev1.Resource("resourcequotas"), "", nil)
+	return client
+}
+
+func callNamespaceOverview(t *testing.T, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	return callResourceTool(t, newNamespaceOverviewClient(), "namespace_overview", args)
+}
+
+func TestHandleNamespaceOverview(t *testing.T) {
+	result := callNamespaceOverview(t, map[string]interface{}{"namespace": "shop"})
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var out NamespaceOverviewResult
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+
+	assert.Equal(t, "shop", out.Namespace)
+	assert.Equal(t, "Active", out.Status)
+
+	// Workloads that are not ready come first.
+	assert.Equal(t, 3, out.TotalWorkloads)
+	require.Len(t, out.Workloads, 3)
+	assert.Equal(t, NamespaceWorkload{Kind: "Deployment", Name: "api", ObjectState: output.ObjectState{Status: "NotReady", Ready: "0/1"}}, out.Workloads[0])
+	assert.Equal(t, "StatefulSet", out.Workloads[1].Kind)
+	assert.Equal(t, "Ready", out.Workloads[2].Status)
+
+	assert.Equal(t, NamespaceJobs{Total: 2, Active: 1, Failed: 1, CronJobs: 1, FailedJobs: []string{"migrate"}}, out.Jobs)
+
+	assert.Equal(t, 3, out.Pods.Total)
+	assert.Equal(t, 1, out.Pods.Running)
+	assert.Equal(t, 2, out.Pods.Pending)
+	assert.Equal(t, 1, out.Pods.Failing)
+	require.Len(t, out.Pods.FailingPods, 1)
+	assert.Equal(t, "api-a", out.Pods.FailingPods[0].Name)
+	assert.Equal(t, "ImagePullBackOff", out.Pods.FailingPods[0].Status)
+
+	require.Len(t, out.Services, 2)
+	assert.Equal(t, NamespaceService{Name: "web", Type: "LoadBalancer", ClusterIP: "10.0.0.10", External: "203.0.113.7", Ports: []string{"80:30080/TCP"}}, out.Services[1])
+	assert.Equal(t, []NamespaceIngress{{Name: "web", Class: "nginx", Hosts: []string{"shop.example.com"}}}, out.Ingresses)
+
+	assert.Equal(t, 2, out.ConfigMaps)
+	assert.Equal(t, 1, out.Secrets)
+	assert.Equal(t, NamespaceClaims{Total: 2, Bound: 1, Pending: []string{"scratch"}}, out.PersistentVolumeClaims)
+
+	assert.Nil(t, out.Quotas)
+	require.Len(t, out.Unavailable, 1)
+	assert.Contains(t, out.Unavailable[0], "resourcequotas")
+	assert.Empty(t, out.Truncated)
+}
+
+func TestHandleNamespaceOverview_Limit(t *testing.T) {
+	result := callNamespaceOverview(t, map[string]interface{}{"namespace": "shop", "limit": float64(1)})
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var out NamespaceOverviewResult
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+	assert.Len(t, out.Workloads, 1)
+	assert.Equal(t, 3, out.TotalWorkloads)
+	assert.Len(t, out.Services, 1)
+	assert.Equal(t, []string{"workloads", "services"}, out.Truncated)
+}
+
+func TestHandleNamespaceOverview_Errors(t *testing.T) {
+	result := callNamespaceOverview(t, map[string]interface{}{})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getErrorText(t, result), "namespace")
+
+	result = callNamespaceOverview(t, map[string]interface{}{"namespace": "missing"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, getErrorText(t, result), "not found")
+}
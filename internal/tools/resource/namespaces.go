@@ -0,0 +1,356 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Default and maximum values for the list_namespaces tool's limit param.
+const (
+	DefaultNamespacesLimit = 100
+	MaxNamespacesLimit     = 1000
+)
+
+// systemNamespaces are created and used by Kubernetes itself.
+// delete_namespace never deletes them, whatever the server configuration.
+var systemNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease"}
+
+// NamespaceListResult is the output of the list_namespaces tool.
+type NamespaceListResult struct {
+	Namespaces []NamespaceSummary `json:"namespaces"`
+	Total      int                `json:"total"`
+	Returned   int                `json:"returned"`
+	Truncated  bool               `json:"truncated,omitempty"`
+
+	// QuotaError explains why quotas are missing, e.g. when the caller may
+	// not list ResourceQuotas in every namespace.
+	QuotaError string `json:"quotaError,omitempty"`
+}
+
+// NamespaceSummary is a namespace in the output of list_namespaces.
+type NamespaceSummary struct {
+	Name   string            `json:"name"`
+	Status string            `json:"status"`
+	Age    string            `json:"age"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Quotas is set when the namespace has ResourceQuotas.
+	Quotas *NamespaceQuotaSummary `json:"quotas,omitempty"`
+}
+
+// NamespaceQuotaSummary condenses the ResourceQuotas of a namespace.
+type NamespaceQuotaSummary struct {
+	Count int `json:"count"`
+
+	// Usage maps each tracked resource to "used/hard", e.g. "1500m/4".
+	// When several quotas track a resource, the fullest one is shown.
+	Usage map[string]string `json:"usage"`
+
+	// Exhausted lists the resources with nothing left.
+	Exhausted []string `json:"exhausted,omitempty"`
+}
+
+// NamespaceResult is the output of the create_namespace and
+// delete_namespace tools.
+type NamespaceResult struct {
+	Namespace   string            `json:"namespace"`
+	Status      string            `json:"status,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	DryRun      bool              `json:"dryRun,omitempty"`
+	Message     string            `json:"message,omitempty"`
+}
+
+// namespaceListRequest holds the validated list_namespaces arguments.
+type namespaceListRequest struct {
+	cluster       string
+	kubeContext   string
+	labelSelector string
+	includeLabels bool
+	includeQuotas bool
+	limit         int
+}
+
+// handleListNamespaces lists namespaces with their status and, unless
+// disabled, a summary of their quota usage.
+func handleListNamespaces(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseNamespaceListRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	namespaces, err := listObjects[corev1.Namespace](ctx, k8sClient, req.kubeContext, "", "namespaces", k8s.ListOptions{LabelSelector: req.labelSelector})
+	if err != nil {
+		return tools.K8sError("Failed to list namespaces", err, client.User()).Result(), nil
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+
+	result := &NamespaceListResult{Total: len(namespaces)}
+	if len(namespaces) > req.limit {
+		namespaces = namespaces[:req.limit]
+		result.Truncated = true
+	}
+	result.Returned = len(namespaces)
+
+	var quotas map[string][]corev1.ResourceQuota
+	if req.includeQuotas && len(namespaces) > 0 {
+		all, err := listObjects[corev1.ResourceQuota](ctx, k8sClient, req.kubeContext, "", "resourcequotas", k8s.ListOptions{AllNamespaces: true})
+		if err != nil {
+			result.QuotaError = fmt.Sprintf("failed to list resource quotas: %v", err)
+		}
+		quotas = make(map[string][]corev1.ResourceQuota)
+		for _, quota := range all {
+			quotas[quota.Namespace] = append(quotas[quota.Namespace], quota)
+		}
+	}
+
+	result.Namespaces = make([]NamespaceSummary, 0, len(namespaces))
+	for i := range namespaces {
+		ns := &namespaces[i]
+		summary := NamespaceSummary{
+			Name:   ns.Name,
+			Status: string(ns.Status.Phase),
+			Age:    formatAge(ns.CreationTimestamp.Time),
+			Quotas: summarizeQuotas(quotas[ns.Name]),
+		}
+		if req.includeLabels {
+			summary.Labels = namespaceLabels(ns.Labels)
+		}
+		result.Namespaces = append(result.Namespaces, summary)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseNamespaceListRequest validates the list_namespaces arguments. limit
+// must lie within 1 and MaxNamespacesLimit, and labelSelector must parse.
+func parseNamespaceListRequest(args map[string]interface{}) (namespaceListRequest, string) {
+	req := namespaceListRequest{
+		cluster:       tools.ExtractClusterParam(args),
+		includeQuotas: true,
+		limit:         DefaultNamespacesLimit,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.includeLabels, _ = args["includeLabels"].(bool)
+	if v, ok := args["includeQuotas"].(bool); ok {
+		req.includeQuotas = v
+	}
+
+	if v, ok := args["limit"].(float64); ok {
+		if v < 1 || v > MaxNamespacesLimit {
+			return req, fmt.Sprintf("limit must be between 1 and %d", MaxNamespacesLimit)
+		}
+		req.limit = int(v)
+	}
+
+	req.labelSelector, _ = args["labelSelector"].(string)
+	if req.labelSelector != "" {
+		if _, err := labels.Parse(req.labelSelector); err != nil {
+			return req, fmt.Sprintf("invalid labelSelector: %v", err)
+		}
+	}
+	return req, ""
+}
+
+// summarizeQuotas condenses the quotas of one namespace. It returns nil when
+// there are none.
+func summarizeQuotas(quotas []corev1.ResourceQuota) *NamespaceQuotaSummary {
+	if len(quotas) == 0 {
+		return nil
+	}
+	summary := &NamespaceQuotaSummary{Count: len(quotas), Usage: map[string]string{}}
+	fullest := map[string]float64{}
+	exhausted := map[string]bool{}
+	for i := range quotas {
+		for _, r := range quotaStatus(&quotas[i]).Resources {
+			if percent, seen := fullest[r.Resource]; !seen || r.UsedPercent > percent {
+				fullest[r.Resource] = r.UsedPercent
+				summary.Usage[r.Resource] = r.Used + "/" + r.Hard
+			}
+			if r.Exhausted {
+				exhausted[r.Resource] = true
+			}
+		}
+	}
+	for name := range exhausted {
+		summary.Exhausted = append(summary.Exhausted, name)
+	}
+	slices.Sort(summary.Exhausted)
+	return summary
+}
+
+// namespaceLabels drops the kubernetes.io/metadata.name label, which every
+// namespace carries with its own name.
+func namespaceLabels(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		if k != corev1.LabelMetadataName {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// handleCreateNamespace creates a namespace with optional labels and
+// annotations.
+func handleCreateNamespace(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "create"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	kubeContext, _ := args["kubeContext"].(string)
+	name, _ := args["namespace"].(string)
+	if name == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return toolerrors.InvalidArgumentf("invalid namespace name %q: %s", name, strings.Join(errs, "; ")).Result(), nil
+	}
+	nsLabels, errMsg := stringMapArg(args, "labels")
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+	annotations, errMsg := stringMapArg(args, "annotations")
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Namespace")
+	obj.SetName(name)
+	obj.SetLabels(nsLabels)
+	obj.SetAnnotations(annotations)
+
+	created, err := client.K8s().Create(ctx, kubeContext, "", obj)
+	if err != nil {
+		return tools.K8sError("Failed to create namespace", err, client.User()).Result(), nil
+	}
+
+	result := NamespaceResult{
+		Namespace:   name,
+		Labels:      nsLabels,
+		Annotations: annotations,
+		DryRun:      tools.IsDryRun(ctx, sc),
+	}
+	var ns corev1.Namespace
+	if u, err := toUnstructuredObject(created); err == nil {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &ns); err == nil {
+			result.Status = string(ns.Status.Phase)
+			result.Labels = namespaceLabels(ns.Labels)
+			result.Annotations = ns.Annotations
+		}
+	}
+	return namespaceResult(result)
+}
+
+// handleDeleteNamespace deletes a namespace and everything in it. The caller
+// must repeat the namespace name in confirm, and system and restricted
+// namespaces are refused.
+func handleDeleteNamespace(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	if result := checkMutatingOperation(sc, "delete"); result != nil {
+		return result, nil
+	}
+
+	args := request.GetArguments()
+	kubeContext, _ := args["kubeContext"].(string)
+	name, _ := args["namespace"].(string)
+	if name == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+	if reason := protectedNamespace(sc, name); reason != "" {
+		return toolerrors.New(toolerrors.CodeOperationNotAllowed, reason).Result(), nil
+	}
+	if confirm, _ := args["confirm"].(string); confirm != name {
+		return toolerrors.InvalidArgumentf("confirm must repeat the namespace name %q to delete it and everything in it", name).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, tools.ExtractClusterParam(args))
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	if _, err := client.K8s().Delete(ctx, kubeContext, "", "namespaces", "", name); err != nil {
+		return tools.K8sError("Failed to delete namespace", err, client.User()).Result(), nil
+	}
+
+	return namespaceResult(NamespaceResult{
+		Namespace: name,
+		Status:    string(corev1.NamespaceTerminating),
+		DryRun:    tools.IsDryRun(ctx, sc),
+		Message:   "The namespace controller deletes every object in the namespace before the namespace itself disappears; finalizers can hold it in Terminating.",
+	})
+}
+
+// protectedNamespace returns why delete_namespace refuses a namespace, or
+// an empty string if it may be deleted.
+func protectedNamespace(sc *server.ServerContext, name string) string {
+	if slices.Contains(systemNamespaces, name) {
+		return fmt.Sprintf("namespace %q is a system namespace and cannot be deleted", name)
+	}
+	if slices.Contains(sc.Config().RestrictedNamespaces, name) {
+		return fmt.Sprintf("namespace %q is restricted and cannot be deleted", name)
+	}
+	return ""
+}
+
+// stringMapArg reads an optional object argument whose values are strings.
+func stringMapArg(args map[string]interface{}, key string) (map[string]string, string) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, ""
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Sprintf("%s must be an object of strings", key)
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Sprintf("%s.%s must be a string", key, k)
+		}
+		out[k] = s
+	}
+	return out, ""
+}
+
+func namespaceResult(result NamespaceResult) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,241 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// namespaceMock serves namespaces and quotas, and records creates and
+// deletes.
+type namespaceMock struct {
+	*testdata.MockK8sClient
+
+	namespaces []corev1.Namespace
+	quotas     []corev1.ResourceQuota
+	quotaErr   error
+
+	listOpts []k8s.ListOptions
+	created  []runtime.Object
+	deleted  []string
+}
+
+func (m *namespaceMock) List(_ context.Context, _, _, resourceType, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	m.listOpts = append(m.listOpts, opts)
+	var items []runtime.Object
+	switch resourceType {
+	case "namespaces":
+		for i := range m.namespaces {
+			items = append(items, mcptest.ToUnstructured(&m.namespaces[i]))
+		}
+	case "resourcequotas":
+		if m.quotaErr != nil {
+			return nil, m.quotaErr
+		}
+		for i := range m.quotas {
+			items = append(items, mcptest.ToUnstructured(&m.quotas[i]))
+		}
+	}
+	return &k8s.PaginatedListResponse{Items: items, TotalItems: len(items)}, nil
+}
+
+func (m *namespaceMock) Create(_ context.Context, _, _ string, obj runtime.Object) (runtime.Object, error) {
+	m.created = append(m.created, obj)
+	return obj, nil
+}
+
+func (m *namespaceMock) Delete(_ context.Context, _, _, resourceType, _, name string) (*k8s.DeleteResponse, error) {
+	m.deleted = append(m.deleted, resourceType+"/"+name)
+	return &k8s.DeleteResponse{Message: "deleted"}, nil
+}
+
+func newNamespaceMock() *namespaceMock {
+	created := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	namespace := func(name string, phase corev1.NamespacePhase, labels map[string]string) corev1.Namespace {
+		return corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, CreationTimestamp: created},
+			Status:     corev1.NamespaceStatus{Phase: phase},
+		}
+	}
+	return &namespaceMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		namespaces: []corev1.Namespace{
+			namespace("team-b", corev1.NamespaceTerminating, nil),
+			namespace("team-a", corev1.NamespaceActive, map[string]string{corev1.LabelMetadataName: "team-a", "team": "a"}),
+		},
+		quotas: []corev1.ResourceQuota{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "team-a"},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4"), corev1.ResourcePods: resource.MustParse("10")},
+					Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1500m"), corev1.ResourcePods: resource.MustParse("10")},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "team-a"},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+					Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1500m")},
+				},
+			},
+		},
+	}
+}
+
+func callNamespaceTool(t *testing.T, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), client k8s.Client, args map[string]interface{}, opts ...server.Option) *mcp.CallToolResult {
+	t.Helper()
+	opts = append([]server.Option{
+		server.WithK8sClient(client),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+	}, opts...)
+	sc, err := server.NewServerContext(context.Background(), opts...)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request, sc)
+	require.NoError(t, err)
+	return result
+}
+
+func TestHandleListNamespaces(t *testing.T) {
+	mock := newNamespaceMock()
+	result := callNamespaceTool(t, handleListNamespaces, mock, map[string]interface{}{"includeLabels": true})
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var out NamespaceListResult
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+	assert.Equal(t, 2, out.Total)
+	require.Len(t, out.Namespaces, 2)
+
+	teamA := out.Namespaces[0]
+	assert.Equal(t, "team-a", teamA.Name)
+	assert.Equal(t, "Active", teamA.Status)
+	assert.Equal(t, "2d", teamA.Age)
+	assert.Equal(t, map[string]string{"team": "a"}, teamA.Labels)
+	require.NotNil(t, teamA.Quotas)
+	assert.Equal(t, &NamespaceQuotaSummary{
+		Count:     2,
+		Usage:     map[string]string{"requests.cpu": "1500m/2", "pods": "10/10"},
+		Exhausted: []string{"pods"},
+	}, teamA.Quotas)
+
+	assert.Equal(t, "Terminating", out.Namespaces[1].Status)
+	assert.Nil(t, out.Namespaces[1].Quotas)
+	assert.True(t, mock.listOpts[1].AllNamespaces, "quotas are listed across namespaces")
+}
+
+func TestHandleListNamespaces_LimitAndQuotaError(t *testing.T) {
+	mock := newNamespaceMock()
+	mock.quotaErr = apierrors.NewForbidden(corev1.Resource("resourcequotas"), "", nil)
+	result := callNamespaceTool(t, handleListNamespaces, mock, map[string]interface{}{"limit": float64(1)})
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var out NamespaceListResult
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+	assert.Equal(t, 2, out.Total)
+	assert.Equal(t, 1, out.Returned)
+	assert.True(t, out.Truncated)
+	assert.Contains(t, out.QuotaError, "forbidden")
+	assert.Nil(t, out.Namespaces[0].Labels, "labels are omitted by default")
+}
+
+func TestHandleCreateNamespace(t *testing.T) {
+	mock := newNamespaceMock()
+	result := callNamespaceTool(t, handleCreateNamespace, mock, map[string]interface{}{
+		"namespace":   "payments",
+		"labels":      map[string]interface{}{"team": "payments"},
+		"annotations": map[string]interface{}{"owner": "alice@example.com"},
+	})
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	require.Len(t, mock.created, 1)
+	ns := mcptest.ToUnstructured(mock.created[0])
+	assert.Equal(t, "Namespace", ns.GetKind())
+	assert.Equal(t, "payments", ns.GetName())
+	assert.Equal(t, map[string]string{"team": "payments"}, ns.GetLabels())
+	assert.Equal(t, map[string]string{"owner": "alice@example.com"}, ns.GetAnnotations())
+
+	var out NamespaceResult
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+	assert.Equal(t, "payments", out.Namespace)
+	assert.Equal(t, map[string]string{"team": "payments"}, out.Labels)
+}
+
+func TestHandleCreateNamespace_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "missing name", args: map[string]interface{}{}, wantErr: "namespace"},
+		{name: "invalid name", args: map[string]interface{}{"namespace": "Team_A"}, wantErr: "invalid namespace name"},
+		{name: "non-string label", args: map[string]interface{}{"namespace": "a", "labels": map[string]interface{}{"n": float64(1)}}, wantErr: "labels.n must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newNamespaceMock()
+			result := callNamespaceTool(t, handleCreateNamespace, mock, tt.args)
+			assert.True(t, result.IsError)
+			assert.Contains(t, getErrorText(t, result), tt.wantErr)
+			assert.Empty(t, mock.created)
+		})
+	}
+}
+
+func TestHandleDeleteNamespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "deletes", args: map[string]interface{}{"namespace": "team-a", "confirm": "team-a"}},
+		{name: "without confirmation", args: map[string]interface{}{"namespace": "team-a"}, wantErr: "confirm must repeat"},
+		{name: "wrong confirmation", args: map[string]interface{}{"namespace": "team-a", "confirm": "team-b"}, wantErr: "confirm must repeat"},
+		{name: "system namespace", args: map[string]interface{}{"namespace": "kube-system", "confirm": "kube-system"}, wantErr: "system namespace"},
+		{name: "restricted namespace", args: map[string]interface{}{"namespace": "vault", "confirm": "vault"}, wantErr: "restricted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newNamespaceMock()
+			result := callNamespaceTool(t, handleDeleteNamespace, mock, tt.args, server.WithRestrictedNamespaces([]string{"vault"}))
+			if tt.wantErr != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, getErrorText(t, result), tt.wantErr)
+				assert.Empty(t, mock.deleted)
+				return
+			}
+			require.False(t, result.IsError, getErrorText(t, result))
+			assert.Equal(t, []string{"namespaces/team-a"}, mock.deleted)
+
+			var out NamespaceResult
+			require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+			assert.Equal(t, "Terminating", out.Status)
+		})
+	}
+}
+
+func TestHandleDeleteNamespace_NonDestructiveMode(t *testing.T) {
+	mock := newNamespaceMock()
+	result := callNamespaceTool(t, handleDeleteNamespace, mock,
+		map[string]interface{}{"namespace": "team-a", "confirm": "team-a"},
+		server.WithNonDestructiveMode(true), server.WithDryRun(false))
+	assert.True(t, result.IsError)
+	assert.Empty(t, mock.deleted)
+}
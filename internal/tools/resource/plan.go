@@ -0,0 +1,661 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/plan"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Plan tool names.
+const (
+	addToPlanToolName   = "add_to_plan"
+	showPlanToolName    = "show_plan"
+	applyPlanToolName   = "apply_plan"
+	discardPlanToolName = "discard_plan"
+)
+
+// maxPlanNameLength limits the length of plan names.
+const maxPlanNameLength = 63
+
+// planRollbackTimeout bounds the rollback of a failed plan, which runs even
+// when the apply_plan call itself timed out.
+const planRollbackTimeout = time.Minute
+
+// Per-operation statuses reported by the plan tools.
+const (
+	planStatusPending    = "pending"
+	planStatusValid      = "valid"
+	planStatusInvalid    = "invalid"
+	planStatusApplied    = "applied"
+	planStatusFailed     = "failed"
+	planStatusRolledBack = "rolledBack"
+	planStatusNotRun     = "notRun"
+)
+
+// planOperations are the tools whose calls can be added to a plan.
+var planOperations = map[string]tools.ToolHandler{
+	"create": handleCreateResource,
+	"patch":  handlePatchResource,
+	"scale":  handleScaleResource,
+}
+
+// PlanResult is the result of the plan tools.
+type PlanResult struct {
+	Plan       string                `json:"plan"`
+	Operations []PlanOperationResult `json:"operations"`
+	ExpiresAt  *time.Time            `json:"expiresAt,omitempty"`
+	// RollbackErrors lists the objects a failed apply_plan could not put
+	// back, which need to be fixed by hand.
+	RollbackErrors []string `json:"rollbackErrors,omitempty"`
+	Message        string   `json:"message"`
+}
+
+// PlanOperationResult is one operation of a plan.
+type PlanOperationResult struct {
+	Index   int      `json:"index"`
+	Tool    string   `json:"tool"`
+	Objects []string `json:"objects"`
+	Status  string   `json:"status"`
+	Error   string   `json:"error,omitempty"`
+	// Diff is a unified diff of the objects between their current and
+	// planned state.
+	Diff string `json:"diff,omitempty"`
+}
+
+// registerPlanTools registers the plan tools when plans are enabled and at
+// least one operation that can be planned is allowed.
+func registerPlanTools(s *mcpserver.MCPServer, sc *server.ServerContext) {
+	store := sc.Plans()
+	if store == nil {
+		return
+	}
+	var allowed []string
+	for _, op := range []string{"create", "patch", "scale"} {
+		if tools.IsMutatingOperationAllowed(sc, op) {
+			allowed = append(allowed, op)
+		}
+	}
+	if len(allowed) == 0 {
+		return
+	}
+
+	planParam := mcp.WithString("plan",
+		mcp.Required(),
+		mcp.Description("Name of the plan"),
+		mcp.MaxLength(maxPlanNameLength),
+	)
+
+	s.AddTool(mcp.NewTool(addToPlanToolName,
+		mcp.WithDescription(fmt.Sprintf(`Add an operation to a plan: a named change-set that is reviewed with show_plan and run as a whole with apply_plan. The plan is created by its first operation.
+
+Nothing is changed when an operation is added. arguments are the arguments of the operation's tool, as if it were called directly (e.g. for scale: {"namespace": "prod", "resourceType": "deployment", "name": "web", "replicas": 3}), including cluster or kubeContext.
+
+A plan holds at most %d operations and is dropped %s after its last change.`, store.MaxOperations(), store.TTL())),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		planParam,
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("Tool of the operation"),
+			mcp.Enum(allowed...),
+		),
+		mcp.WithObject("arguments",
+			mcp.Required(),
+			mcp.Description("Arguments of the operation's tool"),
+		),
+	), tools.WrapWithAuditLogging(addToPlanToolName, handleAddToPlan, sc))
+
+	s.AddTool(mcp.NewTool(showPlanToolName,
+		mcp.WithDescription(`Validate every operation of a plan with a server-side dry-run and show the plan with a diff of each object between its current and planned state.
+
+Each operation is validated against the current state of the cluster, so an operation that depends on an earlier one in the plan, such as a create in a namespace the plan creates, may only be valid once the earlier one is applied.`),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		planParam,
+	), tools.WrapWithAuditLogging(showPlanToolName, handleShowPlan, sc))
+
+	s.AddTool(mcp.NewTool(applyPlanToolName,
+		mcp.WithDescription(`Apply a plan: validate every operation with a server-side dry-run, then run them in order. Nothing is changed unless every operation is valid.
+
+If an operation fails, the operations already run are rolled back in reverse order: objects they created are deleted and objects they changed are put back as they were. The plan is kept so that it can be applied again or discarded; a plan that was applied is removed.
+
+Applying a plan counts as confirming its operations, such as a scale to 0 replicas.`),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		planParam,
+	), tools.WrapWithAuditLogging(applyPlanToolName, handleApplyPlan, sc))
+
+	s.AddTool(mcp.NewTool(discardPlanToolName,
+		mcp.WithDescription("Discard a plan without applying it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		planParam,
+	), tools.WrapWithAuditLogging(discardPlanToolName, handleDiscardPlan, sc))
+}
+
+// handleAddToPlan adds an operation to the caller's plan.
+func handleAddToPlan(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	store, name, toolErr := planRequest(request, sc)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	operation, err := request.RequireString("operation")
+	if err != nil {
+		return toolerrors.Required("operation").Result(), nil
+	}
+	if _, ok := planOperations[operation]; !ok {
+		return toolerrors.InvalidArgumentf("operation must be one of: create, patch, scale; got %q", operation).Result(), nil
+	}
+	if result := checkMutatingOperation(sc, operation); result != nil {
+		return result, nil
+	}
+	args, ok := request.GetArguments()["arguments"].(map[string]interface{})
+	if !ok {
+		return toolerrors.Required("arguments").Result(), nil
+	}
+	if _, ok := args[tools.ParamDryRun]; ok {
+		return toolerrors.InvalidArgumentf("%s is not accepted in plan operations; use %s to preview the plan", tools.ParamDryRun, showPlanToolName).Result(), nil
+	}
+	args, err = copyPlanArguments(args)
+	if err != nil {
+		return toolerrors.InvalidArgumentf("invalid arguments: %v", err).Result(), nil
+	}
+	op := plan.Operation{Tool: operation, Arguments: args}
+	if _, err := planTargets(op); err != nil {
+		return toolerrors.InvalidArgument(err.Error()).Result(), nil
+	}
+
+	p, err := store.Add(tools.CallerIdentity(ctx), name, op)
+	switch {
+	case errors.Is(err, plan.ErrTooManyOperations):
+		return toolerrors.InvalidArgumentf("plan %s already has the maximum of %d operations", name, store.MaxOperations()).Result(), nil
+	case errors.Is(err, plan.ErrTooManyPlans):
+		return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+	case err != nil:
+		return toolerrors.New(toolerrors.CodeInternal, err.Error()).Result(), nil
+	}
+
+	result := PlanResult{Plan: name, ExpiresAt: &p.ExpiresAt}
+	for i, op := range p.Operations {
+		targets, _ := planTargets(op)
+		result.Operations = append(result.Operations, PlanOperationResult{
+			Index:   i + 1,
+			Tool:    op.Tool,
+			Objects: planObjects(targets),
+			Status:  planStatusPending,
+		})
+	}
+	result.Message = fmt.Sprintf("Added operation %d to plan %s. Nothing was changed; call %s to review the plan and %s to apply it.",
+		len(p.Operations), name, showPlanToolName, applyPlanToolName)
+	return planResult(result)
+}
+
+// handleShowPlan validates the caller's plan and shows its diffs.
+func handleShowPlan(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	store, name, toolErr := planRequest(request, sc)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	p, err := store.Get(tools.CallerIdentity(ctx), name)
+	if err != nil {
+		return planNotFound(name, err).Result(), nil
+	}
+
+	result, invalid := previewPlan(ctx, sc, p)
+	result.ExpiresAt = &p.ExpiresAt
+	if invalid > 0 {
+		result.Message = fmt.Sprintf("%d of %d operation(s) are invalid; apply_plan would change nothing", invalid, len(p.Operations))
+	} else {
+		result.Message = fmt.Sprintf("All %d operation(s) are valid. Nothing was changed; call %s to apply the plan.", len(p.Operations), applyPlanToolName)
+	}
+	return planResult(result)
+}
+
+// handleApplyPlan runs the operations of the caller's plan in order and
+// rolls back those already run when one fails.
+func handleApplyPlan(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	store, name, toolErr := planRequest(request, sc)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	// Taking the plan keeps concurrent calls from applying it twice.
+	p, err := store.Take(tools.CallerIdentity(ctx), name)
+	if err != nil {
+		return planNotFound(name, err).Result(), nil
+	}
+
+	result, invalid := previewPlan(ctx, sc, p)
+	if invalid > 0 || tools.IsDryRun(ctx, sc) {
+		store.Put(p)
+		result.ExpiresAt = &p.ExpiresAt
+		if invalid > 0 {
+			result.Message = fmt.Sprintf("%d of %d operation(s) are invalid; nothing was applied", invalid, len(p.Operations))
+		} else {
+			result.Message = "Dry run: the plan is valid; nothing was applied"
+		}
+		return planResult(result)
+	}
+	for i := range result.Operations {
+		result.Operations[i].Status = planStatusNotRun
+	}
+
+	// Operations run as confirmed: applying the plan is the confirmation.
+	runCtx := tools.ContextWithConfirmed(ctx)
+	var snapshots [][]*journal.Entry
+	for i, op := range p.Operations {
+		out := &result.Operations[i]
+		entries, err := snapshotPlanOperation(ctx, sc, op)
+		snapshots = append(snapshots, entries)
+		if err == nil {
+			_, err = callPlanOperation(runCtx, sc, op, false)
+		}
+		if err != nil {
+			out.Status = planStatusFailed
+			out.Error = err.Error()
+			result.RollbackErrors = rollbackPlan(ctx, sc, snapshots, result.Operations)
+			store.Put(p)
+			result.ExpiresAt = &p.ExpiresAt
+			result.Message = fmt.Sprintf("Operation %d of %d failed; the operations before it were rolled back", i+1, len(p.Operations))
+			if len(result.RollbackErrors) > 0 {
+				result.Message = fmt.Sprintf("Operation %d of %d failed and the rollback failed for %d object(s); see rollbackErrors", i+1, len(p.Operations), len(result.RollbackErrors))
+			}
+			return planResult(result)
+		}
+		out.Status = planStatusApplied
+	}
+
+	result.Message = fmt.Sprintf("Applied all %d operation(s) of plan %s", len(p.Operations), name)
+	return planResult(result)
+}
+
+// handleDiscardPlan removes the caller's plan.
+func handleDiscardPlan(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	store, name, toolErr := planRequest(request, sc)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	if err := store.Discard(tools.CallerIdentity(ctx), name); err != nil {
+		return planNotFound(name, err).Result(), nil
+	}
+	return planResult(PlanResult{Plan: name, Operations: []PlanOperationResult{}, Message: fmt.Sprintf("Discarded plan %s", name)})
+}
+
+// planRequest returns the plan store and the plan name of a request.
+func planRequest(request mcp.CallToolRequest, sc *server.ServerContext) (*plan.Store, string, *toolerrors.Error) {
+	store := sc.Plans()
+	if store == nil {
+		return nil, "", toolerrors.New(toolerrors.CodeNotEnabled, "plans are not enabled on this server")
+	}
+	name, err := request.RequireString("plan")
+	if err != nil || name == "" {
+		return nil, "", toolerrors.Required("plan")
+	}
+	if len(name) > maxPlanNameLength {
+		return nil, "", toolerrors.InvalidArgumentf("plan must be at most %d characters", maxPlanNameLength)
+	}
+	return store, name, nil
+}
+
+func planNotFound(name string, err error) *toolerrors.Error {
+	if errors.Is(err, plan.ErrNotFound) {
+		return toolerrors.Newf(toolerrors.CodeNotFound, "plan %s is unknown, expired or already applied", name)
+	}
+	return toolerrors.New(toolerrors.CodeInternal, err.Error())
+}
+
+// previewPlan validates every operation of p with a dry-run and returns the
+// plan with its diffs and the number of invalid operations.
+func previewPlan(ctx context.Context, sc *server.ServerContext, p *plan.Plan) (PlanResult, int) {
+	result := PlanResult{Plan: p.Name, Operations: make([]PlanOperationResult, 0, len(p.Operations))}
+	invalid := 0
+	for i, op := range p.Operations {
+		out := previewPlanOperation(ctx, sc, op)
+		out.Index = i + 1
+		if out.Status == planStatusInvalid {
+			invalid++
+		}
+		result.Operations = append(result.Operations, out)
+	}
+	return result, invalid
+}
+
+// previewPlanOperation runs op as a dry-run and diffs the objects it
+// changes against their current state.
+func previewPlanOperation(ctx context.Context, sc *server.ServerContext, op plan.Operation) PlanOperationResult {
+	out := PlanOperationResult{Tool: op.Tool, Objects: []string{}}
+	targets, err := planTargets(op)
+	if err != nil {
+		out.Status, out.Error = planStatusInvalid, err.Error()
+		return out
+	}
+	out.Objects = planObjects(targets)
+	// apply_plan writes the objects itself, so they are authorized for it as
+	// well as for the operation's tool.
+	for i, target := range targets {
+		if msg := tools.TargetWriteDenied(ctx, sc, applyPlanToolName, target); msg != "" {
+			out.Status, out.Error = planStatusInvalid, fmt.Sprintf("%s: %s", out.Objects[i], msg)
+			return out
+		}
+	}
+
+	// The current state is read first; the dry-run does not change it.
+	befores := make([]map[string]interface{}, len(targets))
+	if client, toolErr := tools.GetClusterClient(ctx, sc, targets[0].Cluster); toolErr == nil {
+		for i, target := range targets {
+			if entry, err := tools.SnapshotObject(ctx, client.K8s(), op.Tool, target); err == nil {
+				befores[i] = entry.Before
+			}
+		}
+	}
+
+	dryRunResult, err := callPlanOperation(ctx, sc, op, true)
+	if err != nil {
+		out.Status, out.Error = planStatusInvalid, err.Error()
+		return out
+	}
+	out.Status = planStatusValid
+
+	afters := plannedObjects(op, dryRunResult, befores)
+	processor := getOutputProcessorForFormat(ctx, sc, targets[0].Cluster, "")
+	var diffs []string
+	for i, target := range targets {
+		if isSecretTarget(target) {
+			diffs = append(diffs, out.Objects[i]+": Secret contents are not shown")
+			continue
+		}
+		var after map[string]interface{}
+		if i < len(afters) {
+			after = afters[i]
+		}
+		diffs = append(diffs, objectDiff(out.Objects[i], planDiffObject(processor.ProcessSingle, befores[i]), planDiffObject(processor.ProcessSingle, after)))
+	}
+	out.Diff = strings.Join(diffs, "\n")
+	return out
+}
+
+// plannedObjects returns the planned state of the objects of op from the
+// result of its dry-run, in the order of planTargets.
+func plannedObjects(op plan.Operation, dryRunResult *mcp.CallToolResult, befores []map[string]interface{}) []map[string]interface{} {
+	switch op.Tool {
+	case "scale":
+		if befores[0] == nil {
+			return nil
+		}
+		after := deepCopyObject(befores[0])
+		replicas, _ := op.Arguments["replicas"].(float64)
+		_ = unstructured.SetNestedField(after, int64(replicas), "spec", "replicas")
+		return []map[string]interface{}{after}
+	}
+
+	text := resultText(dryRunResult)
+	switch op.Tool {
+	case "create":
+		var objects []map[string]interface{}
+		if json.Unmarshal([]byte(text), &objects) == nil {
+			return objects
+		}
+		var object map[string]interface{}
+		if json.Unmarshal([]byte(text), &object) == nil {
+			return []map[string]interface{}{object}
+		}
+	case "patch":
+		var response struct {
+			Resource map[string]interface{} `json:"resource"`
+		}
+		if json.Unmarshal([]byte(text), &response) == nil && response.Resource != nil {
+			return []map[string]interface{}{response.Resource}
+		}
+	}
+	return nil
+}
+
+// planDiffObject prepares obj for a diff: processed like tool output and
+// without server-managed fields, which change on every write.
+func planDiffObject(process func(map[string]interface{}) map[string]interface{}, obj map[string]interface{}) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+	obj = process(deepCopyObject(obj))
+	tools.StripServerManagedFields(obj)
+	return obj
+}
+
+// objectDiff returns a unified diff of an object between its current and
+// planned state, either of which may be nil.
+func objectDiff(object string, before, after map[string]interface{}) string {
+	a, b := objectYAML(before), objectYAML(after)
+	if a == b {
+		return object + ": no changes"
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: object + " (current)",
+		ToFile:   object + " (planned)",
+		Context:  3,
+	})
+	if err != nil {
+		return object + ": diff unavailable"
+	}
+	return diff
+}
+
+func objectYAML(obj map[string]interface{}) string {
+	if obj == nil {
+		return ""
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// snapshotPlanOperation reads the state of the objects of op before it
+// runs, so that it can be rolled back. The state stays in memory for the
+// duration of the apply_plan call.
+func snapshotPlanOperation(ctx context.Context, sc *server.ServerContext, op plan.Operation) ([]*journal.Entry, error) {
+	targets, err := planTargets(op)
+	if err != nil {
+		return nil, err
+	}
+	client, toolErr := tools.GetClusterClient(ctx, sc, targets[0].Cluster)
+	if toolErr != nil {
+		return nil, toolErr
+	}
+	entries := make([]*journal.Entry, 0, len(targets))
+	for _, target := range targets {
+		entry, err := tools.SnapshotObject(ctx, client.K8s(), op.Tool, target)
+		if err != nil {
+			return entries, fmt.Errorf("cannot read %s to be able to roll it back: %w", entry.Object(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// rollbackPlan reverts the operations of snapshots, newest first, and marks
+// the applied ones in results as rolled back. The failed operation, the
+// last one, is reverted too, as it may have changed some of its objects.
+// Returns the objects that could not be reverted.
+func rollbackPlan(ctx context.Context, sc *server.ServerContext, snapshots [][]*journal.Entry, results []PlanOperationResult) []string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), planRollbackTimeout)
+	defer cancel()
+
+	var failed []string
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		ok := true
+		for j := len(snapshots[i]) - 1; j >= 0; j-- {
+			entry := snapshots[i][j]
+			if _, toolErr := tools.RevertChange(ctx, sc, applyPlanToolName, *entry); toolErr != nil {
+				failed = append(failed, fmt.Sprintf("%s: %s", entry.Object(), toolErr.Message))
+				ok = false
+			}
+		}
+		if ok && results[i].Status == planStatusApplied {
+			results[i].Status = planStatusRolledBack
+		}
+	}
+	return failed
+}
+
+// callPlanOperation calls the tool of op through the same checks and audit
+// logging as a direct call. A failed call is returned as an error.
+func callPlanOperation(ctx context.Context, sc *server.ServerContext, op plan.Operation, dryRun bool) (*mcp.CallToolResult, error) {
+	args, err := copyPlanArguments(op.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		args[tools.ParamDryRun] = true
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = op.Tool
+	request.Params.Arguments = args
+
+	result, err := tools.WrapWithAuditLogging(op.Tool, planOperations[op.Tool], sc)(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("no result")
+	}
+	if result.IsError {
+		if e := toolerrors.FromResult(result); e != nil {
+			return nil, e
+		}
+		return nil, errors.New(resultText(result))
+	}
+	return result, nil
+}
+
+// planTargets returns the objects op changes.
+func planTargets(op plan.Operation) ([]tools.UndoTarget, error) {
+	args := op.Arguments
+	cluster := tools.ExtractClusterParam(args)
+	kubeContext, _ := args["kubeContext"].(string)
+	namespace, _ := args["namespace"].(string)
+
+	if op.Tool == "create" {
+		if namespace == "" {
+			return nil, errors.New("namespace is required")
+		}
+		manifest, ok := args["manifest"]
+		if !ok || manifest == nil {
+			return nil, errors.New("manifest is required")
+		}
+		objects, err := parseManifestArg(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(objects) == 0 {
+			return nil, errors.New("manifest contains no objects")
+		}
+		targets := make([]tools.UndoTarget, 0, len(objects))
+		for _, obj := range objects {
+			targets = append(targets, undoTargetOf(cluster, kubeContext, namespace, obj))
+		}
+		return targets, nil
+	}
+
+	if namespace == "" {
+		if op.Tool == "scale" {
+			return nil, errors.New("namespace is required")
+		}
+		namespace = k8s.DefaultNamespace
+	}
+	target := tools.UndoTarget{Cluster: cluster, KubeContext: kubeContext, Namespace: namespace}
+	target.ResourceType, _ = args["resourceType"].(string)
+	target.APIGroup, _ = args["apiGroup"].(string)
+	target.Name, _ = args["name"].(string)
+	switch {
+	case target.ResourceType == "":
+		return nil, errors.New("resourceType is required")
+	case target.Name == "":
+		return nil, errors.New("name is required")
+	}
+	if _, ok := args["replicas"].(float64); op.Tool == "scale" && !ok {
+		return nil, errors.New("replicas is required")
+	}
+	return []tools.UndoTarget{target}, nil
+}
+
+// isSecretTarget reports whether target is a core Secret, named by
+// resource type or, for manifest objects, by kind.
+func isSecretTarget(target tools.UndoTarget) bool {
+	return isSecretResourceType(target.ResourceType, target.APIGroup) ||
+		(target.ResourceType == "Secret" && target.APIGroup == "core/v1")
+}
+
+// planObjects describes targets for plan results.
+func planObjects(targets []tools.UndoTarget) []string {
+	objects := make([]string, 0, len(targets))
+	for _, target := range targets {
+		objects = append(objects, journal.Entry{ResourceType: target.ResourceType, Namespace: target.Namespace, Name: target.Name}.Object())
+	}
+	return objects
+}
+
+// copyPlanArguments deep-copies tool arguments, so that a call cannot
+// change the operation kept in the plan.
+func copyPlanArguments(args map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func deepCopyObject(obj map[string]interface{}) map[string]interface{} {
+	return (&unstructured.Unstructured{Object: obj}).DeepCopy().Object
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+func planResult(result PlanResult) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,266 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/plan"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// planMock keeps objects by name and honours server-side dry-runs. Scaling
+// the object named in failScale fails, but passes its dry-run.
+type planMock struct {
+	*testdata.MockK8sClient
+
+	objects   map[string]*unstructured.Unstructured
+	failScale string
+}
+
+func (m *planMock) Get(_ context.Context, _, _, resourceType, _, name string) (*k8s.GetResponse, error) {
+	obj, ok := m.objects[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	return &k8s.GetResponse{Resource: obj.DeepCopy()}, nil
+}
+
+func (m *planMock) Create(ctx context.Context, _, _ string, obj runtime.Object) (runtime.Object, error) {
+	u := obj.(*unstructured.Unstructured).DeepCopy()
+	if _, ok := m.objects[u.GetName()]; ok {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: u.GetKind()}, u.GetName())
+	}
+	if !k8s.DryRunFromContext(ctx) {
+		m.objects[u.GetName()] = u
+	}
+	return u, nil
+}
+
+func (m *planMock) Apply(_ context.Context, _, _ string, obj runtime.Object) (runtime.Object, error) {
+	u := obj.(*unstructured.Unstructured).DeepCopy()
+	m.objects[u.GetName()] = u
+	return u, nil
+}
+
+func (m *planMock) Delete(_ context.Context, _, _, resourceType, _, name string) (*k8s.DeleteResponse, error) {
+	if _, ok := m.objects[name]; !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	delete(m.objects, name)
+	return &k8s.DeleteResponse{}, nil
+}
+
+// Patch replaces the data of a ConfigMap with the patch's.
+func (m *planMock) Patch(ctx context.Context, _, _, resourceType, _, name string, _ types.PatchType, data []byte) (*k8s.PatchResponse, error) {
+	obj, ok := m.objects[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	var patch map[string]interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	patched := obj.DeepCopy()
+	patched.Object["data"] = patch["data"]
+	if !k8s.DryRunFromContext(ctx) {
+		m.objects[name] = patched
+	}
+	return &k8s.PatchResponse{Resource: patched}, nil
+}
+
+func (m *planMock) Scale(ctx context.Context, _, _, _, _, name string, replicas int32) (*k8s.ScaleResponse, error) {
+	if name == m.failScale && !k8s.DryRunFromContext(ctx) {
+		return nil, errors.New("admission webhook denied the request")
+	}
+	obj, ok := m.objects[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, name)
+	}
+	if !k8s.DryRunFromContext(ctx) {
+		_ = unstructured.SetNestedField(obj.Object, int64(replicas), "spec", "replicas")
+	}
+	return &k8s.ScaleResponse{Replicas: replicas}, nil
+}
+
+func planConfigMap(name, value string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "prod", "resourceVersion": "7"},
+		"data":       map[string]interface{}{"value": value},
+	}}
+}
+
+func newPlanMock() *planMock {
+	return &planMock{objects: map[string]*unstructured.Unstructured{
+		"app": planConfigMap("app", "1"),
+		"web": {Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "prod"},
+			"spec":       map[string]interface{}{"replicas": int64(1)},
+		}},
+	}}
+}
+
+func callPlanTool(t *testing.T, sc *server.ServerContext, handler func(context.Context, mcp.CallToolRequest, *server.ServerContext) (*mcp.CallToolResult, error), args map[string]interface{}) PlanResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var out PlanResult
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &out))
+	return out
+}
+
+func buildPlan(t *testing.T, sc *server.ServerContext, scaleName string) {
+	t.Helper()
+	for _, op := range []map[string]interface{}{
+		{"operation": "create", "arguments": map[string]interface{}{
+			"namespace": "prod",
+			"manifest":  map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "new"}},
+		}},
+		{"operation": "patch", "arguments": map[string]interface{}{
+			"namespace": "prod", "resourceType": "configmaps", "name": "app",
+			"patchType": "merge", "patch": map[string]interface{}{"data": map[string]interface{}{"value": "2"}},
+		}},
+		{"operation": "scale", "arguments": map[string]interface{}{
+			"namespace": "prod", "resourceType": "deployments", "name": scaleName, "replicas": float64(3),
+		}},
+	} {
+		op["plan"] = "rollout"
+		callPlanTool(t, sc, handleAddToPlan, op)
+	}
+}
+
+func newPlanServerContext(t *testing.T, mock *planMock) *server.ServerContext {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithNonDestructiveMode(false),
+		server.WithPlans(plan.NewStore(time.Minute)),
+	)
+	require.NoError(t, err)
+	return sc
+}
+
+func TestPlan_ShowAndApply(t *testing.T) {
+	mock := newPlanMock()
+	sc := newPlanServerContext(t, mock)
+	buildPlan(t, sc, "web")
+
+	shown := callPlanTool(t, sc, handleShowPlan, map[string]interface{}{"plan": "rollout"})
+	require.Len(t, shown.Operations, 3)
+	for _, op := range shown.Operations {
+		assert.Equal(t, planStatusValid, op.Status, op.Error)
+	}
+	assert.Equal(t, []string{"ConfigMap/new in namespace prod"}, shown.Operations[0].Objects)
+	assert.Contains(t, shown.Operations[0].Diff, "+kind: ConfigMap")
+	assert.Contains(t, shown.Operations[1].Diff, "-  value: \"1\"\n+  value: \"2\"")
+	assert.Contains(t, shown.Operations[2].Diff, "-  replicas: 1\n+  replicas: 3")
+	assert.NotContains(t, shown.Operations[1].Diff, "resourceVersion")
+	assert.NotContains(t, mock.objects, "new", "showing a plan changes nothing")
+
+	applied := callPlanTool(t, sc, handleApplyPlan, map[string]interface{}{"plan": "rollout"})
+	for _, op := range applied.Operations {
+		assert.Equal(t, planStatusApplied, op.Status, op.Error)
+	}
+	assert.Contains(t, mock.objects, "new")
+	assert.Equal(t, map[string]interface{}{"value": "2"}, mock.objects["app"].Object["data"])
+	replicas, _, _ := unstructured.NestedInt64(mock.objects["web"].Object, "spec", "replicas")
+	assert.Equal(t, int64(3), replicas)
+
+	_, err := sc.Plans().Get("anonymous", "rollout")
+	assert.ErrorIs(t, err, plan.ErrNotFound, "an applied plan is removed")
+}
+
+func TestPlan_ApplyRollsBackOnFailure(t *testing.T) {
+	mock := newPlanMock()
+	mock.failScale = "web"
+	sc := newPlanServerContext(t, mock)
+	buildPlan(t, sc, "web")
+
+	applied := callPlanTool(t, sc, handleApplyPlan, map[string]interface{}{"plan": "rollout"})
+	require.Len(t, applied.Operations, 3)
+	assert.Equal(t, planStatusRolledBack, applied.Operations[0].Status)
+	assert.Equal(t, planStatusRolledBack, applied.Operations[1].Status)
+	assert.Equal(t, planStatusFailed, applied.Operations[2].Status)
+	assert.Contains(t, applied.Operations[2].Error, "admission webhook denied the request")
+	assert.Empty(t, applied.RollbackErrors)
+
+	assert.NotContains(t, mock.objects, "new", "created objects are deleted")
+	assert.Equal(t, map[string]interface{}{"value": "1"}, mock.objects["app"].Object["data"], "changed objects are put back")
+
+	_, err := sc.Plans().Get("anonymous", "rollout")
+	assert.NoError(t, err, "a failed plan is kept")
+}
+
+func TestPlan_InvalidPlanChangesNothing(t *testing.T) {
+	mock := newPlanMock()
+	sc := newPlanServerContext(t, mock)
+	buildPlan(t, sc, "missing")
+
+	applied := callPlanTool(t, sc, handleApplyPlan, map[string]interface{}{"plan": "rollout"})
+	assert.Equal(t, planStatusInvalid, applied.Operations[2].Status)
+	assert.Contains(t, applied.Message, "nothing was applied")
+	assert.NotContains(t, mock.objects, "new")
+	assert.Equal(t, map[string]interface{}{"value": "1"}, mock.objects["app"].Object["data"])
+
+	callPlanTool(t, sc, handleDiscardPlan, map[string]interface{}{"plan": "rollout"})
+	assert.Zero(t, sc.Plans().Len())
+}
+
+func TestAddToPlan_Validation(t *testing.T) {
+	sc := newPlanServerContext(t, newPlanMock())
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{
+			name: "unsupported operation",
+			args: map[string]interface{}{"plan": "p", "operation": "delete", "arguments": map[string]interface{}{}},
+			want: "operation must be one of",
+		},
+		{
+			name: "missing name",
+			args: map[string]interface{}{"plan": "p", "operation": "patch", "arguments": map[string]interface{}{"resourceType": "configmaps"}},
+			want: "name is required",
+		},
+		{
+			name: "dryRun",
+			args: map[string]interface{}{"plan": "p", "operation": "scale", "arguments": map[string]interface{}{"dryRun": true}},
+			want: "dryRun is not accepted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.args
+			result, err := handleAddToPlan(context.Background(), request, sc)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Contains(t, getErrorText(t, result), tt.want)
+		})
+	}
+	assert.Zero(t, sc.Plans().Len())
+}
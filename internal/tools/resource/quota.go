@@ -0,0 +1,871 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// MaxQuotaPreflightObjects limits how many manifest objects one
+// resource_quotas call may check.
+const MaxQuotaPreflightObjects = 50
+
+// QuotaResult is the output of the resource_quotas tool.
+type QuotaResult struct {
+	Namespace   string             `json:"namespace"`
+	Quotas      []QuotaStatus      `json:"quotas"`
+	LimitRanges []LimitRangeStatus `json:"limitRanges"`
+
+	// Preflight is set when a manifest was given.
+	Preflight *QuotaPreflight `json:"preflight,omitempty"`
+}
+
+// QuotaStatus is a ResourceQuota and its usage.
+type QuotaStatus struct {
+	Name string `json:"name"`
+
+	// Scopes limit the quota to matching pods, such as "BestEffort" or
+	// "PriorityClass In (high)".
+	Scopes    []string        `json:"scopes,omitempty"`
+	Resources []QuotaResource `json:"resources"`
+}
+
+// QuotaResource is the usage of one resource tracked by a quota.
+type QuotaResource struct {
+	Resource    string  `json:"resource"`
+	Hard        string  `json:"hard"`
+	Used        string  `json:"used"`
+	Remaining   string  `json:"remaining"`
+	UsedPercent float64 `json:"usedPercent"`
+	Exhausted   bool    `json:"exhausted,omitempty"`
+}
+
+// LimitRangeStatus is a LimitRange with its constraints and defaults per
+// type (Container, Pod or PersistentVolumeClaim).
+type LimitRangeStatus struct {
+	Name   string                  `json:"name"`
+	Limits []corev1.LimitRangeItem `json:"limits"`
+}
+
+// QuotaPreflight reports whether a manifest fits the namespace's remaining
+// quota and its limit ranges.
+type QuotaPreflight struct {
+	// Fits is true when no quota would be exceeded and no limit range or
+	// quota requirement violated.
+	Fits    bool              `json:"fits"`
+	Objects []PreflightObject `json:"objects"`
+
+	// Quotas lists, per quota, the resources the manifest would use.
+	Quotas []PreflightQuota `json:"quotas,omitempty"`
+
+	// Violations explain why the API server would reject the manifest.
+	Violations []string `json:"violations,omitempty"`
+	Notes      []string `json:"notes,omitempty"`
+}
+
+// PreflightObject is one manifest object.
+type PreflightObject struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Pods is the number of pods the object runs at the same time.
+	Pods int64 `json:"pods,omitempty"`
+
+	// Skipped explains why the object was not checked.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// PreflightQuota is what a manifest would use of one quota resource.
+type PreflightQuota struct {
+	Quota     string `json:"quota"`
+	Resource  string `json:"resource"`
+	Requested string `json:"requested"`
+	Remaining string `json:"remaining"`
+	Fits      bool   `json:"fits"`
+}
+
+// handleResourceQuotas reports the quotas and limit ranges of a namespace
+// and, given a manifest, whether it fits within them.
+func handleResourceQuotas(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+	kubeContext := request.GetString("kubeContext", "")
+	namespace := request.GetString("namespace", "")
+	if namespace == "" {
+		return toolerrors.Required("namespace").Result(), nil
+	}
+
+	var objects []*unstructured.Unstructured
+	if manifestData, ok := args["manifest"]; ok && manifestData != nil {
+		var err error
+		objects, err = parseManifestArg(manifestData)
+		if err != nil {
+			return toolerrors.InvalidArgumentf("Failed to parse manifest: %v", err).Result(), nil
+		}
+		if len(objects) > MaxQuotaPreflightObjects {
+			return toolerrors.InvalidArgumentf("manifest contains %d objects; at most %d can be checked at once", len(objects), MaxQuotaPreflightObjects).Result(), nil
+		}
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	quotas, err := listNamespaced[corev1.ResourceQuota](ctx, client.K8s(), kubeContext, namespace, "resourcequotas")
+	if err != nil {
+		return tools.K8sError("Failed to list resource quotas", err, client.User()).Result(), nil
+	}
+	limitRanges, err := listNamespaced[corev1.LimitRange](ctx, client.K8s(), kubeContext, namespace, "limitranges")
+	if err != nil {
+		return tools.K8sError("Failed to list limit ranges", err, client.User()).Result(), nil
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Name < quotas[j].Name })
+	sort.Slice(limitRanges, func(i, j int) bool { return limitRanges[i].Name < limitRanges[j].Name })
+
+	result := &QuotaResult{
+		Namespace:   namespace,
+		Quotas:      make([]QuotaStatus, 0, len(quotas)),
+		LimitRanges: make([]LimitRangeStatus, 0, len(limitRanges)),
+	}
+	for i := range quotas {
+		result.Quotas = append(result.Quotas, quotaStatus(&quotas[i]))
+	}
+	for _, lr := range limitRanges {
+		result.LimitRanges = append(result.LimitRanges, LimitRangeStatus{Name: lr.Name, Limits: lr.Spec.Limits})
+	}
+	if objects != nil {
+		clusterScoped := func(obj *unstructured.Unstructured) bool {
+			scoped, _ := tools.ObjectClusterScoped(ctx, sc, args, obj)
+			return scoped
+		}
+		result.Preflight = preflightQuota(namespace, objects, clusterScoped, quotas, limitRanges)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// listNamespaced lists every object of a core resource type in a namespace.
+func listNamespaced[T any](ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType string) ([]T, error) {
+	return listObjects[T](ctx, client, kubeContext, namespace, resourceType, k8s.ListOptions{})
+}
+
+// listObjects pages through every object of a core resource type matching
+// opts.
+func listObjects[T any](ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType string, opts k8s.ListOptions) ([]T, error) {
+	return listGroupObjects[T](ctx, client, kubeContext, namespace, resourceType, "", opts)
+}
+
+// listGroupObjects pages through every object of a resource type of an API
+// group matching opts.
+func listGroupObjects[T any](ctx context.Context, client k8s.Client, kubeContext, namespace, resourceType, apiGroup string, opts k8s.ListOptions) ([]T, error) {
+	var items []T
+	for {
+		resp, err := client.List(ctx, kubeContext, namespace, resourceType, apiGroup, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range resp.Items {
+			u, err := toUnstructuredObject(obj)
+			if err != nil {
+				return nil, err
+			}
+			var item T
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &item); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if resp.Continue == "" {
+			return items, nil
+		}
+		opts.Continue = resp.Continue
+	}
+}
+
+// quotaHard returns the enforced limits of a quota. The status lags behind
+// the spec until the quota controller has seen the quota.
+func quotaHard(quota *corev1.ResourceQuota) corev1.ResourceList {
+	if len(quota.Status.Hard) > 0 {
+		return quota.Status.Hard
+	}
+	return quota.Spec.Hard
+}
+
+// quotaRemaining returns what is left of a quota resource, never below zero.
+func quotaRemaining(quota *corev1.ResourceQuota, name corev1.ResourceName) resource.Quantity {
+	remaining := quotaHard(quota)[name].DeepCopy()
+	remaining.Sub(quota.Status.Used[name])
+	if remaining.Sign() < 0 {
+		return resource.Quantity{}
+	}
+	return remaining
+}
+
+func quotaStatus(quota *corev1.ResourceQuota) QuotaStatus {
+	hard := quotaHard(quota)
+	out := QuotaStatus{
+		Name:      quota.Name,
+		Scopes:    quotaScopeNames(quota),
+		Resources: make([]QuotaResource, 0, len(hard)),
+	}
+	for _, name := range sortedResourceNames(hard) {
+		limit := hard[name]
+		used := quota.Status.Used[name]
+		remaining := quotaRemaining(quota, name)
+		out.Resources = append(out.Resources, QuotaResource{
+			Resource:    string(name),
+			Hard:        limit.String(),
+			Used:        used.String(),
+			Remaining:   remaining.String(),
+			UsedPercent: quantityPercent(used, limit),
+			Exhausted:   remaining.Sign() <= 0,
+		})
+	}
+	return out
+}
+
+// quotaScopes returns the scopes of a quota as selector requirements.
+// Plain scopes match with the Exists operator.
+func quotaScopes(quota *corev1.ResourceQuota) []corev1.ScopedResourceSelectorRequirement {
+	var scopes []corev1.ScopedResourceSelectorRequirement
+	for _, scope := range quota.Spec.Scopes {
+		scopes = append(scopes, corev1.ScopedResourceSelectorRequirement{ScopeName: scope, Operator: corev1.ScopeSelectorOpExists})
+	}
+	if quota.Spec.ScopeSelector != nil {
+		scopes = append(scopes, quota.Spec.ScopeSelector.MatchExpressions...)
+	}
+	return scopes
+}
+
+func quotaScopeNames(quota *corev1.ResourceQuota) []string {
+	var names []string
+	for _, scope := range quotaScopes(quota) {
+		switch scope.Operator {
+		case corev1.ScopeSelectorOpIn, corev1.ScopeSelectorOpNotIn:
+			names = append(names, fmt.Sprintf("%s %s (%s)", scope.ScopeName, scope.Operator, strings.Join(scope.Values, ", ")))
+		case corev1.ScopeSelectorOpDoesNotExist:
+			names = append(names, fmt.Sprintf("%s %s", scope.ScopeName, scope.Operator))
+		default:
+			names = append(names, string(scope.ScopeName))
+		}
+	}
+	return names
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// quantityPercent returns part as a percentage of total, rounded to one decimal.
+func quantityPercent(part, total resource.Quantity) float64 {
+	t := total.AsApproximateFloat64()
+	if t <= 0 {
+		return 0
+	}
+	return math.Round(part.AsApproximateFloat64()*1000/t) / 10
+}
+
+// quotaPreflight accumulates what a manifest would use of each quota.
+type quotaPreflight struct {
+	namespace     string
+	clusterScoped func(*unstructured.Unstructured) bool
+	quotas        []corev1.ResourceQuota
+	limitRanges   []corev1.LimitRange
+
+	// demand holds, per quota, the resources the manifest would use.
+	demand []corev1.ResourceList
+	out    *QuotaPreflight
+}
+
+// preflightQuota checks manifest objects against the namespace's quotas and
+// limit ranges the way the ResourceQuota and LimitRanger admission plugins
+// would on create. Objects clusterScoped reports count against no quota.
+func preflightQuota(namespace string, objects []*unstructured.Unstructured, clusterScoped func(*unstructured.Unstructured) bool, quotas []corev1.ResourceQuota, limitRanges []corev1.LimitRange) *QuotaPreflight {
+	p := &quotaPreflight{
+		namespace:     namespace,
+		clusterScoped: clusterScoped,
+		quotas:        quotas,
+		limitRanges:   limitRanges,
+		demand:        make([]corev1.ResourceList, len(quotas)),
+		out:           &QuotaPreflight{Objects: make([]PreflightObject, 0, len(objects))},
+	}
+	for i := range p.demand {
+		p.demand[i] = corev1.ResourceList{}
+	}
+
+	for _, obj := range objects {
+		p.out.Objects = append(p.out.Objects, p.checkObject(obj))
+	}
+	p.checkQuotas()
+
+	if len(quotas) == 0 && len(limitRanges) == 0 {
+		p.note("The namespace has no ResourceQuotas or LimitRanges, so neither limits the manifest.")
+	} else if len(p.out.Quotas) > 0 {
+		p.note("Usage includes existing objects. If the manifest updates existing workloads, only the difference to their current pods is needed.")
+	}
+	p.out.Fits = len(p.out.Violations) == 0
+	return p.out
+}
+
+func (p *quotaPreflight) violation(format string, args ...interface{}) {
+	p.out.Violations = append(p.out.Violations, fmt.Sprintf(format, args...))
+}
+
+func (p *quotaPreflight) note(format string, args ...interface{}) {
+	p.out.Notes = append(p.out.Notes, fmt.Sprintf(format, args...))
+}
+
+// checkObject adds the object to the demand on each quota it counts against
+// and checks it against the limit ranges.
+func (p *quotaPreflight) checkObject(obj *unstructured.Unstructured) PreflightObject {
+	out := PreflightObject{Kind: obj.GetKind(), Name: obj.GetName()}
+	if out.Name == "" {
+		out.Name = obj.GetGenerateName()
+	}
+	ref := out.Kind + "/" + out.Name
+
+	if p.clusterScoped(obj) {
+		out.Skipped = "cluster-scoped objects do not count against namespace quotas"
+		return out
+	}
+	if ns := obj.GetNamespace(); ns != "" && ns != p.namespace {
+		out.Skipped = fmt.Sprintf("object is in namespace %s", ns)
+		return out
+	}
+
+	// Every object counts against the object count quotas, such as
+	// count/deployments.apps, of quotas without scopes.
+	counts := corev1.ResourceList{objectCountResource(obj): resource.MustParse("1")}
+	switch out.Kind {
+	case "PersistentVolumeClaim":
+		if err := p.addClaim(ref, obj, counts); err != nil {
+			out.Skipped = fmt.Sprintf("invalid PersistentVolumeClaim: %v", err)
+			return out
+		}
+	case "Service":
+		addServiceCounts(obj, counts)
+	case "ConfigMap", "Secret", "ReplicationController", "ResourceQuota":
+		counts[corev1.ResourceName(strings.ToLower(out.Kind)+"s")] = resource.MustParse("1")
+	}
+	for i := range p.quotas {
+		if len(quotaScopes(&p.quotas[i])) == 0 {
+			addResources(p.demand[i], counts)
+		}
+	}
+
+	spec, pods, found, err := podTemplate(obj)
+	if err != nil {
+		out.Skipped = fmt.Sprintf("invalid pod template: %v", err)
+		return out
+	}
+	if !found {
+		return out
+	}
+	out.Pods = pods
+	p.addPods(ref, spec, pods)
+
+	switch out.Kind {
+	case "Deployment":
+		if strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "strategy", "type"); strategy != "Recreate" && len(p.quotas) > 0 {
+			p.note("%s: a rolling update briefly runs extra pods (maxSurge), which also need quota.", ref)
+		}
+	case "DaemonSet":
+		p.note("%s: counted as one pod, but it runs one pod on every matching node.", ref)
+	}
+	return out
+}
+
+// addPods applies the limit range defaults to a pod template, checks it and
+// adds its pods to the demand on each quota whose scopes match.
+func (p *quotaPreflight) addPods(ref string, spec *corev1.PodSpec, pods int64) {
+	applyLimitRangeDefaults(spec, p.limitRanges)
+	p.checkPodLimitRanges(ref, spec)
+
+	requests := podResources(spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }, true)
+	limits := podResources(spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits }, false)
+
+	usage := corev1.ResourceList{
+		corev1.ResourcePods:               *resource.NewQuantity(pods, resource.DecimalSI),
+		corev1.ResourceName("count/pods"): *resource.NewQuantity(pods, resource.DecimalSI),
+	}
+	for name, q := range requests {
+		total := scaleQuantity(q, pods)
+		usage[corev1.ResourceName("requests."+string(name))] = total
+		if name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage {
+			usage[name] = total
+		}
+	}
+	for name, q := range limits {
+		usage[corev1.ResourceName("limits."+string(name))] = scaleQuantity(q, pods)
+	}
+
+	for i := range p.quotas {
+		quota := &p.quotas[i]
+		if !podMatchesQuota(quota, spec) {
+			continue
+		}
+		p.checkRequiredResources(ref, quota, spec)
+		addResources(p.demand[i], usage)
+	}
+}
+
+// quotaRequirements maps quota resources to the container request or limit
+// the ResourceQuota admission plugin requires every container to set.
+var quotaRequirements = []struct {
+	quota    corev1.ResourceName
+	resource corev1.ResourceName
+	limit    bool
+}{
+	{corev1.ResourceRequestsCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceRequestsMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceLimitsCPU, corev1.ResourceCPU, true},
+	{corev1.ResourceLimitsMemory, corev1.ResourceMemory, true},
+}
+
+// checkRequiredResources reports containers that do not set a request or
+// limit the quota tracks; the API server rejects such pods.
+func (p *quotaPreflight) checkRequiredResources(ref string, quota *corev1.ResourceQuota, spec *corev1.PodSpec) {
+	hard := quotaHard(quota)
+	reported := map[string]bool{}
+	for _, req := range quotaRequirements {
+		if _, ok := hard[req.quota]; !ok {
+			continue
+		}
+		kind := "request"
+		if req.limit {
+			kind = "limit"
+		}
+		key := string(req.resource) + " " + kind
+		if reported[key] {
+			continue
+		}
+		for _, c := range allContainers(spec) {
+			list := c.Resources.Requests
+			if req.limit {
+				list = c.Resources.Limits
+			}
+			if _, ok := list[req.resource]; !ok {
+				p.violation("%s: ResourceQuota %q requires a %s, which container %q does not set and no LimitRange defaults", ref, quota.Name, key, c.Name)
+				reported[key] = true
+				break
+			}
+		}
+	}
+}
+
+// addClaim adds the storage of a PersistentVolumeClaim to the counts and
+// checks it against PersistentVolumeClaim limit ranges.
+func (p *quotaPreflight) addClaim(ref string, obj *unstructured.Unstructured, counts corev1.ResourceList) error {
+	var claim corev1.PersistentVolumeClaim
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &claim); err != nil {
+		return err
+	}
+	storage := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	counts[corev1.ResourcePersistentVolumeClaims] = resource.MustParse("1")
+	counts[corev1.ResourceRequestsStorage] = storage
+	if class := claim.Spec.StorageClassName; class != nil && *class != "" {
+		prefix := *class + ".storageclass.storage.k8s.io/"
+		counts[corev1.ResourceName(prefix+"persistentvolumeclaims")] = resource.MustParse("1")
+		counts[corev1.ResourceName(prefix+"requests.storage")] = storage
+	}
+
+	for _, lr := range p.limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypePersistentVolumeClaim {
+				continue
+			}
+			if lower, ok := item.Min[corev1.ResourceStorage]; ok && storage.Cmp(lower) < 0 {
+				p.violation("%s: storage request %s is below the minimum %s of LimitRange %q", ref, storage.String(), lower.String(), lr.Name)
+			}
+			if upper, ok := item.Max[corev1.ResourceStorage]; ok && storage.Cmp(upper) > 0 {
+				p.violation("%s: storage request %s is above the maximum %s of LimitRange %q", ref, storage.String(), upper.String(), lr.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// addServiceCounts adds the load balancers and node ports of a Service.
+func addServiceCounts(obj *unstructured.Unstructured, counts corev1.ResourceList) {
+	counts[corev1.ResourceServices] = resource.MustParse("1")
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	ports, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+	switch corev1.ServiceType(serviceType) {
+	case corev1.ServiceTypeLoadBalancer:
+		counts[corev1.ResourceServicesLoadBalancers] = resource.MustParse("1")
+		if allocate, found, _ := unstructured.NestedBool(obj.Object, "spec", "allocateLoadBalancerNodePorts"); !found || allocate {
+			counts[corev1.ResourceServicesNodePorts] = *resource.NewQuantity(int64(len(ports)), resource.DecimalSI)
+		}
+	case corev1.ServiceTypeNodePort:
+		counts[corev1.ResourceServicesNodePorts] = *resource.NewQuantity(int64(len(ports)), resource.DecimalSI)
+	}
+}
+
+// checkQuotas compares the demand on each quota with what remains of it.
+func (p *quotaPreflight) checkQuotas() {
+	for i := range p.quotas {
+		quota := &p.quotas[i]
+		if len(quota.Status.Used) == 0 && len(p.demand[i]) > 0 {
+			p.note("ResourceQuota %q reports no usage yet, so its usage is counted as zero.", quota.Name)
+		}
+		hard := quotaHard(quota)
+		for _, name := range sortedResourceNames(p.demand[i]) {
+			if _, ok := hard[name]; !ok {
+				continue
+			}
+			requested := p.demand[i][name]
+			remaining := quotaRemaining(quota, name)
+			fits := requested.Cmp(remaining) <= 0
+			p.out.Quotas = append(p.out.Quotas, PreflightQuota{
+				Quota:     quota.Name,
+				Resource:  string(name),
+				Requested: requested.String(),
+				Remaining: remaining.String(),
+				Fits:      fits,
+			})
+			if !fits {
+				p.violation("exceeds ResourceQuota %q: %s needs %s but only %s remains", quota.Name, name, requested.String(), remaining.String())
+			}
+		}
+	}
+}
+
+// podTemplate returns the pod spec of a Pod or workload object and how many
+// of its pods run at the same time.
+func podTemplate(obj *unstructured.Unstructured) (*corev1.PodSpec, int64, bool, error) {
+	var path []string
+	pods := int64(1)
+	switch obj.GetKind() {
+	case "Pod":
+		path = []string{"spec"}
+	case "Deployment", "ReplicaSet", "StatefulSet", "ReplicationController":
+		path = []string{"spec", "template", "spec"}
+		if replicas, ok := nestedInt(obj.Object, "spec", "replicas"); ok {
+			pods = replicas
+		}
+	case "DaemonSet":
+		path = []string{"spec", "template", "spec"}
+	case "Job":
+		path = []string{"spec", "template", "spec"}
+		pods = jobPods(obj.Object, "spec")
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+		pods = jobPods(obj.Object, "spec", "jobTemplate", "spec")
+	default:
+		return nil, 0, false, nil
+	}
+
+	content, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return nil, 0, false, err
+	}
+	var spec corev1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &spec); err != nil {
+		return nil, 0, false, err
+	}
+	return &spec, pods, true, nil
+}
+
+// jobPods returns how many pods of a Job run at the same time.
+func jobPods(obj map[string]interface{}, fields ...string) int64 {
+	pods := int64(1)
+	if parallelism, ok := nestedInt(obj, append(fields, "parallelism")...); ok {
+		pods = parallelism
+	}
+	if completions, ok := nestedInt(obj, append(fields, "completions")...); ok && completions < pods {
+		pods = completions
+	}
+	return pods
+}
+
+// nestedInt reads an integer field, which YAML manifests decode as float64.
+func nestedInt(obj map[string]interface{}, fields ...string) (int64, bool) {
+	value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || !found {
+		return 0, false
+	}
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// objectCountResource returns the object count quota resource of an object,
+// such as count/deployments.apps.
+func objectCountResource(obj *unstructured.Unstructured) corev1.ResourceName {
+	gvr, _ := meta.UnsafeGuessKindToResource(obj.GroupVersionKind())
+	if gvr.Group == "" {
+		return corev1.ResourceName("count/" + gvr.Resource)
+	}
+	return corev1.ResourceName("count/" + gvr.Resource + "." + gvr.Group)
+}
+
+// allContainers returns the init and regular containers of a pod.
+func allContainers(spec *corev1.PodSpec) []*corev1.Container {
+	containers := make([]*corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	for i := range spec.InitContainers {
+		containers = append(containers, &spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		containers = append(containers, &spec.Containers[i])
+	}
+	return containers
+}
+
+// applyLimitRangeDefaults fills in container requests and limits like the
+// API server: requests default to the container's own limits, then to the
+// defaults of Container limit ranges.
+func applyLimitRangeDefaults(spec *corev1.PodSpec, limitRanges []corev1.LimitRange) {
+	for _, c := range allContainers(spec) {
+		r := &c.Resources
+		setMissing(&r.Requests, r.Limits)
+		for _, lr := range limitRanges {
+			for _, item := range lr.Spec.Limits {
+				if item.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				setMissing(&r.Limits, item.Default)
+				setMissing(&r.Requests, item.DefaultRequest)
+			}
+		}
+	}
+}
+
+// setMissing copies the resources of defaults that list does not set.
+func setMissing(list *corev1.ResourceList, defaults corev1.ResourceList) {
+	for name, q := range defaults {
+		if _, ok := (*list)[name]; ok {
+			continue
+		}
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[name] = q.DeepCopy()
+	}
+}
+
+// checkPodLimitRanges checks the containers of a pod, and the pod as a
+// whole, against the minimums, maximums and limit/request ratios of the
+// namespace's limit ranges.
+func (p *quotaPreflight) checkPodLimitRanges(ref string, spec *corev1.PodSpec) {
+	requests := podResources(spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }, false)
+	limits := podResources(spec, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits }, false)
+
+	for _, lr := range p.limitRanges {
+		for _, item := range lr.Spec.Limits {
+			switch item.Type {
+			case corev1.LimitTypeContainer:
+				for _, c := range allContainers(spec) {
+					p.checkLimitRangeItem(fmt.Sprintf("%s: container %q", ref, c.Name), lr.Name, item, c.Resources.Requests, c.Resources.Limits)
+				}
+			case corev1.LimitTypePod:
+				p.checkLimitRangeItem(ref+": pod", lr.Name, item, requests, limits)
+			}
+		}
+	}
+}
+
+func (p *quotaPreflight) checkLimitRangeItem(subject, limitRange string, item corev1.LimitRangeItem, requests, limits corev1.ResourceList) {
+	for _, name := range sortedResourceNames(item.Min) {
+		lower := item.Min[name]
+		if request, ok := requests[name]; !ok {
+			p.violation("%s sets no %s request, but LimitRange %q requires at least %s", subject, name, limitRange, lower.String())
+		} else if request.Cmp(lower) < 0 {
+			p.violation("%s %s request %s is below the minimum %s of LimitRange %q", subject, name, request.String(), lower.String(), limitRange)
+		}
+	}
+	for _, name := range sortedResourceNames(item.Max) {
+		upper := item.Max[name]
+		if limit, ok := limits[name]; !ok {
+			p.violation("%s sets no %s limit, but LimitRange %q requires at most %s", subject, name, limitRange, upper.String())
+		} else if limit.Cmp(upper) > 0 {
+			p.violation("%s %s limit %s is above the maximum %s of LimitRange %q", subject, name, limit.String(), upper.String(), limitRange)
+		}
+	}
+	for _, name := range sortedResourceNames(item.MaxLimitRequestRatio) {
+		ratio := item.MaxLimitRequestRatio[name]
+		request, hasRequest := requests[name]
+		limit, hasLimit := limits[name]
+		if !hasRequest || !hasLimit || request.IsZero() {
+			continue
+		}
+		if actual := limit.AsApproximateFloat64() / request.AsApproximateFloat64(); actual > ratio.AsApproximateFloat64() {
+			p.violation("%s %s limit/request ratio %.2f is above the maximum %s of LimitRange %q", subject, name, actual, ratio.String(), limitRange)
+		}
+	}
+}
+
+// podMatchesQuota reports whether the quota's scopes select the pod.
+func podMatchesQuota(quota *corev1.ResourceQuota, spec *corev1.PodSpec) bool {
+	for _, scope := range quotaScopes(quota) {
+		if !podMatchesScope(scope, spec) {
+			return false
+		}
+	}
+	return true
+}
+
+func podMatchesScope(scope corev1.ScopedResourceSelectorRequirement, spec *corev1.PodSpec) bool {
+	switch scope.ScopeName {
+	case corev1.ResourceQuotaScopeTerminating:
+		return spec.ActiveDeadlineSeconds != nil && *spec.ActiveDeadlineSeconds >= 0
+	case corev1.ResourceQuotaScopeNotTerminating:
+		return spec.ActiveDeadlineSeconds == nil || *spec.ActiveDeadlineSeconds < 0
+	case corev1.ResourceQuotaScopeBestEffort:
+		return isBestEffort(spec)
+	case corev1.ResourceQuotaScopeNotBestEffort:
+		return !isBestEffort(spec)
+	case corev1.ResourceQuotaScopePriorityClass:
+		switch scope.Operator {
+		case corev1.ScopeSelectorOpIn:
+			return slices.Contains(scope.Values, spec.PriorityClassName)
+		case corev1.ScopeSelectorOpNotIn:
+			return !slices.Contains(scope.Values, spec.PriorityClassName)
+		case corev1.ScopeSelectorOpExists:
+			return spec.PriorityClassName != ""
+		case corev1.ScopeSelectorOpDoesNotExist:
+			return spec.PriorityClassName == ""
+		}
+	case corev1.ResourceQuotaScopeCrossNamespacePodAffinity:
+		return usesCrossNamespaceAffinity(spec)
+	}
+	return false
+}
+
+// isBestEffort reports whether no container sets a request or limit.
+func isBestEffort(spec *corev1.PodSpec) bool {
+	for _, c := range allContainers(spec) {
+		if len(c.Resources.Requests) > 0 || len(c.Resources.Limits) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// usesCrossNamespaceAffinity reports whether a pod (anti-)affinity term
+// selects pods in other namespaces.
+func usesCrossNamespaceAffinity(spec *corev1.PodSpec) bool {
+	if spec.Affinity == nil {
+		return false
+	}
+	var terms []corev1.PodAffinityTerm
+	if a := spec.Affinity.PodAffinity; a != nil {
+		terms = append(terms, a.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, w := range a.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, w.PodAffinityTerm)
+		}
+	}
+	if a := spec.Affinity.PodAntiAffinity; a != nil {
+		terms = append(terms, a.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, w := range a.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, w.PodAffinityTerm)
+		}
+	}
+	for _, term := range terms {
+		if len(term.Namespaces) > 0 || term.NamespaceSelector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// podResources returns the requests or limits of a pod as quota counts
+// them: the larger of the regular containers plus sidecars and any init
+// container plus the sidecars started before it. Pod-level resources, when
+// set, take precedence. The pod overhead is added to requests, and to
+// limits the pod sets.
+func podResources(spec *corev1.PodSpec, pick func(corev1.ResourceRequirements) corev1.ResourceList, withOverhead bool) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	sidecars := corev1.ResourceList{}
+	initMax := corev1.ResourceList{}
+	for _, c := range spec.Containers {
+		addResources(total, pick(c.Resources))
+	}
+	for _, c := range spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResources(sidecars, pick(c.Resources))
+			continue
+		}
+		current := pick(c.Resources).DeepCopy()
+		if current == nil {
+			current = corev1.ResourceList{}
+		}
+		addResources(current, sidecars)
+		maxResources(initMax, current)
+	}
+	addResources(total, sidecars)
+	maxResources(total, initMax)
+
+	if spec.Resources != nil {
+		for name, q := range pick(*spec.Resources) {
+			total[name] = q.DeepCopy()
+		}
+	}
+	if withOverhead {
+		addResources(total, spec.Overhead)
+	} else {
+		for name, q := range spec.Overhead {
+			if existing, ok := total[name]; ok {
+				existing.Add(q)
+				total[name] = existing
+			}
+		}
+	}
+	return total
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, q := range add {
+		existing := total[name].DeepCopy()
+		existing.Add(q)
+		total[name] = existing
+	}
+}
+
+func maxResources(total, other corev1.ResourceList) {
+	for name, q := range other {
+		if existing, ok := total[name]; !ok || q.Cmp(existing) > 0 {
+			total[name] = q.DeepCopy()
+		}
+	}
+}
+
+// scaleQuantity multiplies a quantity by n.
+func scaleQuantity(q resource.Quantity, n int64) resource.Quantity {
+	scaled := q.DeepCopy()
+	scaled.Mul(n)
+	return scaled
+}
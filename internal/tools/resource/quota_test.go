@@ -0,0 +1,365 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+)
+
+// newQuotaClient serves quotas and limitRanges.
+func newQuotaClient(quotas []corev1.ResourceQuota, limitRanges []corev1.LimitRange) *mcptest.FakeClient {
+	objects := map[string][]runtime.Object{"resourcequotas": nil, "limitranges": nil}
+	for i := range quotas {
+		objects["resourcequotas"] = append(objects["resourcequotas"], &quotas[i])
+	}
+	for i := range limitRanges {
+		objects["limitranges"] = append(objects["limitranges"], &limitRanges[i])
+	}
+	return mcptest.NewFakeClient(objects)
+}
+
+func callResourceQuotas(t *testing.T, client *mcptest.FakeClient, args map[string]interface{}) QuotaResult {
+	t.Helper()
+	return mcptest.DecodeResult[QuotaResult](t, callResourceTool(t, client, "resource_quotas", args))
+}
+
+func computeQuota() corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "team-a"},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+				corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+				corev1.ResourceLimitsMemory:   resource.MustParse("16Gi"),
+				corev1.ResourcePods:           resource.MustParse("10"),
+				"count/deployments.apps":      resource.MustParse("5"),
+			},
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+				corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+				corev1.ResourceLimitsMemory:   resource.MustParse("16Gi"),
+				corev1.ResourcePods:           resource.MustParse("10"),
+				"count/deployments.apps":      resource.MustParse("5"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("3"),
+				corev1.ResourceRequestsMemory: resource.MustParse("2Gi"),
+				corev1.ResourceLimitsMemory:   resource.MustParse("4Gi"),
+				corev1.ResourcePods:           resource.MustParse("4"),
+				"count/deployments.apps":      resource.MustParse("2"),
+			},
+		},
+	}
+}
+
+func defaultsLimitRange() corev1.LimitRange {
+	return corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "team-a"},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Default:        corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				DefaultRequest: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Max:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}},
+		},
+	}
+}
+
+const webDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx
+        resources:
+          requests:
+            cpu: 500m
+          limits:
+            cpu: "1"
+`
+
+func TestResourceQuotas_Usage(t *testing.T) {
+	client := newQuotaClient(
+		[]corev1.ResourceQuota{
+			computeQuota(),
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "best-effort", Namespace: "team-a"},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard:   corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")},
+					Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort},
+				},
+			},
+		},
+		[]corev1.LimitRange{defaultsLimitRange()},
+	)
+
+	out := callResourceQuotas(t, client, map[string]interface{}{"namespace": "team-a"})
+
+	assert.Equal(t, "team-a", out.Namespace)
+	assert.Nil(t, out.Preflight)
+	require.Len(t, out.Quotas, 2)
+
+	assert.Equal(t, "best-effort", out.Quotas[0].Name)
+	assert.Equal(t, []string{"BestEffort"}, out.Quotas[0].Scopes)
+	assert.Equal(t, []QuotaResource{{Resource: "pods", Hard: "2", Used: "0", Remaining: "2"}}, out.Quotas[0].Resources)
+
+	compute := out.Quotas[1]
+	require.Len(t, compute.Resources, 5)
+	assert.Equal(t, QuotaResource{Resource: "requests.cpu", Hard: "4", Used: "3", Remaining: "1", UsedPercent: 75}, compute.Resources[3])
+	assert.Equal(t, QuotaResource{Resource: "requests.memory", Hard: "8Gi", Used: "2Gi", Remaining: "6Gi", UsedPercent: 25}, compute.Resources[4])
+
+	require.Len(t, out.LimitRanges, 1)
+	assert.Equal(t, "defaults", out.LimitRanges[0].Name)
+}
+
+func TestResourceQuotas_Preflight(t *testing.T) {
+	tests := []struct {
+		name           string
+		manifest       interface{}
+		wantFits       bool
+		wantViolations []string
+		wantQuotas     []PreflightQuota
+	}{
+		{
+			name:     "deployment exceeding the remaining CPU",
+			manifest: webDeployment,
+			wantViolations: []string{
+				`exceeds ResourceQuota "compute": requests.cpu needs 1500m but only 1 remains`,
+			},
+			wantQuotas: []PreflightQuota{
+				{Quota: "compute", Resource: "count/deployments.apps", Requested: "1", Remaining: "3", Fits: true},
+				{Quota: "compute", Resource: "limits.memory", Requested: "1536Mi", Remaining: "12Gi", Fits: true},
+				{Quota: "compute", Resource: "pods", Requested: "3", Remaining: "6", Fits: true},
+				{Quota: "compute", Resource: "requests.cpu", Requested: "1500m", Remaining: "1", Fits: false},
+				{Quota: "compute", Resource: "requests.memory", Requested: "768Mi", Remaining: "6Gi", Fits: true},
+			},
+		},
+		{
+			name: "pod that fits",
+			manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]interface{}{"name": "debug"},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{
+						"name":  "shell",
+						"image": "busybox",
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"cpu": "100m"},
+							"limits":   map[string]interface{}{"cpu": "500m"},
+						},
+					}},
+				},
+			},
+			wantFits: true,
+			wantQuotas: []PreflightQuota{
+				{Quota: "compute", Resource: "limits.memory", Requested: "512Mi", Remaining: "12Gi", Fits: true},
+				{Quota: "compute", Resource: "pods", Requested: "1", Remaining: "6", Fits: true},
+				{Quota: "compute", Resource: "requests.cpu", Requested: "100m", Remaining: "1", Fits: true},
+				{Quota: "compute", Resource: "requests.memory", Requested: "256Mi", Remaining: "6Gi", Fits: true},
+			},
+		},
+		{
+			name: "limit range maximum and missing quota request",
+			manifest: `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  parallelism: 2
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate
+        resources:
+          limits:
+            memory: 1Gi
+      - name: proxy
+        image: proxy
+        resources:
+          limits:
+            cpu: "3"
+`,
+			wantViolations: []string{
+				`Job/migrate: container "migrate" sets no cpu limit, but LimitRange "defaults" requires at most 2`,
+				`Job/migrate: container "proxy" cpu limit 3 is above the maximum 2 of LimitRange "defaults"`,
+				`Job/migrate: ResourceQuota "compute" requires a cpu request, which container "migrate" does not set and no LimitRange defaults`,
+				`exceeds ResourceQuota "compute": requests.cpu needs 6 but only 1 remains`,
+			},
+			wantQuotas: []PreflightQuota{
+				{Quota: "compute", Resource: "limits.memory", Requested: "3Gi", Remaining: "12Gi", Fits: true},
+				{Quota: "compute", Resource: "pods", Requested: "2", Remaining: "6", Fits: true},
+				{Quota: "compute", Resource: "requests.cpu", Requested: "6", Remaining: "1", Fits: false},
+				{Quota: "compute", Resource: "requests.memory", Requested: "2560Mi", Remaining: "6Gi", Fits: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newQuotaClient([]corev1.ResourceQuota{computeQuota()}, []corev1.LimitRange{defaultsLimitRange()})
+
+			out := callResourceQuotas(t, client, map[string]interface{}{"namespace": "team-a", "manifest": tt.manifest})
+
+			require.NotNil(t, out.Preflight)
+			assert.Equal(t, tt.wantFits, out.Preflight.Fits)
+			assert.Equal(t, tt.wantViolations, out.Preflight.Violations)
+			assert.Equal(t, tt.wantQuotas, out.Preflight.Quotas)
+		})
+	}
+}
+
+func TestResourceQuotas_PreflightScopesAndObjects(t *testing.T) {
+	client := newQuotaClient(
+		[]corev1.ResourceQuota{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "high-priority", Namespace: "team-a"},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+					ScopeSelector: &corev1.ScopeSelector{MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+						ScopeName: corev1.ResourceQuotaScopePriorityClass,
+						Operator:  corev1.ScopeSelectorOpIn,
+						Values:    []string{"high"},
+					}}},
+				},
+				Status: corev1.ResourceQuotaStatus{Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "objects", Namespace: "team-a"},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{
+						corev1.ResourceServicesLoadBalancers:                      resource.MustParse("0"),
+						corev1.ResourceRequestsStorage:                            resource.MustParse("100Gi"),
+						"fast.storageclass.storage.k8s.io/persistentvolumeclaims": resource.MustParse("5"),
+					},
+				},
+				Status: corev1.ResourceQuotaStatus{Used: corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("20Gi")}},
+			},
+		},
+		nil,
+	)
+
+	manifest := `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 2
+  template:
+    spec:
+      priorityClassName: high
+      containers:
+      - name: db
+        image: postgres
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+spec:
+  type: LoadBalancer
+  ports:
+  - port: 5432
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  storageClassName: fast
+  resources:
+    requests:
+      storage: 50Gi
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team-b
+`
+	out := callResourceQuotas(t, client, map[string]interface{}{"namespace": "team-a", "manifest": manifest})
+
+	require.NotNil(t, out.Preflight)
+	assert.False(t, out.Preflight.Fits)
+	assert.Equal(t, []string{
+		`exceeds ResourceQuota "high-priority": pods needs 2 but only 1 remains`,
+		`exceeds ResourceQuota "objects": services.loadbalancers needs 1 but only 0 remains`,
+	}, out.Preflight.Violations)
+	assert.Equal(t, []PreflightQuota{
+		{Quota: "high-priority", Resource: "pods", Requested: "2", Remaining: "1", Fits: false},
+		{Quota: "objects", Resource: "fast.storageclass.storage.k8s.io/persistentvolumeclaims", Requested: "1", Remaining: "5", Fits: true},
+		{Quota: "objects", Resource: "requests.storage", Requested: "50Gi", Remaining: "80Gi", Fits: true},
+		{Quota: "objects", Resource: "services.loadbalancers", Requested: "1", Remaining: "0", Fits: false},
+	}, out.Preflight.Quotas)
+	assert.Equal(t, []PreflightObject{
+		{Kind: "StatefulSet", Name: "db", Pods: 2},
+		{Kind: "Deployment", Name: "worker", Pods: 5},
+		{Kind: "Service", Name: "db"},
+		{Kind: "PersistentVolumeClaim", Name: "data"},
+		{Kind: "ConfigMap", Name: "settings", Skipped: "object is in namespace team-b"},
+	}, out.Preflight.Objects)
+}
+
+func TestResourceQuotas_NoQuotas(t *testing.T) {
+	out := callResourceQuotas(t, newQuotaClient(nil, nil), map[string]interface{}{
+		"namespace": "team-a",
+		"manifest":  webDeployment,
+	})
+
+	assert.Empty(t, out.Quotas)
+	assert.Empty(t, out.LimitRanges)
+	require.NotNil(t, out.Preflight)
+	assert.True(t, out.Preflight.Fits)
+	assert.Equal(t, []string{"The namespace has no ResourceQuotas or LimitRanges, so neither limits the manifest."}, out.Preflight.Notes)
+}
+
+func TestResourceQuotas_RequiresNamespace(t *testing.T) {
+	result := callResourceTool(t, newQuotaClient(nil, nil), "resource_quotas", map[string]interface{}{})
+	assert.True(t, result.IsError)
+}
+
+func TestPodResources(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "setup", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}},
+			{Name: "sidecar", RestartPolicy: &always, Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}},
+		},
+		Containers: []corev1.Container{
+			{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}}},
+		},
+		Overhead: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+	}
+	pick := func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests }
+
+	requests := podResources(spec, pick, true)
+	cpu := requests[corev1.ResourceCPU]
+	assert.Equal(t, "2050m", cpu.String(), "the init container dominates, plus the overhead")
+}
@@ -18,6 +18,17 @@ var (
 		"list",
 		"describe",
 	}
+	// readOnlyResourceToolsWithoutAlias postdate the kubernetes_ prefix and
+	// have no deprecated alias.
+	readOnlyResourceToolsWithoutAlias = []string{
+		"wait",
+		"validate",
+		"resource_quotas",
+		"list_namespaces",
+		"namespace_overview",
+		"tree",
+		"conditions",
+	}
 	mutatingResourceTools = []string{
 		"create",
 		"apply",
@@ -25,6 +36,13 @@ var (
 		"patch",
 		"scale",
 	}
+	mutatingResourceToolsWithoutAlias = []string{
+		"apply_all",
+		"create_namespace",
+		"delete_namespace",
+		"label",
+		"annotate",
+	}
 )
 
 // registerResourceToolsWith builds a fresh ServerContext with the given options
@@ -53,11 +71,11 @@ func TestRegisterResourceTools_NonDestructiveMode_HidesMutating(t *testing.T) {
 		server.WithDryRun(false),
 	)
 
-	for _, name := range readOnlyResourceTools {
+	for _, name := range append(readOnlyResourceTools, readOnlyResourceToolsWithoutAlias...) {
 		assert.Contains(t, tools, name, "read-only tool %q should be registered", name)
 	}
 
-	for _, name := range mutatingResourceTools {
+	for _, name := range append(mutatingResourceTools, mutatingResourceToolsWithoutAlias...) {
 		assert.NotContains(t, tools, name, "mutating tool %q should be hidden in non-destructive mode", name)
 	}
 }
@@ -70,6 +88,9 @@ func TestRegisterResourceTools_DestructiveMode_RegistersAll(t *testing.T) {
 	for _, name := range append(readOnlyResourceTools, mutatingResourceTools...) {
 		assert.Contains(t, tools, name, "tool %q should be registered when non-destructive is off", name)
 	}
+	for _, name := range mutatingResourceToolsWithoutAlias {
+		assert.Contains(t, tools, name, "tool %q should be registered when non-destructive is off", name)
+	}
 }
 
 func TestRegisterResourceTools_DryRun_RegistersAll(t *testing.T) {
@@ -125,7 +146,7 @@ func TestRegisterResourceTools_Whitelist_RegistersWhitelisted(t *testing.T) {
 	}
 	assert.Contains(t, tools, "create", "create should be registered when 'create' is whitelisted")
 
-	for _, name := range []string{"apply", "delete", "patch", "scale"} {
+	for _, name := range []string{"apply", "apply_all", "delete", "patch", "scale"} {
 		assert.NotContains(t, tools, name, "tool %q should be hidden when not whitelisted", name)
 	}
 }
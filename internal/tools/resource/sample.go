@@ -0,0 +1,103 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+)
+
+const (
+	// samplePageSize is the page size used while scanning a list for sampling.
+	samplePageSize int64 = 500
+
+	// maxSamplePages bounds the scan so sampling cannot walk an unbounded
+	// list. With samplePageSize this is 100k objects.
+	maxSamplePages = 200
+)
+
+// errInvalidFilter marks client-side filter errors raised while sampling, so
+// callers can report them separately from Kubernetes API errors.
+var errInvalidFilter = errors.New("invalid filter criteria")
+
+// SampleMetadata describes how a sampled list response was produced.
+type SampleMetadata struct {
+	// SampleSize is the number of items returned.
+	SampleSize int `json:"sampleSize"`
+
+	// TotalCount is the number of matching items scanned. It is the exact
+	// total when Complete is true.
+	TotalCount int `json:"totalCount"`
+
+	// Complete is false when the scan stopped at the page limit; the sample
+	// is then uniform over the first TotalCount items only.
+	Complete bool `json:"complete"`
+}
+
+// listPageFunc fetches one page of a list starting at continueToken.
+type listPageFunc func(ctx context.Context, continueToken string) (*k8s.PaginatedListResponse, error)
+
+// sampledList is the outcome of sampleList.
+type sampledList struct {
+	Items           []runtime.Object
+	Metadata        SampleMetadata
+	ResourceVersion string
+	Meta            *k8s.ResponseMeta
+}
+
+// sampleList pages through a list and keeps a uniform random sample of up to
+// size items (reservoir sampling), so memory stays bounded by size rather
+// than by the list length. Client-side filter criteria are applied per page
+// before sampling, so TotalCount counts matching items only.
+func sampleList(ctx context.Context, fetch listPageFunc, size int, criteria FilterCriteria, intN func(int) int) (*sampledList, error) {
+	if intN == nil {
+		intN = rand.IntN
+	}
+
+	result := &sampledList{Items: make([]runtime.Object, 0, size)}
+	continueToken := ""
+	seen := 0
+
+	for page := 0; page < maxSamplePages; page++ {
+		resp, err := fetch(ctx, continueToken)
+		if err != nil {
+			return nil, err
+		}
+		if page == 0 {
+			result.ResourceVersion = resp.ResourceVersion
+			result.Meta = resp.Meta
+		}
+
+		items := resp.Items
+		if len(criteria) > 0 {
+			if items, err = ApplyClientSideFilter(items, criteria); err != nil {
+				return nil, fmt.Errorf("%w: %v", errInvalidFilter, err)
+			}
+		}
+
+		for _, item := range items {
+			seen++
+			if len(result.Items) < size {
+				result.Items = append(result.Items, item)
+				continue
+			}
+			if j := intN(seen); j < size {
+				result.Items[j] = item
+			}
+		}
+
+		continueToken = resp.Continue
+		if continueToken == "" {
+			result.Metadata.Complete = true
+			break
+		}
+	}
+
+	result.Metadata.SampleSize = len(result.Items)
+	result.Metadata.TotalCount = seen
+	return result, nil
+}
@@ -0,0 +1,245 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// samplePods returns n pods named pod-0..pod-n-1; even pods run image "a".
+func samplePods(n int) []runtime.Object {
+	pods := make([]runtime.Object, n)
+	for i := range pods {
+		image := "b"
+		if i%2 == 0 {
+			image = "a"
+		}
+		pods[i] = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": fmt.Sprintf("pod-%d", i), "namespace": "default"},
+			"spec":       map[string]interface{}{"image": image},
+		}}
+	}
+	return pods
+}
+
+// pagedFetch serves objects in pages of pageSize using the offset as the
+// continue token, and counts the pages requested.
+func pagedFetch(objects []runtime.Object, pageSize int, pages *int) listPageFunc {
+	return func(_ context.Context, continueToken string) (*k8s.PaginatedListResponse, error) {
+		*pages++
+		start := 0
+		if continueToken != "" {
+			start, _ = strconv.Atoi(continueToken)
+		}
+		end := min(start+pageSize, len(objects))
+		resp := &k8s.PaginatedListResponse{Items: objects[start:end], TotalItems: end - start, ResourceVersion: "42"}
+		if end < len(objects) {
+			resp.Continue = strconv.Itoa(end)
+		}
+		return resp, nil
+	}
+}
+
+func sampleNames(items []runtime.Object) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.(*unstructured.Unstructured).GetName())
+	}
+	return names
+}
+
+func TestSampleList(t *testing.T) {
+	pages := 0
+	sample, err := sampleList(context.Background(), pagedFetch(samplePods(25), 10, &pages), 5, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, pages, "every page is scanned")
+	assert.Equal(t, SampleMetadata{SampleSize: 5, TotalCount: 25, Complete: true}, sample.Metadata)
+	assert.Len(t, sample.Items, 5)
+	assert.Equal(t, "42", sample.ResourceVersion)
+
+	unique := map[string]bool{}
+	for _, name := range sampleNames(sample.Items) {
+		unique[name] = true
+	}
+	assert.Len(t, unique, 5, "items are sampled without replacement")
+}
+
+func TestSampleList_SmallerThanSample(t *testing.T) {
+	pages := 0
+	sample, err := sampleList(context.Background(), pagedFetch(samplePods(3), 10, &pages), 5, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, SampleMetadata{SampleSize: 3, TotalCount: 3, Complete: true}, sample.Metadata)
+	assert.Equal(t, []string{"pod-0", "pod-1", "pod-2"}, sampleNames(sample.Items))
+}
+
+func TestSampleList_ReservoirReplacement(t *testing.T) {
+	// Always replacing slot 0 keeps the last item there.
+	pages := 0
+	sample, err := sampleList(context.Background(), pagedFetch(samplePods(10), 4, &pages), 2, nil, func(int) int { return 0 })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod-9", "pod-1"}, sampleNames(sample.Items))
+
+	// Never replacing keeps the first items.
+	sample, err = sampleList(context.Background(), pagedFetch(samplePods(10), 4, &pages), 2, nil, func(n int) int { return n - 1 })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pod-0", "pod-1"}, sampleNames(sample.Items))
+}
+
+func TestSampleList_Uniform(t *testing.T) {
+	// Each of 10 items should land in a 3-item sample about 30% of the time.
+	counts := map[string]int{}
+	const runs = 3000
+	for i := 0; i < runs; i++ {
+		pages := 0
+		sample, err := sampleList(context.Background(), pagedFetch(samplePods(10), 4, &pages), 3, nil, nil)
+		require.NoError(t, err)
+		for _, name := range sampleNames(sample.Items) {
+			counts[name]++
+		}
+	}
+	for name, n := range counts {
+		assert.InDelta(t, 0.3, float64(n)/runs, 0.05, "item %s is over- or under-sampled", name)
+	}
+}
+
+func TestSampleList_Filter(t *testing.T) {
+	pages := 0
+	sample, err := sampleList(context.Background(), pagedFetch(samplePods(20), 6, &pages), 50, FilterCriteria{"spec.image": "a"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 10, sample.Metadata.TotalCount, "the total counts matching items only")
+
+	_, err = sampleList(context.Background(), pagedFetch(samplePods(20), 6, &pages), 5, FilterCriteria{"": "a"}, nil)
+	assert.ErrorIs(t, err, errInvalidFilter)
+}
+
+func TestSampleList_StopsAtPageLimit(t *testing.T) {
+	pages := 0
+	sample, err := sampleList(context.Background(), pagedFetch(samplePods(maxSamplePages+10), 1, &pages), 5, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, maxSamplePages, pages)
+	assert.False(t, sample.Metadata.Complete)
+	assert.Equal(t, maxSamplePages, sample.Metadata.TotalCount)
+}
+
+func TestSampleList_FetchError(t *testing.T) {
+	fetch := func(context.Context, string) (*k8s.PaginatedListResponse, error) {
+		return nil, errors.New("forbidden")
+	}
+	_, err := sampleList(context.Background(), fetch, 5, nil, nil)
+	assert.EqualError(t, err, "forbidden")
+}
+
+// pagingK8sClient serves a fixed list of pods in pages honouring the limit
+// and continue options.
+type pagingK8sClient struct {
+	testdata.MockK8sClient
+	objects []runtime.Object
+	limits  []int64
+}
+
+func (c *pagingK8sClient) List(ctx context.Context, _, _, _, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	c.limits = append(c.limits, opts.Limit)
+	pages := 0
+	return pagedFetch(c.objects, int(opts.Limit), &pages)(ctx, opts.Continue)
+}
+
+func TestHandleListResources_Sample(t *testing.T) {
+	client := &pagingK8sClient{objects: samplePods(1200)}
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(client),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resourceType": "pods",
+		"sample":       float64(10),
+	}
+	result, err := handleListResources(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var response PaginatedSummaryResponse
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &response))
+	assert.Len(t, response.Items, 10)
+	assert.Empty(t, response.Continue)
+	assert.Equal(t, map[string]interface{}{
+		"sample": map[string]interface{}{"sampleSize": float64(10), "totalCount": float64(1200), "complete": true},
+	}, response.Metadata)
+	assert.Equal(t, []int64{samplePageSize, samplePageSize, samplePageSize}, client.limits)
+}
+
+func TestHandleListResources_SampleFullOutput(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&pagingK8sClient{objects: samplePods(30)}),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"resourceType": "pods",
+		"sample":       float64(4),
+		"fullOutput":   true,
+	}
+	result, err := handleListResources(context.Background(), request, sc)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getErrorText(t, result))
+
+	var response struct {
+		Items  []map[string]interface{} `json:"items"`
+		Sample SampleMetadata           `json:"sample"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getErrorText(t, result)), &response))
+	assert.Len(t, response.Items, 4)
+	assert.Equal(t, SampleMetadata{SampleSize: 4, TotalCount: 30, Complete: true}, response.Sample)
+}
+
+func TestHandleListResources_SampleValidation(t *testing.T) {
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&pagingK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "zero", args: map[string]interface{}{"sample": float64(0)}, wantErr: "sample must be a positive number"},
+		{name: "too large", args: map[string]interface{}{"sample": float64(500)}, wantErr: "sample must be between 1 and 100"},
+		{name: "with continue", args: map[string]interface{}{"sample": float64(5), "continue": "abc"}, wantErr: "cannot be combined"},
+		{name: "with summary", args: map[string]interface{}{"sample": float64(5), "summary": true}, wantErr: "cannot be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["resourceType"] = "pods"
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.args
+			result, err := handleListResources(context.Background(), request, sc)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, getErrorText(t, result), tt.wantErr)
+		})
+	}
+}
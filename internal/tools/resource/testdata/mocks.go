@@ -6,8 +6,10 @@ import (
 	"io"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
@@ -130,6 +132,26 @@ func (m *MockK8sClient) PortForwardToService(_ context.Context, _, _, _ string,
 	}, nil
 }
 
+// Watch implements k8s.ResourceManager.
+func (m *MockK8sClient) Watch(_ context.Context, _, _, _, _ string, _ k8s.WatchOptions) (watch.Interface, error) {
+	return watch.NewEmptyWatch(), nil
+}
+
+// Validate implements k8s.ResourceManager.
+func (m *MockK8sClient) Validate(_ context.Context, _, _ string, _ runtime.Object, _ k8s.ValidateOptions) (*k8s.ValidateResponse, error) {
+	return &k8s.ValidateResponse{Operation: "create"}, nil
+}
+
+// EvictPod implements k8s.PodManager.
+func (m *MockK8sClient) EvictPod(_ context.Context, _, _, _ string, _ k8s.EvictOptions) error {
+	return nil
+}
+
+// AddEphemeralContainer implements k8s.PodManager.
+func (m *MockK8sClient) AddEphemeralContainer(_ context.Context, _, _, _ string, _ corev1.EphemeralContainer) (*corev1.Pod, error) {
+	return &corev1.Pod{}, nil
+}
+
 // GetAPIResources implements k8s.ClusterManager.
 func (m *MockK8sClient) GetAPIResources(_ context.Context, _ string, _, _ int, _ string, _ bool, _ []string) (*k8s.PaginatedAPIResourceResponse, error) {
 	return nil, nil
@@ -140,6 +162,11 @@ func (m *MockK8sClient) GetClusterHealth(_ context.Context, _ string) (*k8s.Clus
 	return nil, nil
 }
 
+// ProxyGetService implements k8s.ClusterManager.
+func (m *MockK8sClient) ProxyGetService(_ context.Context, _, _ string, _ k8s.ServiceProxyRequest) (*k8s.ServiceProxyResponse, error) {
+	return nil, nil
+}
+
 // MockLogger implements server.Logger for testing.
 type MockLogger struct{}
 
@@ -6,6 +6,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools"
 )
@@ -202,9 +203,20 @@ Supports both server-side selectors (labelSelector, fieldSelector) and client-si
 		mcp.WithBoolean("summary",
 			mcp.Description("Return aggregated counts (by status, namespace) instead of full objects. Useful for fleet-scale operations with many results. Default: false"),
 		),
+		mcp.WithNumber("sample",
+			mcp.Description("Return a uniform random sample of this many items plus the exact total count of matching items (max: 100). Scans every page server-side, so use it for statistical questions over large lists, e.g. 'roughly how many pods use image X'. Cannot be combined with continue or summary."),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("changedSince",
+			mcp.Description("Return only the resources whose resourceVersion changed since this session last listed the same query (same cluster, resource type, namespace, selectors and filter), plus the names of removed ones. The first call returns everything and records a baseline. Useful for monitoring loops that repeat a list. Requires an MCP session; cannot be combined with continue, summary or sample."),
+		),
 		mcp.WithString("output",
-			mcp.Description("Output format: 'slim' (default; blacklist exclusion + per-Kind shaping), 'normal' (blacklist exclusion only), 'wide' / 'full' (no field stripping). Secret data is always masked regardless of output. See docs/slim-output-tuning.md for the full per-Kind shape table."),
-			mcp.Enum("slim", "normal", "wide", "full"),
+			mcp.Description("Output format: 'slim' (default; blacklist exclusion + per-Kind shaping), 'normal' (blacklist exclusion only), 'wide' / 'full' (no field stripping), 'table' (compact rows with kubectl-get-wide-like columns per Kind, e.g. NAME/READY/STATUS/RESTARTS/AGE/IP/NODE for pods; by far the cheapest format for scanning many resources, includeLabels adds a LABELS column, overrides fullOutput). Secret data is always masked regardless of output. See docs/slim-output-tuning.md for the full per-Kind shape table."),
+			mcp.Enum("slim", "normal", "wide", "full", "table"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'json' (default), 'markdown' or 'csv'. Markdown and CSV render the items as the rows of output=table (or the counts of summary=true) for clients that display Markdown tables, followed by a JSON object with the continue token and metadata. Cannot be combined with fullOutput."),
+			mcp.Enum("json", "markdown", "csv"),
 		),
 	)
 	listResourceTool := mcp.NewTool("list", listResourceOpts...)
@@ -256,6 +268,227 @@ For cluster-scoped resources (nodes, namespaces, PVs, clusterroles), this is ign
 	s.AddTool(describeResourceTool, tools.WrapWithAuditLogging("describe", handleDescribeResource, sc))
 	tools.MaybeAddDeprecatedAlias(s, sc, "describe", handleDescribeResource, describeResourceOpts...)
 
+	// wait tool
+	waitOpts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf(`Block until a resource reaches a condition, like kubectl wait. Watches the resource and returns as soon as the condition holds or the timeout passes.
+
+Conditions ('for'):
+- condition=Available, condition=Ready, condition=Complete=True: a status condition with the given status (default True). Conditions reported for an older generation do not count.
+- jsonpath={.status.phase}=Running: a field with the given value, or any non-empty value when no value is given
+- delete: the resource no longer exists
+- create: the resource exists
+
+The timeout is capped at %d seconds. A result with met=false and timedOut=true is not an error: call wait again to keep waiting.`, MaxWaitTimeoutSeconds)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	waitOpts = append(waitOpts, clusterContextParams...)
+	waitOpts = append(waitOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for namespaced resources. Uses 'default' if not specified; ignored for cluster-scoped resources."),
+		),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of Kubernetes resource (e.g., deployment, pod, job)"),
+		),
+		mcp.WithString("apiGroup",
+			mcp.Description("Optional API group for the resource (e.g., 'apps', 'batch', or 'cluster.x-k8s.io')"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the resource to wait for. Exactly one of name or labelSelector is required."),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Wait for all resources matching this label selector (e.g., 'app=web')"),
+		),
+		mcp.WithString("for",
+			mcp.Required(),
+			mcp.Description("Condition to wait for: condition=Type[=Value], jsonpath={path}[=value], delete or create"),
+		),
+		mcp.WithNumber("timeoutSeconds",
+			mcp.Min(1),
+			mcp.Max(MaxWaitTimeoutSeconds),
+			mcp.Description(fmt.Sprintf("How long to wait in seconds. Default: %d. Range: [1, %d].", DefaultWaitTimeoutSeconds, MaxWaitTimeoutSeconds)),
+		),
+	)
+	s.AddTool(mcp.NewTool("wait", waitOpts...), tools.WrapWithAuditLogging("wait", handleWait, sc))
+
+	// validate tool
+	validateOpts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf(`Validate Kubernetes manifests against the cluster without changing anything.
+
+Each object is sent to the API server as a server-side dry-run create, or update if it already exists, so schema validation and admission webhooks run as for a real change. The result reports per object whether it was accepted, the error if not, and any API warnings such as deprecated API versions or webhook warnings. At most %d objects per call.`, MaxValidateObjects)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	validateOpts = append(validateOpts, clusterContextParams...)
+	validateOpts = append(validateOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for namespaced objects that do not set metadata.namespace. Uses 'default' if not specified."),
+		),
+		mcp.WithAny("manifest",
+			mcp.Required(),
+			mcp.Description("Kubernetes manifest as a JSON object, or as a YAML or JSON string which may hold several documents separated by '---'"),
+		),
+		mcp.WithString("fieldValidation",
+			mcp.Description("How unknown or duplicate fields are reported: Strict rejects the object (default), Warn reports them as warnings, Ignore drops them"),
+			mcp.Enum(k8s.FieldValidationStrict, k8s.FieldValidationWarn, k8s.FieldValidationIgnore),
+		),
+	)
+	s.AddTool(mcp.NewTool("validate", validateOpts...), tools.WrapWithAuditLogging("validate", handleValidate, sc))
+
+	// resource_quotas tool
+	resourceQuotasOpts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf(`Show the ResourceQuotas of a namespace with their hard limits, current usage and what remains, and its LimitRanges with their per-container and per-pod minimums, maximums and defaults.
+
+Given a manifest, also check before applying it whether it would fit: pods of Pods, Deployments, StatefulSets, ReplicaSets, DaemonSets, Jobs and CronJobs are given the LimitRange defaults and multiplied by their replicas, then compared with the remaining quota, including object counts, PersistentVolumeClaim storage and Service load balancers and node ports. Quota scopes such as BestEffort or PriorityClass are honoured. The result lists every reason the API server would reject the manifest: an exceeded quota, a LimitRange violation, or a container missing a request or limit that a quota tracks. Usage includes existing objects, so updating an existing workload needs less than reported. At most %d objects per call.`, MaxQuotaPreflightObjects)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	resourceQuotasOpts = append(resourceQuotasOpts, clusterContextParams...)
+	resourceQuotasOpts = append(resourceQuotasOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace whose quotas and limit ranges to show. Manifest objects in other namespaces are skipped."),
+		),
+		mcp.WithAny("manifest",
+			mcp.Description("Kubernetes manifest to check against the remaining quota, as a JSON object, or as a YAML or JSON string which may hold several documents separated by '---' (optional)"),
+		),
+	)
+	s.AddTool(mcp.NewTool("resource_quotas", resourceQuotasOpts...), tools.WrapWithAuditLogging("resource_quotas", handleResourceQuotas, sc))
+
+	// list_namespaces tool
+	listNamespacesOpts := []mcp.ToolOption{
+		mcp.WithDescription("List namespaces with their status (Active or Terminating) and age and, for namespaces with ResourceQuotas, how much of each quota is used and which resources are exhausted. Sorted by name."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	listNamespacesOpts = append(listNamespacesOpts, clusterContextParams...)
+	listNamespacesOpts = append(listNamespacesOpts,
+		mcp.WithString("labelSelector",
+			mcp.Description("Label selector limiting the namespaces listed, e.g. 'team=payments' (optional)"),
+		),
+		mcp.WithBoolean("includeLabels",
+			mcp.Description("Include namespace labels (default: false)"),
+		),
+		mcp.WithBoolean("includeQuotas",
+			mcp.Description("Include a summary of each namespace's ResourceQuotas (default: true)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxNamespacesLimit),
+			mcp.Description(fmt.Sprintf("Maximum number of namespaces to return. Default: %d. Maximum: %d. total always counts all matching namespaces.", DefaultNamespacesLimit, MaxNamespacesLimit)),
+		),
+	)
+	s.AddTool(mcp.NewTool("list_namespaces", listNamespacesOpts...), tools.WrapWithAuditLogging("list_namespaces", handleListNamespaces, sc))
+
+	// namespace_overview tool
+	namespaceOverviewOpts := []mcp.ToolOption{
+		mcp.WithDescription("Get a compact snapshot of everything in a namespace in one call, the first call to make when asked to look at a namespace: Deployments, StatefulSets and DaemonSets with their status (those not ready first), Job and CronJob counts with the failed Jobs, pods by phase with the failing ones (crash looping, image pull errors, failed), Services with their type and ports, Ingresses with their hosts, ConfigMap and Secret counts, PersistentVolumeClaims with the pending ones, and ResourceQuota usage. Follow up with tree, diagnose_pod or resource_quotas for details."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	namespaceOverviewOpts = append(namespaceOverviewOpts, clusterContextParams...)
+	namespaceOverviewOpts = append(namespaceOverviewOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace to summarize"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxNamespaceOverviewLimit),
+			mcp.Description(fmt.Sprintf("Maximum number of entries in each list. Default: %d. Maximum: %d. The counts always cover everything.", DefaultNamespaceOverviewLimit, MaxNamespaceOverviewLimit)),
+		),
+	)
+	s.AddTool(mcp.NewTool("namespace_overview", namespaceOverviewOpts...), tools.WrapWithAuditLogging("namespace_overview", handleNamespaceOverview, sc))
+
+	// tree tool
+	treeOpts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf(`Show the ownership tree of an object, like kubectl tree: its owners up to the topmost controller (e.g. Pod -> ReplicaSet -> Deployment) and everything it owns down to the requested depth (e.g. Deployment -> ReplicaSets -> Pods), with a one-line status per node such as Running 1/1, CrashLoopBackOff with restarts, or Partially Ready 2/3.
+
+Use it to find which controller manages a failing pod, or which pods belong to a workload. An owner that no longer exists is shown with status Missing. Children of custom resources are searched among Deployments, StatefulSets, DaemonSets, Jobs, CronJobs, Services and ConfigMaps unless childTypes says otherwise. At most %d nodes are returned.`, MaxTreeNodes)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	treeOpts = append(treeOpts, clusterContextParams...)
+	treeOpts = append(treeOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the object. Uses 'default' if not specified; ignored for cluster-scoped resources."),
+		),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of Kubernetes resource (e.g., pod, deployment, helmrelease)"),
+		),
+		mcp.WithString("apiGroup",
+			mcp.Description("Optional API group for the resource (e.g., 'apps', 'helm.toolkit.fluxcd.io', or 'apps/v1')"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the object"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Which way to walk ownerReferences: 'up' to the owners, 'down' to the owned objects, or 'both' (default)"),
+			mcp.Enum(treeUp, treeDown, treeBoth),
+		),
+		mcp.WithNumber("depth",
+			mcp.Min(1),
+			mcp.Max(MaxTreeDepth),
+			mcp.Description(fmt.Sprintf("How many levels of owned objects to show below the object. Default: %d. Range: [1, %d].", DefaultTreeDepth, MaxTreeDepth)),
+		),
+		mcp.WithString("childTypes",
+			mcp.Description("Comma-separated resource types to search for the children of custom resources, as resource or resource.group (e.g. 'certificates.cert-manager.io,secrets'). Built-in controllers always use their known child types."),
+		),
+	)
+	s.AddTool(mcp.NewTool("tree", treeOpts...), tools.WrapWithAuditLogging("tree", handleTree, sc))
+
+	// conditions tool
+	conditionsOpts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf(`Summarize the status conditions of every object of a type, such as the custom resources of an operator, without kind-specific knowledge. Each object's status.conditions are normalized to type, status (True, False or Unknown), reason, message and last transition, and counted per type and status.
+
+An object is unhealthy when a condition is Unknown, False for a positive type such as Ready or Available, or True for a negative type such as Stalled, Degraded, *Failed or *Pressure. Conditions computed for an older generation of the object are marked stale. Unhealthy objects come first. At most %d objects are scanned.`, samplePageSize*maxSamplePages)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	conditionsOpts = append(conditionsOpts, clusterContextParams...)
+	conditionsOpts = append(conditionsOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the objects. Uses 'default' if not specified; ignored for cluster-scoped resources."),
+		),
+		mcp.WithBoolean("allNamespaces",
+			mcp.Description("Summarize the objects of all namespaces. Overrides namespace."),
+		),
+		mcp.WithString("resourceType",
+			mcp.Required(),
+			mcp.Description("Type of Kubernetes resource (e.g., certificates, helmreleases, clusters)"),
+		),
+		mcp.WithString("apiGroup",
+			mcp.Description("Optional API group for the resource (e.g., 'cert-manager.io', 'helm.toolkit.fluxcd.io', or 'cluster.x-k8s.io/v1beta1')"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label selector limiting the objects summarized (optional)"),
+		),
+		mcp.WithString("conditionTypes",
+			mcp.Description("Comma-separated condition types to report, e.g. 'Ready,Stalled' (optional, default: all)"),
+		),
+		mcp.WithBoolean("problemsOnly",
+			mcp.Description("Return only unhealthy objects. The counts still cover every object (default: false)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Min(1),
+			mcp.Max(MaxConditionsLimit),
+			mcp.Description(fmt.Sprintf("Maximum number of objects to return. Default: %d. Maximum: %d. The counts always cover all objects.", DefaultConditionsLimit, MaxConditionsLimit)),
+		),
+	)
+	s.AddTool(mcp.NewTool("conditions", conditionsOpts...), tools.WrapWithAuditLogging("conditions", handleConditions, sc))
+
 	// create tool
 	createResourceOpts := []mcp.ToolOption{
 		mcp.WithDescription("Create a new Kubernetes resource from a manifest"),
@@ -271,9 +504,9 @@ For cluster-scoped resources (nodes, namespaces, PVs, clusterroles), this is ign
 			mcp.Required(),
 			mcp.Description("Namespace where the resource should be created"),
 		),
-		mcp.WithObject("manifest",
+		mcp.WithAny("manifest",
 			mcp.Required(),
-			mcp.Description("Kubernetes manifest as JSON object"),
+			mcp.Description("Kubernetes manifest as a JSON object, or as a YAML or JSON string. A string may hold several documents separated by '---'; they are handled in order and the first failure stops the rest."),
 		),
 	)
 	addMutatingTool(s, sc, "create", "create", handleCreateResource, createResourceOpts...)
@@ -293,13 +526,39 @@ For cluster-scoped resources (nodes, namespaces, PVs, clusterroles), this is ign
 			mcp.Required(),
 			mcp.Description("Namespace where the resource should be applied"),
 		),
-		mcp.WithObject("manifest",
+		mcp.WithAny("manifest",
 			mcp.Required(),
-			mcp.Description("Kubernetes manifest as JSON object"),
+			mcp.Description("Kubernetes manifest as a JSON object, or as a YAML or JSON string. A string may hold several documents separated by '---'; they are handled in order and the first failure stops the rest."),
 		),
 	)
 	addMutatingTool(s, sc, "apply", "apply", handleApplyResource, applyResourceOpts...)
 
+	// apply_all tool
+	applyAllOpts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf(`Apply a bundle of Kubernetes manifests in one call.
+
+The bundle is multi-document YAML or JSON (documents separated by '---'); List kinds are expanded. Every object is validated before anything is applied: if any object is invalid, nothing is applied. Objects are then applied in dependency order (namespaces, quotas, service accounts, config, CRDs and RBAC before services and workloads; custom resources last) and the outcome is reported per object. At most %d objects per call.`, MaxApplyAllObjects)),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	applyAllOpts = append(applyAllOpts, clusterContextParams...)
+	applyAllOpts = append(applyAllOpts,
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for namespaced objects that do not set metadata.namespace. Uses 'default' if not specified."),
+		),
+		mcp.WithString("manifests",
+			mcp.Required(),
+			mcp.Description("Multi-document YAML or JSON bundle of Kubernetes manifests"),
+		),
+		mcp.WithBoolean("stopOnError",
+			mcp.Description("Skip the remaining objects after the first failure (default: false, apply every object)"),
+		),
+	)
+	addMutatingTool(s, sc, "apply", "apply_all", handleApplyAll, applyAllOpts...)
+
 	// delete tool
 	deleteResourceOpts := []mcp.ToolOption{
 		mcp.WithDescription(`Delete a Kubernetes resource.
@@ -334,6 +593,58 @@ For cluster-scoped resources (nodes, namespaces, PVs, clusterroles), this is ign
 	)
 	addMutatingTool(s, sc, "delete", "delete", handleDeleteResource, deleteResourceOpts...)
 
+	// create_namespace tool
+	createNamespaceOpts := []mcp.ToolOption{
+		mcp.WithDescription("Create a namespace with optional labels and annotations"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	createNamespaceOpts = append(createNamespaceOpts, clusterContextParams...)
+	createNamespaceOpts = append(createNamespaceOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Name of the namespace to create (a DNS-1123 label)"),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("Labels to set, as an object of strings, e.g. {\"team\": \"payments\"} (optional)"),
+		),
+		mcp.WithObject("annotations",
+			mcp.Description("Annotations to set, as an object of strings (optional)"),
+		),
+	)
+	addMutatingTool(s, sc, "create", "create_namespace", handleCreateNamespace, createNamespaceOpts...)
+
+	// delete_namespace tool
+	deleteNamespaceOpts := []mcp.ToolOption{
+		mcp.WithDescription(`Delete a namespace and every object in it.
+
+Safeguards:
+- confirm must repeat the namespace name exactly
+- System namespaces (default, kube-system, kube-public, kube-node-lease) and the server's restricted namespaces are refused
+
+Deletion is asynchronous: the namespace stays Terminating until its contents are gone.`),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	}
+	deleteNamespaceOpts = append(deleteNamespaceOpts, clusterContextParams...)
+	deleteNamespaceOpts = append(deleteNamespaceOpts,
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Name of the namespace to delete"),
+		),
+		mcp.WithString("confirm",
+			mcp.Required(),
+			mcp.Description("The namespace name again, to confirm the deletion"),
+		),
+	)
+	addMutatingTool(s, sc, "delete", "delete_namespace", handleDeleteNamespace, deleteNamespaceOpts...)
+
 	// patch tool
 	patchResourceOpts := []mcp.ToolOption{
 		mcp.WithDescription(`Patch a Kubernetes resource with specific changes.
@@ -370,13 +681,69 @@ For cluster-scoped resources (nodes, namespaces, PVs, clusterroles), this is ign
 			mcp.Description("Type of patch (strategic, merge, json)"),
 			mcp.Enum("strategic", "merge", "json"),
 		),
-		mcp.WithObject("patch",
+		mcp.WithAny("patch",
 			mcp.Required(),
-			mcp.Description("Patch data as JSON object"),
+			mcp.Description("Patch data as a JSON object (a JSON array for json patches), or as a YAML or JSON string"),
 		),
 	)
 	addMutatingTool(s, sc, "patch", "patch", handlePatchResource, patchResourceOpts...)
 
+	// label and annotate tools
+	for _, t := range []struct {
+		name, field, title, example string
+		handler                     tools.ToolHandler
+	}{
+		{"label", "labels", "Labels", `{"team": "payments"}`, handleLabel},
+		{"annotate", "annotations", "Annotations", `{"example.com/owner": "alice"}`, handleAnnotate},
+	} {
+		opts := []mcp.ToolOption{
+			mcp.WithDescription(fmt.Sprintf(`Add, change or remove %[1]s on one object (name) or on every object matching a label selector, like kubectl %[2]s. Reports the outcome per object: updated, unchanged, planned (preview) or failed.
+
+An existing key with a different value is only replaced when overwrite is true; otherwise that object fails and is left alone. Set preview to see what would change without changing anything. At most %[3]d objects per call.`, t.field, t.name, MaxMetadataEditObjects)),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+		}
+		opts = append(opts, clusterContextParams...)
+		opts = append(opts,
+			mcp.WithString("namespace",
+				mcp.Description("Namespace of the objects. Uses 'default' if not specified; ignored for cluster-scoped resources."),
+			),
+			mcp.WithBoolean("allNamespaces",
+				mcp.Description("Match objects in all namespaces (labelSelector only)"),
+			),
+			mcp.WithString("resourceType",
+				mcp.Required(),
+				mcp.Description("Type of Kubernetes resource (e.g., pod, service, deployment)"),
+			),
+			mcp.WithString("apiGroup",
+				mcp.Description("Optional API group for the resource (e.g., 'apps', 'networking.k8s.io', or 'apps/v1')"),
+			),
+			mcp.WithString("name",
+				mcp.Description("Name of a single object to edit. Either name or labelSelector is required."),
+			),
+			mcp.WithString("labelSelector",
+				mcp.Description("Edit every object matching this label selector (e.g., 'app=nginx'). Either name or labelSelector is required."),
+			),
+			mcp.WithObject("set",
+				mcp.Description(fmt.Sprintf("%s to add or change, as an object of strings, e.g. %s", t.title, t.example)),
+			),
+			mcp.WithArray("remove",
+				mcp.Description(fmt.Sprintf("Keys of %s to remove", t.field)),
+				mcp.WithStringItems(),
+			),
+			mcp.WithBoolean("overwrite",
+				mcp.Description("Replace keys that already have a different value (default: false)"),
+			),
+			mcp.WithBoolean("preview",
+				mcp.Description("Report what would change without changing anything (default: false)"),
+			),
+		)
+		addMutatingTool(s, sc, "patch", t.name, t.handler, opts...)
+	}
+
 	// scale tool
 	scaleResourceOpts := []mcp.ToolOption{
 		mcp.WithDescription("Scale a Kubernetes resource (deployment, replicaset, etc.)"),
@@ -410,6 +777,9 @@ For cluster-scoped resources (nodes, namespaces, PVs, clusterroles), this is ign
 	)
 	addMutatingTool(s, sc, "scale", "scale", handleScaleResource, scaleResourceOpts...)
 
+	// plan tools (only registered when plans are enabled)
+	registerPlanTools(s, sc)
+
 	return nil
 }
 
@@ -429,6 +799,7 @@ func addMutatingTool(
 	if !tools.IsMutatingOperationAllowed(sc, op) {
 		return
 	}
+	opts = append(opts, tools.DryRunParam())
 	s.AddTool(mcp.NewTool(name, opts...), tools.WrapWithAuditLogging(name, handler, sc))
 	tools.MaybeAddDeprecatedAlias(s, sc, name, handler, opts...)
 }
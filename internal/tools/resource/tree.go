@@ -0,0 +1,408 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// Default and maximum values for the tree tool's depth param, and the most
+// nodes one tree holds.
+const (
+	DefaultTreeDepth = 3
+	MaxTreeDepth     = 10
+	MaxTreeNodes     = 200
+)
+
+// Directions the tree tool walks ownerReferences in.
+const (
+	treeDown = "down"
+	treeUp   = "up"
+	treeBoth = "both"
+)
+
+// treeMissing is the status of an owner that no longer exists.
+const treeMissing = "Missing"
+
+// treeChildType is a resource type searched for the children of an object.
+type treeChildType struct {
+	resource string
+	apiGroup string
+	kind     string
+}
+
+// treeChildTypes are the types holding the children of the built-in
+// controllers. Kinds mapped to nothing never own anything worth showing.
+var treeChildTypes = map[string][]treeChildType{
+	"Deployment":            {{"replicasets", "apps", "ReplicaSet"}},
+	"ReplicaSet":            {{"pods", "", "Pod"}},
+	"StatefulSet":           {{"pods", "", "Pod"}, {"persistentvolumeclaims", "", "PersistentVolumeClaim"}},
+	"DaemonSet":             {{"pods", "", "Pod"}},
+	"Job":                   {{"pods", "", "Pod"}},
+	"CronJob":               {{"jobs", "batch", "Job"}},
+	"Service":               {{"endpointslices", "discovery.k8s.io", "EndpointSlice"}},
+	"Pod":                   nil,
+	"EndpointSlice":         nil,
+	"ConfigMap":             nil,
+	"Secret":                nil,
+	"PersistentVolumeClaim": nil,
+}
+
+// defaultTreeChildTypes are searched for the children of other kinds, such
+// as custom resources managed by an operator.
+var defaultTreeChildTypes = []treeChildType{
+	{"deployments", "apps", "Deployment"},
+	{"statefulsets", "apps", "StatefulSet"},
+	{"daemonsets", "apps", "DaemonSet"},
+	{"jobs", "batch", "Job"},
+	{"cronjobs", "batch", "CronJob"},
+	{"services", "", "Service"},
+	{"configmaps", "", "ConfigMap"},
+}
+
+// TreeResult is the output of the tree tool.
+type TreeResult struct {
+	// Root is the topmost owner found, or the requested object when owners
+	// were not followed or it has none.
+	Root *TreeNode `json:"root"`
+
+	// Target is the requested object as Kind/name.
+	Target    string `json:"target"`
+	Namespace string `json:"namespace,omitempty"`
+	Nodes     int    `json:"nodes"`
+
+	// Truncated is set when the tree stopped growing at MaxTreeNodes.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Skipped lists the types that could not be searched for children and
+	// the owners that could not be read.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// TreeNode is an object in the tree. Owners are above the requested object
+// and hold only the path to it; its own children are below it.
+type TreeNode struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Namespace is set when it differs from the requested object's.
+	Namespace string `json:"namespace,omitempty"`
+
+	output.ObjectState
+	Age string `json:"age,omitempty"`
+
+	// Target marks the requested object.
+	Target   bool        `json:"target,omitempty"`
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// treeRequest holds the validated tree arguments.
+type treeRequest struct {
+	cluster      string
+	kubeContext  string
+	namespace    string
+	resourceType string
+	apiGroup     string
+	name         string
+	direction    string
+	depth        int
+	childTypes   []treeChildType
+}
+
+// handleTree walks the ownerReferences of an object up to its topmost
+// controller and down to everything it owns, like kubectl tree.
+func handleTree(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseTreeRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	resp, err := client.K8s().Get(ctx, req.kubeContext, req.namespace, req.resourceType, req.apiGroup, req.name)
+	if err != nil {
+		return tools.K8sError("Failed to get resource", err, client.User()).Result(), nil
+	}
+	target, err := toUnstructuredObject(resp.Resource)
+	if err != nil {
+		return toolerrors.Internalf("Failed to read resource: %v", err).Result(), nil
+	}
+
+	b := &treeBuilder{
+		client:  client.K8s(),
+		req:     req,
+		lists:   map[string][]*unstructured.Unstructured{},
+		visited: map[types.UID]bool{},
+		result:  &TreeResult{Namespace: target.GetNamespace()},
+	}
+	jsonData, err := json.MarshalIndent(b.build(ctx, target), "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseTreeRequest validates the tree arguments. resourceType and name are
+// required, direction must be down, up or both, and depth must lie within 1
+// and MaxTreeDepth.
+func parseTreeRequest(args map[string]interface{}) (treeRequest, string) {
+	req := treeRequest{
+		cluster:   tools.ExtractClusterParam(args),
+		direction: treeBoth,
+		depth:     DefaultTreeDepth,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.apiGroup, _ = args["apiGroup"].(string)
+	req.resourceType, _ = args["resourceType"].(string)
+	if req.resourceType == "" {
+		return req, "resourceType is required"
+	}
+	req.name, _ = args["name"].(string)
+	if req.name == "" {
+		return req, "name is required"
+	}
+	req.namespace, _ = args["namespace"].(string)
+	if req.namespace == "" {
+		req.namespace = k8s.DefaultNamespace
+	}
+
+	if v, ok := args["direction"].(string); ok && v != "" {
+		switch v {
+		case treeDown, treeUp, treeBoth:
+			req.direction = v
+		default:
+			return req, fmt.Sprintf("direction must be one of %s, %s or %s", treeDown, treeUp, treeBoth)
+		}
+	}
+	if v, ok := args["depth"].(float64); ok {
+		if v < 1 || v > MaxTreeDepth {
+			return req, fmt.Sprintf("depth must be between 1 and %d", MaxTreeDepth)
+		}
+		req.depth = int(v)
+	}
+
+	if v, _ := args["childTypes"].(string); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			resource, group, _ := strings.Cut(t, ".")
+			req.childTypes = append(req.childTypes, treeChildType{resource: strings.ToLower(resource), apiGroup: group})
+		}
+	}
+	return req, ""
+}
+
+// treeBuilder grows a tree around one object, listing each child type at
+// most once per namespace.
+type treeBuilder struct {
+	client  k8s.Client
+	req     treeRequest
+	lists   map[string][]*unstructured.Unstructured
+	visited map[types.UID]bool
+	result  *TreeResult
+}
+
+func (b *treeBuilder) build(ctx context.Context, target *unstructured.Unstructured) *TreeResult {
+	node := b.node(target, "")
+	node.Target = true
+	b.result.Target = node.Kind + "/" + node.Name
+	b.visited[target.GetUID()] = true
+
+	if b.req.direction != treeUp {
+		b.addChildren(ctx, node, target, 1)
+	}
+	b.result.Root = node
+	if b.req.direction != treeDown {
+		b.result.Root = b.addOwners(ctx, node, target)
+	}
+	return b.result
+}
+
+// node returns the tree node of obj. kind is used when obj carries none,
+// as typed objects decoded from protobuf do not.
+func (b *treeBuilder) node(obj *unstructured.Unstructured, kind string) *TreeNode {
+	b.result.Nodes++
+	if obj.GetKind() == "" && kind != "" {
+		obj.SetKind(kind)
+	}
+	node := &TreeNode{
+		Kind:        obj.GetKind(),
+		Name:        obj.GetName(),
+		ObjectState: output.ObjectStatus(obj.Object),
+	}
+	if ns := obj.GetNamespace(); ns != b.result.Namespace {
+		node.Namespace = ns
+	}
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		node.Age = formatAge(created.Time)
+	}
+	return node
+}
+
+// addOwners walks up from obj through its controller, or its first owner
+// when it has no controller, and returns the topmost node.
+func (b *treeBuilder) addOwners(ctx context.Context, node *TreeNode, obj *unstructured.Unstructured) *TreeNode {
+	for range MaxTreeDepth {
+		ref := ownerToFollow(obj.GetOwnerReferences())
+		if ref == nil {
+			return node
+		}
+
+		apiGroup := ""
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group != "" {
+			apiGroup = ref.APIVersion
+		}
+		resp, err := b.client.Get(ctx, b.req.kubeContext, obj.GetNamespace(), ref.Kind, apiGroup, ref.Name)
+		var owner *unstructured.Unstructured
+		if err == nil {
+			owner, err = toUnstructuredObject(resp.Resource)
+		}
+		switch {
+		case apierrors.IsNotFound(err) || (err == nil && owner.GetUID() != ref.UID):
+			// The owner is gone, or was replaced by a new object of the
+			// same name; the garbage collector will delete obj.
+			b.result.Nodes++
+			return &TreeNode{Kind: ref.Kind, Name: ref.Name, ObjectState: output.ObjectState{Status: treeMissing}, Children: []*TreeNode{node}}
+		case err != nil:
+			b.result.Skipped = append(b.result.Skipped, fmt.Sprintf("owner %s/%s: %v", ref.Kind, ref.Name, err))
+			return node
+		case b.visited[owner.GetUID()]:
+			return node
+		}
+
+		b.visited[owner.GetUID()] = true
+		parent := b.node(owner, ref.Kind)
+		parent.Children = []*TreeNode{node}
+		node, obj = parent, owner
+	}
+	return node
+}
+
+// ownerToFollow returns the controller among refs, or the first owner when
+// none is the controller.
+func ownerToFollow(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
+
+// addChildren adds the objects owned by obj below node, recursing until
+// depth exceeds the requested depth.
+func (b *treeBuilder) addChildren(ctx context.Context, node *TreeNode, obj *unstructured.Unstructured, depth int) {
+	if depth > b.req.depth {
+		return
+	}
+	childTypes, known := treeChildTypes[obj.GetKind()]
+	if !known {
+		childTypes = defaultTreeChildTypes
+		if len(b.req.childTypes) > 0 {
+			childTypes = b.req.childTypes
+		}
+	}
+
+	type child struct {
+		obj  *unstructured.Unstructured
+		node *TreeNode
+	}
+	var children []child
+	for _, t := range childTypes {
+		items, ok := b.list(ctx, t, obj.GetNamespace())
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			if b.visited[item.GetUID()] || !ownedBy(item, obj.GetUID()) {
+				continue
+			}
+			if b.result.Nodes >= MaxTreeNodes {
+				b.result.Truncated = true
+				break
+			}
+			b.visited[item.GetUID()] = true
+			children = append(children, child{item, b.node(item, t.kind)})
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].node.Kind != children[j].node.Kind {
+			return children[i].node.Kind < children[j].node.Kind
+		}
+		return children[i].node.Name < children[j].node.Name
+	})
+	for _, c := range children {
+		node.Children = append(node.Children, c.node)
+		b.addChildren(ctx, c.node, c.obj, depth+1)
+	}
+}
+
+// ownedBy reports whether obj has an ownerReference to uid.
+func ownedBy(obj *unstructured.Unstructured, uid types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns every object of t in namespace, or in all namespaces for
+// the children of a cluster-scoped object. A type that cannot be listed is
+// recorded in Skipped once and reported as not ok.
+func (b *treeBuilder) list(ctx context.Context, t treeChildType, namespace string) ([]*unstructured.Unstructured, bool) {
+	key := t.resource + "." + t.apiGroup + "/" + namespace
+	if items, seen := b.lists[key]; seen {
+		return items, items != nil
+	}
+
+	opts := k8s.ListOptions{AllNamespaces: namespace == ""}
+	items := []*unstructured.Unstructured{}
+	for {
+		resp, err := b.client.List(ctx, b.req.kubeContext, namespace, t.resource, t.apiGroup, opts)
+		if err == nil {
+			for _, obj := range resp.Items {
+				var u *unstructured.Unstructured
+				if u, err = toUnstructuredObject(obj); err != nil {
+					break
+				}
+				items = append(items, u)
+			}
+		}
+		if err != nil {
+			b.lists[key] = nil
+			b.result.Skipped = append(b.result.Skipped, fmt.Sprintf("%s: %v", t.resource, err))
+			return nil, false
+		}
+		if resp.Continue == "" {
+			break
+		}
+		opts.Continue = resp.Continue
+	}
+	b.lists[key] = items
+	return items, true
+}
@@ -0,0 +1,190 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/mcptest"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+func treeOwner(kind, name string, uid types.UID) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: name, UID: uid, Controller: &controller}}
+}
+
+// newTreeClient serves a Deployment with an old, scaled-down ReplicaSet and
+// a current one running a healthy and a crashing pod, and a pod whose
+// ReplicaSet is gone.
+func newTreeClient() *mcptest.FakeClient {
+	three, zero := int32(3), int32(0)
+	meta := func(name string, uid types.UID, owners []metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: "shop", Name: name, UID: uid, OwnerReferences: owners}
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta("web", "d1", nil),
+		Spec:       appsv1.DeploymentSpec{Replicas: &three},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1, AvailableReplicas: 1},
+	}
+	current := &appsv1.ReplicaSet{
+		ObjectMeta: meta("web-new", "r1", treeOwner("Deployment", "web", "d1")),
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &three},
+		Status:     appsv1.ReplicaSetStatus{ReadyReplicas: 1, AvailableReplicas: 1},
+	}
+	old := &appsv1.ReplicaSet{
+		ObjectMeta: meta("web-old", "r0", treeOwner("Deployment", "web", "d1")),
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &zero},
+	}
+	running := &corev1.Pod{
+		ObjectMeta: meta("web-new-a", "p1", treeOwner("ReplicaSet", "web-new", "r1")),
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true}},
+		},
+	}
+	crashing := &corev1.Pod{
+		ObjectMeta: meta("web-new-b", "p2", treeOwner("ReplicaSet", "web-new", "r1")),
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "web",
+				RestartCount: 12,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	}
+	stray := &corev1.Pod{ObjectMeta: meta("other", "p3", treeOwner("ReplicaSet", "api", "r9"))}
+
+	return mcptest.NewFakeClient(map[string][]runtime.Object{
+		"deployments": {deployment},
+		"replicasets": {current, old},
+		"pods":        {crashing, running, stray},
+	})
+}
+
+func callTree(t *testing.T, client *mcptest.FakeClient, args map[string]interface{}) TreeResult {
+	t.Helper()
+	return mcptest.DecodeResult[TreeResult](t, callResourceTool(t, client, "tree", args))
+}
+
+// listedTypes returns the resource types client listed
+// with the number of List calls made for each.
+func listedTypes(client *mcptest.FakeClient) map[string]int {
+	counts := map[string]int{}
+	for resourceType, opts := range client.ListOpts {
+		counts[resourceType] = len(opts)
+	}
+	return counts
+}
+
+func TestHandleTree_Down(t *testing.T) {
+	client := newTreeClient()
+	out := callTree(t, client, map[string]interface{}{"resourceType": "deployment", "namespace": "shop", "name": "web"})
+
+	assert.Equal(t, "Deployment/web", out.Target)
+	assert.Equal(t, "shop", out.Namespace)
+	assert.Equal(t, 5, out.Nodes)
+	assert.Empty(t, out.Skipped)
+
+	root := out.Root
+	assert.True(t, root.Target)
+	assert.Equal(t, "Partially Ready", root.Status)
+	assert.Equal(t, "1/3", root.Ready)
+	require.Len(t, root.Children, 2)
+
+	assert.Equal(t, "web-new", root.Children[0].Name)
+	assert.Equal(t, "ReplicaSet", root.Children[0].Kind)
+	assert.Equal(t, "web-old", root.Children[1].Name)
+	assert.Equal(t, "Scaled to Zero", root.Children[1].Status)
+	assert.Empty(t, root.Children[1].Children)
+
+	pods := root.Children[0].Children
+	require.Len(t, pods, 2)
+	assert.Equal(t, TreeNode{Kind: "Pod", Name: "web-new-a", ObjectState: output.ObjectState{Status: "Running", Ready: "1/1"}}, *pods[0])
+	assert.Equal(t, "CrashLoopBackOff", pods[1].Status)
+	assert.Equal(t, 12, pods[1].Restarts)
+
+	assert.Equal(t, map[string]int{"replicasets": 1, "pods": 1}, listedTypes(client), "pods are listed once for both ReplicaSets")
+}
+
+func TestHandleTree_Up(t *testing.T) {
+	out := callTree(t, newTreeClient(), map[string]interface{}{"resourceType": "pod", "namespace": "shop", "name": "web-new-b", "direction": "up"})
+
+	assert.Equal(t, "Pod/web-new-b", out.Target)
+	assert.Equal(t, 3, out.Nodes)
+	assert.Equal(t, "Deployment", out.Root.Kind)
+	assert.False(t, out.Root.Target)
+	require.Len(t, out.Root.Children, 1)
+	rs := out.Root.Children[0]
+	assert.Equal(t, "web-new", rs.Name)
+	require.Len(t, rs.Children, 1)
+	assert.True(t, rs.Children[0].Target)
+	assert.Equal(t, "CrashLoopBackOff", rs.Children[0].Status)
+}
+
+func TestHandleTree_MissingOwner(t *testing.T) {
+	out := callTree(t, newTreeClient(), map[string]interface{}{"resourceType": "pod", "namespace": "shop", "name": "other"})
+
+	assert.Equal(t, "ReplicaSet", out.Root.Kind)
+	assert.Equal(t, "api", out.Root.Name)
+	assert.Equal(t, treeMissing, out.Root.Status)
+	require.Len(t, out.Root.Children, 1)
+	assert.True(t, out.Root.Children[0].Target)
+}
+
+func TestHandleTree_DepthAndSkipped(t *testing.T) {
+	client := newTreeClient()
+	out := callTree(t, client, map[string]interface{}{"resourceType": "deployment", "namespace": "shop", "name": "web", "depth": float64(1)})
+	assert.Equal(t, 3, out.Nodes)
+	assert.Empty(t, out.Root.Children[0].Children)
+	assert.Equal(t, map[string]int{"replicasets": 1}, listedTypes(client))
+
+	client = newTreeClient()
+	client.Errs["pods"] = apierrors.NewForbidden(corev1.Resource("pods"), "", nil)
+	out = callTree(t, client, map[string]interface{}{"resourceType": "deployment", "namespace": "shop", "name": "web"})
+	assert.Equal(t, 3, out.Nodes)
+	require.Len(t, out.Skipped, 1)
+	assert.Contains(t, out.Skipped[0], "pods:")
+}
+
+func TestParseTreeRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{name: "missing name", args: map[string]interface{}{"resourceType": "pod"}, wantErr: "name is required"},
+		{name: "bad direction", args: map[string]interface{}{"resourceType": "pod", "name": "x", "direction": "sideways"}, wantErr: "direction must be"},
+		{name: "depth too large", args: map[string]interface{}{"resourceType": "pod", "name": "x", "depth": float64(MaxTreeDepth + 1)}, wantErr: "depth must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errMsg := parseTreeRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+
+	req, errMsg := parseTreeRequest(map[string]interface{}{
+		"resourceType": "helmrelease",
+		"name":         "web",
+		"childTypes":   "certificates.cert-manager.io, Secrets",
+	})
+	require.Empty(t, errMsg)
+	assert.Equal(t, k8s.DefaultNamespace, req.namespace)
+	assert.Equal(t, []treeChildType{
+		{resource: "certificates", apiGroup: "cert-manager.io"},
+		{resource: "secrets"},
+	}, req.childTypes)
+}
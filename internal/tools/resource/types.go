@@ -8,6 +8,8 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
 )
 
 // eventMessageMaxRunes caps event.message length in summary mode. Empirically
@@ -54,6 +56,23 @@ type PaginatedSummaryResponse struct {
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// PaginatedTableResponse is a page of resources rendered as a table of
+// compact rows (output=table)
+type PaginatedTableResponse struct {
+	*output.Table
+	TableEnvelope
+}
+
+// TableEnvelope is the part of a table response besides the table. With
+// format markdown or csv it follows the rendered table as JSON.
+type TableEnvelope struct {
+	Continue        string                 `json:"continue,omitempty"`
+	RemainingItems  *int64                 `json:"remainingItems,omitempty"`
+	ResourceVersion string                 `json:"resourceVersion,omitempty"`
+	TotalItems      int                    `json:"totalItems"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // SummarizeResources converts a list of runtime.Objects to compact ResourceSummary objects
 func SummarizeResources(objects []runtime.Object, includeLabels, includeAnnotations bool) *ListSummaryResponse {
 	if len(objects) == 0 {
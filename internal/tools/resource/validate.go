@@ -0,0 +1,127 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// MaxValidateObjects limits how many objects one validate call may check.
+const MaxValidateObjects = 50
+
+// ValidateResult is the output of the validate tool.
+type ValidateResult struct {
+	// Valid is true when the API server accepted every object.
+	Valid   bool             `json:"valid"`
+	Objects []ValidateObject `json:"objects"`
+}
+
+// ValidateObject is the outcome for one object, in manifest order.
+type ValidateObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	// Operation is the dry-run operation performed: create or update.
+	Operation string   `json:"operation,omitempty"`
+	Valid     bool     `json:"valid"`
+	Error     string   `json:"error,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// handleValidate validates manifests with a server-side dry-run.
+func handleValidate(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	clusterName := tools.ExtractClusterParam(args)
+	kubeContext := request.GetString("kubeContext", "")
+	namespace := request.GetString("namespace", "")
+	if namespace == "" {
+		namespace = k8s.DefaultNamespace
+	}
+
+	fieldValidation := request.GetString("fieldValidation", k8s.FieldValidationStrict)
+	switch fieldValidation {
+	case k8s.FieldValidationStrict, k8s.FieldValidationWarn, k8s.FieldValidationIgnore:
+	default:
+		return toolerrors.InvalidArgumentf("Invalid fieldValidation %q. Must be one of: Strict, Warn, Ignore", fieldValidation).Result(), nil
+	}
+
+	manifestData, ok := args["manifest"]
+	if !ok || manifestData == nil {
+		return toolerrors.Required("manifest").Result(), nil
+	}
+	objects, err := parseManifestArg(manifestData)
+	if err != nil {
+		return toolerrors.InvalidArgumentf("Failed to parse manifest: %v", err).Result(), nil
+	}
+	if len(objects) > MaxValidateObjects {
+		return toolerrors.InvalidArgumentf("manifest contains %d objects; at most %d can be validated at once", len(objects), MaxValidateObjects).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, clusterName)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+	k8sClient := client.K8s()
+
+	result := &ValidateResult{Valid: true, Objects: make([]ValidateObject, 0, len(objects))}
+	for _, obj := range objects {
+		out := ValidateObject{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		}
+		objNamespace := out.Namespace
+		if objNamespace == "" {
+			objNamespace = namespace
+			if scoped, _ := tools.ObjectClusterScoped(ctx, sc, args, obj); !scoped {
+				out.Namespace = namespace
+			}
+		}
+
+		start := time.Now()
+		resp, err := k8sClient.Validate(ctx, kubeContext, objNamespace, obj, k8s.ValidateOptions{FieldValidation: fieldValidation})
+		duration := time.Since(start)
+		if resp != nil {
+			out.Operation = resp.Operation
+			out.Warnings = resp.Warnings
+			if name := resourceName(resp); name != "" {
+				out.Name = name
+			}
+		}
+		if err != nil {
+			recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationValidate, out.Kind, objNamespace, instrumentation.StatusError, duration)
+			out.Error = tools.FormatK8sError("Validation failed", err, client.User())
+			result.Valid = false
+		} else {
+			recordK8sOperation(ctx, sc, clusterName, instrumentation.OperationValidate, out.Kind, objNamespace, instrumentation.StatusSuccess, duration)
+			out.Valid = true
+		}
+		result.Objects = append(result.Objects, out)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// resourceName returns the name the API server gave the validated object,
+// which differs from the manifest for objects using generateName.
+func resourceName(resp *k8s.ValidateResponse) string {
+	if u, ok := resp.Resource.(*unstructured.Unstructured); ok {
+		return u.GetName()
+	}
+	return ""
+}
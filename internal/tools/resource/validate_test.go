@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// validateMock rejects objects named "bad" and warns about every Ingress.
+type validateMock struct {
+	*testdata.MockK8sClient
+
+	namespaces []string
+	opts       k8s.ValidateOptions
+}
+
+func (m *validateMock) Validate(_ context.Context, _, namespace string, obj runtime.Object, opts k8s.ValidateOptions) (*k8s.ValidateResponse, error) {
+	m.namespaces = append(m.namespaces, namespace)
+	m.opts = opts
+	u := obj.(*unstructured.Unstructured)
+	resp := &k8s.ValidateResponse{Operation: "create", Resource: u}
+	if u.GetKind() == "Ingress" {
+		resp.Warnings = []string{"networking.k8s.io/v1beta1 Ingress is deprecated"}
+	}
+	if u.GetName() == "bad" {
+		return resp, errors.New(`admission webhook "policy.example.com" denied the request`)
+	}
+	return resp, nil
+}
+
+func callValidate(t *testing.T, mock *validateMock, args map[string]interface{}) (*mcp.CallToolResult, ValidateResult) {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handleValidate(context.Background(), request, sc)
+	require.NoError(t, err)
+
+	var out ValidateResult
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	}
+	return result, out
+}
+
+func TestValidate_ReportsPerObject(t *testing.T) {
+	mock := &validateMock{MockK8sClient: &testdata.MockK8sClient{}}
+
+	result, out := callValidate(t, mock, map[string]interface{}{
+		"namespace": "shop",
+		"manifest": `apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bad
+  namespace: other
+`,
+	})
+	require.False(t, result.IsError, "a rejected object is a result, not an error")
+	assert.False(t, out.Valid)
+	require.Len(t, out.Objects, 2)
+
+	assert.True(t, out.Objects[0].Valid)
+	assert.Equal(t, "shop", out.Objects[0].Namespace)
+	assert.Equal(t, "create", out.Objects[0].Operation)
+	assert.Equal(t, []string{"networking.k8s.io/v1beta1 Ingress is deprecated"}, out.Objects[0].Warnings)
+
+	assert.False(t, out.Objects[1].Valid)
+	assert.Contains(t, out.Objects[1].Error, "denied the request")
+	assert.Equal(t, []string{"shop", "other"}, mock.namespaces)
+	assert.Equal(t, k8s.FieldValidationStrict, mock.opts.FieldValidation)
+}
+
+func TestValidate_AllowedInNonDestructiveMode(t *testing.T) {
+	mock := &validateMock{MockK8sClient: &testdata.MockK8sClient{}}
+
+	result, out := callValidate(t, mock, map[string]interface{}{
+		"manifest": map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": "shop"},
+		},
+		"fieldValidation": "Warn",
+	})
+	require.False(t, result.IsError)
+	assert.True(t, out.Valid)
+	assert.Empty(t, out.Objects[0].Namespace, "cluster-scoped objects have no namespace")
+	assert.Equal(t, k8s.FieldValidationWarn, mock.opts.FieldValidation)
+}
+
+func TestValidate_RejectsInvalidFieldValidation(t *testing.T) {
+	result, _ := callValidate(t, &validateMock{MockK8sClient: &testdata.MockK8sClient{}}, map[string]interface{}{
+		"manifest":        map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "a"}},
+		"fieldValidation": "strict",
+	})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,510 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+const (
+	// DefaultWaitTimeoutSeconds is how long wait blocks when no timeout is given.
+	DefaultWaitTimeoutSeconds = 20
+
+	// MaxWaitTimeoutSeconds keeps a wait below the server's 30-second tool
+	// call timeout. Callers wait longer by calling wait again.
+	MaxWaitTimeoutSeconds = 25
+)
+
+// waitPollInterval is used instead of a watch when watching fails.
+var waitPollInterval = 2 * time.Second
+
+// Wait condition kinds, as in kubectl wait --for.
+const (
+	waitForCondition = "condition"
+	waitForJSONPath  = "jsonpath"
+	waitForDelete    = "delete"
+	waitForCreate    = "create"
+)
+
+// WaitResult is the output of the wait tool.
+type WaitResult struct {
+	For string `json:"for"`
+	Met bool   `json:"met"`
+	// TimedOut is true when the timeout passed before the condition was met.
+	TimedOut bool `json:"timedOut,omitempty"`
+	// Method is "watch", or "poll" when the watch could not be started.
+	Method  string       `json:"method"`
+	Elapsed string       `json:"elapsed"`
+	Objects []WaitObject `json:"objects"`
+	Message string       `json:"message,omitempty"`
+}
+
+// WaitObject reports the last observed state of one matched resource.
+type WaitObject struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Met       bool   `json:"met"`
+	// Observed is the condition or JSONPath value last seen.
+	Observed string `json:"observed,omitempty"`
+}
+
+// waitCondition is a parsed --for expression.
+type waitCondition struct {
+	raw  string
+	kind string
+
+	// condition kind
+	conditionType  string
+	conditionValue string
+
+	// jsonpath kind
+	path          *jsonpath.JSONPath
+	expectedValue string
+	hasValue      bool
+}
+
+// waitRequest holds the validated wait arguments.
+type waitRequest struct {
+	cluster       string
+	kubeContext   string
+	namespace     string
+	resourceType  string
+	apiGroup      string
+	name          string
+	labelSelector string
+	condition     waitCondition
+	timeout       time.Duration
+}
+
+// parseWaitCondition parses a kubectl wait --for expression:
+// condition=Type[=Value], jsonpath={path}[=value], delete or create.
+func parseWaitCondition(s string) (waitCondition, error) {
+	c := waitCondition{raw: s}
+	switch {
+	case strings.EqualFold(s, waitForDelete):
+		c.kind = waitForDelete
+	case strings.EqualFold(s, waitForCreate):
+		c.kind = waitForCreate
+	case strings.HasPrefix(s, waitForCondition+"="):
+		c.kind = waitForCondition
+		spec := strings.TrimPrefix(s, waitForCondition+"=")
+		c.conditionType, c.conditionValue, _ = strings.Cut(spec, "=")
+		if c.conditionType == "" {
+			return c, fmt.Errorf("condition type is missing in %q", s)
+		}
+		if c.conditionValue == "" {
+			c.conditionValue = "True"
+		}
+	case strings.HasPrefix(s, waitForJSONPath+"="):
+		c.kind = waitForJSONPath
+		spec := strings.TrimPrefix(s, waitForJSONPath+"=")
+		expr := spec
+		if end := strings.LastIndex(spec, "}"); strings.HasPrefix(spec, "{") && end > 0 {
+			expr = spec[:end+1]
+			if rest := spec[end+1:]; rest != "" {
+				if !strings.HasPrefix(rest, "=") {
+					return c, fmt.Errorf("expected '=' after the JSONPath expression in %q", s)
+				}
+				c.expectedValue, c.hasValue = rest[1:], true
+			}
+		} else {
+			var value string
+			expr, value, c.hasValue = strings.Cut(spec, "=")
+			c.expectedValue = value
+			expr = "{" + expr + "}"
+		}
+		c.path = jsonpath.New("wait").AllowMissingKeys(true)
+		if err := c.path.Parse(expr); err != nil {
+			return c, fmt.Errorf("invalid JSONPath expression %q: %w", expr, err)
+		}
+	default:
+		return c, fmt.Errorf("unsupported condition %q: use condition=Type[=Value], jsonpath={path}[=value], delete or create", s)
+	}
+	return c, nil
+}
+
+// check reports whether obj satisfies a condition or JSONPath wait, and what
+// was observed.
+func (c waitCondition) check(obj *unstructured.Unstructured) (bool, string) {
+	switch c.kind {
+	case waitForCondition:
+		return c.checkCondition(obj)
+	case waitForJSONPath:
+		return c.checkJSONPath(obj)
+	}
+	return true, ""
+}
+
+func (c waitCondition) checkCondition(obj *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		cond, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		if !strings.EqualFold(condType, c.conditionType) {
+			continue
+		}
+		status, _ := cond["status"].(string)
+		observed := condType + "=" + status
+		if reason, _ := cond["reason"].(string); reason != "" {
+			observed += " (" + reason + ")"
+		}
+		// A condition computed for an older generation says nothing about
+		// the current spec.
+		if gen, ok := cond["observedGeneration"].(int64); ok && gen < obj.GetGeneration() {
+			return false, fmt.Sprintf("%s, stale: observedGeneration %d < generation %d", observed, gen, obj.GetGeneration())
+		}
+		return strings.EqualFold(status, c.conditionValue), observed
+	}
+	return false, fmt.Sprintf("no %s condition", c.conditionType)
+}
+
+func (c waitCondition) checkJSONPath(obj *unstructured.Unstructured) (bool, string) {
+	results, err := c.path.FindResults(obj.Object)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return false, "<missing>"
+	}
+	value := results[0][0].Interface()
+	var observed string
+	switch v := value.(type) {
+	case string:
+		observed = v
+	case nil:
+		return false, "<missing>"
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			observed = fmt.Sprint(v)
+		} else {
+			observed = string(data)
+		}
+	}
+	if !c.hasValue {
+		return observed != "", observed
+	}
+	return observed == c.expectedValue, observed
+}
+
+// handleWait blocks until resources reach a condition, like kubectl wait.
+func handleWait(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	req, errMsg := parseWaitRequest(request.GetArguments())
+	if errMsg != "" {
+		return toolerrors.InvalidArgument(errMsg).Result(), nil
+	}
+
+	client, toolErr := tools.GetClusterClient(ctx, sc, req.cluster)
+	if toolErr != nil {
+		return toolErr.Result(), nil
+	}
+
+	result, err := waitFor(ctx, client.K8s(), req)
+	if err != nil {
+		return tools.K8sError("Failed to wait", err, client.User()).Result(), nil
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseWaitRequest validates the wait arguments. It needs resourceType, a
+// for condition and exactly one of name or labelSelector, and limits
+// timeoutSeconds to 1..MaxWaitTimeoutSeconds.
+func parseWaitRequest(args map[string]interface{}) (waitRequest, string) {
+	req := waitRequest{
+		cluster: tools.ExtractClusterParam(args),
+		timeout: DefaultWaitTimeoutSeconds * time.Second,
+	}
+	req.kubeContext, _ = args["kubeContext"].(string)
+	req.namespace, _ = args["namespace"].(string)
+	// Follow kubectl behavior: if no namespace specified, use "default".
+	if req.namespace == "" {
+		req.namespace = k8s.DefaultNamespace
+	}
+	req.apiGroup, _ = args["apiGroup"].(string)
+	req.name, _ = args["name"].(string)
+	req.labelSelector, _ = args["labelSelector"].(string)
+
+	req.resourceType, _ = args["resourceType"].(string)
+	if req.resourceType == "" {
+		return req, "resourceType is required"
+	}
+	if (req.name == "") == (req.labelSelector == "") {
+		return req, "exactly one of name or labelSelector is required"
+	}
+	if req.labelSelector != "" {
+		if _, err := labels.Parse(req.labelSelector); err != nil {
+			return req, fmt.Sprintf("invalid labelSelector: %v", err)
+		}
+	}
+
+	forExpr, _ := args["for"].(string)
+	if forExpr == "" {
+		return req, "for is required"
+	}
+	condition, err := parseWaitCondition(forExpr)
+	if err != nil {
+		return req, err.Error()
+	}
+	req.condition = condition
+
+	if v, ok := args["timeoutSeconds"].(float64); ok {
+		if v < 1 || v > MaxWaitTimeoutSeconds {
+			return req, fmt.Sprintf("timeoutSeconds must be between 1 and %d", MaxWaitTimeoutSeconds)
+		}
+		req.timeout = time.Duration(v) * time.Second
+	}
+	return req, ""
+}
+
+// waitState tracks the matched resources between watch events.
+type waitState struct {
+	req             waitRequest
+	objects         map[string]*unstructured.Unstructured
+	resourceVersion string
+}
+
+func (s *waitState) key(obj *unstructured.Unstructured) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// met reports whether the wait is over.
+func (s *waitState) met() bool {
+	switch s.req.condition.kind {
+	case waitForDelete:
+		return len(s.objects) == 0
+	case waitForCreate:
+		return len(s.objects) > 0
+	}
+	if len(s.objects) == 0 {
+		return false
+	}
+	for _, obj := range s.objects {
+		if ok, _ := s.req.condition.check(obj); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// listOptions selects the resources to wait for.
+func (s *waitState) listOptions() k8s.ListOptions {
+	opts := k8s.ListOptions{LabelSelector: s.req.labelSelector}
+	if s.req.name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", s.req.name).String()
+	}
+	return opts
+}
+
+// relist replaces the state with a fresh list.
+func (s *waitState) relist(ctx context.Context, client k8s.Client) error {
+	resp, err := client.List(ctx, s.req.kubeContext, s.req.namespace, s.req.resourceType, s.req.apiGroup, s.listOptions())
+	if err != nil {
+		return err
+	}
+	s.objects = make(map[string]*unstructured.Unstructured, len(resp.Items))
+	for _, item := range resp.Items {
+		obj, err := toUnstructuredObject(item)
+		if err != nil {
+			return err
+		}
+		s.objects[s.key(obj)] = obj
+	}
+	s.resourceVersion = resp.ResourceVersion
+	return nil
+}
+
+// apply updates the state from a watch event. It returns false when the
+// watch must be restarted from a fresh list.
+func (s *waitState) apply(event watch.Event) bool {
+	switch event.Type {
+	case watch.Added, watch.Modified, watch.Deleted:
+		obj, err := toUnstructuredObject(event.Object)
+		if err != nil {
+			return false
+		}
+		if event.Type == watch.Deleted {
+			delete(s.objects, s.key(obj))
+		} else {
+			s.objects[s.key(obj)] = obj
+		}
+		s.resourceVersion = obj.GetResourceVersion()
+	case watch.Bookmark:
+		if obj, err := toUnstructuredObject(event.Object); err == nil {
+			s.resourceVersion = obj.GetResourceVersion()
+		}
+	case watch.Error:
+		// Typically 410 Gone: the resource version is too old.
+		return false
+	}
+	return true
+}
+
+// result builds the tool output from the current state.
+func (s *waitState) result(method string, elapsed time.Duration) *WaitResult {
+	out := &WaitResult{
+		For:     s.req.condition.raw,
+		Met:     s.met(),
+		Method:  method,
+		Elapsed: elapsed.Round(100 * time.Millisecond).String(),
+		Objects: make([]WaitObject, 0, len(s.objects)),
+	}
+	for _, obj := range s.objects {
+		met, observed := true, ""
+		switch s.req.condition.kind {
+		case waitForDelete:
+			met = false
+		case waitForCondition, waitForJSONPath:
+			met, observed = s.req.condition.check(obj)
+		}
+		out.Objects = append(out.Objects, WaitObject{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Met:       met,
+			Observed:  observed,
+		})
+	}
+	sort.Slice(out.Objects, func(i, j int) bool {
+		if out.Objects[i].Namespace != out.Objects[j].Namespace {
+			return out.Objects[i].Namespace < out.Objects[j].Namespace
+		}
+		return out.Objects[i].Name < out.Objects[j].Name
+	})
+	if !out.Met && len(s.objects) == 0 && s.req.condition.kind != waitForDelete {
+		out.Message = "no matching resources found"
+	}
+	return out
+}
+
+// waitFor lists the resources, then watches them until the condition is met
+// or the timeout passes. If the watch cannot be started, for example because
+// the caller may list but not watch, it polls instead.
+func waitFor(ctx context.Context, client k8s.Client, req waitRequest) (*WaitResult, error) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, req.timeout)
+	defer cancel()
+
+	state := &waitState{req: req}
+	if err := state.relist(ctx, client); err != nil {
+		return nil, err
+	}
+
+	method := "watch"
+	for !state.met() {
+		var err error
+		if method == "watch" {
+			err = watchUntilMet(ctx, client, state)
+			if err != nil && !isDone(ctx, err) {
+				method = "poll"
+				err = pollUntilMet(ctx, client, state)
+			}
+		} else {
+			err = pollUntilMet(ctx, client, state)
+		}
+		if isDone(ctx, err) {
+			out := state.result(method, time.Since(start))
+			out.TimedOut = !out.Met
+			if out.TimedOut {
+				out.Message = strings.TrimSpace(fmt.Sprintf("timed out after %s; call wait again to keep waiting. %s", req.timeout, out.Message))
+			}
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return state.result(method, time.Since(start)), nil
+}
+
+// isDone reports whether err, or the context, signals the end of the wait.
+func isDone(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// watchUntilMet consumes one watch until the condition is met, the watch
+// ends (in which case the state is relisted) or the context is done. It
+// returns an error only when the watch cannot be started or relisting fails.
+func watchUntilMet(ctx context.Context, client k8s.Client, state *waitState) error {
+	opts := state.listOptions()
+	w, err := client.Watch(ctx, state.req.kubeContext, state.req.namespace, state.req.resourceType, state.req.apiGroup, k8s.WatchOptions{
+		LabelSelector:   opts.LabelSelector,
+		FieldSelector:   opts.FieldSelector,
+		ResourceVersion: state.resourceVersion,
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok || !state.apply(event) {
+				return state.relist(ctx, client)
+			}
+			if state.met() {
+				return nil
+			}
+		}
+	}
+}
+
+// pollUntilMet relists the resources until the condition is met or the
+// context is done.
+func pollUntilMet(ctx context.Context, client k8s.Client, state *waitState) error {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := state.relist(ctx, client); err != nil {
+				if apierrors.IsNotFound(err) && state.req.condition.kind == waitForDelete {
+					state.objects = nil
+					return nil
+				}
+				return err
+			}
+			if state.met() {
+				return nil
+			}
+		}
+	}
+}
+
+// toUnstructuredObject converts a runtime.Object to unstructured form.
+func toUnstructuredObject(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
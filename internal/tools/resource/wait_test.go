@@ -0,0 +1,254 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+// waitMock lists a fixed set of objects and hands out a fake watch. When
+// watchErr is set, watching fails and the objects can be swapped between
+// polls.
+type waitMock struct {
+	*testdata.MockK8sClient
+
+	mu       sync.Mutex
+	objects  []runtime.Object
+	watcher  *watch.FakeWatcher
+	watchErr error
+	listOpts []k8s.ListOptions
+}
+
+func (m *waitMock) List(_ context.Context, _, _, _, _ string, opts k8s.ListOptions) (*k8s.PaginatedListResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listOpts = append(m.listOpts, opts)
+	return &k8s.PaginatedListResponse{Items: m.objects, ResourceVersion: "100"}, nil
+}
+
+func (m *waitMock) Watch(context.Context, string, string, string, string, k8s.WatchOptions) (watch.Interface, error) {
+	if m.watchErr != nil {
+		return nil, m.watchErr
+	}
+	return m.watcher, nil
+}
+
+func (m *waitMock) setObjects(objects ...runtime.Object) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects = objects
+}
+
+func waitDeployment(name string, generation int64, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	items := make([]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		items = append(items, c)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":       name,
+			"namespace":  "default",
+			"generation": generation,
+		},
+		"status": map[string]interface{}{
+			"phase":      "Progressing",
+			"replicas":   int64(3),
+			"conditions": items,
+		},
+	}}
+}
+
+func callWait(t *testing.T, mock *waitMock, args map[string]interface{}) (*mcp.CallToolResult, WaitResult) {
+	t.Helper()
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(mock),
+		server.WithLogger(&testdata.MockLogger{}),
+	)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := handleWait(context.Background(), request, sc)
+	require.NoError(t, err)
+
+	var out WaitResult
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	}
+	return result, out
+}
+
+func TestWait_ConditionMetByWatchEvent(t *testing.T) {
+	mock := &waitMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects:       []runtime.Object{waitDeployment("web", 2, map[string]interface{}{"type": "Available", "status": "False"})},
+		watcher:       watch.NewFake(),
+	}
+	go func() {
+		// Stale: reported for generation 1.
+		mock.watcher.Modify(waitDeployment("web", 2, map[string]interface{}{"type": "Available", "status": "True", "observedGeneration": int64(1)}))
+		mock.watcher.Modify(waitDeployment("web", 2, map[string]interface{}{"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable", "observedGeneration": int64(2)}))
+	}()
+
+	result, out := callWait(t, mock, map[string]interface{}{
+		"resourceType": "deployments",
+		"name":         "web",
+		"for":          "condition=available",
+	})
+	require.False(t, result.IsError)
+	assert.True(t, out.Met)
+	assert.False(t, out.TimedOut)
+	assert.Equal(t, "watch", out.Method)
+	assert.Equal(t, []WaitObject{{Namespace: "default", Name: "web", Met: true, Observed: "Available=True (MinimumReplicasAvailable)"}}, out.Objects)
+	assert.Equal(t, "metadata.name=web", mock.listOpts[0].FieldSelector)
+}
+
+func TestWait_TimesOut(t *testing.T) {
+	mock := &waitMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects:       []runtime.Object{waitDeployment("web", 1)},
+		watcher:       watch.NewFake(),
+	}
+
+	start := time.Now()
+	result, out := callWait(t, mock, map[string]interface{}{
+		"resourceType":   "deployments",
+		"name":           "web",
+		"for":            "condition=Available",
+		"timeoutSeconds": float64(1),
+	})
+	require.False(t, result.IsError, "a timeout is a result, not an error")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.False(t, out.Met)
+	assert.True(t, out.TimedOut)
+	assert.Contains(t, out.Message, "call wait again")
+	require.Len(t, out.Objects, 1)
+	assert.Equal(t, "no Available condition", out.Objects[0].Observed)
+}
+
+func TestWait_Delete(t *testing.T) {
+	web := waitDeployment("web", 1)
+	mock := &waitMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects:       []runtime.Object{web},
+		watcher:       watch.NewFake(),
+	}
+	go mock.watcher.Delete(web)
+
+	result, out := callWait(t, mock, map[string]interface{}{
+		"resourceType":  "deployments",
+		"labelSelector": "app=web",
+		"for":           "delete",
+	})
+	require.False(t, result.IsError)
+	assert.True(t, out.Met)
+	assert.Empty(t, out.Objects)
+	assert.Equal(t, "app=web", mock.listOpts[0].LabelSelector)
+}
+
+func TestWait_AlreadyMet(t *testing.T) {
+	mock := &waitMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		objects:       []runtime.Object{waitDeployment("web", 1)},
+		watchErr:      errors.New("must not watch"),
+	}
+
+	result, out := callWait(t, mock, map[string]interface{}{
+		"resourceType": "deployments",
+		"name":         "web",
+		"for":          "jsonpath={.status.replicas}=3",
+	})
+	require.False(t, result.IsError)
+	assert.True(t, out.Met)
+	assert.Equal(t, "3", out.Objects[0].Observed)
+}
+
+func TestWait_FallsBackToPolling(t *testing.T) {
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = 10 * time.Millisecond
+
+	mock := &waitMock{
+		MockK8sClient: &testdata.MockK8sClient{},
+		watchErr:      errors.New("watching is not available"),
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		mock.setObjects(waitDeployment("web", 1))
+	}()
+
+	result, out := callWait(t, mock, map[string]interface{}{
+		"resourceType": "deployments",
+		"name":         "web",
+		"for":          "create",
+	})
+	require.False(t, result.IsError)
+	assert.True(t, out.Met)
+	assert.Equal(t, "poll", out.Method)
+}
+
+func TestParseWaitCondition(t *testing.T) {
+	obj := waitDeployment("web", 1, map[string]interface{}{"type": "Progressing", "status": "True"})
+
+	tests := []struct {
+		expr     string
+		wantErr  string
+		met      bool
+		observed string
+	}{
+		{expr: "condition=Progressing", met: true, observed: "Progressing=True"},
+		{expr: "condition=Progressing=False", met: false, observed: "Progressing=True"},
+		{expr: "jsonpath={.status.phase}=Progressing", met: true, observed: "Progressing"},
+		{expr: "jsonpath=.status.phase=Running", met: false, observed: "Progressing"},
+		{expr: "jsonpath={.status.phase}", met: true, observed: "Progressing"},
+		{expr: "jsonpath={.status.missing}", met: false, observed: "<missing>"},
+		{expr: "condition=", wantErr: "condition type is missing"},
+		{expr: "jsonpath={.status.phase}Running", wantErr: "expected '='"},
+		{expr: "ready", wantErr: "unsupported condition"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := parseWaitCondition(tt.expr)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			met, observed := c.check(obj)
+			assert.Equal(t, tt.met, met)
+			assert.Equal(t, tt.observed, observed)
+		})
+	}
+}
+
+func TestParseWaitRequest_Validation(t *testing.T) {
+	for name, tt := range map[string]struct {
+		args    map[string]interface{}
+		wantErr string
+	}{
+		"missing resourceType":   {map[string]interface{}{"name": "web", "for": "delete"}, "resourceType is required"},
+		"name and labelSelector": {map[string]interface{}{"resourceType": "pods", "name": "web", "labelSelector": "app=web", "for": "delete"}, "exactly one of name or labelSelector"},
+		"missing for":            {map[string]interface{}{"resourceType": "pods", "name": "web"}, "for is required"},
+		"timeout too long":       {map[string]interface{}{"resourceType": "pods", "name": "web", "for": "delete", "timeoutSeconds": float64(60)}, "timeoutSeconds must be between 1 and 25"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, errMsg := parseWaitRequest(tt.args)
+			assert.Contains(t, errMsg, tt.wantErr)
+		})
+	}
+}
@@ -3,12 +3,14 @@ package tools
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
 )
 
 // CheckMutatingOperation verifies if a mutating operation is allowed given the current
@@ -28,10 +30,10 @@ func CheckMutatingOperation(sc *server.ServerContext, operation string) *mcp.Cal
 		return nil
 	}
 
-	return mcp.NewToolResultError(fmt.Sprintf(
+	return toolerrors.Newf(toolerrors.CodeOperationNotAllowed,
 		"%s operations are not allowed in non-destructive mode (use --dry-run to validate without applying)",
 		cases.Title(language.English).String(operation),
-	))
+	).Result()
 }
 
 // IsMutatingOperationAllowed reports whether the given operation verb would be
@@ -63,3 +65,44 @@ func IsMutatingOperationAllowed(sc *server.ServerContext, operation string) bool
 
 	return false
 }
+
+// CheckSecretWrite verifies that a call writing a Secret is allowed by the
+// server's SecretWrites mode. Returns an error result if blocked, nil if
+// allowed.
+func CheckSecretWrite(sc *server.ServerContext, args map[string]interface{}) *mcp.CallToolResult {
+	if reason := SecretWriteDenied(sc, args); reason != "" {
+		return toolerrors.New(toolerrors.CodeOperationNotAllowed, reason).Result()
+	}
+	return nil
+}
+
+// SecretWriteDenied returns why the server's SecretWrites mode blocks a call
+// writing a Secret, or an empty string if the write is allowed.
+//
+// Under SecretWritesOperation the write needs server.SecretWriteOperation,
+// either in AllowedOperations or through a policy allow rule whose verbs
+// match it on secrets for the call's cluster and namespace. Neither the
+// policy's default effect nor a rule without verbs counts. Unlike
+// CheckMutatingOperation, dry-run does not lift the check, because a dry-run
+// request still sends the Secret's values to the API server.
+func SecretWriteDenied(sc *server.ServerContext, args map[string]interface{}) string {
+	config := sc.Config()
+	switch config.SecretWrites {
+	case "", server.SecretWritesAllow:
+		return ""
+	case server.SecretWritesOperation:
+		if slices.Contains(config.AllowedOperations, server.SecretWriteOperation) {
+			return ""
+		}
+		if engine := sc.PolicyEngine(); engine != nil {
+			req := policyRequestFromArgs(server.SecretWriteOperation, args)
+			req.Resource = "secrets"
+			if decision := engine.Evaluate(req); decision.Allowed && decision.Rule != nil && len(decision.Rule.Verbs) > 0 {
+				return ""
+			}
+		}
+		return fmt.Sprintf("writing Secrets requires the %q operation, which is not allowed here", server.SecretWriteOperation)
+	default:
+		return "writing Secrets is disabled on this server"
+	}
+}
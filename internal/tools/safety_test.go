@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
 	"github.com/giantswarm/mcp-kubernetes/internal/server"
 	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
 )
@@ -244,3 +245,61 @@ func TestCheckMutatingOperation_ErrorMessageFormat(t *testing.T) {
 		assert.Contains(t, text, "--dry-run")
 	}
 }
+
+// TestCheckSecretWrite verifies the SecretWrites modes, including that only
+// an explicit policy rule grants the secret-write operation.
+func TestCheckSecretWrite(t *testing.T) {
+	ctx := context.Background()
+	args := map[string]interface{}{"namespace": "shop"}
+
+	newContext := func(t *testing.T, opts ...server.Option) *server.ServerContext {
+		t.Helper()
+		sc, err := server.NewServerContext(ctx, append([]server.Option{
+			server.WithK8sClient(&testdata.MockK8sClient{}),
+			server.WithLogger(&testdata.MockLogger{}),
+		}, opts...)...)
+		require.NoError(t, err)
+		return sc
+	}
+
+	t.Run("allowed by default", func(t *testing.T) {
+		assert.Nil(t, CheckSecretWrite(newContext(t), args))
+	})
+
+	t.Run("deny blocks even in dry-run", func(t *testing.T) {
+		result := CheckSecretWrite(newContext(t, server.WithSecretWrites(server.SecretWritesDeny), server.WithDryRun(true)), args)
+		require.NotNil(t, result)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("operation needs the secret-write operation", func(t *testing.T) {
+		result := CheckSecretWrite(newContext(t, server.WithSecretWrites(server.SecretWritesOperation)), args)
+		require.NotNil(t, result)
+		assert.True(t, result.IsError)
+
+		sc := newContext(t, server.WithSecretWrites(server.SecretWritesOperation), server.WithAuth([]string{"get", server.SecretWriteOperation}))
+		assert.Nil(t, CheckSecretWrite(sc, args))
+	})
+
+	t.Run("operation granted by a policy rule", func(t *testing.T) {
+		engine := security.NewEngine(&security.Policy{Rules: []security.Rule{{
+			Effect:     security.EffectAllow,
+			Namespaces: []string{"shop"},
+			Resources:  []string{"secrets"},
+			Verbs:      []string{server.SecretWriteOperation},
+		}}})
+		sc := newContext(t, server.WithSecretWrites(server.SecretWritesOperation), server.WithPolicyEngine(engine))
+
+		assert.Nil(t, CheckSecretWrite(sc, args))
+		assert.NotNil(t, CheckSecretWrite(sc, map[string]interface{}{"namespace": "billing"}), "the rule only covers shop")
+	})
+
+	t.Run("policy default allow does not grant it", func(t *testing.T) {
+		engine := security.NewEngine(&security.Policy{Rules: []security.Rule{{
+			Effect:     security.EffectAllow,
+			Namespaces: []string{"shop"},
+		}}})
+		sc := newContext(t, server.WithSecretWrites(server.SecretWritesOperation), server.WithPolicyEngine(engine))
+		assert.NotNil(t, CheckSecretWrite(sc, args))
+	})
+}
@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// Names of the session context tools.
+const (
+	SetDefaultClusterToolName   = "set_default_cluster"
+	SetDefaultNamespaceToolName = "set_default_namespace"
+	GetSessionContextToolName   = "get_session_context"
+)
+
+// Parameters filled from the session defaults when a call leaves them out.
+const (
+	paramCluster       = "cluster"
+	paramNamespace     = "namespace"
+	paramAllNamespaces = "allNamespaces"
+)
+
+// sessionTools are the session context tools, whose own cluster and
+// namespace arguments are never filled from the defaults, like those of the
+// preferences tools.
+var sessionTools = map[string]bool{
+	SetDefaultClusterToolName:   true,
+	SetDefaultNamespaceToolName: true,
+	GetSessionContextToolName:   true,
+}
+
+// SessionContextResponse reports the defaults of the caller's MCP session.
+type SessionContextResponse struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Message   string `json:"message"`
+}
+
+// RegisterSessionTools registers the tools that set and show the default
+// cluster and namespace of the caller's MCP session. set_default_cluster is
+// only registered when federation is enabled, as tools only take a cluster
+// then.
+func RegisterSessionTools(s *mcpserver.MCPServer, sc *server.ServerContext) {
+	if sc.FederationEnabled() {
+		s.AddTool(mcp.NewTool(SetDefaultClusterToolName,
+			mcp.WithDescription(`Set the cluster that tool calls in this session use when they leave out the cluster parameter. Pass an empty string to go back to the management cluster.
+
+The default lasts until the session ends. A cluster passed to a tool call still takes precedence.`),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithSchemaAdditionalProperties(false),
+			mcp.WithString(paramCluster,
+				mcp.Required(),
+				mcp.Description("Workload cluster name, or an empty string to clear the default"),
+			),
+		), WrapWithAuditLogging(SetDefaultClusterToolName, handleSetDefaultCluster, sc))
+	}
+
+	s.AddTool(mcp.NewTool(SetDefaultNamespaceToolName,
+		mcp.WithDescription(`Set the namespace that tool calls in this session use when they leave out the namespace parameter, instead of "default". Pass an empty string to clear it.
+
+The default lasts until the session ends. A namespace passed to a tool call, or allNamespaces, still takes precedence.`),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithString(paramNamespace,
+			mcp.Required(),
+			mcp.Description("Namespace name, or an empty string to clear the default"),
+		),
+	), WrapWithAuditLogging(SetDefaultNamespaceToolName, handleSetDefaultNamespace, sc))
+
+	s.AddTool(mcp.NewTool(GetSessionContextToolName,
+		mcp.WithDescription("Show the default cluster and namespace of this session, set with set_default_cluster and set_default_namespace."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+	), WrapWithAuditLogging(GetSessionContextToolName, handleGetSessionContext, sc))
+}
+
+// mcpSessionID returns the ID of the caller's MCP session, or "" when the
+// transport has no sessions.
+func mcpSessionID(ctx context.Context) string {
+	if s := mcpserver.ClientSessionFromContext(ctx); s != nil {
+		return s.SessionID()
+	}
+	return ""
+}
+
+// errNoSession is returned by the session context tools outside an MCP
+// session.
+var errNoSession = toolerrors.New(toolerrors.CodeFailedPrecondition, "session defaults need an MCP session, which this connection does not have")
+
+func handleSetDefaultCluster(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	cluster, _ := request.GetArguments()[paramCluster].(string)
+	if cluster != "" {
+		if err := federation.ValidateClusterName(cluster); err != nil {
+			return toolerrors.InvalidArgument(errMsgInvalidClusterName).Result(), nil
+		}
+	}
+	return updateSessionDefaults(ctx, sc, func(d *sessions.Defaults) { d.Cluster = cluster })
+}
+
+func handleSetDefaultNamespace(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	namespace, _ := request.GetArguments()[paramNamespace].(string)
+	if namespace != "" {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return toolerrors.InvalidArgumentf("invalid namespace name %q: %s", namespace, strings.Join(errs, "; ")).Result(), nil
+		}
+	}
+	return updateSessionDefaults(ctx, sc, func(d *sessions.Defaults) { d.Namespace = namespace })
+}
+
+func updateSessionDefaults(ctx context.Context, sc *server.ServerContext, update func(*sessions.Defaults)) (*mcp.CallToolResult, error) {
+	session := mcpSessionID(ctx)
+	if session == "" {
+		return errNoSession.Result(), nil
+	}
+	d, err := sc.SessionDefaults().Update(session, update)
+	if errors.Is(err, sessions.ErrTooManySessions) {
+		return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+	} else if err != nil {
+		return toolerrors.Internalf("Failed to update session defaults: %v", err).Result(), nil
+	}
+	return sessionContextResult(d)
+}
+
+func handleGetSessionContext(ctx context.Context, _ mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	session := mcpSessionID(ctx)
+	if session == "" {
+		return errNoSession.Result(), nil
+	}
+	return sessionContextResult(sc.SessionDefaults().Get(session))
+}
+
+func sessionContextResult(d sessions.Defaults) (*mcp.CallToolResult, error) {
+	response := SessionContextResponse{Cluster: d.Cluster, Namespace: d.Namespace}
+	switch {
+	case d.IsZero():
+		response.Message = "No session defaults are set"
+	default:
+		var parts []string
+		if d.Cluster != "" {
+			parts = append(parts, fmt.Sprintf("cluster %q", d.Cluster))
+		}
+		if d.Namespace != "" {
+			parts = append(parts, fmt.Sprintf("namespace %q", d.Namespace))
+		}
+		response.Message = "Tool calls that leave them out use " + strings.Join(parts, " and ")
+	}
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// withSessionDefaults fills the cluster and namespace arguments a call left
+// out from the defaults of the caller's MCP session, for tools that take
+// them, and returns the request with the defaults it applied. A namespace is
+// not filled in when the call asks for all namespaces.
+func withSessionDefaults(ctx context.Context, sc *server.ServerContext, toolName string, request mcp.CallToolRequest) (mcp.CallToolRequest, sessions.Defaults) {
+	if name := primaryToolName(toolName); sessionTools[name] || preferencesTools[name] {
+		return request, sessions.Defaults{}
+	}
+	session := mcpSessionID(ctx)
+	if session == "" {
+		return request, sessions.Defaults{}
+	}
+	defaults := sc.SessionDefaults().Get(session)
+	if defaults.IsZero() {
+		return request, sessions.Defaults{}
+	}
+	srv := mcpserver.ServerFromContext(ctx)
+	if srv == nil {
+		return request, sessions.Defaults{}
+	}
+	tool := srv.GetTool(toolName)
+	if tool == nil {
+		return request, sessions.Defaults{}
+	}
+
+	args := request.GetArguments()
+	var applied sessions.Defaults
+	fill := map[string]interface{}{}
+	if defaults.Cluster != "" && takesParam(tool.Tool, paramCluster) && isUnset(args, paramCluster) {
+		applied.Cluster = defaults.Cluster
+		fill[paramCluster] = defaults.Cluster
+	}
+	if allNamespaces, _ := args[paramAllNamespaces].(bool); defaults.Namespace != "" && !allNamespaces &&
+		takesParam(tool.Tool, paramNamespace) && isUnset(args, paramNamespace) {
+		applied.Namespace = defaults.Namespace
+		fill[paramNamespace] = defaults.Namespace
+	}
+	return withArguments(request, fill), applied
+}
+
+// withArguments returns request with the arguments in fill added to a copy
+// of its own.
+func withArguments(request mcp.CallToolRequest, fill map[string]interface{}) mcp.CallToolRequest {
+	if len(fill) == 0 {
+		return request
+	}
+	args := request.GetArguments()
+	filled := make(map[string]interface{}, len(args)+len(fill))
+	for k, v := range args {
+		filled[k] = v
+	}
+	for k, v := range fill {
+		filled[k] = v
+	}
+	request.Params.Arguments = filled
+	return request
+}
+
+// takesParam reports whether tool declares the parameter name.
+func takesParam(tool mcp.Tool, name string) bool {
+	_, ok := tool.InputSchema.Properties[name]
+	return ok
+}
+
+// isUnset reports whether args leave the string argument name out or empty.
+func isUnset(args map[string]interface{}, name string) bool {
+	v, _ := args[name].(string)
+	return v == ""
+}
+
+// withSessionDefaultsMeta reports the session defaults a call used in the
+// result's _meta, so that callers can tell where the cluster or namespace
+// came from.
+func withSessionDefaultsMeta(result *mcp.CallToolResult, applied sessions.Defaults) *mcp.CallToolResult {
+	if result == nil || applied.IsZero() {
+		return result
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields["sessionDefaults"] = applied
+	return result
+}
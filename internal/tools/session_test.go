@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/sessions"
+)
+
+// sessionTestServer registers the session tools and an "echo" tool that
+// takes a cluster and namespace and returns its arguments.
+func sessionTestServer(t *testing.T) (*mcpserver.MCPServer, *server.ServerContext) {
+	t.Helper()
+	sc := createTestServerContextNoInstrumentation(t)
+	s := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithToolCapabilities(true))
+	RegisterSessionTools(s, sc)
+
+	echo := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		data, err := json.Marshal(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+	s.AddTool(mcp.NewTool("echo",
+		mcp.WithString("cluster"),
+		mcp.WithString("namespace"),
+		mcp.WithBoolean("allNamespaces"),
+	), WrapWithAuditLogging("echo", echo, sc))
+	return s, sc
+}
+
+func callSessionTool(t *testing.T, s *mcpserver.MCPServer, sessionID, tool string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	ctx := context.Background()
+	if sessionID != "" {
+		ctx = s.WithContext(ctx, &clientInfoSession{id: sessionID})
+	}
+	return callToolWithContext(t, ctx, s, tool, args)
+}
+
+// callToolWithContext calls tool on s through the MCP protocol handler.
+func callToolWithContext(t *testing.T, ctx context.Context, s *mcpserver.MCPServer, tool string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": tool, "arguments": args},
+	})
+	require.NoError(t, err)
+	resp, ok := s.HandleMessage(ctx, raw).(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected JSON-RPC response")
+	result, ok := resp.Result.(*mcp.CallToolResult)
+	require.True(t, ok, "expected CallToolResult")
+	return result
+}
+
+func echoedArgs(t *testing.T, result *mcp.CallToolResult) map[string]any {
+	t.Helper()
+	require.False(t, result.IsError)
+	var args map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &args))
+	return args
+}
+
+func TestSessionDefaults(t *testing.T) {
+	s, sc := sessionTestServer(t)
+	assert.Nil(t, s.GetTool(SetDefaultClusterToolName), "set_default_cluster needs federation")
+
+	result := callSessionTool(t, s, "s1", SetDefaultNamespaceToolName, map[string]any{"namespace": "team-a"})
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"namespace": "team-a"`)
+
+	result = callSessionTool(t, s, "s1", "echo", map[string]any{})
+	assert.Equal(t, "team-a", echoedArgs(t, result)["namespace"])
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, sessions.Defaults{Namespace: "team-a"}, result.Meta.AdditionalFields["sessionDefaults"])
+
+	assert.Equal(t, "other", echoedArgs(t, callSessionTool(t, s, "s1", "echo", map[string]any{"namespace": "other"}))["namespace"],
+		"an explicit namespace wins")
+	assert.NotContains(t, echoedArgs(t, callSessionTool(t, s, "s1", "echo", map[string]any{"allNamespaces": true})), "namespace",
+		"allNamespaces is not narrowed to the default")
+	assert.NotContains(t, echoedArgs(t, callSessionTool(t, s, "s2", "echo", map[string]any{})), "namespace",
+		"defaults are per session")
+
+	// A default cluster is filled in like the namespace.
+	_, err := sc.SessionDefaults().Update("s1", func(d *sessions.Defaults) { d.Cluster = "prod-wc-01" })
+	require.NoError(t, err)
+	args := echoedArgs(t, callSessionTool(t, s, "s1", "echo", map[string]any{}))
+	assert.Equal(t, "prod-wc-01", args["cluster"])
+	assert.Equal(t, "team-a", args["namespace"])
+
+	result = callSessionTool(t, s, "s1", GetSessionContextToolName, map[string]any{})
+	require.False(t, result.IsError)
+	var response SessionContextResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	assert.Equal(t, "prod-wc-01", response.Cluster)
+	assert.Equal(t, "team-a", response.Namespace)
+
+	result = callSessionTool(t, s, "s1", SetDefaultNamespaceToolName, map[string]any{"namespace": ""})
+	require.False(t, result.IsError)
+	assert.Equal(t, sessions.Defaults{Cluster: "prod-wc-01"}, sc.SessionDefaults().Get("s1"), "an empty namespace clears the default")
+
+	sc.SessionDefaults().Delete("s1")
+	assert.NotContains(t, echoedArgs(t, callSessionTool(t, s, "s1", "echo", map[string]any{})), "cluster",
+		"defaults end with the session")
+}
+
+func TestSessionDefaults_Errors(t *testing.T) {
+	s, _ := sessionTestServer(t)
+
+	result := callSessionTool(t, s, "s1", SetDefaultNamespaceToolName, map[string]any{"namespace": "Team_A"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid namespace name")
+
+	result = callSessionTool(t, s, "", SetDefaultNamespaceToolName, map[string]any{"namespace": "team-a"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "need an MCP session")
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// callWithTimeout runs a tool handler under the tool's call timeout (see
+// server.Config.ToolTimeoutFor). The handler's context is cancelled when the
+// timeout passes or the caller cancels the call, which aborts its Kubernetes
+// requests. A handler that does not return once its context is done is
+// abandoned (not waited for), so a hung API server cannot stall the session.
+func callWithTimeout(
+	ctx context.Context,
+	toolName string,
+	handler ToolHandler,
+	sc *server.ServerContext,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	timeout := sc.Config().ToolTimeoutFor(primaryToolName(toolName))
+	if timeout <= 0 {
+		return handler(ctx, request, sc)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type handlerResult struct {
+		result *mcp.CallToolResult
+		err    error
+	}
+
+	resultChan := make(chan handlerResult, 1)
+	go func() {
+		result, err := handler(ctx, request, sc)
+		resultChan <- handlerResult{result: result, err: err}
+	}()
+
+	select {
+	case r := <-resultChan:
+		// A handler that fails because its context is done reports that
+		// the call timed out or was cancelled, not what it was doing.
+		if ctx.Err() != nil && (r.err != nil || (r.result != nil && r.result.IsError)) {
+			return contextDoneError(ctx, timeout).Result(), nil
+		}
+		return r.result, r.err
+	case <-ctx.Done():
+		return contextDoneError(ctx, timeout).Result(), nil
+	}
+}
+
+// contextDoneError returns the error of a tool call whose context is done.
+func contextDoneError(ctx context.Context, timeout time.Duration) *toolerrors.Error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return toolerrors.Newf(toolerrors.CodeTimeout, "tool call timed out after %s", timeout)
+	}
+	return toolerrors.New(toolerrors.CodeCancelled, "tool call was cancelled")
+}
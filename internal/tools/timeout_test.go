@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+func TestCallWithTimeout(t *testing.T) {
+	request := createTestRequest(map[string]interface{}{})
+
+	hang := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	t.Run("handler within the timeout", func(t *testing.T) {
+		sc := newVisibilityServerContext(t, server.WithToolTimeouts(time.Second, nil))
+		handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			return mcp.NewToolResultText("ok"), nil
+		}
+
+		result, err := callWithTimeout(context.Background(), "get", handler, sc, request)
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("hung handler times out", func(t *testing.T) {
+		sc := newVisibilityServerContext(t, server.WithToolTimeouts(10*time.Millisecond, nil))
+
+		result, err := callWithTimeout(context.Background(), "get", hang, sc, request)
+		require.NoError(t, err)
+		require.NotNil(t, toolerrors.FromResult(result))
+		assert.Equal(t, toolerrors.CodeTimeout, toolerrors.FromResult(result).Code)
+	})
+
+	t.Run("handler that ignores its context is abandoned", func(t *testing.T) {
+		sc := newVisibilityServerContext(t, server.WithToolTimeouts(10*time.Millisecond, nil))
+		release := make(chan struct{})
+		defer close(release)
+		stuck := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+			<-release
+			return mcp.NewToolResultText("late"), nil
+		}
+
+		result, err := callWithTimeout(context.Background(), "get", stuck, sc, request)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("caller cancellation", func(t *testing.T) {
+		sc := newVisibilityServerContext(t, server.WithToolTimeouts(time.Minute, nil))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := callWithTimeout(ctx, "get", hang, sc, request)
+		require.NoError(t, err)
+		require.NotNil(t, toolerrors.FromResult(result))
+		assert.Equal(t, toolerrors.CodeCancelled, toolerrors.FromResult(result).Code)
+	})
+
+	t.Run("per-tool override applies to aliases", func(t *testing.T) {
+		sc := newVisibilityServerContext(t, server.WithToolTimeouts(time.Minute, map[string]time.Duration{"get": 0}))
+		handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+			_, hasDeadline := ctx.Deadline()
+			assert.False(t, hasDeadline, "a zero override disables the timeout")
+			return mcp.NewToolResultText("ok"), nil
+		}
+
+		_, err := callWithTimeout(context.Background(), "kubernetes_get", handler, sc, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestToolTimeoutFor(t *testing.T) {
+	config := server.NewDefaultConfig()
+	assert.Equal(t, server.DefaultToolTimeout, config.ToolTimeoutFor("get"))
+	assert.Equal(t, server.DefaultToolTimeouts["debug_pod"], config.ToolTimeoutFor("debug_pod"))
+
+	config.ToolTimeout = time.Hour
+	assert.Equal(t, time.Hour, config.ToolTimeoutFor("debug_pod"), "a longer global timeout wins over built-in defaults")
+
+	config.ToolTimeouts = map[string]time.Duration{"debug_pod": time.Second}
+	assert.Equal(t, time.Second, config.ToolTimeoutFor("debug_pod"))
+
+	config.ToolTimeout = 0
+	assert.Zero(t, config.ToolTimeoutFor("get"))
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// tokenSuggestions are the arguments that make responses smaller, in the
+// order they are suggested, and the advice given for a tool that takes
+// them.
+var tokenSuggestions = []struct {
+	param  string
+	advice string
+}{
+	{"summary", "summary=true"},
+	{"labelSelector", "labelSelector"},
+	{"fieldSelector", "fieldSelector"},
+	{"filter", "filter"},
+	{"limit", "a smaller limit"},
+	{"tailLines", "a smaller tailLines"},
+}
+
+// withTokenEstimate reports the estimated token count of a result in its
+// _meta and, when the estimate exceeds the configured warning threshold,
+// appends a warning suggesting the arguments of the tool that make
+// responses smaller. Error results are not warned about.
+func withTokenEstimate(ctx context.Context, sc *server.ServerContext, toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil {
+		return result
+	}
+	tokens := instrumentation.EstimateTokens(resultBytes(result))
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields["estimatedTokens"] = tokens
+
+	if result.IsError {
+		return result
+	}
+	if warning := output.TokenWarning(tokens, sc.OutputConfig().TokenWarningThreshold, tokenAdvice(ctx, toolName)); warning != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(warning))
+	}
+	return result
+}
+
+// tokenAdvice returns the advice of tokenSuggestions for the parameters
+// toolName takes.
+func tokenAdvice(ctx context.Context, toolName string) []string {
+	srv := mcpserver.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	tool := srv.GetTool(toolName)
+	if tool == nil {
+		return nil
+	}
+	var advice []string
+	for _, s := range tokenSuggestions {
+		if takesParam(tool.Tool, s.param) {
+			advice = append(advice, s.advice)
+		}
+	}
+	return advice
+}
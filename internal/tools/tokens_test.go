@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/resource/testdata"
+)
+
+func TestWithTokenEstimate(t *testing.T) {
+	outputConfig := server.NewDefaultOutputConfig()
+	outputConfig.TokenWarningThreshold = 100
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithOutputConfig(outputConfig),
+	)
+	require.NoError(t, err)
+
+	s := mcpserver.NewMCPServer("test", "1.0.0", mcpserver.WithToolCapabilities(true))
+	respond := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		size, _ := request.GetArguments()["size"].(float64)
+		return mcp.NewToolResultText(strings.Repeat("x", int(size))), nil
+	}
+	s.AddTool(mcp.NewTool("list",
+		mcp.WithNumber("size"),
+		mcp.WithBoolean("summary"),
+		mcp.WithString("labelSelector"),
+		mcp.WithNumber("limit"),
+	), WrapWithAuditLogging("list", respond, sc))
+	s.AddTool(mcp.NewTool("echo", mcp.WithNumber("size")), WrapWithAuditLogging("echo", respond, sc))
+
+	result := callToolWithContext(t, context.Background(), s, "list", map[string]any{"size": 400})
+	require.Len(t, result.Content, 1, "a response at the threshold is not warned about")
+	assert.Equal(t, 100, result.Meta.AdditionalFields["estimatedTokens"])
+
+	result = callToolWithContext(t, context.Background(), s, "list", map[string]any{"size": 4000})
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, 1000, result.Meta.AdditionalFields["estimatedTokens"])
+	assert.Equal(t,
+		"WARNING: this response is about 1000 tokens, above the warning threshold of 100. To reduce it, use summary=true, labelSelector or a smaller limit.",
+		result.Content[1].(mcp.TextContent).Text)
+
+	result = callToolWithContext(t, context.Background(), s, "echo", map[string]any{"size": 4000})
+	require.Len(t, result.Content, 2)
+	assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "Narrow the request to reduce it.")
+}
+
+func TestWithTokenEstimate_Disabled(t *testing.T) {
+	outputConfig := server.NewDefaultOutputConfig()
+	outputConfig.TokenWarningThreshold = 0
+	sc, err := server.NewServerContext(context.Background(),
+		server.WithK8sClient(&testdata.MockK8sClient{}),
+		server.WithLogger(&testdata.MockLogger{}),
+		server.WithOutputConfig(outputConfig),
+	)
+	require.NoError(t, err)
+
+	result := withTokenEstimate(context.Background(), sc, "list", mcp.NewToolResultText(strings.Repeat("x", 1<<20)))
+	assert.Len(t, result.Content, 1)
+	assert.Equal(t, 1<<18, result.Meta.AdditionalFields["estimatedTokens"])
+
+	errResult := withTokenEstimate(context.Background(), createTestServerContextNoInstrumentation(t), "list", mcp.NewToolResultError(strings.Repeat("x", 1<<20)))
+	assert.Len(t, errResult.Content, 1, "errors are not warned about")
+}
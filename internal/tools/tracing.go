@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// ProcessRuntimeObjects is output.ProcessRuntimeObjects in an
+// "output.process" span recording how many items went in and came out.
+func ProcessRuntimeObjects(ctx context.Context, processor *output.Processor, objects []runtime.Object) ([]runtime.Object, *output.ProcessingResult, error) {
+	_, span := instrumentation.StartOutputSpan(ctx, "process",
+		attribute.Int(instrumentation.SpanAttrOriginalItemCount, len(objects)))
+	defer span.End()
+
+	processed, result, err := output.ProcessRuntimeObjects(processor, objects)
+	if err != nil {
+		instrumentation.SetSpanError(span, err)
+		return nil, nil, err
+	}
+	span.SetAttributes(
+		attribute.Int(instrumentation.SpanAttrItemCount, len(processed)),
+		attribute.Bool(instrumentation.SpanAttrTruncated, result.Metadata.Truncated),
+	)
+	return processed, result, nil
+}
+
+// ProcessSingleRuntimeObject is output.ProcessSingleRuntimeObject in an
+// "output.process" span.
+func ProcessSingleRuntimeObject(ctx context.Context, processor *output.Processor, obj runtime.Object) (runtime.Object, error) {
+	_, span := instrumentation.StartOutputSpan(ctx, "process",
+		attribute.Int(instrumentation.SpanAttrOriginalItemCount, 1))
+	defer span.End()
+
+	processed, err := output.ProcessSingleRuntimeObject(processor, obj)
+	if err != nil {
+		instrumentation.SetSpanError(span, err)
+		return nil, err
+	}
+	return processed, nil
+}
+
+// MarshalResponse marshals a tool response to JSON, indented when indent is
+// set, in an "output.marshal" span recording the response size.
+func MarshalResponse(ctx context.Context, v interface{}, indent bool) ([]byte, error) {
+	_, span := instrumentation.StartOutputSpan(ctx, "marshal")
+	defer span.End()
+
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		instrumentation.SetSpanError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int(instrumentation.SpanAttrResponseBytes, len(data)))
+	return data, nil
+}
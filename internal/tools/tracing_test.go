@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/instrumentation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	"github.com/giantswarm/mcp-kubernetes/internal/tools/output"
+)
+
+// recordSpans installs a tracer provider recording every span for the
+// duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestWrapWithAuditLogging_TracesPipeline(t *testing.T) {
+	recorder := recordSpans(t)
+	sc := createTestServerContextNoInstrumentation(t)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+		if _, toolErr := GetClusterClient(ctx, sc, ""); toolErr != nil {
+			return toolErr.Result(), nil
+		}
+		objects := []runtime.Object{
+			&unstructured.Unstructured{Object: map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "a"}}},
+			&unstructured.Unstructured{Object: map[string]interface{}{"kind": "Pod", "metadata": map[string]interface{}{"name": "b"}}},
+		}
+		processed, _, err := ProcessRuntimeObjects(ctx, output.NewProcessor(output.DefaultConfig()), objects)
+		if err != nil {
+			return nil, err
+		}
+		data, err := MarshalResponse(ctx, processed, false)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+
+	result, err := WrapWithAuditLogging("list", handler, sc)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	require.Contains(t, spans, "tool.list")
+	require.Contains(t, spans, "federation.get_client")
+	require.Contains(t, spans, "output.process")
+	require.Contains(t, spans, "output.marshal")
+
+	tool := spans["tool.list"]
+	for _, name := range []string{"federation.get_client", "output.process", "output.marshal"} {
+		assert.Equal(t, tool.SpanContext().SpanID(), spans[name].Parent().SpanID(), "%s is a child of the tool span", name)
+	}
+
+	assert.Equal(t, int64(2), spanAttr(spans["output.process"], instrumentation.SpanAttrOriginalItemCount).AsInt64())
+	assert.Equal(t, int64(2), spanAttr(spans["output.process"], instrumentation.SpanAttrItemCount).AsInt64())
+	assert.False(t, spanAttr(spans["federation.get_client"], instrumentation.SpanAttrFederated).AsBool())
+
+	responseBytes := int64(len(result.Content[0].(mcp.TextContent).Text))
+	assert.Equal(t, responseBytes, spanAttr(spans["output.marshal"], instrumentation.SpanAttrResponseBytes).AsInt64())
+	assert.Equal(t, responseBytes, spanAttr(tool, instrumentation.SpanAttrResponseBytes).AsInt64())
+}
@@ -0,0 +1,306 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/logging"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+	toolerrors "github.com/giantswarm/mcp-kubernetes/internal/tools/errors"
+)
+
+// UndoToolName is the tool that reverts the caller's recent changes.
+const UndoToolName = "undo"
+
+// MaxUndoCount caps the number of changes one undo call reverts.
+const MaxUndoCount = 20
+
+// Actions reported by undo.
+const (
+	UndoActionDeleted  = "deleted"
+	UndoActionRestored = "restored"
+)
+
+// UndoTarget identifies an object a tool is about to change.
+type UndoTarget struct {
+	Cluster      string
+	KubeContext  string
+	Namespace    string
+	ResourceType string
+	APIGroup     string
+	Name         string
+}
+
+// UndoneChange is a change reverted by undo.
+type UndoneChange struct {
+	Tool       string    `json:"tool"`
+	Object     string    `json:"object"`
+	Cluster    string    `json:"cluster,omitempty"`
+	Action     string    `json:"action"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// UndoResponse is the result of the undo tool.
+type UndoResponse struct {
+	Undone  []UndoneChange `json:"undone"`
+	DryRun  bool           `json:"dryRun,omitempty"`
+	Message string         `json:"message"`
+}
+
+// serverManagedFields are the metadata fields dropped from recorded objects,
+// as the API server sets them when an object is restored.
+var serverManagedFields = []string{
+	"resourceVersion", "uid", "generation", "creationTimestamp", "managedFields",
+	"selfLink", "deletionTimestamp", "deletionGracePeriodSeconds",
+}
+
+// SnapshotForUndo reads the state of target before toolName changes it, to
+// be recorded with RecordForUndo once the change succeeded. An object that
+// does not exist yet is recorded without prior state, so that undo deletes
+// it.
+//
+// Returns nil, and nothing is recorded, when the undo journal is disabled,
+// the call is a dry-run, the object is a Secret (whose data must not be
+// stored) or its state cannot be read.
+func SnapshotForUndo(ctx context.Context, sc *server.ServerContext, client k8s.Client, toolName string, target UndoTarget) *journal.Entry {
+	if sc.UndoJournal() == nil || IsDryRun(ctx, sc) {
+		return nil
+	}
+	entry, err := SnapshotObject(ctx, client, toolName, target)
+	if err != nil {
+		slog.Debug("not recording change for undo: prior state unreadable",
+			slog.String("tool", toolName), slog.String("object", entry.Object()), logging.SanitizedErr(err))
+		return nil
+	}
+	if entry.Before != nil {
+		obj := &unstructured.Unstructured{Object: entry.Before}
+		if obj.GetKind() == "Secret" && (obj.GetAPIVersion() == "v1" || obj.GetAPIVersion() == "") {
+			return nil
+		}
+	}
+	return entry
+}
+
+// SnapshotObject reads the state of target before toolName changes it,
+// without server-managed metadata and status, so that RevertChange can put
+// it back. An object that does not exist yet has no prior state. On error
+// the returned entry still identifies the object.
+func SnapshotObject(ctx context.Context, client k8s.Client, toolName string, target UndoTarget) (*journal.Entry, error) {
+	entry := newUndoEntry(toolName, target)
+	resp, err := client.Get(ctx, target.KubeContext, target.Namespace, target.ResourceType, target.APIGroup, target.Name)
+	if apierrors.IsNotFound(err) {
+		return entry, nil
+	}
+	if err != nil {
+		return entry, err
+	}
+
+	before, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resp.Resource)
+	if err != nil {
+		return entry, err
+	}
+	StripServerManagedFields(before)
+	entry.Before = before
+	return entry, nil
+}
+
+// StripServerManagedFields removes the metadata the API server sets and the
+// status from obj.
+func StripServerManagedFields(obj map[string]interface{}) {
+	for _, field := range serverManagedFields {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+	delete(obj, "status")
+}
+
+// CreationForUndo returns the record of an object toolName is about to
+// create, so that undo deletes it. Returns nil when the undo journal is
+// disabled or the call is a dry-run.
+func CreationForUndo(ctx context.Context, sc *server.ServerContext, toolName string, target UndoTarget) *journal.Entry {
+	if sc.UndoJournal() == nil || IsDryRun(ctx, sc) {
+		return nil
+	}
+	return newUndoEntry(toolName, target)
+}
+
+func newUndoEntry(toolName string, target UndoTarget) *journal.Entry {
+	return &journal.Entry{
+		Tool:         primaryToolName(toolName),
+		Cluster:      target.Cluster,
+		KubeContext:  target.KubeContext,
+		Namespace:    target.Namespace,
+		ResourceType: target.ResourceType,
+		APIGroup:     target.APIGroup,
+		Name:         target.Name,
+	}
+}
+
+// RecordForUndo records entry, from SnapshotForUndo or CreationForUndo, in
+// the caller's journal after the change succeeded. A nil entry is ignored.
+// Failures are logged rather than returned, as the change was made.
+func RecordForUndo(ctx context.Context, sc *server.ServerContext, entry *journal.Entry) {
+	j := sc.UndoJournal()
+	if j == nil || entry == nil {
+		return
+	}
+	if err := j.Record(ctx, undoSession(ctx), *entry); err != nil {
+		slog.Warn("failed to record change for undo",
+			slog.String("tool", entry.Tool), slog.String("object", entry.Object()), logging.SanitizedErr(err))
+	}
+}
+
+// undoSession returns the journal of a tool call: the caller's, per MCP
+// session when the transport has sessions.
+func undoSession(ctx context.Context) string {
+	session := rateLimitCaller(ctx).Identity()
+	if s := mcpserver.ClientSessionFromContext(ctx); s != nil && s.SessionID() != "" {
+		session += "|session:" + s.SessionID()
+	}
+	return session
+}
+
+// RegisterUndoTool registers the undo tool when the undo journal is enabled
+// and undo is allowed.
+func RegisterUndoTool(s *mcpserver.MCPServer, sc *server.ServerContext) {
+	if sc.UndoJournal() == nil || !IsMutatingOperationAllowed(sc, UndoToolName) {
+		return
+	}
+	s.AddTool(mcp.NewTool(UndoToolName,
+		mcp.WithDescription(fmt.Sprintf(`Undo the most recent changes made through this server in the current session, newest first.
+
+Objects that a change created are deleted; objects that it changed or deleted are put back as they were before it. Changes by create, apply, delete, patch, scale, label and annotate are recorded, except to Secrets. The journal keeps the last %d changes of a session.
+
+Restoring overwrites any change made to the object since. Undo stops at the first change it cannot revert.`, sc.UndoJournal().Size())),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithSchemaAdditionalProperties(false),
+		mcp.WithNumber("count",
+			mcp.Description(fmt.Sprintf("Number of recent changes to undo (default: 1, max: %d)", MaxUndoCount)),
+			mcp.Min(1),
+			mcp.Max(MaxUndoCount),
+		),
+		DryRunParam(),
+	), WrapWithAuditLogging(UndoToolName, handleUndo, sc))
+}
+
+// handleUndo reverts the caller's most recent changes.
+func handleUndo(ctx context.Context, request mcp.CallToolRequest, sc *server.ServerContext) (*mcp.CallToolResult, error) {
+	j := sc.UndoJournal()
+	if j == nil {
+		return toolerrors.New(toolerrors.CodeNotEnabled, "the undo journal is not enabled on this server").Result(), nil
+	}
+	count := int(request.GetFloat("count", 1))
+	if count < 1 || count > MaxUndoCount {
+		return toolerrors.InvalidArgumentf("count must be between 1 and %d", MaxUndoCount).Result(), nil
+	}
+
+	session := undoSession(ctx)
+	entries, err := j.Recent(ctx, session, count)
+	if err != nil {
+		return toolerrors.New(toolerrors.CodeUnavailable, err.Error()).Result(), nil
+	}
+
+	dryRun := IsDryRun(ctx, sc)
+	response := UndoResponse{Undone: []UndoneChange{}, DryRun: dryRun}
+	var done []string
+	for _, entry := range entries {
+		action, toolErr := RevertChange(ctx, sc, UndoToolName, entry)
+		if toolErr != nil {
+			toolErr.Message = fmt.Sprintf("Failed to undo %s of %s: %s", entry.Tool, entry.Object(), toolErr.Message)
+			if len(done) > 0 {
+				toolErr.Message += "\nAlready undone: " + strings.Join(done, ", ")
+			}
+			return toolErr.Result(), nil
+		}
+		if !dryRun {
+			if err := j.Remove(ctx, session, entry); err != nil {
+				slog.Warn("failed to remove undone change from journal", slog.String("object", entry.Object()), logging.SanitizedErr(err))
+			}
+		}
+		response.Undone = append(response.Undone, UndoneChange{
+			Tool:       entry.Tool,
+			Object:     entry.Object(),
+			Cluster:    entry.Cluster,
+			Action:     action,
+			RecordedAt: entry.RecordedAt,
+		})
+		done = append(done, entry.Object())
+	}
+
+	switch {
+	case len(entries) == 0:
+		response.Message = "Nothing to undo: no changes are recorded for this session"
+	case dryRun:
+		response.Message = fmt.Sprintf("Dry run: %d change(s) would be undone", len(response.Undone))
+	default:
+		response.Message = fmt.Sprintf("Undid %d change(s)", len(response.Undone))
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return toolerrors.Internalf("Failed to marshal response: %v", err).Result(), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// RevertChange reverts one change for toolName: it deletes an object the
+// change created and puts back any other. The delete or apply is checked
+// like a direct call first: against non-destructive mode and, with
+// TargetWriteDenied, against the server's policy and namespace access, as
+// the call's own checks do not see the object. An object that is already
+// gone counts as deleted. Returns the action taken, UndoActionDeleted or
+// UndoActionRestored.
+func RevertChange(ctx context.Context, sc *server.ServerContext, toolName string, entry journal.Entry) (string, *toolerrors.Error) {
+	operation := "apply"
+	if entry.Before == nil {
+		operation = "delete"
+	}
+	if result := CheckMutatingOperation(sc, operation); result != nil {
+		return "", toolerrors.FromResult(result)
+	}
+
+	target := UndoTarget{
+		Cluster:      entry.Cluster,
+		KubeContext:  entry.KubeContext,
+		Namespace:    entry.Namespace,
+		ResourceType: entry.ResourceType,
+		APIGroup:     entry.APIGroup,
+		Name:         entry.Name,
+	}
+	if msg := TargetWriteDenied(ctx, sc, toolName, target); msg != "" {
+		return "", toolerrors.New(toolerrors.CodePolicyDenied, msg)
+	}
+
+	client, toolErr := GetClusterClient(ctx, sc, entry.Cluster)
+	if toolErr != nil {
+		return "", toolErr
+	}
+
+	if entry.Before == nil {
+		_, err := client.K8s().Delete(ctx, entry.KubeContext, entry.Namespace, entry.ResourceType, entry.APIGroup, entry.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", K8sError("delete", err, client.User())
+		}
+		return UndoActionDeleted, nil
+	}
+
+	obj := &unstructured.Unstructured{Object: entry.Before}
+	if _, err := client.K8s().Apply(ctx, entry.KubeContext, entry.Namespace, obj); err != nil {
+		return "", K8sError("restore", err, client.User())
+	}
+	return UndoActionRestored, nil
+}
@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/journal"
+	"github.com/giantswarm/mcp-kubernetes/internal/k8s"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// undoK8sClient keeps objects by name, whatever their type.
+type undoK8sClient struct {
+	k8s.Client
+	objects map[string]*unstructured.Unstructured
+}
+
+func (c *undoK8sClient) Get(_ context.Context, _, _, resourceType, _, name string) (*k8s.GetResponse, error) {
+	obj, ok := c.objects[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	return &k8s.GetResponse{Resource: obj.DeepCopy()}, nil
+}
+
+func (c *undoK8sClient) Apply(_ context.Context, _, _ string, obj runtime.Object) (runtime.Object, error) {
+	u := obj.(*unstructured.Unstructured).DeepCopy()
+	c.objects[u.GetName()] = u
+	return u, nil
+}
+
+func (c *undoK8sClient) Delete(_ context.Context, _, _, resourceType, _, name string) (*k8s.DeleteResponse, error) {
+	if _, ok := c.objects[name]; !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+	}
+	delete(c.objects, name)
+	return &k8s.DeleteResponse{}, nil
+}
+
+func configMap(name, value string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"namespace":       "prod",
+			"resourceVersion": "42",
+			"uid":             "uid-" + name,
+		},
+		"data": map[string]interface{}{"value": value},
+	}}
+	return obj
+}
+
+func TestUndo(t *testing.T) {
+	client := &undoK8sClient{objects: map[string]*unstructured.Unstructured{"app": configMap("app", "1")}}
+	sc := newVisibilityServerContext(t,
+		server.WithK8sClient(client),
+		server.WithNonDestructiveMode(false),
+		server.WithUndoJournal(journal.New(journal.NewMemoryBackend(), "", 10, time.Hour)),
+	)
+	ctx := context.Background()
+
+	// patch app, then create web.
+	undo := SnapshotForUndo(ctx, sc, client, "patch", UndoTarget{Namespace: "prod", ResourceType: "configmaps", Name: "app"})
+	require.NotNil(t, undo)
+	client.objects["app"] = configMap("app", "2")
+	RecordForUndo(ctx, sc, undo)
+
+	undo = CreationForUndo(ctx, sc, "kubernetes_create", UndoTarget{Namespace: "prod", ResourceType: "ConfigMap", APIGroup: "core/v1", Name: "web"})
+	client.objects["web"] = configMap("web", "1")
+	RecordForUndo(ctx, sc, undo)
+
+	wrapped := WrapWithAuditLogging(UndoToolName, handleUndo, sc)
+	result, err := wrapped(ctx, createTestRequest(map[string]interface{}{"count": float64(5)}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+
+	var response UndoResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	require.Len(t, response.Undone, 2)
+	assert.Equal(t, UndoneChange{Tool: "create", Object: "ConfigMap/web in namespace prod", Action: UndoActionDeleted, RecordedAt: response.Undone[0].RecordedAt}, response.Undone[0])
+	assert.Equal(t, "patch", response.Undone[1].Tool)
+	assert.Equal(t, UndoActionRestored, response.Undone[1].Action)
+
+	assert.NotContains(t, client.objects, "web")
+	restored := client.objects["app"]
+	assert.Equal(t, map[string]interface{}{"value": "1"}, restored.Object["data"])
+	assert.Empty(t, restored.GetResourceVersion(), "server-managed fields are not restored")
+
+	result, err = wrapped(ctx, createTestRequest(nil))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	assert.Empty(t, response.Undone)
+	assert.Contains(t, response.Message, "Nothing to undo")
+}
+
+func TestSnapshotForUndo_NotRecorded(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds"},
+		"data":       map[string]interface{}{"password": "c2VjcmV0"},
+	}}
+	client := &undoK8sClient{objects: map[string]*unstructured.Unstructured{"app": configMap("app", "1"), "creds": secret}}
+	sc := newVisibilityServerContext(t, server.WithUndoJournal(journal.New(journal.NewMemoryBackend(), "", 10, time.Hour)))
+	target := UndoTarget{Namespace: "prod", ResourceType: "configmaps", Name: "app"}
+
+	assert.Nil(t, SnapshotForUndo(context.Background(), sc, client, "patch", UndoTarget{ResourceType: "secrets", Name: "creds"}), "Secrets are not recorded")
+
+	dryRun := withDryRun(context.Background(), map[string]interface{}{ParamDryRun: true})
+	assert.Nil(t, SnapshotForUndo(dryRun, sc, client, "patch", target), "dry-runs are not recorded")
+	assert.Nil(t, CreationForUndo(dryRun, sc, "create", target))
+
+	disabled := newVisibilityServerContext(t)
+	assert.Nil(t, SnapshotForUndo(context.Background(), disabled, client, "patch", target))
+
+	entry := SnapshotForUndo(context.Background(), sc, client, "delete", UndoTarget{ResourceType: "pods", Name: "missing"})
+	require.NotNil(t, entry)
+	assert.Nil(t, entry.Before, "a missing object is recorded as created")
+}
+
+func TestUndo_WriteAllowlist(t *testing.T) {
+	client := &undoK8sClient{objects: map[string]*unstructured.Unstructured{"web": configMap("web", "1")}}
+	sc := newVisibilityServerContext(t,
+		server.WithK8sClient(client),
+		server.WithNonDestructiveMode(false),
+		server.WithUndoJournal(journal.New(journal.NewMemoryBackend(), "", 10, time.Hour)),
+		server.WithNamespaceAccess(nil, []string{"team-*"}, false),
+	)
+	ctx := context.Background()
+	RecordForUndo(ctx, sc, CreationForUndo(ctx, sc, "create", UndoTarget{Namespace: "prod", ResourceType: "ConfigMap", APIGroup: "core/v1", Name: "web"}))
+
+	result, err := WrapWithAuditLogging(UndoToolName, handleUndo, sc)(ctx, createTestRequest(nil))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "undo in namespace prod is not allowed")
+	assert.Contains(t, client.objects, "web", "the object outside the allowlist is not deleted")
+}
+
+func TestUndo_NonDestructiveMode(t *testing.T) {
+	config := server.NewDefaultConfig()
+	config.NonDestructiveMode = true
+	config.DryRun = false
+	config.AllowedOperations = []string{UndoToolName, "apply"}
+	client := &undoK8sClient{objects: map[string]*unstructured.Unstructured{"app": configMap("app", "2"), "web": configMap("web", "1")}}
+	sc := newVisibilityServerContext(t,
+		server.WithK8sClient(client),
+		server.WithConfig(config),
+		server.WithUndoJournal(journal.New(journal.NewMemoryBackend(), "", 10, time.Hour)),
+	)
+	ctx := context.Background()
+	RecordForUndo(ctx, sc, CreationForUndo(ctx, sc, "create", UndoTarget{Namespace: "prod", ResourceType: "ConfigMap", APIGroup: "core/v1", Name: "web"}))
+
+	result, err := WrapWithAuditLogging(UndoToolName, handleUndo, sc)(ctx, createTestRequest(nil))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Delete operations are not allowed")
+	assert.Contains(t, client.objects, "web", "undo cannot delete when deletes are not allowed")
+
+	before := configMap("app", "1").Object
+	action, toolErr := RevertChange(ctx, sc, UndoToolName, journal.Entry{Namespace: "prod", ResourceType: "configmaps", Name: "app", Before: before})
+	require.Nil(t, toolErr)
+	assert.Equal(t, UndoActionRestored, action, "restoring only needs apply")
+}
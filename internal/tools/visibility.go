@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// clusterAccessTTL bounds how long a user's "has any cluster" answer is reused
+// by the tool filter. The filter runs on every tools/list and tools/call, so
+// the underlying ListClusters call must not happen per request.
+const clusterAccessTTL = time.Minute
+
+// capiToolPrefix identifies CAPI tools, which are useless to a caller who
+// cannot see any workload cluster.
+const capiToolPrefix = "capi_"
+
+// AccessAwareToolFilter hides tools the current caller can never use
+// successfully. Because mcp-go applies tool filters both to tools/list and to
+// tools/call, a hidden tool also cannot be invoked.
+//
+// A tool is hidden when:
+//   - it is mutating and the caller belongs to one of Config.ReadOnlyGroups;
+//   - the policy engine denies its verb on every cluster, namespace and resource;
+//   - it is a capi_* tool and federation reports no clusters for the caller.
+//
+// Callers without an identity (e.g. stdio) are only subject to the policy check.
+type AccessAwareToolFilter struct {
+	sc *server.ServerContext
+
+	mu            sync.Mutex
+	clusterAccess map[string]clusterAccessEntry
+	now           func() time.Time
+}
+
+type clusterAccessEntry struct {
+	hasClusters bool
+	expires     time.Time
+}
+
+// NewAccessAwareToolFilter creates a filter bound to sc.
+func NewAccessAwareToolFilter(sc *server.ServerContext) *AccessAwareToolFilter {
+	return &AccessAwareToolFilter{
+		sc:            sc,
+		clusterAccess: make(map[string]clusterAccessEntry),
+		now:           time.Now,
+	}
+}
+
+// Filter is a mcpserver.ToolFilterFunc.
+func (f *AccessAwareToolFilter) Filter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	user, _ := callerUserInfo(ctx, "")
+	readOnly := user != nil && f.inReadOnlyGroup(user.Groups)
+	engine := f.sc.PolicyEngine()
+
+	// Resolved lazily: only needed when a capi_* tool is in the list.
+	var capiChecked, capiHidden bool
+
+	out := tools[:0]
+	for _, t := range tools {
+		if readOnly && isMutatingTool(t) {
+			continue
+		}
+		if engine != nil && engine.VerbDenied(primaryToolName(t.Name)) {
+			continue
+		}
+		if user != nil && strings.HasPrefix(t.Name, capiToolPrefix) {
+			if !capiChecked {
+				capiHidden = !f.hasClusterAccess(ctx, user)
+				capiChecked = true
+			}
+			if capiHidden {
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// ToolFilterFunc returns the filter as a mcpserver.ToolFilterFunc.
+func (f *AccessAwareToolFilter) ToolFilterFunc() mcpserver.ToolFilterFunc {
+	return f.Filter
+}
+
+func (f *AccessAwareToolFilter) inReadOnlyGroup(groups []string) bool {
+	cfg := f.sc.Config()
+	if cfg == nil {
+		return false
+	}
+	for _, ro := range cfg.ReadOnlyGroups {
+		for _, g := range groups {
+			if g == ro {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasClusterAccess reports whether federation lists at least one cluster for
+// user. Errors fail open: the tool stays visible and the call itself reports
+// the problem.
+func (f *AccessAwareToolFilter) hasClusterAccess(ctx context.Context, user *federation.UserInfo) bool {
+	fedManager := f.sc.FederationManager()
+	if fedManager == nil {
+		return true
+	}
+
+	now := f.now()
+	f.mu.Lock()
+	entry, ok := f.clusterAccess[user.Email]
+	f.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.hasClusters
+	}
+
+	clusters, err := fedManager.ListClusters(ctx, user)
+	if err != nil {
+		slog.Debug("tool filter: cluster listing failed, keeping capi tools visible", slog.Any("error", err))
+		return true
+	}
+
+	hasClusters := len(clusters) > 0
+	f.mu.Lock()
+	f.clusterAccess[user.Email] = clusterAccessEntry{hasClusters: hasClusters, expires: now.Add(clusterAccessTTL)}
+	f.mu.Unlock()
+	return hasClusters
+}
+
+// isMutatingTool reports whether a tool may modify cluster state, based on its
+// read-only annotation. Tools without the annotation are treated as read-only.
+func isMutatingTool(t mcp.Tool) bool {
+	hint := t.Annotations.ReadOnlyHint
+	return hint != nil && !*hint
+}
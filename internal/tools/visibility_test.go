@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	oauthhandler "github.com/giantswarm/mcp-oauth/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/giantswarm/mcp-kubernetes/internal/federation"
+	"github.com/giantswarm/mcp-kubernetes/internal/mcp/oauth"
+	"github.com/giantswarm/mcp-kubernetes/internal/security"
+	"github.com/giantswarm/mcp-kubernetes/internal/server"
+)
+
+// listingFedManager answers ListClusters from a fixed list and counts calls.
+type listingFedManager struct {
+	impersonationFedManager
+	clusters []federation.ClusterSummary
+	err      error
+	calls    int
+}
+
+func (m *listingFedManager) ListClusters(context.Context, *federation.UserInfo) ([]federation.ClusterSummary, error) {
+	m.calls++
+	return m.clusters, m.err
+}
+
+func visibilityTestTools() []mcp.Tool {
+	return []mcp.Tool{
+		mcp.NewTool("get", mcp.WithReadOnlyHintAnnotation(true)),
+		mcp.NewTool("delete", mcp.WithReadOnlyHintAnnotation(false)),
+		mcp.NewTool("kubernetes_delete", mcp.WithReadOnlyHintAnnotation(false)),
+		mcp.NewTool("exec", mcp.WithReadOnlyHintAnnotation(false)),
+		mcp.NewTool("capi_list_clusters", mcp.WithReadOnlyHintAnnotation(true)),
+	}
+}
+
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func userContext(groups ...string) context.Context {
+	return oauthhandler.ContextWithUserInfo(context.Background(), &oauth.UserInfo{
+		Email:  "jane@example.com",
+		Groups: groups,
+	})
+}
+
+func newVisibilityServerContext(t *testing.T, opts ...server.Option) *server.ServerContext {
+	t.Helper()
+	opts = append([]server.Option{
+		server.WithK8sClient(&mockK8sClient{}),
+		server.WithLogger(&mockLogger{}),
+	}, opts...)
+	sc, err := server.NewServerContext(context.Background(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sc.Shutdown() })
+	return sc
+}
+
+func TestAccessAwareToolFilter_ReadOnlyGroups(t *testing.T) {
+	sc := newVisibilityServerContext(t, server.WithReadOnlyGroups([]string{"viewers"}))
+	filter := NewAccessAwareToolFilter(sc)
+
+	got := filter.Filter(userContext("viewers"), visibilityTestTools())
+	assert.Equal(t, []string{"get", "capi_list_clusters"}, toolNames(got))
+
+	got = filter.Filter(userContext("admins"), visibilityTestTools())
+	assert.Len(t, got, 5, "users outside read-only groups see everything")
+
+	got = filter.Filter(context.Background(), visibilityTestTools())
+	assert.Len(t, got, 5, "anonymous callers are not subject to group rules")
+}
+
+func TestAccessAwareToolFilter_Policy(t *testing.T) {
+	policy := &security.Policy{Rules: []security.Rule{
+		{Effect: security.EffectDeny, Verbs: []string{"exec", "delete"}},
+		{Effect: security.EffectDeny, Namespaces: []string{"kube-system"}, Verbs: []string{"get"}},
+	}}
+	sc := newVisibilityServerContext(t, server.WithPolicyEngine(security.NewEngine(policy)))
+
+	got := NewAccessAwareToolFilter(sc).Filter(context.Background(), visibilityTestTools())
+	assert.Equal(t, []string{"get", "capi_list_clusters"}, toolNames(got),
+		"verbs denied everywhere are hidden, including their deprecated aliases")
+}
+
+func TestAccessAwareToolFilter_CAPIClusterAccess(t *testing.T) {
+	t.Run("hidden without clusters and cached", func(t *testing.T) {
+		fed := &listingFedManager{}
+		sc := newVisibilityServerContext(t, server.WithFederationManager(fed))
+		filter := NewAccessAwareToolFilter(sc)
+
+		got := filter.Filter(userContext(), visibilityTestTools())
+		assert.NotContains(t, toolNames(got), "capi_list_clusters")
+
+		filter.Filter(userContext(), visibilityTestTools())
+		assert.Equal(t, 1, fed.calls, "cluster access must be cached")
+
+		now := time.Now().Add(2 * clusterAccessTTL)
+		filter.now = func() time.Time { return now }
+		fed.clusters = []federation.ClusterSummary{{Name: "wc-1"}}
+		got = filter.Filter(userContext(), visibilityTestTools())
+		assert.Contains(t, toolNames(got), "capi_list_clusters")
+		assert.Equal(t, 2, fed.calls)
+	})
+
+	t.Run("listing errors keep tools visible", func(t *testing.T) {
+		fed := &listingFedManager{err: errors.New("boom")}
+		sc := newVisibilityServerContext(t, server.WithFederationManager(fed))
+		got := NewAccessAwareToolFilter(sc).Filter(userContext(), visibilityTestTools())
+		assert.Contains(t, toolNames(got), "capi_list_clusters")
+	})
+
+	t.Run("anonymous callers skip the lookup", func(t *testing.T) {
+		fed := &listingFedManager{}
+		sc := newVisibilityServerContext(t, server.WithFederationManager(fed))
+		got := NewAccessAwareToolFilter(sc).Filter(context.Background(), visibilityTestTools())
+		assert.Contains(t, toolNames(got), "capi_list_clusters")
+		assert.Zero(t, fed.calls)
+	})
+}